@@ -14,6 +14,7 @@ import (
 
 	"gist/backend/internal/config"
 	"gist/backend/internal/db"
+	"gist/backend/internal/events"
 	"gist/backend/internal/handler"
 	transport "gist/backend/internal/http"
 	"gist/backend/internal/repository"
@@ -21,6 +22,7 @@ import (
 	"gist/backend/internal/service"
 	"gist/backend/internal/service/ai"
 	"gist/backend/internal/service/anubis"
+	"gist/backend/internal/shutdown"
 	"gist/backend/pkg/logger"
 	"gist/backend/pkg/network"
 	"gist/backend/pkg/snowflake"
@@ -45,16 +47,28 @@ func main() {
 		logger.Error("open database", "error", err)
 		os.Exit(1)
 	}
-	defer dbConn.Close()
 
-	folderRepo := repository.NewFolderRepository(dbConn)
-	feedRepo := repository.NewFeedRepository(dbConn)
-	entryRepo := repository.NewEntryRepository(dbConn)
-	settingsRepo := repository.NewSettingsRepository(dbConn)
-	aiSummaryRepo := repository.NewAISummaryRepository(dbConn)
+	changes := repository.NewChangeTracker()
+	writes := repository.NewWriteTracker()
+	folderRepo := repository.NewFolderRepository(dbConn, changes, writes)
+	folderRuleRepo := repository.NewFolderRuleRepository(dbConn)
+	feedRepo := repository.NewFeedRepository(dbConn, changes, writes)
+	feedOverlapRepo := repository.NewFeedOverlapRepository(dbConn, changes, writes)
+	entryRepo := repository.NewEntryRepository(dbConn, changes, writes)
+	settingsRepo := repository.NewSettingsRepository(dbConn, writes)
+	aiSummaryRepo := repository.NewAISummaryRepository(dbConn, changes, writes)
 	aiTranslationRepo := repository.NewAITranslationRepository(dbConn)
 	aiListTranslationRepo := repository.NewAIListTranslationRepository(dbConn)
-	domainRateLimitRepo := repository.NewDomainRateLimitRepository(dbConn)
+	aiUsageRepo := repository.NewAIUsageRepository(dbConn)
+	feedTitleTranslationRepo := repository.NewFeedTitleTranslationRepository(dbConn, changes)
+	aiDigestRepo := repository.NewAIDigestRepository(dbConn)
+	domainRateLimitRepo := repository.NewDomainRateLimitRepository(dbConn, changes)
+	apiTokenRepo := repository.NewAPITokenRepository(dbConn)
+	loginEventRepo := repository.NewLoginEventRepository(dbConn, writes)
+	refreshRunRepo := repository.NewRefreshRunRepository(dbConn, changes, writes)
+	feedFetchLogRepo := repository.NewFeedFetchLogRepository(dbConn, writes)
+	entryArchiveRepo := repository.NewEntryArchiveRepository(dbConn)
+	storageRepo := repository.NewStorageRepository(dbConn)
 
 	// Initialize rate limiter with stored setting
 	initialRateLimit := ai.DefaultRateLimit
@@ -67,16 +81,30 @@ func main() {
 	}
 	rateLimiter := ai.NewRateLimiter(initialRateLimit)
 
-	settingsService := service.NewSettingsService(settingsRepo, rateLimiter)
+	securityDefaults := service.SecuritySettings{
+		CORSOrigins:    cfg.CORSOrigins,
+		CookieSameSite: cfg.CookieSameSite,
+		CookieSecure:   cfg.CookieSecure,
+		CookieDomain:   cfg.CookieDomain,
+	}
+	if err := service.NormalizeSecuritySettings(&securityDefaults); err != nil {
+		logger.Error("invalid cookie/cors environment settings", "error", err)
+		os.Exit(1)
+	}
+	settingsService := service.NewSettingsService(settingsRepo, rateLimiter, securityDefaults)
 
 	// Initialize client factory for proxy and IP stack support
 	clientFactory := network.NewClientFactory(settingsService, settingsService)
+	settingsService.OnNetworkSettingsChange(clientFactory.Invalidate)
 
 	// Initialize Anubis solver for bypassing Anubis protection
 	anubisStore := anubis.NewStore(settingsRepo)
 	anubisSolver := anubis.NewSolver(clientFactory, anubisStore)
 
-	iconService := service.NewIconService(cfg.DataDir, feedRepo, clientFactory, anubisSolver)
+	// Services publish change events to bus; GET /events streams them
+	bus := events.NewBus(events.SubscriberBuffer)
+
+	iconService := service.NewIconService(cfg.DataDir, feedRepo, clientFactory, anubisSolver, settingsService)
 
 	// Backfill icons for existing feeds (run in background)
 	backfillCtx, cancelBackfill := context.WithCancel(context.Background())
@@ -89,84 +117,122 @@ func main() {
 		}
 	}()
 
-	folderService := service.NewFolderService(folderRepo, feedRepo)
-	feedService := service.NewFeedService(feedRepo, folderRepo, entryRepo, iconService, settingsService, clientFactory, anubisSolver)
-	entryService := service.NewEntryService(entryRepo, feedRepo, folderRepo)
-	readabilityService := service.NewReadabilityService(entryRepo, clientFactory, anubisSolver)
+	service.MaxEntryTitleLength = cfg.MaxTitleLength
+	service.MaxEntryAuthorLength = cfg.MaxAuthorLength
+
+	folderService := service.NewFolderService(folderRepo, feedRepo, folderRuleRepo, bus)
+	feedService := service.NewFeedService(feedRepo, folderRepo, entryRepo, iconService, settingsService, clientFactory, anubisSolver, folderRuleRepo, feedOverlapRepo, bus)
 	domainRateLimitService := service.NewDomainRateLimitService(domainRateLimitRepo)
-	refreshService := service.NewRefreshService(feedRepo, entryRepo, settingsService, iconService, clientFactory, anubisSolver, domainRateLimitService)
+	readabilityService := service.NewReadabilityService(entryRepo, clientFactory, anubisSolver, settingsService, domainRateLimitService, bus)
+	proxyService := service.NewProxyService(clientFactory, anubisSolver, settingsService)
+	imageCacheService := service.NewImageCacheService(cfg.DataDir, entryRepo, feedRepo, settingsService, proxyService, domainRateLimitService)
+	archiveService := service.NewArchiveService(entryRepo, feedRepo, entryArchiveRepo, readabilityService, imageCacheService)
+	entryService := service.NewEntryService(entryRepo, feedRepo, folderRepo, aiSummaryRepo, settingsService, archiveService, bus)
+	refreshService := service.NewRefreshService(feedRepo, entryRepo, refreshRunRepo, settingsService, iconService, imageCacheService, clientFactory, anubisSolver, domainRateLimitService, feedFetchLogRepo, folderRepo, bus)
 	opmlService := service.NewOPMLService(folderService, feedService, refreshService, iconService, folderRepo, feedRepo)
 
-	proxyService := service.NewProxyService(clientFactory, anubisSolver)
-	aiService := service.NewAIServiceWithFeedContext(aiSummaryRepo, aiTranslationRepo, aiListTranslationRepo, settingsRepo, rateLimiter, entryRepo, feedRepo)
+	aiService := service.NewAIServiceWithFeedContext(aiSummaryRepo, aiTranslationRepo, aiListTranslationRepo, settingsRepo, rateLimiter, entryRepo, feedRepo, aiUsageRepo, feedTitleTranslationRepo, aiDigestRepo)
 	authService := service.NewAuthService(settingsRepo)
+	apiTokenService := service.NewAPITokenService(apiTokenRepo)
+	loginGuardService := service.NewLoginGuardService(loginEventRepo)
 
 	folderHandler := handler.NewFolderHandler(folderService)
-	feedHandler := handler.NewFeedHandler(feedService, refreshService)
-	entryHandler := handler.NewEntryHandler(entryService, readabilityService)
+	feedHandler := handler.NewFeedHandler(feedService, refreshService, changes.Version, aiService)
+	entryHandler := handler.NewEntryHandler(entryService, readabilityService, changes.Version)
 	importTaskService := service.NewImportTaskService()
 	opmlHandler := handler.NewOPMLHandler(opmlService, importTaskService)
 	iconHandler := handler.NewIconHandler(iconService)
+	imageCacheHandler := handler.NewImageCacheHandler(imageCacheService)
 	proxyHandler := handler.NewProxyHandler(proxyService)
 	settingsHandler := handler.NewSettingsHandler(settingsService, clientFactory)
-	aiHandler := handler.NewAIHandler(aiService)
-	authHandler := handler.NewAuthHandler(authService)
+	autoTranslateService := service.NewAutoTranslateService(aiListTranslationRepo, aiService, settingsService, feedRepo, folderRepo)
+	aiHandler := handler.NewAIHandler(aiService, autoTranslateService)
+	authHandler := handler.NewAuthHandler(authService, loginGuardService, settingsService)
 	domainRateLimitHandler := handler.NewDomainRateLimitHandler(domainRateLimitService)
+	apiTokenHandler := handler.NewAPITokenHandler(apiTokenService)
+	healthHandler := handler.NewHealthHandler(service.NewHealthService(dbConn, cfg.DataDir, nil, feedRepo, feedFetchLogRepo))
+	maintenanceHandler := handler.NewMaintenanceHandler(service.NewMaintenanceService(entryRepo, feedRepo, settingsService, storageRepo, cfg.DBPath, cfg.DataDir))
+	backupHandler := handler.NewBackupHandler(service.NewBackupService(folderRepo, feedRepo, entryRepo))
+	eventHandler := handler.NewEventHandler(bus)
+	thumbnailHandler := handler.NewThumbnailHandler(service.NewThumbnailService(cfg.DataDir, int64(cfg.ThumbnailCacheMB)<<20, entryRepo, proxyService))
+	bootstrapHandler := handler.NewBootstrapHandler(folderService, feedService, entryService, refreshService, settingsService, changes.Version)
+	savedHandler := handler.NewSavedHandler(service.NewSavedService(feedRepo, entryRepo, readabilityService, bus))
 
-	router := transport.NewRouter(folderHandler, feedHandler, entryHandler, opmlHandler, iconHandler, proxyHandler, settingsHandler, aiHandler, authHandler, domainRateLimitHandler, authService, cfg.StaticDir, cfg.EnableSwagger)
+	router := transport.NewRouter(folderHandler, feedHandler, entryHandler, opmlHandler, iconHandler, imageCacheHandler, proxyHandler, settingsHandler, aiHandler, authHandler, domainRateLimitHandler, apiTokenHandler, healthHandler, maintenanceHandler, backupHandler, eventHandler, thumbnailHandler, bootstrapHandler, savedHandler, authService, apiTokenService, settingsService, cfg.StaticDir, cfg.EnableSwagger, cfg.TrustProxy, transport.BodyLimits{
+		Default:  int64(cfg.BodyLimitKB) << 10,
+		Import:   int64(cfg.ImportBodyLimitKB) << 10,
+		Settings: int64(cfg.SettingsBodyLimitKB) << 10,
+	})
 	pprofServer := startPprofServer(cfg.PprofAddr)
 
 	// Start background scheduler (15 minutes interval)
-	sched := scheduler.New(refreshService, 15*time.Minute)
+	sched := scheduler.New(refreshService, feedService, imageCacheService, autoTranslateService, 15*time.Minute, settingsService)
 	sched.Start()
 
-	// Handle graceful shutdown
-	go func() {
-		sigCh := make(chan os.Signal, 1)
-		signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
-		<-sigCh
-		logger.Info("shutting down...")
-
-		// Create a deadline for shutdown
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-		defer cancel()
-
-		sched.Stop()
-		readabilityService.Close()
-		proxyService.Close()
-		cancelBackfill()
-
-		// Wait for backfill task to exit within shutdown deadline.
-		backfillDone := make(chan struct{})
-		go func() {
-			backfillWG.Wait()
-			close(backfillDone)
-		}()
-		select {
-		case <-backfillDone:
-		case <-ctx.Done():
-			logger.Warn("backfill stop timeout")
-		}
-
-		if pprofServer != nil {
-			if err := pprofServer.Shutdown(ctx); err != nil {
-				logger.Error("pprof shutdown", "module", "server", "action", "shutdown", "resource", "pprof", "result", "failed", "error", err)
+	// Shut down in dependency order: stop taking requests, stop background work,
+	// let writes already started commit, then close the database.
+	timeout := cfg.ShutdownTimeout
+	phases := []shutdown.Phase{
+		{Name: "http", Timeout: timeout, Run: func(ctx context.Context) error {
+			// Event streams never end by themselves; close them so Shutdown can drain
+			bus.Close()
+			if pprofServer != nil {
+				if err := pprofServer.Shutdown(ctx); err != nil {
+					logger.Error("pprof shutdown", "module", "server", "action", "shutdown", "resource", "pprof", "result", "failed", "error", err)
+				}
 			}
-		}
-		// Gracefully shutdown the HTTP server
-		if err := router.Shutdown(ctx); err != nil {
-			logger.Error("server shutdown", "error", err)
-		}
+			return router.Shutdown(ctx)
+		}},
+		{Name: "refresh", Timeout: timeout, Run: func(ctx context.Context) error {
+			refreshService.Close()
+			autoTranslateService.Close()
+			archiveService.Close()
+			readabilityService.Close()
+			proxyService.Close()
+			cancelBackfill()
+			sched.Stop()
+			return waitGroup(ctx, &backfillWG)
+		}},
+		{Name: "writes", Timeout: timeout, Run: writes.Wait},
+		{Name: "database", Timeout: timeout, Run: func(ctx context.Context) error {
+			return dbConn.Close()
+		}},
+	}
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	stopped := make(chan struct{})
+	go func() {
+		shutdown.OnSignal(sigCh, phases)
+		close(stopped)
 	}()
 
 	if err := router.Start(cfg.Addr); err != nil && !errors.Is(err, http.ErrServerClosed) {
 		logger.Error("start server", "error", err)
+		dbConn.Close()
 		os.Exit(1)
 	}
 
+	// Start returns as soon as Shutdown begins; wait for the database to be closed
+	<-stopped
 	logger.Info("server stopped")
 }
 
+// waitGroup waits for wg, or returns ctx.Err() when ctx ends first.
+func waitGroup(ctx context.Context, wg *sync.WaitGroup) error {
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 func startPprofServer(addr string) *http.Server {
 	if addr == "" {
 		return nil
@@ -15,6 +15,143 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/admin/maintenance": {
+            "post": {
+                "description": "Run a one-off data maintenance action. normalize_dates rewrites stored published dates without zone info as UTC. canonicalize_urls fills in the canonical URL of entries saved before it was kept, which are otherwise set when saved again or visited. index_search makes the readable content and AI summaries of entries fetched or summarized earlier searchable.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Run maintenance action",
+                "parameters": [
+                    {
+                        "description": "Maintenance action",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.maintenanceRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.maintenanceResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/storage": {
+            "get": {
+                "description": "Report the size of the database and its WAL, the cache directories under the data directory, and row counts of the largest tables. Table sizes are only given when SQLite has the dbstat table.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Get storage usage",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.storageResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/storage/compact": {
+            "post": {
+                "description": "Checkpoint the WAL into the database and truncate it. With vacuum, also rebuild the database file to give free space back; this blocks all writes while it runs and requires confirm.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Compact database",
+                "parameters": [
+                    {
+                        "description": "Compaction options",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.compactRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.compactResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/ai/auto-translate/status": {
+            "get": {
+                "description": "Whether list titles and summaries of new entries are translated after refreshes, whether a run is in progress, and the stats of the last run since startup.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ai"
+                ],
+                "summary": "Get auto translate status",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.autoTranslateStatusResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/ai/cache": {
             "delete": {
                 "description": "Delete all AI-generated summaries and translations cache.",
@@ -41,15 +178,64 @@ const docTemplate = `{
                 }
             }
         },
+        "/ai/digest": {
+            "post": {
+                "description": "Digest the unread entries of a folder or feed published in a window, using their cached summaries or the start of their text, for up to the 100 newest. Returns the cached digest of the same window end and language if available, a canned \"nothing new\" without calling the provider when there are no unread entries, and otherwise streams the digest as plain text with the entry count in the X-Digest-Entries header, or as NDJSON {\"text\"} and {\"error\"} lines with Accept: application/x-ndjson.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/event-stream",
+                    "application/x-ndjson"
+                ],
+                "tags": [
+                    "ai"
+                ],
+                "summary": "Generate AI digest",
+                "parameters": [
+                    {
+                        "description": "Digest request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.digestRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Cached or empty digest",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.digestResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/ai/summarize": {
             "post": {
-                "description": "Generate an AI summary of the article content. Returns cached result if available, otherwise streams the response.",
+                "description": "Generate an AI summary of the article content. Returns cached result if available, otherwise streams the response. With Accept: application/x-ndjson the stream is sent as NDJSON lines of {\"text\"} chunks and an {\"error\"} on failure, for proxies that buffer event streams.",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
                     "application/json",
-                    "text/event-stream"
+                    "text/event-stream",
+                    "application/x-ndjson"
                 ],
                 "tags": [
                     "ai"
@@ -90,13 +276,14 @@ const docTemplate = `{
         },
         "/ai/translate": {
             "post": {
-                "description": "Translate article content. Returns cached result if available, otherwise streams block translations via SSE.",
+                "description": "Translate article content. Returns cached result if available, otherwise streams block translations via SSE, or as one NDJSON line per event with Accept: application/x-ndjson.",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
                     "application/json",
-                    "text/event-stream"
+                    "text/event-stream",
+                    "application/x-ndjson"
                 ],
                 "tags": [
                     "ai"
@@ -181,6 +368,101 @@ const docTemplate = `{
                 }
             }
         },
+        "/ai/translate/feeds": {
+            "post": {
+                "description": "Translate the titles of up to 500 feeds. Cached translations are reused while the feed title is unchanged; titles already in the target language are returned as-is. Feeds that fail to translate are left out.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ai"
+                ],
+                "summary": "Translate feed titles",
+                "parameters": [
+                    {
+                        "description": "Feed title translate request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.translateFeedsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/internal_handler.feedTitleTranslationResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/ai/usage": {
+            "get": {
+                "description": "Token usage per UTC day and operation (summarize, translate, list_translate, digest) with an estimated cost from the configured per-model prices. Usage the provider did not report is estimated from text length.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ai"
+                ],
+                "summary": "Get AI usage",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "First day as YYYY-MM-DD (default 29 days before to)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last day as YYYY-MM-DD (default today)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.aiUsageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/api/domain-rate-limits": {
             "get": {
                 "produces": [
@@ -200,6 +482,7 @@ const docTemplate = `{
                 }
             },
             "post": {
+                "description": "Set the request interval and concurrency cap for a host. Changes apply from the next refresh run.",
                 "consumes": [
                     "application/json"
                 ],
@@ -233,6 +516,7 @@ const docTemplate = `{
         },
         "/api/domain-rate-limits/{host}": {
             "put": {
+                "description": "Set the request interval and concurrency cap for a host. Changes apply from the next refresh run.",
                 "consumes": [
                     "application/json"
                 ],
@@ -350,9 +634,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/auth/login": {
+        "/auth/2fa/disable": {
             "post": {
-                "description": "Authenticate a user with username or email and get a JWT token",
+                "description": "Disable two-factor authentication after verifying the current password",
                 "consumes": [
                     "application/json"
                 ],
@@ -362,24 +646,21 @@ const docTemplate = `{
                 "tags": [
                     "auth"
                 ],
-                "summary": "Login",
+                "summary": "Disable two-factor authentication",
                 "parameters": [
                     {
-                        "description": "Login credentials",
+                        "description": "Current password",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/internal_handler.loginRequest"
+                            "$ref": "#/definitions/internal_handler.twoFactorDisableRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/internal_handler.authResponse"
-                        }
+                    "204": {
+                        "description": "No Content"
                     },
                     "400": {
                         "description": "Bad Request",
@@ -399,52 +680,98 @@ const docTemplate = `{
                             "$ref": "#/definitions/internal_handler.errorResponse"
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
             }
         },
-        "/auth/logout": {
+        "/auth/2fa/enable": {
             "post": {
-                "description": "Clear authentication cookie and log out the user",
+                "description": "Verify a code for the pending secret and enable two-factor authentication. Returns single-use recovery codes.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "auth"
                 ],
-                "summary": "Logout",
+                "summary": "Enable two-factor authentication",
+                "parameters": [
+                    {
+                        "description": "TOTP code",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.twoFactorEnableRequest"
+                        }
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/internal_handler.twoFactorEnableResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
                         }
                     }
-                }
-            }
-        },
-        "/auth/me": {
-            "get": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
-                "description": "Get the currently authenticated user's info",
+                ]
+            }
+        },
+        "/auth/2fa/setup": {
+            "post": {
+                "description": "Generate a TOTP secret and otpauth URL. The secret is activated by /auth/2fa/enable.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "auth"
                 ],
-                "summary": "Get current user",
+                "summary": "Set up two-factor authentication",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/internal_handler.userResponse"
+                            "$ref": "#/definitions/internal_handler.twoFactorSetupResponse"
                         }
                     },
                     "401": {
@@ -453,23 +780,29 @@ const docTemplate = `{
                             "$ref": "#/definitions/internal_handler.errorResponse"
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/internal_handler.errorResponse"
                         }
                     }
-                }
-            }
-        },
-        "/auth/profile": {
-            "put": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
-                "description": "Update user nickname, email and/or password. Returns new token when password is changed.",
+                ]
+            }
+        },
+        "/auth/2fa/verify": {
+            "post": {
+                "description": "Exchange a pending login token and a TOTP or recovery code for a JWT token",
                 "consumes": [
                     "application/json"
                 ],
@@ -479,15 +812,15 @@ const docTemplate = `{
                 "tags": [
                     "auth"
                 ],
-                "summary": "Update profile",
+                "summary": "Verify two-factor code",
                 "parameters": [
                     {
-                        "description": "Profile update",
+                        "description": "Pending token and code",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/internal_handler.updateProfileRequest"
+                            "$ref": "#/definitions/internal_handler.twoFactorVerifyRequest"
                         }
                     }
                 ],
@@ -495,7 +828,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/internal_handler.updateProfileResponse"
+                            "$ref": "#/definitions/internal_handler.authResponse"
                         }
                     },
                     "400": {
@@ -510,6 +843,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/internal_handler.errorResponse"
                         }
                     },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -519,9 +858,40 @@ const docTemplate = `{
                 }
             }
         },
-        "/auth/register": {
+        "/auth/events": {
+            "get": {
+                "description": "Get the most recent login attempts (newest first, up to 100)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "List login events",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.loginEventListResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/auth/login": {
             "post": {
-                "description": "Register a new user (only if none exists)",
+                "description": "Authenticate a user with username or email and get a JWT token.\nWhen two-factor authentication is enabled, returns requires2fa with a pending token instead.",
                 "consumes": [
                     "application/json"
                 ],
@@ -531,15 +901,15 @@ const docTemplate = `{
                 "tags": [
                     "auth"
                 ],
-                "summary": "Register user",
+                "summary": "Login",
                 "parameters": [
                     {
-                        "description": "Registration info",
+                        "description": "Login credentials",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/internal_handler.registerRequest"
+                            "$ref": "#/definitions/internal_handler.loginRequest"
                         }
                     }
                 ],
@@ -556,8 +926,14 @@ const docTemplate = `{
                             "$ref": "#/definitions/internal_handler.errorResponse"
                         }
                     },
-                    "409": {
-                        "description": "Conflict",
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
                         "schema": {
                             "$ref": "#/definitions/internal_handler.errorResponse"
                         }
@@ -571,118 +947,51 @@ const docTemplate = `{
                 }
             }
         },
-        "/auth/status": {
-            "get": {
-                "description": "Check if a user has been registered",
+        "/auth/logout": {
+            "post": {
+                "description": "Clear authentication cookie and log out the user",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "auth"
                 ],
-                "summary": "Check user status",
+                "summary": "Logout",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/internal_handler.authStatusResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/internal_handler.errorResponse"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     }
                 }
             }
         },
-        "/entries": {
+        "/auth/me": {
             "get": {
-                "description": "Get a list of entries with optional filters and pagination",
+                "description": "Get the currently authenticated user's info",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "entries"
-                ],
-                "summary": "List entries",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Filter by feed ID",
-                        "name": "feedId",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Filter by folder ID",
-                        "name": "folderId",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Filter by content type (article, picture, notification)",
-                        "name": "contentType",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "Only return unread entries",
-                        "name": "unreadOnly",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "Only return starred entries",
-                        "name": "starredOnly",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Limit the number of entries (default 50)",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Offset for pagination",
-                        "name": "offset",
-                        "in": "query"
-                    }
+                    "auth"
                 ],
+                "summary": "Get current user",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/internal_handler.entryListResponse"
+                            "$ref": "#/definitions/internal_handler.userResponse"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/internal_handler.errorResponse"
                         }
-                    }
-                }
-            }
-        },
-        "/entries/cache": {
-            "delete": {
-                "description": "Delete all unstarred entries (preserves starred entries). Also resets all feeds' ETag/Last-Modified to force full refresh on next update.",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "entries"
-                ],
-                "summary": "Clear entry cache",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/internal_handler.entryClearResponse"
-                        }
                     },
                     "500": {
                         "description": "Internal Server Error",
@@ -690,12 +999,17 @@ const docTemplate = `{
                             "$ref": "#/definitions/internal_handler.errorResponse"
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
             }
         },
-        "/entries/mark-read": {
-            "post": {
-                "description": "Mark all entries as read, optionally filtered by feed, folder, or content type",
+        "/auth/profile": {
+            "put": {
+                "description": "Update user nickname, email and/or password. Returns new token when password is changed.",
                 "consumes": [
                     "application/json"
                 ],
@@ -703,36 +1017,56 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "entries"
+                    "auth"
                 ],
-                "summary": "Mark all as read",
+                "summary": "Update profile",
                 "parameters": [
                     {
-                        "description": "Filter criteria",
+                        "description": "Profile update",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/internal_handler.markAllReadRequest"
+                            "$ref": "#/definitions/internal_handler.updateProfileRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.updateProfileResponse"
+                        }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/internal_handler.errorResponse"
                         }
-                    }
-                }
-            }
-        },
-        "/entries/read": {
-            "patch": {
-                "description": "Mark entries as read or unread by ID list",
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/auth/register": {
+            "post": {
+                "description": "Register a new user (only if none exists)",
                 "consumes": [
                     "application/json"
                 ],
@@ -740,48 +1074,37 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "entries"
+                    "auth"
                 ],
-                "summary": "Update read status for entries",
+                "summary": "Register user",
                 "parameters": [
                     {
-                        "description": "Read status for entries",
-                        "name": "read",
+                        "description": "Registration info",
+                        "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/internal_handler.updateManyReadRequest"
+                            "$ref": "#/definitions/internal_handler.registerRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.authResponse"
+                        }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/internal_handler.errorResponse"
                         }
-                    }
-                }
-            }
-        },
-        "/entries/readability-cache": {
-            "delete": {
-                "description": "Delete all extracted readable content from entries",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "entries"
-                ],
-                "summary": "Clear readability cache",
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                    },
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
-                            "$ref": "#/definitions/internal_handler.entryClearResponse"
+                            "$ref": "#/definitions/internal_handler.errorResponse"
                         }
                     },
                     "500": {
@@ -793,40 +1116,25 @@ const docTemplate = `{
                 }
             }
         },
-        "/entries/{id}": {
+        "/auth/status": {
             "get": {
-                "description": "Get a single entry by its ID",
+                "description": "Check if a user has been registered",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "entries"
-                ],
-                "summary": "Get entry",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Entry ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "auth"
                 ],
+                "summary": "Check user status",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/internal_handler.entryResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/internal_handler.errorResponse"
+                            "$ref": "#/definitions/internal_handler.authStatusResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/internal_handler.errorResponse"
                         }
@@ -834,50 +1142,38 @@ const docTemplate = `{
                 }
             }
         },
-        "/entries/{id}/fetch-readable": {
-            "post": {
-                "description": "Extract readable content from the entry's original URL using readability",
+        "/auth/tokens": {
+            "get": {
+                "description": "List personal access tokens. Token values are never returned after creation.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "entries"
-                ],
-                "summary": "Fetch readable content",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Entry ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "auth"
                 ],
+                "summary": "List API tokens",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/internal_handler.readableContentResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/internal_handler.errorResponse"
+                            "$ref": "#/definitions/internal_handler.apiTokenListResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/internal_handler.errorResponse"
                         }
                     }
-                }
-            }
-        },
-        "/entries/{id}/read": {
-            "patch": {
-                "description": "Mark an entry as read or unread",
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Create a personal access token for non-browser clients. The token value is shown only once.",
                 "consumes": [
                     "application/json"
                 ],
@@ -885,30 +1181,26 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "entries"
+                    "auth"
                 ],
-                "summary": "Update read status",
+                "summary": "Create API token",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Entry ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Read status",
-                        "name": "read",
+                        "description": "Token info (expiresAt is RFC3339, optional)",
+                        "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/internal_handler.updateReadRequest"
+                            "$ref": "#/definitions/internal_handler.createAPITokenRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.createdAPITokenResponse"
+                        }
                     },
                     "400": {
                         "description": "Bad Request",
@@ -916,44 +1208,34 @@ const docTemplate = `{
                             "$ref": "#/definitions/internal_handler.errorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/internal_handler.errorResponse"
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
             }
         },
-        "/entries/{id}/starred": {
-            "patch": {
-                "description": "Mark an entry as starred or unstarred",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
+        "/auth/tokens/{id}": {
+            "delete": {
+                "description": "Revoke a personal access token. Takes effect immediately.",
                 "tags": [
-                    "entries"
+                    "auth"
                 ],
-                "summary": "Update starred status",
+                "summary": "Revoke API token",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Entry ID",
+                        "type": "string",
+                        "description": "Token ID",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Starred status",
-                        "name": "starred",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/internal_handler.updateStarredRequest"
-                        }
                     }
                 ],
                 "responses": {
@@ -972,67 +1254,72 @@ const docTemplate = `{
                             "$ref": "#/definitions/internal_handler.errorResponse"
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
             }
         },
-        "/feeds": {
+        "/bootstrap": {
             "get": {
-                "description": "Get a list of all subscribed feeds",
+                "description": "Get what GET /folders, /feeds, /unread-counts, /starred-count, /feeds/refresh and /settings/appearance return, in one response with compact feeds. Send the ETag back in If-None-Match to get 304 while nothing changed.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "feeds"
-                ],
-                "summary": "List feeds",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Filter by folder ID",
-                        "name": "folderId",
-                        "in": "query"
-                    }
+                    "bootstrap"
                 ],
+                "summary": "Get sidebar bootstrap",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/internal_handler.feedResponse"
-                            }
+                            "$ref": "#/definitions/internal_handler.bootstrapResponse"
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified"
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
                         }
                     }
                 }
-            },
-            "post": {
-                "description": "Subscribe to a new RSS/Atom feed",
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/digest": {
+            "get": {
+                "description": "Get entries published on a day, grouped by folder, capped per feed, with unread counts and cached AI summaries. The day is interpreted in the configured timezone.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "feeds"
+                    "entries"
                 ],
-                "summary": "Create a feed",
+                "summary": "Get daily digest",
                 "parameters": [
                     {
-                        "description": "Feed creation request",
-                        "name": "feed",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/internal_handler.createFeedRequest"
-                        }
+                        "type": "string",
+                        "description": "Day as YYYY-MM-DD (default today)",
+                        "name": "date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum entries per feed (default 5, max 50)",
+                        "name": "perFeed",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/internal_handler.feedResponse"
+                            "$ref": "#/definitions/internal_handler.dailyDigestResponse"
                         }
                     },
                     "400": {
@@ -1040,38 +1327,133 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/internal_handler.errorResponse"
                         }
-                    },
-                    "409": {
-                        "description": "Feed URL already exists",
-                        "schema": {
-                            "$ref": "#/definitions/internal_handler.feedConflictResponse"
-                        }
                     }
                 }
-            },
-            "delete": {
-                "description": "Unsubscribe from multiple feeds at once",
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/entries": {
+            "get": {
+                "description": "Get a list of entries with optional filters and pagination.\nWith since or If-Modified-Since (without If-None-Match) the list is a delta: only entries updated since then,\nwithout content unless include has content, plus the ids of entries deleted since then. Deltas reaching back\nfurther than deleted entries are remembered are full lists. Last-Modified is the time to ask from next.",
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "feeds"
+                    "entries"
                 ],
-                "summary": "Delete multiple feeds",
+                "summary": "List entries",
                 "parameters": [
                     {
-                        "description": "Feed IDs to delete",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/internal_handler.deleteFeedsRequest"
-                        }
+                        "type": "integer",
+                        "description": "Filter by feed ID",
+                        "name": "feedId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter by folder ID",
+                        "name": "folderId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by content type (article, picture, notification)",
+                        "name": "contentType",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only return unread entries",
+                        "name": "unreadOnly",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only return starred entries",
+                        "name": "starredOnly",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only return entries with a note",
+                        "name": "notesOnly",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only return entries whose readable content has been extracted",
+                        "name": "hasReadableContent",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only return entries with a cached AI summary",
+                        "name": "hasSummary",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Summary language for hasSummary (default: the configured summary language)",
+                        "name": "language",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Entry language as a primary subtag, e.g. en",
+                        "name": "contentLanguage",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Minimum estimated reading time in minutes",
+                        "name": "minReadingMinutes",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum estimated reading time in minutes",
+                        "name": "maxReadingMinutes",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated: feed to inline the feed title, icon and type of each entry, content to keep content in a delta",
+                        "name": "include",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "List only entries updated since this RFC 3339 time",
+                        "name": "since",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "List only entries updated since this HTTP date; answered 304 when nothing changed",
+                        "name": "If-Modified-Since",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit the number of entries (default 50)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Offset for pagination",
+                        "name": "offset",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.entryListResponse"
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified"
                     },
                     "400": {
                         "description": "Bad Request",
@@ -1082,30 +1464,35 @@ const docTemplate = `{
                 }
             }
         },
-        "/feeds/preview": {
-            "get": {
-                "description": "Fetch information about a feed from its URL",
+        "/entries/bulk": {
+            "post": {
+                "description": "Get up to 100 entries with full content in the order requested. Unknown IDs are left out.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "feeds"
+                    "entries"
                 ],
-                "summary": "Preview a feed",
+                "summary": "Get entries by IDs",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Feed URL",
-                        "name": "url",
-                        "in": "query",
-                        "required": true
+                        "description": "Entry IDs",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.bulkEntriesRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/internal_handler.feedPreviewResponse"
+                            "$ref": "#/definitions/internal_handler.bulkEntriesResponse"
                         }
                     },
                     "400": {
@@ -1117,37 +1504,25 @@ const docTemplate = `{
                 }
             }
         },
-        "/feeds/refresh": {
-            "get": {
-                "description": "Get the current refresh status including whether a refresh is in progress and when the last refresh completed",
+        "/entries/cache": {
+            "delete": {
+                "description": "Delete all unstarred entries (preserves starred entries). Also resets all feeds' ETag/Last-Modified to force full refresh on next update.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "feeds"
+                    "entries"
                 ],
-                "summary": "Get refresh status",
+                "summary": "Clear entry cache",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/internal_handler.refreshStatusResponse"
+                            "$ref": "#/definitions/internal_handler.entryClearResponse"
                         }
-                    }
-                }
-            },
-            "post": {
-                "description": "Trigger an immediate refresh of all subscribed feeds",
-                "tags": [
-                    "feeds"
-                ],
-                "summary": "Refresh all feeds",
-                "responses": {
-                    "204": {
-                        "description": "No Content"
                     },
-                    "409": {
-                        "description": "Refresh already in progress",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/internal_handler.errorResponse"
                         }
@@ -1155,44 +1530,100 @@ const docTemplate = `{
                 }
             }
         },
-        "/feeds/{id}": {
-            "put": {
-                "description": "Update an existing feed. title is required; folder and summary prompt reminder are optional.",
-                "consumes": [
-                    "application/json"
-                ],
+        "/entries/counts": {
+            "get": {
+                "description": "Count the entries List would return for the same filters, without loading them",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "feeds"
+                    "entries"
                 ],
-                "summary": "Update a feed",
+                "summary": "Count entries",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Feed ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Filter by feed ID",
+                        "name": "feedId",
+                        "in": "query"
                     },
                     {
-                        "description": "Feed update request",
-                        "name": "feed",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/internal_handler.updateFeedRequest"
-                        }
+                        "type": "integer",
+                        "description": "Filter by folder ID",
+                        "name": "folderId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by content type (article, picture, notification)",
+                        "name": "contentType",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only count unread entries",
+                        "name": "unreadOnly",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only count starred entries",
+                        "name": "starredOnly",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only count entries with a note",
+                        "name": "notesOnly",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only count entries whose readable content has been extracted",
+                        "name": "hasReadableContent",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only count entries with a cached AI summary",
+                        "name": "hasSummary",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Summary language for hasSummary (default: the configured summary language)",
+                        "name": "language",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Entry language as a primary subtag, e.g. en",
+                        "name": "contentLanguage",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Minimum estimated reading time in minutes",
+                        "name": "minReadingMinutes",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum estimated reading time in minutes",
+                        "name": "maxReadingMinutes",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/internal_handler.feedResponse"
+                            "$ref": "#/definitions/internal_handler.entryCountResponse"
                         }
                     },
+                    "304": {
+                        "description": "Not Modified"
+                    },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
@@ -1206,20 +1637,30 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Unsubscribe from a feed",
+            }
+        },
+        "/entries/mark-read": {
+            "post": {
+                "description": "Mark all entries as read, optionally filtered by feed, folder, or content type",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "feeds"
+                    "entries"
                 ],
-                "summary": "Delete a feed",
+                "summary": "Mark all as read",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Feed ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Filter criteria",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.markAllReadRequest"
+                        }
                     }
                 ],
                 "responses": {
@@ -1231,41 +1672,31 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/internal_handler.errorResponse"
                         }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/internal_handler.errorResponse"
-                        }
                     }
                 }
             }
         },
-        "/feeds/{id}/type": {
+        "/entries/read": {
             "patch": {
-                "description": "Change the content type of a feed (article/picture/notification)",
+                "description": "Mark entries as read or unread by ID list",
                 "consumes": [
                     "application/json"
                 ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "feeds"
+                    "entries"
                 ],
-                "summary": "Update feed type",
+                "summary": "Update read status for entries",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Feed ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Type update request",
-                        "name": "request",
+                        "description": "Read status for entries",
+                        "name": "read",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/internal_handler.updateTypeRequest"
+                            "$ref": "#/definitions/internal_handler.updateManyReadRequest"
                         }
                     }
                 ],
@@ -1278,102 +1709,29 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/internal_handler.errorResponse"
                         }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/internal_handler.errorResponse"
-                        }
                     }
                 }
             }
         },
-        "/folders": {
-            "get": {
-                "description": "Get a list of all folders",
+        "/entries/readability-cache": {
+            "delete": {
+                "description": "Delete all extracted readable content from entries, except that of archived starred entries",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "folders"
+                    "entries"
                 ],
-                "summary": "List folders",
+                "summary": "Clear readability cache",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/internal_handler.folderResponse"
-                            }
-                        }
-                    }
-                }
-            },
-            "post": {
-                "description": "Create a new folder to organize feeds",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "folders"
-                ],
-                "summary": "Create a folder",
-                "parameters": [
-                    {
-                        "description": "Folder creation request",
-                        "name": "folder",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/internal_handler.folderRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "201": {
-                        "description": "Created",
-                        "schema": {
-                            "type": "Created"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/internal_handler.errorResponse"
-                        }
-                    }
-                }
-            },
-            "delete": {
-                "description": "Delete multiple folders at once (also deletes feeds in them)",
-                "consumes": [
-                    "application/json"
-                ],
-                "tags": [
-                    "folders"
-                ],
-                "summary": "Delete multiple folders",
-                "parameters": [
-                    {
-                        "description": "Folder IDs to delete",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/internal_handler.deleteFoldersRequest"
+                            "$ref": "#/definitions/internal_handler.entryClearResponse"
                         }
-                    }
-                ],
-                "responses": {
-                    "204": {
-                        "description": "No Content"
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/internal_handler.errorResponse"
                         }
@@ -1381,42 +1739,72 @@ const docTemplate = `{
                 }
             }
         },
-        "/folders/{id}": {
-            "put": {
-                "description": "Update the name or parent ID of an existing folder",
-                "consumes": [
-                    "application/json"
-                ],
+        "/entries/search": {
+            "get": {
+                "description": "Full-text search over entries, most relevant first, optionally within a feed or a folder and its subfolders.\nBesides the feed's own fields, the readable content and AI summaries of entries are searched.\nUnread entries rank a little higher unless boostUnread is false.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "folders"
+                    "entries"
                 ],
-                "summary": "Update a folder",
+                "summary": "Search entries",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Folder ID",
-                        "name": "id",
-                        "in": "path",
+                        "type": "string",
+                        "description": "Search text; every word must match",
+                        "name": "q",
+                        "in": "query",
                         "required": true
                     },
                     {
-                        "description": "Folder update request",
-                        "name": "folder",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/internal_handler.folderRequest"
-                        }
+                        "type": "string",
+                        "description": "Where to match: title, content or all (default all, which also covers author and URL). Content includes readable content and AI summaries",
+                        "name": "searchIn",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Search within a feed",
+                        "name": "feedId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Search within a folder and its subfolders",
+                        "name": "folderId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Rank unread entries a little higher (default true); false orders by relevance alone",
+                        "name": "boostUnread",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Set to feed to inline the feed title, icon and type of each entry",
+                        "name": "include",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit the number of entries (default 50)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Offset for pagination",
+                        "name": "offset",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/internal_handler.folderResponse"
+                            "$ref": "#/definitions/internal_handler.entrySearchResponse"
                         }
                     },
                     "400": {
@@ -1432,34 +1820,27 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Delete an existing folder",
-                "tags": [
-                    "folders"
+            }
+        },
+        "/entries/starred/archive-status": {
+            "get": {
+                "description": "List starred entries whose full-content archival gave up after its retries. Starring an entry again retries its archival.",
+                "produces": [
+                    "application/json"
                 ],
-                "summary": "Delete a folder",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Folder ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
+                "tags": [
+                    "entries"
                 ],
+                "summary": "Get starred archive status",
                 "responses": {
-                    "204": {
-                        "description": "No Content"
-                    },
-                    "400": {
-                        "description": "Bad Request",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/internal_handler.errorResponse"
+                            "$ref": "#/definitions/internal_handler.archiveStatusResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/internal_handler.errorResponse"
                         }
@@ -1467,37 +1848,37 @@ const docTemplate = `{
                 }
             }
         },
-        "/folders/{id}/type": {
-            "patch": {
-                "description": "Change the content type of a folder (article/picture/notification)",
-                "consumes": [
+        "/entries/{id}": {
+            "get": {
+                "description": "Get a single entry by its ID",
+                "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "folders"
+                    "entries"
                 ],
-                "summary": "Update folder type",
+                "summary": "Get entry",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Folder ID",
+                        "description": "Entry ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Type update request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/internal_handler.updateFolderTypeRequest"
-                        }
+                        "type": "string",
+                        "description": "Set to feed to inline the feed title, icon and type",
+                        "name": "include",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.entryResponse"
+                        }
                     },
                     "400": {
                         "description": "Bad Request",
@@ -1514,79 +1895,170 @@ const docTemplate = `{
                 }
             }
         },
-        "/icons/cache": {
-            "delete": {
-                "description": "Delete all feed icon files and clear icon_path references in database",
+        "/entries/{id}/adjacent": {
+            "get": {
+                "description": "Get the entry that follows (next) or precedes (prev) an entry under the same filters as the list",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "icons"
+                    "entries"
                 ],
-                "summary": "Clear icon cache",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/internal_handler.iconClearResponse"
-                        }
+                "summary": "Get adjacent entry",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Entry ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/internal_handler.errorResponse"
-                        }
+                    {
+                        "type": "string",
+                        "description": "next (default) or prev",
+                        "name": "direction",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter by feed ID",
+                        "name": "feedId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter by folder ID",
+                        "name": "folderId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by content type (article, picture, notification)",
+                        "name": "contentType",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only consider unread entries",
+                        "name": "unreadOnly",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only consider starred entries",
+                        "name": "starredOnly",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only consider entries with a note",
+                        "name": "notesOnly",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only consider entries whose readable content has been extracted",
+                        "name": "hasReadableContent",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only consider entries with a cached AI summary",
+                        "name": "hasSummary",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Summary language for hasSummary (default: the configured summary language)",
+                        "name": "language",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Entry language as a primary subtag, e.g. en",
+                        "name": "contentLanguage",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Minimum estimated reading time in minutes",
+                        "name": "minReadingMinutes",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum estimated reading time in minutes",
+                        "name": "maxReadingMinutes",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Set to feed to inline the feed title, icon and type",
+                        "name": "include",
+                        "in": "query"
                     }
-                }
-            }
-        },
-        "/opml/export": {
-            "get": {
-                "description": "Export all feeds and folders to an OPML file",
-                "produces": [
-                    "text/xml"
-                ],
-                "tags": [
-                    "opml"
                 ],
-                "summary": "Export OPML",
                 "responses": {
                     "200": {
-                        "description": "OPML file content",
+                        "description": "OK",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/internal_handler.entryResponse"
+                        }
+                    },
+                    "204": {
+                        "description": "No adjacent entry"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
                         }
                     }
                 }
             }
         },
-        "/opml/import": {
+        "/entries/{id}/fetch-readable": {
             "post": {
-                "description": "Start importing feeds and folders from an OPML file",
-                "consumes": [
-                    "multipart/form-data",
-                    "text/xml"
-                ],
+                "description": "Extract readable content from the entry's original URL using readability. PDF and plain-text links are converted to paragraphs. Unless cached, extraction runs in the background and 202 is returned with status pending; poll again or wait for the entry.readable event. A failed extraction is returned by the next request, and the one after retries. wait=true blocks until the content is ready instead.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "opml"
+                    "entries"
                 ],
-                "summary": "Import OPML",
+                "summary": "Fetch readable content",
                 "parameters": [
                     {
-                        "type": "file",
-                        "description": "OPML file to import",
-                        "name": "file",
-                        "in": "formData"
+                        "type": "integer",
+                        "description": "Entry ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Wait for the extraction to finish",
+                        "name": "wait",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/internal_handler.importStartedResponse"
+                            "$ref": "#/definitions/internal_handler.readableContentResponse"
+                        }
+                    },
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.readableContentResponse"
                         }
                     },
                     "400": {
@@ -1595,107 +2067,97 @@ const docTemplate = `{
                             "$ref": "#/definitions/internal_handler.errorResponse"
                         }
                     },
-                    "413": {
-                        "description": "Request Entity Too Large",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/internal_handler.errorResponse"
                         }
-                    }
-                }
-            },
-            "delete": {
-                "description": "Cancel the current import task",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "opml"
-                ],
-                "summary": "Cancel Import",
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
                         "schema": {
-                            "$ref": "#/definitions/internal_handler.importCancelledResponse"
+                            "$ref": "#/definitions/internal_handler.errorResponse"
                         }
                     }
                 }
             }
         },
-        "/opml/import/status": {
-            "get": {
-                "description": "Get current import task status via SSE stream",
+        "/entries/{id}/note": {
+            "put": {
+                "description": "Attach a note of up to 10 KB to an entry, replacing any previous one. A blank note deletes it.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
-                    "text/event-stream"
+                    "application/json"
                 ],
                 "tags": [
-                    "opml"
+                    "entries"
                 ],
-                "summary": "Import Status",
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                "summary": "Update entry note",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Entry ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Note",
+                        "name": "note",
+                        "in": "body",
+                        "required": true,
                         "schema": {
-                            "$ref": "#/definitions/gist_backend_internal_service.ImportTask"
+                            "$ref": "#/definitions/internal_handler.updateNoteRequest"
                         }
                     }
-                }
-            }
-        },
-        "/settings/ai": {
-            "get": {
-                "description": "Get the AI provider configuration with masked API keys",
-                "produces": [
-                    "application/json"
                 ],
-                "tags": [
-                    "settings"
-                ],
-                "summary": "Get AI settings",
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/internal_handler.aiSettingsResponse"
+                            "$ref": "#/definitions/internal_handler.errorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
                         "schema": {
                             "$ref": "#/definitions/internal_handler.errorResponse"
                         }
                     }
                 }
             },
-            "put": {
-                "description": "Update the AI provider configuration. Empty apiKey keeps existing key.",
-                "consumes": [
-                    "application/json"
-                ],
+            "delete": {
+                "description": "Remove the note attached to an entry",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "settings"
+                    "entries"
                 ],
-                "summary": "Update AI settings",
+                "summary": "Delete entry note",
                 "parameters": [
                     {
-                        "description": "AI settings",
-                        "name": "settings",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/internal_handler.aiSettingsRequest"
-                        }
+                        "type": "integer",
+                        "description": "Entry ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/internal_handler.aiSettingsResponse"
-                        }
+                    "204": {
+                        "description": "No Content"
                     },
                     "400": {
                         "description": "Bad Request",
@@ -1703,8 +2165,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/internal_handler.errorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/internal_handler.errorResponse"
                         }
@@ -1712,35 +2174,30 @@ const docTemplate = `{
                 }
             }
         },
-        "/settings/ai/test": {
-            "post": {
-                "description": "Test the AI provider connection with a \"Hello world\" message",
-                "consumes": [
-                    "application/json"
-                ],
+        "/entries/{id}/raw": {
+            "get": {
+                "description": "Return the feed item the entry was made from, as delivered by the last refresh and serialized to JSON, capped at 64KB. Items are only kept with the keepRawItems general setting, for 7 days.",
                 "produces": [
-                    "application/json"
+                    "text/plain"
                 ],
                 "tags": [
-                    "settings"
+                    "entries"
                 ],
-                "summary": "Test AI connection",
+                "summary": "Get entry source item",
                 "parameters": [
                     {
-                        "description": "AI test configuration",
-                        "name": "config",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/internal_handler.aiTestRequest"
-                        }
+                        "type": "integer",
+                        "description": "Entry ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Feed item as JSON",
                         "schema": {
-                            "$ref": "#/definitions/internal_handler.aiTestResponse"
+                            "type": "string"
                         }
                     },
                     "400": {
@@ -1748,29 +2205,9 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/internal_handler.errorResponse"
                         }
-                    }
-                }
-            }
-        },
-        "/settings/anubis-cookies": {
-            "delete": {
-                "description": "Delete all Anubis challenge cookies used for bypassing protection",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "settings"
-                ],
-                "summary": "Clear Anubis cookies",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/internal_handler.deletedCountResponse"
-                        }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/internal_handler.errorResponse"
                         }
@@ -1778,33 +2215,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/settings/appearance": {
-            "get": {
-                "description": "Get appearance settings including visible content types",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "settings"
-                ],
-                "summary": "Get appearance settings",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/internal_handler.appearanceSettingsResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/internal_handler.errorResponse"
-                        }
-                    }
-                }
-            },
-            "put": {
-                "description": "Update appearance settings including visible content types",
+        "/entries/{id}/read": {
+            "patch": {
+                "description": "Mark an entry as read or unread",
                 "consumes": [
                     "application/json"
                 ],
@@ -1812,26 +2225,30 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "settings"
+                    "entries"
                 ],
-                "summary": "Update appearance settings",
+                "summary": "Update read status",
                 "parameters": [
                     {
-                        "description": "Appearance settings",
-                        "name": "settings",
+                        "type": "integer",
+                        "description": "Entry ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Read status",
+                        "name": "read",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/internal_handler.appearanceSettingsRequest"
+                            "$ref": "#/definitions/internal_handler.updateReadRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/internal_handler.appearanceSettingsResponse"
-                        }
+                    "204": {
+                        "description": "No Content"
                     },
                     "400": {
                         "description": "Bad Request",
@@ -1839,8 +2256,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/internal_handler.errorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/internal_handler.errorResponse"
                         }
@@ -1848,33 +2265,50 @@ const docTemplate = `{
                 }
             }
         },
-        "/settings/general": {
+        "/entries/{id}/revisions": {
             "get": {
-                "description": "Get general application settings including fallback user agent, auto readability, and mark-read-on-scroll",
+                "description": "List previous content snapshots (newest first), each with a line diff against the version that replaced it",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "settings"
+                    "entries"
+                ],
+                "summary": "List entry revisions",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Entry ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get general settings",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/internal_handler.generalSettingsResponse"
+                            "$ref": "#/definitions/internal_handler.entryRevisionListResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/internal_handler.errorResponse"
                         }
                     }
                 }
-            },
-            "put": {
-                "description": "Update general application settings",
+            }
+        },
+        "/entries/{id}/starred": {
+            "patch": {
+                "description": "Mark an entry as starred or unstarred",
                 "consumes": [
                     "application/json"
                 ],
@@ -1882,26 +2316,30 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "settings"
+                    "entries"
                 ],
-                "summary": "Update general settings",
+                "summary": "Update starred status",
                 "parameters": [
                     {
-                        "description": "General settings",
-                        "name": "settings",
+                        "type": "integer",
+                        "description": "Entry ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Starred status",
+                        "name": "starred",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/internal_handler.generalSettingsRequest"
+                            "$ref": "#/definitions/internal_handler.updateStarredRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/internal_handler.generalSettingsResponse"
-                        }
+                    "204": {
+                        "description": "No Content"
                     },
                     "400": {
                         "description": "Bad Request",
@@ -1909,8 +2347,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/internal_handler.errorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/internal_handler.errorResponse"
                         }
@@ -1918,59 +2356,54 @@ const docTemplate = `{
                 }
             }
         },
-        "/settings/network": {
+        "/entries/{id}/text": {
             "get": {
-                "description": "Get the network proxy configuration with masked password",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "settings"
-                ],
-                "summary": "Get network settings",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/internal_handler.networkSettingsResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/internal_handler.errorResponse"
-                        }
-                    }
-                }
-            },
-            "put": {
-                "description": "Update the network proxy configuration. Empty password keeps existing password.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Render the entry content as plain text for text-to-speech clients: paragraphs are separated by blank lines, list items keep their bullets and numbers and images read as [Image: alt]. X-Text-Truncated tells whether maxChars cut the text.",
                 "produces": [
-                    "application/json"
+                    "text/plain"
                 ],
                 "tags": [
-                    "settings"
+                    "entries"
                 ],
-                "summary": "Update network settings",
+                "summary": "Get entry as plain text",
                 "parameters": [
                     {
-                        "description": "Network settings",
-                        "name": "settings",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/internal_handler.networkSettingsRequest"
-                        }
+                        "type": "integer",
+                        "description": "Entry ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Use the readable content, extracting it first when the entry has none",
+                        "name": "readability",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "omit (default) replaces code blocks with [code omitted]; drop removes them",
+                        "name": "code",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Cut the text to at most this many characters",
+                        "name": "maxChars",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Plain text",
                         "schema": {
-                            "$ref": "#/definitions/internal_handler.networkSettingsResponse"
+                            "type": "string"
+                        },
+                        "headers": {
+                            "X-Text-Truncated": {
+                                "type": "string",
+                                "description": "true when maxChars cut the text"
+                            }
                         }
                     },
                     "400": {
@@ -1979,8 +2412,20 @@ const docTemplate = `{
                             "$ref": "#/definitions/internal_handler.errorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
                         "schema": {
                             "$ref": "#/definitions/internal_handler.errorResponse"
                         }
@@ -1988,35 +2433,37 @@ const docTemplate = `{
                 }
             }
         },
-        "/settings/network/test": {
-            "post": {
-                "description": "Test the network proxy connection by accessing https://captive.apple.com/",
-                "consumes": [
-                    "application/json"
-                ],
+        "/entries/{id}/thumbnail": {
+            "get": {
+                "description": "Serve the entry thumbnail as a JPEG at most w pixels wide, generated on first request and cached on disk. When the image can't be fetched or decoded, a 1x1 transparent PNG is served instead and the image is not retried for an hour.",
                 "produces": [
-                    "application/json"
+                    "image/jpeg",
+                    "image/png"
                 ],
                 "tags": [
-                    "settings"
+                    "entries"
                 ],
-                "summary": "Test network proxy",
+                "summary": "Get entry thumbnail",
                 "parameters": [
                     {
-                        "description": "Network test configuration",
-                        "name": "config",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/internal_handler.networkTestRequest"
-                        }
+                        "type": "integer",
+                        "description": "Entry ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Width in pixels, 32 to 1600 (default 400)",
+                        "name": "w",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Thumbnail image",
                         "schema": {
-                            "$ref": "#/definitions/internal_handler.networkTestResponse"
+                            "type": "file"
                         }
                     },
                     "400": {
@@ -2024,695 +2471,5848 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/internal_handler.errorResponse"
                         }
-                    }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    }
                 }
             }
         },
-        "/starred-count": {
+        "/entries/{id}/visit": {
             "get": {
-                "description": "Get the total count of starred entries",
+                "description": "Record a click-through and redirect to the entry's URL. Referrer-Policy: no-referrer keeps the destination from seeing the Gist address. The entry is marked read unless markRead is false. Only http and https URLs are redirected to.",
+                "tags": [
+                    "entries"
+                ],
+                "summary": "Open original article",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Entry ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Mark the entry read (default true)",
+                        "name": "markRead",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "302": {
+                        "description": "Redirect to the entry URL"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/events": {
+            "get": {
+                "description": "Server-sent events for refresh runs (refresh.started, refresh.finished), new entries (entries.new with feedId and count), read and starred changes (entries.read, entry.starred) and feed and folder changes (feed.created, feed.updated, feed.deleted, folder.created, folder.updated, folder.deleted). Payloads only carry ids and counts. A comment line is sent every 20 seconds as heartbeat. Clients that fall too far behind are disconnected and should resync after reconnecting.",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Stream change events",
+                "responses": {
+                    "200": {
+                        "description": "Event stream",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/export/full": {
+            "get": {
+                "description": "Export folders, feeds (with conditional GET validators and icon reference) and entries as JSON that POST /import/full accepts. By default only unread, starred and annotated entries are included.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "entries"
+                    "backup"
+                ],
+                "summary": "Export full backup",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Entries to include: unread_starred (default), all, or none",
+                        "name": "entries",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Backup document",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/feeds": {
+            "get": {
+                "description": "Get a list of all subscribed feeds",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "feeds"
+                ],
+                "summary": "List feeds",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Filter by folder ID",
+                        "name": "folderId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Set to stats to inline activity stats",
+                        "name": "include",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Include cached title translations in this language, e.g. en-US",
+                        "name": "language",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Get starred count",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/internal_handler.starredCountResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/internal_handler.feedResponse"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Subscribe to a new RSS/Atom feed",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "feeds"
+                ],
+                "summary": "Create a feed",
+                "parameters": [
+                    {
+                        "description": "Feed creation request",
+                        "name": "feed",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.createFeedRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.feedResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Feed URL already exists; details carry existingFeedId and existingFeed",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Unsubscribe from multiple feeds at once",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "feeds"
+                ],
+                "summary": "Delete multiple feeds",
+                "parameters": [
+                    {
+                        "description": "Feed IDs to delete",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.deleteFeedsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/feeds/overlaps": {
+            "get": {
+                "description": "Get the pairs of feeds found after scheduled refreshes to share a site URL, or most of their 50 most recent entries, largest overlap first. Nothing is merged until requested.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "feeds"
+                ],
+                "summary": "List overlapping feeds",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/internal_handler.feedOverlapResponse"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/feeds/overlaps/{id}/merge": {
+            "post": {
+                "description": "Move the entries of the other feed of the pair onto the kept one, merging the ones both have along with their read and starred state, and move the other feed to the trash.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "feeds"
+                ],
+                "summary": "Merge overlapping feeds",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Overlap ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Feed to keep",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.mergeFeedOverlapRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.feedResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/feeds/preview": {
+            "get": {
+                "description": "Fetch information about a feed from its URL",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "feeds"
+                ],
+                "summary": "Preview a feed",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Feed URL",
+                        "name": "url",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.feedPreviewResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/feeds/refresh": {
+            "get": {
+                "description": "Get the current refresh status including whether a refresh is in progress and when the last refresh completed. During quiet hours paused is \"quiet_hours\" and pausedUntil is when scheduled refreshes resume; manual refreshes still run.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "feeds"
+                ],
+                "summary": "Get refresh status",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.refreshStatusResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Trigger an immediate refresh of all subscribed feeds",
+                "tags": [
+                    "feeds"
+                ],
+                "summary": "Refresh all feeds",
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "409": {
+                        "description": "Refresh already in progress",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/feeds/reorder": {
+            "put": {
+                "description": "Place feeds of one folder in the given order. Feeds not listed keep their position.",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "feeds"
+                ],
+                "summary": "Reorder feeds",
+                "parameters": [
+                    {
+                        "description": "Feed IDs in display order",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.reorderFeedsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/feeds/static": {
+            "post": {
+                "description": "Add a feed from a pasted RSS, Atom or JSON feed document, for sources the server cannot reach. The feed gets a synthetic static:// URL and is skipped by refreshes; send newer content with PUT /feeds/{id}/static. With a title and no content the feed starts empty, to be filled with POST /feeds/{id}/entries.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "feeds"
+                ],
+                "summary": "Create a static feed",
+                "parameters": [
+                    {
+                        "description": "Static feed creation request",
+                        "name": "feed",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.createStaticFeedRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.feedResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/feeds/stats": {
+            "get": {
+                "description": "Get entries in the last 7 days, last published time and total entry count for every feed",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "feeds"
+                ],
+                "summary": "Get feed activity stats",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/internal_handler.feedStatsResponse"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/feeds/{id}": {
+            "put": {
+                "description": "Update an existing feed. title is required; folder, summary prompt reminder and entry cap are optional.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "feeds"
+                ],
+                "summary": "Update a feed",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Feed ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Feed update request",
+                        "name": "feed",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.updateFeedRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.feedResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Unsubscribe from a feed. It can be restored for 7 days before it and its entries are purged.",
+                "tags": [
+                    "feeds"
+                ],
+                "summary": "Delete a feed",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Feed ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Change only the fields present in the body. Every field is validated before any is saved, so an invalid one leaves the feed unchanged. null clears folderId, summaryPromptReminder, assumeTimezone, userAgent, autoReadability and pausedUntil.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "feeds"
+                ],
+                "summary": "Patch a feed",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Feed ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fields to change",
+                        "name": "feed",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.patchFeedRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.feedResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/feeds/{id}/auto-readability": {
+            "patch": {
+                "description": "Set whether the feed's entries open in readability mode. Null inherits the choice of the feed's folders, then the autoReadability general setting.",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "feeds"
+                ],
+                "summary": "Update feed auto readability",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Feed ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Auto readability update request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.updateAutoReadabilityRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/feeds/{id}/auto-translate": {
+            "patch": {
+                "description": "Set whether list titles and summaries of the feed's new entries are translated in the background after each refresh: inherit follows the translateOnRefresh AI setting, on and off override it.",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "feeds"
+                ],
+                "summary": "Update feed auto translate",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Feed ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Auto translate update request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.updateAutoTranslateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/feeds/{id}/backfill-archive": {
+            "get": {
+                "description": "Get the progress of the latest archive backfill of a feed since the server started, null when there was none.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "feeds"
+                ],
+                "summary": "Get a feed's archive backfill",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Feed ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.feedArchiveBackfillResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Fetch the feed, then follow the archive pages its documents link to with rel=\"next\" (RFC 5005 paged feeds) or next_url (JSON Feed), saving their items like a refresh. Runs in the background; poll GET /feeds/{id}/backfill-archive for progress. Requests to each host keep to its rate limit. A page linking back to an earlier one or a page without items stops the backfill as failed. Regular refreshes never follow these links.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "feeds"
+                ],
+                "summary": "Backfill a feed's archive",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Feed ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Backfill options",
+                        "name": "options",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.archiveBackfillRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.archiveBackfillResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "A backfill of the feed is already running",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/feeds/{id}/dedupe-key": {
+            "patch": {
+                "description": "Set the entry hash strategy: auto (GUID, then link, then title and content), guid, url or title_content. Links wrapped in tracking redirects are unwrapped first. Switching to url or title_content re-hashes stored entries in the background and merges duplicates.",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "feeds"
+                ],
+                "summary": "Update feed dedupe key",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Feed ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Dedupe key update request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.updateDedupeKeyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/feeds/{id}/entries": {
+            "post": {
+                "description": "Save up to 200 items (2 MB) into a static feed, authenticated with the feed's ingest token instead of a login. Items are deduplicated like fetched ones, by externalId when given, else by url; items linking to the same page are merged. Items without a url need an externalId and are skipped otherwise.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "feeds"
+                ],
+                "summary": "Push entries into a feed",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Feed ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Items to save",
+                        "name": "items",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/internal_handler.ingestItemRequest"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ingestResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/feeds/{id}/fetch-log": {
+            "get": {
+                "description": "Get the last 50 refresh attempts of a feed, newest first, with their HTTP status, latency and size. The status is ok while nearly all attempts succeed, erroring after several failures in a row, and unstable when failures come and go.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "feeds"
+                ],
+                "summary": "Get a feed's fetch log",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Feed ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.feedFetchLogResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/feeds/{id}/ingest-report": {
+            "get": {
+                "description": "Get the items the last refresh of a feed skipped (no link, duplicate hash, beyond the initial backfill limit) or saved altered (truncated title or author, unparsed date), with counts per reason and up to 50 examples. The report is null when every item was taken as delivered.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "feeds"
+                ],
+                "summary": "Get a feed's ingest report",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Feed ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.feedIngestReportResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/feeds/{id}/ingest-token": {
+            "post": {
+                "description": "Generate the bearer token for POST /feeds/{id}/entries, replacing the previous one. The token is only shown once.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "feeds"
+                ],
+                "summary": "Create a feed ingest token",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Feed ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ingestTokenResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/feeds/{id}/muted-authors": {
+            "get": {
+                "description": "List the authors whose entries are left out of the feed's unread list.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "feeds"
+                ],
+                "summary": "List muted authors",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Feed ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.mutedAuthorsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Leave the author's entries out of unread lists and counts, and mark their new entries read as they arrive. Authors match ignoring case and surrounding spaces.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "feeds"
+                ],
+                "summary": "Mute an author",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Feed ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Author to mute",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.mutedAuthorRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.mutedAuthorsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Show the author's unread entries again. Entries marked read while muted stay read.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "feeds"
+                ],
+                "summary": "Unmute an author",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Feed ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Author to unmute",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.mutedAuthorRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.mutedAuthorsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/feeds/{id}/pause": {
+            "post": {
+                "description": "Skip the feed in bulk refreshes and unread counts until a time, given as a duration or an RFC3339 timestamp. Refreshing the feed by hand ends the pause.",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "feeds"
+                ],
+                "summary": "Pause a feed",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Feed ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Pause request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.pauseFeedRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "tags": [
+                    "feeds"
+                ],
+                "summary": "Unpause a feed",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Feed ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/feeds/{id}/probe": {
+            "post": {
+                "description": "Fetch the feed once outside the refresh path and report resolved IPs, timing, status, caching headers, the first 2 KB of the body, Anubis detection and parse results. Conditional GET headers are not sent and Anubis challenges are not solved. Nothing is stored and the feed's error message is left alone.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "feeds"
+                ],
+                "summary": "Probe a feed",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Feed ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User agent to use: default or fallback; the one a refresh would start with if empty",
+                        "name": "userAgent",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.feedProbeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/feeds/{id}/refresh": {
+            "post": {
+                "description": "Fetch one feed now, clearing its pause. If a refresh of the feed is already in progress, the request waits for it and returns 409 with the refresh status in details.status instead of fetching again.",
+                "tags": [
+                    "feeds"
+                ],
+                "summary": "Refresh a feed",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Feed ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Feed refresh already in progress",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/feeds/{id}/restore": {
+            "post": {
+                "description": "Restore a feed deleted within the last 7 days, with its entries",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "feeds"
+                ],
+                "summary": "Restore a deleted feed",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Feed ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.feedResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/feeds/{id}/static": {
+            "put": {
+                "description": "Save the items of a newer copy of the feed document. Items already stored are matched by hash, so sending the same content twice adds nothing.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "feeds"
+                ],
+                "summary": "Update a static feed",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Feed ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Feed content",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.updateStaticFeedRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.staticFeedUpdateResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/feeds/{id}/timezone": {
+            "patch": {
+                "description": "Set the IANA timezone used for item dates without zone info. Null or empty falls back to the global timezone setting.",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "feeds"
+                ],
+                "summary": "Update feed timezone",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Feed ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Timezone update request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.updateTimezoneRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/feeds/{id}/type": {
+            "patch": {
+                "description": "Change the content type of a feed (article/picture/notification)",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "feeds"
+                ],
+                "summary": "Update feed type",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Feed ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Type update request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.updateTypeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/feeds/{id}/user-agent": {
+            "patch": {
+                "description": "Set a custom User-Agent sent by the feed's refreshes in place of the default one; the fallback User-Agent setting still applies when it is rejected. Null or blank inherits the User-Agent of the feed's folders.",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "feeds"
+                ],
+                "summary": "Update feed user agent",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Feed ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "User agent update request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.updateUserAgentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/folders": {
+            "get": {
+                "description": "Get a list of all folders",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "folders"
+                ],
+                "summary": "List folders",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/internal_handler.folderResponse"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a new folder to organize feeds",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "folders"
+                ],
+                "summary": "Create a folder",
+                "parameters": [
+                    {
+                        "description": "Folder creation request",
+                        "name": "folder",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.folderRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "type": "Created"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete multiple folders at once (also deletes feeds in them)",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "folders"
+                ],
+                "summary": "Delete multiple folders",
+                "parameters": [
+                    {
+                        "description": "Folder IDs to delete",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.deleteFoldersRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/folders/reorder": {
+            "put": {
+                "description": "Place folders under one parent in the given order. Folders not listed keep their position.",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "folders"
+                ],
+                "summary": "Reorder folders",
+                "parameters": [
+                    {
+                        "description": "Folder IDs in display order",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.reorderFoldersRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/folders/rules/apply": {
+            "post": {
+                "description": "Run the folder rules over feeds without a folder and move the ones that match",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "folders"
+                ],
+                "summary": "Apply folder rules",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.applyFolderRulesResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/folders/{id}": {
+            "put": {
+                "description": "Update the name or parent ID of an existing folder",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "folders"
+                ],
+                "summary": "Update a folder",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Folder ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Folder update request",
+                        "name": "folder",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.folderRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.folderResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Move a folder, its subfolders and their feeds to the trash. They can be restored for 7 days.",
+                "tags": [
+                    "folders"
+                ],
+                "summary": "Delete a folder",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Folder ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/folders/{id}/feed-defaults": {
+            "put": {
+                "description": "Replace the options the feeds in the folder and its subfolders inherit: a custom User-Agent for refreshes, opening entries in readability mode and translating list titles after refreshes. A feed's own value or a nearer folder's beats them; null inherits from the parent folder, then the global setting. Feeds are not rewritten, so the change applies to them at once.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "folders"
+                ],
+                "summary": "Update folder feed defaults",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Folder ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Feed defaults",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.updateFolderFeedDefaultsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.folderResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/folders/{id}/restore": {
+            "post": {
+                "description": "Restore a folder deleted within the last 7 days, together with the subfolders and feeds deleted with it",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "folders"
+                ],
+                "summary": "Restore a deleted folder",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Folder ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.folderResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/folders/{id}/rules": {
+            "get": {
+                "description": "Get the rules that file new feeds into a folder, in evaluation order",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "folders"
+                ],
+                "summary": "List folder rules",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Folder ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/internal_handler.folderRuleResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Add a rule that files feeds added without a folder into this one. Host patterns also match subdomains; url and title patterns are case-insensitive globs (* and ?) or /regular expressions/. Rules are tried by ascending priority.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "folders"
+                ],
+                "summary": "Create a folder rule",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Folder ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Folder rule",
+                        "name": "rule",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.folderRuleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.folderRuleResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/folders/{id}/rules/{ruleId}": {
+            "put": {
+                "description": "Replace the pattern, match field and priority of a folder rule",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "folders"
+                ],
+                "summary": "Update a folder rule",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Folder ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Rule ID",
+                        "name": "ruleId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Folder rule",
+                        "name": "rule",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.folderRuleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.folderRuleResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a folder rule. Feeds it already filed stay where they are.",
+                "tags": [
+                    "folders"
+                ],
+                "summary": "Delete a folder rule",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Folder ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Rule ID",
+                        "name": "ruleId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/folders/{id}/type": {
+            "patch": {
+                "description": "Change the content type of a folder (article/picture/notification)",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "folders"
+                ],
+                "summary": "Update folder type",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Folder ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Type update request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.updateFolderTypeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/healthz": {
+            "get": {
+                "description": "Returns build information. Does not touch the database.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Liveness probe",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.healthResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/icons/cache": {
+            "delete": {
+                "description": "Delete all feed icon files and clear icon_path references in database",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "icons"
+                ],
+                "summary": "Clear icon cache",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.iconClearResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/import/full": {
+            "post": {
+                "description": "Import a full backup. Feeds are matched by canonical URL and entries by hash, so importing twice is harmless; existing entries only take over read and starred state.",
+                "consumes": [
+                    "application/json",
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "backup"
+                ],
+                "summary": "Import full backup",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "Backup file to import",
+                        "name": "file",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.backupImportResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/opml/export": {
+            "get": {
+                "description": "Export all feeds and folders to an OPML file",
+                "produces": [
+                    "text/xml"
+                ],
+                "tags": [
+                    "opml"
+                ],
+                "summary": "Export OPML",
+                "responses": {
+                    "200": {
+                        "description": "OPML file content",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/opml/import": {
+            "post": {
+                "description": "Start importing feeds and folders from an OPML file",
+                "consumes": [
+                    "multipart/form-data",
+                    "text/xml"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "opml"
+                ],
+                "summary": "Import OPML",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "OPML file to import",
+                        "name": "file",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Items each new feed brings in on its first refresh: all (default), none (saved as read), or a count of the most recent items",
+                        "name": "initialBackfill",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.importStartedResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Cancel the current import task",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "opml"
+                ],
+                "summary": "Cancel Import",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.importCancelledResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/opml/import/status": {
+            "get": {
+                "description": "Get current import task status via SSE stream",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "opml"
+                ],
+                "summary": "Import Status",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/gist_backend_internal_service.ImportTask"
+                        }
+                    }
+                }
+            }
+        },
+        "/readyz": {
+            "get": {
+                "description": "Checks the database connection, data directory writability and migration state. Warnings, such as feeds flagged for suspect caching upstream or unstable over their recent refreshes, don't affect readiness.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Readiness probe",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.readinessResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.readinessResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/refresh/runs": {
+            "get": {
+                "description": "Get the most recent refresh runs with aggregate counts, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "feeds"
+                ],
+                "summary": "List refresh runs",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/internal_handler.refreshRunResponse"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/refresh/runs/{id}": {
+            "get": {
+                "description": "Get a refresh run and the outcome of each feed, failed feeds first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "feeds"
+                ],
+                "summary": "Get a refresh run",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Refresh run ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.refreshRunDetailResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/saved": {
+            "get": {
+                "description": "Save a page like POST /saved and redirect to the saved entry in the web app. Meant for bookmarklets, which are authenticated by the session cookie.",
+                "tags": [
+                    "saved"
+                ],
+                "summary": "Save a page from a bookmarklet",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Page URL",
+                        "name": "url",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "303": {
+                        "description": "Redirect to the saved entry"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Fetch a page and store it as an entry of the built-in Saved feed, which is created on first use. Saving a URL again returns the entry saved the first time without fetching the page.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "saved"
+                ],
+                "summary": "Save a page",
+                "parameters": [
+                    {
+                        "description": "Page to save",
+                        "name": "page",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.savePageRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Already saved",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.entryResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.entryResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/saved/{entryId}": {
+            "delete": {
+                "description": "Delete an entry of the Saved feed. Entries of other feeds are not found.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "saved"
+                ],
+                "summary": "Delete a saved page",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Entry ID",
+                        "name": "entryId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/settings/ai": {
+            "get": {
+                "description": "Get the AI provider configuration with masked API keys",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "settings"
+                ],
+                "summary": "Get AI settings",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.aiSettingsResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update the AI provider configuration. Empty apiKey keeps existing key.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "settings"
+                ],
+                "summary": "Update AI settings",
+                "parameters": [
+                    {
+                        "description": "AI settings",
+                        "name": "settings",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.aiSettingsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.aiSettingsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/settings/ai/test": {
+            "post": {
+                "description": "Test the AI provider connection with a \"Hello world\" message",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "settings"
+                ],
+                "summary": "Test AI connection",
+                "parameters": [
+                    {
+                        "description": "AI test configuration",
+                        "name": "config",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.aiTestRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.aiTestResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/settings/anubis-cookies": {
+            "delete": {
+                "description": "Delete all Anubis challenge cookies used for bypassing protection",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "settings"
+                ],
+                "summary": "Clear Anubis cookies",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.deletedCountResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/settings/appearance": {
+            "get": {
+                "description": "Get appearance settings including visible content types",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "settings"
+                ],
+                "summary": "Get appearance settings",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.appearanceSettingsResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update appearance settings including visible content types",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "settings"
+                ],
+                "summary": "Update appearance settings",
+                "parameters": [
+                    {
+                        "description": "Appearance settings",
+                        "name": "settings",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.appearanceSettingsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.appearanceSettingsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/settings/general": {
+            "get": {
+                "description": "Get general application settings including fallback user agent, auto readability, and mark-read-on-scroll",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "settings"
+                ],
+                "summary": "Get general settings",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.generalSettingsResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update general application settings. Concurrency changes apply from the next refresh run; Anubis retry changes apply to the next fetch.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "settings"
+                ],
+                "summary": "Update general settings",
+                "parameters": [
+                    {
+                        "description": "General settings",
+                        "name": "settings",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.generalSettingsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.generalSettingsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/settings/network": {
+            "get": {
+                "description": "Get the network proxy configuration with masked password",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "settings"
+                ],
+                "summary": "Get network settings",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.networkSettingsResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update the network proxy configuration. Empty password keeps existing password.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "settings"
+                ],
+                "summary": "Update network settings",
+                "parameters": [
+                    {
+                        "description": "Network settings",
+                        "name": "settings",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.networkSettingsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.networkSettingsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/settings/network/test": {
+            "post": {
+                "description": "Fetch testUrl (default https://captive.apple.com/) through the proxy and report the latency, plus the egress IP when testUrl echoes it (e.g. https://api.ipify.org)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "settings"
+                ],
+                "summary": "Test network proxy",
+                "parameters": [
+                    {
+                        "description": "Network test configuration",
+                        "name": "config",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.networkTestRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.networkTestResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/settings/security": {
+            "get": {
+                "description": "Get allowed CORS origins and auth cookie attributes",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "settings"
+                ],
+                "summary": "Get security settings",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.securitySettingsResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update allowed CORS origins and auth cookie attributes. SameSite=None requires Secure.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "settings"
+                ],
+                "summary": "Update security settings",
+                "parameters": [
+                    {
+                        "description": "Security settings",
+                        "name": "settings",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.securitySettingsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.securitySettingsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/starred-count": {
+            "get": {
+                "description": "Get the total count of starred entries",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "entries"
+                ],
+                "summary": "Get starred count",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.starredCountResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/stats/clicks": {
+            "get": {
+                "description": "Get the number of entries opened through /entries/{id}/visit per feed, to find feeds worth unsubscribing from. Every feed is listed, most clicked first. Days are counted in the configured timezone.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "entries"
+                ],
+                "summary": "Get click-through statistics",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Number of days including today (default 30, max 365)",
+                        "name": "days",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.clickStatsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/stats/reading": {
+            "get": {
+                "description": "Get entries read per day, the most read feeds, the average time from publication to reading and the starred total. Days are counted in the configured timezone; reads from before read times were recorded are not included.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "entries"
+                ],
+                "summary": "Get reading statistics",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Number of days including today (default 90, max 365)",
+                        "name": "days",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.readingStatsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/unread-counts": {
+            "get": {
+                "description": "Get a map of feed IDs to their respective unread entry counts, and under today the number of entries each feed saved since the start of today in the configured timezone. Entries count as new by when they were saved, not published, so backfilled old articles are not. Feeds without entries today are left out.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "entries"
+                ],
+                "summary": "Get unread counts",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.unreadCountsResponse"
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified"
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "gist_backend_internal_service.BatchTranslateResult": {
+            "type": "object",
+            "properties": {
+                "cached": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "summary": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "gist_backend_internal_service.ImportResult": {
+            "type": "object",
+            "properties": {
+                "feedsCreated": {
+                    "type": "integer"
+                },
+                "feedsSkipped": {
+                    "type": "integer"
+                },
+                "foldersCreated": {
+                    "type": "integer"
+                },
+                "foldersSkipped": {
+                    "type": "integer"
+                }
+            }
+        },
+        "gist_backend_internal_service.ImportTask": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "current": {
+                    "type": "integer"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "feed": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "result": {
+                    "$ref": "#/definitions/gist_backend_internal_service.ImportResult"
+                },
+                "status": {
+                    "description": "\"running\", \"done\", \"error\", \"cancelled\"",
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "internal_handler.aiModelPrice": {
+            "type": "object",
+            "properties": {
+                "input": {
+                    "type": "number",
+                    "example": 2.5
+                },
+                "output": {
+                    "type": "number",
+                    "example": 10
+                }
+            }
+        },
+        "internal_handler.aiSettingsRequest": {
+            "type": "object",
+            "properties": {
+                "apiKey": {
+                    "type": "string"
+                },
+                "autoSummary": {
+                    "type": "boolean"
+                },
+                "autoTranslate": {
+                    "type": "boolean"
+                },
+                "baseUrl": {
+                    "type": "string"
+                },
+                "model": {
+                    "type": "string"
+                },
+                "modelPrices": {
+                    "description": "ModelPrices replaces the price table when sent; omitted keeps the stored one",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/internal_handler.aiModelPrice"
+                    }
+                },
+                "provider": {
+                    "type": "string"
+                },
+                "rateLimit": {
+                    "type": "integer"
+                },
+                "requestOptions": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "summaryLanguage": {
+                    "type": "string"
+                },
+                "translateBlockChars": {
+                    "description": "TranslateBlockChars of 0 or omitted keeps the stored budget",
+                    "type": "integer"
+                },
+                "translateOnRefresh": {
+                    "description": "TranslateOnRefresh translates list titles of new entries in the background;\nfeeds can override it",
+                    "type": "boolean"
+                },
+                "usageRetentionMonths": {
+                    "description": "UsageRetentionMonths of 0 or omitted keeps the stored retention",
+                    "type": "integer"
+                },
+                "version": {
+                    "description": "Version from the last read; a stale one is rejected with 409. Omitted saves unconditionally",
+                    "type": "string"
+                }
+            }
+        },
+        "internal_handler.aiSettingsResponse": {
+            "type": "object",
+            "properties": {
+                "apiKey": {
+                    "type": "string"
+                },
+                "autoSummary": {
+                    "type": "boolean"
+                },
+                "autoTranslate": {
+                    "type": "boolean"
+                },
+                "baseUrl": {
+                    "type": "string"
+                },
+                "model": {
+                    "type": "string"
+                },
+                "modelPrices": {
+                    "description": "ModelPrices are USD per million tokens, keyed by model name",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/internal_handler.aiModelPrice"
+                    }
+                },
+                "provider": {
+                    "type": "string"
+                },
+                "rateLimit": {
+                    "type": "integer"
+                },
+                "requestOptions": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "summaryLanguage": {
+                    "type": "string"
+                },
+                "translateBlockChars": {
+                    "description": "TranslateBlockChars is the article HTML sent per translation call",
+                    "type": "integer",
+                    "example": 1500
+                },
+                "translateOnRefresh": {
+                    "description": "TranslateOnRefresh translates list titles of new entries in the background;\nfeeds can override it",
+                    "type": "boolean"
+                },
+                "usageRetentionMonths": {
+                    "type": "integer"
+                },
+                "version": {
+                    "description": "Version identifies this read; send it back on update to detect concurrent saves",
+                    "type": "string",
+                    "example": "3"
+                }
+            }
+        },
+        "internal_handler.aiTestRequest": {
+            "type": "object",
+            "properties": {
+                "apiKey": {
+                    "type": "string"
+                },
+                "baseUrl": {
+                    "type": "string"
+                },
+                "model": {
+                    "type": "string"
+                },
+                "provider": {
+                    "type": "string"
+                },
+                "requestOptions": {
+                    "type": "object",
+                    "additionalProperties": {}
+                }
+            }
+        },
+        "internal_handler.aiTestResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "internal_handler.aiUsageDayResponse": {
+            "type": "object",
+            "properties": {
+                "calls": {
+                    "type": "integer"
+                },
+                "completionTokens": {
+                    "type": "integer"
+                },
+                "cost": {
+                    "type": "number"
+                },
+                "date": {
+                    "type": "string",
+                    "example": "2025-03-01"
+                },
+                "operation": {
+                    "type": "string",
+                    "example": "summarize"
+                },
+                "promptTokens": {
+                    "type": "integer"
+                }
+            }
+        },
+        "internal_handler.aiUsageResponse": {
+            "type": "object",
+            "properties": {
+                "completionTokens": {
+                    "type": "integer"
+                },
+                "cost": {
+                    "description": "Cost is an estimate in USD from the AI settings price table",
+                    "type": "number"
+                },
+                "days": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_handler.aiUsageDayResponse"
+                    }
+                },
+                "from": {
+                    "type": "string"
+                },
+                "promptTokens": {
+                    "type": "integer"
+                },
+                "to": {
+                    "type": "string"
+                },
+                "unpricedModels": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "internal_handler.apiTokenListResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_handler.apiTokenResponse"
+                    }
+                }
+            }
+        },
+        "internal_handler.apiTokenResponse": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "expiresAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "lastUsedAt": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_handler.appearanceSettingsRequest": {
+            "type": "object",
+            "properties": {
+                "contentTypes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "version": {
+                    "description": "Version from the last read; a stale one is rejected with 409. Omitted saves unconditionally",
+                    "type": "string"
+                }
+            }
+        },
+        "internal_handler.appearanceSettingsResponse": {
+            "type": "object",
+            "properties": {
+                "contentTypes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "version": {
+                    "description": "Version identifies this read; send it back on update to detect concurrent saves",
+                    "type": "string",
+                    "example": "3"
+                }
+            }
+        },
+        "internal_handler.applyFolderRulesResponse": {
+            "type": "object",
+            "properties": {
+                "moved": {
+                    "type": "integer"
+                }
+            }
+        },
+        "internal_handler.archiveBackfillRequest": {
+            "type": "object",
+            "properties": {
+                "markRead": {
+                    "type": "boolean"
+                },
+                "maxPages": {
+                    "description": "MaxPages is how many archive pages to follow, 5 by default and 50 at most.",
+                    "type": "integer"
+                }
+            }
+        },
+        "internal_handler.archiveBackfillResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "description": "Error tells why a failed backfill stopped, such as a page linking back to an earlier one",
+                    "type": "string"
+                },
+                "feedId": {
+                    "type": "string"
+                },
+                "finishedAt": {
+                    "type": "string"
+                },
+                "hasMore": {
+                    "type": "boolean"
+                },
+                "items": {
+                    "type": "integer"
+                },
+                "maxPages": {
+                    "type": "integer"
+                },
+                "newEntries": {
+                    "type": "integer"
+                },
+                "pages": {
+                    "description": "Pages counts the documents fetched, the feed document included",
+                    "type": "integer"
+                },
+                "startedAt": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "Status is running, done or failed",
+                    "type": "string"
+                }
+            }
+        },
+        "internal_handler.archiveFailureResponse": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "entryId": {
+                    "type": "string"
+                },
+                "failedAt": {
+                    "type": "string"
+                },
+                "feedId": {
+                    "type": "string"
+                },
+                "lastError": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_handler.archiveStatusResponse": {
+            "type": "object",
+            "properties": {
+                "failed": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_handler.archiveFailureResponse"
+                    }
+                }
+            }
+        },
+        "internal_handler.authResponse": {
+            "type": "object",
+            "properties": {
+                "pendingToken": {
+                    "type": "string"
+                },
+                "requires2fa": {
+                    "type": "boolean"
+                },
+                "token": {
+                    "type": "string"
+                },
+                "user": {
+                    "$ref": "#/definitions/internal_handler.userResponse"
+                }
+            }
+        },
+        "internal_handler.authStatusResponse": {
+            "type": "object",
+            "properties": {
+                "exists": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "internal_handler.autoTranslateRunResponse": {
+            "type": "object",
+            "properties": {
+                "entries": {
+                    "description": "Entries counts the untranslated new entries collected",
+                    "type": "integer"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "failed": {
+                    "type": "integer"
+                },
+                "finishedAt": {
+                    "type": "string"
+                },
+                "skipped": {
+                    "description": "Skipped counts entries of feeds already in the target language and entries without text",
+                    "type": "integer"
+                },
+                "startedAt": {
+                    "type": "string"
+                },
+                "translated": {
+                    "type": "integer"
+                }
+            }
+        },
+        "internal_handler.autoTranslateStatusResponse": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "description": "Enabled is the translateOnRefresh AI setting; feeds set to on are translated either way",
+                    "type": "boolean"
+                },
+                "language": {
+                    "type": "string",
+                    "example": "zh-CN"
+                },
+                "lastRun": {
+                    "description": "LastRun is omitted until the first run since startup finishes",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/internal_handler.autoTranslateRunResponse"
+                        }
+                    ]
+                },
+                "running": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "internal_handler.backupImportResponse": {
+            "type": "object",
+            "properties": {
+                "entries": {
+                    "$ref": "#/definitions/internal_handler.backupSectionResponse"
+                },
+                "feeds": {
+                    "$ref": "#/definitions/internal_handler.backupSectionResponse"
+                },
+                "folders": {
+                    "$ref": "#/definitions/internal_handler.backupSectionResponse"
+                }
+            }
+        },
+        "internal_handler.backupSectionResponse": {
+            "type": "object",
+            "properties": {
+                "created": {
+                    "type": "integer"
+                },
+                "skipped": {
+                    "type": "integer"
+                },
+                "updated": {
+                    "type": "integer"
+                }
+            }
+        },
+        "internal_handler.batchTranslateRequest": {
+            "type": "object",
+            "properties": {
+                "articles": {
+                    "type": "array",
+                    "items": {
+                        "type": "object",
+                        "properties": {
+                            "id": {
+                                "type": "string"
+                            },
+                            "summary": {
+                                "type": "string"
+                            },
+                            "title": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "language": {
+                    "description": "Language overrides the configured summary language, e.g. \"en-US\".",
+                    "type": "string"
+                }
+            }
+        },
+        "internal_handler.bootstrapFeedResponse": {
+            "type": "object",
+            "properties": {
+                "folderId": {
+                    "type": "string"
+                },
+                "hasError": {
+                    "description": "HasError is set when the last refresh failed",
+                    "type": "boolean"
+                },
+                "iconPath": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "paused": {
+                    "type": "boolean"
+                },
+                "siteUrl": {
+                    "type": "string"
+                },
+                "sortOrder": {
+                    "type": "integer"
+                },
+                "title": {
+                    "description": "Title is the custom title when the feed was renamed",
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_handler.bootstrapResponse": {
+            "type": "object",
+            "properties": {
+                "appearance": {
+                    "$ref": "#/definitions/internal_handler.appearanceSettingsResponse"
+                },
+                "feeds": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_handler.bootstrapFeedResponse"
+                    }
+                },
+                "folders": {
+                    "description": "Folders is flat; nesting follows parentId",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_handler.folderResponse"
+                    }
+                },
+                "refresh": {
+                    "$ref": "#/definitions/internal_handler.refreshStatusResponse"
+                },
+                "starredCount": {
+                    "type": "integer"
+                },
+                "unreadCounts": {
+                    "description": "UnreadCounts maps feed IDs to unread counts like GET /unread-counts",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "internal_handler.bulkEntriesRequest": {
+            "type": "object",
+            "properties": {
+                "ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "includeAI": {
+                    "description": "IncludeAI adds each entry's cached AI summary, if any.",
+                    "type": "boolean"
+                }
+            }
+        },
+        "internal_handler.bulkEntriesResponse": {
+            "type": "object",
+            "properties": {
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_handler.bulkEntryResponse"
+                    }
+                }
+            }
+        },
+        "internal_handler.bulkEntryResponse": {
+            "type": "object",
+            "properties": {
+                "author": {
+                    "type": "string"
+                },
+                "authorMuted": {
+                    "description": "AuthorMuted is only set on single entries whose author is muted in the feed.",
+                    "type": "boolean"
+                },
+                "content": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "feedIconPath": {
+                    "type": "string"
+                },
+                "feedId": {
+                    "type": "string"
+                },
+                "feedTitle": {
+                    "description": "FeedTitle, FeedIconPath and FeedType are only set with ?include=feed.",
+                    "type": "string"
+                },
+                "feedType": {
+                    "type": "string"
+                },
+                "hasNote": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "language": {
+                    "type": "string"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "publishedAt": {
+                    "type": "string"
+                },
+                "read": {
+                    "type": "boolean"
+                },
+                "readableContent": {
+                    "type": "string"
+                },
+                "readingMinutes": {
+                    "type": "integer"
+                },
+                "starred": {
+                    "type": "boolean"
+                },
+                "summary": {
+                    "type": "string"
+                },
+                "thumbnailUrl": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                },
+                "wordCount": {
+                    "type": "integer"
+                }
+            }
+        },
+        "internal_handler.clearCacheResponse": {
+            "type": "object",
+            "properties": {
+                "listTranslations": {
+                    "type": "integer"
+                },
+                "summaries": {
+                    "type": "integer"
+                },
+                "translations": {
+                    "type": "integer"
+                }
+            }
+        },
+        "internal_handler.clickStatsResponse": {
+            "type": "object",
+            "properties": {
+                "days": {
+                    "type": "integer"
+                },
+                "feeds": {
+                    "description": "Feeds has every feed, most clicked first; those never clicked have count 0.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_handler.feedClicksResponse"
+                    }
+                },
+                "since": {
+                    "type": "string"
+                },
+                "timezone": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_handler.compactRequest": {
+            "type": "object",
+            "properties": {
+                "confirm": {
+                    "description": "Confirm must be set with vacuum, which locks the database while it rebuilds it.",
+                    "type": "boolean"
+                },
+                "vacuum": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "internal_handler.compactResponse": {
+            "type": "object",
+            "properties": {
+                "bytesAfter": {
+                    "type": "integer"
+                },
+                "bytesBefore": {
+                    "type": "integer"
+                },
+                "checkpointed": {
+                    "type": "boolean"
+                },
+                "vacuumed": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "internal_handler.createAPITokenRequest": {
+            "type": "object",
+            "properties": {
+                "expiresAt": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_handler.createFeedRequest": {
+            "type": "object",
+            "properties": {
+                "archivePages": {
+                    "description": "ArchivePages is how many archive pages to follow, 5 by default and 50 at most.",
+                    "type": "integer"
+                },
+                "backfillArchive": {
+                    "description": "BackfillArchive also crawls the archive pages the feed links to with\nrel=\"next\" (RFC 5005) in the background once it is added.",
+                    "type": "boolean"
+                },
+                "folderId": {
+                    "type": "string"
+                },
+                "initialBackfill": {
+                    "description": "InitialBackfill is \"all\" (default), \"none\" to save the current items as read,\nor a count N to keep only the N most recent items.",
+                    "type": "string",
+                    "example": "all"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "typeMode": {
+                    "description": "TypeMode \"auto\" ignores Type and picks it from the fetched items.",
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_handler.createStaticFeedRequest": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "description": "Content is the RSS, Atom or JSON feed document. It may be left empty\nwhen Title is set, for a feed that only receives pushed entries.",
+                    "type": "string"
+                },
+                "folderId": {
+                    "type": "string"
+                },
+                "title": {
+                    "description": "Title defaults to the title in Content.",
+                    "type": "string"
+                },
+                "type": {
+                    "description": "Type is article, picture or notification; empty picks it from the items.",
+                    "type": "string"
+                }
+            }
+        },
+        "internal_handler.createdAPITokenResponse": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "expiresAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "lastUsedAt": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_handler.dailyDigestResponse": {
+            "type": "object",
+            "properties": {
+                "date": {
+                    "type": "string"
+                },
+                "end": {
+                    "type": "string"
+                },
+                "folders": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_handler.digestFolderResponse"
+                    }
+                },
+                "start": {
+                    "type": "string"
+                },
+                "timezone": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_handler.deleteFeedsRequest": {
+            "type": "object",
+            "properties": {
+                "ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "internal_handler.deleteFoldersRequest": {
+            "type": "object",
+            "properties": {
+                "ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "internal_handler.deletedCountResponse": {
+            "type": "object",
+            "properties": {
+                "deleted": {
+                    "type": "integer"
+                }
+            }
+        },
+        "internal_handler.digestEntryResponse": {
+            "type": "object",
+            "properties": {
+                "author": {
+                    "type": "string"
+                },
+                "feedId": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "publishedAt": {
+                    "type": "string"
+                },
+                "read": {
+                    "type": "boolean"
+                },
+                "readingMinutes": {
+                    "type": "integer"
+                },
+                "starred": {
+                    "type": "boolean"
+                },
+                "summary": {
+                    "type": "string"
+                },
+                "thumbnailUrl": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_handler.digestFeedResponse": {
+            "type": "object",
+            "properties": {
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_handler.digestEntryResponse"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "unreadCount": {
+                    "type": "integer"
+                }
+            }
+        },
+        "internal_handler.digestFolderResponse": {
+            "type": "object",
+            "properties": {
+                "feeds": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_handler.digestFeedResponse"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "unreadCount": {
+                    "type": "integer"
+                }
+            }
+        },
+        "internal_handler.digestRequest": {
+            "type": "object",
+            "properties": {
+                "feedId": {
+                    "type": "string"
+                },
+                "folderId": {
+                    "description": "Exactly one of FolderID and FeedID selects the entries.",
+                    "type": "string"
+                },
+                "language": {
+                    "description": "Language overrides the configured summary language, e.g. \"en-US\".",
+                    "type": "string"
+                },
+                "since": {
+                    "description": "Since and Until bound the window as RFC 3339 times, truncated to the\nminute. Until defaults to now.",
+                    "type": "string",
+                    "example": "2026-01-01T00:00:00Z"
+                },
+                "until": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_handler.digestResponse": {
+            "type": "object",
+            "properties": {
+                "cached": {
+                    "type": "boolean"
+                },
+                "digest": {
+                    "type": "string"
+                },
+                "entries": {
+                    "type": "integer"
+                }
+            }
+        },
+        "internal_handler.domainRateLimitListResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_handler.domainRateLimitResponse"
+                    }
+                }
+            }
+        },
+        "internal_handler.domainRateLimitRequest": {
+            "type": "object",
+            "properties": {
+                "host": {
+                    "type": "string"
+                },
+                "intervalSeconds": {
+                    "type": "integer"
+                },
+                "maxConcurrent": {
+                    "type": "integer"
+                }
+            }
+        },
+        "internal_handler.domainRateLimitResponse": {
+            "type": "object",
+            "properties": {
+                "host": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "intervalSeconds": {
+                    "type": "integer"
+                },
+                "maxConcurrent": {
+                    "description": "MaxConcurrent caps parallel refresh requests to the host; 0 uses the general setting.\nTakes effect from the next refresh run.",
+                    "type": "integer"
+                }
+            }
+        },
+        "internal_handler.entryClearResponse": {
+            "type": "object",
+            "properties": {
+                "deleted": {
+                    "type": "integer"
+                }
+            }
+        },
+        "internal_handler.entryCountResponse": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "internal_handler.entryListResponse": {
+            "type": "object",
+            "properties": {
+                "deleted": {
+                    "description": "Deleted lists the entries deleted since that time, on the first page of a delta list.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_handler.entryResponse"
+                    }
+                },
+                "full": {
+                    "description": "Full is false for a delta list, which holds only the entries updated since\nthe time asked for, without content unless include=content.",
+                    "type": "boolean"
+                },
+                "hasMore": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "internal_handler.entryResponse": {
+            "type": "object",
+            "properties": {
+                "author": {
+                    "type": "string"
+                },
+                "authorMuted": {
+                    "description": "AuthorMuted is only set on single entries whose author is muted in the feed.",
+                    "type": "boolean"
+                },
+                "content": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "feedIconPath": {
+                    "type": "string"
+                },
+                "feedId": {
+                    "type": "string"
+                },
+                "feedTitle": {
+                    "description": "FeedTitle, FeedIconPath and FeedType are only set with ?include=feed.",
+                    "type": "string"
+                },
+                "feedType": {
+                    "type": "string"
+                },
+                "hasNote": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "language": {
+                    "type": "string"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "publishedAt": {
+                    "type": "string"
+                },
+                "read": {
+                    "type": "boolean"
+                },
+                "readableContent": {
+                    "type": "string"
+                },
+                "readingMinutes": {
+                    "type": "integer"
+                },
+                "starred": {
+                    "type": "boolean"
+                },
+                "thumbnailUrl": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                },
+                "wordCount": {
+                    "type": "integer"
+                }
+            }
+        },
+        "internal_handler.entryRevisionDiffLine": {
+            "type": "object",
+            "properties": {
+                "op": {
+                    "type": "string"
+                },
+                "text": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_handler.entryRevisionListResponse": {
+            "type": "object",
+            "properties": {
+                "revisions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_handler.entryRevisionResponse"
+                    }
+                }
+            }
+        },
+        "internal_handler.entryRevisionResponse": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "diff": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_handler.entryRevisionDiffLine"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_handler.entrySearchResponse": {
+            "type": "object",
+            "properties": {
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_handler.entryResponse"
+                    }
+                },
+                "hasMore": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "internal_handler.errorResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "invalid_request"
+                },
+                "details": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "message": {
+                    "type": "string",
+                    "example": "invalid request"
+                }
+            }
+        },
+        "internal_handler.feedArchiveBackfillResponse": {
+            "type": "object",
+            "properties": {
+                "backfill": {
+                    "description": "Backfill is null when none ran since the server started",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/internal_handler.archiveBackfillResponse"
+                        }
+                    ]
+                }
+            }
+        },
+        "internal_handler.feedClicksResponse": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "feedId": {
+                    "type": "string"
+                },
+                "lastClickedAt": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_handler.feedConfigSourceResponse": {
+            "type": "object",
+            "properties": {
+                "folderId": {
+                    "type": "string"
+                },
+                "folderName": {
+                    "type": "string"
+                },
+                "source": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_handler.feedEffectiveConfigResponse": {
+            "type": "object",
+            "properties": {
+                "autoReadability": {
+                    "type": "boolean"
+                },
+                "autoReadabilitySource": {
+                    "$ref": "#/definitions/internal_handler.feedConfigSourceResponse"
+                },
+                "autoTranslate": {
+                    "type": "boolean"
+                },
+                "autoTranslateSource": {
+                    "$ref": "#/definitions/internal_handler.feedConfigSourceResponse"
+                },
+                "userAgent": {
+                    "type": "string"
+                },
+                "userAgentSource": {
+                    "$ref": "#/definitions/internal_handler.feedConfigSourceResponse"
+                }
+            }
+        },
+        "internal_handler.feedFetchLogResponse": {
+            "type": "object",
+            "properties": {
+                "fetches": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_handler.feedFetchResponse"
+                    }
+                },
+                "status": {
+                    "description": "Status is unknown, ok, unstable or erroring, from the success ratio and\nthe latest failures in a row",
+                    "type": "string"
+                },
+                "successRatio": {
+                    "type": "number"
+                }
+            }
+        },
+        "internal_handler.feedFetchResponse": {
+            "type": "object",
+            "properties": {
+                "bytes": {
+                    "type": "integer"
+                },
+                "durationMs": {
+                    "type": "integer"
+                },
+                "fetchedAt": {
+                    "type": "string"
+                },
+                "outcome": {
+                    "description": "Outcome is ok, not_modified, http_error, network_error or parse_error",
+                    "type": "string"
+                },
+                "statusCode": {
+                    "description": "StatusCode is 0 when no response arrived",
+                    "type": "integer"
+                }
+            }
+        },
+        "internal_handler.feedIngestReportResponse": {
+            "type": "object",
+            "properties": {
+                "report": {
+                    "description": "Report is null when the last refresh took every item as delivered",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/internal_handler.ingestReportResponse"
+                        }
+                    ]
+                }
+            }
+        },
+        "internal_handler.feedOverlapResponse": {
+            "type": "object",
+            "properties": {
+                "detectedAt": {
+                    "type": "string"
+                },
+                "feed": {
+                    "$ref": "#/definitions/internal_handler.feedResponse"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "otherFeed": {
+                    "$ref": "#/definitions/internal_handler.feedResponse"
+                },
+                "overlapRatio": {
+                    "type": "number"
+                },
+                "sameSite": {
+                    "description": "SameSite is set when both feeds declare the same site URL.",
+                    "type": "boolean"
+                },
+                "sharedEntries": {
+                    "description": "SharedEntries counts the 50 most recent entries of one feed the other has\ntoo; OverlapRatio is their share of that sample, the higher of the two.",
+                    "type": "integer"
+                }
+            }
+        },
+        "internal_handler.feedPreviewResponse": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "imageUrl": {
+                    "type": "string"
+                },
+                "itemCount": {
+                    "type": "integer"
+                },
+                "lastUpdated": {
+                    "type": "string"
+                },
+                "siteUrl": {
+                    "type": "string"
+                },
+                "suggestedType": {
+                    "description": "SuggestedType is the feed type the server would pick in auto mode.",
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_handler.feedProbeResponse": {
+            "type": "object",
+            "properties": {
+                "anubisChallenge": {
+                    "type": "boolean"
+                },
+                "anubisPage": {
+                    "type": "boolean"
+                },
+                "bodyPreview": {
+                    "type": "string"
+                },
+                "bodySize": {
+                    "type": "integer"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "feedType": {
+                    "type": "string"
+                },
+                "headers": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "ingest": {
+                    "description": "Ingest is what a refresh would skip or alter, null when nothing",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/internal_handler.ingestReportResponse"
                         }
+                    ]
+                },
+                "itemCount": {
+                    "type": "integer"
+                },
+                "itemsNoGuid": {
+                    "type": "integer"
+                },
+                "itemsNoLink": {
+                    "type": "integer"
+                },
+                "parseError": {
+                    "type": "string"
+                },
+                "parsed": {
+                    "type": "boolean"
+                },
+                "remoteAddr": {
+                    "type": "string"
+                },
+                "resolvedIps": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
                     }
+                },
+                "sentCookie": {
+                    "type": "boolean"
+                },
+                "statusCode": {
+                    "type": "integer"
+                },
+                "timing": {
+                    "$ref": "#/definitions/internal_handler.feedProbeTimingResponse"
+                },
+                "url": {
+                    "type": "string"
+                },
+                "userAgent": {
+                    "type": "string"
+                },
+                "userAgentValue": {
+                    "type": "string"
                 }
             }
         },
-        "/unread-counts": {
-            "get": {
-                "description": "Get a map of feed IDs to their respective unread entry counts",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "entries"
-                ],
-                "summary": "Get unread counts",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/internal_handler.unreadCountsResponse"
-                        }
-                    }
+        "internal_handler.feedProbeTimingResponse": {
+            "type": "object",
+            "properties": {
+                "connectMs": {
+                    "type": "integer"
+                },
+                "dnsMs": {
+                    "type": "integer"
+                },
+                "tlsMs": {
+                    "type": "integer"
+                },
+                "totalMs": {
+                    "type": "integer"
+                },
+                "ttfbMs": {
+                    "type": "integer"
                 }
             }
-        }
-    },
-    "definitions": {
-        "gist_backend_internal_service.BatchTranslateResult": {
+        },
+        "internal_handler.feedResponse": {
             "type": "object",
             "properties": {
-                "cached": {
+                "assumeTimezone": {
+                    "type": "string"
+                },
+                "autoReadability": {
                     "type": "boolean"
                 },
+                "autoTranslate": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "customTitle": {
+                    "type": "string"
+                },
+                "dedupeKey": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "effective": {
+                    "description": "Effective holds the options after inheritance from the feed's folders.\nOnly the list resolves it.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/internal_handler.feedEffectiveConfigResponse"
+                        }
+                    ]
+                },
+                "errorMessage": {
+                    "type": "string"
+                },
+                "etag": {
+                    "type": "string"
+                },
+                "folderId": {
+                    "type": "string"
+                },
+                "iconPath": {
+                    "type": "string"
+                },
                 "id": {
                     "type": "string"
                 },
-                "summary": {
+                "language": {
+                    "type": "string"
+                },
+                "lastFetchedAt": {
+                    "type": "string"
+                },
+                "lastModified": {
+                    "type": "string"
+                },
+                "maxEntries": {
+                    "description": "MaxEntries caps the entries kept for the feed, 0 when unlimited.",
+                    "type": "integer"
+                },
+                "paused": {
+                    "type": "boolean"
+                },
+                "pausedUntil": {
+                    "type": "string"
+                },
+                "pollIntervalSeconds": {
+                    "description": "PollIntervalSeconds is the shortest time between two scheduled refreshes,\n0 when the feed is refreshed on every run. PollIntervalSource says what set\nit: ttl or syndication for the feed's own hints, host for its domain rate\nlimit. Only the list accounts for the domain rate limit.",
+                    "type": "integer"
+                },
+                "pollIntervalSource": {
+                    "type": "string"
+                },
+                "preferredUserAgent": {
+                    "type": "string"
+                },
+                "siteUrl": {
+                    "type": "string"
+                },
+                "sortOrder": {
+                    "type": "integer"
+                },
+                "stats": {
+                    "description": "Stats is only filled when the list is requested with include=stats.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/internal_handler.feedStatsResponse"
+                        }
+                    ]
+                },
+                "summaryPromptReminder": {
+                    "type": "string"
+                },
+                "suspectCachingAt": {
+                    "description": "SuspectCachingAt is when a fetch without ETag and Last-Modified found new\nentries the feed's 304 answers had hidden; cleared once they work again.",
                     "type": "string"
                 },
                 "title": {
+                    "description": "Title is the name shown to the user: the rename if there is one,\notherwise the title the feed itself declares (UpstreamTitle).",
+                    "type": "string"
+                },
+                "translatedTitle": {
+                    "description": "TranslatedTitle is the cached translation of Title when the list is requested with language.",
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "upstreamTitle": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                },
+                "userAgent": {
+                    "description": "UserAgent and AutoReadability are the feed's own values, absent when it\ninherits them; AutoTranslate is its own choice too.",
                     "type": "string"
                 }
             }
         },
-        "gist_backend_internal_service.ImportResult": {
+        "internal_handler.feedStatsResponse": {
             "type": "object",
             "properties": {
-                "feedsCreated": {
+                "entriesLastWeek": {
                     "type": "integer"
                 },
-                "feedsSkipped": {
-                    "type": "integer"
+                "feedId": {
+                    "type": "string"
                 },
-                "foldersCreated": {
-                    "type": "integer"
+                "lastPublishedAt": {
+                    "type": "string"
                 },
-                "foldersSkipped": {
+                "totalEntries": {
                     "type": "integer"
                 }
             }
         },
-        "gist_backend_internal_service.ImportTask": {
+        "internal_handler.feedTitleTranslationResponse": {
+            "type": "object",
+            "properties": {
+                "cached": {
+                    "type": "boolean"
+                },
+                "feedId": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "unchanged": {
+                    "description": "Unchanged means the title already looked like the target language.",
+                    "type": "boolean"
+                }
+            }
+        },
+        "internal_handler.folderFeedDefaultsResponse": {
+            "type": "object",
+            "properties": {
+                "autoReadability": {
+                    "type": "boolean"
+                },
+                "autoTranslate": {
+                    "type": "boolean"
+                },
+                "userAgent": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_handler.folderRequest": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "parentId": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_handler.folderResponse": {
             "type": "object",
             "properties": {
                 "createdAt": {
                     "type": "string"
                 },
-                "current": {
+                "feedDefaults": {
+                    "$ref": "#/definitions/internal_handler.folderFeedDefaultsResponse"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "parentId": {
+                    "type": "string"
+                },
+                "sortOrder": {
                     "type": "integer"
                 },
-                "error": {
+                "type": {
                     "type": "string"
                 },
-                "feed": {
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_handler.folderRuleRequest": {
+            "type": "object",
+            "properties": {
+                "matchField": {
+                    "description": "MatchField is host, url or title.",
+                    "type": "string"
+                },
+                "pattern": {
+                    "type": "string"
+                },
+                "priority": {
+                    "type": "integer"
+                }
+            }
+        },
+        "internal_handler.folderRuleResponse": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "folderId": {
                     "type": "string"
                 },
                 "id": {
                     "type": "string"
                 },
-                "result": {
-                    "$ref": "#/definitions/gist_backend_internal_service.ImportResult"
+                "matchField": {
+                    "type": "string"
                 },
-                "status": {
-                    "description": "\"running\", \"done\", \"error\", \"cancelled\"",
+                "pattern": {
                     "type": "string"
                 },
-                "total": {
+                "priority": {
                     "type": "integer"
+                },
+                "updatedAt": {
+                    "type": "string"
                 }
             }
         },
-        "internal_handler.aiSettingsRequest": {
+        "internal_handler.generalSettingsRequest": {
             "type": "object",
             "properties": {
-                "apiKey": {
+                "anubisMaxRetries": {
+                    "description": "Anubis retry settings are optional; omitted keeps the stored ones",
+                    "type": "integer"
+                },
+                "anubisRetryDelayMs": {
+                    "type": "integer"
+                },
+                "autoReadability": {
+                    "type": "boolean"
+                },
+                "entryRevisions": {
+                    "description": "EntryRevisions is optional so older clients don't disable versioning by omission",
+                    "type": "boolean"
+                },
+                "fallbackUserAgent": {
                     "type": "string"
                 },
-                "autoSummary": {
+                "imageCache": {
+                    "description": "Image cache fields are optional for the same reason; omitted values keep the stored ones",
+                    "type": "boolean"
+                },
+                "imageCacheEntryLimitMb": {
+                    "type": "integer"
+                },
+                "imageCacheTotalLimitMb": {
+                    "type": "integer"
+                },
+                "keepRawItems": {
+                    "description": "KeepRawItems is optional; omitted keeps the stored one",
                     "type": "boolean"
                 },
-                "autoTranslate": {
+                "markReadOnScroll": {
                     "type": "boolean"
                 },
-                "baseUrl": {
-                    "type": "string"
+                "maxConcurrentPerHost": {
+                    "type": "integer"
                 },
-                "model": {
-                    "type": "string"
+                "maxConcurrentRefresh": {
+                    "description": "Concurrency limits are optional; omitted keeps the stored ones",
+                    "type": "integer"
                 },
-                "provider": {
+                "quietHoursEnd": {
+                    "type": "string",
+                    "example": "07:00"
+                },
+                "quietHoursStart": {
+                    "description": "Quiet hours are optional; omitted keeps the stored ones, both empty disables them",
+                    "type": "string",
+                    "example": "22:00"
+                },
+                "timezone": {
+                    "description": "Timezone is an IANA name such as \"Asia/Shanghai\"; omitted keeps the stored one",
                     "type": "string"
                 },
-                "rateLimit": {
+                "unconditionalFetchAfter": {
+                    "description": "UnconditionalFetchAfter is optional; omitted keeps the stored one",
                     "type": "integer"
                 },
-                "requestOptions": {
-                    "type": "object",
-                    "additionalProperties": {}
-                },
-                "summaryLanguage": {
+                "version": {
+                    "description": "Version from the last read; a stale one is rejected with 409. Omitted saves unconditionally",
                     "type": "string"
                 }
             }
         },
-        "internal_handler.aiSettingsResponse": {
+        "internal_handler.generalSettingsResponse": {
             "type": "object",
             "properties": {
-                "apiKey": {
-                    "type": "string"
+                "anubisMaxRetries": {
+                    "description": "Anubis challenges solved per fetch at most, and the wait after each solve",
+                    "type": "integer"
                 },
-                "autoSummary": {
+                "anubisRetryDelayMs": {
+                    "type": "integer"
+                },
+                "autoReadability": {
                     "type": "boolean"
                 },
-                "autoTranslate": {
+                "entryRevisions": {
                     "type": "boolean"
                 },
-                "baseUrl": {
+                "fallbackUserAgent": {
                     "type": "string"
                 },
-                "model": {
-                    "type": "string"
+                "imageCache": {
+                    "type": "boolean"
                 },
-                "provider": {
-                    "type": "string"
+                "imageCacheEntryLimitMb": {
+                    "type": "integer"
                 },
-                "rateLimit": {
+                "imageCacheTotalLimitMb": {
                     "type": "integer"
                 },
-                "requestOptions": {
-                    "type": "object",
-                    "additionalProperties": {}
+                "keepRawItems": {
+                    "description": "KeepRawItems stores fetched feed items for GET /entries/{id}/raw",
+                    "type": "boolean"
                 },
-                "summaryLanguage": {
+                "markReadOnScroll": {
+                    "type": "boolean"
+                },
+                "maxConcurrentPerHost": {
+                    "type": "integer"
+                },
+                "maxConcurrentRefresh": {
+                    "description": "Concurrency limits are read when a refresh run starts",
+                    "type": "integer"
+                },
+                "quietHoursEnd": {
+                    "type": "string",
+                    "example": "07:00"
+                },
+                "quietHoursStart": {
+                    "description": "Quiet hours as \"HH:MM\" in timezone; both empty when disabled",
+                    "type": "string",
+                    "example": "22:00"
+                },
+                "timezone": {
                     "type": "string"
+                },
+                "unconditionalFetchAfter": {
+                    "description": "Refreshes in a row answered 304 before a feed due for new entries is fetched without validators",
+                    "type": "integer"
+                },
+                "version": {
+                    "description": "Version identifies this read; send it back on update to detect concurrent saves",
+                    "type": "string",
+                    "example": "3"
                 }
             }
         },
-        "internal_handler.aiTestRequest": {
+        "internal_handler.healthResponse": {
             "type": "object",
             "properties": {
-                "apiKey": {
+                "buildTime": {
                     "type": "string"
                 },
-                "baseUrl": {
+                "commit": {
                     "type": "string"
                 },
-                "model": {
+                "status": {
                     "type": "string"
                 },
-                "provider": {
+                "version": {
                     "type": "string"
-                },
-                "requestOptions": {
-                    "type": "object",
-                    "additionalProperties": {}
                 }
             }
         },
-        "internal_handler.aiTestResponse": {
+        "internal_handler.iconClearResponse": {
             "type": "object",
             "properties": {
-                "error": {
-                    "type": "string"
-                },
-                "message": {
-                    "type": "string"
-                },
-                "success": {
+                "deleted": {
+                    "type": "integer"
+                }
+            }
+        },
+        "internal_handler.importCancelledResponse": {
+            "type": "object",
+            "properties": {
+                "cancelled": {
                     "type": "boolean"
                 }
             }
         },
-        "internal_handler.appearanceSettingsRequest": {
+        "internal_handler.importStartedResponse": {
             "type": "object",
             "properties": {
-                "contentTypes": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
+                "status": {
+                    "type": "string"
                 }
             }
         },
-        "internal_handler.appearanceSettingsResponse": {
+        "internal_handler.ingestIssueResponse": {
             "type": "object",
             "properties": {
-                "contentTypes": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
+                "detail": {
+                    "description": "Detail is the truncated field, the unparsed date text or the implausible date",
+                    "type": "string"
+                },
+                "guid": {
+                    "type": "string"
+                },
+                "link": {
+                    "type": "string"
+                },
+                "reason": {
+                    "description": "Reason is missing_link, duplicate, backfill_limit, truncated, unparsed_date\nor implausible_date",
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
                 }
             }
         },
-        "internal_handler.authResponse": {
+        "internal_handler.ingestItemRequest": {
             "type": "object",
             "properties": {
-                "token": {
+                "author": {
                     "type": "string"
                 },
-                "user": {
-                    "$ref": "#/definitions/internal_handler.userResponse"
+                "content": {
+                    "type": "string"
+                },
+                "externalId": {
+                    "description": "ExternalID identifies the item across pushes; without it the URL does.\nItems without a URL need one.",
+                    "type": "string"
+                },
+                "publishedAt": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
                 }
             }
         },
-        "internal_handler.authStatusResponse": {
+        "internal_handler.ingestItemResult": {
             "type": "object",
             "properties": {
-                "exists": {
-                    "type": "boolean"
+                "index": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "created"
                 }
             }
         },
-        "internal_handler.batchTranslateRequest": {
+        "internal_handler.ingestReportResponse": {
             "type": "object",
             "properties": {
-                "articles": {
+                "checkedAt": {
+                    "type": "string"
+                },
+                "counts": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "issues": {
                     "type": "array",
                     "items": {
-                        "type": "object",
-                        "properties": {
-                            "id": {
-                                "type": "string"
-                            },
-                            "summary": {
-                                "type": "string"
-                            },
-                            "title": {
-                                "type": "string"
-                            }
-                        }
+                        "$ref": "#/definitions/internal_handler.ingestIssueResponse"
                     }
+                },
+                "items": {
+                    "type": "integer"
+                },
+                "saved": {
+                    "type": "integer"
                 }
             }
         },
-        "internal_handler.clearCacheResponse": {
+        "internal_handler.ingestResponse": {
             "type": "object",
             "properties": {
-                "listTranslations": {
+                "created": {
                     "type": "integer"
                 },
-                "summaries": {
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_handler.ingestItemResult"
+                    }
+                },
+                "skipped": {
                     "type": "integer"
                 },
-                "translations": {
+                "updated": {
                     "type": "integer"
                 }
             }
         },
-        "internal_handler.createFeedRequest": {
+        "internal_handler.ingestTokenResponse": {
             "type": "object",
             "properties": {
-                "folderId": {
-                    "type": "string"
-                },
-                "title": {
-                    "type": "string"
-                },
-                "type": {
-                    "type": "string"
-                },
-                "url": {
+                "token": {
                     "type": "string"
                 }
             }
         },
-        "internal_handler.deleteFeedsRequest": {
+        "internal_handler.loginEventListResponse": {
             "type": "object",
             "properties": {
-                "ids": {
+                "items": {
                     "type": "array",
                     "items": {
-                        "type": "string"
+                        "$ref": "#/definitions/internal_handler.loginEventResponse"
                     }
                 }
             }
         },
-        "internal_handler.deleteFoldersRequest": {
+        "internal_handler.loginEventResponse": {
             "type": "object",
             "properties": {
-                "ids": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
+                "createdAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "identifier": {
+                    "type": "string"
+                },
+                "ip": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                },
+                "userAgent": {
+                    "type": "string"
                 }
             }
         },
-        "internal_handler.deletedCountResponse": {
+        "internal_handler.loginRequest": {
             "type": "object",
             "properties": {
-                "deleted": {
-                    "type": "integer"
+                "identifier": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
                 }
             }
         },
-        "internal_handler.domainRateLimitListResponse": {
+        "internal_handler.maintenanceRequest": {
             "type": "object",
             "properties": {
-                "items": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/internal_handler.domainRateLimitResponse"
-                    }
+                "action": {
+                    "type": "string",
+                    "example": "normalize_dates"
                 }
             }
         },
-        "internal_handler.domainRateLimitRequest": {
+        "internal_handler.maintenanceResponse": {
             "type": "object",
             "properties": {
-                "host": {
+                "action": {
                     "type": "string"
                 },
-                "intervalSeconds": {
+                "skipped": {
+                    "type": "integer"
+                },
+                "updated": {
                     "type": "integer"
                 }
             }
         },
-        "internal_handler.domainRateLimitResponse": {
+        "internal_handler.markAllReadRequest": {
             "type": "object",
             "properties": {
-                "host": {
+                "contentType": {
                     "type": "string"
                 },
-                "id": {
+                "feedId": {
                     "type": "string"
                 },
-                "intervalSeconds": {
-                    "type": "integer"
+                "folderId": {
+                    "type": "string"
                 }
             }
         },
-        "internal_handler.entryClearResponse": {
+        "internal_handler.mergeFeedOverlapRequest": {
             "type": "object",
             "properties": {
-                "deleted": {
-                    "type": "integer"
+                "keepFeedId": {
+                    "description": "KeepFeedID is the feed of the pair to keep; the pair's first feed if empty.",
+                    "type": "string"
                 }
             }
         },
-        "internal_handler.entryListResponse": {
+        "internal_handler.mutedAuthorRequest": {
+            "type": "object",
+            "properties": {
+                "author": {
+                    "type": "string",
+                    "example": "Jane Doe"
+                }
+            }
+        },
+        "internal_handler.mutedAuthorsResponse": {
             "type": "object",
             "properties": {
-                "entries": {
+                "authors": {
+                    "description": "Authors are matched ignoring case and surrounding spaces.",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/internal_handler.entryResponse"
+                        "type": "string"
                     }
-                },
-                "hasMore": {
-                    "type": "boolean"
                 }
             }
         },
-        "internal_handler.entryResponse": {
+        "internal_handler.networkSettingsRequest": {
             "type": "object",
             "properties": {
-                "author": {
-                    "type": "string"
+                "enabled": {
+                    "type": "boolean"
                 },
-                "content": {
+                "host": {
                     "type": "string"
                 },
-                "createdAt": {
+                "ipStack": {
                     "type": "string"
                 },
-                "feedId": {
+                "password": {
                     "type": "string"
                 },
-                "id": {
-                    "type": "string"
+                "port": {
+                    "type": "integer"
                 },
-                "publishedAt": {
+                "type": {
                     "type": "string"
                 },
-                "read": {
-                    "type": "boolean"
-                },
-                "readableContent": {
+                "username": {
                     "type": "string"
                 },
-                "starred": {
+                "version": {
+                    "description": "Version from the last read; a stale one is rejected with 409. Omitted saves unconditionally",
+                    "type": "string"
+                }
+            }
+        },
+        "internal_handler.networkSettingsResponse": {
+            "type": "object",
+            "properties": {
+                "enabled": {
                     "type": "boolean"
                 },
-                "thumbnailUrl": {
+                "host": {
                     "type": "string"
                 },
-                "title": {
+                "ipStack": {
                     "type": "string"
                 },
-                "updatedAt": {
+                "password": {
                     "type": "string"
                 },
-                "url": {
+                "port": {
+                    "type": "integer"
+                },
+                "type": {
                     "type": "string"
-                }
-            }
-        },
-        "internal_handler.errorResponse": {
-            "type": "object",
-            "properties": {
-                "error": {
+                },
+                "username": {
                     "type": "string"
-                }
-            }
-        },
-        "internal_handler.feedConflictResponse": {
-            "type": "object",
-            "properties": {
-                "error": {
-                    "type": "string",
-                    "example": "feed_exists"
                 },
-                "existingFeed": {
-                    "$ref": "#/definitions/internal_handler.feedResponse"
+                "version": {
+                    "description": "Version identifies this read; send it back on update to detect concurrent saves",
+                    "type": "string",
+                    "example": "3"
                 }
             }
         },
-        "internal_handler.feedPreviewResponse": {
+        "internal_handler.networkTestRequest": {
             "type": "object",
             "properties": {
-                "description": {
+                "enabled": {
+                    "type": "boolean"
+                },
+                "host": {
                     "type": "string"
                 },
-                "imageUrl": {
+                "password": {
                     "type": "string"
                 },
-                "itemCount": {
+                "port": {
                     "type": "integer"
                 },
-                "lastUpdated": {
-                    "type": "string"
-                },
-                "siteUrl": {
+                "testUrl": {
+                    "description": "TestURL is fetched through the proxy; defaults to defaultProxyTestURL.",
                     "type": "string"
                 },
-                "title": {
+                "type": {
                     "type": "string"
                 },
-                "url": {
+                "username": {
                     "type": "string"
                 }
             }
         },
-        "internal_handler.feedResponse": {
+        "internal_handler.networkTestResponse": {
             "type": "object",
             "properties": {
-                "createdAt": {
+                "egressIp": {
+                    "description": "EgressIP is set when the test URL echoes the caller's address.",
                     "type": "string"
                 },
-                "description": {
+                "error": {
                     "type": "string"
                 },
-                "errorMessage": {
-                    "type": "string"
+                "latencyMs": {
+                    "type": "integer"
                 },
-                "etag": {
+                "message": {
                     "type": "string"
                 },
-                "folderId": {
-                    "type": "string"
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "internal_handler.patchFeedRequest": {
+            "type": "object",
+            "properties": {
+                "assumeTimezone": {
+                    "type": "string",
+                    "example": "Asia/Shanghai"
                 },
-                "iconPath": {
-                    "type": "string"
+                "autoReadability": {
+                    "description": "AutoReadability null inherits the folder's choice.",
+                    "type": "boolean",
+                    "example": true
                 },
-                "id": {
-                    "type": "string"
+                "autoTranslate": {
+                    "type": "string",
+                    "example": "off"
                 },
-                "lastModified": {
-                    "type": "string"
+                "dedupeKey": {
+                    "type": "string",
+                    "example": "url"
                 },
-                "siteUrl": {
-                    "type": "string"
+                "folderId": {
+                    "description": "FolderID moves the feed into a folder of its type; null moves it out of any.",
+                    "type": "string",
+                    "example": "12"
+                },
+                "maxEntries": {
+                    "description": "MaxEntries caps the entries kept for the feed; 0 removes the cap.",
+                    "type": "integer",
+                    "example": 500
+                },
+                "pausedUntil": {
+                    "description": "PausedUntil is an RFC3339 timestamp in the future; null unpauses the feed.",
+                    "type": "string",
+                    "example": "2026-01-01T00:00:00Z"
                 },
                 "summaryPromptReminder": {
+                    "description": "SummaryPromptReminder, AssumeTimezone and UserAgent are cleared by null or a blank value.",
                     "type": "string"
                 },
                 "title": {
-                    "type": "string"
+                    "type": "string",
+                    "example": "My feed"
                 },
                 "type": {
-                    "type": "string"
-                },
-                "updatedAt": {
-                    "type": "string"
+                    "type": "string",
+                    "example": "article"
                 },
-                "url": {
-                    "type": "string"
+                "userAgent": {
+                    "type": "string",
+                    "example": "Mozilla/5.0"
                 }
             }
         },
-        "internal_handler.folderRequest": {
+        "internal_handler.pauseFeedRequest": {
             "type": "object",
             "properties": {
-                "name": {
-                    "type": "string"
+                "duration": {
+                    "description": "Duration is a Go duration such as \"24h\"; set either it or Until.",
+                    "type": "string",
+                    "example": "168h"
                 },
-                "parentId": {
+                "until": {
+                    "description": "Until is an RFC3339 timestamp.",
+                    "type": "string",
+                    "example": "2026-01-01T00:00:00Z"
+                }
+            }
+        },
+        "internal_handler.readableContentResponse": {
+            "type": "object",
+            "properties": {
+                "readableContent": {
                     "type": "string"
                 },
-                "type": {
+                "status": {
+                    "description": "Status is \"ready\" with ReadableContent, or \"pending\" while it is being extracted.",
                     "type": "string"
                 }
             }
         },
-        "internal_handler.folderResponse": {
+        "internal_handler.readinessCheckResponse": {
             "type": "object",
             "properties": {
-                "createdAt": {
-                    "type": "string"
-                },
-                "id": {
+                "error": {
                     "type": "string"
                 },
                 "name": {
                     "type": "string"
                 },
-                "parentId": {
-                    "type": "string"
-                },
-                "type": {
-                    "type": "string"
-                },
-                "updatedAt": {
+                "status": {
                     "type": "string"
                 }
             }
         },
-        "internal_handler.generalSettingsRequest": {
+        "internal_handler.readinessResponse": {
             "type": "object",
             "properties": {
-                "autoReadability": {
-                    "type": "boolean"
+                "checks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_handler.readinessCheckResponse"
+                    }
                 },
-                "fallbackUserAgent": {
+                "status": {
                     "type": "string"
                 },
-                "markReadOnScroll": {
-                    "type": "boolean"
+                "warnings": {
+                    "description": "Warnings don't affect readiness, e.g. feeds flagged for suspect caching upstream or unstable",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_handler.readinessWarningResponse"
+                    }
                 }
             }
         },
-        "internal_handler.generalSettingsResponse": {
+        "internal_handler.readinessWarningResponse": {
             "type": "object",
             "properties": {
-                "autoReadability": {
-                    "type": "boolean"
-                },
-                "fallbackUserAgent": {
+                "message": {
                     "type": "string"
                 },
-                "markReadOnScroll": {
-                    "type": "boolean"
+                "name": {
+                    "type": "string"
                 }
             }
         },
-        "internal_handler.iconClearResponse": {
+        "internal_handler.readingDayResponse": {
             "type": "object",
             "properties": {
-                "deleted": {
+                "count": {
                     "type": "integer"
+                },
+                "date": {
+                    "type": "string"
                 }
             }
         },
-        "internal_handler.importCancelledResponse": {
+        "internal_handler.readingFeedResponse": {
             "type": "object",
             "properties": {
-                "cancelled": {
-                    "type": "boolean"
+                "count": {
+                    "type": "integer"
+                },
+                "feedId": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
                 }
             }
         },
-        "internal_handler.importStartedResponse": {
+        "internal_handler.readingStatsResponse": {
             "type": "object",
             "properties": {
-                "status": {
+                "averageReadDelaySeconds": {
+                    "description": "AverageReadDelaySeconds is omitted when no read entry has a published date.",
+                    "type": "integer"
+                },
+                "daily": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_handler.readingDayResponse"
+                    }
+                },
+                "days": {
+                    "type": "integer"
+                },
+                "since": {
+                    "type": "string"
+                },
+                "timezone": {
                     "type": "string"
+                },
+                "topFeeds": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_handler.readingFeedResponse"
+                    }
+                },
+                "totalRead": {
+                    "type": "integer"
+                },
+                "totalStarred": {
+                    "type": "integer"
                 }
             }
         },
-        "internal_handler.loginRequest": {
+        "internal_handler.refreshRunDetailResponse": {
             "type": "object",
             "properties": {
-                "identifier": {
-                    "type": "string"
+                "feeds": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_handler.refreshRunFeedResponse"
+                    }
                 },
-                "password": {
-                    "type": "string"
+                "run": {
+                    "$ref": "#/definitions/internal_handler.refreshRunResponse"
                 }
             }
         },
-        "internal_handler.markAllReadRequest": {
+        "internal_handler.refreshRunFeedResponse": {
             "type": "object",
             "properties": {
-                "contentType": {
+                "entriesEvicted": {
+                    "type": "integer"
+                },
+                "entriesNew": {
+                    "type": "integer"
+                },
+                "entriesUpdated": {
+                    "type": "integer"
+                },
+                "errorMessage": {
                     "type": "string"
                 },
                 "feedId": {
                     "type": "string"
                 },
-                "folderId": {
+                "feedTitle": {
+                    "type": "string"
+                },
+                "status": {
                     "type": "string"
                 }
             }
         },
-        "internal_handler.networkSettingsRequest": {
+        "internal_handler.refreshRunResponse": {
             "type": "object",
             "properties": {
-                "enabled": {
-                    "type": "boolean"
+                "entriesEvicted": {
+                    "type": "integer"
                 },
-                "host": {
-                    "type": "string"
+                "entriesNew": {
+                    "type": "integer"
                 },
-                "ipStack": {
-                    "type": "string"
+                "entriesUpdated": {
+                    "type": "integer"
+                },
+                "feedsFailed": {
+                    "type": "integer"
+                },
+                "feedsTotal": {
+                    "type": "integer"
                 },
-                "password": {
+                "finishedAt": {
                     "type": "string"
                 },
-                "port": {
-                    "type": "integer"
+                "id": {
+                    "type": "string"
                 },
-                "type": {
+                "startedAt": {
                     "type": "string"
                 },
-                "username": {
+                "trigger": {
                     "type": "string"
                 }
             }
         },
-        "internal_handler.networkSettingsResponse": {
+        "internal_handler.refreshStatusResponse": {
             "type": "object",
             "properties": {
-                "enabled": {
+                "isRefreshing": {
                     "type": "boolean"
                 },
-                "host": {
+                "lastRefreshedAt": {
                     "type": "string"
                 },
-                "ipStack": {
-                    "type": "string"
+                "paused": {
+                    "description": "Paused is \"quiet_hours\" while scheduled refreshes are skipped, until PausedUntil",
+                    "type": "string",
+                    "enum": [
+                        "quiet_hours"
+                    ]
                 },
-                "password": {
+                "pausedUntil": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_handler.registerRequest": {
+            "type": "object",
+            "properties": {
+                "email": {
                     "type": "string"
                 },
-                "port": {
-                    "type": "integer"
+                "nickname": {
+                    "type": "string"
                 },
-                "type": {
+                "password": {
                     "type": "string"
                 },
                 "username": {
@@ -2720,87 +8320,162 @@ const docTemplate = `{
                 }
             }
         },
-        "internal_handler.networkTestRequest": {
+        "internal_handler.reorderFeedsRequest": {
             "type": "object",
             "properties": {
-                "enabled": {
-                    "type": "boolean"
-                },
-                "host": {
+                "ids": {
+                    "description": "IDs lists every feed to place, all from one folder, in display order.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "internal_handler.reorderFoldersRequest": {
+            "type": "object",
+            "properties": {
+                "ids": {
+                    "description": "IDs lists every folder to place, all under one parent, in display order.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "internal_handler.savePageRequest": {
+            "type": "object",
+            "properties": {
+                "url": {
                     "type": "string"
-                },
-                "password": {
+                }
+            }
+        },
+        "internal_handler.securitySettingsRequest": {
+            "type": "object",
+            "properties": {
+                "cookieDomain": {
                     "type": "string"
                 },
-                "port": {
-                    "type": "integer"
-                },
-                "type": {
+                "cookieSameSite": {
                     "type": "string"
                 },
-                "username": {
-                    "type": "string"
+                "cookieSecure": {
+                    "type": "boolean"
+                },
+                "corsOrigins": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 }
             }
         },
-        "internal_handler.networkTestResponse": {
+        "internal_handler.securitySettingsResponse": {
             "type": "object",
             "properties": {
-                "error": {
+                "cookieDomain": {
                     "type": "string"
                 },
-                "message": {
+                "cookieSameSite": {
                     "type": "string"
                 },
-                "success": {
+                "cookieSecure": {
                     "type": "boolean"
+                },
+                "corsOrigins": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 }
             }
         },
-        "internal_handler.readableContentResponse": {
+        "internal_handler.starredCountResponse": {
             "type": "object",
             "properties": {
-                "readableContent": {
-                    "type": "string"
+                "count": {
+                    "type": "integer"
                 }
             }
         },
-        "internal_handler.refreshStatusResponse": {
+        "internal_handler.staticFeedUpdateResponse": {
             "type": "object",
             "properties": {
-                "isRefreshing": {
-                    "type": "boolean"
+                "entriesNew": {
+                    "type": "integer"
                 },
-                "lastRefreshedAt": {
-                    "type": "string"
+                "entriesUpdated": {
+                    "type": "integer"
                 }
             }
         },
-        "internal_handler.registerRequest": {
+        "internal_handler.storageDirectoryResponse": {
             "type": "object",
             "properties": {
-                "email": {
-                    "type": "string"
+                "bytes": {
+                    "type": "integer"
                 },
-                "nickname": {
-                    "type": "string"
+                "files": {
+                    "type": "integer"
                 },
-                "password": {
-                    "type": "string"
+                "name": {
+                    "type": "string",
+                    "example": "icons"
                 },
-                "username": {
-                    "type": "string"
+                "present": {
+                    "type": "boolean"
                 }
             }
         },
-        "internal_handler.starredCountResponse": {
+        "internal_handler.storageResponse": {
             "type": "object",
             "properties": {
-                "count": {
+                "databaseBytes": {
+                    "type": "integer"
+                },
+                "directories": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_handler.storageDirectoryResponse"
+                    }
+                },
+                "shmBytes": {
+                    "type": "integer"
+                },
+                "tableBytesAvailable": {
+                    "type": "boolean"
+                },
+                "tables": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_handler.storageTableResponse"
+                    }
+                },
+                "walBytes": {
                     "type": "integer"
                 }
             }
         },
+        "internal_handler.storageTableResponse": {
+            "type": "object",
+            "properties": {
+                "bytes": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "entries"
+                },
+                "rows": {
+                    "type": "integer"
+                },
+                "share": {
+                    "type": "number"
+                }
+            }
+        },
         "internal_handler.summarizeRequest": {
             "type": "object",
             "properties": {
@@ -2813,6 +8488,10 @@ const docTemplate = `{
                 "isReadability": {
                     "type": "boolean"
                 },
+                "language": {
+                    "description": "Language overrides the configured summary language, e.g. \"en-US\".",
+                    "type": "string"
+                },
                 "title": {
                     "type": "string"
                 }
@@ -2829,6 +8508,21 @@ const docTemplate = `{
                 }
             }
         },
+        "internal_handler.translateFeedsRequest": {
+            "type": "object",
+            "properties": {
+                "feedIds": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "language": {
+                    "description": "Language overrides the configured summary language, e.g. \"en-US\".",
+                    "type": "string"
+                }
+            }
+        },
         "internal_handler.translateRequest": {
             "type": "object",
             "properties": {
@@ -2841,6 +8535,10 @@ const docTemplate = `{
                 "isReadability": {
                     "type": "boolean"
                 },
+                "language": {
+                    "description": "Language overrides the configured summary language, e.g. \"en-US\".",
+                    "type": "string"
+                },
                 "title": {
                     "type": "string"
                 }
@@ -2857,6 +8555,55 @@ const docTemplate = `{
                 }
             }
         },
+        "internal_handler.twoFactorDisableRequest": {
+            "type": "object",
+            "properties": {
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_handler.twoFactorEnableRequest": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_handler.twoFactorEnableResponse": {
+            "type": "object",
+            "properties": {
+                "recoveryCodes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "internal_handler.twoFactorSetupResponse": {
+            "type": "object",
+            "properties": {
+                "otpauthUrl": {
+                    "type": "string"
+                },
+                "secret": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_handler.twoFactorVerifyRequest": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "pendingToken": {
+                    "type": "string"
+                }
+            }
+        },
         "internal_handler.unreadCountsResponse": {
             "type": "object",
             "properties": {
@@ -2865,6 +8612,42 @@ const docTemplate = `{
                     "additionalProperties": {
                         "type": "integer"
                     }
+                },
+                "today": {
+                    "description": "Today maps feed IDs to the entries saved since the start of today in\nthe configured timezone; feeds without any are left out.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "internal_handler.updateAutoReadabilityRequest": {
+            "type": "object",
+            "properties": {
+                "autoReadability": {
+                    "description": "AutoReadability opens entries in readability mode; null inherits the folder's choice.",
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "internal_handler.updateAutoTranslateRequest": {
+            "type": "object",
+            "properties": {
+                "autoTranslate": {
+                    "description": "AutoTranslate is inherit, on or off.",
+                    "type": "string",
+                    "example": "off"
+                }
+            }
+        },
+        "internal_handler.updateDedupeKeyRequest": {
+            "type": "object",
+            "properties": {
+                "dedupeKey": {
+                    "type": "string",
+                    "example": "url"
                 }
             }
         },
@@ -2877,6 +8660,11 @@ const docTemplate = `{
                 "folderId": {
                     "type": "string"
                 },
+                "maxEntries": {
+                    "description": "MaxEntries caps the entries kept for the feed; 0 removes the cap.",
+                    "type": "integer",
+                    "example": 500
+                },
                 "summaryPromptReminder": {
                     "type": "string"
                 },
@@ -2885,6 +8673,22 @@ const docTemplate = `{
                 }
             }
         },
+        "internal_handler.updateFolderFeedDefaultsRequest": {
+            "type": "object",
+            "properties": {
+                "autoReadability": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "autoTranslate": {
+                    "type": "boolean"
+                },
+                "userAgent": {
+                    "type": "string",
+                    "example": "Mozilla/5.0"
+                }
+            }
+        },
         "internal_handler.updateFolderTypeRequest": {
             "type": "object",
             "properties": {
@@ -2907,6 +8711,14 @@ const docTemplate = `{
                 }
             }
         },
+        "internal_handler.updateNoteRequest": {
+            "type": "object",
+            "properties": {
+                "note": {
+                    "type": "string"
+                }
+            }
+        },
         "internal_handler.updateProfileRequest": {
             "type": "object",
             "properties": {
@@ -2951,6 +8763,23 @@ const docTemplate = `{
                 }
             }
         },
+        "internal_handler.updateStaticFeedRequest": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_handler.updateTimezoneRequest": {
+            "type": "object",
+            "properties": {
+                "assumeTimezone": {
+                    "description": "AssumeTimezone is an IANA zone name; null or empty uses the global setting.",
+                    "type": "string"
+                }
+            }
+        },
         "internal_handler.updateTypeRequest": {
             "type": "object",
             "properties": {
@@ -2959,6 +8788,16 @@ const docTemplate = `{
                 }
             }
         },
+        "internal_handler.updateUserAgentRequest": {
+            "type": "object",
+            "properties": {
+                "userAgent": {
+                    "description": "UserAgent replaces the default User-Agent of refreshes; null or blank inherits the folder's.",
+                    "type": "string",
+                    "example": "Mozilla/5.0"
+                }
+            }
+        },
         "internal_handler.userResponse": {
             "type": "object",
             "properties": {
@@ -2971,6 +8810,9 @@ const docTemplate = `{
                 "nickname": {
                     "type": "string"
                 },
+                "twoFactorEnabled": {
+                    "type": "boolean"
+                },
                 "username": {
                     "type": "string"
                 }
@@ -3,6 +3,9 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 )
 
 const (
@@ -31,6 +34,29 @@ type Config struct {
 	LogLevel      string
 	EnableSwagger bool
 	PprofAddr     string
+	// TrustProxy honors X-Forwarded-For when resolving the client IP.
+	// Only enable when Gist is reachable solely through a reverse proxy.
+	TrustProxy bool
+	// CORSOrigins lists origins allowed to call the API cross-origin (GIST_CORS_ORIGINS, comma separated).
+	CORSOrigins []string
+	// Auth cookie defaults; overridable via the security settings API.
+	CookieSameSite string
+	CookieSecure   bool
+	CookieDomain   string
+	// Entry title/author length limits in runes applied at ingest
+	// (GIST_MAX_TITLE_LENGTH, GIST_MAX_AUTHOR_LENGTH).
+	MaxTitleLength  int
+	MaxAuthorLength int
+	// ShutdownTimeout bounds each shutdown phase (GIST_SHUTDOWN_TIMEOUT, seconds).
+	ShutdownTimeout time.Duration
+	// ThumbnailCacheMB caps the disk space of scaled entry thumbnails (GIST_THUMBNAIL_CACHE_MB).
+	ThumbnailCacheMB int
+	// Request body limits in KB: most routes (GIST_BODY_LIMIT_KB), OPML,
+	// static feed and pushed entry imports (GIST_IMPORT_BODY_LIMIT_KB), and
+	// auth and settings (GIST_SETTINGS_BODY_LIMIT_KB).
+	BodyLimitKB         int
+	ImportBodyLimitKB   int
+	SettingsBodyLimitKB int
 }
 
 func Load() Config {
@@ -57,6 +83,14 @@ func Load() Config {
 	}
 
 	enableSwagger := os.Getenv("GIST_SWAGGER") == "true"
+	trustProxy := os.Getenv("GIST_TRUST_PROXY") == "true"
+
+	var corsOrigins []string
+	for _, origin := range strings.Split(os.Getenv("GIST_CORS_ORIGINS"), ",") {
+		if origin = strings.TrimSpace(origin); origin != "" {
+			corsOrigins = append(corsOrigins, origin)
+		}
+	}
 	pprofAddr := os.Getenv("GIST_PPROF_ADDR")
 	if os.Getenv("GIST_ENABLE_PPROF") == "true" && pprofAddr == "" {
 		pprofAddr = "127.0.0.1:6060"
@@ -70,7 +104,30 @@ func Load() Config {
 		LogLevel:      logLevel,
 		EnableSwagger: enableSwagger,
 		PprofAddr:     pprofAddr,
+		TrustProxy:    trustProxy,
+		CORSOrigins:   corsOrigins,
+
+		CookieSameSite: os.Getenv("GIST_COOKIE_SAMESITE"),
+		CookieSecure:   os.Getenv("GIST_COOKIE_SECURE") == "true",
+		CookieDomain:   os.Getenv("GIST_COOKIE_DOMAIN"),
+
+		MaxTitleLength:   positiveIntEnv("GIST_MAX_TITLE_LENGTH", 500),
+		MaxAuthorLength:  positiveIntEnv("GIST_MAX_AUTHOR_LENGTH", 200),
+		ShutdownTimeout:  time.Duration(positiveIntEnv("GIST_SHUTDOWN_TIMEOUT", 10)) * time.Second,
+		ThumbnailCacheMB: positiveIntEnv("GIST_THUMBNAIL_CACHE_MB", 500),
+
+		BodyLimitKB:         positiveIntEnv("GIST_BODY_LIMIT_KB", 1024),
+		ImportBodyLimitKB:   positiveIntEnv("GIST_IMPORT_BODY_LIMIT_KB", 10240),
+		SettingsBodyLimitKB: positiveIntEnv("GIST_SETTINGS_BODY_LIMIT_KB", 256),
+	}
+}
+
+func positiveIntEnv(key string, fallback int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil || value <= 0 {
+		return fallback
 	}
+	return value
 }
 
 func detectStaticDir() string {
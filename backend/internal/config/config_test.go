@@ -4,6 +4,7 @@ import (
 	"gist/backend/internal/config"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -14,11 +15,15 @@ func TestLoad(t *testing.T) {
 	os.Setenv("GIST_DATA_DIR", "/tmp/gist")
 	os.Setenv("GIST_LOG_LEVEL", "debug")
 	os.Setenv("GIST_PPROF_ADDR", "127.0.0.1:6060")
+	os.Setenv("GIST_TRUST_PROXY", "true")
+	os.Setenv("GIST_IMPORT_BODY_LIMIT_KB", "51200")
 	defer func() {
 		os.Unsetenv("GIST_ADDR")
 		os.Unsetenv("GIST_DATA_DIR")
 		os.Unsetenv("GIST_LOG_LEVEL")
 		os.Unsetenv("GIST_PPROF_ADDR")
+		os.Unsetenv("GIST_TRUST_PROXY")
+		os.Unsetenv("GIST_IMPORT_BODY_LIMIT_KB")
 	}()
 
 	cfg := config.Load()
@@ -27,6 +32,8 @@ func TestLoad(t *testing.T) {
 	require.Contains(t, cfg.DBPath, "/tmp/gist/gist.db")
 	require.Equal(t, "debug", cfg.LogLevel)
 	require.Equal(t, "127.0.0.1:6060", cfg.PprofAddr)
+	require.True(t, cfg.TrustProxy)
+	require.Equal(t, 51200, cfg.ImportBodyLimitKB)
 }
 
 func TestLoad_Defaults(t *testing.T) {
@@ -37,6 +44,14 @@ func TestLoad_Defaults(t *testing.T) {
 	os.Unsetenv("GIST_LOG_LEVEL")
 	os.Unsetenv("GIST_PPROF_ADDR")
 	os.Unsetenv("GIST_ENABLE_PPROF")
+	os.Unsetenv("GIST_TRUST_PROXY")
+	os.Unsetenv("GIST_MAX_TITLE_LENGTH")
+	os.Unsetenv("GIST_MAX_AUTHOR_LENGTH")
+	os.Unsetenv("GIST_SHUTDOWN_TIMEOUT")
+	os.Unsetenv("GIST_THUMBNAIL_CACHE_MB")
+	os.Unsetenv("GIST_BODY_LIMIT_KB")
+	os.Unsetenv("GIST_IMPORT_BODY_LIMIT_KB")
+	os.Unsetenv("GIST_SETTINGS_BODY_LIMIT_KB")
 
 	cfg := config.Load()
 	require.Equal(t, ":8080", cfg.Addr)
@@ -44,4 +59,12 @@ func TestLoad_Defaults(t *testing.T) {
 	require.Contains(t, cfg.DBPath, "gist.db")
 	require.Equal(t, "info", cfg.LogLevel)
 	require.Empty(t, cfg.PprofAddr)
+	require.False(t, cfg.TrustProxy)
+	require.Equal(t, 500, cfg.MaxTitleLength)
+	require.Equal(t, 200, cfg.MaxAuthorLength)
+	require.Equal(t, 10*time.Second, cfg.ShutdownTimeout)
+	require.Equal(t, 500, cfg.ThumbnailCacheMB)
+	require.Equal(t, 1024, cfg.BodyLimitKB)
+	require.Equal(t, 10240, cfg.ImportBodyLimitKB)
+	require.Equal(t, 256, cfg.SettingsBodyLimitKB)
 }
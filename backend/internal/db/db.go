@@ -38,9 +38,14 @@ func Open(path string) (*sql.DB, error) {
 // This ensures all connections in the pool have the same settings.
 func buildDSN(path string) string {
 	params := url.Values{}
+	// busy_timeout goes first: pragmas apply in order, and switching a new file
+	// to WAL needs a lock another process starting up may hold.
+	params.Add("_pragma", "busy_timeout(30000)")
 	params.Add("_pragma", "journal_mode(WAL)")
 	params.Add("_pragma", "foreign_keys(ON)")
-	params.Add("_pragma", "busy_timeout(30000)")
 	params.Add("_pragma", "synchronous(NORMAL)")
+	// Write transactions that read first (e.g. entry batch saves) would otherwise fail with
+	// SQLITE_BUSY when upgrading to a write lock; BEGIN IMMEDIATE waits on busy_timeout instead.
+	params.Add("_txlock", "immediate")
 	return fmt.Sprintf("file:%s?%s", path, params.Encode())
 }
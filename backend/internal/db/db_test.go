@@ -57,6 +57,11 @@ func TestBuildDSN_ContainsBusyTimeout(t *testing.T) {
 	require.Contains(t, dsn, "NORMAL", "synchronous should be set to NORMAL")
 }
 
+func TestBuildDSN_ImmediateTransactions(t *testing.T) {
+	dsn := db.BuildDSN("test.db")
+	require.Contains(t, dsn, "_txlock=immediate", "transactions must take the write lock up front")
+}
+
 // TestBuildDSN_AllPragmasInDSN verifies all required pragmas are embedded in DSN.
 // This is essential because pragmas applied via Exec only affect the current connection,
 // not other connections in the pool.
@@ -87,3 +92,17 @@ func TestMigrate_ClosedDB(t *testing.T) {
 	err = db.Migrate(database)
 	require.Error(t, err)
 }
+
+func TestMigrationState(t *testing.T) {
+	database, err := sql.Open("sqlite", "file::memory:?cache=shared")
+	require.NoError(t, err)
+	require.NoError(t, database.Close())
+
+	require.Error(t, db.Migrate(database))
+	require.Equal(t, db.MigrationFailed, db.MigrationState())
+
+	database, err = db.Open(filepath.Join(t.TempDir(), "gist.db"))
+	require.NoError(t, err)
+	defer database.Close()
+	require.Equal(t, db.MigrationComplete, db.MigrationState())
+}
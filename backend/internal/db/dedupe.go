@@ -0,0 +1,358 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Querier is the part of *sql.DB and *sql.Tx the entry merge helpers need.
+type Querier interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// DedupeEntry is what a merge key is derived from. Text fields are trimmed.
+type DedupeEntry struct {
+	ID      int64
+	FeedID  int64
+	URL     string
+	Title   string
+	Content string
+}
+
+type dedupeEntry struct {
+	DedupeEntry
+	read      int
+	starred   int
+	updatedAt time.Time
+}
+
+type dedupeGroup struct {
+	keep       dedupeEntry
+	readMax    int
+	starredMax int
+	duplicate  []int64
+}
+
+// MergeDuplicateEntries merges entries of the same feed that share a key: the most
+// recently updated one is kept, takes over read/starred state, AI caches and notes, and the
+// rest are deleted. feedID 0 covers every feed. Returns how many entries were deleted.
+func MergeDuplicateEntries(ctx context.Context, q Querier, feedID int64, key func(DedupeEntry) string) (int, error) {
+	entries, err := queryDedupeEntries(ctx, q, feedID)
+	if err != nil {
+		return 0, err
+	}
+
+	groups := make(map[string]*dedupeGroup)
+	for _, entry := range entries {
+		groupKey := fmt.Sprintf("%d|%s", entry.FeedID, key(entry.DedupeEntry))
+		group, ok := groups[groupKey]
+		if !ok {
+			groups[groupKey] = &dedupeGroup{
+				keep:       entry,
+				readMax:    entry.read,
+				starredMax: entry.starred,
+			}
+			continue
+		}
+
+		if entry.read > group.readMax {
+			group.readMax = entry.read
+		}
+		if entry.starred > group.starredMax {
+			group.starredMax = entry.starred
+		}
+
+		if chooseAsKeep(entry, group.keep) {
+			group.duplicate = append(group.duplicate, group.keep.ID)
+			group.keep = entry
+		} else {
+			group.duplicate = append(group.duplicate, entry.ID)
+		}
+	}
+
+	removed := 0
+	for _, group := range groups {
+		if len(group.duplicate) == 0 {
+			continue
+		}
+
+		if group.keep.read != group.readMax || group.keep.starred != group.starredMax {
+			if _, err := q.ExecContext(
+				ctx,
+				`UPDATE entries SET read = ?, starred = ? WHERE id = ?`,
+				group.readMax,
+				group.starredMax,
+				group.keep.ID,
+			); err != nil {
+				return removed, fmt.Errorf("update merged entry state: %w", err)
+			}
+		}
+
+		for _, duplicateID := range group.duplicate {
+			if err := moveEntryCaches(ctx, q, duplicateID, group.keep.ID); err != nil {
+				return removed, err
+			}
+			if err := moveEntryNote(ctx, q, duplicateID, group.keep.ID); err != nil {
+				return removed, err
+			}
+		}
+
+		if err := deleteEntriesByID(ctx, q, group.duplicate); err != nil {
+			return removed, err
+		}
+		removed += len(group.duplicate)
+	}
+
+	return removed, nil
+}
+
+// RehashFeedEntries sets the hash of every entry of a feed to hash(entry), merging
+// entries whose new hashes collide first. Returns how many entries were merged away.
+func RehashFeedEntries(ctx context.Context, q Querier, feedID int64, hash func(DedupeEntry) string) (int, error) {
+	removed, err := MergeDuplicateEntries(ctx, q, feedID, hash)
+	if err != nil {
+		return 0, err
+	}
+
+	rows, err := q.QueryContext(ctx, `SELECT id, hash, url, title, content FROM entries WHERE feed_id = ?`, feedID)
+	if err != nil {
+		return removed, fmt.Errorf("query entries for rehash: %w", err)
+	}
+	changed := make(map[int64]string)
+	for rows.Next() {
+		var (
+			entry      DedupeEntry
+			current    string
+			urlStr     sql.NullString
+			titleStr   sql.NullString
+			contentStr sql.NullString
+		)
+		if err := rows.Scan(&entry.ID, &current, &urlStr, &titleStr, &contentStr); err != nil {
+			rows.Close()
+			return removed, fmt.Errorf("scan entry for rehash: %w", err)
+		}
+		entry.FeedID = feedID
+		entry.URL = strings.TrimSpace(urlStr.String)
+		entry.Title = strings.TrimSpace(titleStr.String)
+		entry.Content = strings.TrimSpace(contentStr.String)
+		if next := hash(entry); next != current {
+			changed[entry.ID] = next
+		}
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return removed, fmt.Errorf("iterate entries for rehash: %w", err)
+	}
+	rows.Close()
+
+	// Park changed rows on a unique placeholder first, so swapping hashes between
+	// two rows never trips the (feed_id, hash) index halfway through
+	for id := range changed {
+		if _, err := q.ExecContext(ctx, `UPDATE entries SET hash = ? WHERE id = ?`, fmt.Sprintf("rehash:%d", id), id); err != nil {
+			return removed, fmt.Errorf("park entry hash: %w", err)
+		}
+	}
+	for id, next := range changed {
+		if _, err := q.ExecContext(ctx, `UPDATE entries SET hash = ? WHERE id = ?`, next, id); err != nil {
+			return removed, fmt.Errorf("update entry hash: %w", err)
+		}
+	}
+	return removed, nil
+}
+
+// MergeFeedEntries moves every entry of fromID to toID. Entries toID already
+// has by hash stay, taking over read/starred state, AI caches and notes, and
+// the copies are deleted. Returns how many entries were merged that way.
+func MergeFeedEntries(ctx context.Context, q Querier, fromID int64, toID int64) (int, error) {
+	rows, err := q.QueryContext(ctx, `
+		SELECT d.id, k.id, d.read, d.starred
+		FROM entries d
+		JOIN entries k ON k.feed_id = ? AND k.hash = d.hash
+		WHERE d.feed_id = ?
+	`, toID, fromID)
+	if err != nil {
+		return 0, fmt.Errorf("query conflicting entries: %w", err)
+	}
+
+	type conflict struct {
+		duplicateID int64
+		keepID      int64
+		read        int
+		starred     int
+	}
+	var conflicts []conflict
+	for rows.Next() {
+		var c conflict
+		if err := rows.Scan(&c.duplicateID, &c.keepID, &c.read, &c.starred); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("scan conflicting entry: %w", err)
+		}
+		conflicts = append(conflicts, c)
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return 0, fmt.Errorf("iterate conflicting entries: %w", err)
+	}
+	rows.Close()
+
+	duplicateIDs := make([]int64, 0, len(conflicts))
+	for _, c := range conflicts {
+		if _, err := q.ExecContext(ctx,
+			`UPDATE entries SET read = MAX(read, ?), starred = MAX(starred, ?) WHERE id = ?`,
+			c.read,
+			c.starred,
+			c.keepID,
+		); err != nil {
+			return 0, fmt.Errorf("update merged entry state: %w", err)
+		}
+		if err := moveEntryCaches(ctx, q, c.duplicateID, c.keepID); err != nil {
+			return 0, err
+		}
+		if err := moveEntryNote(ctx, q, c.duplicateID, c.keepID); err != nil {
+			return 0, err
+		}
+		duplicateIDs = append(duplicateIDs, c.duplicateID)
+	}
+	if err := deleteEntriesByID(ctx, q, duplicateIDs); err != nil {
+		return 0, err
+	}
+
+	if _, err := q.ExecContext(ctx, `UPDATE entries SET feed_id = ? WHERE feed_id = ?`, toID, fromID); err != nil {
+		return 0, fmt.Errorf("move feed entries: %w", err)
+	}
+	return len(duplicateIDs), nil
+}
+
+func queryDedupeEntries(ctx context.Context, q Querier, feedID int64) ([]dedupeEntry, error) {
+	query := `SELECT id, feed_id, url, title, content, read, starred, updated_at FROM entries`
+	var args []interface{}
+	if feedID != 0 {
+		query += ` WHERE feed_id = ?`
+		args = append(args, feedID)
+	}
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query entries for merge: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []dedupeEntry
+	for rows.Next() {
+		var (
+			entry        dedupeEntry
+			urlStr       sql.NullString
+			titleStr     sql.NullString
+			contentStr   sql.NullString
+			updatedAtStr string
+		)
+		if err := rows.Scan(
+			&entry.ID,
+			&entry.FeedID,
+			&urlStr,
+			&titleStr,
+			&contentStr,
+			&entry.read,
+			&entry.starred,
+			&updatedAtStr,
+		); err != nil {
+			return nil, fmt.Errorf("scan entry for merge: %w", err)
+		}
+		entry.URL = strings.TrimSpace(urlStr.String)
+		entry.Title = strings.TrimSpace(titleStr.String)
+		entry.Content = strings.TrimSpace(contentStr.String)
+		entry.updatedAt, _ = time.Parse(time.RFC3339, updatedAtStr)
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate entries for merge: %w", err)
+	}
+	return entries, nil
+}
+
+func moveEntryCaches(ctx context.Context, q Querier, fromID int64, toID int64) error {
+	tables := []string{"ai_summaries", "ai_translations", "ai_list_translations"}
+	for _, table := range tables {
+		updateQuery := fmt.Sprintf(`UPDATE OR IGNORE %s SET entry_id = ? WHERE entry_id = ?`, table)
+		if _, err := q.ExecContext(ctx, updateQuery, toID, fromID); err != nil {
+			return fmt.Errorf("update %s entry_id: %w", table, err)
+		}
+
+		deleteQuery := fmt.Sprintf(`DELETE FROM %s WHERE entry_id = ?`, table)
+		if _, err := q.ExecContext(ctx, deleteQuery, fromID); err != nil {
+			return fmt.Errorf("delete %s duplicate rows: %w", table, err)
+		}
+	}
+	return nil
+}
+
+// moveEntryNote hands the note of fromID to toID, appending it after a blank line
+// when toID has a note of its own. Databases from before entry_notes existed have
+// no notes to move.
+func moveEntryNote(ctx context.Context, q Querier, fromID int64, toID int64) error {
+	rows, err := q.QueryContext(ctx, `SELECT 1 FROM sqlite_master WHERE type = 'table' AND name = 'entry_notes'`)
+	if err != nil {
+		return fmt.Errorf("check entry_notes table: %w", err)
+	}
+	exists := rows.Next()
+	rows.Close()
+	if !exists {
+		return nil
+	}
+
+	if _, err := q.ExecContext(ctx, `
+		INSERT INTO entry_notes (entry_id, note, updated_at)
+		SELECT ?, note, updated_at FROM entry_notes WHERE entry_id = ?
+		ON CONFLICT(entry_id) DO UPDATE SET
+			note = entry_notes.note || char(10) || char(10) || excluded.note,
+			updated_at = MAX(entry_notes.updated_at, excluded.updated_at)
+	`, toID, fromID); err != nil {
+		return fmt.Errorf("move entry note: %w", err)
+	}
+	if _, err := q.ExecContext(ctx, `DELETE FROM entry_notes WHERE entry_id = ?`, fromID); err != nil {
+		return fmt.Errorf("delete merged entry note: %w", err)
+	}
+	return nil
+}
+
+func deleteEntriesByID(ctx context.Context, q Querier, ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	// Keep each statement below SQLite variable limits.
+	const batchSize = 500
+	for start := 0; start < len(ids); start += batchSize {
+		end := start + batchSize
+		if end > len(ids) {
+			end = len(ids)
+		}
+
+		chunk := ids[start:end]
+		args := make([]interface{}, 0, len(chunk))
+		placeholders := make([]string, 0, len(chunk))
+		for _, id := range chunk {
+			args = append(args, id)
+			placeholders = append(placeholders, "?")
+		}
+
+		query := fmt.Sprintf(`DELETE FROM entries WHERE id IN (%s)`, strings.Join(placeholders, ","))
+		if _, err := q.ExecContext(ctx, query, args...); err != nil {
+			return fmt.Errorf("delete duplicate entries: %w", err)
+		}
+	}
+	return nil
+}
+
+func chooseAsKeep(candidate dedupeEntry, current dedupeEntry) bool {
+	if candidate.updatedAt.After(current.updatedAt) {
+		return true
+	}
+	if candidate.updatedAt.Equal(current.updatedAt) && candidate.ID > current.ID {
+		return true
+	}
+	return false
+}
@@ -0,0 +1,108 @@
+package db_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"gist/backend/internal/db"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRehashFeedEntries_MergesAndSwapsHashes(t *testing.T) {
+	database, err := db.Open(filepath.Join(t.TempDir(), "gist.db"))
+	require.NoError(t, err)
+	defer database.Close()
+
+	_, err = database.Exec(`
+		INSERT INTO feeds (id, title, url, canonical_url, created_at, updated_at) VALUES
+		(1, 'feed', 'https://example.com/rss', 'https://example.com/rss', '2025-01-01T00:00:00Z', '2025-01-01T00:00:00Z'),
+		(2, 'other', 'https://other.example.com/rss', 'https://other.example.com/rss', '2025-01-01T00:00:00Z', '2025-01-01T00:00:00Z');
+		INSERT INTO entries (id, feed_id, hash, title, url, read, starred, created_at, updated_at) VALUES
+		(101, 1, 'h1', 'a', 'https://t.example/r/1', 1, 0, '2025-01-01T00:00:00Z', '2025-01-01T00:00:00Z'),
+		(102, 1, 'h2', 'a', 'https://t.example/r/2', 0, 1, '2025-02-01T00:00:00Z', '2025-02-01T00:00:00Z'),
+		(103, 1, 'k:c', 'b', 'https://t.example/r/3', 0, 0, '2025-01-01T00:00:00Z', '2025-01-01T00:00:00Z'),
+		(104, 1, 'k:b', 'c', 'https://t.example/r/4', 0, 0, '2025-01-01T00:00:00Z', '2025-01-01T00:00:00Z'),
+		(201, 2, 'h1', 'a', 'https://t.example/r/1', 0, 0, '2025-01-01T00:00:00Z', '2025-01-01T00:00:00Z');
+		INSERT INTO ai_summaries (id, entry_id, is_readability, language, summary, created_at) VALUES
+		(301, 101, 0, 'zh-CN', 'summary', '2025-01-01T00:00:00Z');
+	`)
+	require.NoError(t, err)
+
+	merged, err := db.RehashFeedEntries(context.Background(), database, 1, func(entry db.DedupeEntry) string {
+		return "k:" + entry.Title
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, merged)
+
+	hashes := make(map[int64]string)
+	rows, err := database.Query(`SELECT id, hash FROM entries`)
+	require.NoError(t, err)
+	for rows.Next() {
+		var id int64
+		var hash string
+		require.NoError(t, rows.Scan(&id, &hash))
+		hashes[id] = hash
+	}
+	require.NoError(t, rows.Err())
+	rows.Close()
+	require.Equal(t, map[int64]string{102: "k:a", 103: "k:b", 104: "k:c", 201: "h1"}, hashes)
+
+	var read, starred int
+	require.NoError(t, database.QueryRow(`SELECT read, starred FROM entries WHERE id = 102`).Scan(&read, &starred))
+	require.Equal(t, 1, read)
+	require.Equal(t, 1, starred)
+
+	var summaryEntryID int64
+	require.NoError(t, database.QueryRow(`SELECT entry_id FROM ai_summaries WHERE id = 301`).Scan(&summaryEntryID))
+	require.Equal(t, int64(102), summaryEntryID)
+}
+
+func TestMergeDuplicateEntries_MovesNotes(t *testing.T) {
+	database, err := db.Open(filepath.Join(t.TempDir(), "gist.db"))
+	require.NoError(t, err)
+	defer database.Close()
+
+	_, err = database.Exec(`
+		INSERT INTO feeds (id, title, url, canonical_url, created_at, updated_at) VALUES
+		(1, 'feed', 'https://example.com/rss', 'https://example.com/rss', '2025-01-01T00:00:00Z', '2025-01-01T00:00:00Z');
+		INSERT INTO entries (id, feed_id, hash, title, created_at, updated_at) VALUES
+		(101, 1, 'h1', 'a', '2025-01-01T00:00:00Z', '2025-01-01T00:00:00Z'),
+		(102, 1, 'h2', 'a', '2025-01-01T00:00:00Z', '2025-02-01T00:00:00Z'),
+		(103, 1, 'h3', 'b', '2025-01-01T00:00:00Z', '2025-01-01T00:00:00Z'),
+		(104, 1, 'h4', 'b', '2025-01-01T00:00:00Z', '2025-02-01T00:00:00Z'),
+		(105, 1, 'h5', 'c', '2025-01-01T00:00:00Z', '2025-01-01T00:00:00Z');
+		INSERT INTO entry_notes (entry_id, note, updated_at) VALUES
+		(101, 'only on the duplicate', '2025-01-01T00:00:00Z'),
+		(103, 'from the duplicate', '2025-01-01T00:00:00Z'),
+		(104, 'kept', '2025-01-02T00:00:00Z'),
+		(105, 'deleted with its entry', '2025-01-01T00:00:00Z');
+	`)
+	require.NoError(t, err)
+
+	merged, err := db.MergeDuplicateEntries(context.Background(), database, 1, func(entry db.DedupeEntry) string {
+		return entry.Title
+	})
+	require.NoError(t, err)
+	require.Equal(t, 2, merged)
+
+	_, err = database.Exec(`DELETE FROM entries WHERE id = 105`)
+	require.NoError(t, err)
+
+	notes := make(map[int64]string)
+	rows, err := database.Query(`SELECT entry_id, note FROM entry_notes`)
+	require.NoError(t, err)
+	for rows.Next() {
+		var id int64
+		var note string
+		require.NoError(t, rows.Scan(&id, &note))
+		notes[id] = note
+	}
+	require.NoError(t, rows.Err())
+	rows.Close()
+	require.Equal(t, map[int64]string{
+		102: "only on the duplicate",
+		104: "kept\n\nfrom the duplicate",
+	}, notes)
+}
@@ -1,13 +1,17 @@
 package db
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"gist/backend/internal/hashutil"
 	"gist/backend/internal/urlutil"
+	"gist/backend/pkg/readtime"
 )
 
 // Base schema - uses Snowflake IDs (no AUTOINCREMENT)
@@ -74,492 +78,1362 @@ CREATE TRIGGER IF NOT EXISTS entries_ad AFTER DELETE ON entries BEGIN
 END;
 `
 
-func Migrate(db *sql.DB) error {
-	// Run base schema first (without read column)
-	if _, err := db.Exec(baseSchema); err != nil {
-		return fmt.Errorf("migrate base schema: %w", err)
+// Migration states reported by MigrationState.
+const (
+	MigrationPending  = "pending"
+	MigrationRunning  = "running"
+	MigrationComplete = "complete"
+	MigrationFailed   = "failed"
+)
+
+var migrationState atomic.Value
+
+// MigrationState returns the state of the most recent Migrate call in this process.
+func MigrationState() string {
+	if state, ok := migrationState.Load().(string); ok {
+		return state
 	}
+	return MigrationPending
+}
 
-	// Run incremental migrations
-	if err := runMigrations(db); err != nil {
+const schemaMigrationsTable = `
+CREATE TABLE IF NOT EXISTS schema_migrations (
+  version INTEGER PRIMARY KEY,
+  name TEXT NOT NULL,
+  applied_at TEXT NOT NULL
+)`
+
+func Migrate(db *sql.DB) error {
+	migrationState.Store(MigrationRunning)
+
+	if err := runMigrations(context.Background(), db); err != nil {
+		migrationState.Store(MigrationFailed)
 		return fmt.Errorf("run migrations: %w", err)
 	}
 
+	migrationState.Store(MigrationComplete)
 	return nil
 }
 
-func runMigrations(db *sql.DB) error {
-	// Migration 1: Add read column to entries if not exists
-	var count int
-	err := db.QueryRow(`
-		SELECT COUNT(*) FROM pragma_table_info('entries') WHERE name = 'read'
-	`).Scan(&count)
+// runMigrations applies the migrations not yet recorded in schema_migrations.
+// The whole run holds the write lock (BEGIN IMMEDIATE) on one connection, so a
+// second process pointed at the same file waits on busy_timeout and then finds
+// the work done. Each migration runs in its own savepoint together with its
+// record; a failing one is rolled back on its own and the ones before it kept.
+func runMigrations(ctx context.Context, db *sql.DB) error {
+	conn, err := db.Conn(ctx)
 	if err != nil {
-		return fmt.Errorf("check read column: %w", err)
+		return fmt.Errorf("acquire connection: %w", err)
 	}
+	defer conn.Close()
 
-	if count == 0 {
-		if _, err := db.Exec(`ALTER TABLE entries ADD COLUMN read INTEGER NOT NULL DEFAULT 0`); err != nil {
-			return fmt.Errorf("add read column: %w", err)
-		}
+	if _, err := conn.ExecContext(ctx, `BEGIN IMMEDIATE`); err != nil {
+		return fmt.Errorf("lock database: %w", err)
 	}
+	committed := false
+	defer func() {
+		if !committed {
+			_, _ = conn.ExecContext(ctx, `ROLLBACK`)
+		}
+	}()
 
-	// Create indexes (safe to run even if they exist)
-	if _, err := db.Exec(`CREATE INDEX IF NOT EXISTS idx_entries_read ON entries(read)`); err != nil {
-		return fmt.Errorf("create idx_entries_read: %w", err)
-	}
-	if _, err := db.Exec(`CREATE INDEX IF NOT EXISTS idx_entries_feed_read ON entries(feed_id, read)`); err != nil {
-		return fmt.Errorf("create idx_entries_feed_read: %w", err)
+	// Run base schema first (without read column)
+	if _, err := conn.ExecContext(ctx, baseSchema); err != nil {
+		return fmt.Errorf("migrate base schema: %w", err)
 	}
-
-	// Migration 3: Drop the UPDATE trigger (causes issues with FTS5 on read status changes)
-	// RSS entries rarely change content after insertion, so we only need INSERT/DELETE triggers
-	if _, err := db.Exec(`DROP TRIGGER IF EXISTS entries_au`); err != nil {
-		return fmt.Errorf("drop entries_au trigger: %w", err)
+	if _, err := conn.ExecContext(ctx, schemaMigrationsTable); err != nil {
+		return fmt.Errorf("create schema_migrations table: %w", err)
 	}
 
-	// Migration 4: Add readable_content column to entries for readability-extracted content
-	err = db.QueryRow(`
-		SELECT COUNT(*) FROM pragma_table_info('entries') WHERE name = 'readable_content'
-	`).Scan(&count)
+	recorded, err := recordedMigrations(ctx, conn)
 	if err != nil {
-		return fmt.Errorf("check readable_content column: %w", err)
+		return err
 	}
 
-	if count == 0 {
-		if _, err := db.Exec(`ALTER TABLE entries ADD COLUMN readable_content TEXT`); err != nil {
-			return fmt.Errorf("add readable_content column: %w", err)
+	var runErr error
+	for _, m := range migrations {
+		if recorded[m.version] {
+			continue
+		}
+		if err := applyMigration(ctx, conn, m); err != nil {
+			runErr = fmt.Errorf("migration %d (%s): %w", m.version, m.name, err)
+			break
 		}
 	}
 
-	// Migration 5: Add icon_path column to feeds for cached icon file path
-	err = db.QueryRow(`
-		SELECT COUNT(*) FROM pragma_table_info('feeds') WHERE name = 'icon_path'
-	`).Scan(&count)
-	if err != nil {
-		return fmt.Errorf("check icon_path column: %w", err)
-	}
-
-	if count == 0 {
-		if _, err := db.Exec(`ALTER TABLE feeds ADD COLUMN icon_path TEXT`); err != nil {
-			return fmt.Errorf("add icon_path column: %w", err)
-		}
+	if _, err := conn.ExecContext(ctx, `COMMIT`); err != nil {
+		return errors.Join(runErr, fmt.Errorf("commit migrations: %w", err))
 	}
+	committed = true
+	return runErr
+}
 
-	// Migration 6: Add thumbnail_url column to entries for article cover image
-	err = db.QueryRow(`
-		SELECT COUNT(*) FROM pragma_table_info('entries') WHERE name = 'thumbnail_url'
-	`).Scan(&count)
+func recordedMigrations(ctx context.Context, conn *sql.Conn) (map[int]bool, error) {
+	rows, err := conn.QueryContext(ctx, `SELECT version FROM schema_migrations`)
 	if err != nil {
-		return fmt.Errorf("check thumbnail_url column: %w", err)
+		return nil, fmt.Errorf("query schema_migrations: %w", err)
 	}
+	defer rows.Close()
 
-	if count == 0 {
-		if _, err := db.Exec(`ALTER TABLE entries ADD COLUMN thumbnail_url TEXT`); err != nil {
-			return fmt.Errorf("add thumbnail_url column: %w", err)
+	recorded := make(map[int]bool)
+	for rows.Next() {
+		var version int
+		if err := rows.Scan(&version); err != nil {
+			return nil, fmt.Errorf("scan schema_migrations: %w", err)
 		}
+		recorded[version] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate schema_migrations: %w", err)
 	}
+	return recorded, nil
+}
 
-	// Migration 7: Add starred column to entries for bookmarking
-	err = db.QueryRow(`
-		SELECT COUNT(*) FROM pragma_table_info('entries') WHERE name = 'starred'
-	`).Scan(&count)
-	if err != nil {
-		return fmt.Errorf("check starred column: %w", err)
+// applyMigration runs m unless its change is already in the schema, which is
+// the case for installs from before schema_migrations, and records it.
+func applyMigration(ctx context.Context, conn *sql.Conn, m migration) error {
+	if _, err := conn.ExecContext(ctx, `SAVEPOINT migration`); err != nil {
+		return fmt.Errorf("begin savepoint: %w", err)
 	}
 
-	if count == 0 {
-		if _, err := db.Exec(`ALTER TABLE entries ADD COLUMN starred INTEGER NOT NULL DEFAULT 0`); err != nil {
-			return fmt.Errorf("add starred column: %w", err)
+	err := func() error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return fmt.Errorf("check schema: %w", err)
 		}
+		if !applied {
+			if err := m.up(ctx, conn); err != nil {
+				return err
+			}
+		}
+		if _, err := conn.ExecContext(ctx,
+			`INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?)`,
+			m.version,
+			m.name,
+			time.Now().UTC().Format(time.RFC3339),
+		); err != nil {
+			return fmt.Errorf("record migration: %w", err)
+		}
+		return nil
+	}()
+	if err != nil {
+		_, _ = conn.ExecContext(ctx, `ROLLBACK TO migration`)
+		_, _ = conn.ExecContext(ctx, `RELEASE migration`)
+		return err
 	}
 
-	if _, err := db.Exec(`CREATE INDEX IF NOT EXISTS idx_entries_starred ON entries(starred)`); err != nil {
-		return fmt.Errorf("create idx_entries_starred: %w", err)
+	if _, err := conn.ExecContext(ctx, `RELEASE migration`); err != nil {
+		return fmt.Errorf("release savepoint: %w", err)
 	}
+	return nil
+}
 
-	// Migration 8: Add error_message column to feeds for tracking fetch/refresh errors
-	err = db.QueryRow(`
-		SELECT COUNT(*) FROM pragma_table_info('feeds') WHERE name = 'error_message'
-	`).Scan(&count)
-	if err != nil {
-		return fmt.Errorf("check error_message column: %w", err)
+// migration is one numbered schema change. Versions are never reused or
+// reordered; new migrations go at the end of the list.
+type migration struct {
+	version int
+	name    string
+	// applied reports whether the change is already in the schema.
+	applied schemaCheck
+	up      migrationFunc
+}
+
+type schemaCheck func(ctx context.Context, conn *sql.Conn) (bool, error)
+
+type migrationFunc func(ctx context.Context, conn *sql.Conn) error
+
+var migrations = []migration{
+	{
+		version: 1,
+		name:    "add entries.read",
+		applied: hasColumns("entries", "read"),
+		up:      addColumn("entries", "read", "INTEGER NOT NULL DEFAULT 0"),
+	},
+	{
+		version: 2,
+		name:    "index entries by read state",
+		applied: hasObjects("index", "idx_entries_read", "idx_entries_feed_read"),
+		up: execStatements(
+			`CREATE INDEX IF NOT EXISTS idx_entries_read ON entries(read)`,
+			`CREATE INDEX IF NOT EXISTS idx_entries_feed_read ON entries(feed_id, read)`,
+		),
+	},
+	{
+		// The UPDATE trigger causes issues with FTS5 on read status changes. RSS
+		// entries rarely change content after insertion, so INSERT/DELETE suffice
+		version: 3,
+		name:    "drop entries_au trigger",
+		applied: lacksObject("trigger", "entries_au"),
+		up:      execStatements(`DROP TRIGGER IF EXISTS entries_au`),
+	},
+	{
+		version: 4,
+		name:    "add entries.readable_content",
+		applied: hasColumns("entries", "readable_content"),
+		up:      addColumn("entries", "readable_content", "TEXT"),
+	},
+	{
+		version: 5,
+		name:    "add feeds.icon_path",
+		applied: hasColumns("feeds", "icon_path"),
+		up:      addColumn("feeds", "icon_path", "TEXT"),
+	},
+	{
+		version: 6,
+		name:    "add entries.thumbnail_url",
+		applied: hasColumns("entries", "thumbnail_url"),
+		up:      addColumn("entries", "thumbnail_url", "TEXT"),
+	},
+	{
+		version: 7,
+		name:    "add entries.starred",
+		applied: allOf(hasColumns("entries", "starred"), hasObjects("index", "idx_entries_starred")),
+		up: steps(
+			addColumn("entries", "starred", "INTEGER NOT NULL DEFAULT 0"),
+			execStatements(`CREATE INDEX IF NOT EXISTS idx_entries_starred ON entries(starred)`),
+		),
+	},
+	{
+		version: 8,
+		name:    "add feeds.error_message",
+		applied: hasColumns("feeds", "error_message"),
+		up:      addColumn("feeds", "error_message", "TEXT"),
+	},
+	{
+		version: 9,
+		name:    "create settings",
+		applied: hasObjects("table", "settings"),
+		up: execStatements(`
+			CREATE TABLE IF NOT EXISTS settings (
+				key TEXT PRIMARY KEY,
+				value TEXT NOT NULL,
+				updated_at TEXT NOT NULL
+			)
+		`),
+	},
+	{
+		version: 10,
+		name:    "create ai_summaries",
+		applied: hasObjects("index", "idx_ai_summaries_entry_mode"),
+		up: execStatements(`
+			CREATE TABLE IF NOT EXISTS ai_summaries (
+				id INTEGER PRIMARY KEY,
+				entry_id INTEGER NOT NULL,
+				is_readability INTEGER NOT NULL DEFAULT 0,
+				language TEXT NOT NULL,
+				summary TEXT NOT NULL,
+				created_at TEXT NOT NULL,
+				FOREIGN KEY (entry_id) REFERENCES entries(id) ON DELETE CASCADE
+			)
+		`,
+			`CREATE UNIQUE INDEX IF NOT EXISTS idx_ai_summaries_entry_mode ON ai_summaries(entry_id, is_readability, language)`,
+		),
+	},
+	{
+		version: 11,
+		name:    "create ai_translations",
+		applied: hasObjects("index", "idx_ai_translations_entry_mode"),
+		up: execStatements(`
+			CREATE TABLE IF NOT EXISTS ai_translations (
+				id INTEGER PRIMARY KEY,
+				entry_id INTEGER NOT NULL,
+				is_readability INTEGER NOT NULL DEFAULT 0,
+				language TEXT NOT NULL,
+				content TEXT NOT NULL,
+				created_at TEXT NOT NULL,
+				FOREIGN KEY (entry_id) REFERENCES entries(id) ON DELETE CASCADE
+			)
+		`,
+			`CREATE UNIQUE INDEX IF NOT EXISTS idx_ai_translations_entry_mode ON ai_translations(entry_id, is_readability, language)`,
+		),
+	},
+	{
+		// Title/summary translation cache for the entry list
+		version: 12,
+		name:    "create ai_list_translations",
+		applied: hasObjects("index", "idx_ai_list_translations_entry_lang"),
+		up: execStatements(`
+			CREATE TABLE IF NOT EXISTS ai_list_translations (
+				id INTEGER PRIMARY KEY,
+				entry_id INTEGER NOT NULL,
+				language TEXT NOT NULL,
+				title TEXT NOT NULL,
+				summary TEXT NOT NULL,
+				created_at TEXT NOT NULL,
+				FOREIGN KEY (entry_id) REFERENCES entries(id) ON DELETE CASCADE
+			)
+		`,
+			`CREATE UNIQUE INDEX IF NOT EXISTS idx_ai_list_translations_entry_lang ON ai_list_translations(entry_id, language)`,
+		),
+	},
+	{
+		version: 13,
+		name:    "add feeds.type",
+		applied: hasColumns("feeds", "type"),
+		up:      addColumn("feeds", "type", "TEXT NOT NULL DEFAULT 'article'"),
+	},
+	{
+		version: 14,
+		name:    "add folders.type",
+		applied: hasColumns("folders", "type"),
+		up:      addColumn("folders", "type", "TEXT NOT NULL DEFAULT 'article'"),
+	},
+	{
+		// modernc.org/sqlite doesn't support the FTS5 special insert syntax the
+		// trigger first used, so it is recreated with a direct DELETE
+		version: 15,
+		name:    "fix entries_ad trigger",
+		applied: entriesDeleteTriggerFixed,
+		up: execStatements(
+			`DROP TRIGGER IF EXISTS entries_ad`,
+			`CREATE TRIGGER IF NOT EXISTS entries_ad AFTER DELETE ON entries BEGIN
+				DELETE FROM entries_fts WHERE rowid = old.id;
+			END`,
+		),
+	},
+	{
+		version: 16,
+		name:    "create domain_rate_limits",
+		applied: hasObjects("index", "idx_domain_rate_limits_host"),
+		up: execStatements(`
+			CREATE TABLE IF NOT EXISTS domain_rate_limits (
+				id INTEGER PRIMARY KEY,
+				host TEXT NOT NULL UNIQUE,
+				interval_seconds INTEGER NOT NULL,
+				created_at TEXT NOT NULL,
+				updated_at TEXT NOT NULL
+			)
+		`,
+			`CREATE INDEX IF NOT EXISTS idx_domain_rate_limits_host ON domain_rate_limits(host)`,
+		),
+	},
+	{
+		version: 17,
+		name:    "deduplicate entries by hash",
+		applied: hasObjects("index", "idx_entries_feed_hash"),
+		up:      migrateEntryHashDeduplication,
+	},
+	{
+		// Per-feed summarize prompt customization
+		version: 18,
+		name:    "add feeds.summary_prompt_reminder",
+		applied: hasColumns("feeds", "summary_prompt_reminder"),
+		up:      addColumn("feeds", "summary_prompt_reminder", "TEXT"),
+	},
+	{
+		version: 19,
+		name:    "create api_tokens",
+		applied: hasObjects("table", "api_tokens"),
+		up: execStatements(`
+			CREATE TABLE IF NOT EXISTS api_tokens (
+				id INTEGER PRIMARY KEY,
+				name TEXT NOT NULL,
+				token_hash TEXT NOT NULL UNIQUE,
+				created_at TEXT NOT NULL,
+				last_used_at TEXT,
+				expires_at TEXT
+			)
+		`),
+	},
+	{
+		version: 20,
+		name:    "create login_events",
+		applied: hasObjects("index", "idx_login_events_created_at"),
+		up: execStatements(`
+			CREATE TABLE IF NOT EXISTS login_events (
+				id INTEGER PRIMARY KEY,
+				identifier TEXT NOT NULL,
+				ip TEXT NOT NULL,
+				user_agent TEXT NOT NULL,
+				success INTEGER NOT NULL,
+				created_at TEXT NOT NULL
+			)
+		`,
+			`CREATE INDEX IF NOT EXISTS idx_login_events_created_at ON login_events(created_at)`,
+		),
+	},
+	{
+		version: 21,
+		name:    "create entry_revisions",
+		applied: hasObjects("index", "idx_entry_revisions_entry_id"),
+		up: execStatements(`
+			CREATE TABLE IF NOT EXISTS entry_revisions (
+				id INTEGER PRIMARY KEY,
+				entry_id INTEGER NOT NULL,
+				title TEXT,
+				content TEXT,
+				created_at TEXT NOT NULL,
+				FOREIGN KEY (entry_id) REFERENCES entries(id) ON DELETE CASCADE
+			)
+		`,
+			`CREATE INDEX IF NOT EXISTS idx_entry_revisions_entry_id ON entry_revisions(entry_id, created_at)`,
+		),
+	},
+	{
+		// Also merges feeds that differ only by tracking params
+		version: 22,
+		name:    "add feeds.canonical_url",
+		applied: hasObjects("index", "idx_feeds_canonical_url"),
+		up:      migrateFeedCanonicalURL,
+	},
+	{
+		version: 23,
+		name:    "create refresh_runs",
+		applied: hasObjects("index", "idx_refresh_run_feeds_run_id"),
+		up: execStatements(`
+			CREATE TABLE IF NOT EXISTS refresh_runs (
+				id INTEGER PRIMARY KEY,
+				trigger TEXT NOT NULL,
+				started_at TEXT NOT NULL,
+				finished_at TEXT NOT NULL,
+				feeds_total INTEGER NOT NULL DEFAULT 0,
+				feeds_failed INTEGER NOT NULL DEFAULT 0,
+				entries_new INTEGER NOT NULL DEFAULT 0,
+				entries_updated INTEGER NOT NULL DEFAULT 0
+			)
+		`, `
+			CREATE TABLE IF NOT EXISTS refresh_run_feeds (
+				id INTEGER PRIMARY KEY,
+				run_id INTEGER NOT NULL,
+				feed_id INTEGER NOT NULL,
+				feed_title TEXT NOT NULL,
+				entries_new INTEGER NOT NULL DEFAULT 0,
+				entries_updated INTEGER NOT NULL DEFAULT 0,
+				error_message TEXT,
+				FOREIGN KEY (run_id) REFERENCES refresh_runs(id) ON DELETE CASCADE
+			)
+		`,
+			`CREATE INDEX IF NOT EXISTS idx_refresh_run_feeds_run_id ON refresh_run_feeds(run_id)`,
+		),
+	},
+	{
+		version: 24,
+		name:    "add entries.word_count",
+		applied: entryWordCountsFilled,
+		up:      migrateEntryWordCount,
+	},
+	{
+		// Soft delete
+		version: 25,
+		name:    "add feeds.deleted_at and folders.deleted_at",
+		applied: allOf(
+			hasColumns("feeds", "deleted_at"),
+			hasColumns("folders", "deleted_at"),
+			hasObjects("index", "idx_feeds_deleted_at", "idx_folders_deleted_at"),
+		),
+		up: steps(
+			addColumn("feeds", "deleted_at", "TEXT"),
+			addColumn("folders", "deleted_at", "TEXT"),
+			execStatements(
+				`CREATE INDEX IF NOT EXISTS idx_feeds_deleted_at ON feeds(deleted_at)`,
+				`CREATE INDEX IF NOT EXISTS idx_folders_deleted_at ON folders(deleted_at)`,
+			),
+		),
+	},
+	{
+		// List order, for adjacent-entry lookups
+		version: 26,
+		name:    "index entries by publish time",
+		applied: hasObjects("index", "idx_entries_published_id"),
+		up:      execStatements(`CREATE INDEX IF NOT EXISTS idx_entries_published_id ON entries(published_at, id)`),
+	},
+	{
+		// For pubDates without a zone
+		version: 27,
+		name:    "add feeds.assume_timezone",
+		applied: hasColumns("feeds", "assume_timezone"),
+		up:      addColumn("feeds", "assume_timezone", "TEXT"),
+	},
+	{
+		// Muting without unsubscribing
+		version: 28,
+		name:    "add feeds.paused_until",
+		applied: hasColumns("feeds", "paused_until"),
+		up:      addColumn("feeds", "paused_until", "TEXT"),
+	},
+	{
+		// Per-feed entry hash strategy
+		version: 29,
+		name:    "add feeds.dedupe_key",
+		applied: hasColumns("feeds", "dedupe_key"),
+		up:      addColumn("feeds", "dedupe_key", "TEXT NOT NULL DEFAULT 'auto'"),
+	},
+	{
+		// Manual ordering
+		version: 30,
+		name:    "add folders.sort_order and feeds.sort_order",
+		applied: allOf(hasColumns("folders", "sort_order"), hasColumns("feeds", "sort_order")),
+		up: steps(
+			addColumn("folders", "sort_order", "INTEGER NOT NULL DEFAULT 0"),
+			addColumn("feeds", "sort_order", "INTEGER NOT NULL DEFAULT 0"),
+		),
+	},
+	{
+		// Per-host concurrency overrides
+		version: 31,
+		name:    "add domain_rate_limits.max_concurrent",
+		applied: hasColumns("domain_rate_limits", "max_concurrent"),
+		up:      addColumn("domain_rate_limits", "max_concurrent", "INTEGER NOT NULL DEFAULT 0"),
+	},
+	{
+		// AI token usage accounting
+		version: 32,
+		name:    "create ai_usage",
+		applied: hasObjects("index", "idx_ai_usage_created_at"),
+		up: execStatements(`
+			CREATE TABLE IF NOT EXISTS ai_usage (
+				id INTEGER PRIMARY KEY,
+				operation TEXT NOT NULL,
+				provider TEXT NOT NULL,
+				model TEXT NOT NULL,
+				prompt_tokens INTEGER NOT NULL DEFAULT 0,
+				completion_tokens INTEGER NOT NULL DEFAULT 0,
+				estimated INTEGER NOT NULL DEFAULT 0,
+				entry_id INTEGER,
+				created_at TEXT NOT NULL,
+				FOREIGN KEY (entry_id) REFERENCES entries(id) ON DELETE SET NULL
+			)
+		`,
+			`CREATE INDEX IF NOT EXISTS idx_ai_usage_created_at ON ai_usage(created_at)`,
+		),
+	},
+	{
+		// Remembers which UA the feed accepts
+		version: 33,
+		name:    "add feeds.preferred_user_agent",
+		applied: hasColumns("feeds", "preferred_user_agent"),
+		up:      addColumn("feeds", "preferred_user_agent", "TEXT NOT NULL DEFAULT 'default'"),
+	},
+	{
+		// Sidebar feed title translation cache
+		version: 34,
+		name:    "create feed_title_translations",
+		applied: hasObjects("index", "idx_feed_title_translations_feed_lang"),
+		up: execStatements(`
+			CREATE TABLE IF NOT EXISTS feed_title_translations (
+				id INTEGER PRIMARY KEY,
+				feed_id INTEGER NOT NULL,
+				language TEXT NOT NULL,
+				source_hash TEXT NOT NULL,
+				title TEXT NOT NULL,
+				created_at TEXT NOT NULL,
+				FOREIGN KEY (feed_id) REFERENCES feeds(id) ON DELETE CASCADE
+			)
+		`,
+			`CREATE UNIQUE INDEX IF NOT EXISTS idx_feed_title_translations_feed_lang ON feed_title_translations(feed_id, language)`,
+		),
+	},
+	{
+		// Merges same-named sibling folders first
+		version: 35,
+		name:    "make folder names unique per parent",
+		applied: hasObjects("index", "idx_folders_parent_name"),
+		up:      migrateFolderUniqueName,
+	},
+	{
+		// Personal notes on entries
+		version: 36,
+		name:    "create entry_notes",
+		applied: hasObjects("table", "entry_notes"),
+		up: execStatements(`
+			CREATE TABLE IF NOT EXISTS entry_notes (
+				entry_id INTEGER PRIMARY KEY,
+				note TEXT NOT NULL,
+				updated_at TEXT NOT NULL,
+				FOREIGN KEY (entry_id) REFERENCES entries(id) ON DELETE CASCADE
+			)
+		`),
+	},
+	{
+		// Assigning new feeds to folders
+		version: 37,
+		name:    "create folder_rules",
+		applied: hasObjects("index", "idx_folder_rules_folder_id"),
+		up: execStatements(`
+			CREATE TABLE IF NOT EXISTS folder_rules (
+				id INTEGER PRIMARY KEY,
+				folder_id INTEGER NOT NULL,
+				pattern TEXT NOT NULL,
+				match_field TEXT NOT NULL,
+				priority INTEGER NOT NULL DEFAULT 0,
+				created_at TEXT NOT NULL,
+				updated_at TEXT NOT NULL,
+				FOREIGN KEY (folder_id) REFERENCES folders(id) ON DELETE CASCADE
+			)
+		`,
+			`CREATE INDEX IF NOT EXISTS idx_folder_rules_folder_id ON folder_rules(folder_id)`,
+		),
+	},
+	{
+		// Poll hints and the last fetch time, for polite scheduling
+		version: 38,
+		name:    "add feeds poll hints",
+		applied: hasColumns("feeds", "min_poll_seconds", "min_poll_source", "last_fetched_at"),
+		up: steps(
+			addColumn("feeds", "min_poll_seconds", "INTEGER NOT NULL DEFAULT 0"),
+			addColumn("feeds", "min_poll_source", "TEXT NOT NULL DEFAULT ''"),
+			addColumn("feeds", "last_fetched_at", "TEXT"),
+		),
+	},
+	{
+		// Tracks full-content archival of starred entries
+		version: 39,
+		name:    "create entry_archives",
+		applied: hasObjects("table", "entry_archives"),
+		up: execStatements(`
+			CREATE TABLE IF NOT EXISTS entry_archives (
+				entry_id INTEGER PRIMARY KEY,
+				attempts INTEGER NOT NULL DEFAULT 0,
+				last_error TEXT,
+				failed_at TEXT,
+				archived_at TEXT,
+				FOREIGN KEY (entry_id) REFERENCES entries(id) ON DELETE CASCADE
+			)
+		`),
+	},
+	{
+		// Reading statistics. Entries read before this keep NULL, as when they
+		// were read is unknown
+		version: 40,
+		name:    "add entries.read_at",
+		applied: allOf(hasColumns("entries", "read_at"), hasObjects("index", "idx_entries_read_at")),
+		up: steps(
+			addColumn("entries", "read_at", "TEXT"),
+			execStatements(`CREATE INDEX IF NOT EXISTS idx_entries_read_at ON entries(read_at)`),
+		),
+	},
+	{
+		// Pushing entries into static feeds
+		version: 41,
+		name:    "add feeds.ingest_token_hash",
+		applied: hasColumns("feeds", "ingest_token_hash"),
+		up:      addColumn("feeds", "ingest_token_hash", "TEXT"),
+	},
+	{
+		// Lets refreshes skip rewriting unchanged entries. Existing rows keep
+		// NULL until their next change
+		version: 42,
+		name:    "add entries.content_hash",
+		applied: hasColumns("entries", "content_hash"),
+		up:      addColumn("entries", "content_hash", "TEXT"),
+	},
+	{
+		// 0 = unlimited; refresh history counts the entries evicted to honor it
+		version: 43,
+		name:    "add feeds.max_entries",
+		applied: allOf(
+			hasColumns("feeds", "max_entries"),
+			hasColumns("refresh_runs", "entries_evicted"),
+			hasColumns("refresh_run_feeds", "entries_evicted"),
+		),
+		up: steps(
+			addColumn("feeds", "max_entries", "INTEGER NOT NULL DEFAULT 0"),
+			addColumn("refresh_runs", "entries_evicted", "INTEGER NOT NULL DEFAULT 0"),
+			addColumn("refresh_run_feeds", "entries_evicted", "INTEGER NOT NULL DEFAULT 0"),
+		),
+	},
+	{
+		// normalized_author is LOWER(TRIM(author)) so entries can be matched in SQL
+		version: 44,
+		name:    "create muted_authors",
+		applied: hasObjects("table", "muted_authors"),
+		up: execStatements(`
+			CREATE TABLE IF NOT EXISTS muted_authors (
+				feed_id INTEGER NOT NULL,
+				author TEXT NOT NULL,
+				normalized_author TEXT NOT NULL,
+				created_at TEXT NOT NULL,
+				PRIMARY KEY (feed_id, normalized_author),
+				FOREIGN KEY (feed_id) REFERENCES feeds(id) ON DELETE CASCADE
+			)
+		`),
+	},
+	{
+		// Matches legacy rows hashed by URL. Unlike the unique
+		// idx_entries_feed_url dropped by migration 17, it allows repeated URLs
+		version: 45,
+		name:    "index entries by feed and url",
+		applied: hasObjects("index", "idx_entries_feed_id_url"),
+		up:      execStatements(`CREATE INDEX IF NOT EXISTS idx_entries_feed_id_url ON entries(feed_id, url)`),
+	},
+	{
+		// A rename survives upstream title changes, which keep going to title
+		version: 46,
+		name:    "add feeds.custom_title",
+		applied: hasColumns("feeds", "custom_title"),
+		up:      migrateFeedCustomTitle,
+	},
+	{
+		// inherit follows the global translate-on-refresh setting
+		version: 47,
+		name:    "add feeds.auto_translate",
+		applied: hasColumns("feeds", "auto_translate"),
+		up:      addColumn("feeds", "auto_translate", "TEXT NOT NULL DEFAULT 'inherit'"),
+	},
+	{
+		// NULL entries.language means not detected yet; refreshes fill it in
+		version: 48,
+		name:    "add feeds.language and entries.language",
+		applied: allOf(
+			hasColumns("feeds", "language"),
+			hasColumns("entries", "language"),
+		),
+		up: steps(
+			addColumn("feeds", "language", "TEXT NOT NULL DEFAULT ''"),
+			addColumn("entries", "language", "TEXT"),
+		),
+	},
+	{
+		// last_entry_seen_at starts at the newest published date already stored
+		version: 49,
+		name:    "add feeds not-modified tracking",
+		applied: hasColumns("feeds", "not_modified_streak", "last_entry_seen_at", "suspect_caching_at"),
+		up: steps(
+			addColumn("feeds", "not_modified_streak", "INTEGER NOT NULL DEFAULT 0"),
+			addColumn("feeds", "last_entry_seen_at", "TEXT"),
+			addColumn("feeds", "suspect_caching_at", "TEXT"),
+			execStatements(`UPDATE feeds SET last_entry_seen_at = (
+				SELECT strftime('%Y-%m-%dT%H:%M:%SZ', MAX(julianday(e.published_at)))
+				FROM entries e
+				WHERE e.feed_id = feeds.id AND julianday(e.published_at) <= julianday('now')
+			)`),
+		),
+	},
+	{
+		// Folder and feed digests; scope_id is a folder or feed ID depending on scope
+		version: 50,
+		name:    "create ai_digests",
+		applied: hasObjects("index", "idx_ai_digests_key"),
+		up: execStatements(`
+			CREATE TABLE IF NOT EXISTS ai_digests (
+				id INTEGER PRIMARY KEY,
+				scope TEXT NOT NULL,
+				scope_id INTEGER NOT NULL,
+				window_start TEXT NOT NULL,
+				window_end TEXT NOT NULL,
+				language TEXT NOT NULL,
+				entries INTEGER NOT NULL DEFAULT 0,
+				digest TEXT NOT NULL,
+				created_at TEXT NOT NULL
+			)
+		`,
+			`CREATE UNIQUE INDEX IF NOT EXISTS idx_ai_digests_key ON ai_digests(scope, scope_id, window_end, language)`,
+		),
+	},
+	{
+		// Rolling history of refresh attempts, trimmed per feed on every write
+		version: 51,
+		name:    "create feed_fetch_log",
+		applied: hasObjects("index", "idx_feed_fetch_log_feed_id"),
+		up: execStatements(`
+			CREATE TABLE IF NOT EXISTS feed_fetch_log (
+				id INTEGER PRIMARY KEY,
+				feed_id INTEGER NOT NULL,
+				fetched_at TEXT NOT NULL,
+				status_code INTEGER NOT NULL DEFAULT 0,
+				duration_ms INTEGER NOT NULL DEFAULT 0,
+				bytes INTEGER NOT NULL DEFAULT 0,
+				outcome TEXT NOT NULL,
+				FOREIGN KEY (feed_id) REFERENCES feeds(id) ON DELETE CASCADE
+			)
+		`,
+			`CREATE INDEX IF NOT EXISTS idx_feed_fetch_log_feed_id ON feed_fetch_log(feed_id, id)`,
+		),
+	},
+	{
+		// Feed items as delivered, kept apart so entries stay lean
+		version: 52,
+		name:    "create entry_raw_items",
+		applied: hasObjects("index", "idx_entry_raw_items_stored_at"),
+		up: execStatements(`
+			CREATE TABLE IF NOT EXISTS entry_raw_items (
+				entry_id INTEGER PRIMARY KEY,
+				raw TEXT NOT NULL,
+				stored_at TEXT NOT NULL,
+				FOREIGN KEY (entry_id) REFERENCES entries(id) ON DELETE CASCADE
+			)
+		`,
+			`CREATE INDEX IF NOT EXISTS idx_entry_raw_items_stored_at ON entry_raw_items(stored_at)`,
+		),
+	},
+	{
+		// Delta entry lists: what changed by updated_at, what went away by tombstone.
+		// The trigger covers every delete path, cascades from feed purges included.
+		version: 53,
+		name:    "create entry_tombstones",
+		applied: allOf(
+			hasObjects("index", "idx_entries_updated_at", "idx_entry_tombstones_deleted_at"),
+			hasObjects("trigger", "entries_tombstone"),
+		),
+		up: execStatements(
+			`CREATE INDEX IF NOT EXISTS idx_entries_updated_at ON entries(updated_at)`,
+			`CREATE TABLE IF NOT EXISTS entry_tombstones (
+				entry_id INTEGER PRIMARY KEY,
+				feed_id INTEGER NOT NULL,
+				deleted_at TEXT NOT NULL
+			)`,
+			`CREATE INDEX IF NOT EXISTS idx_entry_tombstones_deleted_at ON entry_tombstones(deleted_at)`,
+			`CREATE TRIGGER IF NOT EXISTS entries_tombstone AFTER DELETE ON entries BEGIN
+				INSERT OR REPLACE INTO entry_tombstones (entry_id, feed_id, deleted_at)
+				VALUES (old.id, old.feed_id, strftime('%Y-%m-%dT%H:%M:%fZ', 'now'));
+			END`,
+		),
+	},
+	{
+		version: 54,
+		name:    "add feeds.icon_source",
+		applied: hasColumns("feeds", "icon_source"),
+		up:      addColumn("feeds", "icon_source", "TEXT"),
+	},
+	{
+		// NULL inherits: feeds from their folder, folders from their parent
+		version: 55,
+		name:    "add feed and folder defaults",
+		applied: allOf(
+			hasColumns("feeds", "user_agent", "auto_readability"),
+			hasColumns("folders", "user_agent", "auto_readability", "auto_translate"),
+		),
+		up: steps(
+			addColumn("feeds", "user_agent", "TEXT"),
+			addColumn("feeds", "auto_readability", "INTEGER"),
+			addColumn("folders", "user_agent", "TEXT"),
+			addColumn("folders", "auto_readability", "INTEGER"),
+			addColumn("folders", "auto_translate", "INTEGER"),
+		),
+	},
+	{
+		// Click-throughs to original articles. feed_id is kept so clicks outlive
+		// the cleanup of their entry.
+		version: 56,
+		name:    "create entry_clicks",
+		applied: hasObjects("index", "idx_entry_clicks_clicked_at"),
+		up: execStatements(`
+			CREATE TABLE IF NOT EXISTS entry_clicks (
+				id INTEGER PRIMARY KEY,
+				entry_id INTEGER NOT NULL,
+				feed_id INTEGER NOT NULL,
+				clicked_at TEXT NOT NULL,
+				FOREIGN KEY (feed_id) REFERENCES feeds(id) ON DELETE CASCADE
+			)
+		`,
+			`CREATE INDEX IF NOT EXISTS idx_entry_clicks_clicked_at ON entry_clicks(clicked_at)`,
+		),
+	},
+	{
+		// What the last refresh of each feed skipped or altered; feeds whose
+		// last refresh was clean have no row.
+		version: 57,
+		name:    "create feed_ingest_reports",
+		applied: hasObjects("table", "feed_ingest_reports"),
+		up: execStatements(`
+			CREATE TABLE IF NOT EXISTS feed_ingest_reports (
+				feed_id INTEGER PRIMARY KEY,
+				report TEXT NOT NULL,
+				FOREIGN KEY (feed_id) REFERENCES feeds(id) ON DELETE CASCADE
+			)
+		`),
+	},
+	{
+		// Where published_at came from: feed, summary or fetch. Entries saved
+		// before have NULL.
+		version: 58,
+		name:    "add entries.published_at_source",
+		applied: hasColumns("entries", "published_at_source"),
+		up:      addColumn("entries", "published_at_source", "TEXT"),
+	},
+	{
+		// Counts of the entries saved since the start of the day
+		version: 59,
+		name:    "add idx_entries_created_at",
+		applied: hasObjects("index", "idx_entries_created_at"),
+		up:      execStatements(`CREATE INDEX IF NOT EXISTS idx_entries_created_at ON entries(created_at)`),
+	},
+	{
+		// Article links normalized across mobile, AMP and tracking variants.
+		// NULL until the entry is saved again, visited or backfilled by the
+		// canonicalize_urls maintenance action; empty for entries without one.
+		version: 60,
+		name:    "add entries.canonical_url",
+		applied: allOf(hasColumns("entry_clicks", "canonical_url"), hasObjects("index", "idx_entries_canonical_url")),
+		up: steps(
+			addColumn("entries", "canonical_url", "TEXT"),
+			addColumn("entry_clicks", "canonical_url", "TEXT"),
+			execStatements(`CREATE INDEX IF NOT EXISTS idx_entries_canonical_url ON entries(canonical_url)`),
+		),
+	},
+	{
+		// Readable content and AI summaries are indexed apart from entries_fts,
+		// whose insert trigger only sees the feed's columns. The repository
+		// keeps rows up to date; existing entries are filled in by the
+		// index_search maintenance action.
+		version: 61,
+		name:    "create entries_fts_ext",
+		applied: allOf(hasObjects("table", "entries_fts_ext"), hasObjects("trigger", "entries_ad_ext")),
+		up: execStatements(
+			`CREATE VIRTUAL TABLE IF NOT EXISTS entries_fts_ext USING fts5(
+				readable_text,
+				summary_text,
+				tokenize = 'unicode61'
+			)`,
+			`CREATE TRIGGER IF NOT EXISTS entries_ad_ext AFTER DELETE ON entries BEGIN
+				DELETE FROM entries_fts_ext WHERE rowid = old.id;
+			END`,
+		),
+	},
+	{
+		// Pairs of live feeds that look like the same subscription, with
+		// feed_id < other_feed_id. idx_entries_hash lets detection look up a
+		// feed's recent hashes in every other feed.
+		version: 62,
+		name:    "create feed_overlaps",
+		applied: allOf(hasObjects("table", "feed_overlaps"), hasObjects("index", "idx_entries_hash")),
+		up: execStatements(`
+			CREATE TABLE IF NOT EXISTS feed_overlaps (
+				id INTEGER PRIMARY KEY,
+				feed_id INTEGER NOT NULL,
+				other_feed_id INTEGER NOT NULL,
+				same_site INTEGER NOT NULL DEFAULT 0,
+				shared_entries INTEGER NOT NULL DEFAULT 0,
+				overlap_ratio REAL NOT NULL DEFAULT 0,
+				detected_at TEXT NOT NULL,
+				UNIQUE (feed_id, other_feed_id),
+				FOREIGN KEY (feed_id) REFERENCES feeds(id) ON DELETE CASCADE,
+				FOREIGN KEY (other_feed_id) REFERENCES feeds(id) ON DELETE CASCADE
+			)
+		`,
+			`CREATE INDEX IF NOT EXISTS idx_entries_hash ON entries(hash)`,
+		),
+	},
+}
+
+func execStatements(statements ...string) migrationFunc {
+	return func(ctx context.Context, conn *sql.Conn) error {
+		for _, statement := range statements {
+			if _, err := conn.ExecContext(ctx, statement); err != nil {
+				return err
+			}
+		}
+		return nil
 	}
+}
 
-	if count == 0 {
-		if _, err := db.Exec(`ALTER TABLE feeds ADD COLUMN error_message TEXT`); err != nil {
-			return fmt.Errorf("add error_message column: %w", err)
+// addColumn adds the column unless the table already has it.
+func addColumn(table, column, definition string) migrationFunc {
+	return func(ctx context.Context, conn *sql.Conn) error {
+		exists, err := hasColumn(ctx, conn, table, column)
+		if err != nil {
+			return fmt.Errorf("check %s %s column: %w", table, column, err)
+		}
+		if exists {
+			return nil
 		}
+		if _, err := conn.ExecContext(ctx, fmt.Sprintf(`ALTER TABLE %s ADD COLUMN %s %s`, table, column, definition)); err != nil {
+			return fmt.Errorf("add %s %s column: %w", table, column, err)
+		}
+		return nil
 	}
+}
 
-	// Migration 9: Create settings table for key-value configuration storage
-	if _, err := db.Exec(`
-		CREATE TABLE IF NOT EXISTS settings (
-			key TEXT PRIMARY KEY,
-			value TEXT NOT NULL,
-			updated_at TEXT NOT NULL
-		)
-	`); err != nil {
-		return fmt.Errorf("create settings table: %w", err)
-	}
-
-	// Migration 10: Create ai_summaries table for AI summary cache
-	if _, err := db.Exec(`
-		CREATE TABLE IF NOT EXISTS ai_summaries (
-			id INTEGER PRIMARY KEY,
-			entry_id INTEGER NOT NULL,
-			is_readability INTEGER NOT NULL DEFAULT 0,
-			language TEXT NOT NULL,
-			summary TEXT NOT NULL,
-			created_at TEXT NOT NULL,
-			FOREIGN KEY (entry_id) REFERENCES entries(id) ON DELETE CASCADE
-		)
-	`); err != nil {
-		return fmt.Errorf("create ai_summaries table: %w", err)
+func steps(fns ...migrationFunc) migrationFunc {
+	return func(ctx context.Context, conn *sql.Conn) error {
+		for _, fn := range fns {
+			if err := fn(ctx, conn); err != nil {
+				return err
+			}
+		}
+		return nil
 	}
+}
 
-	if _, err := db.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS idx_ai_summaries_entry_mode ON ai_summaries(entry_id, is_readability, language)`); err != nil {
-		return fmt.Errorf("create idx_ai_summaries_entry_mode: %w", err)
+func hasColumns(table string, columns ...string) schemaCheck {
+	return func(ctx context.Context, conn *sql.Conn) (bool, error) {
+		for _, column := range columns {
+			exists, err := hasColumn(ctx, conn, table, column)
+			if err != nil || !exists {
+				return false, err
+			}
+		}
+		return true, nil
 	}
+}
 
-	// Migration 11: Create ai_translations table for AI translation cache
-	if _, err := db.Exec(`
-		CREATE TABLE IF NOT EXISTS ai_translations (
-			id INTEGER PRIMARY KEY,
-			entry_id INTEGER NOT NULL,
-			is_readability INTEGER NOT NULL DEFAULT 0,
-			language TEXT NOT NULL,
-			content TEXT NOT NULL,
-			created_at TEXT NOT NULL,
-			FOREIGN KEY (entry_id) REFERENCES entries(id) ON DELETE CASCADE
-		)
-	`); err != nil {
-		return fmt.Errorf("create ai_translations table: %w", err)
+// hasObjects reports whether sqlite_master lists every name as a kind (table,
+// index or trigger).
+func hasObjects(kind string, names ...string) schemaCheck {
+	return func(ctx context.Context, conn *sql.Conn) (bool, error) {
+		for _, name := range names {
+			exists, err := hasObject(ctx, conn, kind, name)
+			if err != nil || !exists {
+				return false, err
+			}
+		}
+		return true, nil
 	}
+}
 
-	if _, err := db.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS idx_ai_translations_entry_mode ON ai_translations(entry_id, is_readability, language)`); err != nil {
-		return fmt.Errorf("create idx_ai_translations_entry_mode: %w", err)
+func lacksObject(kind, name string) schemaCheck {
+	return func(ctx context.Context, conn *sql.Conn) (bool, error) {
+		exists, err := hasObject(ctx, conn, kind, name)
+		return !exists, err
 	}
+}
 
-	// Migration 12: Create ai_list_translations table for title/summary translation cache
-	if _, err := db.Exec(`
-		CREATE TABLE IF NOT EXISTS ai_list_translations (
-			id INTEGER PRIMARY KEY,
-			entry_id INTEGER NOT NULL,
-			language TEXT NOT NULL,
-			title TEXT NOT NULL,
-			summary TEXT NOT NULL,
-			created_at TEXT NOT NULL,
-			FOREIGN KEY (entry_id) REFERENCES entries(id) ON DELETE CASCADE
-		)
-	`); err != nil {
-		return fmt.Errorf("create ai_list_translations table: %w", err)
+func allOf(checks ...schemaCheck) schemaCheck {
+	return func(ctx context.Context, conn *sql.Conn) (bool, error) {
+		for _, check := range checks {
+			ok, err := check(ctx, conn)
+			if err != nil || !ok {
+				return false, err
+			}
+		}
+		return true, nil
 	}
+}
 
-	if _, err := db.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS idx_ai_list_translations_entry_lang ON ai_list_translations(entry_id, language)`); err != nil {
-		return fmt.Errorf("create idx_ai_list_translations_entry_lang: %w", err)
+func hasObject(ctx context.Context, conn *sql.Conn, kind, name string) (bool, error) {
+	var count int
+	if err := conn.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM sqlite_master WHERE type = ? AND name = ?`,
+		kind,
+		name,
+	).Scan(&count); err != nil {
+		return false, err
 	}
+	return count > 0, nil
+}
 
-	// Migration 13: Add type column to feeds for content type (article/picture/notification)
-	err = db.QueryRow(`
-		SELECT COUNT(*) FROM pragma_table_info('feeds') WHERE name = 'type'
-	`).Scan(&count)
+// entriesDeleteTriggerFixed reports whether entries_ad deletes from entries_fts
+// directly rather than through the FTS5 'delete' command.
+func entriesDeleteTriggerFixed(ctx context.Context, conn *sql.Conn) (bool, error) {
+	var definition sql.NullString
+	err := conn.QueryRowContext(ctx,
+		`SELECT sql FROM sqlite_master WHERE type = 'trigger' AND name = 'entries_ad'`,
+	).Scan(&definition)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
 	if err != nil {
-		return fmt.Errorf("check feeds type column: %w", err)
+		return false, err
 	}
+	return !strings.Contains(definition.String, "'delete'"), nil
+}
 
-	if count == 0 {
-		if _, err := db.Exec(`ALTER TABLE feeds ADD COLUMN type TEXT NOT NULL DEFAULT 'article'`); err != nil {
-			return fmt.Errorf("add feeds type column: %w", err)
-		}
+// entryWordCountsFilled reports whether entries.word_count exists and has been
+// backfilled for every row.
+func entryWordCountsFilled(ctx context.Context, conn *sql.Conn) (bool, error) {
+	exists, err := hasColumn(ctx, conn, "entries", "word_count")
+	if err != nil || !exists {
+		return false, err
 	}
+	var missing bool
+	if err := conn.QueryRowContext(ctx,
+		`SELECT EXISTS (SELECT 1 FROM entries WHERE word_count IS NULL)`,
+	).Scan(&missing); err != nil {
+		return false, err
+	}
+	return !missing, nil
+}
 
-	// Migration 14: Add type column to folders for content type (article/picture/notification)
-	err = db.QueryRow(`
-		SELECT COUNT(*) FROM pragma_table_info('folders') WHERE name = 'type'
-	`).Scan(&count)
+// migrateFeedCustomTitle adds feeds.custom_title. A stored title may be a
+// rename, so it is copied there; the first refresh that gets the same title
+// from the feed clears it again. Titles of static feeds, which never refresh,
+// and URL placeholders of feeds never fetched are not copied.
+func migrateFeedCustomTitle(ctx context.Context, conn *sql.Conn) error {
+	exists, err := hasColumn(ctx, conn, "feeds", "custom_title")
 	if err != nil {
-		return fmt.Errorf("check folders type column: %w", err)
+		return fmt.Errorf("check feeds custom_title column: %w", err)
 	}
-
-	if count == 0 {
-		if _, err := db.Exec(`ALTER TABLE folders ADD COLUMN type TEXT NOT NULL DEFAULT 'article'`); err != nil {
-			return fmt.Errorf("add folders type column: %w", err)
-		}
+	if exists {
+		return nil
 	}
 
-	// Migration 15: Fix FTS5 delete trigger (modernc.org/sqlite doesn't support the special insert syntax)
-	// Recreate the trigger with direct DELETE syntax
-	if _, err := db.Exec(`DROP TRIGGER IF EXISTS entries_ad`); err != nil {
-		return fmt.Errorf("drop entries_ad trigger: %w", err)
+	if _, err := conn.ExecContext(ctx, `ALTER TABLE feeds ADD COLUMN custom_title TEXT`); err != nil {
+		return fmt.Errorf("add feeds custom_title column: %w", err)
 	}
-	if _, err := db.Exec(`CREATE TRIGGER IF NOT EXISTS entries_ad AFTER DELETE ON entries BEGIN
-		DELETE FROM entries_fts WHERE rowid = old.id;
-	END`); err != nil {
-		return fmt.Errorf("create entries_ad trigger: %w", err)
+	if _, err := conn.ExecContext(ctx, `UPDATE feeds SET custom_title = title WHERE title <> url AND url NOT LIKE 'static://%'`); err != nil {
+		return fmt.Errorf("copy feed titles: %w", err)
 	}
+	return nil
+}
 
-	// Migration 16: Create domain_rate_limits table for per-host rate limiting
-	if _, err := db.Exec(`
-		CREATE TABLE IF NOT EXISTS domain_rate_limits (
-			id INTEGER PRIMARY KEY,
-			host TEXT NOT NULL UNIQUE,
-			interval_seconds INTEGER NOT NULL,
-			created_at TEXT NOT NULL,
-			updated_at TEXT NOT NULL
-		)
-	`); err != nil {
-		return fmt.Errorf("create domain_rate_limits table: %w", err)
-	}
+// wordCountBatchSize bounds how many entries the backfill holds in memory at once.
+const wordCountBatchSize = 500
 
-	if _, err := db.Exec(`CREATE INDEX IF NOT EXISTS idx_domain_rate_limits_host ON domain_rate_limits(host)`); err != nil {
-		return fmt.Errorf("create idx_domain_rate_limits_host: %w", err)
+// migrateEntryWordCount adds entries.word_count and fills it for rows where it is
+// still NULL, a batch at a time.
+func migrateEntryWordCount(ctx context.Context, conn *sql.Conn) error {
+	if err := addColumn("entries", "word_count", "INTEGER")(ctx, conn); err != nil {
+		return err
 	}
 
-	// Migration 17: Switch entry dedup key to hash and merge historical duplicates.
-	if err := migrateEntryHashDeduplication(db); err != nil {
-		return fmt.Errorf("migrate entry hash deduplication: %w", err)
+	for {
+		done, err := backfillWordCountBatch(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if done {
+			return nil
+		}
 	}
+}
 
-	// Migration 18: Add summary_prompt_reminder column to feeds for per-feed summarize prompt customization.
-	err = db.QueryRow(`
-		SELECT COUNT(*) FROM pragma_table_info('feeds') WHERE name = 'summary_prompt_reminder'
-	`).Scan(&count)
+func backfillWordCountBatch(ctx context.Context, conn *sql.Conn) (bool, error) {
+	rows, err := conn.QueryContext(ctx,
+		`SELECT id, content, readable_content FROM entries WHERE word_count IS NULL ORDER BY id LIMIT ?`,
+		wordCountBatchSize,
+	)
 	if err != nil {
-		return fmt.Errorf("check feeds summary_prompt_reminder column: %w", err)
+		return false, fmt.Errorf("query entries for word count: %w", err)
 	}
 
-	if count == 0 {
-		if _, err := db.Exec(`ALTER TABLE feeds ADD COLUMN summary_prompt_reminder TEXT`); err != nil {
-			return fmt.Errorf("add feeds summary_prompt_reminder column: %w", err)
+	counts := make(map[int64]int)
+	for rows.Next() {
+		var id int64
+		var content, readable sql.NullString
+		if err := rows.Scan(&id, &content, &readable); err != nil {
+			rows.Close()
+			return false, fmt.Errorf("scan entry for word count: %w", err)
 		}
+		counts[id] = max(readtime.WordCount(content.String), readtime.WordCount(readable.String))
 	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return false, fmt.Errorf("iterate entries for word count: %w", err)
+	}
+	rows.Close()
 
-	return nil
-}
-
-type dedupeEntry struct {
-	id        int64
-	feedID    int64
-	url       string
-	title     string
-	content   string
-	read      int
-	starred   int
-	updatedAt time.Time
-}
+	if len(counts) == 0 {
+		return true, nil
+	}
 
-type dedupeGroup struct {
-	keep       dedupeEntry
-	hasKeep    bool
-	readMax    int
-	starredMax int
-	duplicate  []int64
+	for id, count := range counts {
+		if _, err := conn.ExecContext(ctx, `UPDATE entries SET word_count = ? WHERE id = ?`, count, id); err != nil {
+			return false, fmt.Errorf("update entry word count: %w", err)
+		}
+	}
+	return len(counts) < wordCountBatchSize, nil
 }
 
-func migrateEntryHashDeduplication(db *sql.DB) error {
-	var hashIndexCount int
-	if err := db.QueryRow(
-		`SELECT COUNT(*) FROM sqlite_master WHERE type = 'index' AND name = 'idx_entries_feed_hash'`,
-	).Scan(&hashIndexCount); err != nil {
-		return fmt.Errorf("check idx_entries_feed_hash: %w", err)
+// migrateFolderUniqueName merges live folders sharing a name under the same parent
+// into the earliest created one and then adds a unique index, so concurrent creates
+// can no longer produce such twins. Deleted folders are left out of both, keeping
+// trashed names free for reuse.
+func migrateFolderUniqueName(ctx context.Context, conn *sql.Conn) error {
+	exists, err := hasObject(ctx, conn, "index", "idx_folders_parent_name")
+	if err != nil {
+		return fmt.Errorf("check idx_folders_parent_name: %w", err)
 	}
-	if hashIndexCount > 0 {
+	if exists {
 		return nil
 	}
 
-	exists, err := hasColumn(db, "entries", "hash")
-	if err != nil {
-		return fmt.Errorf("check hash column: %w", err)
-	}
-	if !exists {
-		if _, err := db.Exec(`ALTER TABLE entries ADD COLUMN hash TEXT NOT NULL DEFAULT ''`); err != nil {
-			return fmt.Errorf("add hash column: %w", err)
+	// Merging two folders brings their subfolders together, which can leave new
+	// twins one level down, so repeat until a pass finds nothing to merge.
+	for {
+		merged, err := mergeDuplicateFolders(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if merged == 0 {
+			break
 		}
 	}
 
-	tx, err := db.Begin()
-	if err != nil {
-		return fmt.Errorf("begin tx: %w", err)
+	// SQLite treats NULLs as distinct, so top-level folders are keyed as parent 0
+	if _, err := conn.ExecContext(ctx,
+		`CREATE UNIQUE INDEX IF NOT EXISTS idx_folders_parent_name ON folders(COALESCE(parent_id, 0), name) WHERE deleted_at IS NULL`,
+	); err != nil {
+		return fmt.Errorf("create idx_folders_parent_name: %w", err)
 	}
-	defer func() { _ = tx.Rollback() }()
+	return nil
+}
 
-	if err := mergeHistoricalEntries(tx); err != nil {
-		return err
-	}
-	if err := backfillEntryHash(tx); err != nil {
-		return err
+// mergeDuplicateFolders moves the feeds and subfolders of every later live twin onto
+// the earliest one and deletes the twin. Returns how many folders were deleted.
+func mergeDuplicateFolders(ctx context.Context, conn *sql.Conn) (int, error) {
+	rows, err := conn.QueryContext(ctx, `SELECT id, name, parent_id FROM folders WHERE deleted_at IS NULL ORDER BY created_at, id`)
+	if err != nil {
+		return 0, fmt.Errorf("query folders for merge: %w", err)
 	}
-	if err := ensureHashUniqueness(tx); err != nil {
-		return err
+
+	type folderKey struct {
+		parentID int64
+		name     string
 	}
-	if _, err := tx.Exec(`DROP INDEX IF EXISTS idx_entries_feed_url`); err != nil {
-		return fmt.Errorf("drop idx_entries_feed_url: %w", err)
+	keepByKey := make(map[folderKey]int64)
+	duplicates := make(map[int64]int64)
+	for rows.Next() {
+		var id int64
+		var key folderKey
+		var parentID sql.NullInt64
+		if err := rows.Scan(&id, &key.name, &parentID); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("scan folder for merge: %w", err)
+		}
+		key.parentID = parentID.Int64
+		if keepID, ok := keepByKey[key]; ok {
+			duplicates[id] = keepID
+			continue
+		}
+		keepByKey[key] = id
 	}
-	if _, err := tx.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS idx_entries_feed_hash ON entries(feed_id, hash)`); err != nil {
-		return fmt.Errorf("create idx_entries_feed_hash: %w", err)
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return 0, fmt.Errorf("iterate folders for merge: %w", err)
 	}
+	rows.Close()
 
-	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("commit tx: %w", err)
+	for duplicateID, keepID := range duplicates {
+		if _, err := conn.ExecContext(ctx, `UPDATE feeds SET folder_id = ? WHERE folder_id = ?`, keepID, duplicateID); err != nil {
+			return 0, fmt.Errorf("move folder feeds: %w", err)
+		}
+		if _, err := conn.ExecContext(ctx, `UPDATE folders SET parent_id = ? WHERE parent_id = ?`, keepID, duplicateID); err != nil {
+			return 0, fmt.Errorf("move subfolders: %w", err)
+		}
+		if _, err := conn.ExecContext(ctx, `DELETE FROM folders WHERE id = ?`, duplicateID); err != nil {
+			return 0, fmt.Errorf("delete duplicate folder: %w", err)
+		}
 	}
-	return nil
+	return len(duplicates), nil
 }
 
-func hasColumn(db *sql.DB, table string, column string) (bool, error) {
-	var count int
-	if err := db.QueryRow(
-		fmt.Sprintf(`SELECT COUNT(*) FROM pragma_table_info('%s') WHERE name = ?`, table),
-		column,
-	).Scan(&count); err != nil {
-		return false, err
-	}
-	return count > 0, nil
+type canonicalFeed struct {
+	id       int64
+	folderID sql.NullInt64
 }
 
-func mergeHistoricalEntries(tx *sql.Tx) error {
-	rows, err := tx.Query(`
-		SELECT id, feed_id, url, title, content, read, starred, updated_at
-		FROM entries
-	`)
+// migrateFeedCanonicalURL backfills feeds.canonical_url and merges duplicates into
+// the earliest subscribed feed: entries move over (merging read/starred state when
+// the kept feed already has the same entry), the folder is adopted if the kept
+// feed has none, and the duplicate feed is deleted.
+func migrateFeedCanonicalURL(ctx context.Context, conn *sql.Conn) error {
+	exists, err := hasObject(ctx, conn, "index", "idx_feeds_canonical_url")
 	if err != nil {
-		return fmt.Errorf("query entries for merge: %w", err)
+		return fmt.Errorf("check idx_feeds_canonical_url: %w", err)
+	}
+	if exists {
+		return nil
+	}
+
+	if err := addColumn("feeds", "canonical_url", "TEXT NOT NULL DEFAULT ''")(ctx, conn); err != nil {
+		return err
+	}
+
+	rows, err := conn.QueryContext(ctx, `SELECT id, url, folder_id FROM feeds ORDER BY created_at, id`)
+	if err != nil {
+		return fmt.Errorf("query feeds for canonical url: %w", err)
 	}
-	defer rows.Close()
 
-	groups := make(map[string]*dedupeGroup)
+	keepByURL := make(map[string]*canonicalFeed)
+	canonicalByID := make(map[int64]string)
+	duplicates := make(map[int64]*canonicalFeed)
+	var order []int64
 	for rows.Next() {
-		var (
-			entry        dedupeEntry
-			urlStr       sql.NullString
-			titleStr     sql.NullString
-			contentStr   sql.NullString
-			updatedAtStr string
-		)
-		if err := rows.Scan(
-			&entry.id,
-			&entry.feedID,
-			&urlStr,
-			&titleStr,
-			&contentStr,
-			&entry.read,
-			&entry.starred,
-			&updatedAtStr,
-		); err != nil {
-			return fmt.Errorf("scan entry for merge: %w", err)
-		}
-		entry.url = strings.TrimSpace(urlStr.String)
-		entry.title = strings.TrimSpace(titleStr.String)
-		entry.content = strings.TrimSpace(contentStr.String)
-		entry.updatedAt, _ = time.Parse(time.RFC3339, updatedAtStr)
-
-		groupKey := fmt.Sprintf("%d|%s", entry.feedID, legacyMergeKey(entry))
-		group, ok := groups[groupKey]
-		if !ok {
-			groups[groupKey] = &dedupeGroup{
-				keep:       entry,
-				hasKeep:    true,
-				readMax:    entry.read,
-				starredMax: entry.starred,
+		var feed canonicalFeed
+		var rawURL string
+		if err := rows.Scan(&feed.id, &rawURL, &feed.folderID); err != nil {
+			rows.Close()
+			return fmt.Errorf("scan feed for canonical url: %w", err)
+		}
+		canonical := urlutil.CanonicalFeedURL(rawURL)
+		if keep, ok := keepByURL[canonical]; ok {
+			duplicates[feed.id] = keep
+			if !keep.folderID.Valid && feed.folderID.Valid {
+				keep.folderID = feed.folderID
 			}
 			continue
 		}
-
-		if entry.read > group.readMax {
-			group.readMax = entry.read
-		}
-		if entry.starred > group.starredMax {
-			group.starredMax = entry.starred
-		}
-
-		if chooseAsKeep(entry, group.keep) {
-			group.duplicate = append(group.duplicate, group.keep.id)
-			group.keep = entry
-		} else {
-			group.duplicate = append(group.duplicate, entry.id)
-		}
+		f := feed
+		keepByURL[canonical] = &f
+		canonicalByID[feed.id] = canonical
+		order = append(order, feed.id)
 	}
 	if err := rows.Err(); err != nil {
-		return fmt.Errorf("iterate entries for merge: %w", err)
+		rows.Close()
+		return fmt.Errorf("iterate feeds for canonical url: %w", err)
 	}
+	rows.Close()
 
-	for _, group := range groups {
-		if !group.hasKeep || len(group.duplicate) == 0 {
-			continue
-		}
-
-		if group.keep.read != group.readMax || group.keep.starred != group.starredMax {
-			if _, err := tx.Exec(
-				`UPDATE entries SET read = ?, starred = ? WHERE id = ?`,
-				group.readMax,
-				group.starredMax,
-				group.keep.id,
-			); err != nil {
-				return fmt.Errorf("update merged entry state: %w", err)
-			}
-		}
-
-		for _, duplicateID := range group.duplicate {
-			if err := moveEntryCaches(tx, duplicateID, group.keep.id); err != nil {
-				return err
-			}
+	for duplicateID, keep := range duplicates {
+		if err := mergeFeedInto(ctx, conn, duplicateID, keep.id); err != nil {
+			return err
 		}
+	}
 
-		if err := deleteEntriesByID(tx, group.duplicate); err != nil {
-			return err
+	for _, id := range order {
+		canonical := canonicalByID[id]
+		keep := keepByURL[canonical]
+		if _, err := conn.ExecContext(ctx,
+			`UPDATE feeds SET canonical_url = ?, folder_id = ? WHERE id = ?`,
+			canonical,
+			keep.folderID,
+			id,
+		); err != nil {
+			return fmt.Errorf("update feed canonical url: %w", err)
 		}
 	}
 
+	if _, err := conn.ExecContext(ctx, `CREATE UNIQUE INDEX IF NOT EXISTS idx_feeds_canonical_url ON feeds(canonical_url)`); err != nil {
+		return fmt.Errorf("create idx_feeds_canonical_url: %w", err)
+	}
 	return nil
 }
 
-func moveEntryCaches(tx *sql.Tx, fromID int64, toID int64) error {
-	tables := []string{"ai_summaries", "ai_translations", "ai_list_translations"}
-	for _, table := range tables {
-		updateQuery := fmt.Sprintf(`UPDATE OR IGNORE %s SET entry_id = ? WHERE entry_id = ?`, table)
-		if _, err := tx.Exec(updateQuery, toID, fromID); err != nil {
-			return fmt.Errorf("update %s entry_id: %w", table, err)
-		}
-
-		deleteQuery := fmt.Sprintf(`DELETE FROM %s WHERE entry_id = ?`, table)
-		if _, err := tx.Exec(deleteQuery, fromID); err != nil {
-			return fmt.Errorf("delete %s duplicate rows: %w", table, err)
-		}
+// mergeFeedInto moves all entries of fromID to toID and deletes fromID.
+func mergeFeedInto(ctx context.Context, conn *sql.Conn, fromID int64, toID int64) error {
+	if _, err := MergeFeedEntries(ctx, conn, fromID, toID); err != nil {
+		return err
+	}
+	if _, err := conn.ExecContext(ctx, `DELETE FROM feeds WHERE id = ?`, fromID); err != nil {
+		return fmt.Errorf("delete duplicate feed: %w", err)
 	}
 	return nil
 }
 
-func deleteEntriesByID(tx *sql.Tx, ids []int64) error {
-	if len(ids) == 0 {
+func migrateEntryHashDeduplication(ctx context.Context, conn *sql.Conn) error {
+	exists, err := hasObject(ctx, conn, "index", "idx_entries_feed_hash")
+	if err != nil {
+		return fmt.Errorf("check idx_entries_feed_hash: %w", err)
+	}
+	if exists {
 		return nil
 	}
 
-	// Keep each statement below SQLite variable limits.
-	const batchSize = 500
-	for start := 0; start < len(ids); start += batchSize {
-		end := start + batchSize
-		if end > len(ids) {
-			end = len(ids)
-		}
-
-		chunk := ids[start:end]
-		args := make([]interface{}, 0, len(chunk))
-		placeholders := make([]string, 0, len(chunk))
-		for _, id := range chunk {
-			args = append(args, id)
-			placeholders = append(placeholders, "?")
-		}
+	if err := addColumn("entries", "hash", "TEXT NOT NULL DEFAULT ''")(ctx, conn); err != nil {
+		return err
+	}
 
-		query := fmt.Sprintf(`DELETE FROM entries WHERE id IN (%s)`, strings.Join(placeholders, ","))
-		if _, err := tx.Exec(query, args...); err != nil {
-			return fmt.Errorf("delete duplicate entries: %w", err)
-		}
+	if _, err := MergeDuplicateEntries(ctx, conn, 0, legacyMergeKey); err != nil {
+		return err
+	}
+	if err := backfillEntryHash(ctx, conn); err != nil {
+		return err
+	}
+	if err := ensureHashUniqueness(ctx, conn); err != nil {
+		return err
+	}
+	if _, err := conn.ExecContext(ctx, `DROP INDEX IF EXISTS idx_entries_feed_url`); err != nil {
+		return fmt.Errorf("drop idx_entries_feed_url: %w", err)
+	}
+	if _, err := conn.ExecContext(ctx, `CREATE UNIQUE INDEX IF NOT EXISTS idx_entries_feed_hash ON entries(feed_id, hash)`); err != nil {
+		return fmt.Errorf("create idx_entries_feed_hash: %w", err)
 	}
 	return nil
 }
 
-func backfillEntryHash(tx *sql.Tx) error {
-	rows, err := tx.Query(`SELECT id, url, title, content FROM entries WHERE hash = ''`)
+func hasColumn(ctx context.Context, conn *sql.Conn, table string, column string) (bool, error) {
+	var count int
+	if err := conn.QueryRowContext(ctx,
+		fmt.Sprintf(`SELECT COUNT(*) FROM pragma_table_info('%s') WHERE name = ?`, table),
+		column,
+	).Scan(&count); err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func backfillEntryHash(ctx context.Context, conn *sql.Conn) error {
+	rows, err := conn.QueryContext(ctx, `SELECT id, url, title, content FROM entries WHERE hash = ''`)
 	if err != nil {
 		return fmt.Errorf("query entries for hash backfill: %w", err)
 	}
@@ -590,7 +1464,7 @@ func backfillEntryHash(tx *sql.Tx) error {
 			hash = hashHex(fmt.Sprintf("legacy-entry-id:%d", id))
 		}
 
-		if _, err := tx.Exec(`UPDATE entries SET hash = ? WHERE id = ?`, hash, id); err != nil {
+		if _, err := conn.ExecContext(ctx, `UPDATE entries SET hash = ? WHERE id = ?`, hash, id); err != nil {
 			return fmt.Errorf("update entry hash: %w", err)
 		}
 	}
@@ -602,9 +1476,9 @@ func backfillEntryHash(tx *sql.Tx) error {
 	return nil
 }
 
-func ensureHashUniqueness(tx *sql.Tx) error {
+func ensureHashUniqueness(ctx context.Context, conn *sql.Conn) error {
 	var duplicateGroups int
-	if err := tx.QueryRow(`
+	if err := conn.QueryRowContext(ctx, `
 		SELECT COUNT(*) FROM (
 			SELECT feed_id, hash, COUNT(*) AS c
 			FROM entries
@@ -620,24 +1494,14 @@ func ensureHashUniqueness(tx *sql.Tx) error {
 	return nil
 }
 
-func legacyMergeKey(entry dedupeEntry) string {
-	if entry.url != "" {
-		return "url:" + urlutil.StripFragment(entry.url)
-	}
-	if entry.title != "" || entry.content != "" {
-		return "content:" + entry.title + "\n" + entry.content
-	}
-	return fmt.Sprintf("id:%d", entry.id)
-}
-
-func chooseAsKeep(candidate dedupeEntry, current dedupeEntry) bool {
-	if candidate.updatedAt.After(current.updatedAt) {
-		return true
+func legacyMergeKey(entry DedupeEntry) string {
+	if entry.URL != "" {
+		return "url:" + urlutil.StripFragment(entry.URL)
 	}
-	if candidate.updatedAt.Equal(current.updatedAt) && candidate.id > current.id {
-		return true
+	if entry.Title != "" || entry.Content != "" {
+		return "content:" + entry.Title + "\n" + entry.Content
 	}
-	return false
+	return fmt.Sprintf("id:%d", entry.ID)
 }
 
 func hashHex(input string) string {
@@ -2,6 +2,9 @@ package db_test
 
 import (
 	"database/sql"
+	"fmt"
+	"path/filepath"
+	"sync"
 	"testing"
 
 	"gist/backend/internal/db"
@@ -180,3 +183,324 @@ func TestMigrate_EntryHashDeduplication_MergesHistoricalDuplicates(t *testing.T)
 	err = db.Migrate(database)
 	require.NoError(t, err)
 }
+
+func TestMigrate_FeedCanonicalURL_MergesDuplicateFeeds(t *testing.T) {
+	database, err := db.Open(filepath.Join(t.TempDir(), "gist.db"))
+	require.NoError(t, err)
+	defer database.Close()
+
+	// Simulate a database from before canonical URLs existed.
+	_, err = database.Exec(`DROP INDEX idx_feeds_canonical_url; DROP TABLE schema_migrations`)
+	require.NoError(t, err)
+
+	_, err = database.Exec(`
+		INSERT INTO folders (id, name, created_at, updated_at) VALUES (10, 'news', '2025-01-01T00:00:00Z', '2025-01-01T00:00:00Z');
+		INSERT INTO feeds (id, folder_id, title, url, created_at, updated_at) VALUES
+		(1, NULL, 'keep', 'https://Example.com/rss/', '2025-01-01T00:00:00Z', '2025-01-01T00:00:00Z'),
+		(2, 10, 'dup', 'https://example.com/rss?utm_source=rss', '2025-02-01T00:00:00Z', '2025-02-01T00:00:00Z'),
+		(3, NULL, 'other', 'https://other.example.com/rss', '2025-03-01T00:00:00Z', '2025-03-01T00:00:00Z');
+		INSERT INTO entries (id, feed_id, hash, title, url, read, starred, created_at, updated_at) VALUES
+		(101, 1, 'h1', 'shared', 'https://example.com/a', 0, 0, '2025-01-01T00:00:00Z', '2025-01-01T00:00:00Z'),
+		(201, 2, 'h1', 'shared', 'https://example.com/a', 1, 1, '2025-02-01T00:00:00Z', '2025-02-01T00:00:00Z'),
+		(202, 2, 'h2', 'only dup', 'https://example.com/b', 0, 0, '2025-02-01T00:00:00Z', '2025-02-01T00:00:00Z');
+		INSERT INTO ai_summaries (id, entry_id, is_readability, language, summary, created_at) VALUES
+		(301, 201, 0, 'zh-CN', 'summary', '2025-02-01T00:00:00Z');
+	`)
+	require.NoError(t, err)
+
+	err = db.Migrate(database)
+	require.NoError(t, err)
+
+	var feedCount int
+	require.NoError(t, database.QueryRow(`SELECT COUNT(*) FROM feeds`).Scan(&feedCount))
+	require.Equal(t, 2, feedCount)
+
+	var canonical string
+	var folderID sql.NullInt64
+	require.NoError(t, database.QueryRow(`SELECT canonical_url, folder_id FROM feeds WHERE id = 1`).Scan(&canonical, &folderID))
+	require.Equal(t, "https://example.com/rss", canonical)
+	require.True(t, folderID.Valid)
+	require.Equal(t, int64(10), folderID.Int64)
+
+	var entryCount int
+	require.NoError(t, database.QueryRow(`SELECT COUNT(*) FROM entries WHERE feed_id = 1`).Scan(&entryCount))
+	require.Equal(t, 2, entryCount)
+
+	var read, starred int
+	require.NoError(t, database.QueryRow(`SELECT read, starred FROM entries WHERE id = 101`).Scan(&read, &starred))
+	require.Equal(t, 1, read)
+	require.Equal(t, 1, starred)
+
+	var summaryEntryID int64
+	require.NoError(t, database.QueryRow(`SELECT entry_id FROM ai_summaries WHERE id = 301`).Scan(&summaryEntryID))
+	require.Equal(t, int64(101), summaryEntryID)
+
+	// Canonical duplicates are now rejected by the unique index.
+	_, err = database.Exec(`INSERT INTO feeds (id, title, url, canonical_url, created_at, updated_at) VALUES (4, 'x', 'https://example.com/rss?ref=hn', 'https://example.com/rss', '2025-04-01T00:00:00Z', '2025-04-01T00:00:00Z')`)
+	require.Error(t, err)
+
+	// Migration should be idempotent.
+	require.NoError(t, db.Migrate(database))
+}
+
+func TestMigrate_EntryWordCount_BackfillsInBatches(t *testing.T) {
+	database, err := db.Open(filepath.Join(t.TempDir(), "gist.db"))
+	require.NoError(t, err)
+	defer database.Close()
+
+	// Simulate a database whose word count backfill was cut short.
+	_, err = database.Exec(`DROP TABLE schema_migrations`)
+	require.NoError(t, err)
+	_, err = database.Exec(`INSERT INTO feeds (id, title, url, created_at, updated_at) VALUES (1, 'f', 'https://example.com/rss', '2025-01-01T00:00:00Z', '2025-01-01T00:00:00Z')`)
+	require.NoError(t, err)
+
+	// More rows than one batch, all still missing their word count
+	tx, err := database.Begin()
+	require.NoError(t, err)
+	for i := 1; i <= 1200; i++ {
+		_, err = tx.Exec(
+			`INSERT INTO entries (id, feed_id, hash, content, readable_content, read, starred, word_count, created_at, updated_at)
+			 VALUES (?, 1, ?, ?, ?, 0, 0, NULL, '2025-01-01T00:00:00Z', '2025-01-01T00:00:00Z')`,
+			i, fmt.Sprintf("h%d", i), "<p>one two three</p>", nil,
+		)
+		require.NoError(t, err)
+	}
+	_, err = tx.Exec(`UPDATE entries SET readable_content = '<p>readable content is longer</p>' WHERE id = 7`)
+	require.NoError(t, err)
+	_, err = tx.Exec(`UPDATE entries SET content = NULL WHERE id = 8`)
+	require.NoError(t, err)
+	require.NoError(t, tx.Commit())
+
+	err = db.Migrate(database)
+	require.NoError(t, err)
+
+	var missing int
+	require.NoError(t, database.QueryRow(`SELECT COUNT(*) FROM entries WHERE word_count IS NULL`).Scan(&missing))
+	require.Zero(t, missing)
+
+	var count int
+	require.NoError(t, database.QueryRow(`SELECT word_count FROM entries WHERE id = 1200`).Scan(&count))
+	require.Equal(t, 3, count)
+	require.NoError(t, database.QueryRow(`SELECT word_count FROM entries WHERE id = 7`).Scan(&count))
+	require.Equal(t, 4, count, "the longer of content and readable content wins")
+	require.NoError(t, database.QueryRow(`SELECT word_count FROM entries WHERE id = 8`).Scan(&count))
+	require.Zero(t, count)
+}
+
+func TestMigrate_FolderUniqueName_MergesDuplicateFolders(t *testing.T) {
+	database, err := db.Open(filepath.Join(t.TempDir(), "gist.db"))
+	require.NoError(t, err)
+	defer database.Close()
+
+	// Simulate a database from before folder names were unique.
+	_, err = database.Exec(`DROP INDEX idx_folders_parent_name; DROP TABLE schema_migrations`)
+	require.NoError(t, err)
+
+	_, err = database.Exec(`
+		INSERT INTO folders (id, name, parent_id, created_at, updated_at, deleted_at) VALUES
+		(1, 'Tech', NULL, '2025-01-01T00:00:00Z', '2025-01-01T00:00:00Z', NULL),
+		(2, 'Tech', NULL, '2025-02-01T00:00:00Z', '2025-02-01T00:00:00Z', NULL),
+		(3, 'Go', 1, '2025-01-01T00:00:00Z', '2025-01-01T00:00:00Z', NULL),
+		(4, 'Go', 2, '2025-02-01T00:00:00Z', '2025-02-01T00:00:00Z', NULL),
+		(5, 'Rust', 2, '2025-02-01T00:00:00Z', '2025-02-01T00:00:00Z', NULL),
+		(6, 'Tech', NULL, '2025-03-01T00:00:00Z', '2025-03-01T00:00:00Z', '2025-03-02T00:00:00Z');
+		INSERT INTO feeds (id, folder_id, title, url, canonical_url, created_at, updated_at) VALUES
+		(11, 2, 'a', 'https://a.example.com/rss', 'https://a.example.com/rss', '2025-02-01T00:00:00Z', '2025-02-01T00:00:00Z'),
+		(12, 4, 'b', 'https://b.example.com/rss', 'https://b.example.com/rss', '2025-02-01T00:00:00Z', '2025-02-01T00:00:00Z');
+	`)
+	require.NoError(t, err)
+
+	require.NoError(t, db.Migrate(database))
+
+	rows, err := database.Query(`SELECT id FROM folders ORDER BY id`)
+	require.NoError(t, err)
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		require.NoError(t, rows.Scan(&id))
+		ids = append(ids, id)
+	}
+	require.NoError(t, rows.Err())
+	rows.Close()
+	// The deleted folder is left alone; twins at both levels are merged
+	require.Equal(t, []int64{1, 3, 5, 6}, ids)
+
+	var parentID int64
+	require.NoError(t, database.QueryRow(`SELECT parent_id FROM folders WHERE id = 5`).Scan(&parentID))
+	require.Equal(t, int64(1), parentID)
+
+	var folderID int64
+	require.NoError(t, database.QueryRow(`SELECT folder_id FROM feeds WHERE id = 11`).Scan(&folderID))
+	require.Equal(t, int64(1), folderID)
+	require.NoError(t, database.QueryRow(`SELECT folder_id FROM feeds WHERE id = 12`).Scan(&folderID))
+	require.Equal(t, int64(3), folderID)
+
+	// Same-named siblings are now rejected, top level included.
+	_, err = database.Exec(`INSERT INTO folders (id, name, created_at, updated_at) VALUES (7, 'Tech', '2025-04-01T00:00:00Z', '2025-04-01T00:00:00Z')`)
+	require.Error(t, err)
+	_, err = database.Exec(`INSERT INTO folders (id, name, parent_id, created_at, updated_at) VALUES (8, 'Go', 1, '2025-04-01T00:00:00Z', '2025-04-01T00:00:00Z')`)
+	require.Error(t, err)
+
+	// Migration should be idempotent.
+	require.NoError(t, db.Migrate(database))
+}
+
+func TestMigrate_FeedCustomTitle_KeepsExistingTitles(t *testing.T) {
+	database, err := db.Open(filepath.Join(t.TempDir(), "gist.db"))
+	require.NoError(t, err)
+	defer database.Close()
+
+	// Simulate a database from before custom titles existed.
+	_, err = database.Exec(`ALTER TABLE feeds DROP COLUMN custom_title; DROP TABLE schema_migrations`)
+	require.NoError(t, err)
+	_, err = database.Exec(`
+		INSERT INTO feeds (id, title, url, canonical_url, created_at, updated_at) VALUES
+		(1, 'Renamed', 'https://example.com/rss', 'https://example.com/rss', '2025-01-01T00:00:00Z', '2025-01-01T00:00:00Z'),
+		(2, 'https://example.org/rss', 'https://example.org/rss', 'https://example.org/rss', '2025-01-01T00:00:00Z', '2025-01-01T00:00:00Z');
+	`)
+	require.NoError(t, err)
+
+	require.NoError(t, db.Migrate(database))
+
+	var custom sql.NullString
+	require.NoError(t, database.QueryRow(`SELECT custom_title FROM feeds WHERE id = 1`).Scan(&custom))
+	require.True(t, custom.Valid)
+	require.Equal(t, "Renamed", custom.String)
+	require.NoError(t, database.QueryRow(`SELECT custom_title FROM feeds WHERE id = 2`).Scan(&custom))
+	require.False(t, custom.Valid, "a title that is only the URL was never a rename")
+
+	require.NoError(t, db.Migrate(database))
+}
+
+func TestMigrate_FeedNotModifiedTracking_BackfillsLastEntrySeen(t *testing.T) {
+	database, err := db.Open(filepath.Join(t.TempDir(), "gist.db"))
+	require.NoError(t, err)
+	defer database.Close()
+
+	// Simulate a database from before not-modified tracking existed.
+	_, err = database.Exec(`ALTER TABLE feeds DROP COLUMN not_modified_streak; ALTER TABLE feeds DROP COLUMN last_entry_seen_at; ALTER TABLE feeds DROP COLUMN suspect_caching_at; DROP TABLE schema_migrations`)
+	require.NoError(t, err)
+	_, err = database.Exec(`
+		INSERT INTO feeds (id, title, url, canonical_url, created_at, updated_at) VALUES
+		(1, 'Feed', 'https://example.com/rss', 'https://example.com/rss', '2025-01-01T00:00:00Z', '2025-01-01T00:00:00Z'),
+		(2, 'Empty', 'https://example.org/rss', 'https://example.org/rss', '2025-01-01T00:00:00Z', '2025-01-01T00:00:00Z');
+		INSERT INTO entries (id, feed_id, url, hash, published_at, created_at, updated_at) VALUES
+		(1, 1, 'https://example.com/1', 'h1', '2025-03-01T08:00:00.5Z', '2025-03-01T08:00:00Z', '2025-03-01T08:00:00Z'),
+		(2, 1, 'https://example.com/2', 'h2', '2025-02-01T08:00:00Z', '2025-02-01T08:00:00Z', '2025-02-01T08:00:00Z'),
+		(3, 1, 'https://example.com/3', 'h3', '2999-01-01T00:00:00Z', '2025-02-01T08:00:00Z', '2025-02-01T08:00:00Z');
+	`)
+	require.NoError(t, err)
+
+	require.NoError(t, db.Migrate(database))
+
+	var seen sql.NullString
+	require.NoError(t, database.QueryRow(`SELECT last_entry_seen_at FROM feeds WHERE id = 1`).Scan(&seen))
+	require.Equal(t, "2025-03-01T08:00:00Z", seen.String, "future dates are ignored")
+	require.NoError(t, database.QueryRow(`SELECT last_entry_seen_at FROM feeds WHERE id = 2`).Scan(&seen))
+	require.False(t, seen.Valid)
+
+	require.NoError(t, db.Migrate(database))
+}
+
+func TestMigrate_RecordsMigrations(t *testing.T) {
+	database, err := db.Open(filepath.Join(t.TempDir(), "gist.db"))
+	require.NoError(t, err)
+	defer database.Close()
+
+	var count, latest int
+	require.NoError(t, database.QueryRow(`SELECT COUNT(*), MAX(version) FROM schema_migrations`).Scan(&count, &latest))
+	require.Equal(t, latest, count)
+
+	// A recorded migration is not run again
+	_, err = database.Exec(`DROP INDEX idx_entries_starred`)
+	require.NoError(t, err)
+	require.NoError(t, db.Migrate(database))
+
+	var indexes int
+	require.NoError(t, database.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'index' AND name = 'idx_entries_starred'`).Scan(&indexes))
+	require.Zero(t, indexes)
+}
+
+func TestMigrate_InstallWithoutVersionTable_RecordsAppliedMigrations(t *testing.T) {
+	database, err := db.Open(filepath.Join(t.TempDir(), "gist.db"))
+	require.NoError(t, err)
+	defer database.Close()
+
+	var latest int
+	require.NoError(t, database.QueryRow(`SELECT MAX(version) FROM schema_migrations`).Scan(&latest))
+
+	_, err = database.Exec(`
+		DROP TABLE schema_migrations;
+		INSERT INTO feeds (id, title, url, canonical_url, created_at, updated_at) VALUES
+		(1, 'Upstream', 'https://example.com/rss', 'https://example.com/rss', '2025-01-01T00:00:00Z', '2025-01-01T00:00:00Z');
+	`)
+	require.NoError(t, err)
+
+	require.NoError(t, db.Migrate(database))
+
+	var count int
+	require.NoError(t, database.QueryRow(`SELECT COUNT(*) FROM schema_migrations`).Scan(&count))
+	require.Equal(t, latest, count)
+
+	// Migration 46 would have copied the title had it run again
+	var custom sql.NullString
+	require.NoError(t, database.QueryRow(`SELECT custom_title FROM feeds WHERE id = 1`).Scan(&custom))
+	require.False(t, custom.Valid)
+}
+
+func TestMigrate_FailedMigrationKeepsEarlierOnes(t *testing.T) {
+	database, err := db.Open(filepath.Join(t.TempDir(), "gist.db"))
+	require.NoError(t, err)
+	defer database.Close()
+
+	// A table squatting on the name of migration 45's index makes it fail
+	_, err = database.Exec(`
+		DROP INDEX idx_entries_feed_id_url;
+		DELETE FROM schema_migrations WHERE version >= 45;
+		CREATE TABLE idx_entries_feed_id_url (id INTEGER);
+	`)
+	require.NoError(t, err)
+
+	err = db.Migrate(database)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "migration 45")
+
+	var latest int
+	require.NoError(t, database.QueryRow(`SELECT MAX(version) FROM schema_migrations`).Scan(&latest))
+	require.Equal(t, 44, latest)
+
+	_, err = database.Exec(`DROP TABLE idx_entries_feed_id_url`)
+	require.NoError(t, err)
+	require.NoError(t, db.Migrate(database))
+	require.NoError(t, database.QueryRow(`SELECT MAX(version) FROM schema_migrations`).Scan(&latest))
+	require.Greater(t, latest, 45)
+}
+
+func TestMigrate_ConcurrentOpensWaitForEachOther(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "gist.db")
+
+	var wg sync.WaitGroup
+	errs := make([]error, 4)
+	for i := range errs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			database, err := db.Open(path)
+			if err == nil {
+				_ = database.Close()
+			}
+			errs[i] = err
+		}()
+	}
+	wg.Wait()
+	for _, err := range errs {
+		require.NoError(t, err)
+	}
+
+	database, err := db.Open(path)
+	require.NoError(t, err)
+	defer database.Close()
+	var count, latest int
+	require.NoError(t, database.QueryRow(`SELECT COUNT(*), MAX(version) FROM schema_migrations`).Scan(&count, &latest))
+	require.Equal(t, latest, count)
+}
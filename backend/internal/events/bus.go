@@ -0,0 +1,157 @@
+package events
+
+import (
+	"strconv"
+	"sync"
+)
+
+// Event types published to GET /events. Payloads only carry ids and counts;
+// clients refetch whatever they display.
+const (
+	RefreshStarted  = "refresh.started"
+	RefreshFinished = "refresh.finished"
+	EntriesNew      = "entries.new"
+	EntriesRead     = "entries.read"
+	EntryStarred    = "entry.starred"
+	EntryReadable   = "entry.readable"
+	FeedCreated     = "feed.created"
+	FeedUpdated     = "feed.updated"
+	FeedDeleted     = "feed.deleted"
+	FolderCreated   = "folder.created"
+	FolderUpdated   = "folder.updated"
+	FolderDeleted   = "folder.deleted"
+)
+
+// SubscriberBuffer is how many events a subscriber may fall behind before it is evicted.
+const SubscriberBuffer = 64
+
+type Event struct {
+	Type string
+	// Data is marshalled as the JSON payload of the event; nil sends {}.
+	Data any
+}
+
+// RefreshData describes a refresh run: Count feeds were refreshed, or are about to be.
+type RefreshData struct {
+	Count int `json:"count"`
+}
+
+// EntriesNewData reports entries inserted into one feed.
+type EntriesNewData struct {
+	FeedID int64 `json:"feedId,string"`
+	Count  int   `json:"count"`
+}
+
+// EntriesReadData reports a read state change. EntryIDs is set for explicit
+// entries; otherwise FeedID, FolderID and ContentType give the scope of a
+// mark-all, all empty meaning every entry.
+type EntriesReadData struct {
+	EntryIDs    []string `json:"entryIds,omitempty"`
+	FeedID      *int64   `json:"feedId,omitempty,string"`
+	FolderID    *int64   `json:"folderId,omitempty,string"`
+	ContentType *string  `json:"contentType,omitempty"`
+	Read        bool     `json:"read"`
+}
+
+// IDs formats snowflake ids for EntriesReadData.EntryIDs.
+func IDs(ids []int64) []string {
+	out := make([]string, len(ids))
+	for i, id := range ids {
+		out[i] = strconv.FormatInt(id, 10)
+	}
+	return out
+}
+
+type EntryStarredData struct {
+	EntryID int64 `json:"entryId,string"`
+	Starred bool  `json:"starred"`
+}
+
+// EntryReadableData reports a finished readable content extraction: Status is
+// "ready" once it is cached, or "failed" with Error.
+type EntryReadableData struct {
+	EntryID int64  `json:"entryId,string"`
+	Status  string `json:"status"`
+	Error   string `json:"error,omitempty"`
+}
+
+type FeedData struct {
+	FeedID int64 `json:"feedId,string"`
+}
+
+type FolderData struct {
+	FolderID int64 `json:"folderId,string"`
+}
+
+// Bus fans events out to subscribers. Publish never blocks: a subscriber whose
+// buffer is full is evicted, its channel closed, and is expected to reconnect
+// and resync.
+type Bus struct {
+	mu          sync.Mutex
+	subscribers map[chan Event]struct{}
+	buffer      int
+	closed      bool
+}
+
+func NewBus(buffer int) *Bus {
+	return &Bus{subscribers: make(map[chan Event]struct{}), buffer: buffer}
+}
+
+// Subscribe returns a channel receiving every event published from now on, and
+// a func that unsubscribes. The channel is closed on eviction or Close.
+func (b *Bus) Subscribe() (<-chan Event, func()) {
+	ch := make(chan Event, b.buffer)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		close(ch)
+		return ch, func() {}
+	}
+	b.subscribers[ch] = struct{}{}
+	return ch, func() { b.remove(ch) }
+}
+
+// Publish sends event to every subscriber; a nil Bus drops it.
+func (b *Bus) Publish(event Event) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for ch := range b.subscribers {
+		select {
+		case ch <- event:
+		default:
+			delete(b.subscribers, ch)
+			close(ch)
+		}
+	}
+}
+
+// Subscribers returns the number of connected subscribers.
+func (b *Bus) Subscribers() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.subscribers)
+}
+
+// Close ends every subscription; later subscribers get a closed channel and
+// events published afterwards are dropped.
+func (b *Bus) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+	for ch := range b.subscribers {
+		delete(b.subscribers, ch)
+		close(ch)
+	}
+}
+
+func (b *Bus) remove(ch chan Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if _, ok := b.subscribers[ch]; ok {
+		delete(b.subscribers, ch)
+		close(ch)
+	}
+}
@@ -0,0 +1,74 @@
+package events_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"gist/backend/internal/events"
+)
+
+func TestBus_FansOutToEverySubscriber(t *testing.T) {
+	bus := events.NewBus(4)
+	first, unsubscribeFirst := bus.Subscribe()
+	defer unsubscribeFirst()
+	second, unsubscribeSecond := bus.Subscribe()
+	defer unsubscribeSecond()
+
+	bus.Publish(events.Event{Type: events.FeedCreated, Data: events.FeedData{FeedID: 1}})
+
+	require.Equal(t, events.FeedCreated, (<-first).Type)
+	require.Equal(t, events.FeedCreated, (<-second).Type)
+}
+
+func TestBus_EvictsSlowSubscriber(t *testing.T) {
+	bus := events.NewBus(2)
+	slow, unsubscribeSlow := bus.Subscribe()
+	defer unsubscribeSlow()
+	fast, unsubscribeFast := bus.Subscribe()
+	defer unsubscribeFast()
+
+	for i := 0; i < 3; i++ {
+		bus.Publish(events.Event{Type: events.RefreshStarted})
+		<-fast
+	}
+
+	// The buffered events are still delivered, then the channel is closed
+	<-slow
+	<-slow
+	_, ok := <-slow
+	require.False(t, ok)
+	require.Equal(t, 1, bus.Subscribers())
+}
+
+func TestBus_Unsubscribe(t *testing.T) {
+	bus := events.NewBus(1)
+	ch, unsubscribe := bus.Subscribe()
+	unsubscribe()
+	unsubscribe()
+
+	_, ok := <-ch
+	require.False(t, ok)
+	require.Zero(t, bus.Subscribers())
+	bus.Publish(events.Event{Type: events.RefreshStarted})
+}
+
+func TestBus_Close(t *testing.T) {
+	bus := events.NewBus(1)
+	ch, unsubscribe := bus.Subscribe()
+	defer unsubscribe()
+
+	bus.Close()
+	_, ok := <-ch
+	require.False(t, ok)
+
+	late, _ := bus.Subscribe()
+	_, ok = <-late
+	require.False(t, ok)
+	bus.Publish(events.Event{Type: events.RefreshStarted})
+}
+
+func TestBus_NilDropsEvents(t *testing.T) {
+	var bus *events.Bus
+	require.NotPanics(t, func() { bus.Publish(events.Event{Type: events.RefreshStarted}) })
+}
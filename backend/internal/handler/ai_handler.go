@@ -1,20 +1,22 @@
 package handler
 
 import (
-	"encoding/json"
-	"fmt"
+	"errors"
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/labstack/echo/v4"
 
 	"gist/backend/pkg/logger"
+	"gist/backend/internal/model"
 	"gist/backend/internal/service"
 )
 
 type AIHandler struct {
-	service service.AIService
+	service       service.AIService
+	autoTranslate service.AutoTranslateService
 }
 
 // Request/Response types
@@ -24,6 +26,8 @@ type summarizeRequest struct {
 	Content       string `json:"content"`
 	Title         string `json:"title"`
 	IsReadability bool   `json:"isReadability"`
+	// Language overrides the configured summary language, e.g. "en-US".
+	Language string `json:"language,omitempty"`
 }
 
 type summarizeResponse struct {
@@ -36,6 +40,8 @@ type translateRequest struct {
 	Content       string `json:"content"`
 	Title         string `json:"title"`
 	IsReadability bool   `json:"isReadability"`
+	// Language overrides the configured summary language, e.g. "en-US".
+	Language string `json:"language,omitempty"`
 }
 
 type translateResponse struct {
@@ -43,24 +49,44 @@ type translateResponse struct {
 	Cached  bool   `json:"cached"`
 }
 
-func NewAIHandler(service service.AIService) *AIHandler {
-	return &AIHandler{service: service}
+// Caps on the text of AI requests. Longer text would not fit the context of
+// the models in use anyway, so it is refused rather than sent.
+const (
+	maxAIContentBytes = 512 << 10
+	maxAITitleBytes   = 4 << 10
+	maxAISummaryBytes = 16 << 10
+)
+
+// validLanguage accepts an empty override (use the configured language) or a supported code.
+func validLanguage(language string) bool {
+	return language == "" || service.IsSupportedLanguage(language)
+}
+
+// NewAIHandler reports the background list translation worker through
+// autoTranslate, which may be nil.
+func NewAIHandler(service service.AIService, autoTranslate service.AutoTranslateService) *AIHandler {
+	return &AIHandler{service: service, autoTranslate: autoTranslate}
 }
 
 func (h *AIHandler) RegisterRoutes(g *echo.Group) {
 	g.POST("/ai/summarize", h.Summarize)
+	g.POST("/ai/digest", h.Digest)
 	g.POST("/ai/translate", h.Translate)
 	g.POST("/ai/translate/batch", h.TranslateBatch)
+	g.POST("/ai/translate/feeds", h.TranslateFeeds)
 	g.DELETE("/ai/cache", h.ClearCache)
+	g.GET("/ai/usage", h.GetUsage)
+	g.GET("/ai/auto-translate/status", h.AutoTranslateStatus)
 }
 
 // Summarize generates an AI summary of the content.
 // @Summary Generate AI summary
-// @Description Generate an AI summary of the article content. Returns cached result if available, otherwise streams the response.
+// @Description Generate an AI summary of the article content. Returns cached result if available, otherwise streams the response. With Accept: application/x-ndjson the stream is sent as NDJSON lines of {"text"} chunks and an {"error"} on failure, for proxies that buffer event streams.
 // @Tags ai
 // @Accept json
 // @Produce json
 // @Produce text/event-stream
+// @Produce application/x-ndjson
 // @Param request body summarizeRequest true "Summarize request"
 // @Success 200 {object} summarizeResponse "Cached summary"
 // @Failure 400 {object} errorResponse
@@ -70,25 +96,38 @@ func (h *AIHandler) Summarize(c echo.Context) error {
 	var req summarizeRequest
 	if err := c.Bind(&req); err != nil {
 		logger.Debug("ai summarize invalid request", "module", "handler", "action", "request", "resource", "ai", "result", "failed", "error", err)
-		return c.JSON(http.StatusBadRequest, errorResponse{Error: "invalid request"})
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid request")
 	}
 
 	if req.Content == "" {
 		logger.Debug("ai summarize missing content", "module", "handler", "action", "request", "resource", "ai", "result", "failed")
-		return c.JSON(http.StatusBadRequest, errorResponse{Error: "content is required"})
+		return invalidField(c, "content", "content is required")
+	}
+	if len(req.Content) > maxAIContentBytes {
+		logger.Debug("ai summarize content too long", "module", "handler", "action", "request", "resource", "ai", "result", "failed", "bytes", len(req.Content))
+		return fieldTooLong(c, "content", maxAIContentBytes)
+	}
+	if len(req.Title) > maxAITitleBytes {
+		logger.Debug("ai summarize title too long", "module", "handler", "action", "request", "resource", "ai", "result", "failed", "bytes", len(req.Title))
+		return fieldTooLong(c, "title", maxAITitleBytes)
+	}
+
+	if !validLanguage(req.Language) {
+		logger.Debug("ai summarize unsupported language", "module", "handler", "action", "request", "resource", "ai", "result", "failed", "language", req.Language)
+		return invalidField(c, "language", "unsupported language")
 	}
 
 	// Parse entry ID
 	entryID, err := strconv.ParseInt(req.EntryID, 10, 64)
 	if err != nil {
 		logger.Debug("ai summarize invalid entry id", "module", "handler", "action", "request", "resource", "ai", "result", "failed", "entry_id", req.EntryID)
-		return c.JSON(http.StatusBadRequest, errorResponse{Error: "invalid entry ID"})
+		return invalidField(c, "entryId", "invalid entry ID")
 	}
 
 	ctx := c.Request().Context()
 
 	// Check cache first
-	cached, err := h.service.GetCachedSummary(ctx, entryID, req.IsReadability)
+	cached, err := h.service.GetCachedSummary(ctx, entryID, req.IsReadability, req.Language)
 	if err != nil {
 		logger.Warn("ai summarize cache lookup failed", "module", "handler", "action", "fetch", "resource", "ai", "result", "failed", "entry_id", entryID, "error", err)
 	}
@@ -101,19 +140,15 @@ func (h *AIHandler) Summarize(c echo.Context) error {
 	}
 
 	// Generate summary with streaming
-	textCh, errCh, err := h.service.Summarize(ctx, entryID, req.Content, req.Title, req.IsReadability)
+	textCh, errCh, err := h.service.Summarize(ctx, entryID, req.Content, req.Title, req.IsReadability, req.Language)
 	if err != nil {
 		logger.Error("ai summarize start failed", "module", "handler", "action", "fetch", "resource", "ai", "result", "failed", "entry_id", entryID, "error", err)
-		return c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return writeServiceError(c, err)
 	}
 
 	logger.Info("ai summarize started", "module", "handler", "action", "fetch", "resource", "ai", "result", "ok", "entry_id", entryID)
 
-	// Set headers for SSE
-	c.Response().Header().Set("Content-Type", "text/event-stream")
-	c.Response().Header().Set("Cache-Control", "no-cache")
-	c.Response().Header().Set("Connection", "keep-alive")
-	c.Response().WriteHeader(http.StatusOK)
+	stream := negotiateStream(c)
 
 	var fullText strings.Builder
 
@@ -128,8 +163,7 @@ func (h *AIHandler) Summarize(c echo.Context) error {
 					if err != nil {
 						logger.Error("ai summarize stream error", "module", "handler", "action", "fetch", "resource", "ai", "result", "failed", "entry_id", entryID, "error", err)
 						// Write error to stream
-						fmt.Fprintf(c.Response(), "event: error\ndata: %s\n\n", err.Error())
-						c.Response().Flush()
+						_ = stream.Error(err.Error())
 						return nil
 					}
 
@@ -138,7 +172,7 @@ func (h *AIHandler) Summarize(c echo.Context) error {
 
 				// Save to cache if we got content
 				if fullText.Len() > 0 {
-					if err := h.service.SaveSummary(ctx, entryID, req.IsReadability, fullText.String()); err != nil {
+					if err := h.service.SaveSummary(ctx, entryID, req.IsReadability, req.Language, fullText.String()); err != nil {
 						logger.Warn("ai summarize cache save failed", "module", "handler", "action", "save", "resource", "ai", "result", "failed", "entry_id", entryID, "error", err)
 					} else {
 						logger.Info("ai summarize cached", "module", "handler", "action", "save", "resource", "ai", "result", "ok", "entry_id", entryID)
@@ -151,11 +185,9 @@ func (h *AIHandler) Summarize(c echo.Context) error {
 
 			fullText.WriteString(text)
 
-			// Write chunk to stream (plain text, not SSE format for simpler client handling)
-			if _, err := c.Response().Write([]byte(text)); err != nil {
+			if err := stream.Text(text); err != nil {
 				return nil
 			}
-			c.Response().Flush()
 
 		case <-ctx.Done():
 			logger.Warn("ai summarize cancelled", "module", "handler", "action", "fetch", "resource", "ai", "result", "cancelled", "entry_id", entryID)
@@ -164,6 +196,162 @@ func (h *AIHandler) Summarize(c echo.Context) error {
 	}
 }
 
+type digestRequest struct {
+	// Exactly one of FolderID and FeedID selects the entries.
+	FolderID string `json:"folderId,omitempty"`
+	FeedID   string `json:"feedId,omitempty"`
+	// Since and Until bound the window as RFC 3339 times, truncated to the
+	// minute. Until defaults to now.
+	Since string `json:"since" example:"2026-01-01T00:00:00Z"`
+	Until string `json:"until,omitempty"`
+	// Language overrides the configured summary language, e.g. "en-US".
+	Language string `json:"language,omitempty"`
+}
+
+type digestResponse struct {
+	Digest  string `json:"digest"`
+	Cached  bool   `json:"cached"`
+	Entries int    `json:"entries"`
+}
+
+// maxDigestWindow is the longest window a digest may cover.
+const maxDigestWindow = 31 * 24 * time.Hour
+
+// Digest generates an AI digest of the unread entries of a folder or feed.
+// @Summary Generate AI digest
+// @Description Digest the unread entries of a folder or feed published in a window, using their cached summaries or the start of their text, for up to the 100 newest. Returns the cached digest of the same window end and language if available, a canned "nothing new" without calling the provider when there are no unread entries, and otherwise streams the digest as plain text with the entry count in the X-Digest-Entries header, or as NDJSON {"text"} and {"error"} lines with Accept: application/x-ndjson.
+// @Tags ai
+// @Accept json
+// @Produce json
+// @Produce text/event-stream
+// @Produce application/x-ndjson
+// @Param request body digestRequest true "Digest request"
+// @Success 200 {object} digestResponse "Cached or empty digest"
+// @Failure 400 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /ai/digest [post]
+func (h *AIHandler) Digest(c echo.Context) error {
+	var req digestRequest
+	if err := c.Bind(&req); err != nil {
+		logger.Debug("ai digest invalid request", "module", "handler", "action", "request", "resource", "ai", "result", "failed", "error", err)
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid request")
+	}
+
+	var params service.AIDigestParams
+	switch {
+	case req.FolderID != "" && req.FeedID != "":
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "only one of folderId and feedId is allowed")
+	case req.FolderID != "":
+		params.Scope = model.AIDigestScopeFolder
+		params.ScopeID, _ = strconv.ParseInt(req.FolderID, 10, 64)
+	case req.FeedID != "":
+		params.Scope = model.AIDigestScopeFeed
+		params.ScopeID, _ = strconv.ParseInt(req.FeedID, 10, 64)
+	default:
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "folderId or feedId is required")
+	}
+	if params.ScopeID <= 0 {
+		logger.Debug("ai digest invalid scope id", "module", "handler", "action", "request", "resource", "ai", "result", "failed", "scope", params.Scope)
+		return invalidField(c, params.Scope+"Id", "invalid "+params.Scope+" ID")
+	}
+
+	since, err := time.Parse(time.RFC3339, req.Since)
+	if err != nil {
+		logger.Debug("ai digest invalid since", "module", "handler", "action", "request", "resource", "ai", "result", "failed", "since", req.Since)
+		return invalidField(c, "since", "invalid since")
+	}
+	until := time.Now()
+	if req.Until != "" {
+		until, err = time.Parse(time.RFC3339, req.Until)
+		if err != nil {
+			logger.Debug("ai digest invalid until", "module", "handler", "action", "request", "resource", "ai", "result", "failed", "until", req.Until)
+			return invalidField(c, "until", "invalid until")
+		}
+	}
+	// Whole minutes let repeated requests for "until now" share a cached digest
+	params.Start = since.UTC().Truncate(time.Minute)
+	params.End = until.UTC().Truncate(time.Minute)
+	if !params.Start.Before(params.End) || params.End.Sub(params.Start) > maxDigestWindow {
+		logger.Debug("ai digest invalid window", "module", "handler", "action", "request", "resource", "ai", "result", "failed", "since", params.Start, "until", params.End)
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "since must be before until and at most 31 days earlier")
+	}
+
+	if !validLanguage(req.Language) {
+		logger.Debug("ai digest unsupported language", "module", "handler", "action", "request", "resource", "ai", "result", "failed", "language", req.Language)
+		return invalidField(c, "language", "unsupported language")
+	}
+	params.Language = req.Language
+
+	ctx := c.Request().Context()
+
+	cached, err := h.service.GetCachedDigest(ctx, params)
+	if err != nil {
+		logger.Warn("ai digest cache lookup failed", "module", "handler", "action", "fetch", "resource", "ai", "result", "failed", "scope", params.Scope, "scope_id", params.ScopeID, "error", err)
+	}
+	if cached != nil {
+		logger.Info("ai digest cache hit", "module", "handler", "action", "fetch", "resource", "ai", "result", "ok", "scope", params.Scope, "scope_id", params.ScopeID, "cache", "hit")
+		return c.JSON(http.StatusOK, digestResponse{
+			Digest:  cached.Digest,
+			Cached:  true,
+			Entries: cached.Entries,
+		})
+	}
+
+	stream, err := h.service.Digest(ctx, params)
+	if err != nil {
+		logger.Error("ai digest start failed", "module", "handler", "action", "fetch", "resource", "ai", "result", "failed", "scope", params.Scope, "scope_id", params.ScopeID, "error", err)
+		return writeServiceError(c, err)
+	}
+	if stream.Entries == 0 {
+		logger.Info("ai digest nothing new", "module", "handler", "action", "fetch", "resource", "ai", "result", "ok", "scope", params.Scope, "scope_id", params.ScopeID)
+		return c.JSON(http.StatusOK, digestResponse{Digest: service.AIDigestNothingNew})
+	}
+
+	logger.Info("ai digest started", "module", "handler", "action", "fetch", "resource", "ai", "result", "ok", "scope", params.Scope, "scope_id", params.ScopeID, "entries", stream.Entries)
+
+	c.Response().Header().Set("X-Digest-Entries", strconv.Itoa(stream.Entries))
+	out := negotiateStream(c)
+
+	var fullText strings.Builder
+
+	for {
+		select {
+		case text, ok := <-stream.Text:
+			if !ok {
+				select {
+				case err := <-stream.Errors:
+					if err != nil {
+						logger.Error("ai digest stream error", "module", "handler", "action", "fetch", "resource", "ai", "result", "failed", "scope", params.Scope, "scope_id", params.ScopeID, "error", err)
+						_ = out.Error(err.Error())
+						return nil
+					}
+
+				default:
+				}
+
+				if fullText.Len() > 0 {
+					if err := h.service.SaveDigest(ctx, params, stream.Entries, fullText.String()); err != nil {
+						logger.Warn("ai digest cache save failed", "module", "handler", "action", "save", "resource", "ai", "result", "failed", "scope", params.Scope, "scope_id", params.ScopeID, "error", err)
+					}
+				}
+
+				logger.Info("ai digest completed", "module", "handler", "action", "fetch", "resource", "ai", "result", "ok", "scope", params.Scope, "scope_id", params.ScopeID)
+				return nil
+			}
+
+			fullText.WriteString(text)
+
+			if err := out.Text(text); err != nil {
+				return nil
+			}
+
+		case <-ctx.Done():
+			logger.Warn("ai digest cancelled", "module", "handler", "action", "fetch", "resource", "ai", "result", "cancelled", "scope", params.Scope, "scope_id", params.ScopeID)
+			return nil
+		}
+	}
+}
+
 // translateInitEvent represents the initial event with all original blocks.
 type translateInitEvent struct {
 	Blocks []translateBlockData `json:"blocks"`
@@ -177,8 +365,11 @@ type translateBlockData struct {
 
 // translateBlockEvent represents an SSE event for translated block.
 type translateBlockEvent struct {
-	Index int    `json:"index"`
-	HTML  string `json:"html"`
+	Index int `json:"index"`
+	// EndIndex is the last block HTML replaces; blocks after Index up to it
+	// were translated together and their translation is all in HTML
+	EndIndex int    `json:"endIndex"`
+	HTML     string `json:"html"`
 }
 
 // translateDoneEvent represents the completion of translation.
@@ -193,11 +384,12 @@ type translateErrorEvent struct {
 
 // Translate generates an AI translation of the content.
 // @Summary Generate AI translation
-// @Description Translate article content. Returns cached result if available, otherwise streams block translations via SSE.
+// @Description Translate article content. Returns cached result if available, otherwise streams block translations via SSE, or as one NDJSON line per event with Accept: application/x-ndjson.
 // @Tags ai
 // @Accept json
 // @Produce json
 // @Produce text/event-stream
+// @Produce application/x-ndjson
 // @Param request body translateRequest true "Translate request"
 // @Success 200 {object} translateResponse
 // @Failure 400 {object} errorResponse
@@ -207,25 +399,38 @@ func (h *AIHandler) Translate(c echo.Context) error {
 	var req translateRequest
 	if err := c.Bind(&req); err != nil {
 		logger.Debug("ai translate invalid request", "module", "handler", "action", "request", "resource", "ai", "result", "failed", "error", err)
-		return c.JSON(http.StatusBadRequest, errorResponse{Error: "invalid request"})
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid request")
 	}
 
 	if req.Content == "" {
 		logger.Debug("ai translate missing content", "module", "handler", "action", "request", "resource", "ai", "result", "failed")
-		return c.JSON(http.StatusBadRequest, errorResponse{Error: "content is required"})
+		return invalidField(c, "content", "content is required")
+	}
+	if len(req.Content) > maxAIContentBytes {
+		logger.Debug("ai translate content too long", "module", "handler", "action", "request", "resource", "ai", "result", "failed", "bytes", len(req.Content))
+		return fieldTooLong(c, "content", maxAIContentBytes)
+	}
+	if len(req.Title) > maxAITitleBytes {
+		logger.Debug("ai translate title too long", "module", "handler", "action", "request", "resource", "ai", "result", "failed", "bytes", len(req.Title))
+		return fieldTooLong(c, "title", maxAITitleBytes)
+	}
+
+	if !validLanguage(req.Language) {
+		logger.Debug("ai translate unsupported language", "module", "handler", "action", "request", "resource", "ai", "result", "failed", "language", req.Language)
+		return invalidField(c, "language", "unsupported language")
 	}
 
 	// Parse entry ID
 	entryID, err := strconv.ParseInt(req.EntryID, 10, 64)
 	if err != nil {
 		logger.Debug("ai translate invalid entry id", "module", "handler", "action", "request", "resource", "ai", "result", "failed", "entry_id", req.EntryID)
-		return c.JSON(http.StatusBadRequest, errorResponse{Error: "invalid entry ID"})
+		return invalidField(c, "entryId", "invalid entry ID")
 	}
 
 	ctx := c.Request().Context()
 
 	// Check cache first
-	cached, err := h.service.GetCachedTranslation(ctx, entryID, req.IsReadability)
+	cached, err := h.service.GetCachedTranslation(ctx, entryID, req.IsReadability, req.Language)
 	if err != nil {
 		logger.Warn("ai translate cache lookup failed", "module", "handler", "action", "fetch", "resource", "ai", "result", "failed", "entry_id", entryID, "error", err)
 	}
@@ -238,19 +443,15 @@ func (h *AIHandler) Translate(c echo.Context) error {
 	}
 
 	// Start block translation
-	blockInfos, resultCh, errCh, err := h.service.TranslateBlocks(ctx, entryID, req.Content, req.Title, req.IsReadability)
+	blockInfos, resultCh, errCh, err := h.service.TranslateBlocks(ctx, entryID, req.Content, req.Title, req.IsReadability, req.Language)
 	if err != nil {
 		logger.Error("ai translate start failed", "module", "handler", "action", "fetch", "resource", "ai", "result", "failed", "entry_id", entryID, "error", err)
-		return c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return writeServiceError(c, err)
 	}
 
 	logger.Info("ai translate started", "module", "handler", "action", "fetch", "resource", "ai", "result", "ok", "entry_id", entryID)
 
-	// Set headers for SSE
-	c.Response().Header().Set("Content-Type", "text/event-stream")
-	c.Response().Header().Set("Cache-Control", "no-cache")
-	c.Response().Header().Set("Connection", "keep-alive")
-	c.Response().WriteHeader(http.StatusOK)
+	stream := negotiateStream(c)
 
 	// Send init event with all original blocks
 	initBlocks := make([]translateBlockData, len(blockInfos))
@@ -261,10 +462,9 @@ func (h *AIHandler) Translate(c echo.Context) error {
 			NeedTranslate: b.NeedTranslate,
 		}
 	}
-	initEvent := translateInitEvent{Blocks: initBlocks}
-	initData, _ := json.Marshal(initEvent)
-	fmt.Fprintf(c.Response(), "data: %s\n\n", initData)
-	c.Response().Flush()
+	if err := stream.JSON(translateInitEvent{Blocks: initBlocks}); err != nil {
+		return nil
+	}
 
 	// Stream the translation results
 	for {
@@ -272,27 +472,20 @@ func (h *AIHandler) Translate(c echo.Context) error {
 		case result, ok := <-resultCh:
 			if !ok {
 				// Channel closed, send done event
-				doneEvent := translateDoneEvent{Done: true}
-				data, _ := json.Marshal(doneEvent)
-				fmt.Fprintf(c.Response(), "data: %s\n\n", data)
-				c.Response().Flush()
+				_ = stream.JSON(translateDoneEvent{Done: true})
 				logger.Info("ai translate completed", "module", "handler", "action", "fetch", "resource", "ai", "result", "ok", "entry_id", entryID)
 				return nil
 			}
 
 			// Send translated block result
-			event := translateBlockEvent{Index: result.Index, HTML: result.HTML}
-			data, _ := json.Marshal(event)
-			fmt.Fprintf(c.Response(), "data: %s\n\n", data)
-			c.Response().Flush()
+			if err := stream.JSON(translateBlockEvent{Index: result.Index, EndIndex: result.EndIndex, HTML: result.HTML}); err != nil {
+				return nil
+			}
 
 		case err := <-errCh:
 			if err != nil {
 				logger.Error("ai translate stream error", "module", "handler", "action", "fetch", "resource", "ai", "result", "failed", "entry_id", entryID, "error", err)
-				errorEvent := translateErrorEvent{Error: err.Error()}
-				data, _ := json.Marshal(errorEvent)
-				fmt.Fprintf(c.Response(), "data: %s\n\n", data)
-				c.Response().Flush()
+				_ = stream.JSON(translateErrorEvent{Error: err.Error()})
 				// Continue to receive remaining results
 			}
 
@@ -310,6 +503,8 @@ type batchTranslateRequest struct {
 		Title   string `json:"title"`
 		Summary string `json:"summary"`
 	} `json:"articles"`
+	// Language overrides the configured summary language, e.g. "en-US".
+	Language string `json:"language,omitempty"`
 }
 
 // TranslateBatch translates multiple articles' titles and summaries.
@@ -327,18 +522,33 @@ func (h *AIHandler) TranslateBatch(c echo.Context) error {
 	var req batchTranslateRequest
 	if err := c.Bind(&req); err != nil {
 		logger.Debug("ai batch translate invalid request", "module", "handler", "action", "request", "resource", "ai", "result", "failed", "error", err)
-		return c.JSON(http.StatusBadRequest, errorResponse{Error: "invalid request"})
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid request")
 	}
 
 	if len(req.Articles) == 0 {
 		logger.Debug("ai batch translate missing articles", "module", "handler", "action", "request", "resource", "ai", "result", "failed")
-		return c.JSON(http.StatusBadRequest, errorResponse{Error: "articles is required"})
+		return invalidField(c, "articles", "articles is required")
 	}
 
 	// Limit batch size
 	if len(req.Articles) > 100 {
 		logger.Debug("ai batch translate too many articles", "module", "handler", "action", "request", "resource", "ai", "result", "failed", "count", len(req.Articles))
-		return c.JSON(http.StatusBadRequest, errorResponse{Error: "maximum 100 articles per batch"})
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "maximum 100 articles per batch")
+	}
+	for _, a := range req.Articles {
+		if len(a.Title) > maxAITitleBytes {
+			logger.Debug("ai batch translate title too long", "module", "handler", "action", "request", "resource", "ai", "result", "failed", "bytes", len(a.Title))
+			return fieldTooLong(c, "articles.title", maxAITitleBytes)
+		}
+		if len(a.Summary) > maxAISummaryBytes {
+			logger.Debug("ai batch translate summary too long", "module", "handler", "action", "request", "resource", "ai", "result", "failed", "bytes", len(a.Summary))
+			return fieldTooLong(c, "articles.summary", maxAISummaryBytes)
+		}
+	}
+
+	if !validLanguage(req.Language) {
+		logger.Debug("ai batch translate unsupported language", "module", "handler", "action", "request", "resource", "ai", "result", "failed", "language", req.Language)
+		return invalidField(c, "language", "unsupported language")
 	}
 
 	ctx := c.Request().Context()
@@ -354,19 +564,15 @@ func (h *AIHandler) TranslateBatch(c echo.Context) error {
 	}
 
 	// Start batch translation
-	resultCh, errCh, err := h.service.TranslateBatch(ctx, articles)
+	resultCh, errCh, err := h.service.TranslateBatch(ctx, articles, req.Language)
 	if err != nil {
 		logger.Error("ai batch translate start failed", "module", "handler", "action", "fetch", "resource", "ai", "result", "failed", "count", len(articles), "error", err)
-		return c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return writeServiceError(c, err)
 	}
 
 	logger.Info("ai batch translate started", "module", "handler", "action", "fetch", "resource", "ai", "result", "ok", "count", len(articles))
 
-	// Set headers for NDJSON streaming
-	c.Response().Header().Set("Content-Type", "application/x-ndjson")
-	c.Response().Header().Set("Cache-Control", "no-cache")
-	c.Response().Header().Set("Connection", "keep-alive")
-	c.Response().WriteHeader(http.StatusOK)
+	stream := startStream(c, mimeNDJSON)
 
 	// Stream the results
 	for {
@@ -378,11 +584,9 @@ func (h *AIHandler) TranslateBatch(c echo.Context) error {
 				return nil
 			}
 
-			// Send result as NDJSON
-			data, _ := json.Marshal(result)
-			c.Response().Write(data)
-			c.Response().Write([]byte("\n"))
-			c.Response().Flush()
+			if err := stream.JSON(result); err != nil {
+				return nil
+			}
 
 		case err := <-errCh:
 			if err != nil {
@@ -397,6 +601,81 @@ func (h *AIHandler) TranslateBatch(c echo.Context) error {
 	}
 }
 
+type translateFeedsRequest struct {
+	FeedIDs []string `json:"feedIds"`
+	// Language overrides the configured summary language, e.g. "en-US".
+	Language string `json:"language,omitempty"`
+}
+
+type feedTitleTranslationResponse struct {
+	FeedID string `json:"feedId"`
+	Title  string `json:"title"`
+	Cached bool   `json:"cached"`
+	// Unchanged means the title already looked like the target language.
+	Unchanged bool `json:"unchanged"`
+}
+
+// TranslateFeeds translates feed titles for the sidebar.
+// @Summary Translate feed titles
+// @Description Translate the titles of up to 500 feeds. Cached translations are reused while the feed title is unchanged; titles already in the target language are returned as-is. Feeds that fail to translate are left out.
+// @Tags ai
+// @Accept json
+// @Produce json
+// @Param request body translateFeedsRequest true "Feed title translate request"
+// @Success 200 {array} feedTitleTranslationResponse
+// @Failure 400 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /ai/translate/feeds [post]
+func (h *AIHandler) TranslateFeeds(c echo.Context) error {
+	var req translateFeedsRequest
+	if err := c.Bind(&req); err != nil {
+		logger.Debug("ai feed title translate invalid request", "module", "handler", "action", "request", "resource", "ai", "result", "failed", "error", err)
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid request")
+	}
+
+	if len(req.FeedIDs) == 0 {
+		logger.Debug("ai feed title translate missing feeds", "module", "handler", "action", "request", "resource", "ai", "result", "failed")
+		return invalidField(c, "feedIds", "feedIds is required")
+	}
+
+	if len(req.FeedIDs) > 500 {
+		logger.Debug("ai feed title translate too many feeds", "module", "handler", "action", "request", "resource", "ai", "result", "failed", "count", len(req.FeedIDs))
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "maximum 500 feeds per batch")
+	}
+
+	if !validLanguage(req.Language) {
+		logger.Debug("ai feed title translate unsupported language", "module", "handler", "action", "request", "resource", "ai", "result", "failed", "language", req.Language)
+		return invalidField(c, "language", "unsupported language")
+	}
+
+	feedIDs := make([]int64, 0, len(req.FeedIDs))
+	for _, raw := range req.FeedIDs {
+		id, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			logger.Debug("ai feed title translate invalid feed id", "module", "handler", "action", "request", "resource", "ai", "result", "failed", "feed_id", raw)
+			return invalidField(c, "feedIds", "invalid feed ID")
+		}
+		feedIDs = append(feedIDs, id)
+	}
+
+	results, err := h.service.TranslateFeedTitles(c.Request().Context(), feedIDs, req.Language)
+	if err != nil {
+		logger.Error("ai feed title translate failed", "module", "handler", "action", "fetch", "resource", "ai", "result", "failed", "count", len(feedIDs), "error", err)
+		return writeServiceError(c, err)
+	}
+
+	response := make([]feedTitleTranslationResponse, 0, len(results))
+	for _, r := range results {
+		response = append(response, feedTitleTranslationResponse{
+			FeedID:    idToString(r.FeedID),
+			Title:     r.Title,
+			Cached:    r.Cached,
+			Unchanged: r.Unchanged,
+		})
+	}
+	return c.JSON(http.StatusOK, response)
+}
+
 type clearCacheResponse struct {
 	Summaries        int64 `json:"summaries"`
 	Translations     int64 `json:"translations"`
@@ -417,7 +696,7 @@ func (h *AIHandler) ClearCache(c echo.Context) error {
 	summaries, translations, listTranslations, err := h.service.ClearAllCache(ctx)
 	if err != nil {
 		logger.Error("ai cache clear failed", "module", "handler", "action", "clear", "resource", "ai", "result", "failed", "error", err)
-		return c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return writeServiceError(c, err)
 	}
 
 	logger.Info("ai cache cleared", "module", "handler", "action", "clear", "resource", "ai", "result", "ok", "summaries", summaries, "translations", translations, "list_translations", listTranslations)
@@ -427,3 +706,126 @@ func (h *AIHandler) ClearCache(c echo.Context) error {
 		ListTranslations: listTranslations,
 	})
 }
+
+type aiUsageDayResponse struct {
+	Date             string  `json:"date" example:"2025-03-01"`
+	Operation        string  `json:"operation" example:"summarize"`
+	Calls            int     `json:"calls"`
+	PromptTokens     int     `json:"promptTokens"`
+	CompletionTokens int     `json:"completionTokens"`
+	Cost             float64 `json:"cost"`
+}
+
+type aiUsageResponse struct {
+	From             string               `json:"from"`
+	To               string               `json:"to"`
+	Days             []aiUsageDayResponse `json:"days"`
+	PromptTokens     int                  `json:"promptTokens"`
+	CompletionTokens int                  `json:"completionTokens"`
+	// Cost is an estimate in USD from the AI settings price table
+	Cost           float64  `json:"cost"`
+	UnpricedModels []string `json:"unpricedModels"`
+}
+
+// GetUsage returns recorded AI token usage.
+// @Summary Get AI usage
+// @Description Token usage per UTC day and operation (summarize, translate, list_translate, digest) with an estimated cost from the configured per-model prices. Usage the provider did not report is estimated from text length.
+// @Tags ai
+// @Produce json
+// @Param from query string false "First day as YYYY-MM-DD (default 29 days before to)"
+// @Param to query string false "Last day as YYYY-MM-DD (default today)"
+// @Success 200 {object} aiUsageResponse
+// @Failure 400 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /ai/usage [get]
+func (h *AIHandler) GetUsage(c echo.Context) error {
+	report, err := h.service.GetUsage(c.Request().Context(), service.AIUsageParams{
+		From: c.QueryParam("from"),
+		To:   c.QueryParam("to"),
+	})
+	if err != nil {
+		if errors.Is(err, service.ErrInvalid) {
+			return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid date range")
+		}
+		logger.Error("ai usage get failed", "module", "handler", "action", "list", "resource", "ai", "result", "failed", "error", err)
+		return writeServiceError(c, err)
+	}
+
+	resp := aiUsageResponse{
+		From:             report.From,
+		To:               report.To,
+		Days:             make([]aiUsageDayResponse, 0, len(report.Days)),
+		PromptTokens:     report.PromptTokens,
+		CompletionTokens: report.CompletionTokens,
+		Cost:             report.Cost,
+		UnpricedModels:   report.UnpricedModels,
+	}
+	if resp.UnpricedModels == nil {
+		resp.UnpricedModels = []string{}
+	}
+	for _, day := range report.Days {
+		resp.Days = append(resp.Days, aiUsageDayResponse{
+			Date:             day.Date,
+			Operation:        day.Operation,
+			Calls:            day.Calls,
+			PromptTokens:     day.PromptTokens,
+			CompletionTokens: day.CompletionTokens,
+			Cost:             day.Cost,
+		})
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+type autoTranslateRunResponse struct {
+	StartedAt  string `json:"startedAt"`
+	FinishedAt string `json:"finishedAt"`
+	// Entries counts the untranslated new entries collected
+	Entries int `json:"entries"`
+	// Skipped counts entries of feeds already in the target language and entries without text
+	Skipped    int    `json:"skipped"`
+	Translated int    `json:"translated"`
+	Failed     int    `json:"failed"`
+	Error      string `json:"error,omitempty"`
+}
+
+type autoTranslateStatusResponse struct {
+	// Enabled is the translateOnRefresh AI setting; feeds set to on are translated either way
+	Enabled  bool   `json:"enabled"`
+	Running  bool   `json:"running"`
+	Language string `json:"language" example:"zh-CN"`
+	// LastRun is omitted until the first run since startup finishes
+	LastRun *autoTranslateRunResponse `json:"lastRun,omitempty"`
+}
+
+// AutoTranslateStatus reports the background list translation worker.
+// @Summary Get auto translate status
+// @Description Whether list titles and summaries of new entries are translated after refreshes, whether a run is in progress, and the stats of the last run since startup.
+// @Tags ai
+// @Produce json
+// @Success 200 {object} autoTranslateStatusResponse
+// @Failure 404 {object} errorResponse
+// @Router /ai/auto-translate/status [get]
+func (h *AIHandler) AutoTranslateStatus(c echo.Context) error {
+	if h.autoTranslate == nil {
+		return Error(c, http.StatusNotFound, CodeNotFound, "auto translate not available")
+	}
+
+	status := h.autoTranslate.Status(c.Request().Context())
+	resp := autoTranslateStatusResponse{
+		Enabled:  status.Enabled,
+		Running:  status.Running,
+		Language: status.Language,
+	}
+	if run := status.LastRun; run != nil {
+		resp.LastRun = &autoTranslateRunResponse{
+			StartedAt:  run.StartedAt.UTC().Format(time.RFC3339),
+			FinishedAt: run.FinishedAt.UTC().Format(time.RFC3339),
+			Entries:    run.Entries,
+			Skipped:    run.Skipped,
+			Translated: run.Translated,
+			Failed:     run.Failed,
+			Error:      run.Error,
+		}
+	}
+	return c.JSON(http.StatusOK, resp)
+}
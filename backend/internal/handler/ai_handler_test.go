@@ -3,13 +3,17 @@ package handler_test
 import (
 	"errors"
 	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"gist/backend/internal/handler"
 	"gist/backend/internal/model"
 	"gist/backend/internal/service"
 	"gist/backend/internal/service/mock"
 
+	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 )
@@ -19,7 +23,7 @@ func TestAIHandler_Summarize_CacheHit(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockService := mock.NewMockAIService(ctrl)
-	h := handler.NewAIHandlerHelper(mockService)
+	h := handler.NewAIHandlerHelper(mockService, nil)
 
 	e := newTestEcho()
 	reqBody := map[string]interface{}{
@@ -35,7 +39,7 @@ func TestAIHandler_Summarize_CacheHit(t *testing.T) {
 	}
 
 	mockService.EXPECT().
-		GetCachedSummary(gomock.Any(), int64(123), false).
+		GetCachedSummary(gomock.Any(), int64(123), false, "").
 		Return(cached, nil)
 
 	err := h.Summarize(c)
@@ -52,7 +56,7 @@ func TestAIHandler_Summarize_InvalidRequest(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockService := mock.NewMockAIService(ctrl)
-	h := handler.NewAIHandlerHelper(mockService)
+	h := handler.NewAIHandlerHelper(mockService, nil)
 
 	e := newTestEcho()
 	reqBody := map[string]interface{}{
@@ -72,7 +76,7 @@ func TestAIHandler_Translate_CacheHit(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockService := mock.NewMockAIService(ctrl)
-	h := handler.NewAIHandlerHelper(mockService)
+	h := handler.NewAIHandlerHelper(mockService, nil)
 
 	e := newTestEcho()
 	reqBody := map[string]interface{}{
@@ -88,7 +92,7 @@ func TestAIHandler_Translate_CacheHit(t *testing.T) {
 	}
 
 	mockService.EXPECT().
-		GetCachedTranslation(gomock.Any(), int64(123), false).
+		GetCachedTranslation(gomock.Any(), int64(123), false, "").
 		Return(cached, nil)
 
 	err := h.Translate(c)
@@ -105,7 +109,7 @@ func TestAIHandler_TranslateBatch_InvalidRequest(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockService := mock.NewMockAIService(ctrl)
-	h := handler.NewAIHandlerHelper(mockService)
+	h := handler.NewAIHandlerHelper(mockService, nil)
 
 	e := newTestEcho()
 	reqBody := map[string]interface{}{
@@ -125,7 +129,7 @@ func TestAIHandler_TranslateBatch_TooManyArticles(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockService := mock.NewMockAIService(ctrl)
-	h := handler.NewAIHandlerHelper(mockService)
+	h := handler.NewAIHandlerHelper(mockService, nil)
 
 	// Create 101 articles to exceed the limit
 	articles := make([]map[string]string, 101)
@@ -151,7 +155,7 @@ func TestAIHandler_TranslateBatch_InvalidJSON(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockService := mock.NewMockAIService(ctrl)
-	h := handler.NewAIHandlerHelper(mockService)
+	h := handler.NewAIHandlerHelper(mockService, nil)
 
 	e := newTestEcho()
 	req := newJSONRequestRaw(http.MethodPost, "/ai/translate/batch", "{invalid json")
@@ -168,7 +172,7 @@ func TestAIHandler_TranslateBatch_ServiceError(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockService := mock.NewMockAIService(ctrl)
-	h := handler.NewAIHandlerHelper(mockService)
+	h := handler.NewAIHandlerHelper(mockService, nil)
 
 	e := newTestEcho()
 	reqBody := map[string]interface{}{
@@ -180,7 +184,7 @@ func TestAIHandler_TranslateBatch_ServiceError(t *testing.T) {
 	c, rec := newTestContext(e, req)
 
 	mockService.EXPECT().
-		TranslateBatch(gomock.Any(), gomock.Any()).
+		TranslateBatch(gomock.Any(), gomock.Any(), "").
 		Return(nil, nil, errors.New("service error"))
 
 	err := h.TranslateBatch(c)
@@ -194,7 +198,7 @@ func TestAIHandler_TranslateBatch_Success(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockService := mock.NewMockAIService(ctrl)
-	h := handler.NewAIHandlerHelper(mockService)
+	h := handler.NewAIHandlerHelper(mockService, nil)
 
 	e := newTestEcho()
 	reqBody := map[string]interface{}{
@@ -220,7 +224,7 @@ func TestAIHandler_TranslateBatch_Success(t *testing.T) {
 	close(errChan)
 
 	mockService.EXPECT().
-		TranslateBatch(gomock.Any(), gomock.Any()).
+		TranslateBatch(gomock.Any(), gomock.Any(), "").
 		Return((<-chan service.BatchTranslateResult)(resultChan), (<-chan error)(errChan), nil)
 
 	err := h.TranslateBatch(c)
@@ -231,12 +235,94 @@ func TestAIHandler_TranslateBatch_Success(t *testing.T) {
 	require.Contains(t, rec.Body.String(), "Translated Title")
 }
 
+func TestAIHandler_TranslateFeeds_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockAIService(ctrl)
+	h := handler.NewAIHandlerHelper(mockService, nil)
+
+	e := newTestEcho()
+	req := newJSONRequest(http.MethodPost, "/ai/translate/feeds", map[string]interface{}{
+		"feedIds":  []string{"1", "2"},
+		"language": "en-US",
+	})
+	c, rec := newTestContext(e, req)
+
+	mockService.EXPECT().
+		TranslateFeedTitles(gomock.Any(), []int64{1, 2}, "en-US").
+		Return([]service.FeedTitleResult{
+			{FeedID: 1, Title: "Habr", Cached: true},
+			{FeedID: 2, Title: "Hacker News", Unchanged: true},
+		}, nil)
+
+	err := h.TranslateFeeds(c)
+	require.NoError(t, err)
+
+	var resp []handler.FeedTitleTranslationResponse
+	assertJSONResponse(t, rec, http.StatusOK, &resp)
+	require.Equal(t, []handler.FeedTitleTranslationResponse{
+		{FeedID: "1", Title: "Habr", Cached: true},
+		{FeedID: "2", Title: "Hacker News", Unchanged: true},
+	}, resp)
+}
+
+func TestAIHandler_TranslateFeeds_InvalidRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockAIService(ctrl)
+	h := handler.NewAIHandlerHelper(mockService, nil)
+
+	tooMany := make([]string, 501)
+	for i := range tooMany {
+		tooMany[i] = "1"
+	}
+
+	tests := []map[string]interface{}{
+		{},
+		{"feedIds": tooMany},
+		{"feedIds": []string{"abc"}},
+		{"feedIds": []string{"1"}, "language": "xx"},
+	}
+	for _, body := range tests {
+		e := newTestEcho()
+		req := newJSONRequest(http.MethodPost, "/ai/translate/feeds", body)
+		c, rec := newTestContext(e, req)
+
+		err := h.TranslateFeeds(c)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+	}
+}
+
+func TestAIHandler_TranslateFeeds_NotConfigured(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockAIService(ctrl)
+	h := handler.NewAIHandlerHelper(mockService, nil)
+
+	e := newTestEcho()
+	req := newJSONRequest(http.MethodPost, "/ai/translate/feeds", map[string]interface{}{"feedIds": []string{"1"}})
+	c, rec := newTestContext(e, req)
+
+	mockService.EXPECT().TranslateFeedTitles(gomock.Any(), []int64{1}, "").Return(nil, service.ErrAINotConfigured)
+
+	err := h.TranslateFeeds(c)
+	require.NoError(t, err)
+
+	var resp handler.ErrorResponse
+	assertJSONResponse(t, rec, http.StatusBadRequest, &resp)
+	require.Equal(t, handler.CodeAINotConfigured, resp.Code)
+}
+
 func TestAIHandler_ClearCache_Success(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockService := mock.NewMockAIService(ctrl)
-	h := handler.NewAIHandlerHelper(mockService)
+	h := handler.NewAIHandlerHelper(mockService, nil)
 
 	e := newTestEcho()
 	req := newJSONRequest(http.MethodDelete, "/ai/cache", nil)
@@ -256,16 +342,68 @@ func TestAIHandler_ClearCache_Success(t *testing.T) {
 	require.Equal(t, int64(3), resp.ListTranslations)
 }
 
+func TestAIHandler_GetUsage_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockAIService(ctrl)
+	h := handler.NewAIHandlerHelper(mockService, nil)
+
+	e := newTestEcho()
+	req := newJSONRequest(http.MethodGet, "/ai/usage?from=2025-03-01&to=2025-03-31", nil)
+	c, rec := newTestContext(e, req)
+
+	mockService.EXPECT().
+		GetUsage(gomock.Any(), service.AIUsageParams{From: "2025-03-01", To: "2025-03-31"}).
+		Return(&service.AIUsageReport{
+			From:             "2025-03-01",
+			To:               "2025-03-31",
+			Days:             []service.AIUsageDay{{Date: "2025-03-02", Operation: "summarize", Calls: 2, PromptTokens: 100, CompletionTokens: 20, Cost: 0.5}},
+			PromptTokens:     100,
+			CompletionTokens: 20,
+			Cost:             0.5,
+		}, nil)
+
+	err := h.GetUsage(c)
+	require.NoError(t, err)
+
+	var resp handler.AIUsageResponse
+	assertJSONResponse(t, rec, http.StatusOK, &resp)
+	require.Len(t, resp.Days, 1)
+	require.Equal(t, "summarize", resp.Days[0].Operation)
+	require.Equal(t, 2, resp.Days[0].Calls)
+	require.Equal(t, 0.5, resp.Cost)
+	require.NotNil(t, resp.UnpricedModels)
+}
+
+func TestAIHandler_GetUsage_InvalidRange(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockAIService(ctrl)
+	h := handler.NewAIHandlerHelper(mockService, nil)
+
+	e := newTestEcho()
+	req := newJSONRequest(http.MethodGet, "/ai/usage?from=2025-04-01&to=2025-03-01", nil)
+	c, rec := newTestContext(e, req)
+
+	mockService.EXPECT().GetUsage(gomock.Any(), gomock.Any()).Return(nil, service.ErrInvalid)
+
+	err := h.GetUsage(c)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
 func TestAIHandler_Summarize_StreamResponse(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockService := mock.NewMockAIService(ctrl)
-	h := handler.NewAIHandlerHelper(mockService)
+	h := handler.NewAIHandlerHelper(mockService, nil)
 
 	// Mock service return nil (cache miss)
 	mockService.EXPECT().
-		GetCachedSummary(gomock.Any(), int64(123), false).
+		GetCachedSummary(gomock.Any(), int64(123), false, "").
 		Return(nil, nil)
 
 	// Mock service return channel
@@ -276,11 +414,11 @@ func TestAIHandler_Summarize_StreamResponse(t *testing.T) {
 	close(resultChan)
 
 	mockService.EXPECT().
-		Summarize(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Summarize(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "").
 		Return(resultChan, make(<-chan error), nil)
 
 	mockService.EXPECT().
-		SaveSummary(gomock.Any(), int64(123), false, "First chunkSecond chunkFinal chunk").
+		SaveSummary(gomock.Any(), int64(123), false, "", "First chunkSecond chunkFinal chunk").
 		Return(nil)
 
 	e := newTestEcho()
@@ -309,22 +447,23 @@ func TestAIHandler_Translate_StreamResponse(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockService := mock.NewMockAIService(ctrl)
-	h := handler.NewAIHandlerHelper(mockService)
+	h := handler.NewAIHandlerHelper(mockService, nil)
 
 	// Mock service return nil (cache miss)
 	mockService.EXPECT().
-		GetCachedTranslation(gomock.Any(), int64(123), false).
+		GetCachedTranslation(gomock.Any(), int64(123), false, "").
 		Return(nil, nil)
 
 	// Mock service return channel
 	resultChan := make(chan service.TranslateBlockResult, 2)
-	resultChan <- service.TranslateBlockResult{Index: 0, HTML: "Translated chunk 1"}
-	resultChan <- service.TranslateBlockResult{Index: 1, HTML: "Translated chunk 2"}
+	resultChan <- service.TranslateBlockResult{Index: 0, EndIndex: 0, HTML: "Translated chunk 1"}
+	// Blocks 1 and 2 were translated together
+	resultChan <- service.TranslateBlockResult{Index: 1, EndIndex: 2, HTML: "Translated chunk 2"}
 	close(resultChan)
 
 	mockService.EXPECT().
-		TranslateBlocks(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
-		Return([]service.TranslateBlockInfo{{Index: 0}, {Index: 1}}, resultChan, make(<-chan error), nil)
+		TranslateBlocks(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "").
+		Return([]service.TranslateBlockInfo{{Index: 0}, {Index: 1}, {Index: 2}}, resultChan, make(<-chan error), nil)
 
 	e := newTestEcho()
 	reqBody := map[string]interface{}{
@@ -342,8 +481,8 @@ func TestAIHandler_Translate_StreamResponse(t *testing.T) {
 	require.Contains(t, rec.Header().Get("Content-Type"), "text/event-stream")
 
 	body := rec.Body.String()
-	require.Contains(t, body, "data: {\"index\":0,\"html\":\"Translated chunk 1\"}")
-	require.Contains(t, body, "data: {\"index\":1,\"html\":\"Translated chunk 2\"}")
+	require.Contains(t, body, "data: {\"index\":0,\"endIndex\":0,\"html\":\"Translated chunk 1\"}")
+	require.Contains(t, body, "data: {\"index\":1,\"endIndex\":2,\"html\":\"Translated chunk 2\"}")
 }
 
 func TestAIHandler_Summarize_ServiceError(t *testing.T) {
@@ -351,14 +490,14 @@ func TestAIHandler_Summarize_ServiceError(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockService := mock.NewMockAIService(ctrl)
-	h := handler.NewAIHandlerHelper(mockService)
+	h := handler.NewAIHandlerHelper(mockService, nil)
 
 	mockService.EXPECT().
-		GetCachedSummary(gomock.Any(), int64(123), false).
+		GetCachedSummary(gomock.Any(), int64(123), false, "").
 		Return(nil, nil)
 
 	mockService.EXPECT().
-		Summarize(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Summarize(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "").
 		Return(nil, nil, errors.New("AI service error"))
 
 	e := newTestEcho()
@@ -380,7 +519,7 @@ func TestAIHandler_Translate_InvalidRequest(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockService := mock.NewMockAIService(ctrl)
-	h := handler.NewAIHandlerHelper(mockService)
+	h := handler.NewAIHandlerHelper(mockService, nil)
 
 	e := newTestEcho()
 	reqBody := map[string]interface{}{
@@ -400,14 +539,14 @@ func TestAIHandler_Translate_ServiceError(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockService := mock.NewMockAIService(ctrl)
-	h := handler.NewAIHandlerHelper(mockService)
+	h := handler.NewAIHandlerHelper(mockService, nil)
 
 	mockService.EXPECT().
-		GetCachedTranslation(gomock.Any(), int64(123), false).
+		GetCachedTranslation(gomock.Any(), int64(123), false, "").
 		Return(nil, nil)
 
 	mockService.EXPECT().
-		TranslateBlocks(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		TranslateBlocks(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "").
 		Return(nil, nil, nil, errors.New("AI service error"))
 
 	e := newTestEcho()
@@ -429,7 +568,7 @@ func TestAIHandler_ClearCache_Error(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockService := mock.NewMockAIService(ctrl)
-	h := handler.NewAIHandlerHelper(mockService)
+	h := handler.NewAIHandlerHelper(mockService, nil)
 
 	e := newTestEcho()
 	req := newJSONRequest(http.MethodDelete, "/ai/cache", nil)
@@ -449,7 +588,7 @@ func TestAIHandler_Summarize_InvalidJSON(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockService := mock.NewMockAIService(ctrl)
-	h := handler.NewAIHandlerHelper(mockService)
+	h := handler.NewAIHandlerHelper(mockService, nil)
 
 	e := newTestEcho()
 	req := newJSONRequestRaw(http.MethodPost, "/ai/summarize", "{invalid json")
@@ -465,7 +604,7 @@ func TestAIHandler_Translate_InvalidJSON(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockService := mock.NewMockAIService(ctrl)
-	h := handler.NewAIHandlerHelper(mockService)
+	h := handler.NewAIHandlerHelper(mockService, nil)
 
 	e := newTestEcho()
 	req := newJSONRequestRaw(http.MethodPost, "/ai/translate", "{invalid json")
@@ -481,11 +620,11 @@ func TestAIHandler_Summarize_CacheLookupError_ContinuesWithService(t *testing.T)
 	defer ctrl.Finish()
 
 	mockService := mock.NewMockAIService(ctrl)
-	h := handler.NewAIHandlerHelper(mockService)
+	h := handler.NewAIHandlerHelper(mockService, nil)
 
 	// Cache lookup fails, but handler continues with service call
 	mockService.EXPECT().
-		GetCachedSummary(gomock.Any(), int64(123), false).
+		GetCachedSummary(gomock.Any(), int64(123), false, "").
 		Return(nil, errors.New("cache lookup error"))
 
 	// Handler continues to call Summarize after cache error
@@ -494,11 +633,11 @@ func TestAIHandler_Summarize_CacheLookupError_ContinuesWithService(t *testing.T)
 	close(resultChan)
 
 	mockService.EXPECT().
-		Summarize(gomock.Any(), int64(123), "test content", "test title", false).
+		Summarize(gomock.Any(), int64(123), "test content", "test title", false, "").
 		Return(resultChan, make(<-chan error), nil)
 
 	mockService.EXPECT().
-		SaveSummary(gomock.Any(), int64(123), false, "Summary content").
+		SaveSummary(gomock.Any(), int64(123), false, "", "Summary content").
 		Return(nil)
 
 	e := newTestEcho()
@@ -520,11 +659,11 @@ func TestAIHandler_Translate_CacheLookupError_ContinuesWithService(t *testing.T)
 	defer ctrl.Finish()
 
 	mockService := mock.NewMockAIService(ctrl)
-	h := handler.NewAIHandlerHelper(mockService)
+	h := handler.NewAIHandlerHelper(mockService, nil)
 
 	// Cache lookup fails, but handler continues with service call
 	mockService.EXPECT().
-		GetCachedTranslation(gomock.Any(), int64(123), false).
+		GetCachedTranslation(gomock.Any(), int64(123), false, "").
 		Return(nil, errors.New("cache lookup error"))
 
 	// Handler continues to call TranslateBlocks after cache error
@@ -533,7 +672,7 @@ func TestAIHandler_Translate_CacheLookupError_ContinuesWithService(t *testing.T)
 	close(resultChan)
 
 	mockService.EXPECT().
-		TranslateBlocks(gomock.Any(), int64(123), "test content", "test title", false).
+		TranslateBlocks(gomock.Any(), int64(123), "test content", "test title", false, "").
 		Return([]service.TranslateBlockInfo{{Index: 0}}, resultChan, make(<-chan error), nil)
 
 	e := newTestEcho()
@@ -549,3 +688,379 @@ func TestAIHandler_Translate_CacheLookupError_ContinuesWithService(t *testing.T)
 	require.NoError(t, err)
 	require.Equal(t, http.StatusOK, rec.Code)
 }
+
+func TestAIHandler_LanguageOverride(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockAIService(ctrl)
+	h := handler.NewAIHandlerHelper(mockService, nil)
+	e := newTestEcho()
+
+	req := newJSONRequest(http.MethodPost, "/ai/summarize", map[string]interface{}{
+		"entryId":  "123",
+		"content":  "test content",
+		"language": "en-US",
+	})
+	c, rec := newTestContext(e, req)
+	mockService.EXPECT().
+		GetCachedSummary(gomock.Any(), int64(123), false, "en-US").
+		Return(&model.AISummary{Summary: "english"}, nil)
+
+	require.NoError(t, h.Summarize(c))
+	var resp handler.SummarizeResponse
+	assertJSONResponse(t, rec, http.StatusOK, &resp)
+	require.Equal(t, "english", resp.Summary)
+
+	req = newJSONRequest(http.MethodPost, "/ai/translate", map[string]interface{}{
+		"entryId":  "123",
+		"content":  "test content",
+		"language": "ja",
+	})
+	c, rec = newTestContext(e, req)
+	mockService.EXPECT().
+		GetCachedTranslation(gomock.Any(), int64(123), false, "ja").
+		Return(&model.AITranslation{Content: "日本語"}, nil)
+
+	require.NoError(t, h.Translate(c))
+	require.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestAIHandler_UnsupportedLanguage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockAIService(ctrl)
+	h := handler.NewAIHandlerHelper(mockService, nil)
+	e := newTestEcho()
+
+	body := map[string]interface{}{
+		"entryId":  "123",
+		"content":  "test content",
+		"language": "klingon",
+		"articles": []map[string]string{{"id": "1", "title": "t"}},
+	}
+	for path, handle := range map[string]echo.HandlerFunc{
+		"/ai/summarize":       h.Summarize,
+		"/ai/translate":       h.Translate,
+		"/ai/translate/batch": h.TranslateBatch,
+	} {
+		c, rec := newTestContext(e, newJSONRequest(http.MethodPost, path, body))
+		require.NoError(t, handle(c))
+		require.Equal(t, http.StatusBadRequest, rec.Code, path)
+	}
+}
+
+func TestAIHandler_AutoTranslateStatus(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	e := newTestEcho()
+
+	// Without the worker the endpoint is not available
+	h := handler.NewAIHandlerHelper(mock.NewMockAIService(ctrl), nil)
+	c, rec := newTestContext(e, httptest.NewRequest(http.MethodGet, "/ai/auto-translate/status", nil))
+	require.NoError(t, h.AutoTranslateStatus(c))
+	require.Equal(t, http.StatusNotFound, rec.Code)
+
+	autoTranslate := mock.NewMockAutoTranslateService(ctrl)
+	h = handler.NewAIHandlerHelper(mock.NewMockAIService(ctrl), autoTranslate)
+	started := time.Date(2026, 10, 15, 8, 0, 0, 0, time.UTC)
+	autoTranslate.EXPECT().Status(gomock.Any()).Return(service.AutoTranslateStatus{
+		Enabled:  true,
+		Language: "zh-CN",
+		LastRun: &service.AutoTranslateRun{
+			StartedAt:  started,
+			FinishedAt: started.Add(time.Minute),
+			Entries:    12,
+			Skipped:    2,
+			Translated: 9,
+			Failed:     1,
+		},
+	})
+	c, rec = newTestContext(e, httptest.NewRequest(http.MethodGet, "/ai/auto-translate/status", nil))
+	require.NoError(t, h.AutoTranslateStatus(c))
+
+	var resp handler.AutoTranslateStatusResponse
+	assertJSONResponse(t, rec, http.StatusOK, &resp)
+	require.True(t, resp.Enabled)
+	require.False(t, resp.Running)
+	require.Equal(t, "zh-CN", resp.Language)
+	require.NotNil(t, resp.LastRun)
+	require.Equal(t, "2026-10-15T08:00:00Z", resp.LastRun.StartedAt)
+	require.Equal(t, "2026-10-15T08:01:00Z", resp.LastRun.FinishedAt)
+	require.Equal(t, 12, resp.LastRun.Entries)
+	require.Equal(t, 9, resp.LastRun.Translated)
+	require.Equal(t, 1, resp.LastRun.Failed)
+}
+
+func TestAIHandler_FieldValidation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	h := handler.NewAIHandlerHelper(mock.NewMockAIService(ctrl), nil)
+	e := newTestEcho()
+	article := func(extra map[string]interface{}) map[string]interface{} {
+		body := map[string]interface{}{"entryId": "123", "content": "<p>Body</p>", "title": "Title"}
+		for k, v := range extra {
+			body[k] = v
+		}
+		return body
+	}
+
+	tests := []struct {
+		name     string
+		call     echo.HandlerFunc
+		body     interface{}
+		field    string
+		maxBytes int
+	}{
+		{name: "summarize entry id", call: h.Summarize, body: article(map[string]interface{}{"entryId": "abc"}), field: "entryId"},
+		{name: "summarize missing content", call: h.Summarize, body: article(map[string]interface{}{"content": ""}), field: "content"},
+		{name: "summarize content too long", call: h.Summarize, body: article(map[string]interface{}{"content": strings.Repeat("x", 512<<10+1)}), field: "content", maxBytes: 512 << 10},
+		{name: "summarize title too long", call: h.Summarize, body: article(map[string]interface{}{"title": strings.Repeat("x", 4<<10+1)}), field: "title", maxBytes: 4 << 10},
+		{name: "translate entry id", call: h.Translate, body: article(map[string]interface{}{"entryId": ""}), field: "entryId"},
+		{name: "translate content too long", call: h.Translate, body: article(map[string]interface{}{"content": strings.Repeat("x", 512<<10+1)}), field: "content", maxBytes: 512 << 10},
+		{name: "translate language", call: h.Translate, body: article(map[string]interface{}{"language": "xx"}), field: "language"},
+		{name: "batch summary too long", call: h.TranslateBatch, body: map[string]interface{}{"articles": []map[string]string{{"id": "1", "title": "T", "summary": strings.Repeat("x", 16<<10+1)}}}, field: "articles.summary", maxBytes: 16 << 10},
+		{name: "feed id", call: h.TranslateFeeds, body: map[string]interface{}{"feedIds": []string{"1", "x"}}, field: "feedIds"},
+		{name: "digest folder id", call: h.Digest, body: map[string]interface{}{"folderId": "abc", "since": "2026-10-14T00:00:00Z"}, field: "folderId"},
+		{name: "digest since", call: h.Digest, body: map[string]interface{}{"feedId": "1", "since": "yesterday"}, field: "since"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c, rec := newTestContext(e, newJSONRequest(http.MethodPost, "/ai", tc.body))
+			require.NoError(t, tc.call(c))
+
+			var resp handler.ErrorResponse
+			assertJSONResponse(t, rec, http.StatusBadRequest, &resp)
+			require.Equal(t, handler.CodeInvalidRequest, resp.Code)
+			require.Equal(t, tc.field, resp.Details["field"])
+			if tc.maxBytes > 0 {
+				require.Equal(t, float64(tc.maxBytes), resp.Details["maxBytes"])
+			}
+		})
+	}
+}
+
+func TestAIHandler_Digest_Validation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	h := handler.NewAIHandlerHelper(mock.NewMockAIService(ctrl), nil)
+	e := newTestEcho()
+
+	for name, body := range map[string]map[string]interface{}{
+		"no scope":          {"since": "2026-10-14T00:00:00Z"},
+		"both scopes":       {"folderId": "1", "feedId": "2", "since": "2026-10-14T00:00:00Z"},
+		"invalid id":        {"folderId": "abc", "since": "2026-10-14T00:00:00Z"},
+		"missing since":     {"folderId": "1"},
+		"reversed window":   {"folderId": "1", "since": "2026-10-14T00:00:00Z", "until": "2026-10-13T00:00:00Z"},
+		"window too long":   {"folderId": "1", "since": "2026-08-01T00:00:00Z", "until": "2026-10-14T00:00:00Z"},
+		"within one minute": {"folderId": "1", "since": "2026-10-14T00:00:10Z", "until": "2026-10-14T00:00:50Z"},
+		"bad language":      {"folderId": "1", "since": "2026-10-14T00:00:00Z", "language": "xx"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			c, rec := newTestContext(e, newJSONRequest(http.MethodPost, "/ai/digest", body))
+			require.NoError(t, h.Digest(c))
+			require.Equal(t, http.StatusBadRequest, rec.Code)
+		})
+	}
+}
+
+func TestAIHandler_Digest_CacheHit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockAIService(ctrl)
+	h := handler.NewAIHandlerHelper(mockService, nil)
+
+	want := service.AIDigestParams{
+		Scope:   model.AIDigestScopeFolder,
+		ScopeID: 7,
+		Start:   time.Date(2026, 10, 14, 0, 0, 0, 0, time.UTC),
+		End:     time.Date(2026, 10, 15, 8, 30, 0, 0, time.UTC),
+	}
+	mockService.EXPECT().GetCachedDigest(gomock.Any(), want).Return(&model.AIDigest{Digest: "cached digest", Entries: 4}, nil)
+
+	req := newJSONRequest(http.MethodPost, "/ai/digest", map[string]interface{}{
+		"folderId": "7",
+		"since":    "2026-10-14T02:00:00+02:00",
+		"until":    "2026-10-15T08:30:45Z",
+	})
+	c, rec := newTestContext(newTestEcho(), req)
+	require.NoError(t, h.Digest(c))
+
+	var resp handler.DigestResponse
+	assertJSONResponse(t, rec, http.StatusOK, &resp)
+	require.Equal(t, "cached digest", resp.Digest)
+	require.True(t, resp.Cached)
+	require.Equal(t, 4, resp.Entries)
+}
+
+func TestAIHandler_Digest_NothingNew(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockAIService(ctrl)
+	h := handler.NewAIHandlerHelper(mockService, nil)
+
+	mockService.EXPECT().GetCachedDigest(gomock.Any(), gomock.Any()).Return(nil, nil)
+	mockService.EXPECT().Digest(gomock.Any(), gomock.Any()).Return(service.AIDigestStream{}, nil)
+
+	req := newJSONRequest(http.MethodPost, "/ai/digest", map[string]interface{}{"feedId": "3", "since": "2026-10-14T00:00:00Z"})
+	c, rec := newTestContext(newTestEcho(), req)
+	require.NoError(t, h.Digest(c))
+
+	var resp handler.DigestResponse
+	assertJSONResponse(t, rec, http.StatusOK, &resp)
+	require.Equal(t, service.AIDigestNothingNew, resp.Digest)
+	require.False(t, resp.Cached)
+	require.Zero(t, resp.Entries)
+}
+
+func TestAIHandler_Digest_StreamsAndCaches(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockAIService(ctrl)
+	h := handler.NewAIHandlerHelper(mockService, nil)
+
+	textCh := make(chan string, 2)
+	textCh <- "Part one. "
+	textCh <- "Part two."
+	close(textCh)
+	errCh := make(chan error)
+	close(errCh)
+
+	mockService.EXPECT().GetCachedDigest(gomock.Any(), gomock.Any()).Return(nil, nil)
+	mockService.EXPECT().Digest(gomock.Any(), gomock.Any()).Return(service.AIDigestStream{Entries: 2, Text: textCh, Errors: errCh}, nil)
+	mockService.EXPECT().SaveDigest(gomock.Any(), gomock.Any(), 2, "Part one. Part two.").Return(nil)
+
+	req := newJSONRequest(http.MethodPost, "/ai/digest", map[string]interface{}{"feedId": "3", "since": "2026-10-14T00:00:00Z"})
+	c, rec := newTestContext(newTestEcho(), req)
+	require.NoError(t, h.Digest(c))
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, "text/event-stream", rec.Header().Get("Content-Type"))
+	require.Equal(t, "2", rec.Header().Get("X-Digest-Entries"))
+	require.Equal(t, "Part one. Part two.", rec.Body.String())
+}
+
+func TestAIHandler_Summarize_FlushesEachChunk(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockAIService(ctrl)
+	h := handler.NewAIHandlerHelper(mockService, nil)
+
+	textCh := make(chan string, 2)
+	textCh <- "First. "
+	textCh <- "Second."
+	close(textCh)
+
+	mockService.EXPECT().GetCachedSummary(gomock.Any(), int64(123), false, "").Return(nil, nil)
+	mockService.EXPECT().Summarize(gomock.Any(), int64(123), "test content", "", false, "").Return(textCh, make(<-chan error), nil)
+	mockService.EXPECT().SaveSummary(gomock.Any(), int64(123), false, "", "First. Second.").Return(nil)
+
+	req := newJSONRequest(http.MethodPost, "/ai/summarize", map[string]interface{}{"entryId": "123", "content": "test content"})
+	c, rec := newFlushContext(newTestEcho(), req)
+	require.NoError(t, h.Summarize(c))
+
+	require.Equal(t, "text/event-stream", rec.Header().Get("Content-Type"))
+	require.Equal(t, "no-cache, no-transform", rec.Header().Get("Cache-Control"))
+	require.Equal(t, "no", rec.Header().Get("X-Accel-Buffering"))
+	// Headers are flushed first, then each chunk as soon as it is written
+	require.Equal(t, []string{"", "First. ", "First. Second."}, rec.flushes)
+}
+
+func TestAIHandler_Summarize_NDJSON(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockAIService(ctrl)
+	h := handler.NewAIHandlerHelper(mockService, nil)
+
+	textCh := make(chan string, 1)
+	textCh <- "Partial"
+	close(textCh)
+	errCh := make(chan error, 1)
+	errCh <- errors.New("provider failed")
+
+	mockService.EXPECT().GetCachedSummary(gomock.Any(), int64(123), false, "").Return(nil, nil)
+	mockService.EXPECT().Summarize(gomock.Any(), int64(123), "test content", "", false, "").Return(textCh, errCh, nil)
+
+	req := newJSONRequest(http.MethodPost, "/ai/summarize", map[string]interface{}{"entryId": "123", "content": "test content"})
+	req.Header.Set(echo.HeaderAccept, "application/x-ndjson")
+	c, rec := newFlushContext(newTestEcho(), req)
+	require.NoError(t, h.Summarize(c))
+
+	require.Equal(t, "application/x-ndjson", rec.Header().Get("Content-Type"))
+	require.Equal(t, "no", rec.Header().Get("X-Accel-Buffering"))
+	require.Equal(t, []string{
+		"",
+		"{\"text\":\"Partial\"}\n",
+		"{\"text\":\"Partial\"}\n{\"error\":\"provider failed\"}\n",
+	}, rec.flushes)
+}
+
+func TestAIHandler_Translate_NDJSON(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockAIService(ctrl)
+	h := handler.NewAIHandlerHelper(mockService, nil)
+
+	resultCh := make(chan service.TranslateBlockResult, 1)
+	resultCh <- service.TranslateBlockResult{Index: 0, EndIndex: 0, HTML: "<p>Bonjour</p>"}
+	close(resultCh)
+
+	mockService.EXPECT().GetCachedTranslation(gomock.Any(), int64(123), false, "").Return(nil, nil)
+	mockService.EXPECT().
+		TranslateBlocks(gomock.Any(), int64(123), "test content", "", false, "").
+		Return([]service.TranslateBlockInfo{{Index: 0, HTML: "<p>Hello</p>", NeedTranslate: true}}, resultCh, make(<-chan error), nil)
+
+	req := newJSONRequest(http.MethodPost, "/ai/translate", map[string]interface{}{"entryId": "123", "content": "test content"})
+	req.Header.Set(echo.HeaderAccept, "application/x-ndjson, text/event-stream")
+	c, rec := newFlushContext(newTestEcho(), req)
+	require.NoError(t, h.Translate(c))
+
+	require.Equal(t, "application/x-ndjson", rec.Header().Get("Content-Type"))
+	require.Equal(t, "no-cache, no-transform", rec.Header().Get("Cache-Control"))
+	lines := []string{
+		"{\"blocks\":[{\"index\":0,\"html\":\"\\u003cp\\u003eHello\\u003c/p\\u003e\",\"needTranslate\":true}]}\n",
+		"{\"index\":0,\"endIndex\":0,\"html\":\"\\u003cp\\u003eBonjour\\u003c/p\\u003e\"}\n",
+		"{\"done\":true}\n",
+	}
+	// One flush for the headers, then one per line
+	require.Len(t, rec.flushes, len(lines)+1)
+	for i := range lines {
+		require.Equal(t, strings.Join(lines[:i+1], ""), rec.flushes[i+1])
+	}
+}
+
+func TestAIHandler_TranslateBatch_FlushesEachResult(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockAIService(ctrl)
+	h := handler.NewAIHandlerHelper(mockService, nil)
+
+	resultCh := make(chan service.BatchTranslateResult, 2)
+	resultCh <- service.BatchTranslateResult{ID: "1"}
+	resultCh <- service.BatchTranslateResult{ID: "2"}
+	close(resultCh)
+
+	mockService.EXPECT().TranslateBatch(gomock.Any(), gomock.Any(), "").Return(resultCh, make(<-chan error), nil)
+
+	req := newJSONRequest(http.MethodPost, "/ai/translate/batch", map[string]interface{}{
+		"articles": []map[string]string{{"id": "1", "title": "One"}, {"id": "2", "title": "Two"}},
+	})
+	c, rec := newFlushContext(newTestEcho(), req)
+	require.NoError(t, h.TranslateBatch(c))
+
+	require.Equal(t, "no", rec.Header().Get("X-Accel-Buffering"))
+	require.Len(t, rec.flushes, 3)
+	require.Equal(t, 1, strings.Count(rec.flushes[1], "\n"))
+	require.Equal(t, 2, strings.Count(rec.flushes[2], "\n"))
+}
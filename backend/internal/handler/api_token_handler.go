@@ -0,0 +1,156 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"gist/backend/internal/model"
+	"gist/backend/internal/service"
+	"gist/backend/pkg/logger"
+)
+
+type APITokenHandler struct {
+	service service.APITokenService
+}
+
+// Request/Response types
+
+type createAPITokenRequest struct {
+	Name      string  `json:"name"`
+	ExpiresAt *string `json:"expiresAt"`
+}
+
+type apiTokenResponse struct {
+	ID         string  `json:"id"`
+	Name       string  `json:"name"`
+	CreatedAt  string  `json:"createdAt"`
+	LastUsedAt *string `json:"lastUsedAt,omitempty"`
+	ExpiresAt  *string `json:"expiresAt,omitempty"`
+}
+
+type createdAPITokenResponse struct {
+	apiTokenResponse
+	Token string `json:"token"`
+}
+
+type apiTokenListResponse struct {
+	Items []apiTokenResponse `json:"items"`
+}
+
+func NewAPITokenHandler(svc service.APITokenService) *APITokenHandler {
+	return &APITokenHandler{service: svc}
+}
+
+func (h *APITokenHandler) RegisterRoutes(g *echo.Group) {
+	g.GET("/auth/tokens", h.List)
+	g.POST("/auth/tokens", h.Create)
+	g.DELETE("/auth/tokens/:id", h.Delete)
+}
+
+// List returns all API tokens.
+// @Summary List API tokens
+// @Description List personal access tokens. Token values are never returned after creation.
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} apiTokenListResponse
+// @Failure 500 {object} errorResponse
+// @Router /auth/tokens [get]
+func (h *APITokenHandler) List(c echo.Context) error {
+	tokens, err := h.service.List(c.Request().Context())
+	if err != nil {
+		logger.Error("api token list failed", "module", "handler", "action", "list", "resource", "api_token", "result", "failed", "error", err)
+		return writeServiceError(c, err)
+	}
+
+	items := make([]apiTokenResponse, len(tokens))
+	for i, t := range tokens {
+		items[i] = toAPITokenResponse(t)
+	}
+	return c.JSON(http.StatusOK, apiTokenListResponse{Items: items})
+}
+
+// Create issues a new API token.
+// @Summary Create API token
+// @Description Create a personal access token for non-browser clients. The token value is shown only once.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body createAPITokenRequest true "Token info (expiresAt is RFC3339, optional)"
+// @Success 201 {object} createdAPITokenResponse
+// @Failure 400 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /auth/tokens [post]
+func (h *APITokenHandler) Create(c echo.Context) error {
+	var req createAPITokenRequest
+	if err := c.Bind(&req); err != nil {
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid request")
+	}
+
+	var expiresAt *time.Time
+	if req.ExpiresAt != nil && strings.TrimSpace(*req.ExpiresAt) != "" {
+		parsed, err := time.Parse(time.RFC3339, strings.TrimSpace(*req.ExpiresAt))
+		if err != nil {
+			return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid expiresAt")
+		}
+		expiresAt = &parsed
+	}
+
+	created, err := h.service.Create(c.Request().Context(), req.Name, expiresAt)
+	if err != nil {
+		logger.Warn("api token create failed", "module", "handler", "action", "create", "resource", "api_token", "result", "failed", "error", err)
+		return writeServiceError(c, err)
+	}
+
+	logger.Info("api token created", "module", "handler", "action", "create", "resource", "api_token", "result", "ok", "token_id", created.APIToken.ID)
+	return c.JSON(http.StatusCreated, createdAPITokenResponse{
+		apiTokenResponse: toAPITokenResponse(created.APIToken),
+		Token:            created.Token,
+	})
+}
+
+// Delete revokes an API token.
+// @Summary Revoke API token
+// @Description Revoke a personal access token. Takes effect immediately.
+// @Tags auth
+// @Security BearerAuth
+// @Param id path string true "Token ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Router /auth/tokens/{id} [delete]
+func (h *APITokenHandler) Delete(c echo.Context) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid request")
+	}
+
+	if err := h.service.Delete(c.Request().Context(), id); err != nil {
+		logger.Warn("api token revoke failed", "module", "handler", "action", "delete", "resource", "api_token", "result", "failed", "token_id", id, "error", err)
+		return writeServiceError(c, err)
+	}
+
+	logger.Info("api token revoked", "module", "handler", "action", "delete", "resource", "api_token", "result", "ok", "token_id", id)
+	return c.NoContent(http.StatusNoContent)
+}
+
+func toAPITokenResponse(token model.APIToken) apiTokenResponse {
+	resp := apiTokenResponse{
+		ID:        idToString(token.ID),
+		Name:      token.Name,
+		CreatedAt: token.CreatedAt.UTC().Format(time.RFC3339),
+	}
+	if token.LastUsedAt != nil {
+		v := token.LastUsedAt.UTC().Format(time.RFC3339)
+		resp.LastUsedAt = &v
+	}
+	if token.ExpiresAt != nil {
+		v := token.ExpiresAt.UTC().Format(time.RFC3339)
+		resp.ExpiresAt = &v
+	}
+	return resp
+}
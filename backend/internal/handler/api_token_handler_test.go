@@ -0,0 +1,106 @@
+package handler_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"gist/backend/internal/handler"
+	"gist/backend/internal/model"
+	"gist/backend/internal/service"
+	"gist/backend/internal/service/mock"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestAPITokenHandler_Create(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockAPITokenService(ctrl)
+	h := handler.NewAPITokenHandlerHelper(mockService)
+
+	e := newTestEcho()
+	req := newJSONRequest(http.MethodPost, "/auth/tokens", map[string]interface{}{
+		"name":      "cli",
+		"expiresAt": "2030-01-01T00:00:00Z",
+	})
+	c, rec := newTestContext(e, req)
+
+	expires := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	mockService.EXPECT().
+		Create(gomock.Any(), "cli", &expires).
+		Return(&service.CreatedAPIToken{
+			Token:    service.APITokenPrefix + "abc",
+			APIToken: model.APIToken{ID: 42, Name: "cli", CreatedAt: time.Now(), ExpiresAt: &expires},
+		}, nil)
+
+	err := h.Create(c)
+	require.NoError(t, err)
+
+	var resp handler.CreatedAPITokenResponse
+	assertJSONResponse(t, rec, http.StatusCreated, &resp)
+	require.Equal(t, "42", resp.ID)
+	require.Equal(t, service.APITokenPrefix+"abc", resp.Token)
+	require.NotNil(t, resp.ExpiresAt)
+}
+
+func TestAPITokenHandler_Create_InvalidExpiry(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	h := handler.NewAPITokenHandlerHelper(mock.NewMockAPITokenService(ctrl))
+
+	e := newTestEcho()
+	req := newJSONRequest(http.MethodPost, "/auth/tokens", map[string]interface{}{"name": "cli", "expiresAt": "tomorrow"})
+	c, rec := newTestContext(e, req)
+
+	err := h.Create(c)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestAPITokenHandler_List(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockAPITokenService(ctrl)
+	h := handler.NewAPITokenHandlerHelper(mockService)
+
+	e := newTestEcho()
+	req := newJSONRequest(http.MethodGet, "/auth/tokens", nil)
+	c, rec := newTestContext(e, req)
+
+	mockService.EXPECT().
+		List(gomock.Any()).
+		Return([]model.APIToken{{ID: 1, Name: "cli", TokenHash: "secret-hash"}}, nil)
+
+	err := h.List(c)
+	require.NoError(t, err)
+
+	var resp handler.APITokenListResponse
+	assertJSONResponse(t, rec, http.StatusOK, &resp)
+	require.Len(t, resp.Items, 1)
+	require.Equal(t, "cli", resp.Items[0].Name)
+	require.NotContains(t, rec.Body.String(), "secret-hash")
+}
+
+func TestAPITokenHandler_Delete(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockAPITokenService(ctrl)
+	h := handler.NewAPITokenHandlerHelper(mockService)
+
+	e := newTestEcho()
+	req := newJSONRequest(http.MethodDelete, "/auth/tokens/1", nil)
+	c, rec := newTestContext(e, req)
+	setPathParams(c, map[string]string{"id": "1"})
+
+	mockService.EXPECT().Delete(gomock.Any(), int64(1)).Return(service.ErrNotFound)
+
+	err := h.Delete(c)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusNotFound, rec.Code)
+}
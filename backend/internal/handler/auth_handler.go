@@ -3,6 +3,7 @@ package handler
 import (
 	"errors"
 	"net/http"
+	"time"
 
 	"github.com/labstack/echo/v4"
 
@@ -14,11 +15,13 @@ import (
 const authCookieName = "gist_auth"
 
 type AuthHandler struct {
-	service service.AuthService
+	service    service.AuthService
+	loginGuard service.LoginGuardService
+	settings   service.SettingsService
 }
 
-func NewAuthHandler(service service.AuthService) *AuthHandler {
-	return &AuthHandler{service: service}
+func NewAuthHandler(service service.AuthService, loginGuard service.LoginGuardService, settings service.SettingsService) *AuthHandler {
+	return &AuthHandler{service: service, loginGuard: loginGuard, settings: settings}
 }
 
 // Request/Response types
@@ -47,8 +50,32 @@ type updateProfileRequest struct {
 }
 
 type authResponse struct {
-	Token string        `json:"token"`
-	User  *userResponse `json:"user"`
+	Token        string        `json:"token"`
+	User         *userResponse `json:"user"`
+	Requires2FA  bool          `json:"requires2fa,omitempty"`
+	PendingToken string        `json:"pendingToken,omitempty"`
+}
+
+type twoFactorSetupResponse struct {
+	Secret     string `json:"secret"`
+	OTPAuthURL string `json:"otpauthUrl"`
+}
+
+type twoFactorEnableRequest struct {
+	Code string `json:"code"`
+}
+
+type twoFactorEnableResponse struct {
+	RecoveryCodes []string `json:"recoveryCodes"`
+}
+
+type twoFactorVerifyRequest struct {
+	PendingToken string `json:"pendingToken"`
+	Code         string `json:"code"`
+}
+
+type twoFactorDisableRequest struct {
+	Password string `json:"password"`
 }
 
 type updateProfileResponse struct {
@@ -56,11 +83,25 @@ type updateProfileResponse struct {
 	Token *string       `json:"token,omitempty"`
 }
 
+type loginEventResponse struct {
+	ID         string `json:"id"`
+	Identifier string `json:"identifier"`
+	IP         string `json:"ip"`
+	UserAgent  string `json:"userAgent"`
+	Success    bool   `json:"success"`
+	CreatedAt  string `json:"createdAt"`
+}
+
+type loginEventListResponse struct {
+	Items []loginEventResponse `json:"items"`
+}
+
 type userResponse struct {
-	Username  string `json:"username"`
-	Nickname  string `json:"nickname"`
-	Email     string `json:"email"`
-	AvatarURL string `json:"avatarUrl"`
+	Username         string `json:"username"`
+	Nickname         string `json:"nickname"`
+	Email            string `json:"email"`
+	AvatarURL        string `json:"avatarUrl"`
+	TwoFactorEnabled bool   `json:"twoFactorEnabled"`
 }
 
 // RegisterPublicRoutes registers routes that don't require authentication.
@@ -69,12 +110,17 @@ func (h *AuthHandler) RegisterPublicRoutes(g *echo.Group) {
 	g.POST("/auth/register", h.Register)
 	g.POST("/auth/login", h.Login)
 	g.POST("/auth/logout", h.Logout)
+	g.POST("/auth/2fa/verify", h.VerifyTwoFactor)
 }
 
 // RegisterProtectedRoutes registers routes that require authentication.
 func (h *AuthHandler) RegisterProtectedRoutes(g *echo.Group) {
 	g.GET("/auth/me", h.GetCurrentUser)
 	g.PUT("/auth/profile", h.UpdateProfile)
+	g.GET("/auth/events", h.ListLoginEvents)
+	g.POST("/auth/2fa/setup", h.SetupTwoFactor)
+	g.POST("/auth/2fa/enable", h.EnableTwoFactor)
+	g.POST("/auth/2fa/disable", h.DisableTwoFactor)
 }
 
 // GetStatus checks if a user has been registered.
@@ -89,7 +135,7 @@ func (h *AuthHandler) GetStatus(c echo.Context) error {
 	exists, err := h.service.CheckUserExists(c.Request().Context())
 	if err != nil {
 		logger.Error("auth status check failed", "module", "handler", "action", "list", "resource", "auth", "result", "failed", "error", err)
-		return c.JSON(http.StatusInternalServerError, errorResponse{Error: "failed to check status"})
+		return Error(c, http.StatusInternalServerError, CodeInternal, "failed to check status")
 	}
 
 	return c.JSON(http.StatusOK, authStatusResponse{Exists: exists})
@@ -111,17 +157,17 @@ func (h *AuthHandler) Register(c echo.Context) error {
 	var req registerRequest
 	if err := c.Bind(&req); err != nil {
 		logger.Warn("auth request invalid", "module", "handler", "action", "create", "resource", "auth", "result", "failed", "error", err)
-		return c.JSON(http.StatusBadRequest, errorResponse{Error: "invalid request"})
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid request")
 	}
 
 	resp, err := h.service.Register(c.Request().Context(), req.Username, req.Nickname, req.Email, req.Password)
 	if err != nil {
 		logger.Warn("auth register failed", "module", "handler", "action", "create", "resource", "auth", "result", "failed", "actor", req.Username, "error", err)
-		return h.handleAuthError(c, err)
+		return writeServiceError(c, err)
 	}
 
 	// Set auth cookie for browser resource requests (images, etc.)
-	setAuthCookie(c, resp.Token)
+	SetAuthCookie(c, h.settings, resp.Token)
 
 	logger.Info("auth register", "module", "handler", "action", "create", "resource", "auth", "result", "ok", "actor", resp.User.Username)
 	return c.JSON(http.StatusOK, authResponse{
@@ -132,7 +178,8 @@ func (h *AuthHandler) Register(c echo.Context) error {
 
 // Login authenticates a user.
 // @Summary Login
-// @Description Authenticate a user with username or email and get a JWT token
+// @Description Authenticate a user with username or email and get a JWT token.
+// @Description When two-factor authentication is enabled, returns requires2fa with a pending token instead.
 // @Tags auth
 // @Accept json
 // @Produce json
@@ -140,23 +187,46 @@ func (h *AuthHandler) Register(c echo.Context) error {
 // @Success 200 {object} authResponse
 // @Failure 400 {object} errorResponse
 // @Failure 401 {object} errorResponse
+// @Failure 429 {object} errorResponse
 // @Failure 500 {object} errorResponse
 // @Router /auth/login [post]
 func (h *AuthHandler) Login(c echo.Context) error {
 	var req loginRequest
 	if err := c.Bind(&req); err != nil {
 		logger.Warn("auth request invalid", "module", "handler", "action", "login", "resource", "auth", "result", "failed", "error", err)
-		return c.JSON(http.StatusBadRequest, errorResponse{Error: "invalid request"})
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid request")
+	}
+
+	ctx := c.Request().Context()
+	ip := c.RealIP()
+	userAgent := c.Request().UserAgent()
+
+	if err := h.loginGuard.Check(ip, req.Identifier); err != nil {
+		logger.Warn("auth login locked", "module", "handler", "action", "login", "resource", "auth", "result", "failed", "actor", req.Identifier, "remote_ip", ip)
+		return writeServiceError(c, err)
 	}
 
-	resp, err := h.service.Login(c.Request().Context(), req.Identifier, req.Password)
+	resp, err := h.service.Login(ctx, req.Identifier, req.Password)
 	if err != nil {
+		if errors.Is(err, service.ErrInvalidPassword) || errors.Is(err, service.ErrUserNotFound) {
+			h.loginGuard.RecordFailure(ctx, ip, userAgent, req.Identifier)
+		}
 		logger.Warn("auth login failed", "module", "handler", "action", "login", "resource", "auth", "result", "failed", "actor", req.Identifier, "error", err)
-		return h.handleAuthError(c, err)
+		return writeServiceError(c, err)
+	}
+	h.loginGuard.RecordSuccess(ctx, ip, userAgent, req.Identifier)
+
+	// Second factor required: no cookie until verification succeeds
+	if resp.Requires2FA {
+		logger.Info("auth login requires 2fa", "module", "handler", "action", "login", "resource", "auth", "result", "pending", "actor", req.Identifier)
+		return c.JSON(http.StatusOK, authResponse{
+			Requires2FA:  true,
+			PendingToken: resp.PendingToken,
+		})
 	}
 
 	// Set auth cookie for browser resource requests (images, etc.)
-	setAuthCookie(c, resp.Token)
+	SetAuthCookie(c, h.settings, resp.Token)
 
 	logger.Info("auth login", "module", "handler", "action", "login", "resource", "auth", "result", "ok", "actor", resp.User.Username)
 	return c.JSON(http.StatusOK, authResponse{
@@ -180,10 +250,10 @@ func (h *AuthHandler) GetCurrentUser(c echo.Context) error {
 	if err != nil {
 		if errors.Is(err, service.ErrUserNotFound) {
 			logger.Warn("auth me not authenticated", "module", "handler", "action", "list", "resource", "auth", "result", "failed")
-			return c.JSON(http.StatusUnauthorized, errorResponse{Error: "not authenticated"})
+			return Error(c, http.StatusUnauthorized, CodeAuthRequired, "not authenticated")
 		}
 		logger.Error("auth me failed", "module", "handler", "action", "list", "resource", "auth", "result", "failed", "error", err)
-		return c.JSON(http.StatusInternalServerError, errorResponse{Error: "failed to get user"})
+		return Error(c, http.StatusInternalServerError, CodeInternal, "failed to get user")
 	}
 
 	logger.Debug("auth me", "module", "handler", "action", "list", "resource", "auth", "result", "ok", "actor", user.Username)
@@ -207,18 +277,18 @@ func (h *AuthHandler) UpdateProfile(c echo.Context) error {
 	var req updateProfileRequest
 	if err := c.Bind(&req); err != nil {
 		logger.Warn("auth request invalid", "module", "handler", "action", "update", "resource", "auth", "result", "failed", "error", err)
-		return c.JSON(http.StatusBadRequest, errorResponse{Error: "invalid request"})
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid request")
 	}
 
 	result, err := h.service.UpdateProfile(c.Request().Context(), req.Nickname, req.Email, req.CurrentPassword, req.NewPassword)
 	if err != nil {
 		logger.Warn("auth profile update failed", "module", "handler", "action", "update", "resource", "auth", "result", "failed", "error", err)
-		return h.handleAuthError(c, err)
+		return writeServiceError(c, err)
 	}
 
 	// Update auth cookie if new token was generated
 	if result.Token != nil {
-		setAuthCookie(c, *result.Token)
+		SetAuthCookie(c, h.settings, *result.Token)
 	}
 
 	logger.Info("auth profile updated", "module", "handler", "action", "update", "resource", "auth", "result", "ok", "actor", result.User.Username)
@@ -228,6 +298,157 @@ func (h *AuthHandler) UpdateProfile(c echo.Context) error {
 	})
 }
 
+// VerifyTwoFactor completes a login that requires a second factor.
+// @Summary Verify two-factor code
+// @Description Exchange a pending login token and a TOTP or recovery code for a JWT token
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body twoFactorVerifyRequest true "Pending token and code"
+// @Success 200 {object} authResponse
+// @Failure 400 {object} errorResponse
+// @Failure 401 {object} errorResponse
+// @Failure 429 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /auth/2fa/verify [post]
+func (h *AuthHandler) VerifyTwoFactor(c echo.Context) error {
+	var req twoFactorVerifyRequest
+	if err := c.Bind(&req); err != nil {
+		logger.Warn("auth request invalid", "module", "handler", "action", "login", "resource", "auth", "result", "failed", "error", err)
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid request")
+	}
+
+	resp, err := h.service.VerifyTwoFactor(c.Request().Context(), req.PendingToken, req.Code)
+	if err != nil {
+		logger.Warn("auth 2fa verify failed", "module", "handler", "action", "login", "resource", "auth", "result", "failed", "error", err)
+		return writeServiceError(c, err)
+	}
+
+	SetAuthCookie(c, h.settings, resp.Token)
+
+	logger.Info("auth login", "module", "handler", "action", "login", "resource", "auth", "result", "ok", "actor", resp.User.Username)
+	return c.JSON(http.StatusOK, authResponse{
+		Token: resp.Token,
+		User:  toUserResponse(resp.User),
+	})
+}
+
+// SetupTwoFactor starts two-factor enrollment.
+// @Summary Set up two-factor authentication
+// @Description Generate a TOTP secret and otpauth URL. The secret is activated by /auth/2fa/enable.
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} twoFactorSetupResponse
+// @Failure 401 {object} errorResponse
+// @Failure 409 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /auth/2fa/setup [post]
+func (h *AuthHandler) SetupTwoFactor(c echo.Context) error {
+	setup, err := h.service.SetupTwoFactor(c.Request().Context())
+	if err != nil {
+		logger.Warn("auth 2fa setup failed", "module", "handler", "action", "create", "resource", "auth", "result", "failed", "error", err)
+		return writeServiceError(c, err)
+	}
+
+	logger.Info("auth 2fa setup", "module", "handler", "action", "create", "resource", "auth", "result", "ok")
+	return c.JSON(http.StatusOK, twoFactorSetupResponse{
+		Secret:     setup.Secret,
+		OTPAuthURL: setup.OTPAuthURL,
+	})
+}
+
+// EnableTwoFactor confirms two-factor enrollment.
+// @Summary Enable two-factor authentication
+// @Description Verify a code for the pending secret and enable two-factor authentication. Returns single-use recovery codes.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body twoFactorEnableRequest true "TOTP code"
+// @Success 200 {object} twoFactorEnableResponse
+// @Failure 400 {object} errorResponse
+// @Failure 401 {object} errorResponse
+// @Failure 409 {object} errorResponse
+// @Failure 429 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /auth/2fa/enable [post]
+func (h *AuthHandler) EnableTwoFactor(c echo.Context) error {
+	var req twoFactorEnableRequest
+	if err := c.Bind(&req); err != nil {
+		logger.Warn("auth request invalid", "module", "handler", "action", "update", "resource", "auth", "result", "failed", "error", err)
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid request")
+	}
+
+	codes, err := h.service.EnableTwoFactor(c.Request().Context(), req.Code)
+	if err != nil {
+		logger.Warn("auth 2fa enable failed", "module", "handler", "action", "update", "resource", "auth", "result", "failed", "error", err)
+		return writeServiceError(c, err)
+	}
+
+	logger.Info("auth 2fa enabled", "module", "handler", "action", "update", "resource", "auth", "result", "ok")
+	return c.JSON(http.StatusOK, twoFactorEnableResponse{RecoveryCodes: codes})
+}
+
+// DisableTwoFactor turns off two-factor authentication.
+// @Summary Disable two-factor authentication
+// @Description Disable two-factor authentication after verifying the current password
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body twoFactorDisableRequest true "Current password"
+// @Success 204 "No Content"
+// @Failure 400 {object} errorResponse
+// @Failure 401 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /auth/2fa/disable [post]
+func (h *AuthHandler) DisableTwoFactor(c echo.Context) error {
+	var req twoFactorDisableRequest
+	if err := c.Bind(&req); err != nil {
+		logger.Warn("auth request invalid", "module", "handler", "action", "update", "resource", "auth", "result", "failed", "error", err)
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid request")
+	}
+
+	if err := h.service.DisableTwoFactor(c.Request().Context(), req.Password); err != nil {
+		logger.Warn("auth 2fa disable failed", "module", "handler", "action", "update", "resource", "auth", "result", "failed", "error", err)
+		return writeServiceError(c, err)
+	}
+
+	logger.Info("auth 2fa disabled", "module", "handler", "action", "update", "resource", "auth", "result", "ok")
+	return c.NoContent(http.StatusNoContent)
+}
+
+// ListLoginEvents returns the login audit log.
+// @Summary List login events
+// @Description Get the most recent login attempts (newest first, up to 100)
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} loginEventListResponse
+// @Failure 500 {object} errorResponse
+// @Router /auth/events [get]
+func (h *AuthHandler) ListLoginEvents(c echo.Context) error {
+	events, err := h.loginGuard.ListEvents(c.Request().Context())
+	if err != nil {
+		logger.Error("auth events list failed", "module", "handler", "action", "list", "resource", "login_event", "result", "failed", "error", err)
+		return writeServiceError(c, err)
+	}
+
+	items := make([]loginEventResponse, len(events))
+	for i, e := range events {
+		items[i] = loginEventResponse{
+			ID:         idToString(e.ID),
+			Identifier: e.Identifier,
+			IP:         e.IP,
+			UserAgent:  e.UserAgent,
+			Success:    e.Success,
+			CreatedAt:  e.CreatedAt.UTC().Format(time.RFC3339),
+		}
+	}
+	return c.JSON(http.StatusOK, loginEventListResponse{Items: items})
+}
+
 // Logout clears the authentication cookie.
 // @Summary Logout
 // @Description Clear authentication cookie and log out the user
@@ -236,73 +457,58 @@ func (h *AuthHandler) UpdateProfile(c echo.Context) error {
 // @Success 200 {object} map[string]string
 // @Router /auth/logout [post]
 func (h *AuthHandler) Logout(c echo.Context) error {
-	clearAuthCookie(c)
+	ClearAuthCookie(c, h.settings)
 	logger.Info("auth logout", "module", "handler", "action", "logout", "resource", "auth", "result", "ok")
 	return c.JSON(http.StatusOK, map[string]string{"message": "logged out"})
 }
 
-func (h *AuthHandler) handleAuthError(c echo.Context, err error) error {
-	switch {
-	case errors.Is(err, service.ErrUserExists):
-		return c.JSON(http.StatusConflict, errorResponse{Error: "user already exists"})
-	case errors.Is(err, service.ErrUserNotFound):
-		return c.JSON(http.StatusUnauthorized, errorResponse{Error: "user not found"})
-	case errors.Is(err, service.ErrInvalidPassword):
-		return c.JSON(http.StatusUnauthorized, errorResponse{Error: "invalid credentials"})
-	case errors.Is(err, service.ErrUsernameRequired):
-		return c.JSON(http.StatusBadRequest, errorResponse{Error: "username is required"})
-	case errors.Is(err, service.ErrInvalidUsername):
-		return c.JSON(http.StatusBadRequest, errorResponse{Error: "username must be lowercase letters and numbers only"})
-	case errors.Is(err, service.ErrEmailRequired):
-		return c.JSON(http.StatusBadRequest, errorResponse{Error: "email is required"})
-	case errors.Is(err, service.ErrPasswordRequired):
-		return c.JSON(http.StatusBadRequest, errorResponse{Error: "password is required"})
-	case errors.Is(err, service.ErrPasswordTooShort):
-		return c.JSON(http.StatusBadRequest, errorResponse{Error: "password must be at least 6 characters"})
-	case errors.Is(err, service.ErrCurrentPasswordRequired):
-		return c.JSON(http.StatusBadRequest, errorResponse{Error: "current password is required"})
-	case errors.Is(err, service.ErrSamePassword):
-		return c.JSON(http.StatusBadRequest, errorResponse{Error: "new password must be different from current password"})
-	default:
-		logger.Error("auth request failed", "module", "handler", "action", "request", "resource", "auth", "result", "failed", "error", err)
-		return c.JSON(http.StatusInternalServerError, errorResponse{Error: "internal error"})
-	}
-}
-
 func toUserResponse(user *service.User) *userResponse {
 	if user == nil {
 		return nil
 	}
 	return &userResponse{
-		Username:  user.Username,
-		Nickname:  user.Nickname,
-		Email:     user.Email,
-		AvatarURL: user.AvatarURL,
+		Username:         user.Username,
+		Nickname:         user.Nickname,
+		Email:            user.Email,
+		AvatarURL:        user.AvatarURL,
+		TwoFactorEnabled: user.TwoFactorEnabled,
 	}
 }
 
-// setAuthCookie sets the authentication cookie for browser resource requests.
-func setAuthCookie(c echo.Context, token string) {
+// SetAuthCookie sets the authentication cookie for browser resource requests.
+func SetAuthCookie(c echo.Context, settings service.SettingsService, token string) {
+	c.SetCookie(newAuthCookie(c, settings, token, 30*24*60*60)) // 30 days (same as JWT expiry)
+}
+
+// ClearAuthCookie clears the authentication cookie.
+// The attributes must match SetAuthCookie or browsers keep the old cookie.
+func ClearAuthCookie(c echo.Context, settings service.SettingsService) {
+	c.SetCookie(newAuthCookie(c, settings, "", -1))
+}
+
+func newAuthCookie(c echo.Context, settings service.SettingsService, value string, maxAge int) *http.Cookie {
+	security, err := settings.GetSecuritySettings(c.Request().Context())
+	if err != nil {
+		logger.Warn("auth cookie settings load failed", "module", "handler", "action", "request", "resource", "auth", "result", "failed", "error", err)
+	}
+
 	cookie := &http.Cookie{
 		Name:     authCookieName,
-		Value:    token,
+		Value:    value,
 		Path:     "/",
 		HttpOnly: true,
 		Secure:   c.Request().TLS != nil, // Secure if HTTPS
 		SameSite: http.SameSiteLaxMode,
-		MaxAge:   30 * 24 * 60 * 60, // 30 days (same as JWT expiry)
+		MaxAge:   maxAge,
 	}
-	c.SetCookie(cookie)
-}
-
-// clearAuthCookie clears the authentication cookie.
-func clearAuthCookie(c echo.Context) {
-	cookie := &http.Cookie{
-		Name:     authCookieName,
-		Value:    "",
-		Path:     "/",
-		HttpOnly: true,
-		MaxAge:   -1, // Delete cookie
+	if security != nil {
+		if security.CookieSecure {
+			cookie.Secure = true
+		}
+		if security.CookieSameSite == "none" && cookie.Secure {
+			cookie.SameSite = http.SameSiteNoneMode
+		}
+		cookie.Domain = security.CookieDomain
 	}
-	c.SetCookie(cookie)
+	return cookie
 }
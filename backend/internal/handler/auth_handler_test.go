@@ -1,11 +1,14 @@
 package handler_test
 
 import (
+	"crypto/tls"
 	"errors"
 	"net/http"
 	"testing"
+	"time"
 
 	"gist/backend/internal/handler"
+	"gist/backend/internal/model"
 	"gist/backend/internal/service"
 	"gist/backend/internal/service/mock"
 
@@ -13,12 +16,22 @@ import (
 	"go.uber.org/mock/gomock"
 )
 
+// newSecuritySettingsMock returns a settings service serving the given cookie settings (default Lax when nil).
+func newSecuritySettingsMock(ctrl *gomock.Controller, settings *service.SecuritySettings) *mock.MockSettingsService {
+	if settings == nil {
+		settings = &service.SecuritySettings{CookieSameSite: "lax"}
+	}
+	m := mock.NewMockSettingsService(ctrl)
+	m.EXPECT().GetSecuritySettings(gomock.Any()).Return(settings, nil).AnyTimes()
+	return m
+}
+
 func TestAuthHandler_GetStatus_Success(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockService := mock.NewMockAuthService(ctrl)
-	h := handler.NewAuthHandlerHelper(mockService)
+	h := handler.NewAuthHandlerHelper(mockService, nil, nil)
 
 	e := newTestEcho()
 	req := newJSONRequest(http.MethodGet, "/auth/status", nil)
@@ -41,7 +54,7 @@ func TestAuthHandler_Register_Success(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockService := mock.NewMockAuthService(ctrl)
-	h := handler.NewAuthHandlerHelper(mockService)
+	h := handler.NewAuthHandlerHelper(mockService, nil, newSecuritySettingsMock(ctrl, nil))
 
 	e := newTestEcho()
 	reqBody := map[string]interface{}{
@@ -82,7 +95,8 @@ func TestAuthHandler_Login_Success(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockService := mock.NewMockAuthService(ctrl)
-	h := handler.NewAuthHandlerHelper(mockService)
+	mockGuard := mock.NewMockLoginGuardService(ctrl)
+	h := handler.NewAuthHandlerHelper(mockService, mockGuard, newSecuritySettingsMock(ctrl, nil))
 
 	e := newTestEcho()
 	reqBody := map[string]interface{}{
@@ -100,9 +114,11 @@ func TestAuthHandler_Login_Success(t *testing.T) {
 		},
 	}
 
+	mockGuard.EXPECT().Check(gomock.Any(), "alice").Return(nil)
 	mockService.EXPECT().
 		Login(gomock.Any(), "alice", "secret123").
 		Return(authResp, nil)
+	mockGuard.EXPECT().RecordSuccess(gomock.Any(), gomock.Any(), gomock.Any(), "alice")
 
 	err := h.Login(c)
 	require.NoError(t, err)
@@ -117,7 +133,8 @@ func TestAuthHandler_Login_InvalidCredentials(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockService := mock.NewMockAuthService(ctrl)
-	h := handler.NewAuthHandlerHelper(mockService)
+	mockGuard := mock.NewMockLoginGuardService(ctrl)
+	h := handler.NewAuthHandlerHelper(mockService, mockGuard, nil)
 
 	e := newTestEcho()
 	reqBody := map[string]interface{}{
@@ -127,14 +144,18 @@ func TestAuthHandler_Login_InvalidCredentials(t *testing.T) {
 	req := newJSONRequest(http.MethodPost, "/auth/login", reqBody)
 	c, rec := newTestContext(e, req)
 
+	mockGuard.EXPECT().Check(gomock.Any(), "alice").Return(nil)
 	mockService.EXPECT().
 		Login(gomock.Any(), "alice", "wrong").
 		Return(nil, service.ErrInvalidPassword)
+	mockGuard.EXPECT().RecordFailure(gomock.Any(), gomock.Any(), gomock.Any(), "alice")
 
 	err := h.Login(c)
 	require.NoError(t, err)
 
-	require.Equal(t, http.StatusUnauthorized, rec.Code)
+	var resp handler.ErrorResponse
+	assertJSONResponse(t, rec, http.StatusUnauthorized, &resp)
+	require.Equal(t, "auth_invalid_credentials", resp.Code)
 }
 
 func TestAuthHandler_GetCurrentUser_Success(t *testing.T) {
@@ -142,7 +163,7 @@ func TestAuthHandler_GetCurrentUser_Success(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockService := mock.NewMockAuthService(ctrl)
-	h := handler.NewAuthHandlerHelper(mockService)
+	h := handler.NewAuthHandlerHelper(mockService, nil, nil)
 
 	e := newTestEcho()
 	req := newJSONRequest(http.MethodGet, "/auth/me", nil)
@@ -171,7 +192,7 @@ func TestAuthHandler_UpdateProfile_Success(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockService := mock.NewMockAuthService(ctrl)
-	h := handler.NewAuthHandlerHelper(mockService)
+	h := handler.NewAuthHandlerHelper(mockService, nil, nil)
 
 	e := newTestEcho()
 	reqBody := map[string]interface{}{
@@ -204,7 +225,7 @@ func TestAuthHandler_Logout_Success(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockService := mock.NewMockAuthService(ctrl)
-	h := handler.NewAuthHandlerHelper(mockService)
+	h := handler.NewAuthHandlerHelper(mockService, nil, newSecuritySettingsMock(ctrl, nil))
 
 	e := newTestEcho()
 	req := newJSONRequest(http.MethodPost, "/auth/logout", nil)
@@ -232,7 +253,7 @@ func TestAuthHandler_Register_UserExists(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockService := mock.NewMockAuthService(ctrl)
-	h := handler.NewAuthHandlerHelper(mockService)
+	h := handler.NewAuthHandlerHelper(mockService, nil, nil)
 
 	e := newTestEcho()
 	reqBody := map[string]interface{}{
@@ -249,7 +270,10 @@ func TestAuthHandler_Register_UserExists(t *testing.T) {
 
 	err := h.Register(c)
 	require.NoError(t, err)
-	require.Equal(t, http.StatusConflict, rec.Code)
+
+	var resp handler.ErrorResponse
+	assertJSONResponse(t, rec, http.StatusConflict, &resp)
+	require.Equal(t, "auth_user_exists", resp.Code)
 }
 
 func TestAuthHandler_Register_UsernameRequired(t *testing.T) {
@@ -257,7 +281,7 @@ func TestAuthHandler_Register_UsernameRequired(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockService := mock.NewMockAuthService(ctrl)
-	h := handler.NewAuthHandlerHelper(mockService)
+	h := handler.NewAuthHandlerHelper(mockService, nil, nil)
 
 	e := newTestEcho()
 	reqBody := map[string]interface{}{
@@ -282,7 +306,7 @@ func TestAuthHandler_Register_InvalidUsername(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockService := mock.NewMockAuthService(ctrl)
-	h := handler.NewAuthHandlerHelper(mockService)
+	h := handler.NewAuthHandlerHelper(mockService, nil, nil)
 
 	e := newTestEcho()
 	reqBody := map[string]interface{}{
@@ -307,7 +331,7 @@ func TestAuthHandler_Register_EmailRequired(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockService := mock.NewMockAuthService(ctrl)
-	h := handler.NewAuthHandlerHelper(mockService)
+	h := handler.NewAuthHandlerHelper(mockService, nil, nil)
 
 	e := newTestEcho()
 	reqBody := map[string]interface{}{
@@ -332,7 +356,7 @@ func TestAuthHandler_Register_PasswordRequired(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockService := mock.NewMockAuthService(ctrl)
-	h := handler.NewAuthHandlerHelper(mockService)
+	h := handler.NewAuthHandlerHelper(mockService, nil, nil)
 
 	e := newTestEcho()
 	reqBody := map[string]interface{}{
@@ -357,7 +381,7 @@ func TestAuthHandler_Register_PasswordTooShort(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockService := mock.NewMockAuthService(ctrl)
-	h := handler.NewAuthHandlerHelper(mockService)
+	h := handler.NewAuthHandlerHelper(mockService, nil, nil)
 
 	e := newTestEcho()
 	reqBody := map[string]interface{}{
@@ -382,7 +406,8 @@ func TestAuthHandler_Login_UserNotFound(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockService := mock.NewMockAuthService(ctrl)
-	h := handler.NewAuthHandlerHelper(mockService)
+	mockGuard := mock.NewMockLoginGuardService(ctrl)
+	h := handler.NewAuthHandlerHelper(mockService, mockGuard, nil)
 
 	e := newTestEcho()
 	reqBody := map[string]interface{}{
@@ -392,9 +417,11 @@ func TestAuthHandler_Login_UserNotFound(t *testing.T) {
 	req := newJSONRequest(http.MethodPost, "/auth/login", reqBody)
 	c, rec := newTestContext(e, req)
 
+	mockGuard.EXPECT().Check(gomock.Any(), "unknown").Return(nil)
 	mockService.EXPECT().
 		Login(gomock.Any(), "unknown", "secret123").
 		Return(nil, service.ErrUserNotFound)
+	mockGuard.EXPECT().RecordFailure(gomock.Any(), gomock.Any(), gomock.Any(), "unknown")
 
 	err := h.Login(c)
 	require.NoError(t, err)
@@ -406,7 +433,7 @@ func TestAuthHandler_UpdateProfile_CurrentPasswordRequired(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockService := mock.NewMockAuthService(ctrl)
-	h := handler.NewAuthHandlerHelper(mockService)
+	h := handler.NewAuthHandlerHelper(mockService, nil, nil)
 
 	e := newTestEcho()
 	reqBody := map[string]interface{}{
@@ -429,7 +456,7 @@ func TestAuthHandler_UpdateProfile_SamePassword(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockService := mock.NewMockAuthService(ctrl)
-	h := handler.NewAuthHandlerHelper(mockService)
+	h := handler.NewAuthHandlerHelper(mockService, nil, nil)
 
 	e := newTestEcho()
 	reqBody := map[string]interface{}{
@@ -453,7 +480,7 @@ func TestAuthHandler_GetStatus_Error(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockService := mock.NewMockAuthService(ctrl)
-	h := handler.NewAuthHandlerHelper(mockService)
+	h := handler.NewAuthHandlerHelper(mockService, nil, nil)
 
 	e := newTestEcho()
 	req := newJSONRequest(http.MethodGet, "/auth/status", nil)
@@ -473,7 +500,7 @@ func TestAuthHandler_GetCurrentUser_NotFound(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockService := mock.NewMockAuthService(ctrl)
-	h := handler.NewAuthHandlerHelper(mockService)
+	h := handler.NewAuthHandlerHelper(mockService, nil, nil)
 
 	e := newTestEcho()
 	req := newJSONRequest(http.MethodGet, "/auth/me", nil)
@@ -493,7 +520,7 @@ func TestAuthHandler_Register_InternalError(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockService := mock.NewMockAuthService(ctrl)
-	h := handler.NewAuthHandlerHelper(mockService)
+	h := handler.NewAuthHandlerHelper(mockService, nil, nil)
 
 	e := newTestEcho()
 	reqBody := map[string]interface{}{
@@ -518,7 +545,7 @@ func TestAuthHandler_Register_InvalidJSON(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockService := mock.NewMockAuthService(ctrl)
-	h := handler.NewAuthHandlerHelper(mockService)
+	h := handler.NewAuthHandlerHelper(mockService, nil, nil)
 
 	e := newTestEcho()
 	req := newJSONRequestRaw(http.MethodPost, "/auth/register", "{invalid json")
@@ -534,7 +561,7 @@ func TestAuthHandler_Login_InvalidJSON(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockService := mock.NewMockAuthService(ctrl)
-	h := handler.NewAuthHandlerHelper(mockService)
+	h := handler.NewAuthHandlerHelper(mockService, nil, nil)
 
 	e := newTestEcho()
 	req := newJSONRequestRaw(http.MethodPost, "/auth/login", "{invalid json")
@@ -550,7 +577,7 @@ func TestAuthHandler_UpdateProfile_InvalidJSON(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockService := mock.NewMockAuthService(ctrl)
-	h := handler.NewAuthHandlerHelper(mockService)
+	h := handler.NewAuthHandlerHelper(mockService, nil, nil)
 
 	e := newTestEcho()
 	req := newJSONRequestRaw(http.MethodPut, "/auth/profile", "{invalid json")
@@ -566,7 +593,8 @@ func TestAuthHandler_Login_InternalError(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockService := mock.NewMockAuthService(ctrl)
-	h := handler.NewAuthHandlerHelper(mockService)
+	mockGuard := mock.NewMockLoginGuardService(ctrl)
+	h := handler.NewAuthHandlerHelper(mockService, mockGuard, nil)
 
 	e := newTestEcho()
 	reqBody := map[string]interface{}{
@@ -576,6 +604,7 @@ func TestAuthHandler_Login_InternalError(t *testing.T) {
 	req := newJSONRequest(http.MethodPost, "/auth/login", reqBody)
 	c, rec := newTestContext(e, req)
 
+	mockGuard.EXPECT().Check(gomock.Any(), "alice").Return(nil)
 	mockService.EXPECT().
 		Login(gomock.Any(), "alice", "secret123").
 		Return(nil, errors.New("unexpected database error"))
@@ -590,7 +619,7 @@ func TestAuthHandler_GetCurrentUser_InternalError(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockService := mock.NewMockAuthService(ctrl)
-	h := handler.NewAuthHandlerHelper(mockService)
+	h := handler.NewAuthHandlerHelper(mockService, nil, nil)
 
 	e := newTestEcho()
 	req := newJSONRequest(http.MethodGet, "/auth/me", nil)
@@ -610,7 +639,7 @@ func TestAuthHandler_UpdateProfile_InternalError(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockService := mock.NewMockAuthService(ctrl)
-	h := handler.NewAuthHandlerHelper(mockService)
+	h := handler.NewAuthHandlerHelper(mockService, nil, nil)
 
 	e := newTestEcho()
 	reqBody := map[string]interface{}{
@@ -633,7 +662,7 @@ func TestAuthHandler_UpdateProfile_PasswordChange_WithNewToken(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockService := mock.NewMockAuthService(ctrl)
-	h := handler.NewAuthHandlerHelper(mockService)
+	h := handler.NewAuthHandlerHelper(mockService, nil, newSecuritySettingsMock(ctrl, nil))
 
 	e := newTestEcho()
 	reqBody := map[string]interface{}{
@@ -667,3 +696,334 @@ func TestAuthHandler_UpdateProfile_PasswordChange_WithNewToken(t *testing.T) {
 	cookies := rec.Result().Cookies()
 	require.NotEmpty(t, cookies)
 }
+
+func TestAuthHandler_Login_Requires2FA(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockAuthService(ctrl)
+	mockGuard := mock.NewMockLoginGuardService(ctrl)
+	h := handler.NewAuthHandlerHelper(mockService, mockGuard, nil)
+
+	e := newTestEcho()
+	reqBody := map[string]interface{}{
+		"identifier": "alice",
+		"password":   "secret123",
+	}
+	req := newJSONRequest(http.MethodPost, "/auth/login", reqBody)
+	c, rec := newTestContext(e, req)
+
+	mockGuard.EXPECT().Check(gomock.Any(), "alice").Return(nil)
+	mockService.EXPECT().
+		Login(gomock.Any(), "alice", "secret123").
+		Return(&service.AuthResponse{Requires2FA: true, PendingToken: "pending"}, nil)
+	mockGuard.EXPECT().RecordSuccess(gomock.Any(), gomock.Any(), gomock.Any(), "alice")
+
+	err := h.Login(c)
+	require.NoError(t, err)
+
+	var resp handler.AuthResponseDTO
+	assertJSONResponse(t, rec, http.StatusOK, &resp)
+	require.True(t, resp.Requires2FA)
+	require.Equal(t, "pending", resp.PendingToken)
+	require.Empty(t, resp.Token)
+	require.Empty(t, rec.Header().Get("Set-Cookie"), "cookie must not be set before 2fa")
+}
+
+func TestAuthHandler_VerifyTwoFactor_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockAuthService(ctrl)
+	h := handler.NewAuthHandlerHelper(mockService, nil, newSecuritySettingsMock(ctrl, nil))
+
+	e := newTestEcho()
+	reqBody := map[string]interface{}{
+		"pendingToken": "pending",
+		"code":         "123456",
+	}
+	req := newJSONRequest(http.MethodPost, "/auth/2fa/verify", reqBody)
+	c, rec := newTestContext(e, req)
+
+	mockService.EXPECT().
+		VerifyTwoFactor(gomock.Any(), "pending", "123456").
+		Return(&service.AuthResponse{
+			Token: "real-token",
+			User:  &service.User{Username: "alice", TwoFactorEnabled: true},
+		}, nil)
+
+	err := h.VerifyTwoFactor(c)
+	require.NoError(t, err)
+
+	var resp handler.AuthResponseDTO
+	assertJSONResponse(t, rec, http.StatusOK, &resp)
+	require.Equal(t, "real-token", resp.Token)
+	require.True(t, resp.User.TwoFactorEnabled)
+	require.Contains(t, rec.Header().Get("Set-Cookie"), "gist_auth=real-token")
+}
+
+func TestAuthHandler_VerifyTwoFactor_Errors(t *testing.T) {
+	cases := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantCode   string
+	}{
+		{name: "invalid code", err: service.ErrTwoFactorInvalidCode, wantStatus: http.StatusUnauthorized, wantCode: "auth_two_factor_invalid"},
+		{name: "invalid token", err: service.ErrInvalidToken, wantStatus: http.StatusUnauthorized, wantCode: "auth_invalid_token"},
+		{name: "rate limited", err: service.ErrTooManyAttempts, wantStatus: http.StatusTooManyRequests, wantCode: "auth_too_many_attempts"},
+		{name: "missing code", err: service.ErrTwoFactorCodeRequired, wantStatus: http.StatusBadRequest, wantCode: "auth_two_factor_required"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mock.NewMockAuthService(ctrl)
+			h := handler.NewAuthHandlerHelper(mockService, nil, nil)
+
+			e := newTestEcho()
+			req := newJSONRequest(http.MethodPost, "/auth/2fa/verify", map[string]interface{}{"pendingToken": "p", "code": "1"})
+			c, rec := newTestContext(e, req)
+
+			mockService.EXPECT().
+				VerifyTwoFactor(gomock.Any(), "p", "1").
+				Return(nil, tc.err)
+
+			err := h.VerifyTwoFactor(c)
+			require.NoError(t, err)
+
+			var resp handler.ErrorResponse
+			assertJSONResponse(t, rec, tc.wantStatus, &resp)
+			require.Equal(t, tc.wantCode, resp.Code)
+		})
+	}
+}
+
+func TestAuthHandler_SetupTwoFactor_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockAuthService(ctrl)
+	h := handler.NewAuthHandlerHelper(mockService, nil, nil)
+
+	e := newTestEcho()
+	req := newJSONRequest(http.MethodPost, "/auth/2fa/setup", nil)
+	c, rec := newTestContext(e, req)
+
+	mockService.EXPECT().
+		SetupTwoFactor(gomock.Any()).
+		Return(&service.TwoFactorSetup{Secret: "ABC", OTPAuthURL: "otpauth://totp/Gist:alice?secret=ABC"}, nil)
+
+	err := h.SetupTwoFactor(c)
+	require.NoError(t, err)
+
+	var resp handler.TwoFactorSetupResponse
+	assertJSONResponse(t, rec, http.StatusOK, &resp)
+	require.Equal(t, "ABC", resp.Secret)
+	require.Contains(t, resp.OTPAuthURL, "otpauth://")
+}
+
+func TestAuthHandler_SetupTwoFactor_AlreadyEnabled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockAuthService(ctrl)
+	h := handler.NewAuthHandlerHelper(mockService, nil, nil)
+
+	e := newTestEcho()
+	req := newJSONRequest(http.MethodPost, "/auth/2fa/setup", nil)
+	c, rec := newTestContext(e, req)
+
+	mockService.EXPECT().
+		SetupTwoFactor(gomock.Any()).
+		Return(nil, service.ErrTwoFactorEnabled)
+
+	err := h.SetupTwoFactor(c)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusConflict, rec.Code)
+}
+
+func TestAuthHandler_EnableTwoFactor_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockAuthService(ctrl)
+	h := handler.NewAuthHandlerHelper(mockService, nil, nil)
+
+	e := newTestEcho()
+	req := newJSONRequest(http.MethodPost, "/auth/2fa/enable", map[string]interface{}{"code": "123456"})
+	c, rec := newTestContext(e, req)
+
+	mockService.EXPECT().
+		EnableTwoFactor(gomock.Any(), "123456").
+		Return([]string{"aaaaa-bbbbb"}, nil)
+
+	err := h.EnableTwoFactor(c)
+	require.NoError(t, err)
+
+	var resp handler.TwoFactorEnableResponse
+	assertJSONResponse(t, rec, http.StatusOK, &resp)
+	require.Equal(t, []string{"aaaaa-bbbbb"}, resp.RecoveryCodes)
+}
+
+func TestAuthHandler_DisableTwoFactor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockAuthService(ctrl)
+	h := handler.NewAuthHandlerHelper(mockService, nil, nil)
+
+	e := newTestEcho()
+	req := newJSONRequest(http.MethodPost, "/auth/2fa/disable", map[string]interface{}{"password": "secret123"})
+	c, rec := newTestContext(e, req)
+
+	mockService.EXPECT().
+		DisableTwoFactor(gomock.Any(), "secret123").
+		Return(nil)
+
+	err := h.DisableTwoFactor(c)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusNoContent, rec.Code)
+
+	req = newJSONRequest(http.MethodPost, "/auth/2fa/disable", map[string]interface{}{"password": "wrong"})
+	c, rec = newTestContext(e, req)
+
+	mockService.EXPECT().
+		DisableTwoFactor(gomock.Any(), "wrong").
+		Return(service.ErrInvalidPassword)
+
+	err = h.DisableTwoFactor(c)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestAuthHandler_Login_Locked(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockAuthService(ctrl)
+	mockGuard := mock.NewMockLoginGuardService(ctrl)
+	h := handler.NewAuthHandlerHelper(mockService, mockGuard, nil)
+
+	e := newTestEcho()
+	reqBody := map[string]interface{}{
+		"identifier": "alice",
+		"password":   "guess",
+	}
+	req := newJSONRequest(http.MethodPost, "/auth/login", reqBody)
+	c, rec := newTestContext(e, req)
+
+	mockGuard.EXPECT().
+		Check(gomock.Any(), "alice").
+		Return(&service.LoginLockedError{RetryAfter: 90*time.Second + 300*time.Millisecond})
+
+	err := h.Login(c)
+	require.NoError(t, err)
+	var resp handler.ErrorResponse
+	assertJSONResponse(t, rec, http.StatusTooManyRequests, &resp)
+	require.Equal(t, "auth_too_many_attempts", resp.Code)
+	require.Equal(t, "91", rec.Header().Get("Retry-After"))
+}
+
+func TestAuthHandler_ListLoginEvents(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockAuthService(ctrl)
+	mockGuard := mock.NewMockLoginGuardService(ctrl)
+	h := handler.NewAuthHandlerHelper(mockService, mockGuard, nil)
+
+	e := newTestEcho()
+	req := newJSONRequest(http.MethodGet, "/auth/events", nil)
+	c, rec := newTestContext(e, req)
+
+	mockGuard.EXPECT().
+		ListEvents(gomock.Any()).
+		Return([]model.LoginEvent{{ID: 1, Identifier: "alice", IP: "10.0.0.1", UserAgent: "curl", Success: false, CreatedAt: time.Now()}}, nil)
+
+	err := h.ListLoginEvents(c)
+	require.NoError(t, err)
+
+	var resp handler.LoginEventListResponse
+	assertJSONResponse(t, rec, http.StatusOK, &resp)
+	require.Len(t, resp.Items, 1)
+	require.Equal(t, "10.0.0.1", resp.Items[0].IP)
+	require.False(t, resp.Items[0].Success)
+}
+
+func TestAuthHandler_Login_CookieAttributes(t *testing.T) {
+	cases := []struct {
+		name         string
+		settings     *service.SecuritySettings
+		tls          bool
+		wantSameSite http.SameSite
+		wantSecure   bool
+		wantDomain   string
+	}{
+		{name: "default lax over http", settings: &service.SecuritySettings{CookieSameSite: "lax"}, wantSameSite: http.SameSiteLaxMode},
+		{name: "default lax over https", settings: &service.SecuritySettings{CookieSameSite: "lax"}, tls: true, wantSameSite: http.SameSiteLaxMode, wantSecure: true},
+		{name: "forced secure", settings: &service.SecuritySettings{CookieSameSite: "lax", CookieSecure: true}, wantSameSite: http.SameSiteLaxMode, wantSecure: true},
+		{name: "samesite none", settings: &service.SecuritySettings{CookieSameSite: "none", CookieSecure: true}, wantSameSite: http.SameSiteNoneMode, wantSecure: true},
+		{name: "cookie domain", settings: &service.SecuritySettings{CookieSameSite: "lax", CookieDomain: "example.com"}, wantSameSite: http.SameSiteLaxMode, wantDomain: "example.com"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mock.NewMockAuthService(ctrl)
+			mockGuard := mock.NewMockLoginGuardService(ctrl)
+			h := handler.NewAuthHandlerHelper(mockService, mockGuard, newSecuritySettingsMock(ctrl, tc.settings))
+
+			e := newTestEcho()
+			req := newJSONRequest(http.MethodPost, "/auth/login", map[string]interface{}{"identifier": "alice", "password": "secret123"})
+			if tc.tls {
+				req.TLS = &tls.ConnectionState{}
+			}
+			c, rec := newTestContext(e, req)
+
+			mockGuard.EXPECT().Check(gomock.Any(), "alice").Return(nil)
+			mockService.EXPECT().
+				Login(gomock.Any(), "alice", "secret123").
+				Return(&service.AuthResponse{Token: "tok", User: &service.User{Username: "alice"}}, nil)
+			mockGuard.EXPECT().RecordSuccess(gomock.Any(), gomock.Any(), gomock.Any(), "alice")
+
+			require.NoError(t, h.Login(c))
+			require.Equal(t, http.StatusOK, rec.Code)
+
+			cookies := rec.Result().Cookies()
+			require.Len(t, cookies, 1)
+			cookie := cookies[0]
+			require.Equal(t, "gist_auth", cookie.Name)
+			require.Equal(t, "tok", cookie.Value)
+			require.True(t, cookie.HttpOnly)
+			require.Equal(t, tc.wantSameSite, cookie.SameSite)
+			require.Equal(t, tc.wantSecure, cookie.Secure)
+			require.Equal(t, tc.wantDomain, cookie.Domain)
+		})
+	}
+}
+
+func TestAuthHandler_Logout_CookieMatchesSettings(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	settings := &service.SecuritySettings{CookieSameSite: "none", CookieSecure: true, CookieDomain: "example.com"}
+	h := handler.NewAuthHandlerHelper(mock.NewMockAuthService(ctrl), nil, newSecuritySettingsMock(ctrl, settings))
+
+	e := newTestEcho()
+	req := newJSONRequest(http.MethodPost, "/auth/logout", nil)
+	c, rec := newTestContext(e, req)
+
+	require.NoError(t, h.Logout(c))
+
+	header := rec.Header().Get("Set-Cookie")
+	require.Contains(t, header, "gist_auth=;")
+	require.Contains(t, header, "Domain=example.com")
+	require.Contains(t, header, "Secure")
+	require.Contains(t, header, "SameSite=None")
+	require.Contains(t, header, "Max-Age=0")
+}
@@ -0,0 +1,125 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"gist/backend/internal/service"
+	"gist/backend/pkg/logger"
+)
+
+// maxBackupSize bounds full imports; the document is streamed, so this only caps disk and time.
+const maxBackupSize = 1 << 30
+
+type BackupHandler struct {
+	service service.BackupService
+}
+
+type backupSectionResponse struct {
+	Created int `json:"created"`
+	Updated int `json:"updated"`
+	Skipped int `json:"skipped"`
+}
+
+type backupImportResponse struct {
+	Folders backupSectionResponse `json:"folders"`
+	Feeds   backupSectionResponse `json:"feeds"`
+	Entries backupSectionResponse `json:"entries"`
+}
+
+func NewBackupHandler(svc service.BackupService) *BackupHandler {
+	return &BackupHandler{service: svc}
+}
+
+func (h *BackupHandler) RegisterRoutes(g *echo.Group) {
+	g.GET("/export/full", h.Export)
+	g.POST("/import/full", h.Import)
+}
+
+// Export streams folders, feeds and entries as one JSON document.
+// @Summary Export full backup
+// @Description Export folders, feeds (with conditional GET validators and icon reference) and entries as JSON that POST /import/full accepts. By default only unread, starred and annotated entries are included.
+// @Tags backup
+// @Produce json
+// @Param entries query string false "Entries to include: unread_starred (default), all, or none"
+// @Success 200 {file} file "Backup document"
+// @Failure 400 {object} errorResponse
+// @Router /export/full [get]
+func (h *BackupHandler) Export(c echo.Context) error {
+	entries := c.QueryParam("entries")
+	switch entries {
+	case "":
+		entries = service.BackupEntriesUnreadStarred
+	case service.BackupEntriesUnreadStarred, service.BackupEntriesAll, service.BackupEntriesNone:
+	default:
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "entries must be unread_starred, all, or none")
+	}
+
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+	res.Header().Set("Content-Disposition", `attachment; filename="gist-backup.json"`)
+	res.WriteHeader(http.StatusOK)
+
+	// The status is already sent, so a failure can only cut the document short
+	if err := h.service.Export(c.Request().Context(), res, service.BackupExportOptions{Entries: entries}); err != nil {
+		logger.Error("backup export failed", "module", "handler", "action", "export", "resource", "backup", "result", "failed", "error", err)
+		return nil
+	}
+	logger.Info("backup export", "module", "handler", "action", "export", "resource", "backup", "result", "ok", "entries", entries)
+	return nil
+}
+
+// Import restores a document produced by Export.
+// @Summary Import full backup
+// @Description Import a full backup. Feeds are matched by canonical URL and entries by hash, so importing twice is harmless; existing entries only take over read and starred state.
+// @Tags backup
+// @Accept json
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file false "Backup file to import"
+// @Success 200 {object} backupImportResponse
+// @Failure 400 {object} errorResponse
+// @Failure 413 {object} errorResponse
+// @Router /import/full [post]
+func (h *BackupHandler) Import(c echo.Context) error {
+	req := c.Request()
+	req.Body = http.MaxBytesReader(c.Response().Writer, req.Body, maxBackupSize)
+
+	var reader io.Reader = req.Body
+	if strings.HasPrefix(req.Header.Get(echo.HeaderContentType), "multipart/") {
+		file, err := c.FormFile("file")
+		if err != nil {
+			if err == http.ErrMissingFile {
+				return Error(c, http.StatusBadRequest, CodeInvalidRequest, "missing file")
+			}
+			return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid request")
+		}
+		src, err := file.Open()
+		if err != nil {
+			return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid request")
+		}
+		defer src.Close()
+		reader = src
+	}
+
+	result, err := h.service.Import(req.Context(), reader)
+	if err != nil {
+		logger.Warn("backup import failed", "module", "handler", "action", "import", "resource", "backup", "result", "failed", "error", err)
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			return Error(c, http.StatusRequestEntityTooLarge, CodePayloadTooLarge, "file too large")
+		}
+		return writeServiceError(c, err)
+	}
+
+	logger.Info("backup import", "module", "handler", "action", "import", "resource", "backup", "result", "ok", "feeds_created", result.Feeds.Created, "entries_created", result.Entries.Created)
+	return c.JSON(http.StatusOK, backupImportResponse{
+		Folders: backupSectionResponse(result.Folders),
+		Feeds:   backupSectionResponse(result.Feeds),
+		Entries: backupSectionResponse(result.Entries),
+	})
+}
@@ -0,0 +1,154 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gist/backend/internal/handler"
+	"gist/backend/internal/service"
+	"gist/backend/internal/service/mock"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestBackupHandler_Export(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockService := mock.NewMockBackupService(ctrl)
+	h := handler.NewBackupHandler(mockService)
+	e := newTestEcho()
+
+	req := httptest.NewRequest(http.MethodGet, "/export/full?entries=all", nil)
+	c, rec := newTestContext(e, req)
+
+	mockService.EXPECT().
+		Export(gomock.Any(), gomock.Any(), service.BackupExportOptions{Entries: service.BackupEntriesAll}).
+		DoAndReturn(func(_ context.Context, w io.Writer, _ service.BackupExportOptions) error {
+			_, err := io.WriteString(w, `{"version":1}`)
+			return err
+		})
+
+	require.NoError(t, h.Export(c))
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Contains(t, rec.Header().Get("Content-Disposition"), "gist-backup.json")
+	require.JSONEq(t, `{"version":1}`, rec.Body.String())
+}
+
+func TestBackupHandler_Export_DefaultsToUnreadStarred(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockService := mock.NewMockBackupService(ctrl)
+	h := handler.NewBackupHandler(mockService)
+	e := newTestEcho()
+
+	c, _ := newTestContext(e, httptest.NewRequest(http.MethodGet, "/export/full", nil))
+	mockService.EXPECT().
+		Export(gomock.Any(), gomock.Any(), service.BackupExportOptions{Entries: service.BackupEntriesUnreadStarred}).
+		Return(nil)
+
+	require.NoError(t, h.Export(c))
+}
+
+func TestBackupHandler_Export_InvalidEntries(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	h := handler.NewBackupHandler(mock.NewMockBackupService(ctrl))
+	e := newTestEcho()
+
+	c, rec := newTestContext(e, httptest.NewRequest(http.MethodGet, "/export/full?entries=some", nil))
+
+	require.NoError(t, h.Export(c))
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestBackupHandler_Import(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockService := mock.NewMockBackupService(ctrl)
+	h := handler.NewBackupHandler(mockService)
+	e := newTestEcho()
+
+	req := newJSONRequestRaw(http.MethodPost, "/import/full", `{"version":1}`)
+	c, rec := newTestContext(e, req)
+
+	mockService.EXPECT().
+		Import(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, r io.Reader) (service.BackupImportResult, error) {
+			body, err := io.ReadAll(r)
+			require.NoError(t, err)
+			require.JSONEq(t, `{"version":1}`, string(body))
+			return service.BackupImportResult{
+				Folders: service.BackupSectionResult{Created: 1},
+				Feeds:   service.BackupSectionResult{Created: 2, Skipped: 1},
+				Entries: service.BackupSectionResult{Created: 5, Updated: 2, Skipped: 3},
+			}, nil
+		})
+
+	require.NoError(t, h.Import(c))
+
+	var resp handler.BackupImportResponse
+	assertJSONResponse(t, rec, http.StatusOK, &resp)
+	require.Equal(t, 1, resp.Folders.Created)
+	require.Equal(t, 1, resp.Feeds.Skipped)
+	require.Equal(t, 2, resp.Entries.Updated)
+	require.Equal(t, 3, resp.Entries.Skipped)
+}
+
+func TestBackupHandler_Import_Multipart(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockService := mock.NewMockBackupService(ctrl)
+	h := handler.NewBackupHandler(mockService)
+	e := newTestEcho()
+
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	part, err := w.CreateFormFile("file", "gist-backup.json")
+	require.NoError(t, err)
+	_, err = part.Write([]byte(`{"version":1}`))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	req := httptest.NewRequest(http.MethodPost, "/import/full", &buf)
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	c, rec := newTestContext(e, req)
+
+	mockService.EXPECT().
+		Import(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, r io.Reader) (service.BackupImportResult, error) {
+			body, err := io.ReadAll(r)
+			require.NoError(t, err)
+			require.Equal(t, `{"version":1}`, string(body))
+			return service.BackupImportResult{}, nil
+		})
+
+	require.NoError(t, h.Import(c))
+	require.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestBackupHandler_Import_Errors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockService := mock.NewMockBackupService(ctrl)
+	h := handler.NewBackupHandler(mockService)
+	e := newTestEcho()
+
+	for _, tc := range []struct {
+		name       string
+		err        error
+		wantStatus int
+	}{
+		{name: "malformed document", err: service.ErrInvalid, wantStatus: http.StatusBadRequest},
+		{name: "too large", err: &http.MaxBytesError{Limit: 1}, wantStatus: http.StatusRequestEntityTooLarge},
+		{name: "failure", err: errors.New("db down"), wantStatus: http.StatusInternalServerError},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			c, rec := newTestContext(e, newJSONRequestRaw(http.MethodPost, "/import/full", `{}`))
+			mockService.EXPECT().Import(gomock.Any(), gomock.Any()).Return(service.BackupImportResult{}, tc.err)
+
+			require.NoError(t, h.Import(c))
+			require.Equal(t, tc.wantStatus, rec.Code)
+		})
+	}
+}
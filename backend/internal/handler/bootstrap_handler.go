@@ -0,0 +1,147 @@
+package handler
+
+import (
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"gist/backend/internal/service"
+	"gist/backend/pkg/logger"
+)
+
+// BootstrapHandler serves everything the sidebar needs on a cold load in one
+// response.
+type BootstrapHandler struct {
+	folders  service.FolderService
+	feeds    service.FeedService
+	entries  service.EntryService
+	refresh  service.RefreshService
+	settings service.SettingsService
+	// changeVersion feeds the ETag; nil disables it.
+	changeVersion func() uint64
+}
+
+type bootstrapFeedResponse struct {
+	ID       string  `json:"id"`
+	FolderID *string `json:"folderId,omitempty"`
+	// Title is the custom title when the feed was renamed
+	Title     string  `json:"title"`
+	URL       string  `json:"url"`
+	SiteURL   *string `json:"siteUrl,omitempty"`
+	IconPath  *string `json:"iconPath,omitempty"`
+	Type      string  `json:"type"`
+	SortOrder int     `json:"sortOrder"`
+	// HasError is set when the last refresh failed
+	HasError bool `json:"hasError"`
+	Paused   bool `json:"paused"`
+}
+
+type bootstrapResponse struct {
+	// Folders is flat; nesting follows parentId
+	Folders []folderResponse        `json:"folders"`
+	Feeds   []bootstrapFeedResponse `json:"feeds"`
+	// UnreadCounts maps feed IDs to unread counts like GET /unread-counts
+	UnreadCounts map[string]int             `json:"unreadCounts"`
+	StarredCount int                        `json:"starredCount"`
+	Refresh      refreshStatusResponse      `json:"refresh"`
+	Appearance   appearanceSettingsResponse `json:"appearance"`
+}
+
+// NewBootstrapHandler answers conditional GETs with 304 while changeVersion,
+// the appearance settings and the refresh status stay put.
+func NewBootstrapHandler(folders service.FolderService, feeds service.FeedService, entries service.EntryService, refresh service.RefreshService, settings service.SettingsService, changeVersion func() uint64) *BootstrapHandler {
+	return &BootstrapHandler{folders: folders, feeds: feeds, entries: entries, refresh: refresh, settings: settings, changeVersion: changeVersion}
+}
+
+func (h *BootstrapHandler) RegisterRoutes(g *echo.Group) {
+	g.GET("/bootstrap", h.Get)
+}
+
+// Get returns folders, feeds, unread counts, the starred count, the refresh
+// status and the appearance settings.
+// @Summary Get sidebar bootstrap
+// @Description Get what GET /folders, /feeds, /unread-counts, /starred-count, /feeds/refresh and /settings/appearance return, in one response with compact feeds. Send the ETag back in If-None-Match to get 304 while nothing changed.
+// @Tags bootstrap
+// @Produce json
+// @Success 200 {object} bootstrapResponse
+// @Success 304 "Not Modified"
+// @Failure 500 {object} errorResponse
+// @Router /bootstrap [get]
+func (h *BootstrapHandler) Get(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	// The settings and refresh status are cheap and part of the ETag, so they
+	// come first and a revalidation stops here
+	appearance, err := h.settings.GetAppearanceSettings(ctx)
+	if err != nil {
+		logger.Error("bootstrap appearance settings failed", "module", "handler", "action", "list", "resource", "settings", "result", "failed", "error", err)
+		return Error(c, http.StatusInternalServerError, CodeInternal, "failed to get settings")
+	}
+	resp := bootstrapResponse{
+		Refresh:    toRefreshStatusResponse(h.refresh.GetRefreshStatus()),
+		Appearance: appearanceSettingsResponse{ContentTypes: appearance.ContentTypes, Version: appearance.Version},
+	}
+	if h.notModified(c, resp) {
+		return c.NoContent(http.StatusNotModified)
+	}
+
+	folders, err := h.folders.List(ctx)
+	if err != nil {
+		logger.Error("bootstrap folder list failed", "module", "handler", "action", "list", "resource", "folder", "result", "failed", "error", err)
+		return writeServiceError(c, err)
+	}
+	feeds, err := h.feeds.ListWithUnreadCounts(ctx)
+	if err != nil {
+		logger.Error("bootstrap feed list failed", "module", "handler", "action", "list", "resource", "feed", "result", "failed", "error", err)
+		return writeServiceError(c, err)
+	}
+	if resp.StarredCount, err = h.entries.GetStarredCount(ctx); err != nil {
+		logger.Error("bootstrap starred count failed", "module", "handler", "action", "list", "resource", "entry", "result", "failed", "error", err)
+		return writeServiceError(c, err)
+	}
+
+	resp.Folders = make([]folderResponse, 0, len(folders))
+	for _, folder := range folders {
+		resp.Folders = append(resp.Folders, toFolderResponse(folder))
+	}
+	resp.Feeds = make([]bootstrapFeedResponse, 0, len(feeds))
+	resp.UnreadCounts = make(map[string]int)
+	for _, feed := range feeds {
+		full := toFeedResponse(feed.Feed)
+		resp.Feeds = append(resp.Feeds, bootstrapFeedResponse{
+			ID:        full.ID,
+			FolderID:  full.FolderID,
+			Title:     full.Title,
+			URL:       full.URL,
+			SiteURL:   full.SiteURL,
+			IconPath:  full.IconPath,
+			Type:      full.Type,
+			SortOrder: full.SortOrder,
+			HasError:  full.ErrorMessage != nil && *full.ErrorMessage != "",
+			Paused:    full.Paused,
+		})
+		if feed.UnreadCount > 0 {
+			resp.UnreadCounts[full.ID] = feed.UnreadCount
+		}
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// notModified sets a weak ETag over the change version, the appearance
+// settings and the refresh status in resp.
+func (h *BootstrapHandler) notModified(c echo.Context, resp bootstrapResponse) bool {
+	if h.changeVersion == nil {
+		return false
+	}
+
+	hash := fnv.New64a()
+	fmt.Fprintf(hash, "%s\x00%s\x00%t", resp.Appearance.Version, strings.Join(resp.Appearance.ContentTypes, ","), resp.Refresh.IsRefreshing)
+	if resp.Refresh.LastRefreshedAt != nil {
+		hash.Write([]byte{0})
+		hash.Write([]byte(*resp.Refresh.LastRefreshedAt))
+	}
+	return matchETag(c, fmt.Sprintf(`W/"%x-%x"`, h.changeVersion(), hash.Sum64()))
+}
@@ -0,0 +1,128 @@
+package handler_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"gist/backend/internal/handler"
+	"gist/backend/internal/model"
+	"gist/backend/internal/service"
+	"gist/backend/internal/service/mock"
+)
+
+// expectBootstrapHeader sets up the reads every bootstrap request makes.
+func expectBootstrapHeader(mockSettings *mock.MockSettingsService, mockRefresh *mock.MockRefreshService, status service.RefreshStatus) {
+	mockSettings.EXPECT().GetAppearanceSettings(gomock.Any()).Return(&service.AppearanceSettings{ContentTypes: []string{"article"}, Version: "2"}, nil)
+	mockRefresh.EXPECT().GetRefreshStatus().Return(status)
+}
+
+// expectBootstrapBody sets up the reads of a bootstrap response that is sent.
+func expectBootstrapBody(mockFolders *mock.MockFolderService, mockFeeds *mock.MockFeedService, mockEntries *mock.MockEntryService) {
+	parentID := int64(1)
+	folderID := int64(2)
+	errorMessage := "HTTP 500"
+	customTitle := "Renamed"
+	mockFolders.EXPECT().List(gomock.Any()).Return([]model.Folder{
+		{ID: 1, Name: "Tech", Type: "article"},
+		{ID: 2, Name: "Go", ParentID: &parentID, Type: "article"},
+	}, nil)
+	mockFeeds.EXPECT().ListWithUnreadCounts(gomock.Any()).Return([]model.FeedWithUnread{
+		{Feed: model.Feed{ID: 10, FolderID: &folderID, Title: "Upstream", CustomTitle: &customTitle, URL: "https://a.example.com/feed", Type: "article"}, UnreadCount: 3},
+		{Feed: model.Feed{ID: 11, Title: "Broken", URL: "https://b.example.com/feed", Type: "picture", ErrorMessage: &errorMessage}},
+	}, nil)
+	mockEntries.EXPECT().GetStarredCount(gomock.Any()).Return(4, nil)
+}
+
+func TestBootstrapHandler_Get(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFolders := mock.NewMockFolderService(ctrl)
+	mockFeeds := mock.NewMockFeedService(ctrl)
+	mockEntries := mock.NewMockEntryService(ctrl)
+	mockRefresh := mock.NewMockRefreshService(ctrl)
+	mockSettings := mock.NewMockSettingsService(ctrl)
+	version := uint64(1)
+	h := handler.NewBootstrapHandler(mockFolders, mockFeeds, mockEntries, mockRefresh, mockSettings, func() uint64 { return version })
+	e := newTestEcho()
+
+	refreshed := time.Date(2026, 10, 15, 8, 0, 0, 0, time.UTC)
+	expectBootstrapHeader(mockSettings, mockRefresh, service.RefreshStatus{LastRefreshedAt: &refreshed})
+	expectBootstrapBody(mockFolders, mockFeeds, mockEntries)
+	c, rec := newTestContext(e, newJSONRequest(http.MethodGet, "/bootstrap", nil))
+	require.NoError(t, h.Get(c))
+
+	var resp handler.BootstrapResponse
+	assertJSONResponse(t, rec, http.StatusOK, &resp)
+	require.Len(t, resp.Folders, 2)
+	require.NotNil(t, resp.Folders[1].ParentID)
+	require.Equal(t, "1", *resp.Folders[1].ParentID)
+
+	require.Len(t, resp.Feeds, 2)
+	require.Equal(t, "10", resp.Feeds[0].ID)
+	require.Equal(t, "Renamed", resp.Feeds[0].Title)
+	require.NotNil(t, resp.Feeds[0].FolderID)
+	require.Equal(t, "2", *resp.Feeds[0].FolderID)
+	require.False(t, resp.Feeds[0].HasError)
+	require.True(t, resp.Feeds[1].HasError)
+	require.Equal(t, "picture", resp.Feeds[1].Type)
+
+	require.Equal(t, map[string]int{"10": 3}, resp.UnreadCounts)
+	require.Equal(t, 4, resp.StarredCount)
+	require.False(t, resp.Refresh.IsRefreshing)
+	require.NotNil(t, resp.Refresh.LastRefreshedAt)
+	require.Equal(t, []string{"article"}, resp.Appearance.ContentTypes)
+	require.Equal(t, "2", resp.Appearance.Version)
+}
+
+func TestBootstrapHandler_Get_ConditionalGet(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFolders := mock.NewMockFolderService(ctrl)
+	mockFeeds := mock.NewMockFeedService(ctrl)
+	mockEntries := mock.NewMockEntryService(ctrl)
+	mockRefresh := mock.NewMockRefreshService(ctrl)
+	mockSettings := mock.NewMockSettingsService(ctrl)
+	version := uint64(1)
+	h := handler.NewBootstrapHandler(mockFolders, mockFeeds, mockEntries, mockRefresh, mockSettings, func() uint64 { return version })
+	e := newTestEcho()
+
+	expectBootstrapHeader(mockSettings, mockRefresh, service.RefreshStatus{})
+	expectBootstrapBody(mockFolders, mockFeeds, mockEntries)
+	c, rec := newTestContext(e, newJSONRequest(http.MethodGet, "/bootstrap", nil))
+	require.NoError(t, h.Get(c))
+	require.Equal(t, http.StatusOK, rec.Code)
+	etag := rec.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+
+	// Only the settings and refresh status are read to revalidate
+	expectBootstrapHeader(mockSettings, mockRefresh, service.RefreshStatus{})
+	req := newJSONRequest(http.MethodGet, "/bootstrap", nil)
+	req.Header.Set("If-None-Match", etag)
+	c, rec = newTestContext(e, req)
+	require.NoError(t, h.Get(c))
+	require.Equal(t, http.StatusNotModified, rec.Code)
+
+	// A refresh starting changes the ETag without a data write
+	expectBootstrapHeader(mockSettings, mockRefresh, service.RefreshStatus{IsRefreshing: true})
+	expectBootstrapBody(mockFolders, mockFeeds, mockEntries)
+	req = newJSONRequest(http.MethodGet, "/bootstrap", nil)
+	req.Header.Set("If-None-Match", etag)
+	c, rec = newTestContext(e, req)
+	require.NoError(t, h.Get(c))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	version++
+	expectBootstrapHeader(mockSettings, mockRefresh, service.RefreshStatus{IsRefreshing: true})
+	expectBootstrapBody(mockFolders, mockFeeds, mockEntries)
+	req = newJSONRequest(http.MethodGet, "/bootstrap", nil)
+	req.Header.Set("If-None-Match", rec.Header().Get("ETag"))
+	c, rec = newTestContext(e, req)
+	require.NoError(t, h.Get(c))
+	require.Equal(t, http.StatusOK, rec.Code)
+}
@@ -2,6 +2,7 @@ package handler
 
 import (
 	"database/sql"
+	"fmt"
 	"net/http"
 
 	"github.com/labstack/echo/v4"
@@ -20,11 +21,15 @@ type domainRateLimitResponse struct {
 	ID              string `json:"id"`
 	Host            string `json:"host"`
 	IntervalSeconds int    `json:"intervalSeconds"`
+	// MaxConcurrent caps parallel refresh requests to the host; 0 uses the general setting.
+	// Takes effect from the next refresh run.
+	MaxConcurrent int `json:"maxConcurrent"`
 }
 
 type domainRateLimitRequest struct {
 	Host            string `json:"host"`
 	IntervalSeconds int    `json:"intervalSeconds"`
+	MaxConcurrent   int    `json:"maxConcurrent"`
 }
 
 type domainRateLimitListResponse struct {
@@ -63,6 +68,7 @@ func (h *DomainRateLimitHandler) List(c echo.Context) error {
 			ID:              l.ID,
 			Host:            l.Host,
 			IntervalSeconds: l.IntervalSeconds,
+			MaxConcurrent:   l.MaxConcurrent,
 		}
 	}
 
@@ -73,6 +79,7 @@ func (h *DomainRateLimitHandler) List(c echo.Context) error {
 
 // Create godoc
 // @Summary Create a domain rate limit
+// @Description Set the request interval and concurrency cap for a host. Changes apply from the next refresh run.
 // @Tags domain-rate-limits
 // @Accept json
 // @Produce json
@@ -82,19 +89,23 @@ func (h *DomainRateLimitHandler) List(c echo.Context) error {
 func (h *DomainRateLimitHandler) Create(c echo.Context) error {
 	var req domainRateLimitRequest
 	if err := c.Bind(&req); err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid request body")
 	}
 
 	if req.Host == "" {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "host is required"})
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "host is required")
 	}
 
 	if req.IntervalSeconds < 0 {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "intervalSeconds must be non-negative"})
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "intervalSeconds must be non-negative")
+	}
+
+	if req.MaxConcurrent < 0 || req.MaxConcurrent > service.MaxConcurrentPerHostLimit {
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, fmt.Sprintf("maxConcurrent must be between 0 and %d", service.MaxConcurrentPerHostLimit))
 	}
 
 	ctx := c.Request().Context()
-	if err := h.service.SetInterval(ctx, req.Host, req.IntervalSeconds); err != nil {
+	if err := h.service.SetInterval(ctx, req.Host, req.IntervalSeconds, req.MaxConcurrent); err != nil {
 		logger.Error("domain rate limit create failed", "module", "handler", "action", "create", "resource", "domain_rate_limit", "result", "failed", "host", req.Host, "error", err)
 		return writeServiceError(c, err)
 	}
@@ -112,6 +123,7 @@ func (h *DomainRateLimitHandler) Create(c echo.Context) error {
 				ID:              l.ID,
 				Host:            l.Host,
 				IntervalSeconds: l.IntervalSeconds,
+				MaxConcurrent:   l.MaxConcurrent,
 			})
 		}
 	}
@@ -120,11 +132,13 @@ func (h *DomainRateLimitHandler) Create(c echo.Context) error {
 	return c.JSON(http.StatusCreated, domainRateLimitResponse{
 		Host:            req.Host,
 		IntervalSeconds: req.IntervalSeconds,
+		MaxConcurrent:   req.MaxConcurrent,
 	})
 }
 
 // Update godoc
 // @Summary Update a domain rate limit
+// @Description Set the request interval and concurrency cap for a host. Changes apply from the next refresh run.
 // @Tags domain-rate-limits
 // @Accept json
 // @Produce json
@@ -135,20 +149,24 @@ func (h *DomainRateLimitHandler) Create(c echo.Context) error {
 func (h *DomainRateLimitHandler) Update(c echo.Context) error {
 	host := c.Param("host")
 	if host == "" {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "host is required"})
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "host is required")
 	}
 
 	var req domainRateLimitRequest
 	if err := c.Bind(&req); err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid request body")
 	}
 
 	if req.IntervalSeconds < 0 {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "intervalSeconds must be non-negative"})
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "intervalSeconds must be non-negative")
+	}
+
+	if req.MaxConcurrent < 0 || req.MaxConcurrent > service.MaxConcurrentPerHostLimit {
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, fmt.Sprintf("maxConcurrent must be between 0 and %d", service.MaxConcurrentPerHostLimit))
 	}
 
 	ctx := c.Request().Context()
-	if err := h.service.SetInterval(ctx, host, req.IntervalSeconds); err != nil {
+	if err := h.service.SetInterval(ctx, host, req.IntervalSeconds, req.MaxConcurrent); err != nil {
 		logger.Error("domain rate limit update failed", "module", "handler", "action", "update", "resource", "domain_rate_limit", "result", "failed", "host", host, "error", err)
 		return writeServiceError(c, err)
 	}
@@ -157,6 +175,7 @@ func (h *DomainRateLimitHandler) Update(c echo.Context) error {
 	return c.JSON(http.StatusOK, domainRateLimitResponse{
 		Host:            host,
 		IntervalSeconds: req.IntervalSeconds,
+		MaxConcurrent:   req.MaxConcurrent,
 	})
 }
 
@@ -169,14 +188,14 @@ func (h *DomainRateLimitHandler) Update(c echo.Context) error {
 func (h *DomainRateLimitHandler) Delete(c echo.Context) error {
 	host := c.Param("host")
 	if host == "" {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "host is required"})
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "host is required")
 	}
 
 	ctx := c.Request().Context()
 	if err := h.service.DeleteInterval(ctx, host); err != nil {
 		if err == sql.ErrNoRows {
 			logger.Warn("domain rate limit delete not found", "module", "handler", "action", "delete", "resource", "domain_rate_limit", "result", "failed", "host", host, "error", "not found")
-			return c.JSON(http.StatusNotFound, map[string]string{"error": "not found"})
+			return Error(c, http.StatusNotFound, CodeNotFound, "not found")
 		}
 		logger.Error("domain rate limit delete failed", "module", "handler", "action", "delete", "resource", "domain_rate_limit", "result", "failed", "host", host, "error", err)
 		return writeServiceError(c, err)
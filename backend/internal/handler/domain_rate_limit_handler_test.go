@@ -59,7 +59,7 @@ func TestDomainRateLimitHandler_Create_Success(t *testing.T) {
 	c, rec := newTestContext(e, req)
 
 	mockService.EXPECT().
-		SetInterval(gomock.Any(), "example.com", 10).
+		SetInterval(gomock.Any(), "example.com", 10, 0).
 		Return(nil)
 
 	mockService.EXPECT().
@@ -132,7 +132,7 @@ func TestDomainRateLimitHandler_Update_Success(t *testing.T) {
 	setPathParams(c, map[string]string{"host": "example.com"})
 
 	mockService.EXPECT().
-		SetInterval(gomock.Any(), "example.com", 15).
+		SetInterval(gomock.Any(), "example.com", 15, 0).
 		Return(nil)
 
 	err := h.Update(c)
@@ -1,16 +1,21 @@
 package handler
 
 import (
+	"encoding/json"
 	"errors"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/labstack/echo/v4"
 
 	"gist/backend/internal/model"
 	"gist/backend/internal/service"
+	"gist/backend/pkg/langdetect"
 	"gist/backend/pkg/logger"
+	"gist/backend/pkg/plaintext"
+	"gist/backend/pkg/readtime"
 )
 
 const maxEntryReadBatchIDs = 1000
@@ -18,24 +23,41 @@ const maxEntryReadBatchIDs = 1000
 type EntryHandler struct {
 	service            service.EntryService
 	readabilityService service.ReadabilityService
+	// changeVersion feeds the ETags of the list and unread-count endpoints; nil disables them.
+	changeVersion func() uint64
 }
 
-func NewEntryHandler(service service.EntryService, readabilityService service.ReadabilityService) *EntryHandler {
-	return &EntryHandler{service: service, readabilityService: readabilityService}
+// NewEntryHandler answers conditional GETs with 304 while changeVersion stays put.
+func NewEntryHandler(service service.EntryService, readabilityService service.ReadabilityService, changeVersion func() uint64) *EntryHandler {
+	return &EntryHandler{service: service, readabilityService: readabilityService, changeVersion: changeVersion}
 }
 
 func (h *EntryHandler) RegisterRoutes(g *echo.Group) {
 	g.GET("/entries", h.List)
+	g.GET("/entries/counts", h.Count)
+	g.GET("/entries/search", h.Search)
 	g.GET("/entries/:id", h.GetByID)
+	g.POST("/entries/bulk", h.GetBulk)
+	g.GET("/entries/:id/adjacent", h.GetAdjacent)
+	g.GET("/entries/:id/revisions", h.ListRevisions)
+	g.GET("/entries/:id/raw", h.GetRawItem)
+	g.GET("/entries/:id/text", h.GetText)
+	g.GET("/entries/:id/visit", h.Visit)
 	g.PATCH("/entries/read", h.UpdateManyReadStatus)
 	g.PATCH("/entries/:id/read", h.UpdateReadStatus)
 	g.PATCH("/entries/:id/starred", h.UpdateStarredStatus)
+	g.PUT("/entries/:id/note", h.UpdateNote)
+	g.DELETE("/entries/:id/note", h.DeleteNote)
 	g.POST("/entries/:id/fetch-readable", h.FetchReadable)
 	g.POST("/entries/mark-read", h.MarkAllAsRead)
 	g.DELETE("/entries/readability-cache", h.ClearReadabilityCache)
 	g.DELETE("/entries/cache", h.ClearEntryCache)
 	g.GET("/unread-counts", h.GetUnreadCounts)
 	g.GET("/starred-count", h.GetStarredCount)
+	g.GET("/entries/starred/archive-status", h.GetArchiveStatus)
+	g.GET("/digest", h.GetDailyDigest)
+	g.GET("/stats/reading", h.GetReadingStats)
+	g.GET("/stats/clicks", h.GetClickStats)
 }
 
 type entryResponse struct {
@@ -50,17 +72,59 @@ type entryResponse struct {
 	PublishedAt     *string `json:"publishedAt,omitempty"`
 	Read            bool    `json:"read"`
 	Starred         bool    `json:"starred"`
+	WordCount       int     `json:"wordCount"`
+	ReadingMinutes  int     `json:"readingMinutes"`
 	CreatedAt       string  `json:"createdAt"`
 	UpdatedAt       string  `json:"updatedAt"`
+	Language        string  `json:"language,omitempty"`
+	Note            *string `json:"note,omitempty"`
+	HasNote         bool    `json:"hasNote,omitempty"`
+	// AuthorMuted is only set on single entries whose author is muted in the feed.
+	AuthorMuted bool `json:"authorMuted,omitempty"`
+	// FeedTitle, FeedIconPath and FeedType are only set with ?include=feed.
+	FeedTitle    *string `json:"feedTitle,omitempty"`
+	FeedIconPath *string `json:"feedIconPath,omitempty"`
+	FeedType     *string `json:"feedType,omitempty"`
+}
+
+type bulkEntriesRequest struct {
+	IDs []string `json:"ids"`
+	// IncludeAI adds each entry's cached AI summary, if any.
+	IncludeAI bool `json:"includeAI"`
+}
+
+type bulkEntryResponse struct {
+	entryResponse
+	Summary *string `json:"summary,omitempty"`
+}
+
+type bulkEntriesResponse struct {
+	Entries []bulkEntryResponse `json:"entries"`
 }
 
 type readableContentResponse struct {
-	ReadableContent string `json:"readableContent"`
+	// Status is "ready" with ReadableContent, or "pending" while it is being extracted.
+	Status          string `json:"status"`
+	ReadableContent string `json:"readableContent,omitempty"`
 }
 
 type entryListResponse struct {
 	Entries []entryResponse `json:"entries"`
 	HasMore bool            `json:"hasMore"`
+	// Full is false for a delta list, which holds only the entries updated since
+	// the time asked for, without content unless include=content.
+	Full bool `json:"full"`
+	// Deleted lists the entries deleted since that time, on the first page of a delta list.
+	Deleted []string `json:"deleted,omitempty"`
+}
+
+type entrySearchResponse struct {
+	Entries []entryResponse `json:"entries"`
+	HasMore bool            `json:"hasMore"`
+}
+
+type entryCountResponse struct {
+	Count int `json:"count"`
 }
 
 type updateReadRequest struct {
@@ -76,10 +140,31 @@ type updateStarredRequest struct {
 	Starred bool `json:"starred"`
 }
 
+type updateNoteRequest struct {
+	Note string `json:"note"`
+}
+
+// noteBodyLimit leaves room for JSON escaping around MaxEntryNoteSize bytes of note.
+const noteBodyLimit = 6*service.MaxEntryNoteSize + 1<<10
+
 type starredCountResponse struct {
 	Count int `json:"count"`
 }
 
+type archiveFailureResponse struct {
+	EntryID   string  `json:"entryId"`
+	FeedID    string  `json:"feedId"`
+	Title     *string `json:"title,omitempty"`
+	URL       *string `json:"url,omitempty"`
+	Attempts  int     `json:"attempts"`
+	LastError *string `json:"lastError,omitempty"`
+	FailedAt  *string `json:"failedAt,omitempty"`
+}
+
+type archiveStatusResponse struct {
+	Failed []archiveFailureResponse `json:"failed"`
+}
+
 type entryClearResponse struct {
 	Deleted int64 `json:"deleted"`
 }
@@ -90,8 +175,103 @@ type markAllReadRequest struct {
 	ContentType *string `json:"contentType,omitempty"`
 }
 
+type entryRevisionDiffLine struct {
+	Op   string `json:"op"`
+	Text string `json:"text"`
+}
+
+type entryRevisionResponse struct {
+	ID        string                  `json:"id"`
+	Title     *string                 `json:"title,omitempty"`
+	Content   *string                 `json:"content,omitempty"`
+	CreatedAt string                  `json:"createdAt"`
+	Diff      []entryRevisionDiffLine `json:"diff"`
+}
+
+type entryRevisionListResponse struct {
+	Revisions []entryRevisionResponse `json:"revisions"`
+}
+
+type digestEntryResponse struct {
+	ID             string  `json:"id"`
+	FeedID         string  `json:"feedId"`
+	Title          *string `json:"title,omitempty"`
+	URL            *string `json:"url,omitempty"`
+	ThumbnailURL   *string `json:"thumbnailUrl,omitempty"`
+	Author         *string `json:"author,omitempty"`
+	PublishedAt    *string `json:"publishedAt,omitempty"`
+	Read           bool    `json:"read"`
+	Starred        bool    `json:"starred"`
+	ReadingMinutes int     `json:"readingMinutes"`
+	Summary        *string `json:"summary,omitempty"`
+}
+
+type digestFeedResponse struct {
+	ID          string                `json:"id"`
+	Title       string                `json:"title"`
+	UnreadCount int                   `json:"unreadCount"`
+	Total       int                   `json:"total"`
+	Entries     []digestEntryResponse `json:"entries"`
+}
+
+type digestFolderResponse struct {
+	ID          *string              `json:"id,omitempty"`
+	Name        string               `json:"name"`
+	UnreadCount int                  `json:"unreadCount"`
+	Feeds       []digestFeedResponse `json:"feeds"`
+}
+
+type dailyDigestResponse struct {
+	Date     string                 `json:"date"`
+	Timezone string                 `json:"timezone"`
+	Start    string                 `json:"start"`
+	End      string                 `json:"end"`
+	Folders  []digestFolderResponse `json:"folders"`
+}
+
+type readingDayResponse struct {
+	Date  string `json:"date"`
+	Count int    `json:"count"`
+}
+
+type readingFeedResponse struct {
+	FeedID string `json:"feedId"`
+	Title  string `json:"title"`
+	Count  int    `json:"count"`
+}
+
+type readingStatsResponse struct {
+	Days     int                   `json:"days"`
+	Timezone string                `json:"timezone"`
+	Since    string                `json:"since"`
+	Daily    []readingDayResponse  `json:"daily"`
+	TopFeeds []readingFeedResponse `json:"topFeeds"`
+	// AverageReadDelaySeconds is omitted when no read entry has a published date.
+	AverageReadDelaySeconds *int64 `json:"averageReadDelaySeconds,omitempty"`
+	TotalRead               int    `json:"totalRead"`
+	TotalStarred            int    `json:"totalStarred"`
+}
+
+type feedClicksResponse struct {
+	FeedID        string  `json:"feedId"`
+	Title         string  `json:"title"`
+	Count         int     `json:"count"`
+	LastClickedAt *string `json:"lastClickedAt,omitempty"`
+}
+
+type clickStatsResponse struct {
+	Days     int    `json:"days"`
+	Timezone string `json:"timezone"`
+	Since    string `json:"since"`
+	// Feeds has every feed, most clicked first; those never clicked have count 0.
+	Feeds []feedClicksResponse `json:"feeds"`
+}
+
 type unreadCountsResponse struct {
 	Counts map[string]int `json:"counts"`
+	// Today maps feed IDs to the entries saved since the start of today in
+	// the configured timezone; feeds without any are left out.
+	Today map[string]int `json:"today"`
 }
 
 func parseEntryIDList(rawIDs []string) ([]int64, string) {
@@ -119,31 +299,14 @@ func parseEntryIDList(rawIDs []string) ([]int64, string) {
 	return ids, ""
 }
 
-// List returns a list of entries.
-// @Summary List entries
-// @Description Get a list of entries with optional filters and pagination
-// @Tags entries
-// @Produce json
-// @Param feedId query int false "Filter by feed ID"
-// @Param folderId query int false "Filter by folder ID"
-// @Param contentType query string false "Filter by content type (article, picture, notification)"
-// @Param unreadOnly query bool false "Only return unread entries"
-// @Param starredOnly query bool false "Only return starred entries"
-// @Param limit query int false "Limit the number of entries (default 50)"
-// @Param offset query int false "Offset for pagination"
-// @Success 200 {object} entryListResponse
-// @Failure 400 {object} errorResponse
-// @Router /entries [get]
-func (h *EntryHandler) List(c echo.Context) error {
-	params := service.EntryListParams{
-		Limit:  50,
-		Offset: 0,
-	}
+// parseEntryFilterQuery reads the list filter query params shared by List and GetAdjacent.
+func parseEntryFilterQuery(c echo.Context) (service.EntryListParams, string) {
+	var params service.EntryListParams
 
 	if raw := c.QueryParam("feedId"); raw != "" {
 		id, err := strconv.ParseInt(raw, 10, 64)
 		if err != nil {
-			return c.JSON(http.StatusBadRequest, errorResponse{Error: "invalid feedId"})
+			return params, "invalid feedId"
 		}
 		params.FeedID = &id
 	}
@@ -151,14 +314,14 @@ func (h *EntryHandler) List(c echo.Context) error {
 	if raw := c.QueryParam("folderId"); raw != "" {
 		id, err := strconv.ParseInt(raw, 10, 64)
 		if err != nil {
-			return c.JSON(http.StatusBadRequest, errorResponse{Error: "invalid folderId"})
+			return params, "invalid folderId"
 		}
 		params.FolderID = &id
 	}
 
 	if raw := c.QueryParam("contentType"); raw != "" {
 		if raw != "article" && raw != "picture" && raw != "notification" {
-			return c.JSON(http.StatusBadRequest, errorResponse{Error: "invalid contentType"})
+			return params, "invalid contentType"
 		}
 		params.ContentType = &raw
 	}
@@ -175,6 +338,158 @@ func (h *EntryHandler) List(c echo.Context) error {
 		params.HasThumbnail = true
 	}
 
+	if c.QueryParam("notesOnly") == "true" {
+		params.NotesOnly = true
+	}
+
+	if c.QueryParam("hasReadableContent") == "true" {
+		params.HasReadableContent = true
+	}
+
+	if c.QueryParam("hasSummary") == "true" {
+		params.HasSummary = true
+	}
+
+	if raw := c.QueryParam("language"); raw != "" {
+		if !validLanguage(raw) {
+			return params, "invalid language"
+		}
+		params.SummaryLanguage = raw
+	}
+
+	if raw := c.QueryParam("contentLanguage"); raw != "" {
+		language := langdetect.Normalize(raw)
+		if language == "" {
+			return params, "invalid contentLanguage"
+		}
+		params.ContentLanguage = language
+	}
+
+	if raw := c.QueryParam("minReadingMinutes"); raw != "" {
+		minutes, err := strconv.Atoi(raw)
+		if err != nil || minutes < 0 {
+			return params, "invalid minReadingMinutes"
+		}
+		params.MinReadingMinutes = &minutes
+	}
+
+	if raw := c.QueryParam("maxReadingMinutes"); raw != "" {
+		minutes, err := strconv.Atoi(raw)
+		if err != nil || minutes < 0 {
+			return params, "invalid maxReadingMinutes"
+		}
+		params.MaxReadingMinutes = &minutes
+	}
+
+	include, ok := parseEntryInclude(c)
+	if !ok {
+		return params, "invalid include"
+	}
+	params.IncludeFeed = include.feed
+
+	return params, ""
+}
+
+// entryInclude is what the include query param asks to add to entries.
+type entryInclude struct {
+	feed bool
+	// content keeps content in delta lists, which leave it out by default.
+	content bool
+}
+
+// parseEntryInclude reads the comma-separated include query param, whose
+// values are "feed" and "content". ok is false when it names anything else.
+func parseEntryInclude(c echo.Context) (entryInclude, bool) {
+	var include entryInclude
+	raw := c.QueryParam("include")
+	if raw == "" {
+		return include, true
+	}
+	for _, part := range strings.Split(raw, ",") {
+		switch strings.TrimSpace(part) {
+		case "feed":
+			include.feed = true
+		case "content":
+			include.content = true
+		default:
+			return entryInclude{}, false
+		}
+	}
+	return include, true
+}
+
+// parseEntrySince reads where a delta list starts: the since query param
+// (RFC 3339), else the If-Modified-Since header. conditional is set for the
+// header. If-Modified-Since is ignored alongside If-None-Match, as HTTP
+// requires, so a browser revalidating a cached full list gets a full list.
+// ok is false for a malformed since param.
+func parseEntrySince(c echo.Context) (since *time.Time, conditional bool, ok bool) {
+	if raw := c.QueryParam("since"); raw != "" {
+		t, err := time.Parse(time.RFC3339Nano, raw)
+		if err != nil {
+			return nil, false, false
+		}
+		return &t, false, true
+	}
+
+	header := c.Request().Header
+	raw := header.Get("If-Modified-Since")
+	if raw == "" || header.Get("If-None-Match") != "" {
+		return nil, false, true
+	}
+	// A malformed header is ignored rather than rejected, like any HTTP date
+	t, err := http.ParseTime(raw)
+	if err != nil {
+		return nil, false, true
+	}
+	return &t, true, true
+}
+
+// List returns a list of entries.
+// @Summary List entries
+// @Description Get a list of entries with optional filters and pagination.
+// @Description With since or If-Modified-Since (without If-None-Match) the list is a delta: only entries updated since then,
+// @Description without content unless include has content, plus the ids of entries deleted since then. Deltas reaching back
+// @Description further than deleted entries are remembered are full lists. Last-Modified is the time to ask from next.
+// @Tags entries
+// @Produce json
+// @Param feedId query int false "Filter by feed ID"
+// @Param folderId query int false "Filter by folder ID"
+// @Param contentType query string false "Filter by content type (article, picture, notification)"
+// @Param unreadOnly query bool false "Only return unread entries"
+// @Param starredOnly query bool false "Only return starred entries"
+// @Param notesOnly query bool false "Only return entries with a note"
+// @Param hasReadableContent query bool false "Only return entries whose readable content has been extracted"
+// @Param hasSummary query bool false "Only return entries with a cached AI summary"
+// @Param language query string false "Summary language for hasSummary (default: the configured summary language)"
+// @Param contentLanguage query string false "Entry language as a primary subtag, e.g. en"
+// @Param minReadingMinutes query int false "Minimum estimated reading time in minutes"
+// @Param maxReadingMinutes query int false "Maximum estimated reading time in minutes"
+// @Param include query string false "Comma-separated: feed to inline the feed title, icon and type of each entry, content to keep content in a delta"
+// @Param since query string false "List only entries updated since this RFC 3339 time"
+// @Param If-Modified-Since header string false "List only entries updated since this HTTP date; answered 304 when nothing changed"
+// @Param limit query int false "Limit the number of entries (default 50)"
+// @Param offset query int false "Offset for pagination"
+// @Success 200 {object} entryListResponse
+// @Success 304 "Not Modified"
+// @Failure 400 {object} errorResponse
+// @Router /entries [get]
+func (h *EntryHandler) List(c echo.Context) error {
+	params, validationError := parseEntryFilterQuery(c)
+	if validationError != "" {
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, validationError)
+	}
+	include, _ := parseEntryInclude(c)
+	since, conditional, ok := parseEntrySince(c)
+	if !ok {
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid since")
+	}
+	// Deletions further back are forgotten, so only a full list is complete
+	if since != nil && time.Since(*since) > service.EntryTombstoneRetention {
+		since, conditional = nil, false
+	}
+	params.Limit = 50
+
 	if raw := c.QueryParam("limit"); raw != "" {
 		limit, err := strconv.Atoi(raw)
 		if err == nil && limit > 0 && limit <= 100 {
@@ -189,9 +504,18 @@ func (h *EntryHandler) List(c echo.Context) error {
 		}
 	}
 
+	// A delta depends on since, which the ETag doesn't cover
+	if since == nil && notModified(c, h.changeVersion) {
+		return c.NoContent(http.StatusNotModified)
+	}
+
+	// Taken before reading, so the next delta from it misses nothing written meanwhile
+	listedAt := time.Now()
+
 	// Request one extra to determine if there are more results
 	queryParams := params
 	queryParams.Limit = params.Limit + 1
+	queryParams.UpdatedSince = since
 
 	entries, err := h.service.List(c.Request().Context(), queryParams)
 	if err != nil {
@@ -208,11 +532,166 @@ func (h *EntryHandler) List(c echo.Context) error {
 	response := entryListResponse{
 		Entries: make([]entryResponse, len(entries)),
 		HasMore: hasMore,
+		Full:    since == nil,
 	}
 	for i, e := range entries {
 		response.Entries[i] = toEntryResponse(e)
+		if since != nil && !include.content {
+			response.Entries[i].Content = nil
+			response.Entries[i].ReadableContent = nil
+		}
 	}
 
+	if since != nil {
+		if params.Offset == 0 {
+			deleted, err := h.service.ListDeleted(c.Request().Context(), *since, params.FeedID)
+			if err != nil {
+				logger.Error("entry deleted list failed", "module", "handler", "action", "list", "resource", "entry", "result", "failed", "error", err)
+				return writeServiceError(c, err)
+			}
+			for _, id := range deleted {
+				response.Deleted = append(response.Deleted, strconv.FormatInt(id, 10))
+			}
+		}
+		if conditional && len(response.Entries) == 0 && len(response.Deleted) == 0 {
+			return c.NoContent(http.StatusNotModified)
+		}
+		// Never let a cache hand a delta out as the full list
+		c.Response().Header().Set("Cache-Control", "no-store")
+	}
+	c.Response().Header().Set("Last-Modified", listedAt.UTC().Format(http.TimeFormat))
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// Count returns how many entries match the list filters.
+// @Summary Count entries
+// @Description Count the entries List would return for the same filters, without loading them
+// @Tags entries
+// @Produce json
+// @Param feedId query int false "Filter by feed ID"
+// @Param folderId query int false "Filter by folder ID"
+// @Param contentType query string false "Filter by content type (article, picture, notification)"
+// @Param unreadOnly query bool false "Only count unread entries"
+// @Param starredOnly query bool false "Only count starred entries"
+// @Param notesOnly query bool false "Only count entries with a note"
+// @Param hasReadableContent query bool false "Only count entries whose readable content has been extracted"
+// @Param hasSummary query bool false "Only count entries with a cached AI summary"
+// @Param language query string false "Summary language for hasSummary (default: the configured summary language)"
+// @Param contentLanguage query string false "Entry language as a primary subtag, e.g. en"
+// @Param minReadingMinutes query int false "Minimum estimated reading time in minutes"
+// @Param maxReadingMinutes query int false "Maximum estimated reading time in minutes"
+// @Success 200 {object} entryCountResponse
+// @Success 304 "Not Modified"
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Router /entries/counts [get]
+func (h *EntryHandler) Count(c echo.Context) error {
+	params, validationError := parseEntryFilterQuery(c)
+	if validationError != "" {
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, validationError)
+	}
+
+	if notModified(c, h.changeVersion) {
+		return c.NoContent(http.StatusNotModified)
+	}
+
+	count, err := h.service.Count(c.Request().Context(), params)
+	if err != nil {
+		logger.Error("entry count failed", "module", "handler", "action", "list", "resource", "entry", "result", "failed", "error", err)
+		return writeServiceError(c, err)
+	}
+	return c.JSON(http.StatusOK, entryCountResponse{Count: count})
+}
+
+// Search returns the entries matching a full-text query.
+// @Summary Search entries
+// @Description Full-text search over entries, most relevant first, optionally within a feed or a folder and its subfolders.
+// @Description Besides the feed's own fields, the readable content and AI summaries of entries are searched.
+// @Description Unread entries rank a little higher unless boostUnread is false.
+// @Tags entries
+// @Produce json
+// @Param q query string true "Search text; every word must match"
+// @Param searchIn query string false "Where to match: title, content or all (default all, which also covers author and URL). Content includes readable content and AI summaries"
+// @Param feedId query int false "Search within a feed"
+// @Param folderId query int false "Search within a folder and its subfolders"
+// @Param boostUnread query bool false "Rank unread entries a little higher (default true); false orders by relevance alone"
+// @Param include query string false "Set to feed to inline the feed title, icon and type of each entry"
+// @Param limit query int false "Limit the number of entries (default 50)"
+// @Param offset query int false "Offset for pagination"
+// @Success 200 {object} entrySearchResponse
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Router /entries/search [get]
+func (h *EntryHandler) Search(c echo.Context) error {
+	params := service.EntrySearchParams{
+		Query:       c.QueryParam("q"),
+		SearchIn:    c.QueryParam("searchIn"),
+		BoostUnread: c.QueryParam("boostUnread") != "false",
+		Limit:       50,
+	}
+	if strings.TrimSpace(params.Query) == "" {
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "q required")
+	}
+	switch params.SearchIn {
+	case "", service.EntrySearchInAll, service.EntrySearchInTitle, service.EntrySearchInContent:
+	default:
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid searchIn")
+	}
+
+	if raw := c.QueryParam("feedId"); raw != "" {
+		id, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid feedId")
+		}
+		params.FeedID = &id
+	}
+	if raw := c.QueryParam("folderId"); raw != "" {
+		id, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid folderId")
+		}
+		params.FolderID = &id
+	}
+
+	include, ok := parseEntryInclude(c)
+	if !ok {
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid include")
+	}
+	params.IncludeFeed = include.feed
+
+	if raw := c.QueryParam("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err == nil && limit > 0 && limit <= 100 {
+			params.Limit = limit
+		}
+	}
+	if raw := c.QueryParam("offset"); raw != "" {
+		offset, err := strconv.Atoi(raw)
+		if err == nil && offset >= 0 {
+			params.Offset = offset
+		}
+	}
+
+	// Request one extra to determine if there are more results
+	queryParams := params
+	queryParams.Limit = params.Limit + 1
+	entries, err := h.service.Search(c.Request().Context(), queryParams)
+	if err != nil {
+		return writeServiceError(c, err)
+	}
+
+	hasMore := len(entries) > params.Limit
+	if hasMore {
+		entries = entries[:params.Limit]
+	}
+	response := entrySearchResponse{
+		Entries: make([]entryResponse, len(entries)),
+		HasMore: hasMore,
+	}
+	for i, e := range entries {
+		response.Entries[i] = toEntryResponse(e)
+	}
 	return c.JSON(http.StatusOK, response)
 }
 
@@ -222,6 +701,7 @@ func (h *EntryHandler) List(c echo.Context) error {
 // @Tags entries
 // @Produce json
 // @Param id path int true "Entry ID"
+// @Param include query string false "Set to feed to inline the feed title, icon and type"
 // @Success 200 {object} entryResponse
 // @Failure 400 {object} errorResponse
 // @Failure 404 {object} errorResponse
@@ -229,10 +709,19 @@ func (h *EntryHandler) List(c echo.Context) error {
 func (h *EntryHandler) GetByID(c echo.Context) error {
 	id, err := parseIDParam(c, "id")
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, errorResponse{Error: "invalid id"})
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid id")
+	}
+	include, ok := parseEntryInclude(c)
+	if !ok {
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid include")
 	}
 
-	entry, err := h.service.GetByID(c.Request().Context(), id)
+	var entry model.Entry
+	if include.feed {
+		entry, err = h.service.GetByIDWithFeed(c.Request().Context(), id)
+	} else {
+		entry, err = h.service.GetByID(c.Request().Context(), id)
+	}
 	if err != nil {
 		logger.Warn("entry get failed", "module", "handler", "action", "fetch", "resource", "entry", "result", "failed", "entry_id", id, "error", err)
 		return writeServiceError(c, err)
@@ -242,6 +731,182 @@ func (h *EntryHandler) GetByID(c echo.Context) error {
 	return c.JSON(http.StatusOK, toEntryResponse(entry))
 }
 
+// GetBulk returns several entries by ID in one request.
+// @Summary Get entries by IDs
+// @Description Get up to 100 entries with full content in the order requested. Unknown IDs are left out.
+// @Tags entries
+// @Accept json
+// @Produce json
+// @Param request body bulkEntriesRequest true "Entry IDs"
+// @Success 200 {object} bulkEntriesResponse
+// @Failure 400 {object} errorResponse
+// @Router /entries/bulk [post]
+func (h *EntryHandler) GetBulk(c echo.Context) error {
+	var req bulkEntriesRequest
+	if err := c.Bind(&req); err != nil {
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid request")
+	}
+	if len(req.IDs) > service.MaxBulkEntries {
+		logger.Debug("entry bulk get too many ids", "module", "handler", "action", "fetch", "resource", "entry", "result", "failed", "count", len(req.IDs))
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "maximum 100 entries per request")
+	}
+	ids, validationError := parseEntryIDList(req.IDs)
+	if validationError != "" {
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, validationError)
+	}
+
+	entries, err := h.service.GetByIDs(c.Request().Context(), ids, req.IncludeAI)
+	if err != nil {
+		logger.Error("entry bulk get failed", "module", "handler", "action", "fetch", "resource", "entry", "result", "failed", "count", len(ids), "error", err)
+		return writeServiceError(c, err)
+	}
+
+	// Encode one entry at a time; readable contents can make the whole body large
+	resp := c.Response()
+	resp.Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	resp.WriteHeader(http.StatusOK)
+	if _, err := resp.Write([]byte(`{"entries":[`)); err != nil {
+		return nil
+	}
+	for i, entry := range entries {
+		data, err := json.Marshal(bulkEntryResponse{entryResponse: toEntryResponse(entry.Entry), Summary: entry.Summary})
+		if err != nil {
+			logger.Error("entry bulk encode failed", "module", "handler", "action", "fetch", "resource", "entry", "result", "failed", "entry_id", entry.Entry.ID, "error", err)
+			return nil
+		}
+		if i > 0 {
+			data = append([]byte{','}, data...)
+		}
+		if _, err := resp.Write(data); err != nil {
+			logger.Debug("entry bulk write aborted", "module", "handler", "action", "fetch", "resource", "entry", "result", "failed", "error", err)
+			return nil
+		}
+	}
+	_, _ = resp.Write([]byte("]}"))
+
+	logger.Debug("entry bulk fetched", "module", "handler", "action", "fetch", "resource", "entry", "result", "ok", "requested", len(ids), "count", len(entries))
+	return nil
+}
+
+// GetAdjacent returns the entry next to or before the given one in list order.
+// @Summary Get adjacent entry
+// @Description Get the entry that follows (next) or precedes (prev) an entry under the same filters as the list
+// @Tags entries
+// @Produce json
+// @Param id path int true "Entry ID"
+// @Param direction query string false "next (default) or prev"
+// @Param feedId query int false "Filter by feed ID"
+// @Param folderId query int false "Filter by folder ID"
+// @Param contentType query string false "Filter by content type (article, picture, notification)"
+// @Param unreadOnly query bool false "Only consider unread entries"
+// @Param starredOnly query bool false "Only consider starred entries"
+// @Param notesOnly query bool false "Only consider entries with a note"
+// @Param hasReadableContent query bool false "Only consider entries whose readable content has been extracted"
+// @Param hasSummary query bool false "Only consider entries with a cached AI summary"
+// @Param language query string false "Summary language for hasSummary (default: the configured summary language)"
+// @Param contentLanguage query string false "Entry language as a primary subtag, e.g. en"
+// @Param minReadingMinutes query int false "Minimum estimated reading time in minutes"
+// @Param maxReadingMinutes query int false "Maximum estimated reading time in minutes"
+// @Param include query string false "Set to feed to inline the feed title, icon and type"
+// @Success 200 {object} entryResponse
+// @Success 204 "No adjacent entry"
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Router /entries/{id}/adjacent [get]
+func (h *EntryHandler) GetAdjacent(c echo.Context) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid id")
+	}
+
+	next := true
+	switch c.QueryParam("direction") {
+	case "", "next":
+	case "prev":
+		next = false
+	default:
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid direction")
+	}
+
+	params, validationError := parseEntryFilterQuery(c)
+	if validationError != "" {
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, validationError)
+	}
+
+	entry, err := h.service.GetAdjacent(c.Request().Context(), id, params, next)
+	if err != nil {
+		logger.Warn("entry adjacent get failed", "module", "handler", "action", "fetch", "resource", "entry", "result", "failed", "entry_id", id, "error", err)
+		return writeServiceError(c, err)
+	}
+	if entry == nil {
+		return c.NoContent(http.StatusNoContent)
+	}
+	return c.JSON(http.StatusOK, toEntryResponse(*entry))
+}
+
+// ListRevisions returns previous content snapshots of an entry.
+// @Summary List entry revisions
+// @Description List previous content snapshots (newest first), each with a line diff against the version that replaced it
+// @Tags entries
+// @Produce json
+// @Param id path int true "Entry ID"
+// @Success 200 {object} entryRevisionListResponse
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Router /entries/{id}/revisions [get]
+func (h *EntryHandler) ListRevisions(c echo.Context) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid id")
+	}
+
+	revisions, err := h.service.ListRevisions(c.Request().Context(), id)
+	if err != nil {
+		logger.Warn("entry revisions list failed", "module", "handler", "action", "list", "resource", "entry", "result", "failed", "entry_id", id, "error", err)
+		return writeServiceError(c, err)
+	}
+
+	response := entryRevisionListResponse{Revisions: make([]entryRevisionResponse, len(revisions))}
+	for i, rev := range revisions {
+		diff := make([]entryRevisionDiffLine, len(rev.Diff))
+		for j, line := range rev.Diff {
+			diff[j] = entryRevisionDiffLine{Op: string(line.Op), Text: line.Text}
+		}
+		response.Revisions[i] = entryRevisionResponse{
+			ID:        idToString(rev.ID),
+			Title:     rev.Title,
+			Content:   rev.Content,
+			CreatedAt: rev.CreatedAt.UTC().Format(time.RFC3339),
+			Diff:      diff,
+		}
+	}
+	return c.JSON(http.StatusOK, response)
+}
+
+// GetRawItem returns the feed item an entry was made from.
+// @Summary Get entry source item
+// @Description Return the feed item the entry was made from, as delivered by the last refresh and serialized to JSON, capped at 64KB. Items are only kept with the keepRawItems general setting, for 7 days.
+// @Tags entries
+// @Produce plain
+// @Param id path int true "Entry ID"
+// @Success 200 {string} string "Feed item as JSON"
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Router /entries/{id}/raw [get]
+func (h *EntryHandler) GetRawItem(c echo.Context) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid id")
+	}
+
+	raw, err := h.service.GetRawItem(c.Request().Context(), id)
+	if err != nil {
+		logger.Warn("entry raw item get failed", "module", "handler", "action", "fetch", "resource", "entry", "result", "failed", "entry_id", id, "error", err)
+		return writeServiceError(c, err)
+	}
+	return c.String(http.StatusOK, raw)
+}
+
 // UpdateReadStatus updates the read status of an entry.
 // @Summary Update read status
 // @Description Mark an entry as read or unread
@@ -257,12 +922,12 @@ func (h *EntryHandler) GetByID(c echo.Context) error {
 func (h *EntryHandler) UpdateReadStatus(c echo.Context) error {
 	id, err := parseIDParam(c, "id")
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, errorResponse{Error: "invalid id"})
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid id")
 	}
 
 	var req updateReadRequest
 	if err := c.Bind(&req); err != nil {
-		return c.JSON(http.StatusBadRequest, errorResponse{Error: "invalid request"})
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid request")
 	}
 
 	if err := h.service.MarkAsRead(c.Request().Context(), id, req.Read); err != nil {
@@ -287,11 +952,11 @@ func (h *EntryHandler) UpdateReadStatus(c echo.Context) error {
 func (h *EntryHandler) UpdateManyReadStatus(c echo.Context) error {
 	var req updateManyReadRequest
 	if err := c.Bind(&req); err != nil {
-		return c.JSON(http.StatusBadRequest, errorResponse{Error: "invalid request"})
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid request")
 	}
 	ids, validationError := parseEntryIDList(req.IDs)
 	if validationError != "" {
-		return c.JSON(http.StatusBadRequest, errorResponse{Error: validationError})
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, validationError)
 	}
 
 	if err := h.service.MarkManyAsRead(c.Request().Context(), ids, req.Read); err != nil {
@@ -305,37 +970,129 @@ func (h *EntryHandler) UpdateManyReadStatus(c echo.Context) error {
 
 // FetchReadable fetches the readable content from the original URL.
 // @Summary Fetch readable content
-// @Description Extract readable content from the entry's original URL using readability
+// @Description Extract readable content from the entry's original URL using readability. PDF and plain-text links are converted to paragraphs. Unless cached, extraction runs in the background and 202 is returned with status pending; poll again or wait for the entry.readable event. A failed extraction is returned by the next request, and the one after retries. wait=true blocks until the content is ready instead.
 // @Tags entries
 // @Produce json
 // @Param id path int true "Entry ID"
+// @Param wait query bool false "Wait for the extraction to finish"
 // @Success 200 {object} readableContentResponse
+// @Success 202 {object} readableContentResponse
 // @Failure 400 {object} errorResponse
 // @Failure 404 {object} errorResponse
+// @Failure 415 {object} errorResponse
 // @Router /entries/{id}/fetch-readable [post]
 func (h *EntryHandler) FetchReadable(c echo.Context) error {
 	id, err := parseIDParam(c, "id")
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, errorResponse{Error: "invalid id"})
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid id")
 	}
 
-	content, err := h.readabilityService.FetchReadableContent(c.Request().Context(), id)
+	ctx := c.Request().Context()
+	if c.QueryParam("wait") == "true" {
+		content, err := h.readabilityService.FetchReadableContent(ctx, id)
+		if err != nil {
+			return writeReadabilityError(c, id, err)
+		}
+		logger.Info("readability fetched", "module", "handler", "action", "fetch", "resource", "entry", "result", "ok", "entry_id", id)
+		return c.JSON(http.StatusOK, readableContentResponse{Status: service.ReadableReady, ReadableContent: content})
+	}
+
+	result, err := h.readabilityService.StartReadableContent(ctx, id)
 	if err != nil {
-		if errors.Is(err, service.ErrNotFound) {
-			logger.Warn("readability fetch failed", "module", "handler", "action", "fetch", "resource", "entry", "result", "failed", "entry_id", id, "error", "not found")
-			return c.JSON(http.StatusNotFound, errorResponse{Error: "entry not found"})
+		return writeReadabilityError(c, id, err)
+	}
+	if result.Status == service.ReadablePending {
+		logger.Debug("readability pending", "module", "handler", "action", "fetch", "resource", "entry", "result", "ok", "entry_id", id)
+		return c.JSON(http.StatusAccepted, readableContentResponse{Status: service.ReadablePending})
+	}
+
+	logger.Info("readability fetched", "module", "handler", "action", "fetch", "resource", "entry", "result", "ok", "entry_id", id)
+	return c.JSON(http.StatusOK, readableContentResponse{Status: service.ReadableReady, ReadableContent: result.Content})
+}
+
+// writeReadabilityError answers a failed readability extraction.
+func writeReadabilityError(c echo.Context, id int64, err error) error {
+	if errors.Is(err, service.ErrNotFound) {
+		logger.Warn("readability fetch failed", "module", "handler", "action", "fetch", "resource", "entry", "result", "failed", "entry_id", id, "error", "not found")
+		return Error(c, http.StatusNotFound, CodeNotFound, "entry not found")
+	}
+	if errors.Is(err, service.ErrInvalid) {
+		logger.Warn("readability fetch failed", "module", "handler", "action", "fetch", "resource", "entry", "result", "failed", "entry_id", id, "error", "invalid content")
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "no URL or empty content")
+	}
+	if errors.Is(err, service.ErrUnsupportedContentType) {
+		logger.Warn("readability fetch failed", "module", "handler", "action", "fetch", "resource", "entry", "result", "failed", "entry_id", id, "error", err)
+		return Error(c, http.StatusUnsupportedMediaType, CodeUnsupportedType, err.Error())
+	}
+	logger.Error("readability fetch failed", "module", "handler", "action", "fetch", "resource", "entry", "result", "failed", "entry_id", id, "error", err)
+	if errors.Is(err, service.ErrAnubisRejected) {
+		return writeServiceError(c, err)
+	}
+	// Return the actual error message
+	return Error(c, http.StatusBadGateway, CodeReadabilityFailed, err.Error())
+}
+
+// GetText returns an entry's content as plain text.
+// @Summary Get entry as plain text
+// @Description Render the entry content as plain text for text-to-speech clients: paragraphs are separated by blank lines, list items keep their bullets and numbers and images read as [Image: alt]. X-Text-Truncated tells whether maxChars cut the text.
+// @Tags entries
+// @Produce plain
+// @Param id path int true "Entry ID"
+// @Param readability query bool false "Use the readable content, extracting it first when the entry has none"
+// @Param code query string false "omit (default) replaces code blocks with [code omitted]; drop removes them"
+// @Param maxChars query int false "Cut the text to at most this many characters"
+// @Success 200 {string} string "Plain text"
+// @Header 200 {string} X-Text-Truncated "true when maxChars cut the text"
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 415 {object} errorResponse
+// @Failure 502 {object} errorResponse
+// @Router /entries/{id}/text [get]
+func (h *EntryHandler) GetText(c echo.Context) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid id")
+	}
+
+	var opts plaintext.Options
+	switch c.QueryParam("code") {
+	case "", "omit":
+	case "drop":
+		opts.DropCode = true
+	default:
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "code must be omit or drop")
+	}
+	maxChars := 0
+	if raw := c.QueryParam("maxChars"); raw != "" {
+		maxChars, err = strconv.Atoi(raw)
+		if err != nil || maxChars <= 0 {
+			return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid maxChars")
 		}
-		if errors.Is(err, service.ErrInvalid) {
-			logger.Warn("readability fetch failed", "module", "handler", "action", "fetch", "resource", "entry", "result", "failed", "entry_id", id, "error", "invalid content")
-			return c.JSON(http.StatusBadRequest, errorResponse{Error: "no URL or empty content"})
+	}
+
+	ctx := c.Request().Context()
+	entry, err := h.service.GetByID(ctx, id)
+	if err != nil {
+		logger.Warn("entry text get failed", "module", "handler", "action", "fetch", "resource", "entry", "result", "failed", "entry_id", id, "error", err)
+		return writeServiceError(c, err)
+	}
+
+	content := ""
+	if entry.Content != nil {
+		content = *entry.Content
+	}
+	if c.QueryParam("readability") == "true" {
+		if entry.ReadableContent != nil && *entry.ReadableContent != "" {
+			content = *entry.ReadableContent
+		} else if content, err = h.readabilityService.FetchReadableContent(ctx, id); err != nil {
+			return writeReadabilityError(c, id, err)
 		}
-		logger.Error("readability fetch failed", "module", "handler", "action", "fetch", "resource", "entry", "result", "failed", "entry_id", id, "error", err)
-		// Return the actual error message
-		return c.JSON(http.StatusBadGateway, errorResponse{Error: err.Error()})
 	}
 
-	logger.Info("readability fetched", "module", "handler", "action", "fetch", "resource", "entry", "result", "ok", "entry_id", id)
-	return c.JSON(http.StatusOK, readableContentResponse{ReadableContent: content})
+	text, truncated := plaintext.Truncate(plaintext.FromHTML(content, opts), maxChars)
+	c.Response().Header().Set("X-Text-Truncated", strconv.FormatBool(truncated))
+	logger.Debug("entry text fetched", "module", "handler", "action", "fetch", "resource", "entry", "result", "ok", "entry_id", id, "truncated", truncated)
+	return c.String(http.StatusOK, text)
 }
 
 // MarkAllAsRead marks all entries as read for a feed or folder.
@@ -351,21 +1108,21 @@ func (h *EntryHandler) FetchReadable(c echo.Context) error {
 func (h *EntryHandler) MarkAllAsRead(c echo.Context) error {
 	var req markAllReadRequest
 	if err := c.Bind(&req); err != nil {
-		return c.JSON(http.StatusBadRequest, errorResponse{Error: "invalid request"})
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid request")
 	}
 
 	var feedID, folderID *int64
 	if req.FeedID != nil {
 		id, err := strconv.ParseInt(*req.FeedID, 10, 64)
 		if err != nil {
-			return c.JSON(http.StatusBadRequest, errorResponse{Error: "invalid feed ID"})
+			return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid feed ID")
 		}
 		feedID = &id
 	}
 	if req.FolderID != nil {
 		id, err := strconv.ParseInt(*req.FolderID, 10, 64)
 		if err != nil {
-			return c.JSON(http.StatusBadRequest, errorResponse{Error: "invalid folder ID"})
+			return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid folder ID")
 		}
 		folderID = &id
 	}
@@ -375,7 +1132,7 @@ func (h *EntryHandler) MarkAllAsRead(c echo.Context) error {
 	if req.ContentType != nil {
 		ct := *req.ContentType
 		if ct != "article" && ct != "picture" && ct != "notification" {
-			return c.JSON(http.StatusBadRequest, errorResponse{Error: "invalid contentType"})
+			return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid contentType")
 		}
 		contentType = &ct
 	}
@@ -404,13 +1161,25 @@ func (h *EntryHandler) MarkAllAsRead(c echo.Context) error {
 
 // GetUnreadCounts returns unread counts for all feeds.
 // @Summary Get unread counts
-// @Description Get a map of feed IDs to their respective unread entry counts
+// @Description Get a map of feed IDs to their respective unread entry counts, and under today the number of entries each feed saved since the start of today in the configured timezone. Entries count as new by when they were saved, not published, so backfilled old articles are not. Feeds without entries today are left out.
 // @Tags entries
 // @Produce json
 // @Success 200 {object} unreadCountsResponse
+// @Success 304 "Not Modified"
 // @Router /unread-counts [get]
 func (h *EntryHandler) GetUnreadCounts(c echo.Context) error {
-	counts, err := h.service.GetUnreadCounts(c.Request().Context())
+	ctx := c.Request().Context()
+	// The today counts start over at midnight without any change to the data
+	recent, err := h.service.GetRecentCounts(ctx)
+	if err != nil {
+		logger.Error("entry recent counts failed", "module", "handler", "action", "list", "resource", "entry", "result", "failed", "error", err)
+		return writeServiceError(c, err)
+	}
+	if notModifiedWith(c, h.changeVersion, recent.Since.Format(time.RFC3339)) {
+		return c.NoContent(http.StatusNotModified)
+	}
+
+	counts, err := h.service.GetUnreadCounts(ctx)
 	if err != nil {
 		logger.Error("entry unread counts failed", "module", "handler", "action", "list", "resource", "entry", "result", "failed", "error", err)
 		return writeServiceError(c, err)
@@ -421,8 +1190,12 @@ func (h *EntryHandler) GetUnreadCounts(c echo.Context) error {
 	for feedID, count := range counts {
 		stringCounts[strconv.FormatInt(feedID, 10)] = count
 	}
+	today := make(map[string]int, len(recent.Counts))
+	for feedID, count := range recent.Counts {
+		today[strconv.FormatInt(feedID, 10)] = count
+	}
 
-	return c.JSON(http.StatusOK, unreadCountsResponse{Counts: stringCounts})
+	return c.JSON(http.StatusOK, unreadCountsResponse{Counts: stringCounts, Today: today})
 }
 
 // UpdateStarredStatus updates the starred status of an entry.
@@ -440,12 +1213,12 @@ func (h *EntryHandler) GetUnreadCounts(c echo.Context) error {
 func (h *EntryHandler) UpdateStarredStatus(c echo.Context) error {
 	id, err := parseIDParam(c, "id")
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, errorResponse{Error: "invalid id"})
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid id")
 	}
 
 	var req updateStarredRequest
 	if err := c.Bind(&req); err != nil {
-		return c.JSON(http.StatusBadRequest, errorResponse{Error: "invalid request"})
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid request")
 	}
 
 	if err := h.service.MarkAsStarred(c.Request().Context(), id, req.Starred); err != nil {
@@ -457,6 +1230,69 @@ func (h *EntryHandler) UpdateStarredStatus(c echo.Context) error {
 	return c.NoContent(http.StatusNoContent)
 }
 
+// UpdateNote sets the personal note of an entry.
+// @Summary Update entry note
+// @Description Attach a note of up to 10 KB to an entry, replacing any previous one. A blank note deletes it.
+// @Tags entries
+// @Accept json
+// @Produce json
+// @Param id path int true "Entry ID"
+// @Param note body updateNoteRequest true "Note"
+// @Success 204 "No Content"
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 413 {object} errorResponse
+// @Router /entries/{id}/note [put]
+func (h *EntryHandler) UpdateNote(c echo.Context) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid id")
+	}
+
+	c.Request().Body = http.MaxBytesReader(c.Response().Writer, c.Request().Body, noteBodyLimit)
+	var req updateNoteRequest
+	if err := c.Bind(&req); err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			return writeServiceError(c, service.ErrNoteTooLarge)
+		}
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid request")
+	}
+
+	if err := h.service.SetNote(c.Request().Context(), id, req.Note); err != nil {
+		logger.Warn("entry note update failed", "module", "handler", "action", "update", "resource", "entry", "result", "failed", "entry_id", id, "error", err)
+		return writeServiceError(c, err)
+	}
+
+	logger.Info("entry note updated", "module", "handler", "action", "update", "resource", "entry", "result", "ok", "entry_id", id)
+	return c.NoContent(http.StatusNoContent)
+}
+
+// DeleteNote removes the personal note of an entry.
+// @Summary Delete entry note
+// @Description Remove the note attached to an entry
+// @Tags entries
+// @Produce json
+// @Param id path int true "Entry ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Router /entries/{id}/note [delete]
+func (h *EntryHandler) DeleteNote(c echo.Context) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid id")
+	}
+
+	if err := h.service.DeleteNote(c.Request().Context(), id); err != nil {
+		logger.Warn("entry note delete failed", "module", "handler", "action", "delete", "resource", "entry", "result", "failed", "entry_id", id, "error", err)
+		return writeServiceError(c, err)
+	}
+
+	logger.Info("entry note deleted", "module", "handler", "action", "delete", "resource", "entry", "result", "ok", "entry_id", id)
+	return c.NoContent(http.StatusNoContent)
+}
+
 // GetStarredCount returns the count of starred entries.
 // @Summary Get starred count
 // @Description Get the total count of starred entries
@@ -474,9 +1310,43 @@ func (h *EntryHandler) GetStarredCount(c echo.Context) error {
 	return c.JSON(http.StatusOK, starredCountResponse{Count: count})
 }
 
+// GetArchiveStatus lists starred entries whose full-content archival failed.
+// @Summary Get starred archive status
+// @Description List starred entries whose full-content archival gave up after its retries. Starring an entry again retries its archival.
+// @Tags entries
+// @Produce json
+// @Success 200 {object} archiveStatusResponse
+// @Failure 500 {object} errorResponse
+// @Router /entries/starred/archive-status [get]
+func (h *EntryHandler) GetArchiveStatus(c echo.Context) error {
+	failed, err := h.service.ListArchiveFailures(c.Request().Context())
+	if err != nil {
+		logger.Error("entry archive status failed", "module", "handler", "action", "list", "resource", "entry", "result", "failed", "error", err)
+		return writeServiceError(c, err)
+	}
+
+	resp := archiveStatusResponse{Failed: make([]archiveFailureResponse, 0, len(failed))}
+	for _, item := range failed {
+		failure := archiveFailureResponse{
+			EntryID:   strconv.FormatInt(item.EntryID, 10),
+			FeedID:    strconv.FormatInt(item.FeedID, 10),
+			Title:     item.Title,
+			URL:       item.URL,
+			Attempts:  item.Attempts,
+			LastError: item.LastError,
+		}
+		if item.FailedAt != nil {
+			formatted := item.FailedAt.UTC().Format(time.RFC3339)
+			failure.FailedAt = &formatted
+		}
+		resp.Failed = append(resp.Failed, failure)
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
 // ClearReadabilityCache clears all readable_content from entries.
 // @Summary Clear readability cache
-// @Description Delete all extracted readable content from entries
+// @Description Delete all extracted readable content from entries, except that of archived starred entries
 // @Tags entries
 // @Produce json
 // @Success 200 {object} entryClearResponse
@@ -486,7 +1356,7 @@ func (h *EntryHandler) ClearReadabilityCache(c echo.Context) error {
 	deleted, err := h.service.ClearReadabilityCache(c.Request().Context())
 	if err != nil {
 		logger.Error("readability cache clear failed", "module", "handler", "action", "clear", "resource", "entry", "result", "failed", "error", err)
-		return c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return writeServiceError(c, err)
 	}
 
 	logger.Info("readability cache cleared", "module", "handler", "action", "clear", "resource", "entry", "result", "ok", "count", deleted)
@@ -505,13 +1375,232 @@ func (h *EntryHandler) ClearEntryCache(c echo.Context) error {
 	deleted, err := h.service.ClearEntryCache(c.Request().Context())
 	if err != nil {
 		logger.Error("entry cache clear failed", "module", "handler", "action", "clear", "resource", "entry", "result", "failed", "error", err)
-		return c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return writeServiceError(c, err)
 	}
 
 	logger.Info("entry cache cleared", "module", "handler", "action", "clear", "resource", "entry", "result", "ok", "count", deleted)
 	return c.JSON(http.StatusOK, entryClearResponse{Deleted: deleted})
 }
 
+// GetDailyDigest returns one day's entries grouped by folder and feed.
+// @Summary Get daily digest
+// @Description Get entries published on a day, grouped by folder, capped per feed, with unread counts and cached AI summaries. The day is interpreted in the configured timezone.
+// @Tags entries
+// @Produce json
+// @Param date query string false "Day as YYYY-MM-DD (default today)"
+// @Param perFeed query int false "Maximum entries per feed (default 5, max 50)"
+// @Success 200 {object} dailyDigestResponse
+// @Failure 400 {object} errorResponse
+// @Router /digest [get]
+func (h *EntryHandler) GetDailyDigest(c echo.Context) error {
+	params := service.DailyDigestParams{Date: c.QueryParam("date")}
+	if raw := c.QueryParam("perFeed"); raw != "" {
+		perFeed, err := strconv.Atoi(raw)
+		if err != nil || perFeed <= 0 {
+			return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid perFeed")
+		}
+		params.PerFeed = perFeed
+	}
+
+	digest, err := h.service.GetDailyDigest(c.Request().Context(), params)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalid) {
+			return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid date")
+		}
+		return writeServiceError(c, err)
+	}
+
+	resp := dailyDigestResponse{
+		Date:     digest.Date,
+		Timezone: digest.Timezone,
+		Start:    digest.Start.Format(time.RFC3339),
+		End:      digest.End.Format(time.RFC3339),
+		Folders:  make([]digestFolderResponse, 0, len(digest.Folders)),
+	}
+	for _, folder := range digest.Folders {
+		folderResp := digestFolderResponse{
+			Name:        folder.Name,
+			UnreadCount: folder.UnreadCount,
+			Feeds:       make([]digestFeedResponse, 0, len(folder.Feeds)),
+		}
+		if folder.FolderID != nil {
+			id := idToString(*folder.FolderID)
+			folderResp.ID = &id
+		}
+		for _, feed := range folder.Feeds {
+			feedResp := digestFeedResponse{
+				ID:          idToString(feed.FeedID),
+				Title:       feed.Title,
+				UnreadCount: feed.UnreadCount,
+				Total:       feed.Total,
+				Entries:     make([]digestEntryResponse, 0, len(feed.Entries)),
+			}
+			for _, item := range feed.Entries {
+				feedResp.Entries = append(feedResp.Entries, toDigestEntryResponse(item))
+			}
+			folderResp.Feeds = append(folderResp.Feeds, feedResp)
+		}
+		resp.Folders = append(resp.Folders, folderResp)
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// GetReadingStats returns reading statistics for a recent period.
+// @Summary Get reading statistics
+// @Description Get entries read per day, the most read feeds, the average time from publication to reading and the starred total. Days are counted in the configured timezone; reads from before read times were recorded are not included.
+// @Tags entries
+// @Produce json
+// @Param days query int false "Number of days including today (default 90, max 365)"
+// @Success 200 {object} readingStatsResponse
+// @Failure 400 {object} errorResponse
+// @Router /stats/reading [get]
+func (h *EntryHandler) GetReadingStats(c echo.Context) error {
+	days := 0
+	if raw := c.QueryParam("days"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 || parsed > service.MaxReadingStatsDays {
+			return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid days")
+		}
+		days = parsed
+	}
+
+	stats, err := h.service.GetReadingStats(c.Request().Context(), days)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalid) {
+			return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid days")
+		}
+		return writeServiceError(c, err)
+	}
+
+	resp := readingStatsResponse{
+		Days:         stats.Days,
+		Timezone:     stats.Timezone,
+		Since:        stats.Since.Format(time.RFC3339),
+		Daily:        make([]readingDayResponse, 0, len(stats.Daily)),
+		TopFeeds:     make([]readingFeedResponse, 0, len(stats.TopFeeds)),
+		TotalRead:    stats.TotalRead,
+		TotalStarred: stats.TotalStarred,
+	}
+	for _, day := range stats.Daily {
+		resp.Daily = append(resp.Daily, readingDayResponse{Date: day.Date, Count: day.Count})
+	}
+	for _, feed := range stats.TopFeeds {
+		resp.TopFeeds = append(resp.TopFeeds, readingFeedResponse{FeedID: idToString(feed.FeedID), Title: feed.Title, Count: feed.Count})
+	}
+	if stats.AverageReadDelay != nil {
+		seconds := int64(stats.AverageReadDelay.Seconds())
+		resp.AverageReadDelaySeconds = &seconds
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// Visit redirects to the original article of an entry.
+// @Summary Open original article
+// @Description Record a click-through and redirect to the entry's URL. Referrer-Policy: no-referrer keeps the destination from seeing the Gist address. The entry is marked read unless markRead is false. Only http and https URLs are redirected to.
+// @Tags entries
+// @Param id path int true "Entry ID"
+// @Param markRead query bool false "Mark the entry read (default true)"
+// @Success 302 "Redirect to the entry URL"
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Router /entries/{id}/visit [get]
+func (h *EntryHandler) Visit(c echo.Context) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid id")
+	}
+
+	markRead := true
+	if raw := c.QueryParam("markRead"); raw != "" {
+		parsed, err := strconv.ParseBool(raw)
+		if err != nil {
+			return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid markRead")
+		}
+		markRead = parsed
+	}
+
+	target, err := h.service.Visit(c.Request().Context(), id, markRead)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalid) {
+			return Error(c, http.StatusBadRequest, CodeInvalidRequest, "entry has no http or https url")
+		}
+		return writeServiceError(c, err)
+	}
+
+	// Applies to the redirected request too; no-store makes every visit count
+	c.Response().Header().Set("Referrer-Policy", "no-referrer")
+	c.Response().Header().Set(echo.HeaderCacheControl, "no-store")
+	return c.Redirect(http.StatusFound, target)
+}
+
+// GetClickStats returns how often the entries of each feed were opened.
+// @Summary Get click-through statistics
+// @Description Get the number of entries opened through /entries/{id}/visit per feed, to find feeds worth unsubscribing from. Every feed is listed, most clicked first. Days are counted in the configured timezone.
+// @Tags entries
+// @Produce json
+// @Param days query int false "Number of days including today (default 30, max 365)"
+// @Success 200 {object} clickStatsResponse
+// @Failure 400 {object} errorResponse
+// @Router /stats/clicks [get]
+func (h *EntryHandler) GetClickStats(c echo.Context) error {
+	days := 0
+	if raw := c.QueryParam("days"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 || parsed > service.MaxClickStatsDays {
+			return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid days")
+		}
+		days = parsed
+	}
+
+	stats, err := h.service.GetClickStats(c.Request().Context(), days)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalid) {
+			return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid days")
+		}
+		return writeServiceError(c, err)
+	}
+
+	resp := clickStatsResponse{
+		Days:     stats.Days,
+		Timezone: stats.Timezone,
+		Since:    stats.Since.Format(time.RFC3339),
+		Feeds:    make([]feedClicksResponse, 0, len(stats.Feeds)),
+	}
+	for _, feed := range stats.Feeds {
+		item := feedClicksResponse{FeedID: idToString(feed.FeedID), Title: feed.Title, Count: feed.Count}
+		if feed.LastClickedAt != nil {
+			formatted := feed.LastClickedAt.UTC().Format(time.RFC3339)
+			item.LastClickedAt = &formatted
+		}
+		resp.Feeds = append(resp.Feeds, item)
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+func toDigestEntryResponse(item service.DigestItem) digestEntryResponse {
+	e := item.Entry
+	resp := digestEntryResponse{
+		ID:             idToString(e.ID),
+		FeedID:         idToString(e.FeedID),
+		Title:          e.Title,
+		URL:            e.URL,
+		ThumbnailURL:   e.ThumbnailURL,
+		Author:         e.Author,
+		Read:           e.Read,
+		Starred:        e.Starred,
+		ReadingMinutes: readtime.Minutes(e.WordCount),
+		Summary:        item.Summary,
+	}
+	if e.PublishedAt != nil {
+		formatted := e.PublishedAt.UTC().Format(time.RFC3339)
+		resp.PublishedAt = &formatted
+	}
+	return resp
+}
+
 func toEntryResponse(e model.Entry) entryResponse {
 	resp := entryResponse{
 		ID:              idToString(e.ID),
@@ -524,14 +1613,25 @@ func toEntryResponse(e model.Entry) entryResponse {
 		Author:          e.Author,
 		Read:            e.Read,
 		Starred:         e.Starred,
+		WordCount:       e.WordCount,
+		ReadingMinutes:  readtime.Minutes(e.WordCount),
 		CreatedAt:       e.CreatedAt.UTC().Format(time.RFC3339),
 		UpdatedAt:       e.UpdatedAt.UTC().Format(time.RFC3339),
+		Language:        e.Language,
+		Note:            e.Note,
+		HasNote:         e.Note != nil,
+		AuthorMuted:     e.AuthorMuted,
 	}
 
 	if e.PublishedAt != nil {
 		formatted := e.PublishedAt.UTC().Format(time.RFC3339)
 		resp.PublishedAt = &formatted
 	}
+	if e.Feed != nil {
+		resp.FeedTitle = &e.Feed.Title
+		resp.FeedIconPath = e.Feed.IconPath
+		resp.FeedType = &e.Feed.Type
+	}
 
 	return resp
 }
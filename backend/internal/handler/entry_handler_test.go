@@ -1,15 +1,24 @@
 package handler_test
 
 import (
+	"context"
+	"fmt"
 	"gist/backend/internal/handler"
 	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 
 	"gist/backend/internal/model"
+	"gist/backend/internal/service"
 	"gist/backend/internal/service/mock"
+	"gist/backend/pkg/linediff"
 )
 
 func TestEntryHandler_List_Success(t *testing.T) {
@@ -17,7 +26,7 @@ func TestEntryHandler_List_Success(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockService := mock.NewMockEntryService(ctrl)
-	h := handler.NewEntryHandlerHelper(mockService, nil)
+	h := handler.NewEntryHandlerHelper(mockService, nil, nil)
 
 	e := newTestEcho()
 	req := newJSONRequest(http.MethodGet, "/entries?limit=10", nil)
@@ -43,12 +52,58 @@ func TestEntryHandler_List_Success(t *testing.T) {
 	require.False(t, resp.HasMore, "should not have more with 2 entries when limit is 10")
 }
 
+func TestEntryHandler_List_ReadingMinutes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockEntryService(ctrl)
+	h := handler.NewEntryHandlerHelper(mockService, nil, nil)
+
+	e := newTestEcho()
+	req := newJSONRequest(http.MethodGet, "/entries?minReadingMinutes=2&maxReadingMinutes=10", nil)
+	c, rec := newTestContext(e, req)
+
+	mockService.EXPECT().
+		List(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, params service.EntryListParams) ([]model.Entry, error) {
+			require.NotNil(t, params.MinReadingMinutes)
+			require.Equal(t, 2, *params.MinReadingMinutes)
+			require.NotNil(t, params.MaxReadingMinutes)
+			require.Equal(t, 10, *params.MaxReadingMinutes)
+			return []model.Entry{{ID: 1, WordCount: 600}}, nil
+		})
+
+	err := h.List(c)
+	require.NoError(t, err)
+
+	var resp handler.EntryListResponse
+	assertJSONResponse(t, rec, http.StatusOK, &resp)
+	require.Len(t, resp.Entries, 1)
+	require.Equal(t, 600, resp.Entries[0].WordCount)
+	require.Equal(t, 3, resp.Entries[0].ReadingMinutes)
+}
+
+func TestEntryHandler_List_InvalidReadingMinutes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	h := handler.NewEntryHandlerHelper(mock.NewMockEntryService(ctrl), nil, nil)
+
+	e := newTestEcho()
+	req := newJSONRequest(http.MethodGet, "/entries?minReadingMinutes=abc", nil)
+	c, rec := newTestContext(e, req)
+
+	err := h.List(c)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
 func TestEntryHandler_List_HasMore(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockService := mock.NewMockEntryService(ctrl)
-	h := handler.NewEntryHandlerHelper(mockService, nil)
+	h := handler.NewEntryHandlerHelper(mockService, nil, nil)
 
 	e := newTestEcho()
 	req := newJSONRequest(http.MethodGet, "/entries?limit=2", nil)
@@ -82,7 +137,7 @@ func TestEntryHandler_GetByID_Success(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockService := mock.NewMockEntryService(ctrl)
-	h := handler.NewEntryHandlerHelper(mockService, nil)
+	h := handler.NewEntryHandlerHelper(mockService, nil, nil)
 
 	e := newTestEcho()
 	req := newJSONRequest(http.MethodGet, "/entries/123", nil)
@@ -109,12 +164,113 @@ func TestEntryHandler_GetByID_Success(t *testing.T) {
 	require.Equal(t, "Test Entry", *resp.Title)
 }
 
+func TestEntryHandler_IncludeFeed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockEntryService(ctrl)
+	h := handler.NewEntryHandlerHelper(mockService, nil, nil)
+	e := newTestEcho()
+
+	iconPath := "icons/1.png"
+	withFeed := model.Entry{ID: 5, FeedID: 1, Feed: &model.EntryFeed{Title: "Go Blog", IconPath: &iconPath, Type: "article"}}
+
+	mockService.EXPECT().
+		List(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, params service.EntryListParams) ([]model.Entry, error) {
+			require.True(t, params.IncludeFeed)
+			return []model.Entry{withFeed}, nil
+		})
+	c, rec := newTestContext(e, newJSONRequest(http.MethodGet, "/entries?include=feed", nil))
+	require.NoError(t, h.List(c))
+	var list handler.EntryListResponse
+	assertJSONResponse(t, rec, http.StatusOK, &list)
+	require.Len(t, list.Entries, 1)
+	require.Equal(t, "Go Blog", *list.Entries[0].FeedTitle)
+	require.Equal(t, "icons/1.png", *list.Entries[0].FeedIconPath)
+	require.Equal(t, "article", *list.Entries[0].FeedType)
+
+	mockService.EXPECT().GetByIDWithFeed(gomock.Any(), int64(5)).Return(withFeed, nil)
+	c, rec = newTestContext(e, newJSONRequest(http.MethodGet, "/entries/5?include=feed", nil))
+	setPathParams(c, map[string]string{"id": "5"})
+	require.NoError(t, h.GetByID(c))
+	var single handler.EntryResponse
+	assertJSONResponse(t, rec, http.StatusOK, &single)
+	require.Equal(t, "Go Blog", *single.FeedTitle)
+
+	// Without the flag the feed fields stay out of the payload
+	mockService.EXPECT().GetByID(gomock.Any(), int64(5)).Return(model.Entry{ID: 5, FeedID: 1}, nil)
+	c, rec = newTestContext(e, newJSONRequest(http.MethodGet, "/entries/5", nil))
+	setPathParams(c, map[string]string{"id": "5"})
+	require.NoError(t, h.GetByID(c))
+	require.NotContains(t, rec.Body.String(), "feedTitle")
+
+	c, rec = newTestContext(e, newJSONRequest(http.MethodGet, "/entries/5?include=author", nil))
+	setPathParams(c, map[string]string{"id": "5"})
+	require.NoError(t, h.GetByID(c))
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestEntryHandler_GetBulk(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockEntryService(ctrl)
+	h := handler.NewEntryHandlerHelper(mockService, nil, nil)
+	e := newTestEcho()
+
+	summary := "short"
+	content := "<p>full</p>"
+	mockService.EXPECT().
+		GetByIDs(gomock.Any(), []int64{2, 1}, true).
+		Return([]service.BulkEntry{
+			{Entry: model.Entry{ID: 2, FeedID: 9, Content: &content, Starred: true}, Summary: &summary},
+			{Entry: model.Entry{ID: 1, FeedID: 9}},
+		}, nil)
+
+	reqBody := map[string]interface{}{"ids": []string{"2", "1"}, "includeAI": true}
+	c, rec := newTestContext(e, newJSONRequest(http.MethodPost, "/entries/bulk", reqBody))
+	require.NoError(t, h.GetBulk(c))
+
+	var resp struct {
+		Entries []struct {
+			ID      string  `json:"id"`
+			Content *string `json:"content"`
+			Starred bool    `json:"starred"`
+			Summary *string `json:"summary"`
+		} `json:"entries"`
+	}
+	assertJSONResponse(t, rec, http.StatusOK, &resp)
+	require.Len(t, resp.Entries, 2)
+	require.Equal(t, "2", resp.Entries[0].ID)
+	require.Equal(t, "<p>full</p>", *resp.Entries[0].Content)
+	require.True(t, resp.Entries[0].Starred)
+	require.Equal(t, "short", *resp.Entries[0].Summary)
+	require.Equal(t, "1", resp.Entries[1].ID)
+	require.Nil(t, resp.Entries[1].Summary)
+
+	mockService.EXPECT().GetByIDs(gomock.Any(), []int64{3}, false).Return(nil, nil)
+	c, rec = newTestContext(e, newJSONRequest(http.MethodPost, "/entries/bulk", map[string]interface{}{"ids": []string{"3"}}))
+	require.NoError(t, h.GetBulk(c))
+	require.JSONEq(t, `{"entries":[]}`, rec.Body.String())
+
+	ids := make([]string, 101)
+	for i := range ids {
+		ids[i] = fmt.Sprint(i + 1)
+	}
+	for _, body := range []map[string]interface{}{{"ids": ids}, {"ids": []string{}}, {"ids": []string{"abc"}}} {
+		c, rec = newTestContext(e, newJSONRequest(http.MethodPost, "/entries/bulk", body))
+		require.NoError(t, h.GetBulk(c))
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+	}
+}
+
 func TestEntryHandler_UpdateRead_Success(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockService := mock.NewMockEntryService(ctrl)
-	h := handler.NewEntryHandlerHelper(mockService, nil)
+	h := handler.NewEntryHandlerHelper(mockService, nil, nil)
 
 	e := newTestEcho()
 	reqBody := map[string]interface{}{
@@ -139,7 +295,7 @@ func TestEntryHandler_UpdateManyRead_Success(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockService := mock.NewMockEntryService(ctrl)
-	h := handler.NewEntryHandlerHelper(mockService, nil)
+	h := handler.NewEntryHandlerHelper(mockService, nil, nil)
 
 	e := newTestEcho()
 	reqBody := map[string]interface{}{
@@ -164,7 +320,7 @@ func TestEntryHandler_UpdateManyRead_InvalidID(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockService := mock.NewMockEntryService(ctrl)
-	h := handler.NewEntryHandlerHelper(mockService, nil)
+	h := handler.NewEntryHandlerHelper(mockService, nil, nil)
 
 	e := newTestEcho()
 	reqBody := map[string]interface{}{
@@ -185,7 +341,7 @@ func TestEntryHandler_UpdateStarred_Success(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockService := mock.NewMockEntryService(ctrl)
-	h := handler.NewEntryHandlerHelper(mockService, nil)
+	h := handler.NewEntryHandlerHelper(mockService, nil, nil)
 
 	e := newTestEcho()
 	reqBody := map[string]interface{}{
@@ -205,16 +361,63 @@ func TestEntryHandler_UpdateStarred_Success(t *testing.T) {
 	require.Equal(t, http.StatusNoContent, rec.Code)
 }
 
-func TestEntryHandler_FetchReadable_Success(t *testing.T) {
+func TestEntryHandler_FetchReadable(t *testing.T) {
+	tests := []struct {
+		name       string
+		result     service.ReadableResult
+		wantStatus int
+		want       handler.ReadableContentResponse
+	}{
+		{
+			name:       "cached",
+			result:     service.ReadableResult{Status: service.ReadableReady, Content: "readable content"},
+			wantStatus: http.StatusOK,
+			want:       handler.ReadableContentResponse{Status: "ready", ReadableContent: "readable content"},
+		},
+		{
+			name:       "extracting",
+			result:     service.ReadableResult{Status: service.ReadablePending},
+			wantStatus: http.StatusAccepted,
+			want:       handler.ReadableContentResponse{Status: "pending"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mock.NewMockEntryService(ctrl)
+			mockReadability := mock.NewMockReadabilityService(ctrl)
+			h := handler.NewEntryHandlerHelper(mockService, mockReadability, nil)
+
+			e := newTestEcho()
+			req := newJSONRequest(http.MethodPost, "/entries/123/fetch-readable", nil)
+			c, rec := newTestContext(e, req)
+			setPathParams(c, map[string]string{"id": "123"})
+
+			mockReadability.EXPECT().StartReadableContent(gomock.Any(), int64(123)).Return(tt.result, nil)
+
+			err := h.FetchReadable(c)
+			require.NoError(t, err)
+
+			var resp handler.ReadableContentResponse
+			assertJSONResponse(t, rec, tt.wantStatus, &resp)
+			require.Equal(t, tt.want, resp)
+		})
+	}
+}
+
+func TestEntryHandler_FetchReadable_Wait(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockService := mock.NewMockEntryService(ctrl)
 	mockReadability := mock.NewMockReadabilityService(ctrl)
-	h := handler.NewEntryHandlerHelper(mockService, mockReadability)
+	h := handler.NewEntryHandlerHelper(mockService, mockReadability, nil)
 
 	e := newTestEcho()
-	req := newJSONRequest(http.MethodPost, "/entries/123/fetch-readable", nil)
+	req := newJSONRequest(http.MethodPost, "/entries/123/fetch-readable?wait=true", nil)
 	c, rec := newTestContext(e, req)
 	setPathParams(c, map[string]string{"id": "123"})
 
@@ -230,12 +433,39 @@ func TestEntryHandler_FetchReadable_Success(t *testing.T) {
 	require.Equal(t, "readable content", resp.ReadableContent)
 }
 
+func TestEntryHandler_FetchReadable_UnsupportedContentType(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockEntryService(ctrl)
+	mockReadability := mock.NewMockReadabilityService(ctrl)
+	h := handler.NewEntryHandlerHelper(mockService, mockReadability, nil)
+
+	e := newTestEcho()
+	req := newJSONRequest(http.MethodPost, "/entries/123/fetch-readable", nil)
+	c, rec := newTestContext(e, req)
+	setPathParams(c, map[string]string{"id": "123"})
+
+	// The failure of the background extraction is returned to the next poll
+	mockReadability.EXPECT().
+		StartReadableContent(gomock.Any(), int64(123)).
+		Return(service.ReadableResult{}, fmt.Errorf("%w: image/png", service.ErrUnsupportedContentType))
+
+	err := h.FetchReadable(c)
+	require.NoError(t, err)
+
+	var resp handler.ErrorResponse
+	assertJSONResponse(t, rec, http.StatusUnsupportedMediaType, &resp)
+	require.Equal(t, handler.CodeUnsupportedType, resp.Code)
+	require.Equal(t, "unsupported content type: image/png", resp.Message)
+}
+
 func TestEntryHandler_MarkAllAsRead_Success(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockService := mock.NewMockEntryService(ctrl)
-	h := handler.NewEntryHandlerHelper(mockService, nil)
+	h := handler.NewEntryHandlerHelper(mockService, nil, nil)
 
 	e := newTestEcho()
 	reqBody := map[string]interface{}{
@@ -259,7 +489,7 @@ func TestEntryHandler_GetUnreadCounts_Success(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockService := mock.NewMockEntryService(ctrl)
-	h := handler.NewEntryHandlerHelper(mockService, nil)
+	h := handler.NewEntryHandlerHelper(mockService, nil, nil)
 
 	e := newTestEcho()
 	req := newJSONRequest(http.MethodGet, "/unread-counts", nil)
@@ -269,6 +499,9 @@ func TestEntryHandler_GetUnreadCounts_Success(t *testing.T) {
 		1: 10,
 		2: 5,
 	}
+	mockService.EXPECT().
+		GetRecentCounts(gomock.Any()).
+		Return(service.RecentCounts{Since: time.Date(2026, 3, 4, 0, 0, 0, 0, time.UTC), Counts: map[int64]int{2: 3}}, nil)
 	mockService.EXPECT().
 		GetUnreadCounts(gomock.Any()).
 		Return(counts, nil)
@@ -280,6 +513,7 @@ func TestEntryHandler_GetUnreadCounts_Success(t *testing.T) {
 	assertJSONResponse(t, rec, http.StatusOK, &resp)
 	require.Equal(t, 10, resp.Counts["1"])
 	require.Equal(t, 5, resp.Counts["2"])
+	require.Equal(t, map[string]int{"2": 3}, resp.Today)
 }
 
 func TestEntryHandler_GetStarredCount_Success(t *testing.T) {
@@ -287,7 +521,7 @@ func TestEntryHandler_GetStarredCount_Success(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockService := mock.NewMockEntryService(ctrl)
-	h := handler.NewEntryHandlerHelper(mockService, nil)
+	h := handler.NewEntryHandlerHelper(mockService, nil, nil)
 
 	e := newTestEcho()
 	req := newJSONRequest(http.MethodGet, "/starred-count", nil)
@@ -305,12 +539,54 @@ func TestEntryHandler_GetStarredCount_Success(t *testing.T) {
 	require.Equal(t, 42, resp.Count)
 }
 
+func TestEntryHandler_GetArchiveStatus(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockEntryService(ctrl)
+	h := handler.NewEntryHandlerHelper(mockService, nil, nil)
+
+	e := newTestEcho()
+	req := newJSONRequest(http.MethodGet, "/entries/starred/archive-status", nil)
+	c, rec := newTestContext(e, req)
+
+	failedAt := time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC)
+	lastError := "status 503"
+	postURL := "https://example.com/post"
+	mockService.EXPECT().
+		ListArchiveFailures(gomock.Any()).
+		Return([]model.FailedArchive{{
+			EntryArchive: model.EntryArchive{EntryID: 1234567890123, Attempts: 3, LastError: &lastError, FailedAt: &failedAt},
+			FeedID:       42,
+			URL:          &postURL,
+		}}, nil)
+
+	err := h.GetArchiveStatus(c)
+	require.NoError(t, err)
+
+	var resp handler.ArchiveStatusResponse
+	assertJSONResponse(t, rec, http.StatusOK, &resp)
+	require.Len(t, resp.Failed, 1)
+	require.Equal(t, "1234567890123", resp.Failed[0].EntryID)
+	require.Equal(t, "42", resp.Failed[0].FeedID)
+	require.Equal(t, 3, resp.Failed[0].Attempts)
+	require.Equal(t, "status 503", *resp.Failed[0].LastError)
+	require.Equal(t, "2026-03-01T08:00:00Z", *resp.Failed[0].FailedAt)
+	require.Nil(t, resp.Failed[0].Title)
+
+	// An empty list is an array, not null
+	c, rec = newTestContext(e, newJSONRequest(http.MethodGet, "/entries/starred/archive-status", nil))
+	mockService.EXPECT().ListArchiveFailures(gomock.Any()).Return(nil, nil)
+	require.NoError(t, h.GetArchiveStatus(c))
+	require.JSONEq(t, `{"failed":[]}`, rec.Body.String())
+}
+
 func TestEntryHandler_ClearCaches_Success(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockService := mock.NewMockEntryService(ctrl)
-	h := handler.NewEntryHandlerHelper(mockService, nil)
+	h := handler.NewEntryHandlerHelper(mockService, nil, nil)
 
 	// Test ClearReadabilityCache
 	e := newTestEcho()
@@ -374,6 +650,13 @@ func TestEntryHandler_List_Errors(t *testing.T) {
 			},
 			wantStatus: http.StatusBadRequest,
 		},
+		{
+			name:  "unknown include",
+			query: "?include=folder",
+			mockSetup: func(m *mock.MockEntryService) {
+			},
+			wantStatus: http.StatusBadRequest,
+		},
 		{
 			name:  "service error",
 			query: "?limit=10",
@@ -390,7 +673,7 @@ func TestEntryHandler_List_Errors(t *testing.T) {
 			defer ctrl.Finish()
 
 			mockService := mock.NewMockEntryService(ctrl)
-			h := handler.NewEntryHandlerHelper(mockService, nil)
+			h := handler.NewEntryHandlerHelper(mockService, nil, nil)
 
 			e := newTestEcho()
 			req := newJSONRequest(http.MethodGet, "/entries"+tt.query, nil)
@@ -414,3 +697,858 @@ type errorString struct {
 }
 
 func (e *errorString) Error() string { return e.s }
+
+func TestEntryHandler_ListRevisions_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockEntryService(ctrl)
+	h := handler.NewEntryHandlerHelper(mockService, nil, nil)
+
+	e := newTestEcho()
+	req := newJSONRequest(http.MethodGet, "/entries/123/revisions", nil)
+	c, rec := newTestContext(e, req)
+	setPathParams(c, map[string]string{"id": "123"})
+
+	content := "old"
+	mockService.EXPECT().
+		ListRevisions(gomock.Any(), int64(123)).
+		Return([]service.EntryRevision{{
+			EntryRevision: model.EntryRevision{ID: 7, EntryID: 123, Content: &content, CreatedAt: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)},
+			Diff: []linediff.Line{
+				{Op: linediff.Delete, Text: "old"},
+				{Op: linediff.Insert, Text: "new"},
+			},
+		}}, nil)
+
+	err := h.ListRevisions(c)
+	require.NoError(t, err)
+
+	var resp handler.EntryRevisionListResponse
+	assertJSONResponse(t, rec, http.StatusOK, &resp)
+	require.Len(t, resp.Revisions, 1)
+	require.Equal(t, "7", resp.Revisions[0].ID)
+	require.Equal(t, "2026-01-02T03:04:05Z", resp.Revisions[0].CreatedAt)
+	require.Len(t, resp.Revisions[0].Diff, 2)
+	require.Equal(t, "delete", resp.Revisions[0].Diff[0].Op)
+	require.Equal(t, "new", resp.Revisions[0].Diff[1].Text)
+}
+
+func TestEntryHandler_ListRevisions_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockEntryService(ctrl)
+	h := handler.NewEntryHandlerHelper(mockService, nil, nil)
+
+	e := newTestEcho()
+	req := newJSONRequest(http.MethodGet, "/entries/999/revisions", nil)
+	c, rec := newTestContext(e, req)
+	setPathParams(c, map[string]string{"id": "999"})
+
+	mockService.EXPECT().
+		ListRevisions(gomock.Any(), int64(999)).
+		Return(nil, service.ErrNotFound)
+
+	err := h.ListRevisions(c)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestEntryHandler_GetRawItem_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockEntryService(ctrl)
+	h := handler.NewEntryHandlerHelper(mockService, nil, nil)
+
+	e := newTestEcho()
+	req := newJSONRequest(http.MethodGet, "/entries/123/raw", nil)
+	c, rec := newTestContext(e, req)
+	setPathParams(c, map[string]string{"id": "123"})
+
+	mockService.EXPECT().
+		GetRawItem(gomock.Any(), int64(123)).
+		Return(`{"title": "Hello"}`, nil)
+
+	err := h.GetRawItem(c)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Contains(t, rec.Header().Get(echo.HeaderContentType), "text/plain")
+	require.Equal(t, `{"title": "Hello"}`, rec.Body.String())
+}
+
+func TestEntryHandler_GetRawItem_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockEntryService(ctrl)
+	h := handler.NewEntryHandlerHelper(mockService, nil, nil)
+
+	e := newTestEcho()
+	req := newJSONRequest(http.MethodGet, "/entries/999/raw", nil)
+	c, rec := newTestContext(e, req)
+	setPathParams(c, map[string]string{"id": "999"})
+
+	mockService.EXPECT().
+		GetRawItem(gomock.Any(), int64(999)).
+		Return("", service.ErrNotFound)
+
+	err := h.GetRawItem(c)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestEntryHandler_GetAdjacent_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockEntryService(ctrl)
+	h := handler.NewEntryHandlerHelper(mockService, nil, nil)
+
+	e := newTestEcho()
+	req := newJSONRequest(http.MethodGet, "/entries/123/adjacent?direction=prev&feedId=7&unreadOnly=true", nil)
+	c, rec := newTestContext(e, req)
+	setPathParams(c, map[string]string{"id": "123"})
+
+	feedID := int64(7)
+	mockService.EXPECT().
+		GetAdjacent(gomock.Any(), int64(123), service.EntryListParams{FeedID: &feedID, UnreadOnly: true}, false).
+		Return(&model.Entry{ID: 124}, nil)
+
+	err := h.GetAdjacent(c)
+	require.NoError(t, err)
+
+	var resp handler.EntryResponse
+	assertJSONResponse(t, rec, http.StatusOK, &resp)
+	require.Equal(t, "124", resp.ID)
+}
+
+func TestEntryHandler_GetAdjacent_NoneLeft(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockEntryService(ctrl)
+	h := handler.NewEntryHandlerHelper(mockService, nil, nil)
+
+	e := newTestEcho()
+	req := newJSONRequest(http.MethodGet, "/entries/123/adjacent", nil)
+	c, rec := newTestContext(e, req)
+	setPathParams(c, map[string]string{"id": "123"})
+
+	mockService.EXPECT().
+		GetAdjacent(gomock.Any(), int64(123), service.EntryListParams{}, true).
+		Return(nil, nil)
+
+	err := h.GetAdjacent(c)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusNoContent, rec.Code)
+}
+
+func TestEntryHandler_GetAdjacent_Errors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockEntryService(ctrl)
+	h := handler.NewEntryHandlerHelper(mockService, nil, nil)
+	e := newTestEcho()
+
+	for _, tc := range []struct {
+		id    string
+		query string
+	}{
+		{id: "abc"},
+		{id: "123", query: "?direction=sideways"},
+		{id: "123", query: "?contentType=video"},
+	} {
+		req := newJSONRequest(http.MethodGet, "/entries/"+tc.id+"/adjacent"+tc.query, nil)
+		c, rec := newTestContext(e, req)
+		setPathParams(c, map[string]string{"id": tc.id})
+
+		require.NoError(t, h.GetAdjacent(c))
+		require.Equal(t, http.StatusBadRequest, rec.Code, tc.query)
+	}
+
+	req := newJSONRequest(http.MethodGet, "/entries/999/adjacent", nil)
+	c, rec := newTestContext(e, req)
+	setPathParams(c, map[string]string{"id": "999"})
+	mockService.EXPECT().
+		GetAdjacent(gomock.Any(), int64(999), gomock.Any(), true).
+		Return(nil, service.ErrNotFound)
+
+	require.NoError(t, h.GetAdjacent(c))
+	require.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestEntryHandler_GetDailyDigest_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockEntryService(ctrl)
+	h := handler.NewEntryHandlerHelper(mockService, nil, nil)
+
+	e := newTestEcho()
+	req := newJSONRequest(http.MethodGet, "/digest?date=2024-03-01&perFeed=3", nil)
+	c, rec := newTestContext(e, req)
+
+	shanghai := time.FixedZone("CST", 8*3600)
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, shanghai)
+	folderID := int64(10)
+	summary := "tl;dr"
+	mockService.EXPECT().
+		GetDailyDigest(gomock.Any(), service.DailyDigestParams{Date: "2024-03-01", PerFeed: 3}).
+		Return(&service.DailyDigest{
+			Date:     "2024-03-01",
+			Timezone: "Asia/Shanghai",
+			Start:    start,
+			End:      start.AddDate(0, 0, 1),
+			Folders: []service.DigestFolder{{
+				FolderID:    &folderID,
+				Name:        "Tech",
+				UnreadCount: 4,
+				Feeds: []service.DigestFeed{{
+					FeedID: 100, Title: "Feed", UnreadCount: 4, Total: 6,
+					Entries: []service.DigestItem{{Entry: model.Entry{ID: 1, FeedID: 100}, Summary: &summary}},
+				}},
+			}},
+		}, nil)
+
+	err := h.GetDailyDigest(c)
+	require.NoError(t, err)
+
+	var resp handler.DailyDigestResponse
+	assertJSONResponse(t, rec, http.StatusOK, &resp)
+	require.Equal(t, "2024-03-01T00:00:00+08:00", resp.Start)
+	require.Len(t, resp.Folders, 1)
+	require.Equal(t, "10", *resp.Folders[0].ID)
+	require.Equal(t, 6, resp.Folders[0].Feeds[0].Total)
+	require.Equal(t, "tl;dr", *resp.Folders[0].Feeds[0].Entries[0].Summary)
+}
+
+func TestEntryHandler_GetDailyDigest_EmptyDay(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockEntryService(ctrl)
+	h := handler.NewEntryHandlerHelper(mockService, nil, nil)
+
+	e := newTestEcho()
+	req := newJSONRequest(http.MethodGet, "/digest", nil)
+	c, rec := newTestContext(e, req)
+
+	mockService.EXPECT().
+		GetDailyDigest(gomock.Any(), service.DailyDigestParams{}).
+		Return(&service.DailyDigest{Date: "2024-03-01", Timezone: "UTC", Folders: []service.DigestFolder{}}, nil)
+
+	err := h.GetDailyDigest(c)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Contains(t, rec.Body.String(), `"folders":[]`)
+}
+
+func TestEntryHandler_GetDailyDigest_Invalid(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockEntryService(ctrl)
+	h := handler.NewEntryHandlerHelper(mockService, nil, nil)
+	e := newTestEcho()
+
+	req := newJSONRequest(http.MethodGet, "/digest?perFeed=0", nil)
+	c, rec := newTestContext(e, req)
+	require.NoError(t, h.GetDailyDigest(c))
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+
+	mockService.EXPECT().
+		GetDailyDigest(gomock.Any(), service.DailyDigestParams{Date: "yesterday"}).
+		Return(nil, service.ErrInvalid)
+	req = newJSONRequest(http.MethodGet, "/digest?date=yesterday", nil)
+	c, rec = newTestContext(e, req)
+	require.NoError(t, h.GetDailyDigest(c))
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+	require.Contains(t, rec.Body.String(), "invalid date")
+}
+
+func TestEntryHandler_GetReadingStats(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockEntryService(ctrl)
+	h := handler.NewEntryHandlerHelper(mockService, nil, nil)
+
+	e := newTestEcho()
+	req := newJSONRequest(http.MethodGet, "/stats/reading?days=2", nil)
+	c, rec := newTestContext(e, req)
+
+	delay := 90 * time.Minute
+	mockService.EXPECT().
+		GetReadingStats(gomock.Any(), 2).
+		Return(&service.ReadingStats{
+			Days:             2,
+			Timezone:         "UTC",
+			Since:            time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+			Daily:            []service.ReadingDay{{Date: "2024-03-01", Count: 0}, {Date: "2024-03-02", Count: 3}},
+			TopFeeds:         []service.ReadingFeed{{FeedID: 100, Title: "Feed", Count: 3}},
+			AverageReadDelay: &delay,
+			TotalRead:        3,
+			TotalStarred:     7,
+		}, nil)
+
+	require.NoError(t, h.GetReadingStats(c))
+
+	var resp handler.ReadingStatsResponse
+	assertJSONResponse(t, rec, http.StatusOK, &resp)
+	require.Equal(t, "2024-03-01T00:00:00Z", resp.Since)
+	require.Len(t, resp.Daily, 2)
+	require.Equal(t, 3, resp.Daily[1].Count)
+	require.Equal(t, "100", resp.TopFeeds[0].FeedID)
+	require.Equal(t, int64(5400), *resp.AverageReadDelaySeconds)
+	require.Equal(t, 7, resp.TotalStarred)
+}
+
+func TestEntryHandler_GetReadingStats_NoLatency(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockEntryService(ctrl)
+	h := handler.NewEntryHandlerHelper(mockService, nil, nil)
+
+	e := newTestEcho()
+	req := newJSONRequest(http.MethodGet, "/stats/reading", nil)
+	c, rec := newTestContext(e, req)
+
+	mockService.EXPECT().
+		GetReadingStats(gomock.Any(), 0).
+		Return(&service.ReadingStats{Days: 90, Timezone: "UTC", Daily: []service.ReadingDay{}, TopFeeds: []service.ReadingFeed{}}, nil)
+
+	require.NoError(t, h.GetReadingStats(c))
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Contains(t, rec.Body.String(), `"topFeeds":[]`)
+	require.NotContains(t, rec.Body.String(), "averageReadDelaySeconds")
+}
+
+func TestEntryHandler_GetReadingStats_InvalidDays(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockEntryService(ctrl)
+	h := handler.NewEntryHandlerHelper(mockService, nil, nil)
+	e := newTestEcho()
+
+	for _, days := range []string{"0", "abc", "366"} {
+		req := newJSONRequest(http.MethodGet, "/stats/reading?days="+days, nil)
+		c, rec := newTestContext(e, req)
+		require.NoError(t, h.GetReadingStats(c))
+		require.Equal(t, http.StatusBadRequest, rec.Code, days)
+		require.Contains(t, rec.Body.String(), "invalid days")
+	}
+}
+
+func TestEntryHandler_Visit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockEntryService(ctrl)
+	h := handler.NewEntryHandlerHelper(mockService, nil, nil)
+	e := newTestEcho()
+
+	mockService.EXPECT().Visit(gomock.Any(), int64(1), true).Return("https://example.com/post", nil)
+	c, rec := newTestContext(e, httptest.NewRequest(http.MethodGet, "/entries/1/visit", nil))
+	setPathParams(c, map[string]string{"id": "1"})
+	require.NoError(t, h.Visit(c))
+	require.Equal(t, http.StatusFound, rec.Code)
+	require.Equal(t, "https://example.com/post", rec.Header().Get("Location"))
+	require.Equal(t, "no-referrer", rec.Header().Get("Referrer-Policy"))
+	require.Equal(t, "no-store", rec.Header().Get("Cache-Control"))
+
+	mockService.EXPECT().Visit(gomock.Any(), int64(1), false).Return("https://example.com/post", nil)
+	c, rec = newTestContext(e, httptest.NewRequest(http.MethodGet, "/entries/1/visit?markRead=false", nil))
+	setPathParams(c, map[string]string{"id": "1"})
+	require.NoError(t, h.Visit(c))
+	require.Equal(t, http.StatusFound, rec.Code)
+}
+
+func TestEntryHandler_Visit_Errors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockEntryService(ctrl)
+	h := handler.NewEntryHandlerHelper(mockService, nil, nil)
+	e := newTestEcho()
+
+	visit := func(id, query string) *httptest.ResponseRecorder {
+		c, rec := newTestContext(e, httptest.NewRequest(http.MethodGet, "/entries/"+id+"/visit"+query, nil))
+		setPathParams(c, map[string]string{"id": id})
+		require.NoError(t, h.Visit(c))
+		return rec
+	}
+
+	require.Equal(t, http.StatusBadRequest, visit("abc", "").Code)
+	require.Equal(t, http.StatusBadRequest, visit("1", "?markRead=maybe").Code)
+
+	// A javascript: link from a feed is not redirected to
+	mockService.EXPECT().Visit(gomock.Any(), int64(1), true).Return("", service.ErrInvalid)
+	rec := visit("1", "")
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+	require.Empty(t, rec.Header().Get("Location"))
+
+	mockService.EXPECT().Visit(gomock.Any(), int64(2), true).Return("", service.ErrNotFound)
+	require.Equal(t, http.StatusNotFound, visit("2", "").Code)
+}
+
+func TestEntryHandler_GetClickStats(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockEntryService(ctrl)
+	h := handler.NewEntryHandlerHelper(mockService, nil, nil)
+	e := newTestEcho()
+
+	clicked := time.Date(2024, 3, 2, 10, 0, 0, 0, time.UTC)
+	mockService.EXPECT().GetClickStats(gomock.Any(), 7).Return(&service.ClickStats{
+		Days:     7,
+		Timezone: "UTC",
+		Since:    time.Date(2024, 2, 25, 0, 0, 0, 0, time.UTC),
+		Feeds: []service.FeedClicks{
+			{FeedID: 100, Title: "Busy", Count: 4, LastClickedAt: &clicked},
+			{FeedID: 200, Title: "Ignored"},
+		},
+	}, nil)
+
+	c, rec := newTestContext(e, newJSONRequest(http.MethodGet, "/stats/clicks?days=7", nil))
+	require.NoError(t, h.GetClickStats(c))
+
+	var resp handler.ClickStatsResponse
+	assertJSONResponse(t, rec, http.StatusOK, &resp)
+	require.Equal(t, "2024-02-25T00:00:00Z", resp.Since)
+	require.Len(t, resp.Feeds, 2)
+	require.Equal(t, "100", resp.Feeds[0].FeedID)
+	require.Equal(t, "2024-03-02T10:00:00Z", *resp.Feeds[0].LastClickedAt)
+	require.Zero(t, resp.Feeds[1].Count)
+	require.Nil(t, resp.Feeds[1].LastClickedAt)
+
+	for _, days := range []string{"0", "abc", "366"} {
+		c, rec := newTestContext(e, newJSONRequest(http.MethodGet, "/stats/clicks?days="+days, nil))
+		require.NoError(t, h.GetClickStats(c))
+		require.Equal(t, http.StatusBadRequest, rec.Code, days)
+	}
+}
+
+func TestEntryHandler_List_ConditionalGet(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockEntryService(ctrl)
+	version := uint64(1)
+	h := handler.NewEntryHandlerHelper(mockService, nil, func() uint64 { return version })
+	e := newTestEcho()
+
+	list := func(ifNoneMatch string) *httptest.ResponseRecorder {
+		req := newJSONRequest(http.MethodGet, "/entries?feedId=1&unreadOnly=true", nil)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		c, rec := newTestContext(e, req)
+		require.NoError(t, h.List(c))
+		return rec
+	}
+
+	// Miss: no validator yet, full response with an ETag
+	mockService.EXPECT().List(gomock.Any(), gomock.Any()).Return([]model.Entry{{ID: 1}}, nil)
+	rec := list("")
+	require.Equal(t, http.StatusOK, rec.Code)
+	etag := rec.Header().Get("ETag")
+	require.True(t, strings.HasPrefix(etag, `W/"`))
+
+	// Hit: nothing changed, so the service is not queried again
+	rec = list(etag)
+	require.Equal(t, http.StatusNotModified, rec.Code)
+	require.Empty(t, rec.Body.String())
+	require.Equal(t, etag, rec.Header().Get("ETag"))
+
+	// Another filter must not reuse this ETag
+	mockService.EXPECT().List(gomock.Any(), gomock.Any()).Return(nil, nil)
+	req := newJSONRequest(http.MethodGet, "/entries?feedId=2", nil)
+	req.Header.Set("If-None-Match", etag)
+	c, other := newTestContext(e, req)
+	require.NoError(t, h.List(c))
+	require.Equal(t, http.StatusOK, other.Code)
+
+	// Invalidation: marking an entry read bumps the version (the repositories
+	// do this in production), so the old ETag no longer matches
+	mockService.EXPECT().
+		MarkAsRead(gomock.Any(), int64(1), true).
+		DoAndReturn(func(context.Context, int64, bool) error {
+			version++
+			return nil
+		})
+	req = newJSONRequest(http.MethodPatch, "/entries/1/read", map[string]interface{}{"read": true})
+	c, _ = newTestContext(e, req)
+	setPathParams(c, map[string]string{"id": "1"})
+	require.NoError(t, h.UpdateReadStatus(c))
+
+	mockService.EXPECT().List(gomock.Any(), gomock.Any()).Return([]model.Entry{{ID: 1, Read: true}}, nil)
+	rec = list(etag)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NotEqual(t, etag, rec.Header().Get("ETag"))
+}
+
+func TestEntryHandler_List_Delta(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockEntryService(ctrl)
+	h := handler.NewEntryHandlerHelper(mockService, nil, func() uint64 { return 1 })
+	e := newTestEcho()
+
+	// Full list: marked full, with the time to ask from next
+	content := "<p>body</p>"
+	mockService.EXPECT().
+		List(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, params service.EntryListParams) ([]model.Entry, error) {
+			require.Nil(t, params.UpdatedSince)
+			return []model.Entry{{ID: 1, Content: &content}}, nil
+		})
+	c, rec := newTestContext(e, newJSONRequest(http.MethodGet, "/entries?feedId=5", nil))
+	require.NoError(t, h.List(c))
+	var full handler.EntryListResponse
+	assertJSONResponse(t, rec, http.StatusOK, &full)
+	require.True(t, full.Full)
+	require.Equal(t, content, *full.Entries[0].Content)
+	lastModified := rec.Header().Get("Last-Modified")
+	since, err := http.ParseTime(lastModified)
+	require.NoError(t, err)
+
+	// Delta from Last-Modified: changed entries without content, and the deleted ones
+	mockService.EXPECT().
+		List(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, params service.EntryListParams) ([]model.Entry, error) {
+			require.NotNil(t, params.UpdatedSince)
+			require.True(t, since.Equal(*params.UpdatedSince))
+			return []model.Entry{{ID: 2, Content: &content, Read: true}}, nil
+		})
+	mockService.EXPECT().
+		ListDeleted(gomock.Any(), since, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ time.Time, feedID *int64) ([]int64, error) {
+			require.Equal(t, int64(5), *feedID)
+			return []int64{3}, nil
+		})
+	req := newJSONRequest(http.MethodGet, "/entries?feedId=5", nil)
+	req.Header.Set("If-Modified-Since", lastModified)
+	c, rec = newTestContext(e, req)
+	require.NoError(t, h.List(c))
+	var delta handler.EntryListResponse
+	assertJSONResponse(t, rec, http.StatusOK, &delta)
+	require.False(t, delta.Full)
+	require.Len(t, delta.Entries, 1)
+	require.Equal(t, "2", delta.Entries[0].ID)
+	require.Nil(t, delta.Entries[0].Content)
+	require.Equal(t, []string{"3"}, delta.Deleted)
+	require.Equal(t, "no-store", rec.Header().Get("Cache-Control"))
+	require.Empty(t, rec.Header().Get("ETag"))
+	require.NotEmpty(t, rec.Header().Get("Last-Modified"))
+
+	// include=content keeps content; later pages skip the deletions
+	mockService.EXPECT().List(gomock.Any(), gomock.Any()).Return([]model.Entry{{ID: 4, Content: &content}}, nil)
+	c, rec = newTestContext(e, newJSONRequest(http.MethodGet, "/entries?since="+url.QueryEscape(since.Format(time.RFC3339))+"&include=content&offset=50", nil))
+	require.NoError(t, h.List(c))
+	delta = handler.EntryListResponse{}
+	assertJSONResponse(t, rec, http.StatusOK, &delta)
+	require.False(t, delta.Full)
+	require.Equal(t, content, *delta.Entries[0].Content)
+	require.Empty(t, delta.Deleted)
+
+	// Nothing changed since If-Modified-Since
+	mockService.EXPECT().List(gomock.Any(), gomock.Any()).Return(nil, nil)
+	mockService.EXPECT().ListDeleted(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil)
+	req = newJSONRequest(http.MethodGet, "/entries", nil)
+	req.Header.Set("If-Modified-Since", lastModified)
+	c, rec = newTestContext(e, req)
+	require.NoError(t, h.List(c))
+	require.Equal(t, http.StatusNotModified, rec.Code)
+
+	// Next to If-None-Match, or older than deletions are remembered, the list is full
+	stale := time.Now().Add(-service.EntryTombstoneRetention - time.Hour).UTC().Format(http.TimeFormat)
+	for _, header := range []map[string]string{
+		{"If-Modified-Since": lastModified, "If-None-Match": `W/"stale"`},
+		{"If-Modified-Since": stale},
+	} {
+		mockService.EXPECT().
+			List(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, params service.EntryListParams) ([]model.Entry, error) {
+				require.Nil(t, params.UpdatedSince)
+				return nil, nil
+			})
+		req = newJSONRequest(http.MethodGet, "/entries", nil)
+		for name, value := range header {
+			req.Header.Set(name, value)
+		}
+		c, rec = newTestContext(e, req)
+		require.NoError(t, h.List(c))
+		full = handler.EntryListResponse{}
+		assertJSONResponse(t, rec, http.StatusOK, &full)
+		require.True(t, full.Full)
+	}
+
+	c, rec = newTestContext(e, newJSONRequest(http.MethodGet, "/entries?since=yesterday", nil))
+	require.NoError(t, h.List(c))
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestEntryHandler_GetUnreadCounts_NotModified(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockEntryService(ctrl)
+	h := handler.NewEntryHandlerHelper(mockService, nil, func() uint64 { return 7 })
+	e := newTestEcho()
+
+	today := time.Date(2026, 3, 4, 0, 0, 0, 0, time.UTC)
+	mockService.EXPECT().GetRecentCounts(gomock.Any()).Return(service.RecentCounts{Since: today}, nil).Times(2)
+	mockService.EXPECT().GetUnreadCounts(gomock.Any()).Return(map[int64]int{1: 3}, nil)
+	c, rec := newTestContext(e, newJSONRequest(http.MethodGet, "/unread-counts", nil))
+	require.NoError(t, h.GetUnreadCounts(c))
+	require.Equal(t, http.StatusOK, rec.Code)
+	etag := rec.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+
+	req := newJSONRequest(http.MethodGet, "/unread-counts", nil)
+	req.Header.Set("If-None-Match", `"stale", `+etag)
+	c, rec = newTestContext(e, req)
+	require.NoError(t, h.GetUnreadCounts(c))
+	require.Equal(t, http.StatusNotModified, rec.Code)
+
+	// A new day changes the today counts without any change to the data
+	mockService.EXPECT().GetRecentCounts(gomock.Any()).Return(service.RecentCounts{Since: today.AddDate(0, 0, 1)}, nil)
+	mockService.EXPECT().GetUnreadCounts(gomock.Any()).Return(map[int64]int{1: 3}, nil)
+	req = newJSONRequest(http.MethodGet, "/unread-counts", nil)
+	req.Header.Set("If-None-Match", etag)
+	c, rec = newTestContext(e, req)
+	require.NoError(t, h.GetUnreadCounts(c))
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NotEqual(t, etag, rec.Header().Get("ETag"))
+}
+
+func TestEntryHandler_GetText(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockEntryService(ctrl)
+	mockReadability := mock.NewMockReadabilityService(ctrl)
+	h := handler.NewEntryHandlerHelper(mockService, mockReadability, nil)
+	e := newTestEcho()
+
+	content := "<p>Héllo <b>world</b></p><pre>x := 1</pre><ol><li>One</li><li>Two</li></ol>"
+	readable := "<p>Readable body</p>"
+	mockService.EXPECT().GetByID(gomock.Any(), int64(1)).Return(model.Entry{ID: 1, Content: &content, ReadableContent: &readable}, nil).AnyTimes()
+	mockService.EXPECT().GetByID(gomock.Any(), int64(2)).Return(model.Entry{ID: 2, Content: &content}, nil).AnyTimes()
+
+	get := func(target string, id string) *httptest.ResponseRecorder {
+		c, rec := newTestContext(e, httptest.NewRequest(http.MethodGet, target, nil))
+		setPathParams(c, map[string]string{"id": id})
+		require.NoError(t, h.GetText(c))
+		return rec
+	}
+
+	rec := get("/entries/1/text", "1")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, echo.MIMETextPlainCharsetUTF8, rec.Header().Get(echo.HeaderContentType))
+	require.Equal(t, "false", rec.Header().Get("X-Text-Truncated"))
+	require.Equal(t, "Héllo world\n\n[code omitted]\n\n1. One\n2. Two", rec.Body.String())
+
+	rec = get("/entries/1/text?code=drop&maxChars=13", "1")
+	require.Equal(t, "true", rec.Header().Get("X-Text-Truncated"))
+	require.Equal(t, "Héllo world", rec.Body.String())
+
+	rec = get("/entries/1/text?readability=true", "1")
+	require.Equal(t, "Readable body", rec.Body.String())
+
+	// Without stored readable content the extraction runs first
+	mockReadability.EXPECT().FetchReadableContent(gomock.Any(), int64(2)).Return("<p>Fetched</p>", nil)
+	rec = get("/entries/2/text?readability=true", "2")
+	require.Equal(t, "Fetched", rec.Body.String())
+
+	mockReadability.EXPECT().FetchReadableContent(gomock.Any(), int64(2)).Return("", service.ErrUnsupportedContentType)
+	rec = get("/entries/2/text?readability=true", "2")
+	require.Equal(t, http.StatusUnsupportedMediaType, rec.Code)
+
+	for _, target := range []string{"/entries/1/text?code=keep", "/entries/1/text?maxChars=0", "/entries/1/text?maxChars=x"} {
+		rec = get(target, "1")
+		require.Equal(t, http.StatusBadRequest, rec.Code, target)
+	}
+
+	mockService.EXPECT().GetByID(gomock.Any(), int64(3)).Return(model.Entry{}, service.ErrNotFound)
+	rec = get("/entries/3/text", "3")
+	require.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestEntryHandler_Note(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockEntryService(ctrl)
+	h := handler.NewEntryHandlerHelper(mockService, nil, nil)
+	e := newTestEcho()
+
+	req := newJSONRequest(http.MethodPut, "/entries/123/note", map[string]interface{}{"note": "cite this"})
+	c, rec := newTestContext(e, req)
+	setPathParams(c, map[string]string{"id": "123"})
+	mockService.EXPECT().SetNote(gomock.Any(), int64(123), "cite this").Return(nil)
+	require.NoError(t, h.UpdateNote(c))
+	require.Equal(t, http.StatusNoContent, rec.Code)
+
+	req = newJSONRequest(http.MethodPut, "/entries/123/note", map[string]interface{}{"note": strings.Repeat("x", service.MaxEntryNoteSize+1)})
+	c, rec = newTestContext(e, req)
+	setPathParams(c, map[string]string{"id": "123"})
+	mockService.EXPECT().SetNote(gomock.Any(), int64(123), gomock.Any()).Return(service.ErrNoteTooLarge)
+	require.NoError(t, h.UpdateNote(c))
+	require.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+
+	// Oversized bodies are cut off before they are decoded
+	req = newJSONRequest(http.MethodPut, "/entries/123/note", map[string]interface{}{"note": strings.Repeat("x", 10*service.MaxEntryNoteSize)})
+	c, rec = newTestContext(e, req)
+	setPathParams(c, map[string]string{"id": "123"})
+	require.NoError(t, h.UpdateNote(c))
+	require.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+
+	req = newJSONRequest(http.MethodDelete, "/entries/9/note", nil)
+	c, rec = newTestContext(e, req)
+	setPathParams(c, map[string]string{"id": "9"})
+	mockService.EXPECT().DeleteNote(gomock.Any(), int64(9)).Return(service.ErrNotFound)
+	require.NoError(t, h.DeleteNote(c))
+	require.Equal(t, http.StatusNotFound, rec.Code)
+
+	// Entries carry their note and a flag for list rendering
+	note := "cite this"
+	req = newJSONRequest(http.MethodGet, "/entries/123", nil)
+	c, rec = newTestContext(e, req)
+	setPathParams(c, map[string]string{"id": "123"})
+	mockService.EXPECT().GetByID(gomock.Any(), int64(123)).Return(model.Entry{ID: 123, Note: &note}, nil)
+	require.NoError(t, h.GetByID(c))
+	var resp handler.EntryResponse
+	assertJSONResponse(t, rec, http.StatusOK, &resp)
+	require.Equal(t, "cite this", *resp.Note)
+	require.True(t, resp.HasNote)
+}
+
+func TestEntryHandler_List_NotesOnly(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockEntryService(ctrl)
+	h := handler.NewEntryHandlerHelper(mockService, nil, nil)
+
+	e := newTestEcho()
+	req := newJSONRequest(http.MethodGet, "/entries?notesOnly=true", nil)
+	c, rec := newTestContext(e, req)
+
+	note := "cite this"
+	mockService.EXPECT().
+		List(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, params service.EntryListParams) ([]model.Entry, error) {
+			require.True(t, params.NotesOnly)
+			return []model.Entry{{ID: 1, Note: &note}}, nil
+		})
+
+	require.NoError(t, h.List(c))
+
+	var resp handler.EntryListResponse
+	assertJSONResponse(t, rec, http.StatusOK, &resp)
+	require.Len(t, resp.Entries, 1)
+	require.True(t, resp.Entries[0].HasNote)
+}
+
+func TestEntryHandler_Count(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockEntryService(ctrl)
+	h := handler.NewEntryHandlerHelper(mockService, nil, nil)
+
+	e := newTestEcho()
+	req := newJSONRequest(http.MethodGet, "/entries/counts?unreadOnly=true&hasReadableContent=true&hasSummary=true&language=en-US&contentLanguage=pt-BR", nil)
+	c, rec := newTestContext(e, req)
+
+	mockService.EXPECT().
+		Count(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, params service.EntryListParams) (int, error) {
+			require.True(t, params.UnreadOnly)
+			require.True(t, params.HasReadableContent)
+			require.True(t, params.HasSummary)
+			require.Equal(t, "en-US", params.SummaryLanguage)
+			require.Equal(t, "pt", params.ContentLanguage)
+			return 12, nil
+		})
+
+	require.NoError(t, h.Count(c))
+
+	var resp handler.EntryCountResponse
+	assertJSONResponse(t, rec, http.StatusOK, &resp)
+	require.Equal(t, 12, resp.Count)
+}
+
+func TestEntryHandler_Count_Errors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockEntryService(ctrl)
+	h := handler.NewEntryHandlerHelper(mockService, nil, nil)
+	e := newTestEcho()
+
+	c, rec := newTestContext(e, newJSONRequest(http.MethodGet, "/entries/counts?hasSummary=true&language=xx", nil))
+	require.NoError(t, h.Count(c))
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+
+	c, rec = newTestContext(e, newJSONRequest(http.MethodGet, "/entries/counts?contentLanguage=english", nil))
+	require.NoError(t, h.Count(c))
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+
+	mockService.EXPECT().Count(gomock.Any(), gomock.Any()).Return(0, service.ErrNotFound)
+	c, rec = newTestContext(e, newJSONRequest(http.MethodGet, "/entries/counts?feedId=9", nil))
+	require.NoError(t, h.Count(c))
+	require.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestEntryHandler_Search(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockEntryService(ctrl)
+	h := handler.NewEntryHandlerHelper(mockService, nil, nil)
+	e := newTestEcho()
+
+	mockService.EXPECT().
+		Search(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, params service.EntrySearchParams) ([]model.Entry, error) {
+			require.Equal(t, "go release", params.Query)
+			require.Equal(t, service.EntrySearchInTitle, params.SearchIn)
+			require.Equal(t, int64(7), *params.FolderID)
+			require.True(t, params.BoostUnread)
+			require.True(t, params.IncludeFeed)
+			require.Equal(t, 3, params.Limit)
+			return []model.Entry{{ID: 1}, {ID: 2}, {ID: 3}}, nil
+		})
+	c, rec := newTestContext(e, newJSONRequest(http.MethodGet, "/entries/search?q=go+release&searchIn=title&folderId=7&include=feed&limit=2", nil))
+	require.NoError(t, h.Search(c))
+	var resp handler.EntrySearchResponse
+	assertJSONResponse(t, rec, http.StatusOK, &resp)
+	require.Len(t, resp.Entries, 2)
+	require.True(t, resp.HasMore)
+
+	// Strict relevance order on request
+	mockService.EXPECT().
+		Search(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, params service.EntrySearchParams) ([]model.Entry, error) {
+			require.False(t, params.BoostUnread)
+			return nil, nil
+		})
+	c, rec = newTestContext(e, newJSONRequest(http.MethodGet, "/entries/search?q=go&boostUnread=false", nil))
+	require.NoError(t, h.Search(c))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	for _, target := range []string{"/entries/search", "/entries/search?q=go&searchIn=author", "/entries/search?q=go&feedId=x"} {
+		c, rec = newTestContext(e, newJSONRequest(http.MethodGet, target, nil))
+		require.NoError(t, h.Search(c))
+		require.Equal(t, http.StatusBadRequest, rec.Code, target)
+	}
+}
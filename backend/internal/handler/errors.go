@@ -0,0 +1,179 @@
+package handler
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"gist/backend/internal/service"
+	"gist/backend/internal/service/ai"
+	"gist/backend/pkg/logger"
+)
+
+// Error codes returned in errorResponse.Code. Clients branch on these, so
+// existing values must not change; messages are for humans and may.
+const (
+	CodeInvalidRequest  = "invalid_request"
+	CodeNotFound        = "not_found"
+	CodeConflict        = "conflict"
+	CodePayloadTooLarge = "payload_too_large"
+	CodeInternal        = "internal_error"
+
+	CodeFeedConflict       = "feed_conflict"
+	CodeFeedFetchFailed    = "feed_fetch_failed"
+	CodeAnubisRejected     = "anubis_rejected"
+	CodeRefreshInProgress  = "refresh_in_progress"
+	CodeMaintenanceRunning = "maintenance_running"
+	CodeFolderCycle        = "folder_cycle"
+	CodeReadabilityFailed  = "readability_failed"
+	CodeUnsupportedType    = "unsupported_content_type"
+
+	CodeAIRateLimited   = "ai_rate_limited"
+	CodeAINotConfigured = "ai_not_configured"
+
+	CodeAuthRequired            = "auth_required"
+	CodeAuthInvalidCredentials  = "auth_invalid_credentials"
+	CodeAuthInvalidToken        = "auth_invalid_token"
+	CodeAuthUserExists          = "auth_user_exists"
+	CodeAuthUserNotFound        = "auth_user_not_found"
+	CodeAuthTooManyAttempts     = "auth_too_many_attempts"
+	CodeAuthTwoFactorRequired   = "auth_two_factor_required"
+	CodeAuthTwoFactorInvalid    = "auth_two_factor_invalid"
+	CodeAuthTwoFactorNotSetup   = "auth_two_factor_not_setup"
+	CodeAuthTwoFactorEnabled    = "auth_two_factor_enabled"
+	CodeAuthTwoFactorNotEnabled = "auth_two_factor_not_enabled"
+	CodeInsecureCookieSettings  = "settings_insecure_cookie"
+	CodeProxyInvalidURL         = "proxy_invalid_url"
+	CodeProxyTimeout            = "proxy_timeout"
+	CodeProxyInvalidImage       = "proxy_invalid_image"
+	CodeProxyUpstreamRejected   = "proxy_upstream_rejected"
+	CodeProxyFetchFailed        = "proxy_fetch_failed"
+)
+
+type errorResponse struct {
+	Code    string         `json:"code" example:"invalid_request"`
+	Message string         `json:"message" example:"invalid request"`
+	Details map[string]any `json:"details,omitempty"`
+}
+
+type serviceErrorMapping struct {
+	target  error
+	status  int
+	code    string
+	message string
+}
+
+// serviceErrorMappings is matched in order with errors.Is, so sentinels that
+// wrap a generic one (ErrFolderCycle wraps ErrInvalid) must come first.
+var serviceErrorMappings = []serviceErrorMapping{
+	{service.ErrFolderCycle, http.StatusBadRequest, CodeFolderCycle, "folder cannot be moved into itself"},
+	{service.ErrNotAFeed, http.StatusBadRequest, CodeInvalidRequest, "content is not an RSS, Atom or JSON feed"},
+	{service.ErrStaticFeedTooLarge, http.StatusRequestEntityTooLarge, CodePayloadTooLarge, "feed content is too large"},
+	{service.ErrIngestTooLarge, http.StatusRequestEntityTooLarge, CodePayloadTooLarge, "at most 200 items and 2 MB per request"},
+	{service.ErrNoteTooLarge, http.StatusRequestEntityTooLarge, CodePayloadTooLarge, "note must be at most 10 KB"},
+	{service.ErrAnubisRejected, http.StatusBadGateway, CodeAnubisRejected, "upstream rejected"},
+	{service.ErrUnsupportedContentType, http.StatusUnsupportedMediaType, CodeUnsupportedType, "unsupported content type"},
+	{service.ErrAlreadyRefreshing, http.StatusConflict, CodeRefreshInProgress, "refresh already in progress"},
+	{service.ErrMaintenanceRunning, http.StatusConflict, CodeMaintenanceRunning, "maintenance already running"},
+	{service.ErrAIRateLimited, http.StatusTooManyRequests, CodeAIRateLimited, "ai rate limit exceeded"},
+	{service.ErrAINotConfigured, http.StatusBadRequest, CodeAINotConfigured, "ai is not configured"},
+	{ai.ErrInvalidProvider, http.StatusBadRequest, CodeAINotConfigured, "ai is not configured"},
+	{ai.ErrMissingAPIKey, http.StatusBadRequest, CodeAINotConfigured, "ai is not configured"},
+	{ai.ErrMissingBaseURL, http.StatusBadRequest, CodeAINotConfigured, "ai is not configured"},
+	{ai.ErrMissingModel, http.StatusBadRequest, CodeAINotConfigured, "ai is not configured"},
+	{service.ErrInsecureSameSiteNone, http.StatusBadRequest, CodeInsecureCookieSettings, "SameSite=None requires Secure cookies"},
+
+	{service.ErrUserExists, http.StatusConflict, CodeAuthUserExists, "user already exists"},
+	{service.ErrUserNotFound, http.StatusUnauthorized, CodeAuthUserNotFound, "user not found"},
+	{service.ErrInvalidPassword, http.StatusUnauthorized, CodeAuthInvalidCredentials, "invalid credentials"},
+	{service.ErrUsernameRequired, http.StatusBadRequest, CodeInvalidRequest, "username is required"},
+	{service.ErrInvalidUsername, http.StatusBadRequest, CodeInvalidRequest, "username must be lowercase letters and numbers only"},
+	{service.ErrEmailRequired, http.StatusBadRequest, CodeInvalidRequest, "email is required"},
+	{service.ErrPasswordRequired, http.StatusBadRequest, CodeInvalidRequest, "password is required"},
+	{service.ErrPasswordTooShort, http.StatusBadRequest, CodeInvalidRequest, "password must be at least 6 characters"},
+	{service.ErrCurrentPasswordRequired, http.StatusBadRequest, CodeInvalidRequest, "current password is required"},
+	{service.ErrSamePassword, http.StatusBadRequest, CodeInvalidRequest, "new password must be different from current password"},
+	{service.ErrInvalidToken, http.StatusUnauthorized, CodeAuthInvalidToken, "invalid or expired token"},
+	{service.ErrTwoFactorCodeRequired, http.StatusBadRequest, CodeAuthTwoFactorRequired, "two-factor code is required"},
+	{service.ErrTwoFactorInvalidCode, http.StatusUnauthorized, CodeAuthTwoFactorInvalid, "invalid two-factor code"},
+	{service.ErrTwoFactorNotSetup, http.StatusBadRequest, CodeAuthTwoFactorNotSetup, "two-factor setup has not been started"},
+	{service.ErrTwoFactorEnabled, http.StatusConflict, CodeAuthTwoFactorEnabled, "two-factor authentication is already enabled"},
+	{service.ErrTwoFactorNotEnabled, http.StatusBadRequest, CodeAuthTwoFactorNotEnabled, "two-factor authentication is not enabled"},
+	{service.ErrTooManyAttempts, http.StatusTooManyRequests, CodeAuthTooManyAttempts, "too many attempts, try again later"},
+
+	{service.ErrInvalidURL, http.StatusBadRequest, CodeProxyInvalidURL, "Invalid URL"},
+	{service.ErrInvalidProtocol, http.StatusBadRequest, CodeProxyInvalidURL, "Invalid protocol"},
+	{service.ErrRequestTimeout, http.StatusGatewayTimeout, CodeProxyTimeout, "Request timeout"},
+	{service.ErrInvalidImage, http.StatusBadGateway, CodeProxyInvalidImage, "Invalid image"},
+	{service.ErrUpstreamRejected, http.StatusBadGateway, CodeProxyUpstreamRejected, "Upstream rejected"},
+	{service.ErrFetchFailed, http.StatusInternalServerError, CodeProxyFetchFailed, "Failed to fetch image"},
+
+	{service.ErrInvalid, http.StatusBadRequest, CodeInvalidRequest, "invalid request"},
+	{service.ErrNotFound, http.StatusNotFound, CodeNotFound, "resource not found"},
+	{service.ErrConflict, http.StatusConflict, CodeConflict, "conflict"},
+	{service.ErrFeedFetch, http.StatusBadGateway, CodeFeedFetchFailed, "feed fetch failed"},
+}
+
+// writeServiceError is the single place service errors become HTTP responses.
+func writeServiceError(c echo.Context, err error) error {
+	var conflict *service.FeedConflictError
+	if errors.As(err, &conflict) {
+		return c.JSON(http.StatusConflict, errorResponse{
+			Code:    CodeFeedConflict,
+			Message: "feed already exists",
+			Details: map[string]any{
+				"existingFeedId": idToString(conflict.ExistingFeed.ID),
+				"existingFeed":   toFeedResponse(conflict.ExistingFeed),
+			},
+		})
+	}
+
+	var locked *service.LoginLockedError
+	if errors.As(err, &locked) {
+		retryAfter := int(math.Ceil(locked.RetryAfter.Seconds()))
+		c.Response().Header().Set("Retry-After", strconv.Itoa(retryAfter))
+		return c.JSON(http.StatusTooManyRequests, errorResponse{
+			Code:    CodeAuthTooManyAttempts,
+			Message: "too many attempts, try again later",
+			Details: map[string]any{"retryAfter": retryAfter},
+		})
+	}
+
+	if errors.Is(err, service.ErrUpstreamRejected) {
+		// Upstream rejected the request; keep browsers from caching the failure
+		c.Response().Header().Set("Cache-Control", "no-store, no-cache, must-revalidate")
+	}
+
+	for _, m := range serviceErrorMappings {
+		if errors.Is(err, m.target) {
+			return Error(c, m.status, m.code, m.message)
+		}
+	}
+
+	logger.Error("handler internal error", "module", "handler", "action", "request", "resource", "http", "result", "failed", "error", err)
+	return Error(c, http.StatusInternalServerError, CodeInternal, "internal error")
+}
+
+// Error returns a JSON error response with the given status, code and message
+func Error(c echo.Context, status int, code, message string) error {
+	return c.JSON(status, errorResponse{Code: code, Message: message})
+}
+
+// ErrorWithDetails is Error with details for clients to act on.
+func ErrorWithDetails(c echo.Context, status int, code, message string, details map[string]any) error {
+	return c.JSON(status, errorResponse{Code: code, Message: message, Details: details})
+}
+
+// invalidField rejects a request for one of its fields, naming it in the
+// details so clients can point at the field.
+func invalidField(c echo.Context, field, message string) error {
+	return ErrorWithDetails(c, http.StatusBadRequest, CodeInvalidRequest, message, map[string]any{"field": field})
+}
+
+// fieldTooLong rejects a request whose field is over maxBytes.
+func fieldTooLong(c echo.Context, field string, maxBytes int) error {
+	return ErrorWithDetails(c, http.StatusBadRequest, CodeInvalidRequest, field+" is too long", map[string]any{"field": field, "maxBytes": maxBytes})
+}
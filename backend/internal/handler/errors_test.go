@@ -0,0 +1,121 @@
+package handler_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"gist/backend/internal/handler"
+	"gist/backend/internal/model"
+	"gist/backend/internal/service"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteServiceError_Mapping(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		status  int
+		code    string
+		message string
+	}{
+		{name: "invalid", err: service.ErrInvalid, status: http.StatusBadRequest, code: "invalid_request", message: "invalid request"},
+		{name: "not_found", err: service.ErrNotFound, status: http.StatusNotFound, code: "not_found", message: "resource not found"},
+		{name: "conflict", err: service.ErrConflict, status: http.StatusConflict, code: "conflict", message: "conflict"},
+		{name: "feed_fetch", err: service.ErrFeedFetch, status: http.StatusBadGateway, code: "feed_fetch_failed", message: "feed fetch failed"},
+		{name: "folder_cycle", err: service.ErrFolderCycle, status: http.StatusBadRequest, code: "folder_cycle"},
+		{name: "anubis_rejected", err: service.ErrAnubisRejected, status: http.StatusBadGateway, code: "anubis_rejected"},
+		{name: "refresh_in_progress", err: service.ErrAlreadyRefreshing, status: http.StatusConflict, code: "refresh_in_progress"},
+		{name: "ai_rate_limited", err: fmt.Errorf("%w: context deadline exceeded", service.ErrAIRateLimited), status: http.StatusTooManyRequests, code: "ai_rate_limited"},
+		{name: "ai_not_configured", err: fmt.Errorf("%w: model is missing", service.ErrAINotConfigured), status: http.StatusBadRequest, code: "ai_not_configured"},
+		{name: "invalid_credentials", err: service.ErrInvalidPassword, status: http.StatusUnauthorized, code: "auth_invalid_credentials", message: "invalid credentials"},
+		{name: "user_exists", err: service.ErrUserExists, status: http.StatusConflict, code: "auth_user_exists"},
+		{name: "proxy_timeout", err: service.ErrRequestTimeout, status: http.StatusGatewayTimeout, code: "proxy_timeout"},
+		{name: "wrapped", err: fmt.Errorf("update folder: %w", service.ErrNotFound), status: http.StatusNotFound, code: "not_found"},
+		{name: "default", err: errors.New("boom"), status: http.StatusInternalServerError, code: "internal_error", message: "internal error"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			e := newTestEcho()
+			req := newJSONRequest(http.MethodGet, "/", nil)
+			c, rec := newTestContext(e, req)
+
+			err := handler.WriteServiceError(c, tc.err)
+			require.NoError(t, err)
+
+			var resp handler.ErrorResponse
+			assertJSONResponse(t, rec, tc.status, &resp)
+			require.Equal(t, tc.code, resp.Code)
+			require.NotEmpty(t, resp.Message)
+			if tc.message != "" {
+				require.Equal(t, tc.message, resp.Message)
+			}
+			require.Nil(t, resp.Details)
+		})
+	}
+}
+
+func TestWriteServiceError_FeedConflictDetails(t *testing.T) {
+	e := newTestEcho()
+	req := newJSONRequest(http.MethodPost, "/feeds", nil)
+	c, rec := newTestContext(e, req)
+
+	err := handler.WriteServiceError(c, &service.FeedConflictError{ExistingFeed: model.Feed{ID: 123, Title: "Existing"}})
+	require.NoError(t, err)
+
+	var resp handler.ErrorResponse
+	assertJSONResponse(t, rec, http.StatusConflict, &resp)
+	require.Equal(t, "feed_conflict", resp.Code)
+	require.Equal(t, "123", resp.Details["existingFeedId"])
+	existing, ok := resp.Details["existingFeed"].(map[string]any)
+	require.True(t, ok)
+	require.Equal(t, "Existing", existing["title"])
+}
+
+func TestWriteServiceError_LoginLocked(t *testing.T) {
+	e := newTestEcho()
+	req := newJSONRequest(http.MethodPost, "/auth/login", nil)
+	c, rec := newTestContext(e, req)
+
+	err := handler.WriteServiceError(c, &service.LoginLockedError{RetryAfter: 90 * time.Second})
+	require.NoError(t, err)
+
+	var resp handler.ErrorResponse
+	assertJSONResponse(t, rec, http.StatusTooManyRequests, &resp)
+	require.Equal(t, "auth_too_many_attempts", resp.Code)
+	require.Equal(t, float64(90), resp.Details["retryAfter"])
+	require.Equal(t, "90", rec.Header().Get("Retry-After"))
+}
+
+func TestWriteServiceError_UpstreamRejectedNotCached(t *testing.T) {
+	e := newTestEcho()
+	req := newJSONRequest(http.MethodGet, "/", nil)
+	c, rec := newTestContext(e, req)
+
+	err := handler.WriteServiceError(c, service.ErrUpstreamRejected)
+	require.NoError(t, err)
+
+	var resp handler.ErrorResponse
+	assertJSONResponse(t, rec, http.StatusBadGateway, &resp)
+	require.Equal(t, "proxy_upstream_rejected", resp.Code)
+	require.Contains(t, rec.Header().Get("Cache-Control"), "no-store")
+}
+
+func TestErrorResponse(t *testing.T) {
+	e := newTestEcho()
+	req := newJSONRequest(http.MethodGet, "/", nil)
+	c, rec := newTestContext(e, req)
+
+	err := handler.Error(c, http.StatusBadRequest, handler.CodeInvalidRequest, "bad request")
+	require.NoError(t, err)
+
+	var resp map[string]any
+	assertJSONResponse(t, rec, http.StatusBadRequest, &resp)
+	require.Equal(t, "invalid_request", resp["code"])
+	require.Equal(t, "bad request", resp["message"])
+	require.NotContains(t, resp, "details")
+}
@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// notModified sets a weak ETag built from the change version and the request's
+// path and query, and reports whether the client's If-None-Match already holds
+// it. A nil version disables caching.
+func notModified(c echo.Context, version func() uint64) bool {
+	return notModifiedWith(c, version, "")
+}
+
+// notModifiedWith is notModified for responses that also change with extra,
+// such as counts relative to the current day.
+func notModifiedWith(c echo.Context, version func() uint64, extra string) bool {
+	if version == nil {
+		return false
+	}
+
+	// QueryParams().Encode sorts keys, so reordered filters share an ETag
+	h := fnv.New64a()
+	h.Write([]byte(c.Request().URL.Path))
+	h.Write([]byte{'?'})
+	h.Write([]byte(c.QueryParams().Encode()))
+	if extra != "" {
+		h.Write([]byte{0})
+		h.Write([]byte(extra))
+	}
+	return matchETag(c, fmt.Sprintf(`W/"%x-%x"`, version(), h.Sum64()))
+}
+
+// matchETag sets etag on the response and reports whether the client's
+// If-None-Match already holds it.
+func matchETag(c echo.Context, etag string) bool {
+	header := c.Response().Header()
+	header.Set("ETag", etag)
+	header.Set("Cache-Control", "no-cache")
+
+	for _, candidate := range strings.Split(c.Request().Header.Get("If-None-Match"), ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
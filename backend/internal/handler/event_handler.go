@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"gist/backend/internal/events"
+	"gist/backend/pkg/logger"
+)
+
+// eventHeartbeat keeps idle streams alive through proxies that drop silent connections.
+const eventHeartbeat = 20 * time.Second
+
+type EventHandler struct {
+	bus       *events.Bus
+	heartbeat time.Duration
+}
+
+func NewEventHandler(bus *events.Bus) *EventHandler {
+	return &EventHandler{bus: bus, heartbeat: eventHeartbeat}
+}
+
+func (h *EventHandler) RegisterRoutes(g *echo.Group) {
+	g.GET("/events", h.Stream)
+}
+
+// Stream sends change notifications as server-sent events.
+// @Summary Stream change events
+// @Description Server-sent events for refresh runs (refresh.started, refresh.finished), new entries (entries.new with feedId and count), read and starred changes (entries.read, entry.starred) and feed and folder changes (feed.created, feed.updated, feed.deleted, folder.created, folder.updated, folder.deleted). Payloads only carry ids and counts. A comment line is sent every 20 seconds as heartbeat. Clients that fall too far behind are disconnected and should resync after reconnecting.
+// @Tags events
+// @Produce text/event-stream
+// @Success 200 {string} string "Event stream"
+// @Router /events [get]
+func (h *EventHandler) Stream(c echo.Context) error {
+	ch, unsubscribe := h.bus.Subscribe()
+	defer unsubscribe()
+
+	res := c.Response()
+	setStreamHeaders(res, mimeEventStream)
+	res.WriteHeader(http.StatusOK)
+	res.Flush()
+
+	logger.Debug("event stream opened", "module", "handler", "action", "request", "resource", "events", "result", "ok", "subscribers", h.bus.Subscribers())
+
+	ticker := time.NewTicker(h.heartbeat)
+	defer ticker.Stop()
+	ctx := c.Request().Context()
+	for {
+		select {
+		case event, ok := <-ch:
+			if !ok {
+				logger.Debug("event stream evicted", "module", "handler", "action", "request", "resource", "events", "result", "skipped")
+				return nil
+			}
+			if err := writeEvent(res, event); err != nil {
+				return nil
+			}
+		case <-ticker.C:
+			if _, err := fmt.Fprint(res, ": heartbeat\n\n"); err != nil {
+				return nil
+			}
+		case <-ctx.Done():
+			logger.Debug("event stream closed", "module", "handler", "action", "request", "resource", "events", "result", "ok")
+			return nil
+		}
+		res.Flush()
+	}
+}
+
+func writeEvent(res *echo.Response, event events.Event) error {
+	data := event.Data
+	if data == nil {
+		data = struct{}{}
+	}
+	payload, err := json.Marshal(data)
+	if err != nil {
+		logger.Warn("event marshal failed", "module", "handler", "action", "request", "resource", "events", "result", "failed", "type", event.Type, "error", err)
+		return nil
+	}
+	_, err = fmt.Fprintf(res, "event: %s\ndata: %s\n\n", event.Type, payload)
+	return err
+}
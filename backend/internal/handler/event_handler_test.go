@@ -0,0 +1,89 @@
+package handler_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"gist/backend/internal/events"
+	"gist/backend/internal/handler"
+)
+
+// streamEvents runs the event stream until publish has run and the stream has
+// had time to write, then returns the response.
+func streamEvents(t *testing.T, h *handler.EventHandler, bus *events.Bus, publish func()) *httptest.ResponseRecorder {
+	t.Helper()
+	e := newTestEcho()
+	ctx, cancel := context.WithCancel(context.Background())
+	req := httptest.NewRequest(http.MethodGet, "/api/events", nil).WithContext(ctx)
+	c, rec := newTestContext(e, req)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- h.Stream(c)
+	}()
+	require.Eventually(t, func() bool { return bus.Subscribers() == 1 }, time.Second, time.Millisecond)
+	publish()
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("stream did not end")
+	}
+	require.Zero(t, bus.Subscribers())
+	return rec
+}
+
+func TestEventHandler_Stream(t *testing.T) {
+	bus := events.NewBus(events.SubscriberBuffer)
+	h := handler.NewEventHandler(bus)
+
+	rec := streamEvents(t, h, bus, func() {
+		bus.Publish(events.Event{Type: events.EntriesNew, Data: events.EntriesNewData{FeedID: 42, Count: 3}})
+		bus.Publish(events.Event{Type: events.RefreshFinished})
+	})
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, "text/event-stream", rec.Header().Get("Content-Type"))
+	require.Equal(t, "no-cache, no-transform", rec.Header().Get("Cache-Control"))
+	require.Equal(t, "no", rec.Header().Get("X-Accel-Buffering"))
+	require.Equal(t, "event: entries.new\ndata: {\"feedId\":\"42\",\"count\":3}\n\nevent: refresh.finished\ndata: {}\n\n", rec.Body.String())
+}
+
+func TestEventHandler_StreamHeartbeat(t *testing.T) {
+	bus := events.NewBus(events.SubscriberBuffer)
+	h := handler.NewEventHandler(bus)
+	handler.SetEventHeartbeat(h, 10*time.Millisecond)
+
+	rec := streamEvents(t, h, bus, func() {})
+
+	require.Contains(t, rec.Body.String(), ": heartbeat\n\n")
+}
+
+func TestEventHandler_StreamEndsWhenBusCloses(t *testing.T) {
+	bus := events.NewBus(events.SubscriberBuffer)
+	h := handler.NewEventHandler(bus)
+	e := newTestEcho()
+	c, _ := newTestContext(e, httptest.NewRequest(http.MethodGet, "/api/events", nil))
+
+	done := make(chan error, 1)
+	go func() {
+		done <- h.Stream(c)
+	}()
+	require.Eventually(t, func() bool { return bus.Subscribers() == 1 }, time.Second, time.Millisecond)
+	bus.Close()
+
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("stream did not end")
+	}
+}
@@ -1,9 +1,16 @@
 package handler
 
+import "time"
+
 // Export for testing
 type SummarizeResponse = summarizeResponse
 type TranslateResponse = translateResponse
+type DigestResponse = digestResponse
 type ClearCacheResponse = clearCacheResponse
+type FeedTitleTranslationResponse = feedTitleTranslationResponse
+type AIUsageResponse = aiUsageResponse
+type AutoTranslateStatusResponse = autoTranslateStatusResponse
+type BootstrapResponse = bootstrapResponse
 type UpdateProfileResponse = updateProfileResponse
 type UserResponse = userResponse
 type DomainRateLimitResponse = domainRateLimitResponse
@@ -11,34 +18,76 @@ type DomainRateLimitListResponse = domainRateLimitListResponse
 type EntryResponse = entryResponse
 type ReadableContentResponse = readableContentResponse
 type EntryListResponse = entryListResponse
+type EntrySearchResponse = entrySearchResponse
+type EntryCountResponse = entryCountResponse
+type EntryRevisionListResponse = entryRevisionListResponse
 type StarredCountResponse = starredCountResponse
+type ArchiveStatusResponse = archiveStatusResponse
 type EntryClearResponse = entryClearResponse
+type DailyDigestResponse = dailyDigestResponse
+type ReadingStatsResponse = readingStatsResponse
+type ClickStatsResponse = clickStatsResponse
+type MaintenanceResponse = maintenanceResponse
+type StorageResponse = storageResponse
+type CompactResponse = compactResponse
+type BackupImportResponse = backupImportResponse
+type ErrorResponse = errorResponse
+type StaticFeedUpdateResponse = staticFeedUpdateResponse
+type IngestTokenResponse = ingestTokenResponse
+type IngestResponse = ingestResponse
 type UnreadCountsResponse = unreadCountsResponse
-type FeedConflictResponse = feedConflictResponse
 type FeedResponse = feedResponse
+type FeedStatsResponse = feedStatsResponse
 type FeedPreviewResponse = feedPreviewResponse
+type FeedProbeResponse = feedProbeResponse
+type FeedOverlapResponse = feedOverlapResponse
+type RefreshRunResponse = refreshRunResponse
+type RefreshRunDetailResponse = refreshRunDetailResponse
+type FeedFetchLogResponse = feedFetchLogResponse
+type FeedIngestReportResponse = feedIngestReportResponse
+type FeedArchiveBackfillResponse = feedArchiveBackfillResponse
+type ArchiveBackfillResponse = archiveBackfillResponse
 type FolderResponse = folderResponse
+type FolderRuleResponse = folderRuleResponse
+type ApplyFolderRulesResponse = applyFolderRulesResponse
 type ImportStartedResponse = importStartedResponse
 type ImportCancelledResponse = importCancelledResponse
 type AuthStatusResponse = authStatusResponse
 type AuthResponseDTO = authResponse
+type TwoFactorSetupResponse = twoFactorSetupResponse
+type TwoFactorEnableResponse = twoFactorEnableResponse
+type APITokenResponse = apiTokenResponse
+type LoginEventListResponse = loginEventListResponse
+type CreatedAPITokenResponse = createdAPITokenResponse
+type APITokenListResponse = apiTokenListResponse
 type AISettingsResponse = aiSettingsResponse
 type NetworkTestResponse = networkTestResponse
 type GeneralSettingsResponse = generalSettingsResponse
 type AppearanceSettingsResponse = appearanceSettingsResponse
+type SecuritySettingsResponse = securitySettingsResponse
 type AITestResponse = aiTestResponse
+type HealthResponse = healthResponse
+type ReadinessResponse = readinessResponse
 
 var NewFeedHandlerHelper = NewFeedHandler
 var NewEntryHandlerHelper = NewEntryHandler
 var NewFolderHandlerHelper = NewFolderHandler
 var NewAuthHandlerHelper = NewAuthHandler
+var NewAPITokenHandlerHelper = NewAPITokenHandler
 var NewSettingsHandlerHelper = NewSettingsHandler
 var NewAIHandlerHelper = NewAIHandler
 var NewDomainRateLimitHandlerHelper = NewDomainRateLimitHandler
 var NewOPMLHandlerHelper = NewOPMLHandler
 var NewIconHandlerHelper = NewIconHandler
+var NewImageCacheHandlerHelper = NewImageCacheHandler
 var NewProxyHandlerHelper = NewProxyHandler
+var NewHealthHandlerHelper = NewHealthHandler
 
 var WriteServiceError = writeServiceError
 var IDPtrToString = idPtrToString
 var Itoa = itoa
+
+// SetEventHeartbeat shortens the heartbeat interval of h.
+func SetEventHeartbeat(h *EventHandler, interval time.Duration) {
+	h.heartbeat = interval
+}
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"encoding/json"
 	"errors"
 	"net/http"
 	"strconv"
@@ -8,6 +9,7 @@ import (
 	"time"
 
 	"github.com/labstack/echo/v4"
+	"github.com/mmcdole/gofeed"
 
 	"gist/backend/internal/model"
 	"gist/backend/internal/service"
@@ -18,6 +20,10 @@ import (
 type FeedHandler struct {
 	service        service.FeedService
 	refreshService service.RefreshService
+	// changeVersion feeds the ETag of the list endpoint; nil disables it.
+	changeVersion func() uint64
+	// titleTranslations fills translatedTitle in the list; nil leaves it out.
+	titleTranslations service.AIService
 }
 
 type createFeedRequest struct {
@@ -25,47 +31,370 @@ type createFeedRequest struct {
 	FolderID *string `json:"folderId"`
 	Title    string  `json:"title"`
 	Type     string  `json:"type"`
+	// TypeMode "auto" ignores Type and picks it from the fetched items.
+	TypeMode string `json:"typeMode"`
+	// InitialBackfill is "all" (default), "none" to save the current items as read,
+	// or a count N to keep only the N most recent items.
+	InitialBackfill initialBackfillValue `json:"initialBackfill" swaggertype:"string" example:"all"`
+	// BackfillArchive also crawls the archive pages the feed links to with
+	// rel="next" (RFC 5005) in the background once it is added.
+	BackfillArchive bool `json:"backfillArchive"`
+	// ArchivePages is how many archive pages to follow, 5 by default and 50 at most.
+	ArchivePages int `json:"archivePages"`
+}
+
+type createStaticFeedRequest struct {
+	// Content is the RSS, Atom or JSON feed document. It may be left empty
+	// when Title is set, for a feed that only receives pushed entries.
+	Content  string  `json:"content"`
+	FolderID *string `json:"folderId"`
+	// Title defaults to the title in Content.
+	Title string `json:"title"`
+	// Type is article, picture or notification; empty picks it from the items.
+	Type string `json:"type"`
+}
+
+type updateStaticFeedRequest struct {
+	Content string `json:"content"`
+}
+
+type staticFeedUpdateResponse struct {
+	EntriesNew     int `json:"entriesNew"`
+	EntriesUpdated int `json:"entriesUpdated"`
+}
+
+type ingestTokenResponse struct {
+	Token string `json:"token"`
+}
+
+type ingestItemRequest struct {
+	Title       string     `json:"title"`
+	URL         string     `json:"url"`
+	Content     string     `json:"content"`
+	Author      string     `json:"author"`
+	PublishedAt *time.Time `json:"publishedAt"`
+	// ExternalID identifies the item across pushes; without it the URL does.
+	// Items without a URL need one.
+	ExternalID string `json:"externalId"`
+}
+
+type ingestItemResult struct {
+	Index  int    `json:"index"`
+	Status string `json:"status" example:"created"`
+	Reason string `json:"reason,omitempty"`
+}
+
+type ingestResponse struct {
+	Created int                `json:"created"`
+	Updated int                `json:"updated"`
+	Skipped int                `json:"skipped"`
+	Results []ingestItemResult `json:"results"`
+}
+
+// staticFeedBodyLimit leaves room for JSON escaping around MaxStaticFeedSize of content.
+const staticFeedBodyLimit = 2*service.MaxStaticFeedSize + 64<<10
+
+// initialBackfillValue accepts the backfill choice as a string or a bare number.
+type initialBackfillValue string
+
+func (v *initialBackfillValue) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		*v = initialBackfillValue(text)
+		return nil
+	}
+	var count int
+	if err := json.Unmarshal(data, &count); err != nil {
+		return err
+	}
+	*v = initialBackfillValue(strconv.Itoa(count))
+	return nil
+}
+
+// nullableValue tells a field sent as null from one left out of the request.
+type nullableValue[T any] struct {
+	Set   bool
+	Value *T
+}
+
+func (v *nullableValue[T]) UnmarshalJSON(data []byte) error {
+	v.Set = true
+	v.Value = nil
+	if string(data) == "null" {
+		return nil
+	}
+	var value T
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	v.Value = &value
+	return nil
 }
 
 type updateTypeRequest struct {
 	Type string `json:"type"`
 }
 
-type feedConflictResponse struct {
-	Error        string       `json:"error" example:"feed_exists"`
-	ExistingFeed feedResponse `json:"existingFeed"`
+type updateTimezoneRequest struct {
+	// AssumeTimezone is an IANA zone name; null or empty uses the global setting.
+	AssumeTimezone *string `json:"assumeTimezone"`
+}
+
+type updateDedupeKeyRequest struct {
+	DedupeKey string `json:"dedupeKey" example:"url"`
+}
+
+type updateAutoTranslateRequest struct {
+	// AutoTranslate is inherit, on or off.
+	AutoTranslate string `json:"autoTranslate" example:"off"`
+}
+
+type updateUserAgentRequest struct {
+	// UserAgent replaces the default User-Agent of refreshes; null or blank inherits the folder's.
+	UserAgent *string `json:"userAgent" example:"Mozilla/5.0"`
+}
+
+type updateAutoReadabilityRequest struct {
+	// AutoReadability opens entries in readability mode; null inherits the folder's choice.
+	AutoReadability *bool `json:"autoReadability" example:"true"`
+}
+
+type mutedAuthorRequest struct {
+	Author string `json:"author" example:"Jane Doe"`
+}
+
+type mutedAuthorsResponse struct {
+	// Authors are matched ignoring case and surrounding spaces.
+	Authors []string `json:"authors"`
+}
+
+type pauseFeedRequest struct {
+	// Duration is a Go duration such as "24h"; set either it or Until.
+	Duration string `json:"duration,omitempty" example:"168h"`
+	// Until is an RFC3339 timestamp.
+	Until string `json:"until,omitempty" example:"2026-01-01T00:00:00Z"`
 }
 
 type updateFeedRequest struct {
 	Title                 string  `json:"title" binding:"required"`
 	FolderID              *string `json:"folderId"`
 	SummaryPromptReminder *string `json:"summaryPromptReminder"`
+	// MaxEntries caps the entries kept for the feed; 0 removes the cap.
+	MaxEntries *int `json:"maxEntries" example:"500"`
+}
+
+// patchFeedRequest changes only the fields it carries.
+type patchFeedRequest struct {
+	Title *string `json:"title" example:"My feed"`
+	// FolderID moves the feed into a folder of its type; null moves it out of any.
+	FolderID nullableValue[string] `json:"folderId" swaggertype:"string" example:"12"`
+	// SummaryPromptReminder, AssumeTimezone and UserAgent are cleared by null or a blank value.
+	SummaryPromptReminder nullableValue[string] `json:"summaryPromptReminder" swaggertype:"string"`
+	// MaxEntries caps the entries kept for the feed; 0 removes the cap.
+	MaxEntries     *int                  `json:"maxEntries" example:"500"`
+	Type           *string               `json:"type" example:"article"`
+	AssumeTimezone nullableValue[string] `json:"assumeTimezone" swaggertype:"string" example:"Asia/Shanghai"`
+	DedupeKey      *string               `json:"dedupeKey" example:"url"`
+	AutoTranslate  *string               `json:"autoTranslate" example:"off"`
+	UserAgent      nullableValue[string] `json:"userAgent" swaggertype:"string" example:"Mozilla/5.0"`
+	// AutoReadability null inherits the folder's choice.
+	AutoReadability nullableValue[bool] `json:"autoReadability" swaggertype:"boolean" example:"true"`
+	// PausedUntil is an RFC3339 timestamp in the future; null unpauses the feed.
+	PausedUntil nullableValue[string] `json:"pausedUntil" swaggertype:"string" example:"2026-01-01T00:00:00Z"`
 }
 
 type deleteFeedsRequest struct {
 	IDs []string `json:"ids"`
 }
 
+type reorderFeedsRequest struct {
+	// IDs lists every feed to place, all from one folder, in display order.
+	IDs []string `json:"ids"`
+}
+
 type feedResponse struct {
-	ID                    string  `json:"id"`
-	FolderID              *string `json:"folderId,omitempty"`
+	ID       string  `json:"id"`
+	FolderID *string `json:"folderId,omitempty"`
+	// Title is the name shown to the user: the rename if there is one,
+	// otherwise the title the feed itself declares (UpstreamTitle).
 	Title                 string  `json:"title"`
+	UpstreamTitle         string  `json:"upstreamTitle"`
+	CustomTitle           *string `json:"customTitle,omitempty"`
 	URL                   string  `json:"url"`
 	SiteURL               *string `json:"siteUrl,omitempty"`
 	Description           *string `json:"description,omitempty"`
 	SummaryPromptReminder *string `json:"summaryPromptReminder,omitempty"`
+	AssumeTimezone        *string `json:"assumeTimezone,omitempty"`
+	DedupeKey             string  `json:"dedupeKey"`
+	AutoTranslate         string  `json:"autoTranslate"`
+	Language              string  `json:"language,omitempty"`
+	PreferredUserAgent    string  `json:"preferredUserAgent"`
 	IconPath              *string `json:"iconPath,omitempty"`
 	Type                  string  `json:"type"`
+	SortOrder             int     `json:"sortOrder"`
 	ETag                  *string `json:"etag,omitempty"`
 	LastModified          *string `json:"lastModified,omitempty"`
 	ErrorMessage          *string `json:"errorMessage,omitempty"`
-	CreatedAt             string  `json:"createdAt"`
-	UpdatedAt             string  `json:"updatedAt"`
+	Paused                bool    `json:"paused"`
+	PausedUntil           *string `json:"pausedUntil,omitempty"`
+	LastFetchedAt         *string `json:"lastFetchedAt,omitempty"`
+	// SuspectCachingAt is when a fetch without ETag and Last-Modified found new
+	// entries the feed's 304 answers had hidden; cleared once they work again.
+	SuspectCachingAt *string `json:"suspectCachingAt,omitempty"`
+	// PollIntervalSeconds is the shortest time between two scheduled refreshes,
+	// 0 when the feed is refreshed on every run. PollIntervalSource says what set
+	// it: ttl or syndication for the feed's own hints, host for its domain rate
+	// limit. Only the list accounts for the domain rate limit.
+	PollIntervalSeconds int    `json:"pollIntervalSeconds"`
+	PollIntervalSource  string `json:"pollIntervalSource,omitempty"`
+	// MaxEntries caps the entries kept for the feed, 0 when unlimited.
+	MaxEntries int    `json:"maxEntries"`
+	CreatedAt  string `json:"createdAt"`
+	UpdatedAt  string `json:"updatedAt"`
+	// Stats is only filled when the list is requested with include=stats.
+	Stats *feedStatsResponse `json:"stats,omitempty"`
+	// TranslatedTitle is the cached translation of Title when the list is requested with language.
+	TranslatedTitle *string `json:"translatedTitle,omitempty"`
+	// UserAgent and AutoReadability are the feed's own values, absent when it
+	// inherits them; AutoTranslate is its own choice too.
+	UserAgent       *string `json:"userAgent,omitempty"`
+	AutoReadability *bool   `json:"autoReadability,omitempty"`
+	// Effective holds the options after inheritance from the feed's folders.
+	// Only the list resolves it.
+	Effective *feedEffectiveConfigResponse `json:"effective,omitempty"`
+}
+
+// feedConfigSourceResponse says where an effective option is set: feed,
+// folder (naming it) or default for the global setting.
+type feedConfigSourceResponse struct {
+	Source     string  `json:"source"`
+	FolderID   *string `json:"folderId,omitempty"`
+	FolderName *string `json:"folderName,omitempty"`
+}
+
+// feedEffectiveConfigResponse holds a feed's options after inheritance. An
+// absent value follows the global setting.
+type feedEffectiveConfigResponse struct {
+	UserAgent             *string                  `json:"userAgent,omitempty"`
+	UserAgentSource       feedConfigSourceResponse `json:"userAgentSource"`
+	AutoReadability       *bool                    `json:"autoReadability,omitempty"`
+	AutoReadabilitySource feedConfigSourceResponse `json:"autoReadabilitySource"`
+	AutoTranslate         *bool                    `json:"autoTranslate,omitempty"`
+	AutoTranslateSource   feedConfigSourceResponse `json:"autoTranslateSource"`
+}
+
+type feedStatsResponse struct {
+	FeedID          string  `json:"feedId"`
+	EntriesLastWeek int     `json:"entriesLastWeek"`
+	LastPublishedAt *string `json:"lastPublishedAt,omitempty"`
+	TotalEntries    int     `json:"totalEntries"`
 }
 
 type refreshStatusResponse struct {
 	IsRefreshing    bool    `json:"isRefreshing"`
 	LastRefreshedAt *string `json:"lastRefreshedAt,omitempty"`
+	// Paused is "quiet_hours" while scheduled refreshes are skipped, until PausedUntil
+	Paused      string  `json:"paused,omitempty" enums:"quiet_hours"`
+	PausedUntil *string `json:"pausedUntil,omitempty"`
+}
+
+// refreshPausedQuietHours is the Paused reason of refresh statuses inside quiet hours.
+const refreshPausedQuietHours = "quiet_hours"
+
+type refreshRunResponse struct {
+	ID             string `json:"id"`
+	Trigger        string `json:"trigger"`
+	StartedAt      string `json:"startedAt"`
+	FinishedAt     string `json:"finishedAt"`
+	FeedsTotal     int    `json:"feedsTotal"`
+	FeedsFailed    int    `json:"feedsFailed"`
+	EntriesNew     int    `json:"entriesNew"`
+	EntriesUpdated int    `json:"entriesUpdated"`
+	EntriesEvicted int    `json:"entriesEvicted"`
+}
+
+type refreshRunFeedResponse struct {
+	FeedID         string  `json:"feedId"`
+	FeedTitle      string  `json:"feedTitle"`
+	Status         string  `json:"status"`
+	EntriesNew     int     `json:"entriesNew"`
+	EntriesUpdated int     `json:"entriesUpdated"`
+	EntriesEvicted int     `json:"entriesEvicted"`
+	ErrorMessage   *string `json:"errorMessage,omitempty"`
+}
+
+type refreshRunDetailResponse struct {
+	Run   refreshRunResponse       `json:"run"`
+	Feeds []refreshRunFeedResponse `json:"feeds"`
+}
+
+type feedFetchResponse struct {
+	FetchedAt string `json:"fetchedAt"`
+	// StatusCode is 0 when no response arrived
+	StatusCode int   `json:"statusCode"`
+	DurationMs int64 `json:"durationMs"`
+	Bytes      int   `json:"bytes"`
+	// Outcome is ok, not_modified, http_error, network_error or parse_error
+	Outcome string `json:"outcome"`
+}
+
+type feedFetchLogResponse struct {
+	// Status is unknown, ok, unstable or erroring, from the success ratio and
+	// the latest failures in a row
+	Status       string              `json:"status"`
+	SuccessRatio float64             `json:"successRatio"`
+	Fetches      []feedFetchResponse `json:"fetches"`
+}
+
+type ingestIssueResponse struct {
+	// Reason is missing_link, duplicate, backfill_limit, truncated, unparsed_date
+	// or implausible_date
+	Reason string `json:"reason"`
+	Title  string `json:"title,omitempty"`
+	Link   string `json:"link,omitempty"`
+	GUID   string `json:"guid,omitempty"`
+	// Detail is the truncated field, the unparsed date text or the implausible date
+	Detail string `json:"detail,omitempty"`
+}
+
+type ingestReportResponse struct {
+	CheckedAt string                `json:"checkedAt"`
+	Items     int                   `json:"items"`
+	Saved     int                   `json:"saved"`
+	Counts    map[string]int        `json:"counts"`
+	Issues    []ingestIssueResponse `json:"issues"`
+}
+
+type feedIngestReportResponse struct {
+	// Report is null when the last refresh took every item as delivered
+	Report *ingestReportResponse `json:"report"`
+}
+
+type archiveBackfillRequest struct {
+	// MaxPages is how many archive pages to follow, 5 by default and 50 at most.
+	MaxPages int  `json:"maxPages"`
+	MarkRead bool `json:"markRead"`
+}
+
+type archiveBackfillResponse struct {
+	FeedID string `json:"feedId"`
+	// Status is running, done or failed
+	Status   string `json:"status"`
+	MaxPages int    `json:"maxPages"`
+	// Pages counts the documents fetched, the feed document included
+	Pages      int  `json:"pages"`
+	Items      int  `json:"items"`
+	NewEntries int  `json:"newEntries"`
+	HasMore    bool `json:"hasMore"`
+	// Error tells why a failed backfill stopped, such as a page linking back to an earlier one
+	Error      string  `json:"error,omitempty"`
+	StartedAt  string  `json:"startedAt"`
+	FinishedAt *string `json:"finishedAt,omitempty"`
+}
+
+type feedArchiveBackfillResponse struct {
+	// Backfill is null when none ran since the server started
+	Backfill *archiveBackfillResponse `json:"backfill"`
 }
 
 type feedPreviewResponse struct {
@@ -76,21 +405,110 @@ type feedPreviewResponse struct {
 	ImageURL    *string `json:"imageUrl,omitempty"`
 	ItemCount   *int    `json:"itemCount,omitempty"`
 	LastUpdated *string `json:"lastUpdated,omitempty"`
+	// SuggestedType is the feed type the server would pick in auto mode.
+	SuggestedType string `json:"suggestedType"`
+}
+
+type feedProbeTimingResponse struct {
+	DNSMs     int64 `json:"dnsMs"`
+	ConnectMs int64 `json:"connectMs"`
+	TLSMs     int64 `json:"tlsMs"`
+	TTFBMs    int64 `json:"ttfbMs"`
+	TotalMs   int64 `json:"totalMs"`
+}
+
+type feedProbeResponse struct {
+	URL             string                  `json:"url"`
+	UserAgent       string                  `json:"userAgent"`
+	UserAgentValue  string                  `json:"userAgentValue"`
+	SentCookie      bool                    `json:"sentCookie"`
+	ResolvedIPs     []string                `json:"resolvedIps"`
+	RemoteAddr      string                  `json:"remoteAddr,omitempty"`
+	Timing          feedProbeTimingResponse `json:"timing"`
+	Error           string                  `json:"error,omitempty"`
+	StatusCode      int                     `json:"statusCode,omitempty"`
+	Headers         map[string]string       `json:"headers"`
+	BodySize        int                     `json:"bodySize"`
+	BodyPreview     string                  `json:"bodyPreview"`
+	AnubisPage      bool                    `json:"anubisPage"`
+	AnubisChallenge bool                    `json:"anubisChallenge"`
+	Parsed          bool                    `json:"parsed"`
+	ParseError      string                  `json:"parseError,omitempty"`
+	FeedType        string                  `json:"feedType,omitempty"`
+	ItemCount       int                     `json:"itemCount"`
+	ItemsNoGUID     int                     `json:"itemsNoGuid"`
+	ItemsNoLink     int                     `json:"itemsNoLink"`
+	// Ingest is what a refresh would skip or alter, null when nothing
+	Ingest *ingestReportResponse `json:"ingest"`
 }
 
-func NewFeedHandler(service service.FeedService, refreshService service.RefreshService) *FeedHandler {
-	return &FeedHandler{service: service, refreshService: refreshService}
+type feedOverlapResponse struct {
+	ID        string       `json:"id"`
+	Feed      feedResponse `json:"feed"`
+	OtherFeed feedResponse `json:"otherFeed"`
+	// SameSite is set when both feeds declare the same site URL.
+	SameSite bool `json:"sameSite"`
+	// SharedEntries counts the 50 most recent entries of one feed the other has
+	// too; OverlapRatio is their share of that sample, the higher of the two.
+	SharedEntries int     `json:"sharedEntries"`
+	OverlapRatio  float64 `json:"overlapRatio"`
+	DetectedAt    string  `json:"detectedAt"`
+}
+
+type mergeFeedOverlapRequest struct {
+	// KeepFeedID is the feed of the pair to keep; the pair's first feed if empty.
+	KeepFeedID string `json:"keepFeedId,omitempty"`
+}
+
+// NewFeedHandler answers conditional GETs with 304 while changeVersion stays
+// put, and adds cached translated titles from titleTranslations to the list
+// when ?language= is given. Both may be nil.
+func NewFeedHandler(service service.FeedService, refreshService service.RefreshService, changeVersion func() uint64, titleTranslations service.AIService) *FeedHandler {
+	return &FeedHandler{service: service, refreshService: refreshService, changeVersion: changeVersion, titleTranslations: titleTranslations}
+}
+
+// RegisterIngestRoutes registers the routes authenticated by feed ingest
+// tokens; g must not require a login.
+func (h *FeedHandler) RegisterIngestRoutes(g *echo.Group) {
+	g.POST("/feeds/:id/entries", h.Ingest)
 }
 
 func (h *FeedHandler) RegisterRoutes(g *echo.Group) {
 	g.POST("/feeds", h.Create)
+	g.POST("/feeds/static", h.CreateStatic)
 	g.POST("/feeds/refresh", h.RefreshAll)
 	g.GET("/feeds/refresh", h.RefreshStatus)
+	g.GET("/refresh/runs", h.ListRefreshRuns)
+	g.GET("/refresh/runs/:id", h.GetRefreshRun)
 	g.GET("/feeds/preview", h.Preview)
+	g.GET("/feeds/stats", h.Stats)
 	g.GET("/feeds", h.List)
+	g.GET("/feeds/overlaps", h.ListOverlaps)
+	g.POST("/feeds/overlaps/:id/merge", h.MergeOverlap)
+	g.PUT("/feeds/reorder", h.Reorder)
 	g.PUT("/feeds/:id", h.Update)
+	g.PATCH("/feeds/:id", h.Patch)
+	g.PUT("/feeds/:id/static", h.UpdateStatic)
+	g.POST("/feeds/:id/ingest-token", h.CreateIngestToken)
 	g.PATCH("/feeds/:id/type", h.UpdateType)
+	g.PATCH("/feeds/:id/timezone", h.UpdateTimezone)
+	g.PATCH("/feeds/:id/dedupe-key", h.UpdateDedupeKey)
+	g.PATCH("/feeds/:id/auto-translate", h.UpdateAutoTranslate)
+	g.PATCH("/feeds/:id/user-agent", h.UpdateUserAgent)
+	g.PATCH("/feeds/:id/auto-readability", h.UpdateAutoReadability)
+	g.GET("/feeds/:id/fetch-log", h.GetFetchLog)
+	g.GET("/feeds/:id/ingest-report", h.GetIngestReport)
+	g.POST("/feeds/:id/backfill-archive", h.StartArchiveBackfill)
+	g.GET("/feeds/:id/backfill-archive", h.GetArchiveBackfill)
+	g.GET("/feeds/:id/muted-authors", h.ListMutedAuthors)
+	g.PUT("/feeds/:id/muted-authors", h.MuteAuthor)
+	g.DELETE("/feeds/:id/muted-authors", h.UnmuteAuthor)
+	g.POST("/feeds/:id/pause", h.Pause)
+	g.DELETE("/feeds/:id/pause", h.Unpause)
 	g.DELETE("/feeds/:id", h.Delete)
+	g.POST("/feeds/:id/restore", h.Restore)
+	g.POST("/feeds/:id/probe", h.Probe)
+	g.POST("/feeds/:id/refresh", h.RefreshFeed)
 	g.DELETE("/feeds", h.DeleteBatch)
 }
 
@@ -103,71 +521,378 @@ func (h *FeedHandler) RegisterRoutes(g *echo.Group) {
 // @Param feed body createFeedRequest true "Feed creation request"
 // @Success 201 {object} feedResponse
 // @Failure 400 {object} errorResponse
-// @Failure 409 {object} feedConflictResponse "Feed URL already exists"
+// @Failure 409 {object} errorResponse "Feed URL already exists; details carry existingFeedId and existingFeed"
 // @Router /feeds [post]
 func (h *FeedHandler) Create(c echo.Context) error {
 	var req createFeedRequest
 	if err := c.Bind(&req); err != nil {
 		logger.Debug("feed create invalid request", "module", "handler", "action", "create", "resource", "feed", "result", "failed", "error", err)
-		return c.JSON(http.StatusBadRequest, errorResponse{Error: "invalid request"})
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid request")
 	}
 	var folderID *int64
 	if req.FolderID != nil {
 		id, err := strconv.ParseInt(*req.FolderID, 10, 64)
 		if err != nil {
-			return c.JSON(http.StatusBadRequest, errorResponse{Error: "invalid folder ID"})
+			return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid folder ID")
 		}
 		folderID = &id
 	}
 	feedType := req.Type
-	if feedType == "" {
-		feedType = "article"
-	} else if !isValidContentType(feedType) {
-		return c.JSON(http.StatusBadRequest, errorResponse{Error: "type must be article, picture, or notification"})
+	switch req.TypeMode {
+	case "auto":
+		feedType = service.FeedTypeAuto
+	case "", "manual":
+		if feedType == "" {
+			feedType = "article"
+		} else if !isValidContentType(feedType) {
+			return Error(c, http.StatusBadRequest, CodeInvalidRequest, "type must be article, picture, or notification")
+		}
+	default:
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "typeMode must be auto or manual")
+	}
+	backfill, err := service.ParseInitialBackfill(string(req.InitialBackfill))
+	if err != nil {
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "initialBackfill must be all, none, or a positive number")
+	}
+	if req.ArchivePages < 0 || req.ArchivePages > service.MaxArchivePages {
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "archivePages must be between 1 and 50")
 	}
-	feed, err := h.service.Add(c.Request().Context(), req.URL, folderID, req.Title, feedType)
+	feed, err := h.service.Add(c.Request().Context(), req.URL, folderID, req.Title, feedType, backfill)
 	if err != nil {
 		var conflictErr *service.FeedConflictError
 		if errors.As(err, &conflictErr) {
-			logger.Warn("feed create conflict", "module", "handler", "action", "create", "resource", "feed", "result", "failed", "host", network.ExtractHost(req.URL), "feed_id", conflictErr.ExistingFeed.ID, "feed_title", conflictErr.ExistingFeed.Title)
-			return c.JSON(http.StatusConflict, feedConflictResponse{
-				Error:        "feed_exists",
-				ExistingFeed: toFeedResponse(conflictErr.ExistingFeed),
-			})
+			logger.Warn("feed create conflict", "module", "handler", "action", "create", "resource", "feed", "result", "failed", "host", network.ExtractHost(req.URL), "feed_id", conflictErr.ExistingFeed.ID, "feed_title", conflictErr.ExistingFeed.DisplayTitle())
+			return writeServiceError(c, err)
 		}
 		logger.Error("feed create failed", "module", "handler", "action", "create", "resource", "feed", "result", "failed", "host", network.ExtractHost(req.URL), "error", err)
 		return writeServiceError(c, err)
 	}
-	logger.Info("feed created", "module", "handler", "action", "create", "resource", "feed", "result", "ok", "feed_id", feed.ID, "feed_title", feed.Title, "host", network.ExtractHost(feed.URL))
+	logger.Info("feed created", "module", "handler", "action", "create", "resource", "feed", "result", "ok", "feed_id", feed.ID, "feed_title", feed.DisplayTitle(), "host", network.ExtractHost(feed.URL))
+	if req.BackfillArchive {
+		// The feed is there either way; the backfill's status tells how it went
+		opts := service.ArchiveBackfillOptions{MaxPages: req.ArchivePages, MarkRead: backfill.MarkRead}
+		if _, err := h.refreshService.StartArchiveBackfill(c.Request().Context(), feed.ID, opts); err != nil {
+			logger.Warn("feed archive backfill start failed", "module", "handler", "action", "create", "resource", "feed", "result", "failed", "feed_id", feed.ID, "error", err)
+		}
+	}
+	return c.JSON(http.StatusCreated, toFeedResponse(feed))
+}
+
+// CreateStatic creates a feed from pasted feed content.
+// @Summary Create a static feed
+// @Description Add a feed from a pasted RSS, Atom or JSON feed document, for sources the server cannot reach. The feed gets a synthetic static:// URL and is skipped by refreshes; send newer content with PUT /feeds/{id}/static. With a title and no content the feed starts empty, to be filled with POST /feeds/{id}/entries.
+// @Tags feeds
+// @Accept json
+// @Produce json
+// @Param feed body createStaticFeedRequest true "Static feed creation request"
+// @Success 201 {object} feedResponse
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 413 {object} errorResponse
+// @Router /feeds/static [post]
+func (h *FeedHandler) CreateStatic(c echo.Context) error {
+	var req createStaticFeedRequest
+	if err := bindStaticFeedRequest(c, &req); err != nil {
+		return err
+	}
+	var folderID *int64
+	if req.FolderID != nil {
+		id, err := strconv.ParseInt(*req.FolderID, 10, 64)
+		if err != nil {
+			return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid folder ID")
+		}
+		folderID = &id
+	}
+	feedType := req.Type
+	if feedType == "" {
+		feedType = service.FeedTypeAuto
+	} else if !isValidContentType(feedType) {
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "type must be article, picture, or notification")
+	}
+
+	// A titled feed without content starts empty and is filled by pushes
+	parsed := &gofeed.Feed{}
+	if strings.TrimSpace(req.Content) != "" || strings.TrimSpace(req.Title) == "" {
+		var err error
+		parsed, err = service.ParseStaticFeed([]byte(req.Content))
+		if err != nil {
+			logger.Debug("static feed create invalid content", "module", "handler", "action", "create", "resource", "feed", "result", "failed", "error", err)
+			return writeServiceError(c, err)
+		}
+	}
+	ctx := c.Request().Context()
+	feed, err := h.service.AddStatic(ctx, parsed, folderID, req.Title, feedType)
+	if err != nil {
+		logger.Error("static feed create failed", "module", "handler", "action", "create", "resource", "feed", "result", "failed", "error", err)
+		return writeServiceError(c, err)
+	}
+	newCount, _, err := h.refreshService.IngestStatic(ctx, feed.ID, parsed)
+	if err != nil {
+		logger.Error("static feed ingest failed", "module", "handler", "action", "create", "resource", "feed", "result", "failed", "feed_id", feed.ID, "error", err)
+		return writeServiceError(c, err)
+	}
+	logger.Info("static feed created", "module", "handler", "action", "create", "resource", "feed", "result", "ok", "feed_id", feed.ID, "feed_title", feed.DisplayTitle(), "new", newCount)
 	return c.JSON(http.StatusCreated, toFeedResponse(feed))
 }
 
+// UpdateStatic adds the entries of newly pasted content to a static feed.
+// @Summary Update a static feed
+// @Description Save the items of a newer copy of the feed document. Items already stored are matched by hash, so sending the same content twice adds nothing.
+// @Tags feeds
+// @Accept json
+// @Produce json
+// @Param id path int true "Feed ID"
+// @Param request body updateStaticFeedRequest true "Feed content"
+// @Success 200 {object} staticFeedUpdateResponse
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 413 {object} errorResponse
+// @Router /feeds/{id}/static [put]
+func (h *FeedHandler) UpdateStatic(c echo.Context) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid request")
+	}
+	var req updateStaticFeedRequest
+	if err := bindStaticFeedRequest(c, &req); err != nil {
+		return err
+	}
+	parsed, err := service.ParseStaticFeed([]byte(req.Content))
+	if err != nil {
+		logger.Debug("static feed update invalid content", "module", "handler", "action", "update", "resource", "feed", "result", "failed", "feed_id", id, "error", err)
+		return writeServiceError(c, err)
+	}
+	newCount, updatedCount, err := h.refreshService.IngestStatic(c.Request().Context(), id, parsed)
+	if err != nil {
+		logger.Error("static feed update failed", "module", "handler", "action", "update", "resource", "feed", "result", "failed", "feed_id", id, "error", err)
+		return writeServiceError(c, err)
+	}
+	logger.Info("static feed updated", "module", "handler", "action", "update", "resource", "feed", "result", "ok", "feed_id", id, "new", newCount, "updated", updatedCount)
+	return c.JSON(http.StatusOK, staticFeedUpdateResponse{EntriesNew: newCount, EntriesUpdated: updatedCount})
+}
+
+// CreateIngestToken issues the token for pushing entries into a static feed.
+// @Summary Create a feed ingest token
+// @Description Generate the bearer token for POST /feeds/{id}/entries, replacing the previous one. The token is only shown once.
+// @Tags feeds
+// @Produce json
+// @Param id path int true "Feed ID"
+// @Success 201 {object} ingestTokenResponse
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Router /feeds/{id}/ingest-token [post]
+func (h *FeedHandler) CreateIngestToken(c echo.Context) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid request")
+	}
+	token, err := h.service.CreateIngestToken(c.Request().Context(), id)
+	if err != nil {
+		return writeServiceError(c, err)
+	}
+	return c.JSON(http.StatusCreated, ingestTokenResponse{Token: token})
+}
+
+// Ingest saves entries pushed by an outside integration into a static feed.
+// @Summary Push entries into a feed
+// @Description Save up to 200 items (2 MB) into a static feed, authenticated with the feed's ingest token instead of a login. Items are deduplicated like fetched ones, by externalId when given, else by url; items linking to the same page are merged. Items without a url need an externalId and are skipped otherwise.
+// @Tags feeds
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Feed ID"
+// @Param items body []ingestItemRequest true "Items to save"
+// @Success 200 {object} ingestResponse
+// @Failure 400 {object} errorResponse
+// @Failure 401 {object} errorResponse
+// @Failure 413 {object} errorResponse
+// @Router /feeds/{id}/entries [post]
+func (h *FeedHandler) Ingest(c echo.Context) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid request")
+	}
+	ctx := c.Request().Context()
+
+	var token string
+	if scheme, value, ok := strings.Cut(c.Request().Header.Get("Authorization"), " "); ok && strings.EqualFold(scheme, "bearer") {
+		token = strings.TrimSpace(value)
+	}
+	if err := h.service.ValidateIngestToken(ctx, id, token); err != nil {
+		if errors.Is(err, service.ErrInvalidToken) {
+			logger.Warn("feed ingest unauthorized", "module", "handler", "action", "save", "resource", "feed", "result", "failed", "feed_id", id, "remote_ip", c.RealIP())
+		}
+		return writeServiceError(c, err)
+	}
+
+	c.Request().Body = http.MaxBytesReader(c.Response().Writer, c.Request().Body, service.MaxIngestBodySize)
+	var req []ingestItemRequest
+	if err := c.Bind(&req); err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			return writeServiceError(c, service.ErrIngestTooLarge)
+		}
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid request")
+	}
+
+	items := make([]service.IngestItem, 0, len(req))
+	for _, item := range req {
+		items = append(items, service.IngestItem{
+			Title:       item.Title,
+			URL:         item.URL,
+			Content:     item.Content,
+			Author:      item.Author,
+			PublishedAt: item.PublishedAt,
+			ExternalID:  item.ExternalID,
+		})
+	}
+	results, err := h.refreshService.IngestItems(ctx, id, items)
+	if err != nil {
+		return writeServiceError(c, err)
+	}
+
+	resp := ingestResponse{Results: make([]ingestItemResult, 0, len(results))}
+	for _, result := range results {
+		switch result.Status {
+		case service.IngestCreated:
+			resp.Created++
+		case service.IngestUpdated:
+			resp.Updated++
+		default:
+			resp.Skipped++
+		}
+		resp.Results = append(resp.Results, ingestItemResult{Index: result.Index, Status: result.Status, Reason: result.Reason})
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// bindStaticFeedRequest binds a request carrying pasted feed content, answering
+// oversized bodies with 413. A non-nil return has already been written.
+func bindStaticFeedRequest(c echo.Context, req interface{}) error {
+	c.Request().Body = http.MaxBytesReader(c.Response().Writer, c.Request().Body, staticFeedBodyLimit)
+	if err := c.Bind(req); err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			return writeServiceError(c, service.ErrStaticFeedTooLarge)
+		}
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid request")
+	}
+	return nil
+}
+
 // List returns all feeds, optionally filtered by folder.
 // @Summary List feeds
 // @Description Get a list of all subscribed feeds
 // @Tags feeds
 // @Produce json
 // @Param folderId query int false "Filter by folder ID"
+// @Param include query string false "Set to stats to inline activity stats"
+// @Param language query string false "Include cached title translations in this language, e.g. en-US"
 // @Success 200 {array} feedResponse
+// @Success 304 "Not Modified"
+// @Failure 400 {object} errorResponse
 // @Router /feeds [get]
 func (h *FeedHandler) List(c echo.Context) error {
 	var folderID *int64
 	if raw := c.QueryParam("folderId"); raw != "" {
 		parsed, err := strconv.ParseInt(raw, 10, 64)
 		if err != nil {
-			return c.JSON(http.StatusBadRequest, errorResponse{Error: "invalid request"})
+			return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid request")
 		}
 		folderID = &parsed
 	}
+	include := c.QueryParam("include")
+	if include != "" && include != "stats" {
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid include")
+	}
+	language := c.QueryParam("language")
+	if !validLanguage(language) {
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "unsupported language")
+	}
+	if notModified(c, h.changeVersion) {
+		return c.NoContent(http.StatusNotModified)
+	}
 
 	feeds, err := h.service.List(c.Request().Context(), folderID)
 	if err != nil {
 		logger.Error("feed list failed", "module", "handler", "action", "list", "resource", "feed", "result", "failed", "folder_id", folderID, "error", err)
 		return writeServiceError(c, err)
 	}
+
+	var statsByFeed map[int64]model.FeedActivityStats
+	if include == "stats" {
+		stats, err := h.service.GetActivityStats(c.Request().Context())
+		if err != nil {
+			logger.Error("feed stats failed", "module", "handler", "action", "list", "resource", "feed", "result", "failed", "error", err)
+			return writeServiceError(c, err)
+		}
+		statsByFeed = make(map[int64]model.FeedActivityStats, len(stats))
+		for _, stat := range stats {
+			statsByFeed[stat.FeedID] = stat
+		}
+	}
+
+	var translatedTitles map[int64]string
+	if language != "" && h.titleTranslations != nil {
+		translatedTitles, err = h.titleTranslations.GetCachedFeedTitles(c.Request().Context(), feeds, language)
+		if err != nil {
+			// The list is still usable with original titles
+			logger.Warn("feed title translations lookup failed", "module", "handler", "action", "list", "resource", "feed", "result", "failed", "language", language, "error", err)
+		}
+	}
+
+	var pollIntervals map[int64]service.PollInterval
+	if h.refreshService != nil {
+		pollIntervals = h.refreshService.PollIntervals(c.Request().Context(), feeds)
+	}
+
+	configs, err := h.service.EffectiveConfigs(c.Request().Context(), feeds)
+	if err != nil {
+		logger.Error("feed config resolve failed", "module", "handler", "action", "list", "resource", "feed", "result", "failed", "error", err)
+		return writeServiceError(c, err)
+	}
+
 	response := make([]feedResponse, 0, len(feeds))
 	for _, feed := range feeds {
-		response = append(response, toFeedResponse(feed))
+		item := toFeedResponse(feed)
+		if interval, ok := pollIntervals[feed.ID]; ok {
+			item.PollIntervalSeconds = int(interval.Interval / time.Second)
+			item.PollIntervalSource = interval.Source
+		}
+		if title, ok := translatedTitles[feed.ID]; ok {
+			item.TranslatedTitle = &title
+		}
+		if config, ok := configs[feed.ID]; ok {
+			effective := toFeedEffectiveConfigResponse(config)
+			item.Effective = &effective
+		}
+		if statsByFeed != nil {
+			stat := statsByFeed[feed.ID]
+			stat.FeedID = feed.ID
+			statResponse := toFeedStatsResponse(stat)
+			item.Stats = &statResponse
+		}
+		response = append(response, item)
+	}
+	return c.JSON(http.StatusOK, response)
+}
+
+// Stats returns per-feed entry volume.
+// @Summary Get feed activity stats
+// @Description Get entries in the last 7 days, last published time and total entry count for every feed
+// @Tags feeds
+// @Produce json
+// @Success 200 {array} feedStatsResponse
+// @Router /feeds/stats [get]
+func (h *FeedHandler) Stats(c echo.Context) error {
+	stats, err := h.service.GetActivityStats(c.Request().Context())
+	if err != nil {
+		logger.Error("feed stats failed", "module", "handler", "action", "list", "resource", "feed", "result", "failed", "error", err)
+		return writeServiceError(c, err)
+	}
+	response := make([]feedStatsResponse, 0, len(stats))
+	for _, stat := range stats {
+		response = append(response, toFeedStatsResponse(stat))
 	}
 	return c.JSON(http.StatusOK, response)
 }
@@ -184,7 +909,7 @@ func (h *FeedHandler) List(c echo.Context) error {
 func (h *FeedHandler) Preview(c echo.Context) error {
 	rawURL := strings.TrimSpace(c.QueryParam("url"))
 	if rawURL == "" {
-		return c.JSON(http.StatusBadRequest, errorResponse{Error: "invalid request"})
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid request")
 	}
 	preview, err := h.service.Preview(c.Request().Context(), rawURL)
 	if err != nil {
@@ -197,7 +922,7 @@ func (h *FeedHandler) Preview(c echo.Context) error {
 
 // Update updates an existing feed.
 // @Summary Update a feed
-// @Description Update an existing feed. title is required; folder and summary prompt reminder are optional.
+// @Description Update an existing feed. title is required; folder, summary prompt reminder and entry cap are optional.
 // @Tags feeds
 // @Accept json
 // @Produce json
@@ -210,32 +935,116 @@ func (h *FeedHandler) Preview(c echo.Context) error {
 func (h *FeedHandler) Update(c echo.Context) error {
 	id, err := parseIDParam(c, "id")
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, errorResponse{Error: "invalid request"})
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid request")
 	}
 	var req updateFeedRequest
 	if err := c.Bind(&req); err != nil {
-		return c.JSON(http.StatusBadRequest, errorResponse{Error: "invalid request"})
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid request")
 	}
 	if strings.TrimSpace(req.Title) == "" {
-		return c.JSON(http.StatusBadRequest, errorResponse{Error: "title is required"})
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "title is required")
 	}
 	var folderID *int64
 	if req.FolderID != nil {
 		fid, err := strconv.ParseInt(*req.FolderID, 10, 64)
 		if err != nil {
-			return c.JSON(http.StatusBadRequest, errorResponse{Error: "invalid folder ID"})
+			return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid folder ID")
 		}
 		folderID = &fid
 	}
-	feed, err := h.service.Update(c.Request().Context(), id, req.Title, folderID, req.SummaryPromptReminder)
+	if req.MaxEntries != nil && *req.MaxEntries < 0 {
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "maxEntries must not be negative")
+	}
+	feed, err := h.service.Update(c.Request().Context(), id, req.Title, folderID, req.SummaryPromptReminder, req.MaxEntries)
 	if err != nil {
 		logger.Error("feed update failed", "module", "handler", "action", "update", "resource", "feed", "result", "failed", "feed_id", id, "error", err)
 		return writeServiceError(c, err)
 	}
-	logger.Info("feed updated", "module", "handler", "action", "update", "resource", "feed", "result", "ok", "feed_id", feed.ID, "feed_title", feed.Title)
+	logger.Info("feed updated", "module", "handler", "action", "update", "resource", "feed", "result", "ok", "feed_id", feed.ID, "feed_title", feed.DisplayTitle())
+	return c.JSON(http.StatusOK, toFeedResponse(feed))
+}
+
+// Patch changes some settings of a feed at once.
+// @Summary Patch a feed
+// @Description Change only the fields present in the body. Every field is validated before any is saved, so an invalid one leaves the feed unchanged. null clears folderId, summaryPromptReminder, assumeTimezone, userAgent, autoReadability and pausedUntil.
+// @Tags feeds
+// @Accept json
+// @Produce json
+// @Param id path int true "Feed ID"
+// @Param feed body patchFeedRequest true "Fields to change"
+// @Success 200 {object} feedResponse
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Router /feeds/{id} [patch]
+func (h *FeedHandler) Patch(c echo.Context) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid request")
+	}
+	var req patchFeedRequest
+	if err := c.Bind(&req); err != nil {
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid request")
+	}
+
+	patch := service.FeedPatch{
+		Title:                 req.Title,
+		MaxEntries:            req.MaxEntries,
+		Type:                  req.Type,
+		DedupeKey:             req.DedupeKey,
+		AutoTranslate:         req.AutoTranslate,
+		SummaryPromptReminder: clearedIfNull(req.SummaryPromptReminder),
+		AssumeTimezone:        clearedIfNull(req.AssumeTimezone),
+		UserAgent:             clearedIfNull(req.UserAgent),
+	}
+	if req.FolderID.Set {
+		if req.FolderID.Value == nil {
+			patch.ClearFolder = true
+		} else {
+			folderID, err := strconv.ParseInt(*req.FolderID.Value, 10, 64)
+			if err != nil {
+				return invalidField(c, "folderId", "invalid folder ID")
+			}
+			patch.FolderID = &folderID
+		}
+	}
+	if req.AutoReadability.Set {
+		patch.AutoReadability = req.AutoReadability.Value
+		patch.InheritAutoReadability = req.AutoReadability.Value == nil
+	}
+	if req.PausedUntil.Set {
+		if req.PausedUntil.Value == nil {
+			patch.Unpause = true
+		} else {
+			until, err := time.Parse(time.RFC3339, *req.PausedUntil.Value)
+			if err != nil {
+				return invalidField(c, "pausedUntil", "invalid pausedUntil")
+			}
+			patch.PausedUntil = &until
+		}
+	}
+
+	feed, err := h.service.Patch(c.Request().Context(), id, patch)
+	if err != nil {
+		logger.Error("feed patch failed", "module", "handler", "action", "update", "resource", "feed", "result", "failed", "feed_id", id, "error", err)
+		return writeServiceError(c, err)
+	}
+	logger.Info("feed patched", "module", "handler", "action", "update", "resource", "feed", "result", "ok", "feed_id", feed.ID, "feed_title", feed.DisplayTitle())
 	return c.JSON(http.StatusOK, toFeedResponse(feed))
 }
 
+// clearedIfNull maps a field sent as null to "", which clears it in a
+// service.FeedPatch, and one left out to nil.
+func clearedIfNull(value nullableValue[string]) *string {
+	if !value.Set {
+		return nil
+	}
+	if value.Value == nil {
+		empty := ""
+		return &empty
+	}
+	return value.Value
+}
+
 // UpdateType updates the content type of a feed.
 // @Summary Update feed type
 // @Description Change the content type of a feed (article/picture/notification)
@@ -250,14 +1059,14 @@ func (h *FeedHandler) Update(c echo.Context) error {
 func (h *FeedHandler) UpdateType(c echo.Context) error {
 	id, err := parseIDParam(c, "id")
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, errorResponse{Error: "invalid request"})
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid request")
 	}
 	var req updateTypeRequest
 	if err := c.Bind(&req); err != nil {
-		return c.JSON(http.StatusBadRequest, errorResponse{Error: "invalid request"})
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid request")
 	}
 	if !isValidContentType(req.Type) {
-		return c.JSON(http.StatusBadRequest, errorResponse{Error: "type must be article, picture, or notification"})
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "type must be article, picture, or notification")
 	}
 	if err := h.service.UpdateType(c.Request().Context(), id, req.Type); err != nil {
 		logger.Error("feed update type failed", "module", "handler", "action", "update", "resource", "feed", "result", "failed", "feed_id", id, "type", req.Type, "error", err)
@@ -267,19 +1076,337 @@ func (h *FeedHandler) UpdateType(c echo.Context) error {
 	return c.NoContent(http.StatusNoContent)
 }
 
-// Delete deletes a feed.
-// @Summary Delete a feed
-// @Description Unsubscribe from a feed
+// UpdateTimezone sets the timezone assumed for a feed's dates that carry none.
+// @Summary Update feed timezone
+// @Description Set the IANA timezone used for item dates without zone info. Null or empty falls back to the global timezone setting.
 // @Tags feeds
+// @Accept json
 // @Param id path int true "Feed ID"
+// @Param request body updateTimezoneRequest true "Timezone update request"
 // @Success 204 "No Content"
 // @Failure 400 {object} errorResponse
 // @Failure 404 {object} errorResponse
-// @Router /feeds/{id} [delete]
+// @Router /feeds/{id}/timezone [patch]
+func (h *FeedHandler) UpdateTimezone(c echo.Context) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid request")
+	}
+	var req updateTimezoneRequest
+	if err := c.Bind(&req); err != nil {
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid request")
+	}
+	if err := h.service.UpdateAssumeTimezone(c.Request().Context(), id, req.AssumeTimezone); err != nil {
+		if errors.Is(err, service.ErrInvalid) {
+			return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid timezone")
+		}
+		logger.Error("feed update timezone failed", "module", "handler", "action", "update", "resource", "feed", "result", "failed", "feed_id", id, "error", err)
+		return writeServiceError(c, err)
+	}
+	logger.Info("feed timezone updated", "module", "handler", "action", "update", "resource", "feed", "result", "ok", "feed_id", id)
+	return c.NoContent(http.StatusNoContent)
+}
+
+// UpdateDedupeKey sets how a feed's entries are told apart.
+// @Summary Update feed dedupe key
+// @Description Set the entry hash strategy: auto (GUID, then link, then title and content), guid, url or title_content. Links wrapped in tracking redirects are unwrapped first. Switching to url or title_content re-hashes stored entries in the background and merges duplicates.
+// @Tags feeds
+// @Accept json
+// @Param id path int true "Feed ID"
+// @Param request body updateDedupeKeyRequest true "Dedupe key update request"
+// @Success 204 "No Content"
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Router /feeds/{id}/dedupe-key [patch]
+func (h *FeedHandler) UpdateDedupeKey(c echo.Context) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid request")
+	}
+	var req updateDedupeKeyRequest
+	if err := c.Bind(&req); err != nil {
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid request")
+	}
+	if err := h.service.UpdateDedupeKey(c.Request().Context(), id, req.DedupeKey); err != nil {
+		if errors.Is(err, service.ErrInvalid) {
+			return Error(c, http.StatusBadRequest, CodeInvalidRequest, "dedupeKey must be auto, guid, url, or title_content")
+		}
+		logger.Error("feed update dedupe key failed", "module", "handler", "action", "update", "resource", "feed", "result", "failed", "feed_id", id, "error", err)
+		return writeServiceError(c, err)
+	}
+	logger.Info("feed dedupe key updated", "module", "handler", "action", "update", "resource", "feed", "result", "ok", "feed_id", id, "dedupe_key", req.DedupeKey)
+	return c.NoContent(http.StatusNoContent)
+}
+
+// UpdateAutoTranslate sets whether the feed's new entries get list translations after refreshes.
+// @Summary Update feed auto translate
+// @Description Set whether list titles and summaries of the feed's new entries are translated in the background after each refresh: inherit follows the translateOnRefresh AI setting, on and off override it.
+// @Tags feeds
+// @Accept json
+// @Param id path int true "Feed ID"
+// @Param request body updateAutoTranslateRequest true "Auto translate update request"
+// @Success 204 "No Content"
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Router /feeds/{id}/auto-translate [patch]
+func (h *FeedHandler) UpdateAutoTranslate(c echo.Context) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid request")
+	}
+	var req updateAutoTranslateRequest
+	if err := c.Bind(&req); err != nil {
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid request")
+	}
+	if err := h.service.UpdateAutoTranslate(c.Request().Context(), id, req.AutoTranslate); err != nil {
+		if errors.Is(err, service.ErrInvalid) {
+			return Error(c, http.StatusBadRequest, CodeInvalidRequest, "autoTranslate must be inherit, on, or off")
+		}
+		logger.Error("feed update auto translate failed", "module", "handler", "action", "update", "resource", "feed", "result", "failed", "feed_id", id, "error", err)
+		return writeServiceError(c, err)
+	}
+	logger.Info("feed auto translate updated", "module", "handler", "action", "update", "resource", "feed", "result", "ok", "feed_id", id, "auto_translate", req.AutoTranslate)
+	return c.NoContent(http.StatusNoContent)
+}
+
+// UpdateUserAgent sets the User-Agent the feed's refreshes send.
+// @Summary Update feed user agent
+// @Description Set a custom User-Agent sent by the feed's refreshes in place of the default one; the fallback User-Agent setting still applies when it is rejected. Null or blank inherits the User-Agent of the feed's folders.
+// @Tags feeds
+// @Accept json
+// @Param id path int true "Feed ID"
+// @Param request body updateUserAgentRequest true "User agent update request"
+// @Success 204 "No Content"
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Router /feeds/{id}/user-agent [patch]
+func (h *FeedHandler) UpdateUserAgent(c echo.Context) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid request")
+	}
+	var req updateUserAgentRequest
+	if err := c.Bind(&req); err != nil {
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid request")
+	}
+	if err := h.service.UpdateUserAgent(c.Request().Context(), id, req.UserAgent); err != nil {
+		if errors.Is(err, service.ErrInvalid) {
+			return Error(c, http.StatusBadRequest, CodeInvalidRequest, "userAgent must be at most 512 characters without control characters")
+		}
+		logger.Error("feed update user agent failed", "module", "handler", "action", "update", "resource", "feed", "result", "failed", "feed_id", id, "error", err)
+		return writeServiceError(c, err)
+	}
+	logger.Info("feed user agent updated", "module", "handler", "action", "update", "resource", "feed", "result", "ok", "feed_id", id)
+	return c.NoContent(http.StatusNoContent)
+}
+
+// UpdateAutoReadability sets whether the feed's entries open in readability mode.
+// @Summary Update feed auto readability
+// @Description Set whether the feed's entries open in readability mode. Null inherits the choice of the feed's folders, then the autoReadability general setting.
+// @Tags feeds
+// @Accept json
+// @Param id path int true "Feed ID"
+// @Param request body updateAutoReadabilityRequest true "Auto readability update request"
+// @Success 204 "No Content"
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Router /feeds/{id}/auto-readability [patch]
+func (h *FeedHandler) UpdateAutoReadability(c echo.Context) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid request")
+	}
+	var req updateAutoReadabilityRequest
+	if err := c.Bind(&req); err != nil {
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid request")
+	}
+	if err := h.service.UpdateAutoReadability(c.Request().Context(), id, req.AutoReadability); err != nil {
+		logger.Error("feed update auto readability failed", "module", "handler", "action", "update", "resource", "feed", "result", "failed", "feed_id", id, "error", err)
+		return writeServiceError(c, err)
+	}
+	logger.Info("feed auto readability updated", "module", "handler", "action", "update", "resource", "feed", "result", "ok", "feed_id", id)
+	return c.NoContent(http.StatusNoContent)
+}
+
+// ListMutedAuthors lists the authors muted in a feed.
+// @Summary List muted authors
+// @Description List the authors whose entries are left out of the feed's unread list.
+// @Tags feeds
+// @Produce json
+// @Param id path int true "Feed ID"
+// @Success 200 {object} mutedAuthorsResponse
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Router /feeds/{id}/muted-authors [get]
+func (h *FeedHandler) ListMutedAuthors(c echo.Context) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid request")
+	}
+	authors, err := h.service.ListMutedAuthors(c.Request().Context(), id)
+	if err != nil {
+		logger.Error("feed muted authors list failed", "module", "handler", "action", "list", "resource", "feed", "result", "failed", "feed_id", id, "error", err)
+		return writeServiceError(c, err)
+	}
+	return c.JSON(http.StatusOK, toMutedAuthorsResponse(authors))
+}
+
+// MuteAuthor mutes an author within a feed.
+// @Summary Mute an author
+// @Description Leave the author's entries out of unread lists and counts, and mark their new entries read as they arrive. Authors match ignoring case and surrounding spaces.
+// @Tags feeds
+// @Accept json
+// @Produce json
+// @Param id path int true "Feed ID"
+// @Param request body mutedAuthorRequest true "Author to mute"
+// @Success 200 {object} mutedAuthorsResponse
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Router /feeds/{id}/muted-authors [put]
+func (h *FeedHandler) MuteAuthor(c echo.Context) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid request")
+	}
+	var req mutedAuthorRequest
+	if err := c.Bind(&req); err != nil {
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid request")
+	}
+	if strings.TrimSpace(req.Author) == "" {
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "author is required")
+	}
+	authors, err := h.service.MuteAuthor(c.Request().Context(), id, req.Author)
+	if err != nil {
+		logger.Error("feed mute author failed", "module", "handler", "action", "update", "resource", "feed", "result", "failed", "feed_id", id, "error", err)
+		return writeServiceError(c, err)
+	}
+	logger.Info("feed author muted", "module", "handler", "action", "update", "resource", "feed", "result", "ok", "feed_id", id)
+	return c.JSON(http.StatusOK, toMutedAuthorsResponse(authors))
+}
+
+// UnmuteAuthor unmutes an author within a feed.
+// @Summary Unmute an author
+// @Description Show the author's unread entries again. Entries marked read while muted stay read.
+// @Tags feeds
+// @Accept json
+// @Produce json
+// @Param id path int true "Feed ID"
+// @Param request body mutedAuthorRequest true "Author to unmute"
+// @Success 200 {object} mutedAuthorsResponse
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Router /feeds/{id}/muted-authors [delete]
+func (h *FeedHandler) UnmuteAuthor(c echo.Context) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid request")
+	}
+	var req mutedAuthorRequest
+	if err := c.Bind(&req); err != nil {
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid request")
+	}
+	if strings.TrimSpace(req.Author) == "" {
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "author is required")
+	}
+	authors, err := h.service.UnmuteAuthor(c.Request().Context(), id, req.Author)
+	if err != nil {
+		logger.Error("feed unmute author failed", "module", "handler", "action", "update", "resource", "feed", "result", "failed", "feed_id", id, "error", err)
+		return writeServiceError(c, err)
+	}
+	logger.Info("feed author unmuted", "module", "handler", "action", "update", "resource", "feed", "result", "ok", "feed_id", id)
+	return c.JSON(http.StatusOK, toMutedAuthorsResponse(authors))
+}
+
+func toMutedAuthorsResponse(authors []string) mutedAuthorsResponse {
+	if authors == nil {
+		authors = []string{}
+	}
+	return mutedAuthorsResponse{Authors: authors}
+}
+
+// Pause mutes a feed for a while without unsubscribing.
+// @Summary Pause a feed
+// @Description Skip the feed in bulk refreshes and unread counts until a time, given as a duration or an RFC3339 timestamp. Refreshing the feed by hand ends the pause.
+// @Tags feeds
+// @Accept json
+// @Param id path int true "Feed ID"
+// @Param request body pauseFeedRequest true "Pause request"
+// @Success 204 "No Content"
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Router /feeds/{id}/pause [post]
+func (h *FeedHandler) Pause(c echo.Context) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid request")
+	}
+	var req pauseFeedRequest
+	if err := c.Bind(&req); err != nil {
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid request")
+	}
+	var until time.Time
+	switch {
+	case req.Duration != "" && req.Until != "":
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "set either duration or until")
+	case req.Duration != "":
+		d, err := time.ParseDuration(req.Duration)
+		if err != nil || d <= 0 {
+			return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid duration")
+		}
+		until = time.Now().Add(d)
+	case req.Until != "":
+		until, err = time.Parse(time.RFC3339, req.Until)
+		if err != nil {
+			return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid until")
+		}
+	default:
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "duration or until is required")
+	}
+	if err := h.service.Pause(c.Request().Context(), id, until); err != nil {
+		if errors.Is(err, service.ErrInvalid) {
+			return Error(c, http.StatusBadRequest, CodeInvalidRequest, "pause must end in the future")
+		}
+		logger.Error("feed pause failed", "module", "handler", "action", "update", "resource", "feed", "result", "failed", "feed_id", id, "error", err)
+		return writeServiceError(c, err)
+	}
+	logger.Info("feed paused", "module", "handler", "action", "update", "resource", "feed", "result", "ok", "feed_id", id)
+	return c.NoContent(http.StatusNoContent)
+}
+
+// Unpause ends a feed's pause.
+// @Summary Unpause a feed
+// @Tags feeds
+// @Param id path int true "Feed ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Router /feeds/{id}/pause [delete]
+func (h *FeedHandler) Unpause(c echo.Context) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid request")
+	}
+	if err := h.service.Unpause(c.Request().Context(), id); err != nil {
+		logger.Error("feed unpause failed", "module", "handler", "action", "update", "resource", "feed", "result", "failed", "feed_id", id, "error", err)
+		return writeServiceError(c, err)
+	}
+	logger.Info("feed unpaused", "module", "handler", "action", "update", "resource", "feed", "result", "ok", "feed_id", id)
+	return c.NoContent(http.StatusNoContent)
+}
+
+// Delete deletes a feed.
+// @Summary Delete a feed
+// @Description Unsubscribe from a feed. It can be restored for 7 days before it and its entries are purged.
+// @Tags feeds
+// @Param id path int true "Feed ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Router /feeds/{id} [delete]
 func (h *FeedHandler) Delete(c echo.Context) error {
 	id, err := parseIDParam(c, "id")
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, errorResponse{Error: "invalid request"})
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid request")
 	}
 	if err := h.service.Delete(c.Request().Context(), id); err != nil {
 		logger.Error("feed delete failed", "module", "handler", "action", "delete", "resource", "feed", "result", "failed", "feed_id", id, "error", err)
@@ -289,6 +1416,129 @@ func (h *FeedHandler) Delete(c echo.Context) error {
 	return c.NoContent(http.StatusNoContent)
 }
 
+// Restore undoes a feed deletion.
+// @Summary Restore a deleted feed
+// @Description Restore a feed deleted within the last 7 days, with its entries
+// @Tags feeds
+// @Produce json
+// @Param id path int true "Feed ID"
+// @Success 200 {object} feedResponse
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Router /feeds/{id}/restore [post]
+func (h *FeedHandler) Restore(c echo.Context) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid request")
+	}
+	feed, err := h.service.Restore(c.Request().Context(), id)
+	if err != nil {
+		logger.Error("feed restore failed", "module", "handler", "action", "restore", "resource", "feed", "result", "failed", "feed_id", id, "error", err)
+		return writeServiceError(c, err)
+	}
+	logger.Info("feed restored", "module", "handler", "action", "restore", "resource", "feed", "result", "ok", "feed_id", id)
+	return c.JSON(http.StatusOK, toFeedResponse(feed))
+}
+
+// ListOverlaps returns feeds that look like duplicate subscriptions.
+// @Summary List overlapping feeds
+// @Description Get the pairs of feeds found after scheduled refreshes to share a site URL, or most of their 50 most recent entries, largest overlap first. Nothing is merged until requested.
+// @Tags feeds
+// @Produce json
+// @Success 200 {array} feedOverlapResponse
+// @Router /feeds/overlaps [get]
+func (h *FeedHandler) ListOverlaps(c echo.Context) error {
+	overlaps, err := h.service.ListOverlaps(c.Request().Context())
+	if err != nil {
+		logger.Error("feed overlap list failed", "module", "handler", "action", "list", "resource", "feed", "result", "failed", "error", err)
+		return writeServiceError(c, err)
+	}
+	response := make([]feedOverlapResponse, 0, len(overlaps))
+	for _, overlap := range overlaps {
+		response = append(response, toFeedOverlapResponse(overlap))
+	}
+	return c.JSON(http.StatusOK, response)
+}
+
+// MergeOverlap merges one feed of an overlapping pair into the other.
+// @Summary Merge overlapping feeds
+// @Description Move the entries of the other feed of the pair onto the kept one, merging the ones both have along with their read and starred state, and move the other feed to the trash.
+// @Tags feeds
+// @Accept json
+// @Produce json
+// @Param id path string true "Overlap ID"
+// @Param request body mergeFeedOverlapRequest false "Feed to keep"
+// @Success 200 {object} feedResponse
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Router /feeds/overlaps/{id}/merge [post]
+func (h *FeedHandler) MergeOverlap(c echo.Context) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid request")
+	}
+	var req mergeFeedOverlapRequest
+	if err := c.Bind(&req); err != nil {
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid request")
+	}
+	var keepFeedID int64
+	if req.KeepFeedID != "" {
+		keepFeedID, err = strconv.ParseInt(req.KeepFeedID, 10, 64)
+		if err != nil {
+			return invalidField(c, "keepFeedId", "invalid feed ID")
+		}
+	}
+
+	feed, err := h.service.MergeOverlap(c.Request().Context(), id, keepFeedID)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalid) {
+			return invalidField(c, "keepFeedId", "feed is not part of the overlap")
+		}
+		logger.Error("feed overlap merge failed", "module", "handler", "action", "delete", "resource", "feed", "result", "failed", "overlap_id", id, "error", err)
+		return writeServiceError(c, err)
+	}
+	logger.Info("feed overlap merged", "module", "handler", "action", "delete", "resource", "feed", "result", "ok", "overlap_id", id, "feed_id", feed.ID)
+	return c.JSON(http.StatusOK, toFeedResponse(feed))
+}
+
+// Reorder sets the manual order of feeds in a folder.
+// @Summary Reorder feeds
+// @Description Place feeds of one folder in the given order. Feeds not listed keep their position.
+// @Tags feeds
+// @Accept json
+// @Param request body reorderFeedsRequest true "Feed IDs in display order"
+// @Success 204 "No Content"
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Router /feeds/reorder [put]
+func (h *FeedHandler) Reorder(c echo.Context) error {
+	var req reorderFeedsRequest
+	if err := c.Bind(&req); err != nil {
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid request")
+	}
+	if len(req.IDs) == 0 {
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "no feed IDs provided")
+	}
+	ids := make([]int64, 0, len(req.IDs))
+	for _, idStr := range req.IDs {
+		id, err := strconv.ParseInt(idStr, 10, 64)
+		if err != nil {
+			return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid feed ID")
+		}
+		ids = append(ids, id)
+	}
+
+	if err := h.service.Reorder(c.Request().Context(), ids); err != nil {
+		if errors.Is(err, service.ErrInvalid) {
+			return Error(c, http.StatusBadRequest, CodeInvalidRequest, "feeds must be unique and in the same folder")
+		}
+		logger.Error("feed reorder failed", "module", "handler", "action", "update", "resource", "feed", "result", "failed", "count", len(ids), "error", err)
+		return writeServiceError(c, err)
+	}
+	logger.Info("feeds reordered", "module", "handler", "action", "update", "resource", "feed", "result", "ok", "count", len(ids))
+	return c.NoContent(http.StatusNoContent)
+}
+
 // DeleteBatch deletes multiple feeds.
 // @Summary Delete multiple feeds
 // @Description Unsubscribe from multiple feeds at once
@@ -301,10 +1551,10 @@ func (h *FeedHandler) Delete(c echo.Context) error {
 func (h *FeedHandler) DeleteBatch(c echo.Context) error {
 	var req deleteFeedsRequest
 	if err := c.Bind(&req); err != nil {
-		return c.JSON(http.StatusBadRequest, errorResponse{Error: "invalid request"})
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid request")
 	}
 	if len(req.IDs) == 0 {
-		return c.JSON(http.StatusBadRequest, errorResponse{Error: "no feed IDs provided"})
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "no feed IDs provided")
 	}
 
 	// Parse all IDs first
@@ -312,7 +1562,7 @@ func (h *FeedHandler) DeleteBatch(c echo.Context) error {
 	for _, idStr := range req.IDs {
 		id, err := strconv.ParseInt(idStr, 10, 64)
 		if err != nil {
-			return c.JSON(http.StatusBadRequest, errorResponse{Error: "invalid feed ID"})
+			return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid feed ID")
 		}
 		ids = append(ids, id)
 	}
@@ -329,13 +1579,16 @@ func (h *FeedHandler) DeleteBatch(c echo.Context) error {
 
 // RefreshStatus returns the current refresh status.
 // @Summary Get refresh status
-// @Description Get the current refresh status including whether a refresh is in progress and when the last refresh completed
+// @Description Get the current refresh status including whether a refresh is in progress and when the last refresh completed. During quiet hours paused is "quiet_hours" and pausedUntil is when scheduled refreshes resume; manual refreshes still run.
 // @Tags feeds
 // @Produce json
 // @Success 200 {object} refreshStatusResponse
 // @Router /feeds/refresh [get]
 func (h *FeedHandler) RefreshStatus(c echo.Context) error {
-	status := h.refreshService.GetRefreshStatus()
+	return c.JSON(http.StatusOK, toRefreshStatusResponse(h.refreshService.GetRefreshStatus()))
+}
+
+func toRefreshStatusResponse(status service.RefreshStatus) refreshStatusResponse {
 	resp := refreshStatusResponse{
 		IsRefreshing: status.IsRefreshing,
 	}
@@ -343,7 +1596,12 @@ func (h *FeedHandler) RefreshStatus(c echo.Context) error {
 		t := status.LastRefreshedAt.UTC().Format(time.RFC3339)
 		resp.LastRefreshedAt = &t
 	}
-	return c.JSON(http.StatusOK, resp)
+	if status.QuietUntil != nil {
+		t := status.QuietUntil.UTC().Format(time.RFC3339)
+		resp.Paused = refreshPausedQuietHours
+		resp.PausedUntil = &t
+	}
+	return resp
 }
 
 // RefreshAll triggers a refresh of all feeds.
@@ -354,10 +1612,10 @@ func (h *FeedHandler) RefreshStatus(c echo.Context) error {
 // @Failure 409 {object} errorResponse "Refresh already in progress"
 // @Router /feeds/refresh [post]
 func (h *FeedHandler) RefreshAll(c echo.Context) error {
-	if err := h.refreshService.RefreshAll(c.Request().Context()); err != nil {
+	if err := h.refreshService.RefreshAll(c.Request().Context(), model.RefreshTriggerManual); err != nil {
 		if errors.Is(err, service.ErrAlreadyRefreshing) {
 			logger.Warn("feed refresh skipped", "module", "handler", "action", "refresh", "resource", "feed", "result", "skipped")
-			return c.JSON(http.StatusConflict, errorResponse{Error: "refresh already in progress"})
+			return writeServiceError(c, err)
 		}
 		logger.Error("feed refresh failed", "module", "handler", "action", "refresh", "resource", "feed", "result", "failed", "error", err)
 		return writeServiceError(c, err)
@@ -366,33 +1624,445 @@ func (h *FeedHandler) RefreshAll(c echo.Context) error {
 	return c.NoContent(http.StatusNoContent)
 }
 
+// RefreshFeed refreshes a single feed.
+// @Summary Refresh a feed
+// @Description Fetch one feed now, clearing its pause. If a refresh of the feed is already in progress, the request waits for it and returns 409 with the refresh status in details.status instead of fetching again.
+// @Tags feeds
+// @Param id path string true "Feed ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 409 {object} errorResponse "Feed refresh already in progress"
+// @Router /feeds/{id}/refresh [post]
+func (h *FeedHandler) RefreshFeed(c echo.Context) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid request")
+	}
+	if err := h.refreshService.RefreshFeed(c.Request().Context(), id); err != nil {
+		if errors.Is(err, service.ErrAlreadyRefreshing) {
+			logger.Warn("feed refresh skipped", "module", "handler", "action", "refresh", "resource", "feed", "result", "skipped", "feed_id", id)
+			return ErrorWithDetails(c, http.StatusConflict, CodeRefreshInProgress, "refresh already in progress", map[string]any{"status": toRefreshStatusResponse(h.refreshService.GetRefreshStatus())})
+		}
+		logger.Error("feed refresh failed", "module", "handler", "action", "refresh", "resource", "feed", "result", "failed", "feed_id", id, "error", err)
+		return writeServiceError(c, err)
+	}
+	logger.Info("feed refreshed", "module", "handler", "action", "refresh", "resource", "feed", "result", "ok", "feed_id", id)
+	return c.NoContent(http.StatusNoContent)
+}
+
+// ListRefreshRuns returns the recent refresh run history.
+// @Summary List refresh runs
+// @Description Get the most recent refresh runs with aggregate counts, newest first
+// @Tags feeds
+// @Produce json
+// @Success 200 {array} refreshRunResponse
+// @Router /refresh/runs [get]
+func (h *FeedHandler) ListRefreshRuns(c echo.Context) error {
+	runs, err := h.refreshService.ListRuns(c.Request().Context())
+	if err != nil {
+		logger.Error("refresh run list failed", "module", "handler", "action", "list", "resource", "refresh_run", "result", "failed", "error", err)
+		return writeServiceError(c, err)
+	}
+	response := make([]refreshRunResponse, 0, len(runs))
+	for _, run := range runs {
+		response = append(response, toRefreshRunResponse(run))
+	}
+	return c.JSON(http.StatusOK, response)
+}
+
+// Probe fetches a feed once and reports what the server received.
+// @Summary Probe a feed
+// @Description Fetch the feed once outside the refresh path and report resolved IPs, timing, status, caching headers, the first 2 KB of the body, Anubis detection and parse results. Conditional GET headers are not sent and Anubis challenges are not solved. Nothing is stored and the feed's error message is left alone.
+// @Tags feeds
+// @Produce json
+// @Param id path string true "Feed ID"
+// @Param userAgent query string false "User agent to use: default or fallback; the one a refresh would start with if empty"
+// @Success 200 {object} feedProbeResponse
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Router /feeds/{id}/probe [post]
+func (h *FeedHandler) Probe(c echo.Context) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid request")
+	}
+	probe, err := h.refreshService.Probe(c.Request().Context(), id, c.QueryParam("userAgent"))
+	if err != nil {
+		logger.Warn("feed probe failed", "module", "handler", "action", "fetch", "resource", "feed", "result", "failed", "feed_id", id, "error", err)
+		return writeServiceError(c, err)
+	}
+	resolvedIPs := probe.ResolvedIPs
+	if resolvedIPs == nil {
+		resolvedIPs = []string{}
+	}
+	headers := probe.Headers
+	if headers == nil {
+		headers = map[string]string{}
+	}
+	return c.JSON(http.StatusOK, feedProbeResponse{
+		URL:            probe.URL,
+		UserAgent:      probe.UserAgent,
+		UserAgentValue: probe.UserAgentValue,
+		SentCookie:     probe.SentCookie,
+		ResolvedIPs:    resolvedIPs,
+		RemoteAddr:     probe.RemoteAddr,
+		Timing: feedProbeTimingResponse{
+			DNSMs:     probe.Timing.DNS.Milliseconds(),
+			ConnectMs: probe.Timing.Connect.Milliseconds(),
+			TLSMs:     probe.Timing.TLS.Milliseconds(),
+			TTFBMs:    probe.Timing.TTFB.Milliseconds(),
+			TotalMs:   probe.Timing.Total.Milliseconds(),
+		},
+		Error:           probe.Error,
+		StatusCode:      probe.StatusCode,
+		Headers:         headers,
+		BodySize:        probe.BodySize,
+		BodyPreview:     probe.BodyPreview,
+		AnubisPage:      probe.AnubisPage,
+		AnubisChallenge: probe.AnubisChallenge,
+		Parsed:          probe.Parsed,
+		ParseError:      probe.ParseError,
+		FeedType:        probe.FeedType,
+		ItemCount:       probe.ItemCount,
+		ItemsNoGUID:     probe.ItemsNoGUID,
+		ItemsNoLink:     probe.ItemsNoLink,
+		Ingest:          toIngestReportResponse(probe.Ingest),
+	})
+}
+
+// GetRefreshRun returns a refresh run with per-feed outcomes.
+// @Summary Get a refresh run
+// @Description Get a refresh run and the outcome of each feed, failed feeds first
+// @Tags feeds
+// @Produce json
+// @Param id path string true "Refresh run ID"
+// @Success 200 {object} refreshRunDetailResponse
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Router /refresh/runs/{id} [get]
+func (h *FeedHandler) GetRefreshRun(c echo.Context) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid request")
+	}
+	run, feeds, err := h.refreshService.GetRun(c.Request().Context(), id)
+	if err != nil {
+		logger.Error("refresh run get failed", "module", "handler", "action", "get", "resource", "refresh_run", "result", "failed", "run_id", id, "error", err)
+		return writeServiceError(c, err)
+	}
+	response := refreshRunDetailResponse{
+		Run:   toRefreshRunResponse(run),
+		Feeds: make([]refreshRunFeedResponse, 0, len(feeds)),
+	}
+	for _, feed := range feeds {
+		status := "ok"
+		if feed.ErrorMessage != nil {
+			status = "failed"
+		}
+		response.Feeds = append(response.Feeds, refreshRunFeedResponse{
+			FeedID:         idToString(feed.FeedID),
+			FeedTitle:      feed.FeedTitle,
+			Status:         status,
+			EntriesNew:     feed.EntriesNew,
+			EntriesUpdated: feed.EntriesUpdated,
+			EntriesEvicted: feed.EntriesEvicted,
+			ErrorMessage:   feed.ErrorMessage,
+		})
+	}
+	return c.JSON(http.StatusOK, response)
+}
+
+// GetFetchLog returns the recent refresh attempts of a feed.
+// @Summary Get a feed's fetch log
+// @Description Get the last 50 refresh attempts of a feed, newest first, with their HTTP status, latency and size. The status is ok while nearly all attempts succeed, erroring after several failures in a row, and unstable when failures come and go.
+// @Tags feeds
+// @Produce json
+// @Param id path int true "Feed ID"
+// @Success 200 {object} feedFetchLogResponse
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Router /feeds/{id}/fetch-log [get]
+func (h *FeedHandler) GetFetchLog(c echo.Context) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid request")
+	}
+	log, err := h.refreshService.GetFetchLog(c.Request().Context(), id)
+	if err != nil {
+		logger.Error("feed fetch log get failed", "module", "handler", "action", "get", "resource", "feed", "result", "failed", "feed_id", id, "error", err)
+		return writeServiceError(c, err)
+	}
+	response := feedFetchLogResponse{
+		Status:       log.Status,
+		SuccessRatio: log.SuccessRatio,
+		Fetches:      make([]feedFetchResponse, 0, len(log.Fetches)),
+	}
+	for _, fetch := range log.Fetches {
+		response.Fetches = append(response.Fetches, feedFetchResponse{
+			FetchedAt:  fetch.FetchedAt.UTC().Format(time.RFC3339),
+			StatusCode: fetch.StatusCode,
+			DurationMs: fetch.DurationMs,
+			Bytes:      fetch.Bytes,
+			Outcome:    fetch.Outcome,
+		})
+	}
+	return c.JSON(http.StatusOK, response)
+}
+
+// GetIngestReport returns what the last refresh of a feed skipped or altered.
+// @Summary Get a feed's ingest report
+// @Description Get the items the last refresh of a feed skipped (no link, duplicate hash, beyond the initial backfill limit) or saved altered (truncated title or author, unparsed date), with counts per reason and up to 50 examples. The report is null when every item was taken as delivered.
+// @Tags feeds
+// @Produce json
+// @Param id path int true "Feed ID"
+// @Success 200 {object} feedIngestReportResponse
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Router /feeds/{id}/ingest-report [get]
+func (h *FeedHandler) GetIngestReport(c echo.Context) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid request")
+	}
+	report, err := h.refreshService.GetIngestReport(c.Request().Context(), id)
+	if err != nil {
+		logger.Error("feed ingest report get failed", "module", "handler", "action", "get", "resource", "feed", "result", "failed", "feed_id", id, "error", err)
+		return writeServiceError(c, err)
+	}
+	return c.JSON(http.StatusOK, feedIngestReportResponse{Report: toIngestReportResponse(report)})
+}
+
+// StartArchiveBackfill crawls the archive pages of a feed in the background.
+// @Summary Backfill a feed's archive
+// @Description Fetch the feed, then follow the archive pages its documents link to with rel="next" (RFC 5005 paged feeds) or next_url (JSON Feed), saving their items like a refresh. Runs in the background; poll GET /feeds/{id}/backfill-archive for progress. Requests to each host keep to its rate limit. A page linking back to an earlier one or a page without items stops the backfill as failed. Regular refreshes never follow these links.
+// @Tags feeds
+// @Accept json
+// @Produce json
+// @Param id path int true "Feed ID"
+// @Param options body archiveBackfillRequest false "Backfill options"
+// @Success 202 {object} archiveBackfillResponse
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 409 {object} errorResponse "A backfill of the feed is already running"
+// @Router /feeds/{id}/backfill-archive [post]
+func (h *FeedHandler) StartArchiveBackfill(c echo.Context) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid request")
+	}
+	var req archiveBackfillRequest
+	if err := c.Bind(&req); err != nil {
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid request")
+	}
+	if req.MaxPages < 0 || req.MaxPages > service.MaxArchivePages {
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "maxPages must be between 1 and 50")
+	}
+
+	job, err := h.refreshService.StartArchiveBackfill(c.Request().Context(), id, service.ArchiveBackfillOptions{MaxPages: req.MaxPages, MarkRead: req.MarkRead})
+	if err != nil {
+		logger.Warn("feed archive backfill start failed", "module", "handler", "action", "fetch", "resource", "feed", "result", "failed", "feed_id", id, "error", err)
+		if errors.Is(err, service.ErrConflict) {
+			return Error(c, http.StatusConflict, CodeConflict, "archive backfill already running")
+		}
+		return writeServiceError(c, err)
+	}
+	return c.JSON(http.StatusAccepted, toArchiveBackfillResponse(&job))
+}
+
+// GetArchiveBackfill returns the progress of a feed's archive backfill.
+// @Summary Get a feed's archive backfill
+// @Description Get the progress of the latest archive backfill of a feed since the server started, null when there was none.
+// @Tags feeds
+// @Produce json
+// @Param id path int true "Feed ID"
+// @Success 200 {object} feedArchiveBackfillResponse
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Router /feeds/{id}/backfill-archive [get]
+func (h *FeedHandler) GetArchiveBackfill(c echo.Context) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid request")
+	}
+	job, err := h.refreshService.GetArchiveBackfill(c.Request().Context(), id)
+	if err != nil {
+		logger.Error("feed archive backfill get failed", "module", "handler", "action", "get", "resource", "feed", "result", "failed", "feed_id", id, "error", err)
+		return writeServiceError(c, err)
+	}
+	return c.JSON(http.StatusOK, feedArchiveBackfillResponse{Backfill: toArchiveBackfillResponse(job)})
+}
+
+func toArchiveBackfillResponse(job *service.ArchiveBackfill) *archiveBackfillResponse {
+	if job == nil {
+		return nil
+	}
+	response := &archiveBackfillResponse{
+		FeedID:     strconv.FormatInt(job.FeedID, 10),
+		Status:     job.Status,
+		MaxPages:   job.MaxPages,
+		Pages:      job.Pages,
+		Items:      job.Items,
+		NewEntries: job.NewEntries,
+		HasMore:    job.HasMore,
+		Error:      job.Error,
+		StartedAt:  job.StartedAt.UTC().Format(time.RFC3339),
+	}
+	if job.FinishedAt != nil {
+		finishedAt := job.FinishedAt.UTC().Format(time.RFC3339)
+		response.FinishedAt = &finishedAt
+	}
+	return response
+}
+
+func toIngestReportResponse(report *service.IngestReport) *ingestReportResponse {
+	if report == nil {
+		return nil
+	}
+	response := &ingestReportResponse{
+		CheckedAt: report.CheckedAt.UTC().Format(time.RFC3339),
+		Items:     report.Items,
+		Saved:     report.Saved,
+		Counts:    report.Counts,
+		Issues:    make([]ingestIssueResponse, 0, len(report.Issues)),
+	}
+	for _, issue := range report.Issues {
+		response.Issues = append(response.Issues, ingestIssueResponse{
+			Reason: issue.Reason,
+			Title:  issue.Title,
+			Link:   issue.Link,
+			GUID:   issue.GUID,
+			Detail: issue.Detail,
+		})
+	}
+	return response
+}
+
+func toFeedOverlapResponse(overlap service.FeedOverlap) feedOverlapResponse {
+	return feedOverlapResponse{
+		ID:            idToString(overlap.ID),
+		Feed:          toFeedResponse(overlap.Feed),
+		OtherFeed:     toFeedResponse(overlap.OtherFeed),
+		SameSite:      overlap.SameSite,
+		SharedEntries: overlap.SharedEntries,
+		OverlapRatio:  overlap.OverlapRatio,
+		DetectedAt:    overlap.DetectedAt.UTC().Format(time.RFC3339),
+	}
+}
+
+func toRefreshRunResponse(run model.RefreshRun) refreshRunResponse {
+	return refreshRunResponse{
+		ID:             idToString(run.ID),
+		Trigger:        run.Trigger,
+		StartedAt:      run.StartedAt.UTC().Format(time.RFC3339),
+		FinishedAt:     run.FinishedAt.UTC().Format(time.RFC3339),
+		FeedsTotal:     run.FeedsTotal,
+		FeedsFailed:    run.FeedsFailed,
+		EntriesNew:     run.EntriesNew,
+		EntriesUpdated: run.EntriesUpdated,
+		EntriesEvicted: run.EntriesEvicted,
+	}
+}
+
 func toFeedResponse(feed model.Feed) feedResponse {
+	var pausedUntil *string
+	if feed.PausedUntil != nil && feed.PausedUntil.After(time.Now()) {
+		formatted := feed.PausedUntil.UTC().Format(time.RFC3339)
+		pausedUntil = &formatted
+	}
+	var lastFetchedAt *string
+	if feed.LastFetchedAt != nil {
+		formatted := feed.LastFetchedAt.UTC().Format(time.RFC3339)
+		lastFetchedAt = &formatted
+	}
+	var suspectCachingAt *string
+	if feed.SuspectCachingAt != nil {
+		formatted := feed.SuspectCachingAt.UTC().Format(time.RFC3339)
+		suspectCachingAt = &formatted
+	}
+	var pollSource string
+	if feed.MinPollSeconds > 0 {
+		pollSource = feed.MinPollSource
+	}
 	return feedResponse{
 		ID:                    idToString(feed.ID),
 		FolderID:              idPtrToString(feed.FolderID),
-		Title:                 feed.Title,
+		Title:                 feed.DisplayTitle(),
+		UpstreamTitle:         feed.Title,
+		CustomTitle:           feed.CustomTitle,
 		URL:                   feed.URL,
 		SiteURL:               feed.SiteURL,
 		Description:           feed.Description,
 		SummaryPromptReminder: feed.SummaryPromptReminder,
+		AssumeTimezone:        feed.AssumeTimezone,
+		DedupeKey:             feed.DedupeKey,
+		AutoTranslate:         feed.AutoTranslate,
+		Language:              feed.Language,
+		PreferredUserAgent:    feed.PreferredUserAgent,
 		IconPath:              feed.IconPath,
 		Type:                  feed.Type,
+		SortOrder:             feed.SortOrder,
 		ETag:                  feed.ETag,
 		LastModified:          feed.LastModified,
 		ErrorMessage:          feed.ErrorMessage,
+		Paused:                pausedUntil != nil,
+		PausedUntil:           pausedUntil,
+		LastFetchedAt:         lastFetchedAt,
+		SuspectCachingAt:      suspectCachingAt,
+		PollIntervalSeconds:   feed.MinPollSeconds,
+		PollIntervalSource:    pollSource,
+		MaxEntries:            feed.MaxEntries,
+		UserAgent:             feed.UserAgent,
+		AutoReadability:       feed.AutoReadability,
 		CreatedAt:             feed.CreatedAt.UTC().Format(time.RFC3339),
 		UpdatedAt:             feed.UpdatedAt.UTC().Format(time.RFC3339),
 	}
 }
 
+func toFeedEffectiveConfigResponse(config service.FeedConfig) feedEffectiveConfigResponse {
+	return feedEffectiveConfigResponse{
+		UserAgent:             config.UserAgent,
+		UserAgentSource:       toFeedConfigSourceResponse(config.UserAgentSource),
+		AutoReadability:       config.AutoReadability,
+		AutoReadabilitySource: toFeedConfigSourceResponse(config.AutoReadabilitySource),
+		AutoTranslate:         config.AutoTranslate,
+		AutoTranslateSource:   toFeedConfigSourceResponse(config.AutoTranslateSource),
+	}
+}
+
+func toFeedConfigSourceResponse(source service.FeedConfigSource) feedConfigSourceResponse {
+	response := feedConfigSourceResponse{Source: source.Kind}
+	if source.Kind == service.FeedConfigFromFolder {
+		folderID := idToString(source.FolderID)
+		folderName := source.FolderName
+		response.FolderID, response.FolderName = &folderID, &folderName
+	}
+	return response
+}
+
+func toFeedStatsResponse(stat model.FeedActivityStats) feedStatsResponse {
+	var lastPublishedAt *string
+	if stat.LastPublishedAt != nil {
+		formatted := stat.LastPublishedAt.UTC().Format(time.RFC3339)
+		lastPublishedAt = &formatted
+	}
+	return feedStatsResponse{
+		FeedID:          idToString(stat.FeedID),
+		EntriesLastWeek: stat.EntriesLastWeek,
+		LastPublishedAt: lastPublishedAt,
+		TotalEntries:    stat.TotalEntries,
+	}
+}
+
 func toFeedPreviewResponse(preview service.FeedPreview) feedPreviewResponse {
 	return feedPreviewResponse{
-		URL:         preview.URL,
-		Title:       preview.Title,
-		Description: preview.Description,
-		SiteURL:     preview.SiteURL,
-		ImageURL:    preview.ImageURL,
-		ItemCount:   preview.ItemCount,
-		LastUpdated: preview.LastUpdated,
+		URL:           preview.URL,
+		Title:         preview.Title,
+		Description:   preview.Description,
+		SiteURL:       preview.SiteURL,
+		ImageURL:      preview.ImageURL,
+		ItemCount:     preview.ItemCount,
+		LastUpdated:   preview.LastUpdated,
+		SuggestedType: preview.SuggestedType,
 	}
 }
@@ -1,10 +1,14 @@
 package handler_test
 
 import (
+	"context"
 	"gist/backend/internal/handler"
 	"net/http"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/mmcdole/gofeed"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 
@@ -19,7 +23,7 @@ func TestFeedHandler_Create_Success(t *testing.T) {
 
 	mockService := mock.NewMockFeedService(ctrl)
 	mockRefreshService := mock.NewMockRefreshService(ctrl)
-	h := handler.NewFeedHandlerHelper(mockService, mockRefreshService)
+	h := handler.NewFeedHandlerHelper(mockService, mockRefreshService, nil, nil)
 
 	e := newTestEcho()
 	reqBody := map[string]interface{}{
@@ -37,7 +41,7 @@ func TestFeedHandler_Create_Success(t *testing.T) {
 	}
 
 	mockService.EXPECT().
-		Add(gomock.Any(), "https://example.com/feed.xml", gomock.Any(), "", "article").
+		Add(gomock.Any(), "https://example.com/feed.xml", gomock.Any(), "", "article", service.InitialBackfill{}).
 		Return(expectedFeed, nil)
 
 	err := h.Create(c)
@@ -49,13 +53,120 @@ func TestFeedHandler_Create_Success(t *testing.T) {
 	require.Equal(t, "Example Feed", resp.Title)
 }
 
+func TestFeedHandler_Create_InitialBackfill(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockFeedService(ctrl)
+	h := handler.NewFeedHandlerHelper(mockService, mock.NewMockRefreshService(ctrl), nil, nil)
+	e := newTestEcho()
+
+	for value, want := range map[any]service.InitialBackfill{
+		"none": {MarkRead: true},
+		5:      {Limit: 5},
+		"5":    {Limit: 5},
+	} {
+		req := newJSONRequest(http.MethodPost, "/feeds", map[string]any{"url": "https://example.com/feed.xml", "initialBackfill": value})
+		c, rec := newTestContext(e, req)
+
+		mockService.EXPECT().
+			Add(gomock.Any(), "https://example.com/feed.xml", gomock.Any(), "", "article", want).
+			Return(model.Feed{ID: 1, URL: "https://example.com/feed.xml"}, nil)
+
+		require.NoError(t, h.Create(c))
+		require.Equal(t, http.StatusCreated, rec.Code)
+	}
+
+	req := newJSONRequest(http.MethodPost, "/feeds", map[string]any{"url": "https://example.com/feed.xml", "initialBackfill": 0})
+	c, rec := newTestContext(e, req)
+	require.NoError(t, h.Create(c))
+	var resp handler.ErrorResponse
+	assertJSONResponse(t, rec, http.StatusBadRequest, &resp)
+	require.Equal(t, handler.CodeInvalidRequest, resp.Code)
+}
+
+func TestFeedHandler_Create_BackfillArchive(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockFeedService(ctrl)
+	mockRefreshService := mock.NewMockRefreshService(ctrl)
+	h := handler.NewFeedHandlerHelper(mockService, mockRefreshService, nil, nil)
+	e := newTestEcho()
+
+	mockService.EXPECT().
+		Add(gomock.Any(), "https://example.com/feed.xml", gomock.Any(), "", "article", service.InitialBackfill{MarkRead: true}).
+		Return(model.Feed{ID: 1, URL: "https://example.com/feed.xml"}, nil)
+	mockRefreshService.EXPECT().
+		StartArchiveBackfill(gomock.Any(), int64(1), service.ArchiveBackfillOptions{MaxPages: 10, MarkRead: true}).
+		Return(service.ArchiveBackfill{}, nil)
+
+	req := newJSONRequest(http.MethodPost, "/feeds", map[string]any{"url": "https://example.com/feed.xml", "initialBackfill": "none", "backfillArchive": true, "archivePages": 10})
+	c, rec := newTestContext(e, req)
+	require.NoError(t, h.Create(c))
+	require.Equal(t, http.StatusCreated, rec.Code)
+
+	req = newJSONRequest(http.MethodPost, "/feeds", map[string]any{"url": "https://example.com/feed.xml", "backfillArchive": true, "archivePages": 51})
+	c, rec = newTestContext(e, req)
+	require.NoError(t, h.Create(c))
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestFeedHandler_Create_AutoTypeMode(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockFeedService(ctrl)
+	mockRefreshService := mock.NewMockRefreshService(ctrl)
+	h := handler.NewFeedHandlerHelper(mockService, mockRefreshService, nil, nil)
+
+	e := newTestEcho()
+	reqBody := map[string]interface{}{
+		"url":      "https://example.com/feed.xml",
+		"type":     "article",
+		"typeMode": "auto",
+	}
+	req := newJSONRequest(http.MethodPost, "/feeds", reqBody)
+	c, rec := newTestContext(e, req)
+
+	mockService.EXPECT().
+		Add(gomock.Any(), "https://example.com/feed.xml", gomock.Any(), "", service.FeedTypeAuto, service.InitialBackfill{}).
+		Return(model.Feed{ID: 1, Type: "picture"}, nil)
+
+	err := h.Create(c)
+	require.NoError(t, err)
+
+	var resp handler.FeedResponse
+	assertJSONResponse(t, rec, http.StatusCreated, &resp)
+	require.Equal(t, "picture", resp.Type)
+}
+
+func TestFeedHandler_Create_InvalidTypeMode(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	h := handler.NewFeedHandlerHelper(mock.NewMockFeedService(ctrl), mock.NewMockRefreshService(ctrl), nil, nil)
+
+	e := newTestEcho()
+	reqBody := map[string]interface{}{
+		"url":      "https://example.com/feed.xml",
+		"typeMode": "guess",
+	}
+	req := newJSONRequest(http.MethodPost, "/feeds", reqBody)
+	c, rec := newTestContext(e, req)
+
+	err := h.Create(c)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
 func TestFeedHandler_Create_Conflict(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockService := mock.NewMockFeedService(ctrl)
 	mockRefreshService := mock.NewMockRefreshService(ctrl)
-	h := handler.NewFeedHandlerHelper(mockService, mockRefreshService)
+	h := handler.NewFeedHandlerHelper(mockService, mockRefreshService, nil, nil)
 
 	e := newTestEcho()
 	reqBody := map[string]interface{}{
@@ -69,13 +180,16 @@ func TestFeedHandler_Create_Conflict(t *testing.T) {
 	}
 
 	mockService.EXPECT().
-		Add(gomock.Any(), "https://example.com/feed.xml", gomock.Any(), "", "article").
+		Add(gomock.Any(), "https://example.com/feed.xml", gomock.Any(), "", "article", service.InitialBackfill{}).
 		Return(model.Feed{}, conflictErr)
 
 	err := h.Create(c)
 	require.NoError(t, err)
 
-	require.Equal(t, http.StatusConflict, rec.Code)
+	var resp handler.ErrorResponse
+	assertJSONResponse(t, rec, http.StatusConflict, &resp)
+	require.Equal(t, "feed_conflict", resp.Code)
+	require.Equal(t, "999", resp.Details["existingFeedId"])
 }
 
 func TestFeedHandler_Create_InvalidRequest(t *testing.T) {
@@ -84,7 +198,7 @@ func TestFeedHandler_Create_InvalidRequest(t *testing.T) {
 
 	mockService := mock.NewMockFeedService(ctrl)
 	mockRefreshService := mock.NewMockRefreshService(ctrl)
-	h := handler.NewFeedHandlerHelper(mockService, mockRefreshService)
+	h := handler.NewFeedHandlerHelper(mockService, mockRefreshService, nil, nil)
 
 	e := newTestEcho()
 	req := newJSONRequest(http.MethodPost, "/feeds", map[string]interface{}{})
@@ -92,7 +206,7 @@ func TestFeedHandler_Create_InvalidRequest(t *testing.T) {
 
 	// Empty URL will be passed to service, which should return an error
 	mockService.EXPECT().
-		Add(gomock.Any(), "", gomock.Any(), "", "article").
+		Add(gomock.Any(), "", gomock.Any(), "", "article", service.InitialBackfill{}).
 		Return(model.Feed{}, service.ErrInvalid)
 
 	err := h.Create(c)
@@ -107,7 +221,7 @@ func TestFeedHandler_List_Success(t *testing.T) {
 
 	mockService := mock.NewMockFeedService(ctrl)
 	mockRefreshService := mock.NewMockRefreshService(ctrl)
-	h := handler.NewFeedHandlerHelper(mockService, mockRefreshService)
+	h := handler.NewFeedHandlerHelper(mockService, mockRefreshService, nil, nil)
 
 	e := newTestEcho()
 	req := newJSONRequest(http.MethodGet, "/feeds", nil)
@@ -121,6 +235,27 @@ func TestFeedHandler_List_Success(t *testing.T) {
 	mockService.EXPECT().
 		List(gomock.Any(), gomock.Any()).
 		Return(feeds, nil)
+	mockRefreshService.EXPECT().
+		PollIntervals(gomock.Any(), feeds).
+		Return(map[int64]service.PollInterval{2: {Interval: time.Hour, Source: service.PollSourceHost}})
+	userAgent := "Mozilla/5.0 (Feed)"
+	readability := true
+	mockService.EXPECT().
+		EffectiveConfigs(gomock.Any(), feeds).
+		Return(map[int64]service.FeedConfig{
+			1: {
+				UserAgentSource:       service.FeedConfigSource{Kind: service.FeedConfigFromDefault},
+				AutoReadabilitySource: service.FeedConfigSource{Kind: service.FeedConfigFromDefault},
+				AutoTranslateSource:   service.FeedConfigSource{Kind: service.FeedConfigFromDefault},
+			},
+			2: {
+				UserAgent:             &userAgent,
+				UserAgentSource:       service.FeedConfigSource{Kind: service.FeedConfigFromFeed},
+				AutoReadability:       &readability,
+				AutoReadabilitySource: service.FeedConfigSource{Kind: service.FeedConfigFromFolder, FolderID: 7, FolderName: "Japanese blogs"},
+				AutoTranslateSource:   service.FeedConfigSource{Kind: service.FeedConfigFromDefault},
+			},
+		}, nil)
 
 	err := h.List(c)
 	require.NoError(t, err)
@@ -130,6 +265,23 @@ func TestFeedHandler_List_Success(t *testing.T) {
 	require.Len(t, resp, 2)
 	require.Equal(t, "1", resp[0].ID)
 	require.Equal(t, "Feed 1", resp[0].Title)
+	require.Zero(t, resp[0].PollIntervalSeconds)
+	require.Empty(t, resp[0].PollIntervalSource)
+	require.Equal(t, 3600, resp[1].PollIntervalSeconds)
+	require.Equal(t, service.PollSourceHost, resp[1].PollIntervalSource)
+
+	require.NotNil(t, resp[0].Effective)
+	require.Nil(t, resp[0].Effective.UserAgent)
+	require.Equal(t, "default", resp[0].Effective.UserAgentSource.Source)
+	require.Nil(t, resp[0].Effective.UserAgentSource.FolderID)
+	require.NotNil(t, resp[1].Effective)
+	require.Equal(t, userAgent, *resp[1].Effective.UserAgent)
+	require.Equal(t, "feed", resp[1].Effective.UserAgentSource.Source)
+	require.True(t, *resp[1].Effective.AutoReadability)
+	require.Equal(t, "folder", resp[1].Effective.AutoReadabilitySource.Source)
+	require.Equal(t, "7", *resp[1].Effective.AutoReadabilitySource.FolderID)
+	require.Equal(t, "Japanese blogs", *resp[1].Effective.AutoReadabilitySource.FolderName)
+	require.Nil(t, resp[1].Effective.AutoTranslate)
 }
 
 func TestFeedHandler_Update_Success(t *testing.T) {
@@ -138,7 +290,7 @@ func TestFeedHandler_Update_Success(t *testing.T) {
 
 	mockService := mock.NewMockFeedService(ctrl)
 	mockRefreshService := mock.NewMockRefreshService(ctrl)
-	h := handler.NewFeedHandlerHelper(mockService, mockRefreshService)
+	h := handler.NewFeedHandlerHelper(mockService, mockRefreshService, nil, nil)
 
 	e := newTestEcho()
 	reqBody := map[string]interface{}{
@@ -157,7 +309,7 @@ func TestFeedHandler_Update_Success(t *testing.T) {
 	}
 
 	mockService.EXPECT().
-		Update(gomock.Any(), int64(123), "Updated Title", gomock.Any(), gomock.Any()).
+		Update(gomock.Any(), int64(123), "Updated Title", gomock.Any(), gomock.Any(), gomock.Nil()).
 		Return(updatedFeed, nil)
 
 	err := h.Update(c)
@@ -171,13 +323,207 @@ func TestFeedHandler_Update_Success(t *testing.T) {
 	require.Equal(t, "突出结论", *resp.SummaryPromptReminder)
 }
 
+func TestFeedHandler_Update_MaxEntries(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockFeedService(ctrl)
+	mockRefreshService := mock.NewMockRefreshService(ctrl)
+	h := handler.NewFeedHandlerHelper(mockService, mockRefreshService, nil, nil)
+
+	e := newTestEcho()
+	req := newJSONRequest(http.MethodPut, "/feeds/123", map[string]interface{}{
+		"title":      "Hashtag",
+		"maxEntries": 500,
+	})
+	c, rec := newTestContext(e, req)
+	setPathParams(c, map[string]string{"id": "123"})
+
+	maxEntries := 500
+	mockService.EXPECT().
+		Update(gomock.Any(), int64(123), "Hashtag", gomock.Nil(), gomock.Nil(), &maxEntries).
+		Return(model.Feed{ID: 123, Title: "Hashtag", MaxEntries: 500}, nil)
+
+	require.NoError(t, h.Update(c))
+	var resp handler.FeedResponse
+	assertJSONResponse(t, rec, http.StatusOK, &resp)
+	require.Equal(t, 500, resp.MaxEntries)
+
+	req = newJSONRequest(http.MethodPut, "/feeds/123", map[string]interface{}{
+		"title":      "Hashtag",
+		"maxEntries": -1,
+	})
+	c, rec = newTestContext(e, req)
+	setPathParams(c, map[string]string{"id": "123"})
+	require.NoError(t, h.Update(c))
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestFeedHandler_ListOverlaps(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockFeedService(ctrl)
+	h := handler.NewFeedHandlerHelper(mockService, mock.NewMockRefreshService(ctrl), nil, nil)
+	e := newTestEcho()
+
+	detectedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	mockService.EXPECT().ListOverlaps(gomock.Any()).Return([]service.FeedOverlap{{
+		FeedOverlap: model.FeedOverlap{ID: 9, FeedID: 1, OtherFeedID: 2, SameSite: true, SharedEntries: 40, OverlapRatio: 0.8, DetectedAt: detectedAt},
+		Feed:        model.Feed{ID: 1, Title: "Atom", Type: "article"},
+		OtherFeed:   model.Feed{ID: 2, Title: "RSS", Type: "article"},
+	}}, nil)
+
+	c, rec := newTestContext(e, newJSONRequest(http.MethodGet, "/feeds/overlaps", nil))
+	require.NoError(t, h.ListOverlaps(c))
+	var resp []handler.FeedOverlapResponse
+	assertJSONResponse(t, rec, http.StatusOK, &resp)
+	require.Len(t, resp, 1)
+	require.Equal(t, "9", resp[0].ID)
+	require.Equal(t, "1", resp[0].Feed.ID)
+	require.Equal(t, "RSS", resp[0].OtherFeed.Title)
+	require.True(t, resp[0].SameSite)
+	require.Equal(t, 40, resp[0].SharedEntries)
+	require.Equal(t, "2026-01-02T03:04:05Z", resp[0].DetectedAt)
+}
+
+func TestFeedHandler_MergeOverlap(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockFeedService(ctrl)
+	h := handler.NewFeedHandlerHelper(mockService, mock.NewMockRefreshService(ctrl), nil, nil)
+	e := newTestEcho()
+
+	req := newJSONRequest(http.MethodPost, "/feeds/overlaps/9/merge", map[string]interface{}{"keepFeedId": "2"})
+	c, rec := newTestContext(e, req)
+	setPathParams(c, map[string]string{"id": "9"})
+	mockService.EXPECT().MergeOverlap(gomock.Any(), int64(9), int64(2)).Return(model.Feed{ID: 2, Title: "RSS", Type: "article"}, nil)
+	require.NoError(t, h.MergeOverlap(c))
+	var resp handler.FeedResponse
+	assertJSONResponse(t, rec, http.StatusOK, &resp)
+	require.Equal(t, "2", resp.ID)
+
+	// Without a body the service picks the feed to keep
+	c, rec = newTestContext(e, newJSONRequest(http.MethodPost, "/feeds/overlaps/9/merge", nil))
+	setPathParams(c, map[string]string{"id": "9"})
+	mockService.EXPECT().MergeOverlap(gomock.Any(), int64(9), int64(0)).Return(model.Feed{ID: 1, Title: "Atom", Type: "article"}, nil)
+	require.NoError(t, h.MergeOverlap(c))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	req = newJSONRequest(http.MethodPost, "/feeds/overlaps/9/merge", map[string]interface{}{"keepFeedId": "3"})
+	c, rec = newTestContext(e, req)
+	setPathParams(c, map[string]string{"id": "9"})
+	mockService.EXPECT().MergeOverlap(gomock.Any(), int64(9), int64(3)).Return(model.Feed{}, service.ErrInvalid)
+	require.NoError(t, h.MergeOverlap(c))
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+
+	req = newJSONRequest(http.MethodPost, "/feeds/overlaps/9/merge", map[string]interface{}{"keepFeedId": "rss"})
+	c, rec = newTestContext(e, req)
+	setPathParams(c, map[string]string{"id": "9"})
+	require.NoError(t, h.MergeOverlap(c))
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+
+	c, rec = newTestContext(e, newJSONRequest(http.MethodPost, "/feeds/overlaps/8/merge", nil))
+	setPathParams(c, map[string]string{"id": "8"})
+	mockService.EXPECT().MergeOverlap(gomock.Any(), int64(8), int64(0)).Return(model.Feed{}, service.ErrNotFound)
+	require.NoError(t, h.MergeOverlap(c))
+	require.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestFeedHandler_Patch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockFeedService(ctrl)
+	h := handler.NewFeedHandlerHelper(mockService, mock.NewMockRefreshService(ctrl), nil, nil)
+	e := newTestEcho()
+
+	// Left out fields stay nil; null ones clear
+	req := newJSONRequest(http.MethodPatch, "/feeds/123", map[string]interface{}{
+		"type":            "picture",
+		"folderId":        nil,
+		"userAgent":       nil,
+		"autoReadability": true,
+		"pausedUntil":     "2030-01-01T00:00:00Z",
+	})
+	c, rec := newTestContext(e, req)
+	setPathParams(c, map[string]string{"id": "123"})
+	mockService.EXPECT().Patch(gomock.Any(), int64(123), gomock.Any()).DoAndReturn(func(_ context.Context, _ int64, patch service.FeedPatch) (model.Feed, error) {
+		require.Nil(t, patch.Title)
+		require.Nil(t, patch.FolderID)
+		require.True(t, patch.ClearFolder)
+		require.Equal(t, "picture", *patch.Type)
+		require.Equal(t, "", *patch.UserAgent)
+		require.Nil(t, patch.AssumeTimezone)
+		require.True(t, *patch.AutoReadability)
+		require.False(t, patch.InheritAutoReadability)
+		require.True(t, time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC).Equal(*patch.PausedUntil))
+		return model.Feed{ID: 123, Title: "Feed", Type: "picture"}, nil
+	})
+	require.NoError(t, h.Patch(c))
+	var resp handler.FeedResponse
+	assertJSONResponse(t, rec, http.StatusOK, &resp)
+	require.Equal(t, "picture", resp.Type)
+
+	req = newJSONRequest(http.MethodPatch, "/feeds/123", map[string]interface{}{"folderId": "7", "autoReadability": nil, "pausedUntil": nil})
+	c, rec = newTestContext(e, req)
+	setPathParams(c, map[string]string{"id": "123"})
+	folderID := int64(7)
+	mockService.EXPECT().Patch(gomock.Any(), int64(123), service.FeedPatch{FolderID: &folderID, InheritAutoReadability: true, Unpause: true}).Return(model.Feed{}, service.ErrInvalid)
+	require.NoError(t, h.Patch(c))
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+
+	for _, body := range []map[string]interface{}{
+		{"folderId": "music"},
+		{"pausedUntil": "tomorrow"},
+		{"autoReadability": "yes"},
+	} {
+		req = newJSONRequest(http.MethodPatch, "/feeds/123", body)
+		c, rec = newTestContext(e, req)
+		setPathParams(c, map[string]string{"id": "123"})
+		require.NoError(t, h.Patch(c))
+		require.Equal(t, http.StatusBadRequest, rec.Code, "%v", body)
+	}
+}
+
+func TestFeedHandler_MuteAuthor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockFeedService(ctrl)
+	mockRefreshService := mock.NewMockRefreshService(ctrl)
+	h := handler.NewFeedHandlerHelper(mockService, mockRefreshService, nil, nil)
+	e := newTestEcho()
+
+	mockService.EXPECT().MuteAuthor(gomock.Any(), int64(123), "Jane Doe").Return([]string{"Jane Doe"}, nil)
+	c, rec := newTestContext(e, newJSONRequest(http.MethodPut, "/feeds/123/muted-authors", map[string]string{"author": "Jane Doe"}))
+	setPathParams(c, map[string]string{"id": "123"})
+	require.NoError(t, h.MuteAuthor(c))
+	var resp map[string][]string
+	assertJSONResponse(t, rec, http.StatusOK, &resp)
+	require.Equal(t, []string{"Jane Doe"}, resp["authors"])
+
+	mockService.EXPECT().UnmuteAuthor(gomock.Any(), int64(123), "Jane Doe").Return(nil, nil)
+	c, rec = newTestContext(e, newJSONRequest(http.MethodDelete, "/feeds/123/muted-authors", map[string]string{"author": "Jane Doe"}))
+	setPathParams(c, map[string]string{"id": "123"})
+	require.NoError(t, h.UnmuteAuthor(c))
+	assertJSONResponse(t, rec, http.StatusOK, &resp)
+	require.Equal(t, []string{}, resp["authors"])
+
+	c, rec = newTestContext(e, newJSONRequest(http.MethodPut, "/feeds/123/muted-authors", map[string]string{"author": "  "}))
+	setPathParams(c, map[string]string{"id": "123"})
+	require.NoError(t, h.MuteAuthor(c))
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
 func TestFeedHandler_Update_TitleRequired(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockService := mock.NewMockFeedService(ctrl)
 	mockRefreshService := mock.NewMockRefreshService(ctrl)
-	h := handler.NewFeedHandlerHelper(mockService, mockRefreshService)
+	h := handler.NewFeedHandlerHelper(mockService, mockRefreshService, nil, nil)
 
 	e := newTestEcho()
 	req := newJSONRequest(http.MethodPut, "/feeds/123", map[string]interface{}{
@@ -198,7 +544,7 @@ func TestFeedHandler_Delete_Success(t *testing.T) {
 
 	mockService := mock.NewMockFeedService(ctrl)
 	mockRefreshService := mock.NewMockRefreshService(ctrl)
-	h := handler.NewFeedHandlerHelper(mockService, mockRefreshService)
+	h := handler.NewFeedHandlerHelper(mockService, mockRefreshService, nil, nil)
 
 	e := newTestEcho()
 	req := newJSONRequest(http.MethodDelete, "/feeds/123", nil)
@@ -221,7 +567,7 @@ func TestFeedHandler_UpdateType_Success(t *testing.T) {
 
 	mockService := mock.NewMockFeedService(ctrl)
 	mockRefreshService := mock.NewMockRefreshService(ctrl)
-	h := handler.NewFeedHandlerHelper(mockService, mockRefreshService)
+	h := handler.NewFeedHandlerHelper(mockService, mockRefreshService, nil, nil)
 
 	e := newTestEcho()
 	reqBody := map[string]interface{}{
@@ -241,79 +587,1091 @@ func TestFeedHandler_UpdateType_Success(t *testing.T) {
 	require.Equal(t, http.StatusNoContent, rec.Code)
 }
 
-func TestFeedHandler_DeleteBatch_Success(t *testing.T) {
+func TestFeedHandler_UpdateTimezone(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockService := mock.NewMockFeedService(ctrl)
-	mockRefreshService := mock.NewMockRefreshService(ctrl)
-	h := handler.NewFeedHandlerHelper(mockService, mockRefreshService)
-
+	h := handler.NewFeedHandlerHelper(mockService, mock.NewMockRefreshService(ctrl), nil, nil)
 	e := newTestEcho()
-	reqBody := map[string]interface{}{
-		"ids": []string{"1", "2", "3"},
-	}
-	req := newJSONRequest(http.MethodDelete, "/feeds", reqBody)
+
+	req := newJSONRequest(http.MethodPatch, "/feeds/123/timezone", map[string]interface{}{"assumeTimezone": "Asia/Shanghai"})
 	c, rec := newTestContext(e, req)
+	setPathParams(c, map[string]string{"id": "123"})
+	tz := "Asia/Shanghai"
+	mockService.EXPECT().UpdateAssumeTimezone(gomock.Any(), int64(123), &tz).Return(nil)
+	require.NoError(t, h.UpdateTimezone(c))
+	require.Equal(t, http.StatusNoContent, rec.Code)
 
-	mockService.EXPECT().
-		DeleteBatch(gomock.Any(), []int64{1, 2, 3}).
-		Return(nil)
+	req = newJSONRequest(http.MethodPatch, "/feeds/123/timezone", map[string]interface{}{"assumeTimezone": "Mars/Olympus"})
+	c, rec = newTestContext(e, req)
+	setPathParams(c, map[string]string{"id": "123"})
+	mockService.EXPECT().UpdateAssumeTimezone(gomock.Any(), int64(123), gomock.Any()).Return(service.ErrInvalid)
+	require.NoError(t, h.UpdateTimezone(c))
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+}
 
-	err := h.DeleteBatch(c)
-	require.NoError(t, err)
+func TestFeedHandler_Reorder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockFeedService(ctrl)
+	h := handler.NewFeedHandlerHelper(mockService, mock.NewMockRefreshService(ctrl), nil, nil)
+	e := newTestEcho()
 
+	req := newJSONRequest(http.MethodPut, "/feeds/reorder", map[string]interface{}{"ids": []string{"3", "1", "2"}})
+	c, rec := newTestContext(e, req)
+	mockService.EXPECT().Reorder(gomock.Any(), []int64{3, 1, 2}).Return(nil)
+	require.NoError(t, h.Reorder(c))
 	require.Equal(t, http.StatusNoContent, rec.Code)
+
+	req = newJSONRequest(http.MethodPut, "/feeds/reorder", map[string]interface{}{"ids": []string{}})
+	c, rec = newTestContext(e, req)
+	require.NoError(t, h.Reorder(c))
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+
+	req = newJSONRequest(http.MethodPut, "/feeds/reorder", map[string]interface{}{"ids": []string{"1", "9"}})
+	c, rec = newTestContext(e, req)
+	mockService.EXPECT().Reorder(gomock.Any(), []int64{1, 9}).Return(service.ErrNotFound)
+	require.NoError(t, h.Reorder(c))
+	require.Equal(t, http.StatusNotFound, rec.Code)
 }
 
-func TestFeedHandler_RefreshAll_Success(t *testing.T) {
+func TestFeedHandler_UpdateDedupeKey(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockService := mock.NewMockFeedService(ctrl)
-	mockRefreshService := mock.NewMockRefreshService(ctrl)
-	h := handler.NewFeedHandlerHelper(mockService, mockRefreshService)
+	h := handler.NewFeedHandlerHelper(mockService, mock.NewMockRefreshService(ctrl), nil, nil)
+	e := newTestEcho()
+
+	req := newJSONRequest(http.MethodPatch, "/feeds/123/dedupe-key", map[string]interface{}{"dedupeKey": "url"})
+	c, rec := newTestContext(e, req)
+	setPathParams(c, map[string]string{"id": "123"})
+	mockService.EXPECT().UpdateDedupeKey(gomock.Any(), int64(123), "url").Return(nil)
+	require.NoError(t, h.UpdateDedupeKey(c))
+	require.Equal(t, http.StatusNoContent, rec.Code)
+
+	req = newJSONRequest(http.MethodPatch, "/feeds/123/dedupe-key", map[string]interface{}{"dedupeKey": "link"})
+	c, rec = newTestContext(e, req)
+	setPathParams(c, map[string]string{"id": "123"})
+	mockService.EXPECT().UpdateDedupeKey(gomock.Any(), int64(123), "link").Return(service.ErrInvalid)
+	require.NoError(t, h.UpdateDedupeKey(c))
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestFeedHandler_UpdateAutoTranslate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
 
+	mockService := mock.NewMockFeedService(ctrl)
+	h := handler.NewFeedHandlerHelper(mockService, mock.NewMockRefreshService(ctrl), nil, nil)
 	e := newTestEcho()
-	req := newJSONRequest(http.MethodPost, "/feeds/refresh", nil)
+
+	req := newJSONRequest(http.MethodPatch, "/feeds/123/auto-translate", map[string]interface{}{"autoTranslate": "on"})
 	c, rec := newTestContext(e, req)
+	setPathParams(c, map[string]string{"id": "123"})
+	mockService.EXPECT().UpdateAutoTranslate(gomock.Any(), int64(123), "on").Return(nil)
+	require.NoError(t, h.UpdateAutoTranslate(c))
+	require.Equal(t, http.StatusNoContent, rec.Code)
 
-	mockRefreshService.EXPECT().
-		RefreshAll(gomock.Any()).
-		Return(nil)
+	req = newJSONRequest(http.MethodPatch, "/feeds/123/auto-translate", map[string]interface{}{"autoTranslate": "always"})
+	c, rec = newTestContext(e, req)
+	setPathParams(c, map[string]string{"id": "123"})
+	mockService.EXPECT().UpdateAutoTranslate(gomock.Any(), int64(123), "always").Return(service.ErrInvalid)
+	require.NoError(t, h.UpdateAutoTranslate(c))
+	require.Equal(t, http.StatusBadRequest, rec.Code)
 
-	err := h.RefreshAll(c)
-	require.NoError(t, err)
+	req = newJSONRequest(http.MethodPatch, "/feeds/123/auto-translate", map[string]interface{}{"autoTranslate": "off"})
+	c, rec = newTestContext(e, req)
+	setPathParams(c, map[string]string{"id": "123"})
+	mockService.EXPECT().UpdateAutoTranslate(gomock.Any(), int64(123), "off").Return(service.ErrNotFound)
+	require.NoError(t, h.UpdateAutoTranslate(c))
+	require.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestFeedHandler_UpdateUserAgent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockFeedService(ctrl)
+	h := handler.NewFeedHandlerHelper(mockService, mock.NewMockRefreshService(ctrl), nil, nil)
+	e := newTestEcho()
+
+	req := newJSONRequest(http.MethodPatch, "/feeds/123/user-agent", map[string]interface{}{"userAgent": "Mozilla/5.0 (Feed)"})
+	c, rec := newTestContext(e, req)
+	setPathParams(c, map[string]string{"id": "123"})
+	mockService.EXPECT().UpdateUserAgent(gomock.Any(), int64(123), gomock.Any()).DoAndReturn(func(_ context.Context, _ int64, userAgent *string) error {
+		require.NotNil(t, userAgent)
+		require.Equal(t, "Mozilla/5.0 (Feed)", *userAgent)
+		return nil
+	})
+	require.NoError(t, h.UpdateUserAgent(c))
+	require.Equal(t, http.StatusNoContent, rec.Code)
 
+	// null goes back to inheriting
+	req = newJSONRequest(http.MethodPatch, "/feeds/123/user-agent", map[string]interface{}{"userAgent": nil})
+	c, rec = newTestContext(e, req)
+	setPathParams(c, map[string]string{"id": "123"})
+	mockService.EXPECT().UpdateUserAgent(gomock.Any(), int64(123), (*string)(nil)).Return(nil)
+	require.NoError(t, h.UpdateUserAgent(c))
 	require.Equal(t, http.StatusNoContent, rec.Code)
+
+	req = newJSONRequest(http.MethodPatch, "/feeds/123/user-agent", map[string]interface{}{"userAgent": "bad\r\nX-Injected: 1"})
+	c, rec = newTestContext(e, req)
+	setPathParams(c, map[string]string{"id": "123"})
+	mockService.EXPECT().UpdateUserAgent(gomock.Any(), int64(123), gomock.Any()).Return(service.ErrInvalid)
+	require.NoError(t, h.UpdateUserAgent(c))
+	require.Equal(t, http.StatusBadRequest, rec.Code)
 }
 
-func TestFeedHandler_Preview_Success(t *testing.T) {
+func TestFeedHandler_UpdateAutoReadability(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockService := mock.NewMockFeedService(ctrl)
-	mockRefreshService := mock.NewMockRefreshService(ctrl)
-	h := handler.NewFeedHandlerHelper(mockService, mockRefreshService)
+	h := handler.NewFeedHandlerHelper(mockService, mock.NewMockRefreshService(ctrl), nil, nil)
+	e := newTestEcho()
+
+	req := newJSONRequest(http.MethodPatch, "/feeds/123/auto-readability", map[string]interface{}{"autoReadability": false})
+	c, rec := newTestContext(e, req)
+	setPathParams(c, map[string]string{"id": "123"})
+	mockService.EXPECT().UpdateAutoReadability(gomock.Any(), int64(123), gomock.Any()).DoAndReturn(func(_ context.Context, _ int64, enabled *bool) error {
+		require.NotNil(t, enabled)
+		require.False(t, *enabled)
+		return nil
+	})
+	require.NoError(t, h.UpdateAutoReadability(c))
+	require.Equal(t, http.StatusNoContent, rec.Code)
+
+	req = newJSONRequest(http.MethodPatch, "/feeds/123/auto-readability", map[string]interface{}{"autoReadability": nil})
+	c, rec = newTestContext(e, req)
+	setPathParams(c, map[string]string{"id": "123"})
+	mockService.EXPECT().UpdateAutoReadability(gomock.Any(), int64(123), (*bool)(nil)).Return(service.ErrNotFound)
+	require.NoError(t, h.UpdateAutoReadability(c))
+	require.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestFeedHandler_Pause(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
 
+	mockService := mock.NewMockFeedService(ctrl)
+	h := handler.NewFeedHandlerHelper(mockService, mock.NewMockRefreshService(ctrl), nil, nil)
 	e := newTestEcho()
-	req := newJSONRequest(http.MethodGet, "/feeds/preview?url=https://example.com/feed.xml", nil)
+
+	req := newJSONRequest(http.MethodPost, "/feeds/123/pause", map[string]interface{}{"duration": "24h"})
 	c, rec := newTestContext(e, req)
+	setPathParams(c, map[string]string{"id": "123"})
+	mockService.EXPECT().Pause(gomock.Any(), int64(123), gomock.Any()).DoAndReturn(func(_ context.Context, _ int64, until time.Time) error {
+		require.WithinDuration(t, time.Now().Add(24*time.Hour), until, time.Minute)
+		return nil
+	})
+	require.NoError(t, h.Pause(c))
+	require.Equal(t, http.StatusNoContent, rec.Code)
 
-	siteURL := "https://example.com"
-	preview := service.FeedPreview{
-		Title:   "Example Feed",
-		SiteURL: &siteURL,
-	}
+	until := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	req = newJSONRequest(http.MethodPost, "/feeds/123/pause", map[string]interface{}{"until": "2030-01-01T00:00:00Z"})
+	c, rec = newTestContext(e, req)
+	setPathParams(c, map[string]string{"id": "123"})
+	mockService.EXPECT().Pause(gomock.Any(), int64(123), until).Return(nil)
+	require.NoError(t, h.Pause(c))
+	require.Equal(t, http.StatusNoContent, rec.Code)
 
-	mockService.EXPECT().
-		Preview(gomock.Any(), "https://example.com/feed.xml").
-		Return(preview, nil)
+	for _, body := range []map[string]interface{}{
+		{},
+		{"duration": "soon"},
+		{"duration": "-1h"},
+		{"until": "tomorrow"},
+		{"duration": "1h", "until": "2030-01-01T00:00:00Z"},
+	} {
+		req = newJSONRequest(http.MethodPost, "/feeds/123/pause", body)
+		c, rec = newTestContext(e, req)
+		setPathParams(c, map[string]string{"id": "123"})
+		require.NoError(t, h.Pause(c))
+		require.Equal(t, http.StatusBadRequest, rec.Code, "%v", body)
+	}
+}
 
-	err := h.Preview(c)
-	require.NoError(t, err)
+func TestFeedHandler_Unpause(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockFeedService(ctrl)
+	h := handler.NewFeedHandlerHelper(mockService, mock.NewMockRefreshService(ctrl), nil, nil)
+	e := newTestEcho()
+
+	req := newJSONRequest(http.MethodDelete, "/feeds/123/pause", nil)
+	c, rec := newTestContext(e, req)
+	setPathParams(c, map[string]string{"id": "123"})
+	mockService.EXPECT().Unpause(gomock.Any(), int64(123)).Return(nil)
+	require.NoError(t, h.Unpause(c))
+	require.Equal(t, http.StatusNoContent, rec.Code)
+
+	req = newJSONRequest(http.MethodDelete, "/feeds/9/pause", nil)
+	c, rec = newTestContext(e, req)
+	setPathParams(c, map[string]string{"id": "9"})
+	mockService.EXPECT().Unpause(gomock.Any(), int64(9)).Return(service.ErrNotFound)
+	require.NoError(t, h.Unpause(c))
+	require.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestFeedHandler_DeleteBatch_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockFeedService(ctrl)
+	mockRefreshService := mock.NewMockRefreshService(ctrl)
+	h := handler.NewFeedHandlerHelper(mockService, mockRefreshService, nil, nil)
+
+	e := newTestEcho()
+	reqBody := map[string]interface{}{
+		"ids": []string{"1", "2", "3"},
+	}
+	req := newJSONRequest(http.MethodDelete, "/feeds", reqBody)
+	c, rec := newTestContext(e, req)
+
+	mockService.EXPECT().
+		DeleteBatch(gomock.Any(), []int64{1, 2, 3}).
+		Return(nil)
+
+	err := h.DeleteBatch(c)
+	require.NoError(t, err)
+
+	require.Equal(t, http.StatusNoContent, rec.Code)
+}
+
+func TestFeedHandler_RefreshAll_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockFeedService(ctrl)
+	mockRefreshService := mock.NewMockRefreshService(ctrl)
+	h := handler.NewFeedHandlerHelper(mockService, mockRefreshService, nil, nil)
+
+	e := newTestEcho()
+	req := newJSONRequest(http.MethodPost, "/feeds/refresh", nil)
+	c, rec := newTestContext(e, req)
+
+	mockRefreshService.EXPECT().
+		RefreshAll(gomock.Any(), model.RefreshTriggerManual).
+		Return(nil)
+
+	err := h.RefreshAll(c)
+	require.NoError(t, err)
+
+	require.Equal(t, http.StatusNoContent, rec.Code)
+}
+
+func TestFeedHandler_RefreshAll_InProgress(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockFeedService(ctrl)
+	mockRefreshService := mock.NewMockRefreshService(ctrl)
+	h := handler.NewFeedHandlerHelper(mockService, mockRefreshService, nil, nil)
+
+	e := newTestEcho()
+	req := newJSONRequest(http.MethodPost, "/feeds/refresh", nil)
+	c, rec := newTestContext(e, req)
+
+	mockRefreshService.EXPECT().
+		RefreshAll(gomock.Any(), model.RefreshTriggerManual).
+		Return(service.ErrAlreadyRefreshing)
+
+	err := h.RefreshAll(c)
+	require.NoError(t, err)
+
+	var resp handler.ErrorResponse
+	assertJSONResponse(t, rec, http.StatusConflict, &resp)
+	require.Equal(t, "refresh_in_progress", resp.Code)
+}
+
+func TestFeedHandler_RefreshFeed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockFeedService(ctrl)
+	mockRefreshService := mock.NewMockRefreshService(ctrl)
+	h := handler.NewFeedHandlerHelper(mockService, mockRefreshService, nil, nil)
+
+	e := newTestEcho()
+	req := newJSONRequest(http.MethodPost, "/feeds/3/refresh", nil)
+	c, rec := newTestContext(e, req)
+	setPathParams(c, map[string]string{"id": "3"})
+
+	mockRefreshService.EXPECT().RefreshFeed(gomock.Any(), int64(3)).Return(nil)
+
+	require.NoError(t, h.RefreshFeed(c))
+	require.Equal(t, http.StatusNoContent, rec.Code)
+}
+
+func TestFeedHandler_RefreshFeed_InProgress(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockFeedService(ctrl)
+	mockRefreshService := mock.NewMockRefreshService(ctrl)
+	h := handler.NewFeedHandlerHelper(mockService, mockRefreshService, nil, nil)
+
+	e := newTestEcho()
+	req := newJSONRequest(http.MethodPost, "/feeds/3/refresh", nil)
+	c, rec := newTestContext(e, req)
+	setPathParams(c, map[string]string{"id": "3"})
+
+	mockRefreshService.EXPECT().RefreshFeed(gomock.Any(), int64(3)).Return(service.ErrAlreadyRefreshing)
+	mockRefreshService.EXPECT().GetRefreshStatus().Return(service.RefreshStatus{IsRefreshing: true})
+
+	require.NoError(t, h.RefreshFeed(c))
+
+	var resp handler.ErrorResponse
+	assertJSONResponse(t, rec, http.StatusConflict, &resp)
+	require.Equal(t, "refresh_in_progress", resp.Code)
+	require.Equal(t, map[string]any{"isRefreshing": true}, resp.Details["status"])
+}
+
+func TestFeedHandler_Preview_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockFeedService(ctrl)
+	mockRefreshService := mock.NewMockRefreshService(ctrl)
+	h := handler.NewFeedHandlerHelper(mockService, mockRefreshService, nil, nil)
+
+	e := newTestEcho()
+	req := newJSONRequest(http.MethodGet, "/feeds/preview?url=https://example.com/feed.xml", nil)
+	c, rec := newTestContext(e, req)
+
+	siteURL := "https://example.com"
+	preview := service.FeedPreview{
+		Title:         "Example Feed",
+		SiteURL:       &siteURL,
+		SuggestedType: "picture",
+	}
+
+	mockService.EXPECT().
+		Preview(gomock.Any(), "https://example.com/feed.xml").
+		Return(preview, nil)
+
+	err := h.Preview(c)
+	require.NoError(t, err)
 
 	var resp handler.FeedPreviewResponse
 	assertJSONResponse(t, rec, http.StatusOK, &resp)
 	require.Equal(t, "Example Feed", resp.Title)
+	require.Equal(t, "picture", resp.SuggestedType)
+}
+
+func TestFeedHandler_List_IncludeStats(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockFeedService(ctrl)
+	mockRefreshService := mock.NewMockRefreshService(ctrl)
+	h := handler.NewFeedHandlerHelper(mockService, mockRefreshService, nil, nil)
+
+	mockRefreshService.EXPECT().PollIntervals(gomock.Any(), gomock.Any()).Return(nil)
+	e := newTestEcho()
+	req := newJSONRequest(http.MethodGet, "/feeds?include=stats", nil)
+	c, rec := newTestContext(e, req)
+
+	published := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	mockService.EXPECT().List(gomock.Any(), gomock.Any()).Return([]model.Feed{{ID: 1, Title: "Feed 1"}, {ID: 2, Title: "Feed 2"}}, nil)
+	mockService.EXPECT().EffectiveConfigs(gomock.Any(), gomock.Any()).Return(nil, nil)
+	mockService.EXPECT().GetActivityStats(gomock.Any()).Return([]model.FeedActivityStats{
+		{FeedID: 1, EntriesLastWeek: 4, LastPublishedAt: &published, TotalEntries: 20},
+	}, nil)
+
+	err := h.List(c)
+	require.NoError(t, err)
+
+	var resp []handler.FeedResponse
+	assertJSONResponse(t, rec, http.StatusOK, &resp)
+	require.Len(t, resp, 2)
+	require.NotNil(t, resp[0].Stats)
+	require.Equal(t, 4, resp[0].Stats.EntriesLastWeek)
+	require.Equal(t, 20, resp[0].Stats.TotalEntries)
+	require.Equal(t, "2026-01-02T03:04:05Z", *resp[0].Stats.LastPublishedAt)
+	require.NotNil(t, resp[1].Stats)
+	require.Equal(t, handler.FeedStatsResponse{FeedID: "2"}, *resp[1].Stats)
+}
+
+func TestFeedHandler_List_InvalidInclude(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockFeedService(ctrl)
+	mockRefreshService := mock.NewMockRefreshService(ctrl)
+	h := handler.NewFeedHandlerHelper(mockService, mockRefreshService, nil, nil)
+
+	e := newTestEcho()
+	req := newJSONRequest(http.MethodGet, "/feeds?include=health", nil)
+	c, rec := newTestContext(e, req)
+
+	err := h.List(c)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestFeedHandler_List_TranslatedTitles(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockFeedService(ctrl)
+	mockAI := mock.NewMockAIService(ctrl)
+	h := handler.NewFeedHandler(mockService, nil, nil, mockAI)
+
+	e := newTestEcho()
+	req := newJSONRequest(http.MethodGet, "/feeds?language=en-US", nil)
+	c, rec := newTestContext(e, req)
+
+	feeds := []model.Feed{{ID: 1, Title: "Хабр"}, {ID: 2, Title: "Hacker News"}}
+	mockService.EXPECT().List(gomock.Any(), gomock.Any()).Return(feeds, nil)
+	mockService.EXPECT().EffectiveConfigs(gomock.Any(), feeds).Return(nil, nil)
+	mockAI.EXPECT().GetCachedFeedTitles(gomock.Any(), feeds, "en-US").Return(map[int64]string{1: "Habr"}, nil)
+
+	err := h.List(c)
+	require.NoError(t, err)
+
+	var resp []handler.FeedResponse
+	assertJSONResponse(t, rec, http.StatusOK, &resp)
+	require.Len(t, resp, 2)
+	require.Equal(t, "Хабр", resp[0].Title)
+	require.NotNil(t, resp[0].TranslatedTitle)
+	require.Equal(t, "Habr", *resp[0].TranslatedTitle)
+	require.Nil(t, resp[1].TranslatedTitle)
+}
+
+func TestFeedHandler_List_UnsupportedLanguage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	h := handler.NewFeedHandler(mock.NewMockFeedService(ctrl), nil, nil, mock.NewMockAIService(ctrl))
+
+	e := newTestEcho()
+	req := newJSONRequest(http.MethodGet, "/feeds?language=xx", nil)
+	c, rec := newTestContext(e, req)
+
+	err := h.List(c)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestFeedHandler_List_ConditionalGet(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockFeedService(ctrl)
+	version := uint64(1)
+	h := handler.NewFeedHandler(mockService, nil, func() uint64 { return version }, nil)
+	e := newTestEcho()
+	mockService.EXPECT().EffectiveConfigs(gomock.Any(), gomock.Any()).Return(nil, nil).Times(2)
+
+	mockService.EXPECT().List(gomock.Any(), gomock.Any()).Return([]model.Feed{{ID: 1, Title: "Feed"}}, nil)
+	c, rec := newTestContext(e, newJSONRequest(http.MethodGet, "/feeds", nil))
+	require.NoError(t, h.List(c))
+	require.Equal(t, http.StatusOK, rec.Code)
+	etag := rec.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+
+	req := newJSONRequest(http.MethodGet, "/feeds", nil)
+	req.Header.Set("If-None-Match", etag)
+	c, rec = newTestContext(e, req)
+	require.NoError(t, h.List(c))
+	require.Equal(t, http.StatusNotModified, rec.Code)
+
+	version++
+	mockService.EXPECT().List(gomock.Any(), gomock.Any()).Return([]model.Feed{{ID: 1, Title: "Renamed"}}, nil)
+	req = newJSONRequest(http.MethodGet, "/feeds", nil)
+	req.Header.Set("If-None-Match", etag)
+	c, rec = newTestContext(e, req)
+	require.NoError(t, h.List(c))
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NotEqual(t, etag, rec.Header().Get("ETag"))
+}
+
+func TestFeedHandler_Stats(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockFeedService(ctrl)
+	mockRefreshService := mock.NewMockRefreshService(ctrl)
+	h := handler.NewFeedHandlerHelper(mockService, mockRefreshService, nil, nil)
+
+	e := newTestEcho()
+	req := newJSONRequest(http.MethodGet, "/feeds/stats", nil)
+	c, rec := newTestContext(e, req)
+
+	mockService.EXPECT().GetActivityStats(gomock.Any()).Return([]model.FeedActivityStats{
+		{FeedID: 1, EntriesLastWeek: 2, TotalEntries: 5},
+		{FeedID: 2},
+	}, nil)
+
+	err := h.Stats(c)
+	require.NoError(t, err)
+
+	var resp []handler.FeedStatsResponse
+	assertJSONResponse(t, rec, http.StatusOK, &resp)
+	require.Equal(t, []handler.FeedStatsResponse{
+		{FeedID: "1", EntriesLastWeek: 2, TotalEntries: 5},
+		{FeedID: "2"},
+	}, resp)
+}
+
+func TestFeedHandler_Restore_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockFeedService(ctrl)
+	mockRefreshService := mock.NewMockRefreshService(ctrl)
+	h := handler.NewFeedHandlerHelper(mockService, mockRefreshService, nil, nil)
+
+	e := newTestEcho()
+	req := newJSONRequest(http.MethodPost, "/feeds/5/restore", nil)
+	c, rec := newTestContext(e, req)
+	setPathParams(c, map[string]string{"id": "5"})
+
+	mockService.EXPECT().Restore(gomock.Any(), int64(5)).Return(model.Feed{ID: 5, Title: "Feed"}, nil)
+
+	err := h.Restore(c)
+	require.NoError(t, err)
+
+	var resp handler.FeedResponse
+	assertJSONResponse(t, rec, http.StatusOK, &resp)
+	require.Equal(t, "5", resp.ID)
+}
+
+func TestFeedHandler_Restore_Expired(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockFeedService(ctrl)
+	mockRefreshService := mock.NewMockRefreshService(ctrl)
+	h := handler.NewFeedHandlerHelper(mockService, mockRefreshService, nil, nil)
+
+	e := newTestEcho()
+	req := newJSONRequest(http.MethodPost, "/feeds/5/restore", nil)
+	c, rec := newTestContext(e, req)
+	setPathParams(c, map[string]string{"id": "5"})
+
+	mockService.EXPECT().Restore(gomock.Any(), int64(5)).Return(model.Feed{}, service.ErrNotFound)
+
+	err := h.Restore(c)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestFeedHandler_ListRefreshRuns_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockFeedService(ctrl)
+	mockRefreshService := mock.NewMockRefreshService(ctrl)
+	h := handler.NewFeedHandlerHelper(mockService, mockRefreshService, nil, nil)
+
+	e := newTestEcho()
+	req := newJSONRequest(http.MethodGet, "/refresh/runs", nil)
+	c, rec := newTestContext(e, req)
+
+	started := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	mockRefreshService.EXPECT().ListRuns(gomock.Any()).Return([]model.RefreshRun{
+		{ID: 7, Trigger: model.RefreshTriggerScheduled, StartedAt: started, FinishedAt: started.Add(time.Minute), FeedsTotal: 3, FeedsFailed: 1, EntriesNew: 5},
+	}, nil)
+
+	err := h.ListRefreshRuns(c)
+	require.NoError(t, err)
+
+	var resp []handler.RefreshRunResponse
+	assertJSONResponse(t, rec, http.StatusOK, &resp)
+	require.Len(t, resp, 1)
+	require.Equal(t, "7", resp[0].ID)
+	require.Equal(t, "scheduled", resp[0].Trigger)
+	require.Equal(t, "2026-01-02T03:04:05Z", resp[0].StartedAt)
+	require.Equal(t, 5, resp[0].EntriesNew)
+}
+
+func TestFeedHandler_GetRefreshRun_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockFeedService(ctrl)
+	mockRefreshService := mock.NewMockRefreshService(ctrl)
+	h := handler.NewFeedHandlerHelper(mockService, mockRefreshService, nil, nil)
+
+	e := newTestEcho()
+	req := newJSONRequest(http.MethodGet, "/refresh/runs/7", nil)
+	c, rec := newTestContext(e, req)
+	setPathParams(c, map[string]string{"id": "7"})
+
+	errMsg := "HTTP 500"
+	mockRefreshService.EXPECT().GetRun(gomock.Any(), int64(7)).Return(
+		model.RefreshRun{ID: 7, Trigger: model.RefreshTriggerSingleFeed},
+		[]model.RefreshRunFeed{
+			{FeedID: 2, FeedTitle: "Broken", ErrorMessage: &errMsg},
+			{FeedID: 1, FeedTitle: "OK", EntriesNew: 2},
+		},
+		nil,
+	)
+
+	err := h.GetRefreshRun(c)
+	require.NoError(t, err)
+
+	var resp handler.RefreshRunDetailResponse
+	assertJSONResponse(t, rec, http.StatusOK, &resp)
+	require.Equal(t, "7", resp.Run.ID)
+	require.Len(t, resp.Feeds, 2)
+	require.Equal(t, "failed", resp.Feeds[0].Status)
+	require.Equal(t, "HTTP 500", *resp.Feeds[0].ErrorMessage)
+	require.Equal(t, "ok", resp.Feeds[1].Status)
+	require.Equal(t, 2, resp.Feeds[1].EntriesNew)
+}
+
+func TestFeedHandler_GetRefreshRun_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockFeedService(ctrl)
+	mockRefreshService := mock.NewMockRefreshService(ctrl)
+	h := handler.NewFeedHandlerHelper(mockService, mockRefreshService, nil, nil)
+
+	e := newTestEcho()
+	req := newJSONRequest(http.MethodGet, "/refresh/runs/8", nil)
+	c, rec := newTestContext(e, req)
+	setPathParams(c, map[string]string{"id": "8"})
+
+	mockRefreshService.EXPECT().GetRun(gomock.Any(), int64(8)).Return(model.RefreshRun{}, nil, service.ErrNotFound)
+
+	err := h.GetRefreshRun(c)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestFeedHandler_Probe(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockFeedService(ctrl)
+	mockRefreshService := mock.NewMockRefreshService(ctrl)
+	h := handler.NewFeedHandlerHelper(mockService, mockRefreshService, nil, nil)
+
+	e := newTestEcho()
+	req := newJSONRequest(http.MethodPost, "/feeds/3/probe?userAgent=fallback", nil)
+	c, rec := newTestContext(e, req)
+	setPathParams(c, map[string]string{"id": "3"})
+
+	mockRefreshService.EXPECT().Probe(gomock.Any(), int64(3), model.FeedUserAgentFallback).Return(service.FeedProbe{
+		URL:         "https://example.com/rss",
+		UserAgent:   model.FeedUserAgentFallback,
+		ResolvedIPs: []string{"203.0.113.7"},
+		Timing:      service.FeedProbeTiming{DNS: 12 * time.Millisecond, TTFB: 80 * time.Millisecond, Total: 95 * time.Millisecond},
+		StatusCode:  http.StatusForbidden,
+		Headers:     map[string]string{"Content-Type": "text/html"},
+		BodyPreview: "<html>",
+		AnubisPage:  true,
+		ParseError:  "Failed to detect feed type",
+	}, nil)
+
+	err := h.Probe(c)
+	require.NoError(t, err)
+
+	var resp handler.FeedProbeResponse
+	assertJSONResponse(t, rec, http.StatusOK, &resp)
+	require.Equal(t, "fallback", resp.UserAgent)
+	require.Equal(t, []string{"203.0.113.7"}, resp.ResolvedIPs)
+	require.Equal(t, int64(12), resp.Timing.DNSMs)
+	require.Equal(t, int64(80), resp.Timing.TTFBMs)
+	require.Equal(t, http.StatusForbidden, resp.StatusCode)
+	require.Equal(t, "text/html", resp.Headers["Content-Type"])
+	require.True(t, resp.AnubisPage)
+	require.False(t, resp.Parsed)
+}
+
+func TestFeedHandler_Probe_InvalidUserAgent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockFeedService(ctrl)
+	mockRefreshService := mock.NewMockRefreshService(ctrl)
+	h := handler.NewFeedHandlerHelper(mockService, mockRefreshService, nil, nil)
+
+	e := newTestEcho()
+	req := newJSONRequest(http.MethodPost, "/feeds/3/probe?userAgent=curl", nil)
+	c, rec := newTestContext(e, req)
+	setPathParams(c, map[string]string{"id": "3"})
+
+	mockRefreshService.EXPECT().Probe(gomock.Any(), int64(3), "curl").Return(service.FeedProbe{}, service.ErrInvalid)
+
+	err := h.Probe(c)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+const staticFeedRSS = `<?xml version="1.0"?><rss version="2.0"><channel><title>Pasted</title>
+<item><title>One</title><link>https://example.com/1</link><guid>1</guid></item></channel></rss>`
+
+func TestFeedHandler_CreateStatic(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockFeedService(ctrl)
+	mockRefreshService := mock.NewMockRefreshService(ctrl)
+	h := handler.NewFeedHandlerHelper(mockService, mockRefreshService, nil, nil)
+	e := newTestEcho()
+
+	req := newJSONRequest(http.MethodPost, "/feeds/static", map[string]interface{}{"content": staticFeedRSS, "folderId": "7"})
+	c, rec := newTestContext(e, req)
+	feed := model.Feed{ID: 5, Title: "Pasted", URL: service.StaticFeedURLPrefix + "abc"}
+	mockService.EXPECT().
+		AddStatic(gomock.Any(), gomock.Any(), gomock.Any(), "", service.FeedTypeAuto).
+		DoAndReturn(func(_ context.Context, parsed *gofeed.Feed, folderID *int64, _ string, _ string) (model.Feed, error) {
+			require.Equal(t, "Pasted", parsed.Title)
+			require.Equal(t, int64(7), *folderID)
+			return feed, nil
+		})
+	mockRefreshService.EXPECT().IngestStatic(gomock.Any(), int64(5), gomock.Any()).Return(1, 0, nil)
+	require.NoError(t, h.CreateStatic(c))
+
+	var resp handler.FeedResponse
+	assertJSONResponse(t, rec, http.StatusCreated, &resp)
+	require.Equal(t, "5", resp.ID)
+
+	req = newJSONRequest(http.MethodPost, "/feeds/static", map[string]interface{}{"content": "<html><body>hi</body></html>"})
+	c, rec = newTestContext(e, req)
+	require.NoError(t, h.CreateStatic(c))
+	var errResp handler.ErrorResponse
+	assertJSONResponse(t, rec, http.StatusBadRequest, &errResp)
+	require.Equal(t, handler.CodeInvalidRequest, errResp.Code)
+	require.Equal(t, "content is not an RSS, Atom or JSON feed", errResp.Message)
+
+	req = newJSONRequest(http.MethodPost, "/feeds/static", map[string]interface{}{"content": strings.Repeat("x", service.MaxStaticFeedSize+1)})
+	c, rec = newTestContext(e, req)
+	require.NoError(t, h.CreateStatic(c))
+	require.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+
+	req = newJSONRequest(http.MethodPost, "/feeds/static", map[string]interface{}{"content": strings.Repeat("x", 3*service.MaxStaticFeedSize)})
+	c, rec = newTestContext(e, req)
+	require.NoError(t, h.CreateStatic(c))
+	require.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+}
+
+func TestFeedHandler_CreateStatic_EmptyForPushes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockFeedService(ctrl)
+	mockRefreshService := mock.NewMockRefreshService(ctrl)
+	h := handler.NewFeedHandlerHelper(mockService, mockRefreshService, nil, nil)
+	e := newTestEcho()
+
+	req := newJSONRequest(http.MethodPost, "/feeds/static", map[string]interface{}{"title": "Changelog"})
+	c, rec := newTestContext(e, req)
+	mockService.EXPECT().
+		AddStatic(gomock.Any(), gomock.Any(), gomock.Nil(), "Changelog", service.FeedTypeAuto).
+		DoAndReturn(func(_ context.Context, parsed *gofeed.Feed, _ *int64, _ string, _ string) (model.Feed, error) {
+			require.Empty(t, parsed.Items)
+			return model.Feed{ID: 5, Title: "Changelog", URL: service.StaticFeedURLPrefix + "abc"}, nil
+		})
+	mockRefreshService.EXPECT().IngestStatic(gomock.Any(), int64(5), gomock.Any()).Return(0, 0, nil)
+	require.NoError(t, h.CreateStatic(c))
+	require.Equal(t, http.StatusCreated, rec.Code)
+
+	// Without a title there is nothing to name the feed after
+	req = newJSONRequest(http.MethodPost, "/feeds/static", map[string]interface{}{"content": " "})
+	c, rec = newTestContext(e, req)
+	require.NoError(t, h.CreateStatic(c))
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestFeedHandler_CreateIngestToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockFeedService(ctrl)
+	h := handler.NewFeedHandlerHelper(mockService, nil, nil, nil)
+	e := newTestEcho()
+
+	req := newJSONRequest(http.MethodPost, "/feeds/5/ingest-token", nil)
+	c, rec := newTestContext(e, req)
+	setPathParams(c, map[string]string{"id": "5"})
+	mockService.EXPECT().CreateIngestToken(gomock.Any(), int64(5)).Return("gist_ingest_abc", nil)
+	require.NoError(t, h.CreateIngestToken(c))
+
+	var resp handler.IngestTokenResponse
+	assertJSONResponse(t, rec, http.StatusCreated, &resp)
+	require.Equal(t, "gist_ingest_abc", resp.Token)
+
+	// Fetched feeds take no pushes
+	req = newJSONRequest(http.MethodPost, "/feeds/6/ingest-token", nil)
+	c, rec = newTestContext(e, req)
+	setPathParams(c, map[string]string{"id": "6"})
+	mockService.EXPECT().CreateIngestToken(gomock.Any(), int64(6)).Return("", service.ErrInvalid)
+	require.NoError(t, h.CreateIngestToken(c))
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestFeedHandler_Ingest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockFeedService(ctrl)
+	mockRefreshService := mock.NewMockRefreshService(ctrl)
+	h := handler.NewFeedHandlerHelper(mockService, mockRefreshService, nil, nil)
+	e := newTestEcho()
+
+	published := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+	req := newJSONRequest(http.MethodPost, "/feeds/5/entries", []map[string]interface{}{
+		{"title": "v1.2", "url": "https://example.com/changelog#1.2", "externalId": "1.2", "publishedAt": "2024-05-01T10:00:00+02:00"},
+		{"title": "No link"},
+	})
+	req.Header.Set("Authorization", "Bearer gist_ingest_abc")
+	c, rec := newTestContext(e, req)
+	setPathParams(c, map[string]string{"id": "5"})
+
+	mockService.EXPECT().ValidateIngestToken(gomock.Any(), int64(5), "gist_ingest_abc").Return(nil)
+	mockRefreshService.EXPECT().IngestItems(gomock.Any(), int64(5), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ int64, items []service.IngestItem) ([]service.IngestResult, error) {
+			require.Len(t, items, 2)
+			require.Equal(t, "1.2", items[0].ExternalID)
+			require.True(t, published.Equal(*items[0].PublishedAt))
+			return []service.IngestResult{
+				{Index: 0, Status: service.IngestCreated},
+				{Index: 1, Status: service.IngestSkipped, Reason: "url is required"},
+			}, nil
+		},
+	)
+	require.NoError(t, h.Ingest(c))
+
+	var resp handler.IngestResponse
+	assertJSONResponse(t, rec, http.StatusOK, &resp)
+	require.Equal(t, 1, resp.Created)
+	require.Equal(t, 1, resp.Skipped)
+	require.Len(t, resp.Results, 2)
+	require.Equal(t, "url is required", resp.Results[1].Reason)
+}
+
+func TestFeedHandler_Ingest_Rejected(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockFeedService(ctrl)
+	mockRefreshService := mock.NewMockRefreshService(ctrl)
+	h := handler.NewFeedHandlerHelper(mockService, mockRefreshService, nil, nil)
+	e := newTestEcho()
+
+	// Wrong or missing token
+	req := newJSONRequest(http.MethodPost, "/feeds/5/entries", []map[string]interface{}{})
+	c, rec := newTestContext(e, req)
+	setPathParams(c, map[string]string{"id": "5"})
+	mockService.EXPECT().ValidateIngestToken(gomock.Any(), int64(5), "").Return(service.ErrInvalidToken)
+	require.NoError(t, h.Ingest(c))
+	require.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	// Body over the limit
+	mockService.EXPECT().ValidateIngestToken(gomock.Any(), int64(5), "gist_ingest_abc").Return(nil).Times(2)
+	req = newJSONRequest(http.MethodPost, "/feeds/5/entries", []map[string]interface{}{{"content": strings.Repeat("x", service.MaxIngestBodySize)}})
+	req.Header.Set("Authorization", "Bearer gist_ingest_abc")
+	c, rec = newTestContext(e, req)
+	setPathParams(c, map[string]string{"id": "5"})
+	require.NoError(t, h.Ingest(c))
+	require.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+
+	// Too many items
+	req = newJSONRequest(http.MethodPost, "/feeds/5/entries", make([]map[string]interface{}, service.MaxIngestItems+1))
+	req.Header.Set("Authorization", "Bearer gist_ingest_abc")
+	c, rec = newTestContext(e, req)
+	setPathParams(c, map[string]string{"id": "5"})
+	mockRefreshService.EXPECT().IngestItems(gomock.Any(), int64(5), gomock.Any()).Return(nil, service.ErrIngestTooLarge)
+	require.NoError(t, h.Ingest(c))
+	require.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+}
+
+func TestFeedHandler_UpdateStatic(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRefreshService := mock.NewMockRefreshService(ctrl)
+	h := handler.NewFeedHandlerHelper(mock.NewMockFeedService(ctrl), mockRefreshService, nil, nil)
+	e := newTestEcho()
+
+	req := newJSONRequest(http.MethodPut, "/feeds/5/static", map[string]interface{}{"content": staticFeedRSS})
+	c, rec := newTestContext(e, req)
+	setPathParams(c, map[string]string{"id": "5"})
+	mockRefreshService.EXPECT().IngestStatic(gomock.Any(), int64(5), gomock.Any()).Return(1, 2, nil)
+	require.NoError(t, h.UpdateStatic(c))
+
+	var resp handler.StaticFeedUpdateResponse
+	assertJSONResponse(t, rec, http.StatusOK, &resp)
+	require.Equal(t, 1, resp.EntriesNew)
+	require.Equal(t, 2, resp.EntriesUpdated)
+
+	req = newJSONRequest(http.MethodPut, "/feeds/6/static", map[string]interface{}{"content": staticFeedRSS})
+	c, rec = newTestContext(e, req)
+	setPathParams(c, map[string]string{"id": "6"})
+	mockRefreshService.EXPECT().IngestStatic(gomock.Any(), int64(6), gomock.Any()).Return(0, 0, service.ErrInvalid)
+	require.NoError(t, h.UpdateStatic(c))
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestFeedHandler_GetFetchLog(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRefreshService := mock.NewMockRefreshService(ctrl)
+	h := handler.NewFeedHandlerHelper(mock.NewMockFeedService(ctrl), mockRefreshService, nil, nil)
+
+	fetchedAt := time.Date(2026, 10, 15, 8, 0, 0, 0, time.UTC)
+	mockRefreshService.EXPECT().GetFetchLog(gomock.Any(), int64(3)).Return(service.FeedFetchLog{
+		Status:       service.FeedFetchHealthUnstable,
+		SuccessRatio: 0.5,
+		Fetches: []model.FeedFetch{
+			{FeedID: 3, FetchedAt: fetchedAt, StatusCode: 502, DurationMs: 120, Outcome: model.FeedFetchHTTPError},
+			{FeedID: 3, FetchedAt: fetchedAt.Add(-time.Hour), StatusCode: 200, DurationMs: 80, Bytes: 2048, Outcome: model.FeedFetchOK},
+		},
+	}, nil)
+
+	c, rec := newTestContext(newTestEcho(), newJSONRequest(http.MethodGet, "/feeds/3/fetch-log", nil))
+	setPathParams(c, map[string]string{"id": "3"})
+	require.NoError(t, h.GetFetchLog(c))
+
+	var resp handler.FeedFetchLogResponse
+	assertJSONResponse(t, rec, http.StatusOK, &resp)
+	require.Equal(t, "unstable", resp.Status)
+	require.Equal(t, 0.5, resp.SuccessRatio)
+	require.Len(t, resp.Fetches, 2)
+	require.Equal(t, "2026-10-15T08:00:00Z", resp.Fetches[0].FetchedAt)
+	require.Equal(t, 502, resp.Fetches[0].StatusCode)
+	require.Equal(t, "http_error", resp.Fetches[0].Outcome)
+	require.Equal(t, 2048, resp.Fetches[1].Bytes)
+
+	// Unknown feeds are a 404
+	mockRefreshService.EXPECT().GetFetchLog(gomock.Any(), int64(4)).Return(service.FeedFetchLog{}, service.ErrNotFound)
+	c, rec = newTestContext(newTestEcho(), newJSONRequest(http.MethodGet, "/feeds/4/fetch-log", nil))
+	setPathParams(c, map[string]string{"id": "4"})
+	require.NoError(t, h.GetFetchLog(c))
+	require.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestFeedHandler_GetIngestReport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRefreshService := mock.NewMockRefreshService(ctrl)
+	h := handler.NewFeedHandlerHelper(mock.NewMockFeedService(ctrl), mockRefreshService, nil, nil)
+
+	mockRefreshService.EXPECT().GetIngestReport(gomock.Any(), int64(3)).Return(&service.IngestReport{
+		CheckedAt: time.Date(2026, 10, 15, 8, 0, 0, 0, time.UTC),
+		Items:     5,
+		Saved:     4,
+		Counts:    map[string]int{service.IngestUnparsedDate: 1},
+		Issues:    []service.IngestIssue{{Reason: service.IngestUnparsedDate, Title: "Post", Link: "https://example.com/p", Detail: "tomorrow"}},
+	}, nil)
+
+	c, rec := newTestContext(newTestEcho(), newJSONRequest(http.MethodGet, "/feeds/3/ingest-report", nil))
+	setPathParams(c, map[string]string{"id": "3"})
+	require.NoError(t, h.GetIngestReport(c))
+
+	var resp handler.FeedIngestReportResponse
+	assertJSONResponse(t, rec, http.StatusOK, &resp)
+	require.NotNil(t, resp.Report)
+	require.Equal(t, "2026-10-15T08:00:00Z", resp.Report.CheckedAt)
+	require.Equal(t, 5, resp.Report.Items)
+	require.Equal(t, 4, resp.Report.Saved)
+	require.Equal(t, 1, resp.Report.Counts["unparsed_date"])
+	require.Len(t, resp.Report.Issues, 1)
+	require.Equal(t, "tomorrow", resp.Report.Issues[0].Detail)
+
+	// A clean last refresh has no report
+	mockRefreshService.EXPECT().GetIngestReport(gomock.Any(), int64(4)).Return(nil, nil)
+	c, rec = newTestContext(newTestEcho(), newJSONRequest(http.MethodGet, "/feeds/4/ingest-report", nil))
+	setPathParams(c, map[string]string{"id": "4"})
+	require.NoError(t, h.GetIngestReport(c))
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.JSONEq(t, `{"report":null}`, rec.Body.String())
+
+	mockRefreshService.EXPECT().GetIngestReport(gomock.Any(), int64(5)).Return(nil, service.ErrNotFound)
+	c, rec = newTestContext(newTestEcho(), newJSONRequest(http.MethodGet, "/feeds/5/ingest-report", nil))
+	setPathParams(c, map[string]string{"id": "5"})
+	require.NoError(t, h.GetIngestReport(c))
+	require.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestFeedHandler_ArchiveBackfill(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRefreshService := mock.NewMockRefreshService(ctrl)
+	h := handler.NewFeedHandlerHelper(mock.NewMockFeedService(ctrl), mockRefreshService, nil, nil)
+	startedAt := time.Date(2026, 10, 15, 8, 0, 0, 0, time.UTC)
+	finishedAt := startedAt.Add(time.Minute)
+
+	mockRefreshService.EXPECT().
+		StartArchiveBackfill(gomock.Any(), int64(3), service.ArchiveBackfillOptions{MaxPages: 20}).
+		Return(service.ArchiveBackfill{FeedID: 3, Status: service.ArchiveBackfillRunning, MaxPages: 20, StartedAt: startedAt}, nil)
+	c, rec := newTestContext(newTestEcho(), newJSONRequest(http.MethodPost, "/feeds/3/backfill-archive", map[string]any{"maxPages": 20}))
+	setPathParams(c, map[string]string{"id": "3"})
+	require.NoError(t, h.StartArchiveBackfill(c))
+	var started handler.ArchiveBackfillResponse
+	assertJSONResponse(t, rec, http.StatusAccepted, &started)
+	require.Equal(t, "3", started.FeedID)
+	require.Equal(t, "running", started.Status)
+	require.Equal(t, 20, started.MaxPages)
+
+	mockRefreshService.EXPECT().
+		StartArchiveBackfill(gomock.Any(), int64(3), service.ArchiveBackfillOptions{}).
+		Return(service.ArchiveBackfill{}, service.ErrConflict)
+	c, rec = newTestContext(newTestEcho(), newJSONRequest(http.MethodPost, "/feeds/3/backfill-archive", nil))
+	setPathParams(c, map[string]string{"id": "3"})
+	require.NoError(t, h.StartArchiveBackfill(c))
+	require.Equal(t, http.StatusConflict, rec.Code)
+
+	c, rec = newTestContext(newTestEcho(), newJSONRequest(http.MethodPost, "/feeds/3/backfill-archive", map[string]any{"maxPages": 500}))
+	setPathParams(c, map[string]string{"id": "3"})
+	require.NoError(t, h.StartArchiveBackfill(c))
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+
+	mockRefreshService.EXPECT().GetArchiveBackfill(gomock.Any(), int64(3)).Return(&service.ArchiveBackfill{
+		FeedID: 3, Status: service.ArchiveBackfillFailed, MaxPages: 5, Pages: 2, Items: 40, NewEntries: 38,
+		Error: "page 1 links back to page 0", StartedAt: startedAt, FinishedAt: &finishedAt,
+	}, nil)
+	c, rec = newTestContext(newTestEcho(), newJSONRequest(http.MethodGet, "/feeds/3/backfill-archive", nil))
+	setPathParams(c, map[string]string{"id": "3"})
+	require.NoError(t, h.GetArchiveBackfill(c))
+	var resp handler.FeedArchiveBackfillResponse
+	assertJSONResponse(t, rec, http.StatusOK, &resp)
+	require.NotNil(t, resp.Backfill)
+	require.Equal(t, "failed", resp.Backfill.Status)
+	require.Equal(t, 38, resp.Backfill.NewEntries)
+	require.Equal(t, "page 1 links back to page 0", resp.Backfill.Error)
+	require.Equal(t, "2026-10-15T08:01:00Z", *resp.Backfill.FinishedAt)
+
+	mockRefreshService.EXPECT().GetArchiveBackfill(gomock.Any(), int64(4)).Return(nil, nil)
+	c, rec = newTestContext(newTestEcho(), newJSONRequest(http.MethodGet, "/feeds/4/backfill-archive", nil))
+	setPathParams(c, map[string]string{"id": "4"})
+	require.NoError(t, h.GetArchiveBackfill(c))
+	require.JSONEq(t, `{"backfill":null}`, rec.Body.String())
+}
+
+func TestFeedHandler_RefreshStatus_QuietHours(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRefreshService := mock.NewMockRefreshService(ctrl)
+	h := handler.NewFeedHandlerHelper(mock.NewMockFeedService(ctrl), mockRefreshService, nil, nil)
+
+	until := time.Date(2026, 3, 2, 7, 0, 0, 0, time.UTC)
+	mockRefreshService.EXPECT().GetRefreshStatus().Return(service.RefreshStatus{QuietUntil: &until})
+	mockRefreshService.EXPECT().GetRefreshStatus().Return(service.RefreshStatus{})
+
+	e := newTestEcho()
+	c, rec := newTestContext(e, newJSONRequest(http.MethodGet, "/feeds/refresh", nil))
+	require.NoError(t, h.RefreshStatus(c))
+	var resp map[string]any
+	assertJSONResponse(t, rec, http.StatusOK, &resp)
+	require.Equal(t, map[string]any{"isRefreshing": false, "paused": "quiet_hours", "pausedUntil": "2026-03-02T07:00:00Z"}, resp)
+
+	c, rec = newTestContext(e, newJSONRequest(http.MethodGet, "/feeds/refresh", nil))
+	require.NoError(t, h.RefreshStatus(c))
+	resp = nil
+	assertJSONResponse(t, rec, http.StatusOK, &resp)
+	require.Equal(t, map[string]any{"isRefreshing": false}, resp)
 }
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 	"time"
@@ -30,13 +31,57 @@ type deleteFoldersRequest struct {
 	IDs []string `json:"ids"`
 }
 
+type reorderFoldersRequest struct {
+	// IDs lists every folder to place, all under one parent, in display order.
+	IDs []string `json:"ids"`
+}
+
+type folderRuleRequest struct {
+	Pattern string `json:"pattern"`
+	// MatchField is host, url or title.
+	MatchField string `json:"matchField"`
+	Priority   int    `json:"priority"`
+}
+
+type folderRuleResponse struct {
+	ID         string `json:"id"`
+	FolderID   string `json:"folderId"`
+	Pattern    string `json:"pattern"`
+	MatchField string `json:"matchField"`
+	Priority   int    `json:"priority"`
+	CreatedAt  string `json:"createdAt"`
+	UpdatedAt  string `json:"updatedAt"`
+}
+
+type applyFolderRulesResponse struct {
+	Moved int `json:"moved"`
+}
+
+// folderFeedDefaultsResponse holds the options the folder's feeds inherit;
+// an absent option is inherited from the parent folder or the global setting.
+type folderFeedDefaultsResponse struct {
+	UserAgent       *string `json:"userAgent,omitempty"`
+	AutoReadability *bool   `json:"autoReadability,omitempty"`
+	AutoTranslate   *bool   `json:"autoTranslate,omitempty"`
+}
+
+// updateFolderFeedDefaultsRequest replaces all of a folder's feed defaults;
+// null or omitted options inherit.
+type updateFolderFeedDefaultsRequest struct {
+	UserAgent       *string `json:"userAgent" example:"Mozilla/5.0"`
+	AutoReadability *bool   `json:"autoReadability" example:"true"`
+	AutoTranslate   *bool   `json:"autoTranslate"`
+}
+
 type folderResponse struct {
-	ID        string  `json:"id"`
-	Name      string  `json:"name"`
-	ParentID  *string `json:"parentId,omitempty"`
-	Type      string  `json:"type"`
-	CreatedAt string  `json:"createdAt"`
-	UpdatedAt string  `json:"updatedAt"`
+	ID           string                     `json:"id"`
+	Name         string                     `json:"name"`
+	ParentID     *string                    `json:"parentId,omitempty"`
+	Type         string                     `json:"type"`
+	SortOrder    int                        `json:"sortOrder"`
+	FeedDefaults folderFeedDefaultsResponse `json:"feedDefaults"`
+	CreatedAt    string                     `json:"createdAt"`
+	UpdatedAt    string                     `json:"updatedAt"`
 }
 
 func NewFolderHandler(service service.FolderService) *FolderHandler {
@@ -46,10 +91,18 @@ func NewFolderHandler(service service.FolderService) *FolderHandler {
 func (h *FolderHandler) RegisterRoutes(g *echo.Group) {
 	g.POST("/folders", h.Create)
 	g.GET("/folders", h.List)
+	g.PUT("/folders/reorder", h.Reorder)
+	g.POST("/folders/rules/apply", h.ApplyRules)
 	g.PUT("/folders/:id", h.Update)
 	g.PATCH("/folders/:id/type", h.UpdateType)
+	g.PUT("/folders/:id/feed-defaults", h.UpdateFeedDefaults)
 	g.DELETE("/folders/:id", h.Delete)
+	g.POST("/folders/:id/restore", h.Restore)
 	g.DELETE("/folders", h.DeleteBatch)
+	g.GET("/folders/:id/rules", h.ListRules)
+	g.POST("/folders/:id/rules", h.CreateRule)
+	g.PUT("/folders/:id/rules/:ruleId", h.UpdateRule)
+	g.DELETE("/folders/:id/rules/:ruleId", h.DeleteRule)
 }
 
 // Create creates a new folder.
@@ -65,13 +118,13 @@ func (h *FolderHandler) RegisterRoutes(g *echo.Group) {
 func (h *FolderHandler) Create(c echo.Context) error {
 	var req folderRequest
 	if err := c.Bind(&req); err != nil {
-		return c.JSON(http.StatusBadRequest, errorResponse{Error: "invalid request"})
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid request")
 	}
 	var parentID *int64
 	if req.ParentID != nil {
 		id, err := strconv.ParseInt(*req.ParentID, 10, 64)
 		if err != nil {
-			return c.JSON(http.StatusBadRequest, errorResponse{Error: "invalid parent ID"})
+			return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid parent ID")
 		}
 		parentID = &id
 	}
@@ -79,7 +132,7 @@ func (h *FolderHandler) Create(c echo.Context) error {
 	if folderType == "" {
 		folderType = "article"
 	} else if !isValidContentType(folderType) {
-		return c.JSON(http.StatusBadRequest, errorResponse{Error: "type must be article, picture, or notification"})
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "type must be article, picture, or notification")
 	}
 	folder, err := h.service.Create(c.Request().Context(), req.Name, parentID, folderType)
 	if err != nil {
@@ -110,6 +163,44 @@ func (h *FolderHandler) List(c echo.Context) error {
 	return c.JSON(http.StatusOK, response)
 }
 
+// Reorder sets the manual order of sibling folders.
+// @Summary Reorder folders
+// @Description Place folders under one parent in the given order. Folders not listed keep their position.
+// @Tags folders
+// @Accept json
+// @Param request body reorderFoldersRequest true "Folder IDs in display order"
+// @Success 204 "No Content"
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Router /folders/reorder [put]
+func (h *FolderHandler) Reorder(c echo.Context) error {
+	var req reorderFoldersRequest
+	if err := c.Bind(&req); err != nil {
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid request")
+	}
+	if len(req.IDs) == 0 {
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "no folder IDs provided")
+	}
+	ids := make([]int64, 0, len(req.IDs))
+	for _, idStr := range req.IDs {
+		id, err := strconv.ParseInt(idStr, 10, 64)
+		if err != nil {
+			return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid folder ID")
+		}
+		ids = append(ids, id)
+	}
+
+	if err := h.service.Reorder(c.Request().Context(), ids); err != nil {
+		if errors.Is(err, service.ErrInvalid) {
+			return Error(c, http.StatusBadRequest, CodeInvalidRequest, "folders must be unique and share a parent")
+		}
+		logger.Error("folder reorder failed", "module", "handler", "action", "update", "resource", "folder", "result", "failed", "count", len(ids), "error", err)
+		return writeServiceError(c, err)
+	}
+	logger.Info("folders reordered", "module", "handler", "action", "update", "resource", "folder", "result", "ok", "count", len(ids))
+	return c.NoContent(http.StatusNoContent)
+}
+
 // Update updates an existing folder.
 // @Summary Update a folder
 // @Description Update the name or parent ID of an existing folder
@@ -125,17 +216,17 @@ func (h *FolderHandler) List(c echo.Context) error {
 func (h *FolderHandler) Update(c echo.Context) error {
 	id, err := parseIDParam(c, "id")
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, errorResponse{Error: "invalid request"})
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid request")
 	}
 	var req folderRequest
 	if err := c.Bind(&req); err != nil {
-		return c.JSON(http.StatusBadRequest, errorResponse{Error: "invalid request"})
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid request")
 	}
 	var parentID *int64
 	if req.ParentID != nil {
 		pid, err := strconv.ParseInt(*req.ParentID, 10, 64)
 		if err != nil {
-			return c.JSON(http.StatusBadRequest, errorResponse{Error: "invalid parent ID"})
+			return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid parent ID")
 		}
 		parentID = &pid
 	}
@@ -162,14 +253,14 @@ func (h *FolderHandler) Update(c echo.Context) error {
 func (h *FolderHandler) UpdateType(c echo.Context) error {
 	id, err := parseIDParam(c, "id")
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, errorResponse{Error: "invalid request"})
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid request")
 	}
 	var req updateFolderTypeRequest
 	if err := c.Bind(&req); err != nil {
-		return c.JSON(http.StatusBadRequest, errorResponse{Error: "invalid request"})
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid request")
 	}
 	if !isValidContentType(req.Type) {
-		return c.JSON(http.StatusBadRequest, errorResponse{Error: "type must be article, picture, or notification"})
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "type must be article, picture, or notification")
 	}
 	if err := h.service.UpdateType(c.Request().Context(), id, req.Type); err != nil {
 		logger.Error("folder update type failed", "module", "handler", "action", "update", "resource", "folder", "result", "failed", "folder_id", id, "type", req.Type, "error", err)
@@ -179,9 +270,46 @@ func (h *FolderHandler) UpdateType(c echo.Context) error {
 	return c.NoContent(http.StatusNoContent)
 }
 
+// UpdateFeedDefaults sets the options a folder's feeds inherit.
+// @Summary Update folder feed defaults
+// @Description Replace the options the feeds in the folder and its subfolders inherit: a custom User-Agent for refreshes, opening entries in readability mode and translating list titles after refreshes. A feed's own value or a nearer folder's beats them; null inherits from the parent folder, then the global setting. Feeds are not rewritten, so the change applies to them at once.
+// @Tags folders
+// @Accept json
+// @Produce json
+// @Param id path int true "Folder ID"
+// @Param request body updateFolderFeedDefaultsRequest true "Feed defaults"
+// @Success 200 {object} folderResponse
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Router /folders/{id}/feed-defaults [put]
+func (h *FolderHandler) UpdateFeedDefaults(c echo.Context) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid request")
+	}
+	var req updateFolderFeedDefaultsRequest
+	if err := c.Bind(&req); err != nil {
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid request")
+	}
+	folder, err := h.service.UpdateFeedDefaults(c.Request().Context(), id, model.FeedDefaults{
+		UserAgent:       req.UserAgent,
+		AutoReadability: req.AutoReadability,
+		AutoTranslate:   req.AutoTranslate,
+	})
+	if err != nil {
+		if errors.Is(err, service.ErrInvalid) {
+			return Error(c, http.StatusBadRequest, CodeInvalidRequest, "userAgent must be at most 512 characters without control characters")
+		}
+		logger.Error("folder update feed defaults failed", "module", "handler", "action", "update", "resource", "folder", "result", "failed", "folder_id", id, "error", err)
+		return writeServiceError(c, err)
+	}
+	logger.Info("folder feed defaults updated", "module", "handler", "action", "update", "resource", "folder", "result", "ok", "folder_id", id)
+	return c.JSON(http.StatusOK, toFolderResponse(folder))
+}
+
 // Delete deletes a folder.
 // @Summary Delete a folder
-// @Description Delete an existing folder
+// @Description Move a folder, its subfolders and their feeds to the trash. They can be restored for 7 days.
 // @Tags folders
 // @Param id path int true "Folder ID"
 // @Success 204 "No Content"
@@ -191,7 +319,7 @@ func (h *FolderHandler) UpdateType(c echo.Context) error {
 func (h *FolderHandler) Delete(c echo.Context) error {
 	id, err := parseIDParam(c, "id")
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, errorResponse{Error: "invalid request"})
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid request")
 	}
 	if err := h.service.Delete(c.Request().Context(), id); err != nil {
 		logger.Error("folder delete failed", "module", "handler", "action", "delete", "resource", "folder", "result", "failed", "folder_id", id, "error", err)
@@ -201,6 +329,31 @@ func (h *FolderHandler) Delete(c echo.Context) error {
 	return c.NoContent(http.StatusNoContent)
 }
 
+// Restore undoes a folder deletion.
+// @Summary Restore a deleted folder
+// @Description Restore a folder deleted within the last 7 days, together with the subfolders and feeds deleted with it
+// @Tags folders
+// @Produce json
+// @Param id path int true "Folder ID"
+// @Success 200 {object} folderResponse
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 409 {object} errorResponse
+// @Router /folders/{id}/restore [post]
+func (h *FolderHandler) Restore(c echo.Context) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid request")
+	}
+	folder, err := h.service.Restore(c.Request().Context(), id)
+	if err != nil {
+		logger.Error("folder restore failed", "module", "handler", "action", "restore", "resource", "folder", "result", "failed", "folder_id", id, "error", err)
+		return writeServiceError(c, err)
+	}
+	logger.Info("folder restored", "module", "handler", "action", "restore", "resource", "folder", "result", "ok", "folder_id", id)
+	return c.JSON(http.StatusOK, toFolderResponse(folder))
+}
+
 // DeleteBatch deletes multiple folders.
 // @Summary Delete multiple folders
 // @Description Delete multiple folders at once (also deletes feeds in them)
@@ -213,16 +366,16 @@ func (h *FolderHandler) Delete(c echo.Context) error {
 func (h *FolderHandler) DeleteBatch(c echo.Context) error {
 	var req deleteFoldersRequest
 	if err := c.Bind(&req); err != nil {
-		return c.JSON(http.StatusBadRequest, errorResponse{Error: "invalid request"})
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid request")
 	}
 	if len(req.IDs) == 0 {
-		return c.JSON(http.StatusBadRequest, errorResponse{Error: "no folder IDs provided"})
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "no folder IDs provided")
 	}
 
 	for _, idStr := range req.IDs {
 		id, err := strconv.ParseInt(idStr, 10, 64)
 		if err != nil {
-			return c.JSON(http.StatusBadRequest, errorResponse{Error: "invalid folder ID"})
+			return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid folder ID")
 		}
 		if err := h.service.Delete(c.Request().Context(), id); err != nil {
 			logger.Error("folder batch delete failed", "module", "handler", "action", "delete", "resource", "folder", "result", "failed", "folder_id", id, "error", err)
@@ -234,12 +387,167 @@ func (h *FolderHandler) DeleteBatch(c echo.Context) error {
 	return c.NoContent(http.StatusNoContent)
 }
 
+// ListRules returns the rules of a folder.
+// @Summary List folder rules
+// @Description Get the rules that file new feeds into a folder, in evaluation order
+// @Tags folders
+// @Produce json
+// @Param id path int true "Folder ID"
+// @Success 200 {array} folderRuleResponse
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Router /folders/{id}/rules [get]
+func (h *FolderHandler) ListRules(c echo.Context) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid request")
+	}
+	rules, err := h.service.ListRules(c.Request().Context(), id)
+	if err != nil {
+		logger.Error("folder rule list failed", "module", "handler", "action", "list", "resource", "folder", "result", "failed", "folder_id", id, "error", err)
+		return writeServiceError(c, err)
+	}
+	response := make([]folderRuleResponse, 0, len(rules))
+	for _, rule := range rules {
+		response = append(response, toFolderRuleResponse(rule))
+	}
+	return c.JSON(http.StatusOK, response)
+}
+
+// CreateRule adds a rule to a folder.
+// @Summary Create a folder rule
+// @Description Add a rule that files feeds added without a folder into this one. Host patterns also match subdomains; url and title patterns are case-insensitive globs (* and ?) or /regular expressions/. Rules are tried by ascending priority.
+// @Tags folders
+// @Accept json
+// @Produce json
+// @Param id path int true "Folder ID"
+// @Param rule body folderRuleRequest true "Folder rule"
+// @Success 201 {object} folderRuleResponse
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Router /folders/{id}/rules [post]
+func (h *FolderHandler) CreateRule(c echo.Context) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid request")
+	}
+	var req folderRuleRequest
+	if err := c.Bind(&req); err != nil {
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid request")
+	}
+	rule, err := h.service.CreateRule(c.Request().Context(), model.FolderRule{FolderID: id, Pattern: req.Pattern, MatchField: req.MatchField, Priority: req.Priority})
+	if err != nil {
+		return h.writeRuleError(c, "create", id, err)
+	}
+	return c.JSON(http.StatusCreated, toFolderRuleResponse(rule))
+}
+
+// UpdateRule changes a folder rule.
+// @Summary Update a folder rule
+// @Description Replace the pattern, match field and priority of a folder rule
+// @Tags folders
+// @Accept json
+// @Produce json
+// @Param id path int true "Folder ID"
+// @Param ruleId path int true "Rule ID"
+// @Param rule body folderRuleRequest true "Folder rule"
+// @Success 200 {object} folderRuleResponse
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Router /folders/{id}/rules/{ruleId} [put]
+func (h *FolderHandler) UpdateRule(c echo.Context) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid request")
+	}
+	ruleID, err := parseIDParam(c, "ruleId")
+	if err != nil {
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid request")
+	}
+	var req folderRuleRequest
+	if err := c.Bind(&req); err != nil {
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid request")
+	}
+	rule, err := h.service.UpdateRule(c.Request().Context(), model.FolderRule{ID: ruleID, FolderID: id, Pattern: req.Pattern, MatchField: req.MatchField, Priority: req.Priority})
+	if err != nil {
+		return h.writeRuleError(c, "update", id, err)
+	}
+	return c.JSON(http.StatusOK, toFolderRuleResponse(rule))
+}
+
+// DeleteRule removes a folder rule.
+// @Summary Delete a folder rule
+// @Description Delete a folder rule. Feeds it already filed stay where they are.
+// @Tags folders
+// @Param id path int true "Folder ID"
+// @Param ruleId path int true "Rule ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Router /folders/{id}/rules/{ruleId} [delete]
+func (h *FolderHandler) DeleteRule(c echo.Context) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid request")
+	}
+	ruleID, err := parseIDParam(c, "ruleId")
+	if err != nil {
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid request")
+	}
+	if err := h.service.DeleteRule(c.Request().Context(), id, ruleID); err != nil {
+		return h.writeRuleError(c, "delete", id, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// ApplyRules files existing uncategorized feeds.
+// @Summary Apply folder rules
+// @Description Run the folder rules over feeds without a folder and move the ones that match
+// @Tags folders
+// @Produce json
+// @Success 200 {object} applyFolderRulesResponse
+// @Router /folders/rules/apply [post]
+func (h *FolderHandler) ApplyRules(c echo.Context) error {
+	moved, err := h.service.ApplyRules(c.Request().Context())
+	if err != nil {
+		logger.Error("folder rules apply failed", "module", "handler", "action", "update", "resource", "feed", "result", "failed", "count", moved, "error", err)
+		return writeServiceError(c, err)
+	}
+	return c.JSON(http.StatusOK, applyFolderRulesResponse{Moved: moved})
+}
+
+func (h *FolderHandler) writeRuleError(c echo.Context, action string, folderID int64, err error) error {
+	if errors.Is(err, service.ErrInvalid) {
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "matchField must be host, url, or title with a valid pattern")
+	}
+	logger.Error("folder rule "+action+" failed", "module", "handler", "action", action, "resource", "folder", "result", "failed", "folder_id", folderID, "error", err)
+	return writeServiceError(c, err)
+}
+
+func toFolderRuleResponse(rule model.FolderRule) folderRuleResponse {
+	return folderRuleResponse{
+		ID:         idToString(rule.ID),
+		FolderID:   idToString(rule.FolderID),
+		Pattern:    rule.Pattern,
+		MatchField: rule.MatchField,
+		Priority:   rule.Priority,
+		CreatedAt:  rule.CreatedAt.UTC().Format(time.RFC3339),
+		UpdatedAt:  rule.UpdatedAt.UTC().Format(time.RFC3339),
+	}
+}
+
 func toFolderResponse(folder model.Folder) folderResponse {
 	return folderResponse{
 		ID:        idToString(folder.ID),
 		Name:      folder.Name,
 		ParentID:  idPtrToString(folder.ParentID),
 		Type:      folder.Type,
+		SortOrder: folder.SortOrder,
+		FeedDefaults: folderFeedDefaultsResponse{
+			UserAgent:       folder.FeedDefaults.UserAgent,
+			AutoReadability: folder.FeedDefaults.AutoReadability,
+			AutoTranslate:   folder.FeedDefaults.AutoTranslate,
+		},
 		CreatedAt: folder.CreatedAt.UTC().Format(time.RFC3339),
 		UpdatedAt: folder.UpdatedAt.UTC().Format(time.RFC3339),
 	}
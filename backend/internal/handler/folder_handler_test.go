@@ -9,6 +9,7 @@ import (
 	"go.uber.org/mock/gomock"
 
 	"gist/backend/internal/model"
+	"gist/backend/internal/service"
 	"gist/backend/internal/service/mock"
 )
 
@@ -106,6 +107,34 @@ func TestFolderHandler_Update_Success(t *testing.T) {
 	require.Equal(t, "Updated Name", resp.Name)
 }
 
+func TestFolderHandler_Update_Cycle(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockFolderService(ctrl)
+	h := handler.NewFolderHandlerHelper(mockService)
+
+	e := newTestEcho()
+	reqBody := map[string]interface{}{
+		"name":     "Child",
+		"parentId": "2",
+	}
+	req := newJSONRequest(http.MethodPut, "/folders/1", reqBody)
+	c, rec := newTestContext(e, req)
+	setPathParams(c, map[string]string{"id": "1"})
+
+	mockService.EXPECT().
+		Update(gomock.Any(), int64(1), "Child", gomock.Any()).
+		Return(model.Folder{}, service.ErrFolderCycle)
+
+	err := h.Update(c)
+	require.NoError(t, err)
+
+	var resp handler.ErrorResponse
+	assertJSONResponse(t, rec, http.StatusBadRequest, &resp)
+	require.Equal(t, "folder_cycle", resp.Code)
+}
+
 func TestFolderHandler_Delete_Success(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -128,6 +157,52 @@ func TestFolderHandler_Delete_Success(t *testing.T) {
 	require.Equal(t, http.StatusNoContent, rec.Code)
 }
 
+func TestFolderHandler_Restore_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockFolderService(ctrl)
+	h := handler.NewFolderHandlerHelper(mockService)
+
+	e := newTestEcho()
+	req := newJSONRequest(http.MethodPost, "/folders/123/restore", nil)
+	c, rec := newTestContext(e, req)
+	setPathParams(c, map[string]string{"id": "123"})
+
+	mockService.EXPECT().
+		Restore(gomock.Any(), int64(123)).
+		Return(model.Folder{ID: 123, Name: "Tech", Type: "article"}, nil)
+
+	err := h.Restore(c)
+	require.NoError(t, err)
+
+	var resp handler.FolderResponse
+	assertJSONResponse(t, rec, http.StatusOK, &resp)
+	require.Equal(t, "123", resp.ID)
+	require.Equal(t, "Tech", resp.Name)
+}
+
+func TestFolderHandler_Restore_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockFolderService(ctrl)
+	h := handler.NewFolderHandlerHelper(mockService)
+
+	e := newTestEcho()
+	req := newJSONRequest(http.MethodPost, "/folders/123/restore", nil)
+	c, rec := newTestContext(e, req)
+	setPathParams(c, map[string]string{"id": "123"})
+
+	mockService.EXPECT().
+		Restore(gomock.Any(), int64(123)).
+		Return(model.Folder{}, service.ErrNotFound)
+
+	err := h.Restore(c)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusNotFound, rec.Code)
+}
+
 func TestFolderHandler_UpdateType_Success(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -153,6 +228,44 @@ func TestFolderHandler_UpdateType_Success(t *testing.T) {
 	require.Equal(t, http.StatusNoContent, rec.Code)
 }
 
+func TestFolderHandler_UpdateFeedDefaults(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockFolderService(ctrl)
+	h := handler.NewFolderHandlerHelper(mockService)
+	e := newTestEcho()
+
+	reqBody := map[string]interface{}{
+		"userAgent":     "Mozilla/5.0 (Folder)",
+		"autoTranslate": true,
+	}
+	req := newJSONRequest(http.MethodPut, "/folders/123/feed-defaults", reqBody)
+	c, rec := newTestContext(e, req)
+	setPathParams(c, map[string]string{"id": "123"})
+
+	userAgent := "Mozilla/5.0 (Folder)"
+	translate := true
+	mockService.EXPECT().
+		UpdateFeedDefaults(gomock.Any(), int64(123), model.FeedDefaults{UserAgent: &userAgent, AutoTranslate: &translate}).
+		Return(model.Folder{ID: 123, Name: "Japanese blogs", FeedDefaults: model.FeedDefaults{UserAgent: &userAgent, AutoTranslate: &translate}}, nil)
+
+	require.NoError(t, h.UpdateFeedDefaults(c))
+	var resp handler.FolderResponse
+	assertJSONResponse(t, rec, http.StatusOK, &resp)
+	require.Equal(t, "123", resp.ID)
+	require.Equal(t, userAgent, *resp.FeedDefaults.UserAgent)
+	require.Nil(t, resp.FeedDefaults.AutoReadability)
+	require.True(t, *resp.FeedDefaults.AutoTranslate)
+
+	req = newJSONRequest(http.MethodPut, "/folders/123/feed-defaults", map[string]interface{}{"userAgent": "bad\nagent"})
+	c, rec = newTestContext(e, req)
+	setPathParams(c, map[string]string{"id": "123"})
+	mockService.EXPECT().UpdateFeedDefaults(gomock.Any(), int64(123), gomock.Any()).Return(model.Folder{}, service.ErrInvalid)
+	require.NoError(t, h.UpdateFeedDefaults(c))
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
 func TestFolderHandler_DeleteBatch_Success(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -179,3 +292,81 @@ func TestFolderHandler_DeleteBatch_Success(t *testing.T) {
 
 	require.Equal(t, http.StatusNoContent, rec.Code)
 }
+
+func TestFolderHandler_Reorder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockFolderService(ctrl)
+	h := handler.NewFolderHandlerHelper(mockService)
+	e := newTestEcho()
+
+	req := newJSONRequest(http.MethodPut, "/folders/reorder", map[string]interface{}{"ids": []string{"2", "1"}})
+	c, rec := newTestContext(e, req)
+	mockService.EXPECT().Reorder(gomock.Any(), []int64{2, 1}).Return(nil)
+	require.NoError(t, h.Reorder(c))
+	require.Equal(t, http.StatusNoContent, rec.Code)
+
+	req = newJSONRequest(http.MethodPut, "/folders/reorder", map[string]interface{}{"ids": []string{"1", "3"}})
+	c, rec = newTestContext(e, req)
+	mockService.EXPECT().Reorder(gomock.Any(), []int64{1, 3}).Return(service.ErrInvalid)
+	require.NoError(t, h.Reorder(c))
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+
+	req = newJSONRequest(http.MethodPut, "/folders/reorder", map[string]interface{}{"ids": []string{"x"}})
+	c, rec = newTestContext(e, req)
+	require.NoError(t, h.Reorder(c))
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestFolderHandler_Rules(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockFolderService(ctrl)
+	h := handler.NewFolderHandlerHelper(mockService)
+	e := newTestEcho()
+
+	req := newJSONRequest(http.MethodPost, "/folders/1/rules", map[string]interface{}{"pattern": "reddit.com", "matchField": "host", "priority": 2})
+	c, rec := newTestContext(e, req)
+	setPathParams(c, map[string]string{"id": "1"})
+	mockService.EXPECT().
+		CreateRule(gomock.Any(), model.FolderRule{FolderID: 1, Pattern: "reddit.com", MatchField: "host", Priority: 2}).
+		Return(model.FolderRule{ID: 10, FolderID: 1, Pattern: "reddit.com", MatchField: "host", Priority: 2}, nil)
+	require.NoError(t, h.CreateRule(c))
+	var created handler.FolderRuleResponse
+	assertJSONResponse(t, rec, http.StatusCreated, &created)
+	require.Equal(t, "10", created.ID)
+	require.Equal(t, "1", created.FolderID)
+
+	req = newJSONRequest(http.MethodGet, "/folders/1/rules", nil)
+	c, rec = newTestContext(e, req)
+	setPathParams(c, map[string]string{"id": "1"})
+	mockService.EXPECT().ListRules(gomock.Any(), int64(1)).Return([]model.FolderRule{{ID: 10, FolderID: 1}}, nil)
+	require.NoError(t, h.ListRules(c))
+	var rules []handler.FolderRuleResponse
+	assertJSONResponse(t, rec, http.StatusOK, &rules)
+	require.Len(t, rules, 1)
+
+	req = newJSONRequest(http.MethodPut, "/folders/1/rules/10", map[string]interface{}{"pattern": "/(/", "matchField": "title"})
+	c, rec = newTestContext(e, req)
+	setPathParams(c, map[string]string{"id": "1", "ruleId": "10"})
+	mockService.EXPECT().UpdateRule(gomock.Any(), gomock.Any()).Return(model.FolderRule{}, service.ErrInvalid)
+	require.NoError(t, h.UpdateRule(c))
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+
+	req = newJSONRequest(http.MethodDelete, "/folders/1/rules/11", nil)
+	c, rec = newTestContext(e, req)
+	setPathParams(c, map[string]string{"id": "1", "ruleId": "11"})
+	mockService.EXPECT().DeleteRule(gomock.Any(), int64(1), int64(11)).Return(service.ErrNotFound)
+	require.NoError(t, h.DeleteRule(c))
+	require.Equal(t, http.StatusNotFound, rec.Code)
+
+	req = newJSONRequest(http.MethodPost, "/folders/rules/apply", nil)
+	c, rec = newTestContext(e, req)
+	mockService.EXPECT().ApplyRules(gomock.Any()).Return(3, nil)
+	require.NoError(t, h.ApplyRules(c))
+	var applied handler.ApplyFolderRulesResponse
+	assertJSONResponse(t, rec, http.StatusOK, &applied)
+	require.Equal(t, 3, applied.Moved)
+}
@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"gist/backend/internal/service"
+	"gist/backend/pkg/logger"
+)
+
+type HealthHandler struct {
+	service service.HealthService
+}
+
+// Request/Response types
+
+type healthResponse struct {
+	Status    string `json:"status"`
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildTime string `json:"buildTime"`
+}
+
+type readinessCheckResponse struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+type readinessWarningResponse struct {
+	Name    string `json:"name"`
+	Message string `json:"message"`
+}
+
+type readinessResponse struct {
+	Status string                   `json:"status"`
+	Checks []readinessCheckResponse `json:"checks"`
+	// Warnings don't affect readiness, e.g. feeds flagged for suspect caching upstream or unstable
+	Warnings []readinessWarningResponse `json:"warnings,omitempty"`
+}
+
+func NewHealthHandler(svc service.HealthService) *HealthHandler {
+	return &HealthHandler{service: svc}
+}
+
+// RegisterRoutes registers probe routes at the root, outside the authenticated /api group.
+func (h *HealthHandler) RegisterRoutes(e *echo.Echo) {
+	e.GET("/healthz", h.Healthz)
+	e.GET("/readyz", h.Readyz)
+}
+
+// Healthz reports that the process is up.
+// @Summary Liveness probe
+// @Description Returns build information. Does not touch the database.
+// @Tags health
+// @Produce json
+// @Success 200 {object} healthResponse
+// @Router /healthz [get]
+func (h *HealthHandler) Healthz(c echo.Context) error {
+	info := h.service.BuildInfo()
+	return c.JSON(http.StatusOK, healthResponse{
+		Status:    "ok",
+		Version:   info.Version,
+		Commit:    info.Commit,
+		BuildTime: info.BuildTime,
+	})
+}
+
+// Readyz reports whether the server can serve traffic.
+// @Summary Readiness probe
+// @Description Checks the database connection, data directory writability and migration state. Warnings, such as feeds flagged for suspect caching upstream or unstable over their recent refreshes, don't affect readiness.
+// @Tags health
+// @Produce json
+// @Success 200 {object} readinessResponse
+// @Failure 503 {object} readinessResponse
+// @Router /readyz [get]
+func (h *HealthHandler) Readyz(c echo.Context) error {
+	report := h.service.Ready(c.Request().Context())
+
+	checks := make([]readinessCheckResponse, len(report.Checks))
+	for i, check := range report.Checks {
+		status := "ok"
+		if !check.OK {
+			status = "failed"
+			logger.Warn("readiness check failed", "module", "handler", "action", "check", "resource", "health", "result", "failed", "check", check.Name, "error", check.Error)
+		}
+		checks[i] = readinessCheckResponse{Name: check.Name, Status: status, Error: check.Error}
+	}
+	var warnings []readinessWarningResponse
+	for _, warning := range report.Warnings {
+		warnings = append(warnings, readinessWarningResponse{Name: warning.Name, Message: warning.Message})
+	}
+
+	if !report.Ready {
+		return c.JSON(http.StatusServiceUnavailable, readinessResponse{Status: "unavailable", Checks: checks, Warnings: warnings})
+	}
+	return c.JSON(http.StatusOK, readinessResponse{Status: "ready", Checks: checks, Warnings: warnings})
+}
@@ -0,0 +1,123 @@
+package handler_test
+
+import (
+	"net/http"
+	"testing"
+
+	"gist/backend/internal/handler"
+	"gist/backend/internal/service"
+	"gist/backend/internal/service/mock"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestHealthHandler_Healthz(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockHealthService(ctrl)
+	h := handler.NewHealthHandlerHelper(mockService)
+
+	e := newTestEcho()
+	req := newJSONRequest(http.MethodGet, "/healthz", nil)
+	c, rec := newTestContext(e, req)
+
+	mockService.EXPECT().BuildInfo().Return(service.BuildInfo{Version: "1.2.3", Commit: "abc123", BuildTime: "2026-01-01T00:00:00Z"})
+
+	err := h.Healthz(c)
+	require.NoError(t, err)
+
+	var resp handler.HealthResponse
+	assertJSONResponse(t, rec, http.StatusOK, &resp)
+	require.Equal(t, "ok", resp.Status)
+	require.Equal(t, "1.2.3", resp.Version)
+	require.Equal(t, "abc123", resp.Commit)
+}
+
+func TestHealthHandler_Readyz_Ready(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockHealthService(ctrl)
+	h := handler.NewHealthHandlerHelper(mockService)
+
+	e := newTestEcho()
+	req := newJSONRequest(http.MethodGet, "/readyz", nil)
+	c, rec := newTestContext(e, req)
+
+	mockService.EXPECT().Ready(gomock.Any()).Return(service.ReadinessReport{
+		Ready: true,
+		Checks: []service.HealthCheck{
+			{Name: service.HealthCheckDatabase, OK: true},
+			{Name: service.HealthCheckDataDir, OK: true},
+			{Name: service.HealthCheckMigration, OK: true},
+		},
+	})
+
+	err := h.Readyz(c)
+	require.NoError(t, err)
+
+	var resp handler.ReadinessResponse
+	assertJSONResponse(t, rec, http.StatusOK, &resp)
+	require.Equal(t, "ready", resp.Status)
+	require.Len(t, resp.Checks, 3)
+	require.Equal(t, "ok", resp.Checks[0].Status)
+}
+
+func TestHealthHandler_Readyz_Warnings(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockHealthService(ctrl)
+	h := handler.NewHealthHandlerHelper(mockService)
+
+	e := newTestEcho()
+	req := newJSONRequest(http.MethodGet, "/readyz", nil)
+	c, rec := newTestContext(e, req)
+
+	mockService.EXPECT().Ready(gomock.Any()).Return(service.ReadinessReport{
+		Ready:    true,
+		Checks:   []service.HealthCheck{{Name: service.HealthCheckDatabase, OK: true}},
+		Warnings: []service.HealthWarning{{Name: service.HealthCheckFeedCaching, Message: "suspect caching upstream on 2 feeds"}},
+	})
+
+	err := h.Readyz(c)
+	require.NoError(t, err)
+
+	var resp handler.ReadinessResponse
+	assertJSONResponse(t, rec, http.StatusOK, &resp)
+	require.Equal(t, "ready", resp.Status)
+	require.Len(t, resp.Warnings, 1)
+	require.Equal(t, service.HealthCheckFeedCaching, resp.Warnings[0].Name)
+	require.Equal(t, "suspect caching upstream on 2 feeds", resp.Warnings[0].Message)
+}
+
+func TestHealthHandler_Readyz_Unavailable(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockHealthService(ctrl)
+	h := handler.NewHealthHandlerHelper(mockService)
+
+	e := newTestEcho()
+	req := newJSONRequest(http.MethodGet, "/readyz", nil)
+	c, rec := newTestContext(e, req)
+
+	mockService.EXPECT().Ready(gomock.Any()).Return(service.ReadinessReport{
+		Checks: []service.HealthCheck{
+			{Name: service.HealthCheckDatabase, OK: true},
+			{Name: service.HealthCheckDataDir, Error: "data dir not writable: permission denied"},
+			{Name: service.HealthCheckMigration, OK: true},
+		},
+	})
+
+	err := h.Readyz(c)
+	require.NoError(t, err)
+
+	var resp handler.ReadinessResponse
+	assertJSONResponse(t, rec, http.StatusServiceUnavailable, &resp)
+	require.Equal(t, "unavailable", resp.Status)
+	require.Equal(t, "failed", resp.Checks[1].Status)
+	require.Equal(t, "data dir not writable: permission denied", resp.Checks[1].Error)
+}
@@ -1,9 +1,12 @@
 package handler
 
 import (
+	"fmt"
 	"net/http"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 
 	"github.com/labstack/echo/v4"
 
@@ -33,27 +36,49 @@ func (h *IconHandler) RegisterAPIRoutes(g *echo.Group) {
 	g.DELETE("/icons/cache", h.ClearIconCache)
 }
 
+// iconCacheControl lets browsers keep icons for a week; the ETag makes revalidation cheap.
+const iconCacheControl = "public, max-age=604800"
+
 // GetIcon serves icon files.
 // Icons are named by domain (e.g., "example.com.png"), not by feed ID.
+// The optional size query scales raster icons down so their longer edge fits.
 func (h *IconHandler) GetIcon(c echo.Context) error {
 	filename := c.Param("filename")
 	if filename == "" {
 		return c.NoContent(http.StatusNotFound)
 	}
 
-	// Sanitize filename to prevent path traversal
-	filename = filepath.Base(filename)
-	fullPath := h.iconService.GetIconPath(filename)
+	size := 0
+	if raw := c.QueryParam("size"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 || parsed > service.MaxIconSize {
+			return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid size")
+		}
+		size = parsed
+	}
 
-	// Check if file exists
-	if _, err := os.Stat(fullPath); err == nil {
-		logger.Debug("icon served", "module", "handler", "action", "fetch", "resource", "icon", "result", "ok", "filename", filename)
-		return c.File(fullPath)
+	// The service rejects traversal; neither case reveals where icons live
+	fullPath, err := h.iconService.ResolveIcon(filename, size)
+	if err != nil {
+		logger.Debug("icon not found", "module", "handler", "action", "fetch", "resource", "icon", "result", "failed", "filename", filename)
+		// Icon not found - frontend will show fallback
+		return c.NoContent(http.StatusNotFound)
+	}
+	info, err := os.Stat(fullPath)
+	if err != nil {
+		return c.NoContent(http.StatusNotFound)
 	}
 
-	logger.Debug("icon not found", "module", "handler", "action", "fetch", "resource", "icon", "result", "failed", "filename", filename)
-	// Icon not found - frontend will show fallback
-	return c.NoContent(http.StatusNotFound)
+	header := c.Response().Header()
+	header.Set("Cache-Control", iconCacheControl)
+	header.Set("ETag", fmt.Sprintf(`"%x-%x"`, info.ModTime().UnixNano(), info.Size()))
+	header.Set("X-Content-Type-Options", "nosniff")
+	if strings.EqualFold(filepath.Ext(fullPath), ".svg") {
+		header.Set("Content-Security-Policy", svgContentSecurityPolicy)
+	}
+	logger.Debug("icon served", "module", "handler", "action", "fetch", "resource", "icon", "result", "ok", "filename", filename, "size", size)
+	// ServeContent answers If-None-Match against the ETag with 304
+	return c.File(fullPath)
 }
 
 // ClearIconCache deletes all icon files and clears icon_path in database.
@@ -68,7 +93,7 @@ func (h *IconHandler) ClearIconCache(c echo.Context) error {
 	deleted, err := h.iconService.ClearAllIcons(c.Request().Context())
 	if err != nil {
 		logger.Error("icon cache clear failed", "module", "handler", "action", "clear", "resource", "icon", "result", "failed", "error", err)
-		return c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return writeServiceError(c, err)
 	}
 
 	logger.Info("icon cache cleared", "module", "handler", "action", "clear", "resource", "icon", "result", "ok", "count", deleted)
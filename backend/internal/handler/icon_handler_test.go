@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	"gist/backend/internal/handler"
+	"gist/backend/internal/service"
 	"gist/backend/internal/service/mock"
 
 	"github.com/stretchr/testify/require"
@@ -31,8 +32,8 @@ func TestIconHandler_GetIcon_FileExists(t *testing.T) {
 	require.NoError(t, os.WriteFile(fullPath, []byte("icon-data"), 0o600))
 
 	mockService.EXPECT().
-		GetIconPath(filename).
-		Return(fullPath)
+		ResolveIcon(filename, 0).
+		Return(fullPath, nil)
 
 	e := newTestEcho()
 	req := newJSONRequest(http.MethodGet, "/icons/"+filename, nil)
@@ -43,27 +44,107 @@ func TestIconHandler_GetIcon_FileExists(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, http.StatusOK, rec.Code)
 	require.Contains(t, rec.Body.String(), "icon-data")
+	require.Equal(t, "public, max-age=604800", rec.Header().Get("Cache-Control"))
+	require.NotEmpty(t, rec.Header().Get("ETag"))
+	require.Equal(t, "nosniff", rec.Header().Get("X-Content-Type-Options"))
+	require.Empty(t, rec.Header().Get("Content-Security-Policy"))
 }
 
-func TestIconHandler_GetIcon_NotFound(t *testing.T) {
+func TestIconHandler_GetIcon_SVGSandboxed(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockService := mock.NewMockIconService(ctrl)
 	h := handler.NewIconHandlerHelper(mockService)
 
+	filename := "example.com.svg"
+	fullPath := filepath.Join(t.TempDir(), filename)
+	require.NoError(t, os.WriteFile(fullPath, []byte(`<svg xmlns="http://www.w3.org/2000/svg"/>`), 0o600))
+
 	mockService.EXPECT().
-		GetIconPath("missing.png").
-		Return(filepath.Join(t.TempDir(), "missing.png"))
+		ResolveIcon(filename, 0).
+		Return(fullPath, nil)
 
 	e := newTestEcho()
-	req := newJSONRequest(http.MethodGet, "/icons/missing.png", nil)
+	req := newJSONRequest(http.MethodGet, "/icons/"+filename, nil)
 	c, rec := newTestContext(e, req)
-	setPathParams(c, map[string]string{"filename": "missing.png"})
+	setPathParams(c, map[string]string{"filename": filename})
 
 	err := h.GetIcon(c)
 	require.NoError(t, err)
-	require.Equal(t, http.StatusNotFound, rec.Code)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, "nosniff", rec.Header().Get("X-Content-Type-Options"))
+	require.Contains(t, rec.Header().Get("Content-Security-Policy"), "sandbox")
+	require.Contains(t, rec.Header().Get("Content-Security-Policy"), "script-src 'none'")
+}
+
+func TestIconHandler_GetIcon_Revalidate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockIconService(ctrl)
+	h := handler.NewIconHandlerHelper(mockService)
+
+	fullPath := filepath.Join(t.TempDir(), "icon@32.png")
+	require.NoError(t, os.WriteFile(fullPath, []byte("icon-data"), 0o600))
+	mockService.EXPECT().ResolveIcon("icon.png", 32).Return(fullPath, nil).Times(2)
+
+	e := newTestEcho()
+	req := newJSONRequest(http.MethodGet, "/icons/icon.png?size=32", nil)
+	c, rec := newTestContext(e, req)
+	setPathParams(c, map[string]string{"filename": "icon.png"})
+	require.NoError(t, h.GetIcon(c))
+	etag := rec.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+
+	req = newJSONRequest(http.MethodGet, "/icons/icon.png?size=32", nil)
+	req.Header.Set("If-None-Match", etag)
+	c, rec = newTestContext(e, req)
+	setPathParams(c, map[string]string{"filename": "icon.png"})
+	require.NoError(t, h.GetIcon(c))
+	require.Equal(t, http.StatusNotModified, rec.Code)
+	require.Empty(t, rec.Body.String())
+}
+
+func TestIconHandler_GetIcon_InvalidSize(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	h := handler.NewIconHandlerHelper(mock.NewMockIconService(ctrl))
+	e := newTestEcho()
+
+	for _, size := range []string{"abc", "0", "-1", "4096"} {
+		req := newJSONRequest(http.MethodGet, "/icons/icon.png?size="+size, nil)
+		c, rec := newTestContext(e, req)
+		setPathParams(c, map[string]string{"filename": "icon.png"})
+
+		require.NoError(t, h.GetIcon(c))
+		require.Equal(t, http.StatusBadRequest, rec.Code, size)
+	}
+}
+
+func TestIconHandler_GetIcon_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockIconService(ctrl)
+	h := handler.NewIconHandlerHelper(mockService)
+
+	for _, filename := range []string{"missing.png", "../../etc/passwd"} {
+		mockService.EXPECT().
+			ResolveIcon(filename, 0).
+			Return("", service.ErrNotFound)
+
+		e := newTestEcho()
+		req := newJSONRequest(http.MethodGet, "/icons/missing.png", nil)
+		c, rec := newTestContext(e, req)
+		setPathParams(c, map[string]string{"filename": filename})
+
+		err := h.GetIcon(c)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusNotFound, rec.Code)
+		require.Empty(t, rec.Body.String())
+	}
 }
 
 func TestIconHandler_GetIcon_EmptyFilename(t *testing.T) {
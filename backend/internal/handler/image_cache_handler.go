@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"net/http"
+	"os"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"gist/backend/internal/service"
+	"gist/backend/pkg/logger"
+)
+
+type ImageCacheHandler struct {
+	service service.ImageCacheService
+}
+
+func NewImageCacheHandler(imageCacheService service.ImageCacheService) *ImageCacheHandler {
+	return &ImageCacheHandler{service: imageCacheService}
+}
+
+// RegisterRoutes serves cached images at the root, like icons, so <img> tags can load them.
+func (h *ImageCacheHandler) RegisterRoutes(e *echo.Echo) {
+	e.GET("/cached-images/:entryId/:filename", h.GetCachedImage)
+}
+
+// GetCachedImage serves a locally cached entry image.
+func (h *ImageCacheHandler) GetCachedImage(c echo.Context) error {
+	entryID, err := strconv.ParseInt(c.Param("entryId"), 10, 64)
+	if err != nil {
+		return c.NoContent(http.StatusNotFound)
+	}
+	filename := c.Param("filename")
+
+	// GetImagePath rejects anything that isn't a cache file name
+	fullPath := h.service.GetImagePath(entryID, filename)
+	if fullPath == "" {
+		return c.NoContent(http.StatusNotFound)
+	}
+	if _, err := os.Stat(fullPath); err != nil {
+		logger.Debug("cached image not found", "module", "handler", "action", "fetch", "resource", "image", "result", "failed", "entry_id", entryID, "filename", filename)
+		return c.NoContent(http.StatusNotFound)
+	}
+
+	// File names are content-addressed by URL, so the response never changes
+	c.Response().Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+	c.Response().Header().Set("X-Content-Type-Options", "nosniff")
+	return c.File(fullPath)
+}
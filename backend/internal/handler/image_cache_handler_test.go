@@ -0,0 +1,84 @@
+package handler_test
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gist/backend/internal/handler"
+	"gist/backend/internal/service/mock"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestImageCacheHandler_GetCachedImage_FileExists(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockImageCacheService(ctrl)
+	h := handler.NewImageCacheHandlerHelper(mockService)
+
+	filename := "0123456789abcdef.png"
+	fullPath := filepath.Join(t.TempDir(), filename)
+	require.NoError(t, os.WriteFile(fullPath, []byte("image-data"), 0o600))
+
+	mockService.EXPECT().GetImagePath(int64(42), filename).Return(fullPath)
+
+	e := newTestEcho()
+	req := newJSONRequest(http.MethodGet, "/cached-images/42/"+filename, nil)
+	c, rec := newTestContext(e, req)
+	setPathParams(c, map[string]string{"entryId": "42", "filename": filename})
+
+	err := h.GetCachedImage(c)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, "image-data", rec.Body.String())
+	require.Contains(t, rec.Header().Get("Cache-Control"), "immutable")
+	require.Equal(t, "nosniff", rec.Header().Get("X-Content-Type-Options"))
+}
+
+func TestImageCacheHandler_GetCachedImage_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockImageCacheService(ctrl)
+	h := handler.NewImageCacheHandlerHelper(mockService)
+
+	mockService.EXPECT().
+		GetImagePath(int64(42), "0123456789abcdef.png").
+		Return(filepath.Join(t.TempDir(), "0123456789abcdef.png"))
+
+	e := newTestEcho()
+	req := newJSONRequest(http.MethodGet, "/cached-images/42/0123456789abcdef.png", nil)
+	c, rec := newTestContext(e, req)
+	setPathParams(c, map[string]string{"entryId": "42", "filename": "0123456789abcdef.png"})
+
+	err := h.GetCachedImage(c)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestImageCacheHandler_GetCachedImage_InvalidParams(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockImageCacheService(ctrl)
+	h := handler.NewImageCacheHandlerHelper(mockService)
+	mockService.EXPECT().GetImagePath(int64(42), "..").Return("")
+
+	e := newTestEcho()
+	for _, params := range []map[string]string{
+		{"entryId": "abc", "filename": "0123456789abcdef.png"},
+		{"entryId": "42", "filename": ".."},
+	} {
+		req := newJSONRequest(http.MethodGet, "/cached-images/", nil)
+		c, rec := newTestContext(e, req)
+		setPathParams(c, params)
+
+		err := h.GetCachedImage(c)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusNotFound, rec.Code)
+	}
+}
@@ -0,0 +1,179 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"gist/backend/internal/service"
+	"gist/backend/pkg/logger"
+)
+
+type MaintenanceHandler struct {
+	service service.MaintenanceService
+}
+
+type maintenanceRequest struct {
+	Action string `json:"action" example:"normalize_dates"`
+}
+
+type maintenanceResponse struct {
+	Action  string `json:"action"`
+	Updated int    `json:"updated"`
+	Skipped int    `json:"skipped"`
+}
+
+type storageDirectoryResponse struct {
+	Name    string `json:"name" example:"icons"`
+	Present bool   `json:"present"`
+	Files   int64  `json:"files"`
+	Bytes   int64  `json:"bytes"`
+}
+
+type storageTableResponse struct {
+	Name  string   `json:"name" example:"entries"`
+	Rows  int64    `json:"rows"`
+	Bytes *int64   `json:"bytes,omitempty"`
+	Share *float64 `json:"share,omitempty"`
+}
+
+type storageResponse struct {
+	DatabaseBytes       int64                      `json:"databaseBytes"`
+	WALBytes            int64                      `json:"walBytes"`
+	SHMBytes            int64                      `json:"shmBytes"`
+	Directories         []storageDirectoryResponse `json:"directories"`
+	Tables              []storageTableResponse     `json:"tables"`
+	TableBytesAvailable bool                       `json:"tableBytesAvailable"`
+}
+
+type compactRequest struct {
+	Vacuum bool `json:"vacuum"`
+	// Confirm must be set with vacuum, which locks the database while it rebuilds it.
+	Confirm bool `json:"confirm"`
+}
+
+type compactResponse struct {
+	Checkpointed bool  `json:"checkpointed"`
+	Vacuumed     bool  `json:"vacuumed"`
+	BytesBefore  int64 `json:"bytesBefore"`
+	BytesAfter   int64 `json:"bytesAfter"`
+}
+
+func NewMaintenanceHandler(svc service.MaintenanceService) *MaintenanceHandler {
+	return &MaintenanceHandler{service: svc}
+}
+
+func (h *MaintenanceHandler) RegisterRoutes(g *echo.Group) {
+	g.POST("/admin/maintenance", h.Run)
+	g.GET("/admin/storage", h.Storage)
+	g.POST("/admin/storage/compact", h.Compact)
+}
+
+// Run executes a one-off maintenance action.
+// @Summary Run maintenance action
+// @Description Run a one-off data maintenance action. normalize_dates rewrites stored published dates without zone info as UTC. canonicalize_urls fills in the canonical URL of entries saved before it was kept, which are otherwise set when saved again or visited. index_search makes the readable content and AI summaries of entries fetched or summarized earlier searchable.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body maintenanceRequest true "Maintenance action"
+// @Success 200 {object} maintenanceResponse
+// @Failure 400 {object} errorResponse
+// @Router /admin/maintenance [post]
+func (h *MaintenanceHandler) Run(c echo.Context) error {
+	var req maintenanceRequest
+	if err := c.Bind(&req); err != nil {
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid request")
+	}
+
+	result, err := h.service.Run(c.Request().Context(), req.Action)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalid) {
+			return Error(c, http.StatusBadRequest, CodeInvalidRequest, "unknown action")
+		}
+		logger.Error("maintenance failed", "module", "handler", "action", "update", "resource", "maintenance", "result", "failed", "maintenance_action", req.Action, "error", err)
+		return writeServiceError(c, err)
+	}
+
+	logger.Info("maintenance done", "module", "handler", "action", "update", "resource", "maintenance", "result", "ok", "maintenance_action", result.Action, "updated", result.Updated, "skipped", result.Skipped)
+	return c.JSON(http.StatusOK, maintenanceResponse{
+		Action:  result.Action,
+		Updated: result.Updated,
+		Skipped: result.Skipped,
+	})
+}
+
+// Storage reports disk usage.
+// @Summary Get storage usage
+// @Description Report the size of the database and its WAL, the cache directories under the data directory, and row counts of the largest tables. Table sizes are only given when SQLite has the dbstat table.
+// @Tags admin
+// @Produce json
+// @Success 200 {object} storageResponse
+// @Failure 500 {object} errorResponse
+// @Router /admin/storage [get]
+func (h *MaintenanceHandler) Storage(c echo.Context) error {
+	report, err := h.service.Storage(c.Request().Context())
+	if err != nil {
+		return writeServiceError(c, err)
+	}
+
+	resp := storageResponse{
+		DatabaseBytes:       report.DatabaseBytes,
+		WALBytes:            report.WALBytes,
+		SHMBytes:            report.SHMBytes,
+		Directories:         make([]storageDirectoryResponse, 0, len(report.Directories)),
+		Tables:              make([]storageTableResponse, 0, len(report.Tables)),
+		TableBytesAvailable: report.TableBytesAvailable,
+	}
+	for _, dir := range report.Directories {
+		resp.Directories = append(resp.Directories, storageDirectoryResponse{
+			Name:    dir.Name,
+			Present: dir.Present,
+			Files:   dir.Files,
+			Bytes:   dir.Bytes,
+		})
+	}
+	for _, table := range report.Tables {
+		resp.Tables = append(resp.Tables, storageTableResponse{
+			Name:  table.Name,
+			Rows:  table.Rows,
+			Bytes: table.Bytes,
+			Share: table.Share,
+		})
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// Compact checkpoints the WAL and optionally vacuums the database.
+// @Summary Compact database
+// @Description Checkpoint the WAL into the database and truncate it. With vacuum, also rebuild the database file to give free space back; this blocks all writes while it runs and requires confirm.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body compactRequest false "Compaction options"
+// @Success 200 {object} compactResponse
+// @Failure 400 {object} errorResponse
+// @Failure 409 {object} errorResponse
+// @Router /admin/storage/compact [post]
+func (h *MaintenanceHandler) Compact(c echo.Context) error {
+	var req compactRequest
+	if err := c.Bind(&req); err != nil {
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid request")
+	}
+	if req.Vacuum && !req.Confirm {
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "vacuum requires confirm")
+	}
+
+	result, err := h.service.Compact(c.Request().Context(), req.Vacuum)
+	if err != nil {
+		return writeServiceError(c, err)
+	}
+
+	logger.Info("storage compacted", "module", "handler", "action", "update", "resource", "storage", "result", "ok", "vacuum", result.Vacuumed, "bytes_before", result.BytesBefore, "bytes_after", result.BytesAfter)
+	return c.JSON(http.StatusOK, compactResponse{
+		Checkpointed: result.Checkpointed,
+		Vacuumed:     result.Vacuumed,
+		BytesBefore:  result.BytesBefore,
+		BytesAfter:   result.BytesAfter,
+	})
+}
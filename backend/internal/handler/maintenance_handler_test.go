@@ -0,0 +1,121 @@
+package handler_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"gist/backend/internal/handler"
+	"gist/backend/internal/service"
+	"gist/backend/internal/service/mock"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestMaintenanceHandler_Run(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockService := mock.NewMockMaintenanceService(ctrl)
+	h := handler.NewMaintenanceHandler(mockService)
+
+	e := newTestEcho()
+	req := newJSONRequest(http.MethodPost, "/admin/maintenance", map[string]string{"action": "normalize_dates"})
+	c, rec := newTestContext(e, req)
+
+	mockService.EXPECT().
+		Run(gomock.Any(), "normalize_dates").
+		Return(service.MaintenanceResult{Action: "normalize_dates", Updated: 3, Skipped: 1}, nil)
+
+	require.NoError(t, h.Run(c))
+
+	var resp handler.MaintenanceResponse
+	assertJSONResponse(t, rec, http.StatusOK, &resp)
+	require.Equal(t, handler.MaintenanceResponse{Action: "normalize_dates", Updated: 3, Skipped: 1}, resp)
+}
+
+func TestMaintenanceHandler_Run_Errors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockService := mock.NewMockMaintenanceService(ctrl)
+	h := handler.NewMaintenanceHandler(mockService)
+	e := newTestEcho()
+
+	for _, tc := range []struct {
+		name       string
+		err        error
+		wantStatus int
+	}{
+		{name: "unknown action", err: service.ErrInvalid, wantStatus: http.StatusBadRequest},
+		{name: "failure", err: errors.New("db down"), wantStatus: http.StatusInternalServerError},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			req := newJSONRequest(http.MethodPost, "/admin/maintenance", map[string]string{"action": "x"})
+			c, rec := newTestContext(e, req)
+			mockService.EXPECT().Run(gomock.Any(), "x").Return(service.MaintenanceResult{}, tc.err)
+
+			require.NoError(t, h.Run(c))
+			require.Equal(t, tc.wantStatus, rec.Code)
+		})
+	}
+}
+
+func TestMaintenanceHandler_Storage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockService := mock.NewMockMaintenanceService(ctrl)
+	h := handler.NewMaintenanceHandler(mockService)
+
+	e := newTestEcho()
+	req := newJSONRequest(http.MethodGet, "/admin/storage", nil)
+	c, rec := newTestContext(e, req)
+
+	entryBytes := int64(400)
+	share := 0.4
+	mockService.EXPECT().Storage(gomock.Any()).Return(service.StorageReport{
+		DatabaseBytes:       1000,
+		WALBytes:            20,
+		Directories:         []service.StorageDirectory{{Name: "icons", Present: true, Files: 2, Bytes: 12}},
+		Tables:              []service.StorageTable{{Name: "entries", Rows: 3, Bytes: &entryBytes, Share: &share}},
+		TableBytesAvailable: true,
+	}, nil)
+
+	require.NoError(t, h.Storage(c))
+
+	var resp handler.StorageResponse
+	assertJSONResponse(t, rec, http.StatusOK, &resp)
+	require.Equal(t, int64(1000), resp.DatabaseBytes)
+	require.Equal(t, int64(20), resp.WALBytes)
+	require.True(t, resp.TableBytesAvailable)
+	require.Len(t, resp.Directories, 1)
+	require.Equal(t, int64(2), resp.Directories[0].Files)
+	require.Len(t, resp.Tables, 1)
+	require.Equal(t, entryBytes, *resp.Tables[0].Bytes)
+	require.InDelta(t, share, *resp.Tables[0].Share, 1e-9)
+}
+
+func TestMaintenanceHandler_Compact(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockService := mock.NewMockMaintenanceService(ctrl)
+	h := handler.NewMaintenanceHandler(mockService)
+	e := newTestEcho()
+
+	// Vacuum without confirmation never reaches the service
+	req := newJSONRequest(http.MethodPost, "/admin/storage/compact", map[string]bool{"vacuum": true})
+	c, rec := newTestContext(e, req)
+	require.NoError(t, h.Compact(c))
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+
+	req = newJSONRequest(http.MethodPost, "/admin/storage/compact", map[string]bool{"vacuum": true, "confirm": true})
+	c, rec = newTestContext(e, req)
+	mockService.EXPECT().Compact(gomock.Any(), true).
+		Return(service.CompactResult{Checkpointed: true, Vacuumed: true, BytesBefore: 2000, BytesAfter: 1200}, nil)
+	require.NoError(t, h.Compact(c))
+
+	var resp handler.CompactResponse
+	assertJSONResponse(t, rec, http.StatusOK, &resp)
+	require.Equal(t, handler.CompactResponse{Checkpointed: true, Vacuumed: true, BytesBefore: 2000, BytesAfter: 1200}, resp)
+
+	req = newJSONRequest(http.MethodPost, "/admin/storage/compact", map[string]bool{})
+	c, rec = newTestContext(e, req)
+	mockService.EXPECT().Compact(gomock.Any(), false).Return(service.CompactResult{}, service.ErrMaintenanceRunning)
+	require.NoError(t, h.Compact(c))
+	require.Equal(t, http.StatusConflict, rec.Code)
+}
@@ -44,6 +44,7 @@ func (h *OPMLHandler) RegisterRoutes(g *echo.Group) {
 // @Accept xml
 // @Produce json
 // @Param file formData file false "OPML file to import"
+// @Param initialBackfill query string false "Items each new feed brings in on its first refresh: all (default), none (saved as read), or a count of the most recent items"
 // @Success 200 {object} importStartedResponse
 // @Failure 400 {object} errorResponse
 // @Failure 413 {object} errorResponse
@@ -53,43 +54,52 @@ func (h *OPMLHandler) Import(c echo.Context) error {
 	req.Body = http.MaxBytesReader(c.Response().Writer, req.Body, maxOPMLSize)
 
 	var reader io.Reader
+	backfillValue := c.QueryParam("initialBackfill")
 	contentType := req.Header.Get("Content-Type")
 	if strings.HasPrefix(contentType, "multipart/") {
 		file, err := c.FormFile("file")
 		if err != nil {
 			if err == http.ErrMissingFile {
-				return c.JSON(http.StatusBadRequest, errorResponse{Error: "missing file"})
+				return Error(c, http.StatusBadRequest, CodeInvalidRequest, "missing file")
 			}
-			return c.JSON(http.StatusBadRequest, errorResponse{Error: "invalid request"})
+			return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid request")
 		}
 		if file.Size > maxOPMLSize {
-			return c.JSON(http.StatusRequestEntityTooLarge, errorResponse{Error: "file too large"})
+			return Error(c, http.StatusRequestEntityTooLarge, CodePayloadTooLarge, "file too large")
 		}
 		src, err := file.Open()
 		if err != nil {
-			return c.JSON(http.StatusBadRequest, errorResponse{Error: "invalid request"})
+			return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid request")
 		}
 		defer src.Close()
 		reader = io.LimitReader(src, maxOPMLSize)
+		if value := c.FormValue("initialBackfill"); value != "" {
+			backfillValue = value
+		}
 	} else {
 		reader = io.LimitReader(req.Body, maxOPMLSize)
 	}
 
+	backfill, err := service.ParseInitialBackfill(backfillValue)
+	if err != nil {
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "initialBackfill must be all, none, or a positive number")
+	}
+
 	// Read file content into memory for background processing
 	content, err := io.ReadAll(reader)
 	if err != nil {
 		logger.Warn("opml import read failed", "module", "handler", "action", "import", "resource", "opml", "result", "failed", "error", err)
-		return c.JSON(http.StatusBadRequest, errorResponse{Error: "read file failed"})
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "read file failed")
 	}
 
 	logger.Info("opml import started", "module", "handler", "action", "import", "resource", "opml", "result", "ok", "count", len(content))
 	// Start background import
-	go h.runImport(content)
+	go h.runImport(content, backfill)
 
 	return c.JSON(http.StatusOK, importStartedResponse{Status: "started"})
 }
 
-func (h *OPMLHandler) runImport(content []byte) {
+func (h *OPMLHandler) runImport(content []byte, backfill service.InitialBackfill) {
 	reader := bytes.NewReader(content)
 
 	// Pre-count total feeds for progress
@@ -103,7 +113,7 @@ func (h *OPMLHandler) runImport(content []byte) {
 		h.taskManager.Update(p.Current, p.Feed)
 	}
 
-	result, err := h.service.Import(ctx, reader, onProgress)
+	result, err := h.service.Import(ctx, reader, backfill, onProgress)
 	if err != nil {
 		// Check if cancelled
 		if ctx.Err() != nil {
@@ -156,9 +166,7 @@ func (h *OPMLHandler) CancelImport(c echo.Context) error {
 // @Router /opml/import/status [get]
 func (h *OPMLHandler) ImportStatus(c echo.Context) error {
 	res := c.Response()
-	res.Header().Set("Content-Type", "text/event-stream")
-	res.Header().Set("Cache-Control", "no-cache")
-	res.Header().Set("Connection", "keep-alive")
+	setStreamHeaders(res, mimeEventStream)
 	res.WriteHeader(http.StatusOK)
 
 	ctx := c.Request().Context()
@@ -39,7 +39,7 @@ func TestOPMLHandler_Import_Success(t *testing.T) {
 		Return("task-id", context.Background())
 
 	mockOPML.EXPECT().
-		Import(gomock.Any(), gomock.Any(), gomock.Any()).
+		Import(gomock.Any(), gomock.Any(), service.InitialBackfill{}, gomock.Any()).
 		Return(service.ImportResult{}, nil)
 
 	mockTask.EXPECT().
@@ -57,6 +57,25 @@ func TestOPMLHandler_Import_Success(t *testing.T) {
 	require.Equal(t, "started", resp.Status)
 }
 
+func TestOPMLHandler_Import_InvalidBackfill(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// No import task starts for a rejected request
+	h := handler.NewOPMLHandlerHelper(mock.NewMockOPMLService(ctrl), mock.NewMockImportTaskService(ctrl))
+
+	e := newTestEcho()
+	req := newJSONRequest(http.MethodPost, "/opml/import?initialBackfill=latest", nil)
+	req.Body = &ioReaderCloser{s: `<opml version="2.0"><body/></opml>`}
+	req.Header.Set("Content-Type", "application/xml")
+	c, rec := newTestContext(e, req)
+
+	require.NoError(t, h.Import(c))
+	var resp handler.ErrorResponse
+	assertJSONResponse(t, rec, http.StatusBadRequest, &resp)
+	require.Equal(t, handler.CodeInvalidRequest, resp.Code)
+}
+
 func TestOPMLHandler_CancelImport_Success(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
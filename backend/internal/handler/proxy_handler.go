@@ -2,7 +2,6 @@ package handler
 
 import (
 	"encoding/base64"
-	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -56,14 +55,14 @@ func (h *ProxyHandler) ProxyImage(c echo.Context) error {
 	encoded := c.Param("encoded")
 	if encoded == "" {
 		logger.Debug("proxy image missing url", "module", "handler", "action", "request", "resource", "proxy", "result", "failed")
-		return Error(c, http.StatusBadRequest, "URL is required")
+		return Error(c, http.StatusBadRequest, CodeProxyInvalidURL, "URL is required")
 	}
 
 	// Decode Base64 URL-safe
 	decoded, err := base64.URLEncoding.DecodeString(encoded)
 	if err != nil {
 		logger.Debug("proxy image invalid encoding", "module", "handler", "action", "request", "resource", "proxy", "result", "failed", "error", err)
-		return Error(c, http.StatusBadRequest, "Invalid encoding")
+		return Error(c, http.StatusBadRequest, CodeProxyInvalidURL, "Invalid encoding")
 	}
 	imageURL := string(decoded)
 
@@ -78,7 +77,7 @@ func (h *ProxyHandler) ProxyImage(c echo.Context) error {
 	result, err := h.proxyService.FetchImage(c.Request().Context(), imageURL, refererURL)
 	if err != nil {
 		logger.Warn("proxy image fetch failed", "module", "handler", "action", "fetch", "resource", "proxy", "result", "failed", "host", safeHost(imageURL), "error", err)
-		return h.handleServiceError(c, err)
+		return writeServiceError(c, err)
 	}
 
 	logger.Debug("proxy image fetched", "module", "handler", "action", "fetch", "resource", "proxy", "result", "ok", "host", safeHost(imageURL), "content_type", strings.ToLower(result.ContentType))
@@ -91,22 +90,3 @@ func (h *ProxyHandler) ProxyImage(c echo.Context) error {
 
 	return c.Blob(http.StatusOK, result.ContentType, result.Data)
 }
-
-func (h *ProxyHandler) handleServiceError(c echo.Context, err error) error {
-	switch {
-	case errors.Is(err, service.ErrInvalidURL):
-		return Error(c, http.StatusBadRequest, "Invalid URL")
-	case errors.Is(err, service.ErrInvalidProtocol):
-		return Error(c, http.StatusBadRequest, "Invalid protocol")
-	case errors.Is(err, service.ErrRequestTimeout):
-		return Error(c, http.StatusGatewayTimeout, "Request timeout")
-	case errors.Is(err, service.ErrInvalidImage):
-		return Error(c, http.StatusBadGateway, "Invalid image")
-	case errors.Is(err, service.ErrUpstreamRejected):
-		// Upstream rejected the request, return 502 and prevent caching
-		c.Response().Header().Set("Cache-Control", "no-store, no-cache, must-revalidate")
-		return Error(c, http.StatusBadGateway, "Upstream rejected")
-	default:
-		return Error(c, http.StatusInternalServerError, "Failed to fetch image")
-	}
-}
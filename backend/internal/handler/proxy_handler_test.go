@@ -117,15 +117,16 @@ func TestProxyHandler_ProxyImage_ErrorMapping(t *testing.T) {
 		name           string
 		err            error
 		status         int
+		code           string
 		cacheControl   string
 		expectedPrefix string
 	}{
-		{name: "invalid_url", err: service.ErrInvalidURL, status: http.StatusBadRequest},
-		{name: "invalid_protocol", err: service.ErrInvalidProtocol, status: http.StatusBadRequest},
-		{name: "invalid_image", err: service.ErrInvalidImage, status: http.StatusBadGateway},
-		{name: "timeout", err: service.ErrRequestTimeout, status: http.StatusGatewayTimeout},
-		{name: "upstream_rejected", err: service.ErrUpstreamRejected, status: http.StatusBadGateway, cacheControl: "no-store, no-cache, must-revalidate"},
-		{name: "default", err: errors.New("boom"), status: http.StatusInternalServerError},
+		{name: "invalid_url", err: service.ErrInvalidURL, status: http.StatusBadRequest, code: "proxy_invalid_url"},
+		{name: "invalid_protocol", err: service.ErrInvalidProtocol, status: http.StatusBadRequest, code: "proxy_invalid_url"},
+		{name: "invalid_image", err: service.ErrInvalidImage, status: http.StatusBadGateway, code: "proxy_invalid_image"},
+		{name: "timeout", err: service.ErrRequestTimeout, status: http.StatusGatewayTimeout, code: "proxy_timeout"},
+		{name: "upstream_rejected", err: service.ErrUpstreamRejected, status: http.StatusBadGateway, code: "proxy_upstream_rejected", cacheControl: "no-store, no-cache, must-revalidate"},
+		{name: "default", err: errors.New("boom"), status: http.StatusInternalServerError, code: "internal_error"},
 	}
 
 	for _, tc := range tests {
@@ -144,7 +145,10 @@ func TestProxyHandler_ProxyImage_ErrorMapping(t *testing.T) {
 
 			err := h.ProxyImage(c)
 			require.NoError(t, err)
-			require.Equal(t, tc.status, rec.Code)
+
+			var resp handler.ErrorResponse
+			assertJSONResponse(t, rec, tc.status, &resp)
+			require.Equal(t, tc.code, resp.Code)
 			if tc.cacheControl != "" {
 				require.Equal(t, tc.cacheControl, rec.Header().Get("Cache-Control"))
 			}
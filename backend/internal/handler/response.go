@@ -1,14 +1,7 @@
 package handler
 
 import (
-	"errors"
-	"net/http"
 	"strconv"
-
-	"github.com/labstack/echo/v4"
-
-	"gist/backend/pkg/logger"
-	"gist/backend/internal/service"
 )
 
 // idToString converts an int64 ID to string for JSON serialization.
@@ -27,10 +20,6 @@ func idPtrToString(id *int64) *string {
 	return &s
 }
 
-type errorResponse struct {
-	Error string `json:"error"`
-}
-
 type importStartedResponse struct {
 	Status string `json:"status"`
 }
@@ -42,24 +31,3 @@ type importCancelledResponse struct {
 type importIdleResponse struct {
 	Status string `json:"status"`
 }
-
-func writeServiceError(c echo.Context, err error) error {
-	switch {
-	case errors.Is(err, service.ErrInvalid):
-		return c.JSON(http.StatusBadRequest, errorResponse{Error: "invalid request"})
-	case errors.Is(err, service.ErrNotFound):
-		return c.JSON(http.StatusNotFound, errorResponse{Error: "resource not found"})
-	case errors.Is(err, service.ErrConflict):
-		return c.JSON(http.StatusConflict, errorResponse{Error: "conflict"})
-	case errors.Is(err, service.ErrFeedFetch):
-		return c.JSON(http.StatusBadGateway, errorResponse{Error: "feed fetch failed"})
-	default:
-		logger.Error("handler internal error", "module", "handler", "action", "request", "resource", "http", "result", "failed", "error", err)
-		return c.JSON(http.StatusInternalServerError, errorResponse{Error: "internal error"})
-	}
-}
-
-// Error returns a JSON error response with the given status and message
-func Error(c echo.Context, status int, message string) error {
-	return c.JSON(status, errorResponse{Error: message})
-}
@@ -24,18 +24,26 @@ func TestHandler_RegisterRoutes(t *testing.T) {
 	e := newTestEcho()
 	g := e.Group("")
 
-	handler.NewAIHandler(nil).RegisterRoutes(g)
+	handler.NewAIHandler(nil, nil).RegisterRoutes(g)
 
-	authHandler := handler.NewAuthHandler(nil)
+	authHandler := handler.NewAuthHandler(nil, nil, nil)
 	authHandler.RegisterPublicRoutes(g)
 	authHandler.RegisterProtectedRoutes(g)
 
 	handler.NewDomainRateLimitHandler(nil).RegisterRoutes(g)
-	handler.NewEntryHandler(nil, nil).RegisterRoutes(g)
-	handler.NewFeedHandler(nil, nil).RegisterRoutes(g)
+	handler.NewAPITokenHandler(nil).RegisterRoutes(g)
+	handler.NewEntryHandler(nil, nil, nil).RegisterRoutes(g)
+	feedHandler := handler.NewFeedHandler(nil, nil, nil, nil)
+	feedHandler.RegisterRoutes(g)
+	feedHandler.RegisterIngestRoutes(g)
 	handler.NewFolderHandler(nil).RegisterRoutes(g)
 	handler.NewProxyHandler(nil).RegisterRoutes(g)
 	handler.NewOPMLHandler(nil, nil).RegisterRoutes(g)
+	handler.NewBackupHandler(nil).RegisterRoutes(g)
+	handler.NewEventHandler(nil).RegisterRoutes(g)
+	handler.NewThumbnailHandler(nil).RegisterRoutes(g)
+	handler.NewBootstrapHandler(nil, nil, nil, nil, nil, nil).RegisterRoutes(g)
+	handler.NewSavedHandler(nil).RegisterRoutes(g)
 	handler.NewSettingsHandler(nil, network.NewClientFactoryForTest(&http.Client{})).RegisterRoutes(g)
 
 	iconHandler := handler.NewIconHandler(nil)
@@ -48,13 +56,28 @@ func TestHandler_RegisterRoutes(t *testing.T) {
 	assertRoute(t, routes, http.MethodPost, "/ai/translate")
 	assertRoute(t, routes, http.MethodPost, "/ai/translate/batch")
 	assertRoute(t, routes, http.MethodDelete, "/ai/cache")
+	assertRoute(t, routes, http.MethodGet, "/ai/usage")
+	assertRoute(t, routes, http.MethodGet, "/ai/auto-translate/status")
+
+	assertRoute(t, routes, http.MethodGet, "/bootstrap")
+	assertRoute(t, routes, http.MethodPost, "/saved")
+	assertRoute(t, routes, http.MethodGet, "/saved")
+	assertRoute(t, routes, http.MethodDelete, "/saved/:entryId")
 
 	assertRoute(t, routes, http.MethodGet, "/auth/status")
 	assertRoute(t, routes, http.MethodPost, "/auth/register")
 	assertRoute(t, routes, http.MethodPost, "/auth/login")
 	assertRoute(t, routes, http.MethodGet, "/auth/me")
 	assertRoute(t, routes, http.MethodPut, "/auth/profile")
+	assertRoute(t, routes, http.MethodGet, "/auth/events")
 	assertRoute(t, routes, http.MethodPost, "/auth/logout")
+	assertRoute(t, routes, http.MethodPost, "/auth/2fa/verify")
+	assertRoute(t, routes, http.MethodPost, "/auth/2fa/setup")
+	assertRoute(t, routes, http.MethodPost, "/auth/2fa/enable")
+	assertRoute(t, routes, http.MethodPost, "/auth/2fa/disable")
+	assertRoute(t, routes, http.MethodGet, "/auth/tokens")
+	assertRoute(t, routes, http.MethodPost, "/auth/tokens")
+	assertRoute(t, routes, http.MethodDelete, "/auth/tokens/:id")
 
 	assertRoute(t, routes, http.MethodGet, "/domain-rate-limits")
 	assertRoute(t, routes, http.MethodPost, "/domain-rate-limits")
@@ -62,7 +85,15 @@ func TestHandler_RegisterRoutes(t *testing.T) {
 	assertRoute(t, routes, http.MethodDelete, "/domain-rate-limits/:host")
 
 	assertRoute(t, routes, http.MethodGet, "/entries")
+	assertRoute(t, routes, http.MethodGet, "/entries/counts")
 	assertRoute(t, routes, http.MethodGet, "/entries/:id")
+	assertRoute(t, routes, http.MethodPost, "/entries/bulk")
+	assertRoute(t, routes, http.MethodGet, "/entries/:id/revisions")
+	assertRoute(t, routes, http.MethodGet, "/entries/:id/text")
+	assertRoute(t, routes, http.MethodGet, "/entries/:id/visit")
+	assertRoute(t, routes, http.MethodGet, "/entries/:id/thumbnail")
+	assertRoute(t, routes, http.MethodPut, "/entries/:id/note")
+	assertRoute(t, routes, http.MethodDelete, "/entries/:id/note")
 	assertRoute(t, routes, http.MethodPatch, "/entries/:id/read")
 	assertRoute(t, routes, http.MethodPatch, "/entries/read")
 	assertRoute(t, routes, http.MethodPatch, "/entries/:id/starred")
@@ -72,14 +103,30 @@ func TestHandler_RegisterRoutes(t *testing.T) {
 	assertRoute(t, routes, http.MethodDelete, "/entries/cache")
 	assertRoute(t, routes, http.MethodGet, "/unread-counts")
 	assertRoute(t, routes, http.MethodGet, "/starred-count")
+	assertRoute(t, routes, http.MethodGet, "/entries/starred/archive-status")
+	assertRoute(t, routes, http.MethodGet, "/stats/reading")
+	assertRoute(t, routes, http.MethodGet, "/stats/clicks")
 
 	assertRoute(t, routes, http.MethodPost, "/feeds")
+	assertRoute(t, routes, http.MethodPost, "/feeds/static")
+	assertRoute(t, routes, http.MethodPut, "/feeds/:id/static")
+	assertRoute(t, routes, http.MethodPost, "/feeds/:id/ingest-token")
+	assertRoute(t, routes, http.MethodPost, "/feeds/:id/entries")
 	assertRoute(t, routes, http.MethodPost, "/feeds/refresh")
 	assertRoute(t, routes, http.MethodGet, "/feeds/refresh")
+	assertRoute(t, routes, http.MethodPost, "/feeds/:id/refresh")
 	assertRoute(t, routes, http.MethodGet, "/feeds/preview")
 	assertRoute(t, routes, http.MethodGet, "/feeds")
 	assertRoute(t, routes, http.MethodPut, "/feeds/:id")
 	assertRoute(t, routes, http.MethodPatch, "/feeds/:id/type")
+	assertRoute(t, routes, http.MethodPatch, "/feeds/:id/auto-translate")
+	assertRoute(t, routes, http.MethodGet, "/feeds/:id/muted-authors")
+	assertRoute(t, routes, http.MethodPut, "/feeds/:id/muted-authors")
+	assertRoute(t, routes, http.MethodDelete, "/feeds/:id/muted-authors")
+	assertRoute(t, routes, http.MethodPost, "/feeds/:id/probe")
+	assertRoute(t, routes, http.MethodGet, "/feeds/:id/ingest-report")
+	assertRoute(t, routes, http.MethodPost, "/feeds/:id/backfill-archive")
+	assertRoute(t, routes, http.MethodGet, "/feeds/:id/backfill-archive")
 	assertRoute(t, routes, http.MethodDelete, "/feeds/:id")
 	assertRoute(t, routes, http.MethodDelete, "/feeds")
 
@@ -89,6 +136,11 @@ func TestHandler_RegisterRoutes(t *testing.T) {
 	assertRoute(t, routes, http.MethodPatch, "/folders/:id/type")
 	assertRoute(t, routes, http.MethodDelete, "/folders/:id")
 	assertRoute(t, routes, http.MethodDelete, "/folders")
+	assertRoute(t, routes, http.MethodGet, "/folders/:id/rules")
+	assertRoute(t, routes, http.MethodPost, "/folders/:id/rules")
+	assertRoute(t, routes, http.MethodPut, "/folders/:id/rules/:ruleId")
+	assertRoute(t, routes, http.MethodDelete, "/folders/:id/rules/:ruleId")
+	assertRoute(t, routes, http.MethodPost, "/folders/rules/apply")
 
 	assertRoute(t, routes, http.MethodGet, "/icons/:filename")
 	assertRoute(t, routes, http.MethodDelete, "/icons/cache")
@@ -97,6 +149,9 @@ func TestHandler_RegisterRoutes(t *testing.T) {
 	assertRoute(t, routes, http.MethodDelete, "/opml/import")
 	assertRoute(t, routes, http.MethodGet, "/opml/import/status")
 	assertRoute(t, routes, http.MethodGet, "/opml/export")
+	assertRoute(t, routes, http.MethodGet, "/export/full")
+	assertRoute(t, routes, http.MethodGet, "/events")
+	assertRoute(t, routes, http.MethodPost, "/import/full")
 
 	assertRoute(t, routes, http.MethodGet, "/proxy/image/:encoded")
 
@@ -110,5 +165,7 @@ func TestHandler_RegisterRoutes(t *testing.T) {
 	assertRoute(t, routes, http.MethodPost, "/settings/network/test")
 	assertRoute(t, routes, http.MethodGet, "/settings/appearance")
 	assertRoute(t, routes, http.MethodPut, "/settings/appearance")
+	assertRoute(t, routes, http.MethodGet, "/settings/security")
+	assertRoute(t, routes, http.MethodPut, "/settings/security")
 	assertRoute(t, routes, http.MethodDelete, "/settings/anubis-cookies")
 }
@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"gist/backend/internal/service"
+	"gist/backend/pkg/logger"
+	"gist/backend/pkg/network"
+)
+
+type SavedHandler struct {
+	service service.SavedService
+}
+
+type savePageRequest struct {
+	URL string `json:"url"`
+}
+
+func NewSavedHandler(svc service.SavedService) *SavedHandler {
+	return &SavedHandler{service: svc}
+}
+
+func (h *SavedHandler) RegisterRoutes(g *echo.Group) {
+	g.POST("/saved", h.Save)
+	g.GET("/saved", h.SaveAndOpen)
+	g.DELETE("/saved/:entryId", h.Delete)
+}
+
+// Save stores a page as an entry of the Saved feed.
+// @Summary Save a page
+// @Description Fetch a page and store it as an entry of the built-in Saved feed, which is created on first use. Saving a URL again returns the entry saved the first time without fetching the page.
+// @Tags saved
+// @Accept json
+// @Produce json
+// @Param page body savePageRequest true "Page to save"
+// @Success 200 {object} entryResponse "Already saved"
+// @Success 201 {object} entryResponse
+// @Failure 400 {object} errorResponse
+// @Failure 415 {object} errorResponse
+// @Failure 502 {object} errorResponse
+// @Router /saved [post]
+func (h *SavedHandler) Save(c echo.Context) error {
+	var req savePageRequest
+	if err := c.Bind(&req); err != nil {
+		logger.Debug("page save invalid request", "module", "handler", "action", "create", "resource", "entry", "result", "failed", "error", err)
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid request")
+	}
+
+	entry, created, err := h.service.Save(c.Request().Context(), req.URL)
+	if err != nil {
+		logger.Warn("page save failed", "module", "handler", "action", "create", "resource", "entry", "result", "failed", "host", network.ExtractHost(req.URL), "error", err)
+		return writeServiceError(c, err)
+	}
+
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+	return c.JSON(status, toEntryResponse(entry))
+}
+
+// SaveAndOpen saves a page and opens it in the reader, for bookmarklets.
+// @Summary Save a page from a bookmarklet
+// @Description Save a page like POST /saved and redirect to the saved entry in the web app. Meant for bookmarklets, which are authenticated by the session cookie.
+// @Tags saved
+// @Param url query string true "Page URL"
+// @Success 303 "Redirect to the saved entry"
+// @Failure 400 {object} errorResponse
+// @Failure 415 {object} errorResponse
+// @Failure 502 {object} errorResponse
+// @Router /saved [get]
+func (h *SavedHandler) SaveAndOpen(c echo.Context) error {
+	pageURL := c.QueryParam("url")
+	entry, _, err := h.service.Save(c.Request().Context(), pageURL)
+	if err != nil {
+		logger.Warn("page save failed", "module", "handler", "action", "create", "resource", "entry", "result", "failed", "host", network.ExtractHost(pageURL), "error", err)
+		return writeServiceError(c, err)
+	}
+
+	c.Response().Header().Set(echo.HeaderCacheControl, "no-store")
+	return c.Redirect(http.StatusSeeOther, "/feed/"+strconv.FormatInt(entry.FeedID, 10)+"/"+strconv.FormatInt(entry.ID, 10))
+}
+
+// Delete removes a saved page.
+// @Summary Delete a saved page
+// @Description Delete an entry of the Saved feed. Entries of other feeds are not found.
+// @Tags saved
+// @Produce json
+// @Param entryId path int true "Entry ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Router /saved/{entryId} [delete]
+func (h *SavedHandler) Delete(c echo.Context) error {
+	id, err := parseIDParam(c, "entryId")
+	if err != nil {
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid id")
+	}
+
+	if err := h.service.Delete(c.Request().Context(), id); err != nil {
+		logger.Warn("saved entry delete failed", "module", "handler", "action", "delete", "resource", "entry", "result", "failed", "entry_id", id, "error", err)
+		return writeServiceError(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
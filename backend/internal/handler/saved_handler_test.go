@@ -0,0 +1,97 @@
+package handler_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"gist/backend/internal/handler"
+	"gist/backend/internal/model"
+	"gist/backend/internal/service"
+	"gist/backend/internal/service/mock"
+)
+
+func TestSavedHandler_Save(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockService := mock.NewMockSavedService(ctrl)
+	h := handler.NewSavedHandler(mockService)
+	e := newTestEcho()
+
+	title := "Page"
+	entry := model.Entry{ID: 7, FeedID: 3, Title: &title}
+	mockService.EXPECT().Save(gomock.Any(), "https://example.com/page").Return(entry, true, nil)
+	mockService.EXPECT().Save(gomock.Any(), "https://example.com/page").Return(entry, false, nil)
+
+	c, rec := newTestContext(e, newJSONRequest(http.MethodPost, "/saved", map[string]string{"url": "https://example.com/page"}))
+	require.NoError(t, h.Save(c))
+	var resp handler.EntryResponse
+	assertJSONResponse(t, rec, http.StatusCreated, &resp)
+	require.Equal(t, "7", resp.ID)
+	require.Equal(t, "3", resp.FeedID)
+
+	c, rec = newTestContext(e, newJSONRequest(http.MethodPost, "/saved", map[string]string{"url": "https://example.com/page"}))
+	require.NoError(t, h.Save(c))
+	assertJSONResponse(t, rec, http.StatusOK, &resp)
+	require.Equal(t, "7", resp.ID)
+}
+
+func TestSavedHandler_Save_Errors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockService := mock.NewMockSavedService(ctrl)
+	h := handler.NewSavedHandler(mockService)
+	e := newTestEcho()
+
+	mockService.EXPECT().Save(gomock.Any(), "javascript:alert(1)").Return(model.Entry{}, false, service.ErrInvalid)
+	mockService.EXPECT().Save(gomock.Any(), "https://example.com/down").Return(model.Entry{}, false, fmt.Errorf("%w: timeout", service.ErrFeedFetch))
+
+	c, rec := newTestContext(e, newJSONRequest(http.MethodPost, "/saved", map[string]string{"url": "javascript:alert(1)"}))
+	require.NoError(t, h.Save(c))
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+
+	c, rec = newTestContext(e, newJSONRequest(http.MethodPost, "/saved", map[string]string{"url": "https://example.com/down"}))
+	require.NoError(t, h.Save(c))
+	require.Equal(t, http.StatusBadGateway, rec.Code)
+}
+
+func TestSavedHandler_SaveAndOpen(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockService := mock.NewMockSavedService(ctrl)
+	h := handler.NewSavedHandler(mockService)
+	e := newTestEcho()
+
+	mockService.EXPECT().Save(gomock.Any(), "https://example.com/a?b=c").Return(model.Entry{ID: 7, FeedID: 3}, false, nil)
+
+	req, err := http.NewRequest(http.MethodGet, "/saved?url=https%3A%2F%2Fexample.com%2Fa%3Fb%3Dc", nil)
+	require.NoError(t, err)
+	c, rec := newTestContext(e, req)
+	require.NoError(t, h.SaveAndOpen(c))
+	require.Equal(t, http.StatusSeeOther, rec.Code)
+	require.Equal(t, "/feed/3/7", rec.Header().Get("Location"))
+}
+
+func TestSavedHandler_Delete(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockService := mock.NewMockSavedService(ctrl)
+	h := handler.NewSavedHandler(mockService)
+	e := newTestEcho()
+
+	mockService.EXPECT().Delete(gomock.Any(), int64(7)).Return(nil)
+	mockService.EXPECT().Delete(gomock.Any(), int64(8)).Return(service.ErrNotFound)
+
+	req, err := http.NewRequest(http.MethodDelete, "/saved/7", nil)
+	require.NoError(t, err)
+	c, rec := newTestContext(e, req)
+	setPathParams(c, map[string]string{"entryId": "7"})
+	require.NoError(t, h.Delete(c))
+	require.Equal(t, http.StatusNoContent, rec.Code)
+
+	req, err = http.NewRequest(http.MethodDelete, "/saved/8", nil)
+	require.NoError(t, err)
+	c, rec = newTestContext(e, req)
+	setPathParams(c, map[string]string{"entryId": "8"})
+	require.NoError(t, h.Delete(c))
+	require.Equal(t, http.StatusNotFound, rec.Code)
+}
@@ -1,8 +1,12 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/labstack/echo/v4"
 
@@ -23,6 +27,21 @@ type aiSettingsResponse struct {
 	AutoTranslate   bool           `json:"autoTranslate"`
 	AutoSummary     bool           `json:"autoSummary"`
 	RateLimit       int            `json:"rateLimit"`
+	// TranslateOnRefresh translates list titles of new entries in the background;
+	// feeds can override it
+	TranslateOnRefresh bool `json:"translateOnRefresh"`
+	// ModelPrices are USD per million tokens, keyed by model name
+	ModelPrices          map[string]aiModelPrice `json:"modelPrices"`
+	UsageRetentionMonths int                     `json:"usageRetentionMonths"`
+	// TranslateBlockChars is the article HTML sent per translation call
+	TranslateBlockChars int `json:"translateBlockChars" example:"1500"`
+	// Version identifies this read; send it back on update to detect concurrent saves
+	Version string `json:"version" example:"3"`
+}
+
+type aiModelPrice struct {
+	Input  float64 `json:"input" example:"2.5"`
+	Output float64 `json:"output" example:"10"`
 }
 
 type aiSettingsRequest struct {
@@ -35,6 +54,17 @@ type aiSettingsRequest struct {
 	AutoTranslate   bool           `json:"autoTranslate"`
 	AutoSummary     bool           `json:"autoSummary"`
 	RateLimit       int            `json:"rateLimit"`
+	// TranslateOnRefresh translates list titles of new entries in the background;
+	// feeds can override it
+	TranslateOnRefresh bool `json:"translateOnRefresh"`
+	// ModelPrices replaces the price table when sent; omitted keeps the stored one
+	ModelPrices map[string]aiModelPrice `json:"modelPrices,omitempty"`
+	// UsageRetentionMonths of 0 or omitted keeps the stored retention
+	UsageRetentionMonths int `json:"usageRetentionMonths,omitempty"`
+	// TranslateBlockChars of 0 or omitted keeps the stored budget
+	TranslateBlockChars int `json:"translateBlockChars,omitempty"`
+	// Version from the last read; a stale one is rejected with 409. Omitted saves unconditionally
+	Version string `json:"version,omitempty"`
 }
 
 type aiTestRequest struct {
@@ -52,15 +82,58 @@ type aiTestResponse struct {
 }
 
 type generalSettingsResponse struct {
-	FallbackUserAgent string `json:"fallbackUserAgent"`
-	AutoReadability   bool   `json:"autoReadability"`
-	MarkReadOnScroll  bool   `json:"markReadOnScroll"`
+	FallbackUserAgent      string `json:"fallbackUserAgent"`
+	AutoReadability        bool   `json:"autoReadability"`
+	MarkReadOnScroll       bool   `json:"markReadOnScroll"`
+	EntryRevisions         bool   `json:"entryRevisions"`
+	ImageCache             bool   `json:"imageCache"`
+	ImageCacheEntryLimitMB int    `json:"imageCacheEntryLimitMb"`
+	ImageCacheTotalLimitMB int    `json:"imageCacheTotalLimitMb"`
+	Timezone               string `json:"timezone"`
+	// Concurrency limits are read when a refresh run starts
+	MaxConcurrentRefresh int `json:"maxConcurrentRefresh"`
+	MaxConcurrentPerHost int `json:"maxConcurrentPerHost"`
+	// Anubis challenges solved per fetch at most, and the wait after each solve
+	AnubisMaxRetries   int `json:"anubisMaxRetries"`
+	AnubisRetryDelayMs int `json:"anubisRetryDelayMs"`
+	// Refreshes in a row answered 304 before a feed due for new entries is fetched without validators
+	UnconditionalFetchAfter int `json:"unconditionalFetchAfter"`
+	// KeepRawItems stores fetched feed items for GET /entries/{id}/raw
+	KeepRawItems bool `json:"keepRawItems"`
+	// Quiet hours as "HH:MM" in timezone; both empty when disabled
+	QuietHoursStart string `json:"quietHoursStart" example:"22:00"`
+	QuietHoursEnd   string `json:"quietHoursEnd" example:"07:00"`
+	// Version identifies this read; send it back on update to detect concurrent saves
+	Version string `json:"version" example:"3"`
 }
 
 type generalSettingsRequest struct {
 	FallbackUserAgent string `json:"fallbackUserAgent"`
 	AutoReadability   bool   `json:"autoReadability"`
 	MarkReadOnScroll  bool   `json:"markReadOnScroll"`
+	// EntryRevisions is optional so older clients don't disable versioning by omission
+	EntryRevisions *bool `json:"entryRevisions,omitempty"`
+	// Image cache fields are optional for the same reason; omitted values keep the stored ones
+	ImageCache             *bool `json:"imageCache,omitempty"`
+	ImageCacheEntryLimitMB *int  `json:"imageCacheEntryLimitMb,omitempty"`
+	ImageCacheTotalLimitMB *int  `json:"imageCacheTotalLimitMb,omitempty"`
+	// Timezone is an IANA name such as "Asia/Shanghai"; omitted keeps the stored one
+	Timezone *string `json:"timezone,omitempty"`
+	// Concurrency limits are optional; omitted keeps the stored ones
+	MaxConcurrentRefresh *int `json:"maxConcurrentRefresh,omitempty"`
+	MaxConcurrentPerHost *int `json:"maxConcurrentPerHost,omitempty"`
+	// Anubis retry settings are optional; omitted keeps the stored ones
+	AnubisMaxRetries   *int `json:"anubisMaxRetries,omitempty"`
+	AnubisRetryDelayMs *int `json:"anubisRetryDelayMs,omitempty"`
+	// UnconditionalFetchAfter is optional; omitted keeps the stored one
+	UnconditionalFetchAfter *int `json:"unconditionalFetchAfter,omitempty"`
+	// KeepRawItems is optional; omitted keeps the stored one
+	KeepRawItems *bool `json:"keepRawItems,omitempty"`
+	// Quiet hours are optional; omitted keeps the stored ones, both empty disables them
+	QuietHoursStart *string `json:"quietHoursStart,omitempty" example:"22:00"`
+	QuietHoursEnd   *string `json:"quietHoursEnd,omitempty" example:"07:00"`
+	// Version from the last read; a stale one is rejected with 409. Omitted saves unconditionally
+	Version string `json:"version,omitempty"`
 }
 
 type networkSettingsResponse struct {
@@ -71,6 +144,8 @@ type networkSettingsResponse struct {
 	Username string `json:"username"`
 	Password string `json:"password"`
 	IPStack  string `json:"ipStack"`
+	// Version identifies this read; send it back on update to detect concurrent saves
+	Version string `json:"version" example:"3"`
 }
 
 type networkSettingsRequest struct {
@@ -81,6 +156,8 @@ type networkSettingsRequest struct {
 	Username string `json:"username"`
 	Password string `json:"password"`
 	IPStack  string `json:"ipStack"`
+	// Version from the last read; a stale one is rejected with 409. Omitted saves unconditionally
+	Version string `json:"version,omitempty"`
 }
 
 type networkTestRequest struct {
@@ -90,20 +167,46 @@ type networkTestRequest struct {
 	Port     int    `json:"port"`
 	Username string `json:"username"`
 	Password string `json:"password"`
+	// TestURL is fetched through the proxy; defaults to defaultProxyTestURL.
+	TestURL string `json:"testUrl,omitempty"`
 }
 
 type networkTestResponse struct {
-	Success bool   `json:"success"`
-	Message string `json:"message,omitempty"`
-	Error   string `json:"error,omitempty"`
+	Success   bool   `json:"success"`
+	Message   string `json:"message,omitempty"`
+	Error     string `json:"error,omitempty"`
+	LatencyMs int64  `json:"latencyMs,omitempty"`
+	// EgressIP is set when the test URL echoes the caller's address.
+	EgressIP string `json:"egressIp,omitempty"`
 }
 
+// defaultProxyTestURL answers quickly from most networks but does not report the egress IP.
+const defaultProxyTestURL = "https://captive.apple.com/"
+
 type appearanceSettingsResponse struct {
 	ContentTypes []string `json:"contentTypes"`
+	// Version identifies this read; send it back on update to detect concurrent saves
+	Version string `json:"version" example:"3"`
 }
 
 type appearanceSettingsRequest struct {
 	ContentTypes []string `json:"contentTypes"`
+	// Version from the last read; a stale one is rejected with 409. Omitted saves unconditionally
+	Version string `json:"version,omitempty"`
+}
+
+type securitySettingsResponse struct {
+	CORSOrigins    []string `json:"corsOrigins"`
+	CookieSameSite string   `json:"cookieSameSite"`
+	CookieSecure   bool     `json:"cookieSecure"`
+	CookieDomain   string   `json:"cookieDomain"`
+}
+
+type securitySettingsRequest struct {
+	CORSOrigins    []string `json:"corsOrigins"`
+	CookieSameSite string   `json:"cookieSameSite"`
+	CookieSecure   bool     `json:"cookieSecure"`
+	CookieDomain   string   `json:"cookieDomain"`
 }
 
 type SettingsHandler struct {
@@ -134,6 +237,8 @@ func (h *SettingsHandler) RegisterRoutes(g *echo.Group) {
 	g.POST("/settings/network/test", h.TestNetworkProxy)
 	g.GET("/settings/appearance", h.GetAppearanceSettings)
 	g.PUT("/settings/appearance", h.UpdateAppearanceSettings)
+	g.GET("/settings/security", h.GetSecuritySettings)
+	g.PUT("/settings/security", h.UpdateSecuritySettings)
 	g.DELETE("/settings/anubis-cookies", h.ClearAnubisCookies)
 }
 
@@ -149,22 +254,35 @@ func (h *SettingsHandler) GetAISettings(c echo.Context) error {
 	settings, err := h.service.GetAISettings(c.Request().Context())
 	if err != nil {
 		logger.Error("ai settings get failed", "module", "handler", "action", "list", "resource", "settings", "result", "failed", "error", err)
-		return c.JSON(http.StatusInternalServerError, errorResponse{Error: "failed to get settings"})
+		return Error(c, http.StatusInternalServerError, CodeInternal, "failed to get settings")
 	}
 
 	return c.JSON(http.StatusOK, aiSettingsResponse{
-		Provider:        settings.Provider,
-		APIKey:          settings.APIKey,
-		BaseURL:         settings.BaseURL,
-		Model:           settings.Model,
-		RequestOptions:  settings.RequestOptions,
-		SummaryLanguage: settings.SummaryLanguage,
-		AutoTranslate:   settings.AutoTranslate,
-		AutoSummary:     settings.AutoSummary,
-		RateLimit:       settings.RateLimit,
+		Provider:             settings.Provider,
+		APIKey:               settings.APIKey,
+		BaseURL:              settings.BaseURL,
+		Model:                settings.Model,
+		RequestOptions:       settings.RequestOptions,
+		SummaryLanguage:      settings.SummaryLanguage,
+		AutoTranslate:        settings.AutoTranslate,
+		AutoSummary:          settings.AutoSummary,
+		RateLimit:            settings.RateLimit,
+		TranslateOnRefresh:   settings.TranslateOnRefresh,
+		ModelPrices:          toAIModelPriceResponse(settings.ModelPrices),
+		UsageRetentionMonths: settings.UsageRetentionMonths,
+		TranslateBlockChars:  settings.TranslateBlockChars,
+		Version:              settings.Version,
 	})
 }
 
+func toAIModelPriceResponse(prices map[string]service.AIModelPrice) map[string]aiModelPrice {
+	resp := make(map[string]aiModelPrice, len(prices))
+	for name, price := range prices {
+		resp[name] = aiModelPrice{Input: price.Input, Output: price.Output}
+	}
+	return resp
+}
+
 // UpdateAISettings updates the AI configuration.
 // @Summary Update AI settings
 // @Description Update the AI provider configuration. Empty apiKey keeps existing key.
@@ -174,38 +292,63 @@ func (h *SettingsHandler) GetAISettings(c echo.Context) error {
 // @Param settings body aiSettingsRequest true "AI settings"
 // @Success 200 {object} aiSettingsResponse
 // @Failure 400 {object} errorResponse
+// @Failure 409 {object} errorResponse
 // @Failure 500 {object} errorResponse
 // @Router /settings/ai [put]
 func (h *SettingsHandler) UpdateAISettings(c echo.Context) error {
 	var req aiSettingsRequest
 	if err := c.Bind(&req); err != nil {
-		return c.JSON(http.StatusBadRequest, errorResponse{Error: "invalid request"})
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid request")
 	}
 	if req.Provider == "" {
-		return c.JSON(http.StatusBadRequest, errorResponse{Error: "provider is required"})
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "provider is required")
 	}
 	if req.Model == "" {
-		return c.JSON(http.StatusBadRequest, errorResponse{Error: "model is required"})
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "model is required")
 	}
 	if isBaseURLRequiredForProvider(req.Provider) && req.BaseURL == "" {
-		return c.JSON(http.StatusBadRequest, errorResponse{Error: "baseUrl is required"})
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "baseUrl is required")
+	}
+	if req.UsageRetentionMonths < 0 {
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "usageRetentionMonths must be non-negative")
+	}
+	if req.TranslateBlockChars < 0 {
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "translateBlockChars must be non-negative")
+	}
+	var modelPrices map[string]service.AIModelPrice
+	if req.ModelPrices != nil {
+		modelPrices = make(map[string]service.AIModelPrice, len(req.ModelPrices))
+		for name, price := range req.ModelPrices {
+			if strings.TrimSpace(name) == "" || price.Input < 0 || price.Output < 0 {
+				return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid model price")
+			}
+			modelPrices[name] = service.AIModelPrice{Input: price.Input, Output: price.Output}
+		}
 	}
 
 	settings := &service.AISettings{
-		Provider:        req.Provider,
-		APIKey:          req.APIKey,
-		BaseURL:         req.BaseURL,
-		Model:           req.Model,
-		RequestOptions:  req.RequestOptions,
-		SummaryLanguage: req.SummaryLanguage,
-		AutoTranslate:   req.AutoTranslate,
-		AutoSummary:     req.AutoSummary,
-		RateLimit:       req.RateLimit,
+		Provider:             req.Provider,
+		APIKey:               req.APIKey,
+		BaseURL:              req.BaseURL,
+		Model:                req.Model,
+		RequestOptions:       req.RequestOptions,
+		SummaryLanguage:      req.SummaryLanguage,
+		AutoTranslate:        req.AutoTranslate,
+		AutoSummary:          req.AutoSummary,
+		RateLimit:            req.RateLimit,
+		TranslateOnRefresh:   req.TranslateOnRefresh,
+		ModelPrices:          modelPrices,
+		UsageRetentionMonths: req.UsageRetentionMonths,
+		TranslateBlockChars:  req.TranslateBlockChars,
+		Version:              req.Version,
 	}
 
 	if err := h.service.SetAISettings(c.Request().Context(), settings); err != nil {
+		if errors.Is(err, service.ErrConflict) {
+			return writeServiceError(c, err)
+		}
 		logger.Error("ai settings update failed", "module", "handler", "action", "update", "resource", "settings", "result", "failed", "provider", req.Provider, "error", err)
-		return c.JSON(http.StatusInternalServerError, errorResponse{Error: "failed to save settings"})
+		return Error(c, http.StatusInternalServerError, CodeInternal, "failed to save settings")
 	}
 
 	logger.Info("ai settings updated", "module", "handler", "action", "update", "resource", "settings", "result", "ok", "provider", req.Provider)
@@ -226,17 +369,17 @@ func (h *SettingsHandler) UpdateAISettings(c echo.Context) error {
 func (h *SettingsHandler) TestAI(c echo.Context) error {
 	var req aiTestRequest
 	if err := c.Bind(&req); err != nil {
-		return c.JSON(http.StatusBadRequest, errorResponse{Error: "invalid request"})
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid request")
 	}
 
 	if req.Provider == "" {
-		return c.JSON(http.StatusBadRequest, errorResponse{Error: "provider is required"})
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "provider is required")
 	}
 	if req.Model == "" {
-		return c.JSON(http.StatusBadRequest, errorResponse{Error: "model is required"})
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "model is required")
 	}
 	if isBaseURLRequiredForProvider(req.Provider) && req.BaseURL == "" {
-		return c.JSON(http.StatusBadRequest, errorResponse{Error: "baseUrl is required"})
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "baseUrl is required")
 	}
 	response, err := h.service.TestAI(c.Request().Context(), req.Provider, req.APIKey, req.BaseURL, req.Model, req.RequestOptions)
 	if err != nil {
@@ -266,48 +409,122 @@ func (h *SettingsHandler) GetGeneralSettings(c echo.Context) error {
 	settings, err := h.service.GetGeneralSettings(c.Request().Context())
 	if err != nil {
 		logger.Error("general settings get failed", "module", "handler", "action", "list", "resource", "settings", "result", "failed", "error", err)
-		return c.JSON(http.StatusInternalServerError, errorResponse{Error: "failed to get settings"})
+		return Error(c, http.StatusInternalServerError, CodeInternal, "failed to get settings")
 	}
 
 	return c.JSON(http.StatusOK, generalSettingsResponse{
-		FallbackUserAgent: settings.FallbackUserAgent,
-		AutoReadability:   settings.AutoReadability,
-		MarkReadOnScroll:  settings.MarkReadOnScroll,
+		FallbackUserAgent:       settings.FallbackUserAgent,
+		AutoReadability:         settings.AutoReadability,
+		MarkReadOnScroll:        settings.MarkReadOnScroll,
+		EntryRevisions:          settings.EntryRevisions,
+		ImageCache:              settings.ImageCache,
+		ImageCacheEntryLimitMB:  settings.ImageCacheEntryLimitMB,
+		ImageCacheTotalLimitMB:  settings.ImageCacheTotalLimitMB,
+		Timezone:                settings.Timezone,
+		MaxConcurrentRefresh:    settings.MaxConcurrentRefresh,
+		MaxConcurrentPerHost:    settings.MaxConcurrentPerHost,
+		AnubisMaxRetries:        settings.AnubisMaxRetries,
+		AnubisRetryDelayMs:      settings.AnubisRetryDelayMs,
+		UnconditionalFetchAfter: settings.UnconditionalFetchAfter,
+		KeepRawItems:            settings.KeepRawItems,
+		QuietHoursStart:         settings.QuietHoursStart,
+		QuietHoursEnd:           settings.QuietHoursEnd,
+		Version:                 settings.Version,
 	})
 }
 
 // UpdateGeneralSettings updates the general settings.
 // @Summary Update general settings
-// @Description Update general application settings
+// @Description Update general application settings. Concurrency changes apply from the next refresh run; Anubis retry changes apply to the next fetch.
 // @Tags settings
 // @Accept json
 // @Produce json
 // @Param settings body generalSettingsRequest true "General settings"
 // @Success 200 {object} generalSettingsResponse
 // @Failure 400 {object} errorResponse
+// @Failure 409 {object} errorResponse
 // @Failure 500 {object} errorResponse
 // @Router /settings/general [put]
 func (h *SettingsHandler) UpdateGeneralSettings(c echo.Context) error {
 	var req generalSettingsRequest
 	if err := c.Bind(&req); err != nil {
-		return c.JSON(http.StatusBadRequest, errorResponse{Error: "invalid request"})
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid request")
+	}
+
+	if (req.ImageCacheEntryLimitMB != nil && *req.ImageCacheEntryLimitMB <= 0) ||
+		(req.ImageCacheTotalLimitMB != nil && *req.ImageCacheTotalLimitMB <= 0) {
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid request")
+	}
+	if req.Timezone != nil {
+		if _, err := time.LoadLocation(*req.Timezone); *req.Timezone == "" || err != nil {
+			return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid timezone")
+		}
+	}
+	if (req.MaxConcurrentRefresh != nil && (*req.MaxConcurrentRefresh < 1 || *req.MaxConcurrentRefresh > service.MaxConcurrentRefreshLimit)) ||
+		(req.MaxConcurrentPerHost != nil && (*req.MaxConcurrentPerHost < 1 || *req.MaxConcurrentPerHost > service.MaxConcurrentPerHostLimit)) {
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid concurrency")
+	}
+	if (req.AnubisMaxRetries != nil && (*req.AnubisMaxRetries < 1 || *req.AnubisMaxRetries > service.AnubisMaxRetriesLimit)) ||
+		(req.AnubisRetryDelayMs != nil && (*req.AnubisRetryDelayMs < 0 || *req.AnubisRetryDelayMs > service.AnubisRetryDelayLimitMs)) {
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid anubis retry")
+	}
+	if req.UnconditionalFetchAfter != nil && (*req.UnconditionalFetchAfter < 1 || *req.UnconditionalFetchAfter > service.UnconditionalFetchAfterLimit) {
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid unconditionalFetchAfter")
+	}
+
+	// Fields older clients don't send fall back to the stored values
+	current := &service.GeneralSettings{EntryRevisions: true}
+	if req.EntryRevisions == nil || req.ImageCache == nil || req.ImageCacheEntryLimitMB == nil || req.ImageCacheTotalLimitMB == nil || req.Timezone == nil ||
+		req.MaxConcurrentRefresh == nil || req.MaxConcurrentPerHost == nil || req.AnubisMaxRetries == nil || req.AnubisRetryDelayMs == nil ||
+		req.UnconditionalFetchAfter == nil || req.KeepRawItems == nil || req.QuietHoursStart == nil || req.QuietHoursEnd == nil {
+		if stored, err := h.service.GetGeneralSettings(c.Request().Context()); err == nil {
+			current = stored
+		}
 	}
 
 	settings := &service.GeneralSettings{
-		FallbackUserAgent: req.FallbackUserAgent,
-		AutoReadability:   req.AutoReadability,
-		MarkReadOnScroll:  req.MarkReadOnScroll,
+		FallbackUserAgent:       req.FallbackUserAgent,
+		AutoReadability:         req.AutoReadability,
+		MarkReadOnScroll:        req.MarkReadOnScroll,
+		EntryRevisions:          derefOr(req.EntryRevisions, current.EntryRevisions),
+		ImageCache:              derefOr(req.ImageCache, current.ImageCache),
+		ImageCacheEntryLimitMB:  derefOr(req.ImageCacheEntryLimitMB, current.ImageCacheEntryLimitMB),
+		ImageCacheTotalLimitMB:  derefOr(req.ImageCacheTotalLimitMB, current.ImageCacheTotalLimitMB),
+		Timezone:                derefOr(req.Timezone, current.Timezone),
+		MaxConcurrentRefresh:    derefOr(req.MaxConcurrentRefresh, current.MaxConcurrentRefresh),
+		MaxConcurrentPerHost:    derefOr(req.MaxConcurrentPerHost, current.MaxConcurrentPerHost),
+		AnubisMaxRetries:        derefOr(req.AnubisMaxRetries, current.AnubisMaxRetries),
+		AnubisRetryDelayMs:      derefOr(req.AnubisRetryDelayMs, current.AnubisRetryDelayMs),
+		UnconditionalFetchAfter: derefOr(req.UnconditionalFetchAfter, current.UnconditionalFetchAfter),
+		KeepRawItems:            derefOr(req.KeepRawItems, current.KeepRawItems),
+		QuietHoursStart:         derefOr(req.QuietHoursStart, current.QuietHoursStart),
+		QuietHoursEnd:           derefOr(req.QuietHoursEnd, current.QuietHoursEnd),
+		Version:                 req.Version,
+	}
+
+	if err := service.ValidateQuietHours(settings.QuietHoursStart, settings.QuietHoursEnd); err != nil {
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid quiet hours")
 	}
 
 	if err := h.service.SetGeneralSettings(c.Request().Context(), settings); err != nil {
+		if errors.Is(err, service.ErrConflict) || errors.Is(err, service.ErrInvalid) {
+			return writeServiceError(c, err)
+		}
 		logger.Error("general settings update failed", "module", "handler", "action", "update", "resource", "settings", "result", "failed", "error", err)
-		return c.JSON(http.StatusInternalServerError, errorResponse{Error: "failed to save settings"})
+		return Error(c, http.StatusInternalServerError, CodeInternal, "failed to save settings")
 	}
 
 	logger.Info("general settings updated", "module", "handler", "action", "update", "resource", "settings", "result", "ok")
 	return h.GetGeneralSettings(c)
 }
 
+func derefOr[T any](value *T, fallback T) T {
+	if value != nil {
+		return *value
+	}
+	return fallback
+}
+
 // ClearAnubisCookies deletes all Anubis cookies from settings.
 // @Summary Clear Anubis cookies
 // @Description Delete all Anubis challenge cookies used for bypassing protection
@@ -320,7 +537,7 @@ func (h *SettingsHandler) ClearAnubisCookies(c echo.Context) error {
 	deleted, err := h.service.ClearAnubisCookies(c.Request().Context())
 	if err != nil {
 		logger.Error("anubis cookies clear failed", "module", "handler", "action", "clear", "resource", "settings", "result", "failed", "error", err)
-		return c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return writeServiceError(c, err)
 	}
 
 	logger.Info("anubis cookies cleared", "module", "handler", "action", "clear", "resource", "settings", "result", "ok", "count", deleted)
@@ -339,7 +556,7 @@ func (h *SettingsHandler) GetNetworkSettings(c echo.Context) error {
 	settings, err := h.service.GetNetworkSettings(c.Request().Context())
 	if err != nil {
 		logger.Error("network settings get failed", "module", "handler", "action", "list", "resource", "settings", "result", "failed", "error", err)
-		return c.JSON(http.StatusInternalServerError, errorResponse{Error: "failed to get settings"})
+		return Error(c, http.StatusInternalServerError, CodeInternal, "failed to get settings")
 	}
 
 	return c.JSON(http.StatusOK, networkSettingsResponse{
@@ -350,6 +567,7 @@ func (h *SettingsHandler) GetNetworkSettings(c echo.Context) error {
 		Username: settings.Username,
 		Password: settings.Password,
 		IPStack:  settings.IPStack,
+		Version:  settings.Version,
 	})
 }
 
@@ -362,12 +580,13 @@ func (h *SettingsHandler) GetNetworkSettings(c echo.Context) error {
 // @Param settings body networkSettingsRequest true "Network settings"
 // @Success 200 {object} networkSettingsResponse
 // @Failure 400 {object} errorResponse
+// @Failure 409 {object} errorResponse
 // @Failure 500 {object} errorResponse
 // @Router /settings/network [put]
 func (h *SettingsHandler) UpdateNetworkSettings(c echo.Context) error {
 	var req networkSettingsRequest
 	if err := c.Bind(&req); err != nil {
-		return c.JSON(http.StatusBadRequest, errorResponse{Error: "invalid request"})
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid request")
 	}
 
 	settings := &service.NetworkSettings{
@@ -378,11 +597,15 @@ func (h *SettingsHandler) UpdateNetworkSettings(c echo.Context) error {
 		Username: req.Username,
 		Password: req.Password,
 		IPStack:  req.IPStack,
+		Version:  req.Version,
 	}
 
 	if err := h.service.SetNetworkSettings(c.Request().Context(), settings); err != nil {
+		if errors.Is(err, service.ErrConflict) {
+			return writeServiceError(c, err)
+		}
 		logger.Error("network settings update failed", "module", "handler", "action", "update", "resource", "settings", "result", "failed", "enabled", req.Enabled, "type", req.Type, "error", err)
-		return c.JSON(http.StatusInternalServerError, errorResponse{Error: "failed to save settings"})
+		return Error(c, http.StatusInternalServerError, CodeInternal, "failed to save settings")
 	}
 
 	logger.Info("network settings updated", "module", "handler", "action", "update", "resource", "settings", "result", "ok", "enabled", req.Enabled, "type", req.Type)
@@ -401,10 +624,10 @@ func (h *SettingsHandler) GetAppearanceSettings(c echo.Context) error {
 	settings, err := h.service.GetAppearanceSettings(c.Request().Context())
 	if err != nil {
 		logger.Error("appearance settings get failed", "module", "handler", "action", "list", "resource", "settings", "result", "failed", "error", err)
-		return c.JSON(http.StatusInternalServerError, errorResponse{Error: "failed to get settings"})
+		return Error(c, http.StatusInternalServerError, CodeInternal, "failed to get settings")
 	}
 
-	return c.JSON(http.StatusOK, appearanceSettingsResponse{ContentTypes: settings.ContentTypes})
+	return c.JSON(http.StatusOK, appearanceSettingsResponse{ContentTypes: settings.ContentTypes, Version: settings.Version})
 }
 
 // UpdateAppearanceSettings updates the appearance settings.
@@ -416,15 +639,16 @@ func (h *SettingsHandler) GetAppearanceSettings(c echo.Context) error {
 // @Param settings body appearanceSettingsRequest true "Appearance settings"
 // @Success 200 {object} appearanceSettingsResponse
 // @Failure 400 {object} errorResponse
+// @Failure 409 {object} errorResponse
 // @Failure 500 {object} errorResponse
 // @Router /settings/appearance [put]
 func (h *SettingsHandler) UpdateAppearanceSettings(c echo.Context) error {
 	var req appearanceSettingsRequest
 	if err := c.Bind(&req); err != nil {
-		return c.JSON(http.StatusBadRequest, errorResponse{Error: "invalid request"})
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid request")
 	}
 
-	settings := &service.AppearanceSettings{ContentTypes: req.ContentTypes}
+	settings := &service.AppearanceSettings{ContentTypes: req.ContentTypes, Version: req.Version}
 	if err := h.service.SetAppearanceSettings(c.Request().Context(), settings); err != nil {
 		logger.Error("appearance settings update failed", "module", "handler", "action", "update", "resource", "settings", "result", "failed", "error", err)
 		return writeServiceError(c, err)
@@ -434,9 +658,66 @@ func (h *SettingsHandler) UpdateAppearanceSettings(c echo.Context) error {
 	return h.GetAppearanceSettings(c)
 }
 
+// GetSecuritySettings returns the CORS and cookie settings.
+// @Summary Get security settings
+// @Description Get allowed CORS origins and auth cookie attributes
+// @Tags settings
+// @Produce json
+// @Success 200 {object} securitySettingsResponse
+// @Failure 500 {object} errorResponse
+// @Router /settings/security [get]
+func (h *SettingsHandler) GetSecuritySettings(c echo.Context) error {
+	settings, err := h.service.GetSecuritySettings(c.Request().Context())
+	if err != nil {
+		logger.Error("security settings get failed", "module", "handler", "action", "list", "resource", "settings", "result", "failed", "error", err)
+		return Error(c, http.StatusInternalServerError, CodeInternal, "failed to get settings")
+	}
+
+	return c.JSON(http.StatusOK, securitySettingsResponse{
+		CORSOrigins:    settings.CORSOrigins,
+		CookieSameSite: settings.CookieSameSite,
+		CookieSecure:   settings.CookieSecure,
+		CookieDomain:   settings.CookieDomain,
+	})
+}
+
+// UpdateSecuritySettings updates the CORS and cookie settings.
+// @Summary Update security settings
+// @Description Update allowed CORS origins and auth cookie attributes. SameSite=None requires Secure.
+// @Tags settings
+// @Accept json
+// @Produce json
+// @Param settings body securitySettingsRequest true "Security settings"
+// @Success 200 {object} securitySettingsResponse
+// @Failure 400 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /settings/security [put]
+func (h *SettingsHandler) UpdateSecuritySettings(c echo.Context) error {
+	var req securitySettingsRequest
+	if err := c.Bind(&req); err != nil {
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid request")
+	}
+
+	settings := &service.SecuritySettings{
+		CORSOrigins:    req.CORSOrigins,
+		CookieSameSite: req.CookieSameSite,
+		CookieSecure:   req.CookieSecure,
+		CookieDomain:   req.CookieDomain,
+	}
+	if err := h.service.SetSecuritySettings(c.Request().Context(), settings); err != nil {
+		if !errors.Is(err, service.ErrInsecureSameSiteNone) {
+			logger.Error("security settings update failed", "module", "handler", "action", "update", "resource", "settings", "result", "failed", "error", err)
+		}
+		return writeServiceError(c, err)
+	}
+
+	logger.Info("security settings updated", "module", "handler", "action", "update", "resource", "settings", "result", "ok", "same_site", settings.CookieSameSite, "secure", settings.CookieSecure)
+	return h.GetSecuritySettings(c)
+}
+
 // TestNetworkProxy tests the network proxy connection.
 // @Summary Test network proxy
-// @Description Test the network proxy connection by accessing https://captive.apple.com/
+// @Description Fetch testUrl (default https://captive.apple.com/) through the proxy and report the latency, plus the egress IP when testUrl echoes it (e.g. https://api.ipify.org)
 // @Tags settings
 // @Accept json
 // @Produce json
@@ -447,7 +728,7 @@ func (h *SettingsHandler) UpdateAppearanceSettings(c echo.Context) error {
 func (h *SettingsHandler) TestNetworkProxy(c echo.Context) error {
 	var req networkTestRequest
 	if err := c.Bind(&req); err != nil {
-		return c.JSON(http.StatusBadRequest, errorResponse{Error: "invalid request"})
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid request")
 	}
 
 	if !req.Enabled {
@@ -458,10 +739,18 @@ func (h *SettingsHandler) TestNetworkProxy(c echo.Context) error {
 	}
 
 	if req.Host == "" {
-		return c.JSON(http.StatusBadRequest, errorResponse{Error: "host is required"})
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "host is required")
 	}
 	if req.Port <= 0 {
-		return c.JSON(http.StatusBadRequest, errorResponse{Error: "valid port is required"})
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "valid port is required")
+	}
+	testURL := defaultProxyTestURL
+	if req.TestURL != "" {
+		parsed, err := url.Parse(req.TestURL)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid testUrl")
+		}
+		testURL = req.TestURL
 	}
 
 	// Build proxy URL from request
@@ -476,9 +765,7 @@ func (h *SettingsHandler) TestNetworkProxy(c echo.Context) error {
 		proxyURL = proxyType + "://" + req.Host + ":" + itoa(req.Port)
 	}
 
-	// Test proxy connection using https://captive.apple.com/
-	const testURL = "https://captive.apple.com/"
-	err := h.clientFactory.TestProxyWithConfig(c.Request().Context(), proxyURL, testURL)
+	result, err := h.clientFactory.TestProxyWithConfig(c.Request().Context(), proxyURL, testURL)
 	if err != nil {
 		logger.Warn("network proxy test failed", "module", "handler", "action", "test", "resource", "settings", "result", "failed", "type", proxyType, "host", req.Host, "error", err)
 		return c.JSON(http.StatusOK, networkTestResponse{
@@ -487,10 +774,12 @@ func (h *SettingsHandler) TestNetworkProxy(c echo.Context) error {
 		})
 	}
 
-	logger.Info("network proxy test ok", "module", "handler", "action", "test", "resource", "settings", "result", "ok", "type", proxyType, "host", req.Host)
+	logger.Info("network proxy test ok", "module", "handler", "action", "test", "resource", "settings", "result", "ok", "type", proxyType, "host", req.Host, "latency_ms", result.Latency.Milliseconds())
 	return c.JSON(http.StatusOK, networkTestResponse{
-		Success: true,
-		Message: "Proxy connection successful",
+		Success:   true,
+		Message:   "Proxy connection successful",
+		LatencyMs: result.Latency.Milliseconds(),
+		EgressIP:  result.EgressIP,
 	})
 }
 
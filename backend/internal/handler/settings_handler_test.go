@@ -2,6 +2,7 @@ package handler_test
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -80,6 +81,51 @@ func TestSettingsHandler_UpdateAISettings_Success(t *testing.T) {
 	require.Equal(t, http.StatusOK, rec.Code)
 }
 
+func TestSettingsHandler_UpdateAISettings_ModelPrices(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockSettingsService(ctrl)
+	h := handler.NewSettingsHandlerHelper(mockService, nil)
+	e := newTestEcho()
+
+	req := newJSONRequest(http.MethodPut, "/settings/ai", map[string]interface{}{
+		"provider":             "anthropic",
+		"model":                "claude-3-5-haiku",
+		"modelPrices":          map[string]any{"claude-3-5-haiku": map[string]any{"input": 0.8, "output": 4}},
+		"usageRetentionMonths": 3,
+	})
+	c, rec := newTestContext(e, req)
+
+	mockService.EXPECT().
+		SetAISettings(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, settings *service.AISettings) error {
+			require.Equal(t, map[string]service.AIModelPrice{"claude-3-5-haiku": {Input: 0.8, Output: 4}}, settings.ModelPrices)
+			require.Equal(t, 3, settings.UsageRetentionMonths)
+			return nil
+		})
+	mockService.EXPECT().
+		GetAISettings(gomock.Any()).
+		Return(&service.AISettings{Provider: "anthropic", Model: "claude-3-5-haiku"}, nil)
+
+	require.NoError(t, h.UpdateAISettings(c))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	for _, prices := range []map[string]any{
+		{"claude-3-5-haiku": map[string]any{"input": -1, "output": 4}},
+		{" ": map[string]any{"input": 1, "output": 4}},
+	} {
+		req := newJSONRequest(http.MethodPut, "/settings/ai", map[string]interface{}{
+			"provider":    "anthropic",
+			"model":       "claude-3-5-haiku",
+			"modelPrices": prices,
+		})
+		c, rec := newTestContext(e, req)
+		require.NoError(t, h.UpdateAISettings(c))
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+	}
+}
+
 func TestSettingsHandler_UpdateAISettings_MissingBaseURLForOpenAI(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -143,6 +189,27 @@ func TestSettingsHandler_TestNetworkProxy_InvalidParams(t *testing.T) {
 	require.Equal(t, http.StatusBadRequest, rec.Code)
 }
 
+func TestSettingsHandler_TestNetworkProxy_InvalidTestURL(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	h := handler.NewSettingsHandlerHelper(mock.NewMockSettingsService(ctrl), nil)
+	e := newTestEcho()
+
+	for _, testURL := range []string{"ftp://example.com/", "/relative", "https://"} {
+		reqBody := map[string]interface{}{
+			"enabled": true,
+			"type":    "socks5",
+			"host":    "127.0.0.1",
+			"port":    1080,
+			"testUrl": testURL,
+		}
+		c, rec := newTestContext(e, newJSONRequest(http.MethodPost, "/settings/network/test", reqBody))
+		require.NoError(t, h.TestNetworkProxy(c))
+		require.Equal(t, http.StatusBadRequest, rec.Code, testURL)
+	}
+}
+
 func TestSettingsHandler_GetGeneralSettings_Success(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -192,12 +259,15 @@ func TestSettingsHandler_UpdateGeneralSettings_Success(t *testing.T) {
 		DoAndReturn(func(_ context.Context, settings *service.GeneralSettings) error {
 			require.True(t, settings.AutoReadability)
 			require.True(t, settings.MarkReadOnScroll)
+			// Omitted entryRevisions keeps the stored value
+			require.True(t, settings.EntryRevisions)
 			return nil
 		})
 
 	mockService.EXPECT().
 		GetGeneralSettings(gomock.Any()).
-		Return(&service.GeneralSettings{AutoReadability: true, MarkReadOnScroll: true}, nil)
+		Return(&service.GeneralSettings{AutoReadability: true, MarkReadOnScroll: true, EntryRevisions: true}, nil).
+		Times(2)
 
 	err := h.UpdateGeneralSettings(c)
 	require.NoError(t, err)
@@ -205,6 +275,270 @@ func TestSettingsHandler_UpdateGeneralSettings_Success(t *testing.T) {
 	require.Equal(t, http.StatusOK, rec.Code)
 }
 
+func TestSettingsHandler_UpdateGeneralSettings_DisableEntryRevisions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockSettingsService(ctrl)
+	h := handler.NewSettingsHandlerHelper(mockService, nil)
+
+	e := newTestEcho()
+	req := newJSONRequest(http.MethodPut, "/settings/general", map[string]interface{}{
+		"entryRevisions": false,
+	})
+	c, rec := newTestContext(e, req)
+
+	mockService.EXPECT().
+		SetGeneralSettings(gomock.Any(), gomock.AssignableToTypeOf(&service.GeneralSettings{})).
+		DoAndReturn(func(_ context.Context, settings *service.GeneralSettings) error {
+			require.False(t, settings.EntryRevisions)
+			return nil
+		})
+
+	mockService.EXPECT().
+		GetGeneralSettings(gomock.Any()).
+		Return(&service.GeneralSettings{}, nil).
+		Times(2)
+
+	err := h.UpdateGeneralSettings(c)
+	require.NoError(t, err)
+
+	var resp handler.GeneralSettingsResponse
+	assertJSONResponse(t, rec, http.StatusOK, &resp)
+	require.False(t, resp.EntryRevisions)
+}
+
+func TestSettingsHandler_UpdateGeneralSettings_ImageCache(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockSettingsService(ctrl)
+	h := handler.NewSettingsHandlerHelper(mockService, nil)
+
+	e := newTestEcho()
+	req := newJSONRequest(http.MethodPut, "/settings/general", map[string]interface{}{
+		"imageCache":             true,
+		"imageCacheEntryLimitMb": 5,
+	})
+	c, rec := newTestContext(e, req)
+
+	stored := &service.GeneralSettings{EntryRevisions: true, ImageCacheEntryLimitMB: 20, ImageCacheTotalLimitMB: 512}
+	mockService.EXPECT().
+		SetGeneralSettings(gomock.Any(), gomock.AssignableToTypeOf(&service.GeneralSettings{})).
+		DoAndReturn(func(_ context.Context, settings *service.GeneralSettings) error {
+			require.True(t, settings.ImageCache)
+			require.Equal(t, 5, settings.ImageCacheEntryLimitMB)
+			// Omitted total limit keeps the stored value
+			require.Equal(t, 512, settings.ImageCacheTotalLimitMB)
+			require.True(t, settings.EntryRevisions)
+			return nil
+		})
+	mockService.EXPECT().GetGeneralSettings(gomock.Any()).Return(stored, nil)
+	mockService.EXPECT().
+		GetGeneralSettings(gomock.Any()).
+		Return(&service.GeneralSettings{ImageCache: true, ImageCacheEntryLimitMB: 5, ImageCacheTotalLimitMB: 512}, nil)
+
+	err := h.UpdateGeneralSettings(c)
+	require.NoError(t, err)
+
+	var resp handler.GeneralSettingsResponse
+	assertJSONResponse(t, rec, http.StatusOK, &resp)
+	require.True(t, resp.ImageCache)
+	require.Equal(t, 5, resp.ImageCacheEntryLimitMB)
+	require.Equal(t, 512, resp.ImageCacheTotalLimitMB)
+}
+
+func TestSettingsHandler_UpdateGeneralSettings_InvalidImageCacheLimit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockSettingsService(ctrl)
+	h := handler.NewSettingsHandlerHelper(mockService, nil)
+
+	e := newTestEcho()
+	req := newJSONRequest(http.MethodPut, "/settings/general", map[string]interface{}{
+		"imageCacheTotalLimitMb": 0,
+	})
+	c, rec := newTestContext(e, req)
+
+	err := h.UpdateGeneralSettings(c)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestSettingsHandler_UpdateGeneralSettings_Timezone(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockSettingsService(ctrl)
+	h := handler.NewSettingsHandlerHelper(mockService, nil)
+
+	e := newTestEcho()
+	req := newJSONRequest(http.MethodPut, "/settings/general", map[string]interface{}{
+		"timezone": "Asia/Shanghai",
+	})
+	c, rec := newTestContext(e, req)
+
+	mockService.EXPECT().
+		SetGeneralSettings(gomock.Any(), gomock.AssignableToTypeOf(&service.GeneralSettings{})).
+		DoAndReturn(func(_ context.Context, settings *service.GeneralSettings) error {
+			require.Equal(t, "Asia/Shanghai", settings.Timezone)
+			return nil
+		})
+	mockService.EXPECT().GetGeneralSettings(gomock.Any()).Return(&service.GeneralSettings{Timezone: "UTC"}, nil)
+	mockService.EXPECT().GetGeneralSettings(gomock.Any()).Return(&service.GeneralSettings{Timezone: "Asia/Shanghai"}, nil)
+
+	err := h.UpdateGeneralSettings(c)
+	require.NoError(t, err)
+
+	var resp handler.GeneralSettingsResponse
+	assertJSONResponse(t, rec, http.StatusOK, &resp)
+	require.Equal(t, "Asia/Shanghai", resp.Timezone)
+}
+
+func TestSettingsHandler_UpdateGeneralSettings_InvalidTimezone(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockSettingsService(ctrl)
+	h := handler.NewSettingsHandlerHelper(mockService, nil)
+	e := newTestEcho()
+
+	for _, tz := range []string{"", "Mars/Olympus"} {
+		req := newJSONRequest(http.MethodPut, "/settings/general", map[string]interface{}{
+			"timezone": tz,
+		})
+		c, rec := newTestContext(e, req)
+
+		err := h.UpdateGeneralSettings(c)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadRequest, rec.Code, tz)
+	}
+}
+
+func TestSettingsHandler_UpdateGeneralSettings_InvalidFallbackUserAgent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockSettingsService(ctrl)
+	h := handler.NewSettingsHandlerHelper(mockService, nil)
+	mockService.EXPECT().GetGeneralSettings(gomock.Any()).Return(&service.GeneralSettings{}, nil)
+	mockService.EXPECT().SetGeneralSettings(gomock.Any(), gomock.Any()).Return(fmt.Errorf("fallback user agent: %w", service.ErrInvalid))
+
+	req := newJSONRequest(http.MethodPut, "/settings/general", map[string]interface{}{
+		"fallbackUserAgent": "UA\r\nX-Injected: 1",
+	})
+	c, rec := newTestContext(newTestEcho(), req)
+
+	require.NoError(t, h.UpdateGeneralSettings(c))
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestSettingsHandler_UpdateGeneralSettings_Concurrency(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockSettingsService(ctrl)
+	h := handler.NewSettingsHandlerHelper(mockService, nil)
+
+	e := newTestEcho()
+	req := newJSONRequest(http.MethodPut, "/settings/general", map[string]interface{}{
+		"maxConcurrentRefresh": 4,
+	})
+	c, rec := newTestContext(e, req)
+
+	stored := &service.GeneralSettings{MaxConcurrentRefresh: 8, MaxConcurrentPerHost: 2}
+	mockService.EXPECT().GetGeneralSettings(gomock.Any()).Return(stored, nil)
+	mockService.EXPECT().
+		SetGeneralSettings(gomock.Any(), gomock.AssignableToTypeOf(&service.GeneralSettings{})).
+		DoAndReturn(func(_ context.Context, settings *service.GeneralSettings) error {
+			require.Equal(t, 4, settings.MaxConcurrentRefresh)
+			require.Equal(t, 2, settings.MaxConcurrentPerHost)
+			return nil
+		})
+	mockService.EXPECT().GetGeneralSettings(gomock.Any()).Return(&service.GeneralSettings{MaxConcurrentRefresh: 4, MaxConcurrentPerHost: 2}, nil)
+
+	err := h.UpdateGeneralSettings(c)
+	require.NoError(t, err)
+
+	var resp handler.GeneralSettingsResponse
+	assertJSONResponse(t, rec, http.StatusOK, &resp)
+	require.Equal(t, 4, resp.MaxConcurrentRefresh)
+	require.Equal(t, 2, resp.MaxConcurrentPerHost)
+}
+
+func TestSettingsHandler_UpdateGeneralSettings_InvalidConcurrency(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockSettingsService(ctrl)
+	h := handler.NewSettingsHandlerHelper(mockService, nil)
+	e := newTestEcho()
+
+	for _, body := range []map[string]interface{}{
+		{"maxConcurrentRefresh": 0},
+		{"maxConcurrentRefresh": service.MaxConcurrentRefreshLimit + 1},
+		{"maxConcurrentPerHost": 0},
+		{"maxConcurrentPerHost": service.MaxConcurrentPerHostLimit + 1},
+	} {
+		req := newJSONRequest(http.MethodPut, "/settings/general", body)
+		c, rec := newTestContext(e, req)
+
+		err := h.UpdateGeneralSettings(c)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadRequest, rec.Code, body)
+	}
+}
+
+func TestSettingsHandler_UpdateGeneralSettings_AnubisRetry(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockSettingsService(ctrl)
+	h := handler.NewSettingsHandlerHelper(mockService, nil)
+	e := newTestEcho()
+
+	for _, body := range []map[string]interface{}{
+		{"anubisMaxRetries": 0},
+		{"anubisMaxRetries": service.AnubisMaxRetriesLimit + 1},
+		{"anubisRetryDelayMs": -1},
+		{"anubisRetryDelayMs": service.AnubisRetryDelayLimitMs + 1},
+		{"unconditionalFetchAfter": 0},
+		{"unconditionalFetchAfter": service.UnconditionalFetchAfterLimit + 1},
+	} {
+		req := newJSONRequest(http.MethodPut, "/settings/general", body)
+		c, rec := newTestContext(e, req)
+
+		err := h.UpdateGeneralSettings(c)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadRequest, rec.Code, body)
+	}
+
+	req := newJSONRequest(http.MethodPut, "/settings/general", map[string]interface{}{
+		"anubisMaxRetries": 4,
+	})
+	c, rec := newTestContext(e, req)
+
+	stored := &service.GeneralSettings{AnubisMaxRetries: 2, AnubisRetryDelayMs: 500}
+	mockService.EXPECT().GetGeneralSettings(gomock.Any()).Return(stored, nil)
+	mockService.EXPECT().
+		SetGeneralSettings(gomock.Any(), gomock.AssignableToTypeOf(&service.GeneralSettings{})).
+		DoAndReturn(func(_ context.Context, settings *service.GeneralSettings) error {
+			require.Equal(t, 4, settings.AnubisMaxRetries)
+			require.Equal(t, 500, settings.AnubisRetryDelayMs)
+			return nil
+		})
+	mockService.EXPECT().GetGeneralSettings(gomock.Any()).Return(&service.GeneralSettings{AnubisMaxRetries: 4, AnubisRetryDelayMs: 500}, nil)
+
+	err := h.UpdateGeneralSettings(c)
+	require.NoError(t, err)
+
+	var resp handler.GeneralSettingsResponse
+	assertJSONResponse(t, rec, http.StatusOK, &resp)
+	require.Equal(t, 4, resp.AnubisMaxRetries)
+	require.Equal(t, 500, resp.AnubisRetryDelayMs)
+}
+
 func TestSettingsHandler_GetAppearanceSettings_Success(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -260,6 +594,36 @@ func TestSettingsHandler_UpdateAppearanceSettings_Success(t *testing.T) {
 	require.Equal(t, http.StatusOK, rec.Code)
 }
 
+func TestSettingsHandler_UpdateAppearanceSettings_StaleVersion(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockSettingsService(ctrl)
+	h := handler.NewSettingsHandlerHelper(mockService, nil)
+
+	e := newTestEcho()
+	reqBody := map[string]interface{}{
+		"contentTypes": []string{"article"},
+		"version":      "2",
+	}
+	req := newJSONRequest(http.MethodPut, "/settings/appearance", reqBody)
+	c, rec := newTestContext(e, req)
+
+	mockService.EXPECT().
+		SetAppearanceSettings(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, settings *service.AppearanceSettings) error {
+			require.Equal(t, "2", settings.Version)
+			return fmt.Errorf("set appearance content types: %w", service.ErrConflict)
+		})
+
+	err := h.UpdateAppearanceSettings(c)
+	require.NoError(t, err)
+
+	var resp handler.ErrorResponse
+	assertJSONResponse(t, rec, http.StatusConflict, &resp)
+	require.Equal(t, handler.CodeConflict, resp.Code)
+}
+
 func TestSettingsHandler_TestAI_Success(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -453,3 +817,81 @@ func TestSettingsHandler_UpdateNetworkSettings_InvalidRequest(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, http.StatusBadRequest, rec.Code)
 }
+
+func TestSettingsHandler_UpdateSecuritySettings_SameSiteNoneRequiresSecure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockSettingsService(ctrl)
+	h := handler.NewSettingsHandlerHelper(mockService, nil)
+
+	e := newTestEcho()
+	req := newJSONRequest(http.MethodPut, "/settings/security", map[string]interface{}{
+		"corsOrigins":    []string{"https://app.example.com"},
+		"cookieSameSite": "none",
+		"cookieSecure":   false,
+	})
+	c, rec := newTestContext(e, req)
+
+	mockService.EXPECT().
+		SetSecuritySettings(gomock.Any(), gomock.Any()).
+		Return(service.ErrInsecureSameSiteNone)
+
+	err := h.UpdateSecuritySettings(c)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestSettingsHandler_UpdateGeneralSettings_QuietHours(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockSettingsService(ctrl)
+	h := handler.NewSettingsHandlerHelper(mockService, nil)
+
+	e := newTestEcho()
+	req := newJSONRequest(http.MethodPut, "/settings/general", map[string]interface{}{
+		"quietHoursStart": "23:30",
+	})
+	c, rec := newTestContext(e, req)
+
+	stored := &service.GeneralSettings{Timezone: "UTC", QuietHoursStart: "22:00", QuietHoursEnd: "07:00"}
+	mockService.EXPECT().GetGeneralSettings(gomock.Any()).Return(stored, nil)
+	mockService.EXPECT().
+		SetGeneralSettings(gomock.Any(), gomock.AssignableToTypeOf(&service.GeneralSettings{})).
+		DoAndReturn(func(_ context.Context, settings *service.GeneralSettings) error {
+			require.Equal(t, "23:30", settings.QuietHoursStart)
+			require.Equal(t, "07:00", settings.QuietHoursEnd)
+			return nil
+		})
+	mockService.EXPECT().GetGeneralSettings(gomock.Any()).Return(&service.GeneralSettings{QuietHoursStart: "23:30", QuietHoursEnd: "07:00"}, nil)
+
+	require.NoError(t, h.UpdateGeneralSettings(c))
+
+	var resp handler.GeneralSettingsResponse
+	assertJSONResponse(t, rec, http.StatusOK, &resp)
+	require.Equal(t, "23:30", resp.QuietHoursStart)
+	require.Equal(t, "07:00", resp.QuietHoursEnd)
+}
+
+func TestSettingsHandler_UpdateGeneralSettings_InvalidQuietHours(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock.NewMockSettingsService(ctrl)
+	h := handler.NewSettingsHandlerHelper(mockService, nil)
+	mockService.EXPECT().GetGeneralSettings(gomock.Any()).Return(&service.GeneralSettings{Timezone: "UTC"}, nil).AnyTimes()
+	e := newTestEcho()
+
+	for _, window := range []map[string]interface{}{
+		{"quietHoursStart": "22:00"},
+		{"quietHoursStart": "25:00", "quietHoursEnd": "07:00"},
+		{"quietHoursStart": "10pm", "quietHoursEnd": "7am"},
+	} {
+		req := newJSONRequest(http.MethodPut, "/settings/general", window)
+		c, rec := newTestContext(e, req)
+
+		require.NoError(t, h.UpdateGeneralSettings(c))
+		require.Equal(t, http.StatusBadRequest, rec.Code, window)
+	}
+}
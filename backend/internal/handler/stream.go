@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Content types of streamed responses.
+const (
+	mimeEventStream = "text/event-stream"
+	mimeNDJSON      = "application/x-ndjson"
+)
+
+// streamTextEvent carries a chunk of streamed text in NDJSON mode.
+type streamTextEvent struct {
+	Text string `json:"text"`
+}
+
+// streamErrorEvent reports a failure in the middle of an NDJSON stream.
+type streamErrorEvent struct {
+	Error string `json:"error"`
+}
+
+// streamWriter writes the events of a streamed response and flushes after
+// each one, so they reach the client even through buffering proxies.
+type streamWriter struct {
+	res    *echo.Response
+	ndjson bool
+}
+
+// acceptsNDJSON reports whether the client asked for newline-delimited JSON
+// instead of server-sent events, for proxies that buffer text/event-stream.
+func acceptsNDJSON(c echo.Context) bool {
+	return strings.Contains(c.Request().Header.Get(echo.HeaderAccept), mimeNDJSON)
+}
+
+// setStreamHeaders sets the headers of a streamed response. no-transform and
+// X-Accel-Buffering keep proxies such as nginx from compressing or buffering it.
+func setStreamHeaders(res *echo.Response, contentType string) {
+	res.Header().Set(echo.HeaderContentType, contentType)
+	res.Header().Set(echo.HeaderCacheControl, "no-cache, no-transform")
+	res.Header().Set(echo.HeaderConnection, "keep-alive")
+	res.Header().Set("X-Accel-Buffering", "no")
+}
+
+// startStream sends the headers of a streamed response of contentType, which
+// is mimeEventStream or mimeNDJSON. Extra headers must be set before calling it.
+func startStream(c echo.Context, contentType string) *streamWriter {
+	res := c.Response()
+	setStreamHeaders(res, contentType)
+	res.WriteHeader(http.StatusOK)
+	res.Flush()
+	return &streamWriter{res: res, ndjson: contentType == mimeNDJSON}
+}
+
+// negotiateStream starts a server-sent event stream, or an NDJSON one when
+// the client accepts it.
+func negotiateStream(c echo.Context) *streamWriter {
+	if acceptsNDJSON(c) {
+		return startStream(c, mimeNDJSON)
+	}
+	return startStream(c, mimeEventStream)
+}
+
+// JSON writes v as one event: a data line in SSE mode, a line of its own in
+// NDJSON mode.
+func (w *streamWriter) JSON(v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if w.ndjson {
+		_, err = fmt.Fprintf(w.res, "%s\n", data)
+	} else {
+		_, err = fmt.Fprintf(w.res, "data: %s\n\n", data)
+	}
+	if err != nil {
+		return err
+	}
+	w.res.Flush()
+	return nil
+}
+
+// Text writes a chunk of generated text. SSE mode sends it as is for simpler
+// client handling; NDJSON mode wraps it in a streamTextEvent.
+func (w *streamWriter) Text(text string) error {
+	if w.ndjson {
+		return w.JSON(streamTextEvent{Text: text})
+	}
+	if _, err := w.res.Write([]byte(text)); err != nil {
+		return err
+	}
+	w.res.Flush()
+	return nil
+}
+
+// Error reports a failure of a text stream: an error event in SSE mode, a
+// streamErrorEvent in NDJSON mode.
+func (w *streamWriter) Error(message string) error {
+	if w.ndjson {
+		return w.JSON(streamErrorEvent{Error: message})
+	}
+	if _, err := fmt.Fprintf(w.res, "event: error\ndata: %s\n\n", message); err != nil {
+		return err
+	}
+	w.res.Flush()
+	return nil
+}
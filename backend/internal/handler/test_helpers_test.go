@@ -52,6 +52,25 @@ func newTestContext(e *echo.Echo, req *http.Request) (echo.Context, *httptest.Re
 	return c, rec
 }
 
+// flushRecorder is a ResponseRecorder that keeps the body written so far at
+// each flush, to check that a stream flushes every event.
+type flushRecorder struct {
+	*httptest.ResponseRecorder
+	flushes []string
+}
+
+func (r *flushRecorder) Flush() {
+	r.flushes = append(r.flushes, r.Body.String())
+	r.ResponseRecorder.Flush()
+}
+
+// newFlushContext creates a new Echo context recording into a flushRecorder.
+func newFlushContext(e *echo.Echo, req *http.Request) (echo.Context, *flushRecorder) {
+	rec := &flushRecorder{ResponseRecorder: httptest.NewRecorder()}
+	c := e.NewContext(req, rec)
+	return c, rec
+}
+
 // setPathParams sets path parameters on the Echo context.
 func setPathParams(c echo.Context, params map[string]string) {
 	names := make([]string, 0, len(params))
@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/png"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"gist/backend/internal/service"
+)
+
+const (
+	// thumbnailCacheControl lets browsers keep generated thumbnails for 30 days.
+	thumbnailCacheControl = "private, max-age=2592000"
+	// placeholderCacheControl matches how long the server skips a failed thumbnail.
+	placeholderCacheControl = "private, max-age=3600"
+)
+
+// transparentPixel is served instead of thumbnails that can't be loaded, so
+// the picture grid shows no broken-image icon.
+var transparentPixel = func() []byte {
+	var buf bytes.Buffer
+	_ = png.Encode(&buf, image.NewNRGBA(image.Rect(0, 0, 1, 1)))
+	return buf.Bytes()
+}()
+
+type ThumbnailHandler struct {
+	service service.ThumbnailService
+}
+
+func NewThumbnailHandler(thumbnailService service.ThumbnailService) *ThumbnailHandler {
+	return &ThumbnailHandler{service: thumbnailService}
+}
+
+func (h *ThumbnailHandler) RegisterRoutes(g *echo.Group) {
+	g.GET("/entries/:id/thumbnail", h.GetThumbnail)
+}
+
+// GetThumbnail serves an entry's thumbnail scaled down for the picture grid.
+// @Summary Get entry thumbnail
+// @Description Serve the entry thumbnail as a JPEG at most w pixels wide, generated on first request and cached on disk. When the image can't be fetched or decoded, a 1x1 transparent PNG is served instead and the image is not retried for an hour.
+// @Tags entries
+// @Produce jpeg
+// @Produce png
+// @Param id path int true "Entry ID"
+// @Param w query int false "Width in pixels, 32 to 1600 (default 400)"
+// @Success 200 {file} binary "Thumbnail image"
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Router /entries/{id}/thumbnail [get]
+func (h *ThumbnailHandler) GetThumbnail(c echo.Context) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid id")
+	}
+	width := service.DefaultThumbnailWidth
+	if raw := c.QueryParam("w"); raw != "" {
+		width, err = strconv.Atoi(raw)
+		if err != nil || width < service.MinThumbnailWidth || width > service.MaxThumbnailWidth {
+			return Error(c, http.StatusBadRequest, CodeInvalidRequest, "invalid w")
+		}
+	}
+
+	path, err := h.service.Thumbnail(c.Request().Context(), id, width)
+	if errors.Is(err, service.ErrThumbnailUnavailable) {
+		c.Response().Header().Set("Cache-Control", placeholderCacheControl)
+		return c.Blob(http.StatusOK, "image/png", transparentPixel)
+	}
+	if err != nil {
+		return writeServiceError(c, err)
+	}
+
+	c.Response().Header().Set("Cache-Control", thumbnailCacheControl)
+	return c.File(path)
+}
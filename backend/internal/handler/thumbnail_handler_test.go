@@ -0,0 +1,75 @@
+package handler_test
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"gist/backend/internal/handler"
+	"gist/backend/internal/service"
+	"gist/backend/internal/service/mock"
+)
+
+func getThumbnail(t *testing.T, svc service.ThumbnailService, target string) *http.Response {
+	t.Helper()
+	e := newTestEcho()
+	c, rec := newTestContext(e, newJSONRequest(http.MethodGet, target, nil))
+	setPathParams(c, map[string]string{"id": "1"})
+	require.NoError(t, handler.NewThumbnailHandler(svc).GetThumbnail(c))
+	return rec.Result()
+}
+
+func TestThumbnailHandler_GetThumbnail(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockService := mock.NewMockThumbnailService(ctrl)
+
+	path := filepath.Join(t.TempDir(), "1-400.jpg")
+	require.NoError(t, os.WriteFile(path, []byte("jpeg-data"), 0o600))
+	mockService.EXPECT().Thumbnail(gomock.Any(), int64(1), service.DefaultThumbnailWidth).Return(path, nil)
+
+	resp := getThumbnail(t, mockService, "/api/entries/1/thumbnail")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "private, max-age=2592000", resp.Header.Get("Cache-Control"))
+	require.Equal(t, "image/jpeg", resp.Header.Get("Content-Type"))
+}
+
+func TestThumbnailHandler_GetThumbnail_Unavailable(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockService := mock.NewMockThumbnailService(ctrl)
+	mockService.EXPECT().Thumbnail(gomock.Any(), int64(1), 800).Return("", service.ErrThumbnailUnavailable)
+
+	resp := getThumbnail(t, mockService, "/api/entries/1/thumbnail?w=800")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "image/png", resp.Header.Get("Content-Type"))
+	require.Equal(t, "private, max-age=3600", resp.Header.Get("Cache-Control"))
+
+	var body bytes.Buffer
+	_, err := body.ReadFrom(resp.Body)
+	require.NoError(t, err)
+	img, err := png.Decode(&body)
+	require.NoError(t, err)
+	require.Equal(t, image.Rect(0, 0, 1, 1), img.Bounds())
+	_, _, _, alpha := img.At(0, 0).RGBA()
+	require.Zero(t, alpha)
+}
+
+func TestThumbnailHandler_GetThumbnail_Errors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockService := mock.NewMockThumbnailService(ctrl)
+
+	for _, target := range []string{"/api/entries/1/thumbnail?w=abc", "/api/entries/1/thumbnail?w=10", "/api/entries/1/thumbnail?w=5000"} {
+		resp := getThumbnail(t, mockService, target)
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode, target)
+	}
+
+	mockService.EXPECT().Thumbnail(gomock.Any(), int64(1), 400).Return("", service.ErrNotFound)
+	resp := getThumbnail(t, mockService, "/api/entries/1/thumbnail?w=400")
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
@@ -1,14 +1,19 @@
 package http
 
 import (
+	"bytes"
+	"context"
+	"io"
 	"net/http"
 	"strings"
 	"time"
 
 	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
 
-	"gist/backend/pkg/logger"
+	"gist/backend/internal/handler"
 	"gist/backend/internal/service"
+	"gist/backend/pkg/logger"
 )
 
 // AuthCookieName is the name of the authentication cookie.
@@ -86,7 +91,8 @@ func RequestLoggerMiddleware() echo.MiddlewareFunc {
 
 // JWTAuthMiddleware creates a middleware that validates JWT tokens.
 // It checks both Authorization header (for API calls) and Cookie (for browser resource requests like images).
-func JWTAuthMiddleware(authService service.AuthService) echo.MiddlewareFunc {
+// Bearer values carrying the API token prefix are validated as personal access tokens instead.
+func JWTAuthMiddleware(authService service.AuthService, apiTokenService service.APITokenService, settingsService service.SettingsService) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
 			var token string
@@ -100,6 +106,24 @@ func JWTAuthMiddleware(authService service.AuthService) echo.MiddlewareFunc {
 				}
 			}
 
+			// Personal access tokens never expire with the JWT and are not stored in cookies
+			if token != "" && service.IsAPIToken(token) {
+				valid, err := apiTokenService.Validate(c.Request().Context(), token)
+				if err != nil || !valid {
+					logger.Warn("auth invalid api token",
+						"module", "http",
+						"action", "request",
+						"resource", "auth",
+						"result", "failed",
+						"method", c.Request().Method,
+						"path", c.Request().URL.Path,
+						"remote_ip", c.RealIP(),
+					)
+					return handler.Error(c, http.StatusUnauthorized, handler.CodeAuthInvalidToken, "invalid token")
+				}
+				return next(c)
+			}
+
 			// Fallback to cookie (for image/resource requests)
 			if token == "" {
 				if cookie, err := c.Cookie(AuthCookieName); err == nil && cookie.Value != "" {
@@ -117,15 +141,13 @@ func JWTAuthMiddleware(authService service.AuthService) echo.MiddlewareFunc {
 					"path", c.Request().URL.Path,
 					"remote_ip", c.RealIP(),
 				)
-				return c.JSON(http.StatusUnauthorized, map[string]string{
-					"error": "missing authentication",
-				})
+				return handler.Error(c, http.StatusUnauthorized, handler.CodeAuthRequired, "missing authentication")
 			}
 
 			// Validate token
 			valid, err := authService.ValidateToken(token)
 			if err != nil || !valid {
-				clearAuthCookie(c)
+				handler.ClearAuthCookie(c, settingsService)
 				logger.Warn("auth invalid",
 					"module", "http",
 					"action", "request",
@@ -135,9 +157,7 @@ func JWTAuthMiddleware(authService service.AuthService) echo.MiddlewareFunc {
 					"path", c.Request().URL.Path,
 					"remote_ip", c.RealIP(),
 				)
-				return c.JSON(http.StatusUnauthorized, map[string]string{
-					"error": "invalid token",
-				})
+				return handler.Error(c, http.StatusUnauthorized, handler.CodeAuthInvalidToken, "invalid token")
 			}
 
 			return next(c)
@@ -145,15 +165,116 @@ func JWTAuthMiddleware(authService service.AuthService) echo.MiddlewareFunc {
 	}
 }
 
-func clearAuthCookie(c echo.Context) {
-	cookie := &http.Cookie{
-		Name:     AuthCookieName,
-		Value:    "",
-		Path:     "/",
-		HttpOnly: true,
-		Secure:   c.Request().TLS != nil,
-		SameSite: http.SameSiteLaxMode,
-		MaxAge:   -1,
+// Default request body limits, overridable through BodyLimits.
+const (
+	DefaultBodyLimit         = 1 << 20
+	DefaultImportBodyLimit   = 10 << 20
+	DefaultSettingsBodyLimit = 256 << 10
+)
+
+// BodyLimits are the request body limits in bytes. Zero fields take the
+// defaults.
+type BodyLimits struct {
+	// Default applies to routes without a limit of their own.
+	Default int64
+	// Import applies to routes taking documents: OPML, static feeds and
+	// pushed entries. Their handlers bound the document too.
+	Import int64
+	// Settings applies to the auth, settings and domain rate limit routes.
+	Settings int64
+}
+
+// importBodyRoutes take documents rather than requests.
+var importBodyRoutes = map[string]bool{
+	"/api/opml/import":       true,
+	"/api/feeds/static":      true,
+	"/api/feeds/:id/static":  true,
+	"/api/feeds/:id/entries": true,
+}
+
+// unlimitedBodyRoutes stream their bodies under a cap of their own.
+var unlimitedBodyRoutes = map[string]bool{
+	"/api/import/full": true,
+}
+
+// settingsBodyPrefixes start the routes under the settings limit.
+var settingsBodyPrefixes = []string{"/api/auth/", "/api/settings/", "/api/domain-rate-limits"}
+
+// limitFor returns the body limit of route, 0 for none.
+func (l BodyLimits) limitFor(route string) int64 {
+	if unlimitedBodyRoutes[route] {
+		return 0
+	}
+	if importBodyRoutes[route] {
+		return orDefault(l.Import, DefaultImportBodyLimit)
+	}
+	for _, prefix := range settingsBodyPrefixes {
+		if strings.HasPrefix(route, prefix) {
+			return orDefault(l.Settings, DefaultSettingsBodyLimit)
+		}
+	}
+	return orDefault(l.Default, DefaultBodyLimit)
+}
+
+func orDefault(value, fallback int64) int64 {
+	if value <= 0 {
+		return fallback
 	}
-	c.SetCookie(cookie)
+	return value
+}
+
+// BodyLimitMiddleware answers 413 to requests whose body is over the limit of
+// their route, before any handler reads it. Bodies of unknown length are read
+// up to the limit first, so they are refused alike.
+func BodyLimitMiddleware(limits BodyLimits) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			limit := limits.limitFor(c.Path())
+			if limit == 0 || req.Body == nil || req.Body == http.NoBody {
+				return next(c)
+			}
+
+			size := req.ContentLength
+			if size < 0 {
+				body, err := io.ReadAll(io.LimitReader(req.Body, limit+1))
+				if err != nil {
+					logger.Debug("http request body read failed", "module", "http", "action", "request", "resource", "http", "result", "failed", "path", c.Path(), "error", err)
+					return handler.Error(c, http.StatusBadRequest, handler.CodeInvalidRequest, "invalid request")
+				}
+				size = int64(len(body))
+				req.Body = io.NopCloser(bytes.NewReader(body))
+			}
+			if size > limit {
+				logger.Warn("http request body too large", "module", "http", "action", "request", "resource", "http", "result", "failed", "path", c.Path(), "limit", limit)
+				return handler.ErrorWithDetails(c, http.StatusRequestEntityTooLarge, handler.CodePayloadTooLarge, "request body too large", map[string]any{"maxBytes": limit})
+			}
+			return next(c)
+		}
+	}
+}
+
+// CORSMiddleware allows cross-origin API calls from the configured origins.
+// Origins are read per request so changes in the security settings apply without restart.
+func CORSMiddleware(settingsService service.SettingsService) echo.MiddlewareFunc {
+	return middleware.CORSWithConfig(middleware.CORSConfig{
+		AllowOriginFunc: func(origin string) (bool, error) {
+			settings, err := settingsService.GetSecuritySettings(context.Background())
+			if err != nil || settings == nil {
+				return false, nil
+			}
+			origin = strings.ToLower(origin)
+			for _, allowed := range settings.CORSOrigins {
+				if allowed == origin {
+					return true, nil
+				}
+			}
+			return false, nil
+		},
+		AllowMethods:     []string{http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete},
+		AllowHeaders:     []string{echo.HeaderAuthorization, echo.HeaderContentType, echo.HeaderIfModifiedSince, "If-None-Match"},
+		ExposeHeaders:    []string{"X-Text-Truncated", "X-Digest-Entries", echo.HeaderLastModified, "ETag"},
+		AllowCredentials: true,
+		MaxAge:           600,
+	})
 }
@@ -1,9 +1,12 @@
 package http_test
 
 import (
+	"encoding/json"
 	"errors"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	gh "gist/backend/internal/http"
@@ -11,6 +14,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 
+	"gist/backend/internal/service"
 	"gist/backend/internal/service/mock"
 )
 
@@ -19,7 +23,10 @@ func TestJWTAuthMiddleware(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockAuth := mock.NewMockAuthService(ctrl)
-	middleware := gh.JWTAuthMiddleware(mockAuth)
+	mockTokens := mock.NewMockAPITokenService(ctrl)
+	mockSettings := mock.NewMockSettingsService(ctrl)
+	mockSettings.EXPECT().GetSecuritySettings(gomock.Any()).Return(&service.SecuritySettings{CookieSameSite: "lax"}, nil).AnyTimes()
+	middleware := gh.JWTAuthMiddleware(mockAuth, mockTokens, mockSettings)
 
 	e := echo.New()
 	handler := func(c echo.Context) error {
@@ -101,6 +108,32 @@ func TestJWTAuthMiddleware(t *testing.T) {
 		require.NoError(t, err)
 		require.Equal(t, http.StatusOK, rec.Code)
 	})
+
+	t.Run("ValidAPIToken", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Authorization", "Bearer gist_pat_abc")
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		mockTokens.EXPECT().Validate(gomock.Any(), "gist_pat_abc").Return(true, nil)
+
+		err := middleware(handler)(c)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("RevokedAPIToken", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Authorization", "Bearer gist_pat_revoked")
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		mockTokens.EXPECT().Validate(gomock.Any(), "gist_pat_revoked").Return(false, errors.New("invalid token"))
+
+		err := middleware(handler)(c)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
 }
 
 func TestRequestLoggerMiddleware_StatusBranches(t *testing.T) {
@@ -132,3 +165,162 @@ func TestRequestLoggerMiddleware_StatusBranches(t *testing.T) {
 		})
 	}
 }
+
+func TestCORSMiddleware_AllowsConfiguredOrigins(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSettings := mock.NewMockSettingsService(ctrl)
+	mockSettings.EXPECT().GetSecuritySettings(gomock.Any()).Return(&service.SecuritySettings{
+		CORSOrigins:    []string{"https://app.example.com"},
+		CookieSameSite: "lax",
+	}, nil).AnyTimes()
+
+	e := echo.New()
+	e.Use(gh.CORSMiddleware(mockSettings))
+	e.GET("/", func(c echo.Context) error {
+		return c.String(http.StatusOK, "ok")
+	})
+
+	t.Run("Allowed", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Origin", "https://app.example.com")
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		require.Equal(t, "https://app.example.com", rec.Header().Get("Access-Control-Allow-Origin"))
+		require.Equal(t, "true", rec.Header().Get("Access-Control-Allow-Credentials"))
+	})
+
+	t.Run("Disallowed", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Origin", "https://evil.example.com")
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		require.Empty(t, rec.Header().Get("Access-Control-Allow-Origin"))
+	})
+
+	// Polling clients revalidate cross-origin with the ETag they were shown
+	t.Run("ConditionalPreflight", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodOptions, "/", nil)
+		req.Header.Set("Origin", "https://app.example.com")
+		req.Header.Set("Access-Control-Request-Method", http.MethodGet)
+		req.Header.Set("Access-Control-Request-Headers", "If-None-Match")
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		require.Equal(t, http.StatusNoContent, rec.Code)
+		require.Contains(t, rec.Header().Get("Access-Control-Allow-Headers"), "If-None-Match")
+		require.Contains(t, rec.Header().Get("Access-Control-Allow-Headers"), "If-Modified-Since")
+	})
+
+	t.Run("ExposesValidators", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Origin", "https://app.example.com")
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		require.Contains(t, rec.Header().Get("Access-Control-Expose-Headers"), "ETag")
+		require.Contains(t, rec.Header().Get("Access-Control-Expose-Headers"), "Last-Modified")
+		require.Contains(t, rec.Header().Get("Access-Control-Expose-Headers"), "X-Digest-Entries")
+	})
+}
+
+func TestBodyLimitMiddleware_PerRoute(t *testing.T) {
+	e := echo.New()
+	e.Use(gh.BodyLimitMiddleware(gh.BodyLimits{Default: 1000, Import: 5000, Settings: 100}))
+	echoBody := func(c echo.Context) error {
+		body, err := io.ReadAll(c.Request().Body)
+		if err != nil {
+			return err
+		}
+		return c.String(http.StatusOK, string(body))
+	}
+
+	tests := []struct {
+		method string
+		path   string
+		target string
+		limit  int
+	}{
+		{method: http.MethodPost, path: "/api/ai/summarize", target: "/api/ai/summarize", limit: 1000},
+		{method: http.MethodPost, path: "/api/ai/translate", target: "/api/ai/translate", limit: 1000},
+		{method: http.MethodPut, path: "/api/feeds/:id", target: "/api/feeds/1", limit: 1000},
+		{method: http.MethodPost, path: "/api/opml/import", target: "/api/opml/import", limit: 5000},
+		{method: http.MethodPost, path: "/api/feeds/static", target: "/api/feeds/static", limit: 5000},
+		{method: http.MethodPut, path: "/api/feeds/:id/static", target: "/api/feeds/1/static", limit: 5000},
+		{method: http.MethodPost, path: "/api/feeds/:id/entries", target: "/api/feeds/1/entries", limit: 5000},
+		{method: http.MethodPost, path: "/api/auth/login", target: "/api/auth/login", limit: 100},
+		{method: http.MethodPut, path: "/api/settings/general", target: "/api/settings/general", limit: 100},
+		{method: http.MethodPut, path: "/api/domain-rate-limits/:host", target: "/api/domain-rate-limits/example.com", limit: 100},
+	}
+	for _, tc := range tests {
+		e.Add(tc.method, tc.path, echoBody)
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.method+" "+tc.path, func(t *testing.T) {
+			body := strings.Repeat("x", tc.limit)
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, httptest.NewRequest(tc.method, tc.target, strings.NewReader(body)))
+			require.Equal(t, http.StatusOK, rec.Code)
+			require.Equal(t, body, rec.Body.String())
+
+			rec = httptest.NewRecorder()
+			e.ServeHTTP(rec, httptest.NewRequest(tc.method, tc.target, strings.NewReader(body+"x")))
+			requirePayloadTooLarge(t, rec, tc.limit)
+
+			// Bodies of unknown length are refused alike
+			req := httptest.NewRequest(tc.method, tc.target, strings.NewReader(body+"x"))
+			req.ContentLength = -1
+			rec = httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+			requirePayloadTooLarge(t, rec, tc.limit)
+
+			req = httptest.NewRequest(tc.method, tc.target, strings.NewReader(body))
+			req.ContentLength = -1
+			rec = httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+			require.Equal(t, http.StatusOK, rec.Code)
+			require.Equal(t, body, rec.Body.String())
+		})
+	}
+}
+
+func TestBodyLimitMiddleware_DefaultsAndUnlimitedRoutes(t *testing.T) {
+	e := echo.New()
+	e.Use(gh.BodyLimitMiddleware(gh.BodyLimits{}))
+	ok := func(c echo.Context) error {
+		_, err := io.Copy(io.Discard, c.Request().Body)
+		if err != nil {
+			return err
+		}
+		return c.NoContent(http.StatusNoContent)
+	}
+	e.POST("/api/ai/summarize", ok)
+	e.POST("/api/import/full", ok)
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/ai/summarize", strings.NewReader(strings.Repeat("x", gh.DefaultBodyLimit+1))))
+	requirePayloadTooLarge(t, rec, gh.DefaultBodyLimit)
+
+	// Full imports stream their body under a cap of their own
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/import/full", strings.NewReader(strings.Repeat("x", gh.DefaultImportBodyLimit+1))))
+	require.Equal(t, http.StatusNoContent, rec.Code)
+}
+
+func requirePayloadTooLarge(t *testing.T, rec *httptest.ResponseRecorder, limit int) {
+	t.Helper()
+	require.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+	var resp struct {
+		Code    string         `json:"code"`
+		Message string         `json:"message"`
+		Details map[string]any `json:"details"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	require.Equal(t, "payload_too_large", resp.Code)
+	require.Equal(t, "request body too large", resp.Message)
+	require.Equal(t, float64(limit), resp.Details["maxBytes"])
+}
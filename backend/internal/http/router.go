@@ -17,22 +17,46 @@ func NewRouter(
 	entryHandler *handler.EntryHandler,
 	opmlHandler *handler.OPMLHandler,
 	iconHandler *handler.IconHandler,
+	imageCacheHandler *handler.ImageCacheHandler,
 	proxyHandler *handler.ProxyHandler,
 	settingsHandler *handler.SettingsHandler,
 	aiHandler *handler.AIHandler,
 	authHandler *handler.AuthHandler,
 	domainRateLimitHandler *handler.DomainRateLimitHandler,
+	apiTokenHandler *handler.APITokenHandler,
+	healthHandler *handler.HealthHandler,
+	maintenanceHandler *handler.MaintenanceHandler,
+	backupHandler *handler.BackupHandler,
+	eventHandler *handler.EventHandler,
+	thumbnailHandler *handler.ThumbnailHandler,
+	bootstrapHandler *handler.BootstrapHandler,
+	savedHandler *handler.SavedHandler,
 	authService service.AuthService,
+	apiTokenService service.APITokenService,
+	settingsService service.SettingsService,
 	staticDir string,
 	enableSwagger bool,
+	trustProxy bool,
+	bodyLimits BodyLimits,
 ) *echo.Echo {
 	e := echo.New()
 	e.HideBanner = true
+	// Only trust X-Forwarded-For behind a known reverse proxy, otherwise clients could spoof their IP
+	if trustProxy {
+		e.IPExtractor = echo.ExtractIPFromXFFHeader()
+	} else {
+		e.IPExtractor = echo.ExtractIPDirect()
+	}
 	e.Use(middleware.Recover())
 	e.Use(RequestLoggerMiddleware())
+	e.Use(CORSMiddleware(settingsService))
+	e.Use(BodyLimitMiddleware(bodyLimits))
 
 	logger.Info("router initialized", "module", "http", "action", "request", "resource", "http", "result", "ok", "static_dir", staticDir)
 
+	// Probe routes live outside /api so they bypass auth
+	healthHandler.RegisterRoutes(e)
+
 	if enableSwagger {
 		e.GET("/swagger/*", echoSwagger.WrapHandler)
 	}
@@ -40,10 +64,12 @@ func NewRouter(
 	// Public API routes (no auth required)
 	publicAPI := e.Group("/api")
 	authHandler.RegisterPublicRoutes(publicAPI)
+	// Pushed entries carry a per-feed ingest token instead of a login
+	feedHandler.RegisterIngestRoutes(publicAPI)
 
 	// Protected API routes (auth required)
 	api := e.Group("/api")
-	api.Use(JWTAuthMiddleware(authService))
+	api.Use(JWTAuthMiddleware(authService, apiTokenService, settingsService))
 
 	folderHandler.RegisterRoutes(api)
 	feedHandler.RegisterRoutes(api)
@@ -55,9 +81,17 @@ func NewRouter(
 	iconHandler.RegisterAPIRoutes(api)
 	authHandler.RegisterProtectedRoutes(api)
 	domainRateLimitHandler.RegisterRoutes(api)
+	apiTokenHandler.RegisterRoutes(api)
+	maintenanceHandler.RegisterRoutes(api)
+	backupHandler.RegisterRoutes(api)
+	eventHandler.RegisterRoutes(api)
+	thumbnailHandler.RegisterRoutes(api)
+	bootstrapHandler.RegisterRoutes(api)
+	savedHandler.RegisterRoutes(api)
 
 	// Icon routes with cache recovery
 	iconHandler.RegisterRoutes(e)
+	imageCacheHandler.RegisterRoutes(e)
 
 	registerStatic(e, staticDir)
 
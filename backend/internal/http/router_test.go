@@ -3,10 +3,13 @@ package http_test
 import (
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
+	"gist/backend/internal/events"
 	"gist/backend/internal/handler"
 	gh "gist/backend/internal/http"
+	"gist/backend/internal/service"
 	"gist/backend/internal/service/mock"
 	"gist/backend/pkg/network"
 
@@ -24,25 +27,35 @@ func TestNewRouter_RegistersRoutes(t *testing.T) {
 	entryService := mock.NewMockEntryService(ctrl)
 	opmlService := mock.NewMockOPMLService(ctrl)
 	iconService := mock.NewMockIconService(ctrl)
+	imageCacheService := mock.NewMockImageCacheService(ctrl)
 	proxyService := mock.NewMockProxyService(ctrl)
 	settingsService := mock.NewMockSettingsService(ctrl)
+	settingsService.EXPECT().GetSecuritySettings(gomock.Any()).Return(&service.SecuritySettings{CookieSameSite: "lax"}, nil).AnyTimes()
 	aiService := mock.NewMockAIService(ctrl)
 	authService := mock.NewMockAuthService(ctrl)
+	loginGuardService := mock.NewMockLoginGuardService(ctrl)
 	domainRateLimitService := mock.NewMockDomainRateLimitService(ctrl)
+	apiTokenService := mock.NewMockAPITokenService(ctrl)
+	healthService := mock.NewMockHealthService(ctrl)
 	refreshService := mock.NewMockRefreshService(ctrl)
 	readabilityService := mock.NewMockReadabilityService(ctrl)
 	importTaskService := mock.NewMockImportTaskService(ctrl)
 
 	folderHandler := handler.NewFolderHandler(folderService)
-	feedHandler := handler.NewFeedHandler(feedService, refreshService)
-	entryHandler := handler.NewEntryHandler(entryService, readabilityService)
+	feedHandler := handler.NewFeedHandler(feedService, refreshService, nil, nil)
+	entryHandler := handler.NewEntryHandler(entryService, readabilityService, nil)
 	opmlHandler := handler.NewOPMLHandler(opmlService, importTaskService)
 	iconHandler := handler.NewIconHandler(iconService)
+	imageCacheHandler := handler.NewImageCacheHandler(imageCacheService)
 	proxyHandler := handler.NewProxyHandler(proxyService)
 	settingsHandler := handler.NewSettingsHandler(settingsService, network.NewClientFactoryForTest(&http.Client{}))
-	aiHandler := handler.NewAIHandler(aiService)
-	authHandler := handler.NewAuthHandler(authService)
+	aiHandler := handler.NewAIHandler(aiService, nil)
+	authHandler := handler.NewAuthHandler(authService, loginGuardService, settingsService)
 	domainRateLimitHandler := handler.NewDomainRateLimitHandler(domainRateLimitService)
+	apiTokenHandler := handler.NewAPITokenHandler(apiTokenService)
+	healthHandler := handler.NewHealthHandler(healthService)
+	maintenanceHandler := handler.NewMaintenanceHandler(mock.NewMockMaintenanceService(ctrl))
+	backupHandler := handler.NewBackupHandler(mock.NewMockBackupService(ctrl))
 
 	e := gh.NewRouter(
 		folderHandler,
@@ -50,21 +63,52 @@ func TestNewRouter_RegistersRoutes(t *testing.T) {
 		entryHandler,
 		opmlHandler,
 		iconHandler,
+		imageCacheHandler,
 		proxyHandler,
 		settingsHandler,
 		aiHandler,
 		authHandler,
 		domainRateLimitHandler,
+		apiTokenHandler,
+		healthHandler,
+		maintenanceHandler,
+		backupHandler,
+		handler.NewEventHandler(events.NewBus(events.SubscriberBuffer)),
+		handler.NewThumbnailHandler(mock.NewMockThumbnailService(ctrl)),
+		handler.NewBootstrapHandler(folderService, feedService, entryService, refreshService, settingsService, nil),
+		handler.NewSavedHandler(nil),
 		authService,
+		apiTokenService,
+		settingsService,
 		"",
 		true,
+		false,
+		gh.BodyLimits{},
 	)
 
 	require.NotNil(t, e)
 	require.True(t, hasRoute(e, http.MethodGet, "/swagger/*"))
 	require.True(t, hasRoute(e, http.MethodGet, "/api/feeds"))
+	require.True(t, hasRoute(e, http.MethodPost, "/api/admin/maintenance"))
+	require.True(t, hasRoute(e, http.MethodPost, "/api/auth/tokens"))
+	require.True(t, hasRoute(e, http.MethodPost, "/api/feeds/:id/entries"))
+	require.True(t, hasRoute(e, http.MethodGet, "/api/events"))
+	require.True(t, hasRoute(e, http.MethodGet, "/api/entries/:id/thumbnail"))
+	require.True(t, hasRoute(e, http.MethodGet, "/api/bootstrap"))
 	require.True(t, hasRoute(e, http.MethodGet, "/icons/:filename"))
+	require.True(t, hasRoute(e, http.MethodGet, "/cached-images/:entryId/:filename"))
 	require.True(t, hasRoute(e, http.MethodGet, "/api/proxy/image/:encoded"))
+	require.True(t, hasRoute(e, http.MethodGet, "/healthz"))
+	require.True(t, hasRoute(e, http.MethodGet, "/readyz"))
+
+	// Routes with their own body limits must keep their registered paths
+	require.True(t, hasRoute(e, http.MethodPost, "/api/opml/import"))
+	require.True(t, hasRoute(e, http.MethodPost, "/api/feeds/static"))
+	require.True(t, hasRoute(e, http.MethodPut, "/api/feeds/:id/static"))
+	require.True(t, hasRoute(e, http.MethodPost, "/api/import/full"))
+	require.True(t, hasRoute(e, http.MethodPost, "/api/auth/login"))
+	require.True(t, hasRoute(e, http.MethodPut, "/api/settings/general"))
+	require.True(t, hasRoute(e, http.MethodPut, "/api/domain-rate-limits/:host"))
 }
 
 func TestNewRouter_SwaggerDisabled(t *testing.T) {
@@ -76,25 +120,35 @@ func TestNewRouter_SwaggerDisabled(t *testing.T) {
 	entryService := mock.NewMockEntryService(ctrl)
 	opmlService := mock.NewMockOPMLService(ctrl)
 	iconService := mock.NewMockIconService(ctrl)
+	imageCacheService := mock.NewMockImageCacheService(ctrl)
 	proxyService := mock.NewMockProxyService(ctrl)
 	settingsService := mock.NewMockSettingsService(ctrl)
+	settingsService.EXPECT().GetSecuritySettings(gomock.Any()).Return(&service.SecuritySettings{CookieSameSite: "lax"}, nil).AnyTimes()
 	aiService := mock.NewMockAIService(ctrl)
 	authService := mock.NewMockAuthService(ctrl)
+	loginGuardService := mock.NewMockLoginGuardService(ctrl)
 	domainRateLimitService := mock.NewMockDomainRateLimitService(ctrl)
+	apiTokenService := mock.NewMockAPITokenService(ctrl)
+	healthService := mock.NewMockHealthService(ctrl)
 	refreshService := mock.NewMockRefreshService(ctrl)
 	readabilityService := mock.NewMockReadabilityService(ctrl)
 	importTaskService := mock.NewMockImportTaskService(ctrl)
 
 	folderHandler := handler.NewFolderHandler(folderService)
-	feedHandler := handler.NewFeedHandler(feedService, refreshService)
-	entryHandler := handler.NewEntryHandler(entryService, readabilityService)
+	feedHandler := handler.NewFeedHandler(feedService, refreshService, nil, nil)
+	entryHandler := handler.NewEntryHandler(entryService, readabilityService, nil)
 	opmlHandler := handler.NewOPMLHandler(opmlService, importTaskService)
 	iconHandler := handler.NewIconHandler(iconService)
+	imageCacheHandler := handler.NewImageCacheHandler(imageCacheService)
 	proxyHandler := handler.NewProxyHandler(proxyService)
 	settingsHandler := handler.NewSettingsHandler(settingsService, network.NewClientFactoryForTest(&http.Client{}))
-	aiHandler := handler.NewAIHandler(aiService)
-	authHandler := handler.NewAuthHandler(authService)
+	aiHandler := handler.NewAIHandler(aiService, nil)
+	authHandler := handler.NewAuthHandler(authService, loginGuardService, settingsService)
 	domainRateLimitHandler := handler.NewDomainRateLimitHandler(domainRateLimitService)
+	apiTokenHandler := handler.NewAPITokenHandler(apiTokenService)
+	healthHandler := handler.NewHealthHandler(healthService)
+	maintenanceHandler := handler.NewMaintenanceHandler(mock.NewMockMaintenanceService(ctrl))
+	backupHandler := handler.NewBackupHandler(mock.NewMockBackupService(ctrl))
 
 	e := gh.NewRouter(
 		folderHandler,
@@ -102,14 +156,27 @@ func TestNewRouter_SwaggerDisabled(t *testing.T) {
 		entryHandler,
 		opmlHandler,
 		iconHandler,
+		imageCacheHandler,
 		proxyHandler,
 		settingsHandler,
 		aiHandler,
 		authHandler,
 		domainRateLimitHandler,
+		apiTokenHandler,
+		healthHandler,
+		maintenanceHandler,
+		backupHandler,
+		handler.NewEventHandler(events.NewBus(events.SubscriberBuffer)),
+		handler.NewThumbnailHandler(mock.NewMockThumbnailService(ctrl)),
+		handler.NewBootstrapHandler(folderService, feedService, entryService, refreshService, settingsService, nil),
+		handler.NewSavedHandler(nil),
 		authService,
+		apiTokenService,
+		settingsService,
 		"",
 		false,
+		false,
+		gh.BodyLimits{},
 	)
 
 	require.NotNil(t, e)
@@ -128,25 +195,35 @@ func TestNewRouter_LogoutRouteIsPublic(t *testing.T) {
 	entryService := mock.NewMockEntryService(ctrl)
 	opmlService := mock.NewMockOPMLService(ctrl)
 	iconService := mock.NewMockIconService(ctrl)
+	imageCacheService := mock.NewMockImageCacheService(ctrl)
 	proxyService := mock.NewMockProxyService(ctrl)
 	settingsService := mock.NewMockSettingsService(ctrl)
+	settingsService.EXPECT().GetSecuritySettings(gomock.Any()).Return(&service.SecuritySettings{CookieSameSite: "lax"}, nil).AnyTimes()
 	aiService := mock.NewMockAIService(ctrl)
 	authService := mock.NewMockAuthService(ctrl)
+	loginGuardService := mock.NewMockLoginGuardService(ctrl)
 	domainRateLimitService := mock.NewMockDomainRateLimitService(ctrl)
+	apiTokenService := mock.NewMockAPITokenService(ctrl)
+	healthService := mock.NewMockHealthService(ctrl)
 	refreshService := mock.NewMockRefreshService(ctrl)
 	readabilityService := mock.NewMockReadabilityService(ctrl)
 	importTaskService := mock.NewMockImportTaskService(ctrl)
 
 	folderHandler := handler.NewFolderHandler(folderService)
-	feedHandler := handler.NewFeedHandler(feedService, refreshService)
-	entryHandler := handler.NewEntryHandler(entryService, readabilityService)
+	feedHandler := handler.NewFeedHandler(feedService, refreshService, nil, nil)
+	entryHandler := handler.NewEntryHandler(entryService, readabilityService, nil)
 	opmlHandler := handler.NewOPMLHandler(opmlService, importTaskService)
 	iconHandler := handler.NewIconHandler(iconService)
+	imageCacheHandler := handler.NewImageCacheHandler(imageCacheService)
 	proxyHandler := handler.NewProxyHandler(proxyService)
 	settingsHandler := handler.NewSettingsHandler(settingsService, network.NewClientFactoryForTest(&http.Client{}))
-	aiHandler := handler.NewAIHandler(aiService)
-	authHandler := handler.NewAuthHandler(authService)
+	aiHandler := handler.NewAIHandler(aiService, nil)
+	authHandler := handler.NewAuthHandler(authService, loginGuardService, settingsService)
 	domainRateLimitHandler := handler.NewDomainRateLimitHandler(domainRateLimitService)
+	apiTokenHandler := handler.NewAPITokenHandler(apiTokenService)
+	healthHandler := handler.NewHealthHandler(healthService)
+	maintenanceHandler := handler.NewMaintenanceHandler(mock.NewMockMaintenanceService(ctrl))
+	backupHandler := handler.NewBackupHandler(mock.NewMockBackupService(ctrl))
 
 	e := gh.NewRouter(
 		folderHandler,
@@ -154,14 +231,27 @@ func TestNewRouter_LogoutRouteIsPublic(t *testing.T) {
 		entryHandler,
 		opmlHandler,
 		iconHandler,
+		imageCacheHandler,
 		proxyHandler,
 		settingsHandler,
 		aiHandler,
 		authHandler,
 		domainRateLimitHandler,
+		apiTokenHandler,
+		healthHandler,
+		maintenanceHandler,
+		backupHandler,
+		handler.NewEventHandler(events.NewBus(events.SubscriberBuffer)),
+		handler.NewThumbnailHandler(mock.NewMockThumbnailService(ctrl)),
+		handler.NewBootstrapHandler(folderService, feedService, entryService, refreshService, settingsService, nil),
+		handler.NewSavedHandler(nil),
 		authService,
+		apiTokenService,
+		settingsService,
 		"",
 		false,
+		false,
+		gh.BodyLimits{},
 	)
 
 	req := httptest.NewRequest(http.MethodPost, "/api/auth/logout", nil)
@@ -183,6 +273,175 @@ func TestNewRouter_LogoutRouteIsPublic(t *testing.T) {
 	require.Equal(t, -1, authCookie.MaxAge)
 }
 
+func TestNewRouter_IngestRouteUsesFeedToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	folderService := mock.NewMockFolderService(ctrl)
+	feedService := mock.NewMockFeedService(ctrl)
+	entryService := mock.NewMockEntryService(ctrl)
+	opmlService := mock.NewMockOPMLService(ctrl)
+	iconService := mock.NewMockIconService(ctrl)
+	imageCacheService := mock.NewMockImageCacheService(ctrl)
+	proxyService := mock.NewMockProxyService(ctrl)
+	settingsService := mock.NewMockSettingsService(ctrl)
+	settingsService.EXPECT().GetSecuritySettings(gomock.Any()).Return(&service.SecuritySettings{CookieSameSite: "lax"}, nil).AnyTimes()
+	aiService := mock.NewMockAIService(ctrl)
+	authService := mock.NewMockAuthService(ctrl)
+	loginGuardService := mock.NewMockLoginGuardService(ctrl)
+	domainRateLimitService := mock.NewMockDomainRateLimitService(ctrl)
+	apiTokenService := mock.NewMockAPITokenService(ctrl)
+	healthService := mock.NewMockHealthService(ctrl)
+	refreshService := mock.NewMockRefreshService(ctrl)
+	readabilityService := mock.NewMockReadabilityService(ctrl)
+	importTaskService := mock.NewMockImportTaskService(ctrl)
+
+	// No login is checked, only the feed's ingest token
+	feedService.EXPECT().ValidateIngestToken(gomock.Any(), int64(7), "gist_ingest_abc").Return(nil)
+	refreshService.EXPECT().IngestItems(gomock.Any(), int64(7), []service.IngestItem{{URL: "https://example.com/1"}}).
+		Return([]service.IngestResult{{Index: 0, Status: service.IngestCreated}}, nil)
+
+	folderHandler := handler.NewFolderHandler(folderService)
+	feedHandler := handler.NewFeedHandler(feedService, refreshService, nil, nil)
+	entryHandler := handler.NewEntryHandler(entryService, readabilityService, nil)
+	opmlHandler := handler.NewOPMLHandler(opmlService, importTaskService)
+	iconHandler := handler.NewIconHandler(iconService)
+	imageCacheHandler := handler.NewImageCacheHandler(imageCacheService)
+	proxyHandler := handler.NewProxyHandler(proxyService)
+	settingsHandler := handler.NewSettingsHandler(settingsService, network.NewClientFactoryForTest(&http.Client{}))
+	aiHandler := handler.NewAIHandler(aiService, nil)
+	authHandler := handler.NewAuthHandler(authService, loginGuardService, settingsService)
+	domainRateLimitHandler := handler.NewDomainRateLimitHandler(domainRateLimitService)
+	apiTokenHandler := handler.NewAPITokenHandler(apiTokenService)
+	healthHandler := handler.NewHealthHandler(healthService)
+	maintenanceHandler := handler.NewMaintenanceHandler(mock.NewMockMaintenanceService(ctrl))
+	backupHandler := handler.NewBackupHandler(mock.NewMockBackupService(ctrl))
+
+	e := gh.NewRouter(
+		folderHandler,
+		feedHandler,
+		entryHandler,
+		opmlHandler,
+		iconHandler,
+		imageCacheHandler,
+		proxyHandler,
+		settingsHandler,
+		aiHandler,
+		authHandler,
+		domainRateLimitHandler,
+		apiTokenHandler,
+		healthHandler,
+		maintenanceHandler,
+		backupHandler,
+		handler.NewEventHandler(events.NewBus(events.SubscriberBuffer)),
+		handler.NewThumbnailHandler(mock.NewMockThumbnailService(ctrl)),
+		handler.NewBootstrapHandler(folderService, feedService, entryService, refreshService, settingsService, nil),
+		handler.NewSavedHandler(nil),
+		authService,
+		apiTokenService,
+		settingsService,
+		"",
+		false,
+		false,
+		gh.BodyLimits{},
+	)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/feeds/7/entries", strings.NewReader(`[{"url": "https://example.com/1"}]`))
+	req.Header.Set("Authorization", "Bearer gist_ingest_abc")
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+
+	e.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Contains(t, rec.Body.String(), `"created":1`)
+}
+
+func TestNewRouter_HealthRoutesArePublic(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	folderService := mock.NewMockFolderService(ctrl)
+	feedService := mock.NewMockFeedService(ctrl)
+	entryService := mock.NewMockEntryService(ctrl)
+	opmlService := mock.NewMockOPMLService(ctrl)
+	iconService := mock.NewMockIconService(ctrl)
+	imageCacheService := mock.NewMockImageCacheService(ctrl)
+	proxyService := mock.NewMockProxyService(ctrl)
+	settingsService := mock.NewMockSettingsService(ctrl)
+	settingsService.EXPECT().GetSecuritySettings(gomock.Any()).Return(&service.SecuritySettings{CookieSameSite: "lax"}, nil).AnyTimes()
+	aiService := mock.NewMockAIService(ctrl)
+	authService := mock.NewMockAuthService(ctrl)
+	loginGuardService := mock.NewMockLoginGuardService(ctrl)
+	domainRateLimitService := mock.NewMockDomainRateLimitService(ctrl)
+	apiTokenService := mock.NewMockAPITokenService(ctrl)
+	healthService := mock.NewMockHealthService(ctrl)
+	healthService.EXPECT().BuildInfo().Return(service.BuildInfo{Version: "1.2.3"})
+	healthService.EXPECT().Ready(gomock.Any()).Return(service.ReadinessReport{
+		Checks: []service.HealthCheck{{Name: service.HealthCheckDatabase, Error: "ping database: closed"}},
+	})
+	refreshService := mock.NewMockRefreshService(ctrl)
+	readabilityService := mock.NewMockReadabilityService(ctrl)
+	importTaskService := mock.NewMockImportTaskService(ctrl)
+
+	folderHandler := handler.NewFolderHandler(folderService)
+	feedHandler := handler.NewFeedHandler(feedService, refreshService, nil, nil)
+	entryHandler := handler.NewEntryHandler(entryService, readabilityService, nil)
+	opmlHandler := handler.NewOPMLHandler(opmlService, importTaskService)
+	iconHandler := handler.NewIconHandler(iconService)
+	imageCacheHandler := handler.NewImageCacheHandler(imageCacheService)
+	proxyHandler := handler.NewProxyHandler(proxyService)
+	settingsHandler := handler.NewSettingsHandler(settingsService, network.NewClientFactoryForTest(&http.Client{}))
+	aiHandler := handler.NewAIHandler(aiService, nil)
+	authHandler := handler.NewAuthHandler(authService, loginGuardService, settingsService)
+	domainRateLimitHandler := handler.NewDomainRateLimitHandler(domainRateLimitService)
+	apiTokenHandler := handler.NewAPITokenHandler(apiTokenService)
+	healthHandler := handler.NewHealthHandler(healthService)
+	maintenanceHandler := handler.NewMaintenanceHandler(mock.NewMockMaintenanceService(ctrl))
+	backupHandler := handler.NewBackupHandler(mock.NewMockBackupService(ctrl))
+
+	e := gh.NewRouter(
+		folderHandler,
+		feedHandler,
+		entryHandler,
+		opmlHandler,
+		iconHandler,
+		imageCacheHandler,
+		proxyHandler,
+		settingsHandler,
+		aiHandler,
+		authHandler,
+		domainRateLimitHandler,
+		apiTokenHandler,
+		healthHandler,
+		maintenanceHandler,
+		backupHandler,
+		handler.NewEventHandler(events.NewBus(events.SubscriberBuffer)),
+		handler.NewThumbnailHandler(mock.NewMockThumbnailService(ctrl)),
+		handler.NewBootstrapHandler(folderService, feedService, entryService, refreshService, settingsService, nil),
+		handler.NewSavedHandler(nil),
+		authService,
+		apiTokenService,
+		settingsService,
+		"",
+		false,
+		false,
+		gh.BodyLimits{},
+	)
+
+	req := httptest.NewRequest(http.MethodGet, "/healthz", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Contains(t, rec.Body.String(), `"version":"1.2.3"`)
+
+	req = httptest.NewRequest(http.MethodGet, "/readyz", nil)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	require.Contains(t, rec.Body.String(), `"status":"unavailable"`)
+}
+
 func hasRoute(e *echo.Echo, method, path string) bool {
 	for _, r := range e.Routes() {
 		if r.Method == method && r.Path == path {
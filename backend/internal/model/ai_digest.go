@@ -0,0 +1,24 @@
+package model
+
+import "time"
+
+// AI digest scopes.
+const (
+	AIDigestScopeFolder = "folder"
+	AIDigestScopeFeed   = "feed"
+)
+
+// AIDigest is a cached AI digest of the unread entries of a folder or feed
+// published in [WindowStart, WindowEnd).
+type AIDigest struct {
+	ID          int64
+	Scope       string
+	ScopeID     int64
+	WindowStart time.Time
+	WindowEnd   time.Time
+	Language    string
+	// Entries is how many entries the digest was written from.
+	Entries   int
+	Digest    string
+	CreatedAt time.Time
+}
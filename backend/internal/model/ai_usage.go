@@ -0,0 +1,35 @@
+package model
+
+import "time"
+
+// AI usage operations.
+const (
+	AIUsageSummarize     = "summarize"
+	AIUsageTranslate     = "translate"
+	AIUsageListTranslate = "list_translate"
+	AIUsageDigest        = "digest"
+)
+
+// AIUsage is the token spend of one AI operation.
+type AIUsage struct {
+	ID               int64
+	Operation        string
+	Provider         string
+	Model            string
+	PromptTokens     int
+	CompletionTokens int
+	// Estimated is set when the provider sent no usage and tokens were counted from text.
+	Estimated bool
+	EntryID   *int64
+	CreatedAt time.Time
+}
+
+// AIUsageTotal sums usage rows of one UTC day, operation and model.
+type AIUsageTotal struct {
+	Day              string
+	Operation        string
+	Model            string
+	Calls            int
+	PromptTokens     int
+	CompletionTokens int
+}
@@ -0,0 +1,14 @@
+package model
+
+import "time"
+
+// APIToken represents a personal access token for non-browser clients.
+// Only the SHA-256 hash of the token is stored.
+type APIToken struct {
+	ID         int64
+	Name       string
+	TokenHash  string
+	CreatedAt  time.Time
+	LastUsedAt *time.Time
+	ExpiresAt  *time.Time
+}
@@ -9,4 +9,6 @@ type DomainRateLimit struct {
 	IntervalSeconds int
 	CreatedAt       time.Time
 	UpdatedAt       time.Time
+	// MaxConcurrent caps parallel refresh requests to the host; 0 uses the general setting.
+	MaxConcurrent int
 }
@@ -15,6 +15,42 @@ type Entry struct {
 	PublishedAt     *time.Time
 	Read            bool
 	Starred         bool
+	WordCount       int
 	CreatedAt       time.Time
 	UpdatedAt       time.Time
+	// PublishedAtSource tells where PublishedAt came from, one of the
+	// PublishedAtFrom* values. Only set when saving; entries saved before it
+	// was recorded have none.
+	PublishedAtSource string
+	// Language is the primary subtag of the entry's language: the feed's, else
+	// detected from its text. Empty when unknown or not detected yet.
+	Language string
+	// Note is the user's note on the entry, loaded by the single-entry and list queries.
+	Note *string
+	// Feed summarizes the entry's feed; only set when a query asks for it.
+	Feed *EntryFeed
+	// AuthorMuted is set when the author is muted in the entry's feed; only the
+	// single-entry lookups fill it.
+	AuthorMuted bool
+	// RawItem is the feed item the entry was made from, serialized to JSON. It
+	// is only set when saving entries with raw items kept, and stored apart.
+	RawItem *string
+}
+
+// Where the date of an entry came from.
+const (
+	// PublishedAtFromFeed dates are the item's published or updated date.
+	PublishedAtFromFeed = "feed"
+	// PublishedAtFromSummary dates are read from the item's summary.
+	PublishedAtFromSummary = "summary"
+	// PublishedAtFromFetch dates are the fetch time, for items with no
+	// plausible date.
+	PublishedAtFromFetch = "fetch"
+)
+
+// EntryFeed is the part of a feed shown next to its entries.
+type EntryFeed struct {
+	Title    string
+	IconPath *string
+	Type     string
 }
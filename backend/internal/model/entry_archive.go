@@ -0,0 +1,21 @@
+package model
+
+import "time"
+
+// EntryArchive tracks saving the full content of a starred entry.
+type EntryArchive struct {
+	EntryID int64
+	// Attempts counts the fetches of the last failed archival.
+	Attempts   int
+	LastError  *string
+	FailedAt   *time.Time
+	ArchivedAt *time.Time
+}
+
+// FailedArchive is a starred entry whose archival gave up after its retries.
+type FailedArchive struct {
+	EntryArchive
+	FeedID int64
+	Title  *string
+	URL    *string
+}
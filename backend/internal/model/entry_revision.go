@@ -0,0 +1,13 @@
+package model
+
+import "time"
+
+// EntryRevision is a snapshot of an entry's content before it was overwritten by a refresh.
+// CreatedAt is when the change was detected, i.e. when this snapshot stopped being current.
+type EntryRevision struct {
+	ID        int64
+	EntryID   int64
+	Title     *string
+	Content   *string
+	CreatedAt time.Time
+}
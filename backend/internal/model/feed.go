@@ -3,18 +3,122 @@ package model
 import "time"
 
 type Feed struct {
-	ID                    int64
-	FolderID              *int64
+	ID       int64
+	FolderID *int64
+	// Title is the feed's own title, kept up to date by refreshes; CustomTitle
+	// is the user's rename, nil when there is none. DisplayTitle picks one.
 	Title                 string
+	CustomTitle           *string
 	URL                   string
 	SiteURL               *string
 	Description           *string
 	SummaryPromptReminder *string
-	IconPath              *string
-	Type                  string // article, picture, notification
-	ETag                  *string
-	LastModified          *string
-	ErrorMessage          *string
-	CreatedAt             time.Time
-	UpdatedAt             time.Time
+	// AssumeTimezone is the IANA zone for item dates that carry none; nil uses the global setting.
+	AssumeTimezone *string
+	// PausedUntil skips the feed in bulk refreshes and unread totals until that time.
+	PausedUntil *time.Time
+	// DedupeKey is the entry hash strategy, one of the DedupeKey* constants.
+	DedupeKey string
+	IconPath  *string
+	// IconSource is IconSourceGenerated for a drawn fallback icon, nil for a fetched one.
+	IconSource   *string
+	Type         string // article, picture, notification
+	ETag         *string
+	LastModified *string
+	ErrorMessage *string
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	// DeletedAt is set on soft-deleted feeds, which only FindByURL returns.
+	DeletedAt *time.Time
+	// SortOrder positions the feed within its folder; ties sort by title.
+	SortOrder int
+	// PreferredUserAgent is the FeedUserAgent* choice tried first on refresh; it
+	// switches when the other one succeeds after it failed.
+	PreferredUserAgent string
+	// MinPollSeconds is the shortest refresh interval the feed asks for through
+	// its ttl or syndication hints, 0 when it sets none; MinPollSource says which.
+	MinPollSeconds int
+	MinPollSource  string
+	// LastFetchedAt is when a refresh last sent the feed a request.
+	LastFetchedAt *time.Time
+	// MaxEntries caps the entries kept for the feed by evicting the oldest read
+	// ones after each refresh; 0 keeps all.
+	MaxEntries int
+	// AutoTranslate is one of the FeedAutoTranslate* choices for translating
+	// list titles of new entries in the background after a refresh.
+	AutoTranslate string
+	// UserAgent replaces the default User-Agent header of refreshes, and
+	// AutoReadability whether entries open in readability mode; nil inherits
+	// the folder's FeedDefaults.
+	UserAgent       *string
+	AutoReadability *bool
+	// Language is the primary subtag of the language the feed declares, such as
+	// "en"; empty when it declares none.
+	Language string
+	// NotModifiedStreak counts the refreshes in a row answered 304 Not Modified.
+	NotModifiedStreak int
+	// LastEntrySeenAt is the newest published date seen in the feed.
+	LastEntrySeenAt *time.Time
+	// SuspectCachingAt is when a fetch without validators, made after a long run
+	// of 304s, found new entries: the upstream cache likely ignores them. A
+	// conditional fetch that brings new entries clears it.
+	SuspectCachingAt *time.Time
+}
+
+// DisplayTitle returns the custom title if the feed has one, else its own title.
+func (f Feed) DisplayTitle() string {
+	if f.CustomTitle != nil {
+		return *f.CustomTitle
+	}
+	return f.Title
+}
+
+// Entry hash strategies for Feed.DedupeKey.
+const (
+	// DedupeKeyAuto hashes the GUID, else the link, else title and content.
+	DedupeKeyAuto         = "auto"
+	DedupeKeyGUID         = "guid"
+	DedupeKeyURL          = "url"
+	DedupeKeyTitleContent = "title_content"
+)
+
+// User agent choices for Feed.PreferredUserAgent.
+const (
+	FeedUserAgentDefault  = "default"
+	FeedUserAgentFallback = "fallback"
+)
+
+// Background list translation choices for Feed.AutoTranslate.
+const (
+	// FeedAutoTranslateInherit follows the folder's FeedDefaults, else the
+	// global translate-on-refresh setting.
+	FeedAutoTranslateInherit = "inherit"
+	FeedAutoTranslateOn      = "on"
+	FeedAutoTranslateOff     = "off"
+)
+
+// Sources of Feed.MinPollSeconds.
+const (
+	// PollHintTTL is the RSS <ttl> element, in minutes.
+	PollHintTTL = "ttl"
+	// PollHintSyndication is sy:updatePeriod divided by sy:updateFrequency.
+	PollHintSyndication = "syndication"
+)
+
+// IconSourceGenerated marks a Feed.IconPath drawn from the feed's initial
+// because no icon could be fetched.
+const IconSourceGenerated = "generated"
+
+// FeedWithUnread is a feed with the unread count the sidebar shows for it.
+type FeedWithUnread struct {
+	Feed
+	UnreadCount int
+}
+
+// FeedActivityStats summarizes how much content a feed has produced.
+type FeedActivityStats struct {
+	FeedID          int64
+	EntriesLastWeek int
+	LastPublishedAt *time.Time
+	TotalEntries    int
 }
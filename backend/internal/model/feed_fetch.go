@@ -0,0 +1,38 @@
+package model
+
+import "time"
+
+// Feed fetch outcomes.
+const (
+	FeedFetchOK           = "ok"
+	FeedFetchNotModified  = "not_modified"
+	FeedFetchHTTPError    = "http_error"
+	FeedFetchNetworkError = "network_error"
+	FeedFetchParseError   = "parse_error"
+)
+
+// FeedFetch is one refresh attempt of a feed.
+type FeedFetch struct {
+	ID        int64
+	FeedID    int64
+	FetchedAt time.Time
+	// StatusCode is 0 when no response arrived.
+	StatusCode int
+	DurationMs int64
+	Bytes      int
+	Outcome    string
+}
+
+// Succeeded reports whether the attempt got the feed or learned it is unchanged.
+func (f FeedFetch) Succeeded() bool {
+	return f.Outcome == FeedFetchOK || f.Outcome == FeedFetchNotModified
+}
+
+// FeedFetchStats totals the fetch history of one feed.
+type FeedFetchStats struct {
+	FeedID    int64
+	Attempts  int
+	Successes int
+	// TrailingFailures counts the failed attempts since the last success.
+	TrailingFailures int
+}
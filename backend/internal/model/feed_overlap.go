@@ -0,0 +1,27 @@
+package model
+
+import "time"
+
+// FeedOverlap is a pair of feeds that look like the same subscription:
+// they share a site URL, entries, or both. FeedID is the lower ID.
+type FeedOverlap struct {
+	ID          int64
+	FeedID      int64
+	OtherFeedID int64
+	SameSite    bool
+	// SharedEntries counts the sampled recent entries of one feed that the
+	// other has too; OverlapRatio is their share of the sample, the higher of
+	// the two directions.
+	SharedEntries int
+	OverlapRatio  float64
+	DetectedAt    time.Time
+}
+
+// FeedOverlapSample is how many entries of one feed are also in another.
+type FeedOverlapSample struct {
+	FeedID      int64
+	OtherFeedID int64
+	// Sampled is how many recent entries of FeedID were compared.
+	Sampled int
+	Shared  int
+}
@@ -0,0 +1,15 @@
+package model
+
+import "time"
+
+// FeedTitleTranslation stores a cached translation of a feed title for the sidebar.
+type FeedTitleTranslation struct {
+	ID       int64
+	FeedID   int64
+	Language string
+	// SourceHash is the hash of the feed title that was translated; a renamed
+	// feed no longer matches it and gets translated again.
+	SourceHash string
+	Title      string
+	CreatedAt  time.Time
+}
@@ -9,4 +9,20 @@ type Folder struct {
 	Type      string // article, picture, notification
 	CreatedAt time.Time
 	UpdatedAt time.Time
+	// SortOrder positions the folder among its siblings; ties sort by name.
+	SortOrder int
+	// FeedDefaults apply to the feeds in the folder and its subfolders.
+	FeedDefaults FeedDefaults
+}
+
+// FeedDefaults are feed options a folder sets for the feeds under it. A nil
+// option inherits from the parent folder, and at the top from the global
+// setting; a feed's own value beats them all.
+type FeedDefaults struct {
+	// UserAgent replaces the default User-Agent header of refreshes.
+	UserAgent *string
+	// AutoReadability opens entries in readability mode.
+	AutoReadability *bool
+	// AutoTranslate translates list titles of new entries after refreshes.
+	AutoTranslate *bool
 }
@@ -0,0 +1,23 @@
+package model
+
+import "time"
+
+// Feed fields a folder rule can match.
+const (
+	FolderRuleMatchHost  = "host"
+	FolderRuleMatchURL   = "url"
+	FolderRuleMatchTitle = "title"
+)
+
+// FolderRule files new feeds without a folder into FolderID when Pattern
+// matches their MatchField.
+type FolderRule struct {
+	ID         int64
+	FolderID   int64
+	Pattern    string
+	MatchField string
+	// Priority orders rules across folders; lower values are tried first.
+	Priority  int
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
@@ -0,0 +1,14 @@
+package model
+
+import "time"
+
+// LoginEvent records a single login attempt for the audit log.
+// Success means the credentials were accepted (a second factor may still follow).
+type LoginEvent struct {
+	ID         int64
+	Identifier string
+	IP         string
+	UserAgent  string
+	Success    bool
+	CreatedAt  time.Time
+}
@@ -0,0 +1,36 @@
+package model
+
+import "time"
+
+// Refresh run triggers.
+const (
+	RefreshTriggerManual     = "manual"
+	RefreshTriggerScheduled  = "scheduled"
+	RefreshTriggerSingleFeed = "single-feed"
+)
+
+// RefreshRun summarizes one refresh pass over one or more feeds.
+type RefreshRun struct {
+	ID             int64
+	Trigger        string
+	StartedAt      time.Time
+	FinishedAt     time.Time
+	FeedsTotal     int
+	FeedsFailed    int
+	EntriesNew     int
+	EntriesUpdated int
+	// EntriesEvicted counts read entries deleted to keep feeds under their cap.
+	EntriesEvicted int
+}
+
+// RefreshRunFeed is the outcome of a single feed within a refresh run.
+// ErrorMessage matches what was written to the feed's error_message.
+type RefreshRunFeed struct {
+	RunID          int64
+	FeedID         int64
+	FeedTitle      string
+	EntriesNew     int
+	EntriesUpdated int
+	EntriesEvicted int
+	ErrorMessage   *string
+}
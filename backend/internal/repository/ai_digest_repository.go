@@ -0,0 +1,152 @@
+//go:generate mockgen -source=$GOFILE -destination=mock/$GOFILE -package=mock
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"gist/backend/internal/model"
+	"gist/backend/pkg/snowflake"
+)
+
+// AIDigestEntry is an unread entry listed by ListUnread for a digest.
+type AIDigestEntry struct {
+	EntryID     int64
+	FeedTitle   string
+	Title       string
+	Content     string
+	PublishedAt *time.Time
+	// Summary is the entry's cached AI summary in the digest language, empty
+	// when it has none.
+	Summary string
+}
+
+type AIDigestRepository interface {
+	// Get returns the digest of scope and scopeID for the window ending at
+	// windowEnd in language, or nil when none is cached.
+	Get(ctx context.Context, scope string, scopeID int64, windowEnd time.Time, language string) (*model.AIDigest, error)
+	// Save stores digest, replacing one cached for the same scope, window end and language.
+	Save(ctx context.Context, digest model.AIDigest) error
+	DeleteAll(ctx context.Context) (int64, error)
+	// ListUnread returns up to limit unread entries of live feeds in scope
+	// published in [start, end), newest first, with their summary in language.
+	// Entries without a publish date count by when they were stored.
+	ListUnread(ctx context.Context, scope string, scopeID int64, start, end time.Time, language string, limit int) ([]AIDigestEntry, error)
+}
+
+type aiDigestRepository struct {
+	db dbtx
+}
+
+func NewAIDigestRepository(db dbtx) AIDigestRepository {
+	return &aiDigestRepository{db: db}
+}
+
+func (r *aiDigestRepository) Get(ctx context.Context, scope string, scopeID int64, windowEnd time.Time, language string) (*model.AIDigest, error) {
+	row := r.db.QueryRowContext(
+		ctx,
+		`SELECT id, scope, scope_id, window_start, window_end, language, entries, digest, created_at
+		 FROM ai_digests WHERE scope = ? AND scope_id = ? AND window_end = ? AND language = ?`,
+		scope, scopeID, formatTime(windowEnd), language,
+	)
+
+	var d model.AIDigest
+	var windowStart, windowEndDB, createdAt string
+
+	err := row.Scan(&d.ID, &d.Scope, &d.ScopeID, &windowStart, &windowEndDB, &d.Language, &d.Entries, &d.Digest, &createdAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	d.WindowStart, _ = parseTime(windowStart)
+	d.WindowEnd, _ = parseTime(windowEndDB)
+	d.CreatedAt, _ = parseTime(createdAt)
+
+	return &d, nil
+}
+
+func (r *aiDigestRepository) Save(ctx context.Context, digest model.AIDigest) error {
+	id := snowflake.NextID()
+	now := formatTime(time.Now())
+
+	_, err := r.db.ExecContext(
+		ctx,
+		`INSERT INTO ai_digests (id, scope, scope_id, window_start, window_end, language, entries, digest, created_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		 ON CONFLICT(scope, scope_id, window_end, language) DO UPDATE SET
+		   window_start = excluded.window_start,
+		   entries = excluded.entries,
+		   digest = excluded.digest,
+		   created_at = excluded.created_at`,
+		id, digest.Scope, digest.ScopeID, formatTime(digest.WindowStart), formatTime(digest.WindowEnd), digest.Language, digest.Entries, digest.Digest, now,
+	)
+	return err
+}
+
+func (r *aiDigestRepository) DeleteAll(ctx context.Context) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM ai_digests`)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func (r *aiDigestRepository) ListUnread(ctx context.Context, scope string, scopeID int64, start, end time.Time, language string, limit int) ([]AIDigestEntry, error) {
+	var scopeCondition string
+	switch scope {
+	case model.AIDigestScopeFolder:
+		scopeCondition = "f.folder_id = ?"
+	case model.AIDigestScopeFeed:
+		scopeCondition = "e.feed_id = ?"
+	default:
+		return nil, fmt.Errorf("unknown digest scope %q", scope)
+	}
+	if limit <= 0 {
+		return nil, nil
+	}
+
+	// The plain summary wins over the readability one, as in GetBatch
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT e.id, COALESCE(f.custom_title, f.title), e.title, e.content, e.published_at,
+		       (SELECT s.summary FROM ai_summaries s
+		        WHERE s.entry_id = e.id AND s.language = ?
+		        ORDER BY s.is_readability LIMIT 1)
+		FROM entries e
+		INNER JOIN feeds f ON e.feed_id = f.id
+		WHERE f.deleted_at IS NULL
+		  AND e.read = 0
+		  AND `+scopeCondition+`
+		  AND julianday(COALESCE(e.published_at, e.created_at)) >= julianday(?)
+		  AND julianday(COALESCE(e.published_at, e.created_at)) < julianday(?)
+		ORDER BY COALESCE(e.published_at, e.created_at) DESC, e.id DESC
+		LIMIT ?`,
+		language, scopeID, formatTime(start), formatTime(end), limit)
+	if err != nil {
+		return nil, fmt.Errorf("list digest entries: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []AIDigestEntry
+	for rows.Next() {
+		var entry AIDigestEntry
+		var title, content, publishedAt, summary sql.NullString
+		if err := rows.Scan(&entry.EntryID, &entry.FeedTitle, &title, &content, &publishedAt, &summary); err != nil {
+			return nil, fmt.Errorf("scan digest entry: %w", err)
+		}
+		entry.Title = title.String
+		entry.Content = content.String
+		entry.Summary = summary.String
+		if publishedAt.Valid {
+			if t, err := parseTime(publishedAt.String); err == nil {
+				entry.PublishedAt = &t
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
@@ -4,6 +4,8 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"fmt"
+	"strings"
 	"time"
 
 	"gist/backend/internal/model"
@@ -16,6 +18,14 @@ type AIListTranslationRepository interface {
 	Save(ctx context.Context, entryID int64, language, title, summary string) error
 	DeleteByEntryID(ctx context.Context, entryID int64) error
 	DeleteAll(ctx context.Context) (int64, error)
+	// ListUntranslated returns up to limit entries created after since that have
+	// no list translation in language, newest first. Only entries of live feeds
+	// whose auto_translate is one of modes count. Only ID, FeedID, Title,
+	// Content and Language are set.
+	ListUntranslated(ctx context.Context, language string, modes []string, since time.Time, limit int) ([]model.Entry, error)
+	// ListUntranslatedInFeeds is ListUntranslated for the entries of the live
+	// feeds in feedIDs, whatever their auto_translate.
+	ListUntranslatedInFeeds(ctx context.Context, language string, feedIDs []int64, since time.Time, limit int) ([]model.Entry, error)
 }
 
 type aiListTranslationRepository struct {
@@ -121,3 +131,73 @@ func (r *aiListTranslationRepository) DeleteAll(ctx context.Context) (int64, err
 	}
 	return result.RowsAffected()
 }
+
+func (r *aiListTranslationRepository) ListUntranslated(ctx context.Context, language string, modes []string, since time.Time, limit int) ([]model.Entry, error) {
+	if len(modes) == 0 {
+		return nil, nil
+	}
+	args := make([]interface{}, 0, len(modes))
+	for _, mode := range modes {
+		args = append(args, mode)
+	}
+	return r.listUntranslated(ctx, `f.auto_translate IN (`+strings.TrimSuffix(strings.Repeat("?,", len(modes)), ",")+`)`, args, language, since, limit)
+}
+
+func (r *aiListTranslationRepository) ListUntranslatedInFeeds(ctx context.Context, language string, feedIDs []int64, since time.Time, limit int) ([]model.Entry, error) {
+	if len(feedIDs) == 0 {
+		return nil, nil
+	}
+	args := make([]interface{}, 0, len(feedIDs))
+	for _, id := range feedIDs {
+		args = append(args, id)
+	}
+	return r.listUntranslated(ctx, `f.id IN (`+strings.TrimSuffix(strings.Repeat("?,", len(feedIDs)), ",")+`)`, args, language, since, limit)
+}
+
+// listUntranslated runs ListUntranslated with feedCondition, a condition on
+// the feed f taking feedArgs, choosing the feeds.
+func (r *aiListTranslationRepository) listUntranslated(ctx context.Context, feedCondition string, feedArgs []interface{}, language string, since time.Time, limit int) ([]model.Entry, error) {
+	if limit <= 0 {
+		return nil, nil
+	}
+
+	args := make([]interface{}, 0, len(feedArgs)+3)
+	args = append(args, formatTime(since))
+	args = append(args, feedArgs...)
+	args = append(args, language, limit)
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT e.id, e.feed_id, e.title, e.content, e.language
+		FROM entries e
+		JOIN feeds f ON f.id = e.feed_id
+		WHERE e.created_at > ?
+		  AND f.deleted_at IS NULL
+		  AND `+feedCondition+`
+		  AND NOT EXISTS (
+			SELECT 1 FROM ai_list_translations t WHERE t.entry_id = e.id AND t.language = ?
+		  )
+		ORDER BY e.created_at DESC, e.id DESC
+		LIMIT ?`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list untranslated entries: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []model.Entry
+	for rows.Next() {
+		var entry model.Entry
+		var title, content, language sql.NullString
+		if err := rows.Scan(&entry.ID, &entry.FeedID, &title, &content, &language); err != nil {
+			return nil, fmt.Errorf("scan untranslated entry: %w", err)
+		}
+		if title.Valid {
+			entry.Title = &title.String
+		}
+		if content.Valid {
+			entry.Content = &content.String
+		}
+		entry.Language = language.String
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
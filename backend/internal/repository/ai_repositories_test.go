@@ -4,6 +4,7 @@ import (
 	"context"
 	"gist/backend/internal/repository"
 	"testing"
+	"time"
 
 	"gist/backend/internal/model"
 	"gist/backend/internal/repository/testutil"
@@ -13,7 +14,7 @@ import (
 
 func TestAISummaryRepository(t *testing.T) {
 	db := testutil.NewTestDB(t)
-	repo := repository.NewAISummaryRepository(db)
+	repo := repository.NewAISummaryRepository(db, nil, nil)
 	ctx := context.Background()
 
 	feedID := testutil.SeedFeed(t, db, model.Feed{Title: "F", URL: "u"})
@@ -45,6 +46,33 @@ func TestAISummaryRepository(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestAISummaryRepository_GetBatchPrefersFeedContent(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	repo := repository.NewAISummaryRepository(db, nil, nil)
+	ctx := context.Background()
+
+	feedID := testutil.SeedFeed(t, db, model.Feed{Title: "F", URL: "u"})
+	both := testutil.SeedEntry(t, db, model.Entry{FeedID: feedID})
+	readableOnly := testutil.SeedEntry(t, db, model.Entry{FeedID: feedID})
+	otherLanguage := testutil.SeedEntry(t, db, model.Entry{FeedID: feedID})
+
+	require.NoError(t, repo.Save(ctx, both, true, "en-US", "readable"))
+	require.NoError(t, repo.Save(ctx, both, false, "en-US", "plain"))
+	require.NoError(t, repo.Save(ctx, readableOnly, true, "en-US", "readable only"))
+	require.NoError(t, repo.Save(ctx, otherLanguage, false, "zh-CN", "chinese"))
+
+	batch, err := repo.GetBatch(ctx, []int64{both, readableOnly, otherLanguage}, "en-US")
+	require.NoError(t, err)
+	require.Len(t, batch, 2)
+	require.Equal(t, "plain", batch[both].Summary)
+	require.False(t, batch[both].IsReadability)
+	require.Equal(t, "readable only", batch[readableOnly].Summary)
+
+	empty, err := repo.GetBatch(ctx, nil, "en-US")
+	require.NoError(t, err)
+	require.Empty(t, empty)
+}
+
 func TestAITranslationRepository(t *testing.T) {
 	db := testutil.NewTestDB(t)
 	repo := repository.NewAITranslationRepository(db)
@@ -123,3 +151,249 @@ func TestAIListTranslationRepository_GetBatchAndDelete(t *testing.T) {
 	require.NoError(t, err)
 	require.Len(t, remaining, 1)
 }
+
+func TestAIListTranslationRepository_ListUntranslated(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	repo := repository.NewAIListTranslationRepository(db)
+	ctx := context.Background()
+
+	inheritFeed := testutil.SeedFeed(t, db, model.Feed{Title: "Inherit", URL: "https://a.example.com/feed"})
+	onFeed := testutil.SeedFeed(t, db, model.Feed{Title: "On", URL: "https://b.example.com/feed", AutoTranslate: model.FeedAutoTranslateOn})
+	offFeed := testutil.SeedFeed(t, db, model.Feed{Title: "Off", URL: "https://c.example.com/feed", AutoTranslate: model.FeedAutoTranslateOff})
+	deletedFeed := testutil.SeedFeed(t, db, model.Feed{Title: "Deleted", URL: "https://d.example.com/feed", AutoTranslate: model.FeedAutoTranslateOn})
+	_, err := db.Exec(`UPDATE feeds SET deleted_at = ? WHERE id = ?`, time.Now().UTC().Format(time.RFC3339), deletedFeed)
+	require.NoError(t, err)
+
+	title := "Hello"
+	inheritEntry := testutil.SeedEntry(t, db, model.Entry{FeedID: inheritFeed, Title: &title})
+	onEntry := testutil.SeedEntry(t, db, model.Entry{FeedID: onFeed, Title: &title, Language: "en"})
+	translatedEntry := testutil.SeedEntry(t, db, model.Entry{FeedID: onFeed})
+	oldEntry := testutil.SeedEntry(t, db, model.Entry{FeedID: onFeed})
+	testutil.SeedEntry(t, db, model.Entry{FeedID: offFeed})
+	testutil.SeedEntry(t, db, model.Entry{FeedID: deletedFeed})
+
+	_, err = db.Exec(`UPDATE entries SET created_at = ? WHERE id = ?`, time.Now().Add(-48*time.Hour).UTC().Format(time.RFC3339), oldEntry)
+	require.NoError(t, err)
+	require.NoError(t, repo.Save(ctx, translatedEntry, "zh-CN", "T", "S"))
+
+	since := time.Now().Add(-time.Hour)
+	ids := func(entries []model.Entry) []int64 {
+		out := make([]int64, 0, len(entries))
+		for _, entry := range entries {
+			out = append(out, entry.ID)
+		}
+		return out
+	}
+
+	entries, err := repo.ListUntranslated(ctx, "zh-CN", []string{model.FeedAutoTranslateOn}, since, 10)
+	require.NoError(t, err)
+	require.Equal(t, []int64{onEntry}, ids(entries))
+	require.Equal(t, onFeed, entries[0].FeedID)
+	require.NotNil(t, entries[0].Title)
+	require.Equal(t, "Hello", *entries[0].Title)
+	require.Equal(t, "en", entries[0].Language)
+
+	entries, err = repo.ListUntranslated(ctx, "zh-CN", []string{model.FeedAutoTranslateOn, model.FeedAutoTranslateInherit}, since, 10)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []int64{onEntry, inheritEntry}, ids(entries))
+
+	// A translation in another language does not count
+	entries, err = repo.ListUntranslated(ctx, "en-US", []string{model.FeedAutoTranslateOn}, since, 10)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []int64{onEntry, translatedEntry}, ids(entries))
+
+	entries, err = repo.ListUntranslated(ctx, "en-US", []string{model.FeedAutoTranslateOn}, since, 1)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+
+	entries, err = repo.ListUntranslated(ctx, "zh-CN", nil, since, 10)
+	require.NoError(t, err)
+	require.Empty(t, entries)
+}
+
+func TestAIListTranslationRepository_ListUntranslatedInFeeds(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	repo := repository.NewAIListTranslationRepository(db)
+	ctx := context.Background()
+
+	// The feeds' own auto_translate doesn't matter here
+	offFeed := testutil.SeedFeed(t, db, model.Feed{Title: "Off", URL: "https://a.example.com/feed", AutoTranslate: model.FeedAutoTranslateOff})
+	otherFeed := testutil.SeedFeed(t, db, model.Feed{Title: "Other", URL: "https://b.example.com/feed"})
+	offEntry := testutil.SeedEntry(t, db, model.Entry{FeedID: offFeed})
+	testutil.SeedEntry(t, db, model.Entry{FeedID: otherFeed})
+
+	since := time.Now().Add(-time.Hour)
+	entries, err := repo.ListUntranslatedInFeeds(ctx, "zh-CN", []int64{offFeed}, since, 10)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, offEntry, entries[0].ID)
+
+	entries, err = repo.ListUntranslatedInFeeds(ctx, "zh-CN", nil, since, 10)
+	require.NoError(t, err)
+	require.Empty(t, entries)
+}
+
+func TestAIUsageRepository(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	repo := repository.NewAIUsageRepository(db)
+	ctx := context.Background()
+
+	feedID := testutil.SeedFeed(t, db, model.Feed{Title: "F", URL: "u"})
+	entryID := testutil.SeedEntry(t, db, model.Entry{FeedID: feedID})
+
+	day1 := time.Date(2025, 3, 1, 23, 30, 0, 0, time.UTC)
+	day2 := time.Date(2025, 3, 2, 8, 0, 0, 0, time.UTC)
+	rows := []model.AIUsage{
+		{Operation: model.AIUsageSummarize, Provider: "openai", Model: "gpt-4o", PromptTokens: 100, CompletionTokens: 20, EntryID: &entryID, CreatedAt: day1},
+		{Operation: model.AIUsageSummarize, Provider: "openai", Model: "gpt-4o", PromptTokens: 50, CompletionTokens: 10, CreatedAt: day1},
+		{Operation: model.AIUsageTranslate, Provider: "openai", Model: "gpt-4o", PromptTokens: 30, CompletionTokens: 30, Estimated: true, CreatedAt: day2},
+		{Operation: model.AIUsageTranslate, Provider: "openai", Model: "gpt-4o", PromptTokens: 1, CompletionTokens: 1, CreatedAt: day2.AddDate(0, 1, 0)},
+	}
+	for _, row := range rows {
+		require.NoError(t, repo.Create(ctx, row))
+	}
+
+	totals, err := repo.SumByDay(ctx, day1.Truncate(24*time.Hour), day2.Truncate(24*time.Hour).AddDate(0, 0, 1))
+	require.NoError(t, err)
+	require.Equal(t, []model.AIUsageTotal{
+		{Day: "2025-03-01", Operation: model.AIUsageSummarize, Model: "gpt-4o", Calls: 2, PromptTokens: 150, CompletionTokens: 30},
+		{Day: "2025-03-02", Operation: model.AIUsageTranslate, Model: "gpt-4o", Calls: 1, PromptTokens: 30, CompletionTokens: 30},
+	}, totals)
+
+	// Usage outlives the entry it was spent on
+	_, err = db.Exec(`DELETE FROM entries WHERE id = ?`, entryID)
+	require.NoError(t, err)
+	var count int
+	require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM ai_usage`).Scan(&count))
+	require.Equal(t, 4, count)
+	require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM ai_usage WHERE entry_id IS NOT NULL`).Scan(&count))
+	require.Equal(t, 0, count)
+
+	deleted, err := repo.DeleteBefore(ctx, day2)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), deleted)
+}
+
+func TestFeedTitleTranslationRepository(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	repo := repository.NewFeedTitleTranslationRepository(db, nil)
+	ctx := context.Background()
+
+	feedID1 := testutil.SeedFeed(t, db, model.Feed{Title: "Хабр", URL: "u1"})
+	feedID2 := testutil.SeedFeed(t, db, model.Feed{Title: "はてな", URL: "u2"})
+
+	require.NoError(t, repo.Save(ctx, feedID1, "en-US", "h1", "Habr"))
+	require.NoError(t, repo.Save(ctx, feedID2, "en-US", "h2", "Hatena"))
+	require.NoError(t, repo.Save(ctx, feedID1, "zh-CN", "h1", "哈布尔"))
+
+	// Saving again replaces the row for that language
+	require.NoError(t, repo.Save(ctx, feedID2, "en-US", "h2b", "Hatena Blog"))
+
+	batch, err := repo.GetBatch(ctx, []int64{feedID1, feedID2}, "en-US")
+	require.NoError(t, err)
+	require.Len(t, batch, 2)
+	require.Equal(t, "Habr", batch[feedID1].Title)
+	require.Equal(t, "h2b", batch[feedID2].SourceHash)
+	require.Equal(t, "Hatena Blog", batch[feedID2].Title)
+
+	_, err = db.Exec(`DELETE FROM feeds WHERE id = ?`, feedID1)
+	require.NoError(t, err)
+	count, err := repo.DeleteAll(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), count)
+}
+
+func TestAIDigestRepository(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	repo := repository.NewAIDigestRepository(db)
+	ctx := context.Background()
+
+	end := time.Date(2026, 5, 2, 8, 0, 0, 0, time.UTC)
+	digest := model.AIDigest{Scope: model.AIDigestScopeFolder, ScopeID: 7, WindowStart: end.Add(-24 * time.Hour), WindowEnd: end, Language: "en-US", Entries: 3, Digest: "first"}
+	require.NoError(t, repo.Save(ctx, digest))
+
+	got, err := repo.Get(ctx, model.AIDigestScopeFolder, 7, end, "en-US")
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	require.Equal(t, "first", got.Digest)
+	require.Equal(t, 3, got.Entries)
+	require.True(t, got.WindowStart.Equal(digest.WindowStart))
+
+	// Any part of the key missing is a miss
+	for _, miss := range []struct {
+		scope    string
+		scopeID  int64
+		end      time.Time
+		language string
+	}{
+		{model.AIDigestScopeFeed, 7, end, "en-US"},
+		{model.AIDigestScopeFolder, 8, end, "en-US"},
+		{model.AIDigestScopeFolder, 7, end.Add(time.Minute), "en-US"},
+		{model.AIDigestScopeFolder, 7, end, "zh-CN"},
+	} {
+		got, err := repo.Get(ctx, miss.scope, miss.scopeID, miss.end, miss.language)
+		require.NoError(t, err)
+		require.Nil(t, got)
+	}
+
+	digest.Digest = "second"
+	require.NoError(t, repo.Save(ctx, digest))
+	got, err = repo.Get(ctx, model.AIDigestScopeFolder, 7, end, "en-US")
+	require.NoError(t, err)
+	require.Equal(t, "second", got.Digest)
+
+	count, err := repo.DeleteAll(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), count)
+}
+
+func TestAIDigestRepository_ListUnread(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	repo := repository.NewAIDigestRepository(db)
+	summaries := repository.NewAISummaryRepository(db, nil, nil)
+	ctx := context.Background()
+
+	folderID := testutil.SeedFolder(t, db, "Security", nil, "article")
+	feedID := testutil.SeedFeed(t, db, model.Feed{Title: "Upstream", CustomTitle: stringPtr("Renamed"), URL: "https://a.example.com/feed", FolderID: &folderID})
+	otherFeed := testutil.SeedFeed(t, db, model.Feed{Title: "Other", URL: "https://b.example.com/feed"})
+
+	end := time.Now().UTC()
+	at := func(ago time.Duration) *time.Time {
+		t := end.Add(-ago)
+		return &t
+	}
+	title, content := "Hello", "<p>Body</p>"
+	newer := testutil.SeedEntry(t, db, model.Entry{FeedID: feedID, Title: &title, Content: &content, PublishedAt: at(time.Hour)})
+	older := testutil.SeedEntry(t, db, model.Entry{FeedID: feedID, PublishedAt: at(3 * time.Hour)})
+	testutil.SeedEntry(t, db, model.Entry{FeedID: feedID, PublishedAt: at(2 * time.Hour), Read: true})
+	testutil.SeedEntry(t, db, model.Entry{FeedID: feedID, PublishedAt: at(48 * time.Hour)})
+	otherEntry := testutil.SeedEntry(t, db, model.Entry{FeedID: otherFeed, PublishedAt: at(time.Hour)})
+	require.NoError(t, summaries.Save(ctx, newer, true, "en-US", "readable"))
+	require.NoError(t, summaries.Save(ctx, newer, false, "en-US", "plain"))
+	require.NoError(t, summaries.Save(ctx, older, false, "zh-CN", "other language"))
+
+	start := end.Add(-24 * time.Hour)
+	entries, err := repo.ListUnread(ctx, model.AIDigestScopeFolder, folderID, start, end, "en-US", 10)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	require.Equal(t, newer, entries[0].EntryID)
+	require.Equal(t, "Renamed", entries[0].FeedTitle)
+	require.Equal(t, "Hello", entries[0].Title)
+	require.Equal(t, content, entries[0].Content)
+	require.Equal(t, "plain", entries[0].Summary)
+	require.NotNil(t, entries[0].PublishedAt)
+	require.Equal(t, older, entries[1].EntryID)
+	require.Empty(t, entries[1].Summary)
+
+	entries, err = repo.ListUnread(ctx, model.AIDigestScopeFolder, folderID, start, end, "en-US", 1)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+
+	entries, err = repo.ListUnread(ctx, model.AIDigestScopeFeed, otherFeed, start, end, "en-US", 10)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, otherEntry, entries[0].EntryID)
+
+	_, err = repo.ListUnread(ctx, "tag", 1, start, end, "en-US", 10)
+	require.Error(t, err)
+}
@@ -12,17 +12,22 @@ import (
 
 type AISummaryRepository interface {
 	Get(ctx context.Context, entryID int64, isReadability bool, language string) (*model.AISummary, error)
+	// GetBatch returns one summary per entry, preferring the feed-content summary
+	// over the readability one when both exist.
+	GetBatch(ctx context.Context, entryIDs []int64, language string) (map[int64]*model.AISummary, error)
 	Save(ctx context.Context, entryID int64, isReadability bool, language, summary string) error
 	DeleteByEntryID(ctx context.Context, entryID int64) error
 	DeleteAll(ctx context.Context) (int64, error)
 }
 
 type aiSummaryRepository struct {
-	db dbtx
+	db      dbtx
+	changes *ChangeTracker
+	writes  *WriteTracker
 }
 
-func NewAISummaryRepository(db dbtx) AISummaryRepository {
-	return &aiSummaryRepository{db: db}
+func NewAISummaryRepository(db dbtx, changes *ChangeTracker, writes *WriteTracker) AISummaryRepository {
+	return &aiSummaryRepository{db: db, changes: changes, writes: writes}
 }
 
 func (r *aiSummaryRepository) Get(ctx context.Context, entryID int64, isReadability bool, language string) (*model.AISummary, error) {
@@ -56,7 +61,57 @@ func (r *aiSummaryRepository) Get(ctx context.Context, entryID int64, isReadabil
 	return &s, nil
 }
 
+func (r *aiSummaryRepository) GetBatch(ctx context.Context, entryIDs []int64, language string) (map[int64]*model.AISummary, error) {
+	if len(entryIDs) == 0 {
+		return make(map[int64]*model.AISummary), nil
+	}
+
+	// Readability rows sort last so the plain summary wins
+	query := `SELECT id, entry_id, is_readability, language, summary, created_at
+	          FROM ai_summaries WHERE language = ? AND entry_id IN (`
+	args := make([]interface{}, 0, len(entryIDs)+1)
+	args = append(args, language)
+
+	for i, id := range entryIDs {
+		if i > 0 {
+			query += ","
+		}
+		query += "?"
+		args = append(args, id)
+	}
+	query += ") ORDER BY is_readability"
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make(map[int64]*model.AISummary)
+	for rows.Next() {
+		var s model.AISummary
+		var isReadabilityDB int
+		var createdAt string
+
+		if err := rows.Scan(&s.ID, &s.EntryID, &isReadabilityDB, &s.Language, &s.Summary, &createdAt); err != nil {
+			return nil, err
+		}
+		if _, ok := result[s.EntryID]; ok {
+			continue
+		}
+
+		s.IsReadability = isReadabilityDB == 1
+		s.CreatedAt, _ = parseTime(createdAt)
+		result[s.EntryID] = &s
+	}
+
+	return result, rows.Err()
+}
+
 func (r *aiSummaryRepository) Save(ctx context.Context, entryID int64, isReadability bool, language, summary string) error {
+	// Entry lists can be filtered by having a summary
+	defer r.changes.Notify()
+
 	id := snowflake.NextID()
 	now := formatTime(time.Now())
 
@@ -65,27 +120,53 @@ func (r *aiSummaryRepository) Save(ctx context.Context, entryID int64, isReadabi
 		isReadabilityInt = 1
 	}
 
-	_, err := r.db.ExecContext(
-		ctx,
-		`INSERT INTO ai_summaries (id, entry_id, is_readability, language, summary, created_at)
-		 VALUES (?, ?, ?, ?, ?, ?)
-		 ON CONFLICT(entry_id, is_readability, language) DO UPDATE SET
-		   summary = excluded.summary,
-		   created_at = excluded.created_at`,
-		id, entryID, isReadabilityInt, language, summary, now,
-	)
-	return err
+	// Summaries are searchable along with the entry
+	return withTx(ctx, r.db, r.writes, func(tx dbtx) error {
+		if _, err := tx.ExecContext(
+			ctx,
+			`INSERT INTO ai_summaries (id, entry_id, is_readability, language, summary, created_at)
+			 VALUES (?, ?, ?, ?, ?, ?)
+			 ON CONFLICT(entry_id, is_readability, language) DO UPDATE SET
+			   summary = excluded.summary,
+			   created_at = excluded.created_at`,
+			id, entryID, isReadabilityInt, language, summary, now,
+		); err != nil {
+			return err
+		}
+		return indexSummaryText(ctx, tx, entryID)
+	})
 }
 
 func (r *aiSummaryRepository) DeleteByEntryID(ctx context.Context, entryID int64) error {
-	_, err := r.db.ExecContext(ctx, `DELETE FROM ai_summaries WHERE entry_id = ?`, entryID)
-	return err
+	defer r.changes.Notify()
+
+	return withTx(ctx, r.db, r.writes, func(tx dbtx) error {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM ai_summaries WHERE entry_id = ?`, entryID); err != nil {
+			return err
+		}
+		return indexSummaryText(ctx, tx, entryID)
+	})
 }
 
 func (r *aiSummaryRepository) DeleteAll(ctx context.Context) (int64, error) {
-	result, err := r.db.ExecContext(ctx, `DELETE FROM ai_summaries`)
+	defer r.changes.Notify()
+
+	var deleted int64
+	err := withTx(ctx, r.db, r.writes, func(tx dbtx) error {
+		result, err := tx.ExecContext(ctx, `DELETE FROM ai_summaries`)
+		if err != nil {
+			return err
+		}
+		if deleted, err = result.RowsAffected(); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, `UPDATE entries_fts_ext SET summary_text = '' WHERE summary_text != ''`); err != nil {
+			return err
+		}
+		return pruneSearchText(ctx, tx)
+	})
 	if err != nil {
 		return 0, err
 	}
-	return result.RowsAffected()
+	return deleted, nil
 }
@@ -0,0 +1,83 @@
+//go:generate mockgen -source=$GOFILE -destination=mock/$GOFILE -package=mock
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gist/backend/internal/model"
+	"gist/backend/pkg/snowflake"
+)
+
+// AIUsageRepository stores per-operation AI token usage.
+type AIUsageRepository interface {
+	Create(ctx context.Context, usage model.AIUsage) error
+	// SumByDay totals usage created in [from, to) per UTC day, operation and model,
+	// ordered by day then operation.
+	SumByDay(ctx context.Context, from, to time.Time) ([]model.AIUsageTotal, error)
+	// DeleteBefore removes usage created before the given time.
+	DeleteBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+type aiUsageRepository struct {
+	db dbtx
+}
+
+func NewAIUsageRepository(db dbtx) AIUsageRepository {
+	return &aiUsageRepository{db: db}
+}
+
+func (r *aiUsageRepository) Create(ctx context.Context, usage model.AIUsage) error {
+	if usage.CreatedAt.IsZero() {
+		usage.CreatedAt = time.Now()
+	}
+	estimated := 0
+	if usage.Estimated {
+		estimated = 1
+	}
+
+	_, err := r.db.ExecContext(
+		ctx,
+		`INSERT INTO ai_usage (id, operation, provider, model, prompt_tokens, completion_tokens, estimated, entry_id, created_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		snowflake.NextID(), usage.Operation, usage.Provider, usage.Model,
+		usage.PromptTokens, usage.CompletionTokens, estimated, nullableInt64(usage.EntryID), formatTime(usage.CreatedAt),
+	)
+	return err
+}
+
+func (r *aiUsageRepository) SumByDay(ctx context.Context, from, to time.Time) ([]model.AIUsageTotal, error) {
+	// created_at is UTC RFC3339, so its first ten characters are the UTC day
+	rows, err := r.db.QueryContext(
+		ctx,
+		`SELECT substr(created_at, 1, 10) AS day, operation, model,
+		        COUNT(*), COALESCE(SUM(prompt_tokens), 0), COALESCE(SUM(completion_tokens), 0)
+		 FROM ai_usage
+		 WHERE created_at >= ? AND created_at < ?
+		 GROUP BY day, operation, model
+		 ORDER BY day, operation, model`,
+		formatTime(from), formatTime(to),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var totals []model.AIUsageTotal
+	for rows.Next() {
+		var t model.AIUsageTotal
+		if err := rows.Scan(&t.Day, &t.Operation, &t.Model, &t.Calls, &t.PromptTokens, &t.CompletionTokens); err != nil {
+			return nil, err
+		}
+		totals = append(totals, t)
+	}
+	return totals, rows.Err()
+}
+
+func (r *aiUsageRepository) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM ai_usage WHERE created_at < ?`, formatTime(before))
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,142 @@
+//go:generate mockgen -source=$GOFILE -destination=mock/$GOFILE -package=mock
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"gist/backend/internal/model"
+	"gist/backend/pkg/snowflake"
+)
+
+// APITokenRepository defines the interface for API token storage.
+type APITokenRepository interface {
+	Create(ctx context.Context, name, tokenHash string, expiresAt *time.Time) (*model.APIToken, error)
+	GetByHash(ctx context.Context, tokenHash string) (*model.APIToken, error)
+	List(ctx context.Context) ([]model.APIToken, error)
+	Delete(ctx context.Context, id int64) error
+	UpdateLastUsed(ctx context.Context, id int64, usedAt time.Time) error
+}
+
+type apiTokenRepository struct {
+	db *sql.DB
+}
+
+// NewAPITokenRepository creates a new API token repository.
+func NewAPITokenRepository(db *sql.DB) APITokenRepository {
+	return &apiTokenRepository{db: db}
+}
+
+// Create stores a new token hash.
+func (r *apiTokenRepository) Create(ctx context.Context, name, tokenHash string, expiresAt *time.Time) (*model.APIToken, error) {
+	id := snowflake.NextID()
+	now := time.Now().UTC()
+
+	var expires interface{}
+	if expiresAt != nil {
+		expires = formatTime(*expiresAt)
+	}
+
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO api_tokens (id, name, token_hash, created_at, expires_at)
+		VALUES (?, ?, ?, ?, ?)
+	`, id, name, tokenHash, formatTime(now), expires)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.APIToken{
+		ID:        id,
+		Name:      name,
+		TokenHash: tokenHash,
+		CreatedAt: now,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// GetByHash retrieves a token by its hash. Returns nil if not found.
+func (r *apiTokenRepository) GetByHash(ctx context.Context, tokenHash string) (*model.APIToken, error) {
+	row := r.db.QueryRowContext(ctx, `
+		SELECT id, name, token_hash, created_at, last_used_at, expires_at FROM api_tokens WHERE token_hash = ?
+	`, tokenHash)
+
+	token, err := scanAPIToken(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return token, nil
+}
+
+// List retrieves all tokens, newest first.
+func (r *apiTokenRepository) List(ctx context.Context) ([]model.APIToken, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, name, token_hash, created_at, last_used_at, expires_at FROM api_tokens ORDER BY created_at DESC, id DESC
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tokens []model.APIToken
+	for rows.Next() {
+		token, err := scanAPIToken(rows)
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, *token)
+	}
+	return tokens, rows.Err()
+}
+
+// Delete removes a token by ID.
+func (r *apiTokenRepository) Delete(ctx context.Context, id int64) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM api_tokens WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// UpdateLastUsed records when a token was last used.
+func (r *apiTokenRepository) UpdateLastUsed(ctx context.Context, id int64, usedAt time.Time) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE api_tokens SET last_used_at = ? WHERE id = ?`, formatTime(usedAt), id)
+	return err
+}
+
+type apiTokenScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanAPIToken(scanner apiTokenScanner) (*model.APIToken, error) {
+	var t model.APIToken
+	var createdAt string
+	var lastUsedAt, expiresAt sql.NullString
+	if err := scanner.Scan(&t.ID, &t.Name, &t.TokenHash, &createdAt, &lastUsedAt, &expiresAt); err != nil {
+		return nil, err
+	}
+
+	t.CreatedAt, _ = parseTime(createdAt)
+	if lastUsedAt.Valid {
+		if parsed, err := parseTime(lastUsedAt.String); err == nil {
+			t.LastUsedAt = &parsed
+		}
+	}
+	if expiresAt.Valid {
+		if parsed, err := parseTime(expiresAt.String); err == nil {
+			t.ExpiresAt = &parsed
+		}
+	}
+	return &t, nil
+}
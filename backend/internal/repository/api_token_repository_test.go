@@ -0,0 +1,54 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"gist/backend/internal/repository"
+	"gist/backend/internal/repository/testutil"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPITokenRepository(t *testing.T) {
+	t.Parallel()
+
+	db := testutil.NewTestDB(t)
+	repo := repository.NewAPITokenRepository(db)
+	ctx := context.Background()
+
+	expires := time.Now().Add(24 * time.Hour).UTC().Truncate(time.Second)
+	created, err := repo.Create(ctx, "cli", "hash-1", &expires)
+	require.NoError(t, err)
+	require.NotZero(t, created.ID)
+
+	_, err = repo.Create(ctx, "mobile", "hash-2", nil)
+	require.NoError(t, err)
+
+	got, err := repo.GetByHash(ctx, "hash-1")
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	require.Equal(t, "cli", got.Name)
+	require.NotNil(t, got.ExpiresAt)
+	require.True(t, got.ExpiresAt.Equal(expires))
+	require.Nil(t, got.LastUsedAt)
+
+	usedAt := time.Now().UTC()
+	require.NoError(t, repo.UpdateLastUsed(ctx, created.ID, usedAt))
+	got, err = repo.GetByHash(ctx, "hash-1")
+	require.NoError(t, err)
+	require.NotNil(t, got.LastUsedAt)
+
+	tokens, err := repo.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, tokens, 2)
+
+	require.NoError(t, repo.Delete(ctx, created.ID))
+	require.ErrorIs(t, repo.Delete(ctx, created.ID), sql.ErrNoRows)
+
+	missing, err := repo.GetByHash(ctx, "hash-1")
+	require.NoError(t, err)
+	require.Nil(t, missing)
+}
@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// ChangeTracker counts writes to entries, feeds and folders. It only lives in
+// memory and starts from the boot time, so versions from a previous process
+// never match after a restart.
+type ChangeTracker struct {
+	version atomic.Uint64
+}
+
+// NewChangeTracker creates a tracker seeded with the current time.
+func NewChangeTracker() *ChangeTracker {
+	t := &ChangeTracker{}
+	t.version.Store(uint64(time.Now().UnixNano()))
+	return t
+}
+
+// Version returns the current write counter.
+func (t *ChangeTracker) Version() uint64 {
+	return t.version.Load()
+}
+
+// Notify bumps the write counter. The entry, feed and folder repositories call
+// it after every write; a nil tracker ignores it.
+func (t *ChangeTracker) Notify() {
+	if t == nil {
+		return
+	}
+	t.version.Add(1)
+}
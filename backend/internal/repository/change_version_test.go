@@ -0,0 +1,42 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+
+	"gist/backend/internal/repository"
+	"gist/backend/internal/repository/testutil"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangeVersion_BumpsOnWrites(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	changes := repository.NewChangeTracker()
+	folders := repository.NewFolderRepository(db, changes, nil)
+	ctx := context.Background()
+
+	before := changes.Version()
+	folder, err := folders.Create(ctx, "Tech", nil, "article")
+	require.NoError(t, err)
+	afterCreate := changes.Version()
+	require.Greater(t, afterCreate, before)
+
+	_, err = folders.List(ctx)
+	require.NoError(t, err)
+	_, err = folders.GetByID(ctx, folder.ID)
+	require.NoError(t, err)
+	require.Equal(t, afterCreate, changes.Version())
+
+	_, err = folders.Update(ctx, folder.ID, "Science", nil)
+	require.NoError(t, err)
+	require.Greater(t, changes.Version(), afterCreate)
+}
+
+func TestChangeVersion_NilTrackerIgnoresWrites(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	folders := repository.NewFolderRepository(db, nil, nil)
+
+	_, err := folders.Create(context.Background(), "Tech", nil, "article")
+	require.NoError(t, err)
+}
@@ -12,32 +12,36 @@ import (
 
 // DomainRateLimitRepository defines the interface for domain rate limit storage.
 type DomainRateLimitRepository interface {
-	Create(ctx context.Context, host string, intervalSeconds int) (*model.DomainRateLimit, error)
-	Update(ctx context.Context, host string, intervalSeconds int) error
+	Create(ctx context.Context, host string, intervalSeconds int, maxConcurrent int) (*model.DomainRateLimit, error)
+	Update(ctx context.Context, host string, intervalSeconds int, maxConcurrent int) error
 	Delete(ctx context.Context, host string) error
 	GetByHost(ctx context.Context, host string) (*model.DomainRateLimit, error)
 	List(ctx context.Context) ([]model.DomainRateLimit, error)
 }
 
 type domainRateLimitRepository struct {
-	db *sql.DB
+	db      *sql.DB
+	changes *ChangeTracker
 }
 
 // NewDomainRateLimitRepository creates a new domain rate limit repository.
-func NewDomainRateLimitRepository(db *sql.DB) DomainRateLimitRepository {
-	return &domainRateLimitRepository{db: db}
+func NewDomainRateLimitRepository(db *sql.DB, changes *ChangeTracker) DomainRateLimitRepository {
+	return &domainRateLimitRepository{db: db, changes: changes}
 }
 
 // Create creates a new domain rate limit.
-func (r *domainRateLimitRepository) Create(ctx context.Context, host string, intervalSeconds int) (*model.DomainRateLimit, error) {
+func (r *domainRateLimitRepository) Create(ctx context.Context, host string, intervalSeconds int, maxConcurrent int) (*model.DomainRateLimit, error) {
+	// Feed list responses include the poll interval a host limit sets
+	defer r.changes.Notify()
+
 	id := snowflake.NextID()
 	now := time.Now().UTC()
 	nowStr := now.Format(time.RFC3339)
 
 	_, err := r.db.ExecContext(ctx, `
-		INSERT INTO domain_rate_limits (id, host, interval_seconds, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?)
-	`, id, host, intervalSeconds, nowStr, nowStr)
+		INSERT INTO domain_rate_limits (id, host, interval_seconds, max_concurrent, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, id, host, intervalSeconds, maxConcurrent, nowStr, nowStr)
 	if err != nil {
 		return nil, err
 	}
@@ -48,15 +52,18 @@ func (r *domainRateLimitRepository) Create(ctx context.Context, host string, int
 		IntervalSeconds: intervalSeconds,
 		CreatedAt:       now,
 		UpdatedAt:       now,
+		MaxConcurrent:   maxConcurrent,
 	}, nil
 }
 
 // Update updates an existing domain rate limit.
-func (r *domainRateLimitRepository) Update(ctx context.Context, host string, intervalSeconds int) error {
+func (r *domainRateLimitRepository) Update(ctx context.Context, host string, intervalSeconds int, maxConcurrent int) error {
+	defer r.changes.Notify()
+
 	now := time.Now().UTC().Format(time.RFC3339)
 	result, err := r.db.ExecContext(ctx, `
-		UPDATE domain_rate_limits SET interval_seconds = ?, updated_at = ? WHERE host = ?
-	`, intervalSeconds, now, host)
+		UPDATE domain_rate_limits SET interval_seconds = ?, max_concurrent = ?, updated_at = ? WHERE host = ?
+	`, intervalSeconds, maxConcurrent, now, host)
 	if err != nil {
 		return err
 	}
@@ -73,6 +80,8 @@ func (r *domainRateLimitRepository) Update(ctx context.Context, host string, int
 
 // Delete removes a domain rate limit by host.
 func (r *domainRateLimitRepository) Delete(ctx context.Context, host string) error {
+	defer r.changes.Notify()
+
 	result, err := r.db.ExecContext(ctx, `DELETE FROM domain_rate_limits WHERE host = ?`, host)
 	if err != nil {
 		return err
@@ -91,12 +100,12 @@ func (r *domainRateLimitRepository) Delete(ctx context.Context, host string) err
 // GetByHost retrieves a domain rate limit by host.
 func (r *domainRateLimitRepository) GetByHost(ctx context.Context, host string) (*model.DomainRateLimit, error) {
 	row := r.db.QueryRowContext(ctx, `
-		SELECT id, host, interval_seconds, created_at, updated_at FROM domain_rate_limits WHERE host = ?
+		SELECT id, host, interval_seconds, max_concurrent, created_at, updated_at FROM domain_rate_limits WHERE host = ?
 	`, host)
 
 	var d model.DomainRateLimit
 	var createdAt, updatedAt string
-	if err := row.Scan(&d.ID, &d.Host, &d.IntervalSeconds, &createdAt, &updatedAt); err != nil {
+	if err := row.Scan(&d.ID, &d.Host, &d.IntervalSeconds, &d.MaxConcurrent, &createdAt, &updatedAt); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
 		}
@@ -111,7 +120,7 @@ func (r *domainRateLimitRepository) GetByHost(ctx context.Context, host string)
 // List retrieves all domain rate limits.
 func (r *domainRateLimitRepository) List(ctx context.Context) ([]model.DomainRateLimit, error) {
 	rows, err := r.db.QueryContext(ctx, `
-		SELECT id, host, interval_seconds, created_at, updated_at FROM domain_rate_limits ORDER BY host
+		SELECT id, host, interval_seconds, max_concurrent, created_at, updated_at FROM domain_rate_limits ORDER BY host
 	`)
 	if err != nil {
 		return nil, err
@@ -122,7 +131,7 @@ func (r *domainRateLimitRepository) List(ctx context.Context) ([]model.DomainRat
 	for rows.Next() {
 		var d model.DomainRateLimit
 		var createdAt, updatedAt string
-		if err := rows.Scan(&d.ID, &d.Host, &d.IntervalSeconds, &createdAt, &updatedAt); err != nil {
+		if err := rows.Scan(&d.ID, &d.Host, &d.IntervalSeconds, &d.MaxConcurrent, &createdAt, &updatedAt); err != nil {
 			return nil, err
 		}
 		d.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
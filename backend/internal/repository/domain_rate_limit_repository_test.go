@@ -12,11 +12,11 @@ import (
 
 func TestDomainRateLimitRepository(t *testing.T) {
 	db := testutil.NewTestDB(t)
-	repo := repository.NewDomainRateLimitRepository(db)
+	repo := repository.NewDomainRateLimitRepository(db, nil)
 	ctx := context.Background()
 
 	// Create
-	created, err := repo.Create(ctx, "example.com", 60)
+	created, err := repo.Create(ctx, "example.com", 60, 0)
 	require.NoError(t, err)
 	require.NotNil(t, created)
 
@@ -26,12 +26,14 @@ func TestDomainRateLimitRepository(t *testing.T) {
 	require.Len(t, limits, 1)
 	require.Equal(t, "example.com", limits[0].Host)
 	require.Equal(t, 60, limits[0].IntervalSeconds)
+	require.Zero(t, limits[0].MaxConcurrent)
 
 	// Update
-	err = repo.Update(ctx, "example.com", 120)
+	err = repo.Update(ctx, "example.com", 120, 4)
 	require.NoError(t, err)
 	updated, _ := repo.GetByHost(ctx, "example.com")
 	require.Equal(t, 120, updated.IntervalSeconds)
+	require.Equal(t, 4, updated.MaxConcurrent)
 
 	// Delete
 	err = repo.Delete(ctx, "example.com")
@@ -0,0 +1,135 @@
+//go:generate mockgen -source=$GOFILE -destination=mock/$GOFILE -package=mock
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"gist/backend/internal/model"
+)
+
+type EntryArchiveRepository interface {
+	// Get returns sql.ErrNoRows when archival was never attempted for the entry.
+	Get(ctx context.Context, entryID int64) (model.EntryArchive, error)
+	// MarkArchived records that the entry's full content is saved and clears any earlier failure.
+	MarkArchived(ctx context.Context, entryID int64) error
+	// MarkFailed records that archival gave up after attempts fetches.
+	MarkFailed(ctx context.Context, entryID int64, attempts int, lastError string) error
+	// ListFailed returns starred entries whose last archival failed, most recent failure first.
+	ListFailed(ctx context.Context) ([]model.FailedArchive, error)
+}
+
+type entryArchiveRepository struct {
+	db dbtx
+}
+
+func NewEntryArchiveRepository(db dbtx) EntryArchiveRepository {
+	return &entryArchiveRepository{db: db}
+}
+
+func (r *entryArchiveRepository) Get(ctx context.Context, entryID int64) (model.EntryArchive, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT entry_id, attempts, last_error, failed_at, archived_at FROM entry_archives WHERE entry_id = ?`, entryID)
+	var archive model.EntryArchive
+	if err := scanEntryArchive(row, &archive); err != nil {
+		return model.EntryArchive{}, err
+	}
+	return archive, nil
+}
+
+func (r *entryArchiveRepository) MarkArchived(ctx context.Context, entryID int64) error {
+	_, err := r.db.ExecContext(
+		ctx,
+		`INSERT INTO entry_archives (entry_id, attempts, last_error, failed_at, archived_at) VALUES (?, 0, NULL, NULL, ?)
+		 ON CONFLICT(entry_id) DO UPDATE SET attempts = 0, last_error = NULL, failed_at = NULL, archived_at = excluded.archived_at`,
+		entryID,
+		formatTime(time.Now()),
+	)
+	if err != nil {
+		return fmt.Errorf("mark entry archived: %w", err)
+	}
+	return nil
+}
+
+func (r *entryArchiveRepository) MarkFailed(ctx context.Context, entryID int64, attempts int, lastError string) error {
+	_, err := r.db.ExecContext(
+		ctx,
+		`INSERT INTO entry_archives (entry_id, attempts, last_error, failed_at, archived_at) VALUES (?, ?, ?, ?, NULL)
+		 ON CONFLICT(entry_id) DO UPDATE SET attempts = excluded.attempts, last_error = excluded.last_error, failed_at = excluded.failed_at, archived_at = NULL`,
+		entryID,
+		attempts,
+		lastError,
+		formatTime(time.Now()),
+	)
+	if err != nil {
+		return fmt.Errorf("mark entry archive failed: %w", err)
+	}
+	return nil
+}
+
+func (r *entryArchiveRepository) ListFailed(ctx context.Context) ([]model.FailedArchive, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT a.entry_id, a.attempts, a.last_error, a.failed_at, a.archived_at, e.feed_id, e.title, e.url
+		FROM entry_archives a
+		JOIN entries e ON e.id = a.entry_id
+		WHERE a.failed_at IS NOT NULL AND e.starred = 1
+		ORDER BY a.failed_at DESC, a.entry_id DESC
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("list failed entry archives: %w", err)
+	}
+	defer rows.Close()
+
+	var failed []model.FailedArchive
+	for rows.Next() {
+		var item model.FailedArchive
+		var title, url sql.NullString
+		if err := scanEntryArchive(rows, &item.EntryArchive, &item.FeedID, &title, &url); err != nil {
+			return nil, err
+		}
+		if title.Valid {
+			item.Title = &title.String
+		}
+		if url.Valid {
+			item.URL = &url.String
+		}
+		failed = append(failed, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate failed entry archives: %w", err)
+	}
+	return failed, nil
+}
+
+// scanEntryArchive scans the entry_archives columns into archive, followed by any extra destinations.
+func scanEntryArchive(row interface {
+	Scan(dest ...interface{}) error
+}, archive *model.EntryArchive, extra ...interface{}) error {
+	var lastError, failedAt, archivedAt sql.NullString
+	dest := append([]interface{}{&archive.EntryID, &archive.Attempts, &lastError, &failedAt, &archivedAt}, extra...)
+	if err := row.Scan(dest...); err != nil {
+		return fmt.Errorf("scan entry archive: %w", err)
+	}
+	if lastError.Valid {
+		archive.LastError = &lastError.String
+	}
+	for _, field := range []struct {
+		value sql.NullString
+		dest  **time.Time
+		name  string
+	}{
+		{failedAt, &archive.FailedAt, "failed_at"},
+		{archivedAt, &archive.ArchivedAt, "archived_at"},
+	} {
+		if !field.value.Valid {
+			continue
+		}
+		t, err := parseTime(field.value.String)
+		if err != nil {
+			return fmt.Errorf("parse entry archive %s: %w", field.name, err)
+		}
+		*field.dest = &t
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"gist/backend/internal/model"
+	"gist/backend/internal/repository"
+	"gist/backend/internal/repository/testutil"
+)
+
+func TestEntryArchiveRepository_MarkArchivedAndFailed(t *testing.T) {
+	t.Parallel()
+	db := testutil.NewTestDB(t)
+	repo := repository.NewEntryArchiveRepository(db)
+	ctx := context.Background()
+
+	feedID := testutil.SeedFeed(t, db, model.Feed{Title: "Feed", URL: "https://example.com/feed"})
+	entryID := testutil.SeedEntry(t, db, model.Entry{FeedID: feedID, Title: stringPtr("Post"), URL: stringPtr("https://example.com/post"), Starred: true})
+
+	_, err := repo.Get(ctx, entryID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	require.NoError(t, repo.MarkFailed(ctx, entryID, 3, "status 503"))
+	archive, err := repo.Get(ctx, entryID)
+	require.NoError(t, err)
+	require.Equal(t, 3, archive.Attempts)
+	require.Equal(t, "status 503", *archive.LastError)
+	require.NotNil(t, archive.FailedAt)
+	require.Nil(t, archive.ArchivedAt)
+
+	// A later success clears the failure
+	require.NoError(t, repo.MarkArchived(ctx, entryID))
+	archive, err = repo.Get(ctx, entryID)
+	require.NoError(t, err)
+	require.Zero(t, archive.Attempts)
+	require.Nil(t, archive.LastError)
+	require.Nil(t, archive.FailedAt)
+	require.NotNil(t, archive.ArchivedAt)
+
+	// Archive rows go with their entry
+	_, err = db.Exec(`DELETE FROM entries WHERE id = ?`, entryID)
+	require.NoError(t, err)
+	_, err = repo.Get(ctx, entryID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestEntryArchiveRepository_ListFailed(t *testing.T) {
+	t.Parallel()
+	db := testutil.NewTestDB(t)
+	repo := repository.NewEntryArchiveRepository(db)
+	entries := repository.NewEntryRepository(db, nil, nil)
+	ctx := context.Background()
+
+	feedID := testutil.SeedFeed(t, db, model.Feed{Title: "Feed", URL: "https://example.com/feed"})
+	seed := func(url string) int64 {
+		return testutil.SeedEntry(t, db, model.Entry{FeedID: feedID, Title: stringPtr(url), URL: stringPtr(url), Starred: true})
+	}
+	failed := seed("https://example.com/failed")
+	archived := seed("https://example.com/archived")
+	unstarred := seed("https://example.com/unstarred")
+
+	require.NoError(t, repo.MarkFailed(ctx, failed, 3, "timeout"))
+	require.NoError(t, repo.MarkFailed(ctx, archived, 3, "timeout"))
+	require.NoError(t, repo.MarkArchived(ctx, archived))
+	require.NoError(t, repo.MarkFailed(ctx, unstarred, 1, "not found"))
+	require.NoError(t, entries.UpdateStarredStatus(ctx, unstarred, false))
+
+	list, err := repo.ListFailed(ctx)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	require.Equal(t, failed, list[0].EntryID)
+	require.Equal(t, feedID, list[0].FeedID)
+	require.Equal(t, "https://example.com/failed", *list[0].URL)
+	require.Equal(t, "timeout", *list[0].LastError)
+	require.Equal(t, 3, list[0].Attempts)
+}
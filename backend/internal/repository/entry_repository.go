@@ -4,11 +4,15 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"strings"
 	"time"
 
+	"gist/backend/internal/db"
+	"gist/backend/internal/hashutil"
 	"gist/backend/internal/model"
 	"gist/backend/internal/urlutil"
+	"gist/backend/pkg/readtime"
 	"gist/backend/pkg/snowflake"
 )
 
@@ -19,8 +23,54 @@ type EntryListFilter struct {
 	UnreadOnly   bool
 	StarredOnly  bool
 	HasThumbnail bool
-	Limit        int
-	Offset       int
+	NotesOnly    bool
+	// HasReadableContent keeps entries whose readable content has been extracted.
+	HasReadableContent bool
+	// SummaryLanguage, when set, keeps entries with a cached AI summary in that language.
+	SummaryLanguage string
+	// ContentLanguage, when set, keeps entries detected to be in that language,
+	// a primary subtag such as "en".
+	ContentLanguage string
+	// ApplyMutes leaves out entries by authors muted in their feed.
+	ApplyMutes bool
+	// MinReadingMinutes and MaxReadingMinutes bound the estimated reading time (inclusive).
+	MinReadingMinutes *int
+	MaxReadingMinutes *int
+	// UpdatedSince, when set, keeps entries updated at or after it.
+	UpdatedSince *time.Time
+	// IncludeFeed loads Entry.Feed from the joined feed row.
+	IncludeFeed bool
+	Limit       int
+	Offset      int
+}
+
+// Columns of entries_fts an EntrySearchFilter can restrict matching to. The
+// content column also matches readable content and AI summaries.
+const (
+	EntrySearchColumnTitle   = "title"
+	EntrySearchColumnContent = "content"
+)
+
+// unreadSearchBoost scales the relevance of unread hits when a search boosts them.
+const unreadSearchBoost = 1.25
+
+// EntrySearchFilter selects entries for Search.
+type EntrySearchFilter struct {
+	// Query is the search text; every word in it must match, either in the
+	// feed's columns or in the readable content and AI summaries.
+	Query string
+	// Column is an EntrySearchColumn* to match in; empty matches title, content,
+	// author, URL, readable content and AI summaries.
+	Column string
+	FeedID *int64
+	// FolderID keeps entries of feeds in the folder or any of its subfolders.
+	FolderID *int64
+	// BoostUnread ranks unread entries a little above read ones of like relevance.
+	BoostUnread bool
+	// IncludeFeed loads Entry.Feed from the joined feed row.
+	IncludeFeed bool
+	Limit       int
+	Offset      int
 }
 
 type UnreadCount struct {
@@ -28,55 +78,315 @@ type UnreadCount struct {
 	Count  int
 }
 
+// RecentCount is the number of entries of a feed saved since some time.
+type RecentCount struct {
+	FeedID int64
+	Count  int
+}
+
+// RawPublishedAt is a stored published_at value that is not in canonical UTC form.
+type RawPublishedAt struct {
+	EntryID int64
+	FeedID  int64
+	Value   string
+}
+
+// DigestEntry is an entry listed by ListForDigest, with its feed and folder.
+type DigestEntry struct {
+	Entry      model.Entry
+	FeedTitle  string
+	FolderID   *int64
+	FolderName *string
+	// FeedTotal is how many of the feed's entries fall in the window, before the per-feed cap.
+	FeedTotal int
+}
+
+// DailyReadCount is the number of entries read on one calendar day.
+type DailyReadCount struct {
+	// Date is YYYY-MM-DD in the offset passed to CountReadsByDay.
+	Date  string
+	Count int
+}
+
+// FeedReadCount is the number of a feed's entries read in a period.
+type FeedReadCount struct {
+	FeedID int64
+	Title  string
+	Count  int
+}
+
+// FeedClickCount is the number of click-throughs to a feed's entries in a period.
+type FeedClickCount struct {
+	FeedID int64
+	Title  string
+	Count  int
+	// LastClickedAt is the latest click in the period, nil without clicks.
+	LastClickedAt *time.Time
+}
+
+// ReadLatency is the average time from publication to reading.
+type ReadLatency struct {
+	AverageSeconds float64
+	// Samples is how many read entries had both timestamps.
+	Samples int
+}
+
 type EntryRepository interface {
 	GetByID(ctx context.Context, id int64) (model.Entry, error)
+	// GetByIDWithFeed is GetByID with Entry.Feed loaded.
+	GetByIDWithFeed(ctx context.Context, id int64) (model.Entry, error)
+	// GetByIDs returns the entries with the given ids, with notes, in the order
+	// requested. Unknown ids are skipped.
+	GetByIDs(ctx context.Context, ids []int64) ([]model.Entry, error)
+	// ListByHashes returns the feed's stored entries matching hashes.
+	ListByHashes(ctx context.Context, feedID int64, hashes []string) ([]model.Entry, error)
 	List(ctx context.Context, filter EntryListFilter) ([]model.Entry, error)
+	// Count returns how many entries List would return for filter without Limit and Offset.
+	Count(ctx context.Context, filter EntryListFilter) (int, error)
+	// Search returns the entries matching filter.Query in the full-text index,
+	// most relevant first.
+	Search(ctx context.Context, filter EntrySearchFilter) ([]model.Entry, error)
+	// GetAdjacent returns the entry after (next) or before ref in List order under filter.
+	GetAdjacent(ctx context.Context, ref model.Entry, filter EntryListFilter, next bool) (model.Entry, error)
 	UpdateReadStatus(ctx context.Context, id int64, read bool) error
 	UpdateManyReadStatus(ctx context.Context, ids []int64, read bool) error
 	UpdateStarredStatus(ctx context.Context, id int64, starred bool) error
-	UpdateReadableContent(ctx context.Context, id int64, content string) error
+	// UpdateReadableContent caches readable content and raises word_count to wordCount
+	// when the full article is longer than the feed content.
+	UpdateReadableContent(ctx context.Context, id int64, content string, wordCount int) error
+	// UpdateContent replaces the stored feed content without touching updated_at.
+	UpdateContent(ctx context.Context, id int64, content string) error
 	MarkAllAsRead(ctx context.Context, feedID *int64, folderID *int64, contentType *string) error
+	// GetAllUnreadCounts skips deleted feeds, feeds that are currently paused and
+	// entries by muted authors.
 	GetAllUnreadCounts(ctx context.Context) ([]UnreadCount, error)
+	// GetRecentCounts counts the entries of each feed saved at or after since,
+	// read or not, with the feeds GetAllUnreadCounts skips left out. Feeds
+	// with none are omitted.
+	GetRecentCounts(ctx context.Context, since time.Time) ([]RecentCount, error)
+	// ListForDigest returns up to perFeed of each feed's entries published in [start, end),
+	// newest first. Entries without a date count by created_at.
+	ListForDigest(ctx context.Context, start, end time.Time, perFeed int) ([]DigestEntry, error)
+	// ListNonUTCPublishedAt returns published_at values not stored as UTC RFC3339,
+	// such as rows written by older versions without zone info.
+	ListNonUTCPublishedAt(ctx context.Context) ([]RawPublishedAt, error)
+	UpdatePublishedAt(ctx context.Context, id int64, publishedAt time.Time) error
 	GetStarredCount(ctx context.Context) (int, error)
-	CreateOrUpdate(ctx context.Context, entry model.Entry) error
+	// CountReadsByDay counts entries of live feeds read since since, grouped by
+	// calendar day after shifting read_at by utcOffset. Days without reads are omitted.
+	CountReadsByDay(ctx context.Context, since time.Time, utcOffset time.Duration) ([]DailyReadCount, error)
+	// TopReadFeeds returns up to limit live feeds by entries read since since, most read first.
+	TopReadFeeds(ctx context.Context, since time.Time, limit int) ([]FeedReadCount, error)
+	// GetReadLatency averages publication-to-read time over entries read since since
+	// that have a published date. Reads from before read_at was recorded are skipped.
+	GetReadLatency(ctx context.Context, since time.Time) (ReadLatency, error)
+	// RecordClick stores a click-through to the original of an entry of feedID,
+	// with the entry's canonical URL, which it fills in when still missing.
+	RecordClick(ctx context.Context, entryID, feedID int64, clickedAt time.Time) error
+	// BackfillCanonicalURLs fills in the canonical_url of entries saved before
+	// it was kept, then of their clicks, and returns how many entries it set.
+	BackfillCanonicalURLs(ctx context.Context) (int, error)
+	// BackfillSearchIndex indexes the readable content and AI summaries of
+	// every entry that has some in entries_fts_ext, and returns how many
+	// entries it indexed.
+	BackfillSearchIndex(ctx context.Context) (int, error)
+	// CountClicksByFeed returns every live feed with its clicks since since,
+	// most clicked first. Feeds without clicks are included with a zero count.
+	CountClicksByFeed(ctx context.Context, since time.Time) ([]FeedClickCount, error)
+	// CreateOrUpdate upserts an entry. When revisionLimit > 0 and the stored content differs,
+	// the previous content is snapshotted and only the newest revisionLimit snapshots are kept.
+	// entry.Read only sets the read state of new entries. A stored entry whose title, url,
+	// content, thumbnail, author and published date are unchanged is left untouched.
+	CreateOrUpdate(ctx context.Context, entry model.Entry, revisionLimit int) error
+	// SaveBatch upserts a feed's entries in one transaction, like CreateOrUpdate per entry,
+	// and reports how many were new and how many changed existing rows.
+	SaveBatch(ctx context.Context, feedID int64, entries []model.Entry, revisionLimit int) (newCount int, updatedCount int, err error)
+	ListRevisions(ctx context.Context, entryID int64) ([]model.EntryRevision, error)
+	// GetRawItem returns the feed item stored with entry.RawItem by the saves,
+	// or nil when the entry has none.
+	GetRawItem(ctx context.Context, entryID int64) (*string, error)
+	// DeleteRawItemsBefore deletes raw items first stored before before.
+	DeleteRawItemsBefore(ctx context.Context, before time.Time) (int64, error)
+	// ListTombstones returns the ids of entries deleted at or after since,
+	// limited to the feed when feedID is set.
+	ListTombstones(ctx context.Context, since time.Time, feedID *int64) ([]int64, error)
+	// DeleteTombstonesBefore forgets entries deleted before before.
+	DeleteTombstonesBefore(ctx context.Context, before time.Time) (int64, error)
+	// SetNote creates or replaces the note of an entry.
+	SetNote(ctx context.Context, entryID int64, note string) error
+	DeleteNote(ctx context.Context, entryID int64) error
+	// Rehash recomputes a feed's entry hashes from url, title and content, merging
+	// entries that collide. Returns how many entries were merged away.
+	Rehash(ctx context.Context, feedID int64, hash func(link, title, content string) string) (int, error)
 	ExistsByHash(ctx context.Context, feedID int64, hash string) (bool, error)
-	ExistsByLegacyURL(ctx context.Context, feedID int64, rawURL string, hash string) (bool, error)
+	// Delete removes an entry with its note, revisions and raw item. Returns
+	// sql.ErrNoRows when there is none.
+	Delete(ctx context.Context, id int64) error
+	// ExistingLegacyURLs reports which of rawURLs match a stored entry of the feed
+	// by URL, ignoring fragments and tracking parameters, the way rows hashed by
+	// URL before GUID hashing are found. Pass the URLs of entries whose hash
+	// matched nothing.
+	ExistingLegacyURLs(ctx context.Context, feedID int64, rawURLs []string) (map[string]bool, error)
+	// ClearAllReadableContent clears readable content, except that of archived starred entries.
 	ClearAllReadableContent(ctx context.Context) (int64, error)
 	DeleteUnstarred(ctx context.Context) (int64, error)
+	// EvictOverCap deletes the feed's oldest read entries until at most maxEntries
+	// remain, oldest by published date, else by created_at. Starred and annotated
+	// entries are kept. It returns how many were deleted and how many unread remain.
+	EvictOverCap(ctx context.Context, feedID int64, maxEntries int) (evicted int, unread int, err error)
+	// ListForBackup returns up to limit entries of live feeds with id > afterID, by id.
+	// Unless includeRead is set only unread, starred or annotated entries are returned.
+	ListForBackup(ctx context.Context, includeRead bool, afterID int64, limit int) ([]model.Entry, error)
+	// ImportBatch inserts the entries not yet stored for their feed and applies the read
+	// and starred state of the others, in one transaction. Existing content and notes are kept.
+	ImportBatch(ctx context.Context, entries []model.Entry) (created int, updated int, skipped int, err error)
 }
 
 type entryRepository struct {
-	db dbtx
+	db      dbtx
+	changes *ChangeTracker
+	writes  *WriteTracker
 }
 
-func NewEntryRepository(db dbtx) EntryRepository {
-	return &entryRepository{db: db}
+func NewEntryRepository(db dbtx, changes *ChangeTracker, writes *WriteTracker) EntryRepository {
+	return &entryRepository{db: db, changes: changes, writes: writes}
 }
 
 func (r *entryRepository) GetByID(ctx context.Context, id int64) (model.Entry, error) {
 	row := r.db.QueryRowContext(
 		ctx,
-		`SELECT id, feed_id, hash, title, url, content, readable_content, thumbnail_url, author, published_at, read, starred, created_at, updated_at
+		`SELECT id, feed_id, hash, title, url, content, readable_content, thumbnail_url, author, published_at, read, starred, word_count, created_at, updated_at, language,
+		        (SELECT note FROM entry_notes WHERE entry_id = entries.id)
 		 FROM entries WHERE id = ?`,
 		id,
 	)
-	return scanEntry(row)
+	return scanEntryWithNote(row)
 }
 
-func (r *entryRepository) List(ctx context.Context, filter EntryListFilter) ([]model.Entry, error) {
-	var args []interface{}
-	query := `
+func (r *entryRepository) GetByIDWithFeed(ctx context.Context, id int64) (model.Entry, error) {
+	row := r.db.QueryRowContext(ctx, entryListSelect(true)+" WHERE e.id = ?", id)
+	return scanListEntry(row, true)
+}
+
+func (r *entryRepository) GetByIDs(ctx context.Context, ids []int64) ([]model.Entry, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(ids)), ",")
+
+	rows, err := r.db.QueryContext(
+		ctx,
+		`SELECT id, feed_id, hash, title, url, content, readable_content, thumbnail_url, author, published_at, read, starred, word_count, created_at, updated_at, language,
+		        (SELECT note FROM entry_notes WHERE entry_id = entries.id)
+		 FROM entries WHERE id IN (`+placeholders+`)`,
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	byID := make(map[int64]model.Entry, len(ids))
+	for rows.Next() {
+		entry, err := scanEntryWithNote(rows)
+		if err != nil {
+			return nil, err
+		}
+		byID[entry.ID] = entry
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	entries := make([]model.Entry, 0, len(byID))
+	for _, id := range ids {
+		if entry, ok := byID[id]; ok {
+			entries = append(entries, entry)
+			// Repeated ids are returned once
+			delete(byID, id)
+		}
+	}
+	return entries, nil
+}
+
+func (r *entryRepository) ListByHashes(ctx context.Context, feedID int64, hashes []string) ([]model.Entry, error) {
+	var entries []model.Entry
+	for start := 0; start < len(hashes); start += existingHashChunk {
+		chunk := hashes[start:min(start+existingHashChunk, len(hashes))]
+
+		args := make([]interface{}, 0, len(chunk)+1)
+		args = append(args, feedID)
+		for _, hash := range chunk {
+			args = append(args, hash)
+		}
+		placeholders := strings.TrimSuffix(strings.Repeat("?,", len(chunk)), ",")
+
+		rows, err := r.db.QueryContext(
+			ctx,
+			`SELECT id, feed_id, hash, title, url, content, readable_content, thumbnail_url, author, published_at, read, starred, word_count, created_at, updated_at, language
+			 FROM entries WHERE feed_id = ? AND hash IN (`+placeholders+`)`,
+			args...,
+		)
+		if err != nil {
+			return nil, err
+		}
+		for rows.Next() {
+			entry, err := scanEntry(rows)
+			if err != nil {
+				rows.Close()
+				return nil, err
+			}
+			entries = append(entries, entry)
+		}
+		if err := rows.Err(); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		rows.Close()
+	}
+	return entries, nil
+}
+
+// entryListSelect is shared by List and GetAdjacent so both see the same rows.
+// With includeFeed the feed title, icon and type follow the note; feeds is
+// already joined for the filters, so this costs no extra lookup.
+func entryListSelect(includeFeed bool) string {
+	feedColumns := ""
+	if includeFeed {
+		feedColumns = ", COALESCE(f.custom_title, f.title), f.icon_path, f.type"
+	}
+	return `
 		SELECT e.id, e.feed_id, e.hash, e.title, e.url, e.content, e.readable_content, e.thumbnail_url, e.author,
-		       e.published_at, e.read, e.starred, e.created_at, e.updated_at
+		       e.published_at, e.read, e.starred, e.word_count, e.created_at, e.updated_at, e.language, n.note` + feedColumns + `
 		FROM entries e
+		INNER JOIN feeds f ON e.feed_id = f.id
+		LEFT JOIN entry_notes n ON n.entry_id = e.id
 	`
+}
 
-	var conditions []string
-	needFeedsJoin := filter.FolderID != nil || filter.ContentType != nil
-
-	if needFeedsJoin {
-		query += " INNER JOIN feeds f ON e.feed_id = f.id"
+// scanListEntry scans a row selected by entryListSelect.
+func scanListEntry(s entryScanner, includeFeed bool) (model.Entry, error) {
+	if !includeFeed {
+		return scanEntryWithNote(s)
+	}
+	var feed model.EntryFeed
+	entry, err := scanEntryWithNote(extraColumns{s, []interface{}{&feed.Title, &feed.IconPath, &feed.Type}})
+	if err != nil {
+		return model.Entry{}, err
 	}
+	entry.Feed = &feed
+	return entry, nil
+}
+
+// entryListConditions builds the WHERE clauses for filter (ignoring Limit and Offset).
+func entryListConditions(filter EntryListFilter) ([]string, []interface{}) {
+	var args []interface{}
+	// Entries of soft-deleted feeds stay hidden until the feed is restored or purged
+	conditions := []string{"f.deleted_at IS NULL"}
 
 	if filter.FolderID != nil {
 		conditions = append(conditions, "f.folder_id = ?")
@@ -105,11 +415,84 @@ func (r *entryRepository) List(ctx context.Context, filter EntryListFilter) ([]m
 		conditions = append(conditions, "e.thumbnail_url IS NOT NULL AND e.thumbnail_url != ''")
 	}
 
-	if len(conditions) > 0 {
-		query += " WHERE " + strings.Join(conditions, " AND ")
+	if filter.NotesOnly {
+		conditions = append(conditions, "n.entry_id IS NOT NULL")
+	}
+
+	if filter.HasReadableContent {
+		conditions = append(conditions, "e.readable_content IS NOT NULL AND e.readable_content != ''")
+	}
+
+	if filter.SummaryLanguage != "" {
+		conditions = append(conditions, "EXISTS (SELECT 1 FROM ai_summaries s WHERE s.entry_id = e.id AND s.language = ?)")
+		args = append(args, filter.SummaryLanguage)
+	}
+
+	if filter.ContentLanguage != "" {
+		conditions = append(conditions, "e.language = ?")
+		args = append(args, filter.ContentLanguage)
+	}
+
+	if filter.ApplyMutes {
+		conditions = append(conditions, "NOT "+mutedAuthorCondition)
+	}
+
+	if filter.MinReadingMinutes != nil {
+		conditions = append(conditions, "COALESCE(e.word_count, 0) > ?")
+		args = append(args, readtime.MaxWords(*filter.MinReadingMinutes-1))
+	}
+
+	if filter.MaxReadingMinutes != nil {
+		conditions = append(conditions, "COALESCE(e.word_count, 0) <= ?")
+		args = append(args, readtime.MaxWords(*filter.MaxReadingMinutes))
+	}
+
+	if filter.UpdatedSince != nil {
+		// Stored times vary in fractional precision, so the exact test goes through
+		// julianday(); the whole-second prefix bound before it lets the index narrow the scan
+		since := filter.UpdatedSince.UTC()
+		conditions = append(conditions, "e.updated_at >= ? AND julianday(e.updated_at) >= julianday(?)")
+		args = append(args, since.Format("2006-01-02T15:04:05"), formatTime(since))
+	}
+
+	return conditions, args
+}
+
+// GetAdjacent returns the entry right after (next) or before ref in List order under
+// filter. ref itself need not match the filter. Returns sql.ErrNoRows at either end.
+func (r *entryRepository) GetAdjacent(ctx context.Context, ref model.Entry, filter EntryListFilter, next bool) (model.Entry, error) {
+	conditions, args := entryListConditions(filter)
+
+	// List orders by published_at DESC, id DESC, where SQLite puts NULL published_at last.
+	// The reference timestamp is read back as stored text so comparisons match that order exactly.
+	const refPublished = "(SELECT published_at FROM entries WHERE id = ?)"
+	var order string
+	switch {
+	case next && ref.PublishedAt != nil:
+		conditions = append(conditions, "(e.published_at < "+refPublished+" OR (e.published_at = "+refPublished+" AND e.id < ?) OR e.published_at IS NULL)")
+		args = append(args, ref.ID, ref.ID, ref.ID)
+		order = "e.published_at DESC, e.id DESC"
+	case next:
+		conditions = append(conditions, "e.published_at IS NULL AND e.id < ?")
+		args = append(args, ref.ID)
+		order = "e.published_at DESC, e.id DESC"
+	case ref.PublishedAt != nil:
+		conditions = append(conditions, "(e.published_at > "+refPublished+" OR (e.published_at = "+refPublished+" AND e.id > ?))")
+		args = append(args, ref.ID, ref.ID, ref.ID)
+		order = "e.published_at ASC, e.id ASC"
+	default:
+		// Ascending puts NULLs first, so nearer undated rows win over dated ones
+		conditions = append(conditions, "(e.published_at IS NOT NULL OR e.id > ?)")
+		args = append(args, ref.ID)
+		order = "e.published_at ASC, e.id ASC"
 	}
 
-	query += " ORDER BY e.published_at DESC, e.id DESC"
+	query := entryListSelect(filter.IncludeFeed) + " WHERE " + strings.Join(conditions, " AND ") + " ORDER BY " + order + " LIMIT 1"
+	return scanListEntry(r.db.QueryRowContext(ctx, query, args...), filter.IncludeFeed)
+}
+
+func (r *entryRepository) List(ctx context.Context, filter EntryListFilter) ([]model.Entry, error) {
+	query, args := entryListQuery(filter)
 
 	if filter.Limit > 0 {
 		query += " LIMIT ?"
@@ -128,7 +511,7 @@ func (r *entryRepository) List(ctx context.Context, filter EntryListFilter) ([]m
 
 	var entries []model.Entry
 	for rows.Next() {
-		entry, err := scanEntry(rows)
+		entry, err := scanListEntry(rows, filter.IncludeFeed)
 		if err != nil {
 			return nil, err
 		}
@@ -142,6 +525,123 @@ func (r *entryRepository) List(ctx context.Context, filter EntryListFilter) ([]m
 	return entries, nil
 }
 
+func (r *entryRepository) Count(ctx context.Context, filter EntryListFilter) (int, error) {
+	conditions, args := entryListConditions(filter)
+	query := `
+		SELECT COUNT(*)
+		FROM entries e
+		INNER JOIN feeds f ON e.feed_id = f.id
+		LEFT JOIN entry_notes n ON n.entry_id = e.id
+		WHERE ` + strings.Join(conditions, " AND ")
+
+	var count int
+	if err := r.db.QueryRowContext(ctx, query, args...).Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (r *entryRepository) Search(ctx context.Context, filter EntrySearchFilter) ([]model.Entry, error) {
+	var prefix string
+	var args []interface{}
+	conditions := []string{"f.deleted_at IS NULL"}
+
+	if filter.FolderID != nil {
+		prefix = activeFolderTree + ", "
+		args = append(args, *filter.FolderID)
+	} else {
+		prefix = "WITH "
+	}
+	hits, hitArgs := entrySearchHits(filter.Query, filter.Column)
+	prefix += hits
+	args = append(args, hitArgs...)
+
+	if filter.FeedID != nil {
+		conditions = append(conditions, "e.feed_id = ?")
+		args = append(args, *filter.FeedID)
+	}
+	if filter.FolderID != nil {
+		conditions = append(conditions, "e.feed_id IN (SELECT id FROM feeds WHERE folder_id IN (SELECT id FROM tree))")
+	}
+
+	// bm25 scores better matches lower, below zero, so scaling one up ranks it higher
+	order := "hits.rank"
+	if filter.BoostUnread {
+		order += " * CASE WHEN e.read = 0 THEN ? ELSE 1 END"
+		args = append(args, unreadSearchBoost)
+	}
+
+	query := prefix + entryListSelect(filter.IncludeFeed) + `
+		INNER JOIN hits ON hits.id = e.id
+		WHERE ` + strings.Join(conditions, " AND ") + `
+		ORDER BY ` + order + `, e.id DESC`
+	if filter.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, filter.Limit)
+	}
+	if filter.Offset > 0 {
+		query += " OFFSET ?"
+		args = append(args, filter.Offset)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("search entries: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []model.Entry
+	for rows.Next() {
+		entry, err := scanListEntry(rows, filter.IncludeFeed)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
+
+// entrySearchHits returns a common table expression "hits(id, rank)" of the
+// entries matching text, and its args. Feed columns are matched in entries_fts
+// and readable content and AI summaries in entries_fts_ext; an entry found in
+// both ranks by the sum of its bm25 scores. Title searches skip entries_fts_ext.
+func entrySearchHits(text, column string) (string, []interface{}) {
+	matches := `SELECT rowid AS id, bm25(entries_fts) AS rank FROM entries_fts WHERE entries_fts MATCH ?`
+	args := []interface{}{ftsMatchQuery(text, column)}
+	if column == EntrySearchColumnTitle {
+		// bm25 cannot be summed once SQLite flattens a lone subquery into the GROUP BY
+		return `hits(id, rank) AS (` + matches + `)`, args
+	}
+	args = append(args, ftsMatchQuery(text, ""))
+	return `hits(id, rank) AS (
+		SELECT id, SUM(rank) FROM (` + matches + `
+			UNION ALL
+			SELECT rowid, bm25(entries_fts_ext) FROM entries_fts_ext WHERE entries_fts_ext MATCH ?
+		) GROUP BY id
+	)`, args
+}
+
+// ftsMatchQuery turns search text into an FTS5 query matching every word in
+// it, in column when set. Each word is quoted, so FTS5 operators in the text
+// are searched for literally.
+func ftsMatchQuery(text, column string) string {
+	words := strings.Fields(text)
+	for i, word := range words {
+		words[i] = `"` + strings.ReplaceAll(word, `"`, `""`) + `"`
+	}
+	query := strings.Join(words, " ")
+	if column != "" {
+		query = "{" + column + "} : (" + query + ")"
+	}
+	return query
+}
+
+// entryListQuery builds the List query and its args for filter, without LIMIT and OFFSET.
+func entryListQuery(filter EntryListFilter) (string, []interface{}) {
+	conditions, args := entryListConditions(filter)
+	return entryListSelect(filter.IncludeFeed) + " WHERE " + strings.Join(conditions, " AND ") + " ORDER BY e.published_at DESC, e.id DESC", args
+}
+
 func boolToInt(value bool) int {
 	if value {
 		return 1
@@ -149,28 +649,40 @@ func boolToInt(value bool) int {
 	return 0
 }
 
+// readAtExpr sets read_at from the new read flag and time: an entry keeps the
+// time it was first read, and loses it when marked unread.
+const readAtExpr = `CASE WHEN ? = 0 THEN NULL WHEN read = 1 THEN read_at ELSE ? END`
+
 func (r *entryRepository) UpdateReadStatus(ctx context.Context, id int64, read bool) error {
+	defer r.changes.Notify()
+
 	readInt := boolToInt(read)
+	now := formatTime(time.Now())
 
 	_, err := r.db.ExecContext(
 		ctx,
-		`UPDATE entries SET read = ?, updated_at = ? WHERE id = ?`,
+		`UPDATE entries SET read = ?, read_at = `+readAtExpr+`, updated_at = ? WHERE id = ?`,
 		readInt,
-		formatTime(time.Now()),
+		readInt,
+		now,
+		now,
 		id,
 	)
 	return err
 }
 
 func (r *entryRepository) UpdateManyReadStatus(ctx context.Context, ids []int64, read bool) error {
+	defer r.changes.Notify()
+
 	if len(ids) == 0 {
 		return nil
 	}
 
 	readInt := boolToInt(read)
 
-	args := make([]interface{}, 0, len(ids)+2)
-	args = append(args, readInt, formatTime(time.Now()))
+	now := formatTime(time.Now())
+	args := make([]interface{}, 0, len(ids)+4)
+	args = append(args, readInt, readInt, now, now)
 	placeholders := make([]string, len(ids))
 	for i, id := range ids {
 		placeholders[i] = "?"
@@ -179,21 +691,24 @@ func (r *entryRepository) UpdateManyReadStatus(ctx context.Context, ids []int64,
 
 	_, err := r.db.ExecContext(
 		ctx,
-		`UPDATE entries SET read = ?, updated_at = ? WHERE id IN (`+strings.Join(placeholders, ",")+")",
+		`UPDATE entries SET read = ?, read_at = `+readAtExpr+`, updated_at = ? WHERE id IN (`+strings.Join(placeholders, ",")+")",
 		args...,
 	)
 	return err
 }
 
 func (r *entryRepository) MarkAllAsRead(ctx context.Context, feedID *int64, folderID *int64, contentType *string) error {
+	defer r.changes.Notify()
+
 	now := formatTime(time.Now())
 
 	if folderID != nil {
 		_, err := r.db.ExecContext(
 			ctx,
-			`UPDATE entries SET read = 1, updated_at = ?
+			`UPDATE entries SET read = 1, read_at = ?, updated_at = ?
 			 WHERE feed_id IN (SELECT id FROM feeds WHERE folder_id = ?) AND read = 0`,
 			now,
+			now,
 			*folderID,
 		)
 		return err
@@ -202,7 +717,8 @@ func (r *entryRepository) MarkAllAsRead(ctx context.Context, feedID *int64, fold
 	if feedID != nil {
 		_, err := r.db.ExecContext(
 			ctx,
-			`UPDATE entries SET read = 1, updated_at = ? WHERE feed_id = ? AND read = 0`,
+			`UPDATE entries SET read = 1, read_at = ?, updated_at = ? WHERE feed_id = ? AND read = 0`,
+			now,
 			now,
 			*feedID,
 		)
@@ -213,9 +729,10 @@ func (r *entryRepository) MarkAllAsRead(ctx context.Context, feedID *int64, fold
 	if contentType != nil {
 		_, err := r.db.ExecContext(
 			ctx,
-			`UPDATE entries SET read = 1, updated_at = ?
+			`UPDATE entries SET read = 1, read_at = ?, updated_at = ?
 			 WHERE feed_id IN (SELECT id FROM feeds WHERE type = ?) AND read = 0`,
 			now,
+			now,
 			*contentType,
 		)
 		return err
@@ -224,7 +741,8 @@ func (r *entryRepository) MarkAllAsRead(ctx context.Context, feedID *int64, fold
 	// Mark all as read without filter
 	_, err := r.db.ExecContext(
 		ctx,
-		`UPDATE entries SET read = 1, updated_at = ? WHERE read = 0`,
+		`UPDATE entries SET read = 1, read_at = ?, updated_at = ? WHERE read = 0`,
+		now,
 		now,
 	)
 	return err
@@ -233,7 +751,13 @@ func (r *entryRepository) MarkAllAsRead(ctx context.Context, feedID *int64, fold
 func (r *entryRepository) GetAllUnreadCounts(ctx context.Context) ([]UnreadCount, error) {
 	rows, err := r.db.QueryContext(
 		ctx,
-		`SELECT feed_id, COUNT(*) as count FROM entries WHERE read = 0 GROUP BY feed_id`,
+		`SELECT feed_id, COUNT(*) as count FROM entries e
+		 WHERE read = 0 AND feed_id IN (
+		   SELECT id FROM feeds WHERE deleted_at IS NULL
+		   AND (paused_until IS NULL OR julianday(paused_until) <= julianday('now'))
+		 )
+		 AND NOT `+mutedAuthorCondition+`
+		 GROUP BY feed_id`,
 	)
 	if err != nil {
 		return nil, err
@@ -256,69 +780,493 @@ func (r *entryRepository) GetAllUnreadCounts(ctx context.Context) ([]UnreadCount
 	return counts, nil
 }
 
-// entryScanner is an interface for scanning entry rows.
-type entryScanner interface {
-	Scan(dest ...interface{}) error
-}
+func (r *entryRepository) GetRecentCounts(ctx context.Context, since time.Time) ([]RecentCount, error) {
+	// created_at rather than published_at, so backfilled old articles are not new
+	rows, err := r.db.QueryContext(
+		ctx,
+		`SELECT feed_id, COUNT(*) FROM entries e
+		 WHERE created_at >= ? AND feed_id IN (
+		   SELECT id FROM feeds WHERE deleted_at IS NULL
+		   AND (paused_until IS NULL OR julianday(paused_until) <= julianday('now'))
+		 )
+		 AND NOT `+mutedAuthorCondition+`
+		 GROUP BY feed_id`,
+		formatTime(since),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
 
-func scanEntry(s entryScanner) (model.Entry, error) {
-	var e model.Entry
-	var publishedAt sql.NullString
-	var createdAt, updatedAt string
-	var readInt, starredInt int
+	var counts []RecentCount
+	for rows.Next() {
+		var rc RecentCount
+		if err := rows.Scan(&rc.FeedID, &rc.Count); err != nil {
+			return nil, err
+		}
+		counts = append(counts, rc)
+	}
+	return counts, rows.Err()
+}
 
-	err := s.Scan(
-		&e.ID, &e.FeedID, &e.Hash, &e.Title, &e.URL, &e.Content, &e.ReadableContent, &e.ThumbnailURL, &e.Author,
-		&publishedAt, &readInt, &starredInt, &createdAt, &updatedAt,
-	)
+func (r *entryRepository) ListForDigest(ctx context.Context, start, end time.Time, perFeed int) ([]DigestEntry, error) {
+	// Rank within each feed first so the cap doesn't depend on other feeds' volume
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT e.id, e.feed_id, e.hash, e.title, e.url, e.content, e.readable_content, e.thumbnail_url, e.author,
+		       e.published_at, e.read, e.starred, e.word_count, e.created_at, e.updated_at, e.language,
+		       COALESCE(f.custom_title, f.title), f.folder_id, fo.name, ranked.feed_total
+		FROM (
+			SELECT e.id,
+			       ROW_NUMBER() OVER (PARTITION BY e.feed_id ORDER BY COALESCE(e.published_at, e.created_at) DESC, e.id DESC) AS rn,
+			       COUNT(*) OVER (PARTITION BY e.feed_id) AS feed_total
+			FROM entries e
+			INNER JOIN feeds f ON e.feed_id = f.id
+			WHERE f.deleted_at IS NULL
+			  AND julianday(COALESCE(e.published_at, e.created_at)) >= julianday(?)
+			  AND julianday(COALESCE(e.published_at, e.created_at)) < julianday(?)
+		) ranked
+		INNER JOIN entries e ON e.id = ranked.id
+		INNER JOIN feeds f ON e.feed_id = f.id
+		LEFT JOIN folders fo ON f.folder_id = fo.id
+		WHERE ranked.rn <= ?
+		ORDER BY COALESCE(e.published_at, e.created_at) DESC, e.id DESC
+	`, formatTime(start), formatTime(end), perFeed)
 	if err != nil {
-		return model.Entry{}, err
+		return nil, err
 	}
+	defer rows.Close()
 
-	e.Read = readInt == 1
-	e.Starred = starredInt == 1
-	if publishedAt.Valid {
-		e.PublishedAt = parseTimePtr(publishedAt.String)
+	var entries []DigestEntry
+	for rows.Next() {
+		var d DigestEntry
+		var folderID sql.NullInt64
+		var folderName sql.NullString
+		entry, err := scanEntry(extraColumns{rows, []interface{}{&d.FeedTitle, &folderID, &folderName, &d.FeedTotal}})
+		if err != nil {
+			return nil, err
+		}
+		d.Entry = entry
+		if folderID.Valid {
+			d.FolderID = &folderID.Int64
+		}
+		if folderName.Valid {
+			d.FolderName = &folderName.String
+		}
+		entries = append(entries, d)
 	}
-	e.CreatedAt, _ = parseTime(createdAt)
-	e.UpdatedAt, _ = parseTime(updatedAt)
+	return entries, rows.Err()
+}
 
-	return e, nil
+// readAtBound formats since for comparison against read_at. Without the zone
+// suffix it sorts before every stored time in the same second, so the string
+// comparison can use idx_entries_read_at.
+func readAtBound(since time.Time) string {
+	return since.UTC().Format("2006-01-02T15:04:05")
 }
 
-func parseTimePtr(s string) *time.Time {
-	if s == "" {
-		return nil
+func (r *entryRepository) CountReadsByDay(ctx context.Context, since time.Time, utcOffset time.Duration) ([]DailyReadCount, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT date(e.read_at, ?) AS day, COUNT(*)
+		FROM entries e
+		INNER JOIN feeds f ON e.feed_id = f.id
+		WHERE e.read_at >= ? AND f.deleted_at IS NULL
+		GROUP BY day
+		ORDER BY day
+	`, fmt.Sprintf("%+d minutes", int(utcOffset/time.Minute)), readAtBound(since))
+	if err != nil {
+		return nil, err
 	}
-	t, _ := parseTime(s)
-	return &t
+	defer rows.Close()
+
+	var days []DailyReadCount
+	for rows.Next() {
+		var d DailyReadCount
+		if err := rows.Scan(&d.Date, &d.Count); err != nil {
+			return nil, err
+		}
+		days = append(days, d)
+	}
+	return days, rows.Err()
 }
 
-func (r *entryRepository) CreateOrUpdate(ctx context.Context, entry model.Entry) error {
-	id := snowflake.NextID()
-	now := formatTime(time.Now())
+func (r *entryRepository) TopReadFeeds(ctx context.Context, since time.Time, limit int) ([]FeedReadCount, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT f.id, COALESCE(f.custom_title, f.title), COUNT(*) AS reads
+		FROM entries e
+		INNER JOIN feeds f ON e.feed_id = f.id
+		WHERE e.read_at >= ? AND f.deleted_at IS NULL
+		GROUP BY f.id
+		ORDER BY reads DESC, COALESCE(f.custom_title, f.title)
+		LIMIT ?
+	`, readAtBound(since), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
 
-	var publishedAt interface{}
-	if entry.PublishedAt != nil {
-		publishedAt = formatTime(*entry.PublishedAt)
+	var feeds []FeedReadCount
+	for rows.Next() {
+		var f FeedReadCount
+		if err := rows.Scan(&f.FeedID, &f.Title, &f.Count); err != nil {
+			return nil, err
+		}
+		feeds = append(feeds, f)
 	}
+	return feeds, rows.Err()
+}
 
-	// Compatibility path:
-	// legacy databases might still carry URL-derived hashes after migration.
-	// If we receive the same URL with a new GUID-derived hash, upgrade that row in place
-	// so the first refresh after migration doesn't create duplicates.
-	if entry.URL != nil && *entry.URL != "" && entry.Hash != "" {
-		normalizedURL := urlutil.StripFragment(*entry.URL)
-		result, err := r.db.ExecContext(
-			ctx,
-			`UPDATE entries SET
-			   hash = ?,
-			   title = ?,
-			   url = ?,
-			   content = ?,
-			   thumbnail_url = ?,
+func (r *entryRepository) GetReadLatency(ctx context.Context, since time.Time) (ReadLatency, error) {
+	// Entries published after they were fetched and read (clock skew, future dates) count as zero
+	var latency ReadLatency
+	var average sql.NullFloat64
+	err := r.db.QueryRowContext(ctx, `
+		SELECT AVG(MAX(0, (julianday(e.read_at) - julianday(e.published_at)) * 86400)), COUNT(*)
+		FROM entries e
+		INNER JOIN feeds f ON e.feed_id = f.id
+		WHERE e.read_at >= ? AND e.published_at IS NOT NULL AND f.deleted_at IS NULL
+	`, readAtBound(since)).Scan(&average, &latency.Samples)
+	if err != nil {
+		return ReadLatency{}, err
+	}
+	latency.AverageSeconds = average.Float64
+	return latency, nil
+}
+
+func (r *entryRepository) RecordClick(ctx context.Context, entryID, feedID int64, clickedAt time.Time) error {
+	return withTx(ctx, r.db, r.writes, func(tx dbtx) error {
+		var link, canonical sql.NullString
+		err := tx.QueryRowContext(ctx, `SELECT url, canonical_url FROM entries WHERE id = ?`, entryID).Scan(&link, &canonical)
+		if err != nil && err != sql.ErrNoRows {
+			return err
+		}
+		if err == nil && !canonical.Valid {
+			canonical = sql.NullString{String: urlutil.CanonicalEntryURL(link.String), Valid: true}
+			if _, err := tx.ExecContext(ctx, `UPDATE entries SET canonical_url = ? WHERE id = ?`, canonical.String, entryID); err != nil {
+				return err
+			}
+		}
+
+		var clickCanonical interface{}
+		if canonical.String != "" {
+			clickCanonical = canonical.String
+		}
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO entry_clicks (id, entry_id, feed_id, canonical_url, clicked_at) VALUES (?, ?, ?, ?, ?)
+		`, snowflake.NextID(), entryID, feedID, clickCanonical, formatTime(clickedAt))
+		return err
+	})
+}
+
+// entryCanonicalURL is the canonical_url stored for entry, empty without a usable link.
+func entryCanonicalURL(entry model.Entry) string {
+	if entry.URL == nil {
+		return ""
+	}
+	return urlutil.CanonicalEntryURL(*entry.URL)
+}
+
+// canonicalBackfillBatch is how many entries BackfillCanonicalURLs sets per transaction.
+const canonicalBackfillBatch = 500
+
+func (r *entryRepository) BackfillCanonicalURLs(ctx context.Context) (int, error) {
+	total := 0
+	for {
+		var n int
+		err := withTx(ctx, r.db, r.writes, func(tx dbtx) error {
+			rows, err := tx.QueryContext(ctx, `SELECT id, url FROM entries WHERE canonical_url IS NULL LIMIT ?`, canonicalBackfillBatch)
+			if err != nil {
+				return err
+			}
+			links := make(map[int64]string)
+			for rows.Next() {
+				var id int64
+				var link sql.NullString
+				if err := rows.Scan(&id, &link); err != nil {
+					rows.Close()
+					return err
+				}
+				links[id] = link.String
+			}
+			rows.Close()
+			if err := rows.Err(); err != nil {
+				return err
+			}
+
+			// Entries without a usable link get '', so they are not picked again
+			for id, link := range links {
+				if _, err := tx.ExecContext(ctx, `UPDATE entries SET canonical_url = ? WHERE id = ?`, urlutil.CanonicalEntryURL(link), id); err != nil {
+					return err
+				}
+			}
+			n = len(links)
+			return nil
+		})
+		if err != nil {
+			return total, err
+		}
+		total += n
+		if n < canonicalBackfillBatch {
+			break
+		}
+	}
+
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE entry_clicks SET canonical_url = (
+		  SELECT NULLIF(e.canonical_url, '') FROM entries e WHERE e.id = entry_clicks.entry_id
+		)
+		WHERE canonical_url IS NULL
+	`)
+	return total, err
+}
+
+// searchIndexBatch is how many entries BackfillSearchIndex indexes per transaction.
+const searchIndexBatch = 500
+
+func (r *entryRepository) BackfillSearchIndex(ctx context.Context) (int, error) {
+	total := 0
+	var after int64
+	for {
+		var n int
+		err := withTx(ctx, r.db, r.writes, func(tx dbtx) error {
+			rows, err := tx.QueryContext(ctx, `
+				SELECT e.id, COALESCE(e.readable_content, '') FROM entries e
+				WHERE e.id > ?
+				  AND (COALESCE(e.readable_content, '') != '' OR EXISTS (SELECT 1 FROM ai_summaries s WHERE s.entry_id = e.id))
+				ORDER BY e.id
+				LIMIT ?`, after, searchIndexBatch)
+			if err != nil {
+				return err
+			}
+			var ids []int64
+			contents := make(map[int64]string)
+			for rows.Next() {
+				var id int64
+				var content string
+				if err := rows.Scan(&id, &content); err != nil {
+					rows.Close()
+					return err
+				}
+				ids = append(ids, id)
+				contents[id] = content
+			}
+			rows.Close()
+			if err := rows.Err(); err != nil {
+				return err
+			}
+
+			for _, id := range ids {
+				if err := indexReadableText(ctx, tx, id, contents[id]); err != nil {
+					return err
+				}
+				if err := indexSummaryText(ctx, tx, id); err != nil {
+					return err
+				}
+			}
+			n = len(ids)
+			if n > 0 {
+				after = ids[n-1]
+			}
+			return nil
+		})
+		if err != nil {
+			return total, err
+		}
+		total += n
+		if n < searchIndexBatch {
+			return total, nil
+		}
+	}
+}
+
+func (r *entryRepository) CountClicksByFeed(ctx context.Context, since time.Time) ([]FeedClickCount, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT f.id, COALESCE(f.custom_title, f.title), COUNT(c.id) AS clicks, MAX(c.clicked_at)
+		FROM feeds f
+		LEFT JOIN entry_clicks c ON c.feed_id = f.id AND c.clicked_at >= ?
+		WHERE f.deleted_at IS NULL
+		GROUP BY f.id
+		ORDER BY clicks DESC, COALESCE(f.custom_title, f.title)
+	`, readAtBound(since))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var feeds []FeedClickCount
+	for rows.Next() {
+		var f FeedClickCount
+		var lastClickedAt sql.NullString
+		if err := rows.Scan(&f.FeedID, &f.Title, &f.Count, &lastClickedAt); err != nil {
+			return nil, err
+		}
+		if lastClickedAt.Valid {
+			if t, err := parseTime(lastClickedAt.String); err == nil {
+				f.LastClickedAt = &t
+			}
+		}
+		feeds = append(feeds, f)
+	}
+	return feeds, rows.Err()
+}
+
+// entryScanner is an interface for scanning entry rows.
+type entryScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func (r *entryRepository) ListNonUTCPublishedAt(ctx context.Context) ([]RawPublishedAt, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, feed_id, published_at FROM entries
+		WHERE published_at IS NOT NULL AND published_at NOT LIKE '%Z'
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var values []RawPublishedAt
+	for rows.Next() {
+		var v RawPublishedAt
+		if err := rows.Scan(&v.EntryID, &v.FeedID, &v.Value); err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+	}
+	return values, rows.Err()
+}
+
+func (r *entryRepository) UpdatePublishedAt(ctx context.Context, id int64, publishedAt time.Time) error {
+	defer r.changes.Notify()
+
+	_, err := r.db.ExecContext(ctx, `UPDATE entries SET published_at = ? WHERE id = ?`, formatTime(publishedAt), id)
+	return err
+}
+
+// extraColumns scans columns selected after the entry columns into extra.
+type extraColumns struct {
+	entryScanner
+	extra []interface{}
+}
+
+func (x extraColumns) Scan(dest ...interface{}) error {
+	return x.entryScanner.Scan(append(dest, x.extra...)...)
+}
+
+func scanEntry(s entryScanner) (model.Entry, error) {
+	var e model.Entry
+	var publishedAt sql.NullString
+	var createdAt, updatedAt string
+	var readInt, starredInt int
+	var wordCount sql.NullInt64
+	var language sql.NullString
+
+	err := s.Scan(
+		&e.ID, &e.FeedID, &e.Hash, &e.Title, &e.URL, &e.Content, &e.ReadableContent, &e.ThumbnailURL, &e.Author,
+		&publishedAt, &readInt, &starredInt, &wordCount, &createdAt, &updatedAt, &language,
+	)
+	if err != nil {
+		return model.Entry{}, err
+	}
+
+	e.Read = readInt == 1
+	e.Starred = starredInt == 1
+	e.WordCount = int(wordCount.Int64)
+	e.Language = language.String
+	if publishedAt.Valid {
+		e.PublishedAt = parseTimePtr(publishedAt.String)
+	}
+	e.CreatedAt, _ = parseTime(createdAt)
+	e.UpdatedAt, _ = parseTime(updatedAt)
+
+	return e, nil
+}
+
+// scanEntryWithNote scans the entry columns followed by the note.
+func scanEntryWithNote(s entryScanner) (model.Entry, error) {
+	var note *string
+	entry, err := scanEntry(extraColumns{s, []interface{}{&note}})
+	if err != nil {
+		return model.Entry{}, err
+	}
+	entry.Note = note
+	return entry, nil
+}
+
+func parseTimePtr(s string) *time.Time {
+	if s == "" {
+		return nil
+	}
+	t, _ := parseTime(s)
+	return &t
+}
+
+// contentFingerprint hashes the feed-provided fields an upsert writes, so a
+// resent entry can be recognized as unchanged without comparing each column.
+func contentFingerprint(entry model.Entry) string {
+	var publishedAt string
+	if entry.PublishedAt != nil {
+		publishedAt = formatTime(*entry.PublishedAt)
+	}
+	fields := []string{publishedAt}
+	for _, field := range []*string{entry.Title, entry.URL, entry.Content, entry.ThumbnailURL, entry.Author} {
+		if field == nil {
+			fields = append(fields, "")
+		} else {
+			fields = append(fields, *field)
+		}
+	}
+	return hashutil.SHA256Hex(strings.Join(fields, "\x00"))
+}
+
+func (r *entryRepository) CreateOrUpdate(ctx context.Context, entry model.Entry, revisionLimit int) error {
+	defer r.changes.Notify()
+
+	if _, err := r.upsert(ctx, entry, revisionLimit, true); err != nil {
+		return err
+	}
+	return r.saveRawItem(ctx, entry)
+}
+
+// upsert saves entry and reports whether a row was inserted or changed. A
+// stored entry whose content fingerprint matches is left untouched. legacy
+// tries upgrading a row hashed by URL first; callers that know none matches
+// skip it.
+func (r *entryRepository) upsert(ctx context.Context, entry model.Entry, revisionLimit int, legacy bool) (bool, error) {
+	id := snowflake.NextID()
+	now := formatTime(time.Now())
+	fingerprint := contentFingerprint(entry)
+
+	var publishedAt, publishedAtSource interface{}
+	if entry.PublishedAt != nil {
+		publishedAt = formatTime(*entry.PublishedAt)
+	}
+	if entry.PublishedAtSource != "" {
+		publishedAtSource = entry.PublishedAtSource
+	}
+	canonicalURL := entryCanonicalURL(entry)
+
+	// Compatibility path:
+	// legacy databases might still carry URL-derived hashes after migration.
+	// If we receive the same URL with a new GUID-derived hash, upgrade that row in place
+	// so the first refresh after migration doesn't create duplicates.
+	if legacy && entry.URL != nil && *entry.URL != "" && entry.Hash != "" {
+		normalizedURL := urlutil.StripFragment(*entry.URL)
+		hashURL := urlutil.NormalizeForHash(*entry.URL)
+		result, err := r.db.ExecContext(
+			ctx,
+			`UPDATE entries SET
+			   hash = ?,
+			   title = ?,
+			   url = ?,
+			   canonical_url = ?,
+			   content = ?,
+			   thumbnail_url = ?,
 			   author = ?,
+			   published_at_source = CASE WHEN entries.published_at IS NULL THEN ? ELSE entries.published_at_source END,
 			   published_at = COALESCE(entries.published_at, ?),
+			   word_count = ?,
+			   language = ?,
+			   content_hash = ?,
 			   updated_at = ?
 			 WHERE id = (
 			   SELECT id
@@ -327,7 +1275,7 @@ func (r *entryRepository) CreateOrUpdate(ctx context.Context, entry model.Entry)
 			     AND hash <> ?
 			     AND (
 			       url = ?
-			       OR (CASE WHEN instr(url, '#') > 0 THEN substr(url, 1, instr(url, '#') - 1) ELSE url END) = ?
+			       OR (CASE WHEN instr(url, '#') > 0 THEN substr(url, 1, instr(url, '#') - 1) ELSE url END) IN (?, ?)
 			     )
 			   ORDER BY updated_at DESC, id DESC
 			   LIMIT 1
@@ -342,53 +1290,419 @@ func (r *entryRepository) CreateOrUpdate(ctx context.Context, entry model.Entry)
 			entry.Hash,
 			entry.Title,
 			entry.URL,
+			canonicalURL,
 			entry.Content,
 			entry.ThumbnailURL,
 			entry.Author,
+			publishedAtSource,
 			publishedAt,
+			entry.WordCount,
+			entry.Language,
+			fingerprint,
 			now,
 			entry.FeedID,
 			entry.Hash,
 			entry.URL,
 			normalizedURL,
+			hashURL,
 			entry.FeedID,
 			entry.Hash,
 		)
 		if err != nil {
-			return err
+			return false, err
 		}
 		if affected, err := result.RowsAffected(); err == nil && affected > 0 {
-			return nil
+			return true, nil
 		}
 	}
 
-	_, err := r.db.ExecContext(
+	if revisionLimit > 0 && entry.Content != nil {
+		if err := r.snapshotRevision(ctx, entry, revisionLimit, now); err != nil {
+			return false, err
+		}
+	}
+
+	// The WHERE makes the conflict a no-op, so resending an unchanged entry
+	// doesn't bump updated_at
+	result, err := r.db.ExecContext(
 		ctx,
-		`INSERT INTO entries (id, feed_id, hash, title, url, content, thumbnail_url, author, published_at, read, created_at, updated_at)
-		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, 0, ?, ?)
+		`INSERT INTO entries (id, feed_id, hash, title, url, canonical_url, content, thumbnail_url, author, published_at, published_at_source, read, word_count, language, content_hash, created_at, updated_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 		 ON CONFLICT(feed_id, hash) DO UPDATE SET
 		   title = excluded.title,
 		   url = excluded.url,
+		   canonical_url = excluded.canonical_url,
 		   content = excluded.content,
 		   thumbnail_url = excluded.thumbnail_url,
 		   author = excluded.author,
+		   published_at_source = CASE WHEN entries.published_at IS NULL THEN excluded.published_at_source ELSE entries.published_at_source END,
 		   published_at = COALESCE(entries.published_at, excluded.published_at),
-		   updated_at = excluded.updated_at`,
+		   word_count = CASE
+		     WHEN entries.readable_content IS NOT NULL THEN MAX(excluded.word_count, COALESCE(entries.word_count, 0))
+		     ELSE excluded.word_count
+		   END,
+		   language = excluded.language,
+		   content_hash = excluded.content_hash,
+		   updated_at = excluded.updated_at
+		 WHERE entries.content_hash IS NOT excluded.content_hash`,
 		id,
 		entry.FeedID,
 		entry.Hash,
 		entry.Title,
 		entry.URL,
+		canonicalURL,
 		entry.Content,
 		entry.ThumbnailURL,
 		entry.Author,
 		publishedAt,
+		publishedAtSource,
+		boolToInt(entry.Read),
+		entry.WordCount,
+		entry.Language,
+		fingerprint,
+		now,
 		now,
+	)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+// existingHashChunk keeps the IN (...) list of SaveBatch well under SQLite's variable limit.
+const existingHashChunk = 500
+
+func (r *entryRepository) SaveBatch(ctx context.Context, feedID int64, entries []model.Entry, revisionLimit int) (int, int, error) {
+	defer r.changes.Notify()
+
+	if len(entries) == 0 {
+		return 0, 0, nil
+	}
+
+	var newCount, updatedCount int
+	err := withTx(ctx, r.db, r.writes, func(tx dbtx) error {
+		var err error
+		newCount, updatedCount, err = (&entryRepository{db: tx}).saveBatch(ctx, feedID, entries, revisionLimit)
+		return err
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+	return newCount, updatedCount, nil
+}
+
+func (r *entryRepository) saveBatch(ctx context.Context, feedID int64, entries []model.Entry, revisionLimit int) (int, int, error) {
+	hashes := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		hashes = append(hashes, entry.Hash)
+	}
+	existing, err := r.existingHashes(ctx, feedID, hashes)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	// Only entries without a hash match can be legacy rows keyed by URL
+	var urls []string
+	for _, entry := range entries {
+		if !existing[entry.Hash] && entry.URL != nil {
+			urls = append(urls, *entry.URL)
+		}
+	}
+	stored, err := r.storedLegacyURLs(ctx, feedID, urls)
+	if err != nil {
+		return 0, 0, err
+	}
+	undetected, err := r.hasUndetectedLanguage(ctx, feedID)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	newCount, updatedCount := 0, 0
+	for _, entry := range entries {
+		entry.FeedID = feedID
+		exists := existing[entry.Hash]
+		legacy := !exists && entry.URL != nil && stored.matches(*entry.URL)
+		exists = exists || legacy
+
+		changed, err := r.upsert(ctx, entry, revisionLimit, legacy)
+		if err != nil {
+			return 0, 0, err
+		}
+		if err := r.saveRawItem(ctx, entry); err != nil {
+			return 0, 0, err
+		}
+		existing[entry.Hash] = true
+		// Later entries of the batch may match this one by URL
+		if entry.URL != nil {
+			stored.add(*entry.URL)
+		}
+
+		switch {
+		case !changed:
+			if exists && undetected {
+				if err := r.backfillLanguage(ctx, entry); err != nil {
+					return 0, 0, err
+				}
+			}
+		case exists:
+			updatedCount++
+		default:
+			newCount++
+		}
+	}
+	return newCount, updatedCount, nil
+}
+
+// hasUndetectedLanguage reports whether the feed has entries stored before
+// languages were detected.
+func (r *entryRepository) hasUndetectedLanguage(ctx context.Context, feedID int64) (bool, error) {
+	var undetected bool
+	err := r.db.QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM entries WHERE feed_id = ? AND language IS NULL)`, feedID).Scan(&undetected)
+	return undetected, err
+}
+
+// backfillLanguage stores the language of an unchanged entry that has none yet,
+// without touching updated_at.
+func (r *entryRepository) backfillLanguage(ctx context.Context, entry model.Entry) error {
+	_, err := r.db.ExecContext(
+		ctx,
+		`UPDATE entries SET language = ? WHERE feed_id = ? AND hash = ? AND language IS NULL`,
+		entry.Language,
+		entry.FeedID,
+		entry.Hash,
+	)
+	return err
+}
+
+// existingHashes returns which of hashes already exist for the feed.
+func (r *entryRepository) existingHashes(ctx context.Context, feedID int64, hashes []string) (map[string]bool, error) {
+	existing := make(map[string]bool, len(hashes))
+	for start := 0; start < len(hashes); start += existingHashChunk {
+		chunk := hashes[start:min(start+existingHashChunk, len(hashes))]
+
+		args := make([]interface{}, 0, len(chunk)+1)
+		args = append(args, feedID)
+		for _, hash := range chunk {
+			args = append(args, hash)
+		}
+		placeholders := strings.TrimSuffix(strings.Repeat("?,", len(chunk)), ",")
+
+		rows, err := r.db.QueryContext(ctx, `SELECT hash FROM entries WHERE feed_id = ? AND hash IN (`+placeholders+`)`, args...)
+		if err != nil {
+			return nil, err
+		}
+		for rows.Next() {
+			var hash string
+			if err := rows.Scan(&hash); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			existing[hash] = true
+		}
+		if err := rows.Err(); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		rows.Close()
+	}
+	return existing, nil
+}
+
+// snapshotRevision stores the current content of the entry matching (feed_id, hash)
+// if the incoming content differs, then prunes snapshots beyond limit.
+func (r *entryRepository) snapshotRevision(ctx context.Context, entry model.Entry, limit int, now string) error {
+	result, err := r.db.ExecContext(
+		ctx,
+		`INSERT INTO entry_revisions (id, entry_id, title, content, created_at)
+		 SELECT ?, id, title, content, ?
+		 FROM entries
+		 WHERE feed_id = ? AND hash = ? AND content IS NOT NULL AND content <> ?`,
+		snowflake.NextID(),
 		now,
+		entry.FeedID,
+		entry.Hash,
+		*entry.Content,
+	)
+	if err != nil {
+		return err
+	}
+	if affected, err := result.RowsAffected(); err != nil || affected == 0 {
+		return err
+	}
+
+	_, err = r.db.ExecContext(
+		ctx,
+		`DELETE FROM entry_revisions
+		 WHERE entry_id = (SELECT id FROM entries WHERE feed_id = ? AND hash = ?)
+		   AND id NOT IN (
+		     SELECT r.id FROM entry_revisions r
+		     WHERE r.entry_id = entry_revisions.entry_id
+		     ORDER BY r.created_at DESC, r.id DESC
+		     LIMIT ?
+		   )`,
+		entry.FeedID,
+		entry.Hash,
+		limit,
 	)
 	return err
 }
 
+func (r *entryRepository) SetNote(ctx context.Context, entryID int64, note string) error {
+	defer r.changes.Notify()
+
+	_, err := r.db.ExecContext(
+		ctx,
+		`INSERT INTO entry_notes (entry_id, note, updated_at) VALUES (?, ?, ?)
+		 ON CONFLICT(entry_id) DO UPDATE SET note = excluded.note, updated_at = excluded.updated_at`,
+		entryID,
+		note,
+		formatTime(time.Now()),
+	)
+	return err
+}
+
+func (r *entryRepository) Delete(ctx context.Context, id int64) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM entries WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return sql.ErrNoRows
+	}
+	r.changes.Notify()
+	return nil
+}
+
+func (r *entryRepository) DeleteNote(ctx context.Context, entryID int64) error {
+	defer r.changes.Notify()
+
+	_, err := r.db.ExecContext(ctx, `DELETE FROM entry_notes WHERE entry_id = ?`, entryID)
+	return err
+}
+
+// ListRevisions returns the stored snapshots for an entry, newest first.
+func (r *entryRepository) ListRevisions(ctx context.Context, entryID int64) ([]model.EntryRevision, error) {
+	rows, err := r.db.QueryContext(
+		ctx,
+		`SELECT id, entry_id, title, content, created_at
+		 FROM entry_revisions WHERE entry_id = ?
+		 ORDER BY created_at DESC, id DESC`,
+		entryID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var revisions []model.EntryRevision
+	for rows.Next() {
+		var rev model.EntryRevision
+		var createdAt string
+		if err := rows.Scan(&rev.ID, &rev.EntryID, &rev.Title, &rev.Content, &createdAt); err != nil {
+			return nil, err
+		}
+		rev.CreatedAt, _ = parseTime(createdAt)
+		revisions = append(revisions, rev)
+	}
+	return revisions, rows.Err()
+}
+
+// saveRawItem stores entry.RawItem, if any, for the entry matching (feed_id, hash).
+// A new item keeps the time its entry's item was first stored.
+func (r *entryRepository) saveRawItem(ctx context.Context, entry model.Entry) error {
+	if entry.RawItem == nil {
+		return nil
+	}
+	_, err := r.db.ExecContext(
+		ctx,
+		`INSERT INTO entry_raw_items (entry_id, raw, stored_at)
+		 SELECT id, ?, ? FROM entries WHERE feed_id = ? AND hash = ?
+		 ON CONFLICT(entry_id) DO UPDATE SET raw = excluded.raw
+		 WHERE entry_raw_items.raw IS NOT excluded.raw`,
+		*entry.RawItem,
+		formatTime(time.Now()),
+		entry.FeedID,
+		entry.Hash,
+	)
+	return err
+}
+
+func (r *entryRepository) GetRawItem(ctx context.Context, entryID int64) (*string, error) {
+	var raw string
+	err := r.db.QueryRowContext(ctx, `SELECT raw FROM entry_raw_items WHERE entry_id = ?`, entryID).Scan(&raw)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &raw, nil
+}
+
+func (r *entryRepository) DeleteRawItemsBefore(ctx context.Context, before time.Time) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM entry_raw_items WHERE stored_at < ?`, formatTime(before))
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func (r *entryRepository) ListTombstones(ctx context.Context, since time.Time, feedID *int64) ([]int64, error) {
+	query := `SELECT entry_id FROM entry_tombstones WHERE julianday(deleted_at) >= julianday(?)`
+	args := []interface{}{formatTime(since)}
+	if feedID != nil {
+		query += ` AND feed_id = ?`
+		args = append(args, *feedID)
+	}
+	query += ` ORDER BY entry_id`
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+func (r *entryRepository) DeleteTombstonesBefore(ctx context.Context, before time.Time) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM entry_tombstones WHERE julianday(deleted_at) < julianday(?)`, formatTime(before))
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func (r *entryRepository) Rehash(ctx context.Context, feedID int64, hash func(link, title, content string) string) (int, error) {
+	defer r.changes.Notify()
+
+	var merged int
+	err := withTx(ctx, r.db, r.writes, func(tx dbtx) error {
+		var err error
+		merged, err = db.RehashFeedEntries(ctx, tx, feedID, func(entry db.DedupeEntry) string {
+			return hash(entry.URL, entry.Title, entry.Content)
+		})
+		return err
+	})
+	return merged, err
+}
+
 func (r *entryRepository) ExistsByHash(ctx context.Context, feedID int64, hash string) (bool, error) {
 	var count int
 	err := r.db.QueryRowContext(
@@ -403,46 +1717,135 @@ func (r *entryRepository) ExistsByHash(ctx context.Context, feedID int64, hash s
 	return count > 0, nil
 }
 
-func (r *entryRepository) ExistsByLegacyURL(ctx context.Context, feedID int64, rawURL string, hash string) (bool, error) {
-	trimmedURL := strings.TrimSpace(rawURL)
-	if trimmedURL == "" {
-		return false, nil
+// legacyURLChunk keeps the OR terms of storedLegacyURLs well under SQLite's variable limit.
+const legacyURLChunk = 100
+
+// legacyURLs holds stored entry URLs, exactly and without their fragment.
+type legacyURLs struct {
+	exact map[string]bool
+	pages map[string]bool
+}
+
+func (l legacyURLs) add(storedURL string) {
+	l.exact[storedURL] = true
+	page, _, _ := strings.Cut(storedURL, "#")
+	l.pages[page] = true
+}
+
+// matches reports whether rawURL equals a stored URL, or its page with the
+// fragment and tracking parameters dropped equals a stored page.
+func (l legacyURLs) matches(rawURL string) bool {
+	trimmed := strings.TrimSpace(rawURL)
+	if trimmed == "" {
+		return false
 	}
-	normalizedURL := urlutil.StripFragment(trimmedURL)
+	return l.exact[trimmed] || l.pages[urlutil.StripFragment(trimmed)] || l.pages[urlutil.NormalizeForHash(trimmed)]
+}
 
-	var count int
-	err := r.db.QueryRowContext(
-		ctx,
-		`SELECT COUNT(*) FROM entries
-		 WHERE feed_id = ?
-		   AND hash <> ?
-		   AND (
-		     url = ?
-		     OR (CASE WHEN instr(url, '#') > 0 THEN substr(url, 1, instr(url, '#') - 1) ELSE url END) = ?
-		   )`,
-		feedID,
-		hash,
-		trimmedURL,
-		normalizedURL,
-	).Scan(&count)
+func (r *entryRepository) ExistingLegacyURLs(ctx context.Context, feedID int64, rawURLs []string) (map[string]bool, error) {
+	stored, err := r.storedLegacyURLs(ctx, feedID, rawURLs)
 	if err != nil {
-		return false, err
+		return nil, err
 	}
-	return count > 0, nil
+	found := make(map[string]bool)
+	for _, rawURL := range rawURLs {
+		if stored.matches(rawURL) {
+			found[rawURL] = true
+		}
+	}
+	return found, nil
 }
 
-func (r *entryRepository) UpdateReadableContent(ctx context.Context, id int64, content string) error {
-	_, err := r.db.ExecContext(
-		ctx,
-		`UPDATE entries SET readable_content = ?, updated_at = ? WHERE id = ?`,
-		content,
-		formatTime(time.Now()),
-		id,
-	)
+// storedLegacyURLs loads the feed's stored URLs that rawURLs could match. A
+// stored URL matches a page when it equals it or starts with it followed by a
+// fragment, which the ranges select through idx_entries_feed_id_url.
+func (r *entryRepository) storedLegacyURLs(ctx context.Context, feedID int64, rawURLs []string) (legacyURLs, error) {
+	stored := legacyURLs{exact: make(map[string]bool), pages: make(map[string]bool)}
+	for start := 0; start < len(rawURLs); start += legacyURLChunk {
+		chunk := rawURLs[start:min(start+legacyURLChunk, len(rawURLs))]
+
+		var exact []interface{}
+		var ranges []interface{}
+		seen := make(map[string]bool)
+		for _, rawURL := range chunk {
+			trimmed := strings.TrimSpace(rawURL)
+			if trimmed == "" {
+				continue
+			}
+			for _, key := range []string{trimmed, urlutil.StripFragment(trimmed), urlutil.NormalizeForHash(trimmed)} {
+				if !seen[key] {
+					seen[key] = true
+					exact = append(exact, key)
+				}
+			}
+			for _, page := range []string{urlutil.StripFragment(trimmed), urlutil.NormalizeForHash(trimmed)} {
+				if !seen[page+"#"] {
+					seen[page+"#"] = true
+					// '$' follows '#', so the range holds every fragment of page
+					ranges = append(ranges, page+"#", page+"$")
+				}
+			}
+		}
+		if len(exact) == 0 {
+			continue
+		}
+
+		conditions := []string{`url IN (` + strings.TrimSuffix(strings.Repeat("?,", len(exact)), ",") + `)`}
+		for i := 0; i < len(ranges); i += 2 {
+			conditions = append(conditions, `(url >= ? AND url < ?)`)
+		}
+		args := append([]interface{}{feedID}, exact...)
+		args = append(args, ranges...)
+
+		rows, err := r.db.QueryContext(ctx, `SELECT url FROM entries WHERE feed_id = ? AND (`+strings.Join(conditions, " OR ")+`)`, args...)
+		if err != nil {
+			return legacyURLs{}, err
+		}
+		for rows.Next() {
+			var storedURL string
+			if err := rows.Scan(&storedURL); err != nil {
+				rows.Close()
+				return legacyURLs{}, err
+			}
+			stored.add(storedURL)
+		}
+		if err := rows.Err(); err != nil {
+			rows.Close()
+			return legacyURLs{}, err
+		}
+		rows.Close()
+	}
+	return stored, nil
+}
+
+func (r *entryRepository) UpdateReadableContent(ctx context.Context, id int64, content string, wordCount int) error {
+	defer r.changes.Notify()
+
+	return withTx(ctx, r.db, r.writes, func(tx dbtx) error {
+		if _, err := tx.ExecContext(
+			ctx,
+			`UPDATE entries SET readable_content = ?, word_count = MAX(COALESCE(word_count, 0), ?), updated_at = ? WHERE id = ?`,
+			content,
+			wordCount,
+			formatTime(time.Now()),
+			id,
+		); err != nil {
+			return err
+		}
+		return indexReadableText(ctx, tx, id, content)
+	})
+}
+
+func (r *entryRepository) UpdateContent(ctx context.Context, id int64, content string) error {
+	defer r.changes.Notify()
+
+	_, err := r.db.ExecContext(ctx, `UPDATE entries SET content = ? WHERE id = ?`, content, id)
 	return err
 }
 
 func (r *entryRepository) UpdateStarredStatus(ctx context.Context, id int64, starred bool) error {
+	defer r.changes.Notify()
+
 	starredInt := 0
 	if starred {
 		starredInt = 1
@@ -460,22 +1863,180 @@ func (r *entryRepository) UpdateStarredStatus(ctx context.Context, id int64, sta
 
 func (r *entryRepository) GetStarredCount(ctx context.Context) (int, error) {
 	var count int
-	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM entries WHERE starred = 1`).Scan(&count)
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM entries WHERE starred = 1 AND feed_id IN (SELECT id FROM feeds WHERE deleted_at IS NULL)`).Scan(&count)
 	return count, err
 }
 
 func (r *entryRepository) ClearAllReadableContent(ctx context.Context) (int64, error) {
-	result, err := r.db.ExecContext(ctx, `UPDATE entries SET readable_content = NULL, updated_at = ? WHERE readable_content IS NOT NULL`, formatTime(time.Now()))
+	defer r.changes.Notify()
+
+	var cleared int64
+	err := withTx(ctx, r.db, r.writes, func(tx dbtx) error {
+		result, err := tx.ExecContext(ctx, `UPDATE entries SET readable_content = NULL, updated_at = ?
+			 WHERE readable_content IS NOT NULL
+			   AND NOT (starred = 1 AND EXISTS (SELECT 1 FROM entry_archives WHERE entry_id = entries.id AND archived_at IS NOT NULL))`, formatTime(time.Now()))
+		if err != nil {
+			return err
+		}
+		if cleared, err = result.RowsAffected(); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, `UPDATE entries_fts_ext SET readable_text = ''
+			 WHERE readable_text != '' AND rowid IN (SELECT id FROM entries WHERE readable_content IS NULL)`); err != nil {
+			return err
+		}
+		return pruneSearchText(ctx, tx)
+	})
 	if err != nil {
 		return 0, err
 	}
-	return result.RowsAffected()
+	return cleared, nil
 }
 
 func (r *entryRepository) DeleteUnstarred(ctx context.Context) (int64, error) {
+	defer r.changes.Notify()
+
 	result, err := r.db.ExecContext(ctx, `DELETE FROM entries WHERE starred = 0`)
 	if err != nil {
 		return 0, err
 	}
 	return result.RowsAffected()
 }
+
+func (r *entryRepository) EvictOverCap(ctx context.Context, feedID int64, maxEntries int) (int, int, error) {
+	// A negative LIMIT means no limit, so the overflow is clamped at 0
+	result, err := r.db.ExecContext(ctx, `
+		DELETE FROM entries WHERE id IN (
+			SELECT id FROM entries
+			WHERE feed_id = ? AND read = 1 AND starred = 0
+			  AND NOT EXISTS (SELECT 1 FROM entry_notes WHERE entry_id = entries.id)
+			ORDER BY COALESCE(published_at, created_at), id
+			LIMIT MAX(0, (SELECT COUNT(*) FROM entries WHERE feed_id = ?) - ?)
+		)`, feedID, feedID, maxEntries)
+	if err != nil {
+		return 0, 0, fmt.Errorf("evict entries: %w", err)
+	}
+	evicted, err := result.RowsAffected()
+	if err != nil {
+		return 0, 0, fmt.Errorf("evict entries: %w", err)
+	}
+	if evicted > 0 {
+		r.changes.Notify()
+	}
+
+	var unread int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM entries WHERE feed_id = ? AND read = 0`, feedID).Scan(&unread); err != nil {
+		return 0, 0, fmt.Errorf("count unread entries: %w", err)
+	}
+	return int(evicted), unread, nil
+}
+
+func (r *entryRepository) ListForBackup(ctx context.Context, includeRead bool, afterID int64, limit int) ([]model.Entry, error) {
+	query := `SELECT e.id, e.feed_id, e.hash, e.title, e.url, e.content, e.readable_content, e.thumbnail_url, e.author, e.published_at, e.read, e.starred, e.word_count, e.created_at, e.updated_at, e.language,
+		       (SELECT note FROM entry_notes WHERE entry_id = e.id)
+		FROM entries e
+		JOIN feeds f ON f.id = e.feed_id AND f.deleted_at IS NULL
+		WHERE e.id > ?`
+	if !includeRead {
+		query += ` AND (e.read = 0 OR e.starred = 1 OR EXISTS (SELECT 1 FROM entry_notes WHERE entry_id = e.id))`
+	}
+	query += ` ORDER BY e.id LIMIT ?`
+
+	rows, err := r.db.QueryContext(ctx, query, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []model.Entry
+	for rows.Next() {
+		entry, err := scanEntryWithNote(rows)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
+
+func (r *entryRepository) ImportBatch(ctx context.Context, entries []model.Entry) (int, int, int, error) {
+	defer r.changes.Notify()
+
+	var created, updated, skipped int
+	err := withTx(ctx, r.db, r.writes, func(tx dbtx) error {
+		now := formatTime(time.Now())
+		for _, entry := range entries {
+			var id int64
+			var readInt, starredInt int
+			err := tx.QueryRowContext(ctx, `SELECT id, read, starred FROM entries WHERE feed_id = ? AND hash = ?`, entry.FeedID, entry.Hash).
+				Scan(&id, &readInt, &starredInt)
+			switch {
+			case err == sql.ErrNoRows:
+				id = snowflake.NextID()
+				var publishedAt interface{}
+				if entry.PublishedAt != nil {
+					publishedAt = formatTime(*entry.PublishedAt)
+				}
+				createdAt := now
+				if !entry.CreatedAt.IsZero() {
+					createdAt = formatTime(entry.CreatedAt)
+				}
+				if _, err := tx.ExecContext(
+					ctx,
+					`INSERT INTO entries (id, feed_id, hash, title, url, canonical_url, content, readable_content, thumbnail_url, author, published_at, read, starred, word_count, created_at, updated_at)
+					 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+					id, entry.FeedID, entry.Hash, entry.Title, entry.URL, entryCanonicalURL(entry), entry.Content, entry.ReadableContent,
+					entry.ThumbnailURL, entry.Author, publishedAt, boolToInt(entry.Read), boolToInt(entry.Starred), entry.WordCount, createdAt, now,
+				); err != nil {
+					return err
+				}
+				if _, err := importNote(ctx, tx, id, entry.Note, now); err != nil {
+					return err
+				}
+				created++
+			case err != nil:
+				return err
+			default:
+				changed := false
+				if (readInt == 1) != entry.Read || (starredInt == 1) != entry.Starred {
+					if _, err := tx.ExecContext(ctx, `UPDATE entries SET read = ?, read_at = `+readAtExpr+`, starred = ?, updated_at = ? WHERE id = ?`,
+						boolToInt(entry.Read), boolToInt(entry.Read), now, boolToInt(entry.Starred), now, id); err != nil {
+						return err
+					}
+					changed = true
+				}
+				noteAdded, err := importNote(ctx, tx, id, entry.Note, now)
+				if err != nil {
+					return err
+				}
+				if changed || noteAdded {
+					updated++
+				} else {
+					skipped++
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	return created, updated, skipped, nil
+}
+
+// importNote stores an imported note unless the entry already has one, and reports whether it did.
+func importNote(ctx context.Context, tx dbtx, entryID int64, note *string, now string) (bool, error) {
+	if note == nil {
+		return false, nil
+	}
+	result, err := tx.ExecContext(
+		ctx,
+		`INSERT INTO entry_notes (entry_id, note, updated_at) VALUES (?, ?, ?) ON CONFLICT(entry_id) DO NOTHING`,
+		entryID, *note, now,
+	)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
@@ -3,21 +3,25 @@ package repository_test
 import (
 	"context"
 	"crypto/sha256"
+	"database/sql"
 	"encoding/hex"
+	"fmt"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"gist/backend/internal/model"
 	"gist/backend/internal/repository"
 	"gist/backend/internal/repository/testutil"
+	"gist/backend/internal/urlutil"
 
 	"github.com/stretchr/testify/require"
 )
 
 func TestEntryRepository_CreateAndGet(t *testing.T) {
 	db := testutil.NewTestDB(t)
-	repo := repository.NewEntryRepository(db)
+	repo := repository.NewEntryRepository(db, nil, nil)
 	ctx := context.Background()
 
 	feedID := testutil.SeedFeed(t, db, model.Feed{Title: "Test Feed", URL: "url"})
@@ -31,7 +35,7 @@ func TestEntryRepository_CreateAndGet(t *testing.T) {
 		Hash:   hashString(url),
 	}
 
-	err := repo.CreateOrUpdate(ctx, entry)
+	err := repo.CreateOrUpdate(ctx, entry, 0)
 	require.NoError(t, err)
 
 	// List to find the ID
@@ -49,7 +53,7 @@ func TestEntryRepository_CreateAndGet(t *testing.T) {
 
 func TestEntryRepository_CreateOrUpdate_SameHashUpdatesURL(t *testing.T) {
 	db := testutil.NewTestDB(t)
-	repo := repository.NewEntryRepository(db)
+	repo := repository.NewEntryRepository(db, nil, nil)
 	ctx := context.Background()
 
 	feedID := testutil.SeedFeed(t, db, model.Feed{Title: "Test Feed", URL: "url"})
@@ -64,7 +68,7 @@ func TestEntryRepository_CreateOrUpdate_SameHashUpdatesURL(t *testing.T) {
 		Title:  &title,
 		URL:    &url1,
 		Hash:   hash,
-	})
+	}, 0)
 	require.NoError(t, err)
 
 	err = repo.CreateOrUpdate(ctx, model.Entry{
@@ -72,7 +76,7 @@ func TestEntryRepository_CreateOrUpdate_SameHashUpdatesURL(t *testing.T) {
 		Title:  &title,
 		URL:    &url2,
 		Hash:   hash,
-	})
+	}, 0)
 	require.NoError(t, err)
 
 	entries, err := repo.List(ctx, repository.EntryListFilter{FeedID: &feedID})
@@ -82,9 +86,34 @@ func TestEntryRepository_CreateOrUpdate_SameHashUpdatesURL(t *testing.T) {
 	require.Equal(t, url2, *entries[0].URL)
 }
 
+func TestEntryRepository_CreateOrUpdate_ReadOnlyForNewEntries(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	repo := repository.NewEntryRepository(db, nil, nil)
+	ctx := context.Background()
+
+	feedID := testutil.SeedFeed(t, db, model.Feed{Title: "Test Feed", URL: "url"})
+
+	title := "Test Entry"
+	entryURL := "https://example.com/a"
+	entry := model.Entry{FeedID: feedID, Title: &title, URL: &entryURL, Hash: hashString("a"), Read: true}
+	require.NoError(t, repo.CreateOrUpdate(ctx, entry, 0))
+
+	entries, err := repo.List(ctx, repository.EntryListFilter{FeedID: &feedID})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.True(t, entries[0].Read)
+
+	// Updates keep the stored read state
+	require.NoError(t, repo.UpdateReadStatus(ctx, entries[0].ID, false))
+	require.NoError(t, repo.CreateOrUpdate(ctx, entry, 0))
+	entries, err = repo.List(ctx, repository.EntryListFilter{FeedID: &feedID})
+	require.NoError(t, err)
+	require.False(t, entries[0].Read)
+}
+
 func TestEntryRepository_CreateOrUpdate_UpgradesLegacyURLHashToGUIDHash(t *testing.T) {
 	db := testutil.NewTestDB(t)
-	repo := repository.NewEntryRepository(db)
+	repo := repository.NewEntryRepository(db, nil, nil)
 	ctx := context.Background()
 
 	feedID := testutil.SeedFeed(t, db, model.Feed{Title: "Test Feed", URL: "url"})
@@ -105,7 +134,7 @@ func TestEntryRepository_CreateOrUpdate_UpgradesLegacyURLHashToGUIDHash(t *testi
 		Title:  &title,
 		URL:    &legacyURL,
 		Hash:   guidHash,
-	})
+	}, 0)
 	require.NoError(t, err)
 
 	entries, err := repo.List(ctx, repository.EntryListFilter{FeedID: &feedID})
@@ -116,7 +145,7 @@ func TestEntryRepository_CreateOrUpdate_UpgradesLegacyURLHashToGUIDHash(t *testi
 
 func TestEntryRepository_CreateOrUpdate_UpgradesLegacyURLHashToGUIDHash_WhenFragmentChanges(t *testing.T) {
 	db := testutil.NewTestDB(t)
-	repo := repository.NewEntryRepository(db)
+	repo := repository.NewEntryRepository(db, nil, nil)
 	ctx := context.Background()
 
 	feedID := testutil.SeedFeed(t, db, model.Feed{Title: "Test Feed", URL: "url"})
@@ -138,7 +167,7 @@ func TestEntryRepository_CreateOrUpdate_UpgradesLegacyURLHashToGUIDHash_WhenFrag
 		Title:  &title,
 		URL:    &newURL,
 		Hash:   guidHash,
-	})
+	}, 0)
 	require.NoError(t, err)
 
 	entries, err := repo.List(ctx, repository.EntryListFilter{FeedID: &feedID})
@@ -151,7 +180,7 @@ func TestEntryRepository_CreateOrUpdate_UpgradesLegacyURLHashToGUIDHash_WhenFrag
 
 func TestEntryRepository_CreateOrUpdate_CompatibilitySkipsWhenTargetHashExists(t *testing.T) {
 	db := testutil.NewTestDB(t)
-	repo := repository.NewEntryRepository(db)
+	repo := repository.NewEntryRepository(db, nil, nil)
 	ctx := context.Background()
 
 	feedID := testutil.SeedFeed(t, db, model.Feed{Title: "Test Feed", URL: "url"})
@@ -180,13 +209,13 @@ func TestEntryRepository_CreateOrUpdate_CompatibilitySkipsWhenTargetHashExists(t
 		Title:  &title,
 		URL:    &newURL,
 		Hash:   guidHash,
-	})
+	}, 0)
 	require.NoError(t, err)
 }
 
 func TestEntryRepository_List_Filters(t *testing.T) {
 	db := testutil.NewTestDB(t)
-	repo := repository.NewEntryRepository(db)
+	repo := repository.NewEntryRepository(db, nil, nil)
 	ctx := context.Background()
 
 	folderID := testutil.SeedFolder(t, db, "F1", nil, "article")
@@ -216,7 +245,7 @@ func TestEntryRepository_List_Filters(t *testing.T) {
 
 func TestEntryRepository_UpdateStatus(t *testing.T) {
 	db := testutil.NewTestDB(t)
-	repo := repository.NewEntryRepository(db)
+	repo := repository.NewEntryRepository(db, nil, nil)
 	ctx := context.Background()
 
 	feedID := testutil.SeedFeed(t, db, model.Feed{Title: "Feed", URL: "u"})
@@ -237,7 +266,7 @@ func TestEntryRepository_UpdateStatus(t *testing.T) {
 
 func TestEntryRepository_UpdateManyReadStatus(t *testing.T) {
 	db := testutil.NewTestDB(t)
-	repo := repository.NewEntryRepository(db)
+	repo := repository.NewEntryRepository(db, nil, nil)
 	ctx := context.Background()
 
 	feedID := testutil.SeedFeed(t, db, model.Feed{Title: "Feed", URL: "u"})
@@ -269,7 +298,7 @@ func TestEntryRepository_UpdateManyReadStatus(t *testing.T) {
 
 func TestEntryRepository_MarkAllAsRead(t *testing.T) {
 	db := testutil.NewTestDB(t)
-	repo := repository.NewEntryRepository(db)
+	repo := repository.NewEntryRepository(db, nil, nil)
 	ctx := context.Background()
 
 	feedID1 := testutil.SeedFeed(t, db, model.Feed{Title: "F1", URL: "u1"})
@@ -287,19 +316,327 @@ func TestEntryRepository_MarkAllAsRead(t *testing.T) {
 	require.Equal(t, 1, counts[0].Count)
 }
 
+func TestEntryRepository_ReadAt(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	repo := repository.NewEntryRepository(db, nil, nil)
+	ctx := context.Background()
+
+	feedID := testutil.SeedFeed(t, db, model.Feed{Title: "F", URL: "u"})
+	id := testutil.SeedEntry(t, db, model.Entry{FeedID: feedID})
+	other := testutil.SeedEntry(t, db, model.Entry{FeedID: feedID})
+	readAt := func(id int64) *string {
+		var value *string
+		require.NoError(t, db.QueryRow(`SELECT read_at FROM entries WHERE id = ?`, id).Scan(&value))
+		return value
+	}
+
+	require.NoError(t, repo.UpdateReadStatus(ctx, id, true))
+	first := readAt(id)
+	require.NotNil(t, first)
+
+	// Marking read again keeps the first read time
+	_, err := db.Exec(`UPDATE entries SET read_at = '2024-01-01T00:00:00Z' WHERE id = ?`, id)
+	require.NoError(t, err)
+	require.NoError(t, repo.UpdateManyReadStatus(ctx, []int64{id, other}, true))
+	require.Equal(t, "2024-01-01T00:00:00Z", *readAt(id))
+	require.NotNil(t, readAt(other))
+
+	// Marking unread clears it
+	require.NoError(t, repo.UpdateReadStatus(ctx, id, false))
+	require.Nil(t, readAt(id))
+
+	require.NoError(t, repo.MarkAllAsRead(ctx, nil, nil, nil))
+	require.NotNil(t, readAt(id))
+}
+
+func TestEntryRepository_ReadingStats(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	repo := repository.NewEntryRepository(db, nil, nil)
+	ctx := context.Background()
+
+	busy := testutil.SeedFeed(t, db, model.Feed{Title: "Busy", URL: "u1"})
+	quiet := testutil.SeedFeed(t, db, model.Feed{Title: "Quiet", URL: "u2"})
+	published := time.Date(2024, 3, 1, 20, 0, 0, 0, time.UTC)
+	seedRead := func(feedID int64, readAt string, publishedAt *time.Time) {
+		id := testutil.SeedEntry(t, db, model.Entry{FeedID: feedID, PublishedAt: publishedAt, Read: true})
+		_, err := db.Exec(`UPDATE entries SET read_at = ? WHERE id = ?`, readAt, id)
+		require.NoError(t, err)
+	}
+	seedRead(busy, "2024-03-01T22:00:00Z", &published)
+	seedRead(busy, "2024-03-02T00:00:00.5Z", &published)
+	seedRead(busy, "2024-03-02T09:00:00Z", nil)
+	seedRead(quiet, "2024-03-02T10:00:00Z", &published)
+	// Before the period, and read before read_at existed
+	seedRead(quiet, "2024-02-20T10:00:00Z", &published)
+	testutil.SeedEntry(t, db, model.Entry{FeedID: quiet, Read: true})
+
+	since := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	days, err := repo.CountReadsByDay(ctx, since, 0)
+	require.NoError(t, err)
+	require.Equal(t, []repository.DailyReadCount{{Date: "2024-03-01", Count: 1}, {Date: "2024-03-02", Count: 3}}, days)
+
+	// Shifted to UTC+8 the first read falls on the next day
+	days, err = repo.CountReadsByDay(ctx, since, 8*time.Hour)
+	require.NoError(t, err)
+	require.Equal(t, []repository.DailyReadCount{{Date: "2024-03-02", Count: 4}}, days)
+
+	feeds, err := repo.TopReadFeeds(ctx, since, 1)
+	require.NoError(t, err)
+	require.Equal(t, []repository.FeedReadCount{{FeedID: busy, Title: "Busy", Count: 3}}, feeds)
+
+	latency, err := repo.GetReadLatency(ctx, since)
+	require.NoError(t, err)
+	require.Equal(t, 3, latency.Samples)
+	// 2h, 4h and 14h after publication
+	require.InDelta(t, (20*time.Hour).Seconds()/3, latency.AverageSeconds, 1)
+
+	// Deleted feeds are left out
+	_, err = db.Exec(`UPDATE feeds SET deleted_at = ? WHERE id = ?`, time.Now().UTC().Format(time.RFC3339), busy)
+	require.NoError(t, err)
+	feeds, err = repo.TopReadFeeds(ctx, since, 10)
+	require.NoError(t, err)
+	require.Equal(t, []repository.FeedReadCount{{FeedID: quiet, Title: "Quiet", Count: 1}}, feeds)
+
+	latency, err = repo.GetReadLatency(ctx, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.Equal(t, repository.ReadLatency{}, latency)
+}
+
+func TestEntryRepository_Clicks(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	repo := repository.NewEntryRepository(db, nil, nil)
+	ctx := context.Background()
+
+	busy := testutil.SeedFeed(t, db, model.Feed{Title: "Busy", URL: "u1"})
+	ignored := testutil.SeedFeed(t, db, model.Feed{Title: "Ignored", URL: "u2"})
+	gone := testutil.SeedFeed(t, db, model.Feed{Title: "Gone", URL: "u3"})
+	first := testutil.SeedEntry(t, db, model.Entry{FeedID: busy})
+	second := testutil.SeedEntry(t, db, model.Entry{FeedID: busy})
+	old := testutil.SeedEntry(t, db, model.Entry{FeedID: ignored})
+	deleted := testutil.SeedEntry(t, db, model.Entry{FeedID: gone})
+
+	since := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	last := time.Date(2024, 3, 2, 10, 0, 0, 0, time.UTC)
+	require.NoError(t, repo.RecordClick(ctx, first, busy, since))
+	require.NoError(t, repo.RecordClick(ctx, second, busy, last))
+	require.NoError(t, repo.RecordClick(ctx, old, ignored, since.Add(-time.Second)))
+	require.NoError(t, repo.RecordClick(ctx, deleted, gone, last))
+	_, err := db.Exec(`UPDATE feeds SET deleted_at = ? WHERE id = ?`, time.Now().UTC().Format(time.RFC3339), gone)
+	require.NoError(t, err)
+
+	// Clicks outlive their entries
+	_, err = db.Exec(`DELETE FROM entries WHERE id = ?`, second)
+	require.NoError(t, err)
+
+	feeds, err := repo.CountClicksByFeed(ctx, since)
+	require.NoError(t, err)
+	require.Equal(t, []repository.FeedClickCount{
+		{FeedID: busy, Title: "Busy", Count: 2, LastClickedAt: &last},
+		{FeedID: ignored, Title: "Ignored"},
+	}, feeds)
+}
+
+func TestEntryRepository_CanonicalURL(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	repo := repository.NewEntryRepository(db, nil, nil)
+	ctx := context.Background()
+
+	canonical := func(table string, id int64) *string {
+		t.Helper()
+		var value sql.NullString
+		require.NoError(t, db.QueryRow(`SELECT canonical_url FROM `+table+` WHERE id = ?`, id).Scan(&value))
+		if !value.Valid {
+			return nil
+		}
+		return &value.String
+	}
+
+	// Two feeds carrying the same article under different links
+	mobile := testutil.SeedFeed(t, db, model.Feed{Title: "Mobile", URL: "u1"})
+	amp := testutil.SeedFeed(t, db, model.Feed{Title: "AMP", URL: "u2"})
+	mobileURL, ampURL := "https://m.example.com/x?utm_campaign=rss", "https://example.com/amp/x"
+	_, _, err := repo.SaveBatch(ctx, mobile, []model.Entry{{URL: &mobileURL, Hash: hashString(mobileURL)}}, 0)
+	require.NoError(t, err)
+	_, _, err = repo.SaveBatch(ctx, amp, []model.Entry{{URL: &ampURL, Hash: hashString(ampURL)}}, 0)
+	require.NoError(t, err)
+	var ids []int64
+	rows, err := db.Query(`SELECT id FROM entries WHERE canonical_url = ? ORDER BY feed_id`, "https://example.com/x")
+	require.NoError(t, err)
+	for rows.Next() {
+		var id int64
+		require.NoError(t, rows.Scan(&id))
+		ids = append(ids, id)
+	}
+	require.NoError(t, rows.Err())
+	require.Len(t, ids, 2)
+
+	// Entries saved before canonical_url was kept are filled in when clicked
+	oldURL := "https://amp.example.com/y/"
+	old := testutil.SeedEntry(t, db, model.Entry{FeedID: mobile, URL: &oldURL, Hash: "old"})
+	untouched := testutil.SeedEntry(t, db, model.Entry{FeedID: mobile, URL: &oldURL, Hash: "untouched"})
+	linkless := testutil.SeedEntry(t, db, model.Entry{FeedID: mobile})
+	require.Nil(t, canonical("entries", old))
+
+	require.NoError(t, repo.RecordClick(ctx, old, mobile, time.Now()))
+	require.Equal(t, "https://example.com/y", *canonical("entries", old))
+	var clickID int64
+	require.NoError(t, db.QueryRow(`SELECT id FROM entry_clicks WHERE entry_id = ?`, old).Scan(&clickID))
+	require.Equal(t, "https://example.com/y", *canonical("entry_clicks", clickID))
+
+	// Clicks recorded before are filled in by the backfill too
+	_, err = db.Exec(`INSERT INTO entry_clicks (id, entry_id, feed_id, clicked_at) VALUES (1, ?, ?, ?)`, untouched, mobile, time.Now().UTC().Format(time.RFC3339))
+	require.NoError(t, err)
+
+	updated, err := repo.BackfillCanonicalURLs(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 2, updated)
+	require.Equal(t, "https://example.com/y", *canonical("entries", untouched))
+	require.Equal(t, "", *canonical("entries", linkless))
+	require.Equal(t, "https://example.com/y", *canonical("entry_clicks", 1))
+
+	updated, err = repo.BackfillCanonicalURLs(ctx)
+	require.NoError(t, err)
+	require.Zero(t, updated)
+}
+
+func TestEntryRepository_GetAllUnreadCounts_SkipsPausedFeeds(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	repo := repository.NewEntryRepository(db, nil, nil)
+	ctx := context.Background()
+
+	future := time.Now().Add(time.Hour)
+	past := time.Now().Add(-time.Hour)
+	pausedID := testutil.SeedFeed(t, db, model.Feed{Title: "Paused", URL: "u1", PausedUntil: &future})
+	expiredID := testutil.SeedFeed(t, db, model.Feed{Title: "Expired", URL: "u2", PausedUntil: &past})
+
+	testutil.SeedEntry(t, db, model.Entry{FeedID: pausedID})
+	testutil.SeedEntry(t, db, model.Entry{FeedID: expiredID})
+
+	counts, err := repo.GetAllUnreadCounts(ctx)
+	require.NoError(t, err)
+	require.Len(t, counts, 1)
+	require.Equal(t, expiredID, counts[0].FeedID)
+}
+
+func TestEntryRepository_GetRecentCounts(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	repo := repository.NewEntryRepository(db, nil, nil)
+	ctx := context.Background()
+
+	future := time.Now().Add(time.Hour)
+	busy := testutil.SeedFeed(t, db, model.Feed{Title: "Busy", URL: "u1"})
+	quiet := testutil.SeedFeed(t, db, model.Feed{Title: "Quiet", URL: "u2"})
+	paused := testutil.SeedFeed(t, db, model.Feed{Title: "Paused", URL: "u3", PausedUntil: &future})
+	gone := testutil.SeedFeed(t, db, model.Feed{Title: "Gone", URL: "u4"})
+
+	since := time.Date(2026, 3, 4, 0, 0, 0, 0, time.UTC)
+	seed := func(feedID int64, createdAt time.Time) {
+		t.Helper()
+		id := testutil.SeedEntry(t, db, model.Entry{FeedID: feedID})
+		_, err := db.Exec(`UPDATE entries SET created_at = ? WHERE id = ?`, createdAt.Format(time.RFC3339Nano), id)
+		require.NoError(t, err)
+	}
+	seed(busy, since)
+	seed(busy, since.Add(5*time.Hour))
+	seed(busy, since.Add(-time.Second))
+	seed(quiet, since.Add(-time.Hour))
+	seed(paused, since.Add(time.Hour))
+	seed(gone, since.Add(time.Hour))
+	_, err := db.Exec(`UPDATE feeds SET deleted_at = ? WHERE id = ?`, time.Now().UTC().Format(time.RFC3339), gone)
+	require.NoError(t, err)
+
+	// Read entries still count: the count is of arrivals, not unread ones
+	_, err = db.Exec(`UPDATE entries SET read = 1 WHERE feed_id = ?`, busy)
+	require.NoError(t, err)
+
+	counts, err := repo.GetRecentCounts(ctx, since)
+	require.NoError(t, err)
+	require.Equal(t, []repository.RecentCount{{FeedID: busy, Count: 2}}, counts)
+}
+
+func TestEntryRepository_ApplyMutes(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	repo := repository.NewEntryRepository(db, nil, nil)
+	feeds := repository.NewFeedRepository(db, nil, nil)
+	ctx := context.Background()
+
+	feedID := testutil.SeedFeed(t, db, model.Feed{Title: "Group blog", URL: "https://example.com/feed"})
+	otherID := testutil.SeedFeed(t, db, model.Feed{Title: "Other", URL: "https://example.com/other"})
+	mutedID := testutil.SeedEntry(t, db, model.Entry{FeedID: feedID, Title: stringPtr("Muted"), Author: stringPtr("Jane Doe")})
+	keptID := testutil.SeedEntry(t, db, model.Entry{FeedID: feedID, Title: stringPtr("Kept"), Author: stringPtr("Adam")})
+	anonymousID := testutil.SeedEntry(t, db, model.Entry{FeedID: feedID, Title: stringPtr("Anonymous")})
+	otherFeedEntryID := testutil.SeedEntry(t, db, model.Entry{FeedID: otherID, Title: stringPtr("Elsewhere"), Author: stringPtr("Jane Doe")})
+	require.NoError(t, feeds.MuteAuthor(ctx, feedID, "jane doe"))
+
+	ids := func(filter repository.EntryListFilter) []int64 {
+		t.Helper()
+		filter.Limit = 10
+		entries, err := repo.List(ctx, filter)
+		require.NoError(t, err)
+		ids := make([]int64, 0, len(entries))
+		for _, entry := range entries {
+			ids = append(ids, entry.ID)
+		}
+		return ids
+	}
+	require.ElementsMatch(t, []int64{keptID, anonymousID, otherFeedEntryID}, ids(repository.EntryListFilter{UnreadOnly: true, ApplyMutes: true}))
+	require.ElementsMatch(t, []int64{mutedID, keptID, anonymousID, otherFeedEntryID}, ids(repository.EntryListFilter{UnreadOnly: true}))
+
+	counts, err := repo.GetAllUnreadCounts(ctx)
+	require.NoError(t, err)
+	byFeed := map[int64]int{}
+	for _, count := range counts {
+		byFeed[count.FeedID] = count.Count
+	}
+	require.Equal(t, map[int64]int{feedID: 2, otherID: 1}, byFeed)
+}
+
+func TestEntryRepository_Rehash(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	repo := repository.NewEntryRepository(db, nil, nil)
+	ctx := context.Background()
+
+	feedID := testutil.SeedFeed(t, db, model.Feed{Title: "F", URL: "u"})
+	testutil.SeedEntry(t, db, model.Entry{FeedID: feedID, URL: stringPtr("https://t.example/r/1?u=https://example.com/a"), Read: true})
+	keepID := testutil.SeedEntry(t, db, model.Entry{FeedID: feedID, URL: stringPtr("https://t.example/r/2?u=https://example.com/a")})
+	otherID := testutil.SeedEntry(t, db, model.Entry{FeedID: feedID, URL: stringPtr("https://t.example/r/3?u=https://example.com/b")})
+
+	merged, err := repo.Rehash(ctx, feedID, func(link, title, content string) string {
+		return urlutil.UnwrapRedirect(link)
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, merged)
+
+	entries, err := repo.ListByHashes(ctx, feedID, []string{"https://example.com/a", "https://example.com/b"})
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	byHash := map[string]model.Entry{}
+	for _, e := range entries {
+		byHash[e.Hash] = e
+	}
+	require.Equal(t, keepID, byHash["https://example.com/a"].ID, "ties keep the newest id")
+	require.True(t, byHash["https://example.com/a"].Read)
+	require.Equal(t, otherID, byHash["https://example.com/b"].ID)
+}
+
 func TestEntryRepository_ClearCaches(t *testing.T) {
 	db := testutil.NewTestDB(t)
-	repo := repository.NewEntryRepository(db)
+	repo := repository.NewEntryRepository(db, nil, nil)
 	ctx := context.Background()
 
 	feedID := testutil.SeedFeed(t, db, model.Feed{Title: "F", URL: "u"})
 	testutil.SeedEntry(t, db, model.Entry{FeedID: feedID, ReadableContent: stringPtr("content"), Starred: false})
 	testutil.SeedEntry(t, db, model.Entry{FeedID: feedID, Starred: true})
+	archivedID := testutil.SeedEntry(t, db, model.Entry{FeedID: feedID, ReadableContent: stringPtr("archived"), Starred: true})
+	require.NoError(t, repository.NewEntryArchiveRepository(db).MarkArchived(ctx, archivedID))
 
-	// Clear readable
+	// Clear readable, keeping archived content
 	count, err := repo.ClearAllReadableContent(ctx)
 	require.NoError(t, err)
 	require.Equal(t, int64(1), count)
+	archived, err := repo.GetByID(ctx, archivedID)
+	require.NoError(t, err)
+	require.Equal(t, "archived", *archived.ReadableContent)
 
 	// Delete unstarred
 	count, err = repo.DeleteUnstarred(ctx)
@@ -307,13 +644,72 @@ func TestEntryRepository_ClearCaches(t *testing.T) {
 	require.Equal(t, int64(1), count)
 
 	entries, _ := repo.List(ctx, repository.EntryListFilter{})
-	require.Len(t, entries, 1)
+	require.Len(t, entries, 2)
 	require.True(t, entries[0].Starred)
+	require.True(t, entries[1].Starred)
+}
+
+func TestEntryRepository_EvictOverCap(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	repo := repository.NewEntryRepository(db, nil, nil)
+	ctx := context.Background()
+
+	feedID := testutil.SeedFeed(t, db, model.Feed{Title: "Hashtag", URL: "https://example.com/tags/go.rss"})
+	otherFeedID := testutil.SeedFeed(t, db, model.Feed{Title: "Other", URL: "https://example.com/other.rss"})
+	day := func(d int) *time.Time {
+		t := time.Date(2024, 1, d, 0, 0, 0, 0, time.UTC)
+		return &t
+	}
+	oldestID := testutil.SeedEntry(t, db, model.Entry{FeedID: feedID, Title: stringPtr("1"), PublishedAt: day(1), Read: true})
+	starredID := testutil.SeedEntry(t, db, model.Entry{FeedID: feedID, Title: stringPtr("2"), PublishedAt: day(2), Read: true, Starred: true})
+	annotatedID := testutil.SeedEntry(t, db, model.Entry{FeedID: feedID, Title: stringPtr("3"), PublishedAt: day(3), Read: true})
+	olderID := testutil.SeedEntry(t, db, model.Entry{FeedID: feedID, Title: stringPtr("4"), PublishedAt: day(4), Read: true})
+	unreadID := testutil.SeedEntry(t, db, model.Entry{FeedID: feedID, Title: stringPtr("5"), PublishedAt: day(5)})
+	newestID := testutil.SeedEntry(t, db, model.Entry{FeedID: feedID, Title: stringPtr("6"), PublishedAt: day(6), Read: true})
+	otherID := testutil.SeedEntry(t, db, model.Entry{FeedID: otherFeedID, Title: stringPtr("other"), PublishedAt: day(1), Read: true})
+	require.NoError(t, repo.SetNote(ctx, annotatedID, "keep"))
+
+	remaining := func() []int64 {
+		t.Helper()
+		entries, err := repo.List(ctx, repository.EntryListFilter{FeedID: &feedID, Limit: 10})
+		require.NoError(t, err)
+		ids := make([]int64, 0, len(entries))
+		for _, entry := range entries {
+			ids = append(ids, entry.ID)
+		}
+		return ids
+	}
+
+	// The two oldest evictable entries go; starred and annotated ones are skipped
+	evicted, unread, err := repo.EvictOverCap(ctx, feedID, 4)
+	require.NoError(t, err)
+	require.Equal(t, 2, evicted)
+	require.Equal(t, 1, unread)
+	require.ElementsMatch(t, []int64{starredID, annotatedID, unreadID, newestID}, remaining())
+
+	var indexed int
+	require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM entries_fts WHERE rowid IN (?, ?)`, oldestID, olderID).Scan(&indexed))
+	require.Zero(t, indexed)
+
+	// At the cap nothing goes
+	evicted, _, err = repo.EvictOverCap(ctx, feedID, 4)
+	require.NoError(t, err)
+	require.Zero(t, evicted)
+
+	// Unread entries stay even when the cap can't be met
+	evicted, unread, err = repo.EvictOverCap(ctx, feedID, 1)
+	require.NoError(t, err)
+	require.Equal(t, 1, evicted)
+	require.Equal(t, 1, unread)
+	require.ElementsMatch(t, []int64{starredID, annotatedID, unreadID}, remaining())
+
+	_, err = repo.GetByID(ctx, otherID)
+	require.NoError(t, err)
 }
 
 func TestEntryRepository_ExistsByHash(t *testing.T) {
 	db := testutil.NewTestDB(t)
-	repo := repository.NewEntryRepository(db)
+	repo := repository.NewEntryRepository(db, nil, nil)
 	ctx := context.Background()
 
 	feedID := testutil.SeedFeed(t, db, model.Feed{Title: "F", URL: "u"})
@@ -330,170 +726,1320 @@ func TestEntryRepository_ExistsByHash(t *testing.T) {
 	require.False(t, exists)
 }
 
-func TestEntryRepository_ExistsByLegacyURL(t *testing.T) {
+func TestEntryRepository_ExistingLegacyURLs(t *testing.T) {
 	db := testutil.NewTestDB(t)
-	repo := repository.NewEntryRepository(db)
+	repo := repository.NewEntryRepository(db, nil, nil)
 	ctx := context.Background()
 
 	feedID := testutil.SeedFeed(t, db, model.Feed{Title: "F", URL: "u"})
 	legacyURL := "https://www.v2ex.com/t/1193191#reply10"
+	exactURL := "https://example.com/exact"
+	for _, u := range []*string{&legacyURL, &exactURL} {
+		testutil.SeedEntry(t, db, model.Entry{FeedID: feedID, URL: u, Hash: hashString(*u)})
+	}
+	otherFeedID := testutil.SeedFeed(t, db, model.Feed{Title: "Other", URL: "o"})
+	otherURL := "https://example.com/other-feed"
+	testutil.SeedEntry(t, db, model.Entry{FeedID: otherFeedID, URL: &otherURL, Hash: hashString(otherURL)})
+
+	found, err := repo.ExistingLegacyURLs(ctx, feedID, []string{
+		"https://www.v2ex.com/t/1193191#reply20",
+		" https://example.com/exact ",
+		"https://www.v2ex.com/t/other#reply1",
+		// Shares a prefix with the legacy URL but not its page
+		"https://www.v2ex.com/t/1193",
+		otherURL,
+		"",
+	})
+	require.NoError(t, err)
+	require.Equal(t, map[string]bool{
+		"https://www.v2ex.com/t/1193191#reply20": true,
+		" https://example.com/exact ":            true,
+	}, found)
+}
+
+func TestEntryRepository_ExistingLegacyURLs_IgnoresTrackingParams(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	repo := repository.NewEntryRepository(db, nil, nil)
+	ctx := context.Background()
+
+	feedID := testutil.SeedFeed(t, db, model.Feed{Title: "F", URL: "u"})
+	storedURL := "https://example.com/post?id=1#comments"
 	testutil.SeedEntry(t, db, model.Entry{
 		FeedID: feedID,
-		URL:    &legacyURL,
-		Hash:   hashString(legacyURL),
+		URL:    &storedURL,
+		Hash:   hashString(storedURL),
 	})
 
-	exists, err := repo.ExistsByLegacyURL(ctx, feedID, "https://www.v2ex.com/t/1193191#reply20", hashString("v2ex-guid-1193191"))
-	require.NoError(t, err)
-	require.True(t, exists)
-
-	exists, err = repo.ExistsByLegacyURL(ctx, feedID, "https://www.v2ex.com/t/other#reply1", hashString("other-guid"))
+	rawURL := "https://example.com/post?id=1&utm_source=rss#top"
+	found, err := repo.ExistingLegacyURLs(ctx, feedID, []string{rawURL})
 	require.NoError(t, err)
-	require.False(t, exists)
+	require.True(t, found[rawURL])
 }
 
 func TestEntryRepository_UpdateReadableContent(t *testing.T) {
 	db := testutil.NewTestDB(t)
-	repo := repository.NewEntryRepository(db)
+	repo := repository.NewEntryRepository(db, nil, nil)
 	ctx := context.Background()
 
 	feedID := testutil.SeedFeed(t, db, model.Feed{Title: "F", URL: "u"})
-	entryID := testutil.SeedEntry(t, db, model.Entry{FeedID: feedID})
+	entryID := testutil.SeedEntry(t, db, model.Entry{FeedID: feedID, WordCount: 40})
 
-	err := repo.UpdateReadableContent(ctx, entryID, "<article>readable</article>")
+	err := repo.UpdateReadableContent(ctx, entryID, "<article>readable</article>", 900)
 	require.NoError(t, err)
 
 	entry, err := repo.GetByID(ctx, entryID)
 	require.NoError(t, err)
 	require.NotNil(t, entry.ReadableContent)
 	require.Equal(t, "<article>readable</article>", *entry.ReadableContent)
+	require.Equal(t, 900, entry.WordCount)
+
+	// A shorter readable extraction never lowers the count
+	err = repo.UpdateReadableContent(ctx, entryID, "<article>short</article>", 10)
+	require.NoError(t, err)
+	entry, err = repo.GetByID(ctx, entryID)
+	require.NoError(t, err)
+	require.Equal(t, 900, entry.WordCount)
 }
 
-func TestEntryRepository_GetStarredCount(t *testing.T) {
+func TestEntryRepository_ListByHashes(t *testing.T) {
 	db := testutil.NewTestDB(t)
-	repo := repository.NewEntryRepository(db)
+	repo := repository.NewEntryRepository(db, nil, nil)
 	ctx := context.Background()
 
-	feedID := testutil.SeedFeed(t, db, model.Feed{Title: "F", URL: "u"})
-	testutil.SeedEntry(t, db, model.Entry{FeedID: feedID, Starred: true})
-	testutil.SeedEntry(t, db, model.Entry{FeedID: feedID, Starred: false})
+	feedID := testutil.SeedFeed(t, db, model.Feed{Title: "F", URL: "u1"})
+	otherFeedID := testutil.SeedFeed(t, db, model.Feed{Title: "G", URL: "u2"})
+	first := testutil.SeedEntry(t, db, model.Entry{FeedID: feedID, Hash: "a"})
+	testutil.SeedEntry(t, db, model.Entry{FeedID: feedID, Hash: "b"})
+	testutil.SeedEntry(t, db, model.Entry{FeedID: otherFeedID, Hash: "c"})
 
-	count, err := repo.GetStarredCount(ctx)
+	entries, err := repo.ListByHashes(ctx, feedID, []string{"a", "c", "missing"})
 	require.NoError(t, err)
-	require.Equal(t, 1, count)
-}
-
-func TestParseTimePtr(t *testing.T) {
-	require.Nil(t, repository.ParseTimePtr(""))
+	require.Len(t, entries, 1)
+	require.Equal(t, first, entries[0].ID)
+	require.Equal(t, "a", entries[0].Hash)
 
-	ts := "2025-01-04T12:34:56Z"
-	got := repository.ParseTimePtr(ts)
-	require.NotNil(t, got)
-	require.Equal(t, ts, got.UTC().Format(time.RFC3339))
+	entries, err = repo.ListByHashes(ctx, feedID, nil)
+	require.NoError(t, err)
+	require.Empty(t, entries)
 }
 
-// TestEntryRepository_CreateOrUpdate_PreservesExistingPublishedAt tests the BUG fix:
-// When an entry is updated (via ON CONFLICT), the existing published_at should be
-// preserved using COALESCE, not overwritten by the new value.
-// See commit 4b9dbc0: fix: Refresh should not overwrite existing published_at
-func TestEntryRepository_CreateOrUpdate_PreservesExistingPublishedAt(t *testing.T) {
+func TestEntryRepository_GetAdjacent_MatchesListOrder(t *testing.T) {
 	db := testutil.NewTestDB(t)
-	repo := repository.NewEntryRepository(db)
+	repo := repository.NewEntryRepository(db, nil, nil)
 	ctx := context.Background()
 
-	feedID := testutil.SeedFeed(t, db, model.Feed{Title: "Test Feed", URL: "url"})
-
-	title := "Test Entry"
-	url := "https://example.com/entry"
-	originalTime := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	feedID := testutil.SeedFeed(t, db, model.Feed{Title: "F", URL: "u1"})
+	otherFeedID := testutil.SeedFeed(t, db, model.Feed{Title: "G", URL: "u2"})
+	t1 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	t2 := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
 
-	// Create entry with published_at
-	entry := model.Entry{
-		FeedID:      feedID,
-		Title:       &title,
-		URL:         &url,
-		Hash:        hashString(url),
-		PublishedAt: &originalTime,
+	for i, published := range []*time.Time{&t1, &t2, &t2, nil, nil, &t1} {
+		testutil.SeedEntry(t, db, model.Entry{FeedID: feedID, Title: stringPtr(fmt.Sprintf("E%d", i)), PublishedAt: published})
 	}
-	err := repo.CreateOrUpdate(ctx, entry)
+	testutil.SeedEntry(t, db, model.Entry{FeedID: otherFeedID, PublishedAt: &t2})
+
+	filter := repository.EntryListFilter{FeedID: &feedID}
+	listed, err := repo.List(ctx, repository.EntryListFilter{FeedID: &feedID, Limit: 100})
 	require.NoError(t, err)
+	require.Len(t, listed, 6)
 
-	// Update the same entry with a different published_at
-	newTime := time.Date(2025, 6, 15, 12, 0, 0, 0, time.UTC)
-	updatedEntry := model.Entry{
-		FeedID:      feedID,
-		Title:       &title,
-		URL:         &url,
-		Hash:        hashString(url),
-		PublishedAt: &newTime,
+	// Walk forward and back; both must visit entries exactly as List orders them
+	for i := 0; i < len(listed)-1; i++ {
+		next, err := repo.GetAdjacent(ctx, listed[i], filter, true)
+		require.NoError(t, err)
+		require.Equal(t, listed[i+1].ID, next.ID, "next of %d", i)
+
+		prev, err := repo.GetAdjacent(ctx, listed[i+1], filter, false)
+		require.NoError(t, err)
+		require.Equal(t, listed[i].ID, prev.ID, "prev of %d", i+1)
 	}
-	err = repo.CreateOrUpdate(ctx, updatedEntry)
-	require.NoError(t, err)
 
-	// Verify that the original published_at is preserved
-	entries, err := repo.List(ctx, repository.EntryListFilter{FeedID: &feedID})
-	require.NoError(t, err)
-	require.Len(t, entries, 1)
-	require.NotNil(t, entries[0].PublishedAt)
-	require.Equal(t, originalTime.Format(time.RFC3339), entries[0].PublishedAt.UTC().Format(time.RFC3339))
+	_, err = repo.GetAdjacent(ctx, listed[len(listed)-1], filter, true)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+	_, err = repo.GetAdjacent(ctx, listed[0], filter, false)
+	require.ErrorIs(t, err, sql.ErrNoRows)
 }
 
-// TestEntryRepository_CreateOrUpdate_SetsPublishedAtWhenNull tests the BUG fix:
-// When an entry is updated and the existing published_at is NULL, the new value
-// should be used (COALESCE returns the first non-NULL value).
-// See commit 4b9dbc0: fix: Refresh should not overwrite existing published_at
-func TestEntryRepository_CreateOrUpdate_SetsPublishedAtWhenNull(t *testing.T) {
+func TestEntryRepository_GetAdjacent_Filters(t *testing.T) {
 	db := testutil.NewTestDB(t)
-	repo := repository.NewEntryRepository(db)
+	repo := repository.NewEntryRepository(db, nil, nil)
 	ctx := context.Background()
 
-	feedID := testutil.SeedFeed(t, db, model.Feed{Title: "Test Feed", URL: "url"})
+	feedID := testutil.SeedFeed(t, db, model.Feed{Title: "F", URL: "u1"})
+	t1 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	t2 := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	t3 := time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC)
+	newest := testutil.SeedEntry(t, db, model.Entry{FeedID: feedID, PublishedAt: &t3})
+	middle := testutil.SeedEntry(t, db, model.Entry{FeedID: feedID, PublishedAt: &t2, Read: true})
+	oldest := testutil.SeedEntry(t, db, model.Entry{FeedID: feedID, PublishedAt: &t1})
 
-	title := "Test Entry"
-	url := "https://example.com/entry"
+	ref, err := repo.GetByID(ctx, newest)
+	require.NoError(t, err)
+	next, err := repo.GetAdjacent(ctx, ref, repository.EntryListFilter{UnreadOnly: true}, true)
+	require.NoError(t, err)
+	require.Equal(t, oldest, next.ID)
 
-	// Create entry without published_at
-	entry := model.Entry{
-		FeedID:      feedID,
-		Title:       &title,
-		URL:         &url,
-		Hash:        hashString(url),
-		PublishedAt: nil,
-	}
-	err := repo.CreateOrUpdate(ctx, entry)
+	// The reference entry itself need not match the filter
+	ref, err = repo.GetByID(ctx, middle)
 	require.NoError(t, err)
+	prev, err := repo.GetAdjacent(ctx, ref, repository.EntryListFilter{UnreadOnly: true}, false)
+	require.NoError(t, err)
+	require.Equal(t, newest, prev.ID)
+}
 
-	// Verify entry has no published_at
-	entries, err := repo.List(ctx, repository.EntryListFilter{FeedID: &feedID})
+func TestEntryRepository_ListForDigest(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	repo := repository.NewEntryRepository(db, nil, nil)
+	ctx := context.Background()
+
+	folderID := testutil.SeedFolder(t, db, "News", nil, "article")
+	busyFeed := testutil.SeedFeed(t, db, model.Feed{Title: "Busy", URL: "u1", FolderID: &folderID})
+	quietFeed := testutil.SeedFeed(t, db, model.Feed{Title: "Quiet", URL: "u2"})
+	trashedFeed := testutil.SeedFeed(t, db, model.Feed{Title: "Trashed", URL: "u3"})
+	_, err := db.ExecContext(ctx, `UPDATE feeds SET deleted_at = ? WHERE id = ?`, time.Now().UTC().Format(time.RFC3339Nano), trashedFeed)
 	require.NoError(t, err)
-	require.Len(t, entries, 1)
-	require.Nil(t, entries[0].PublishedAt)
 
-	// Update the same entry with a published_at
-	newTime := time.Date(2025, 6, 15, 12, 0, 0, 0, time.UTC)
-	updatedEntry := model.Entry{
-		FeedID:      feedID,
-		Title:       &title,
-		URL:         &url,
-		Hash:        hashString(url),
-		PublishedAt: &newTime,
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 1)
+	var busyIDs []int64
+	for i := 0; i < 4; i++ {
+		published := start.Add(time.Duration(i+1) * time.Hour)
+		busyIDs = append(busyIDs, testutil.SeedEntry(t, db, model.Entry{FeedID: busyFeed, PublishedAt: &published}))
 	}
-	err = repo.CreateOrUpdate(ctx, updatedEntry)
-	require.NoError(t, err)
+	quietAt := start.Add(30 * time.Minute)
+	quietID := testutil.SeedEntry(t, db, model.Entry{FeedID: quietFeed, PublishedAt: &quietAt})
+	testutil.SeedEntry(t, db, model.Entry{FeedID: trashedFeed, PublishedAt: &quietAt})
 
-	// Verify that the new published_at is set
-	entries, err = repo.List(ctx, repository.EntryListFilter{FeedID: &feedID})
+	// Bounds are half-open
+	testutil.SeedEntry(t, db, model.Entry{FeedID: quietFeed, PublishedAt: &end})
+	before := start.Add(-time.Nanosecond)
+	testutil.SeedEntry(t, db, model.Entry{FeedID: quietFeed, PublishedAt: &before})
+
+	entries, err := repo.ListForDigest(ctx, start, end, 2)
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+
+	require.Equal(t, busyIDs[3], entries[0].Entry.ID)
+	require.Equal(t, busyIDs[2], entries[1].Entry.ID)
+	require.Equal(t, "Busy", entries[0].FeedTitle)
+	require.Equal(t, folderID, *entries[0].FolderID)
+	require.Equal(t, "News", *entries[0].FolderName)
+	require.Equal(t, 4, entries[0].FeedTotal)
+
+	require.Equal(t, quietID, entries[2].Entry.ID)
+	require.Nil(t, entries[2].FolderID)
+	require.Nil(t, entries[2].FolderName)
+	require.Equal(t, 1, entries[2].FeedTotal)
+
+	// A window expressed in another zone selects by instant, not by stored text
+	shanghai := time.FixedZone("CST", 8*3600)
+	entries, err = repo.ListForDigest(ctx, time.Date(2024, 3, 1, 9, 30, 0, 0, shanghai), time.Date(2024, 3, 1, 10, 30, 0, 0, shanghai), 10)
 	require.NoError(t, err)
 	require.Len(t, entries, 1)
-	require.NotNil(t, entries[0].PublishedAt)
-	require.Equal(t, newTime.Format(time.RFC3339), entries[0].PublishedAt.UTC().Format(time.RFC3339))
+	require.Equal(t, busyIDs[1], entries[0].Entry.ID)
 }
 
-func stringPtr(s string) *string {
-	return &s
-}
+func TestEntryRepository_NonUTCPublishedAt(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	repo := repository.NewEntryRepository(db, nil, nil)
+	ctx := context.Background()
 
-func hashString(input string) string {
-	sum := sha256.Sum256([]byte(strings.TrimSpace(input)))
-	return hex.EncodeToString(sum[:])
+	feedID := testutil.SeedFeed(t, db, model.Feed{Title: "F", URL: "u"})
+	published := time.Date(2024, 3, 1, 2, 0, 0, 0, time.UTC)
+	testutil.SeedEntry(t, db, model.Entry{FeedID: feedID, PublishedAt: &published})
+	testutil.SeedEntry(t, db, model.Entry{FeedID: feedID})
+	legacyID := testutil.SeedEntry(t, db, model.Entry{FeedID: feedID})
+	_, err := db.ExecContext(ctx, `UPDATE entries SET published_at = ? WHERE id = ?`, "2024-03-01 10:00:00", legacyID)
+	require.NoError(t, err)
+
+	values, err := repo.ListNonUTCPublishedAt(ctx)
+	require.NoError(t, err)
+	require.Len(t, values, 1)
+	require.Equal(t, repository.RawPublishedAt{EntryID: legacyID, FeedID: feedID, Value: "2024-03-01 10:00:00"}, values[0])
+
+	require.NoError(t, repo.UpdatePublishedAt(ctx, legacyID, published))
+	entry, err := repo.GetByID(ctx, legacyID)
+	require.NoError(t, err)
+	require.True(t, published.Equal(*entry.PublishedAt))
+
+	values, err = repo.ListNonUTCPublishedAt(ctx)
+	require.NoError(t, err)
+	require.Empty(t, values)
+}
+
+func TestEntryRepository_UpdateContent(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	repo := repository.NewEntryRepository(db, nil, nil)
+	ctx := context.Background()
+
+	feedID := testutil.SeedFeed(t, db, model.Feed{Title: "F", URL: "u"})
+	entryID := testutil.SeedEntry(t, db, model.Entry{FeedID: feedID})
+	before, err := repo.GetByID(ctx, entryID)
+	require.NoError(t, err)
+
+	require.NoError(t, repo.UpdateContent(ctx, entryID, "<p>local</p>"))
+
+	entry, err := repo.GetByID(ctx, entryID)
+	require.NoError(t, err)
+	require.NotNil(t, entry.Content)
+	require.Equal(t, "<p>local</p>", *entry.Content)
+	require.Equal(t, before.UpdatedAt, entry.UpdatedAt)
+}
+
+func TestEntryRepository_CreateOrUpdate_KeepsReadableWordCount(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	repo := repository.NewEntryRepository(db, nil, nil)
+	ctx := context.Background()
+
+	feedID := testutil.SeedFeed(t, db, model.Feed{Title: "F", URL: "u"})
+	url := "https://example.com/truncated"
+	content := "teaser"
+	entry := model.Entry{FeedID: feedID, Hash: hashString(url), URL: &url, Content: &content, WordCount: 1}
+	require.NoError(t, repo.CreateOrUpdate(ctx, entry, 0))
+
+	entries, err := repo.List(ctx, repository.EntryListFilter{FeedID: &feedID})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, 1, entries[0].WordCount)
+
+	require.NoError(t, repo.UpdateReadableContent(ctx, entries[0].ID, "<article>full</article>", 1200))
+	require.NoError(t, repo.CreateOrUpdate(ctx, entry, 0))
+
+	got, err := repo.GetByID(ctx, entries[0].ID)
+	require.NoError(t, err)
+	require.Equal(t, 1200, got.WordCount, "refreshing the truncated feed content keeps the full article count")
+}
+
+func TestEntryRepository_List_ReadingMinutesFilter(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	repo := repository.NewEntryRepository(db, nil, nil)
+	ctx := context.Background()
+
+	feedID := testutil.SeedFeed(t, db, model.Feed{Title: "F", URL: "u"})
+	ids := make(map[int]int64)
+	for _, words := range []int{0, 200, 250, 251, 1000} {
+		ids[words] = testutil.SeedEntry(t, db, model.Entry{FeedID: feedID, WordCount: words})
+	}
+
+	listIDs := func(filter repository.EntryListFilter) []int64 {
+		entries, err := repo.List(ctx, filter)
+		require.NoError(t, err)
+		got := make([]int64, 0, len(entries))
+		for _, e := range entries {
+			got = append(got, e.ID)
+		}
+		return got
+	}
+
+	one, two, four := 1, 2, 4
+	require.ElementsMatch(t, []int64{ids[200], ids[250]}, listIDs(repository.EntryListFilter{MinReadingMinutes: &one, MaxReadingMinutes: &one}))
+	require.ElementsMatch(t, []int64{ids[251], ids[1000]}, listIDs(repository.EntryListFilter{MinReadingMinutes: &two}))
+	require.ElementsMatch(t, []int64{ids[0], ids[200], ids[250], ids[251], ids[1000]}, listIDs(repository.EntryListFilter{MaxReadingMinutes: &four}))
+}
+
+func TestEntryRepository_List_ReadableAndSummaryFilters(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	repo := repository.NewEntryRepository(db, nil, nil)
+	summaries := repository.NewAISummaryRepository(db, nil, nil)
+	ctx := context.Background()
+
+	feedID := testutil.SeedFeed(t, db, model.Feed{Title: "F", URL: "u"})
+	plain := testutil.SeedEntry(t, db, model.Entry{FeedID: feedID})
+	empty := testutil.SeedEntry(t, db, model.Entry{FeedID: feedID, ReadableContent: stringPtr("")})
+	readable := testutil.SeedEntry(t, db, model.Entry{FeedID: feedID, ReadableContent: stringPtr("<p>full</p>"), Starred: true})
+	summarized := testutil.SeedEntry(t, db, model.Entry{FeedID: feedID, ReadableContent: stringPtr("<p>full</p>")})
+	require.NoError(t, summaries.Save(ctx, summarized, true, "en-US", "summary"))
+	require.NoError(t, summaries.Save(ctx, plain, false, "zh-CN", "摘要"))
+
+	listIDs := func(filter repository.EntryListFilter) []int64 {
+		entries, err := repo.List(ctx, filter)
+		require.NoError(t, err)
+		got := make([]int64, 0, len(entries))
+		for _, e := range entries {
+			got = append(got, e.ID)
+		}
+		count, err := repo.Count(ctx, filter)
+		require.NoError(t, err)
+		require.Equal(t, len(got), count)
+		return got
+	}
+
+	require.ElementsMatch(t, []int64{readable, summarized}, listIDs(repository.EntryListFilter{HasReadableContent: true}))
+	require.ElementsMatch(t, []int64{summarized}, listIDs(repository.EntryListFilter{SummaryLanguage: "en-US"}))
+	require.ElementsMatch(t, []int64{plain}, listIDs(repository.EntryListFilter{SummaryLanguage: "zh-CN"}))
+	require.ElementsMatch(t, []int64{readable}, listIDs(repository.EntryListFilter{HasReadableContent: true, StarredOnly: true}))
+	require.ElementsMatch(t, []int64{plain, empty, readable, summarized}, listIDs(repository.EntryListFilter{FeedID: &feedID}))
+}
+
+func TestEntryRepository_List_ContentLanguage(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	repo := repository.NewEntryRepository(db, nil, nil)
+	ctx := context.Background()
+
+	feedID := testutil.SeedFeed(t, db, model.Feed{Title: "F", URL: "u"})
+	english := testutil.SeedEntry(t, db, model.Entry{FeedID: feedID, Language: "en"})
+	testutil.SeedEntry(t, db, model.Entry{FeedID: feedID, Language: "ja"})
+	testutil.SeedEntry(t, db, model.Entry{FeedID: feedID})
+
+	entries, err := repo.List(ctx, repository.EntryListFilter{ContentLanguage: "en"})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, english, entries[0].ID)
+	require.Equal(t, "en", entries[0].Language)
+
+	count, err := repo.Count(ctx, repository.EntryListFilter{ContentLanguage: "en"})
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+}
+
+func TestEntryRepository_GetStarredCount(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	repo := repository.NewEntryRepository(db, nil, nil)
+	ctx := context.Background()
+
+	feedID := testutil.SeedFeed(t, db, model.Feed{Title: "F", URL: "u"})
+	testutil.SeedEntry(t, db, model.Entry{FeedID: feedID, Starred: true})
+	testutil.SeedEntry(t, db, model.Entry{FeedID: feedID, Starred: false})
+
+	count, err := repo.GetStarredCount(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+}
+
+func TestParseTimePtr(t *testing.T) {
+	require.Nil(t, repository.ParseTimePtr(""))
+
+	ts := "2025-01-04T12:34:56Z"
+	got := repository.ParseTimePtr(ts)
+	require.NotNil(t, got)
+	require.Equal(t, ts, got.UTC().Format(time.RFC3339))
+}
+
+// TestEntryRepository_CreateOrUpdate_PreservesExistingPublishedAt tests the BUG fix:
+// When an entry is updated (via ON CONFLICT), the existing published_at should be
+// preserved using COALESCE, not overwritten by the new value.
+// See commit 4b9dbc0: fix: Refresh should not overwrite existing published_at
+func TestEntryRepository_CreateOrUpdate_PreservesExistingPublishedAt(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	repo := repository.NewEntryRepository(db, nil, nil)
+	ctx := context.Background()
+
+	feedID := testutil.SeedFeed(t, db, model.Feed{Title: "Test Feed", URL: "url"})
+
+	title := "Test Entry"
+	url := "https://example.com/entry"
+	originalTime := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	// Create entry with published_at
+	entry := model.Entry{
+		FeedID:      feedID,
+		Title:       &title,
+		URL:         &url,
+		Hash:        hashString(url),
+		PublishedAt: &originalTime,
+	}
+	err := repo.CreateOrUpdate(ctx, entry, 0)
+	require.NoError(t, err)
+
+	// Update the same entry with a different published_at
+	newTime := time.Date(2025, 6, 15, 12, 0, 0, 0, time.UTC)
+	updatedEntry := model.Entry{
+		FeedID:      feedID,
+		Title:       &title,
+		URL:         &url,
+		Hash:        hashString(url),
+		PublishedAt: &newTime,
+	}
+	err = repo.CreateOrUpdate(ctx, updatedEntry, 0)
+	require.NoError(t, err)
+
+	// Verify that the original published_at is preserved
+	entries, err := repo.List(ctx, repository.EntryListFilter{FeedID: &feedID})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.NotNil(t, entries[0].PublishedAt)
+	require.Equal(t, originalTime.Format(time.RFC3339), entries[0].PublishedAt.UTC().Format(time.RFC3339))
+}
+
+// TestEntryRepository_CreateOrUpdate_SetsPublishedAtWhenNull tests the BUG fix:
+// When an entry is updated and the existing published_at is NULL, the new value
+// should be used (COALESCE returns the first non-NULL value).
+// See commit 4b9dbc0: fix: Refresh should not overwrite existing published_at
+func TestEntryRepository_CreateOrUpdate_SetsPublishedAtWhenNull(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	repo := repository.NewEntryRepository(db, nil, nil)
+	ctx := context.Background()
+
+	feedID := testutil.SeedFeed(t, db, model.Feed{Title: "Test Feed", URL: "url"})
+
+	title := "Test Entry"
+	url := "https://example.com/entry"
+
+	// Create entry without published_at
+	entry := model.Entry{
+		FeedID:      feedID,
+		Title:       &title,
+		URL:         &url,
+		Hash:        hashString(url),
+		PublishedAt: nil,
+	}
+	err := repo.CreateOrUpdate(ctx, entry, 0)
+	require.NoError(t, err)
+
+	// Verify entry has no published_at
+	entries, err := repo.List(ctx, repository.EntryListFilter{FeedID: &feedID})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Nil(t, entries[0].PublishedAt)
+
+	// Update the same entry with a published_at
+	newTime := time.Date(2025, 6, 15, 12, 0, 0, 0, time.UTC)
+	updatedEntry := model.Entry{
+		FeedID:      feedID,
+		Title:       &title,
+		URL:         &url,
+		Hash:        hashString(url),
+		PublishedAt: &newTime,
+	}
+	err = repo.CreateOrUpdate(ctx, updatedEntry, 0)
+	require.NoError(t, err)
+
+	// Verify that the new published_at is set
+	entries, err = repo.List(ctx, repository.EntryListFilter{FeedID: &feedID})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.NotNil(t, entries[0].PublishedAt)
+	require.Equal(t, newTime.Format(time.RFC3339), entries[0].PublishedAt.UTC().Format(time.RFC3339))
+}
+
+func TestEntryRepository_CreateOrUpdate_PublishedAtSource(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	repo := repository.NewEntryRepository(db, nil, nil)
+	ctx := context.Background()
+
+	feedID := testutil.SeedFeed(t, db, model.Feed{Title: "Test Feed", URL: "url"})
+	source := func(url string) *string {
+		var value *string
+		require.NoError(t, db.QueryRow(`SELECT published_at_source FROM entries WHERE hash = ?`, hashString(url)).Scan(&value))
+		return value
+	}
+	published := time.Date(2025, 6, 15, 12, 0, 0, 0, time.UTC)
+
+	dated := "https://example.com/dated"
+	require.NoError(t, repo.CreateOrUpdate(ctx, model.Entry{FeedID: feedID, URL: &dated, Hash: hashString(dated), PublishedAt: &published, PublishedAtSource: model.PublishedAtFromFetch}, 0))
+	require.Equal(t, model.PublishedAtFromFetch, *source(dated))
+
+	// The source stays with the stored date
+	require.NoError(t, repo.CreateOrUpdate(ctx, model.Entry{FeedID: feedID, URL: &dated, Hash: hashString(dated), Title: stringPtr("Changed"), PublishedAt: &published, PublishedAtSource: model.PublishedAtFromFeed}, 0))
+	require.Equal(t, model.PublishedAtFromFetch, *source(dated))
+
+	undated := "https://example.com/undated"
+	require.NoError(t, repo.CreateOrUpdate(ctx, model.Entry{FeedID: feedID, URL: &undated, Hash: hashString(undated)}, 0))
+	require.Nil(t, source(undated))
+	require.NoError(t, repo.CreateOrUpdate(ctx, model.Entry{FeedID: feedID, URL: &undated, Hash: hashString(undated), Title: stringPtr("Changed"), PublishedAt: &published, PublishedAtSource: model.PublishedAtFromSummary}, 0))
+	require.Equal(t, model.PublishedAtFromSummary, *source(undated))
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
+
+func hashString(input string) string {
+	sum := sha256.Sum256([]byte(strings.TrimSpace(input)))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestEntryRepository_CreateOrUpdate_StoresRevisionsWhenContentChanges(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	repo := repository.NewEntryRepository(db, nil, nil)
+	ctx := context.Background()
+
+	feedID := testutil.SeedFeed(t, db, model.Feed{Title: "Test Feed", URL: "url"})
+
+	url := "https://example.com/edited"
+	save := func(content string) {
+		t.Helper()
+		c := content
+		require.NoError(t, repo.CreateOrUpdate(ctx, model.Entry{
+			FeedID:  feedID,
+			URL:     &url,
+			Content: &c,
+			Hash:    hashString(url),
+		}, 3))
+	}
+
+	save("v1")
+	save("v1") // Unchanged content must not create a revision
+
+	entries, err := repo.List(ctx, repository.EntryListFilter{FeedID: &feedID})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	entryID := entries[0].ID
+
+	revisions, err := repo.ListRevisions(ctx, entryID)
+	require.NoError(t, err)
+	require.Empty(t, revisions)
+
+	for _, content := range []string{"v2", "v3", "v4", "v5"} {
+		save(content)
+	}
+
+	// Capped at 3, oldest (v1) pruned, newest first
+	revisions, err = repo.ListRevisions(ctx, entryID)
+	require.NoError(t, err)
+	require.Len(t, revisions, 3)
+	require.Equal(t, "v4", *revisions[0].Content)
+	require.Equal(t, "v3", *revisions[1].Content)
+	require.Equal(t, "v2", *revisions[2].Content)
+
+	entry, err := repo.GetByID(ctx, entryID)
+	require.NoError(t, err)
+	require.Equal(t, "v5", *entry.Content)
+}
+
+func TestEntryRepository_CreateOrUpdate_RevisionsDisabled(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	repo := repository.NewEntryRepository(db, nil, nil)
+	ctx := context.Background()
+
+	feedID := testutil.SeedFeed(t, db, model.Feed{Title: "Test Feed", URL: "url"})
+
+	url := "https://example.com/edited"
+	for _, content := range []string{"v1", "v2"} {
+		c := content
+		require.NoError(t, repo.CreateOrUpdate(ctx, model.Entry{
+			FeedID:  feedID,
+			URL:     &url,
+			Content: &c,
+			Hash:    hashString(url),
+		}, 0))
+	}
+
+	entries, err := repo.List(ctx, repository.EntryListFilter{FeedID: &feedID})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+
+	revisions, err := repo.ListRevisions(ctx, entries[0].ID)
+	require.NoError(t, err)
+	require.Empty(t, revisions)
+}
+
+func TestEntryRepository_SaveBatch_CountsNewAndUpdated(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	repo := repository.NewEntryRepository(db, nil, nil)
+	ctx := context.Background()
+
+	feedID := testutil.SeedFeed(t, db, model.Feed{Title: "Test Feed", URL: "url"})
+	existingURL := "https://example.com/existing"
+	legacyURL := "https://example.com/legacy"
+	newURL := "https://example.com/new"
+	testutil.SeedEntry(t, db, model.Entry{FeedID: feedID, URL: &existingURL, Hash: hashString(existingURL)})
+	testutil.SeedEntry(t, db, model.Entry{FeedID: feedID, URL: &legacyURL, Hash: hashString(legacyURL)})
+
+	newCount, updatedCount, err := repo.SaveBatch(ctx, feedID, []model.Entry{
+		{URL: &existingURL, Hash: hashString(existingURL)},
+		{URL: &legacyURL, Hash: hashString("guid-legacy")},
+		{URL: &newURL, Hash: hashString(newURL)},
+		// Repeated unchanged within the same batch
+		{URL: &newURL, Hash: hashString(newURL)},
+	}, 0)
+	require.NoError(t, err)
+	require.Equal(t, 1, newCount)
+	require.Equal(t, 2, updatedCount)
+
+	entries, err := repo.List(ctx, repository.EntryListFilter{FeedID: &feedID})
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	hashes := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		require.Equal(t, feedID, entry.FeedID)
+		hashes = append(hashes, entry.Hash)
+	}
+	require.ElementsMatch(t, []string{hashString(existingURL), hashString("guid-legacy"), hashString(newURL)}, hashes)
+}
+
+func TestEntryRepository_SaveBatch_RawItems(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	repo := repository.NewEntryRepository(db, nil, nil)
+	ctx := context.Background()
+
+	feedID := testutil.SeedFeed(t, db, model.Feed{Title: "Test Feed", URL: "url"})
+	keptURL := "https://example.com/kept"
+	plainURL := "https://example.com/plain"
+	first := `{"title":"Kept"}`
+	_, _, err := repo.SaveBatch(ctx, feedID, []model.Entry{
+		{URL: &keptURL, Hash: hashString(keptURL), RawItem: &first},
+		{URL: &plainURL, Hash: hashString(plainURL)},
+	}, 0)
+	require.NoError(t, err)
+
+	entries, err := repo.List(ctx, repository.EntryListFilter{FeedID: &feedID})
+	require.NoError(t, err)
+	ids := make(map[string]int64)
+	for _, entry := range entries {
+		ids[*entry.URL] = entry.ID
+	}
+
+	raw, err := repo.GetRawItem(ctx, ids[keptURL])
+	require.NoError(t, err)
+	require.Equal(t, first, *raw)
+	raw, err = repo.GetRawItem(ctx, ids[plainURL])
+	require.NoError(t, err)
+	require.Nil(t, raw)
+
+	// An unchanged entry still gets the item as last delivered
+	second := `{"title":"Kept","extensions":{}}`
+	_, _, err = repo.SaveBatch(ctx, feedID, []model.Entry{{URL: &keptURL, Hash: hashString(keptURL), RawItem: &second}}, 0)
+	require.NoError(t, err)
+	raw, err = repo.GetRawItem(ctx, ids[keptURL])
+	require.NoError(t, err)
+	require.Equal(t, second, *raw)
+
+	deleted, err := repo.DeleteRawItemsBefore(ctx, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	require.Zero(t, deleted)
+	deleted, err = repo.DeleteRawItemsBefore(ctx, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, int64(1), deleted)
+
+	// The entry itself stays
+	raw, err = repo.GetRawItem(ctx, ids[keptURL])
+	require.NoError(t, err)
+	require.Nil(t, raw)
+	_, err = repo.GetByID(ctx, ids[keptURL])
+	require.NoError(t, err)
+}
+
+func TestEntryRepository_UpdatedSinceAndTombstones(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	repo := repository.NewEntryRepository(db, nil, nil)
+	ctx := context.Background()
+
+	feedID := testutil.SeedFeed(t, db, model.Feed{Title: "Test Feed", URL: "https://example.com/feed"})
+	otherFeedID := testutil.SeedFeed(t, db, model.Feed{Title: "Other", URL: "https://example.com/other"})
+	oldID := testutil.SeedEntry(t, db, model.Entry{FeedID: feedID, Title: stringPtr("old")})
+	midID := testutil.SeedEntry(t, db, model.Entry{FeedID: feedID, Title: stringPtr("mid")})
+	newID := testutil.SeedEntry(t, db, model.Entry{FeedID: feedID, Title: stringPtr("new")})
+	otherID := testutil.SeedEntry(t, db, model.Entry{FeedID: otherFeedID, Title: stringPtr("other")})
+
+	// Mixed fractional precision, as formatTime writes it
+	for id, updatedAt := range map[int64]string{
+		oldID:   "2026-01-01T00:00:00Z",
+		midID:   "2026-01-01T00:00:00.5Z",
+		newID:   "2026-01-01T00:00:01Z",
+		otherID: "2026-01-01T00:00:01Z",
+	} {
+		_, err := db.Exec(`UPDATE entries SET updated_at = ? WHERE id = ?`, updatedAt, id)
+		require.NoError(t, err)
+	}
+
+	listSince := func(since time.Time) []int64 {
+		t.Helper()
+		entries, err := repo.List(ctx, repository.EntryListFilter{FeedID: &feedID, UpdatedSince: &since})
+		require.NoError(t, err)
+		ids := make([]int64, 0, len(entries))
+		for _, entry := range entries {
+			ids = append(ids, entry.ID)
+		}
+		return ids
+	}
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	require.ElementsMatch(t, []int64{oldID, midID, newID}, listSince(base))
+	require.ElementsMatch(t, []int64{midID, newID}, listSince(base.Add(250*time.Millisecond)))
+	require.ElementsMatch(t, []int64{newID}, listSince(base.Add(time.Second)))
+
+	// Deletes leave tombstones, whatever the path: a plain delete and a feed purge cascade
+	before := time.Now().Add(-time.Second)
+	_, err := db.Exec(`DELETE FROM entries WHERE id = ?`, oldID)
+	require.NoError(t, err)
+	_, err = db.Exec(`DELETE FROM feeds WHERE id = ?`, otherFeedID)
+	require.NoError(t, err)
+
+	ids, err := repo.ListTombstones(ctx, before, nil)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []int64{oldID, otherID}, ids)
+	ids, err = repo.ListTombstones(ctx, before, &feedID)
+	require.NoError(t, err)
+	require.Equal(t, []int64{oldID}, ids)
+	ids, err = repo.ListTombstones(ctx, time.Now().Add(time.Hour), nil)
+	require.NoError(t, err)
+	require.Empty(t, ids)
+
+	deleted, err := repo.DeleteTombstonesBefore(ctx, before)
+	require.NoError(t, err)
+	require.Zero(t, deleted)
+	deleted, err = repo.DeleteTombstonesBefore(ctx, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, int64(2), deleted)
+}
+
+func TestEntryRepository_SaveBatch_HalfLegacyURLs(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	repo := repository.NewEntryRepository(db, nil, nil)
+	ctx := context.Background()
+
+	feedID := testutil.SeedFeed(t, db, model.Feed{Title: "Test Feed", URL: "url"})
+	var batch []model.Entry
+	var wantHashes []string
+	for i := 0; i < 4; i++ {
+		// Stored before GUID hashing, keyed by URL with a fragment the feed no longer sends
+		storedURL := fmt.Sprintf("https://example.com/post/%d#comments", i)
+		testutil.SeedEntry(t, db, model.Entry{FeedID: feedID, URL: &storedURL, Hash: hashString(storedURL)})
+
+		legacyURL := fmt.Sprintf("https://example.com/post/%d", i)
+		newURL := fmt.Sprintf("https://example.com/new/%d", i)
+		legacyHash, newHash := hashString(fmt.Sprintf("guid-post-%d", i)), hashString(fmt.Sprintf("guid-new-%d", i))
+		batch = append(batch, model.Entry{URL: &legacyURL, Hash: legacyHash}, model.Entry{URL: &newURL, Hash: newHash})
+		wantHashes = append(wantHashes, legacyHash, newHash)
+	}
+
+	newCount, updatedCount, err := repo.SaveBatch(ctx, feedID, batch, 0)
+	require.NoError(t, err)
+	require.Equal(t, 4, newCount)
+	require.Equal(t, 4, updatedCount)
+
+	entries, err := repo.List(ctx, repository.EntryListFilter{FeedID: &feedID})
+	require.NoError(t, err)
+	hashes := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		hashes = append(hashes, entry.Hash)
+	}
+	require.ElementsMatch(t, wantHashes, hashes)
+}
+
+func TestEntryRepository_SaveBatch_SkipsUnchanged(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	repo := repository.NewEntryRepository(db, nil, nil)
+	ctx := context.Background()
+
+	feedID := testutil.SeedFeed(t, db, model.Feed{Title: "Test Feed", URL: "url"})
+	url := "https://example.com/1"
+	title, content := "Title", "Content"
+	entry := model.Entry{URL: &url, Hash: hashString(url), Title: &title, Content: &content}
+
+	newCount, updatedCount, err := repo.SaveBatch(ctx, feedID, []model.Entry{entry}, 0)
+	require.NoError(t, err)
+	require.Equal(t, [2]int{1, 0}, [2]int{newCount, updatedCount})
+	_, err = db.ExecContext(ctx, `UPDATE entries SET updated_at = '2020-01-01T00:00:00Z'`)
+	require.NoError(t, err)
+
+	newCount, updatedCount, err = repo.SaveBatch(ctx, feedID, []model.Entry{entry}, 0)
+	require.NoError(t, err)
+	require.Equal(t, [2]int{0, 0}, [2]int{newCount, updatedCount})
+	stored, err := repo.List(ctx, repository.EntryListFilter{FeedID: &feedID})
+	require.NoError(t, err)
+	require.Len(t, stored, 1)
+	require.Equal(t, time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), stored[0].UpdatedAt.UTC())
+
+	edited := "Edited"
+	entry.Content = &edited
+	newCount, updatedCount, err = repo.SaveBatch(ctx, feedID, []model.Entry{entry}, 0)
+	require.NoError(t, err)
+	require.Equal(t, [2]int{0, 1}, [2]int{newCount, updatedCount})
+	stored, err = repo.List(ctx, repository.EntryListFilter{FeedID: &feedID})
+	require.NoError(t, err)
+	require.Equal(t, edited, *stored[0].Content)
+	require.True(t, stored[0].UpdatedAt.After(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)))
+}
+
+func TestEntryRepository_SaveBatch_BackfillsLanguage(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	repo := repository.NewEntryRepository(db, nil, nil)
+	ctx := context.Background()
+
+	feedID := testutil.SeedFeed(t, db, model.Feed{Title: "Test Feed", URL: "url"})
+	url := "https://example.com/1"
+	title := "Title"
+	entry := model.Entry{URL: &url, Hash: hashString(url), Title: &title, Language: "en"}
+
+	_, _, err := repo.SaveBatch(ctx, feedID, []model.Entry{entry}, 0)
+	require.NoError(t, err)
+	// As stored before languages were detected
+	_, err = db.ExecContext(ctx, `UPDATE entries SET language = NULL, updated_at = '2020-01-01T00:00:00Z'`)
+	require.NoError(t, err)
+
+	newCount, updatedCount, err := repo.SaveBatch(ctx, feedID, []model.Entry{entry}, 0)
+	require.NoError(t, err)
+	require.Equal(t, [2]int{0, 0}, [2]int{newCount, updatedCount})
+	stored, err := repo.List(ctx, repository.EntryListFilter{FeedID: &feedID})
+	require.NoError(t, err)
+	require.Len(t, stored, 1)
+	require.Equal(t, "en", stored[0].Language)
+	require.Equal(t, time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), stored[0].UpdatedAt.UTC())
+
+	// A detected language is only replaced along with changed content
+	entry.Language = "de"
+	_, _, err = repo.SaveBatch(ctx, feedID, []model.Entry{entry}, 0)
+	require.NoError(t, err)
+	stored, err = repo.List(ctx, repository.EntryListFilter{FeedID: &feedID})
+	require.NoError(t, err)
+	require.Equal(t, "en", stored[0].Language)
+}
+
+func TestEntryRepository_SaveBatch_LargeBatch(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	repo := repository.NewEntryRepository(db, nil, nil)
+	ctx := context.Background()
+
+	feedID := testutil.SeedFeed(t, db, model.Feed{Title: "Test Feed", URL: "url"})
+	entries := make([]model.Entry, 600)
+	for i := range entries {
+		url := fmt.Sprintf("https://example.com/%d", i)
+		entries[i] = model.Entry{URL: &url, Hash: hashString(url)}
+	}
+
+	newCount, updatedCount, err := repo.SaveBatch(ctx, feedID, entries, 0)
+	require.NoError(t, err)
+	require.Equal(t, 600, newCount)
+	require.Zero(t, updatedCount)
+
+	// Second pass spans more than one hash lookup chunk
+	content := "edited"
+	for i := range entries {
+		entries[i].Content = &content
+	}
+	newCount, updatedCount, err = repo.SaveBatch(ctx, feedID, entries, 0)
+	require.NoError(t, err)
+	require.Zero(t, newCount)
+	require.Equal(t, 600, updatedCount)
+}
+
+func TestEntryRepository_SaveBatch_RollsBackOnError(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	repo := repository.NewEntryRepository(db, nil, nil)
+	ctx := context.Background()
+
+	url := "https://example.com/entry"
+	// Unknown feed violates the foreign key
+	_, _, err := repo.SaveBatch(ctx, 999, []model.Entry{{URL: &url, Hash: hashString(url)}}, 0)
+	require.Error(t, err)
+
+	var count int
+	require.NoError(t, db.QueryRowContext(ctx, `SELECT COUNT(*) FROM entries`).Scan(&count))
+	require.Zero(t, count)
+}
+
+func TestEntryRepository_SaveBatch_ConcurrentFeeds(t *testing.T) {
+	db := testutil.NewTestFileDB(t)
+	repo := repository.NewEntryRepository(db, nil, nil)
+	ctx := context.Background()
+
+	const feeds, perFeed = 8, 150
+	feedIDs := make([]int64, feeds)
+	for i := range feedIDs {
+		feedIDs[i] = testutil.SeedFeed(t, db, model.Feed{Title: fmt.Sprintf("Feed %d", i), URL: fmt.Sprintf("https://example.com/%d/rss", i)})
+	}
+
+	batch := func(feed int, content string) []model.Entry {
+		entries := make([]model.Entry, perFeed)
+		for i := range entries {
+			url := fmt.Sprintf("https://example.com/%d/%d", feed, i)
+			entries[i] = model.Entry{URL: &url, Hash: hashString(url), Content: &content}
+		}
+		return entries
+	}
+
+	for _, wantNew := range []bool{true, false} {
+		var wg sync.WaitGroup
+		errs := make([]error, feeds)
+		counts := make([][2]int, feeds)
+		for i := range feedIDs {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				newCount, updatedCount, err := repo.SaveBatch(ctx, feedIDs[i], batch(i, fmt.Sprint(wantNew)), 0)
+				errs[i] = err
+				counts[i] = [2]int{newCount, updatedCount}
+			}(i)
+		}
+		wg.Wait()
+
+		for i := range feedIDs {
+			require.NoError(t, errs[i])
+			if wantNew {
+				require.Equal(t, [2]int{perFeed, 0}, counts[i])
+			} else {
+				require.Equal(t, [2]int{0, perFeed}, counts[i])
+			}
+		}
+	}
+
+	var count int
+	require.NoError(t, db.QueryRowContext(ctx, `SELECT COUNT(*) FROM entries`).Scan(&count))
+	require.Equal(t, feeds*perFeed, count)
+}
+
+func TestEntryRepository_ListForBackup(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	repo := repository.NewEntryRepository(db, nil, nil)
+	ctx := context.Background()
+
+	feedID := testutil.SeedFeed(t, db, model.Feed{Title: "Feed", URL: "https://example.com/feed"})
+	deletedFeedID := testutil.SeedFeed(t, db, model.Feed{Title: "Gone", URL: "https://example.com/gone"})
+	_, err := db.Exec(`UPDATE feeds SET deleted_at = ? WHERE id = ?`, time.Now().UTC().Format(time.RFC3339), deletedFeedID)
+	require.NoError(t, err)
+
+	unreadID := testutil.SeedEntry(t, db, model.Entry{FeedID: feedID, Hash: "unread"})
+	readID := testutil.SeedEntry(t, db, model.Entry{FeedID: feedID, Hash: "read", Read: true})
+	starredID := testutil.SeedEntry(t, db, model.Entry{FeedID: feedID, Hash: "starred", Read: true, Starred: true})
+	testutil.SeedEntry(t, db, model.Entry{FeedID: deletedFeedID, Hash: "orphan"})
+
+	ids := func(entries []model.Entry) []int64 {
+		result := make([]int64, 0, len(entries))
+		for _, entry := range entries {
+			result = append(result, entry.ID)
+		}
+		return result
+	}
+
+	entries, err := repo.ListForBackup(ctx, false, 0, 10)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []int64{unreadID, starredID}, ids(entries))
+
+	entries, err = repo.ListForBackup(ctx, true, 0, 10)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []int64{unreadID, readID, starredID}, ids(entries))
+
+	first, err := repo.ListForBackup(ctx, true, 0, 1)
+	require.NoError(t, err)
+	require.Len(t, first, 1)
+	rest, err := repo.ListForBackup(ctx, true, first[0].ID, 10)
+	require.NoError(t, err)
+	require.Len(t, rest, 2)
+	for _, entry := range rest {
+		require.Greater(t, entry.ID, first[0].ID)
+	}
+}
+
+func TestEntryRepository_ImportBatch(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	repo := repository.NewEntryRepository(db, nil, nil)
+	ctx := context.Background()
+
+	feedID := testutil.SeedFeed(t, db, model.Feed{Title: "Feed", URL: "https://example.com/feed"})
+	content := "<p>original</p>"
+	existingID := testutil.SeedEntry(t, db, model.Entry{FeedID: feedID, Hash: "existing", Content: &content})
+	testutil.SeedEntry(t, db, model.Entry{FeedID: feedID, Hash: "same", Read: true})
+
+	title := "New"
+	published := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	replaced := "<p>replaced</p>"
+	batch := []model.Entry{
+		{FeedID: feedID, Hash: "new", Title: &title, PublishedAt: &published, Starred: true, WordCount: 42},
+		{FeedID: feedID, Hash: "existing", Content: &replaced, Read: true, Starred: true},
+		{FeedID: feedID, Hash: "same", Read: true},
+	}
+
+	created, updated, skipped, err := repo.ImportBatch(ctx, batch)
+	require.NoError(t, err)
+	require.Equal(t, 1, created)
+	require.Equal(t, 1, updated)
+	require.Equal(t, 1, skipped)
+
+	existing, err := repo.GetByID(ctx, existingID)
+	require.NoError(t, err)
+	require.True(t, existing.Read)
+	require.True(t, existing.Starred)
+	require.Equal(t, content, *existing.Content)
+
+	entries, err := repo.ListForBackup(ctx, true, 0, 10)
+	require.NoError(t, err)
+	var imported *model.Entry
+	for i := range entries {
+		if entries[i].Hash == "new" {
+			imported = &entries[i]
+		}
+	}
+	require.NotNil(t, imported)
+	require.Equal(t, title, *imported.Title)
+	require.True(t, imported.Starred)
+	require.Equal(t, 42, imported.WordCount)
+	require.True(t, published.Equal(*imported.PublishedAt))
+
+	created, updated, skipped, err = repo.ImportBatch(ctx, batch)
+	require.NoError(t, err)
+	require.Equal(t, 0, created)
+	require.Equal(t, 0, updated)
+	require.Equal(t, 3, skipped)
+}
+
+func TestEntryRepository_Notes(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	repo := repository.NewEntryRepository(db, nil, nil)
+	ctx := context.Background()
+
+	feedID := testutil.SeedFeed(t, db, model.Feed{Title: "Feed", URL: "https://example.com/feed"})
+	annotatedID := testutil.SeedEntry(t, db, model.Entry{FeedID: feedID, Hash: "annotated", Read: true})
+	plainID := testutil.SeedEntry(t, db, model.Entry{FeedID: feedID, Hash: "plain", Read: true})
+
+	require.NoError(t, repo.SetNote(ctx, annotatedID, "first"))
+	require.NoError(t, repo.SetNote(ctx, annotatedID, "cite this in the Q3 report"))
+
+	entry, err := repo.GetByID(ctx, annotatedID)
+	require.NoError(t, err)
+	require.Equal(t, "cite this in the Q3 report", *entry.Note)
+	entry, err = repo.GetByID(ctx, plainID)
+	require.NoError(t, err)
+	require.Nil(t, entry.Note)
+
+	entries, err := repo.List(ctx, repository.EntryListFilter{NotesOnly: true})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, annotatedID, entries[0].ID)
+	require.Equal(t, "cite this in the Q3 report", *entries[0].Note)
+
+	// Read, unstarred entries are exported when they carry a note
+	backup, err := repo.ListForBackup(ctx, false, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, backup, 1)
+	require.Equal(t, "cite this in the Q3 report", *backup[0].Note)
+
+	require.NoError(t, repo.DeleteNote(ctx, annotatedID))
+	entries, err = repo.List(ctx, repository.EntryListFilter{NotesOnly: true})
+	require.NoError(t, err)
+	require.Empty(t, entries)
+
+	// Notes go with their entry
+	require.NoError(t, repo.SetNote(ctx, plainID, "gone soon"))
+	_, err = db.Exec(`DELETE FROM entries WHERE id = ?`, plainID)
+	require.NoError(t, err)
+	var count int
+	require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM entry_notes`).Scan(&count))
+	require.Zero(t, count)
+}
+
+func TestEntryRepository_ImportBatch_Notes(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	repo := repository.NewEntryRepository(db, nil, nil)
+	ctx := context.Background()
+
+	feedID := testutil.SeedFeed(t, db, model.Feed{Title: "Feed", URL: "https://example.com/feed"})
+	keptID := testutil.SeedEntry(t, db, model.Entry{FeedID: feedID, Hash: "kept"})
+	bareID := testutil.SeedEntry(t, db, model.Entry{FeedID: feedID, Hash: "bare"})
+	require.NoError(t, repo.SetNote(ctx, keptID, "local"))
+
+	imported, local := "imported", "ignored"
+	batch := []model.Entry{
+		{FeedID: feedID, Hash: "kept", Note: &local},
+		{FeedID: feedID, Hash: "bare", Note: &imported},
+		{FeedID: feedID, Hash: "new", Note: &imported},
+	}
+	created, updated, skipped, err := repo.ImportBatch(ctx, batch)
+	require.NoError(t, err)
+	require.Equal(t, 1, created)
+	require.Equal(t, 1, updated)
+	require.Equal(t, 1, skipped)
+
+	kept, err := repo.GetByID(ctx, keptID)
+	require.NoError(t, err)
+	require.Equal(t, "local", *kept.Note)
+	bare, err := repo.GetByID(ctx, bareID)
+	require.NoError(t, err)
+	require.Equal(t, "imported", *bare.Note)
+	entries, err := repo.List(ctx, repository.EntryListFilter{NotesOnly: true})
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+
+	_, updated, skipped, err = repo.ImportBatch(ctx, batch)
+	require.NoError(t, err)
+	require.Equal(t, 0, updated)
+	require.Equal(t, 3, skipped)
+}
+
+func TestEntryRepository_IncludeFeed(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	repo := repository.NewEntryRepository(db, nil, nil)
+	ctx := context.Background()
+
+	iconPath := "icons/go.png"
+	feedID := testutil.SeedFeed(t, db, model.Feed{Title: "Go Blog", URL: "u1", IconPath: &iconPath, Type: "article"})
+	entryID := testutil.SeedEntry(t, db, model.Entry{FeedID: feedID, Title: stringPtr("E1")})
+
+	entries, err := repo.List(ctx, repository.EntryListFilter{})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Nil(t, entries[0].Feed)
+
+	entries, err = repo.List(ctx, repository.EntryListFilter{IncludeFeed: true, UnreadOnly: true, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, &model.EntryFeed{Title: "Go Blog", IconPath: &iconPath, Type: "article"}, entries[0].Feed)
+
+	entry, err := repo.GetByIDWithFeed(ctx, entryID)
+	require.NoError(t, err)
+	require.Equal(t, "E1", *entry.Title)
+	require.Equal(t, "Go Blog", entry.Feed.Title)
+
+	_, err = repo.GetByIDWithFeed(ctx, entryID+1)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestEntryRepository_List_FeedJoinUsesPrimaryKey(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	feedID := int64(1)
+	contentType := "article"
+
+	tests := []struct {
+		name   string
+		filter repository.EntryListFilter
+		// entryDriven filters start from an entries index and must reach feeds by id
+		entryDriven bool
+	}{
+		{name: "all", filter: repository.EntryListFilter{IncludeFeed: true}},
+		{name: "unread", filter: repository.EntryListFilter{IncludeFeed: true, UnreadOnly: true}, entryDriven: true},
+		{name: "feed", filter: repository.EntryListFilter{IncludeFeed: true, FeedID: &feedID}, entryDriven: true},
+		{name: "starred by type", filter: repository.EntryListFilter{IncludeFeed: true, ContentType: &contentType, StarredOnly: true}, entryDriven: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, args := repository.EntryListQuery(tt.filter)
+			rows, err := db.Query("EXPLAIN QUERY PLAN "+query, args...)
+			require.NoError(t, err)
+			defer rows.Close()
+
+			var plan []string
+			for rows.Next() {
+				var id, parent, notUsed int
+				var detail string
+				require.NoError(t, rows.Scan(&id, &parent, &notUsed, &detail))
+				plan = append(plan, detail)
+			}
+			require.NoError(t, rows.Err())
+
+			for _, step := range plan {
+				require.False(t, strings.HasPrefix(step, "SCAN e") || strings.HasPrefix(step, "SCAN f"), "full scan in plan: %v", plan)
+			}
+			if tt.entryDriven {
+				require.Contains(t, plan, "SEARCH f USING INTEGER PRIMARY KEY (rowid=?)", "plan: %v", plan)
+			}
+		})
+	}
+}
+
+func TestEntryRepository_GetByIDs(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	repo := repository.NewEntryRepository(db, nil, nil)
+	ctx := context.Background()
+
+	feedID := testutil.SeedFeed(t, db, model.Feed{Title: "Feed", URL: "u1"})
+	first := testutil.SeedEntry(t, db, model.Entry{FeedID: feedID, Title: stringPtr("E1")})
+	second := testutil.SeedEntry(t, db, model.Entry{FeedID: feedID, Title: stringPtr("E2")})
+	require.NoError(t, repo.SetNote(ctx, second, "remember"))
+
+	entries, err := repo.GetByIDs(ctx, []int64{second, second + 1000, first})
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	require.Equal(t, second, entries[0].ID)
+	require.Equal(t, "remember", *entries[0].Note)
+	require.Equal(t, first, entries[1].ID)
+
+	entries, err = repo.GetByIDs(ctx, nil)
+	require.NoError(t, err)
+	require.Empty(t, entries)
+}
+
+func TestEntryRepository_Search(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	repo := repository.NewEntryRepository(db, nil, nil)
+	ctx := context.Background()
+
+	parentID := testutil.SeedFolder(t, db, "Tech", nil, "article")
+	childID := testutil.SeedFolder(t, db, "Go", &parentID, "article")
+	otherFolderID := testutil.SeedFolder(t, db, "News", nil, "article")
+	parentFeed := testutil.SeedFeed(t, db, model.Feed{Title: "Parent", URL: "https://a.example.com/feed", FolderID: &parentID})
+	childFeed := testutil.SeedFeed(t, db, model.Feed{Title: "Child", URL: "https://b.example.com/feed", FolderID: &childID})
+	otherFeed := testutil.SeedFeed(t, db, model.Feed{Title: "Other", URL: "https://c.example.com/feed", FolderID: &otherFolderID})
+
+	inTitle := testutil.SeedEntry(t, db, model.Entry{FeedID: parentFeed, Title: stringPtr("Release notes"), Content: stringPtr("Nothing here"), Read: true})
+	inContent := testutil.SeedEntry(t, db, model.Entry{FeedID: childFeed, Title: stringPtr("Weekly"), Content: stringPtr("The release is out")})
+	elsewhere := testutil.SeedEntry(t, db, model.Entry{FeedID: otherFeed, Title: stringPtr("Release party"), Content: stringPtr("Cake")})
+	testutil.SeedEntry(t, db, model.Entry{FeedID: childFeed, Title: stringPtr("Unrelated"), Content: stringPtr("Nothing")})
+
+	ids := func(entries []model.Entry) []int64 {
+		var out []int64
+		for _, e := range entries {
+			out = append(out, e.ID)
+		}
+		return out
+	}
+
+	// The folder scope covers subfolders and leaves other folders out
+	entries, err := repo.Search(ctx, repository.EntrySearchFilter{Query: "release", FolderID: &parentID})
+	require.NoError(t, err)
+	require.ElementsMatch(t, []int64{inTitle, inContent}, ids(entries))
+
+	entries, err = repo.Search(ctx, repository.EntrySearchFilter{Query: "release", FolderID: &childID})
+	require.NoError(t, err)
+	require.Equal(t, []int64{inContent}, ids(entries))
+
+	entries, err = repo.Search(ctx, repository.EntrySearchFilter{Query: "release", FeedID: &otherFeed})
+	require.NoError(t, err)
+	require.Equal(t, []int64{elsewhere}, ids(entries))
+
+	entries, err = repo.Search(ctx, repository.EntrySearchFilter{Query: "release", Column: repository.EntrySearchColumnTitle})
+	require.NoError(t, err)
+	require.ElementsMatch(t, []int64{inTitle, elsewhere}, ids(entries))
+
+	entries, err = repo.Search(ctx, repository.EntrySearchFilter{Query: "RELEASE out", Column: repository.EntrySearchColumnContent})
+	require.NoError(t, err)
+	require.Equal(t, []int64{inContent}, ids(entries))
+
+	// FTS5 syntax in the text is matched literally instead of failing
+	entries, err = repo.Search(ctx, repository.EntrySearchFilter{Query: `release" OR "cake*`})
+	require.NoError(t, err)
+	require.Empty(t, entries)
+
+	entries, err = repo.Search(ctx, repository.EntrySearchFilter{Query: "release", IncludeFeed: true, Limit: 1, Offset: 1})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.NotNil(t, entries[0].Feed)
+}
+
+func TestEntryRepository_Search_BoostUnread(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	repo := repository.NewEntryRepository(db, nil, nil)
+	ctx := context.Background()
+
+	feedID := testutil.SeedFeed(t, db, model.Feed{Title: "Feed", URL: "https://example.com/feed"})
+	// A read entry matching a little better than an unread one
+	read := testutil.SeedEntry(t, db, model.Entry{FeedID: feedID, Title: stringPtr("Go go"), Content: stringPtr("about go tooling"), Read: true})
+	unread := testutil.SeedEntry(t, db, model.Entry{FeedID: feedID, Title: stringPtr("Go"), Content: stringPtr("about go tooling")})
+
+	entries, err := repo.Search(ctx, repository.EntrySearchFilter{Query: "go"})
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	require.Equal(t, read, entries[0].ID)
+
+	entries, err = repo.Search(ctx, repository.EntrySearchFilter{Query: "go", BoostUnread: true})
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	require.Equal(t, unread, entries[0].ID)
+}
+
+func TestEntryRepository_Delete(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	repo := repository.NewEntryRepository(db, nil, nil)
+	ctx := context.Background()
+
+	feedID := testutil.SeedFeed(t, db, model.Feed{Title: "Test Feed", URL: "https://example.com/feed"})
+	entryID := testutil.SeedEntry(t, db, model.Entry{FeedID: feedID, Title: stringPtr("saved")})
+	keptID := testutil.SeedEntry(t, db, model.Entry{FeedID: feedID, Title: stringPtr("kept")})
+	require.NoError(t, repo.SetNote(ctx, entryID, "for later"))
+
+	before := time.Now().Add(-time.Second)
+	require.NoError(t, repo.Delete(ctx, entryID))
+
+	_, err := repo.GetByID(ctx, entryID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+	_, err = repo.GetByID(ctx, keptID)
+	require.NoError(t, err)
+	var notes int
+	require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM entry_notes WHERE entry_id = ?`, entryID).Scan(&notes))
+	require.Zero(t, notes)
+	ids, err := repo.ListTombstones(ctx, before, nil)
+	require.NoError(t, err)
+	require.Equal(t, []int64{entryID}, ids)
+
+	require.ErrorIs(t, repo.Delete(ctx, entryID), sql.ErrNoRows)
 }
@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"gist/backend/pkg/plaintext"
+)
+
+// searchText is the row of an entry in entries_fts_ext.
+type searchText struct {
+	readable string
+	summary  string
+}
+
+// indexText turns stored HTML into the plain text entries_fts_ext matches.
+// Code blocks are left out rather than indexed as a placeholder.
+func indexText(content string) string {
+	return plaintext.FromHTML(content, plaintext.Options{DropCode: true})
+}
+
+// indexReadableText stores the text of an entry's readable content in
+// entries_fts_ext; empty content removes it.
+func indexReadableText(ctx context.Context, db dbtx, entryID int64, content string) error {
+	return updateSearchText(ctx, db, entryID, func(text *searchText) {
+		text.readable = indexText(content)
+	})
+}
+
+// indexSummaryText stores the text of every AI summary of the entry, in any
+// language, in entries_fts_ext.
+func indexSummaryText(ctx context.Context, db dbtx, entryID int64) error {
+	rows, err := db.QueryContext(ctx, `SELECT summary FROM ai_summaries WHERE entry_id = ? ORDER BY is_readability, language`, entryID)
+	if err != nil {
+		return err
+	}
+	var summaries []string
+	for rows.Next() {
+		var summary string
+		if err := rows.Scan(&summary); err != nil {
+			rows.Close()
+			return err
+		}
+		if text := indexText(summary); text != "" {
+			summaries = append(summaries, text)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	return updateSearchText(ctx, db, entryID, func(text *searchText) {
+		text.summary = strings.Join(summaries, "\n\n")
+	})
+}
+
+// updateSearchText applies update to the entries_fts_ext row of an entry.
+// The row is written only while the entry exists and has some text, so
+// entries_ad_ext never leaves one behind.
+func updateSearchText(ctx context.Context, db dbtx, entryID int64, update func(*searchText)) error {
+	var text searchText
+	err := db.QueryRowContext(ctx, `SELECT readable_text, summary_text FROM entries_fts_ext WHERE rowid = ?`, entryID).Scan(&text.readable, &text.summary)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+	if err == nil {
+		if _, err := db.ExecContext(ctx, `DELETE FROM entries_fts_ext WHERE rowid = ?`, entryID); err != nil {
+			return err
+		}
+	}
+
+	update(&text)
+	if text.readable == "" && text.summary == "" {
+		return nil
+	}
+	_, err = db.ExecContext(ctx,
+		`INSERT INTO entries_fts_ext (rowid, readable_text, summary_text)
+		 SELECT id, ?, ? FROM entries WHERE id = ?`,
+		text.readable, text.summary, entryID,
+	)
+	return err
+}
+
+// pruneSearchText removes entries_fts_ext rows left without any text.
+func pruneSearchText(ctx context.Context, db dbtx) error {
+	_, err := db.ExecContext(ctx, `DELETE FROM entries_fts_ext WHERE readable_text = '' AND summary_text = ''`)
+	return err
+}
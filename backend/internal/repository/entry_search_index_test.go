@@ -0,0 +1,108 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"gist/backend/internal/model"
+	"gist/backend/internal/repository"
+	"gist/backend/internal/repository/testutil"
+)
+
+func searchIDs(t *testing.T, repo repository.EntryRepository, filter repository.EntrySearchFilter) []int64 {
+	t.Helper()
+	entries, err := repo.Search(context.Background(), filter)
+	require.NoError(t, err)
+	var ids []int64
+	for _, e := range entries {
+		ids = append(ids, e.ID)
+	}
+	return ids
+}
+
+func TestEntryRepository_Search_ReadableContentAndSummaries(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	repo := repository.NewEntryRepository(db, nil, nil)
+	summaries := repository.NewAISummaryRepository(db, nil, nil)
+	ctx := context.Background()
+
+	feedID := testutil.SeedFeed(t, db, model.Feed{Title: "Feed", URL: "https://example.com/feed"})
+	readable := testutil.SeedEntry(t, db, model.Entry{FeedID: feedID, Title: stringPtr("Teaser"), Content: stringPtr("Read more")})
+	summarized := testutil.SeedEntry(t, db, model.Entry{FeedID: feedID, Title: stringPtr("Digest"), Content: stringPtr("Links")})
+	inTitle := testutil.SeedEntry(t, db, model.Entry{FeedID: feedID, Title: stringPtr("Quantum leap"), Content: stringPtr("Short")})
+
+	require.NoError(t, repo.UpdateReadableContent(ctx, readable, `<p>The <b>quantum</b> details</p><pre>secretcode()</pre>`, 3))
+	require.NoError(t, summaries.Save(ctx, summarized, false, "en", "A summary of quantum computing"))
+	require.NoError(t, summaries.Save(ctx, summarized, true, "zh-CN", "<p>量子 computing</p>"))
+
+	require.ElementsMatch(t, []int64{readable, summarized, inTitle}, searchIDs(t, repo, repository.EntrySearchFilter{Query: "quantum"}))
+	require.ElementsMatch(t, []int64{readable, summarized}, searchIDs(t, repo, repository.EntrySearchFilter{Query: "quantum", Column: repository.EntrySearchColumnContent}))
+	require.Equal(t, []int64{inTitle}, searchIDs(t, repo, repository.EntrySearchFilter{Query: "quantum", Column: repository.EntrySearchColumnTitle}))
+	// Every summary of the entry is indexed, and markup and code are not
+	require.Equal(t, []int64{summarized}, searchIDs(t, repo, repository.EntrySearchFilter{Query: "量子"}))
+	require.Empty(t, searchIDs(t, repo, repository.EntrySearchFilter{Query: "secretcode"}))
+	require.Empty(t, searchIDs(t, repo, repository.EntrySearchFilter{Query: "pre"}))
+
+	// Matching in both indexes ranks above matching in one
+	require.NoError(t, repo.UpdateReadableContent(ctx, inTitle, `<p>More quantum, quantum and quantum</p>`, 5))
+	require.Equal(t, inTitle, searchIDs(t, repo, repository.EntrySearchFilter{Query: "quantum"})[0])
+
+	// Rewriting one column keeps the other
+	require.NoError(t, summaries.Save(ctx, readable, false, "en", "Physics news"))
+	require.NoError(t, repo.UpdateReadableContent(ctx, readable, `<p>Updated text</p>`, 2))
+	require.Equal(t, []int64{readable}, searchIDs(t, repo, repository.EntrySearchFilter{Query: "physics"}))
+	require.Equal(t, []int64{readable}, searchIDs(t, repo, repository.EntrySearchFilter{Query: "updated"}))
+	require.Empty(t, searchIDs(t, repo, repository.EntrySearchFilter{Query: "details"}))
+
+	require.NoError(t, summaries.DeleteByEntryID(ctx, summarized))
+	require.Empty(t, searchIDs(t, repo, repository.EntrySearchFilter{Query: "computing"}))
+
+	require.NoError(t, repo.Delete(ctx, inTitle))
+	var rows int
+	require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM entries_fts_ext WHERE rowid = ?`, inTitle).Scan(&rows))
+	require.Zero(t, rows)
+
+	cleared, err := repo.ClearAllReadableContent(ctx)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, cleared)
+	require.Empty(t, searchIDs(t, repo, repository.EntrySearchFilter{Query: "updated"}))
+	require.Equal(t, []int64{readable}, searchIDs(t, repo, repository.EntrySearchFilter{Query: "physics"}))
+
+	_, err = summaries.DeleteAll(ctx)
+	require.NoError(t, err)
+	require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM entries_fts_ext`).Scan(&rows))
+	require.Zero(t, rows)
+}
+
+func TestEntryRepository_BackfillSearchIndex(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	repo := repository.NewEntryRepository(db, nil, nil)
+	ctx := context.Background()
+
+	feedID := testutil.SeedFeed(t, db, model.Feed{Title: "Feed", URL: "https://example.com/feed"})
+	readable := testutil.SeedEntry(t, db, model.Entry{FeedID: feedID, Title: stringPtr("One"), Content: stringPtr("Teaser")})
+	summarized := testutil.SeedEntry(t, db, model.Entry{FeedID: feedID, Title: stringPtr("Two"), Content: stringPtr("Teaser")})
+	testutil.SeedEntry(t, db, model.Entry{FeedID: feedID, Title: stringPtr("Three"), Content: stringPtr("Teaser")})
+
+	// Stored before the extended index was kept
+	_, err := db.Exec(`UPDATE entries SET readable_content = '<p>Archived article body</p>' WHERE id = ?`, readable)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO ai_summaries (id, entry_id, is_readability, language, summary, created_at) VALUES (1, ?, 0, 'en', 'Archived summary', '2026-01-01T00:00:00Z')`, summarized)
+	require.NoError(t, err)
+	require.Empty(t, searchIDs(t, repo, repository.EntrySearchFilter{Query: "archived"}))
+
+	indexed, err := repo.BackfillSearchIndex(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 2, indexed)
+	require.ElementsMatch(t, []int64{readable, summarized}, searchIDs(t, repo, repository.EntrySearchFilter{Query: "archived"}))
+
+	// Running it again rebuilds the same rows
+	indexed, err = repo.BackfillSearchIndex(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 2, indexed)
+	var rows int
+	require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM entries_fts_ext`).Scan(&rows))
+	require.Equal(t, 2, rows)
+}
@@ -6,3 +6,4 @@ var NullableString = nullableString
 var FormatTime = formatTime
 var ParseTime = parseTime
 var ParseTimePtr = parseTimePtr
+var EntryListQuery = entryListQuery
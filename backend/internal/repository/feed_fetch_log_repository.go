@@ -0,0 +1,138 @@
+//go:generate mockgen -source=$GOFILE -destination=mock/$GOFILE -package=mock
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"gist/backend/internal/model"
+	"gist/backend/pkg/snowflake"
+)
+
+// FeedFetchLogRepository stores the recent refresh attempts of each feed.
+type FeedFetchLogRepository interface {
+	// Append stores an attempt and trims the feed's history to its newest keep attempts.
+	Append(ctx context.Context, fetch model.FeedFetch, keep int) error
+	// List returns the feed's attempts, newest first.
+	List(ctx context.Context, feedID int64, limit int) ([]model.FeedFetch, error)
+	// ListStats totals the history of every live feed that has one.
+	ListStats(ctx context.Context) ([]model.FeedFetchStats, error)
+	// SaveIngestReport stores the ingest report of the feed's last refresh,
+	// replacing the previous one. A nil report removes it.
+	SaveIngestReport(ctx context.Context, feedID int64, report *string) error
+	// GetIngestReport returns the feed's last ingest report, nil when it has none.
+	GetIngestReport(ctx context.Context, feedID int64) (*string, error)
+}
+
+type feedFetchLogRepository struct {
+	db     *sql.DB
+	writes *WriteTracker
+}
+
+func NewFeedFetchLogRepository(db *sql.DB, writes *WriteTracker) FeedFetchLogRepository {
+	return &feedFetchLogRepository{db: db, writes: writes}
+}
+
+func (r *feedFetchLogRepository) Append(ctx context.Context, fetch model.FeedFetch, keep int) error {
+	return withTx(ctx, r.db, r.writes, func(tx dbtx) error {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO feed_fetch_log (id, feed_id, fetched_at, status_code, duration_ms, bytes, outcome)
+			VALUES (?, ?, ?, ?, ?, ?, ?)
+		`, snowflake.NextID(), fetch.FeedID, formatTime(fetch.FetchedAt), fetch.StatusCode, fetch.DurationMs, fetch.Bytes, fetch.Outcome); err != nil {
+			return err
+		}
+
+		// Snowflake IDs order attempts by when they were logged
+		if _, err := tx.ExecContext(ctx, `
+			DELETE FROM feed_fetch_log WHERE id IN (
+				SELECT id FROM (
+					SELECT id, ROW_NUMBER() OVER (ORDER BY id DESC) AS rn
+					FROM feed_fetch_log WHERE feed_id = ?
+				) WHERE rn > ?
+			)
+		`, fetch.FeedID, keep); err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
+
+func (r *feedFetchLogRepository) List(ctx context.Context, feedID int64, limit int) ([]model.FeedFetch, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, feed_id, fetched_at, status_code, duration_ms, bytes, outcome
+		FROM feed_fetch_log WHERE feed_id = ? ORDER BY id DESC LIMIT ?
+	`, feedID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var fetches []model.FeedFetch
+	for rows.Next() {
+		var f model.FeedFetch
+		var fetchedAt string
+		if err := rows.Scan(&f.ID, &f.FeedID, &fetchedAt, &f.StatusCode, &f.DurationMs, &f.Bytes, &f.Outcome); err != nil {
+			return nil, err
+		}
+		f.FetchedAt, _ = parseTime(fetchedAt)
+		fetches = append(fetches, f)
+	}
+	return fetches, rows.Err()
+}
+
+func (r *feedFetchLogRepository) ListStats(ctx context.Context) ([]model.FeedFetchStats, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT l.feed_id, COUNT(*),
+		       SUM(CASE WHEN l.outcome IN (?, ?) THEN 1 ELSE 0 END),
+		       SUM(CASE WHEN l.outcome NOT IN (?, ?) AND l.id > COALESCE(last_ok.id, 0) THEN 1 ELSE 0 END)
+		FROM feed_fetch_log l
+		INNER JOIN feeds f ON f.id = l.feed_id AND f.deleted_at IS NULL
+		LEFT JOIN (
+			SELECT feed_id, MAX(id) AS id FROM feed_fetch_log
+			WHERE outcome IN (?, ?) GROUP BY feed_id
+		) last_ok ON last_ok.feed_id = l.feed_id
+		GROUP BY l.feed_id
+	`, model.FeedFetchOK, model.FeedFetchNotModified,
+		model.FeedFetchOK, model.FeedFetchNotModified,
+		model.FeedFetchOK, model.FeedFetchNotModified)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var stats []model.FeedFetchStats
+	for rows.Next() {
+		var s model.FeedFetchStats
+		if err := rows.Scan(&s.FeedID, &s.Attempts, &s.Successes, &s.TrailingFailures); err != nil {
+			return nil, err
+		}
+		stats = append(stats, s)
+	}
+	return stats, rows.Err()
+}
+
+func (r *feedFetchLogRepository) SaveIngestReport(ctx context.Context, feedID int64, report *string) error {
+	if report == nil {
+		_, err := r.db.ExecContext(ctx, `DELETE FROM feed_ingest_reports WHERE feed_id = ?`, feedID)
+		return err
+	}
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO feed_ingest_reports (feed_id, report) VALUES (?, ?)
+		ON CONFLICT(feed_id) DO UPDATE SET report = excluded.report
+	`, feedID, *report)
+	return err
+}
+
+func (r *feedFetchLogRepository) GetIngestReport(ctx context.Context, feedID int64) (*string, error) {
+	var report string
+	err := r.db.QueryRowContext(ctx, `SELECT report FROM feed_ingest_reports WHERE feed_id = ?`, feedID).Scan(&report)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
@@ -0,0 +1,106 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gist/backend/internal/model"
+	"gist/backend/internal/repository"
+	"gist/backend/internal/repository/testutil"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFeedFetchLogRepository_AppendTrimsPerFeed(t *testing.T) {
+	t.Parallel()
+
+	db := testutil.NewTestDB(t)
+	repo := repository.NewFeedFetchLogRepository(db, nil)
+	ctx := context.Background()
+	feedID := testutil.SeedFeed(t, db, model.Feed{Title: "A", URL: "https://a.example.com/rss"})
+	otherID := testutil.SeedFeed(t, db, model.Feed{Title: "B", URL: "https://b.example.com/rss"})
+
+	start := time.Now().UTC().Truncate(time.Second)
+	for i := 0; i < 5; i++ {
+		require.NoError(t, repo.Append(ctx, model.FeedFetch{
+			FeedID:     feedID,
+			FetchedAt:  start.Add(time.Duration(i) * time.Minute),
+			StatusCode: 200 + i,
+			DurationMs: int64(10 * i),
+			Bytes:      100 * i,
+			Outcome:    model.FeedFetchOK,
+		}, 3))
+	}
+	require.NoError(t, repo.Append(ctx, model.FeedFetch{FeedID: otherID, FetchedAt: start, Outcome: model.FeedFetchNetworkError}, 3))
+
+	fetches, err := repo.List(ctx, feedID, 50)
+	require.NoError(t, err)
+	require.Len(t, fetches, 3)
+	require.Equal(t, 204, fetches[0].StatusCode, "newest first")
+	require.Equal(t, 202, fetches[2].StatusCode)
+	require.True(t, start.Add(4*time.Minute).Equal(fetches[0].FetchedAt))
+	require.EqualValues(t, 40, fetches[0].DurationMs)
+	require.Equal(t, 400, fetches[0].Bytes)
+
+	other, err := repo.List(ctx, otherID, 50)
+	require.NoError(t, err)
+	require.Len(t, other, 1, "trimming one feed leaves the others alone")
+}
+
+func TestFeedFetchLogRepository_ListStats(t *testing.T) {
+	t.Parallel()
+
+	db := testutil.NewTestDB(t)
+	repo := repository.NewFeedFetchLogRepository(db, nil)
+	ctx := context.Background()
+	flappingID := testutil.SeedFeed(t, db, model.Feed{Title: "Flapping", URL: "https://a.example.com/rss"})
+	brokenID := testutil.SeedFeed(t, db, model.Feed{Title: "Broken", URL: "https://b.example.com/rss"})
+	deletedID := testutil.SeedFeed(t, db, model.Feed{Title: "Deleted", URL: "https://c.example.com/rss"})
+	_, err := db.Exec(`UPDATE feeds SET deleted_at = ? WHERE id = ?`, time.Now().UTC().Format(time.RFC3339), deletedID)
+	require.NoError(t, err)
+
+	for _, outcome := range []string{model.FeedFetchOK, model.FeedFetchHTTPError, model.FeedFetchNotModified, model.FeedFetchNetworkError, model.FeedFetchParseError} {
+		require.NoError(t, repo.Append(ctx, model.FeedFetch{FeedID: flappingID, FetchedAt: time.Now(), Outcome: outcome}, 50))
+	}
+	for i := 0; i < 2; i++ {
+		require.NoError(t, repo.Append(ctx, model.FeedFetch{FeedID: brokenID, FetchedAt: time.Now(), Outcome: model.FeedFetchHTTPError}, 50))
+	}
+	require.NoError(t, repo.Append(ctx, model.FeedFetch{FeedID: deletedID, FetchedAt: time.Now(), Outcome: model.FeedFetchHTTPError}, 50))
+
+	stats, err := repo.ListStats(ctx)
+	require.NoError(t, err)
+	byFeed := make(map[int64]model.FeedFetchStats)
+	for _, s := range stats {
+		byFeed[s.FeedID] = s
+	}
+	require.Len(t, byFeed, 2, "deleted feeds are left out")
+	require.Equal(t, model.FeedFetchStats{FeedID: flappingID, Attempts: 5, Successes: 2, TrailingFailures: 2}, byFeed[flappingID])
+	require.Equal(t, model.FeedFetchStats{FeedID: brokenID, Attempts: 2, Successes: 0, TrailingFailures: 2}, byFeed[brokenID])
+}
+
+func TestFeedFetchLogRepository_IngestReport(t *testing.T) {
+	t.Parallel()
+
+	db := testutil.NewTestDB(t)
+	repo := repository.NewFeedFetchLogRepository(db, nil)
+	ctx := context.Background()
+	feedID := testutil.SeedFeed(t, db, model.Feed{Title: "A", URL: "https://a.example.com/rss"})
+
+	report, err := repo.GetIngestReport(ctx, feedID)
+	require.NoError(t, err)
+	require.Nil(t, report)
+
+	first, second := `{"items":3}`, `{"items":5}`
+	require.NoError(t, repo.SaveIngestReport(ctx, feedID, &first))
+	require.NoError(t, repo.SaveIngestReport(ctx, feedID, &second))
+	report, err = repo.GetIngestReport(ctx, feedID)
+	require.NoError(t, err)
+	require.Equal(t, second, *report)
+
+	// A clean refresh clears the report
+	require.NoError(t, repo.SaveIngestReport(ctx, feedID, nil))
+	report, err = repo.GetIngestReport(ctx, feedID)
+	require.NoError(t, err)
+	require.Nil(t, report)
+}
@@ -0,0 +1,181 @@
+//go:generate mockgen -source=$GOFILE -destination=mock/$GOFILE -package=mock
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"gist/backend/internal/db"
+	"gist/backend/internal/model"
+	"gist/backend/pkg/snowflake"
+)
+
+type FeedOverlapRepository interface {
+	// SampleSharedEntries compares the newest sample entries of a feed with
+	// every other live feed by hash and returns the feeds sharing any.
+	SampleSharedEntries(ctx context.Context, feedID int64, sample int) ([]model.FeedOverlapSample, error)
+	// Replace stores overlaps as the detected pairs in one transaction. Pairs
+	// detected before keep their ID and detection time; the rest are removed.
+	Replace(ctx context.Context, overlaps []model.FeedOverlap) error
+	// List returns the pairs whose feeds are both live, the largest overlap first.
+	List(ctx context.Context) ([]model.FeedOverlap, error)
+	GetByID(ctx context.Context, id int64) (model.FeedOverlap, error)
+	// Merge moves the entries of fromFeedID onto toFeedID, merging the ones
+	// both have, and moves fromFeedID to the trash, in one transaction.
+	// Returns how many entries were merged into existing ones.
+	Merge(ctx context.Context, fromFeedID, toFeedID int64) (int, error)
+}
+
+type feedOverlapRepository struct {
+	db      dbtx
+	changes *ChangeTracker
+	writes  *WriteTracker
+}
+
+func NewFeedOverlapRepository(db dbtx, changes *ChangeTracker, writes *WriteTracker) FeedOverlapRepository {
+	return &feedOverlapRepository{db: db, changes: changes, writes: writes}
+}
+
+const feedOverlapColumns = `o.id, o.feed_id, o.other_feed_id, o.same_site, o.shared_entries, o.overlap_ratio, o.detected_at`
+
+func (r *feedOverlapRepository) SampleSharedEntries(ctx context.Context, feedID int64, sample int) ([]model.FeedOverlapSample, error) {
+	// The sample comes off idx_entries_feed_id and each of its hashes is
+	// looked up through idx_entries_hash, so the cost is bounded by sample
+	rows, err := r.db.QueryContext(ctx, `
+		WITH recent AS (
+			SELECT hash FROM entries WHERE feed_id = ? ORDER BY id DESC LIMIT ?
+		)
+		SELECT o.feed_id, COUNT(DISTINCT o.hash), (SELECT COUNT(*) FROM recent)
+		FROM recent r
+		JOIN entries o ON o.hash = r.hash AND o.feed_id <> ?
+		JOIN feeds f ON f.id = o.feed_id AND f.deleted_at IS NULL
+		GROUP BY o.feed_id
+	`, feedID, sample, feedID)
+	if err != nil {
+		return nil, fmt.Errorf("sample shared entries: %w", err)
+	}
+	defer rows.Close()
+
+	var samples []model.FeedOverlapSample
+	for rows.Next() {
+		s := model.FeedOverlapSample{FeedID: feedID}
+		if err := rows.Scan(&s.OtherFeedID, &s.Shared, &s.Sampled); err != nil {
+			return nil, fmt.Errorf("scan shared entries: %w", err)
+		}
+		samples = append(samples, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate shared entries: %w", err)
+	}
+	return samples, nil
+}
+
+func (r *feedOverlapRepository) Replace(ctx context.Context, overlaps []model.FeedOverlap) error {
+	defer r.changes.Notify()
+
+	now := formatTime(time.Now())
+	return withTx(ctx, r.db, r.writes, func(tx dbtx) error {
+		pairs := make([]string, 0, len(overlaps))
+		args := make([]interface{}, 0, 2*len(overlaps))
+		for _, overlap := range overlaps {
+			if _, err := tx.ExecContext(ctx, `
+				INSERT INTO feed_overlaps (id, feed_id, other_feed_id, same_site, shared_entries, overlap_ratio, detected_at)
+				VALUES (?, ?, ?, ?, ?, ?, ?)
+				ON CONFLICT(feed_id, other_feed_id) DO UPDATE SET
+					same_site = excluded.same_site,
+					shared_entries = excluded.shared_entries,
+					overlap_ratio = excluded.overlap_ratio
+			`, snowflake.NextID(), overlap.FeedID, overlap.OtherFeedID, overlap.SameSite, overlap.SharedEntries, overlap.OverlapRatio, now); err != nil {
+				return fmt.Errorf("save feed overlap: %w", err)
+			}
+			pairs = append(pairs, "(?, ?)")
+			args = append(args, overlap.FeedID, overlap.OtherFeedID)
+		}
+
+		query := `DELETE FROM feed_overlaps`
+		if len(pairs) > 0 {
+			query += ` WHERE (feed_id, other_feed_id) NOT IN (VALUES ` + strings.Join(pairs, ", ") + `)`
+		}
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return fmt.Errorf("delete stale feed overlaps: %w", err)
+		}
+		return nil
+	})
+}
+
+func (r *feedOverlapRepository) List(ctx context.Context) ([]model.FeedOverlap, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+feedOverlapColumns+`
+		FROM feed_overlaps o
+		JOIN feeds a ON a.id = o.feed_id AND a.deleted_at IS NULL
+		JOIN feeds b ON b.id = o.other_feed_id AND b.deleted_at IS NULL
+		ORDER BY o.overlap_ratio DESC, o.shared_entries DESC, o.id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("list feed overlaps: %w", err)
+	}
+	defer rows.Close()
+
+	var overlaps []model.FeedOverlap
+	for rows.Next() {
+		overlap, err := scanFeedOverlap(rows)
+		if err != nil {
+			return nil, err
+		}
+		overlaps = append(overlaps, overlap)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate feed overlaps: %w", err)
+	}
+	return overlaps, nil
+}
+
+func (r *feedOverlapRepository) GetByID(ctx context.Context, id int64) (model.FeedOverlap, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT `+feedOverlapColumns+` FROM feed_overlaps o WHERE o.id = ?`, id)
+	return scanFeedOverlap(row)
+}
+
+func (r *feedOverlapRepository) Merge(ctx context.Context, fromFeedID, toFeedID int64) (int, error) {
+	defer r.changes.Notify()
+
+	now := formatTime(time.Now())
+	var merged int
+	err := withTx(ctx, r.db, r.writes, func(tx dbtx) error {
+		// Delta lists pick up the moved entries and the ones taking over state
+		if _, err := tx.ExecContext(ctx, `
+			UPDATE entries SET updated_at = ?
+			WHERE feed_id = ? OR (feed_id = ? AND hash IN (SELECT hash FROM entries WHERE feed_id = ?))
+		`, now, fromFeedID, toFeedID, fromFeedID); err != nil {
+			return fmt.Errorf("touch merged entries: %w", err)
+		}
+		var err error
+		if merged, err = db.MergeFeedEntries(ctx, tx, fromFeedID, toFeedID); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, `UPDATE feeds SET deleted_at = ?, updated_at = ? WHERE id = ? AND deleted_at IS NULL`, now, now, fromFeedID); err != nil {
+			return fmt.Errorf("delete merged feed: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx, `DELETE FROM feed_overlaps WHERE feed_id = ? OR other_feed_id = ?`, fromFeedID, fromFeedID); err != nil {
+			return fmt.Errorf("delete merged feed overlaps: %w", err)
+		}
+		return nil
+	})
+	return merged, err
+}
+
+func scanFeedOverlap(row interface {
+	Scan(dest ...interface{}) error
+}) (model.FeedOverlap, error) {
+	var overlap model.FeedOverlap
+	var detectedAt string
+	if err := row.Scan(&overlap.ID, &overlap.FeedID, &overlap.OtherFeedID, &overlap.SameSite, &overlap.SharedEntries, &overlap.OverlapRatio, &detectedAt); err != nil {
+		return model.FeedOverlap{}, fmt.Errorf("scan feed overlap: %w", err)
+	}
+	var err error
+	if overlap.DetectedAt, err = parseTime(detectedAt); err != nil {
+		return model.FeedOverlap{}, fmt.Errorf("parse feed overlap detected_at: %w", err)
+	}
+	return overlap, nil
+}
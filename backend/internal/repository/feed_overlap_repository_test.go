@@ -0,0 +1,128 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"gist/backend/internal/model"
+	"gist/backend/internal/repository"
+	"gist/backend/internal/repository/testutil"
+)
+
+func TestFeedOverlapRepository_SampleSharedEntries(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	repo := repository.NewFeedOverlapRepository(db, nil, nil)
+	ctx := context.Background()
+
+	feedA := testutil.SeedFeed(t, db, model.Feed{Title: "A", URL: "https://example.com/feed"})
+	feedB := testutil.SeedFeed(t, db, model.Feed{Title: "B", URL: "https://example.com/atom"})
+	feedC := testutil.SeedFeed(t, db, model.Feed{Title: "C", URL: "https://other.com/feed"})
+	for _, url := range []string{"https://example.com/1", "https://example.com/2", "https://example.com/3"} {
+		testutil.SeedEntry(t, db, model.Entry{FeedID: feedA, URL: stringPtr(url)})
+		testutil.SeedEntry(t, db, model.Entry{FeedID: feedB, URL: stringPtr(url)})
+	}
+	testutil.SeedEntry(t, db, model.Entry{FeedID: feedA, URL: stringPtr("https://example.com/4")})
+	testutil.SeedEntry(t, db, model.Entry{FeedID: feedC, URL: stringPtr("https://other.com/1")})
+
+	samples, err := repo.SampleSharedEntries(ctx, feedA, 50)
+	require.NoError(t, err)
+	require.Equal(t, []model.FeedOverlapSample{{FeedID: feedA, OtherFeedID: feedB, Sampled: 4, Shared: 3}}, samples)
+
+	// Only the newest entries are sampled
+	samples, err = repo.SampleSharedEntries(ctx, feedA, 2)
+	require.NoError(t, err)
+	require.Equal(t, []model.FeedOverlapSample{{FeedID: feedA, OtherFeedID: feedB, Sampled: 2, Shared: 1}}, samples)
+
+	// Deleted feeds are not compared
+	_, err = db.Exec(`UPDATE feeds SET deleted_at = '2026-01-01T00:00:00Z' WHERE id = ?`, feedB)
+	require.NoError(t, err)
+	samples, err = repo.SampleSharedEntries(ctx, feedA, 50)
+	require.NoError(t, err)
+	require.Empty(t, samples)
+}
+
+func TestFeedOverlapRepository_Replace(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	repo := repository.NewFeedOverlapRepository(db, nil, nil)
+	ctx := context.Background()
+
+	feedA := testutil.SeedFeed(t, db, model.Feed{Title: "A", URL: "https://a.com/feed"})
+	feedB := testutil.SeedFeed(t, db, model.Feed{Title: "B", URL: "https://b.com/feed"})
+	feedC := testutil.SeedFeed(t, db, model.Feed{Title: "C", URL: "https://c.com/feed"})
+
+	require.NoError(t, repo.Replace(ctx, []model.FeedOverlap{
+		{FeedID: feedA, OtherFeedID: feedB, SharedEntries: 3, OverlapRatio: 0.6},
+		{FeedID: feedA, OtherFeedID: feedC, SameSite: true},
+	}))
+	overlaps, err := repo.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, overlaps, 2)
+	first := overlaps[0]
+	require.Equal(t, feedB, first.OtherFeedID)
+	require.Equal(t, 3, first.SharedEntries)
+	require.InDelta(t, 0.6, first.OverlapRatio, 1e-9)
+	require.True(t, overlaps[1].SameSite)
+
+	// Pairs detected again keep their ID and detection time; the others go
+	require.NoError(t, repo.Replace(ctx, []model.FeedOverlap{
+		{FeedID: feedA, OtherFeedID: feedB, SharedEntries: 5, OverlapRatio: 1},
+	}))
+	overlaps, err = repo.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, overlaps, 1)
+	require.Equal(t, first.ID, overlaps[0].ID)
+	require.True(t, first.DetectedAt.Equal(overlaps[0].DetectedAt))
+	require.Equal(t, 5, overlaps[0].SharedEntries)
+
+	got, err := repo.GetByID(ctx, first.ID)
+	require.NoError(t, err)
+	require.Equal(t, overlaps[0], got)
+
+	require.NoError(t, repo.Replace(ctx, nil))
+	overlaps, err = repo.List(ctx)
+	require.NoError(t, err)
+	require.Empty(t, overlaps)
+	_, err = repo.GetByID(ctx, first.ID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestFeedOverlapRepository_Merge(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	repo := repository.NewFeedOverlapRepository(db, nil, nil)
+	feeds := repository.NewFeedRepository(db, nil, nil)
+	ctx := context.Background()
+
+	keep := testutil.SeedFeed(t, db, model.Feed{Title: "Keep", URL: "https://example.com/feed"})
+	drop := testutil.SeedFeed(t, db, model.Feed{Title: "Drop", URL: "https://example.com/atom"})
+	kept := testutil.SeedEntry(t, db, model.Entry{FeedID: keep, URL: stringPtr("https://example.com/1")})
+	copied := testutil.SeedEntry(t, db, model.Entry{FeedID: drop, URL: stringPtr("https://example.com/1"), Read: true, Starred: true})
+	unique := testutil.SeedEntry(t, db, model.Entry{FeedID: drop, URL: stringPtr("https://example.com/2")})
+	require.NoError(t, repo.Replace(ctx, []model.FeedOverlap{{FeedID: keep, OtherFeedID: drop, SharedEntries: 1, OverlapRatio: 1}}))
+
+	merged, err := repo.Merge(ctx, drop, keep)
+	require.NoError(t, err)
+	require.Equal(t, 1, merged)
+
+	var feedID int64
+	var read, starred bool
+	require.NoError(t, db.QueryRow(`SELECT feed_id, read, starred FROM entries WHERE id = ?`, kept).Scan(&feedID, &read, &starred))
+	require.Equal(t, keep, feedID)
+	require.True(t, read)
+	require.True(t, starred)
+	require.NoError(t, db.QueryRow(`SELECT feed_id FROM entries WHERE id = ?`, unique).Scan(&feedID))
+	require.Equal(t, keep, feedID)
+	require.ErrorIs(t, db.QueryRow(`SELECT feed_id FROM entries WHERE id = ?`, copied).Scan(&feedID), sql.ErrNoRows)
+
+	// The merged feed goes to the trash with its overlaps
+	_, err = feeds.GetByID(ctx, drop)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+	overlaps, err := repo.List(ctx)
+	require.NoError(t, err)
+	require.Empty(t, overlaps)
+	var rows int
+	require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM feed_overlaps`).Scan(&rows))
+	require.Zero(t, rows)
+}
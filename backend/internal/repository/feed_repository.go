@@ -10,50 +10,135 @@ import (
 	"time"
 
 	"gist/backend/internal/model"
+	"gist/backend/internal/urlutil"
 	"gist/backend/pkg/snowflake"
 )
 
 type FeedRepository interface {
+	// Create returns ErrConflict when a feed, soft-deleted or not, already has
+	// the URL or its canonical form.
 	Create(ctx context.Context, feed model.Feed) (model.Feed, error)
 	GetByID(ctx context.Context, id int64) (model.Feed, error)
 	GetByIDs(ctx context.Context, ids []int64) ([]model.Feed, error)
 	FindByURL(ctx context.Context, url string) (*model.Feed, error)
 	List(ctx context.Context, folderID *int64) ([]model.Feed, error)
+	// ListWithUnreadCounts lists live feeds in List order with their unread
+	// counts, counted as EntryRepository.GetAllUnreadCounts does, in one query.
+	ListWithUnreadCounts(ctx context.Context) ([]model.FeedWithUnread, error)
 	ListWithoutIcon(ctx context.Context) ([]model.Feed, error)
+	// Update writes the feed's titles, folder, URLs, validators and settings in
+	// one statement; empty Type, DedupeKey and AutoTranslate get their defaults.
 	Update(ctx context.Context, feed model.Feed) (model.Feed, error)
+	// UpdateIconPath sets a fetched icon, clearing the icon source.
 	UpdateIconPath(ctx context.Context, id int64, iconPath string) error
+	// UpdateGeneratedIconPath sets a drawn fallback icon, marked model.IconSourceGenerated.
+	UpdateGeneratedIconPath(ctx context.Context, id int64, iconPath string) error
+	// UpdateConditionalGet stores the ETag and Last-Modified of the feed's last response.
+	UpdateConditionalGet(ctx context.Context, id int64, etag, lastModified *string) error
 	UpdateErrorMessage(ctx context.Context, id int64, errorMessage *string) error
 	UpdateType(ctx context.Context, id int64, feedType string) error
-	UpdateTypeByFolderID(ctx context.Context, folderID int64, feedType string) error
+	// UpdateAssumeTimezone sets the zone for dateless-zone items; nil falls back to the global setting.
+	UpdateAssumeTimezone(ctx context.Context, id int64, timezone *string) error
+	// UpdatePausedUntil sets when a paused feed resumes refreshing; nil unpauses it.
+	UpdatePausedUntil(ctx context.Context, id int64, until *time.Time) error
+	UpdateDedupeKey(ctx context.Context, id int64, dedupeKey string) error
+	// UpdateAutoTranslate sets the feed's FeedAutoTranslate* choice.
+	UpdateAutoTranslate(ctx context.Context, id int64, mode string) error
+	// UpdateUserAgent sets the feed's own User-Agent; nil inherits its folder's.
+	UpdateUserAgent(ctx context.Context, id int64, userAgent *string) error
+	// UpdateAutoReadability sets whether the feed's entries open in readability
+	// mode; nil inherits its folder's choice.
+	UpdateAutoReadability(ctx context.Context, id int64, enabled *bool) error
+	// UpdateLanguage stores the language the feed declares; "" clears it.
+	UpdateLanguage(ctx context.Context, id int64, language string) error
+	// UpdatePreferredUserAgent records which FeedUserAgent* choice to try first.
+	UpdatePreferredUserAgent(ctx context.Context, id int64, userAgent string) error
+	// UpdatePollHint stores the feed's own minimum refresh interval; 0 and an empty source clear it.
+	UpdatePollHint(ctx context.Context, id int64, seconds int, source string) error
+	UpdateLastFetchedAt(ctx context.Context, id int64, fetchedAt time.Time) error
+	// UpdateNotModifiedStreak stores how many refreshes in a row got 304 Not Modified.
+	UpdateNotModifiedStreak(ctx context.Context, id int64, streak int) error
+	// UpdateLastEntrySeenAt raises last_entry_seen_at to seenAt; an earlier seenAt changes nothing.
+	UpdateLastEntrySeenAt(ctx context.Context, id int64, seenAt time.Time) error
+	// UpdateSuspectCaching flags the feed's upstream as ignoring validators since at; nil clears it.
+	UpdateSuspectCaching(ctx context.Context, id int64, at *time.Time) error
+	// CountSuspectCaching counts live feeds flagged by UpdateSuspectCaching.
+	CountSuspectCaching(ctx context.Context) (int, error)
+	// PostingInterval returns the average gap between the published dates of the
+	// feed's newest sample entries, 0 when fewer than two have one.
+	PostingInterval(ctx context.Context, id int64, sample int) (time.Duration, error)
+	// Reorder sets sort_order to each feed's position in ids, in one transaction.
+	Reorder(ctx context.Context, ids []int64) error
+	// UpdateTypeByFolderIDs sets the type of every feed in the given folders.
+	UpdateTypeByFolderIDs(ctx context.Context, folderIDs []int64, feedType string) error
+	// Delete and DeleteBatch soft-delete feeds; entries stay until PurgeDeleted.
 	Delete(ctx context.Context, id int64) error
 	DeleteBatch(ctx context.Context, ids []int64) (int64, error)
+	// Restore undoes a soft delete made at or after deletedSince; returns sql.ErrNoRows otherwise.
+	Restore(ctx context.Context, id int64, deletedSince time.Time) error
+	// PurgeDeleted hard-deletes feeds soft-deleted before deletedBefore, cascading to their entries.
+	PurgeDeleted(ctx context.Context, deletedBefore time.Time) (int64, error)
 	ClearAllIconPaths(ctx context.Context) (int64, error)
 	ClearAllConditionalGet(ctx context.Context) (int64, error)
+	// UpdateTitles sets the feed's own title and its custom one, nil for none.
+	UpdateTitles(ctx context.Context, id int64, title string, customTitle *string) error
 	UpdateSiteURL(ctx context.Context, id int64, siteURL string) error
+	// UpdateIngestTokenHash replaces the hash of the feed's ingest token.
+	UpdateIngestTokenHash(ctx context.Context, id int64, tokenHash string) error
+	// GetIngestTokenHash returns the hash of a live feed's ingest token, or ""
+	// when it has none; sql.ErrNoRows when the feed doesn't exist.
+	GetIngestTokenHash(ctx context.Context, id int64) (string, error)
+	// ListMutedAuthors returns the authors muted in the feed, as entered, by name.
+	ListMutedAuthors(ctx context.Context, feedID int64) ([]string, error)
+	// MuteAuthor mutes author in the feed; authors match by NormalizeAuthor.
+	MuteAuthor(ctx context.Context, feedID int64, author string) error
+	// UnmuteAuthor returns sql.ErrNoRows when author is not muted in the feed.
+	UnmuteAuthor(ctx context.Context, feedID int64, author string) error
+	IsAuthorMuted(ctx context.Context, feedID int64, author string) (bool, error)
+	// GetActivityStats returns entry counts for every feed, including feeds without entries.
+	GetActivityStats(ctx context.Context) ([]model.FeedActivityStats, error)
 }
 
 type feedRepository struct {
-	db dbtx
+	db      dbtx
+	changes *ChangeTracker
+	writes  *WriteTracker
 }
 
-func NewFeedRepository(db dbtx) FeedRepository {
-	return &feedRepository{db: db}
+func NewFeedRepository(db dbtx, changes *ChangeTracker, writes *WriteTracker) FeedRepository {
+	return &feedRepository{db: db, changes: changes, writes: writes}
 }
 
 func (r *feedRepository) Create(ctx context.Context, feed model.Feed) (model.Feed, error) {
+	defer r.changes.Notify()
+
 	feed.ID = snowflake.NextID()
 	now := time.Now().UTC()
 	if feed.Type == "" {
 		feed.Type = "article"
 	}
+	var pausedUntil interface{}
+	if feed.PausedUntil != nil {
+		pausedUntil = formatTime(*feed.PausedUntil)
+	}
+	dedupeKey := feed.DedupeKey
+	if dedupeKey == "" {
+		dedupeKey = model.DedupeKeyAuto
+	}
+	var sortOrder int
+	if err := r.db.QueryRowContext(ctx, nextFeedSortOrder, nullableInt64(feed.FolderID)).Scan(&sortOrder); err != nil {
+		return model.Feed{}, fmt.Errorf("next feed sort order: %w", err)
+	}
 	_, err := r.db.ExecContext(
 		ctx,
-		`INSERT INTO feeds (id, folder_id, title, url, site_url, description, summary_prompt_reminder, type, etag, last_modified, error_message, created_at, updated_at)
-		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		`INSERT INTO feeds (id, folder_id, title, custom_title, url, canonical_url, site_url, description, summary_prompt_reminder, type, etag, last_modified, error_message, assume_timezone, paused_until, dedupe_key, sort_order, language, created_at, updated_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 		feed.ID,
 		nullableInt64(feed.FolderID),
 		feed.Title,
+		nullableString(feed.CustomTitle),
 		feed.URL,
+		urlutil.CanonicalFeedURL(feed.URL),
 		nullableString(feed.SiteURL),
 		nullableString(feed.Description),
 		nullableString(feed.SummaryPromptReminder),
@@ -61,19 +146,31 @@ func (r *feedRepository) Create(ctx context.Context, feed model.Feed) (model.Fee
 		nullableString(feed.ETag),
 		nullableString(feed.LastModified),
 		nullableString(feed.ErrorMessage),
+		nullableString(feed.AssumeTimezone),
+		pausedUntil,
+		dedupeKey,
+		sortOrder,
+		feed.Language,
 		formatTime(now),
 		formatTime(now),
 	)
 	if err != nil {
+		if isUniqueViolation(err) {
+			return model.Feed{}, ErrConflict
+		}
 		return model.Feed{}, fmt.Errorf("create feed: %w", err)
 	}
+	feed.DedupeKey = dedupeKey
+	feed.PreferredUserAgent = model.FeedUserAgentDefault
+	feed.AutoTranslate = model.FeedAutoTranslateInherit
+	feed.SortOrder = sortOrder
 	feed.CreatedAt = now
 	feed.UpdatedAt = now
 	return feed, nil
 }
 
 func (r *feedRepository) GetByID(ctx context.Context, id int64) (model.Feed, error) {
-	row := r.db.QueryRowContext(ctx, `SELECT id, folder_id, title, url, site_url, description, summary_prompt_reminder, icon_path, type, etag, last_modified, error_message, created_at, updated_at FROM feeds WHERE id = ?`, id)
+	row := r.db.QueryRowContext(ctx, `SELECT id, folder_id, title, custom_title, url, site_url, description, summary_prompt_reminder, icon_path, icon_source, type, etag, last_modified, error_message, assume_timezone, paused_until, dedupe_key, preferred_user_agent, sort_order, min_poll_seconds, min_poll_source, max_entries, auto_translate, user_agent, auto_readability, language, last_fetched_at, not_modified_streak, last_entry_seen_at, suspect_caching_at, created_at, updated_at, deleted_at FROM feeds WHERE id = ? AND deleted_at IS NULL`, id)
 	return scanFeed(row)
 }
 
@@ -86,7 +183,7 @@ func (r *feedRepository) GetByIDs(ctx context.Context, ids []int64) ([]model.Fee
 	for i, id := range ids {
 		args[i] = id
 	}
-	rows, err := r.db.QueryContext(ctx, `SELECT id, folder_id, title, url, site_url, description, summary_prompt_reminder, icon_path, type, etag, last_modified, error_message, created_at, updated_at FROM feeds WHERE id IN (`+placeholders+`)`, args...)
+	rows, err := r.db.QueryContext(ctx, `SELECT id, folder_id, title, custom_title, url, site_url, description, summary_prompt_reminder, icon_path, icon_source, type, etag, last_modified, error_message, assume_timezone, paused_until, dedupe_key, preferred_user_agent, sort_order, min_poll_seconds, min_poll_source, max_entries, auto_translate, user_agent, auto_readability, language, last_fetched_at, not_modified_streak, last_entry_seen_at, suspect_caching_at, created_at, updated_at, deleted_at FROM feeds WHERE id IN (`+placeholders+`) AND deleted_at IS NULL`, args...)
 	if err != nil {
 		return nil, fmt.Errorf("get feeds by ids: %w", err)
 	}
@@ -106,8 +203,10 @@ func (r *feedRepository) GetByIDs(ctx context.Context, ids []int64) ([]model.Fee
 	return feeds, nil
 }
 
+// FindByURL matches on the canonical form, so URLs differing only by tracking params or trailing slashes collide.
+// Soft-deleted feeds are included (with DeletedAt set) since they still hold the URL.
 func (r *feedRepository) FindByURL(ctx context.Context, url string) (*model.Feed, error) {
-	row := r.db.QueryRowContext(ctx, `SELECT id, folder_id, title, url, site_url, description, summary_prompt_reminder, icon_path, type, etag, last_modified, error_message, created_at, updated_at FROM feeds WHERE url = ?`, url)
+	row := r.db.QueryRowContext(ctx, `SELECT id, folder_id, title, custom_title, url, site_url, description, summary_prompt_reminder, icon_path, icon_source, type, etag, last_modified, error_message, assume_timezone, paused_until, dedupe_key, preferred_user_agent, sort_order, min_poll_seconds, min_poll_source, max_entries, auto_translate, user_agent, auto_readability, language, last_fetched_at, not_modified_streak, last_entry_seen_at, suspect_caching_at, created_at, updated_at, deleted_at FROM feeds WHERE canonical_url = ?`, urlutil.CanonicalFeedURL(url))
 	feed, err := scanFeed(row)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -119,10 +218,10 @@ func (r *feedRepository) FindByURL(ctx context.Context, url string) (*model.Feed
 }
 
 func (r *feedRepository) List(ctx context.Context, folderID *int64) ([]model.Feed, error) {
-	query := `SELECT id, folder_id, title, url, site_url, description, summary_prompt_reminder, icon_path, type, etag, last_modified, error_message, created_at, updated_at FROM feeds ORDER BY title`
+	query := `SELECT id, folder_id, title, custom_title, url, site_url, description, summary_prompt_reminder, icon_path, icon_source, type, etag, last_modified, error_message, assume_timezone, paused_until, dedupe_key, preferred_user_agent, sort_order, min_poll_seconds, min_poll_source, max_entries, auto_translate, user_agent, auto_readability, language, last_fetched_at, not_modified_streak, last_entry_seen_at, suspect_caching_at, created_at, updated_at, deleted_at FROM feeds WHERE deleted_at IS NULL ORDER BY sort_order, COALESCE(custom_title, title)`
 	args := []interface{}{}
 	if folderID != nil {
-		query = `SELECT id, folder_id, title, url, site_url, description, summary_prompt_reminder, icon_path, type, etag, last_modified, error_message, created_at, updated_at FROM feeds WHERE folder_id = ? ORDER BY title`
+		query = `SELECT id, folder_id, title, custom_title, url, site_url, description, summary_prompt_reminder, icon_path, icon_source, type, etag, last_modified, error_message, assume_timezone, paused_until, dedupe_key, preferred_user_agent, sort_order, min_poll_seconds, min_poll_source, max_entries, auto_translate, user_agent, auto_readability, language, last_fetched_at, not_modified_streak, last_entry_seen_at, suspect_caching_at, created_at, updated_at, deleted_at FROM feeds WHERE folder_id = ? AND deleted_at IS NULL ORDER BY sort_order, COALESCE(custom_title, title)`
 		args = append(args, *folderID)
 	}
 	rows, err := r.db.QueryContext(ctx, query, args...)
@@ -146,8 +245,39 @@ func (r *feedRepository) List(ctx context.Context, folderID *int64) ([]model.Fee
 	return feeds, nil
 }
 
+func (r *feedRepository) ListWithUnreadCounts(ctx context.Context) ([]model.FeedWithUnread, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT id, folder_id, title, custom_title, url, site_url, description, summary_prompt_reminder, icon_path, icon_source, type, etag, last_modified, error_message, assume_timezone, paused_until, dedupe_key, preferred_user_agent, sort_order, min_poll_seconds, min_poll_source, max_entries, auto_translate, user_agent, auto_readability, language, last_fetched_at, not_modified_streak, last_entry_seen_at, suspect_caching_at, created_at, updated_at, deleted_at,
+		       CASE WHEN paused_until IS NOT NULL AND julianday(paused_until) > julianday('now') THEN 0 ELSE COALESCE(u.unread, 0) END
+		FROM feeds
+		LEFT JOIN (
+			SELECT e.feed_id, COUNT(*) AS unread FROM entries e
+			WHERE e.read = 0 AND NOT `+mutedAuthorCondition+`
+			GROUP BY e.feed_id
+		) u ON u.feed_id = feeds.id
+		WHERE deleted_at IS NULL
+		ORDER BY sort_order, COALESCE(custom_title, title)`)
+	if err != nil {
+		return nil, fmt.Errorf("list feeds with unread counts: %w", err)
+	}
+	defer rows.Close()
+
+	var feeds []model.FeedWithUnread
+	for rows.Next() {
+		var unread int
+		feed, err := scanFeed(extraColumns{rows, []interface{}{&unread}})
+		if err != nil {
+			return nil, err
+		}
+		feeds = append(feeds, model.FeedWithUnread{Feed: feed, UnreadCount: unread})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate feeds: %w", err)
+	}
+	return feeds, nil
+}
+
 func (r *feedRepository) ListWithoutIcon(ctx context.Context) ([]model.Feed, error) {
-	rows, err := r.db.QueryContext(ctx, `SELECT id, folder_id, title, url, site_url, description, summary_prompt_reminder, icon_path, type, etag, last_modified, error_message, created_at, updated_at FROM feeds WHERE icon_path IS NULL OR icon_path = ''`)
+	rows, err := r.db.QueryContext(ctx, `SELECT id, folder_id, title, custom_title, url, site_url, description, summary_prompt_reminder, icon_path, icon_source, type, etag, last_modified, error_message, assume_timezone, paused_until, dedupe_key, preferred_user_agent, sort_order, min_poll_seconds, min_poll_source, max_entries, auto_translate, user_agent, auto_readability, language, last_fetched_at, not_modified_streak, last_entry_seen_at, suspect_caching_at, created_at, updated_at, deleted_at FROM feeds WHERE deleted_at IS NULL AND (icon_path IS NULL OR icon_path = '')`)
 	if err != nil {
 		return nil, fmt.Errorf("list feeds without icon: %w", err)
 	}
@@ -168,34 +298,70 @@ func (r *feedRepository) ListWithoutIcon(ctx context.Context) ([]model.Feed, err
 	return feeds, nil
 }
 
+// nextFeedSortOrder appends to the end of the feeds in folder ?.
+const nextFeedSortOrder = `SELECT COALESCE(MAX(sort_order), 0) + 1 FROM feeds WHERE folder_id IS ? AND deleted_at IS NULL`
+
 func (r *feedRepository) Update(ctx context.Context, feed model.Feed) (model.Feed, error) {
+	defer r.changes.Notify()
+
 	now := time.Now().UTC()
+	if feed.Type == "" {
+		feed.Type = "article"
+	}
+	if feed.DedupeKey == "" {
+		feed.DedupeKey = model.DedupeKeyAuto
+	}
+	if feed.AutoTranslate == "" {
+		feed.AutoTranslate = model.FeedAutoTranslateInherit
+	}
+	var pausedUntil interface{}
+	if feed.PausedUntil != nil {
+		pausedUntil = formatTime(*feed.PausedUntil)
+	}
+	// A feed moved to another folder goes to the end of that folder
 	_, err := r.db.ExecContext(
 		ctx,
-		`UPDATE feeds SET folder_id = ?, title = ?, url = ?, site_url = ?, description = ?, summary_prompt_reminder = ?, etag = ?, last_modified = ?, error_message = ?, updated_at = ? WHERE id = ?`,
+		`UPDATE feeds SET sort_order = CASE WHEN folder_id IS ? THEN sort_order ELSE (`+nextFeedSortOrder+`) END, folder_id = ?, title = ?, custom_title = ?, url = ?, canonical_url = ?, site_url = ?, description = ?, summary_prompt_reminder = ?, etag = ?, last_modified = ?, error_message = ?, max_entries = ?, type = ?, assume_timezone = ?, paused_until = ?, dedupe_key = ?, auto_translate = ?, user_agent = ?, auto_readability = ?, updated_at = ? WHERE id = ?`,
+		nullableInt64(feed.FolderID),
+		nullableInt64(feed.FolderID),
 		nullableInt64(feed.FolderID),
 		feed.Title,
+		nullableString(feed.CustomTitle),
 		feed.URL,
+		urlutil.CanonicalFeedURL(feed.URL),
 		nullableString(feed.SiteURL),
 		nullableString(feed.Description),
 		nullableString(feed.SummaryPromptReminder),
 		nullableString(feed.ETag),
 		nullableString(feed.LastModified),
 		nullableString(feed.ErrorMessage),
+		feed.MaxEntries,
+		feed.Type,
+		nullableString(feed.AssumeTimezone),
+		pausedUntil,
+		feed.DedupeKey,
+		feed.AutoTranslate,
+		nullableString(feed.UserAgent),
+		nullableBool(feed.AutoReadability),
 		formatTime(now),
 		feed.ID,
 	)
 	if err != nil {
 		return model.Feed{}, fmt.Errorf("update feed: %w", err)
 	}
+	if err := r.db.QueryRowContext(ctx, `SELECT sort_order FROM feeds WHERE id = ?`, feed.ID).Scan(&feed.SortOrder); err != nil {
+		return model.Feed{}, fmt.Errorf("get feed sort order: %w", err)
+	}
 	feed.UpdatedAt = now
 	return feed, nil
 }
 
 func (r *feedRepository) UpdateIconPath(ctx context.Context, id int64, iconPath string) error {
+	defer r.changes.Notify()
+
 	_, err := r.db.ExecContext(
 		ctx,
-		`UPDATE feeds SET icon_path = ?, updated_at = ? WHERE id = ?`,
+		`UPDATE feeds SET icon_path = ?, icon_source = NULL, updated_at = ? WHERE id = ?`,
 		iconPath,
 		formatTime(time.Now()),
 		id,
@@ -203,7 +369,37 @@ func (r *feedRepository) UpdateIconPath(ctx context.Context, id int64, iconPath
 	return err
 }
 
+func (r *feedRepository) UpdateGeneratedIconPath(ctx context.Context, id int64, iconPath string) error {
+	defer r.changes.Notify()
+
+	_, err := r.db.ExecContext(
+		ctx,
+		`UPDATE feeds SET icon_path = ?, icon_source = ?, updated_at = ? WHERE id = ?`,
+		iconPath,
+		model.IconSourceGenerated,
+		formatTime(time.Now()),
+		id,
+	)
+	return err
+}
+
+func (r *feedRepository) UpdateTitles(ctx context.Context, id int64, title string, customTitle *string) error {
+	defer r.changes.Notify()
+
+	_, err := r.db.ExecContext(
+		ctx,
+		`UPDATE feeds SET title = ?, custom_title = ?, updated_at = ? WHERE id = ?`,
+		title,
+		nullableString(customTitle),
+		formatTime(time.Now()),
+		id,
+	)
+	return err
+}
+
 func (r *feedRepository) UpdateSiteURL(ctx context.Context, id int64, siteURL string) error {
+	defer r.changes.Notify()
+
 	_, err := r.db.ExecContext(
 		ctx,
 		`UPDATE feeds SET site_url = ?, updated_at = ? WHERE id = ?`,
@@ -214,7 +410,23 @@ func (r *feedRepository) UpdateSiteURL(ctx context.Context, id int64, siteURL st
 	return err
 }
 
+func (r *feedRepository) UpdateConditionalGet(ctx context.Context, id int64, etag, lastModified *string) error {
+	defer r.changes.Notify()
+
+	_, err := r.db.ExecContext(
+		ctx,
+		`UPDATE feeds SET etag = ?, last_modified = ?, updated_at = ? WHERE id = ?`,
+		nullableString(etag),
+		nullableString(lastModified),
+		formatTime(time.Now()),
+		id,
+	)
+	return err
+}
+
 func (r *feedRepository) UpdateErrorMessage(ctx context.Context, id int64, errorMessage *string) error {
+	defer r.changes.Notify()
+
 	_, err := r.db.ExecContext(
 		ctx,
 		`UPDATE feeds SET error_message = ?, updated_at = ? WHERE id = ?`,
@@ -226,6 +438,8 @@ func (r *feedRepository) UpdateErrorMessage(ctx context.Context, id int64, error
 }
 
 func (r *feedRepository) UpdateType(ctx context.Context, id int64, feedType string) error {
+	defer r.changes.Notify()
+
 	_, err := r.db.ExecContext(
 		ctx,
 		`UPDATE feeds SET type = ?, updated_at = ? WHERE id = ?`,
@@ -236,43 +450,405 @@ func (r *feedRepository) UpdateType(ctx context.Context, id int64, feedType stri
 	return err
 }
 
-func (r *feedRepository) UpdateTypeByFolderID(ctx context.Context, folderID int64, feedType string) error {
+func (r *feedRepository) UpdateAssumeTimezone(ctx context.Context, id int64, timezone *string) error {
+	defer r.changes.Notify()
+
 	_, err := r.db.ExecContext(
 		ctx,
-		`UPDATE feeds SET type = ?, updated_at = ? WHERE folder_id = ?`,
-		feedType,
+		`UPDATE feeds SET assume_timezone = ?, updated_at = ? WHERE id = ?`,
+		nullableString(timezone),
+		formatTime(time.Now()),
+		id,
+	)
+	return err
+}
+
+func (r *feedRepository) UpdatePausedUntil(ctx context.Context, id int64, until *time.Time) error {
+	defer r.changes.Notify()
+
+	var value interface{}
+	if until != nil {
+		value = formatTime(*until)
+	}
+	_, err := r.db.ExecContext(
+		ctx,
+		`UPDATE feeds SET paused_until = ?, updated_at = ? WHERE id = ?`,
+		value,
+		formatTime(time.Now()),
+		id,
+	)
+	return err
+}
+
+func (r *feedRepository) UpdateDedupeKey(ctx context.Context, id int64, dedupeKey string) error {
+	defer r.changes.Notify()
+
+	_, err := r.db.ExecContext(
+		ctx,
+		`UPDATE feeds SET dedupe_key = ?, updated_at = ? WHERE id = ?`,
+		dedupeKey,
+		formatTime(time.Now()),
+		id,
+	)
+	return err
+}
+
+func (r *feedRepository) UpdateAutoTranslate(ctx context.Context, id int64, mode string) error {
+	defer r.changes.Notify()
+
+	_, err := r.db.ExecContext(
+		ctx,
+		`UPDATE feeds SET auto_translate = ?, updated_at = ? WHERE id = ?`,
+		mode,
+		formatTime(time.Now()),
+		id,
+	)
+	return err
+}
+
+func (r *feedRepository) UpdateUserAgent(ctx context.Context, id int64, userAgent *string) error {
+	defer r.changes.Notify()
+
+	_, err := r.db.ExecContext(
+		ctx,
+		`UPDATE feeds SET user_agent = ?, updated_at = ? WHERE id = ?`,
+		nullableString(userAgent),
+		formatTime(time.Now()),
+		id,
+	)
+	return err
+}
+
+func (r *feedRepository) UpdateAutoReadability(ctx context.Context, id int64, enabled *bool) error {
+	defer r.changes.Notify()
+
+	_, err := r.db.ExecContext(
+		ctx,
+		`UPDATE feeds SET auto_readability = ?, updated_at = ? WHERE id = ?`,
+		nullableBool(enabled),
+		formatTime(time.Now()),
+		id,
+	)
+	return err
+}
+
+func (r *feedRepository) UpdateLanguage(ctx context.Context, id int64, language string) error {
+	defer r.changes.Notify()
+
+	_, err := r.db.ExecContext(
+		ctx,
+		`UPDATE feeds SET language = ?, updated_at = ? WHERE id = ?`,
+		language,
+		formatTime(time.Now()),
+		id,
+	)
+	return err
+}
+
+func (r *feedRepository) UpdateIngestTokenHash(ctx context.Context, id int64, tokenHash string) error {
+	_, err := r.db.ExecContext(
+		ctx,
+		`UPDATE feeds SET ingest_token_hash = ?, updated_at = ? WHERE id = ?`,
+		tokenHash,
+		formatTime(time.Now()),
+		id,
+	)
+	return err
+}
+
+func (r *feedRepository) GetIngestTokenHash(ctx context.Context, id int64) (string, error) {
+	var tokenHash sql.NullString
+	err := r.db.QueryRowContext(ctx, `SELECT ingest_token_hash FROM feeds WHERE id = ? AND deleted_at IS NULL`, id).Scan(&tokenHash)
+	if err != nil {
+		return "", err
+	}
+	return tokenHash.String, nil
+}
+
+// NormalizeAuthor is the form muted authors are matched by. It mirrors SQLite's
+// LOWER(TRIM(author)): surrounding spaces go and only ASCII letters are folded.
+func NormalizeAuthor(author string) string {
+	return strings.Map(func(r rune) rune {
+		if 'A' <= r && r <= 'Z' {
+			return r + 'a' - 'A'
+		}
+		return r
+	}, strings.Trim(author, " "))
+}
+
+// mutedAuthorCondition matches entries e whose author is muted in their feed.
+const mutedAuthorCondition = `EXISTS (SELECT 1 FROM muted_authors m WHERE m.feed_id = e.feed_id AND m.normalized_author = LOWER(TRIM(e.author)))`
+
+func (r *feedRepository) ListMutedAuthors(ctx context.Context, feedID int64) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT author FROM muted_authors WHERE feed_id = ? ORDER BY normalized_author`, feedID)
+	if err != nil {
+		return nil, fmt.Errorf("list muted authors: %w", err)
+	}
+	defer rows.Close()
+
+	var authors []string
+	for rows.Next() {
+		var author string
+		if err := rows.Scan(&author); err != nil {
+			return nil, err
+		}
+		authors = append(authors, author)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate muted authors: %w", err)
+	}
+	return authors, nil
+}
+
+func (r *feedRepository) MuteAuthor(ctx context.Context, feedID int64, author string) error {
+	defer r.changes.Notify()
+
+	_, err := r.db.ExecContext(
+		ctx,
+		`INSERT INTO muted_authors (feed_id, author, normalized_author, created_at) VALUES (?, ?, ?, ?)
+		 ON CONFLICT(feed_id, normalized_author) DO UPDATE SET author = excluded.author`,
+		feedID,
+		author,
+		NormalizeAuthor(author),
 		formatTime(time.Now()),
-		folderID,
+	)
+	if err != nil {
+		return fmt.Errorf("mute author: %w", err)
+	}
+	return nil
+}
+
+func (r *feedRepository) UnmuteAuthor(ctx context.Context, feedID int64, author string) error {
+	defer r.changes.Notify()
+
+	result, err := r.db.ExecContext(ctx, `DELETE FROM muted_authors WHERE feed_id = ? AND normalized_author = ?`, feedID, NormalizeAuthor(author))
+	if err != nil {
+		return fmt.Errorf("unmute author: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("unmute author: %w", err)
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *feedRepository) IsAuthorMuted(ctx context.Context, feedID int64, author string) (bool, error) {
+	var muted bool
+	err := r.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM muted_authors WHERE feed_id = ? AND normalized_author = ?)`, feedID, NormalizeAuthor(author)).Scan(&muted)
+	if err != nil {
+		return false, fmt.Errorf("check muted author: %w", err)
+	}
+	return muted, nil
+}
+
+func (r *feedRepository) UpdatePreferredUserAgent(ctx context.Context, id int64, userAgent string) error {
+	defer r.changes.Notify()
+
+	_, err := r.db.ExecContext(
+		ctx,
+		`UPDATE feeds SET preferred_user_agent = ?, updated_at = ? WHERE id = ?`,
+		userAgent,
+		formatTime(time.Now()),
+		id,
+	)
+	return err
+}
+
+func (r *feedRepository) UpdatePollHint(ctx context.Context, id int64, seconds int, source string) error {
+	defer r.changes.Notify()
+
+	_, err := r.db.ExecContext(
+		ctx,
+		`UPDATE feeds SET min_poll_seconds = ?, min_poll_source = ?, updated_at = ? WHERE id = ?`,
+		seconds,
+		source,
+		formatTime(time.Now()),
+		id,
+	)
+	return err
+}
+
+// UpdateLastFetchedAt leaves updated_at alone, as it changes on every refresh.
+func (r *feedRepository) UpdateLastFetchedAt(ctx context.Context, id int64, fetchedAt time.Time) error {
+	defer r.changes.Notify()
+
+	_, err := r.db.ExecContext(
+		ctx,
+		`UPDATE feeds SET last_fetched_at = ? WHERE id = ?`,
+		formatTime(fetchedAt),
+		id,
+	)
+	return err
+}
+
+// UpdateNotModifiedStreak leaves updated_at alone, as it changes on most refreshes.
+func (r *feedRepository) UpdateNotModifiedStreak(ctx context.Context, id int64, streak int) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE feeds SET not_modified_streak = ? WHERE id = ?`, streak, id)
+	return err
+}
+
+func (r *feedRepository) UpdateLastEntrySeenAt(ctx context.Context, id int64, seenAt time.Time) error {
+	// Stored dates vary in fractional precision, so compare via julianday()
+	_, err := r.db.ExecContext(
+		ctx,
+		`UPDATE feeds SET last_entry_seen_at = ? WHERE id = ? AND (last_entry_seen_at IS NULL OR julianday(last_entry_seen_at) < julianday(?))`,
+		formatTime(seenAt),
+		id,
+		formatTime(seenAt),
+	)
+	return err
+}
+
+func (r *feedRepository) UpdateSuspectCaching(ctx context.Context, id int64, at *time.Time) error {
+	defer r.changes.Notify()
+
+	var value interface{}
+	if at != nil {
+		value = formatTime(*at)
+	}
+	_, err := r.db.ExecContext(
+		ctx,
+		`UPDATE feeds SET suspect_caching_at = ?, updated_at = ? WHERE id = ?`,
+		value,
+		formatTime(time.Now()),
+		id,
 	)
 	return err
 }
 
+func (r *feedRepository) CountSuspectCaching(ctx context.Context) (int, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM feeds WHERE suspect_caching_at IS NOT NULL AND deleted_at IS NULL`).Scan(&count)
+	return count, err
+}
+
+func (r *feedRepository) PostingInterval(ctx context.Context, id int64, sample int) (time.Duration, error) {
+	var count int
+	var newest, oldest sql.NullFloat64
+	err := r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*), MAX(day), MIN(day) FROM (
+			SELECT julianday(published_at) AS day
+			FROM entries
+			WHERE feed_id = ? AND published_at IS NOT NULL
+			ORDER BY day DESC
+			LIMIT ?
+		)`, id, sample).Scan(&count, &newest, &oldest)
+	if err != nil {
+		return 0, err
+	}
+	if count < 2 || !newest.Valid || !oldest.Valid {
+		return 0, nil
+	}
+	days := (newest.Float64 - oldest.Float64) / float64(count-1)
+	return time.Duration(days * float64(24*time.Hour)), nil
+}
+
+func (r *feedRepository) Reorder(ctx context.Context, ids []int64) error {
+	defer r.changes.Notify()
+
+	now := formatTime(time.Now())
+	err := withTx(ctx, r.db, r.writes, func(tx dbtx) error {
+		for i, id := range ids {
+			if _, err := tx.ExecContext(ctx, `UPDATE feeds SET sort_order = ?, updated_at = ? WHERE id = ?`, i+1, now, id); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("reorder feeds: %w", err)
+	}
+	return nil
+}
+
+func (r *feedRepository) UpdateTypeByFolderIDs(ctx context.Context, folderIDs []int64, feedType string) error {
+	if len(folderIDs) == 0 {
+		return nil
+	}
+	defer r.changes.Notify()
+
+	args := make([]interface{}, 0, len(folderIDs)+2)
+	args = append(args, feedType, formatTime(time.Now()))
+	for _, id := range folderIDs {
+		args = append(args, id)
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(folderIDs)), ",")
+	_, err := r.db.ExecContext(ctx, `UPDATE feeds SET type = ?, updated_at = ? WHERE folder_id IN (`+placeholders+`)`, args...)
+	return err
+}
+
 func (r *feedRepository) Delete(ctx context.Context, id int64) error {
-	if _, err := r.db.ExecContext(ctx, `DELETE FROM feeds WHERE id = ?`, id); err != nil {
+	defer r.changes.Notify()
+
+	now := formatTime(time.Now())
+	if _, err := r.db.ExecContext(ctx, `UPDATE feeds SET deleted_at = ?, updated_at = ? WHERE id = ? AND deleted_at IS NULL`, now, now, id); err != nil {
 		return fmt.Errorf("delete feed: %w", err)
 	}
 	return nil
 }
 
 func (r *feedRepository) DeleteBatch(ctx context.Context, ids []int64) (int64, error) {
+	defer r.changes.Notify()
+
 	if len(ids) == 0 {
 		return 0, nil
 	}
 	// Build placeholder string: ?,?,?...
 	placeholders := strings.Repeat("?,", len(ids)-1) + "?"
-	args := make([]interface{}, len(ids))
-	for i, id := range ids {
-		args[i] = id
+	now := formatTime(time.Now())
+	args := make([]interface{}, 0, len(ids)+2)
+	args = append(args, now, now)
+	for _, id := range ids {
+		args = append(args, id)
 	}
-	result, err := r.db.ExecContext(ctx, `DELETE FROM feeds WHERE id IN (`+placeholders+`)`, args...)
+	result, err := r.db.ExecContext(ctx, `UPDATE feeds SET deleted_at = ?, updated_at = ? WHERE id IN (`+placeholders+`) AND deleted_at IS NULL`, args...)
 	if err != nil {
 		return 0, fmt.Errorf("delete feeds batch: %w", err)
 	}
 	return result.RowsAffected()
 }
 
+func (r *feedRepository) Restore(ctx context.Context, id int64, deletedSince time.Time) error {
+	defer r.changes.Notify()
+
+	// A feed whose folder is still deleted comes back at the top level
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE feeds SET
+		  deleted_at = NULL,
+		  folder_id = CASE WHEN folder_id IN (SELECT id FROM folders WHERE deleted_at IS NOT NULL) THEN NULL ELSE folder_id END,
+		  updated_at = ?
+		WHERE id = ? AND deleted_at IS NOT NULL AND julianday(deleted_at) >= julianday(?)
+	`, formatTime(time.Now()), id, formatTime(deletedSince))
+	if err != nil {
+		return fmt.Errorf("restore feed: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("restore feed: %w", err)
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *feedRepository) PurgeDeleted(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	defer r.changes.Notify()
+
+	result, err := r.db.ExecContext(ctx, `DELETE FROM feeds WHERE deleted_at IS NOT NULL AND julianday(deleted_at) < julianday(?)`, formatTime(deletedBefore))
+	if err != nil {
+		return 0, fmt.Errorf("purge deleted feeds: %w", err)
+	}
+	return result.RowsAffected()
+}
+
 func (r *feedRepository) ClearAllIconPaths(ctx context.Context) (int64, error) {
-	result, err := r.db.ExecContext(ctx, `UPDATE feeds SET icon_path = NULL, updated_at = ? WHERE icon_path IS NOT NULL`, formatTime(time.Now()))
+	defer r.changes.Notify()
+
+	result, err := r.db.ExecContext(ctx, `UPDATE feeds SET icon_path = NULL, icon_source = NULL, updated_at = ? WHERE icon_path IS NOT NULL`, formatTime(time.Now()))
 	if err != nil {
 		return 0, fmt.Errorf("clear icon paths: %w", err)
 	}
@@ -280,6 +856,8 @@ func (r *feedRepository) ClearAllIconPaths(ctx context.Context) (int64, error) {
 }
 
 func (r *feedRepository) ClearAllConditionalGet(ctx context.Context) (int64, error) {
+	defer r.changes.Notify()
+
 	result, err := r.db.ExecContext(ctx, `UPDATE feeds SET etag = NULL, last_modified = NULL, updated_at = ? WHERE etag IS NOT NULL OR last_modified IS NOT NULL`, formatTime(time.Now()))
 	if err != nil {
 		return 0, fmt.Errorf("clear conditional get: %w", err)
@@ -287,42 +865,113 @@ func (r *feedRepository) ClearAllConditionalGet(ctx context.Context) (int64, err
 	return result.RowsAffected()
 }
 
+// FeedActivityWindow is the period counted by FeedActivityStats.EntriesLastWeek.
+const FeedActivityWindow = 7 * 24 * time.Hour
+
+func (r *feedRepository) GetActivityStats(ctx context.Context) ([]model.FeedActivityStats, error) {
+	// published_at is stored as text with varying fractional precision, so compare
+	// via julianday() rather than lexically. Entries without a date count by created_at.
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT f.id,
+		       COALESCE(SUM(CASE WHEN julianday(COALESCE(e.published_at, e.created_at)) >= julianday(?) THEN 1 ELSE 0 END), 0),
+		       strftime('%Y-%m-%dT%H:%M:%SZ', MAX(julianday(e.published_at))),
+		       COUNT(e.id)
+		FROM feeds f
+		LEFT JOIN entries e ON e.feed_id = f.id
+		WHERE f.deleted_at IS NULL
+		GROUP BY f.id
+		ORDER BY f.id
+	`, formatTime(time.Now().Add(-FeedActivityWindow)))
+	if err != nil {
+		return nil, fmt.Errorf("query feed activity stats: %w", err)
+	}
+	defer rows.Close()
+
+	var stats []model.FeedActivityStats
+	for rows.Next() {
+		var s model.FeedActivityStats
+		var lastPublishedAt sql.NullString
+		if err := rows.Scan(&s.FeedID, &s.EntriesLastWeek, &lastPublishedAt, &s.TotalEntries); err != nil {
+			return nil, fmt.Errorf("scan feed activity stats: %w", err)
+		}
+		if lastPublishedAt.Valid {
+			if t, err := parseTime(lastPublishedAt.String); err == nil {
+				s.LastPublishedAt = &t
+			}
+		}
+		stats = append(stats, s)
+	}
+	return stats, rows.Err()
+}
+
 func scanFeed(scanner interface {
 	Scan(dest ...interface{}) error
 }) (model.Feed, error) {
 	var feed model.Feed
 	var folderID sql.NullInt64
+	var customTitle sql.NullString
 	var siteURL sql.NullString
 	var description sql.NullString
 	var summaryPromptReminder sql.NullString
 	var iconPath sql.NullString
+	var iconSource sql.NullString
 	var feedType sql.NullString
 	var etag sql.NullString
 	var lastModified sql.NullString
 	var errorMessage sql.NullString
+	var assumeTimezone sql.NullString
+	var pausedUntil sql.NullString
+	var lastFetchedAt sql.NullString
+	var lastEntrySeenAt sql.NullString
+	var suspectCachingAt sql.NullString
+	var userAgent sql.NullString
+	var autoReadability sql.NullInt64
 	var createdAt string
 	var updatedAt string
+	var deletedAt sql.NullString
 	if err := scanner.Scan(
 		&feed.ID,
 		&folderID,
 		&feed.Title,
+		&customTitle,
 		&feed.URL,
 		&siteURL,
 		&description,
 		&summaryPromptReminder,
 		&iconPath,
+		&iconSource,
 		&feedType,
 		&etag,
 		&lastModified,
 		&errorMessage,
+		&assumeTimezone,
+		&pausedUntil,
+		&feed.DedupeKey,
+		&feed.PreferredUserAgent,
+		&feed.SortOrder,
+		&feed.MinPollSeconds,
+		&feed.MinPollSource,
+		&feed.MaxEntries,
+		&feed.AutoTranslate,
+		&userAgent,
+		&autoReadability,
+		&feed.Language,
+		&lastFetchedAt,
+		&feed.NotModifiedStreak,
+		&lastEntrySeenAt,
+		&suspectCachingAt,
 		&createdAt,
 		&updatedAt,
+		&deletedAt,
 	); err != nil {
 		return model.Feed{}, err
 	}
 	if folderID.Valid {
 		feed.FolderID = &folderID.Int64
 	}
+	if customTitle.Valid {
+		feed.CustomTitle = &customTitle.String
+	}
 	if siteURL.Valid {
 		feed.SiteURL = &siteURL.String
 	}
@@ -335,6 +984,9 @@ func scanFeed(scanner interface {
 	if iconPath.Valid {
 		feed.IconPath = &iconPath.String
 	}
+	if iconSource.Valid {
+		feed.IconSource = &iconSource.String
+	}
 	if feedType.Valid {
 		feed.Type = feedType.String
 	} else {
@@ -349,7 +1001,42 @@ func scanFeed(scanner interface {
 	if errorMessage.Valid {
 		feed.ErrorMessage = &errorMessage.String
 	}
+	if assumeTimezone.Valid {
+		feed.AssumeTimezone = &assumeTimezone.String
+	}
+	if userAgent.Valid {
+		feed.UserAgent = &userAgent.String
+	}
+	feed.AutoReadability = nullBoolPtr(autoReadability)
 	var err error
+	if pausedUntil.Valid {
+		t, err := parseTime(pausedUntil.String)
+		if err != nil {
+			return model.Feed{}, fmt.Errorf("parse feed paused_until: %w", err)
+		}
+		feed.PausedUntil = &t
+	}
+	if lastFetchedAt.Valid {
+		t, err := parseTime(lastFetchedAt.String)
+		if err != nil {
+			return model.Feed{}, fmt.Errorf("parse feed last_fetched_at: %w", err)
+		}
+		feed.LastFetchedAt = &t
+	}
+	if lastEntrySeenAt.Valid {
+		t, err := parseTime(lastEntrySeenAt.String)
+		if err != nil {
+			return model.Feed{}, fmt.Errorf("parse feed last_entry_seen_at: %w", err)
+		}
+		feed.LastEntrySeenAt = &t
+	}
+	if suspectCachingAt.Valid {
+		t, err := parseTime(suspectCachingAt.String)
+		if err != nil {
+			return model.Feed{}, fmt.Errorf("parse feed suspect_caching_at: %w", err)
+		}
+		feed.SuspectCachingAt = &t
+	}
 	feed.CreatedAt, err = parseTime(createdAt)
 	if err != nil {
 		return model.Feed{}, fmt.Errorf("parse feed created_at: %w", err)
@@ -358,5 +1045,12 @@ func scanFeed(scanner interface {
 	if err != nil {
 		return model.Feed{}, fmt.Errorf("parse feed updated_at: %w", err)
 	}
+	if deletedAt.Valid {
+		t, err := parseTime(deletedAt.String)
+		if err != nil {
+			return model.Feed{}, fmt.Errorf("parse feed deleted_at: %w", err)
+		}
+		feed.DeletedAt = &t
+	}
 	return feed, nil
 }
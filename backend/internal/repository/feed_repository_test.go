@@ -2,8 +2,11 @@ package repository_test
 
 import (
 	"context"
+	"database/sql"
+	"fmt"
 	"gist/backend/internal/repository"
 	"testing"
+	"time"
 
 	"gist/backend/internal/model"
 	"gist/backend/internal/repository/testutil"
@@ -13,7 +16,7 @@ import (
 
 func TestFeedRepository_CreateAndGet(t *testing.T) {
 	db := testutil.NewTestDB(t)
-	repo := repository.NewFeedRepository(db)
+	repo := repository.NewFeedRepository(db, nil, nil)
 	ctx := context.Background()
 
 	reminder := "聚焦核心论点"
@@ -38,9 +41,21 @@ func TestFeedRepository_CreateAndGet(t *testing.T) {
 	require.Equal(t, reminder, *fetched.SummaryPromptReminder)
 }
 
+func TestFeedRepository_Create_DuplicateURL(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	repo := repository.NewFeedRepository(db, nil, nil)
+	ctx := context.Background()
+
+	_, err := repo.Create(ctx, model.Feed{Title: "First", URL: "https://example.com/feed"})
+	require.NoError(t, err)
+
+	_, err = repo.Create(ctx, model.Feed{Title: "Second", URL: "https://example.com/feed"})
+	require.ErrorIs(t, err, repository.ErrConflict)
+}
+
 func TestFeedRepository_List(t *testing.T) {
 	db := testutil.NewTestDB(t)
-	repo := repository.NewFeedRepository(db)
+	repo := repository.NewFeedRepository(db, nil, nil)
 	ctx := context.Background()
 
 	folderID := testutil.SeedFolder(t, db, "Test Folder", nil, "article")
@@ -60,9 +75,42 @@ func TestFeedRepository_List(t *testing.T) {
 	require.Equal(t, "Feed 1", feeds[0].Title)
 }
 
+func TestFeedRepository_ListWithUnreadCounts(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	repo := repository.NewFeedRepository(db, nil, nil)
+	ctx := context.Background()
+
+	paused := time.Now().Add(time.Hour)
+	active := testutil.SeedFeed(t, db, model.Feed{Title: "A Feed", URL: "https://a.example.com/feed"})
+	pausedFeed := testutil.SeedFeed(t, db, model.Feed{Title: "B Paused", URL: "https://b.example.com/feed", PausedUntil: &paused})
+	empty := testutil.SeedFeed(t, db, model.Feed{Title: "C Empty", URL: "https://c.example.com/feed"})
+	deleted := testutil.SeedFeed(t, db, model.Feed{Title: "D Deleted", URL: "https://d.example.com/feed"})
+
+	muted := "Jane Doe"
+	testutil.SeedEntry(t, db, model.Entry{FeedID: active})
+	testutil.SeedEntry(t, db, model.Entry{FeedID: active})
+	testutil.SeedEntry(t, db, model.Entry{FeedID: active, Read: true})
+	testutil.SeedEntry(t, db, model.Entry{FeedID: active, Author: &muted})
+	testutil.SeedEntry(t, db, model.Entry{FeedID: pausedFeed})
+	testutil.SeedEntry(t, db, model.Entry{FeedID: empty, Read: true})
+	require.NoError(t, repo.MuteAuthor(ctx, active, "jane doe"))
+	require.NoError(t, repo.Delete(ctx, deleted))
+
+	feeds, err := repo.ListWithUnreadCounts(ctx)
+	require.NoError(t, err)
+	require.Len(t, feeds, 3)
+	require.Equal(t, active, feeds[0].ID)
+	require.Equal(t, "A Feed", feeds[0].Title)
+	require.Equal(t, 2, feeds[0].UnreadCount)
+	require.Equal(t, pausedFeed, feeds[1].ID)
+	require.Equal(t, 0, feeds[1].UnreadCount)
+	require.Equal(t, empty, feeds[2].ID)
+	require.Equal(t, 0, feeds[2].UnreadCount)
+}
+
 func TestFeedRepository_Update(t *testing.T) {
 	db := testutil.NewTestDB(t)
-	repo := repository.NewFeedRepository(db)
+	repo := repository.NewFeedRepository(db, nil, nil)
 	ctx := context.Background()
 
 	id := testutil.SeedFeed(t, db, model.Feed{Title: "Old Title", URL: "url"})
@@ -94,9 +142,71 @@ func TestFeedRepository_Update(t *testing.T) {
 	require.Nil(t, fetched.SummaryPromptReminder)
 }
 
+func TestFeedRepository_Update_Settings(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	repo := repository.NewFeedRepository(db, nil, nil)
+	ctx := context.Background()
+
+	id := testutil.SeedFeed(t, db, model.Feed{Title: "Feed", URL: "url"})
+	feed, err := repo.GetByID(ctx, id)
+	require.NoError(t, err)
+
+	timezone := "Asia/Shanghai"
+	userAgent := "Reader/1.0"
+	enabled := true
+	until := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+	feed.Type = "picture"
+	feed.AssumeTimezone = &timezone
+	feed.PausedUntil = &until
+	feed.DedupeKey = model.DedupeKeyURL
+	feed.AutoTranslate = model.FeedAutoTranslateOff
+	feed.UserAgent = &userAgent
+	feed.AutoReadability = &enabled
+	_, err = repo.Update(ctx, feed)
+	require.NoError(t, err)
+
+	fetched, err := repo.GetByID(ctx, id)
+	require.NoError(t, err)
+	require.Equal(t, "picture", fetched.Type)
+	require.Equal(t, timezone, *fetched.AssumeTimezone)
+	require.True(t, until.Equal(*fetched.PausedUntil))
+	require.Equal(t, model.DedupeKeyURL, fetched.DedupeKey)
+	require.Equal(t, model.FeedAutoTranslateOff, fetched.AutoTranslate)
+	require.Equal(t, userAgent, *fetched.UserAgent)
+	require.True(t, *fetched.AutoReadability)
+
+	fetched.AssumeTimezone, fetched.PausedUntil, fetched.UserAgent, fetched.AutoReadability = nil, nil, nil, nil
+	_, err = repo.Update(ctx, fetched)
+	require.NoError(t, err)
+
+	fetched, err = repo.GetByID(ctx, id)
+	require.NoError(t, err)
+	require.Nil(t, fetched.AssumeTimezone)
+	require.Nil(t, fetched.PausedUntil)
+	require.Nil(t, fetched.UserAgent)
+	require.Nil(t, fetched.AutoReadability)
+}
+
+func TestFeedRepository_UpdateConditionalGet(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	repo := repository.NewFeedRepository(db, nil, nil)
+	ctx := context.Background()
+
+	id := testutil.SeedFeed(t, db, model.Feed{Title: "Feed", URL: "url"})
+	etag := `"v2"`
+	lastModified := "Mon, 02 Jan 2006 15:04:05 GMT"
+	require.NoError(t, repo.UpdateConditionalGet(ctx, id, &etag, &lastModified))
+
+	fetched, err := repo.GetByID(ctx, id)
+	require.NoError(t, err)
+	require.Equal(t, etag, *fetched.ETag)
+	require.Equal(t, lastModified, *fetched.LastModified)
+	require.Equal(t, "Feed", fetched.Title)
+}
+
 func TestFeedRepository_Delete(t *testing.T) {
 	db := testutil.NewTestDB(t)
-	repo := repository.NewFeedRepository(db)
+	repo := repository.NewFeedRepository(db, nil, nil)
 	ctx := context.Background()
 
 	id := testutil.SeedFeed(t, db, model.Feed{Title: "To Delete", URL: "url"})
@@ -110,7 +220,7 @@ func TestFeedRepository_Delete(t *testing.T) {
 
 func TestFeedRepository_DeleteBatch(t *testing.T) {
 	db := testutil.NewTestDB(t)
-	repo := repository.NewFeedRepository(db)
+	repo := repository.NewFeedRepository(db, nil, nil)
 	ctx := context.Background()
 
 	id1 := testutil.SeedFeed(t, db, model.Feed{Title: "F1", URL: "u1"})
@@ -129,7 +239,7 @@ func TestFeedRepository_DeleteBatch(t *testing.T) {
 
 func TestFeedRepository_FindByURL(t *testing.T) {
 	db := testutil.NewTestDB(t)
-	repo := repository.NewFeedRepository(db)
+	repo := repository.NewFeedRepository(db, nil, nil)
 	ctx := context.Background()
 
 	testutil.SeedFeed(t, db, model.Feed{Title: "Feed", URL: "https://example.com/rss"})
@@ -140,9 +250,28 @@ func TestFeedRepository_FindByURL(t *testing.T) {
 	require.Equal(t, "Feed", found.Title)
 }
 
+func TestFeedRepository_FindByURL_MatchesCanonicalForm(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	repo := repository.NewFeedRepository(db, nil, nil)
+	ctx := context.Background()
+
+	created, err := repo.Create(ctx, model.Feed{Title: "Feed", URL: "https://example.com/rss?utm_source=newsletter"})
+	require.NoError(t, err)
+
+	found, err := repo.FindByURL(ctx, "https://EXAMPLE.com:443/rss/?fbclid=abc")
+	require.NoError(t, err)
+	require.NotNil(t, found)
+	require.Equal(t, created.ID, found.ID)
+	// The original URL is kept for fetching
+	require.Equal(t, "https://example.com/rss?utm_source=newsletter", found.URL)
+
+	_, err = repo.Create(ctx, model.Feed{Title: "Dup", URL: "https://example.com/rss"})
+	require.Error(t, err)
+}
+
 func TestFeedRepository_GetByIDs(t *testing.T) {
 	db := testutil.NewTestDB(t)
-	repo := repository.NewFeedRepository(db)
+	repo := repository.NewFeedRepository(db, nil, nil)
 	ctx := context.Background()
 
 	id1 := testutil.SeedFeed(t, db, model.Feed{Title: "Feed 1", URL: "url1"})
@@ -155,7 +284,7 @@ func TestFeedRepository_GetByIDs(t *testing.T) {
 
 func TestFeedRepository_ListWithoutIcon(t *testing.T) {
 	db := testutil.NewTestDB(t)
-	repo := repository.NewFeedRepository(db)
+	repo := repository.NewFeedRepository(db, nil, nil)
 	ctx := context.Background()
 
 	icon := "icon.png"
@@ -170,7 +299,7 @@ func TestFeedRepository_ListWithoutIcon(t *testing.T) {
 
 func TestFeedRepository_UpdateIconPath(t *testing.T) {
 	db := testutil.NewTestDB(t)
-	repo := repository.NewFeedRepository(db)
+	repo := repository.NewFeedRepository(db, nil, nil)
 	ctx := context.Background()
 
 	id := testutil.SeedFeed(t, db, model.Feed{Title: "Feed", URL: "u"})
@@ -183,9 +312,30 @@ func TestFeedRepository_UpdateIconPath(t *testing.T) {
 	require.Equal(t, "icon.png", *feed.IconPath)
 }
 
+func TestFeedRepository_UpdateGeneratedIconPath(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	repo := repository.NewFeedRepository(db, nil, nil)
+	ctx := context.Background()
+
+	id := testutil.SeedFeed(t, db, model.Feed{Title: "Feed", URL: "u"})
+
+	require.NoError(t, repo.UpdateGeneratedIconPath(ctx, id, "example.com.generated-f.png"))
+	feed, err := repo.GetByID(ctx, id)
+	require.NoError(t, err)
+	require.Equal(t, "example.com.generated-f.png", *feed.IconPath)
+	require.Equal(t, model.IconSourceGenerated, *feed.IconSource)
+
+	// A fetched icon replaces it and clears the source
+	require.NoError(t, repo.UpdateIconPath(ctx, id, "example.com.png"))
+	feed, err = repo.GetByID(ctx, id)
+	require.NoError(t, err)
+	require.Equal(t, "example.com.png", *feed.IconPath)
+	require.Nil(t, feed.IconSource)
+}
+
 func TestFeedRepository_UpdateSiteURL(t *testing.T) {
 	db := testutil.NewTestDB(t)
-	repo := repository.NewFeedRepository(db)
+	repo := repository.NewFeedRepository(db, nil, nil)
 	ctx := context.Background()
 
 	id := testutil.SeedFeed(t, db, model.Feed{Title: "Feed", URL: "u"})
@@ -200,7 +350,7 @@ func TestFeedRepository_UpdateSiteURL(t *testing.T) {
 
 func TestFeedRepository_UpdateErrorMessage(t *testing.T) {
 	db := testutil.NewTestDB(t)
-	repo := repository.NewFeedRepository(db)
+	repo := repository.NewFeedRepository(db, nil, nil)
 	ctx := context.Background()
 
 	id := testutil.SeedFeed(t, db, model.Feed{Title: "Feed", URL: "u"})
@@ -221,7 +371,7 @@ func TestFeedRepository_UpdateErrorMessage(t *testing.T) {
 
 func TestFeedRepository_UpdateType(t *testing.T) {
 	db := testutil.NewTestDB(t)
-	repo := repository.NewFeedRepository(db)
+	repo := repository.NewFeedRepository(db, nil, nil)
 	ctx := context.Background()
 
 	id := testutil.SeedFeed(t, db, model.Feed{Title: "Feed", URL: "u"})
@@ -233,27 +383,264 @@ func TestFeedRepository_UpdateType(t *testing.T) {
 	require.Equal(t, "picture", feed.Type)
 }
 
-func TestFeedRepository_UpdateTypeByFolderID(t *testing.T) {
+func TestFeedRepository_UpdateAssumeTimezone(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	repo := repository.NewFeedRepository(db, nil, nil)
+	ctx := context.Background()
+
+	id := testutil.SeedFeed(t, db, model.Feed{Title: "Feed", URL: "u"})
+
+	tz := "Asia/Shanghai"
+	require.NoError(t, repo.UpdateAssumeTimezone(ctx, id, &tz))
+	feed, _ := repo.GetByID(ctx, id)
+	require.Equal(t, "Asia/Shanghai", *feed.AssumeTimezone)
+
+	require.NoError(t, repo.UpdateAssumeTimezone(ctx, id, nil))
+	feed, _ = repo.GetByID(ctx, id)
+	require.Nil(t, feed.AssumeTimezone)
+}
+
+func TestFeedRepository_UpdatePreferredUserAgent(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	repo := repository.NewFeedRepository(db, nil, nil)
+	ctx := context.Background()
+
+	id := testutil.SeedFeed(t, db, model.Feed{Title: "Feed", URL: "u"})
+	feed, _ := repo.GetByID(ctx, id)
+	require.Equal(t, model.FeedUserAgentDefault, feed.PreferredUserAgent)
+
+	require.NoError(t, repo.UpdatePreferredUserAgent(ctx, id, model.FeedUserAgentFallback))
+	feed, _ = repo.GetByID(ctx, id)
+	require.Equal(t, model.FeedUserAgentFallback, feed.PreferredUserAgent)
+}
+
+func TestFeedRepository_UpdateAutoTranslate(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	repo := repository.NewFeedRepository(db, nil, nil)
+	ctx := context.Background()
+
+	id := testutil.SeedFeed(t, db, model.Feed{Title: "Feed", URL: "u"})
+	feed, _ := repo.GetByID(ctx, id)
+	require.Equal(t, model.FeedAutoTranslateInherit, feed.AutoTranslate)
+
+	require.NoError(t, repo.UpdateAutoTranslate(ctx, id, model.FeedAutoTranslateOff))
+	feed, _ = repo.GetByID(ctx, id)
+	require.Equal(t, model.FeedAutoTranslateOff, feed.AutoTranslate)
+}
+
+func TestFeedRepository_UpdateLanguage(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	repo := repository.NewFeedRepository(db, nil, nil)
+	ctx := context.Background()
+
+	created, err := repo.Create(ctx, model.Feed{Title: "Feed", URL: "https://example.com/feed", Language: "de"})
+	require.NoError(t, err)
+	feed, _ := repo.GetByID(ctx, created.ID)
+	require.Equal(t, "de", feed.Language)
+
+	require.NoError(t, repo.UpdateLanguage(ctx, created.ID, ""))
+	feed, _ = repo.GetByID(ctx, created.ID)
+	require.Empty(t, feed.Language)
+}
+
+func TestFeedRepository_UpdatePausedUntil(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	repo := repository.NewFeedRepository(db, nil, nil)
+	ctx := context.Background()
+
+	id := testutil.SeedFeed(t, db, model.Feed{Title: "Feed", URL: "u"})
+
+	until := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+	require.NoError(t, repo.UpdatePausedUntil(ctx, id, &until))
+	feed, _ := repo.GetByID(ctx, id)
+	require.NotNil(t, feed.PausedUntil)
+	require.True(t, until.Equal(*feed.PausedUntil))
+
+	require.NoError(t, repo.UpdatePausedUntil(ctx, id, nil))
+	feed, _ = repo.GetByID(ctx, id)
+	require.Nil(t, feed.PausedUntil)
+}
+
+func TestFeedRepository_UpdatePollHintAndLastFetchedAt(t *testing.T) {
 	db := testutil.NewTestDB(t)
-	repo := repository.NewFeedRepository(db)
+	repo := repository.NewFeedRepository(db, nil, nil)
+	ctx := context.Background()
+
+	id := testutil.SeedFeed(t, db, model.Feed{Title: "Feed", URL: "u"})
+	feed, _ := repo.GetByID(ctx, id)
+	require.Zero(t, feed.MinPollSeconds)
+	require.Empty(t, feed.MinPollSource)
+	require.Nil(t, feed.LastFetchedAt)
+
+	fetchedAt := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+	require.NoError(t, repo.UpdatePollHint(ctx, id, 3600, model.PollHintSyndication))
+	require.NoError(t, repo.UpdateLastFetchedAt(ctx, id, fetchedAt))
+	feed, _ = repo.GetByID(ctx, id)
+	require.Equal(t, 3600, feed.MinPollSeconds)
+	require.Equal(t, model.PollHintSyndication, feed.MinPollSource)
+	require.NotNil(t, feed.LastFetchedAt)
+	require.True(t, fetchedAt.Equal(*feed.LastFetchedAt))
+}
+
+func TestFeedRepository_NotModifiedTracking(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	repo := repository.NewFeedRepository(db, nil, nil)
+	ctx := context.Background()
+
+	id := testutil.SeedFeed(t, db, model.Feed{Title: "Feed", URL: "u"})
+	feed, _ := repo.GetByID(ctx, id)
+	require.Zero(t, feed.NotModifiedStreak)
+	require.Nil(t, feed.LastEntrySeenAt)
+	require.Nil(t, feed.SuspectCachingAt)
+
+	seen := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+	require.NoError(t, repo.UpdateNotModifiedStreak(ctx, id, 3))
+	require.NoError(t, repo.UpdateLastEntrySeenAt(ctx, id, seen))
+	// An older date leaves the newest one in place
+	require.NoError(t, repo.UpdateLastEntrySeenAt(ctx, id, seen.Add(-time.Hour)))
+	require.NoError(t, repo.UpdateSuspectCaching(ctx, id, &seen))
+	feed, _ = repo.GetByID(ctx, id)
+	require.Equal(t, 3, feed.NotModifiedStreak)
+	require.NotNil(t, feed.LastEntrySeenAt)
+	require.True(t, seen.Equal(*feed.LastEntrySeenAt))
+	require.NotNil(t, feed.SuspectCachingAt)
+
+	count, err := repo.CountSuspectCaching(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+
+	require.NoError(t, repo.UpdateSuspectCaching(ctx, id, nil))
+	count, err = repo.CountSuspectCaching(ctx)
+	require.NoError(t, err)
+	require.Zero(t, count)
+}
+
+func TestFeedRepository_PostingInterval(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	repo := repository.NewFeedRepository(db, nil, nil)
+	ctx := context.Background()
+
+	feedID := testutil.SeedFeed(t, db, model.Feed{Title: "Feed", URL: "u"})
+	interval, err := repo.PostingInterval(ctx, feedID, 3)
+	require.NoError(t, err)
+	require.Zero(t, interval)
+
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, offset := range []time.Duration{0, 10 * 24 * time.Hour, 12 * 24 * time.Hour, 14 * 24 * time.Hour} {
+		published := base.Add(offset)
+		testutil.SeedEntry(t, db, model.Entry{FeedID: feedID, URL: stringPtr(fmt.Sprintf("https://example.com/%d", i)), PublishedAt: &published})
+	}
+
+	// Only the newest three count: 4 days over 2 gaps
+	interval, err = repo.PostingInterval(ctx, feedID, 3)
+	require.NoError(t, err)
+	require.InDelta(t, float64(48*time.Hour), float64(interval), float64(time.Second))
+}
+
+func TestFeedRepository_UpdateTitles(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	repo := repository.NewFeedRepository(db, nil, nil)
+	ctx := context.Background()
+
+	custom := "Zed"
+	id := testutil.SeedFeed(t, db, model.Feed{Title: "Alpha", URL: "u1", CustomTitle: &custom})
+	testutil.SeedFeed(t, db, model.Feed{Title: "Middle", URL: "u2"})
+
+	feeds, err := repo.List(ctx, nil)
+	require.NoError(t, err)
+	require.Len(t, feeds, 2)
+	require.Equal(t, "Middle", feeds[0].DisplayTitle())
+	require.Equal(t, "Zed", feeds[1].DisplayTitle())
+	require.Equal(t, "Alpha", feeds[1].Title)
+
+	require.NoError(t, repo.UpdateTitles(ctx, id, "Beta", &custom))
+	feed, err := repo.GetByID(ctx, id)
+	require.NoError(t, err)
+	require.Equal(t, "Beta", feed.Title)
+	require.NotNil(t, feed.CustomTitle)
+	require.Equal(t, "Zed", *feed.CustomTitle)
+
+	require.NoError(t, repo.UpdateTitles(ctx, id, "Zed", nil))
+	feed, err = repo.GetByID(ctx, id)
+	require.NoError(t, err)
+	require.Equal(t, "Zed", feed.Title)
+	require.Nil(t, feed.CustomTitle)
+}
+
+func TestFeedRepository_SortOrder(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	repo := repository.NewFeedRepository(db, nil, nil)
+	ctx := context.Background()
+
+	folderID := testutil.SeedFolder(t, db, "Folder", nil, "article")
+	legacy := testutil.SeedFeed(t, db, model.Feed{Title: "Z legacy", URL: "u0"})
+	first, err := repo.Create(ctx, model.Feed{Title: "B", URL: "u1"})
+	require.NoError(t, err)
+	second, err := repo.Create(ctx, model.Feed{Title: "A", URL: "u2"})
+	require.NoError(t, err)
+	inFolder, err := repo.Create(ctx, model.Feed{Title: "C", URL: "u3", FolderID: &folderID})
+	require.NoError(t, err)
+	require.Equal(t, 1, first.SortOrder)
+	require.Equal(t, 2, second.SortOrder)
+	require.Equal(t, 1, inFolder.SortOrder)
+
+	feeds, err := repo.List(ctx, nil)
+	require.NoError(t, err)
+	require.Equal(t, []int64{legacy, first.ID, inFolder.ID, second.ID}, feedIDs(feeds))
+
+	require.NoError(t, repo.Reorder(ctx, []int64{second.ID, first.ID}))
+	feeds, err = repo.List(ctx, nil)
+	require.NoError(t, err)
+	require.Equal(t, []int64{legacy, second.ID, inFolder.ID, first.ID}, feedIDs(feeds))
+
+	// Moving to another folder places the feed last there
+	first.FolderID = &folderID
+	moved, err := repo.Update(ctx, first)
+	require.NoError(t, err)
+	require.Equal(t, 2, moved.SortOrder)
+	feeds, err = repo.List(ctx, &folderID)
+	require.NoError(t, err)
+	require.Equal(t, []int64{inFolder.ID, first.ID}, feedIDs(feeds))
+}
+
+func feedIDs(feeds []model.Feed) []int64 {
+	ids := make([]int64, 0, len(feeds))
+	for _, f := range feeds {
+		ids = append(ids, f.ID)
+	}
+	return ids
+}
+
+func TestFeedRepository_UpdateTypeByFolderIDs(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	repo := repository.NewFeedRepository(db, nil, nil)
 	ctx := context.Background()
 
 	folderID := testutil.SeedFolder(t, db, "Folder", nil, "article")
+	otherID := testutil.SeedFolder(t, db, "Other", nil, "article")
+	untouchedID := testutil.SeedFolder(t, db, "Untouched", nil, "article")
 	testutil.SeedFeed(t, db, model.Feed{Title: "Feed 1", URL: "u1", FolderID: &folderID, Type: "article"})
-	testutil.SeedFeed(t, db, model.Feed{Title: "Feed 2", URL: "u2", FolderID: &folderID, Type: "article"})
+	testutil.SeedFeed(t, db, model.Feed{Title: "Feed 2", URL: "u2", FolderID: &otherID, Type: "article"})
+	testutil.SeedFeed(t, db, model.Feed{Title: "Feed 3", URL: "u3", FolderID: &untouchedID, Type: "article"})
 
-	err := repo.UpdateTypeByFolderID(ctx, folderID, "picture")
+	err := repo.UpdateTypeByFolderIDs(ctx, []int64{folderID, otherID}, "picture")
 	require.NoError(t, err)
 
-	feeds, _ := repo.List(ctx, &folderID)
-	for _, feed := range feeds {
-		require.Equal(t, "picture", feed.Type)
+	for _, id := range []int64{folderID, otherID} {
+		feeds, _ := repo.List(ctx, &id)
+		for _, feed := range feeds {
+			require.Equal(t, "picture", feed.Type)
+		}
 	}
+	feeds, _ := repo.List(ctx, &untouchedID)
+	require.Equal(t, "article", feeds[0].Type)
+
+	require.NoError(t, repo.UpdateTypeByFolderIDs(ctx, nil, "picture"))
 }
 
 func TestFeedRepository_ClearAllIconPaths(t *testing.T) {
 	db := testutil.NewTestDB(t)
-	repo := repository.NewFeedRepository(db)
+	repo := repository.NewFeedRepository(db, nil, nil)
 	ctx := context.Background()
 
 	icon := "icon.png"
@@ -272,7 +659,7 @@ func TestFeedRepository_ClearAllIconPaths(t *testing.T) {
 
 func TestFeedRepository_ClearAllConditionalGet(t *testing.T) {
 	db := testutil.NewTestDB(t)
-	repo := repository.NewFeedRepository(db)
+	repo := repository.NewFeedRepository(db, nil, nil)
 	ctx := context.Background()
 
 	etag := "etag"
@@ -290,3 +677,252 @@ func TestFeedRepository_ClearAllConditionalGet(t *testing.T) {
 		require.Nil(t, feed.LastModified)
 	}
 }
+
+func TestFeedRepository_GetActivityStats(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	repo := repository.NewFeedRepository(db, nil, nil)
+	ctx := context.Background()
+
+	activeID := testutil.SeedFeed(t, db, model.Feed{Title: "Active", URL: "https://example.com/active"})
+	emptyID := testutil.SeedFeed(t, db, model.Feed{Title: "Empty", URL: "https://example.com/empty"})
+
+	recent := time.Now().UTC().Add(-48 * time.Hour).Truncate(time.Second)
+	old := time.Now().UTC().Add(-30 * 24 * time.Hour)
+	testutil.SeedEntry(t, db, model.Entry{FeedID: activeID, PublishedAt: &recent})
+	testutil.SeedEntry(t, db, model.Entry{FeedID: activeID, PublishedAt: &old})
+	// Undated entries count by their creation time
+	testutil.SeedEntry(t, db, model.Entry{FeedID: activeID})
+
+	stats, err := repo.GetActivityStats(ctx)
+	require.NoError(t, err)
+	require.Len(t, stats, 2)
+
+	byFeed := make(map[int64]model.FeedActivityStats, len(stats))
+	for _, stat := range stats {
+		byFeed[stat.FeedID] = stat
+	}
+
+	active := byFeed[activeID]
+	require.Equal(t, 2, active.EntriesLastWeek)
+	require.Equal(t, 3, active.TotalEntries)
+	require.NotNil(t, active.LastPublishedAt)
+	require.True(t, recent.Equal(*active.LastPublishedAt))
+
+	empty := byFeed[emptyID]
+	require.Equal(t, model.FeedActivityStats{FeedID: emptyID}, empty)
+}
+
+func TestFeedRepository_GetActivityStats_FractionalTimestamps(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	repo := repository.NewFeedRepository(db, nil, nil)
+	entries := repository.NewEntryRepository(db, nil, nil)
+	ctx := context.Background()
+
+	feedID := testutil.SeedFeed(t, db, model.Feed{Title: "Feed", URL: "https://example.com/rss"})
+	// CreateOrUpdate stores RFC3339Nano, unlike the seed helpers
+	published := time.Now().UTC().Add(-72 * time.Hour).Add(123456789 * time.Nanosecond)
+	url := "https://example.com/entry"
+	require.NoError(t, entries.CreateOrUpdate(ctx, model.Entry{FeedID: feedID, URL: &url, Hash: "hash", PublishedAt: &published}, 0))
+
+	stats, err := repo.GetActivityStats(ctx)
+	require.NoError(t, err)
+	require.Len(t, stats, 1)
+	require.Equal(t, 1, stats[0].EntriesLastWeek)
+	require.NotNil(t, stats[0].LastPublishedAt)
+	require.True(t, published.Truncate(time.Second).Equal(*stats[0].LastPublishedAt))
+}
+
+func TestFeedRepository_SoftDelete_HidesFeedAndEntries(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	repo := repository.NewFeedRepository(db, nil, nil)
+	entries := repository.NewEntryRepository(db, nil, nil)
+	ctx := context.Background()
+
+	deletedID := testutil.SeedFeed(t, db, model.Feed{Title: "Deleted", URL: "https://example.com/deleted"})
+	keptID := testutil.SeedFeed(t, db, model.Feed{Title: "Kept", URL: "https://example.com/kept"})
+	testutil.SeedEntry(t, db, model.Entry{FeedID: deletedID, Starred: true})
+	testutil.SeedEntry(t, db, model.Entry{FeedID: keptID})
+
+	require.NoError(t, repo.Delete(ctx, deletedID))
+
+	feeds, err := repo.List(ctx, nil)
+	require.NoError(t, err)
+	require.Len(t, feeds, 1)
+	require.Equal(t, keptID, feeds[0].ID)
+
+	listed, err := entries.List(ctx, repository.EntryListFilter{})
+	require.NoError(t, err)
+	require.Len(t, listed, 1)
+	require.Equal(t, keptID, listed[0].FeedID)
+
+	counts, err := entries.GetAllUnreadCounts(ctx)
+	require.NoError(t, err)
+	require.Len(t, counts, 1)
+	starred, err := entries.GetStarredCount(ctx)
+	require.NoError(t, err)
+	require.Zero(t, starred)
+
+	// The URL stays taken by the deleted row
+	found, err := repo.FindByURL(ctx, "https://example.com/deleted")
+	require.NoError(t, err)
+	require.NotNil(t, found)
+	require.NotNil(t, found.DeletedAt)
+}
+
+func TestFeedRepository_Restore(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	repo := repository.NewFeedRepository(db, nil, nil)
+	ctx := context.Background()
+
+	id := testutil.SeedFeed(t, db, model.Feed{Title: "Feed", URL: "https://example.com/rss"})
+	entryID := testutil.SeedEntry(t, db, model.Entry{FeedID: id})
+	require.NoError(t, repo.Delete(ctx, id))
+
+	require.NoError(t, repo.Restore(ctx, id, time.Now().Add(-time.Hour)))
+
+	feed, err := repo.GetByID(ctx, id)
+	require.NoError(t, err)
+	require.Nil(t, feed.DeletedAt)
+	_, err = repository.NewEntryRepository(db, nil, nil).GetByID(ctx, entryID)
+	require.NoError(t, err)
+
+	// Restoring a live feed is a miss
+	require.ErrorIs(t, repo.Restore(ctx, id, time.Time{}), sql.ErrNoRows)
+}
+
+func TestFeedRepository_Restore_OutsideWindow(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	repo := repository.NewFeedRepository(db, nil, nil)
+	ctx := context.Background()
+
+	id := testutil.SeedFeed(t, db, model.Feed{Title: "Feed", URL: "https://example.com/rss"})
+	_, err := db.ExecContext(ctx, `UPDATE feeds SET deleted_at = ? WHERE id = ?`, time.Now().Add(-8*24*time.Hour).UTC().Format(time.RFC3339Nano), id)
+	require.NoError(t, err)
+
+	require.ErrorIs(t, repo.Restore(ctx, id, time.Now().Add(-7*24*time.Hour)), sql.ErrNoRows)
+}
+
+func TestFeedRepository_Restore_MovesOutOfDeletedFolder(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	repo := repository.NewFeedRepository(db, nil, nil)
+	ctx := context.Background()
+
+	folderID := testutil.SeedFolder(t, db, "Folder", nil, "article")
+	id := testutil.SeedFeed(t, db, model.Feed{Title: "Feed", URL: "https://example.com/rss", FolderID: &folderID})
+	require.NoError(t, repository.NewFolderRepository(db, nil, nil).Delete(ctx, folderID))
+
+	require.NoError(t, repo.Restore(ctx, id, time.Time{}))
+
+	feed, err := repo.GetByID(ctx, id)
+	require.NoError(t, err)
+	require.Nil(t, feed.FolderID)
+}
+
+func TestFeedRepository_PurgeDeleted(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	repo := repository.NewFeedRepository(db, nil, nil)
+	ctx := context.Background()
+
+	oldID := testutil.SeedFeed(t, db, model.Feed{Title: "Old", URL: "https://example.com/old"})
+	recentID := testutil.SeedFeed(t, db, model.Feed{Title: "Recent", URL: "https://example.com/recent"})
+	testutil.SeedFeed(t, db, model.Feed{Title: "Live", URL: "https://example.com/live"})
+	testutil.SeedEntry(t, db, model.Entry{FeedID: oldID})
+	require.NoError(t, repo.Delete(ctx, recentID))
+	_, err := db.ExecContext(ctx, `UPDATE feeds SET deleted_at = ? WHERE id = ?`, time.Now().Add(-8*24*time.Hour).UTC().Format(time.RFC3339Nano), oldID)
+	require.NoError(t, err)
+
+	purged, err := repo.PurgeDeleted(ctx, time.Now().Add(-7*24*time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, int64(1), purged)
+
+	var feeds, entries int
+	require.NoError(t, db.QueryRowContext(ctx, `SELECT COUNT(*) FROM feeds`).Scan(&feeds))
+	require.NoError(t, db.QueryRowContext(ctx, `SELECT COUNT(*) FROM entries`).Scan(&entries))
+	require.Equal(t, 2, feeds)
+	require.Zero(t, entries)
+}
+
+func TestFeedRepository_IngestTokenHash(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	repo := repository.NewFeedRepository(db, nil, nil)
+	ctx := context.Background()
+
+	id := testutil.SeedFeed(t, db, model.Feed{Title: "Pushed", URL: "static://pushed"})
+
+	hash, err := repo.GetIngestTokenHash(ctx, id)
+	require.NoError(t, err)
+	require.Empty(t, hash)
+
+	require.NoError(t, repo.UpdateIngestTokenHash(ctx, id, "abc"))
+	hash, err = repo.GetIngestTokenHash(ctx, id)
+	require.NoError(t, err)
+	require.Equal(t, "abc", hash)
+
+	// Deleted feeds take no more entries
+	require.NoError(t, repo.Delete(ctx, id))
+	_, err = repo.GetIngestTokenHash(ctx, id)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestFeedRepository_MutedAuthors(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	repo := repository.NewFeedRepository(db, nil, nil)
+	ctx := context.Background()
+
+	id := testutil.SeedFeed(t, db, model.Feed{Title: "Group blog", URL: "https://example.com/feed"})
+	otherID := testutil.SeedFeed(t, db, model.Feed{Title: "Other", URL: "https://example.com/other"})
+
+	authors, err := repo.ListMutedAuthors(ctx, id)
+	require.NoError(t, err)
+	require.Empty(t, authors)
+
+	require.NoError(t, repo.MuteAuthor(ctx, id, "Jane Doe"))
+	require.NoError(t, repo.MuteAuthor(ctx, id, "Adam"))
+	// Muting again under another spelling keeps one row with the latest one
+	require.NoError(t, repo.MuteAuthor(ctx, id, "JANE DOE"))
+	authors, err = repo.ListMutedAuthors(ctx, id)
+	require.NoError(t, err)
+	require.Equal(t, []string{"Adam", "JANE DOE"}, authors)
+
+	muted, err := repo.IsAuthorMuted(ctx, id, " Jane Doe ")
+	require.NoError(t, err)
+	require.True(t, muted)
+	muted, err = repo.IsAuthorMuted(ctx, otherID, "Jane Doe")
+	require.NoError(t, err)
+	require.False(t, muted)
+
+	require.NoError(t, repo.UnmuteAuthor(ctx, id, "jane doe"))
+	require.ErrorIs(t, repo.UnmuteAuthor(ctx, id, "jane doe"), sql.ErrNoRows)
+	authors, err = repo.ListMutedAuthors(ctx, id)
+	require.NoError(t, err)
+	require.Equal(t, []string{"Adam"}, authors)
+}
+
+func TestFeedRepository_UpdateUserAgentAndAutoReadability(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	repo := repository.NewFeedRepository(db, nil, nil)
+	ctx := context.Background()
+
+	id := testutil.SeedFeed(t, db, model.Feed{Title: "Feed", URL: "u"})
+	feed, err := repo.GetByID(ctx, id)
+	require.NoError(t, err)
+	require.Nil(t, feed.UserAgent)
+	require.Nil(t, feed.AutoReadability)
+
+	userAgent := "Mozilla/5.0 (Feed)"
+	enabled := false
+	require.NoError(t, repo.UpdateUserAgent(ctx, id, &userAgent))
+	require.NoError(t, repo.UpdateAutoReadability(ctx, id, &enabled))
+	feed, err = repo.GetByID(ctx, id)
+	require.NoError(t, err)
+	require.Equal(t, userAgent, *feed.UserAgent)
+	require.False(t, *feed.AutoReadability)
+
+	require.NoError(t, repo.UpdateUserAgent(ctx, id, nil))
+	require.NoError(t, repo.UpdateAutoReadability(ctx, id, nil))
+	feeds, err := repo.List(ctx, nil)
+	require.NoError(t, err)
+	require.Len(t, feeds, 1)
+	require.Nil(t, feeds[0].UserAgent)
+	require.Nil(t, feeds[0].AutoReadability)
+}
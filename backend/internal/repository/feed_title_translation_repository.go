@@ -0,0 +1,98 @@
+//go:generate mockgen -source=$GOFILE -destination=mock/$GOFILE -package=mock
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gist/backend/internal/model"
+	"gist/backend/pkg/snowflake"
+)
+
+// FeedTitleTranslationRepository caches translated feed titles per language.
+type FeedTitleTranslationRepository interface {
+	GetBatch(ctx context.Context, feedIDs []int64, language string) (map[int64]*model.FeedTitleTranslation, error)
+	// Save replaces the feed's translation for language; sourceHash identifies the title it was made from.
+	Save(ctx context.Context, feedID int64, language, sourceHash, title string) error
+	DeleteAll(ctx context.Context) (int64, error)
+}
+
+type feedTitleTranslationRepository struct {
+	db      dbtx
+	changes *ChangeTracker
+}
+
+func NewFeedTitleTranslationRepository(db dbtx, changes *ChangeTracker) FeedTitleTranslationRepository {
+	return &feedTitleTranslationRepository{db: db, changes: changes}
+}
+
+func (r *feedTitleTranslationRepository) GetBatch(ctx context.Context, feedIDs []int64, language string) (map[int64]*model.FeedTitleTranslation, error) {
+	if len(feedIDs) == 0 {
+		return make(map[int64]*model.FeedTitleTranslation), nil
+	}
+
+	query := `SELECT id, feed_id, language, source_hash, title, created_at
+	          FROM feed_title_translations WHERE language = ? AND feed_id IN (`
+	args := make([]interface{}, 0, len(feedIDs)+1)
+	args = append(args, language)
+
+	for i, id := range feedIDs {
+		if i > 0 {
+			query += ","
+		}
+		query += "?"
+		args = append(args, id)
+	}
+	query += ")"
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make(map[int64]*model.FeedTitleTranslation)
+	for rows.Next() {
+		var t model.FeedTitleTranslation
+		var createdAt string
+
+		if err := rows.Scan(&t.ID, &t.FeedID, &t.Language, &t.SourceHash, &t.Title, &createdAt); err != nil {
+			return nil, err
+		}
+
+		t.CreatedAt, _ = parseTime(createdAt)
+		result[t.FeedID] = &t
+	}
+
+	return result, rows.Err()
+}
+
+func (r *feedTitleTranslationRepository) Save(ctx context.Context, feedID int64, language, sourceHash, title string) error {
+	// Translated titles are part of the feed list response
+	defer r.changes.Notify()
+
+	id := snowflake.NextID()
+	now := formatTime(time.Now())
+
+	_, err := r.db.ExecContext(
+		ctx,
+		`INSERT INTO feed_title_translations (id, feed_id, language, source_hash, title, created_at)
+		 VALUES (?, ?, ?, ?, ?, ?)
+		 ON CONFLICT(feed_id, language) DO UPDATE SET
+		   source_hash = excluded.source_hash,
+		   title = excluded.title,
+		   created_at = excluded.created_at`,
+		id, feedID, language, sourceHash, title, now,
+	)
+	return err
+}
+
+func (r *feedTitleTranslationRepository) DeleteAll(ctx context.Context) (int64, error) {
+	defer r.changes.Notify()
+
+	result, err := r.db.ExecContext(ctx, `DELETE FROM feed_title_translations`)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -6,6 +6,7 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"gist/backend/internal/model"
@@ -13,40 +14,70 @@ import (
 )
 
 type FolderRepository interface {
+	// Create returns ErrConflict when a live sibling already has the name.
 	Create(ctx context.Context, name string, parentID *int64, folderType string) (model.Folder, error)
 	GetByID(ctx context.Context, id int64) (model.Folder, error)
 	FindByName(ctx context.Context, name string, parentID *int64) (*model.Folder, error)
 	List(ctx context.Context) ([]model.Folder, error)
+	// Update returns ErrConflict when a live sibling under parentID already has the name.
 	Update(ctx context.Context, id int64, name string, parentID *int64) (model.Folder, error)
 	UpdateType(ctx context.Context, id int64, folderType string) error
+	// UpdateFeedDefaults replaces all of the folder's feed defaults.
+	UpdateFeedDefaults(ctx context.Context, id int64, defaults model.FeedDefaults) error
+	// ListDescendantIDs returns the live subfolders of folderID at any depth.
+	ListDescendantIDs(ctx context.Context, folderID int64) ([]int64, error)
+	// UpdateTypeCascade sets the type of the folder, its live descendants and the
+	// feeds in all of them in one transaction.
+	UpdateTypeCascade(ctx context.Context, id int64, folderType string) error
+	// Reorder sets sort_order to each folder's position in ids, in one transaction.
+	Reorder(ctx context.Context, ids []int64) error
+	// Delete soft-deletes the folder, its subfolders and their feeds with one shared timestamp.
 	Delete(ctx context.Context, id int64) error
+	// Restore undoes a Delete made at or after deletedSince, bringing back everything
+	// deleted with it. Returns sql.ErrNoRows when there is nothing to restore and
+	// ErrConflict when a live folder has taken the name in the meantime.
+	Restore(ctx context.Context, id int64, deletedSince time.Time) error
+	// PurgeDeleted hard-deletes folders soft-deleted before deletedBefore.
+	PurgeDeleted(ctx context.Context, deletedBefore time.Time) (int64, error)
 }
 
 type folderRepository struct {
-	db dbtx
+	db      dbtx
+	changes *ChangeTracker
+	writes  *WriteTracker
 }
 
-func NewFolderRepository(db dbtx) FolderRepository {
-	return &folderRepository{db: db}
+func NewFolderRepository(db dbtx, changes *ChangeTracker, writes *WriteTracker) FolderRepository {
+	return &folderRepository{db: db, changes: changes, writes: writes}
 }
 
 func (r *folderRepository) Create(ctx context.Context, name string, parentID *int64, folderType string) (model.Folder, error) {
+	defer r.changes.Notify()
+
 	id := snowflake.NextID()
 	now := time.Now().UTC()
 	if folderType == "" {
 		folderType = "article"
 	}
+	var sortOrder int
+	if err := r.db.QueryRowContext(ctx, nextFolderSortOrder, nullableInt64(parentID)).Scan(&sortOrder); err != nil {
+		return model.Folder{}, fmt.Errorf("next folder sort order: %w", err)
+	}
 	_, err := r.db.ExecContext(
 		ctx,
-		`INSERT INTO folders (id, name, parent_id, type, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?)`,
+		`INSERT INTO folders (id, name, parent_id, type, sort_order, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?)`,
 		id,
 		name,
 		nullableInt64(parentID),
 		folderType,
+		sortOrder,
 		formatTime(now),
 		formatTime(now),
 	)
 	if err != nil {
+		if isUniqueViolation(err) {
+			return model.Folder{}, ErrConflict
+		}
 		return model.Folder{}, fmt.Errorf("create folder: %w", err)
 	}
 
@@ -55,20 +86,22 @@ func (r *folderRepository) Create(ctx context.Context, name string, parentID *in
 		Name:      name,
 		ParentID:  parentID,
 		Type:      folderType,
+		SortOrder: sortOrder,
 		CreatedAt: now,
 		UpdatedAt: now,
 	}, nil
 }
 
 func (r *folderRepository) GetByID(ctx context.Context, id int64) (model.Folder, error) {
-	row := r.db.QueryRowContext(ctx, `SELECT id, name, parent_id, type, created_at, updated_at FROM folders WHERE id = ?`, id)
+	row := r.db.QueryRowContext(ctx, `SELECT id, name, parent_id, type, sort_order, created_at, updated_at, user_agent, auto_readability, auto_translate FROM folders WHERE id = ? AND deleted_at IS NULL`, id)
 
 	var folder model.Folder
 	var parentID sql.NullInt64
 	var folderType sql.NullString
 	var createdAt string
 	var updatedAt string
-	if err := row.Scan(&folder.ID, &folder.Name, &parentID, &folderType, &createdAt, &updatedAt); err != nil {
+	var defaults folderDefaultColumns
+	if err := row.Scan(&folder.ID, &folder.Name, &parentID, &folderType, &folder.SortOrder, &createdAt, &updatedAt, &defaults.userAgent, &defaults.autoReadability, &defaults.autoTranslate); err != nil {
 		return model.Folder{}, fmt.Errorf("get folder: %w", err)
 	}
 	if parentID.Valid {
@@ -79,6 +112,7 @@ func (r *folderRepository) GetByID(ctx context.Context, id int64) (model.Folder,
 	} else {
 		folder.Type = "article"
 	}
+	folder.FeedDefaults = defaults.feedDefaults()
 	var err error
 	folder.CreatedAt, err = parseTime(createdAt)
 	if err != nil {
@@ -93,10 +127,10 @@ func (r *folderRepository) GetByID(ctx context.Context, id int64) (model.Folder,
 }
 
 func (r *folderRepository) FindByName(ctx context.Context, name string, parentID *int64) (*model.Folder, error) {
-	query := `SELECT id, name, parent_id, type, created_at, updated_at FROM folders WHERE name = ? AND parent_id IS NULL`
+	query := `SELECT id, name, parent_id, type, sort_order, created_at, updated_at, user_agent, auto_readability, auto_translate FROM folders WHERE name = ? AND parent_id IS NULL AND deleted_at IS NULL`
 	args := []interface{}{name}
 	if parentID != nil {
-		query = `SELECT id, name, parent_id, type, created_at, updated_at FROM folders WHERE name = ? AND parent_id = ?`
+		query = `SELECT id, name, parent_id, type, sort_order, created_at, updated_at, user_agent, auto_readability, auto_translate FROM folders WHERE name = ? AND parent_id = ? AND deleted_at IS NULL`
 		args = []interface{}{name, *parentID}
 	}
 
@@ -106,7 +140,8 @@ func (r *folderRepository) FindByName(ctx context.Context, name string, parentID
 	var folderType sql.NullString
 	var createdAt string
 	var updatedAt string
-	if err := row.Scan(&folder.ID, &folder.Name, &parent, &folderType, &createdAt, &updatedAt); err != nil {
+	var defaults folderDefaultColumns
+	if err := row.Scan(&folder.ID, &folder.Name, &parent, &folderType, &folder.SortOrder, &createdAt, &updatedAt, &defaults.userAgent, &defaults.autoReadability, &defaults.autoTranslate); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, nil
 		}
@@ -120,6 +155,7 @@ func (r *folderRepository) FindByName(ctx context.Context, name string, parentID
 	} else {
 		folder.Type = "article"
 	}
+	folder.FeedDefaults = defaults.feedDefaults()
 	var err error
 	folder.CreatedAt, err = parseTime(createdAt)
 	if err != nil {
@@ -134,7 +170,7 @@ func (r *folderRepository) FindByName(ctx context.Context, name string, parentID
 }
 
 func (r *folderRepository) List(ctx context.Context) ([]model.Folder, error) {
-	rows, err := r.db.QueryContext(ctx, `SELECT id, name, parent_id, type, created_at, updated_at FROM folders ORDER BY name`)
+	rows, err := r.db.QueryContext(ctx, `SELECT id, name, parent_id, type, sort_order, created_at, updated_at, user_agent, auto_readability, auto_translate FROM folders WHERE deleted_at IS NULL ORDER BY sort_order, name`)
 	if err != nil {
 		return nil, fmt.Errorf("list folders: %w", err)
 	}
@@ -147,7 +183,8 @@ func (r *folderRepository) List(ctx context.Context) ([]model.Folder, error) {
 		var folderType sql.NullString
 		var createdAt string
 		var updatedAt string
-		if err := rows.Scan(&folder.ID, &folder.Name, &parentID, &folderType, &createdAt, &updatedAt); err != nil {
+		var defaults folderDefaultColumns
+		if err := rows.Scan(&folder.ID, &folder.Name, &parentID, &folderType, &folder.SortOrder, &createdAt, &updatedAt, &defaults.userAgent, &defaults.autoReadability, &defaults.autoTranslate); err != nil {
 			return nil, fmt.Errorf("scan folder: %w", err)
 		}
 		if parentID.Valid {
@@ -158,6 +195,7 @@ func (r *folderRepository) List(ctx context.Context) ([]model.Folder, error) {
 		} else {
 			folder.Type = "article"
 		}
+		folder.FeedDefaults = defaults.feedDefaults()
 		folder.CreatedAt, err = parseTime(createdAt)
 		if err != nil {
 			return nil, fmt.Errorf("parse folder created_at: %w", err)
@@ -175,17 +213,45 @@ func (r *folderRepository) List(ctx context.Context) ([]model.Folder, error) {
 	return folders, nil
 }
 
+// folderDefaultColumns holds the FeedDefaults columns of a scanned folder.
+type folderDefaultColumns struct {
+	userAgent       sql.NullString
+	autoReadability sql.NullInt64
+	autoTranslate   sql.NullInt64
+}
+
+func (c folderDefaultColumns) feedDefaults() model.FeedDefaults {
+	var defaults model.FeedDefaults
+	if c.userAgent.Valid {
+		defaults.UserAgent = &c.userAgent.String
+	}
+	defaults.AutoReadability = nullBoolPtr(c.autoReadability)
+	defaults.AutoTranslate = nullBoolPtr(c.autoTranslate)
+	return defaults
+}
+
+// nextFolderSortOrder appends to the end of the siblings under parent ?.
+const nextFolderSortOrder = `SELECT COALESCE(MAX(sort_order), 0) + 1 FROM folders WHERE parent_id IS ? AND deleted_at IS NULL`
+
 func (r *folderRepository) Update(ctx context.Context, id int64, name string, parentID *int64) (model.Folder, error) {
+	defer r.changes.Notify()
+
 	now := time.Now().UTC()
+	// A folder moved to another parent goes to the end of its new siblings
 	_, err := r.db.ExecContext(
 		ctx,
-		`UPDATE folders SET name = ?, parent_id = ?, updated_at = ? WHERE id = ?`,
+		`UPDATE folders SET name = ?, parent_id = ?, sort_order = CASE WHEN parent_id IS ? THEN sort_order ELSE (`+nextFolderSortOrder+`) END, updated_at = ? WHERE id = ?`,
 		name,
 		nullableInt64(parentID),
+		nullableInt64(parentID),
+		nullableInt64(parentID),
 		formatTime(now),
 		id,
 	)
 	if err != nil {
+		if isUniqueViolation(err) {
+			return model.Folder{}, ErrConflict
+		}
 		return model.Folder{}, fmt.Errorf("update folder: %w", err)
 	}
 
@@ -193,6 +259,8 @@ func (r *folderRepository) Update(ctx context.Context, id int64, name string, pa
 }
 
 func (r *folderRepository) UpdateType(ctx context.Context, id int64, folderType string) error {
+	defer r.changes.Notify()
+
 	_, err := r.db.ExecContext(
 		ctx,
 		`UPDATE folders SET type = ?, updated_at = ? WHERE id = ?`,
@@ -203,9 +271,171 @@ func (r *folderRepository) UpdateType(ctx context.Context, id int64, folderType
 	return err
 }
 
+func (r *folderRepository) UpdateFeedDefaults(ctx context.Context, id int64, defaults model.FeedDefaults) error {
+	defer r.changes.Notify()
+
+	_, err := r.db.ExecContext(
+		ctx,
+		`UPDATE folders SET user_agent = ?, auto_readability = ?, auto_translate = ?, updated_at = ? WHERE id = ?`,
+		nullableString(defaults.UserAgent),
+		nullableBool(defaults.AutoReadability),
+		nullableBool(defaults.AutoTranslate),
+		formatTime(time.Now()),
+		id,
+	)
+	return err
+}
+
+func (r *folderRepository) ListDescendantIDs(ctx context.Context, folderID int64) ([]int64, error) {
+	rows, err := r.db.QueryContext(ctx, activeFolderTree+`SELECT id FROM tree WHERE id <> ?`, folderID, folderID)
+	if err != nil {
+		return nil, fmt.Errorf("list descendant folders: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scan descendant folder: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate descendant folders: %w", err)
+	}
+	return ids, nil
+}
+
+func (r *folderRepository) UpdateTypeCascade(ctx context.Context, id int64, folderType string) error {
+	defer r.changes.Notify()
+
+	err := withTx(ctx, r.db, r.writes, func(tx dbtx) error {
+		descendants, err := (&folderRepository{db: tx}).ListDescendantIDs(ctx, id)
+		if err != nil {
+			return err
+		}
+		ids := append([]int64{id}, descendants...)
+
+		args := make([]interface{}, 0, len(ids)+2)
+		args = append(args, folderType, formatTime(time.Now()))
+		for _, folderID := range ids {
+			args = append(args, folderID)
+		}
+		placeholders := strings.TrimSuffix(strings.Repeat("?,", len(ids)), ",")
+		if _, err := tx.ExecContext(ctx, `UPDATE folders SET type = ?, updated_at = ? WHERE id IN (`+placeholders+`)`, args...); err != nil {
+			return err
+		}
+		return (&feedRepository{db: tx}).UpdateTypeByFolderIDs(ctx, ids, folderType)
+	})
+	if err != nil {
+		return fmt.Errorf("update folder tree type: %w", err)
+	}
+	return nil
+}
+
+func (r *folderRepository) Reorder(ctx context.Context, ids []int64) error {
+	defer r.changes.Notify()
+
+	now := formatTime(time.Now())
+	err := withTx(ctx, r.db, r.writes, func(tx dbtx) error {
+		for i, id := range ids {
+			if _, err := tx.ExecContext(ctx, `UPDATE folders SET sort_order = ?, updated_at = ? WHERE id = ?`, i+1, now, id); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("reorder folders: %w", err)
+	}
+	return nil
+}
+
+// activeFolderTree selects the live folder ? and all its live descendants.
+const activeFolderTree = `WITH RECURSIVE tree(id) AS (
+	SELECT id FROM folders WHERE id = ? AND deleted_at IS NULL
+	UNION ALL
+	SELECT f.id FROM folders f JOIN tree ON f.parent_id = tree.id WHERE f.deleted_at IS NULL
+)`
+
+// deletedFolderTree selects folder ? and the descendants deleted in the same Delete call.
+const deletedFolderTree = `WITH RECURSIVE tree(id) AS (
+	SELECT ?
+	UNION ALL
+	SELECT f.id FROM folders f JOIN tree ON f.parent_id = tree.id WHERE f.deleted_at = ?
+)`
+
 func (r *folderRepository) Delete(ctx context.Context, id int64) error {
-	if _, err := r.db.ExecContext(ctx, `DELETE FROM folders WHERE id = ?`, id); err != nil {
+	defer r.changes.Notify()
+
+	now := formatTime(time.Now())
+	err := withTx(ctx, r.db, r.writes, func(tx dbtx) error {
+		// Feeds first: the tree query only follows folders that are still live
+		if _, err := tx.ExecContext(ctx, activeFolderTree+`
+			UPDATE feeds SET deleted_at = ?, updated_at = ?
+			WHERE deleted_at IS NULL AND folder_id IN (SELECT id FROM tree)
+		`, id, now, now); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx, activeFolderTree+`
+			UPDATE folders SET deleted_at = ?, updated_at = ? WHERE id IN (SELECT id FROM tree)
+		`, id, now, now)
+		return err
+	})
+	if err != nil {
 		return fmt.Errorf("delete folder: %w", err)
 	}
 	return nil
 }
+
+func (r *folderRepository) Restore(ctx context.Context, id int64, deletedSince time.Time) error {
+	defer r.changes.Notify()
+
+	return withTx(ctx, r.db, r.writes, func(tx dbtx) error {
+		var deletedAt string
+		if err := tx.QueryRowContext(ctx,
+			`SELECT deleted_at FROM folders WHERE id = ? AND deleted_at IS NOT NULL AND julianday(deleted_at) >= julianday(?)`,
+			id, formatTime(deletedSince),
+		).Scan(&deletedAt); err != nil {
+			return err
+		}
+
+		now := formatTime(time.Now())
+		// A folder whose parent was deleted separately comes back at the top level
+		if _, err := tx.ExecContext(ctx,
+			`UPDATE folders SET parent_id = NULL WHERE id = ? AND parent_id IN (SELECT id FROM folders WHERE deleted_at IS NOT NULL AND deleted_at <> ?)`,
+			id, deletedAt,
+		); err != nil {
+			return fmt.Errorf("restore folder parent: %w", err)
+		}
+		// Feeds first: the tree query follows folders still carrying the shared timestamp
+		if _, err := tx.ExecContext(ctx, deletedFolderTree+`
+			UPDATE feeds SET deleted_at = NULL, updated_at = ?
+			WHERE deleted_at = ? AND folder_id IN (SELECT id FROM tree)
+		`, id, deletedAt, now, deletedAt); err != nil {
+			return fmt.Errorf("restore folder feeds: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx, deletedFolderTree+`
+			UPDATE folders SET deleted_at = NULL, updated_at = ?
+			WHERE deleted_at = ? AND id IN (SELECT id FROM tree)
+		`, id, deletedAt, now, deletedAt); err != nil {
+			if isUniqueViolation(err) {
+				return ErrConflict
+			}
+			return fmt.Errorf("restore folders: %w", err)
+		}
+		return nil
+	})
+}
+
+// PurgeDeleted relies on ON DELETE CASCADE to take subfolders with their parent.
+func (r *folderRepository) PurgeDeleted(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	defer r.changes.Notify()
+
+	result, err := r.db.ExecContext(ctx, `DELETE FROM folders WHERE deleted_at IS NOT NULL AND julianday(deleted_at) < julianday(?)`, formatTime(deletedBefore))
+	if err != nil {
+		return 0, fmt.Errorf("purge deleted folders: %w", err)
+	}
+	return result.RowsAffected()
+}
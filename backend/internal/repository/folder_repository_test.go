@@ -7,7 +7,9 @@ import (
 	"gist/backend/internal/repository"
 	"sync"
 	"testing"
+	"time"
 
+	"gist/backend/internal/model"
 	"gist/backend/internal/repository/testutil"
 
 	"github.com/stretchr/testify/require"
@@ -16,7 +18,7 @@ import (
 func TestFolderRepository_Create_Success(t *testing.T) {
 	t.Parallel()
 	db := testutil.NewTestDB(t)
-	repo := repository.NewFolderRepository(db)
+	repo := repository.NewFolderRepository(db, nil, nil)
 	ctx := context.Background()
 
 	folder, err := repo.Create(ctx, "Tech News", nil, "article")
@@ -32,7 +34,7 @@ func TestFolderRepository_Create_Success(t *testing.T) {
 func TestFolderRepository_Create_WithParent(t *testing.T) {
 	t.Parallel()
 	db := testutil.NewTestDB(t)
-	repo := repository.NewFolderRepository(db)
+	repo := repository.NewFolderRepository(db, nil, nil)
 	ctx := context.Background()
 
 	// Create parent folder
@@ -48,7 +50,7 @@ func TestFolderRepository_Create_WithParent(t *testing.T) {
 func TestFolderRepository_Create_DefaultType(t *testing.T) {
 	t.Parallel()
 	db := testutil.NewTestDB(t)
-	repo := repository.NewFolderRepository(db)
+	repo := repository.NewFolderRepository(db, nil, nil)
 	ctx := context.Background()
 
 	folder, err := repo.Create(ctx, "Test", nil, "")
@@ -59,7 +61,7 @@ func TestFolderRepository_Create_DefaultType(t *testing.T) {
 func TestFolderRepository_GetByID_Success(t *testing.T) {
 	t.Parallel()
 	db := testutil.NewTestDB(t)
-	repo := repository.NewFolderRepository(db)
+	repo := repository.NewFolderRepository(db, nil, nil)
 	ctx := context.Background()
 
 	id := testutil.SeedFolder(t, db, "Test Folder", nil, "picture")
@@ -74,7 +76,7 @@ func TestFolderRepository_GetByID_Success(t *testing.T) {
 func TestFolderRepository_GetByID_NotFound(t *testing.T) {
 	t.Parallel()
 	db := testutil.NewTestDB(t)
-	repo := repository.NewFolderRepository(db)
+	repo := repository.NewFolderRepository(db, nil, nil)
 	ctx := context.Background()
 
 	_, err := repo.GetByID(ctx, 99999)
@@ -85,7 +87,7 @@ func TestFolderRepository_GetByID_NotFound(t *testing.T) {
 func TestFolderRepository_FindByName_Success(t *testing.T) {
 	t.Parallel()
 	db := testutil.NewTestDB(t)
-	repo := repository.NewFolderRepository(db)
+	repo := repository.NewFolderRepository(db, nil, nil)
 	ctx := context.Background()
 
 	parentID := testutil.SeedFolder(t, db, "Parent", nil, "article")
@@ -101,7 +103,7 @@ func TestFolderRepository_FindByName_Success(t *testing.T) {
 func TestFolderRepository_FindByName_NotFound(t *testing.T) {
 	t.Parallel()
 	db := testutil.NewTestDB(t)
-	repo := repository.NewFolderRepository(db)
+	repo := repository.NewFolderRepository(db, nil, nil)
 	ctx := context.Background()
 
 	folder, err := repo.FindByName(ctx, "NonExistent", nil)
@@ -112,7 +114,7 @@ func TestFolderRepository_FindByName_NotFound(t *testing.T) {
 func TestFolderRepository_List_Success(t *testing.T) {
 	t.Parallel()
 	db := testutil.NewTestDB(t)
-	repo := repository.NewFolderRepository(db)
+	repo := repository.NewFolderRepository(db, nil, nil)
 	ctx := context.Background()
 
 	testutil.SeedFolder(t, db, "Folder A", nil, "article")
@@ -130,7 +132,7 @@ func TestFolderRepository_List_Success(t *testing.T) {
 func TestFolderRepository_Update_Success(t *testing.T) {
 	t.Parallel()
 	db := testutil.NewTestDB(t)
-	repo := repository.NewFolderRepository(db)
+	repo := repository.NewFolderRepository(db, nil, nil)
 	ctx := context.Background()
 
 	id := testutil.SeedFolder(t, db, "Original Name", nil, "article")
@@ -147,7 +149,7 @@ func TestFolderRepository_Update_Success(t *testing.T) {
 func TestFolderRepository_UpdateType(t *testing.T) {
 	t.Parallel()
 	db := testutil.NewTestDB(t)
-	repo := repository.NewFolderRepository(db)
+	repo := repository.NewFolderRepository(db, nil, nil)
 	ctx := context.Background()
 
 	id := testutil.SeedFolder(t, db, "Folder", nil, "article")
@@ -160,10 +162,77 @@ func TestFolderRepository_UpdateType(t *testing.T) {
 	require.Equal(t, "picture", folder.Type)
 }
 
+func TestFolderRepository_UpdateFeedDefaults(t *testing.T) {
+	t.Parallel()
+	db := testutil.NewTestDB(t)
+	repo := repository.NewFolderRepository(db, nil, nil)
+	ctx := context.Background()
+
+	id := testutil.SeedFolder(t, db, "Folder", nil, "article")
+	folder, err := repo.GetByID(ctx, id)
+	require.NoError(t, err)
+	require.Equal(t, model.FeedDefaults{}, folder.FeedDefaults)
+
+	userAgent := "Mozilla/5.0 (Folder)"
+	readability, translate := true, false
+	require.NoError(t, repo.UpdateFeedDefaults(ctx, id, model.FeedDefaults{UserAgent: &userAgent, AutoReadability: &readability, AutoTranslate: &translate}))
+	folder, err = repo.GetByID(ctx, id)
+	require.NoError(t, err)
+	require.Equal(t, userAgent, *folder.FeedDefaults.UserAgent)
+	require.True(t, *folder.FeedDefaults.AutoReadability)
+	require.False(t, *folder.FeedDefaults.AutoTranslate)
+
+	// Every option is replaced, so omitted ones go back to inheriting
+	require.NoError(t, repo.UpdateFeedDefaults(ctx, id, model.FeedDefaults{AutoReadability: &readability}))
+	folders, err := repo.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, folders, 1)
+	require.Nil(t, folders[0].FeedDefaults.UserAgent)
+	require.True(t, *folders[0].FeedDefaults.AutoReadability)
+	require.Nil(t, folders[0].FeedDefaults.AutoTranslate)
+}
+
+func TestFolderRepository_UpdateTypeCascade(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	repo := repository.NewFolderRepository(db, nil, nil)
+	feeds := repository.NewFeedRepository(db, nil, nil)
+	ctx := context.Background()
+
+	parentID := testutil.SeedFolder(t, db, "Parent", nil, "article")
+	childID := testutil.SeedFolder(t, db, "Child", &parentID, "article")
+	grandchildID := testutil.SeedFolder(t, db, "Grandchild", &childID, "article")
+	siblingID := testutil.SeedFolder(t, db, "Sibling", nil, "article")
+	testutil.SeedFeed(t, db, model.Feed{Title: "A", URL: "https://example.com/a", FolderID: &parentID, Type: "article"})
+	grandchildFeed := testutil.SeedFeed(t, db, model.Feed{Title: "B", URL: "https://example.com/b", FolderID: &grandchildID, Type: "article"})
+	siblingFeed := testutil.SeedFeed(t, db, model.Feed{Title: "C", URL: "https://example.com/c", FolderID: &siblingID, Type: "article"})
+
+	descendants, err := repo.ListDescendantIDs(ctx, parentID)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []int64{childID, grandchildID}, descendants)
+
+	require.NoError(t, repo.UpdateTypeCascade(ctx, parentID, "picture"))
+
+	for _, id := range []int64{parentID, childID, grandchildID} {
+		folder, err := repo.GetByID(ctx, id)
+		require.NoError(t, err)
+		require.Equal(t, "picture", folder.Type)
+	}
+	sibling, err := repo.GetByID(ctx, siblingID)
+	require.NoError(t, err)
+	require.Equal(t, "article", sibling.Type)
+
+	feed, err := feeds.GetByID(ctx, grandchildFeed)
+	require.NoError(t, err)
+	require.Equal(t, "picture", feed.Type)
+	feed, err = feeds.GetByID(ctx, siblingFeed)
+	require.NoError(t, err)
+	require.Equal(t, "article", feed.Type)
+}
+
 func TestFolderRepository_Delete_Success(t *testing.T) {
 	t.Parallel()
 	db := testutil.NewTestDB(t)
-	repo := repository.NewFolderRepository(db)
+	repo := repository.NewFolderRepository(db, nil, nil)
 	ctx := context.Background()
 
 	id := testutil.SeedFolder(t, db, "To Delete", nil, "article")
@@ -180,7 +249,7 @@ func TestFolderRepository_Delete_Success(t *testing.T) {
 func TestFolderRepository_Delete_CascadeChildren(t *testing.T) {
 	t.Parallel()
 	db := testutil.NewTestDB(t)
-	repo := repository.NewFolderRepository(db)
+	repo := repository.NewFolderRepository(db, nil, nil)
 	ctx := context.Background()
 
 	// Create parent and child
@@ -199,7 +268,7 @@ func TestFolderRepository_Delete_CascadeChildren(t *testing.T) {
 
 func TestFolderRepository_Create_Concurrent(t *testing.T) {
 	db := testutil.NewTestDB(t)
-	repo := repository.NewFolderRepository(db)
+	repo := repository.NewFolderRepository(db, nil, nil)
 	ctx := context.Background()
 
 	const goroutines = 10
@@ -226,3 +295,220 @@ func TestFolderRepository_Create_Concurrent(t *testing.T) {
 	wg.Wait()
 	require.Len(t, ids, goroutines)
 }
+
+func TestFolderRepository_Create_ConcurrentSameName(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	repo := repository.NewFolderRepository(db, nil, nil)
+	ctx := context.Background()
+
+	parentID := testutil.SeedFolder(t, db, "Parent", nil, "article")
+
+	for _, parent := range []*int64{nil, &parentID} {
+		const goroutines = 10
+		var wg sync.WaitGroup
+		var mu sync.Mutex
+		created, conflicts := 0, 0
+
+		for i := 0; i < goroutines; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				_, err := repo.Create(ctx, "Tech", parent, "article")
+				mu.Lock()
+				defer mu.Unlock()
+				if errors.Is(err, repository.ErrConflict) {
+					conflicts++
+					return
+				}
+				require.NoError(t, err)
+				created++
+			}()
+		}
+
+		wg.Wait()
+		require.Equal(t, 1, created)
+		require.Equal(t, goroutines-1, conflicts)
+
+		var count int
+		require.NoError(t, db.QueryRowContext(ctx, `SELECT COUNT(*) FROM folders WHERE name = 'Tech' AND parent_id IS ?`, parent).Scan(&count))
+		require.Equal(t, 1, count)
+	}
+}
+
+func TestFolderRepository_UniqueNameIgnoresDeletedFolders(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	repo := repository.NewFolderRepository(db, nil, nil)
+	ctx := context.Background()
+
+	first, err := repo.Create(ctx, "Tech", nil, "article")
+	require.NoError(t, err)
+	require.NoError(t, repo.Delete(ctx, first.ID))
+
+	// The trashed name is free again
+	second, err := repo.Create(ctx, "Tech", nil, "article")
+	require.NoError(t, err)
+
+	// Restoring would now produce twins
+	err = repo.Restore(ctx, first.ID, time.Now().Add(-time.Hour))
+	require.ErrorIs(t, err, repository.ErrConflict)
+
+	_, err = repo.Update(ctx, second.ID, "News", nil)
+	require.NoError(t, err)
+	require.NoError(t, repo.Restore(ctx, first.ID, time.Now().Add(-time.Hour)))
+
+	other, err := repo.Create(ctx, "Other", nil, "article")
+	require.NoError(t, err)
+	_, err = repo.Update(ctx, other.ID, "News", nil)
+	require.ErrorIs(t, err, repository.ErrConflict)
+}
+
+func TestFolderRepository_Delete_SoftDeletesTreeAndFeeds(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	repo := repository.NewFolderRepository(db, nil, nil)
+	feeds := repository.NewFeedRepository(db, nil, nil)
+	ctx := context.Background()
+
+	parentID := testutil.SeedFolder(t, db, "Parent", nil, "article")
+	childID := testutil.SeedFolder(t, db, "Child", &parentID, "article")
+	parentFeed := testutil.SeedFeed(t, db, model.Feed{Title: "A", URL: "https://example.com/a", FolderID: &parentID})
+	childFeed := testutil.SeedFeed(t, db, model.Feed{Title: "B", URL: "https://example.com/b", FolderID: &childID})
+
+	require.NoError(t, repo.Delete(ctx, parentID))
+
+	folders, err := repo.List(ctx)
+	require.NoError(t, err)
+	require.Empty(t, folders)
+	listed, err := feeds.List(ctx, nil)
+	require.NoError(t, err)
+	require.Empty(t, listed)
+
+	// Rows are kept for restore
+	var count int
+	require.NoError(t, db.QueryRowContext(ctx, `SELECT COUNT(*) FROM feeds WHERE id IN (?, ?)`, parentFeed, childFeed).Scan(&count))
+	require.Equal(t, 2, count)
+}
+
+func TestFolderRepository_Restore_BringsBackWhatWasDeletedTogether(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	repo := repository.NewFolderRepository(db, nil, nil)
+	feeds := repository.NewFeedRepository(db, nil, nil)
+	ctx := context.Background()
+
+	parentID := testutil.SeedFolder(t, db, "Parent", nil, "article")
+	childID := testutil.SeedFolder(t, db, "Child", &parentID, "article")
+	keptFeed := testutil.SeedFeed(t, db, model.Feed{Title: "A", URL: "https://example.com/a", FolderID: &childID})
+	earlierFeed := testutil.SeedFeed(t, db, model.Feed{Title: "B", URL: "https://example.com/b", FolderID: &parentID})
+
+	// Deleted on its own before the folder, so it stays deleted
+	require.NoError(t, feeds.Delete(ctx, earlierFeed))
+	_, err := db.ExecContext(ctx, `UPDATE feeds SET deleted_at = ? WHERE id = ?`, time.Now().Add(-time.Hour).UTC().Format(time.RFC3339Nano), earlierFeed)
+	require.NoError(t, err)
+	require.NoError(t, repo.Delete(ctx, parentID))
+
+	require.NoError(t, repo.Restore(ctx, parentID, time.Now().Add(-time.Minute)))
+
+	folders, err := repo.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, folders, 2)
+	listed, err := feeds.List(ctx, nil)
+	require.NoError(t, err)
+	require.Len(t, listed, 1)
+	require.Equal(t, keptFeed, listed[0].ID)
+}
+
+func TestFolderRepository_Restore_NotDeleted(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	repo := repository.NewFolderRepository(db, nil, nil)
+
+	id := testutil.SeedFolder(t, db, "Folder", nil, "article")
+	err := repo.Restore(context.Background(), id, time.Time{})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestFolderRepository_Restore_ChildOfSeparatelyDeletedParent(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	repo := repository.NewFolderRepository(db, nil, nil)
+	ctx := context.Background()
+
+	parentID := testutil.SeedFolder(t, db, "Parent", nil, "article")
+	childID := testutil.SeedFolder(t, db, "Child", &parentID, "article")
+	require.NoError(t, repo.Delete(ctx, childID))
+	_, err := db.ExecContext(ctx, `UPDATE folders SET deleted_at = ? WHERE id = ?`, time.Now().Add(-time.Hour).UTC().Format(time.RFC3339Nano), childID)
+	require.NoError(t, err)
+	require.NoError(t, repo.Delete(ctx, parentID))
+
+	require.NoError(t, repo.Restore(ctx, childID, time.Time{}))
+
+	child, err := repo.GetByID(ctx, childID)
+	require.NoError(t, err)
+	require.Nil(t, child.ParentID)
+	_, err = repo.GetByID(ctx, parentID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestFolderRepository_PurgeDeleted(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	repo := repository.NewFolderRepository(db, nil, nil)
+	ctx := context.Background()
+
+	parentID := testutil.SeedFolder(t, db, "Parent", nil, "article")
+	testutil.SeedFolder(t, db, "Child", &parentID, "article")
+	testutil.SeedFolder(t, db, "Live", nil, "article")
+	require.NoError(t, repo.Delete(ctx, parentID))
+	_, err := db.ExecContext(ctx, `UPDATE folders SET deleted_at = ? WHERE deleted_at IS NOT NULL`, time.Now().Add(-8*24*time.Hour).UTC().Format(time.RFC3339Nano))
+	require.NoError(t, err)
+
+	// Cascaded child rows are not counted by RowsAffected
+	purged, err := repo.PurgeDeleted(ctx, time.Now().Add(-7*24*time.Hour))
+	require.NoError(t, err)
+	require.NotZero(t, purged)
+
+	var count int
+	require.NoError(t, db.QueryRowContext(ctx, `SELECT COUNT(*) FROM folders`).Scan(&count))
+	require.Equal(t, 1, count)
+}
+
+func TestFolderRepository_SortOrder(t *testing.T) {
+	t.Parallel()
+	db := testutil.NewTestDB(t)
+	repo := repository.NewFolderRepository(db, nil, nil)
+	ctx := context.Background()
+
+	// Untouched rows keep alphabetical order and new folders are appended
+	legacyB := testutil.SeedFolder(t, db, "B", nil, "article")
+	legacyA := testutil.SeedFolder(t, db, "A", nil, "article")
+	created, err := repo.Create(ctx, "0 New", nil, "article")
+	require.NoError(t, err)
+	require.Equal(t, 1, created.SortOrder)
+
+	folders, err := repo.List(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []int64{legacyA, legacyB, created.ID}, folderIDs(folders))
+
+	require.NoError(t, repo.Reorder(ctx, []int64{created.ID, legacyB, legacyA}))
+	folders, err = repo.List(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []int64{created.ID, legacyB, legacyA}, folderIDs(folders))
+
+	// Moving to another parent places the folder last there
+	child, err := repo.Create(ctx, "Child", &legacyA, "article")
+	require.NoError(t, err)
+	require.Equal(t, 1, child.SortOrder)
+	moved, err := repo.Update(ctx, legacyB, "B", &legacyA)
+	require.NoError(t, err)
+	require.Equal(t, 2, moved.SortOrder)
+
+	// Renaming in place keeps the position
+	renamed, err := repo.Update(ctx, legacyB, "B2", &legacyA)
+	require.NoError(t, err)
+	require.Equal(t, 2, renamed.SortOrder)
+}
+
+func folderIDs(folders []model.Folder) []int64 {
+	ids := make([]int64, 0, len(folders))
+	for _, f := range folders {
+		ids = append(ids, f.ID)
+	}
+	return ids
+}
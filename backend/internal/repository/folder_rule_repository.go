@@ -0,0 +1,145 @@
+//go:generate mockgen -source=$GOFILE -destination=mock/$GOFILE -package=mock
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"gist/backend/internal/model"
+	"gist/backend/pkg/snowflake"
+)
+
+type FolderRuleRepository interface {
+	// List returns the rules of live folders in evaluation order: by priority, then oldest first.
+	List(ctx context.Context) ([]model.FolderRule, error)
+	ListByFolder(ctx context.Context, folderID int64) ([]model.FolderRule, error)
+	GetByID(ctx context.Context, id int64) (model.FolderRule, error)
+	Create(ctx context.Context, rule model.FolderRule) (model.FolderRule, error)
+	// Update returns sql.ErrNoRows when the rule does not exist.
+	Update(ctx context.Context, rule model.FolderRule) (model.FolderRule, error)
+	// Delete returns sql.ErrNoRows when the rule does not exist.
+	Delete(ctx context.Context, id int64) error
+}
+
+type folderRuleRepository struct {
+	db dbtx
+}
+
+func NewFolderRuleRepository(db dbtx) FolderRuleRepository {
+	return &folderRuleRepository{db: db}
+}
+
+const folderRuleColumns = `r.id, r.folder_id, r.pattern, r.match_field, r.priority, r.created_at, r.updated_at`
+
+func (r *folderRuleRepository) List(ctx context.Context) ([]model.FolderRule, error) {
+	return r.list(ctx, `SELECT `+folderRuleColumns+` FROM folder_rules r JOIN folders f ON f.id = r.folder_id WHERE f.deleted_at IS NULL ORDER BY r.priority, r.id`)
+}
+
+func (r *folderRuleRepository) ListByFolder(ctx context.Context, folderID int64) ([]model.FolderRule, error) {
+	return r.list(ctx, `SELECT `+folderRuleColumns+` FROM folder_rules r WHERE r.folder_id = ? ORDER BY r.priority, r.id`, folderID)
+}
+
+func (r *folderRuleRepository) list(ctx context.Context, query string, args ...interface{}) ([]model.FolderRule, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list folder rules: %w", err)
+	}
+	defer rows.Close()
+
+	var rules []model.FolderRule
+	for rows.Next() {
+		rule, err := scanFolderRule(rows)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate folder rules: %w", err)
+	}
+	return rules, nil
+}
+
+func (r *folderRuleRepository) GetByID(ctx context.Context, id int64) (model.FolderRule, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT `+folderRuleColumns+` FROM folder_rules r WHERE r.id = ?`, id)
+	return scanFolderRule(row)
+}
+
+func (r *folderRuleRepository) Create(ctx context.Context, rule model.FolderRule) (model.FolderRule, error) {
+	rule.ID = snowflake.NextID()
+	now := time.Now().UTC()
+	_, err := r.db.ExecContext(
+		ctx,
+		`INSERT INTO folder_rules (id, folder_id, pattern, match_field, priority, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		rule.ID,
+		rule.FolderID,
+		rule.Pattern,
+		rule.MatchField,
+		rule.Priority,
+		formatTime(now),
+		formatTime(now),
+	)
+	if err != nil {
+		return model.FolderRule{}, fmt.Errorf("create folder rule: %w", err)
+	}
+	rule.CreatedAt = now
+	rule.UpdatedAt = now
+	return rule, nil
+}
+
+func (r *folderRuleRepository) Update(ctx context.Context, rule model.FolderRule) (model.FolderRule, error) {
+	now := time.Now().UTC()
+	result, err := r.db.ExecContext(
+		ctx,
+		`UPDATE folder_rules SET pattern = ?, match_field = ?, priority = ?, updated_at = ? WHERE id = ?`,
+		rule.Pattern,
+		rule.MatchField,
+		rule.Priority,
+		formatTime(now),
+		rule.ID,
+	)
+	if err != nil {
+		return model.FolderRule{}, fmt.Errorf("update folder rule: %w", err)
+	}
+	if affected, err := result.RowsAffected(); err != nil {
+		return model.FolderRule{}, fmt.Errorf("update folder rule: %w", err)
+	} else if affected == 0 {
+		return model.FolderRule{}, sql.ErrNoRows
+	}
+	return r.GetByID(ctx, rule.ID)
+}
+
+func (r *folderRuleRepository) Delete(ctx context.Context, id int64) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM folder_rules WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("delete folder rule: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("delete folder rule: %w", err)
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func scanFolderRule(row interface {
+	Scan(dest ...interface{}) error
+}) (model.FolderRule, error) {
+	var rule model.FolderRule
+	var createdAt, updatedAt string
+	if err := row.Scan(&rule.ID, &rule.FolderID, &rule.Pattern, &rule.MatchField, &rule.Priority, &createdAt, &updatedAt); err != nil {
+		return model.FolderRule{}, fmt.Errorf("scan folder rule: %w", err)
+	}
+	var err error
+	if rule.CreatedAt, err = parseTime(createdAt); err != nil {
+		return model.FolderRule{}, fmt.Errorf("parse folder rule created_at: %w", err)
+	}
+	if rule.UpdatedAt, err = parseTime(updatedAt); err != nil {
+		return model.FolderRule{}, fmt.Errorf("parse folder rule updated_at: %w", err)
+	}
+	return rule, nil
+}
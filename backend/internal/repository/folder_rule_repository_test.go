@@ -0,0 +1,94 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"gist/backend/internal/model"
+	"gist/backend/internal/repository"
+	"gist/backend/internal/repository/testutil"
+)
+
+func TestFolderRuleRepository_CRUD(t *testing.T) {
+	t.Parallel()
+	db := testutil.NewTestDB(t)
+	folders := repository.NewFolderRepository(db, nil, nil)
+	repo := repository.NewFolderRuleRepository(db)
+	ctx := context.Background()
+
+	folder, err := folders.Create(ctx, "Reddit", nil, "article")
+	require.NoError(t, err)
+
+	created, err := repo.Create(ctx, model.FolderRule{FolderID: folder.ID, Pattern: "reddit.com", MatchField: model.FolderRuleMatchHost, Priority: 2})
+	require.NoError(t, err)
+	require.NotZero(t, created.ID)
+	require.False(t, created.CreatedAt.IsZero())
+
+	got, err := repo.GetByID(ctx, created.ID)
+	require.NoError(t, err)
+	require.Equal(t, folder.ID, got.FolderID)
+	require.Equal(t, "reddit.com", got.Pattern)
+	require.Equal(t, model.FolderRuleMatchHost, got.MatchField)
+	require.Equal(t, 2, got.Priority)
+
+	created.Pattern = "*/r/golang*"
+	created.MatchField = model.FolderRuleMatchURL
+	created.Priority = 1
+	updated, err := repo.Update(ctx, created)
+	require.NoError(t, err)
+	require.Equal(t, "*/r/golang*", updated.Pattern)
+	require.Equal(t, model.FolderRuleMatchURL, updated.MatchField)
+	require.Equal(t, 1, updated.Priority)
+
+	require.NoError(t, repo.Delete(ctx, created.ID))
+	_, err = repo.GetByID(ctx, created.ID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+	require.ErrorIs(t, repo.Delete(ctx, created.ID), sql.ErrNoRows)
+	_, err = repo.Update(ctx, created)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestFolderRuleRepository_List(t *testing.T) {
+	t.Parallel()
+	db := testutil.NewTestDB(t)
+	folders := repository.NewFolderRepository(db, nil, nil)
+	repo := repository.NewFolderRuleRepository(db)
+	ctx := context.Background()
+
+	first, err := folders.Create(ctx, "First", nil, "article")
+	require.NoError(t, err)
+	second, err := folders.Create(ctx, "Second", nil, "article")
+	require.NoError(t, err)
+
+	low, err := repo.Create(ctx, model.FolderRule{FolderID: first.ID, Pattern: "a.com", MatchField: model.FolderRuleMatchHost, Priority: 5})
+	require.NoError(t, err)
+	high, err := repo.Create(ctx, model.FolderRule{FolderID: second.ID, Pattern: "b.com", MatchField: model.FolderRuleMatchHost, Priority: 1})
+	require.NoError(t, err)
+	tie, err := repo.Create(ctx, model.FolderRule{FolderID: first.ID, Pattern: "c.com", MatchField: model.FolderRuleMatchHost, Priority: 5})
+	require.NoError(t, err)
+
+	rules, err := repo.List(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []int64{high.ID, low.ID, tie.ID}, folderRuleIDs(rules))
+
+	rules, err = repo.ListByFolder(ctx, first.ID)
+	require.NoError(t, err)
+	require.Equal(t, []int64{low.ID, tie.ID}, folderRuleIDs(rules))
+
+	// Rules of folders in the trash are not evaluated
+	require.NoError(t, folders.Delete(ctx, second.ID))
+	rules, err = repo.List(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []int64{low.ID, tie.ID}, folderRuleIDs(rules))
+}
+
+func folderRuleIDs(rules []model.FolderRule) []int64 {
+	ids := make([]int64, 0, len(rules))
+	for _, rule := range rules {
+		ids = append(ids, rule.ID)
+	}
+	return ids
+}
@@ -0,0 +1,85 @@
+//go:generate mockgen -source=$GOFILE -destination=mock/$GOFILE -package=mock
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"gist/backend/internal/model"
+	"gist/backend/pkg/snowflake"
+)
+
+// LoginEventRepository defines the interface for login audit log storage.
+type LoginEventRepository interface {
+	// Create stores an event and trims the log to the newest keep rows.
+	Create(ctx context.Context, event model.LoginEvent, keep int) error
+	// List returns the newest events first.
+	List(ctx context.Context, limit int) ([]model.LoginEvent, error)
+}
+
+type loginEventRepository struct {
+	db     *sql.DB
+	writes *WriteTracker
+}
+
+// NewLoginEventRepository creates a new login event repository.
+func NewLoginEventRepository(db *sql.DB, writes *WriteTracker) LoginEventRepository {
+	return &loginEventRepository{db: db, writes: writes}
+}
+
+// Create stores an event and trims the log to the newest keep rows.
+func (r *loginEventRepository) Create(ctx context.Context, event model.LoginEvent, keep int) error {
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now().UTC()
+	}
+	success := 0
+	if event.Success {
+		success = 1
+	}
+
+	return withTx(ctx, r.db, r.writes, func(tx dbtx) error {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO login_events (id, identifier, ip, user_agent, success, created_at)
+			VALUES (?, ?, ?, ?, ?, ?)
+		`, snowflake.NextID(), event.Identifier, event.IP, event.UserAgent, success, formatTime(event.CreatedAt)); err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(ctx, `
+			DELETE FROM login_events WHERE id NOT IN (
+				SELECT id FROM login_events ORDER BY created_at DESC, id DESC LIMIT ?
+			)
+		`, keep); err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
+
+// List returns the newest events first.
+func (r *loginEventRepository) List(ctx context.Context, limit int) ([]model.LoginEvent, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, identifier, ip, user_agent, success, created_at
+		FROM login_events ORDER BY created_at DESC, id DESC LIMIT ?
+	`, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []model.LoginEvent
+	for rows.Next() {
+		var e model.LoginEvent
+		var success int
+		var createdAt string
+		if err := rows.Scan(&e.ID, &e.Identifier, &e.IP, &e.UserAgent, &success, &createdAt); err != nil {
+			return nil, err
+		}
+		e.Success = success == 1
+		e.CreatedAt, _ = parseTime(createdAt)
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
@@ -0,0 +1,40 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gist/backend/internal/model"
+	"gist/backend/internal/repository"
+	"gist/backend/internal/repository/testutil"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoginEventRepository_CreateAndTrim(t *testing.T) {
+	t.Parallel()
+
+	db := testutil.NewTestDB(t)
+	repo := repository.NewLoginEventRepository(db, nil)
+	ctx := context.Background()
+
+	base := time.Now().UTC()
+	for i := 0; i < 5; i++ {
+		err := repo.Create(ctx, model.LoginEvent{
+			Identifier: "alice",
+			IP:         "10.0.0.1",
+			UserAgent:  "curl",
+			Success:    i%2 == 0,
+			CreatedAt:  base.Add(time.Duration(i) * time.Second),
+		}, 3)
+		require.NoError(t, err)
+	}
+
+	events, err := repo.List(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, events, 3, "log is trimmed to keep newest rows")
+	require.True(t, events[0].CreatedAt.After(events[1].CreatedAt), "newest first")
+	require.True(t, events[0].Success)
+	require.Equal(t, "10.0.0.1", events[0].IP)
+}
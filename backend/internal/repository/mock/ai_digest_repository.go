@@ -0,0 +1,103 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ai_digest_repository.go
+//
+// Generated by this command:
+//
+//	mockgen -source=ai_digest_repository.go -destination=mock/ai_digest_repository.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	model "gist/backend/internal/model"
+	repository "gist/backend/internal/repository"
+	reflect "reflect"
+	time "time"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockAIDigestRepository is a mock of AIDigestRepository interface.
+type MockAIDigestRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAIDigestRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockAIDigestRepositoryMockRecorder is the mock recorder for MockAIDigestRepository.
+type MockAIDigestRepositoryMockRecorder struct {
+	mock *MockAIDigestRepository
+}
+
+// NewMockAIDigestRepository creates a new mock instance.
+func NewMockAIDigestRepository(ctrl *gomock.Controller) *MockAIDigestRepository {
+	mock := &MockAIDigestRepository{ctrl: ctrl}
+	mock.recorder = &MockAIDigestRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAIDigestRepository) EXPECT() *MockAIDigestRepositoryMockRecorder {
+	return m.recorder
+}
+
+// DeleteAll mocks base method.
+func (m *MockAIDigestRepository) DeleteAll(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAll", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteAll indicates an expected call of DeleteAll.
+func (mr *MockAIDigestRepositoryMockRecorder) DeleteAll(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAll", reflect.TypeOf((*MockAIDigestRepository)(nil).DeleteAll), ctx)
+}
+
+// Get mocks base method.
+func (m *MockAIDigestRepository) Get(ctx context.Context, scope string, scopeID int64, windowEnd time.Time, language string) (*model.AIDigest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, scope, scopeID, windowEnd, language)
+	ret0, _ := ret[0].(*model.AIDigest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockAIDigestRepositoryMockRecorder) Get(ctx, scope, scopeID, windowEnd, language any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockAIDigestRepository)(nil).Get), ctx, scope, scopeID, windowEnd, language)
+}
+
+// ListUnread mocks base method.
+func (m *MockAIDigestRepository) ListUnread(ctx context.Context, scope string, scopeID int64, start, end time.Time, language string, limit int) ([]repository.AIDigestEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUnread", ctx, scope, scopeID, start, end, language, limit)
+	ret0, _ := ret[0].([]repository.AIDigestEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUnread indicates an expected call of ListUnread.
+func (mr *MockAIDigestRepositoryMockRecorder) ListUnread(ctx, scope, scopeID, start, end, language, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUnread", reflect.TypeOf((*MockAIDigestRepository)(nil).ListUnread), ctx, scope, scopeID, start, end, language, limit)
+}
+
+// Save mocks base method.
+func (m *MockAIDigestRepository) Save(ctx context.Context, digest model.AIDigest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, digest)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockAIDigestRepositoryMockRecorder) Save(ctx, digest any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockAIDigestRepository)(nil).Save), ctx, digest)
+}
@@ -13,6 +13,7 @@ import (
 	context "context"
 	model "gist/backend/internal/model"
 	reflect "reflect"
+	time "time"
 
 	gomock "go.uber.org/mock/gomock"
 )
@@ -100,6 +101,36 @@ func (mr *MockAIListTranslationRepositoryMockRecorder) GetBatch(ctx, entryIDs, l
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBatch", reflect.TypeOf((*MockAIListTranslationRepository)(nil).GetBatch), ctx, entryIDs, language)
 }
 
+// ListUntranslated mocks base method.
+func (m *MockAIListTranslationRepository) ListUntranslated(ctx context.Context, language string, modes []string, since time.Time, limit int) ([]model.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUntranslated", ctx, language, modes, since, limit)
+	ret0, _ := ret[0].([]model.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUntranslated indicates an expected call of ListUntranslated.
+func (mr *MockAIListTranslationRepositoryMockRecorder) ListUntranslated(ctx, language, modes, since, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUntranslated", reflect.TypeOf((*MockAIListTranslationRepository)(nil).ListUntranslated), ctx, language, modes, since, limit)
+}
+
+// ListUntranslatedInFeeds mocks base method.
+func (m *MockAIListTranslationRepository) ListUntranslatedInFeeds(ctx context.Context, language string, feedIDs []int64, since time.Time, limit int) ([]model.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUntranslatedInFeeds", ctx, language, feedIDs, since, limit)
+	ret0, _ := ret[0].([]model.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUntranslatedInFeeds indicates an expected call of ListUntranslatedInFeeds.
+func (mr *MockAIListTranslationRepositoryMockRecorder) ListUntranslatedInFeeds(ctx, language, feedIDs, since, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUntranslatedInFeeds", reflect.TypeOf((*MockAIListTranslationRepository)(nil).ListUntranslatedInFeeds), ctx, language, feedIDs, since, limit)
+}
+
 // Save mocks base method.
 func (m *MockAIListTranslationRepository) Save(ctx context.Context, entryID int64, language, title, summary string) error {
 	m.ctrl.T.Helper()
@@ -85,6 +85,21 @@ func (mr *MockAISummaryRepositoryMockRecorder) Get(ctx, entryID, isReadability,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockAISummaryRepository)(nil).Get), ctx, entryID, isReadability, language)
 }
 
+// GetBatch mocks base method.
+func (m *MockAISummaryRepository) GetBatch(ctx context.Context, entryIDs []int64, language string) (map[int64]*model.AISummary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBatch", ctx, entryIDs, language)
+	ret0, _ := ret[0].(map[int64]*model.AISummary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBatch indicates an expected call of GetBatch.
+func (mr *MockAISummaryRepositoryMockRecorder) GetBatch(ctx, entryIDs, language any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBatch", reflect.TypeOf((*MockAISummaryRepository)(nil).GetBatch), ctx, entryIDs, language)
+}
+
 // Save mocks base method.
 func (m *MockAISummaryRepository) Save(ctx context.Context, entryID int64, isReadability bool, language, summary string) error {
 	m.ctrl.T.Helper()
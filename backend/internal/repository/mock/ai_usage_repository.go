@@ -0,0 +1,87 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ai_usage_repository.go
+//
+// Generated by this command:
+//
+//	mockgen -source=ai_usage_repository.go -destination=mock/ai_usage_repository.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	model "gist/backend/internal/model"
+	reflect "reflect"
+	time "time"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockAIUsageRepository is a mock of AIUsageRepository interface.
+type MockAIUsageRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAIUsageRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockAIUsageRepositoryMockRecorder is the mock recorder for MockAIUsageRepository.
+type MockAIUsageRepositoryMockRecorder struct {
+	mock *MockAIUsageRepository
+}
+
+// NewMockAIUsageRepository creates a new mock instance.
+func NewMockAIUsageRepository(ctrl *gomock.Controller) *MockAIUsageRepository {
+	mock := &MockAIUsageRepository{ctrl: ctrl}
+	mock.recorder = &MockAIUsageRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAIUsageRepository) EXPECT() *MockAIUsageRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockAIUsageRepository) Create(ctx context.Context, usage model.AIUsage) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, usage)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockAIUsageRepositoryMockRecorder) Create(ctx, usage any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockAIUsageRepository)(nil).Create), ctx, usage)
+}
+
+// DeleteBefore mocks base method.
+func (m *MockAIUsageRepository) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteBefore", ctx, before)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteBefore indicates an expected call of DeleteBefore.
+func (mr *MockAIUsageRepositoryMockRecorder) DeleteBefore(ctx, before any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBefore", reflect.TypeOf((*MockAIUsageRepository)(nil).DeleteBefore), ctx, before)
+}
+
+// SumByDay mocks base method.
+func (m *MockAIUsageRepository) SumByDay(ctx context.Context, from, to time.Time) ([]model.AIUsageTotal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SumByDay", ctx, from, to)
+	ret0, _ := ret[0].([]model.AIUsageTotal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SumByDay indicates an expected call of SumByDay.
+func (mr *MockAIUsageRepositoryMockRecorder) SumByDay(ctx, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SumByDay", reflect.TypeOf((*MockAIUsageRepository)(nil).SumByDay), ctx, from, to)
+}
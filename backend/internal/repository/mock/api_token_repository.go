@@ -0,0 +1,158 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: api_token_repository.go
+//
+// Generated by this command:
+//
+//	mockgen -source=api_token_repository.go -destination=mock/api_token_repository.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	model "gist/backend/internal/model"
+	reflect "reflect"
+	time "time"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockAPITokenRepository is a mock of APITokenRepository interface.
+type MockAPITokenRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAPITokenRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockAPITokenRepositoryMockRecorder is the mock recorder for MockAPITokenRepository.
+type MockAPITokenRepositoryMockRecorder struct {
+	mock *MockAPITokenRepository
+}
+
+// NewMockAPITokenRepository creates a new mock instance.
+func NewMockAPITokenRepository(ctrl *gomock.Controller) *MockAPITokenRepository {
+	mock := &MockAPITokenRepository{ctrl: ctrl}
+	mock.recorder = &MockAPITokenRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAPITokenRepository) EXPECT() *MockAPITokenRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockAPITokenRepository) Create(ctx context.Context, name, tokenHash string, expiresAt *time.Time) (*model.APIToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, name, tokenHash, expiresAt)
+	ret0, _ := ret[0].(*model.APIToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockAPITokenRepositoryMockRecorder) Create(ctx, name, tokenHash, expiresAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockAPITokenRepository)(nil).Create), ctx, name, tokenHash, expiresAt)
+}
+
+// Delete mocks base method.
+func (m *MockAPITokenRepository) Delete(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockAPITokenRepositoryMockRecorder) Delete(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockAPITokenRepository)(nil).Delete), ctx, id)
+}
+
+// GetByHash mocks base method.
+func (m *MockAPITokenRepository) GetByHash(ctx context.Context, tokenHash string) (*model.APIToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByHash", ctx, tokenHash)
+	ret0, _ := ret[0].(*model.APIToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByHash indicates an expected call of GetByHash.
+func (mr *MockAPITokenRepositoryMockRecorder) GetByHash(ctx, tokenHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByHash", reflect.TypeOf((*MockAPITokenRepository)(nil).GetByHash), ctx, tokenHash)
+}
+
+// List mocks base method.
+func (m *MockAPITokenRepository) List(ctx context.Context) ([]model.APIToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx)
+	ret0, _ := ret[0].([]model.APIToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockAPITokenRepositoryMockRecorder) List(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockAPITokenRepository)(nil).List), ctx)
+}
+
+// UpdateLastUsed mocks base method.
+func (m *MockAPITokenRepository) UpdateLastUsed(ctx context.Context, id int64, usedAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateLastUsed", ctx, id, usedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateLastUsed indicates an expected call of UpdateLastUsed.
+func (mr *MockAPITokenRepositoryMockRecorder) UpdateLastUsed(ctx, id, usedAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLastUsed", reflect.TypeOf((*MockAPITokenRepository)(nil).UpdateLastUsed), ctx, id, usedAt)
+}
+
+// MockapiTokenScanner is a mock of apiTokenScanner interface.
+type MockapiTokenScanner struct {
+	ctrl     *gomock.Controller
+	recorder *MockapiTokenScannerMockRecorder
+	isgomock struct{}
+}
+
+// MockapiTokenScannerMockRecorder is the mock recorder for MockapiTokenScanner.
+type MockapiTokenScannerMockRecorder struct {
+	mock *MockapiTokenScanner
+}
+
+// NewMockapiTokenScanner creates a new mock instance.
+func NewMockapiTokenScanner(ctrl *gomock.Controller) *MockapiTokenScanner {
+	mock := &MockapiTokenScanner{ctrl: ctrl}
+	mock.recorder = &MockapiTokenScannerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockapiTokenScanner) EXPECT() *MockapiTokenScannerMockRecorder {
+	return m.recorder
+}
+
+// Scan mocks base method.
+func (m *MockapiTokenScanner) Scan(dest ...any) error {
+	m.ctrl.T.Helper()
+	varargs := []any{}
+	for _, a := range dest {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Scan", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Scan indicates an expected call of Scan.
+func (mr *MockapiTokenScannerMockRecorder) Scan(dest ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Scan", reflect.TypeOf((*MockapiTokenScanner)(nil).Scan), dest...)
+}
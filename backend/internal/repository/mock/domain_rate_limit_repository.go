@@ -42,18 +42,18 @@ func (m *MockDomainRateLimitRepository) EXPECT() *MockDomainRateLimitRepositoryM
 }
 
 // Create mocks base method.
-func (m *MockDomainRateLimitRepository) Create(ctx context.Context, host string, intervalSeconds int) (*model.DomainRateLimit, error) {
+func (m *MockDomainRateLimitRepository) Create(ctx context.Context, host string, intervalSeconds, maxConcurrent int) (*model.DomainRateLimit, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", ctx, host, intervalSeconds)
+	ret := m.ctrl.Call(m, "Create", ctx, host, intervalSeconds, maxConcurrent)
 	ret0, _ := ret[0].(*model.DomainRateLimit)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Create indicates an expected call of Create.
-func (mr *MockDomainRateLimitRepositoryMockRecorder) Create(ctx, host, intervalSeconds any) *gomock.Call {
+func (mr *MockDomainRateLimitRepositoryMockRecorder) Create(ctx, host, intervalSeconds, maxConcurrent any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockDomainRateLimitRepository)(nil).Create), ctx, host, intervalSeconds)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockDomainRateLimitRepository)(nil).Create), ctx, host, intervalSeconds, maxConcurrent)
 }
 
 // Delete mocks base method.
@@ -101,15 +101,15 @@ func (mr *MockDomainRateLimitRepositoryMockRecorder) List(ctx any) *gomock.Call
 }
 
 // Update mocks base method.
-func (m *MockDomainRateLimitRepository) Update(ctx context.Context, host string, intervalSeconds int) error {
+func (m *MockDomainRateLimitRepository) Update(ctx context.Context, host string, intervalSeconds, maxConcurrent int) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", ctx, host, intervalSeconds)
+	ret := m.ctrl.Call(m, "Update", ctx, host, intervalSeconds, maxConcurrent)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockDomainRateLimitRepositoryMockRecorder) Update(ctx, host, intervalSeconds any) *gomock.Call {
+func (mr *MockDomainRateLimitRepositoryMockRecorder) Update(ctx, host, intervalSeconds, maxConcurrent any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockDomainRateLimitRepository)(nil).Update), ctx, host, intervalSeconds)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockDomainRateLimitRepository)(nil).Update), ctx, host, intervalSeconds, maxConcurrent)
 }
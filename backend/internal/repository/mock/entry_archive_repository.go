@@ -0,0 +1,100 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: entry_archive_repository.go
+//
+// Generated by this command:
+//
+//	mockgen -source=entry_archive_repository.go -destination=mock/entry_archive_repository.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	model "gist/backend/internal/model"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockEntryArchiveRepository is a mock of EntryArchiveRepository interface.
+type MockEntryArchiveRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockEntryArchiveRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockEntryArchiveRepositoryMockRecorder is the mock recorder for MockEntryArchiveRepository.
+type MockEntryArchiveRepositoryMockRecorder struct {
+	mock *MockEntryArchiveRepository
+}
+
+// NewMockEntryArchiveRepository creates a new mock instance.
+func NewMockEntryArchiveRepository(ctrl *gomock.Controller) *MockEntryArchiveRepository {
+	mock := &MockEntryArchiveRepository{ctrl: ctrl}
+	mock.recorder = &MockEntryArchiveRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEntryArchiveRepository) EXPECT() *MockEntryArchiveRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Get mocks base method.
+func (m *MockEntryArchiveRepository) Get(ctx context.Context, entryID int64) (model.EntryArchive, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, entryID)
+	ret0, _ := ret[0].(model.EntryArchive)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockEntryArchiveRepositoryMockRecorder) Get(ctx, entryID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockEntryArchiveRepository)(nil).Get), ctx, entryID)
+}
+
+// ListFailed mocks base method.
+func (m *MockEntryArchiveRepository) ListFailed(ctx context.Context) ([]model.FailedArchive, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListFailed", ctx)
+	ret0, _ := ret[0].([]model.FailedArchive)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListFailed indicates an expected call of ListFailed.
+func (mr *MockEntryArchiveRepositoryMockRecorder) ListFailed(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListFailed", reflect.TypeOf((*MockEntryArchiveRepository)(nil).ListFailed), ctx)
+}
+
+// MarkArchived mocks base method.
+func (m *MockEntryArchiveRepository) MarkArchived(ctx context.Context, entryID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkArchived", ctx, entryID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkArchived indicates an expected call of MarkArchived.
+func (mr *MockEntryArchiveRepositoryMockRecorder) MarkArchived(ctx, entryID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkArchived", reflect.TypeOf((*MockEntryArchiveRepository)(nil).MarkArchived), ctx, entryID)
+}
+
+// MarkFailed mocks base method.
+func (m *MockEntryArchiveRepository) MarkFailed(ctx context.Context, entryID int64, attempts int, lastError string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkFailed", ctx, entryID, attempts, lastError)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkFailed indicates an expected call of MarkFailed.
+func (mr *MockEntryArchiveRepositoryMockRecorder) MarkFailed(ctx, entryID, attempts, lastError any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkFailed", reflect.TypeOf((*MockEntryArchiveRepository)(nil).MarkFailed), ctx, entryID, attempts, lastError)
+}
@@ -14,6 +14,7 @@ import (
 	model "gist/backend/internal/model"
 	repository "gist/backend/internal/repository"
 	reflect "reflect"
+	time "time"
 
 	gomock "go.uber.org/mock/gomock"
 )
@@ -42,6 +43,36 @@ func (m *MockEntryRepository) EXPECT() *MockEntryRepositoryMockRecorder {
 	return m.recorder
 }
 
+// BackfillCanonicalURLs mocks base method.
+func (m *MockEntryRepository) BackfillCanonicalURLs(ctx context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BackfillCanonicalURLs", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BackfillCanonicalURLs indicates an expected call of BackfillCanonicalURLs.
+func (mr *MockEntryRepositoryMockRecorder) BackfillCanonicalURLs(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BackfillCanonicalURLs", reflect.TypeOf((*MockEntryRepository)(nil).BackfillCanonicalURLs), ctx)
+}
+
+// BackfillSearchIndex mocks base method.
+func (m *MockEntryRepository) BackfillSearchIndex(ctx context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BackfillSearchIndex", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BackfillSearchIndex indicates an expected call of BackfillSearchIndex.
+func (mr *MockEntryRepositoryMockRecorder) BackfillSearchIndex(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BackfillSearchIndex", reflect.TypeOf((*MockEntryRepository)(nil).BackfillSearchIndex), ctx)
+}
+
 // ClearAllReadableContent mocks base method.
 func (m *MockEntryRepository) ClearAllReadableContent(ctx context.Context) (int64, error) {
 	m.ctrl.T.Helper()
@@ -57,18 +88,121 @@ func (mr *MockEntryRepositoryMockRecorder) ClearAllReadableContent(ctx any) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClearAllReadableContent", reflect.TypeOf((*MockEntryRepository)(nil).ClearAllReadableContent), ctx)
 }
 
+// Count mocks base method.
+func (m *MockEntryRepository) Count(ctx context.Context, filter repository.EntryListFilter) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Count", ctx, filter)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Count indicates an expected call of Count.
+func (mr *MockEntryRepositoryMockRecorder) Count(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Count", reflect.TypeOf((*MockEntryRepository)(nil).Count), ctx, filter)
+}
+
+// CountClicksByFeed mocks base method.
+func (m *MockEntryRepository) CountClicksByFeed(ctx context.Context, since time.Time) ([]repository.FeedClickCount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountClicksByFeed", ctx, since)
+	ret0, _ := ret[0].([]repository.FeedClickCount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountClicksByFeed indicates an expected call of CountClicksByFeed.
+func (mr *MockEntryRepositoryMockRecorder) CountClicksByFeed(ctx, since any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountClicksByFeed", reflect.TypeOf((*MockEntryRepository)(nil).CountClicksByFeed), ctx, since)
+}
+
+// CountReadsByDay mocks base method.
+func (m *MockEntryRepository) CountReadsByDay(ctx context.Context, since time.Time, utcOffset time.Duration) ([]repository.DailyReadCount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountReadsByDay", ctx, since, utcOffset)
+	ret0, _ := ret[0].([]repository.DailyReadCount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountReadsByDay indicates an expected call of CountReadsByDay.
+func (mr *MockEntryRepositoryMockRecorder) CountReadsByDay(ctx, since, utcOffset any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountReadsByDay", reflect.TypeOf((*MockEntryRepository)(nil).CountReadsByDay), ctx, since, utcOffset)
+}
+
 // CreateOrUpdate mocks base method.
-func (m *MockEntryRepository) CreateOrUpdate(ctx context.Context, entry model.Entry) error {
+func (m *MockEntryRepository) CreateOrUpdate(ctx context.Context, entry model.Entry, revisionLimit int) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateOrUpdate", ctx, entry)
+	ret := m.ctrl.Call(m, "CreateOrUpdate", ctx, entry, revisionLimit)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // CreateOrUpdate indicates an expected call of CreateOrUpdate.
-func (mr *MockEntryRepositoryMockRecorder) CreateOrUpdate(ctx, entry any) *gomock.Call {
+func (mr *MockEntryRepositoryMockRecorder) CreateOrUpdate(ctx, entry, revisionLimit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrUpdate", reflect.TypeOf((*MockEntryRepository)(nil).CreateOrUpdate), ctx, entry, revisionLimit)
+}
+
+// Delete mocks base method.
+func (m *MockEntryRepository) Delete(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockEntryRepositoryMockRecorder) Delete(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockEntryRepository)(nil).Delete), ctx, id)
+}
+
+// DeleteNote mocks base method.
+func (m *MockEntryRepository) DeleteNote(ctx context.Context, entryID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteNote", ctx, entryID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteNote indicates an expected call of DeleteNote.
+func (mr *MockEntryRepositoryMockRecorder) DeleteNote(ctx, entryID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrUpdate", reflect.TypeOf((*MockEntryRepository)(nil).CreateOrUpdate), ctx, entry)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteNote", reflect.TypeOf((*MockEntryRepository)(nil).DeleteNote), ctx, entryID)
+}
+
+// DeleteRawItemsBefore mocks base method.
+func (m *MockEntryRepository) DeleteRawItemsBefore(ctx context.Context, before time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteRawItemsBefore", ctx, before)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteRawItemsBefore indicates an expected call of DeleteRawItemsBefore.
+func (mr *MockEntryRepositoryMockRecorder) DeleteRawItemsBefore(ctx, before any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRawItemsBefore", reflect.TypeOf((*MockEntryRepository)(nil).DeleteRawItemsBefore), ctx, before)
+}
+
+// DeleteTombstonesBefore mocks base method.
+func (m *MockEntryRepository) DeleteTombstonesBefore(ctx context.Context, before time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTombstonesBefore", ctx, before)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteTombstonesBefore indicates an expected call of DeleteTombstonesBefore.
+func (mr *MockEntryRepositoryMockRecorder) DeleteTombstonesBefore(ctx, before any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTombstonesBefore", reflect.TypeOf((*MockEntryRepository)(nil).DeleteTombstonesBefore), ctx, before)
 }
 
 // DeleteUnstarred mocks base method.
@@ -86,6 +220,37 @@ func (mr *MockEntryRepositoryMockRecorder) DeleteUnstarred(ctx any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUnstarred", reflect.TypeOf((*MockEntryRepository)(nil).DeleteUnstarred), ctx)
 }
 
+// EvictOverCap mocks base method.
+func (m *MockEntryRepository) EvictOverCap(ctx context.Context, feedID int64, maxEntries int) (int, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EvictOverCap", ctx, feedID, maxEntries)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// EvictOverCap indicates an expected call of EvictOverCap.
+func (mr *MockEntryRepositoryMockRecorder) EvictOverCap(ctx, feedID, maxEntries any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EvictOverCap", reflect.TypeOf((*MockEntryRepository)(nil).EvictOverCap), ctx, feedID, maxEntries)
+}
+
+// ExistingLegacyURLs mocks base method.
+func (m *MockEntryRepository) ExistingLegacyURLs(ctx context.Context, feedID int64, rawURLs []string) (map[string]bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExistingLegacyURLs", ctx, feedID, rawURLs)
+	ret0, _ := ret[0].(map[string]bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExistingLegacyURLs indicates an expected call of ExistingLegacyURLs.
+func (mr *MockEntryRepositoryMockRecorder) ExistingLegacyURLs(ctx, feedID, rawURLs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExistingLegacyURLs", reflect.TypeOf((*MockEntryRepository)(nil).ExistingLegacyURLs), ctx, feedID, rawURLs)
+}
+
 // ExistsByHash mocks base method.
 func (m *MockEntryRepository) ExistsByHash(ctx context.Context, feedID int64, hash string) (bool, error) {
 	m.ctrl.T.Helper()
@@ -101,19 +266,19 @@ func (mr *MockEntryRepositoryMockRecorder) ExistsByHash(ctx, feedID, hash any) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExistsByHash", reflect.TypeOf((*MockEntryRepository)(nil).ExistsByHash), ctx, feedID, hash)
 }
 
-// ExistsByLegacyURL mocks base method.
-func (m *MockEntryRepository) ExistsByLegacyURL(ctx context.Context, feedID int64, rawURL, hash string) (bool, error) {
+// GetAdjacent mocks base method.
+func (m *MockEntryRepository) GetAdjacent(ctx context.Context, ref model.Entry, filter repository.EntryListFilter, next bool) (model.Entry, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ExistsByLegacyURL", ctx, feedID, rawURL, hash)
-	ret0, _ := ret[0].(bool)
+	ret := m.ctrl.Call(m, "GetAdjacent", ctx, ref, filter, next)
+	ret0, _ := ret[0].(model.Entry)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ExistsByLegacyURL indicates an expected call of ExistsByLegacyURL.
-func (mr *MockEntryRepositoryMockRecorder) ExistsByLegacyURL(ctx, feedID, rawURL, hash any) *gomock.Call {
+// GetAdjacent indicates an expected call of GetAdjacent.
+func (mr *MockEntryRepositoryMockRecorder) GetAdjacent(ctx, ref, filter, next any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExistsByLegacyURL", reflect.TypeOf((*MockEntryRepository)(nil).ExistsByLegacyURL), ctx, feedID, rawURL, hash)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAdjacent", reflect.TypeOf((*MockEntryRepository)(nil).GetAdjacent), ctx, ref, filter, next)
 }
 
 // GetAllUnreadCounts mocks base method.
@@ -146,6 +311,81 @@ func (mr *MockEntryRepositoryMockRecorder) GetByID(ctx, id any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockEntryRepository)(nil).GetByID), ctx, id)
 }
 
+// GetByIDWithFeed mocks base method.
+func (m *MockEntryRepository) GetByIDWithFeed(ctx context.Context, id int64) (model.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByIDWithFeed", ctx, id)
+	ret0, _ := ret[0].(model.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByIDWithFeed indicates an expected call of GetByIDWithFeed.
+func (mr *MockEntryRepositoryMockRecorder) GetByIDWithFeed(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByIDWithFeed", reflect.TypeOf((*MockEntryRepository)(nil).GetByIDWithFeed), ctx, id)
+}
+
+// GetByIDs mocks base method.
+func (m *MockEntryRepository) GetByIDs(ctx context.Context, ids []int64) ([]model.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByIDs", ctx, ids)
+	ret0, _ := ret[0].([]model.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByIDs indicates an expected call of GetByIDs.
+func (mr *MockEntryRepositoryMockRecorder) GetByIDs(ctx, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByIDs", reflect.TypeOf((*MockEntryRepository)(nil).GetByIDs), ctx, ids)
+}
+
+// GetRawItem mocks base method.
+func (m *MockEntryRepository) GetRawItem(ctx context.Context, entryID int64) (*string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRawItem", ctx, entryID)
+	ret0, _ := ret[0].(*string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRawItem indicates an expected call of GetRawItem.
+func (mr *MockEntryRepositoryMockRecorder) GetRawItem(ctx, entryID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRawItem", reflect.TypeOf((*MockEntryRepository)(nil).GetRawItem), ctx, entryID)
+}
+
+// GetReadLatency mocks base method.
+func (m *MockEntryRepository) GetReadLatency(ctx context.Context, since time.Time) (repository.ReadLatency, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReadLatency", ctx, since)
+	ret0, _ := ret[0].(repository.ReadLatency)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReadLatency indicates an expected call of GetReadLatency.
+func (mr *MockEntryRepositoryMockRecorder) GetReadLatency(ctx, since any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReadLatency", reflect.TypeOf((*MockEntryRepository)(nil).GetReadLatency), ctx, since)
+}
+
+// GetRecentCounts mocks base method.
+func (m *MockEntryRepository) GetRecentCounts(ctx context.Context, since time.Time) ([]repository.RecentCount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRecentCounts", ctx, since)
+	ret0, _ := ret[0].([]repository.RecentCount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRecentCounts indicates an expected call of GetRecentCounts.
+func (mr *MockEntryRepositoryMockRecorder) GetRecentCounts(ctx, since any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecentCounts", reflect.TypeOf((*MockEntryRepository)(nil).GetRecentCounts), ctx, since)
+}
+
 // GetStarredCount mocks base method.
 func (m *MockEntryRepository) GetStarredCount(ctx context.Context) (int, error) {
 	m.ctrl.T.Helper()
@@ -161,6 +401,23 @@ func (mr *MockEntryRepositoryMockRecorder) GetStarredCount(ctx any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStarredCount", reflect.TypeOf((*MockEntryRepository)(nil).GetStarredCount), ctx)
 }
 
+// ImportBatch mocks base method.
+func (m *MockEntryRepository) ImportBatch(ctx context.Context, entries []model.Entry) (int, int, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportBatch", ctx, entries)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(int)
+	ret3, _ := ret[3].(error)
+	return ret0, ret1, ret2, ret3
+}
+
+// ImportBatch indicates an expected call of ImportBatch.
+func (mr *MockEntryRepositoryMockRecorder) ImportBatch(ctx, entries any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportBatch", reflect.TypeOf((*MockEntryRepository)(nil).ImportBatch), ctx, entries)
+}
+
 // List mocks base method.
 func (m *MockEntryRepository) List(ctx context.Context, filter repository.EntryListFilter) ([]model.Entry, error) {
 	m.ctrl.T.Helper()
@@ -176,6 +433,96 @@ func (mr *MockEntryRepositoryMockRecorder) List(ctx, filter any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockEntryRepository)(nil).List), ctx, filter)
 }
 
+// ListByHashes mocks base method.
+func (m *MockEntryRepository) ListByHashes(ctx context.Context, feedID int64, hashes []string) ([]model.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByHashes", ctx, feedID, hashes)
+	ret0, _ := ret[0].([]model.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByHashes indicates an expected call of ListByHashes.
+func (mr *MockEntryRepositoryMockRecorder) ListByHashes(ctx, feedID, hashes any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByHashes", reflect.TypeOf((*MockEntryRepository)(nil).ListByHashes), ctx, feedID, hashes)
+}
+
+// ListForBackup mocks base method.
+func (m *MockEntryRepository) ListForBackup(ctx context.Context, includeRead bool, afterID int64, limit int) ([]model.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListForBackup", ctx, includeRead, afterID, limit)
+	ret0, _ := ret[0].([]model.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListForBackup indicates an expected call of ListForBackup.
+func (mr *MockEntryRepositoryMockRecorder) ListForBackup(ctx, includeRead, afterID, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListForBackup", reflect.TypeOf((*MockEntryRepository)(nil).ListForBackup), ctx, includeRead, afterID, limit)
+}
+
+// ListForDigest mocks base method.
+func (m *MockEntryRepository) ListForDigest(ctx context.Context, start, end time.Time, perFeed int) ([]repository.DigestEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListForDigest", ctx, start, end, perFeed)
+	ret0, _ := ret[0].([]repository.DigestEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListForDigest indicates an expected call of ListForDigest.
+func (mr *MockEntryRepositoryMockRecorder) ListForDigest(ctx, start, end, perFeed any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListForDigest", reflect.TypeOf((*MockEntryRepository)(nil).ListForDigest), ctx, start, end, perFeed)
+}
+
+// ListNonUTCPublishedAt mocks base method.
+func (m *MockEntryRepository) ListNonUTCPublishedAt(ctx context.Context) ([]repository.RawPublishedAt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListNonUTCPublishedAt", ctx)
+	ret0, _ := ret[0].([]repository.RawPublishedAt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListNonUTCPublishedAt indicates an expected call of ListNonUTCPublishedAt.
+func (mr *MockEntryRepositoryMockRecorder) ListNonUTCPublishedAt(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListNonUTCPublishedAt", reflect.TypeOf((*MockEntryRepository)(nil).ListNonUTCPublishedAt), ctx)
+}
+
+// ListRevisions mocks base method.
+func (m *MockEntryRepository) ListRevisions(ctx context.Context, entryID int64) ([]model.EntryRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRevisions", ctx, entryID)
+	ret0, _ := ret[0].([]model.EntryRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRevisions indicates an expected call of ListRevisions.
+func (mr *MockEntryRepositoryMockRecorder) ListRevisions(ctx, entryID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRevisions", reflect.TypeOf((*MockEntryRepository)(nil).ListRevisions), ctx, entryID)
+}
+
+// ListTombstones mocks base method.
+func (m *MockEntryRepository) ListTombstones(ctx context.Context, since time.Time, feedID *int64) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTombstones", ctx, since, feedID)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTombstones indicates an expected call of ListTombstones.
+func (mr *MockEntryRepositoryMockRecorder) ListTombstones(ctx, since, feedID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTombstones", reflect.TypeOf((*MockEntryRepository)(nil).ListTombstones), ctx, since, feedID)
+}
+
 // MarkAllAsRead mocks base method.
 func (m *MockEntryRepository) MarkAllAsRead(ctx context.Context, feedID, folderID *int64, contentType *string) error {
 	m.ctrl.T.Helper()
@@ -190,6 +537,109 @@ func (mr *MockEntryRepositoryMockRecorder) MarkAllAsRead(ctx, feedID, folderID,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkAllAsRead", reflect.TypeOf((*MockEntryRepository)(nil).MarkAllAsRead), ctx, feedID, folderID, contentType)
 }
 
+// RecordClick mocks base method.
+func (m *MockEntryRepository) RecordClick(ctx context.Context, entryID, feedID int64, clickedAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordClick", ctx, entryID, feedID, clickedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordClick indicates an expected call of RecordClick.
+func (mr *MockEntryRepositoryMockRecorder) RecordClick(ctx, entryID, feedID, clickedAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordClick", reflect.TypeOf((*MockEntryRepository)(nil).RecordClick), ctx, entryID, feedID, clickedAt)
+}
+
+// Rehash mocks base method.
+func (m *MockEntryRepository) Rehash(ctx context.Context, feedID int64, hash func(string, string, string) string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Rehash", ctx, feedID, hash)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Rehash indicates an expected call of Rehash.
+func (mr *MockEntryRepositoryMockRecorder) Rehash(ctx, feedID, hash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rehash", reflect.TypeOf((*MockEntryRepository)(nil).Rehash), ctx, feedID, hash)
+}
+
+// SaveBatch mocks base method.
+func (m *MockEntryRepository) SaveBatch(ctx context.Context, feedID int64, entries []model.Entry, revisionLimit int) (int, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveBatch", ctx, feedID, entries, revisionLimit)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// SaveBatch indicates an expected call of SaveBatch.
+func (mr *MockEntryRepositoryMockRecorder) SaveBatch(ctx, feedID, entries, revisionLimit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveBatch", reflect.TypeOf((*MockEntryRepository)(nil).SaveBatch), ctx, feedID, entries, revisionLimit)
+}
+
+// Search mocks base method.
+func (m *MockEntryRepository) Search(ctx context.Context, filter repository.EntrySearchFilter) ([]model.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", ctx, filter)
+	ret0, _ := ret[0].([]model.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Search indicates an expected call of Search.
+func (mr *MockEntryRepositoryMockRecorder) Search(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockEntryRepository)(nil).Search), ctx, filter)
+}
+
+// SetNote mocks base method.
+func (m *MockEntryRepository) SetNote(ctx context.Context, entryID int64, note string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetNote", ctx, entryID, note)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetNote indicates an expected call of SetNote.
+func (mr *MockEntryRepositoryMockRecorder) SetNote(ctx, entryID, note any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetNote", reflect.TypeOf((*MockEntryRepository)(nil).SetNote), ctx, entryID, note)
+}
+
+// TopReadFeeds mocks base method.
+func (m *MockEntryRepository) TopReadFeeds(ctx context.Context, since time.Time, limit int) ([]repository.FeedReadCount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TopReadFeeds", ctx, since, limit)
+	ret0, _ := ret[0].([]repository.FeedReadCount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TopReadFeeds indicates an expected call of TopReadFeeds.
+func (mr *MockEntryRepositoryMockRecorder) TopReadFeeds(ctx, since, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TopReadFeeds", reflect.TypeOf((*MockEntryRepository)(nil).TopReadFeeds), ctx, since, limit)
+}
+
+// UpdateContent mocks base method.
+func (m *MockEntryRepository) UpdateContent(ctx context.Context, id int64, content string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateContent", ctx, id, content)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateContent indicates an expected call of UpdateContent.
+func (mr *MockEntryRepositoryMockRecorder) UpdateContent(ctx, id, content any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateContent", reflect.TypeOf((*MockEntryRepository)(nil).UpdateContent), ctx, id, content)
+}
+
 // UpdateManyReadStatus mocks base method.
 func (m *MockEntryRepository) UpdateManyReadStatus(ctx context.Context, ids []int64, read bool) error {
 	m.ctrl.T.Helper()
@@ -204,6 +654,20 @@ func (mr *MockEntryRepositoryMockRecorder) UpdateManyReadStatus(ctx, ids, read a
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateManyReadStatus", reflect.TypeOf((*MockEntryRepository)(nil).UpdateManyReadStatus), ctx, ids, read)
 }
 
+// UpdatePublishedAt mocks base method.
+func (m *MockEntryRepository) UpdatePublishedAt(ctx context.Context, id int64, publishedAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePublishedAt", ctx, id, publishedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePublishedAt indicates an expected call of UpdatePublishedAt.
+func (mr *MockEntryRepositoryMockRecorder) UpdatePublishedAt(ctx, id, publishedAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePublishedAt", reflect.TypeOf((*MockEntryRepository)(nil).UpdatePublishedAt), ctx, id, publishedAt)
+}
+
 // UpdateReadStatus mocks base method.
 func (m *MockEntryRepository) UpdateReadStatus(ctx context.Context, id int64, read bool) error {
 	m.ctrl.T.Helper()
@@ -219,17 +683,17 @@ func (mr *MockEntryRepositoryMockRecorder) UpdateReadStatus(ctx, id, read any) *
 }
 
 // UpdateReadableContent mocks base method.
-func (m *MockEntryRepository) UpdateReadableContent(ctx context.Context, id int64, content string) error {
+func (m *MockEntryRepository) UpdateReadableContent(ctx context.Context, id int64, content string, wordCount int) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateReadableContent", ctx, id, content)
+	ret := m.ctrl.Call(m, "UpdateReadableContent", ctx, id, content, wordCount)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // UpdateReadableContent indicates an expected call of UpdateReadableContent.
-func (mr *MockEntryRepositoryMockRecorder) UpdateReadableContent(ctx, id, content any) *gomock.Call {
+func (mr *MockEntryRepositoryMockRecorder) UpdateReadableContent(ctx, id, content, wordCount any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateReadableContent", reflect.TypeOf((*MockEntryRepository)(nil).UpdateReadableContent), ctx, id, content)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateReadableContent", reflect.TypeOf((*MockEntryRepository)(nil).UpdateReadableContent), ctx, id, content, wordCount)
 }
 
 // UpdateStarredStatus mocks base method.
@@ -0,0 +1,115 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: feed_fetch_log_repository.go
+//
+// Generated by this command:
+//
+//	mockgen -source=feed_fetch_log_repository.go -destination=mock/feed_fetch_log_repository.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	model "gist/backend/internal/model"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockFeedFetchLogRepository is a mock of FeedFetchLogRepository interface.
+type MockFeedFetchLogRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockFeedFetchLogRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockFeedFetchLogRepositoryMockRecorder is the mock recorder for MockFeedFetchLogRepository.
+type MockFeedFetchLogRepositoryMockRecorder struct {
+	mock *MockFeedFetchLogRepository
+}
+
+// NewMockFeedFetchLogRepository creates a new mock instance.
+func NewMockFeedFetchLogRepository(ctrl *gomock.Controller) *MockFeedFetchLogRepository {
+	mock := &MockFeedFetchLogRepository{ctrl: ctrl}
+	mock.recorder = &MockFeedFetchLogRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFeedFetchLogRepository) EXPECT() *MockFeedFetchLogRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Append mocks base method.
+func (m *MockFeedFetchLogRepository) Append(ctx context.Context, fetch model.FeedFetch, keep int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Append", ctx, fetch, keep)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Append indicates an expected call of Append.
+func (mr *MockFeedFetchLogRepositoryMockRecorder) Append(ctx, fetch, keep any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Append", reflect.TypeOf((*MockFeedFetchLogRepository)(nil).Append), ctx, fetch, keep)
+}
+
+// GetIngestReport mocks base method.
+func (m *MockFeedFetchLogRepository) GetIngestReport(ctx context.Context, feedID int64) (*string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIngestReport", ctx, feedID)
+	ret0, _ := ret[0].(*string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIngestReport indicates an expected call of GetIngestReport.
+func (mr *MockFeedFetchLogRepositoryMockRecorder) GetIngestReport(ctx, feedID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIngestReport", reflect.TypeOf((*MockFeedFetchLogRepository)(nil).GetIngestReport), ctx, feedID)
+}
+
+// List mocks base method.
+func (m *MockFeedFetchLogRepository) List(ctx context.Context, feedID int64, limit int) ([]model.FeedFetch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, feedID, limit)
+	ret0, _ := ret[0].([]model.FeedFetch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockFeedFetchLogRepositoryMockRecorder) List(ctx, feedID, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockFeedFetchLogRepository)(nil).List), ctx, feedID, limit)
+}
+
+// ListStats mocks base method.
+func (m *MockFeedFetchLogRepository) ListStats(ctx context.Context) ([]model.FeedFetchStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListStats", ctx)
+	ret0, _ := ret[0].([]model.FeedFetchStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListStats indicates an expected call of ListStats.
+func (mr *MockFeedFetchLogRepositoryMockRecorder) ListStats(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListStats", reflect.TypeOf((*MockFeedFetchLogRepository)(nil).ListStats), ctx)
+}
+
+// SaveIngestReport mocks base method.
+func (m *MockFeedFetchLogRepository) SaveIngestReport(ctx context.Context, feedID int64, report *string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveIngestReport", ctx, feedID, report)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveIngestReport indicates an expected call of SaveIngestReport.
+func (mr *MockFeedFetchLogRepositoryMockRecorder) SaveIngestReport(ctx, feedID, report any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveIngestReport", reflect.TypeOf((*MockFeedFetchLogRepository)(nil).SaveIngestReport), ctx, feedID, report)
+}
@@ -0,0 +1,116 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: feed_overlap_repository.go
+//
+// Generated by this command:
+//
+//	mockgen -source=feed_overlap_repository.go -destination=mock/feed_overlap_repository.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	model "gist/backend/internal/model"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockFeedOverlapRepository is a mock of FeedOverlapRepository interface.
+type MockFeedOverlapRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockFeedOverlapRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockFeedOverlapRepositoryMockRecorder is the mock recorder for MockFeedOverlapRepository.
+type MockFeedOverlapRepositoryMockRecorder struct {
+	mock *MockFeedOverlapRepository
+}
+
+// NewMockFeedOverlapRepository creates a new mock instance.
+func NewMockFeedOverlapRepository(ctrl *gomock.Controller) *MockFeedOverlapRepository {
+	mock := &MockFeedOverlapRepository{ctrl: ctrl}
+	mock.recorder = &MockFeedOverlapRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFeedOverlapRepository) EXPECT() *MockFeedOverlapRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetByID mocks base method.
+func (m *MockFeedOverlapRepository) GetByID(ctx context.Context, id int64) (model.FeedOverlap, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(model.FeedOverlap)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockFeedOverlapRepositoryMockRecorder) GetByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockFeedOverlapRepository)(nil).GetByID), ctx, id)
+}
+
+// List mocks base method.
+func (m *MockFeedOverlapRepository) List(ctx context.Context) ([]model.FeedOverlap, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx)
+	ret0, _ := ret[0].([]model.FeedOverlap)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockFeedOverlapRepositoryMockRecorder) List(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockFeedOverlapRepository)(nil).List), ctx)
+}
+
+// Merge mocks base method.
+func (m *MockFeedOverlapRepository) Merge(ctx context.Context, fromFeedID, toFeedID int64) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Merge", ctx, fromFeedID, toFeedID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Merge indicates an expected call of Merge.
+func (mr *MockFeedOverlapRepositoryMockRecorder) Merge(ctx, fromFeedID, toFeedID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Merge", reflect.TypeOf((*MockFeedOverlapRepository)(nil).Merge), ctx, fromFeedID, toFeedID)
+}
+
+// Replace mocks base method.
+func (m *MockFeedOverlapRepository) Replace(ctx context.Context, overlaps []model.FeedOverlap) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Replace", ctx, overlaps)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Replace indicates an expected call of Replace.
+func (mr *MockFeedOverlapRepositoryMockRecorder) Replace(ctx, overlaps any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Replace", reflect.TypeOf((*MockFeedOverlapRepository)(nil).Replace), ctx, overlaps)
+}
+
+// SampleSharedEntries mocks base method.
+func (m *MockFeedOverlapRepository) SampleSharedEntries(ctx context.Context, feedID int64, sample int) ([]model.FeedOverlapSample, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SampleSharedEntries", ctx, feedID, sample)
+	ret0, _ := ret[0].([]model.FeedOverlapSample)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SampleSharedEntries indicates an expected call of SampleSharedEntries.
+func (mr *MockFeedOverlapRepositoryMockRecorder) SampleSharedEntries(ctx, feedID, sample any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SampleSharedEntries", reflect.TypeOf((*MockFeedOverlapRepository)(nil).SampleSharedEntries), ctx, feedID, sample)
+}
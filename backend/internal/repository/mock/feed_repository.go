@@ -13,6 +13,7 @@ import (
 	context "context"
 	model "gist/backend/internal/model"
 	reflect "reflect"
+	time "time"
 
 	gomock "go.uber.org/mock/gomock"
 )
@@ -71,6 +72,21 @@ func (mr *MockFeedRepositoryMockRecorder) ClearAllIconPaths(ctx any) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClearAllIconPaths", reflect.TypeOf((*MockFeedRepository)(nil).ClearAllIconPaths), ctx)
 }
 
+// CountSuspectCaching mocks base method.
+func (m *MockFeedRepository) CountSuspectCaching(ctx context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountSuspectCaching", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountSuspectCaching indicates an expected call of CountSuspectCaching.
+func (mr *MockFeedRepositoryMockRecorder) CountSuspectCaching(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountSuspectCaching", reflect.TypeOf((*MockFeedRepository)(nil).CountSuspectCaching), ctx)
+}
+
 // Create mocks base method.
 func (m *MockFeedRepository) Create(ctx context.Context, feed model.Feed) (model.Feed, error) {
 	m.ctrl.T.Helper()
@@ -130,6 +146,21 @@ func (mr *MockFeedRepositoryMockRecorder) FindByURL(ctx, url any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByURL", reflect.TypeOf((*MockFeedRepository)(nil).FindByURL), ctx, url)
 }
 
+// GetActivityStats mocks base method.
+func (m *MockFeedRepository) GetActivityStats(ctx context.Context) ([]model.FeedActivityStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActivityStats", ctx)
+	ret0, _ := ret[0].([]model.FeedActivityStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActivityStats indicates an expected call of GetActivityStats.
+func (mr *MockFeedRepositoryMockRecorder) GetActivityStats(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActivityStats", reflect.TypeOf((*MockFeedRepository)(nil).GetActivityStats), ctx)
+}
+
 // GetByID mocks base method.
 func (m *MockFeedRepository) GetByID(ctx context.Context, id int64) (model.Feed, error) {
 	m.ctrl.T.Helper()
@@ -160,6 +191,36 @@ func (mr *MockFeedRepositoryMockRecorder) GetByIDs(ctx, ids any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByIDs", reflect.TypeOf((*MockFeedRepository)(nil).GetByIDs), ctx, ids)
 }
 
+// GetIngestTokenHash mocks base method.
+func (m *MockFeedRepository) GetIngestTokenHash(ctx context.Context, id int64) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIngestTokenHash", ctx, id)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIngestTokenHash indicates an expected call of GetIngestTokenHash.
+func (mr *MockFeedRepositoryMockRecorder) GetIngestTokenHash(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIngestTokenHash", reflect.TypeOf((*MockFeedRepository)(nil).GetIngestTokenHash), ctx, id)
+}
+
+// IsAuthorMuted mocks base method.
+func (m *MockFeedRepository) IsAuthorMuted(ctx context.Context, feedID int64, author string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsAuthorMuted", ctx, feedID, author)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsAuthorMuted indicates an expected call of IsAuthorMuted.
+func (mr *MockFeedRepositoryMockRecorder) IsAuthorMuted(ctx, feedID, author any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsAuthorMuted", reflect.TypeOf((*MockFeedRepository)(nil).IsAuthorMuted), ctx, feedID, author)
+}
+
 // List mocks base method.
 func (m *MockFeedRepository) List(ctx context.Context, folderID *int64) ([]model.Feed, error) {
 	m.ctrl.T.Helper()
@@ -175,6 +236,36 @@ func (mr *MockFeedRepositoryMockRecorder) List(ctx, folderID any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockFeedRepository)(nil).List), ctx, folderID)
 }
 
+// ListMutedAuthors mocks base method.
+func (m *MockFeedRepository) ListMutedAuthors(ctx context.Context, feedID int64) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListMutedAuthors", ctx, feedID)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListMutedAuthors indicates an expected call of ListMutedAuthors.
+func (mr *MockFeedRepositoryMockRecorder) ListMutedAuthors(ctx, feedID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMutedAuthors", reflect.TypeOf((*MockFeedRepository)(nil).ListMutedAuthors), ctx, feedID)
+}
+
+// ListWithUnreadCounts mocks base method.
+func (m *MockFeedRepository) ListWithUnreadCounts(ctx context.Context) ([]model.FeedWithUnread, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWithUnreadCounts", ctx)
+	ret0, _ := ret[0].([]model.FeedWithUnread)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWithUnreadCounts indicates an expected call of ListWithUnreadCounts.
+func (mr *MockFeedRepositoryMockRecorder) ListWithUnreadCounts(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWithUnreadCounts", reflect.TypeOf((*MockFeedRepository)(nil).ListWithUnreadCounts), ctx)
+}
+
 // ListWithoutIcon mocks base method.
 func (m *MockFeedRepository) ListWithoutIcon(ctx context.Context) ([]model.Feed, error) {
 	m.ctrl.T.Helper()
@@ -190,6 +281,92 @@ func (mr *MockFeedRepositoryMockRecorder) ListWithoutIcon(ctx any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWithoutIcon", reflect.TypeOf((*MockFeedRepository)(nil).ListWithoutIcon), ctx)
 }
 
+// MuteAuthor mocks base method.
+func (m *MockFeedRepository) MuteAuthor(ctx context.Context, feedID int64, author string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MuteAuthor", ctx, feedID, author)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MuteAuthor indicates an expected call of MuteAuthor.
+func (mr *MockFeedRepositoryMockRecorder) MuteAuthor(ctx, feedID, author any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MuteAuthor", reflect.TypeOf((*MockFeedRepository)(nil).MuteAuthor), ctx, feedID, author)
+}
+
+// PostingInterval mocks base method.
+func (m *MockFeedRepository) PostingInterval(ctx context.Context, id int64, sample int) (time.Duration, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PostingInterval", ctx, id, sample)
+	ret0, _ := ret[0].(time.Duration)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PostingInterval indicates an expected call of PostingInterval.
+func (mr *MockFeedRepositoryMockRecorder) PostingInterval(ctx, id, sample any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostingInterval", reflect.TypeOf((*MockFeedRepository)(nil).PostingInterval), ctx, id, sample)
+}
+
+// PurgeDeleted mocks base method.
+func (m *MockFeedRepository) PurgeDeleted(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeDeleted", ctx, deletedBefore)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeDeleted indicates an expected call of PurgeDeleted.
+func (mr *MockFeedRepositoryMockRecorder) PurgeDeleted(ctx, deletedBefore any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeDeleted", reflect.TypeOf((*MockFeedRepository)(nil).PurgeDeleted), ctx, deletedBefore)
+}
+
+// Reorder mocks base method.
+func (m *MockFeedRepository) Reorder(ctx context.Context, ids []int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reorder", ctx, ids)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Reorder indicates an expected call of Reorder.
+func (mr *MockFeedRepositoryMockRecorder) Reorder(ctx, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reorder", reflect.TypeOf((*MockFeedRepository)(nil).Reorder), ctx, ids)
+}
+
+// Restore mocks base method.
+func (m *MockFeedRepository) Restore(ctx context.Context, id int64, deletedSince time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, id, deletedSince)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockFeedRepositoryMockRecorder) Restore(ctx, id, deletedSince any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockFeedRepository)(nil).Restore), ctx, id, deletedSince)
+}
+
+// UnmuteAuthor mocks base method.
+func (m *MockFeedRepository) UnmuteAuthor(ctx context.Context, feedID int64, author string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnmuteAuthor", ctx, feedID, author)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnmuteAuthor indicates an expected call of UnmuteAuthor.
+func (mr *MockFeedRepositoryMockRecorder) UnmuteAuthor(ctx, feedID, author any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnmuteAuthor", reflect.TypeOf((*MockFeedRepository)(nil).UnmuteAuthor), ctx, feedID, author)
+}
+
 // Update mocks base method.
 func (m *MockFeedRepository) Update(ctx context.Context, feed model.Feed) (model.Feed, error) {
 	m.ctrl.T.Helper()
@@ -205,6 +382,76 @@ func (mr *MockFeedRepositoryMockRecorder) Update(ctx, feed any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockFeedRepository)(nil).Update), ctx, feed)
 }
 
+// UpdateAssumeTimezone mocks base method.
+func (m *MockFeedRepository) UpdateAssumeTimezone(ctx context.Context, id int64, timezone *string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAssumeTimezone", ctx, id, timezone)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateAssumeTimezone indicates an expected call of UpdateAssumeTimezone.
+func (mr *MockFeedRepositoryMockRecorder) UpdateAssumeTimezone(ctx, id, timezone any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAssumeTimezone", reflect.TypeOf((*MockFeedRepository)(nil).UpdateAssumeTimezone), ctx, id, timezone)
+}
+
+// UpdateAutoReadability mocks base method.
+func (m *MockFeedRepository) UpdateAutoReadability(ctx context.Context, id int64, enabled *bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAutoReadability", ctx, id, enabled)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateAutoReadability indicates an expected call of UpdateAutoReadability.
+func (mr *MockFeedRepositoryMockRecorder) UpdateAutoReadability(ctx, id, enabled any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAutoReadability", reflect.TypeOf((*MockFeedRepository)(nil).UpdateAutoReadability), ctx, id, enabled)
+}
+
+// UpdateAutoTranslate mocks base method.
+func (m *MockFeedRepository) UpdateAutoTranslate(ctx context.Context, id int64, mode string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAutoTranslate", ctx, id, mode)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateAutoTranslate indicates an expected call of UpdateAutoTranslate.
+func (mr *MockFeedRepositoryMockRecorder) UpdateAutoTranslate(ctx, id, mode any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAutoTranslate", reflect.TypeOf((*MockFeedRepository)(nil).UpdateAutoTranslate), ctx, id, mode)
+}
+
+// UpdateConditionalGet mocks base method.
+func (m *MockFeedRepository) UpdateConditionalGet(ctx context.Context, id int64, etag, lastModified *string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateConditionalGet", ctx, id, etag, lastModified)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateConditionalGet indicates an expected call of UpdateConditionalGet.
+func (mr *MockFeedRepositoryMockRecorder) UpdateConditionalGet(ctx, id, etag, lastModified any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateConditionalGet", reflect.TypeOf((*MockFeedRepository)(nil).UpdateConditionalGet), ctx, id, etag, lastModified)
+}
+
+// UpdateDedupeKey mocks base method.
+func (m *MockFeedRepository) UpdateDedupeKey(ctx context.Context, id int64, dedupeKey string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateDedupeKey", ctx, id, dedupeKey)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateDedupeKey indicates an expected call of UpdateDedupeKey.
+func (mr *MockFeedRepositoryMockRecorder) UpdateDedupeKey(ctx, id, dedupeKey any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateDedupeKey", reflect.TypeOf((*MockFeedRepository)(nil).UpdateDedupeKey), ctx, id, dedupeKey)
+}
+
 // UpdateErrorMessage mocks base method.
 func (m *MockFeedRepository) UpdateErrorMessage(ctx context.Context, id int64, errorMessage *string) error {
 	m.ctrl.T.Helper()
@@ -219,6 +466,20 @@ func (mr *MockFeedRepositoryMockRecorder) UpdateErrorMessage(ctx, id, errorMessa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateErrorMessage", reflect.TypeOf((*MockFeedRepository)(nil).UpdateErrorMessage), ctx, id, errorMessage)
 }
 
+// UpdateGeneratedIconPath mocks base method.
+func (m *MockFeedRepository) UpdateGeneratedIconPath(ctx context.Context, id int64, iconPath string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateGeneratedIconPath", ctx, id, iconPath)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateGeneratedIconPath indicates an expected call of UpdateGeneratedIconPath.
+func (mr *MockFeedRepositoryMockRecorder) UpdateGeneratedIconPath(ctx, id, iconPath any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateGeneratedIconPath", reflect.TypeOf((*MockFeedRepository)(nil).UpdateGeneratedIconPath), ctx, id, iconPath)
+}
+
 // UpdateIconPath mocks base method.
 func (m *MockFeedRepository) UpdateIconPath(ctx context.Context, id int64, iconPath string) error {
 	m.ctrl.T.Helper()
@@ -233,6 +494,118 @@ func (mr *MockFeedRepositoryMockRecorder) UpdateIconPath(ctx, id, iconPath any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateIconPath", reflect.TypeOf((*MockFeedRepository)(nil).UpdateIconPath), ctx, id, iconPath)
 }
 
+// UpdateIngestTokenHash mocks base method.
+func (m *MockFeedRepository) UpdateIngestTokenHash(ctx context.Context, id int64, tokenHash string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateIngestTokenHash", ctx, id, tokenHash)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateIngestTokenHash indicates an expected call of UpdateIngestTokenHash.
+func (mr *MockFeedRepositoryMockRecorder) UpdateIngestTokenHash(ctx, id, tokenHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateIngestTokenHash", reflect.TypeOf((*MockFeedRepository)(nil).UpdateIngestTokenHash), ctx, id, tokenHash)
+}
+
+// UpdateLanguage mocks base method.
+func (m *MockFeedRepository) UpdateLanguage(ctx context.Context, id int64, language string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateLanguage", ctx, id, language)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateLanguage indicates an expected call of UpdateLanguage.
+func (mr *MockFeedRepositoryMockRecorder) UpdateLanguage(ctx, id, language any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLanguage", reflect.TypeOf((*MockFeedRepository)(nil).UpdateLanguage), ctx, id, language)
+}
+
+// UpdateLastEntrySeenAt mocks base method.
+func (m *MockFeedRepository) UpdateLastEntrySeenAt(ctx context.Context, id int64, seenAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateLastEntrySeenAt", ctx, id, seenAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateLastEntrySeenAt indicates an expected call of UpdateLastEntrySeenAt.
+func (mr *MockFeedRepositoryMockRecorder) UpdateLastEntrySeenAt(ctx, id, seenAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLastEntrySeenAt", reflect.TypeOf((*MockFeedRepository)(nil).UpdateLastEntrySeenAt), ctx, id, seenAt)
+}
+
+// UpdateLastFetchedAt mocks base method.
+func (m *MockFeedRepository) UpdateLastFetchedAt(ctx context.Context, id int64, fetchedAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateLastFetchedAt", ctx, id, fetchedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateLastFetchedAt indicates an expected call of UpdateLastFetchedAt.
+func (mr *MockFeedRepositoryMockRecorder) UpdateLastFetchedAt(ctx, id, fetchedAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLastFetchedAt", reflect.TypeOf((*MockFeedRepository)(nil).UpdateLastFetchedAt), ctx, id, fetchedAt)
+}
+
+// UpdateNotModifiedStreak mocks base method.
+func (m *MockFeedRepository) UpdateNotModifiedStreak(ctx context.Context, id int64, streak int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateNotModifiedStreak", ctx, id, streak)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateNotModifiedStreak indicates an expected call of UpdateNotModifiedStreak.
+func (mr *MockFeedRepositoryMockRecorder) UpdateNotModifiedStreak(ctx, id, streak any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateNotModifiedStreak", reflect.TypeOf((*MockFeedRepository)(nil).UpdateNotModifiedStreak), ctx, id, streak)
+}
+
+// UpdatePausedUntil mocks base method.
+func (m *MockFeedRepository) UpdatePausedUntil(ctx context.Context, id int64, until *time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePausedUntil", ctx, id, until)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePausedUntil indicates an expected call of UpdatePausedUntil.
+func (mr *MockFeedRepositoryMockRecorder) UpdatePausedUntil(ctx, id, until any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePausedUntil", reflect.TypeOf((*MockFeedRepository)(nil).UpdatePausedUntil), ctx, id, until)
+}
+
+// UpdatePollHint mocks base method.
+func (m *MockFeedRepository) UpdatePollHint(ctx context.Context, id int64, seconds int, source string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePollHint", ctx, id, seconds, source)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePollHint indicates an expected call of UpdatePollHint.
+func (mr *MockFeedRepositoryMockRecorder) UpdatePollHint(ctx, id, seconds, source any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePollHint", reflect.TypeOf((*MockFeedRepository)(nil).UpdatePollHint), ctx, id, seconds, source)
+}
+
+// UpdatePreferredUserAgent mocks base method.
+func (m *MockFeedRepository) UpdatePreferredUserAgent(ctx context.Context, id int64, userAgent string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePreferredUserAgent", ctx, id, userAgent)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePreferredUserAgent indicates an expected call of UpdatePreferredUserAgent.
+func (mr *MockFeedRepositoryMockRecorder) UpdatePreferredUserAgent(ctx, id, userAgent any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePreferredUserAgent", reflect.TypeOf((*MockFeedRepository)(nil).UpdatePreferredUserAgent), ctx, id, userAgent)
+}
+
 // UpdateSiteURL mocks base method.
 func (m *MockFeedRepository) UpdateSiteURL(ctx context.Context, id int64, siteURL string) error {
 	m.ctrl.T.Helper()
@@ -247,6 +620,34 @@ func (mr *MockFeedRepositoryMockRecorder) UpdateSiteURL(ctx, id, siteURL any) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSiteURL", reflect.TypeOf((*MockFeedRepository)(nil).UpdateSiteURL), ctx, id, siteURL)
 }
 
+// UpdateSuspectCaching mocks base method.
+func (m *MockFeedRepository) UpdateSuspectCaching(ctx context.Context, id int64, at *time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSuspectCaching", ctx, id, at)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateSuspectCaching indicates an expected call of UpdateSuspectCaching.
+func (mr *MockFeedRepositoryMockRecorder) UpdateSuspectCaching(ctx, id, at any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSuspectCaching", reflect.TypeOf((*MockFeedRepository)(nil).UpdateSuspectCaching), ctx, id, at)
+}
+
+// UpdateTitles mocks base method.
+func (m *MockFeedRepository) UpdateTitles(ctx context.Context, id int64, title string, customTitle *string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTitles", ctx, id, title, customTitle)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateTitles indicates an expected call of UpdateTitles.
+func (mr *MockFeedRepositoryMockRecorder) UpdateTitles(ctx, id, title, customTitle any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTitles", reflect.TypeOf((*MockFeedRepository)(nil).UpdateTitles), ctx, id, title, customTitle)
+}
+
 // UpdateType mocks base method.
 func (m *MockFeedRepository) UpdateType(ctx context.Context, id int64, feedType string) error {
 	m.ctrl.T.Helper()
@@ -261,16 +662,30 @@ func (mr *MockFeedRepositoryMockRecorder) UpdateType(ctx, id, feedType any) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateType", reflect.TypeOf((*MockFeedRepository)(nil).UpdateType), ctx, id, feedType)
 }
 
-// UpdateTypeByFolderID mocks base method.
-func (m *MockFeedRepository) UpdateTypeByFolderID(ctx context.Context, folderID int64, feedType string) error {
+// UpdateTypeByFolderIDs mocks base method.
+func (m *MockFeedRepository) UpdateTypeByFolderIDs(ctx context.Context, folderIDs []int64, feedType string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTypeByFolderIDs", ctx, folderIDs, feedType)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateTypeByFolderIDs indicates an expected call of UpdateTypeByFolderIDs.
+func (mr *MockFeedRepositoryMockRecorder) UpdateTypeByFolderIDs(ctx, folderIDs, feedType any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTypeByFolderIDs", reflect.TypeOf((*MockFeedRepository)(nil).UpdateTypeByFolderIDs), ctx, folderIDs, feedType)
+}
+
+// UpdateUserAgent mocks base method.
+func (m *MockFeedRepository) UpdateUserAgent(ctx context.Context, id int64, userAgent *string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateTypeByFolderID", ctx, folderID, feedType)
+	ret := m.ctrl.Call(m, "UpdateUserAgent", ctx, id, userAgent)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// UpdateTypeByFolderID indicates an expected call of UpdateTypeByFolderID.
-func (mr *MockFeedRepositoryMockRecorder) UpdateTypeByFolderID(ctx, folderID, feedType any) *gomock.Call {
+// UpdateUserAgent indicates an expected call of UpdateUserAgent.
+func (mr *MockFeedRepositoryMockRecorder) UpdateUserAgent(ctx, id, userAgent any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTypeByFolderID", reflect.TypeOf((*MockFeedRepository)(nil).UpdateTypeByFolderID), ctx, folderID, feedType)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserAgent", reflect.TypeOf((*MockFeedRepository)(nil).UpdateUserAgent), ctx, id, userAgent)
 }
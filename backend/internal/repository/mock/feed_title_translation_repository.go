@@ -0,0 +1,86 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: feed_title_translation_repository.go
+//
+// Generated by this command:
+//
+//	mockgen -source=feed_title_translation_repository.go -destination=mock/feed_title_translation_repository.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	model "gist/backend/internal/model"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockFeedTitleTranslationRepository is a mock of FeedTitleTranslationRepository interface.
+type MockFeedTitleTranslationRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockFeedTitleTranslationRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockFeedTitleTranslationRepositoryMockRecorder is the mock recorder for MockFeedTitleTranslationRepository.
+type MockFeedTitleTranslationRepositoryMockRecorder struct {
+	mock *MockFeedTitleTranslationRepository
+}
+
+// NewMockFeedTitleTranslationRepository creates a new mock instance.
+func NewMockFeedTitleTranslationRepository(ctrl *gomock.Controller) *MockFeedTitleTranslationRepository {
+	mock := &MockFeedTitleTranslationRepository{ctrl: ctrl}
+	mock.recorder = &MockFeedTitleTranslationRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFeedTitleTranslationRepository) EXPECT() *MockFeedTitleTranslationRepositoryMockRecorder {
+	return m.recorder
+}
+
+// DeleteAll mocks base method.
+func (m *MockFeedTitleTranslationRepository) DeleteAll(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAll", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteAll indicates an expected call of DeleteAll.
+func (mr *MockFeedTitleTranslationRepositoryMockRecorder) DeleteAll(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAll", reflect.TypeOf((*MockFeedTitleTranslationRepository)(nil).DeleteAll), ctx)
+}
+
+// GetBatch mocks base method.
+func (m *MockFeedTitleTranslationRepository) GetBatch(ctx context.Context, feedIDs []int64, language string) (map[int64]*model.FeedTitleTranslation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBatch", ctx, feedIDs, language)
+	ret0, _ := ret[0].(map[int64]*model.FeedTitleTranslation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBatch indicates an expected call of GetBatch.
+func (mr *MockFeedTitleTranslationRepositoryMockRecorder) GetBatch(ctx, feedIDs, language any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBatch", reflect.TypeOf((*MockFeedTitleTranslationRepository)(nil).GetBatch), ctx, feedIDs, language)
+}
+
+// Save mocks base method.
+func (m *MockFeedTitleTranslationRepository) Save(ctx context.Context, feedID int64, language, sourceHash, title string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, feedID, language, sourceHash, title)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockFeedTitleTranslationRepositoryMockRecorder) Save(ctx, feedID, language, sourceHash, title any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockFeedTitleTranslationRepository)(nil).Save), ctx, feedID, language, sourceHash, title)
+}
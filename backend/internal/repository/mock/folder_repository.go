@@ -13,6 +13,7 @@ import (
 	context "context"
 	model "gist/backend/internal/model"
 	reflect "reflect"
+	time "time"
 
 	gomock "go.uber.org/mock/gomock"
 )
@@ -115,6 +116,64 @@ func (mr *MockFolderRepositoryMockRecorder) List(ctx any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockFolderRepository)(nil).List), ctx)
 }
 
+// ListDescendantIDs mocks base method.
+func (m *MockFolderRepository) ListDescendantIDs(ctx context.Context, folderID int64) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDescendantIDs", ctx, folderID)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDescendantIDs indicates an expected call of ListDescendantIDs.
+func (mr *MockFolderRepositoryMockRecorder) ListDescendantIDs(ctx, folderID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDescendantIDs", reflect.TypeOf((*MockFolderRepository)(nil).ListDescendantIDs), ctx, folderID)
+}
+
+// PurgeDeleted mocks base method.
+func (m *MockFolderRepository) PurgeDeleted(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeDeleted", ctx, deletedBefore)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeDeleted indicates an expected call of PurgeDeleted.
+func (mr *MockFolderRepositoryMockRecorder) PurgeDeleted(ctx, deletedBefore any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeDeleted", reflect.TypeOf((*MockFolderRepository)(nil).PurgeDeleted), ctx, deletedBefore)
+}
+
+// Reorder mocks base method.
+func (m *MockFolderRepository) Reorder(ctx context.Context, ids []int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reorder", ctx, ids)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Reorder indicates an expected call of Reorder.
+func (mr *MockFolderRepositoryMockRecorder) Reorder(ctx, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reorder", reflect.TypeOf((*MockFolderRepository)(nil).Reorder), ctx, ids)
+}
+
+// Restore mocks base method.
+func (m *MockFolderRepository) Restore(ctx context.Context, id int64, deletedSince time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, id, deletedSince)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockFolderRepositoryMockRecorder) Restore(ctx, id, deletedSince any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockFolderRepository)(nil).Restore), ctx, id, deletedSince)
+}
+
 // Update mocks base method.
 func (m *MockFolderRepository) Update(ctx context.Context, id int64, name string, parentID *int64) (model.Folder, error) {
 	m.ctrl.T.Helper()
@@ -130,6 +189,20 @@ func (mr *MockFolderRepositoryMockRecorder) Update(ctx, id, name, parentID any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockFolderRepository)(nil).Update), ctx, id, name, parentID)
 }
 
+// UpdateFeedDefaults mocks base method.
+func (m *MockFolderRepository) UpdateFeedDefaults(ctx context.Context, id int64, defaults model.FeedDefaults) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateFeedDefaults", ctx, id, defaults)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateFeedDefaults indicates an expected call of UpdateFeedDefaults.
+func (mr *MockFolderRepositoryMockRecorder) UpdateFeedDefaults(ctx, id, defaults any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateFeedDefaults", reflect.TypeOf((*MockFolderRepository)(nil).UpdateFeedDefaults), ctx, id, defaults)
+}
+
 // UpdateType mocks base method.
 func (m *MockFolderRepository) UpdateType(ctx context.Context, id int64, folderType string) error {
 	m.ctrl.T.Helper()
@@ -143,3 +216,17 @@ func (mr *MockFolderRepositoryMockRecorder) UpdateType(ctx, id, folderType any)
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateType", reflect.TypeOf((*MockFolderRepository)(nil).UpdateType), ctx, id, folderType)
 }
+
+// UpdateTypeCascade mocks base method.
+func (m *MockFolderRepository) UpdateTypeCascade(ctx context.Context, id int64, folderType string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTypeCascade", ctx, id, folderType)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateTypeCascade indicates an expected call of UpdateTypeCascade.
+func (mr *MockFolderRepositoryMockRecorder) UpdateTypeCascade(ctx, id, folderType any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTypeCascade", reflect.TypeOf((*MockFolderRepository)(nil).UpdateTypeCascade), ctx, id, folderType)
+}
@@ -0,0 +1,131 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: folder_rule_repository.go
+//
+// Generated by this command:
+//
+//	mockgen -source=folder_rule_repository.go -destination=mock/folder_rule_repository.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	model "gist/backend/internal/model"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockFolderRuleRepository is a mock of FolderRuleRepository interface.
+type MockFolderRuleRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockFolderRuleRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockFolderRuleRepositoryMockRecorder is the mock recorder for MockFolderRuleRepository.
+type MockFolderRuleRepositoryMockRecorder struct {
+	mock *MockFolderRuleRepository
+}
+
+// NewMockFolderRuleRepository creates a new mock instance.
+func NewMockFolderRuleRepository(ctrl *gomock.Controller) *MockFolderRuleRepository {
+	mock := &MockFolderRuleRepository{ctrl: ctrl}
+	mock.recorder = &MockFolderRuleRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFolderRuleRepository) EXPECT() *MockFolderRuleRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockFolderRuleRepository) Create(ctx context.Context, rule model.FolderRule) (model.FolderRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, rule)
+	ret0, _ := ret[0].(model.FolderRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockFolderRuleRepositoryMockRecorder) Create(ctx, rule any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockFolderRuleRepository)(nil).Create), ctx, rule)
+}
+
+// Delete mocks base method.
+func (m *MockFolderRuleRepository) Delete(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockFolderRuleRepositoryMockRecorder) Delete(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockFolderRuleRepository)(nil).Delete), ctx, id)
+}
+
+// GetByID mocks base method.
+func (m *MockFolderRuleRepository) GetByID(ctx context.Context, id int64) (model.FolderRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(model.FolderRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockFolderRuleRepositoryMockRecorder) GetByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockFolderRuleRepository)(nil).GetByID), ctx, id)
+}
+
+// List mocks base method.
+func (m *MockFolderRuleRepository) List(ctx context.Context) ([]model.FolderRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx)
+	ret0, _ := ret[0].([]model.FolderRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockFolderRuleRepositoryMockRecorder) List(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockFolderRuleRepository)(nil).List), ctx)
+}
+
+// ListByFolder mocks base method.
+func (m *MockFolderRuleRepository) ListByFolder(ctx context.Context, folderID int64) ([]model.FolderRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByFolder", ctx, folderID)
+	ret0, _ := ret[0].([]model.FolderRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByFolder indicates an expected call of ListByFolder.
+func (mr *MockFolderRuleRepositoryMockRecorder) ListByFolder(ctx, folderID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByFolder", reflect.TypeOf((*MockFolderRuleRepository)(nil).ListByFolder), ctx, folderID)
+}
+
+// Update mocks base method.
+func (m *MockFolderRuleRepository) Update(ctx context.Context, rule model.FolderRule) (model.FolderRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, rule)
+	ret0, _ := ret[0].(model.FolderRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockFolderRuleRepositoryMockRecorder) Update(ctx, rule any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockFolderRuleRepository)(nil).Update), ctx, rule)
+}
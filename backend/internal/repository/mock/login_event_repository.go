@@ -0,0 +1,71 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: login_event_repository.go
+//
+// Generated by this command:
+//
+//	mockgen -source=login_event_repository.go -destination=mock/login_event_repository.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	model "gist/backend/internal/model"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockLoginEventRepository is a mock of LoginEventRepository interface.
+type MockLoginEventRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockLoginEventRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockLoginEventRepositoryMockRecorder is the mock recorder for MockLoginEventRepository.
+type MockLoginEventRepositoryMockRecorder struct {
+	mock *MockLoginEventRepository
+}
+
+// NewMockLoginEventRepository creates a new mock instance.
+func NewMockLoginEventRepository(ctrl *gomock.Controller) *MockLoginEventRepository {
+	mock := &MockLoginEventRepository{ctrl: ctrl}
+	mock.recorder = &MockLoginEventRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLoginEventRepository) EXPECT() *MockLoginEventRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockLoginEventRepository) Create(ctx context.Context, event model.LoginEvent, keep int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, event, keep)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockLoginEventRepositoryMockRecorder) Create(ctx, event, keep any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockLoginEventRepository)(nil).Create), ctx, event, keep)
+}
+
+// List mocks base method.
+func (m *MockLoginEventRepository) List(ctx context.Context, limit int) ([]model.LoginEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, limit)
+	ret0, _ := ret[0].([]model.LoginEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockLoginEventRepositoryMockRecorder) List(ctx, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockLoginEventRepository)(nil).List), ctx, limit)
+}
@@ -0,0 +1,102 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: refresh_run_repository.go
+//
+// Generated by this command:
+//
+//	mockgen -source=refresh_run_repository.go -destination=mock/refresh_run_repository.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	model "gist/backend/internal/model"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRefreshRunRepository is a mock of RefreshRunRepository interface.
+type MockRefreshRunRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRefreshRunRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockRefreshRunRepositoryMockRecorder is the mock recorder for MockRefreshRunRepository.
+type MockRefreshRunRepositoryMockRecorder struct {
+	mock *MockRefreshRunRepository
+}
+
+// NewMockRefreshRunRepository creates a new mock instance.
+func NewMockRefreshRunRepository(ctrl *gomock.Controller) *MockRefreshRunRepository {
+	mock := &MockRefreshRunRepository{ctrl: ctrl}
+	mock.recorder = &MockRefreshRunRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRefreshRunRepository) EXPECT() *MockRefreshRunRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockRefreshRunRepository) Create(ctx context.Context, run model.RefreshRun, feeds []model.RefreshRunFeed, keep int) (model.RefreshRun, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, run, feeds, keep)
+	ret0, _ := ret[0].(model.RefreshRun)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockRefreshRunRepositoryMockRecorder) Create(ctx, run, feeds, keep any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockRefreshRunRepository)(nil).Create), ctx, run, feeds, keep)
+}
+
+// GetByID mocks base method.
+func (m *MockRefreshRunRepository) GetByID(ctx context.Context, id int64) (model.RefreshRun, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(model.RefreshRun)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockRefreshRunRepositoryMockRecorder) GetByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockRefreshRunRepository)(nil).GetByID), ctx, id)
+}
+
+// List mocks base method.
+func (m *MockRefreshRunRepository) List(ctx context.Context, limit int) ([]model.RefreshRun, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, limit)
+	ret0, _ := ret[0].([]model.RefreshRun)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockRefreshRunRepositoryMockRecorder) List(ctx, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockRefreshRunRepository)(nil).List), ctx, limit)
+}
+
+// ListFeeds mocks base method.
+func (m *MockRefreshRunRepository) ListFeeds(ctx context.Context, runID int64) ([]model.RefreshRunFeed, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListFeeds", ctx, runID)
+	ret0, _ := ret[0].([]model.RefreshRunFeed)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListFeeds indicates an expected call of ListFeeds.
+func (mr *MockRefreshRunRepositoryMockRecorder) ListFeeds(ctx, runID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListFeeds", reflect.TypeOf((*MockRefreshRunRepository)(nil).ListFeeds), ctx, runID)
+}
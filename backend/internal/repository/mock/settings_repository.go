@@ -127,3 +127,19 @@ func (mr *MockSettingsRepositoryMockRecorder) SetMany(ctx, values any) *gomock.C
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetMany", reflect.TypeOf((*MockSettingsRepository)(nil).SetMany), ctx, values)
 }
+
+// SetManyVersioned mocks base method.
+func (m *MockSettingsRepository) SetManyVersioned(ctx context.Context, versionKey, expected string, values map[string]string) (string, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetManyVersioned", ctx, versionKey, expected, values)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// SetManyVersioned indicates an expected call of SetManyVersioned.
+func (mr *MockSettingsRepositoryMockRecorder) SetManyVersioned(ctx, versionKey, expected, values any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetManyVersioned", reflect.TypeOf((*MockSettingsRepository)(nil).SetManyVersioned), ctx, versionKey, expected, values)
+}
@@ -0,0 +1,86 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: storage_repository.go
+//
+// Generated by this command:
+//
+//	mockgen -source=storage_repository.go -destination=mock/storage_repository.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	repository "gist/backend/internal/repository"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockStorageRepository is a mock of StorageRepository interface.
+type MockStorageRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockStorageRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockStorageRepositoryMockRecorder is the mock recorder for MockStorageRepository.
+type MockStorageRepositoryMockRecorder struct {
+	mock *MockStorageRepository
+}
+
+// NewMockStorageRepository creates a new mock instance.
+func NewMockStorageRepository(ctrl *gomock.Controller) *MockStorageRepository {
+	mock := &MockStorageRepository{ctrl: ctrl}
+	mock.recorder = &MockStorageRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStorageRepository) EXPECT() *MockStorageRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Checkpoint mocks base method.
+func (m *MockStorageRepository) Checkpoint(ctx context.Context) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Checkpoint", ctx)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Checkpoint indicates an expected call of Checkpoint.
+func (mr *MockStorageRepositoryMockRecorder) Checkpoint(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Checkpoint", reflect.TypeOf((*MockStorageRepository)(nil).Checkpoint), ctx)
+}
+
+// TableStats mocks base method.
+func (m *MockStorageRepository) TableStats(ctx context.Context, tables []string) ([]repository.TableStat, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TableStats", ctx, tables)
+	ret0, _ := ret[0].([]repository.TableStat)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TableStats indicates an expected call of TableStats.
+func (mr *MockStorageRepositoryMockRecorder) TableStats(ctx, tables any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TableStats", reflect.TypeOf((*MockStorageRepository)(nil).TableStats), ctx, tables)
+}
+
+// Vacuum mocks base method.
+func (m *MockStorageRepository) Vacuum(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Vacuum", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Vacuum indicates an expected call of Vacuum.
+func (mr *MockStorageRepositoryMockRecorder) Vacuum(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Vacuum", reflect.TypeOf((*MockStorageRepository)(nil).Vacuum), ctx)
+}
@@ -0,0 +1,141 @@
+//go:generate mockgen -source=$GOFILE -destination=mock/$GOFILE -package=mock
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"gist/backend/internal/model"
+	"gist/backend/pkg/snowflake"
+)
+
+// RefreshRunRepository defines the interface for refresh run history storage.
+type RefreshRunRepository interface {
+	// Create stores a run with its per-feed rows and trims history to the newest keep runs.
+	Create(ctx context.Context, run model.RefreshRun, feeds []model.RefreshRunFeed, keep int) (model.RefreshRun, error)
+	// List returns the newest runs first.
+	List(ctx context.Context, limit int) ([]model.RefreshRun, error)
+	GetByID(ctx context.Context, id int64) (model.RefreshRun, error)
+	ListFeeds(ctx context.Context, runID int64) ([]model.RefreshRunFeed, error)
+}
+
+type refreshRunRepository struct {
+	db      *sql.DB
+	changes *ChangeTracker
+	writes  *WriteTracker
+}
+
+// NewRefreshRunRepository creates a new refresh run repository.
+func NewRefreshRunRepository(db *sql.DB, changes *ChangeTracker, writes *WriteTracker) RefreshRunRepository {
+	return &refreshRunRepository{db: db, changes: changes, writes: writes}
+}
+
+// Create stores a run with its per-feed rows and trims history to the newest keep runs.
+func (r *refreshRunRepository) Create(ctx context.Context, run model.RefreshRun, feeds []model.RefreshRunFeed, keep int) (model.RefreshRun, error) {
+	run.ID = snowflake.NextID()
+	// Entry and feed writes already bump the change counter; bumping once more when
+	// the run is stored keeps clients that polled mid-run from holding a stale ETag
+	defer r.changes.Notify()
+
+	err := withTx(ctx, r.db, r.writes, func(tx dbtx) error {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO refresh_runs (id, trigger, started_at, finished_at, feeds_total, feeds_failed, entries_new, entries_updated, entries_evicted)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		`, run.ID, run.Trigger, formatTime(run.StartedAt), formatTime(run.FinishedAt), run.FeedsTotal, run.FeedsFailed, run.EntriesNew, run.EntriesUpdated, run.EntriesEvicted); err != nil {
+			return err
+		}
+
+		for _, feed := range feeds {
+			if _, err := tx.ExecContext(ctx, `
+				INSERT INTO refresh_run_feeds (id, run_id, feed_id, feed_title, entries_new, entries_updated, entries_evicted, error_message)
+				VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+			`, snowflake.NextID(), run.ID, feed.FeedID, feed.FeedTitle, feed.EntriesNew, feed.EntriesUpdated, feed.EntriesEvicted, feed.ErrorMessage); err != nil {
+				return err
+			}
+		}
+
+		// Detail rows go with their run via ON DELETE CASCADE
+		if _, err := tx.ExecContext(ctx, `
+			DELETE FROM refresh_runs WHERE id NOT IN (
+				SELECT id FROM refresh_runs ORDER BY started_at DESC, id DESC LIMIT ?
+			)
+		`, keep); err != nil {
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		return model.RefreshRun{}, err
+	}
+	return run, nil
+}
+
+// List returns the newest runs first.
+func (r *refreshRunRepository) List(ctx context.Context, limit int) ([]model.RefreshRun, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, trigger, started_at, finished_at, feeds_total, feeds_failed, entries_new, entries_updated, entries_evicted
+		FROM refresh_runs ORDER BY started_at DESC, id DESC LIMIT ?
+	`, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var runs []model.RefreshRun
+	for rows.Next() {
+		run, err := scanRefreshRun(rows)
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs, run)
+	}
+	return runs, rows.Err()
+}
+
+func (r *refreshRunRepository) GetByID(ctx context.Context, id int64) (model.RefreshRun, error) {
+	row := r.db.QueryRowContext(ctx, `
+		SELECT id, trigger, started_at, finished_at, feeds_total, feeds_failed, entries_new, entries_updated, entries_evicted
+		FROM refresh_runs WHERE id = ?
+	`, id)
+	return scanRefreshRun(row)
+}
+
+func (r *refreshRunRepository) ListFeeds(ctx context.Context, runID int64) ([]model.RefreshRunFeed, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT run_id, feed_id, feed_title, entries_new, entries_updated, entries_evicted, error_message
+		FROM refresh_run_feeds WHERE run_id = ?
+		ORDER BY error_message IS NULL, entries_new DESC, feed_title
+	`, runID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var feeds []model.RefreshRunFeed
+	for rows.Next() {
+		var f model.RefreshRunFeed
+		var errorMessage sql.NullString
+		if err := rows.Scan(&f.RunID, &f.FeedID, &f.FeedTitle, &f.EntriesNew, &f.EntriesUpdated, &f.EntriesEvicted, &errorMessage); err != nil {
+			return nil, err
+		}
+		if errorMessage.Valid {
+			f.ErrorMessage = &errorMessage.String
+		}
+		feeds = append(feeds, f)
+	}
+	return feeds, rows.Err()
+}
+
+func scanRefreshRun(scanner interface {
+	Scan(dest ...interface{}) error
+}) (model.RefreshRun, error) {
+	var run model.RefreshRun
+	var startedAt, finishedAt string
+	if err := scanner.Scan(&run.ID, &run.Trigger, &startedAt, &finishedAt, &run.FeedsTotal, &run.FeedsFailed, &run.EntriesNew, &run.EntriesUpdated, &run.EntriesEvicted); err != nil {
+		return model.RefreshRun{}, err
+	}
+	run.StartedAt, _ = parseTime(startedAt)
+	run.FinishedAt, _ = parseTime(finishedAt)
+	return run, nil
+}
@@ -0,0 +1,88 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"gist/backend/internal/model"
+	"gist/backend/internal/repository"
+	"gist/backend/internal/repository/testutil"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRefreshRunRepository_CreateAndGet(t *testing.T) {
+	t.Parallel()
+
+	db := testutil.NewTestDB(t)
+	repo := repository.NewRefreshRunRepository(db, nil, nil)
+	ctx := context.Background()
+
+	started := time.Now().UTC().Truncate(time.Second)
+	errMsg := "HTTP 500"
+	run, err := repo.Create(ctx, model.RefreshRun{
+		Trigger:     model.RefreshTriggerScheduled,
+		StartedAt:   started,
+		FinishedAt:  started.Add(3 * time.Second),
+		FeedsTotal:  2,
+		FeedsFailed: 1,
+		EntriesNew:  4,
+	}, []model.RefreshRunFeed{
+		{FeedID: 1, FeedTitle: "Quiet", EntriesNew: 0},
+		{FeedID: 2, FeedTitle: "Busy", EntriesNew: 4},
+		{FeedID: 3, FeedTitle: "Broken", ErrorMessage: &errMsg},
+	}, 50)
+	require.NoError(t, err)
+	require.NotZero(t, run.ID)
+
+	got, err := repo.GetByID(ctx, run.ID)
+	require.NoError(t, err)
+	require.Equal(t, model.RefreshTriggerScheduled, got.Trigger)
+	require.True(t, started.Equal(got.StartedAt))
+	require.Equal(t, 4, got.EntriesNew)
+	require.Equal(t, 1, got.FeedsFailed)
+
+	feeds, err := repo.ListFeeds(ctx, run.ID)
+	require.NoError(t, err)
+	require.Len(t, feeds, 3)
+	require.Equal(t, "Broken", feeds[0].FeedTitle, "failed feeds first")
+	require.Equal(t, "HTTP 500", *feeds[0].ErrorMessage)
+	require.Equal(t, "Busy", feeds[1].FeedTitle, "then by new entries")
+	require.Nil(t, feeds[2].ErrorMessage)
+
+	_, err = repo.GetByID(ctx, run.ID+1)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestRefreshRunRepository_CreateTrimsHistory(t *testing.T) {
+	t.Parallel()
+
+	db := testutil.NewTestDB(t)
+	repo := repository.NewRefreshRunRepository(db, nil, nil)
+	ctx := context.Background()
+
+	base := time.Now().UTC()
+	var first model.RefreshRun
+	for i := 0; i < 4; i++ {
+		run, err := repo.Create(ctx, model.RefreshRun{
+			Trigger:    model.RefreshTriggerManual,
+			StartedAt:  base.Add(time.Duration(i) * time.Minute),
+			FinishedAt: base.Add(time.Duration(i) * time.Minute),
+		}, []model.RefreshRunFeed{{FeedID: 1, FeedTitle: "F"}}, 3)
+		require.NoError(t, err)
+		if i == 0 {
+			first = run
+		}
+	}
+
+	runs, err := repo.List(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, runs, 3, "history is trimmed to keep newest runs")
+	require.True(t, runs[0].StartedAt.After(runs[1].StartedAt), "newest first")
+
+	feeds, err := repo.ListFeeds(ctx, first.ID)
+	require.NoError(t, err)
+	require.Empty(t, feeds, "detail rows of pruned runs are removed")
+}
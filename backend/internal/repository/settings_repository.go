@@ -5,6 +5,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strconv"
 	"time"
 
 	"gist/backend/internal/model"
@@ -15,18 +16,23 @@ type SettingsRepository interface {
 	Get(ctx context.Context, key string) (*model.Setting, error)
 	Set(ctx context.Context, key, value string) error
 	SetMany(ctx context.Context, values map[string]string) error
+	// SetManyVersioned writes values and bumps the counter stored under versionKey in one
+	// transaction. When expected is non-empty it must equal the stored counter, otherwise
+	// nothing is written and ok is false. It returns the counter after the write.
+	SetManyVersioned(ctx context.Context, versionKey, expected string, values map[string]string) (version string, ok bool, err error)
 	GetByPrefix(ctx context.Context, prefix string) ([]model.Setting, error)
 	Delete(ctx context.Context, key string) error
 	DeleteByPrefix(ctx context.Context, prefix string) (int64, error)
 }
 
 type settingsRepository struct {
-	db *sql.DB
+	db     *sql.DB
+	writes *WriteTracker
 }
 
 // NewSettingsRepository creates a new settings repository.
-func NewSettingsRepository(db *sql.DB) SettingsRepository {
-	return &settingsRepository{db: db}
+func NewSettingsRepository(db *sql.DB, writes *WriteTracker) SettingsRepository {
+	return &settingsRepository{db: db, writes: writes}
 }
 
 // Get retrieves a setting by key.
@@ -66,24 +72,72 @@ func (r *settingsRepository) SetMany(ctx context.Context, values map[string]stri
 		return nil
 	}
 
-	tx, err := r.db.BeginTx(ctx, nil)
-	if err != nil {
-		return err
-	}
-	defer tx.Rollback()
+	return withTx(ctx, r.db, r.writes, func(tx dbtx) error {
+		now := time.Now().UTC().Format(time.RFC3339)
+		for key, value := range values {
+			if _, err := tx.ExecContext(ctx, `
+				INSERT INTO settings (key, value, updated_at)
+				VALUES (?, ?, ?)
+				ON CONFLICT(key) DO UPDATE SET value = excluded.value, updated_at = excluded.updated_at
+			`, key, value, now); err != nil {
+				return fmt.Errorf("set setting %s: %w", key, err)
+			}
+		}
 
-	now := time.Now().UTC().Format(time.RFC3339)
-	for key, value := range values {
-		if _, err := tx.ExecContext(ctx, `
+		return nil
+	})
+}
+
+// SetManyVersioned writes values only when the version counter still matches expected.
+// A missing counter reads as "0".
+func (r *settingsRepository) SetManyVersioned(ctx context.Context, versionKey, expected string, values map[string]string) (string, bool, error) {
+	version, applied := "", false
+	err := withTx(ctx, r.db, r.writes, func(tx dbtx) error {
+		current := "0"
+		if err := tx.QueryRowContext(ctx, `SELECT value FROM settings WHERE key = ?`, versionKey).Scan(&current); err != nil && err != sql.ErrNoRows {
+			return fmt.Errorf("get version %s: %w", versionKey, err)
+		}
+		version = current
+		if expected != "" && expected != current {
+			return nil
+		}
+		n, _ := strconv.ParseInt(current, 10, 64)
+		next := strconv.FormatInt(n+1, 10)
+
+		now := time.Now().UTC().Format(time.RFC3339)
+		// The counter row doubles as the compare-and-set guard: a concurrent writer that
+		// bumped it since the read above makes this update miss.
+		res, err := tx.ExecContext(ctx, `
 			INSERT INTO settings (key, value, updated_at)
 			VALUES (?, ?, ?)
 			ON CONFLICT(key) DO UPDATE SET value = excluded.value, updated_at = excluded.updated_at
-		`, key, value, now); err != nil {
-			return fmt.Errorf("set setting %s: %w", key, err)
+			WHERE settings.value = ?
+		`, versionKey, next, now, current)
+		if err != nil {
+			return fmt.Errorf("set version %s: %w", versionKey, err)
+		}
+		if affected, err := res.RowsAffected(); err != nil {
+			return err
+		} else if affected == 0 {
+			return nil
 		}
-	}
 
-	return tx.Commit()
+		for key, value := range values {
+			if _, err := tx.ExecContext(ctx, `
+				INSERT INTO settings (key, value, updated_at)
+				VALUES (?, ?, ?)
+				ON CONFLICT(key) DO UPDATE SET value = excluded.value, updated_at = excluded.updated_at
+			`, key, value, now); err != nil {
+				return fmt.Errorf("set setting %s: %w", key, err)
+			}
+		}
+		version, applied = next, true
+		return nil
+	})
+	if err != nil {
+		return "", false, err
+	}
+	return version, applied, nil
 }
 
 // GetByPrefix retrieves all settings with keys starting with the given prefix.
@@ -13,7 +13,7 @@ import (
 func TestSettingsRepository_Set_Insert(t *testing.T) {
 	t.Parallel()
 	db := testutil.NewTestDB(t)
-	repo := repository.NewSettingsRepository(db)
+	repo := repository.NewSettingsRepository(db, nil)
 	ctx := context.Background()
 
 	err := repo.Set(ctx, "test.key", "test value")
@@ -31,7 +31,7 @@ func TestSettingsRepository_Set_Insert(t *testing.T) {
 func TestSettingsRepository_Set_Update(t *testing.T) {
 	t.Parallel()
 	db := testutil.NewTestDB(t)
-	repo := repository.NewSettingsRepository(db)
+	repo := repository.NewSettingsRepository(db, nil)
 	ctx := context.Background()
 
 	// Initial insertion
@@ -50,7 +50,7 @@ func TestSettingsRepository_Set_Update(t *testing.T) {
 func TestSettingsRepository_Get_Success(t *testing.T) {
 	t.Parallel()
 	db := testutil.NewTestDB(t)
-	repo := repository.NewSettingsRepository(db)
+	repo := repository.NewSettingsRepository(db, nil)
 	ctx := context.Background()
 
 	testutil.SeedSetting(t, db, "ai.provider", "openai")
@@ -65,7 +65,7 @@ func TestSettingsRepository_Get_Success(t *testing.T) {
 func TestSettingsRepository_Get_NotFound(t *testing.T) {
 	t.Parallel()
 	db := testutil.NewTestDB(t)
-	repo := repository.NewSettingsRepository(db)
+	repo := repository.NewSettingsRepository(db, nil)
 	ctx := context.Background()
 
 	setting, err := repo.Get(ctx, "nonexistent.key")
@@ -76,7 +76,7 @@ func TestSettingsRepository_Get_NotFound(t *testing.T) {
 func TestSettingsRepository_GetByPrefix_Success(t *testing.T) {
 	t.Parallel()
 	db := testutil.NewTestDB(t)
-	repo := repository.NewSettingsRepository(db)
+	repo := repository.NewSettingsRepository(db, nil)
 	ctx := context.Background()
 
 	// Seed multiple settings with different prefixes
@@ -99,7 +99,7 @@ func TestSettingsRepository_GetByPrefix_Success(t *testing.T) {
 func TestSettingsRepository_Delete_Success(t *testing.T) {
 	t.Parallel()
 	db := testutil.NewTestDB(t)
-	repo := repository.NewSettingsRepository(db)
+	repo := repository.NewSettingsRepository(db, nil)
 	ctx := context.Background()
 
 	testutil.SeedSetting(t, db, "test.key", "test value")
@@ -117,7 +117,7 @@ func TestSettingsRepository_Delete_Success(t *testing.T) {
 func TestSettingsRepository_DeleteByPrefix(t *testing.T) {
 	t.Parallel()
 	db := testutil.NewTestDB(t)
-	repo := repository.NewSettingsRepository(db)
+	repo := repository.NewSettingsRepository(db, nil)
 	ctx := context.Background()
 
 	testutil.SeedSetting(t, db, "ai.provider", "openai")
@@ -136,3 +136,33 @@ func TestSettingsRepository_DeleteByPrefix(t *testing.T) {
 	require.NoError(t, err)
 	require.NotNil(t, setting)
 }
+
+func TestSettingsRepository_SetManyVersioned(t *testing.T) {
+	t.Parallel()
+	db := testutil.NewTestDB(t)
+	repo := repository.NewSettingsRepository(db, nil)
+	ctx := context.Background()
+
+	version, ok, err := repo.SetManyVersioned(ctx, "meta.v", "", map[string]string{"a.one": "1"})
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, "1", version)
+
+	version, ok, err = repo.SetManyVersioned(ctx, "meta.v", "1", map[string]string{"a.one": "2", "a.two": "2"})
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, "2", version)
+
+	// A stale version writes nothing
+	version, ok, err = repo.SetManyVersioned(ctx, "meta.v", "1", map[string]string{"a.one": "stale"})
+	require.NoError(t, err)
+	require.False(t, ok)
+	require.Equal(t, "2", version)
+
+	setting, err := repo.Get(ctx, "a.one")
+	require.NoError(t, err)
+	require.Equal(t, "2", setting.Value)
+	setting, err = repo.Get(ctx, "meta.v")
+	require.NoError(t, err)
+	require.Equal(t, "2", setting.Value)
+}
@@ -3,15 +3,49 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"time"
+
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
 )
 
+// ErrConflict is returned when a write would break a unique index.
+var ErrConflict = errors.New("conflict")
+
 type dbtx interface {
 	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
 	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
 	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
 }
 
+// withTx runs fn in a transaction, or directly on db when it already is one.
+// writes tracks transactions started here so shutdown can wait for them.
+func withTx(ctx context.Context, db dbtx, writes *WriteTracker, fn func(tx dbtx) error) error {
+	conn, ok := db.(*sql.DB)
+	if !ok {
+		return fn(db)
+	}
+	defer writes.Begin()()
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// isUniqueViolation reports whether err is SQLite rejecting a duplicate key.
+func isUniqueViolation(err error) bool {
+	var sqliteErr *sqlite.Error
+	return errors.As(err, &sqliteErr) && sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_UNIQUE
+}
+
 func nullableInt64(value *int64) interface{} {
 	if value == nil {
 		return nil
@@ -26,6 +60,22 @@ func nullableString(value *string) interface{} {
 	return *value
 }
 
+func nullableBool(value *bool) interface{} {
+	if value == nil {
+		return nil
+	}
+	return boolToInt(*value)
+}
+
+// nullBoolPtr converts a nullable 0/1 column.
+func nullBoolPtr(value sql.NullInt64) *bool {
+	if !value.Valid {
+		return nil
+	}
+	b := value.Int64 != 0
+	return &b
+}
+
 func formatTime(value time.Time) string {
 	return value.UTC().Format(time.RFC3339Nano)
 }
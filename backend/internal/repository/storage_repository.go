@@ -0,0 +1,111 @@
+//go:generate mockgen -source=$GOFILE -destination=mock/$GOFILE -package=mock
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+)
+
+// TableStat is the size of one table.
+type TableStat struct {
+	Name string
+	Rows int64
+	// Bytes is the space of the table's pages and its indexes' pages, nil when
+	// SQLite was built without the dbstat table.
+	Bytes *int64
+}
+
+// StorageRepository reports on and compacts the database file itself.
+type StorageRepository interface {
+	// TableStats counts the rows of each of tables that exists, in the order given.
+	TableStats(ctx context.Context, tables []string) ([]TableStat, error)
+	// Checkpoint copies the WAL into the database and truncates it. busy is set
+	// when readers or writers kept it from completing.
+	Checkpoint(ctx context.Context) (busy bool, err error)
+	// Vacuum rebuilds the database file, giving free pages back to the filesystem.
+	Vacuum(ctx context.Context) error
+}
+
+type storageRepository struct {
+	db *sql.DB
+}
+
+func NewStorageRepository(db *sql.DB) StorageRepository {
+	return &storageRepository{db: db}
+}
+
+func (r *storageRepository) TableStats(ctx context.Context, tables []string) ([]TableStat, error) {
+	var stats []TableStat
+	for _, table := range tables {
+		// Table names can't be bound, so only names of existing tables are
+		// quoted into the count query
+		var exists int
+		if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?`, table).Scan(&exists); err != nil {
+			return nil, err
+		}
+		if exists == 0 {
+			continue
+		}
+		stat := TableStat{Name: table}
+		if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM "`+strings.ReplaceAll(table, `"`, `""`)+`"`).Scan(&stat.Rows); err != nil {
+			return nil, err
+		}
+		stats = append(stats, stat)
+	}
+	if len(stats) == 0 {
+		return stats, nil
+	}
+
+	bytes, ok := r.tableBytes(ctx)
+	if !ok {
+		return stats, nil
+	}
+	for i := range stats {
+		size := bytes[stats[i].Name]
+		stats[i].Bytes = &size
+	}
+	return stats, nil
+}
+
+// tableBytes sums page sizes per table, indexes included, from the dbstat
+// table. ok is false when SQLite has none.
+func (r *storageRepository) tableBytes(ctx context.Context) (map[string]int64, bool) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT m.tbl_name, SUM(s.pgsize)
+		FROM dbstat s
+		INNER JOIN sqlite_master m ON m.name = s.name
+		GROUP BY m.tbl_name
+	`)
+	if err != nil {
+		return nil, false
+	}
+	defer rows.Close()
+
+	bytes := make(map[string]int64)
+	for rows.Next() {
+		var name string
+		var size int64
+		if err := rows.Scan(&name, &size); err != nil {
+			return nil, false
+		}
+		bytes[name] = size
+	}
+	if rows.Err() != nil {
+		return nil, false
+	}
+	return bytes, true
+}
+
+func (r *storageRepository) Checkpoint(ctx context.Context) (bool, error) {
+	var busy, logFrames, checkpointed int
+	if err := r.db.QueryRowContext(ctx, `PRAGMA wal_checkpoint(TRUNCATE)`).Scan(&busy, &logFrames, &checkpointed); err != nil {
+		return false, err
+	}
+	return busy != 0, nil
+}
+
+func (r *storageRepository) Vacuum(ctx context.Context) error {
+	_, err := r.db.ExecContext(ctx, `VACUUM`)
+	return err
+}
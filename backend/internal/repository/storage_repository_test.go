@@ -0,0 +1,50 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+
+	"gist/backend/internal/model"
+	"gist/backend/internal/repository"
+	"gist/backend/internal/repository/testutil"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStorageRepository_TableStats(t *testing.T) {
+	t.Parallel()
+
+	db := testutil.NewTestDB(t)
+	repo := repository.NewStorageRepository(db)
+	ctx := context.Background()
+
+	feedID := testutil.SeedFeed(t, db, model.Feed{Title: "Feed", URL: "https://example.com/feed.xml"})
+	testutil.SeedEntry(t, db, model.Entry{FeedID: feedID})
+	testutil.SeedEntry(t, db, model.Entry{FeedID: feedID})
+
+	stats, err := repo.TableStats(ctx, []string{"entries", "missing", `feeds"; DROP TABLE feeds; --`, "ai_summaries"})
+	require.NoError(t, err)
+	require.Len(t, stats, 2)
+
+	require.Equal(t, "entries", stats[0].Name)
+	require.Equal(t, int64(2), stats[0].Rows)
+	require.NotNil(t, stats[0].Bytes)
+	require.Positive(t, *stats[0].Bytes)
+
+	require.Equal(t, "ai_summaries", stats[1].Name)
+	require.Zero(t, stats[1].Rows)
+	require.NotNil(t, stats[1].Bytes)
+}
+
+func TestStorageRepository_CheckpointAndVacuum(t *testing.T) {
+	t.Parallel()
+
+	db := testutil.NewTestFileDB(t)
+	repo := repository.NewStorageRepository(db)
+	ctx := context.Background()
+
+	busy, err := repo.Checkpoint(ctx)
+	require.NoError(t, err)
+	require.False(t, busy)
+	require.NoError(t, repo.Vacuum(ctx))
+}
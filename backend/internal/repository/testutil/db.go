@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"path/filepath"
 	"strings"
 	"sync"
 	"testing"
@@ -12,6 +13,7 @@ import (
 	"gist/backend/internal/db"
 	"gist/backend/internal/hashutil"
 	"gist/backend/internal/model"
+	"gist/backend/internal/urlutil"
 	"gist/backend/pkg/snowflake"
 
 	_ "modernc.org/sqlite"
@@ -53,6 +55,29 @@ func NewTestDB(t *testing.T) *sql.DB {
 	return database
 }
 
+// NewTestFileDB 在临时目录创建文件 SQLite 数据库（WAL 模式），用于并发写入测试
+// 共享缓存的内存数据库在并发事务下会返回 SQLITE_LOCKED，无法反映真实行为
+func NewTestFileDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	snowflakeOnce.Do(func() {
+		if err := snowflake.Init(0); err != nil {
+			panic("failed to initialize snowflake: " + err.Error())
+		}
+	})
+
+	database, err := db.Open(filepath.Join(t.TempDir(), "gist.db"))
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+
+	t.Cleanup(func() {
+		database.Close()
+	})
+
+	return database
+}
+
 // ptrVal 将指针转换为 interface{}，nil 指针返回 nil
 func ptrVal[T any](p *T) interface{} {
 	if p == nil {
@@ -69,6 +94,14 @@ func timeVal(t *time.Time) interface{} {
 	return t.UTC().Format(time.RFC3339)
 }
 
+// stringVal 将空字符串转换为 nil
+func stringVal(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
 // boolToInt 将布尔值转换为整数 (0/1)
 func boolToInt(b bool) int {
 	if b {
@@ -115,15 +148,21 @@ func SeedFeed(t *testing.T, db *sql.DB, feed model.Feed) int64 {
 	if feed.Type == "" {
 		feed.Type = "article"
 	}
+	if feed.DedupeKey == "" {
+		feed.DedupeKey = model.DedupeKeyAuto
+	}
+	if feed.AutoTranslate == "" {
+		feed.AutoTranslate = model.FeedAutoTranslateInherit
+	}
 
 	now := time.Now().UTC().Format(time.RFC3339)
 
 	_, err := db.ExecContext(
 		context.Background(),
-		`INSERT INTO feeds (id, folder_id, title, url, site_url, description, summary_prompt_reminder, icon_path, type, etag, last_modified, error_message, created_at, updated_at)
-		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
-		feed.ID, ptrVal(feed.FolderID), feed.Title, feed.URL, ptrVal(feed.SiteURL), ptrVal(feed.Description),
-		ptrVal(feed.SummaryPromptReminder), ptrVal(feed.IconPath), feed.Type, ptrVal(feed.ETag), ptrVal(feed.LastModified), ptrVal(feed.ErrorMessage), now, now,
+		`INSERT INTO feeds (id, folder_id, title, custom_title, url, canonical_url, site_url, description, summary_prompt_reminder, icon_path, type, etag, last_modified, error_message, assume_timezone, paused_until, dedupe_key, auto_translate, language, created_at, updated_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		feed.ID, ptrVal(feed.FolderID), feed.Title, ptrVal(feed.CustomTitle), feed.URL, urlutil.CanonicalFeedURL(feed.URL), ptrVal(feed.SiteURL), ptrVal(feed.Description),
+		ptrVal(feed.SummaryPromptReminder), ptrVal(feed.IconPath), feed.Type, ptrVal(feed.ETag), ptrVal(feed.LastModified), ptrVal(feed.ErrorMessage), ptrVal(feed.AssumeTimezone), timeVal(feed.PausedUntil), feed.DedupeKey, feed.AutoTranslate, feed.Language, now, now,
 	)
 	if err != nil {
 		t.Fatalf("failed to seed feed: %v", err)
@@ -147,10 +186,10 @@ func SeedEntry(t *testing.T, db *sql.DB, entry model.Entry) int64 {
 
 	_, err := db.ExecContext(
 		context.Background(),
-		`INSERT INTO entries (id, feed_id, hash, title, url, content, readable_content, thumbnail_url, author, published_at, read, starred, created_at, updated_at)
-		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		`INSERT INTO entries (id, feed_id, hash, title, url, content, readable_content, thumbnail_url, author, published_at, read, starred, word_count, language, created_at, updated_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 		entry.ID, entry.FeedID, entry.Hash, ptrVal(entry.Title), ptrVal(entry.URL), ptrVal(entry.Content), ptrVal(entry.ReadableContent),
-		ptrVal(entry.ThumbnailURL), ptrVal(entry.Author), timeVal(entry.PublishedAt), boolToInt(entry.Read), boolToInt(entry.Starred), now, now,
+		ptrVal(entry.ThumbnailURL), ptrVal(entry.Author), timeVal(entry.PublishedAt), boolToInt(entry.Read), boolToInt(entry.Starred), entry.WordCount, stringVal(entry.Language), now, now,
 	)
 	if err != nil {
 		t.Fatalf("failed to seed entry: %v", err)
@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+	"sync"
+)
+
+// WriteTracker counts write transactions in progress, so shutdown can let them
+// commit before the database is closed.
+type WriteTracker struct {
+	mu      sync.Mutex
+	count   int
+	waiters []chan struct{}
+}
+
+// NewWriteTracker creates an idle tracker.
+func NewWriteTracker() *WriteTracker {
+	return &WriteTracker{}
+}
+
+// Begin marks a write transaction as started; call the returned func when it
+// ends. A nil tracker returns a no-op.
+func (t *WriteTracker) Begin() (done func()) {
+	if t == nil {
+		return func() {}
+	}
+	t.mu.Lock()
+	t.count++
+	t.mu.Unlock()
+
+	return func() {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		t.count--
+		if t.count == 0 {
+			for _, waiter := range t.waiters {
+				close(waiter)
+			}
+			t.waiters = nil
+		}
+	}
+}
+
+// Wait blocks until no write transaction is in progress, or returns ctx.Err()
+// when ctx ends first.
+func (t *WriteTracker) Wait(ctx context.Context) error {
+	t.mu.Lock()
+	if t.count == 0 {
+		t.mu.Unlock()
+		return nil
+	}
+	idle := make(chan struct{})
+	t.waiters = append(t.waiters, idle)
+	t.mu.Unlock()
+
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
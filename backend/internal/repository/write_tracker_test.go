@@ -0,0 +1,37 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gist/backend/internal/repository"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteTracker_Wait(t *testing.T) {
+	writes := repository.NewWriteTracker()
+	require.NoError(t, writes.Wait(context.Background()))
+
+	done := writes.Begin()
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	require.ErrorIs(t, writes.Wait(ctx), context.DeadlineExceeded)
+
+	waited := make(chan error, 1)
+	go func() { waited <- writes.Wait(context.Background()) }()
+	time.Sleep(10 * time.Millisecond)
+	done()
+	select {
+	case err := <-waited:
+		require.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("Wait did not return after the write finished")
+	}
+}
+
+func TestWriteTracker_NilIsNoop(t *testing.T) {
+	var writes *repository.WriteTracker
+	writes.Begin()()
+}
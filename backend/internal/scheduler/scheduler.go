@@ -6,21 +6,32 @@ import (
 	"time"
 
 	"gist/backend/pkg/logger"
+	"gist/backend/internal/model"
 	"gist/backend/internal/service"
 )
 
 type Scheduler struct {
 	refreshService service.RefreshService
+	feedService    service.FeedService // detects overlapping feeds and purges the trash after each refresh; may be nil
+	imageCache     service.ImageCacheService // drops images of deleted or unstarred entries; may be nil
+	autoTranslate  service.AutoTranslateService // translates list titles of new entries; may be nil
+	settings       service.SettingsService // supplies the quiet hours ticks are skipped in; may be nil
 	interval       time.Duration
 	stopCh         chan struct{}
 	wg             sync.WaitGroup
-	cancelFunc     context.CancelFunc // cancels the current refresh operation
+	cancelFunc     context.CancelFunc // cancels the current refresh or translation
 	mu             sync.Mutex         // protects cancelFunc
 }
 
-func New(refreshService service.RefreshService, interval time.Duration) *Scheduler {
+// New returns a scheduler that skips its ticks inside the quiet hours of the
+// general settings.
+func New(refreshService service.RefreshService, feedService service.FeedService, imageCache service.ImageCacheService, autoTranslate service.AutoTranslateService, interval time.Duration, settings service.SettingsService) *Scheduler {
 	return &Scheduler{
 		refreshService: refreshService,
+		feedService:    feedService,
+		imageCache:     imageCache,
+		autoTranslate:  autoTranslate,
+		settings:       settings,
 		interval:       interval,
 		stopCh:         make(chan struct{}),
 	}
@@ -49,7 +60,7 @@ func (s *Scheduler) run() {
 	defer s.wg.Done()
 
 	// Run immediately on start
-	s.refresh()
+	s.tick()
 
 	ticker := time.NewTicker(s.interval)
 	defer ticker.Stop()
@@ -57,13 +68,27 @@ func (s *Scheduler) run() {
 	for {
 		select {
 		case <-ticker.C:
-			s.refresh()
+			s.tick()
 		case <-s.stopCh:
 			return
 		}
 	}
 }
 
+// tick runs the scheduled jobs, unless quiet hours are on. Manual refreshes
+// don't go through the scheduler and still run.
+func (s *Scheduler) tick() {
+	if until, quiet := service.QuietUntil(context.Background(), s.settings, time.Now()); quiet {
+		logger.Info("scheduled run skipped", "module", "scheduler", "action", "refresh", "resource", "feed", "result", "skipped", "reason", "quiet_hours", "until", until.Format(time.RFC3339))
+		return
+	}
+	s.refresh()
+	s.translate()
+	s.detectOverlaps()
+	s.purge()
+	s.collectImages()
+}
+
 func (s *Scheduler) refresh() {
 	// Use the same timeout as the refresh interval
 	ctx, cancel := context.WithTimeout(context.Background(), s.interval)
@@ -81,7 +106,7 @@ func (s *Scheduler) refresh() {
 	}()
 
 	logger.Info("scheduled feed refresh started", "module", "scheduler", "action", "refresh", "resource", "feed", "result", "ok")
-	if err := s.refreshService.RefreshAll(ctx); err != nil {
+	if err := s.refreshService.RefreshAll(ctx, model.RefreshTriggerScheduled); err != nil {
 		if ctx.Err() != nil {
 			logger.Warn("scheduled refresh cancelled", "module", "scheduler", "action", "refresh", "resource", "feed", "result", "cancelled")
 			return
@@ -90,3 +115,76 @@ func (s *Scheduler) refresh() {
 	}
 	logger.Info("scheduled feed refresh completed", "module", "scheduler", "action", "refresh", "resource", "feed", "result", "ok")
 }
+
+// translate translates list titles of the entries the refresh brought in.
+func (s *Scheduler) translate() {
+	if s.autoTranslate == nil {
+		return
+	}
+	select {
+	case <-s.stopCh:
+		return
+	default:
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.interval)
+	s.mu.Lock()
+	s.cancelFunc = cancel
+	s.mu.Unlock()
+	defer func() {
+		cancel()
+		s.mu.Lock()
+		s.cancelFunc = nil
+		s.mu.Unlock()
+	}()
+
+	if err := s.autoTranslate.Run(ctx); err != nil && ctx.Err() == nil {
+		logger.Error("scheduled auto translate failed", "module", "scheduler", "action", "fetch", "resource", "ai", "result", "failed", "error", err)
+	}
+}
+
+// detectOverlaps flags feeds that duplicate another subscription. Merging
+// them is left to the user.
+func (s *Scheduler) detectOverlaps() {
+	if s.feedService == nil {
+		return
+	}
+	select {
+	case <-s.stopCh:
+		return
+	default:
+	}
+	if _, err := s.feedService.DetectOverlaps(context.Background()); err != nil {
+		logger.Error("scheduled overlap detection failed", "module", "scheduler", "action", "fetch", "resource", "feed", "result", "failed", "error", err)
+	}
+}
+
+// purge hard-deletes feeds and folders whose restore window has passed.
+func (s *Scheduler) purge() {
+	if s.feedService == nil {
+		return
+	}
+	select {
+	case <-s.stopCh:
+		return
+	default:
+	}
+	if err := s.feedService.PurgeDeleted(context.Background()); err != nil {
+		logger.Error("scheduled purge failed", "module", "scheduler", "action", "delete", "resource", "feed", "result", "failed", "error", err)
+	}
+}
+
+// collectImages removes cached images whose entries are gone or no longer qualify.
+func (s *Scheduler) collectImages() {
+	if s.imageCache == nil {
+		return
+	}
+	select {
+	case <-s.stopCh:
+		return
+	default:
+	}
+	if _, err := s.imageCache.CollectGarbage(context.Background()); err != nil {
+		logger.Error("scheduled image cache cleanup failed", "module", "scheduler", "action", "clear", "resource", "image", "result", "failed", "error", err)
+	}
+}
@@ -1,6 +1,7 @@
 package scheduler_test
 
 import (
+	"context"
 	"gist/backend/internal/scheduler"
 	"testing"
 	"time"
@@ -8,6 +9,8 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 
+	"gist/backend/internal/model"
+	"gist/backend/internal/service"
 	"gist/backend/internal/service/mock"
 )
 
@@ -18,9 +21,9 @@ func TestScheduler(t *testing.T) {
 	mockRefresh := mock.NewMockRefreshService(ctrl)
 
 	// RefreshAll should be called once immediately on Start
-	mockRefresh.EXPECT().RefreshAll(gomock.Any()).Return(nil).AnyTimes()
+	mockRefresh.EXPECT().RefreshAll(gomock.Any(), model.RefreshTriggerScheduled).Return(nil).AnyTimes()
 
-	s := scheduler.New(mockRefresh, 100*time.Millisecond)
+	s := scheduler.New(mockRefresh, nil, nil, nil, 100*time.Millisecond, nil)
 	s.Start()
 
 	// Let it run for a bit
@@ -29,3 +32,156 @@ func TestScheduler(t *testing.T) {
 	s.Stop()
 	require.True(t, true) // If we reach here without panic/deadlock, it's good
 }
+
+func TestScheduler_PurgesDeletedAfterRefresh(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRefresh := mock.NewMockRefreshService(ctrl)
+	mockFeeds := mock.NewMockFeedService(ctrl)
+
+	purged := make(chan struct{}, 1)
+	refresh := mockRefresh.EXPECT().RefreshAll(gomock.Any(), model.RefreshTriggerScheduled).Return(nil).MinTimes(1)
+	mockFeeds.EXPECT().DetectOverlaps(gomock.Any()).Return(0, nil).AnyTimes()
+	mockFeeds.EXPECT().PurgeDeleted(gomock.Any()).DoAndReturn(func(context.Context) error {
+		select {
+		case purged <- struct{}{}:
+		default:
+		}
+		return nil
+	}).After(refresh).MinTimes(1)
+
+	s := scheduler.New(mockRefresh, mockFeeds, nil, nil, time.Hour, nil)
+	s.Start()
+
+	select {
+	case <-purged:
+	case <-time.After(time.Second):
+		t.Fatal("purge did not run after the initial refresh")
+	}
+	s.Stop()
+}
+
+func TestScheduler_DetectsOverlapsBeforePurge(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRefresh := mock.NewMockRefreshService(ctrl)
+	mockFeeds := mock.NewMockFeedService(ctrl)
+
+	purged := make(chan struct{}, 1)
+	refresh := mockRefresh.EXPECT().RefreshAll(gomock.Any(), model.RefreshTriggerScheduled).Return(nil).MinTimes(1)
+	detect := mockFeeds.EXPECT().DetectOverlaps(gomock.Any()).Return(1, nil).After(refresh).MinTimes(1)
+	mockFeeds.EXPECT().PurgeDeleted(gomock.Any()).DoAndReturn(func(context.Context) error {
+		select {
+		case purged <- struct{}{}:
+		default:
+		}
+		return nil
+	}).After(detect).MinTimes(1)
+
+	s := scheduler.New(mockRefresh, mockFeeds, nil, nil, time.Hour, nil)
+	s.Start()
+
+	select {
+	case <-purged:
+	case <-time.After(time.Second):
+		t.Fatal("purge did not run after overlap detection")
+	}
+	s.Stop()
+}
+
+func TestScheduler_TranslatesAfterRefresh(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRefresh := mock.NewMockRefreshService(ctrl)
+	mockTranslate := mock.NewMockAutoTranslateService(ctrl)
+
+	translated := make(chan struct{}, 1)
+	refresh := mockRefresh.EXPECT().RefreshAll(gomock.Any(), model.RefreshTriggerScheduled).Return(nil).MinTimes(1)
+	mockTranslate.EXPECT().Run(gomock.Any()).DoAndReturn(func(context.Context) error {
+		select {
+		case translated <- struct{}{}:
+		default:
+		}
+		return nil
+	}).After(refresh).MinTimes(1)
+
+	s := scheduler.New(mockRefresh, nil, nil, mockTranslate, time.Hour, nil)
+	s.Start()
+
+	select {
+	case <-translated:
+	case <-time.After(time.Second):
+		t.Fatal("auto translate did not run after the initial refresh")
+	}
+	s.Stop()
+}
+
+func TestScheduler_CollectsImagesAfterPurge(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRefresh := mock.NewMockRefreshService(ctrl)
+	mockFeeds := mock.NewMockFeedService(ctrl)
+	mockImages := mock.NewMockImageCacheService(ctrl)
+
+	collected := make(chan struct{}, 1)
+	refresh := mockRefresh.EXPECT().RefreshAll(gomock.Any(), model.RefreshTriggerScheduled).Return(nil).MinTimes(1)
+	mockFeeds.EXPECT().DetectOverlaps(gomock.Any()).Return(0, nil).AnyTimes()
+	purge := mockFeeds.EXPECT().PurgeDeleted(gomock.Any()).Return(nil).After(refresh).MinTimes(1)
+	mockImages.EXPECT().CollectGarbage(gomock.Any()).DoAndReturn(func(context.Context) (int, error) {
+		select {
+		case collected <- struct{}{}:
+		default:
+		}
+		return 0, nil
+	}).After(purge).MinTimes(1)
+
+	s := scheduler.New(mockRefresh, mockFeeds, mockImages, nil, time.Hour, nil)
+	s.Start()
+
+	select {
+	case <-collected:
+	case <-time.After(time.Second):
+		t.Fatal("image cache cleanup did not run after the purge")
+	}
+	s.Stop()
+}
+
+func TestScheduler_SkipsTicksInQuietHours(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// No RefreshAll, PurgeDeleted or Run is expected
+	mockRefresh := mock.NewMockRefreshService(ctrl)
+	mockFeeds := mock.NewMockFeedService(ctrl)
+	mockTranslate := mock.NewMockAutoTranslateService(ctrl)
+	mockSettings := mock.NewMockSettingsService(ctrl)
+
+	now := time.Now().UTC()
+	checked := make(chan struct{}, 1)
+	mockSettings.EXPECT().GetGeneralSettings(gomock.Any()).DoAndReturn(func(context.Context) (*service.GeneralSettings, error) {
+		select {
+		case checked <- struct{}{}:
+		default:
+		}
+		return &service.GeneralSettings{
+			Timezone:        "UTC",
+			QuietHoursStart: now.Add(-time.Hour).Format("15:04"),
+			QuietHoursEnd:   now.Add(time.Hour).Format("15:04"),
+		}, nil
+	}).MinTimes(1)
+
+	s := scheduler.New(mockRefresh, mockFeeds, nil, mockTranslate, 20*time.Millisecond, mockSettings)
+	s.Start()
+
+	select {
+	case <-checked:
+	case <-time.After(time.Second):
+		t.Fatal("quiet hours were not checked")
+	}
+	time.Sleep(60 * time.Millisecond)
+	s.Stop()
+}
@@ -91,6 +91,9 @@ func (p *AnthropicProvider) SummarizeStream(ctx context.Context, systemPrompt, c
 		stream := p.client.Messages.NewStreaming(ctx, params)
 		defer stream.Close() // Close HTTP connection when done or cancelled
 
+		var usage messageUsage
+		defer usage.report(ctx)
+
 		for stream.Next() {
 			event := stream.Current()
 
@@ -104,6 +107,8 @@ func (p *AnthropicProvider) SummarizeStream(ctx context.Context, systemPrompt, c
 						return
 					}
 				}
+			default:
+				usage.observe(event)
 			}
 		}
 
@@ -139,8 +144,10 @@ func (p *AnthropicProvider) Complete(ctx context.Context, systemPrompt, content
 	applyRequestOptions(&params, p.requestOptions)
 
 	var result strings.Builder
+	var usage messageUsage
 	stream := p.client.Messages.NewStreaming(ctx, params)
 	defer stream.Close()
+	defer usage.report(ctx)
 
 	for stream.Next() {
 		event := stream.Current()
@@ -150,6 +157,8 @@ func (p *AnthropicProvider) Complete(ctx context.Context, systemPrompt, content
 			case anthropic.TextDelta:
 				result.WriteString(deltaVariant.Text)
 			}
+		default:
+			usage.observe(event)
 		}
 	}
 
@@ -159,3 +168,25 @@ func (p *AnthropicProvider) Complete(ctx context.Context, systemPrompt, content
 
 	return result.String(), nil
 }
+
+// messageUsage follows the token counts of a message stream: message_start
+// carries the input count and message_delta the cumulative totals.
+type messageUsage struct {
+	input  int64
+	output int64
+}
+
+func (u *messageUsage) observe(event anthropic.MessageStreamEventUnion) {
+	switch v := event.AsAny().(type) {
+	case anthropic.MessageStartEvent:
+		u.input = max(u.input, v.Message.Usage.InputTokens)
+		u.output = max(u.output, v.Message.Usage.OutputTokens)
+	case anthropic.MessageDeltaEvent:
+		u.input = max(u.input, v.Usage.InputTokens)
+		u.output = max(u.output, v.Usage.OutputTokens)
+	}
+}
+
+func (u *messageUsage) report(ctx context.Context) {
+	reportUsage(ctx, u.input, u.output)
+}
@@ -0,0 +1,232 @@
+package ai
+
+import (
+	"io"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+)
+
+// DefaultTranslateBlockChars is the input budget of one block translation call
+// when none is configured.
+const DefaultTranslateBlockChars = 1500
+
+// BlockBatch is the input of block translation calls: a run of adjacent
+// blocks translated together, or a single block too big for one call.
+type BlockBatch struct {
+	// FirstIndex and LastIndex are the range of original Block.Index values covered.
+	FirstIndex int
+	LastIndex  int
+	// Parts are translated in separate calls. There is more than one only for
+	// a block split between sentences.
+	Parts []string
+	// Open and Close are the outer tags of a split block, kept out of Parts so
+	// every part is balanced HTML.
+	Open  string
+	Close string
+}
+
+// Join reassembles the batch from the translations of its parts, in order.
+// Joining the untranslated Parts gives back the original blocks.
+func (b BlockBatch) Join(parts []string) string {
+	return b.Open + strings.Join(parts, "") + b.Close
+}
+
+// PlanBlocks groups the blocks that need translation into batches of at most
+// budget characters. Adjacent blocks are merged while they fit and never cut;
+// only a block bigger than budget on its own is split, between sentences.
+// Blocks that need no translation are left out and end a merge.
+func PlanBlocks(blocks []Block, budget int) []BlockBatch {
+	if budget <= 0 {
+		budget = DefaultTranslateBlockChars
+	}
+
+	var batches []BlockBatch
+	var run strings.Builder
+	runChars, first, last := 0, 0, 0
+	flush := func() {
+		if run.Len() == 0 {
+			return
+		}
+		batches = append(batches, BlockBatch{FirstIndex: first, LastIndex: last, Parts: []string{run.String()}})
+		run.Reset()
+		runChars = 0
+	}
+
+	for _, block := range blocks {
+		if !block.NeedTranslate {
+			flush()
+			continue
+		}
+		chars := utf8.RuneCountInString(block.HTML)
+		if chars > budget {
+			flush()
+			batches = append(batches, splitBlock(block, budget))
+			continue
+		}
+		if runChars+chars > budget {
+			flush()
+		}
+		if run.Len() == 0 {
+			first = block.Index
+		}
+		run.WriteString(block.HTML)
+		runChars += chars
+		last = block.Index
+	}
+	flush()
+
+	return batches
+}
+
+// voidElements have no end tag.
+var voidElements = map[string]bool{
+	"area": true, "base": true, "br": true, "col": true, "embed": true, "hr": true, "img": true,
+	"input": true, "link": true, "meta": true, "source": true, "track": true, "wbr": true,
+}
+
+// splitBlock splits an oversized block into parts of at most budget
+// characters, cutting only between sentences of text outside any inner
+// element. A sentence bigger than budget stays whole.
+func splitBlock(block Block, budget int) BlockBatch {
+	batch := BlockBatch{FirstIndex: block.Index, LastIndex: block.Index}
+
+	var tokens []html.Token
+	var raws []string
+	z := html.NewTokenizer(strings.NewReader(block.HTML))
+	for {
+		if z.Next() == html.ErrorToken {
+			if z.Err() != io.EOF {
+				batch.Parts = []string{block.HTML}
+				return batch
+			}
+			break
+		}
+		raws = append(raws, string(z.Raw()))
+		tokens = append(tokens, z.Token())
+	}
+
+	// Peel the outer element when it encloses the whole block
+	if n := len(tokens); n >= 2 && tokens[0].Type == html.StartTagToken && tokens[n-1].Type == html.EndTagToken &&
+		tokens[0].Data == tokens[n-1].Data && closesAtEnd(tokens) {
+		batch.Open, batch.Close = raws[0], raws[n-1]
+		tokens, raws = tokens[1:n-1], raws[1:n-1]
+	}
+
+	// Pieces end where a part may be cut
+	var pieces []string
+	var piece strings.Builder
+	depth := 0
+	for i, token := range tokens {
+		switch token.Type {
+		case html.StartTagToken:
+			if !voidElements[token.Data] {
+				depth++
+			}
+		case html.EndTagToken:
+			depth--
+		}
+		if token.Type != html.TextToken || depth > 0 {
+			piece.WriteString(raws[i])
+			continue
+		}
+		sentences := splitSentences(raws[i])
+		for j, sentence := range sentences {
+			piece.WriteString(sentence)
+			if j < len(sentences)-1 || endsSentence(sentence) {
+				pieces = append(pieces, piece.String())
+				piece.Reset()
+			}
+		}
+	}
+	if piece.Len() > 0 {
+		pieces = append(pieces, piece.String())
+	}
+
+	var part strings.Builder
+	partChars := 0
+	for _, p := range pieces {
+		chars := utf8.RuneCountInString(p)
+		if part.Len() > 0 && partChars+chars > budget {
+			batch.Parts = append(batch.Parts, part.String())
+			part.Reset()
+			partChars = 0
+		}
+		part.WriteString(p)
+		partChars += chars
+	}
+	if part.Len() > 0 || len(batch.Parts) == 0 {
+		batch.Parts = append(batch.Parts, part.String())
+	}
+	return batch
+}
+
+// closesAtEnd reports whether the element opened by the first token closes
+// only at the last one.
+func closesAtEnd(tokens []html.Token) bool {
+	depth := 0
+	for i, token := range tokens {
+		switch token.Type {
+		case html.StartTagToken:
+			if !voidElements[token.Data] {
+				depth++
+			}
+		case html.EndTagToken:
+			depth--
+		}
+		if depth == 0 {
+			return i == len(tokens)-1
+		}
+	}
+	return false
+}
+
+// splitSentences splits text after each sentence end, keeping the spaces
+// that follow it with the sentence. Joining the result gives back text.
+func splitSentences(text string) []string {
+	var sentences []string
+	start := 0
+	for i := 0; i < len(text); {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		i += size
+		if !isSentenceEnd(r) {
+			continue
+		}
+		spaced := false
+		for i < len(text) {
+			next, nextSize := utf8.DecodeRuneInString(text[i:])
+			if !unicode.IsSpace(next) {
+				break
+			}
+			i += nextSize
+			spaced = true
+		}
+		// "3.5" or "example.com" end no sentence; CJK full stops need no space
+		if !spaced && r < utf8.RuneSelf && i < len(text) {
+			continue
+		}
+		sentences = append(sentences, text[start:i])
+		start = i
+	}
+	if start < len(text) {
+		sentences = append(sentences, text[start:])
+	}
+	return sentences
+}
+
+// endsSentence reports whether s ends with a sentence end and any spaces.
+func endsSentence(s string) bool {
+	s = strings.TrimRightFunc(s, unicode.IsSpace)
+	r, _ := utf8.DecodeLastRuneInString(s)
+	return isSentenceEnd(r)
+}
+
+func isSentenceEnd(r rune) bool {
+	switch r {
+	case '.', '!', '?', '。', '！', '？', '；':
+		return true
+	}
+	return false
+}
@@ -0,0 +1,101 @@
+package ai_test
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"gist/backend/internal/service/ai"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanBlocks_MergesTinyAndSplitsHuge(t *testing.T) {
+	huge := "<p>" + strings.Repeat("A fairly long sentence about nothing. ", 10) + "<a href=\"https://example.com/x.y\">Link. Text.</a> Tail.</p>"
+	blocks := []ai.Block{
+		{Index: 0, HTML: "<h1>Title</h1>", NeedTranslate: true},
+		{Index: 1, HTML: "<p>One.</p>", NeedTranslate: true},
+		{Index: 2, HTML: "<p>Two.</p>", NeedTranslate: true},
+		{Index: 3, HTML: `<img src="a.png"/>`},
+		{Index: 4, HTML: "<p>Three.</p>", NeedTranslate: true},
+		{Index: 5, HTML: huge, NeedTranslate: true},
+		{Index: 6, HTML: "<p>" + strings.Repeat("x", 60) + "</p>", NeedTranslate: true},
+		{Index: 7, HTML: "<p>" + strings.Repeat("y", 60) + "</p>", NeedTranslate: true},
+	}
+
+	batches := ai.PlanBlocks(blocks, 100)
+	require.Len(t, batches, 5)
+
+	// Merged up to the image, which is never sent
+	require.Equal(t, 0, batches[0].FirstIndex)
+	require.Equal(t, 2, batches[0].LastIndex)
+	require.Equal(t, []string{"<h1>Title</h1><p>One.</p><p>Two.</p>"}, batches[0].Parts)
+
+	require.Equal(t, 4, batches[1].FirstIndex)
+	require.Equal(t, 4, batches[1].LastIndex)
+
+	// The huge block alone, split between sentences outside the link
+	split := batches[2]
+	require.Equal(t, 5, split.FirstIndex)
+	require.Equal(t, 5, split.LastIndex)
+	require.Equal(t, "<p>", split.Open)
+	require.Equal(t, "</p>", split.Close)
+	require.Greater(t, len(split.Parts), 1)
+	for _, part := range split.Parts {
+		require.LessOrEqual(t, utf8.RuneCountInString(part), 100)
+		require.Equal(t, strings.Count(part, "<a "), strings.Count(part, "</a>"))
+	}
+	require.Equal(t, huge, split.Join(split.Parts))
+
+	// Adjacent blocks that would overflow the budget together stay apart
+	require.Equal(t, 6, batches[3].FirstIndex)
+	require.Equal(t, 7, batches[4].FirstIndex)
+}
+
+func TestPlanBlocks_IndexRoundTrip(t *testing.T) {
+	content := `<div>
+		<h2>Intro</h2>
+		<p>Short.</p>
+		<p>` + strings.Repeat("Lorem ipsum dolor sit amet. ", 80) + `</p>
+		<figure><img src="f.png" /></figure>
+		<ul><li>One</li><li>Two</li></ul>
+		<p>这是第一句。这是第二句！` + strings.Repeat("还有更多。", 400) + `</p>
+		<pre><code>x := 1</code></pre>
+		<p>End.</p>
+	</div>`
+	blocks, err := ai.ParseHTMLBlocks(content)
+	require.NoError(t, err)
+
+	for _, budget := range []int{0, 50, 1500, 100000} {
+		batches := ai.PlanBlocks(blocks, budget)
+
+		// Every block is covered once, by a batch or as-is
+		covered := make(map[int]string)
+		for _, batch := range batches {
+			require.LessOrEqual(t, batch.FirstIndex, batch.LastIndex)
+			var original strings.Builder
+			for i := batch.FirstIndex; i <= batch.LastIndex; i++ {
+				_, seen := covered[i]
+				require.False(t, seen)
+				covered[i] = ""
+				original.WriteString(blocks[i].HTML)
+				require.True(t, blocks[i].NeedTranslate)
+			}
+			require.Equal(t, original.String(), batch.Join(batch.Parts))
+			require.NotEmpty(t, batch.Parts)
+		}
+		for _, block := range blocks {
+			_, seen := covered[block.Index]
+			require.Equal(t, block.NeedTranslate, seen, "block %d", block.Index)
+		}
+	}
+}
+
+func TestPlanBlocks_KeepsOversizedSentenceWhole(t *testing.T) {
+	sentence := strings.Repeat("word ", 50)
+	blocks := []ai.Block{{Index: 0, HTML: "<p>" + sentence + "</p>", NeedTranslate: true}}
+
+	batches := ai.PlanBlocks(blocks, 20)
+	require.Len(t, batches, 1)
+	require.Equal(t, []string{sentence}, batches[0].Parts)
+}
@@ -81,8 +81,11 @@ func (p *CompatibleProvider) SummarizeStream(ctx context.Context, systemPrompt,
 		stream := p.client.Chat.Completions.NewStreaming(ctx, params)
 		defer stream.Close()
 
+		// Usage only arrives when the server sends it unasked; stream_options is
+		// left out because not every compatible server accepts it.
 		for stream.Next() {
 			chunk := stream.Current()
+			reportUsage(ctx, chunk.Usage.PromptTokens, chunk.Usage.CompletionTokens)
 			for _, choice := range chunk.Choices {
 				if choice.Delta.Content != "" {
 					select {
@@ -124,6 +127,7 @@ func (p *CompatibleProvider) Complete(ctx context.Context, systemPrompt, content
 	if err != nil {
 		return "", err
 	}
+	reportUsage(ctx, resp.Usage.PromptTokens, resp.Usage.CompletionTokens)
 
 	if len(resp.Choices) == 0 {
 		return "", nil
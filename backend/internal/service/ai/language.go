@@ -0,0 +1,60 @@
+package ai
+
+import (
+	"strings"
+	"unicode"
+)
+
+// LooksLikeLanguage is a cheap check for short text such as titles that is
+// already written in language, so it can be shown as-is instead of translated.
+// It only judges by script: Latin targets other than English can't be told
+// apart this way and always report false. Text without letters has nothing to
+// translate and reports true.
+func LooksLikeLanguage(text, language string) bool {
+	var letters, latin, nonASCIILatin, han, kana, hangul, cyrillic, arabic int
+	for _, r := range text {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		letters++
+		switch {
+		case unicode.Is(unicode.Latin, r):
+			latin++
+			if r > unicode.MaxASCII {
+				nonASCIILatin++
+			}
+		case unicode.Is(unicode.Han, r):
+			han++
+		case unicode.In(r, unicode.Hiragana, unicode.Katakana):
+			kana++
+		case unicode.Is(unicode.Hangul, r):
+			hangul++
+		case unicode.Is(unicode.Cyrillic, r):
+			cyrillic++
+		case unicode.Is(unicode.Arabic, r):
+			arabic++
+		}
+	}
+	if letters == 0 {
+		return true
+	}
+
+	// Brand names and acronyms often stay in Latin letters, so any CJK text
+	// counts, and other scripts only have to make up most of the letters
+	mostly := func(n int) bool { return n*2 > letters }
+	switch {
+	case strings.HasPrefix(language, "zh-"):
+		return han > 0 && kana == 0 && hangul == 0
+	case language == "ja":
+		return kana > 0
+	case language == "ko":
+		return hangul > 0
+	case language == "ru":
+		return mostly(cyrillic)
+	case language == "ar":
+		return mostly(arabic)
+	case strings.HasPrefix(language, "en-"):
+		return latin == letters && nonASCIILatin == 0
+	}
+	return false
+}
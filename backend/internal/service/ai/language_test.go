@@ -0,0 +1,35 @@
+package ai_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"gist/backend/internal/service/ai"
+)
+
+func TestLooksLikeLanguage(t *testing.T) {
+	tests := []struct {
+		text     string
+		language string
+		want     bool
+	}{
+		{"阮一峰的网络日志", "zh-CN", true},
+		{"少数派 sspai", "zh-CN", true},
+		{"はてなブックマーク", "zh-CN", false},
+		{"はてなブックマーク", "ja", true},
+		{"日本経済新聞", "ja", false},
+		{"ITmedia ニュース", "ja", true},
+		{"Хабр", "ru", true},
+		{"Хабр", "en-US", false},
+		{"연합뉴스", "ko", true},
+		{"Hacker News", "en-US", true},
+		{"Le Monde diplomatique", "fr", false},
+		{"Café Society", "en-GB", false},
+		{"36氪", "en-US", false},
+		{"2024 📈", "ja", true},
+	}
+	for _, tt := range tests {
+		require.Equal(t, tt.want, ai.LooksLikeLanguage(tt.text, tt.language), "%q in %s", tt.text, tt.language)
+	}
+}
@@ -115,6 +115,10 @@ func (p *OpenAIProvider) SummarizeStream(ctx context.Context, systemPrompt, cont
 					}
 				}
 			}
+			// The final response.completed event carries the token usage
+			if event.Type == "response.completed" {
+				reportUsage(ctx, event.Response.Usage.InputTokens, event.Response.Usage.OutputTokens)
+			}
 		}
 
 		if err := stream.Err(); err != nil {
@@ -149,6 +153,7 @@ func (p *OpenAIProvider) Complete(ctx context.Context, systemPrompt, content str
 	if err != nil {
 		return "", err
 	}
+	reportUsage(ctx, resp.Usage.InputTokens, resp.Usage.OutputTokens)
 
 	if len(resp.Output) == 0 {
 		return "", nil
@@ -96,3 +96,19 @@ func TestAnthropicProvider_WithBaseURL(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, ai.ProviderAnthropic, provider.Name())
 }
+
+func TestGetDigestPrompt(t *testing.T) {
+	prompt := ai.GetDigestPrompt("en-US", false)
+	require.Contains(t, prompt, "<target_language>English</target_language>")
+	require.Contains(t, prompt, "<security_critical>")
+	require.Contains(t, prompt, "No preamble")
+	require.NotContains(t, prompt, "one part of a larger set")
+
+	require.Contains(t, ai.GetDigestPrompt("en-US", true), "one part of a larger set")
+}
+
+func TestGetDigestMergePrompt(t *testing.T) {
+	prompt := ai.GetDigestMergePrompt("ja")
+	require.Contains(t, prompt, "<target_language>日本語</target_language>")
+	require.Contains(t, prompt, "Merge them into one digest")
+}
@@ -135,6 +135,57 @@ func TestAnthropicProvider_RequestOptionsAreMerged(t *testing.T) {
 	}
 }
 
+func TestProviders_ReportUsage(t *testing.T) {
+	openAIServer := newOpenAITestServer(t)
+	defer openAIServer.Close()
+	anthropicServer := newAnthropicTestServer(t)
+	defer anthropicServer.Close()
+
+	openAI, err := ai.NewOpenAIProvider("key", openAIServer.URL+"/v1/", "gpt-4o-mini", nil)
+	require.NoError(t, err)
+	compatible, err := ai.NewCompatibleProvider("key", openAIServer.URL+"/v1/", "gpt-4o-mini", nil)
+	require.NoError(t, err)
+	anthropic, err := ai.NewAnthropicProvider("key", anthropicServer.URL+"/", "claude-3-sonnet", nil)
+	require.NoError(t, err)
+
+	want := ai.Usage{PromptTokens: 11, CompletionTokens: 7}
+	for name, provider := range map[string]ai.Provider{"openai": openAI, "compatible": compatible, "anthropic": anthropic} {
+		tracker := &ai.UsageTracker{}
+		ctx, cancel := context.WithTimeout(ai.WithUsageTracker(context.Background(), tracker), time.Second)
+		_, err := provider.Complete(ctx, "sys", "content")
+		cancel()
+		require.NoError(t, err, name)
+		got, ok := tracker.Usage()
+		require.True(t, ok, name)
+		require.Equal(t, want, got, name)
+	}
+
+	for name, provider := range map[string]ai.Provider{"openai": openAI, "anthropic": anthropic} {
+		tracker := &ai.UsageTracker{}
+		ctx, cancel := context.WithTimeout(ai.WithUsageTracker(context.Background(), tracker), time.Second)
+		textCh, errCh := provider.SummarizeStream(ctx, "sys", "content")
+		for range textCh {
+		}
+		if err, ok := <-errCh; ok {
+			require.NoError(t, err, name)
+		}
+		cancel()
+		got, ok := tracker.Usage()
+		require.True(t, ok, name)
+		require.Equal(t, want, got, name)
+	}
+
+	// The compatible stream fixture sends no usage, so nothing is reported
+	tracker := &ai.UsageTracker{}
+	ctx, cancel := context.WithTimeout(ai.WithUsageTracker(context.Background(), tracker), time.Second)
+	defer cancel()
+	textCh, _ := compatible.SummarizeStream(ctx, "sys", "content")
+	for range textCh {
+	}
+	_, ok := tracker.Usage()
+	require.False(t, ok)
+}
+
 func testAndCompleteProvider(t *testing.T, provider ai.Provider, expected string) {
 	t.Helper()
 	testProvider(t, provider, expected)
@@ -282,6 +333,11 @@ func writeOpenAIChatResponse(w http.ResponseWriter) {
 				},
 			},
 		},
+		"usage": map[string]interface{}{
+			"prompt_tokens":     11,
+			"completion_tokens": 7,
+			"total_tokens":      18,
+		},
 	}
 	_ = json.NewEncoder(w).Encode(resp)
 }
@@ -328,6 +384,13 @@ func writeOpenAIResponse(w http.ResponseWriter) {
 		"tool_choice":         "auto",
 		"tools":               []interface{}{},
 		"top_p":               1,
+		"usage": map[string]interface{}{
+			"input_tokens":          11,
+			"input_tokens_details":  map[string]interface{}{"cached_tokens": 0},
+			"output_tokens":         7,
+			"output_tokens_details": map[string]interface{}{"reasoning_tokens": 0},
+			"total_tokens":          18,
+		},
 	}
 	_ = json.NewEncoder(w).Encode(resp)
 }
@@ -336,6 +399,8 @@ func writeOpenAIResponseStream(w http.ResponseWriter) {
 	w.Header().Set("Content-Type", "text/event-stream")
 	data := `{"type":"response.output_text.delta","delta":"response-stream"}`
 	_, _ = io.WriteString(w, "data: "+data+"\n\n")
+	completed := `{"type":"response.completed","response":{"id":"resp-1","object":"response","usage":{"input_tokens":11,"output_tokens":7,"total_tokens":18}}}`
+	_, _ = io.WriteString(w, "data: "+completed+"\n\n")
 	_, _ = io.WriteString(w, "data: [DONE]\n\n")
 	if flusher, ok := w.(http.Flusher); ok {
 		flusher.Flush()
@@ -370,9 +435,15 @@ func writeAnthropicMessage(w http.ResponseWriter) {
 
 func writeAnthropicStream(w http.ResponseWriter) {
 	w.Header().Set("Content-Type", "text/event-stream")
+	start := `{"type":"message_start","message":{"id":"msg-1","type":"message","role":"assistant","model":"claude-3-sonnet","content":[],"usage":{"input_tokens":11,"output_tokens":1}}}`
+	_, _ = io.WriteString(w, "event: message_start\n")
+	_, _ = io.WriteString(w, "data: "+start+"\n\n")
 	event := `{"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"claude-stream"}}`
 	_, _ = io.WriteString(w, "event: content_block_delta\n")
 	_, _ = io.WriteString(w, "data: "+event+"\n\n")
+	delta := `{"type":"message_delta","delta":{"stop_reason":"end_turn"},"usage":{"output_tokens":7}}`
+	_, _ = io.WriteString(w, "event: message_delta\n")
+	_, _ = io.WriteString(w, "data: "+delta+"\n\n")
 	if flusher, ok := w.(http.Flusher); ok {
 		flusher.Flush()
 	}
@@ -0,0 +1,82 @@
+package ai
+
+import (
+	"context"
+	"sync"
+	"unicode/utf8"
+)
+
+// Usage counts the tokens spent on one or more provider calls.
+type Usage struct {
+	PromptTokens     int
+	CompletionTokens int
+	// Estimated is set when any part of the count was guessed from text length
+	// because the provider did not report usage.
+	Estimated bool
+}
+
+// Add sums other into u.
+func (u *Usage) Add(other Usage) {
+	u.PromptTokens += other.PromptTokens
+	u.CompletionTokens += other.CompletionTokens
+	u.Estimated = u.Estimated || other.Estimated
+}
+
+// UsageTracker collects the usage providers report for calls made with a
+// context from WithUsageTracker. The zero value is ready to use and it is
+// safe for concurrent calls.
+type UsageTracker struct {
+	mu       sync.Mutex
+	usage    Usage
+	reported bool
+}
+
+// Add records usage on the tracker.
+func (t *UsageTracker) Add(usage Usage) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.usage.Add(usage)
+	t.reported = true
+}
+
+// Usage returns the summed usage and whether anything was reported.
+func (t *UsageTracker) Usage() (Usage, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.usage, t.reported
+}
+
+type usageTrackerKey struct{}
+
+// WithUsageTracker returns a context whose provider calls report usage to t.
+func WithUsageTracker(ctx context.Context, t *UsageTracker) context.Context {
+	return context.WithValue(ctx, usageTrackerKey{}, t)
+}
+
+// reportUsage hands the provider's token counts to the context's tracker, if any.
+// Zero counts mean the provider sent no usage and are ignored.
+func reportUsage(ctx context.Context, promptTokens, completionTokens int64) {
+	if promptTokens <= 0 && completionTokens <= 0 {
+		return
+	}
+	t, ok := ctx.Value(usageTrackerKey{}).(*UsageTracker)
+	if !ok || t == nil {
+		return
+	}
+	t.Add(Usage{PromptTokens: int(promptTokens), CompletionTokens: int(completionTokens)})
+}
+
+// EstimateTokens roughly counts the tokens in text for providers that don't
+// report usage: about four ASCII characters per token, and one token per
+// other rune since CJK text tokenizes close to a character each.
+func EstimateTokens(text string) int {
+	ascii, other := 0, 0
+	for _, r := range text {
+		if r < utf8.RuneSelf {
+			ascii++
+		} else {
+			other++
+		}
+	}
+	return (ascii+3)/4 + other
+}
@@ -0,0 +1,29 @@
+package ai_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"gist/backend/internal/service/ai"
+)
+
+func TestEstimateTokens(t *testing.T) {
+	require.Equal(t, 0, ai.EstimateTokens(""))
+	require.Equal(t, 3, ai.EstimateTokens("hello world!"))
+	require.Equal(t, 4, ai.EstimateTokens("你好世界"))
+	require.Equal(t, 3, ai.EstimateTokens("ab你好"))
+}
+
+func TestUsageTracker_Add(t *testing.T) {
+	var tracker ai.UsageTracker
+	_, ok := tracker.Usage()
+	require.False(t, ok)
+
+	tracker.Add(ai.Usage{PromptTokens: 3, CompletionTokens: 1})
+	tracker.Add(ai.Usage{PromptTokens: 2, CompletionTokens: 4, Estimated: true})
+
+	got, ok := tracker.Usage()
+	require.True(t, ok)
+	require.Equal(t, ai.Usage{PromptTokens: 5, CompletionTokens: 5, Estimated: true}, got)
+}
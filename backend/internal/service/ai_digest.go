@@ -0,0 +1,247 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"gist/backend/internal/model"
+	"gist/backend/internal/repository"
+	"gist/backend/internal/service/ai"
+	"gist/backend/pkg/logger"
+	"gist/backend/pkg/sanitizer"
+)
+
+// AIDigestNothingNew is the digest of a window without unread entries, given
+// without calling the provider.
+const AIDigestNothingNew = "Nothing new in this window."
+
+const (
+	// aiDigestMaxEntries caps how many of the newest unread entries a digest covers.
+	aiDigestMaxEntries = 100
+	// aiDigestExcerptRunes is how much of an entry's text stands in for a missing summary.
+	aiDigestExcerptRunes = 500
+	// aiDigestChunkTokens is the estimated input of one provider call. Entries
+	// that do not fit one call are digested in parts and the parts merged.
+	aiDigestChunkTokens = 6000
+	// aiDigestMaxPromptTokens caps the estimated input of all parts together;
+	// the oldest entries past it are left out.
+	aiDigestMaxPromptTokens = 24000
+)
+
+// AIDigestParams selects the entries of a digest.
+type AIDigestParams struct {
+	// Scope is model.AIDigestScopeFolder or model.AIDigestScopeFeed.
+	Scope   string
+	ScopeID int64
+	// Start and End bound the window [Start, End). End also keys the cache.
+	Start    time.Time
+	End      time.Time
+	Language string
+}
+
+// AIDigestStream is a digest being generated.
+type AIDigestStream struct {
+	// Entries is how many entries the digest covers. With none, the channels
+	// are nil and the digest is AIDigestNothingNew.
+	Entries int
+	Text    <-chan string
+	Errors  <-chan error
+}
+
+func (s *aiService) GetCachedDigest(ctx context.Context, params AIDigestParams) (*model.AIDigest, error) {
+	if s.digestRepo == nil {
+		return nil, nil
+	}
+	return s.digestRepo.Get(ctx, params.Scope, params.ScopeID, params.End, s.resolveLanguage(ctx, params.Language))
+}
+
+func (s *aiService) SaveDigest(ctx context.Context, params AIDigestParams, entries int, digest string) error {
+	if s.digestRepo == nil {
+		return nil
+	}
+	language := s.resolveLanguage(ctx, params.Language)
+	err := s.digestRepo.Save(ctx, model.AIDigest{
+		Scope:       params.Scope,
+		ScopeID:     params.ScopeID,
+		WindowStart: params.Start,
+		WindowEnd:   params.End,
+		Language:    language,
+		Entries:     entries,
+		Digest:      digest,
+	})
+	if err != nil {
+		logger.Warn("ai digest save failed", "module", "service", "action", "save", "resource", "ai", "result", "failed", "scope", params.Scope, "scope_id", params.ScopeID, "error", err)
+		return err
+	}
+	logger.Info("ai digest saved", "module", "service", "action", "save", "resource", "ai", "result", "ok", "scope", params.Scope, "scope_id", params.ScopeID, "language", language)
+	return nil
+}
+
+// Digest streams one digest of the unread entries in the window. Entries that
+// fit one call are digested in a single streamed call; more are digested part
+// by part and the notes streamed back merged.
+func (s *aiService) Digest(ctx context.Context, params AIDigestParams) (AIDigestStream, error) {
+	if s.digestRepo == nil {
+		return AIDigestStream{}, fmt.Errorf("ai digests not configured")
+	}
+	if !params.Start.Before(params.End) {
+		return AIDigestStream{}, ErrInvalid
+	}
+	language := s.resolveLanguage(ctx, params.Language)
+
+	entries, err := s.digestRepo.ListUnread(ctx, params.Scope, params.ScopeID, params.Start, params.End, language, aiDigestMaxEntries)
+	if err != nil {
+		return AIDigestStream{}, fmt.Errorf("list digest entries: %w", err)
+	}
+	chunks, count := digestChunks(entries)
+	if count == 0 {
+		return AIDigestStream{}, nil
+	}
+
+	cfg, err := s.getAIConfig(ctx)
+	if err != nil {
+		return AIDigestStream{}, err
+	}
+	provider, err := ai.NewProvider(cfg)
+	if err != nil {
+		logger.Warn("ai provider create failed", "module", "service", "action", "fetch", "resource", "ai", "result", "failed", "provider", cfg.Provider, "model", cfg.Model, "error", err)
+		return AIDigestStream{}, fmt.Errorf("create provider: %w", err)
+	}
+	if err := s.rateLimiter.Wait(ctx); err != nil {
+		logger.Warn("ai rate limit wait failed", "module", "service", "action", "fetch", "resource", "ai", "result", "failed", "error", err)
+		return AIDigestStream{}, fmt.Errorf("%w: %w", ErrAIRateLimited, err)
+	}
+
+	logger.Info("ai digest stream started", "module", "service", "action", "fetch", "resource", "ai", "result", "ok", "scope", params.Scope, "scope_id", params.ScopeID, "entries", count, "parts", len(chunks), "provider", cfg.Provider, "model", cfg.Model)
+
+	if len(chunks) == 1 {
+		systemPrompt := ai.GetDigestPrompt(language, false)
+		wrapped := ai.WrapInput(chunks[0])
+		tracker := &ai.UsageTracker{}
+		textCh, errCh := provider.SummarizeStream(ai.WithUsageTracker(ctx, tracker), systemPrompt, wrapped)
+		textCh = teeStream(ctx, textCh, func(output string) {
+			s.recordUsage(cfg, model.AIUsageDigest, 0, callUsage(tracker, systemPrompt+wrapped, output))
+		})
+		return AIDigestStream{Entries: count, Text: textCh, Errors: errCh}, nil
+	}
+
+	textCh := make(chan string)
+	errCh := make(chan error, 1)
+	go func() {
+		defer close(textCh)
+		defer close(errCh)
+
+		var spent ai.UsageTracker
+		defer func() {
+			if usage, ok := spent.Usage(); ok {
+				s.recordUsage(cfg, model.AIUsageDigest, 0, usage)
+			}
+		}()
+
+		partPrompt := ai.GetDigestPrompt(language, true)
+		notes := make([]string, 0, len(chunks))
+		for i, chunk := range chunks {
+			// The first call already waited before the stream started
+			if i > 0 {
+				if err := s.rateLimiter.Wait(ctx); err != nil {
+					errCh <- fmt.Errorf("%w: %w", ErrAIRateLimited, err)
+					return
+				}
+			}
+			wrapped := ai.WrapInput(chunk)
+			tracker := &ai.UsageTracker{}
+			note, err := provider.Complete(ai.WithUsageTracker(ctx, tracker), partPrompt, wrapped)
+			addUsage(&spent, callUsage(tracker, partPrompt+wrapped, note))
+			if err != nil {
+				errCh <- fmt.Errorf("digest part %d: %w", i+1, err)
+				return
+			}
+			notes = append(notes, fmt.Sprintf("Part %d:\n%s", i+1, strings.TrimSpace(note)))
+		}
+
+		if err := s.rateLimiter.Wait(ctx); err != nil {
+			errCh <- fmt.Errorf("%w: %w", ErrAIRateLimited, err)
+			return
+		}
+		mergePrompt := ai.GetDigestMergePrompt(language)
+		wrapped := ai.WrapInput(strings.Join(notes, "\n\n"))
+		tracker := &ai.UsageTracker{}
+		mergeText, mergeErr := provider.SummarizeStream(ai.WithUsageTracker(ctx, tracker), mergePrompt, wrapped)
+
+		var output strings.Builder
+		for chunk := range mergeText {
+			output.WriteString(chunk)
+			select {
+			case textCh <- chunk:
+			case <-ctx.Done():
+				for range mergeText {
+				}
+			}
+		}
+		addUsage(&spent, callUsage(tracker, mergePrompt+wrapped, output.String()))
+		if err := <-mergeErr; err != nil {
+			errCh <- err
+		}
+	}()
+
+	return AIDigestStream{Entries: count, Text: textCh, Errors: errCh}, nil
+}
+
+// digestChunks lays out entries, newest first, as the text of one or more
+// provider calls of at most aiDigestChunkTokens each, stopping before
+// aiDigestMaxPromptTokens. It returns the chunks and how many entries they hold.
+func digestChunks(entries []repository.AIDigestEntry) ([]string, int) {
+	var chunks []string
+	var chunk strings.Builder
+	chunkTokens, totalTokens, count := 0, 0, 0
+
+	for _, entry := range entries {
+		text := digestEntryText(entry)
+		tokens := ai.EstimateTokens(text)
+		if totalTokens+tokens > aiDigestMaxPromptTokens {
+			break
+		}
+		if chunk.Len() > 0 && chunkTokens+tokens > aiDigestChunkTokens {
+			chunks = append(chunks, chunk.String())
+			chunk.Reset()
+			chunkTokens = 0
+		}
+		if chunk.Len() > 0 {
+			chunk.WriteString("\n\n")
+		}
+		chunk.WriteString(text)
+		chunkTokens += tokens
+		totalTokens += tokens
+		count++
+	}
+	if chunk.Len() > 0 {
+		chunks = append(chunks, chunk.String())
+	}
+	return chunks, count
+}
+
+// digestEntryText is one entry as the provider sees it: its feed, title and
+// date, then its cached summary or else the start of its text.
+func digestEntryText(entry repository.AIDigestEntry) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "[%s] %s", entry.FeedTitle, strings.TrimSpace(entry.Title))
+	if entry.PublishedAt != nil {
+		fmt.Fprintf(&b, " (%s)", entry.PublishedAt.UTC().Format("2006-01-02"))
+	}
+
+	body := strings.TrimSpace(entry.Summary)
+	if body == "" {
+		body = strings.TrimSpace(sanitizer.StripTags(entry.Content))
+		if utf8.RuneCountInString(body) > aiDigestExcerptRunes {
+			body = string([]rune(body)[:aiDigestExcerptRunes])
+		}
+	}
+	if body != "" {
+		b.WriteString("\n")
+		b.WriteString(body)
+	}
+	return b.String()
+}
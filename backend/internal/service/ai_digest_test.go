@@ -0,0 +1,170 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"gist/backend/internal/model"
+	"gist/backend/internal/repository"
+	"gist/backend/internal/repository/testutil"
+	"gist/backend/internal/service"
+	"gist/backend/internal/service/ai"
+)
+
+// newDigestChatServer answers chat completions with text, streamed when asked,
+// counting the plain and streamed calls.
+func newDigestChatServer(t *testing.T, text string, completions, streams *atomic.Int32) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		var payload struct {
+			Stream bool `json:"stream"`
+		}
+		require.NoError(t, json.Unmarshal(body, &payload))
+
+		if payload.Stream {
+			streams.Add(1)
+			w.Header().Set("Content-Type", "text/event-stream")
+			chunk, _ := json.Marshal(map[string]any{
+				"id": "chatcmpl-1", "object": "chat.completion.chunk", "created": 1, "model": "gpt-4o-mini",
+				"choices": []any{map[string]any{"index": 0, "finish_reason": "stop", "delta": map[string]any{"content": text}}},
+			})
+			_, _ = fmt.Fprintf(w, "data: %s\n\ndata: [DONE]\n\n", chunk)
+			return
+		}
+
+		completions.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"id": "chatcmpl-1", "object": "chat.completion", "created": 1, "model": "gpt-4o-mini",
+			"choices": []any{map[string]any{"index": 0, "finish_reason": "stop", "message": map[string]any{"role": "assistant", "content": "notes"}}},
+		})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func digestWindow(feedID int64) service.AIDigestParams {
+	end := time.Now().UTC().Truncate(time.Minute).Add(time.Minute)
+	return service.AIDigestParams{Scope: model.AIDigestScopeFeed, ScopeID: feedID, Start: end.Add(-24 * time.Hour), End: end, Language: "en-US"}
+}
+
+func drainDigest(t *testing.T, stream service.AIDigestStream) string {
+	t.Helper()
+	var text strings.Builder
+	for chunk := range stream.Text {
+		text.WriteString(chunk)
+	}
+	require.NoError(t, <-stream.Errors)
+	return text.String()
+}
+
+func TestAIService_Digest_NothingNewSkipsProvider(t *testing.T) {
+	// No AI settings: any provider call would fail as not configured
+	db := testutil.NewTestDB(t)
+	repo := newSettingsRepoStub()
+	svc := service.NewAIServiceWithFeedContext(&summaryRepoStub{}, &translationRepoStub{}, &listTranslationRepoStub{}, repo, ai.NewRateLimiter(100), nil, nil, nil, nil, repository.NewAIDigestRepository(db))
+	feedID := testutil.SeedFeed(t, db, model.Feed{Title: "F", URL: "u"})
+	published := time.Now().Add(-time.Hour)
+	testutil.SeedEntry(t, db, model.Entry{FeedID: feedID, Title: stringPtr("Read"), PublishedAt: &published, Read: true})
+
+	stream, err := svc.Digest(context.Background(), digestWindow(feedID))
+	require.NoError(t, err)
+	require.Zero(t, stream.Entries)
+	require.Nil(t, stream.Text)
+}
+
+func TestAIService_Digest_SingleCall(t *testing.T) {
+	var completions, streams atomic.Int32
+	server := newDigestChatServer(t, "The digest.", &completions, &streams)
+	db := testutil.NewTestDB(t)
+	repo := newSettingsRepoStub()
+	repo.data[service.KeyAIProvider] = ai.ProviderCompatible
+	repo.data[service.KeyAIAPIKey] = "test-key"
+	repo.data[service.KeyAIBaseURL] = server.URL + "/v1/"
+	repo.data[service.KeyAIModel] = "gpt-4o-mini"
+	svc := service.NewAIServiceWithFeedContext(&summaryRepoStub{}, &translationRepoStub{}, &listTranslationRepoStub{}, repo, ai.NewRateLimiter(100), nil, nil, nil, nil, repository.NewAIDigestRepository(db))
+	feedID := testutil.SeedFeed(t, db, model.Feed{Title: "F", URL: "u"})
+	published := time.Now().Add(-time.Hour)
+	testutil.SeedEntry(t, db, model.Entry{FeedID: feedID, Title: stringPtr("One"), Content: stringPtr("<p>Body</p>"), PublishedAt: &published})
+	testutil.SeedEntry(t, db, model.Entry{FeedID: feedID, Title: stringPtr("Two"), PublishedAt: &published})
+
+	stream, err := svc.Digest(context.Background(), digestWindow(feedID))
+	require.NoError(t, err)
+	require.Equal(t, 2, stream.Entries)
+	require.Equal(t, "The digest.", drainDigest(t, stream))
+	require.Zero(t, completions.Load())
+	require.EqualValues(t, 1, streams.Load())
+}
+
+func TestAIService_Digest_MergesPartsWithinTokenCap(t *testing.T) {
+	var completions, streams atomic.Int32
+	server := newDigestChatServer(t, "Merged.", &completions, &streams)
+	db := testutil.NewTestDB(t)
+	repo := newSettingsRepoStub()
+	repo.data[service.KeyAIProvider] = ai.ProviderCompatible
+	repo.data[service.KeyAIAPIKey] = "test-key"
+	repo.data[service.KeyAIBaseURL] = server.URL + "/v1/"
+	repo.data[service.KeyAIModel] = "gpt-4o-mini"
+	svc := service.NewAIServiceWithFeedContext(&summaryRepoStub{}, &translationRepoStub{}, &listTranslationRepoStub{}, repo, ai.NewRateLimiter(100), nil, nil, nil, nil, repository.NewAIDigestRepository(db))
+	feedID := testutil.SeedFeed(t, db, model.Feed{Title: "F", URL: "u"})
+
+	// CJK text estimates at a token per rune, so each excerpt is about 500 tokens
+	content := strings.Repeat("新", 800)
+	for i := 0; i < 60; i++ {
+		published := time.Now().Add(-time.Duration(i+1) * time.Minute)
+		testutil.SeedEntry(t, db, model.Entry{FeedID: feedID, Title: stringPtr(fmt.Sprintf("Entry %d", i)), Content: &content, PublishedAt: &published})
+	}
+
+	stream, err := svc.Digest(context.Background(), digestWindow(feedID))
+	require.NoError(t, err)
+	require.Less(t, stream.Entries, 60)
+	require.Greater(t, stream.Entries, 40)
+	require.Equal(t, "Merged.", drainDigest(t, stream))
+	require.Greater(t, completions.Load(), int32(1))
+	require.EqualValues(t, 1, streams.Load())
+}
+
+func TestAIService_Digest_CacheRoundTrip(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	repo := newSettingsRepoStub()
+	svc := service.NewAIServiceWithFeedContext(&summaryRepoStub{}, &translationRepoStub{}, &listTranslationRepoStub{}, repo, ai.NewRateLimiter(100), nil, nil, nil, nil, repository.NewAIDigestRepository(db))
+	ctx := context.Background()
+	params := digestWindow(1)
+
+	cached, err := svc.GetCachedDigest(ctx, params)
+	require.NoError(t, err)
+	require.Nil(t, cached)
+
+	require.NoError(t, svc.SaveDigest(ctx, params, 3, "digest"))
+	cached, err = svc.GetCachedDigest(ctx, params)
+	require.NoError(t, err)
+	require.NotNil(t, cached)
+	require.Equal(t, "digest", cached.Digest)
+	require.Equal(t, 3, cached.Entries)
+
+	// Another language or window end is another digest
+	other := params
+	other.Language = "zh-CN"
+	cached, err = svc.GetCachedDigest(ctx, other)
+	require.NoError(t, err)
+	require.Nil(t, cached)
+
+	summaries, _, _, err := svc.ClearAllCache(ctx)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, summaries)
+	cached, err = svc.GetCachedDigest(ctx, params)
+	require.NoError(t, err)
+	require.Nil(t, cached)
+}
@@ -6,10 +6,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
+	"time"
 
+	"gist/backend/internal/hashutil"
 	"gist/backend/internal/model"
 	"gist/backend/internal/repository"
 	"gist/backend/internal/service/ai"
@@ -18,8 +21,11 @@ import (
 
 // TranslateBlockResult represents a translated block result.
 type TranslateBlockResult struct {
-	Index int    `json:"index"`
-	HTML  string `json:"html"`
+	Index int `json:"index"`
+	// EndIndex is the last original block covered; blocks merged into one
+	// translation call come back as one result for the whole range.
+	EndIndex int    `json:"endIndex"`
+	HTML     string `json:"html"`
 }
 
 // TranslateBlockInfo represents original block info.
@@ -44,31 +50,89 @@ type BatchTranslateResult struct {
 	Cached  bool    `json:"cached,omitempty"`
 }
 
+// FeedTitleResult is the sidebar title of one feed in a language.
+type FeedTitleResult struct {
+	FeedID int64
+	Title  string
+	Cached bool
+	// Unchanged is set when the title already looked like the target language
+	// and is returned as-is without calling the provider.
+	Unchanged bool
+}
+
+// AIUsageParams selects the days of a usage report.
+type AIUsageParams struct {
+	// From and To are inclusive YYYY-MM-DD days in UTC. Empty To means today,
+	// empty From the 30 days ending at To.
+	From string
+	To   string
+}
+
+// AIUsageDay totals one operation's usage on one UTC day.
+type AIUsageDay struct {
+	Date             string
+	Operation        string
+	Calls            int
+	PromptTokens     int
+	CompletionTokens int
+	// Cost is in USD from the configured price table; unpriced models add nothing.
+	Cost float64
+}
+
+// AIUsageReport is the AI usage of a range of days.
+type AIUsageReport struct {
+	From             string
+	To               string
+	Days             []AIUsageDay
+	PromptTokens     int
+	CompletionTokens int
+	Cost             float64
+	// UnpricedModels lists models with usage in the range but no configured price.
+	UnpricedModels []string
+}
+
 // AIService provides AI-related operations like summarization and translation.
 type AIService interface {
 	// GetCachedSummary returns a cached summary if available.
-	GetCachedSummary(ctx context.Context, entryID int64, isReadability bool) (*model.AISummary, error)
+	// An empty language means the configured summary language, here and below.
+	GetCachedSummary(ctx context.Context, entryID int64, isReadability bool, language string) (*model.AISummary, error)
 	// Summarize generates a summary using AI streaming.
 	// Returns channels for text chunks and errors.
-	Summarize(ctx context.Context, entryID int64, content, title string, isReadability bool) (<-chan string, <-chan error, error)
+	Summarize(ctx context.Context, entryID int64, content, title string, isReadability bool, language string) (<-chan string, <-chan error, error)
 	// SaveSummary saves a summary to cache.
-	SaveSummary(ctx context.Context, entryID int64, isReadability bool, summary string) error
+	SaveSummary(ctx context.Context, entryID int64, isReadability bool, language, summary string) error
 	// GetSummaryLanguage returns the configured summary language.
 	GetSummaryLanguage(ctx context.Context) string
 
 	// GetCachedTranslation returns a cached translation if available.
-	GetCachedTranslation(ctx context.Context, entryID int64, isReadability bool) (*model.AITranslation, error)
+	GetCachedTranslation(ctx context.Context, entryID int64, isReadability bool, language string) (*model.AITranslation, error)
 	// TranslateBlocks parses HTML into blocks and translates them in parallel.
 	// Returns block info, a channel of results (in completion order), and an error channel.
-	TranslateBlocks(ctx context.Context, entryID int64, content, title string, isReadability bool) ([]TranslateBlockInfo, <-chan TranslateBlockResult, <-chan error, error)
+	TranslateBlocks(ctx context.Context, entryID int64, content, title string, isReadability bool, language string) ([]TranslateBlockInfo, <-chan TranslateBlockResult, <-chan error, error)
 	// SaveTranslation saves a translation to cache.
-	SaveTranslation(ctx context.Context, entryID int64, isReadability bool, content string) error
+	SaveTranslation(ctx context.Context, entryID int64, isReadability bool, language, content string) error
 	// TranslateBatch translates multiple articles' titles and summaries.
 	// Returns a channel of results and an error channel.
-	TranslateBatch(ctx context.Context, articles []BatchArticleInput) (<-chan BatchTranslateResult, <-chan error, error)
+	TranslateBatch(ctx context.Context, articles []BatchArticleInput, language string) (<-chan BatchTranslateResult, <-chan error, error)
+	// TranslateFeedTitles translates the titles of the given feeds, reusing cached
+	// translations of unchanged titles. Feeds that fail to translate are left out.
+	TranslateFeedTitles(ctx context.Context, feedIDs []int64, language string) ([]FeedTitleResult, error)
+	// GetCachedFeedTitles returns cached translations of the feeds' current titles by feed ID.
+	GetCachedFeedTitles(ctx context.Context, feeds []model.Feed, language string) (map[int64]string, error)
+	// GetCachedDigest returns the cached digest of the window if available.
+	GetCachedDigest(ctx context.Context, params AIDigestParams) (*model.AIDigest, error)
+	// Digest generates a digest of the unread entries in the window using AI streaming.
+	// A window without unread entries returns no channels and never calls the provider.
+	Digest(ctx context.Context, params AIDigestParams) (AIDigestStream, error)
+	// SaveDigest saves the digest of the window, covering entries entries, to cache.
+	SaveDigest(ctx context.Context, params AIDigestParams, entries int, digest string) error
 	// ClearAllCache deletes all AI cache data (summaries, translations, list translations).
-	// Returns the number of deleted records for each type.
+	// Returns the number of deleted records for each type; feed title translations
+	// count as list translations and digests as summaries.
 	ClearAllCache(ctx context.Context) (summaries, translations, listTranslations int64, err error)
+	// GetUsage totals recorded token usage per day and operation with estimated cost.
+	// Malformed or reversed dates return ErrInvalid.
+	GetUsage(ctx context.Context, params AIUsageParams) (*AIUsageReport, error)
 }
 
 type aiService struct {
@@ -78,7 +142,12 @@ type aiService struct {
 	settingsRepo        repository.SettingsRepository
 	entryRepo           repository.EntryRepository
 	feedRepo            repository.FeedRepository
+	usageRepo           repository.AIUsageRepository
+	feedTitleRepo       repository.FeedTitleTranslationRepository
+	digestRepo          repository.AIDigestRepository
 	rateLimiter         *ai.RateLimiter
+	// lastUsagePrune is when old usage rows were last swept, in Unix nanoseconds.
+	lastUsagePrune atomic.Int64
 }
 
 // NewAIService creates a new AI service.
@@ -89,9 +158,13 @@ func NewAIService(
 	settingsRepo repository.SettingsRepository,
 	rateLimiter *ai.RateLimiter,
 ) AIService {
-	return NewAIServiceWithFeedContext(summaryRepo, translationRepo, listTranslationRepo, settingsRepo, rateLimiter, nil, nil)
+	return NewAIServiceWithFeedContext(summaryRepo, translationRepo, listTranslationRepo, settingsRepo, rateLimiter, nil, nil, nil, nil, nil)
 }
 
+// NewAIServiceWithFeedContext also gives prompts the entry and feed they are
+// about. usageRepo records the token usage of every AI operation,
+// feedTitleRepo caches feed title translations and digestRepo caches digests;
+// each may be nil.
 func NewAIServiceWithFeedContext(
 	summaryRepo repository.AISummaryRepository,
 	translationRepo repository.AITranslationRepository,
@@ -100,6 +173,9 @@ func NewAIServiceWithFeedContext(
 	rateLimiter *ai.RateLimiter,
 	entryRepo repository.EntryRepository,
 	feedRepo repository.FeedRepository,
+	usageRepo repository.AIUsageRepository,
+	feedTitleRepo repository.FeedTitleTranslationRepository,
+	digestRepo repository.AIDigestRepository,
 ) AIService {
 	return &aiService{
 		summaryRepo:         summaryRepo,
@@ -108,16 +184,19 @@ func NewAIServiceWithFeedContext(
 		settingsRepo:        settingsRepo,
 		entryRepo:           entryRepo,
 		feedRepo:            feedRepo,
+		usageRepo:           usageRepo,
+		feedTitleRepo:       feedTitleRepo,
+		digestRepo:          digestRepo,
 		rateLimiter:         rateLimiter,
 	}
 }
 
-func (s *aiService) GetCachedSummary(ctx context.Context, entryID int64, isReadability bool) (*model.AISummary, error) {
-	language := s.GetSummaryLanguage(ctx)
+func (s *aiService) GetCachedSummary(ctx context.Context, entryID int64, isReadability bool, language string) (*model.AISummary, error) {
+	language = s.resolveLanguage(ctx, language)
 	return s.summaryRepo.Get(ctx, entryID, isReadability, language)
 }
 
-func (s *aiService) Summarize(ctx context.Context, entryID int64, content, title string, isReadability bool) (<-chan string, <-chan error, error) {
+func (s *aiService) Summarize(ctx context.Context, entryID int64, content, title string, isReadability bool, language string) (<-chan string, <-chan error, error) {
 	// Get AI configuration
 	cfg, err := s.getAIConfig(ctx)
 	if err != nil {
@@ -134,12 +213,11 @@ func (s *aiService) Summarize(ctx context.Context, entryID int64, content, title
 	// Wait for rate limiter
 	if err := s.rateLimiter.Wait(ctx); err != nil {
 		logger.Warn("ai rate limit wait failed", "module", "service", "action", "fetch", "resource", "ai", "result", "failed", "error", err)
-		return nil, nil, fmt.Errorf("rate limit: %w", err)
+		return nil, nil, fmt.Errorf("%w: %w", ErrAIRateLimited, err)
 	}
 
-	// Get language setting
 	// Build system prompt
-	systemPrompt := s.buildSummarizeSystemPrompt(ctx, entryID, title)
+	systemPrompt := s.buildSummarizeSystemPrompt(ctx, entryID, title, s.resolveLanguage(ctx, language))
 
 	// Convert HTML to plain text to save tokens
 	plainText := ai.HTMLToText(content)
@@ -148,14 +226,41 @@ func (s *aiService) Summarize(ctx context.Context, entryID int64, content, title
 	wrappedInput := ai.WrapInput(plainText)
 
 	// Start streaming
-	textCh, errCh := provider.SummarizeStream(ctx, systemPrompt, wrappedInput)
+	tracker := &ai.UsageTracker{}
+	textCh, errCh := provider.SummarizeStream(ai.WithUsageTracker(ctx, tracker), systemPrompt, wrappedInput)
 	logger.Info("ai summarize stream started", "module", "service", "action", "fetch", "resource", "ai", "result", "ok", "entry_id", entryID, "provider", cfg.Provider, "model", cfg.Model)
 
+	textCh = teeStream(ctx, textCh, func(output string) {
+		s.recordUsage(cfg, model.AIUsageSummarize, entryID, callUsage(tracker, systemPrompt+wrappedInput, output))
+	})
 	return textCh, errCh, nil
 }
 
-func (s *aiService) buildSummarizeSystemPrompt(ctx context.Context, entryID int64, title string) string {
-	return ai.GetSummarizePrompt(title, s.GetSummaryLanguage(ctx), s.getSummaryPromptReminder(ctx, entryID))
+// teeStream forwards in and calls done with the whole text once in is closed.
+func teeStream(ctx context.Context, in <-chan string, done func(text string)) <-chan string {
+	out := make(chan string)
+	go func() {
+		defer close(out)
+		var text strings.Builder
+		defer func() { done(text.String()) }()
+
+		for chunk := range in {
+			text.WriteString(chunk)
+			select {
+			case out <- chunk:
+			case <-ctx.Done():
+				// The provider sees the same cancellation and closes in
+				for range in {
+				}
+				return
+			}
+		}
+	}()
+	return out
+}
+
+func (s *aiService) buildSummarizeSystemPrompt(ctx context.Context, entryID int64, title, language string) string {
+	return ai.GetSummarizePrompt(title, language, s.getSummaryPromptReminder(ctx, entryID))
 }
 
 func (s *aiService) getSummaryPromptReminder(ctx context.Context, entryID int64) string {
@@ -182,13 +287,13 @@ func (s *aiService) getSummaryPromptReminder(ctx context.Context, entryID int64)
 	return strings.TrimSpace(*feed.SummaryPromptReminder)
 }
 
-func (s *aiService) SaveSummary(ctx context.Context, entryID int64, isReadability bool, summary string) error {
-	language := s.GetSummaryLanguage(ctx)
+func (s *aiService) SaveSummary(ctx context.Context, entryID int64, isReadability bool, language, summary string) error {
+	language = s.resolveLanguage(ctx, language)
 	if err := s.summaryRepo.Save(ctx, entryID, isReadability, language, summary); err != nil {
 		logger.Warn("ai summary save failed", "module", "service", "action", "save", "resource", "ai", "result", "failed", "entry_id", entryID, "error", err)
 		return err
 	}
-	logger.Info("ai summary saved", "module", "service", "action", "save", "resource", "ai", "result", "ok", "entry_id", entryID, "readability", isReadability)
+	logger.Info("ai summary saved", "module", "service", "action", "save", "resource", "ai", "result", "ok", "entry_id", entryID, "readability", isReadability, "language", language)
 	return nil
 }
 
@@ -200,6 +305,19 @@ func (s *aiService) GetSummaryLanguage(ctx context.Context) string {
 	return setting.Value
 }
 
+// IsSupportedLanguage reports whether code can be requested as an AI output language.
+func IsSupportedLanguage(code string) bool {
+	return ai.IsSupportedLanguage(code)
+}
+
+// resolveLanguage falls back to the configured summary language when no override is given.
+func (s *aiService) resolveLanguage(ctx context.Context, language string) string {
+	if language != "" {
+		return language
+	}
+	return s.GetSummaryLanguage(ctx)
+}
+
 func (s *aiService) getAIConfig(ctx context.Context) (ai.Config, error) {
 	var cfg ai.Config
 
@@ -224,7 +342,7 @@ func (s *aiService) getAIConfig(ctx context.Context) (ai.Config, error) {
 	// Get API key
 	cfg.APIKey = settingsMap["ai.api_key"]
 	if cfg.APIKey == "" {
-		return cfg, fmt.Errorf("AI API key is not configured")
+		return cfg, fmt.Errorf("%w: API key is missing", ErrAINotConfigured)
 	}
 
 	// Get base URL
@@ -233,7 +351,7 @@ func (s *aiService) getAIConfig(ctx context.Context) (ai.Config, error) {
 	// Get model
 	cfg.Model = settingsMap["ai.model"]
 	if cfg.Model == "" {
-		return cfg, fmt.Errorf("AI model is not configured")
+		return cfg, fmt.Errorf("%w: model is missing", ErrAINotConfigured)
 	}
 
 	if val := settingsMap["ai.request_options"]; val != "" {
@@ -247,8 +365,8 @@ func (s *aiService) getAIConfig(ctx context.Context) (ai.Config, error) {
 	return cfg, nil
 }
 
-func (s *aiService) GetCachedTranslation(ctx context.Context, entryID int64, isReadability bool) (*model.AITranslation, error) {
-	language := s.GetSummaryLanguage(ctx)
+func (s *aiService) GetCachedTranslation(ctx context.Context, entryID int64, isReadability bool, language string) (*model.AITranslation, error) {
+	language = s.resolveLanguage(ctx, language)
 	translation, err := s.translationRepo.Get(ctx, entryID, isReadability, language)
 	if err != nil {
 		logger.Warn("ai translation cache lookup failed", "module", "service", "action", "fetch", "resource", "ai", "result", "failed", "entry_id", entryID, "error", err)
@@ -257,19 +375,20 @@ func (s *aiService) GetCachedTranslation(ctx context.Context, entryID int64, isR
 	return translation, nil
 }
 
-func (s *aiService) SaveTranslation(ctx context.Context, entryID int64, isReadability bool, content string) error {
-	language := s.GetSummaryLanguage(ctx)
+func (s *aiService) SaveTranslation(ctx context.Context, entryID int64, isReadability bool, language, content string) error {
+	language = s.resolveLanguage(ctx, language)
 	if err := s.translationRepo.Save(ctx, entryID, isReadability, language, content); err != nil {
 		logger.Warn("ai translation save failed", "module", "service", "action", "save", "resource", "ai", "result", "failed", "entry_id", entryID, "error", err)
 		return err
 	}
-	logger.Info("ai translation saved", "module", "service", "action", "save", "resource", "ai", "result", "ok", "entry_id", entryID, "readability", isReadability)
+	logger.Info("ai translation saved", "module", "service", "action", "save", "resource", "ai", "result", "ok", "entry_id", entryID, "readability", isReadability, "language", language)
 	return nil
 }
 
-// TranslateBlocks parses HTML into blocks and translates them in parallel.
+// TranslateBlocks parses HTML into blocks and translates them in parallel,
+// merging small adjacent blocks into one call; see ai.PlanBlocks.
 // Returns block info, a channel of results, an error channel, and any initial error.
-func (s *aiService) TranslateBlocks(ctx context.Context, entryID int64, content, title string, isReadability bool) ([]TranslateBlockInfo, <-chan TranslateBlockResult, <-chan error, error) {
+func (s *aiService) TranslateBlocks(ctx context.Context, entryID int64, content, title string, isReadability bool, language string) ([]TranslateBlockInfo, <-chan TranslateBlockResult, <-chan error, error) {
 	// Parse HTML into blocks
 	blocks, err := ai.ParseHTMLBlocks(content)
 	if err != nil {
@@ -300,11 +419,13 @@ func (s *aiService) TranslateBlocks(ctx context.Context, entryID int64, content,
 	}
 
 	// Get language setting
-	language := s.GetSummaryLanguage(ctx)
+	language = s.resolveLanguage(ctx, language)
+
+	batches := ai.PlanBlocks(blocks, s.translateBlockChars(ctx))
 
 	// Create channels
 	resultCh := make(chan TranslateBlockResult)
-	errCh := make(chan error, len(blocks))
+	errCh := make(chan error, len(batches))
 
 	// Start parallel translation
 	go func() {
@@ -318,24 +439,25 @@ func (s *aiService) TranslateBlocks(ctx context.Context, entryID int64, content,
 		var results []TranslateBlockResult
 		var resultsMu sync.Mutex
 		var hasError atomic.Bool
+		var spent ai.UsageTracker
 
-	blockLoop:
+		// Blocks without translation are cached as-is; don't send them via
+		// channel - frontend already has original content
 		for _, block := range blocks {
-			// Check if context is cancelled before processing each block
-			if ctx.Err() != nil {
-				break
-			}
-
 			if !block.NeedTranslate {
-				// No translation needed, add to results for caching
-				resultsMu.Lock()
 				results = append(results, TranslateBlockResult{
-					Index: block.Index,
-					HTML:  block.HTML,
+					Index:    block.Index,
+					EndIndex: block.Index,
+					HTML:     block.HTML,
 				})
-				resultsMu.Unlock()
-				// Don't send via channel - frontend already has original content
-				continue
+			}
+		}
+
+	batchLoop:
+		for _, batch := range batches {
+			// Check if context is cancelled before processing each batch
+			if ctx.Err() != nil {
+				break
 			}
 
 			wg.Add(1)
@@ -345,23 +467,13 @@ func (s *aiService) TranslateBlocks(ctx context.Context, entryID int64, content,
 			case sem <- struct{}{}:
 			case <-ctx.Done():
 				wg.Done()
-				break blockLoop
+				break batchLoop
 			}
 
-			go func(b ai.Block) {
+			go func(b ai.BlockBatch) {
 				defer wg.Done()
 				defer func() { <-sem }() // Release semaphore
 
-				// Wait for rate limiter
-				if err := s.rateLimiter.Wait(ctx); err != nil {
-					select {
-					case errCh <- fmt.Errorf("rate limit: %w", err):
-						hasError.Store(true)
-					default:
-					}
-					return
-				}
-
 				// Create provider for this goroutine
 				provider, err := ai.NewProvider(cfg)
 				if err != nil {
@@ -372,32 +484,48 @@ func (s *aiService) TranslateBlocks(ctx context.Context, entryID int64, content,
 					}
 					return
 				}
+				systemPrompt := ai.GetTranslateBlockPrompt(title, language)
 
-				// Replace media elements with placeholders to prevent AI from modifying them
-				htmlForTranslation, mediaElements := ai.ReplaceMediaWithPlaceholders(b.HTML)
+				translated := make([]string, len(b.Parts))
+				for i, part := range b.Parts {
+					// Wait for rate limiter
+					if err := s.rateLimiter.Wait(ctx); err != nil {
+						select {
+						case errCh <- fmt.Errorf("rate limit: %w", err):
+							hasError.Store(true)
+						default:
+						}
+						return
+					}
 
-				// Wrap input with <input> tags
-				wrappedInput := ai.WrapInput(htmlForTranslation)
+					// Replace media elements with placeholders to prevent AI from modifying them
+					htmlForTranslation, mediaElements := ai.ReplaceMediaWithPlaceholders(part)
 
-				// Translate single block using non-streaming Complete
-				systemPrompt := ai.GetTranslateBlockPrompt(title, language)
-				translatedHTML, err := provider.Complete(ctx, systemPrompt, wrappedInput)
-				if err != nil {
-					select {
-					case errCh <- fmt.Errorf("translate block %d: %w", b.Index, err):
-						hasError.Store(true)
-					default:
+					// Wrap input with <input> tags
+					wrappedInput := ai.WrapInput(htmlForTranslation)
+
+					// Translate using non-streaming Complete
+					tracker := &ai.UsageTracker{}
+					translatedHTML, err := provider.Complete(ai.WithUsageTracker(ctx, tracker), systemPrompt, wrappedInput)
+					addUsage(&spent, callUsage(tracker, systemPrompt+wrappedInput, translatedHTML))
+					if err != nil {
+						select {
+						case errCh <- fmt.Errorf("translate block %d: %w", b.FirstIndex, err):
+							hasError.Store(true)
+						default:
+						}
+						return
 					}
-					return
-				}
 
-				// Restore media elements from placeholders
-				translatedHTML = ai.RestoreMediaFromPlaceholders(translatedHTML, mediaElements)
+					// Restore media elements from placeholders
+					translated[i] = ai.RestoreMediaFromPlaceholders(translatedHTML, mediaElements)
+				}
 
 				// Send result
 				result := TranslateBlockResult{
-					Index: b.Index,
-					HTML:  translatedHTML,
+					Index:    b.FirstIndex,
+					EndIndex: b.LastIndex,
+					HTML:     b.Join(translated),
 				}
 				resultsMu.Lock()
 				results = append(results, result)
@@ -408,11 +536,15 @@ func (s *aiService) TranslateBlocks(ctx context.Context, entryID int64, content,
 				case <-ctx.Done():
 					return
 				}
-			}(block)
+			}(batch)
 		}
 
 		wg.Wait()
 
+		if usage, ok := spent.Usage(); ok {
+			s.recordUsage(cfg, model.AIUsageTranslate, entryID, usage)
+		}
+
 		// Cache complete result if no errors and not cancelled
 		if !hasError.Load() && len(results) > 0 && ctx.Err() == nil {
 			// Sort by index
@@ -427,7 +559,7 @@ func (s *aiService) TranslateBlocks(ctx context.Context, entryID int64, content,
 			}
 
 			// Save to cache
-			if err := s.SaveTranslation(ctx, entryID, isReadability, fullHTML.String()); err != nil {
+			if err := s.SaveTranslation(ctx, entryID, isReadability, language, fullHTML.String()); err != nil {
 				logger.Warn("ai translate cache save failed", "module", "service", "action", "save", "resource", "ai", "result", "failed", "entry_id", entryID, "error", err)
 			}
 
@@ -438,16 +570,27 @@ func (s *aiService) TranslateBlocks(ctx context.Context, entryID int64, content,
 	return blockInfos, resultCh, errCh, nil
 }
 
+// translateBlockChars is the configured input budget of one block
+// translation call.
+func (s *aiService) translateBlockChars(ctx context.Context) int {
+	if setting, err := s.settingsRepo.Get(ctx, keyAITranslateBlockChars); err == nil && setting != nil {
+		if n, err := strconv.Atoi(setting.Value); err == nil && n > 0 {
+			return n
+		}
+	}
+	return ai.DefaultTranslateBlockChars
+}
+
 // TranslateBatch translates multiple articles' titles and summaries concurrently.
 // It first checks cache and only translates articles that don't have cached results.
-func (s *aiService) TranslateBatch(ctx context.Context, articles []BatchArticleInput) (<-chan BatchTranslateResult, <-chan error, error) {
+func (s *aiService) TranslateBatch(ctx context.Context, articles []BatchArticleInput, language string) (<-chan BatchTranslateResult, <-chan error, error) {
 	if len(articles) == 0 {
 		logger.Warn("ai batch translate empty input", "module", "service", "action", "fetch", "resource", "ai", "result", "failed")
 		return nil, nil, fmt.Errorf("no articles to translate")
 	}
 
 	// Get language setting
-	language := s.GetSummaryLanguage(ctx)
+	language = s.resolveLanguage(ctx, language)
 
 	// Collect entry IDs for batch cache lookup
 	entryIDs := make([]int64, 0, len(articles))
@@ -537,6 +680,13 @@ func (s *aiService) TranslateBatch(ctx context.Context, articles []BatchArticleI
 				defer wg.Done()
 				defer func() { <-sem }()
 
+				var spent ai.UsageTracker
+				defer func() {
+					if usage, ok := spent.Usage(); ok {
+						s.recordUsage(cfg, model.AIUsageListTranslate, eID, usage)
+					}
+				}()
+
 				// Create provider for this goroutine
 				provider, err := ai.NewProvider(cfg)
 				if err != nil {
@@ -563,7 +713,9 @@ func (s *aiService) TranslateBatch(ctx context.Context, articles []BatchArticleI
 					}
 					titlePrompt := ai.GetTranslateTextPrompt("title", language)
 					wrappedTitle := ai.WrapInput(a.Title)
-					translated, err := provider.Complete(ctx, titlePrompt, wrappedTitle)
+					tracker := &ai.UsageTracker{}
+					translated, err := provider.Complete(ai.WithUsageTracker(ctx, tracker), titlePrompt, wrappedTitle)
+					addUsage(&spent, callUsage(tracker, titlePrompt+wrappedTitle, translated))
 					if err != nil {
 						select {
 						case errCh <- fmt.Errorf("translate title for %s: %w", a.ID, err):
@@ -590,7 +742,9 @@ func (s *aiService) TranslateBatch(ctx context.Context, articles []BatchArticleI
 					}
 					summaryPrompt := ai.GetTranslateTextPrompt("summary", language)
 					wrappedSummary := ai.WrapInput(a.Summary)
-					translated, err := provider.Complete(ctx, summaryPrompt, wrappedSummary)
+					tracker := &ai.UsageTracker{}
+					translated, err := provider.Complete(ai.WithUsageTracker(ctx, tracker), summaryPrompt, wrappedSummary)
+					addUsage(&spent, callUsage(tracker, summaryPrompt+wrappedSummary, translated))
 					if err != nil {
 						select {
 						case errCh <- fmt.Errorf("translate summary for %s: %w", a.ID, err):
@@ -629,6 +783,145 @@ func (s *aiService) TranslateBatch(ctx context.Context, articles []BatchArticleI
 	return resultCh, errCh, nil
 }
 
+// TranslateFeedTitles translates feed titles concurrently. Cached translations
+// count only while their source hash matches the current title, and titles that
+// already look like the target language are cached as-is without a provider call.
+func (s *aiService) TranslateFeedTitles(ctx context.Context, feedIDs []int64, language string) ([]FeedTitleResult, error) {
+	if len(feedIDs) == 0 {
+		return nil, ErrInvalid
+	}
+	if s.feedRepo == nil || s.feedTitleRepo == nil {
+		return nil, fmt.Errorf("feed title translation not configured")
+	}
+
+	language = s.resolveLanguage(ctx, language)
+
+	feeds, err := s.feedRepo.GetByIDs(ctx, feedIDs)
+	if err != nil {
+		return nil, fmt.Errorf("get feeds: %w", err)
+	}
+
+	cachedMap, err := s.feedTitleRepo.GetBatch(ctx, feedIDs, language)
+	if err != nil {
+		logger.Warn("ai feed title translate cache lookup failed", "module", "service", "action", "fetch", "resource", "ai", "result", "failed", "error", err)
+		cachedMap = make(map[int64]*model.FeedTitleTranslation)
+	}
+
+	results := make([]FeedTitleResult, len(feeds))
+	done := make([]bool, len(feeds))
+	var pending []int
+	for i, feed := range feeds {
+		results[i] = FeedTitleResult{FeedID: feed.ID, Title: feed.DisplayTitle()}
+		sourceHash := hashutil.SHA256Hex(feed.DisplayTitle())
+		if cached, ok := cachedMap[feed.ID]; ok && cached.SourceHash == sourceHash {
+			results[i].Title = cached.Title
+			results[i].Cached = true
+			done[i] = true
+			continue
+		}
+		if ai.LooksLikeLanguage(feed.DisplayTitle(), language) {
+			if err := s.feedTitleRepo.Save(ctx, feed.ID, language, sourceHash, feed.DisplayTitle()); err != nil {
+				logger.Warn("ai feed title translate cache save failed", "module", "service", "action", "save", "resource", "ai", "result", "failed", "feed_id", feed.ID, "error", err)
+			}
+			results[i].Unchanged = true
+			done[i] = true
+			continue
+		}
+		pending = append(pending, i)
+	}
+
+	if len(pending) > 0 {
+		cfg, err := s.getAIConfig(ctx)
+		if err != nil {
+			logger.Warn("ai feed title translate get config failed", "module", "service", "action", "fetch", "resource", "ai", "result", "failed", "error", err)
+			return nil, err
+		}
+
+		var wg sync.WaitGroup
+		sem := make(chan struct{}, 5) // Limit to 5 concurrent translations
+		prompt := ai.GetTranslateTextPrompt("title", language)
+
+	feedLoop:
+		for _, i := range pending {
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				break feedLoop
+			}
+
+			wg.Add(1)
+			go func(i int, feed model.Feed) {
+				defer wg.Done()
+				defer func() { <-sem }()
+
+				provider, err := ai.NewProvider(cfg)
+				if err != nil {
+					logger.Warn("ai feed title translate provider create failed", "module", "service", "action", "fetch", "resource", "ai", "result", "failed", "provider", cfg.Provider, "model", cfg.Model, "error", err)
+					return
+				}
+				if err := s.rateLimiter.Wait(ctx); err != nil {
+					logger.Warn("ai feed title translate rate limit", "module", "service", "action", "fetch", "resource", "ai", "result", "failed", "feed_id", feed.ID, "error", err)
+					return
+				}
+
+				wrapped := ai.WrapInput(feed.DisplayTitle())
+				tracker := &ai.UsageTracker{}
+				translated, err := provider.Complete(ai.WithUsageTracker(ctx, tracker), prompt, wrapped)
+				s.recordUsage(cfg, model.AIUsageListTranslate, 0, callUsage(tracker, prompt+wrapped, translated))
+				if err != nil {
+					logger.Warn("ai feed title translate failed", "module", "service", "action", "fetch", "resource", "ai", "result", "failed", "feed_id", feed.ID, "error", err)
+					return
+				}
+				translated = strings.TrimSpace(translated)
+				if translated == "" {
+					return
+				}
+
+				if err := s.feedTitleRepo.Save(ctx, feed.ID, language, hashutil.SHA256Hex(feed.DisplayTitle()), translated); err != nil {
+					logger.Warn("ai feed title translate cache save failed", "module", "service", "action", "save", "resource", "ai", "result", "failed", "feed_id", feed.ID, "error", err)
+				}
+				results[i].Title = translated
+				done[i] = true
+			}(i, feeds[i])
+		}
+
+		wg.Wait()
+	}
+
+	translated := make([]FeedTitleResult, 0, len(results))
+	for i, result := range results {
+		if done[i] {
+			translated = append(translated, result)
+		}
+	}
+	logger.Info("ai feed title translate completed", "module", "service", "action", "fetch", "resource", "ai", "result", "ok", "count", len(feeds), "translated", len(translated))
+	return translated, nil
+}
+
+func (s *aiService) GetCachedFeedTitles(ctx context.Context, feeds []model.Feed, language string) (map[int64]string, error) {
+	titles := make(map[int64]string)
+	if s.feedTitleRepo == nil || len(feeds) == 0 {
+		return titles, nil
+	}
+
+	feedIDs := make([]int64, 0, len(feeds))
+	for _, feed := range feeds {
+		feedIDs = append(feedIDs, feed.ID)
+	}
+	cachedMap, err := s.feedTitleRepo.GetBatch(ctx, feedIDs, s.resolveLanguage(ctx, language))
+	if err != nil {
+		return nil, err
+	}
+
+	// A renamed feed keeps its stale row until it is translated again
+	for _, feed := range feeds {
+		if cached, ok := cachedMap[feed.ID]; ok && cached.SourceHash == hashutil.SHA256Hex(feed.DisplayTitle()) {
+			titles[feed.ID] = cached.Title
+		}
+	}
+	return titles, nil
+}
+
 func parseEntryID(id string) (int64, error) {
 	var entryID int64
 	_, err := fmt.Sscanf(id, "%d", &entryID)
@@ -651,6 +944,179 @@ func (s *aiService) ClearAllCache(ctx context.Context) (summaries, translations,
 		return summaries, translations, 0, fmt.Errorf("clear list translations: %w", err)
 	}
 
+	if s.feedTitleRepo != nil {
+		feedTitles, err := s.feedTitleRepo.DeleteAll(ctx)
+		if err != nil {
+			return summaries, translations, listTranslations, fmt.Errorf("clear feed title translations: %w", err)
+		}
+		listTranslations += feedTitles
+	}
+
+	if s.digestRepo != nil {
+		digests, err := s.digestRepo.DeleteAll(ctx)
+		if err != nil {
+			return summaries, translations, listTranslations, fmt.Errorf("clear digests: %w", err)
+		}
+		summaries += digests
+	}
+
 	logger.Info("ai cache cleared", "module", "service", "action", "clear", "resource", "ai", "result", "ok", "summaries", summaries, "translations", translations, "list_translations", listTranslations)
 	return summaries, translations, listTranslations, nil
 }
+
+// usageWriteTimeout bounds a background usage write.
+const usageWriteTimeout = 5 * time.Second
+
+// usagePruneInterval spaces out sweeps of usage rows past retention.
+const usagePruneInterval = time.Hour
+
+// callUsage is what one provider call cost: the provider's own count when it
+// sent one, else an estimate from the text. A call that reported nothing and
+// produced nothing counts as free.
+func callUsage(tracker *ai.UsageTracker, prompt, output string) ai.Usage {
+	if usage, ok := tracker.Usage(); ok {
+		return usage
+	}
+	if output == "" {
+		return ai.Usage{}
+	}
+	return ai.Usage{
+		PromptTokens:     ai.EstimateTokens(prompt),
+		CompletionTokens: ai.EstimateTokens(output),
+		Estimated:        true,
+	}
+}
+
+func addUsage(total *ai.UsageTracker, usage ai.Usage) {
+	if usage.PromptTokens > 0 || usage.CompletionTokens > 0 {
+		total.Add(usage)
+	}
+}
+
+// recordUsage stores an operation's usage in the background. Bookkeeping must
+// never fail or slow down the AI request, so errors are only logged.
+func (s *aiService) recordUsage(cfg ai.Config, operation string, entryID int64, usage ai.Usage) {
+	if s.usageRepo == nil || (usage.PromptTokens == 0 && usage.CompletionTokens == 0) {
+		return
+	}
+
+	row := model.AIUsage{
+		Operation:        operation,
+		Provider:         cfg.Provider,
+		Model:            cfg.Model,
+		PromptTokens:     usage.PromptTokens,
+		CompletionTokens: usage.CompletionTokens,
+		Estimated:        usage.Estimated,
+		CreatedAt:        time.Now(),
+	}
+	if entryID > 0 {
+		row.EntryID = &entryID
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), usageWriteTimeout)
+		defer cancel()
+
+		if err := s.usageRepo.Create(ctx, row); err != nil {
+			logger.Warn("ai usage record failed", "module", "service", "action", "save", "resource", "ai", "result", "failed", "operation", operation, "error", err)
+			return
+		}
+		s.pruneUsage(ctx)
+	}()
+}
+
+// pruneUsage deletes usage older than the configured retention, at most once per usagePruneInterval.
+func (s *aiService) pruneUsage(ctx context.Context) {
+	now := time.Now()
+	last := s.lastUsagePrune.Load()
+	if now.UnixNano()-last < int64(usagePruneInterval) || !s.lastUsagePrune.CompareAndSwap(last, now.UnixNano()) {
+		return
+	}
+
+	months := DefaultAIUsageRetentionMonths
+	if setting, err := s.settingsRepo.Get(ctx, keyAIUsageRetention); err == nil && setting != nil {
+		if n, err := strconv.Atoi(setting.Value); err == nil && n > 0 {
+			months = n
+		}
+	}
+
+	deleted, err := s.usageRepo.DeleteBefore(ctx, now.AddDate(0, -months, 0))
+	if err != nil {
+		logger.Warn("ai usage prune failed", "module", "service", "action", "clear", "resource", "ai", "result", "failed", "error", err)
+		return
+	}
+	if deleted > 0 {
+		logger.Info("ai usage pruned", "module", "service", "action", "clear", "resource", "ai", "result", "ok", "count", deleted, "retention_months", months)
+	}
+}
+
+func (s *aiService) GetUsage(ctx context.Context, params AIUsageParams) (*AIUsageReport, error) {
+	to := time.Now().UTC().Truncate(24 * time.Hour)
+	if params.To != "" {
+		parsed, err := time.Parse("2006-01-02", params.To)
+		if err != nil {
+			return nil, ErrInvalid
+		}
+		to = parsed
+	}
+	from := to.AddDate(0, 0, -29)
+	if params.From != "" {
+		parsed, err := time.Parse("2006-01-02", params.From)
+		if err != nil {
+			return nil, ErrInvalid
+		}
+		from = parsed
+	}
+	if from.After(to) {
+		return nil, ErrInvalid
+	}
+
+	report := &AIUsageReport{
+		From: from.Format("2006-01-02"),
+		To:   to.Format("2006-01-02"),
+		Days: []AIUsageDay{},
+	}
+	if s.usageRepo == nil {
+		return report, nil
+	}
+
+	totals, err := s.usageRepo.SumByDay(ctx, from, to.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, fmt.Errorf("sum ai usage: %w", err)
+	}
+
+	var prices map[string]AIModelPrice
+	if setting, err := s.settingsRepo.Get(ctx, keyAIModelPrices); err == nil && setting != nil {
+		prices = parseModelPrices(setting.Value)
+	}
+
+	unpriced := make(map[string]struct{})
+	for _, total := range totals {
+		cost := 0.0
+		if price, ok := prices[total.Model]; ok {
+			cost = float64(total.PromptTokens)/1e6*price.Input + float64(total.CompletionTokens)/1e6*price.Output
+		} else {
+			unpriced[total.Model] = struct{}{}
+		}
+
+		// Totals come ordered by day and operation, so one day's operation is contiguous
+		if n := len(report.Days); n == 0 || report.Days[n-1].Date != total.Day || report.Days[n-1].Operation != total.Operation {
+			report.Days = append(report.Days, AIUsageDay{Date: total.Day, Operation: total.Operation})
+		}
+		day := &report.Days[len(report.Days)-1]
+		day.Calls += total.Calls
+		day.PromptTokens += total.PromptTokens
+		day.CompletionTokens += total.CompletionTokens
+		day.Cost += cost
+
+		report.PromptTokens += total.PromptTokens
+		report.CompletionTokens += total.CompletionTokens
+		report.Cost += cost
+	}
+
+	for name := range unpriced {
+		report.UnpricedModels = append(report.UnpricedModels, name)
+	}
+	sort.Strings(report.UnpricedModels)
+	return report, nil
+}
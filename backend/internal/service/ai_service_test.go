@@ -2,12 +2,22 @@ package service_test
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"gist/backend/internal/service"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"gist/backend/internal/repository"
 	repositorymock "gist/backend/internal/repository/mock"
+	"gist/backend/internal/repository/testutil"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 
@@ -35,15 +45,69 @@ func TestAIService_SaveSummaryAndTranslation_UsesLanguage(t *testing.T) {
 	translationRepo := &translationRepoStub{}
 	svc := service.NewAIService(summaryRepo, translationRepo, &listTranslationRepoStub{}, repo, ai.NewRateLimiter(100))
 
-	err := svc.SaveSummary(context.Background(), 1, false, "summary")
+	err := svc.SaveSummary(context.Background(), 1, false, "", "summary")
 	require.NoError(t, err, "SaveSummary should not fail")
 	require.Equal(t, "en-US", summaryRepo.lastLanguage, "expected language en-US")
 
-	err = svc.SaveTranslation(context.Background(), 2, true, "content")
+	err = svc.SaveTranslation(context.Background(), 2, true, "", "content")
 	require.NoError(t, err, "SaveTranslation should not fail")
 	require.Equal(t, "en-US", translationRepo.lastLanguage, "expected language en-US")
 }
 
+func TestAIService_LanguageOverride_IndependentCacheRows(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	feedID := testutil.SeedFeed(t, db, model.Feed{Title: "F", URL: "u"})
+	entryID := testutil.SeedEntry(t, db, model.Entry{FeedID: feedID})
+	listRepo := repository.NewAIListTranslationRepository(db)
+	svc := service.NewAIService(
+		repository.NewAISummaryRepository(db, nil, nil),
+		repository.NewAITranslationRepository(db),
+		listRepo,
+		repository.NewSettingsRepository(db, nil),
+		ai.NewRateLimiter(100),
+	)
+	ctx := context.Background()
+
+	// The configured language defaults to zh-CN
+	require.NoError(t, svc.SaveSummary(ctx, entryID, false, "", "中文摘要"))
+	require.NoError(t, svc.SaveSummary(ctx, entryID, false, "en-US", "english summary"))
+	require.NoError(t, svc.SaveTranslation(ctx, entryID, false, "", "<p>中文</p>"))
+	require.NoError(t, svc.SaveTranslation(ctx, entryID, false, "en-US", "<p>english</p>"))
+
+	summary, err := svc.GetCachedSummary(ctx, entryID, false, "en-US")
+	require.NoError(t, err)
+	require.NotNil(t, summary)
+	require.Equal(t, "english summary", summary.Summary)
+	summary, err = svc.GetCachedSummary(ctx, entryID, false, "zh-CN")
+	require.NoError(t, err)
+	require.NotNil(t, summary)
+	require.Equal(t, "中文摘要", summary.Summary)
+	summary, err = svc.GetCachedSummary(ctx, entryID, false, "ja")
+	require.NoError(t, err)
+	require.Nil(t, summary)
+
+	translation, err := svc.GetCachedTranslation(ctx, entryID, false, "en-US")
+	require.NoError(t, err)
+	require.NotNil(t, translation)
+	require.Equal(t, "<p>english</p>", translation.Content)
+	translation, err = svc.GetCachedTranslation(ctx, entryID, false, "")
+	require.NoError(t, err)
+	require.NotNil(t, translation)
+	require.Equal(t, "<p>中文</p>", translation.Content)
+
+	// List translations are looked up under the requested language too
+	require.NoError(t, listRepo.Save(ctx, entryID, "en-US", "Title", "Summary"))
+	resultCh, _, err := svc.TranslateBatch(ctx, []service.BatchArticleInput{{ID: strconv.FormatInt(entryID, 10)}}, "en-US")
+	require.NoError(t, err)
+	result := <-resultCh
+	require.True(t, result.Cached)
+	require.Equal(t, "Title", *result.Title)
+
+	// Nothing is cached for zh-CN and no AI provider is configured
+	_, _, err = svc.TranslateBatch(ctx, []service.BatchArticleInput{{ID: strconv.FormatInt(entryID, 10)}}, "")
+	require.Error(t, err)
+}
+
 func TestAIService_ClearAllCache_ErrorPropagation(t *testing.T) {
 	summaryRepo := &summaryRepoStub{deleteAllErr: errors.New("summary delete failed")}
 	translationRepo := &translationRepoStub{}
@@ -71,21 +135,21 @@ func TestAIService_Summarize_MissingConfig(t *testing.T) {
 	repo := newSettingsRepoStub()
 	svc := service.NewAIService(&summaryRepoStub{}, &translationRepoStub{}, &listTranslationRepoStub{}, repo, ai.NewRateLimiter(100))
 
-	_, _, err := svc.Summarize(context.Background(), 1, "content", "title", false)
+	_, _, err := svc.Summarize(context.Background(), 1, "content", "title", false, "")
 	require.Error(t, err, "expected error for missing config")
 }
 
 func TestAIService_TranslateBlocks_EmptyContent(t *testing.T) {
 	svc := service.NewAIService(&summaryRepoStub{}, &translationRepoStub{}, &listTranslationRepoStub{}, newSettingsRepoStub(), ai.NewRateLimiter(100))
 
-	_, _, _, err := svc.TranslateBlocks(context.Background(), 1, "", "title", false)
+	_, _, _, err := svc.TranslateBlocks(context.Background(), 1, "", "title", false, "")
 	require.Error(t, err, "expected error for empty content")
 }
 
 func TestAIService_TranslateBatch_EmptyInput(t *testing.T) {
 	svc := service.NewAIService(&summaryRepoStub{}, &translationRepoStub{}, &listTranslationRepoStub{}, newSettingsRepoStub(), ai.NewRateLimiter(100))
 
-	_, _, err := svc.TranslateBatch(context.Background(), nil)
+	_, _, err := svc.TranslateBatch(context.Background(), nil, "")
 	require.Error(t, err, "expected error for empty batch")
 }
 
@@ -94,7 +158,7 @@ func TestAIService_GetCachedTranslation_Error(t *testing.T) {
 	translationRepo := &translationRepoStub{getErr: errors.New("get failed")}
 	svc := service.NewAIService(&summaryRepoStub{}, translationRepo, &listTranslationRepoStub{}, repo, ai.NewRateLimiter(100))
 
-	_, err := svc.GetCachedTranslation(context.Background(), 1, false)
+	_, err := svc.GetCachedTranslation(context.Background(), 1, false, "")
 	require.Error(t, err)
 }
 
@@ -103,7 +167,7 @@ func TestAIService_SaveTranslation_Error(t *testing.T) {
 	translationRepo := &translationRepoStub{saveErr: errors.New("save failed")}
 	svc := service.NewAIService(&summaryRepoStub{}, translationRepo, &listTranslationRepoStub{}, repo, ai.NewRateLimiter(100))
 
-	err := svc.SaveTranslation(context.Background(), 1, false, "content")
+	err := svc.SaveTranslation(context.Background(), 1, false, "", "content")
 	require.Error(t, err)
 }
 
@@ -120,7 +184,7 @@ func TestAIService_TranslateBatch_CacheHit(t *testing.T) {
 	resultCh, errCh, err := svc.TranslateBatch(context.Background(), []service.BatchArticleInput{
 		{ID: "1"},
 		{ID: "2"},
-	})
+	}, "")
 	require.NoError(t, err)
 
 	results := make(map[string]service.BatchTranslateResult)
@@ -148,7 +212,7 @@ func TestAIService_TranslateBatch_InvalidIDs(t *testing.T) {
 
 	resultCh, errCh, err := svc.TranslateBatch(context.Background(), []service.BatchArticleInput{
 		{ID: "not-a-number"},
-	})
+	}, "")
 	require.NoError(t, err)
 
 	for range resultCh {
@@ -177,6 +241,10 @@ func (s *summaryRepoStub) Get(ctx context.Context, entryID int64, isReadability
 	return s.getResult, nil
 }
 
+func (s *summaryRepoStub) GetBatch(ctx context.Context, entryIDs []int64, language string) (map[int64]*model.AISummary, error) {
+	return make(map[int64]*model.AISummary), nil
+}
+
 func (s *summaryRepoStub) Save(ctx context.Context, entryID int64, isReadability bool, language, summary string) error {
 	s.lastLanguage = language
 	return nil
@@ -195,6 +263,7 @@ func (s *summaryRepoStub) DeleteAll(ctx context.Context) (int64, error) {
 
 type translationRepoStub struct {
 	lastLanguage string
+	lastContent  string
 	deleteAllErr error
 	getErr       error
 	saveErr      error
@@ -212,6 +281,7 @@ func (s *translationRepoStub) Save(ctx context.Context, entryID int64, isReadabi
 		return s.saveErr
 	}
 	s.lastLanguage = language
+	s.lastContent = content
 	return nil
 }
 
@@ -250,6 +320,14 @@ func (s *listTranslationRepoStub) DeleteByEntryID(ctx context.Context, entryID i
 	return nil
 }
 
+func (s *listTranslationRepoStub) ListUntranslated(ctx context.Context, language string, modes []string, since time.Time, limit int) ([]model.Entry, error) {
+	return nil, nil
+}
+
+func (s *listTranslationRepoStub) ListUntranslatedInFeeds(ctx context.Context, language string, feedIDs []int64, since time.Time, limit int) ([]model.Entry, error) {
+	return nil, nil
+}
+
 func (s *listTranslationRepoStub) DeleteAll(ctx context.Context) (int64, error) {
 	if s.deleteAllErr != nil {
 		return 0, s.deleteAllErr
@@ -272,7 +350,7 @@ func TestAIService_GetCachedSummary_Success(t *testing.T) {
 	}
 	svc := service.NewAIService(summaryRepo, &translationRepoStub{}, &listTranslationRepoStub{}, repo, ai.NewRateLimiter(100))
 
-	result, err := svc.GetCachedSummary(context.Background(), 123, false)
+	result, err := svc.GetCachedSummary(context.Background(), 123, false, "")
 	require.NoError(t, err)
 	require.NotNil(t, result)
 	require.Equal(t, int64(123), result.EntryID)
@@ -284,7 +362,7 @@ func TestAIService_GetCachedSummary_NotFound(t *testing.T) {
 	summaryRepo := &summaryRepoStub{getResult: nil}
 	svc := service.NewAIService(summaryRepo, &translationRepoStub{}, &listTranslationRepoStub{}, repo, ai.NewRateLimiter(100))
 
-	result, err := svc.GetCachedSummary(context.Background(), 123, false)
+	result, err := svc.GetCachedSummary(context.Background(), 123, false, "")
 	require.NoError(t, err)
 	require.Nil(t, result)
 }
@@ -294,7 +372,7 @@ func TestAIService_GetCachedSummary_Error(t *testing.T) {
 	summaryRepo := &summaryRepoStub{getErr: errors.New("database error")}
 	svc := service.NewAIService(summaryRepo, &translationRepoStub{}, &listTranslationRepoStub{}, repo, ai.NewRateLimiter(100))
 
-	_, err := svc.GetCachedSummary(context.Background(), 123, false)
+	_, err := svc.GetCachedSummary(context.Background(), 123, false, "")
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "database error")
 }
@@ -323,6 +401,9 @@ func TestAIService_BuildSummarizeSystemPrompt_UsesFeedReminder(t *testing.T) {
 		ai.NewRateLimiter(100),
 		entryRepo,
 		feedRepo,
+		nil,
+		nil,
+		nil,
 	)
 
 	prompt := service.BuildAISummarizeSystemPromptForTest(svc, context.Background(), 123, "Title")
@@ -349,6 +430,9 @@ func TestAIService_BuildSummarizeSystemPrompt_FallbackOnFeedLookupError(t *testi
 		ai.NewRateLimiter(100),
 		entryRepo,
 		feedRepo,
+		nil,
+		nil,
+		nil,
 	)
 
 	prompt := service.BuildAISummarizeSystemPromptForTest(svc, context.Background(), 123, "Title")
@@ -371,7 +455,7 @@ func TestAIService_TranslateBlocks_ContextCancelled(t *testing.T) {
 
 	// TranslateBlocks with cancelled context
 	// It should either return an error or return channels that complete quickly
-	blockInfos, resultCh, errCh, err := svc.TranslateBlocks(ctx, 1, "<p>Test content</p>", "title", false)
+	blockInfos, resultCh, errCh, err := svc.TranslateBlocks(ctx, 1, "<p>Test content</p>", "title", false, "")
 
 	// With cancelled context, it should either:
 	// 1. Return early with an error (missing config), or
@@ -416,7 +500,7 @@ func TestAIService_TranslateBlocks_ContextCancelledDuringProcessing(t *testing.T
 	defer cancel()
 
 	// Start TranslateBlocks
-	blockInfos, resultCh, errCh, err := svc.TranslateBlocks(ctx, 1, "<p>Block 1</p><p>Block 2</p><p>Block 3</p>", "title", false)
+	blockInfos, resultCh, errCh, err := svc.TranslateBlocks(ctx, 1, "<p>Block 1</p><p>Block 2</p><p>Block 3</p>", "title", false, "")
 
 	// With proper AI config, it should not return an immediate error
 	if err != nil {
@@ -464,7 +548,7 @@ func TestAIService_TranslateBlocks_NoCacheOnCancel(t *testing.T) {
 	cancel()
 
 	// Try to translate with cancelled context
-	_, resultCh, errCh, err := svc.TranslateBlocks(ctx, 1, "<p>Test</p>", "title", false)
+	_, resultCh, errCh, err := svc.TranslateBlocks(ctx, 1, "<p>Test</p>", "title", false, "")
 
 	if err != nil {
 		// Expected - missing config error
@@ -481,3 +565,353 @@ func TestAIService_TranslateBlocks_NoCacheOnCancel(t *testing.T) {
 	// because SaveTranslation was never called due to ctx.Err() != nil check)
 	require.Empty(t, translationRepo.lastLanguage, "Should not save cache on cancelled context")
 }
+
+// upperText upper-cases the text of HTML, leaving tags alone.
+func upperText(html string) string {
+	return regexp.MustCompile(`(^|>)[^<]*`).ReplaceAllStringFunc(html, strings.ToUpper)
+}
+
+// newEchoTranslateServer answers every chat completion with the text of its
+// input upper-cased, counting the calls.
+func newEchoTranslateServer(t *testing.T, calls *atomic.Int32) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload struct {
+			Messages []struct {
+				Content string `json:"content"`
+			} `json:"messages"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		calls.Add(1)
+
+		input := payload.Messages[len(payload.Messages)-1].Content
+		input = input[strings.Index(input, "<input>\n")+len("<input>\n") : strings.Index(input, "\n</input>")]
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"id": "chatcmpl-1", "object": "chat.completion", "created": 1, "model": "gpt-4o-mini",
+			"choices": []any{map[string]any{"index": 0, "finish_reason": "stop", "message": map[string]any{"role": "assistant", "content": upperText(input)}}},
+		})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestAIService_TranslateBlocks_MergesSmallBlocks(t *testing.T) {
+	var calls atomic.Int32
+	server := newEchoTranslateServer(t, &calls)
+
+	var content strings.Builder
+	for i := range 10 {
+		fmt.Fprintf(&content, "<p>Line %d.</p>", i)
+	}
+	content.WriteString(`<img src="a.png"/><p>After the image.</p>`)
+
+	for _, tc := range []struct {
+		budget  string
+		calls   int32
+		results int
+	}{
+		{budget: "", calls: 2, results: 2},
+		{budget: "1", calls: 11, results: 11},
+	} {
+		calls.Store(0)
+		repo := newSettingsRepoStub()
+		repo.data[service.KeyAIProvider] = ai.ProviderCompatible
+		repo.data[service.KeyAIAPIKey] = "test-key"
+		repo.data[service.KeyAIBaseURL] = server.URL + "/v1/"
+		repo.data[service.KeyAIModel] = "gpt-4o-mini"
+		if tc.budget != "" {
+			repo.data[service.KeyAITranslateBlockChars] = tc.budget
+		}
+		translationRepo := &translationRepoStub{}
+		svc := service.NewAIService(&summaryRepoStub{}, translationRepo, &listTranslationRepoStub{}, repo, ai.NewRateLimiter(1000))
+
+		blockInfos, resultCh, errCh, err := svc.TranslateBlocks(context.Background(), 1, content.String(), "title", false, "en-US")
+		require.NoError(t, err)
+		require.Len(t, blockInfos, 12)
+
+		// Each result replaces its whole range of the client's blocks
+		translated := make(map[int]string)
+		var results []service.TranslateBlockResult
+		for result := range resultCh {
+			results = append(results, result)
+			var original strings.Builder
+			for i := result.Index; i <= result.EndIndex; i++ {
+				original.WriteString(blockInfos[i].HTML)
+				translated[i] = ""
+			}
+			require.Equal(t, upperText(original.String()), result.HTML)
+		}
+		for err := range errCh {
+			require.NoError(t, err)
+		}
+		require.Len(t, results, tc.results)
+		require.Len(t, translated, 11)
+		require.Equal(t, tc.calls, calls.Load())
+
+		// The cached article is still the whole translated HTML
+		var expected strings.Builder
+		for _, block := range blockInfos {
+			if block.NeedTranslate {
+				expected.WriteString(upperText(block.HTML))
+			} else {
+				expected.WriteString(block.HTML)
+			}
+		}
+		require.Equal(t, expected.String(), translationRepo.lastContent)
+	}
+}
+
+// newChatCompletionServer answers every chat completion with text, reporting
+// usage only when promptTokens is positive.
+func newChatCompletionServer(t *testing.T, text string, promptTokens, completionTokens int) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp := map[string]any{
+			"id":      "chatcmpl-1",
+			"object":  "chat.completion",
+			"created": 1,
+			"model":   "gpt-4o-mini",
+			"choices": []any{map[string]any{
+				"index":         0,
+				"finish_reason": "stop",
+				"message":       map[string]any{"role": "assistant", "content": text},
+			}},
+		}
+		if promptTokens > 0 {
+			resp["usage"] = map[string]any{
+				"prompt_tokens":     promptTokens,
+				"completion_tokens": completionTokens,
+				"total_tokens":      promptTokens + completionTokens,
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+}
+
+func drainBatch(t *testing.T, resultCh <-chan service.BatchTranslateResult, errCh <-chan error) {
+	t.Helper()
+	for range resultCh {
+	}
+	for err := range errCh {
+		require.NoError(t, err)
+	}
+}
+
+func TestAIService_TranslateBatch_RecordsProviderUsage(t *testing.T) {
+	server := newChatCompletionServer(t, "translated", 11, 7)
+	defer server.Close()
+	db := testutil.NewTestDB(t)
+	feedID := testutil.SeedFeed(t, db, model.Feed{Title: "F", URL: "u"})
+	entryID := testutil.SeedEntry(t, db, model.Entry{FeedID: feedID})
+	repo := newSettingsRepoStub()
+	repo.data[service.KeyAIProvider] = ai.ProviderCompatible
+	repo.data[service.KeyAIAPIKey] = "test-key"
+	repo.data[service.KeyAIBaseURL] = server.URL + "/v1/"
+	repo.data[service.KeyAIModel] = "gpt-4o-mini"
+	usageRepo := repository.NewAIUsageRepository(db)
+	svc := service.NewAIServiceWithFeedContext(&summaryRepoStub{}, &translationRepoStub{}, &listTranslationRepoStub{}, repo, ai.NewRateLimiter(100), nil, nil, usageRepo, nil, nil)
+
+	resultCh, errCh, err := svc.TranslateBatch(context.Background(), []service.BatchArticleInput{
+		{ID: strconv.FormatInt(entryID, 10), Title: "Title", Summary: "Summary"},
+	}, "en-US")
+	require.NoError(t, err)
+	drainBatch(t, resultCh, errCh)
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	var totals []model.AIUsageTotal
+	require.Eventually(t, func() bool {
+		totals, err = usageRepo.SumByDay(context.Background(), today, today.AddDate(0, 0, 1))
+		return err == nil && len(totals) == 1
+	}, 2*time.Second, 10*time.Millisecond)
+	// Title and summary are two calls folded into one row for the entry
+	require.Equal(t, model.AIUsageTotal{
+		Day:              today.Format("2006-01-02"),
+		Operation:        model.AIUsageListTranslate,
+		Model:            "gpt-4o-mini",
+		Calls:            1,
+		PromptTokens:     22,
+		CompletionTokens: 14,
+	}, totals[0])
+}
+
+func TestAIService_TranslateBatch_EstimatesMissingUsageAndPrunes(t *testing.T) {
+	server := newChatCompletionServer(t, "你好", 0, 0)
+	defer server.Close()
+	db := testutil.NewTestDB(t)
+	feedID := testutil.SeedFeed(t, db, model.Feed{Title: "F", URL: "u"})
+	entryID := testutil.SeedEntry(t, db, model.Entry{FeedID: feedID})
+	repo := newSettingsRepoStub()
+	repo.data[service.KeyAIProvider] = ai.ProviderCompatible
+	repo.data[service.KeyAIAPIKey] = "test-key"
+	repo.data[service.KeyAIBaseURL] = server.URL + "/v1/"
+	repo.data[service.KeyAIModel] = "gpt-4o-mini"
+	usageRepo := repository.NewAIUsageRepository(db)
+	svc := service.NewAIServiceWithFeedContext(&summaryRepoStub{}, &translationRepoStub{}, &listTranslationRepoStub{}, repo, ai.NewRateLimiter(100), nil, nil, usageRepo, nil, nil)
+	repo.data[service.KeyAIUsageRetention] = "1"
+
+	old := time.Now().AddDate(0, -2, 0)
+	require.NoError(t, usageRepo.Create(context.Background(), model.AIUsage{
+		Operation: model.AIUsageSummarize, Provider: "openai", Model: "old", PromptTokens: 1, CreatedAt: old,
+	}))
+
+	resultCh, errCh, err := svc.TranslateBatch(context.Background(), []service.BatchArticleInput{
+		{ID: strconv.FormatInt(entryID, 10), Title: "Title"},
+	}, "zh-CN")
+	require.NoError(t, err)
+	drainBatch(t, resultCh, errCh)
+
+	from := old.AddDate(0, 0, -1)
+	to := time.Now().AddDate(0, 0, 1)
+	var totals []model.AIUsageTotal
+	require.Eventually(t, func() bool {
+		totals, err = usageRepo.SumByDay(context.Background(), from, to)
+		return err == nil && len(totals) == 1 && totals[0].Model == "gpt-4o-mini"
+	}, 2*time.Second, 10*time.Millisecond)
+	require.Equal(t, ai.EstimateTokens("你好"), totals[0].CompletionTokens)
+	require.Positive(t, totals[0].PromptTokens)
+}
+
+func TestAIService_TranslateFeedTitles(t *testing.T) {
+	server := newChatCompletionServer(t, "Habr", 0, 0)
+	defer server.Close()
+	inner := server.Config.Handler
+	var calls atomic.Int32
+	server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		inner.ServeHTTP(w, r)
+	})
+
+	db := testutil.NewTestDB(t)
+	repo := newSettingsRepoStub()
+	repo.data[service.KeyAIProvider] = ai.ProviderCompatible
+	repo.data[service.KeyAIAPIKey] = "test-key"
+	repo.data[service.KeyAIBaseURL] = server.URL + "/v1/"
+	repo.data[service.KeyAIModel] = "gpt-4o-mini"
+	svc := service.NewAIServiceWithFeedContext(
+		&summaryRepoStub{},
+		&translationRepoStub{},
+		&listTranslationRepoStub{},
+		repo,
+		ai.NewRateLimiter(100),
+		nil,
+		repository.NewFeedRepository(db, nil, nil),
+		nil,
+		repository.NewFeedTitleTranslationRepository(db, nil),
+		nil,
+	)
+	ctx := context.Background()
+	foreignID := testutil.SeedFeed(t, db, model.Feed{Title: "Хабр", URL: "u1"})
+	englishID := testutil.SeedFeed(t, db, model.Feed{Title: "Hacker News", URL: "u2"})
+
+	results, err := svc.TranslateFeedTitles(ctx, []int64{foreignID, englishID}, "en-US")
+	require.NoError(t, err)
+	require.ElementsMatch(t, []service.FeedTitleResult{
+		{FeedID: foreignID, Title: "Habr"},
+		{FeedID: englishID, Title: "Hacker News", Unchanged: true},
+	}, results)
+	require.Equal(t, int32(1), calls.Load(), "English title should skip the provider")
+
+	// Both are cached now, including the unchanged title
+	results, err = svc.TranslateFeedTitles(ctx, []int64{foreignID, englishID}, "en-US")
+	require.NoError(t, err)
+	require.ElementsMatch(t, []service.FeedTitleResult{
+		{FeedID: foreignID, Title: "Habr", Cached: true},
+		{FeedID: englishID, Title: "Hacker News", Cached: true},
+	}, results)
+	require.Equal(t, int32(1), calls.Load())
+
+	feeds := []model.Feed{{ID: foreignID, Title: "Хабр"}, {ID: englishID, Title: "Hacker News"}}
+	titles, err := svc.GetCachedFeedTitles(ctx, feeds, "en-US")
+	require.NoError(t, err)
+	require.Equal(t, map[int64]string{foreignID: "Habr", englishID: "Hacker News"}, titles)
+
+	// A renamed feed no longer matches its cached source hash
+	_, err = db.Exec(`UPDATE feeds SET title = ? WHERE id = ?`, "Хабр Карьера", foreignID)
+	require.NoError(t, err)
+	feeds[0].Title = "Хабр Карьера"
+	titles, err = svc.GetCachedFeedTitles(ctx, feeds, "en-US")
+	require.NoError(t, err)
+	require.Equal(t, map[int64]string{englishID: "Hacker News"}, titles)
+
+	results, err = svc.TranslateFeedTitles(ctx, []int64{foreignID}, "en-US")
+	require.NoError(t, err)
+	require.Equal(t, []service.FeedTitleResult{{FeedID: foreignID, Title: "Habr"}}, results)
+	require.Equal(t, int32(2), calls.Load())
+}
+
+func TestAIService_TranslateFeedTitles_NotConfigured(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	repo := newSettingsRepoStub()
+	repo.data[service.KeyAIProvider] = ai.ProviderCompatible
+	repo.data[service.KeyAIAPIKey] = "test-key"
+	repo.data[service.KeyAIBaseURL] = "http://unused" + "/v1/"
+	repo.data[service.KeyAIModel] = "gpt-4o-mini"
+	svc := service.NewAIServiceWithFeedContext(
+		&summaryRepoStub{},
+		&translationRepoStub{},
+		&listTranslationRepoStub{},
+		repo,
+		ai.NewRateLimiter(100),
+		nil,
+		repository.NewFeedRepository(db, nil, nil),
+		nil,
+		repository.NewFeedTitleTranslationRepository(db, nil),
+		nil,
+	)
+	ctx := context.Background()
+	feedID := testutil.SeedFeed(t, db, model.Feed{Title: "Хабр", URL: "u1"})
+	englishID := testutil.SeedFeed(t, db, model.Feed{Title: "Hacker News", URL: "u2"})
+
+	_, err := svc.TranslateFeedTitles(ctx, nil, "en-US")
+	require.ErrorIs(t, err, service.ErrInvalid)
+
+	// Titles that need no provider call still work without AI settings
+	svc = service.NewAIServiceWithFeedContext(&summaryRepoStub{}, &translationRepoStub{}, &listTranslationRepoStub{}, newSettingsRepoStub(), ai.NewRateLimiter(100), nil, repository.NewFeedRepository(db, nil, nil), nil, repository.NewFeedTitleTranslationRepository(db, nil), nil)
+	results, err := svc.TranslateFeedTitles(ctx, []int64{englishID}, "en-US")
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+
+	_, err = svc.TranslateFeedTitles(ctx, []int64{feedID}, "en-US")
+	require.ErrorIs(t, err, service.ErrAINotConfigured)
+}
+
+func TestAIService_GetUsage(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	repo := newSettingsRepoStub()
+	repo.data[service.KeyAIProvider] = ai.ProviderCompatible
+	repo.data[service.KeyAIAPIKey] = "test-key"
+	repo.data[service.KeyAIBaseURL] = "http://unused" + "/v1/"
+	repo.data[service.KeyAIModel] = "gpt-4o-mini"
+	usageRepo := repository.NewAIUsageRepository(db)
+	svc := service.NewAIServiceWithFeedContext(&summaryRepoStub{}, &translationRepoStub{}, &listTranslationRepoStub{}, repo, ai.NewRateLimiter(100), nil, nil, usageRepo, nil, nil)
+	repo.data[service.KeyAIModelPrices] = `{"gpt-4o":{"input":2,"output":10}}`
+
+	ctx := context.Background()
+	day := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	for _, row := range []model.AIUsage{
+		{Operation: model.AIUsageSummarize, Provider: "openai", Model: "gpt-4o", PromptTokens: 1_000_000, CompletionTokens: 100_000, CreatedAt: day},
+		{Operation: model.AIUsageSummarize, Provider: "compatible", Model: "local", PromptTokens: 500, CompletionTokens: 50, CreatedAt: day},
+		{Operation: model.AIUsageTranslate, Provider: "openai", Model: "gpt-4o", PromptTokens: 500_000, CreatedAt: day.AddDate(0, 0, 1)},
+		{Operation: model.AIUsageTranslate, Provider: "openai", Model: "gpt-4o", PromptTokens: 9, CreatedAt: day.AddDate(0, 0, 5)},
+	} {
+		require.NoError(t, usageRepo.Create(ctx, row))
+	}
+
+	report, err := svc.GetUsage(ctx, service.AIUsageParams{From: "2025-03-01", To: "2025-03-02"})
+	require.NoError(t, err)
+	require.Equal(t, "2025-03-01", report.From)
+	require.Equal(t, "2025-03-02", report.To)
+	require.Len(t, report.Days, 2)
+	require.Equal(t, service.AIUsageDay{Date: "2025-03-01", Operation: model.AIUsageSummarize, Calls: 2, PromptTokens: 1_000_500, CompletionTokens: 100_050, Cost: 3}, report.Days[0])
+	require.Equal(t, service.AIUsageDay{Date: "2025-03-02", Operation: model.AIUsageTranslate, Calls: 1, PromptTokens: 500_000, Cost: 1}, report.Days[1])
+	require.InDelta(t, 4.0, report.Cost, 1e-9)
+	require.Equal(t, 1_500_500, report.PromptTokens)
+	require.Equal(t, []string{"local"}, report.UnpricedModels)
+
+	_, err = svc.GetUsage(ctx, service.AIUsageParams{From: "2025-03-02", To: "2025-03-01"})
+	require.ErrorIs(t, err, service.ErrInvalid)
+	_, err = svc.GetUsage(ctx, service.AIUsageParams{From: "March"})
+	require.ErrorIs(t, err, service.ErrInvalid)
+}
@@ -6,6 +6,7 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net"
 	"net/http"
@@ -27,8 +28,31 @@ const (
 	solverTimeout = 30 * time.Second
 	logModule     = "service"
 	logResource   = "anubis"
+
+	// breakerThreshold is how many consecutive rejections or failed solves suspend a host
+	breakerThreshold = 3
+	// breakerCooldown is how long a suspended host is left alone
+	breakerCooldown = 6 * time.Hour
 )
 
+// ErrRejected is returned for Anubis rejection pages, which carry no solvable challenge
+var ErrRejected = errors.New("anubis: upstream rejected")
+
+// CooldownError is returned while a host's circuit breaker is open
+type CooldownError struct {
+	Host  string
+	Until time.Time
+}
+
+func (e *CooldownError) Error() string {
+	return fmt.Sprintf("host rejecting automated access (cooldown until %s)", e.Until.UTC().Format(time.RFC3339))
+}
+
+// Is lets callers that only care about rejection treat a cooldown as one
+func (e *CooldownError) Is(target error) bool {
+	return target == ErrRejected
+}
+
 var submitHeaderOrder = []string{
 	"accept",
 	"accept-language",
@@ -99,6 +123,7 @@ type Solver struct {
 	store         *Store
 	mu            sync.Mutex
 	solving       map[string]chan struct{} // cache_key -> done channel (prevents concurrent solving)
+	hostSlots     map[string]chan struct{} // host -> single slot held by the active solve
 	newSession    newSessionFunc           // for testing injection
 }
 
@@ -108,6 +133,7 @@ func NewSolver(clientFactory *network.ClientFactory, store *Store) *Solver {
 		clientFactory: clientFactory,
 		store:         store,
 		solving:       make(map[string]chan struct{}),
+		hostSlots:     make(map[string]chan struct{}),
 	}
 }
 
@@ -187,12 +213,31 @@ func (s *Solver) SolveFromBody(ctx context.Context, body []byte, originalURL str
 
 // SolveFromBodyWithHeaders detects and solves Anubis challenge from response body.
 // requestHeaders should be the original request headers that triggered the challenge.
+// Rejection pages return ErrRejected; once a host keeps rejecting or failing, every
+// Anubis page from it returns a *CooldownError until the cooldown passes.
+// Solves for one host run one at a time; a solve that had to wait reuses the
+// cookie its predecessor cached, falling back to the host-level entry.
 func (s *Solver) SolveFromBodyWithHeaders(ctx context.Context, body []byte, originalURL string, initialCookies []*http.Cookie, requestHeaders http.Header) (string, error) {
-	if !IsAnubisChallenge(body) {
+	if !IsAnubisPage(body) {
 		return "", nil
 	}
 
 	host := normalizeHost(extractHost(originalURL))
+	if status := s.HostStatus(ctx, host); status.Open(time.Now()) {
+		logger.Debug("anubis host in cooldown",
+			"module", logModule,
+			"action", "solve",
+			"resource", logResource,
+			"result", "skipped",
+			"host", host,
+			"until", status.RejectedUntil.Format(time.RFC3339),
+		)
+		return "", &CooldownError{Host: host, Until: *status.RejectedUntil}
+	}
+	if !IsAnubisChallenge(body) {
+		return "", s.recordFailure(ctx, host, ErrRejected)
+	}
+
 	cacheKey := buildCookieCacheKey(host, requestHeaders)
 	if cacheKey == "" {
 		cacheKey = host
@@ -236,10 +281,32 @@ func (s *Solver) SolveFromBodyWithHeaders(ctx context.Context, body []byte, orig
 		s.mu.Unlock()
 	}()
 
+	// Only one solve per host runs at a time, whatever the fingerprint: parallel
+	// solves against one host mostly earn more challenges or a rejection.
+	waited, err := s.acquireHost(ctx, host)
+	if err != nil {
+		return "", err
+	}
+	defer s.releaseHost(host)
+
+	if waited {
+		// The solve we waited for may have left a cookie or opened the breaker
+		if cookie := s.GetCachedCookieWithHeaders(ctx, host, requestHeaders); cookie != "" {
+			return cookie, nil
+		}
+		if status := s.HostStatus(ctx, host); status.Open(time.Now()) {
+			return "", &CooldownError{Host: host, Until: *status.RejectedUntil}
+		}
+	}
+
+	if s.store != nil {
+		s.store.RecordSolve(host, time.Now())
+	}
+
 	// Parse the challenge JSON from HTML
 	challenge, err := parseChallenge(body)
 	if err != nil {
-		return "", fmt.Errorf("parse anubis challenge: %w", err)
+		return "", s.recordFailure(ctx, host, fmt.Errorf("parse anubis challenge: %w", err))
 	}
 
 	logger.Debug("anubis detected challenge",
@@ -255,20 +322,117 @@ func (s *Solver) SolveFromBodyWithHeaders(ctx context.Context, body []byte, orig
 	// Solve the challenge based on algorithm type
 	result, err := solveChallenge(ctx, challenge)
 	if err != nil {
-		return "", fmt.Errorf("solve anubis challenge: %w", err)
+		return "", s.recordFailure(ctx, host, fmt.Errorf("solve anubis challenge: %w", err))
 	}
 
 	// Submit the solution (pass initial cookies for session)
 	cookie, expiresAt, err := s.submit(ctx, originalURL, challenge, result, initialCookies, requestHeaders)
 	if err != nil {
-		return "", fmt.Errorf("submit anubis solution: %w", err)
+		return "", s.recordFailure(ctx, host, fmt.Errorf("submit anubis solution: %w", err))
 	}
 
 	s.cacheSolvedCookie(ctx, host, cacheKey, cookie, expiresAt)
+	s.resetBreaker(ctx, host)
 
 	return cookie, nil
 }
 
+// acquireHost takes the host's solve slot, blocking while another solve holds it.
+// waited reports whether it had to block.
+func (s *Solver) acquireHost(ctx context.Context, host string) (waited bool, err error) {
+	s.mu.Lock()
+	slot, ok := s.hostSlots[host]
+	if !ok {
+		slot = make(chan struct{}, 1)
+		s.hostSlots[host] = slot
+	}
+	s.mu.Unlock()
+
+	select {
+	case slot <- struct{}{}:
+		return false, nil
+	default:
+	}
+
+	logger.Debug("anubis waiting for host solve",
+		"module", logModule,
+		"action", "solve",
+		"resource", logResource,
+		"result", "ok",
+		"host", host,
+	)
+	select {
+	case slot <- struct{}{}:
+		return true, nil
+	case <-ctx.Done():
+		return true, ctx.Err()
+	}
+}
+
+func (s *Solver) releaseHost(host string) {
+	s.mu.Lock()
+	slot := s.hostSlots[host]
+	s.mu.Unlock()
+	<-slot
+}
+
+// HostStatus returns the circuit breaker state of a host
+func (s *Solver) HostStatus(ctx context.Context, host string) HostStatus {
+	if s.store == nil {
+		return HostStatus{}
+	}
+	status, err := s.store.GetHostStatus(ctx, host)
+	if err != nil {
+		return HostStatus{}
+	}
+	return status
+}
+
+// recordFailure counts a rejection or failed solve against the host and returns
+// the error to report: cause, or a *CooldownError once the breaker opens.
+func (s *Solver) recordFailure(ctx context.Context, host string, cause error) error {
+	// A cancelled caller says nothing about the host
+	if s.store == nil || host == "" || ctx.Err() != nil {
+		return cause
+	}
+
+	status := s.HostStatus(ctx, host)
+	status.Failures++
+	if status.Failures < breakerThreshold {
+		status.RejectedUntil = nil
+		_ = s.store.SetHostStatus(ctx, host, status)
+		return cause
+	}
+
+	until := time.Now().Add(breakerCooldown).UTC()
+	status.RejectedUntil = &until
+	if err := s.store.SetHostStatus(ctx, host, status); err != nil {
+		return cause
+	}
+
+	logger.Warn("anubis host suspended",
+		"module", logModule,
+		"action", "solve",
+		"resource", logResource,
+		"result", "failed",
+		"host", host,
+		"failures", status.Failures,
+		"until", until.Format(time.RFC3339),
+		"error", cause,
+	)
+	return &CooldownError{Host: host, Until: until}
+}
+
+func (s *Solver) resetBreaker(ctx context.Context, host string) {
+	if s.store == nil || host == "" {
+		return
+	}
+	if status := s.HostStatus(ctx, host); status.Failures == 0 {
+		return
+	}
+	_ = s.store.DeleteHostStatus(ctx, host)
+}
+
 func (s *Solver) cacheSolvedCookie(ctx context.Context, host, cacheKey, cookie string, expiresAt time.Time) {
 	if s.store == nil || cacheKey == "" {
 		return
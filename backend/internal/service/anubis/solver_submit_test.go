@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto/sha256"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -692,3 +693,165 @@ func TestSolveFromBody_SolveContextCanceled(t *testing.T) {
 	require.Error(t, err)
 	require.ErrorIs(t, err, context.Canceled)
 }
+
+func TestSolveFromBody_RejectionsOpenBreaker(t *testing.T) {
+	repo := newSettingsRepoStub()
+	store := anubis.NewStore(repo)
+	called := false
+	solver := newSolverWithSession(t, store, &stubSession{doFunc: func(req *azuretls.Request) (*azuretls.Response, error) {
+		called = true
+		return &azuretls.Response{StatusCode: http.StatusFound, Cookies: map[string]string{"techaro.lol-anubis": "cookie-value"}}, nil
+	}})
+	reject := []byte(`<script id="anubis_challenge" type="application/json">null</script>`)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		_, err := solver.SolveFromBody(ctx, reject, "https://Example.com/feed", nil)
+		require.ErrorIs(t, err, anubis.ErrRejected)
+		var cooldown *anubis.CooldownError
+		require.False(t, errors.As(err, &cooldown))
+	}
+	require.Equal(t, 2, solver.HostStatus(ctx, "example.com").Failures)
+
+	_, err := solver.SolveFromBody(ctx, reject, "https://example.com/feed", nil)
+	var cooldown *anubis.CooldownError
+	require.ErrorAs(t, err, &cooldown)
+	require.ErrorIs(t, err, anubis.ErrRejected)
+	require.Equal(t, "example.com", cooldown.Host)
+	require.WithinDuration(t, time.Now().Add(6*time.Hour), cooldown.Until, time.Minute)
+	require.Contains(t, err.Error(), "host rejecting automated access (cooldown until ")
+
+	// Even solvable challenges are skipped while the breaker is open
+	_, err = solver.SolveFromBody(ctx, buildChallengeBody("fast", 0, "id", "data"), "https://example.com/feed", nil)
+	require.ErrorAs(t, err, &cooldown)
+	require.False(t, called)
+	require.True(t, solver.HostStatus(ctx, "example.com").Open(time.Now()))
+
+	// Other hosts are unaffected
+	_, err = solver.SolveFromBody(ctx, buildChallengeBody("fast", 0, "id", "data"), "https://other.example.org/feed", nil)
+	require.NoError(t, err)
+	require.True(t, called)
+}
+
+func TestSolveFromBody_BreakerHalfOpenAndReset(t *testing.T) {
+	repo := newSettingsRepoStub()
+	store := anubis.NewStore(repo)
+	solver := newSolverWithSession(t, store, &stubSession{})
+	ctx := context.Background()
+
+	past := time.Now().Add(-time.Minute)
+	require.NoError(t, store.SetHostStatus(ctx, "example.com", anubis.HostStatus{Failures: 3, RejectedUntil: &past}))
+
+	// Cooldown over: one attempt is allowed, and success closes the breaker
+	cookie, err := solver.SolveFromBody(ctx, buildChallengeBody("fast", 0, "id", "data"), "https://example.com/feed", nil)
+	require.NoError(t, err)
+	require.NotEmpty(t, cookie)
+	require.Equal(t, anubis.HostStatus{}, solver.HostStatus(ctx, "example.com"))
+	require.NotContains(t, repo.data, "anubis.breaker.example.com")
+
+	// A failure right after an expired cooldown reopens it immediately
+	require.NoError(t, store.SetHostStatus(ctx, "example.com", anubis.HostStatus{Failures: 3, RejectedUntil: &past}))
+	_, err = solver.SolveFromBody(ctx, []byte(`<script id="anubis_challenge" type="application/json">null</script>`), "https://example.com/feed", nil)
+	var cooldown *anubis.CooldownError
+	require.ErrorAs(t, err, &cooldown)
+}
+
+func TestSolveFromBody_CancelledSolveNotCounted(t *testing.T) {
+	repo := newSettingsRepoStub()
+	solver := newSolverWithSession(t, anubis.NewStore(repo), &stubSession{})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := solver.SolveFromBody(ctx, buildChallengeBody("preact", 1, "id", "data"), "https://example.com/feed", nil)
+	require.ErrorIs(t, err, context.Canceled)
+	require.Zero(t, solver.HostStatus(context.Background(), "example.com").Failures)
+}
+
+func TestSolveFromBodyWithHeaders_SerializesSolvesPerHost(t *testing.T) {
+	started := make(chan struct{})
+	proceed := make(chan struct{})
+	var mu sync.Mutex
+	calls := 0
+	session := &stubSession{
+		doFunc: func(req *azuretls.Request) (*azuretls.Response, error) {
+			mu.Lock()
+			calls++
+			mu.Unlock()
+			close(started)
+			<-proceed
+			return &azuretls.Response{
+				StatusCode: http.StatusFound,
+				Cookies:    map[string]string{"techaro.lol-anubis": "cookie-a"},
+			}, nil
+		},
+	}
+
+	store := anubis.NewStore(newSettingsRepoStub())
+	solver := newSolverWithSession(t, store, session)
+	headersA := http.Header{"User-Agent": {"Profile-A/1.0"}}
+	headersB := http.Header{"User-Agent": {"Profile-B/1.0"}}
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+
+	var firstErr error
+	go func() {
+		defer wg.Done()
+		_, firstErr = solver.SolveFromBodyWithHeaders(context.Background(), buildChallengeBody("fast", 0, "id-a", "data-a"), "https://example.com/a", nil, headersA)
+	}()
+
+	<-started
+
+	var secondCookie string
+	var secondErr error
+	go func() {
+		defer wg.Done()
+		secondCookie, secondErr = solver.SolveFromBodyWithHeaders(context.Background(), buildChallengeBody("fast", 0, "id-b", "data-b"), "https://example.com/b", nil, headersB)
+	}()
+
+	// Give second goroutine time to block on the host
+	time.Sleep(50 * time.Millisecond)
+	close(proceed)
+	wg.Wait()
+
+	require.NoError(t, firstErr)
+	require.NoError(t, secondErr)
+	// The second profile reuses the host fallback instead of solving again
+	require.Equal(t, "techaro.lol-anubis=cookie-a", secondCookie)
+	require.Equal(t, 1, calls)
+	require.Equal(t, map[string]int{"example.com": 1}, store.SolveCounts(time.Now()))
+}
+
+func TestSolveFromBodyWithHeaders_HostWaitContextCanceled(t *testing.T) {
+	started := make(chan struct{})
+	proceed := make(chan struct{})
+	session := &stubSession{
+		doFunc: func(req *azuretls.Request) (*azuretls.Response, error) {
+			close(started)
+			<-proceed
+			return &azuretls.Response{
+				StatusCode: http.StatusFound,
+				Cookies:    map[string]string{"techaro.lol-anubis": "cookie-value"},
+			}, nil
+		},
+	}
+
+	solver := newSolverWithSession(t, anubis.NewStore(newSettingsRepoStub()), session)
+	body := buildChallengeBody("fast", 0, "id", "data")
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _ = solver.SolveFromBodyWithHeaders(context.Background(), body, "https://example.com/a", nil, http.Header{"User-Agent": {"Profile-A/1.0"}})
+	}()
+
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := solver.SolveFromBodyWithHeaders(ctx, body, "https://example.com/b", nil, http.Header{"User-Agent": {"Profile-B/1.0"}})
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	close(proceed)
+	<-done
+}
@@ -2,7 +2,9 @@ package anubis
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"sync"
 	"time"
 
 	"gist/backend/internal/repository"
@@ -14,16 +16,39 @@ const (
 	cookieKeyPrefix = "anubis.cookie."
 	// expiresSuffix is the suffix for cookie expiration time keys
 	expiresSuffix = ".expires"
+	// breakerKeyPrefix is the prefix for per-host circuit breaker keys in settings
+	breakerKeyPrefix = "anubis.breaker."
+	// solveWindow is how far back SolveCounts looks
+	solveWindow = time.Hour
 )
 
-// Store manages Anubis cookie persistence in the database
+// HostStatus is the circuit breaker state of a host
+type HostStatus struct {
+	// Failures counts consecutive rejections and failed solves
+	Failures int `json:"failures"`
+	// RejectedUntil is set once Failures reaches the threshold
+	RejectedUntil *time.Time `json:"rejectedUntil,omitempty"`
+}
+
+// Open reports whether solving is suspended for the host at now
+func (h HostStatus) Open(now time.Time) bool {
+	return h.RejectedUntil != nil && now.Before(*h.RejectedUntil)
+}
+
+// Store manages Anubis cookie persistence in the database.
+// Solve counts are kept in memory only; they are meant for monitoring.
 type Store struct {
 	settings repository.SettingsRepository
+	mu       sync.Mutex
+	solves   map[string][]time.Time // host -> solve times within solveWindow
 }
 
 // NewStore creates a new Anubis cookie store
 func NewStore(settings repository.SettingsRepository) *Store {
-	return &Store{settings: settings}
+	return &Store{
+		settings: settings,
+		solves:   make(map[string][]time.Time),
+	}
 }
 
 // GetCookie retrieves the cached cookie for the given host
@@ -116,3 +141,104 @@ func (s *Store) DeleteCookie(ctx context.Context, host string) error {
 
 	return nil
 }
+
+// GetHostStatus returns the circuit breaker state for the given host
+// Returns the zero status if none is stored
+func (s *Store) GetHostStatus(ctx context.Context, host string) (HostStatus, error) {
+	if s.settings == nil {
+		return HostStatus{}, nil
+	}
+	host = normalizeHost(host)
+
+	setting, err := s.settings.Get(ctx, breakerKeyPrefix+host)
+	if err != nil {
+		logger.Warn("anubis breaker read failed", "module", "service", "action", "fetch", "resource", "settings", "result", "failed", "host", host, "error", err)
+		return HostStatus{}, fmt.Errorf("get breaker: %w", err)
+	}
+	if setting == nil {
+		return HostStatus{}, nil
+	}
+
+	var status HostStatus
+	if err := json.Unmarshal([]byte(setting.Value), &status); err != nil {
+		// Invalid format, treat as closed
+		return HostStatus{}, nil
+	}
+	return status, nil
+}
+
+// SetHostStatus stores the circuit breaker state for the given host
+func (s *Store) SetHostStatus(ctx context.Context, host string, status HostStatus) error {
+	if s.settings == nil {
+		return nil
+	}
+	host = normalizeHost(host)
+
+	data, err := json.Marshal(status)
+	if err != nil {
+		return fmt.Errorf("marshal breaker: %w", err)
+	}
+	if err := s.settings.Set(ctx, breakerKeyPrefix+host, string(data)); err != nil {
+		logger.Warn("anubis breaker save failed", "module", "service", "action", "save", "resource", "settings", "result", "failed", "host", host, "error", err)
+		return fmt.Errorf("set breaker: %w", err)
+	}
+	return nil
+}
+
+// DeleteHostStatus resets the circuit breaker for the given host
+func (s *Store) DeleteHostStatus(ctx context.Context, host string) error {
+	if s.settings == nil {
+		return nil
+	}
+	host = normalizeHost(host)
+
+	if err := s.settings.Delete(ctx, breakerKeyPrefix+host); err != nil {
+		logger.Warn("anubis breaker delete failed", "module", "service", "action", "delete", "resource", "settings", "result", "failed", "host", host, "error", err)
+		return fmt.Errorf("delete breaker: %w", err)
+	}
+	return nil
+}
+
+// RecordSolve counts a challenge solved for the given host at the given time
+func (s *Store) RecordSolve(host string, at time.Time) {
+	host = normalizeHost(host)
+	if host == "" {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.solves == nil {
+		s.solves = make(map[string][]time.Time)
+	}
+	s.solves[host] = append(pruneSolves(s.solves[host], at), at)
+}
+
+// SolveCounts returns how many challenges each host made us solve in the hour before now.
+// Hosts without solves in that window are left out.
+func (s *Store) SolveCounts(now time.Time) map[string]int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	counts := make(map[string]int, len(s.solves))
+	for host, times := range s.solves {
+		times = pruneSolves(times, now)
+		if len(times) == 0 {
+			delete(s.solves, host)
+			continue
+		}
+		s.solves[host] = times
+		counts[host] = len(times)
+	}
+	return counts
+}
+
+// pruneSolves drops solve times older than solveWindow before now; times are in ascending order
+func pruneSolves(times []time.Time, now time.Time) []time.Time {
+	cutoff := now.Add(-solveWindow)
+	i := 0
+	for i < len(times) && !times[i].After(cutoff) {
+		i++
+	}
+	return times[i:]
+}
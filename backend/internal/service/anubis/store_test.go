@@ -60,6 +60,13 @@ func (s *settingsRepoStub) SetMany(ctx context.Context, values map[string]string
 	return nil
 }
 
+func (s *settingsRepoStub) SetManyVersioned(ctx context.Context, versionKey, expected string, values map[string]string) (string, bool, error) {
+	if err := s.SetMany(ctx, values); err != nil {
+		return "", false, err
+	}
+	return expected, true, nil
+}
+
 func (s *settingsRepoStub) Delete(ctx context.Context, key string) error {
 	if err := s.deleteErr[key]; err != nil {
 		return err
@@ -265,3 +272,54 @@ func TestStore_GetCookie_ExpiresFoundButCookieNotFound(t *testing.T) {
 	require.NoError(t, err)
 	require.Empty(t, cookie)
 }
+
+func TestStore_HostStatus(t *testing.T) {
+	repo := newSettingsRepoStub()
+	store := anubis.NewStore(repo)
+	ctx := context.Background()
+
+	status, err := store.GetHostStatus(ctx, "example.com")
+	require.NoError(t, err)
+	require.Equal(t, anubis.HostStatus{}, status)
+
+	until := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	require.NoError(t, store.SetHostStatus(ctx, "Example.com:443", anubis.HostStatus{Failures: 3, RejectedUntil: &until}))
+	require.Contains(t, repo.data, "anubis.breaker.example.com")
+
+	status, err = store.GetHostStatus(ctx, "example.com")
+	require.NoError(t, err)
+	require.Equal(t, 3, status.Failures)
+	require.True(t, until.Equal(*status.RejectedUntil))
+	require.True(t, status.Open(time.Now()))
+	require.False(t, status.Open(until.Add(time.Second)))
+
+	require.NoError(t, store.DeleteHostStatus(ctx, "example.com"))
+	require.NotContains(t, repo.data, "anubis.breaker.example.com")
+
+	// Invalid values read as closed
+	repo.data["anubis.breaker.example.com"] = "{bad"
+	status, err = store.GetHostStatus(ctx, "example.com")
+	require.NoError(t, err)
+	require.Equal(t, anubis.HostStatus{}, status)
+
+	repo.getErr["anubis.breaker.example.com"] = errors.New("db down")
+	_, err = store.GetHostStatus(ctx, "example.com")
+	require.Error(t, err)
+}
+
+func TestStore_SolveCounts(t *testing.T) {
+	store := anubis.NewStore(nil)
+	now := time.Now()
+
+	require.Empty(t, store.SolveCounts(now))
+
+	store.RecordSolve("Example.com:443", now.Add(-90*time.Minute))
+	store.RecordSolve("example.com", now.Add(-30*time.Minute))
+	store.RecordSolve("example.com", now.Add(-time.Minute))
+	store.RecordSolve("other.org", now.Add(-2*time.Hour))
+	store.RecordSolve("", now)
+
+	require.Equal(t, map[string]int{"example.com": 2}, store.SolveCounts(now))
+	require.Equal(t, map[string]int{"example.com": 1}, store.SolveCounts(now.Add(45*time.Minute)))
+	require.Empty(t, store.SolveCounts(now.Add(2*time.Hour)))
+}
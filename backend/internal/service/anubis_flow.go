@@ -3,20 +3,19 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net/http"
 	"sort"
 	"strings"
+	"time"
 
 	anubischallenge "gist/backend/internal/service/anubis"
 	"github.com/Noooste/azuretls-client"
 )
 
-const anubisMaxRetries = 2
-
 var (
-	errAnubisNotPage       = errors.New("anubis: not challenge page")
-	errAnubisRejected      = errors.New("anubis: upstream rejected")
-	errAnubisRetryExceeded = errors.New("anubis: retry exceeded")
+	errAnubisRetryExceeded = errors.New("anubis challenge persists")
+	errAnubisSolveFailed   = errors.New("anubis solve failed")
 )
 
 // AnubisSolver defines the minimal contract service layer needs from the solver.
@@ -32,33 +31,113 @@ func getCachedAnubisCookie(ctx context.Context, solver AnubisSolver, host string
 	return solver.GetCachedCookieWithHeaders(ctx, host, headers)
 }
 
-// trySolveAnubisChallenge centralizes challenge detection and retry guards.
+// anubisRetryOptions bounds the solves of one fetchPastAnubis call.
+type anubisRetryOptions struct {
+	// maxRetries is how many challenges are solved before giving up.
+	maxRetries int
+	// delay is the wait between a solve and the fetch that follows it.
+	delay time.Duration
+}
+
+// anubisRetryOptionsFrom reads the retry options from the general settings,
+// falling back to the defaults without settings.
+func anubisRetryOptionsFrom(ctx context.Context, settings SettingsService) anubisRetryOptions {
+	opts := anubisRetryOptions{maxRetries: DefaultAnubisMaxRetries}
+	general := loadGeneralSettings(ctx, settings)
+	if general == nil {
+		return opts
+	}
+	if general.AnubisMaxRetries > 0 {
+		opts.maxRetries = general.AnubisMaxRetries
+	}
+	if general.AnubisRetryDelayMs > 0 {
+		opts.delay = time.Duration(general.AnubisRetryDelayMs) * time.Millisecond
+	}
+	return opts
+}
+
+// anubisAttempt is one fetched response, its body already read, together with
+// the request headers it was sent with. resp is nil for azuretls responses.
+type anubisAttempt struct {
+	resp           *http.Response
+	statusCode     int
+	body           []byte
+	cookies        []*http.Cookie
+	requestHeaders http.Header
+}
+
+func newHTTPAnubisAttempt(resp *http.Response, body []byte, requestHeaders http.Header) anubisAttempt {
+	return anubisAttempt{resp: resp, statusCode: resp.StatusCode, body: body, cookies: resp.Cookies(), requestHeaders: requestHeaders}
+}
+
+func newAzureAnubisAttempt(resp *azuretls.Response, requestHeaders http.Header) anubisAttempt {
+	return anubisAttempt{statusCode: resp.StatusCode, body: resp.Body, cookies: cookiesFromMap(resp.Cookies), requestHeaders: requestHeaders}
+}
+
+// anubisCooldown reads as the solver's cooldown notice and matches
+// ErrAnubisRejected, so callers report it like any other rejection.
+type anubisCooldown struct{ err error }
+
+func (e anubisCooldown) Error() string        { return e.err.Error() }
+func (e anubisCooldown) Unwrap() error        { return e.err }
+func (e anubisCooldown) Is(target error) bool { return target == ErrAnubisRejected }
+
+// fetchPastAnubis calls fetch and, while the body is an Anubis page, solves it
+// and calls fetch again with the solved cookie and solved set. fetch should use
+// a new client each time so the retry doesn't reuse the challenged connection.
+// The solver caches the cookie scoped by the request headers, so later fetches
+// with the same fingerprint find it via getCachedAnubisCookie and skip solving.
+// Responses with an HTTP error status are returned without looking at the body.
 //
-// Return contract:
-//   - errAnubisNotPage: body is not an Anubis page, caller should continue normal parsing.
-//   - errAnubisRejected: body is an Anubis rejection page (challenge is not solvable).
-//   - errAnubisRetryExceeded: retry budget exhausted.
-//   - nil error with non-empty cookie: challenge solved successfully.
-//   - other error: solver failed while solving/submitting.
-func trySolveAnubisChallenge(
+// Errors from fetch are returned as is. Rejection pages, which go to the solver
+// too so they count towards its circuit breaker, match ErrAnubisRejected; a host
+// whose breaker is open also matches isAnubisCooldown. A challenge still served
+// after opts.maxRetries solves matches errAnubisRetryExceeded, and a failing
+// solver errAnubisSolveFailed. The int is the number of solves done.
+func fetchPastAnubis(
 	ctx context.Context,
 	solver AnubisSolver,
-	body []byte,
 	originalURL string,
-	initialCookies []*http.Cookie,
-	requestHeaders http.Header,
-	retryCount int,
-) (string, error) {
-	if solver == nil || !anubischallenge.IsAnubisPage(body) {
-		return "", errAnubisNotPage
-	}
-	if !anubischallenge.IsAnubisChallenge(body) {
-		return "", errAnubisRejected
-	}
-	if retryCount >= anubisMaxRetries {
-		return "", errAnubisRetryExceeded
+	opts anubisRetryOptions,
+	fetch func(cookie string, solved bool) (anubisAttempt, error),
+) (anubisAttempt, int, error) {
+	cookie := ""
+	for retryCount := 0; ; retryCount++ {
+		if retryCount > 0 && opts.delay > 0 {
+			select {
+			case <-time.After(opts.delay):
+			case <-ctx.Done():
+				return anubisAttempt{}, retryCount, ctx.Err()
+			}
+		}
+
+		attempt, err := fetch(cookie, retryCount > 0)
+		if err != nil {
+			return anubisAttempt{}, retryCount, err
+		}
+		if solver == nil || attempt.statusCode >= http.StatusBadRequest || !anubischallenge.IsAnubisPage(attempt.body) {
+			return attempt, retryCount, nil
+		}
+		if anubischallenge.IsAnubisChallenge(attempt.body) && retryCount >= opts.maxRetries {
+			return anubisAttempt{}, retryCount, fmt.Errorf("%w after %d retries", errAnubisRetryExceeded, retryCount)
+		}
+
+		cookie, err = solver.SolveFromBodyWithHeaders(ctx, attempt.body, originalURL, attempt.cookies, attempt.requestHeaders)
+		switch {
+		case isAnubisCooldown(err):
+			return anubisAttempt{}, retryCount, anubisCooldown{err: err}
+		case errors.Is(err, anubischallenge.ErrRejected), err == nil && cookie == "":
+			return anubisAttempt{}, retryCount, ErrAnubisRejected
+		case err != nil:
+			return anubisAttempt{}, retryCount, fmt.Errorf("%w: %w", errAnubisSolveFailed, err)
+		}
 	}
-	return solver.SolveFromBodyWithHeaders(ctx, body, originalURL, initialCookies, requestHeaders)
+}
+
+// isAnubisCooldown reports whether err comes from a host whose circuit breaker is open.
+func isAnubisCooldown(err error) bool {
+	var cooldown *anubischallenge.CooldownError
+	return errors.As(err, &cooldown)
 }
 
 func orderedHeadersToHTTPHeader(headers azuretls.OrderedHeaders) http.Header {
@@ -0,0 +1,139 @@
+package service_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"gist/backend/internal/model"
+	"gist/backend/internal/repository/mock"
+	"gist/backend/internal/service"
+	"gist/backend/pkg/network"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// anubisStubbornServer serves the Anubis challenge to the first challenges
+// requests and content to the rest, counting requests.
+func anubisStubbornServer(t *testing.T, challenges int32, content http.HandlerFunc) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) <= challenges {
+			_, _ = w.Write([]byte(anubisChallengePage))
+			return
+		}
+		content(w, r)
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+// anubisCallSite fetches from serverURL past Anubis the way one service does.
+type anubisCallSite struct {
+	name    string
+	content http.HandlerFunc
+	fetch   func(t *testing.T, serverURL string, settings service.SettingsService, solver service.AnubisSolver) error
+}
+
+func anubisCallSites(t *testing.T) []anubisCallSite {
+	iconData := pngBytes(t, 2, 2)
+	writeBody := func(body []byte) http.HandlerFunc {
+		return func(w http.ResponseWriter, _ *http.Request) { _, _ = w.Write(body) }
+	}
+	clientFactory := network.NewClientFactoryForTest(&http.Client{})
+
+	return []anubisCallSite{
+		{
+			name: "refresh",
+			// Not modified keeps the refresh from needing the entry repository
+			content: func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusNotModified) },
+			fetch: func(t *testing.T, serverURL string, settings service.SettingsService, solver service.AnubisSolver) error {
+				ctrl := gomock.NewController(t)
+				mockFeeds := mock.NewMockFeedRepository(ctrl)
+				mockFeeds.EXPECT().UpdateErrorMessage(gomock.Any(), int64(1), gomock.Any()).Return(nil).AnyTimes()
+				mockFeeds.EXPECT().UpdateNotModifiedStreak(gomock.Any(), int64(1), 1).Return(nil).AnyTimes()
+				svc := service.NewRefreshService(mockFeeds, mock.NewMockEntryRepository(ctrl), nil, settings, nil, nil, clientFactory, solver, nil, nil, nil, nil)
+				return service.RefreshFeedWithUAForTest(svc, context.Background(), model.Feed{ID: 1, URL: serverURL + "/rss", Title: "Feed"}, "UA-Test")
+			},
+		},
+		{
+			name:    "feed preview",
+			content: writeBody([]byte(sampleRSS)),
+			fetch: func(t *testing.T, serverURL string, settings service.SettingsService, solver service.AnubisSolver) error {
+				ctrl := gomock.NewController(t)
+				svc := service.NewFeedService(mock.NewMockFeedRepository(ctrl), mock.NewMockFolderRepository(ctrl), mock.NewMockEntryRepository(ctrl), nil, settings, clientFactory, solver, nil, nil, nil)
+				_, err := svc.Preview(context.Background(), serverURL+"/rss")
+				return err
+			},
+		},
+		{
+			name:    "icon",
+			content: writeBody(iconData),
+			fetch: func(t *testing.T, serverURL string, settings service.SettingsService, solver service.AnubisSolver) error {
+				svc := service.NewIconService(t.TempDir(), &feedRepoStub{}, clientFactory, solver, settings)
+				return service.DownloadIconForTest(svc, context.Background(), serverURL+"/icon.png")
+			},
+		},
+		{
+			name:    "readability",
+			content: writeBody([]byte(`<html><body><article><p>Hello</p></article></body></html>`)),
+			fetch: func(t *testing.T, serverURL string, settings service.SettingsService, solver service.AnubisSolver) error {
+				svc := service.NewReadabilityService(nil, clientFactory, solver, settings, nil, nil)
+				defer svc.Close()
+				_, err := service.ReadabilityFetchWithChromeForTest(svc, context.Background(), serverURL+"/post")
+				return err
+			},
+		},
+		{
+			name:    "proxy",
+			content: writeBody(testPNGData),
+			fetch: func(t *testing.T, serverURL string, settings service.SettingsService, solver service.AnubisSolver) error {
+				svc := service.NewProxyService(clientFactory, solver, settings)
+				_, err := svc.FetchImage(context.Background(), serverURL+"/img.png", "")
+				return err
+			},
+		},
+	}
+}
+
+func TestAnubisRetry_SameBudgetAtEveryCallSite(t *testing.T) {
+	for _, site := range anubisCallSites(t) {
+		t.Run(site.name, func(t *testing.T) {
+			// Two challenges fit the default budget of two solves
+			server, requests := anubisStubbornServer(t, service.DefaultAnubisMaxRetries, site.content)
+			solver := &anubisSolverStub{}
+			require.NoError(t, site.fetch(t, server.URL, nil, solver))
+			require.Equal(t, service.DefaultAnubisMaxRetries, solver.solves)
+			require.EqualValues(t, service.DefaultAnubisMaxRetries+1, requests.Load())
+
+			// A third challenge exceeds it without another solve
+			server, requests = anubisStubbornServer(t, service.DefaultAnubisMaxRetries+1, site.content)
+			solver = &anubisSolverStub{}
+			err := site.fetch(t, server.URL, nil, solver)
+			require.ErrorContains(t, err, "anubis challenge persists after 2 retries")
+			require.Equal(t, service.DefaultAnubisMaxRetries, solver.solves)
+			require.EqualValues(t, service.DefaultAnubisMaxRetries+1, requests.Load())
+		})
+	}
+}
+
+func TestAnubisRetry_HonorsMaxRetriesSetting(t *testing.T) {
+	settings := &settingsServiceStub{general: &service.GeneralSettings{AnubisMaxRetries: 3, AnubisRetryDelayMs: 1}}
+	for _, site := range anubisCallSites(t) {
+		t.Run(site.name, func(t *testing.T) {
+			server, requests := anubisStubbornServer(t, 3, site.content)
+			solver := &anubisSolverStub{}
+			require.NoError(t, site.fetch(t, server.URL, settings, solver))
+			require.Equal(t, 3, solver.solves)
+			require.EqualValues(t, 4, requests.Load())
+
+			server, _ = anubisStubbornServer(t, 4, site.content)
+			err := site.fetch(t, server.URL, settings, &anubisSolverStub{})
+			require.ErrorContains(t, err, "anubis challenge persists after 3 retries")
+		})
+	}
+}
@@ -0,0 +1,132 @@
+//go:generate mockgen -source=$GOFILE -destination=mock/$GOFILE -package=mock
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gist/backend/internal/hashutil"
+	"gist/backend/internal/model"
+	"gist/backend/internal/repository"
+	"gist/backend/pkg/logger"
+)
+
+// APITokenPrefix marks personal access tokens so they can be told apart from JWTs.
+const APITokenPrefix = "gist_pat_"
+
+// lastUsedUpdateInterval throttles last_used_at writes to avoid a write per request.
+const lastUsedUpdateInterval = time.Minute
+
+// CreatedAPIToken is returned once at creation and carries the plaintext token.
+type CreatedAPIToken struct {
+	Token    string
+	APIToken model.APIToken
+}
+
+// APITokenService manages personal access tokens for non-browser clients.
+type APITokenService interface {
+	// Create issues a new token. The plaintext is only returned here.
+	Create(ctx context.Context, name string, expiresAt *time.Time) (*CreatedAPIToken, error)
+	// List returns all tokens (without plaintext).
+	List(ctx context.Context) ([]model.APIToken, error)
+	// Delete revokes a token immediately.
+	Delete(ctx context.Context, id int64) error
+	// Validate checks a plaintext token and records its use.
+	Validate(ctx context.Context, token string) (bool, error)
+}
+
+type apiTokenService struct {
+	repo repository.APITokenRepository
+}
+
+// NewAPITokenService creates a new API token service.
+func NewAPITokenService(repo repository.APITokenRepository) APITokenService {
+	return &apiTokenService{repo: repo}
+}
+
+// IsAPIToken reports whether the bearer value looks like a personal access token.
+func IsAPIToken(token string) bool {
+	return strings.HasPrefix(token, APITokenPrefix)
+}
+
+// Create issues a new token. The plaintext is only returned here.
+func (s *apiTokenService) Create(ctx context.Context, name string, expiresAt *time.Time) (*CreatedAPIToken, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, ErrInvalid
+	}
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return nil, ErrInvalid
+	}
+
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, fmt.Errorf("generate api token: %w", err)
+	}
+	plaintext := APITokenPrefix + hex.EncodeToString(raw)
+
+	token, err := s.repo.Create(ctx, name, hashutil.SHA256Hex(plaintext), expiresAt)
+	if err != nil {
+		logger.Error("api token create failed", "module", "service", "action", "create", "resource", "api_token", "result", "failed", "error", err)
+		return nil, fmt.Errorf("create api token: %w", err)
+	}
+
+	logger.Info("api token created", "module", "service", "action", "create", "resource", "api_token", "result", "ok", "token_id", token.ID)
+	return &CreatedAPIToken{Token: plaintext, APIToken: *token}, nil
+}
+
+// List returns all tokens (without plaintext).
+func (s *apiTokenService) List(ctx context.Context) ([]model.APIToken, error) {
+	tokens, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list api tokens: %w", err)
+	}
+	return tokens, nil
+}
+
+// Delete revokes a token immediately.
+func (s *apiTokenService) Delete(ctx context.Context, id int64) error {
+	if err := s.repo.Delete(ctx, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("delete api token: %w", err)
+	}
+	logger.Info("api token revoked", "module", "service", "action", "delete", "resource", "api_token", "result", "ok", "token_id", id)
+	return nil
+}
+
+// Validate checks a plaintext token and records its use.
+func (s *apiTokenService) Validate(ctx context.Context, token string) (bool, error) {
+	if !IsAPIToken(token) {
+		return false, ErrInvalidToken
+	}
+
+	stored, err := s.repo.GetByHash(ctx, hashutil.SHA256Hex(token))
+	if err != nil {
+		return false, fmt.Errorf("get api token: %w", err)
+	}
+	if stored == nil {
+		return false, ErrInvalidToken
+	}
+
+	now := time.Now().UTC()
+	if stored.ExpiresAt != nil && !now.Before(*stored.ExpiresAt) {
+		return false, ErrInvalidToken
+	}
+
+	if stored.LastUsedAt == nil || now.Sub(*stored.LastUsedAt) >= lastUsedUpdateInterval {
+		if err := s.repo.UpdateLastUsed(ctx, stored.ID, now); err != nil {
+			// Not fatal: the token is still valid
+			logger.Warn("api token last used update failed", "module", "service", "action", "update", "resource", "api_token", "result", "failed", "token_id", stored.ID, "error", err)
+		}
+	}
+
+	return true, nil
+}
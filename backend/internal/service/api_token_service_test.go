@@ -0,0 +1,117 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	"gist/backend/internal/hashutil"
+	"gist/backend/internal/model"
+	"gist/backend/internal/repository/mock"
+	"gist/backend/internal/service"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestAPITokenService_Create(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mock.NewMockAPITokenRepository(ctrl)
+	svc := service.NewAPITokenService(repo)
+
+	var storedHash string
+	repo.EXPECT().
+		Create(gomock.Any(), "cli", gomock.Any(), nil).
+		DoAndReturn(func(_ context.Context, name, hash string, _ *time.Time) (*model.APIToken, error) {
+			storedHash = hash
+			return &model.APIToken{ID: 1, Name: name, TokenHash: hash}, nil
+		})
+
+	created, err := svc.Create(context.Background(), " cli ", nil)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(created.Token, service.APITokenPrefix))
+	require.Equal(t, hashutil.SHA256Hex(created.Token), storedHash, "only the hash is stored")
+}
+
+func TestAPITokenService_Create_Invalid(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mock.NewMockAPITokenRepository(ctrl)
+	svc := service.NewAPITokenService(repo)
+
+	_, err := svc.Create(context.Background(), "", nil)
+	require.ErrorIs(t, err, service.ErrInvalid)
+
+	past := time.Now().Add(-time.Hour)
+	_, err = svc.Create(context.Background(), "cli", &past)
+	require.ErrorIs(t, err, service.ErrInvalid)
+}
+
+func TestAPITokenService_Validate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mock.NewMockAPITokenRepository(ctrl)
+	svc := service.NewAPITokenService(repo)
+	token := service.APITokenPrefix + "abc"
+	hash := hashutil.SHA256Hex(token)
+
+	t.Run("records first use", func(t *testing.T) {
+		repo.EXPECT().GetByHash(gomock.Any(), hash).Return(&model.APIToken{ID: 1}, nil)
+		repo.EXPECT().UpdateLastUsed(gomock.Any(), int64(1), gomock.Any()).Return(nil)
+
+		ok, err := svc.Validate(context.Background(), token)
+		require.NoError(t, err)
+		require.True(t, ok)
+	})
+
+	t.Run("throttles last used writes", func(t *testing.T) {
+		recent := time.Now().Add(-10 * time.Second)
+		repo.EXPECT().GetByHash(gomock.Any(), hash).Return(&model.APIToken{ID: 1, LastUsedAt: &recent}, nil)
+
+		ok, err := svc.Validate(context.Background(), token)
+		require.NoError(t, err)
+		require.True(t, ok)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		expired := time.Now().Add(-time.Minute)
+		repo.EXPECT().GetByHash(gomock.Any(), hash).Return(&model.APIToken{ID: 1, ExpiresAt: &expired}, nil)
+
+		ok, err := svc.Validate(context.Background(), token)
+		require.ErrorIs(t, err, service.ErrInvalidTokenHelper)
+		require.False(t, ok)
+	})
+
+	t.Run("revoked", func(t *testing.T) {
+		repo.EXPECT().GetByHash(gomock.Any(), hash).Return(nil, nil)
+
+		ok, err := svc.Validate(context.Background(), token)
+		require.ErrorIs(t, err, service.ErrInvalidTokenHelper)
+		require.False(t, ok)
+	})
+
+	t.Run("not an api token", func(t *testing.T) {
+		ok, err := svc.Validate(context.Background(), "jwt.value.here")
+		require.ErrorIs(t, err, service.ErrInvalidTokenHelper)
+		require.False(t, ok)
+	})
+}
+
+func TestAPITokenService_Delete_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mock.NewMockAPITokenRepository(ctrl)
+	svc := service.NewAPITokenService(repo)
+
+	repo.EXPECT().Delete(gomock.Any(), int64(7)).Return(sql.ErrNoRows)
+
+	err := svc.Delete(context.Background(), 7)
+	require.ErrorIs(t, err, service.ErrNotFound)
+}
@@ -0,0 +1,290 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html/charset"
+
+	"gist/backend/internal/model"
+	"gist/backend/internal/urlutil"
+	"gist/backend/pkg/logger"
+	"gist/backend/pkg/network"
+)
+
+const (
+	// DefaultArchivePages is how many archive pages a backfill follows when
+	// none is asked for.
+	DefaultArchivePages = 5
+	// MaxArchivePages caps the archive pages one backfill follows.
+	MaxArchivePages = 50
+)
+
+// States of an archive backfill.
+const (
+	ArchiveBackfillRunning = "running"
+	ArchiveBackfillDone    = "done"
+	ArchiveBackfillFailed  = "failed"
+)
+
+var (
+	// errArchiveLoop ends a backfill whose pages link back to one already fetched.
+	errArchiveLoop = errors.New("archive pages link in a loop")
+	// errArchiveEmptyPage ends a backfill at a page without items, which would
+	// otherwise be followed for nothing.
+	errArchiveEmptyPage = errors.New("archive page has no items")
+)
+
+// ArchiveBackfill is the state of the crawl of a feed's RFC 5005 archive: the
+// pages its documents link to with rel="next", saved like a refresh saves
+// the feed itself. Regular refreshes never follow these links.
+type ArchiveBackfill struct {
+	FeedID int64
+	Status string
+	// MaxPages is how many pages beyond the feed document are followed.
+	MaxPages int
+	// Pages counts the documents fetched, the feed document included.
+	Pages      int
+	Items      int
+	NewEntries int
+	// HasMore is set when the crawl stopped at MaxPages with pages left.
+	HasMore    bool
+	Error      string
+	StartedAt  time.Time
+	FinishedAt *time.Time
+}
+
+// ArchiveBackfillOptions configure one archive backfill.
+type ArchiveBackfillOptions struct {
+	// MaxPages is the number of pages to follow; 0 means DefaultArchivePages.
+	MaxPages int
+	// MarkRead saves the archived items read.
+	MarkRead bool
+}
+
+func (s *refreshService) StartArchiveBackfill(ctx context.Context, feedID int64, opts ArchiveBackfillOptions) (ArchiveBackfill, error) {
+	if opts.MaxPages == 0 {
+		opts.MaxPages = DefaultArchivePages
+	}
+	if opts.MaxPages < 0 || opts.MaxPages > MaxArchivePages {
+		return ArchiveBackfill{}, fmt.Errorf("archive pages must be between 1 and %d: %w", MaxArchivePages, ErrInvalid)
+	}
+	feed, err := s.feeds.GetByID(ctx, feedID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ArchiveBackfill{}, ErrNotFound
+		}
+		return ArchiveBackfill{}, fmt.Errorf("get feed: %w", err)
+	}
+	if IsStaticFeed(feed) {
+		return ArchiveBackfill{}, fmt.Errorf("static feeds have no archive to fetch: %w", ErrInvalid)
+	}
+
+	s.mu.Lock()
+	if job, ok := s.archiveJobs[feedID]; ok && job.Status == ArchiveBackfillRunning {
+		s.mu.Unlock()
+		return ArchiveBackfill{}, ErrConflict
+	}
+	job := &ArchiveBackfill{FeedID: feedID, Status: ArchiveBackfillRunning, MaxPages: opts.MaxPages, StartedAt: time.Now()}
+	s.archiveJobs[feedID] = job
+	snapshot := *job
+	s.mu.Unlock()
+
+	logger.Info("archive backfill started", "module", "service", "action", "fetch", "resource", "feed", "result", "ok", "feed_id", feedID, "max_pages", opts.MaxPages)
+	go s.runArchiveBackfill(feed, opts, job)
+	return snapshot, nil
+}
+
+func (s *refreshService) GetArchiveBackfill(ctx context.Context, feedID int64) (*ArchiveBackfill, error) {
+	if _, err := s.feeds.GetByID(ctx, feedID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("get feed: %w", err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job, ok := s.archiveJobs[feedID]
+	if !ok {
+		return nil, nil
+	}
+	snapshot := *job
+	return &snapshot, nil
+}
+
+// runArchiveBackfill crawls the archive of feed in the background until
+// Close, keeping job up to date as pages come in.
+func (s *refreshService) runArchiveBackfill(feed model.Feed, opts ArchiveBackfillOptions, job *ArchiveBackfill) {
+	err := s.crawlArchive(s.closed, feed, opts, job)
+
+	s.mu.Lock()
+	now := time.Now()
+	job.FinishedAt = &now
+	job.Status = ArchiveBackfillDone
+	if err != nil {
+		job.Status = ArchiveBackfillFailed
+		job.Error = err.Error()
+	}
+	snapshot := *job
+	s.mu.Unlock()
+
+	if err != nil {
+		logger.Warn("archive backfill failed", "module", "service", "action", "fetch", "resource", "feed", "result", "failed", "feed_id", feed.ID, "pages", snapshot.Pages, "new", snapshot.NewEntries, "error", err)
+		return
+	}
+	logger.Info("archive backfill completed", "module", "service", "action", "fetch", "resource", "feed", "result", "ok", "feed_id", feed.ID, "pages", snapshot.Pages, "new", snapshot.NewEntries, "has_more", snapshot.HasMore)
+}
+
+// crawlArchive fetches the feed document, then follows its rel="next" links
+// for up to opts.MaxPages pages, saving the items of each page as it goes.
+func (s *refreshService) crawlArchive(ctx context.Context, feed model.Feed, opts ArchiveBackfillOptions, job *ArchiveBackfill) error {
+	general := loadGeneralSettings(ctx, s.settings)
+	revisionLimit := entryRevisionLimit(general)
+	backfill := InitialBackfill{MarkRead: opts.MarkRead}
+	userAgent := s.defaultUserAgent(ctx, feed)
+	if feed.PreferredUserAgent == model.FeedUserAgentFallback {
+		if fallback, ok := s.alternateUserAgent(ctx, feed, model.FeedUserAgentDefault); ok {
+			userAgent = fallback
+		}
+	}
+
+	visited := map[string]int{urlutil.CanonicalFeedURL(feed.URL): 0}
+	pageURL := feed.URL
+	for page := 0; ; page++ {
+		body, err := s.fetchArchivePage(ctx, feed, pageURL, userAgent)
+		if err != nil {
+			return fmt.Errorf("page %d: %w", page, err)
+		}
+		parsed, err := parseFeed(body)
+		if err != nil {
+			return fmt.Errorf("page %d: %w", page, err)
+		}
+		if page > 0 && len(parsed.Items) == 0 {
+			return fmt.Errorf("page %d (%s): %w", page, pageURL, errArchiveEmptyPage)
+		}
+
+		entries := prepareEntries(feed, parsed.Items, backfill, general, &ingestReportBuilder{})
+		newCount, _, err := s.storeEntries(ctx, feed, entries, revisionLimit)
+		if err != nil {
+			return fmt.Errorf("page %d: save entries: %w", page, err)
+		}
+		s.evictOverCap(ctx, feed)
+
+		next := nextPageURL(pageURL, parsed.FeedType, body)
+		s.mu.Lock()
+		job.Pages++
+		job.Items += len(parsed.Items)
+		job.NewEntries += newCount
+		job.HasMore = next != "" && page >= opts.MaxPages
+		s.mu.Unlock()
+		logger.Debug("archive page saved", "module", "service", "action", "save", "resource", "entry", "result", "ok", "feed_id", feed.ID, "page", page, "items", len(parsed.Items), "new", newCount)
+
+		if next == "" || page >= opts.MaxPages {
+			return nil
+		}
+		key := urlutil.CanonicalFeedURL(next)
+		if seen, ok := visited[key]; ok {
+			return fmt.Errorf("page %d links back to page %d (%s): %w", page, seen, next, errArchiveLoop)
+		}
+		visited[key] = page + 1
+		pageURL = next
+	}
+}
+
+// fetchArchivePage fetches one archive page within the host's rate limits,
+// past Anubis challenges, and returns its body.
+func (s *refreshService) fetchArchivePage(ctx context.Context, feed model.Feed, pageURL string, userAgent feedUserAgent) ([]byte, error) {
+	if host := network.ExtractHost(pageURL); host != "" {
+		if err := s.hosts.acquireSemaphore(ctx, host); err != nil {
+			return nil, err
+		}
+		defer s.hosts.releaseSemaphore(host)
+		if err := s.hosts.waitForInterval(ctx, host); err != nil {
+			return nil, err
+		}
+		s.hosts.recordRequest(host)
+	}
+
+	// Archive pages are fetched whole: the feed's validators are for its URL
+	request := feed
+	request.URL, request.ETag, request.LastModified = pageURL, nil, nil
+	attempt, _, err := fetchPastAnubis(ctx, s.anubis, pageURL, anubisRetryOptionsFrom(ctx, s.settings), func(cookie string, _ bool) (anubisAttempt, error) {
+		return s.fetchFeed(ctx, request, userAgent, cookie)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if attempt.statusCode >= http.StatusBadRequest {
+		return nil, fmt.Errorf("HTTP %d", attempt.statusCode)
+	}
+	return attempt.body, nil
+}
+
+// nextPageURL returns the absolute http(s) URL of the page after pageURL:
+// the feed-level link with rel="next" of RSS and Atom documents (RFC 5005),
+// or next_url of JSON Feeds. It is empty on the last page.
+func nextPageURL(pageURL, feedType string, body []byte) string {
+	var next string
+	if feedType == "json" {
+		var doc struct {
+			NextURL string `json:"next_url"`
+		}
+		if json.Unmarshal(bytes.TrimPrefix(body, []byte("\ufeff")), &doc) == nil {
+			next = doc.NextURL
+		}
+	} else {
+		next = nextLinkHref(body)
+	}
+	return resolvePageURL(pageURL, next)
+}
+
+// nextLinkHref returns the href of the first <link rel="next"> outside items
+// and entries, whatever namespace it is in (atom:link in RSS channels).
+func nextLinkHref(body []byte) string {
+	decoder := xml.NewDecoder(bytes.NewReader(body))
+	decoder.Strict = false
+	decoder.CharsetReader = charset.NewReaderLabel
+
+	depth := 0
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return ""
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			name := strings.ToLower(t.Name.Local)
+			if name == "item" || name == "entry" {
+				depth++
+				continue
+			}
+			if depth > 0 || name != "link" {
+				continue
+			}
+			var rel, href string
+			for _, attr := range t.Attr {
+				switch strings.ToLower(attr.Name.Local) {
+				case "rel":
+					rel = strings.ToLower(strings.TrimSpace(attr.Value))
+				case "href":
+					href = strings.TrimSpace(attr.Value)
+				}
+			}
+			if rel == "next" && href != "" {
+				return href
+			}
+		case xml.EndElement:
+			if name := strings.ToLower(t.Name.Local); (name == "item" || name == "entry") && depth > 0 {
+				depth--
+			}
+		}
+	}
+}
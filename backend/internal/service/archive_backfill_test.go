@@ -0,0 +1,192 @@
+package service_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"gist/backend/internal/model"
+	"gist/backend/internal/repository"
+	"gist/backend/internal/repository/testutil"
+	"gist/backend/internal/service"
+	"gist/backend/pkg/network"
+)
+
+// archiveRSSPage is an RSS page with one item per id, linking to next unless it is empty.
+func archiveRSSPage(next string, ids ...int) string {
+	doc := `<?xml version="1.0"?><rss version="2.0" xmlns:atom="http://www.w3.org/2005/Atom"><channel><title>Blog</title><link>https://example.com</link>`
+	if next != "" {
+		doc += `<atom:link rel="next" href="` + next + `"/>`
+	}
+	for _, id := range ids {
+		doc += fmt.Sprintf(`<item><title>Post %d</title><link>https://example.com/%d</link><guid>%d</guid></item>`, id, id, id)
+	}
+	return doc + `</channel></rss>`
+}
+
+// waitArchiveBackfill waits for the backfill of feedID to finish and returns it.
+func waitArchiveBackfill(t *testing.T, svc service.RefreshService, feedID int64) service.ArchiveBackfill {
+	t.Helper()
+	var job *service.ArchiveBackfill
+	require.Eventually(t, func() bool {
+		var err error
+		job, err = svc.GetArchiveBackfill(context.Background(), feedID)
+		require.NoError(t, err)
+		return job != nil && job.Status != service.ArchiveBackfillRunning
+	}, 5*time.Second, 10*time.Millisecond)
+	return *job
+}
+
+func TestRefreshService_ArchiveBackfill(t *testing.T) {
+	pages := map[string]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := pages[r.URL.RequestURI()]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	pages["/feed"] = archiveRSSPage("/feed?page=2", 5, 4)
+	// Atom pages use a link element of their own namespace
+	pages["/feed?page=2"] = `<?xml version="1.0"?><feed xmlns="http://www.w3.org/2005/Atom"><title>Blog</title>
+<link rel="self" href="/feed?page=2"/><link rel="next" href="` + server.URL + `/feed?page=3"/>
+<entry><title>Post 3</title><id>3</id><link href="https://example.com/3"/><updated>2024-01-03T00:00:00Z</updated>
+<link rel="next" href="/ignored"/></entry></feed>`
+	pages["/feed?page=3"] = archiveRSSPage("/feed?page=4", 2)
+	pages["/feed?page=4"] = archiveRSSPage("", 1)
+
+	database := testutil.NewTestDB(t)
+	entries := repository.NewEntryRepository(database, nil, nil)
+	svc := service.NewRefreshService(repository.NewFeedRepository(database, nil, nil), entries, nil, nil, nil, nil, network.NewClientFactoryForTest(&http.Client{}), nil, nil, nil, nil, nil)
+	defer svc.Close()
+	ctx := context.Background()
+	feedID := testutil.SeedFeed(t, database, model.Feed{Title: "Blog", URL: server.URL + "/feed"})
+
+	job, err := svc.GetArchiveBackfill(ctx, feedID)
+	require.NoError(t, err)
+	require.Nil(t, job)
+
+	started, err := svc.StartArchiveBackfill(ctx, feedID, service.ArchiveBackfillOptions{MaxPages: 2, MarkRead: true})
+	require.NoError(t, err)
+	require.Equal(t, service.ArchiveBackfillRunning, started.Status)
+	require.Equal(t, 2, started.MaxPages)
+
+	done := waitArchiveBackfill(t, svc, feedID)
+	require.Equal(t, service.ArchiveBackfillDone, done.Status, done.Error)
+	require.Equal(t, 3, done.Pages)
+	require.Equal(t, 4, done.Items)
+	require.Equal(t, 4, done.NewEntries)
+	require.True(t, done.HasMore)
+	require.NotNil(t, done.FinishedAt)
+
+	stored, err := entries.List(ctx, repository.EntryListFilter{FeedID: &feedID})
+	require.NoError(t, err)
+	require.Len(t, stored, 4)
+	for _, entry := range stored {
+		require.True(t, entry.Read)
+	}
+
+	// A second run goes all the way; stored entries are matched by hash
+	_, err = svc.StartArchiveBackfill(ctx, feedID, service.ArchiveBackfillOptions{})
+	require.NoError(t, err)
+	done = waitArchiveBackfill(t, svc, feedID)
+	require.Equal(t, service.ArchiveBackfillDone, done.Status, done.Error)
+	require.Equal(t, service.DefaultArchivePages, done.MaxPages)
+	require.Equal(t, 4, done.Pages)
+	require.Equal(t, 1, done.NewEntries)
+	require.False(t, done.HasMore)
+}
+
+func TestRefreshService_ArchiveBackfill_StopsOnLoopsAndEmptyPages(t *testing.T) {
+	pages := map[string]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(pages[r.URL.RequestURI()]))
+	}))
+	defer server.Close()
+
+	pages["/loop"] = archiveRSSPage("/loop?page=2", 1)
+	pages["/loop?page=2"] = archiveRSSPage("/loop/", 2)
+	pages["/empty"] = archiveRSSPage("/empty?page=2", 1)
+	pages["/empty?page=2"] = archiveRSSPage("/empty?page=3")
+	pages["/json"] = `{"version":"https://jsonfeed.org/version/1.1","title":"Blog","next_url":"/json?page=2","items":[{"id":"1","url":"https://example.com/1"}]}`
+	pages["/json?page=2"] = `{"version":"https://jsonfeed.org/version/1.1","title":"Blog","next_url":"/json?page=2","items":[{"id":"2","url":"https://example.com/2"}]}`
+
+	database := testutil.NewTestDB(t)
+	svc := service.NewRefreshService(repository.NewFeedRepository(database, nil, nil), repository.NewEntryRepository(database, nil, nil), nil, nil, nil, nil, network.NewClientFactoryForTest(&http.Client{}), nil, nil, nil, nil, nil)
+	defer svc.Close()
+	ctx := context.Background()
+
+	loopID := testutil.SeedFeed(t, database, model.Feed{Title: "Loop", URL: server.URL + "/loop"})
+	_, err := svc.StartArchiveBackfill(ctx, loopID, service.ArchiveBackfillOptions{})
+	require.NoError(t, err)
+	done := waitArchiveBackfill(t, svc, loopID)
+	require.Equal(t, service.ArchiveBackfillFailed, done.Status)
+	require.Contains(t, done.Error, "page 1 links back to page 0")
+	require.Equal(t, 2, done.Pages)
+	require.Equal(t, 2, done.NewEntries)
+
+	emptyID := testutil.SeedFeed(t, database, model.Feed{Title: "Empty", URL: server.URL + "/empty"})
+	_, err = svc.StartArchiveBackfill(ctx, emptyID, service.ArchiveBackfillOptions{})
+	require.NoError(t, err)
+	done = waitArchiveBackfill(t, svc, emptyID)
+	require.Equal(t, service.ArchiveBackfillFailed, done.Status)
+	require.Contains(t, done.Error, "archive page has no items")
+
+	jsonID := testutil.SeedFeed(t, database, model.Feed{Title: "JSON", URL: server.URL + "/json"})
+	_, err = svc.StartArchiveBackfill(ctx, jsonID, service.ArchiveBackfillOptions{})
+	require.NoError(t, err)
+	done = waitArchiveBackfill(t, svc, jsonID)
+	require.Equal(t, service.ArchiveBackfillFailed, done.Status)
+	require.Contains(t, done.Error, "page 1 links back to page 1")
+	require.Equal(t, 2, done.NewEntries)
+}
+
+func TestRefreshService_StartArchiveBackfill_Invalid(t *testing.T) {
+	database := testutil.NewTestDB(t)
+	svc := service.NewRefreshService(repository.NewFeedRepository(database, nil, nil), repository.NewEntryRepository(database, nil, nil), nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	ctx := context.Background()
+	feedID := testutil.SeedFeed(t, database, model.Feed{Title: "Blog", URL: "https://example.com/feed"})
+	staticID := testutil.SeedFeed(t, database, model.Feed{Title: "Pasted", URL: service.StaticFeedURLPrefix + "pasted"})
+
+	_, err := svc.StartArchiveBackfill(ctx, feedID, service.ArchiveBackfillOptions{MaxPages: service.MaxArchivePages + 1})
+	require.ErrorIs(t, err, service.ErrInvalid)
+	_, err = svc.StartArchiveBackfill(ctx, feedID, service.ArchiveBackfillOptions{MaxPages: -1})
+	require.ErrorIs(t, err, service.ErrInvalid)
+	_, err = svc.StartArchiveBackfill(ctx, staticID, service.ArchiveBackfillOptions{})
+	require.ErrorIs(t, err, service.ErrInvalid)
+	_, err = svc.StartArchiveBackfill(ctx, 999, service.ArchiveBackfillOptions{})
+	require.ErrorIs(t, err, service.ErrNotFound)
+	_, err = svc.GetArchiveBackfill(ctx, 999)
+	require.ErrorIs(t, err, service.ErrNotFound)
+}
+
+func TestRefreshService_StartArchiveBackfill_RunningConflicts(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		_, _ = w.Write([]byte(archiveRSSPage("", 1)))
+	}))
+	defer server.Close()
+
+	database := testutil.NewTestDB(t)
+	svc := service.NewRefreshService(repository.NewFeedRepository(database, nil, nil), repository.NewEntryRepository(database, nil, nil), nil, nil, nil, nil, network.NewClientFactoryForTest(&http.Client{}), nil, nil, nil, nil, nil)
+	defer svc.Close()
+	ctx := context.Background()
+	feedID := testutil.SeedFeed(t, database, model.Feed{Title: "Slow", URL: server.URL + "/feed"})
+
+	_, err := svc.StartArchiveBackfill(ctx, feedID, service.ArchiveBackfillOptions{})
+	require.NoError(t, err)
+	_, err = svc.StartArchiveBackfill(ctx, feedID, service.ArchiveBackfillOptions{})
+	require.ErrorIs(t, err, service.ErrConflict)
+
+	close(release)
+	require.Equal(t, service.ArchiveBackfillDone, waitArchiveBackfill(t, svc, feedID).Status)
+}
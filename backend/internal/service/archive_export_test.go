@@ -0,0 +1,20 @@
+package service
+
+import (
+	"testing"
+	"time"
+)
+
+// SetArchiveRetryDelaysForTest replaces the waits between archive attempts until t ends.
+func SetArchiveRetryDelaysForTest(t *testing.T, delays ...time.Duration) {
+	previous := archiveRetryDelays
+	archiveRetryDelays = delays
+	t.Cleanup(func() { archiveRetryDelays = previous })
+}
+
+// WaitArchiveIdleForTest blocks until svc has no archival in progress.
+func WaitArchiveIdleForTest(svc ArchiveService) {
+	if impl, ok := svc.(*archiveService); ok {
+		impl.wg.Wait()
+	}
+}
@@ -0,0 +1,185 @@
+//go:generate mockgen -source=$GOFILE -destination=mock/$GOFILE -package=mock
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+	"time"
+
+	"gist/backend/internal/model"
+	"gist/backend/internal/repository"
+	"gist/backend/pkg/logger"
+)
+
+const (
+	// archiveMaxAttempts is how many times archival fetches an entry before giving up.
+	archiveMaxAttempts = 3
+	// archiveConcurrency bounds the readability fetches archival runs at once.
+	archiveConcurrency = 2
+)
+
+// archiveRetryDelays are the waits before the second and third attempts.
+var archiveRetryDelays = []time.Duration{30 * time.Second, 2 * time.Minute}
+
+// ArchiveService saves the full article of starred entries, so they stay
+// readable after the feed drops them or the site goes away.
+type ArchiveService interface {
+	// Archive fetches the readable content of a starred entry in the background
+	// and caches its images. Failed fetches are retried with backoff before the
+	// entry is listed by ListFailed. Entries already being archived are skipped.
+	Archive(entryID int64)
+	// ListFailed returns starred entries whose archival gave up, most recent first.
+	ListFailed(ctx context.Context) ([]model.FailedArchive, error)
+	// Close stops archival in progress and waits for it to return.
+	Close()
+}
+
+type archiveService struct {
+	entries     repository.EntryRepository
+	feeds       repository.FeedRepository
+	archives    repository.EntryArchiveRepository
+	readability ReadabilityService
+	images      ImageCacheService
+
+	slots chan struct{}
+	wg    sync.WaitGroup
+
+	mu      sync.Mutex
+	pending map[int64]struct{}
+	// closed is cancelled by Close and ends every archival in progress.
+	closed context.Context
+	close  context.CancelFunc
+}
+
+// NewArchiveService archives through readability; images may be nil to skip caching them.
+func NewArchiveService(entries repository.EntryRepository, feeds repository.FeedRepository, archives repository.EntryArchiveRepository, readability ReadabilityService, images ImageCacheService) ArchiveService {
+	closed, closeFn := context.WithCancel(context.Background())
+	return &archiveService{
+		entries:     entries,
+		feeds:       feeds,
+		archives:    archives,
+		readability: readability,
+		images:      images,
+		slots:       make(chan struct{}, archiveConcurrency),
+		pending:     make(map[int64]struct{}),
+		closed:      closed,
+		close:       closeFn,
+	}
+}
+
+func (s *archiveService) Archive(entryID int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed.Err() != nil {
+		return
+	}
+	if _, ok := s.pending[entryID]; ok {
+		return
+	}
+	s.pending[entryID] = struct{}{}
+	s.wg.Add(1)
+	go s.run(entryID)
+}
+
+func (s *archiveService) ListFailed(ctx context.Context) ([]model.FailedArchive, error) {
+	failed, err := s.archives.ListFailed(ctx)
+	if err != nil {
+		logger.Error("entry archive list failed", "module", "service", "action", "list", "resource", "entry", "result", "failed", "error", err)
+		return nil, err
+	}
+	return failed, nil
+}
+
+func (s *archiveService) Close() {
+	s.mu.Lock()
+	s.close()
+	s.mu.Unlock()
+	s.wg.Wait()
+}
+
+// run archives one entry, retrying transient failures.
+func (s *archiveService) run(entryID int64) {
+	defer s.wg.Done()
+	defer func() {
+		s.mu.Lock()
+		delete(s.pending, entryID)
+		s.mu.Unlock()
+	}()
+
+	var err error
+	attempts := 0
+	for attempts < archiveMaxAttempts {
+		if attempts > 0 && !s.wait(archiveRetryDelays[min(attempts, len(archiveRetryDelays))-1]) {
+			return
+		}
+		attempts++
+		err = s.archiveOnce(entryID)
+		// Retrying won't help when there is nothing readable to fetch or the entry is gone
+		if err == nil || errors.Is(err, ErrInvalid) || errors.Is(err, ErrNotFound) || s.closed.Err() != nil {
+			break
+		}
+		logger.Debug("entry archive attempt failed", "module", "service", "action", "fetch", "resource", "entry", "result", "failed", "entry_id", entryID, "attempt", attempts, "error", err)
+	}
+	if err == nil || errors.Is(err, ErrNotFound) || s.closed.Err() != nil {
+		return
+	}
+
+	logger.Warn("entry archive failed", "module", "service", "action", "save", "resource", "entry", "result", "failed", "entry_id", entryID, "attempts", attempts, "error", err)
+	if err := s.archives.MarkFailed(s.closed, entryID, attempts, err.Error()); err != nil {
+		logger.Error("entry archive record failure failed", "module", "service", "action", "save", "resource", "entry", "result", "failed", "entry_id", entryID, "error", err)
+	}
+}
+
+// wait sleeps for d and reports false when Close is called first.
+func (s *archiveService) wait(d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-s.closed.Done():
+		return false
+	}
+}
+
+func (s *archiveService) archiveOnce(entryID int64) error {
+	select {
+	case s.slots <- struct{}{}:
+		defer func() { <-s.slots }()
+	case <-s.closed.Done():
+		return s.closed.Err()
+	}
+	ctx := s.closed
+
+	entry, err := s.entries.GetByID(ctx, entryID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			// Deleted meanwhile; there is nothing left to record against
+			return nil
+		}
+		return err
+	}
+	if !entry.Starred {
+		logger.Debug("entry archive skipped unstarred entry", "module", "service", "action", "save", "resource", "entry", "result", "skipped", "entry_id", entryID)
+		return nil
+	}
+
+	if _, err := s.readability.FetchReadableContent(ctx, entryID); err != nil {
+		return err
+	}
+	if s.images != nil {
+		if feed, err := s.feeds.GetByID(ctx, entry.FeedID); err == nil {
+			s.images.CacheEntries(ctx, feed, []string{entry.Hash})
+		} else {
+			logger.Warn("entry archive feed lookup failed", "module", "service", "action", "fetch", "resource", "feed", "result", "failed", "entry_id", entryID, "feed_id", entry.FeedID, "error", err)
+		}
+	}
+
+	if err := s.archives.MarkArchived(ctx, entryID); err != nil {
+		return err
+	}
+	logger.Info("entry archived", "module", "service", "action", "save", "resource", "entry", "result", "ok", "entry_id", entryID)
+	return nil
+}
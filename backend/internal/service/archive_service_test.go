@@ -0,0 +1,149 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"gist/backend/internal/model"
+	"gist/backend/internal/repository"
+	"gist/backend/internal/repository/testutil"
+	"gist/backend/internal/service"
+	servicemock "gist/backend/internal/service/mock"
+)
+
+// starAndWait stars the entry and waits for the archive job it queues.
+func starAndWait(t *testing.T, entrySvc service.EntryService, archiveSvc service.ArchiveService, id int64) {
+	t.Helper()
+	require.NoError(t, entrySvc.MarkAsStarred(context.Background(), id, true))
+	service.WaitArchiveIdleForTest(archiveSvc)
+}
+
+func TestArchiveService_ArchivesOnStar(t *testing.T) {
+	service.SetArchiveRetryDelaysForTest(t, time.Millisecond, time.Millisecond)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	database := testutil.NewTestDB(t)
+	entries := repository.NewEntryRepository(database, nil, nil)
+	feeds := repository.NewFeedRepository(database, nil, nil)
+	archives := repository.NewEntryArchiveRepository(database)
+	mockReadability := servicemock.NewMockReadabilityService(ctrl)
+	mockImages := servicemock.NewMockImageCacheService(ctrl)
+	archiveSvc := service.NewArchiveService(entries, feeds, archives, mockReadability, mockImages)
+	t.Cleanup(archiveSvc.Close)
+	entrySvc := service.NewEntryService(entries, feeds, repository.NewFolderRepository(database, nil, nil), nil, nil, archiveSvc, nil)
+	feedID := testutil.SeedFeed(t, database, model.Feed{Title: "Feed", URL: "https://example.com/rss"})
+	ctx := context.Background()
+	id := testutil.SeedEntry(t, database, model.Entry{FeedID: feedID, Title: stringPtr("Post"), URL: stringPtr("https://example.com/post"), Hash: "post"})
+
+	mockReadability.EXPECT().FetchReadableContent(gomock.Any(), id).Return("<p>full</p>", nil)
+	mockImages.EXPECT().CacheEntries(gomock.Any(), gomock.Any(), []string{"post"}).Do(
+		func(_ context.Context, feed model.Feed, _ []string) {
+			require.Equal(t, feedID, feed.ID)
+		},
+	)
+	starAndWait(t, entrySvc, archiveSvc, id)
+
+	archive, err := archives.Get(ctx, id)
+	require.NoError(t, err)
+	require.NotNil(t, archive.ArchivedAt)
+
+	// Unstarring keeps the archive
+	require.NoError(t, entrySvc.MarkAsStarred(ctx, id, false))
+	archive, err = archives.Get(ctx, id)
+	require.NoError(t, err)
+	require.NotNil(t, archive.ArchivedAt)
+}
+
+func TestArchiveService_SkipsEntriesWithContentOrWithoutURL(t *testing.T) {
+	service.SetArchiveRetryDelaysForTest(t, time.Millisecond, time.Millisecond)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	database := testutil.NewTestDB(t)
+	entries := repository.NewEntryRepository(database, nil, nil)
+	feeds := repository.NewFeedRepository(database, nil, nil)
+	archives := repository.NewEntryArchiveRepository(database)
+	mockReadability := servicemock.NewMockReadabilityService(ctrl)
+	mockImages := servicemock.NewMockImageCacheService(ctrl)
+	archiveSvc := service.NewArchiveService(entries, feeds, archives, mockReadability, mockImages)
+	t.Cleanup(archiveSvc.Close)
+	entrySvc := service.NewEntryService(entries, feeds, repository.NewFolderRepository(database, nil, nil), nil, nil, archiveSvc, nil)
+	feedID := testutil.SeedFeed(t, database, model.Feed{Title: "Feed", URL: "https://example.com/rss"})
+	readable := testutil.SeedEntry(t, database, model.Entry{FeedID: feedID, URL: stringPtr("https://example.com/a"), ReadableContent: stringPtr("<p>kept</p>")})
+	noURL := testutil.SeedEntry(t, database, model.Entry{FeedID: feedID, Title: stringPtr("No link")})
+
+	// No readability or image cache calls are expected
+	starAndWait(t, entrySvc, archiveSvc, readable)
+	starAndWait(t, entrySvc, archiveSvc, noURL)
+}
+
+func TestArchiveService_RetriesThenListsFailure(t *testing.T) {
+	service.SetArchiveRetryDelaysForTest(t, time.Millisecond, time.Millisecond)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	database := testutil.NewTestDB(t)
+	entries := repository.NewEntryRepository(database, nil, nil)
+	feeds := repository.NewFeedRepository(database, nil, nil)
+	archives := repository.NewEntryArchiveRepository(database)
+	mockReadability := servicemock.NewMockReadabilityService(ctrl)
+	mockImages := servicemock.NewMockImageCacheService(ctrl)
+	archiveSvc := service.NewArchiveService(entries, feeds, archives, mockReadability, mockImages)
+	t.Cleanup(archiveSvc.Close)
+	entrySvc := service.NewEntryService(entries, feeds, repository.NewFolderRepository(database, nil, nil), nil, nil, archiveSvc, nil)
+	feedID := testutil.SeedFeed(t, database, model.Feed{Title: "Feed", URL: "https://example.com/rss"})
+	ctx := context.Background()
+	id := testutil.SeedEntry(t, database, model.Entry{FeedID: feedID, Title: stringPtr("Flaky"), URL: stringPtr("https://example.com/flaky")})
+
+	mockReadability.EXPECT().FetchReadableContent(gomock.Any(), id).Return("", errors.New("status 503")).Times(3)
+	starAndWait(t, entrySvc, archiveSvc, id)
+
+	failed, err := entrySvc.ListArchiveFailures(ctx)
+	require.NoError(t, err)
+	require.Len(t, failed, 1)
+	require.Equal(t, id, failed[0].EntryID)
+	require.Equal(t, 3, failed[0].Attempts)
+	require.Equal(t, "status 503", *failed[0].LastError)
+
+	// Starring again retries, and a success clears the failure
+	mockReadability.EXPECT().FetchReadableContent(gomock.Any(), id).Return("", errors.New("timeout"))
+	mockReadability.EXPECT().FetchReadableContent(gomock.Any(), id).Return("<p>full</p>", nil)
+	mockImages.EXPECT().CacheEntries(gomock.Any(), gomock.Any(), gomock.Any())
+	starAndWait(t, entrySvc, archiveSvc, id)
+
+	failed, err = entrySvc.ListArchiveFailures(ctx)
+	require.NoError(t, err)
+	require.Empty(t, failed)
+}
+
+func TestArchiveService_InvalidContentIsNotRetried(t *testing.T) {
+	service.SetArchiveRetryDelaysForTest(t, time.Millisecond, time.Millisecond)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	database := testutil.NewTestDB(t)
+	entries := repository.NewEntryRepository(database, nil, nil)
+	feeds := repository.NewFeedRepository(database, nil, nil)
+	archives := repository.NewEntryArchiveRepository(database)
+	mockReadability := servicemock.NewMockReadabilityService(ctrl)
+	mockImages := servicemock.NewMockImageCacheService(ctrl)
+	archiveSvc := service.NewArchiveService(entries, feeds, archives, mockReadability, mockImages)
+	t.Cleanup(archiveSvc.Close)
+	entrySvc := service.NewEntryService(entries, feeds, repository.NewFolderRepository(database, nil, nil), nil, nil, archiveSvc, nil)
+	feedID := testutil.SeedFeed(t, database, model.Feed{Title: "Feed", URL: "https://example.com/rss"})
+	id := testutil.SeedEntry(t, database, model.Entry{FeedID: feedID, URL: stringPtr("https://example.com/scan.pdf")})
+
+	mockReadability.EXPECT().FetchReadableContent(gomock.Any(), id).Return("", service.ErrInvalid)
+	starAndWait(t, entrySvc, archiveSvc, id)
+
+	archive, err := archives.Get(context.Background(), id)
+	require.NoError(t, err)
+	require.Equal(t, 1, archive.Attempts)
+	require.Nil(t, archive.ArchivedAt)
+}
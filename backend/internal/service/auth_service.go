@@ -6,17 +6,22 @@ import (
 	"crypto/md5"
 	"crypto/rand"
 	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"golang.org/x/crypto/bcrypt"
 
-	"gist/backend/pkg/logger"
+	"gist/backend/internal/hashutil"
 	"gist/backend/internal/repository"
+	"gist/backend/pkg/logger"
+	"gist/backend/pkg/totp"
 )
 
 // usernameRegex validates username format: lowercase letters and numbers only, starts with letter
@@ -29,6 +34,22 @@ const (
 	keyUserEmail        = "user.email"
 	keyUserPasswordHash = "user.password_hash"
 	keyUserJWTSecret    = "user.jwt_secret"
+
+	keyUserTOTPSecret        = "user.totp_secret"
+	keyUserTOTPPendingSecret = "user.totp_pending_secret"
+	keyUserTOTPRecoveryCodes = "user.totp_recovery_codes"
+	keyUserTOTPLastStep      = "user.totp_last_step"
+)
+
+// Two-factor authentication parameters
+const (
+	totpIssuer             = "Gist"
+	totpSkew               = 1
+	recoveryCodeCount      = 10
+	pendingTokenTTL        = 5 * time.Minute
+	pendingTokenType       = "2fa_pending"
+	maxTwoFactorAttempts   = 5
+	twoFactorAttemptWindow = 5 * time.Minute
 )
 
 // Auth errors
@@ -44,6 +65,12 @@ var (
 	ErrPasswordTooShort        = errors.New("password must be at least 6 characters")
 	ErrCurrentPasswordRequired = errors.New("current password is required")
 	ErrSamePassword            = errors.New("new password must be different from current password")
+	ErrTwoFactorCodeRequired   = errors.New("two-factor code is required")
+	ErrTwoFactorInvalidCode    = errors.New("invalid two-factor code")
+	ErrTwoFactorNotSetup       = errors.New("two-factor setup has not been started")
+	ErrTwoFactorEnabled        = errors.New("two-factor authentication is already enabled")
+	ErrTwoFactorNotEnabled     = errors.New("two-factor authentication is not enabled")
+	ErrTooManyAttempts         = errors.New("too many attempts, try again later")
 )
 
 // User represents the authenticated user.
@@ -52,6 +79,8 @@ type User struct {
 	Nickname  string `json:"nickname"`
 	Email     string `json:"email"`
 	AvatarURL string `json:"avatarUrl"`
+	// TwoFactorEnabled reports whether TOTP is required at login.
+	TwoFactorEnabled bool `json:"twoFactorEnabled"`
 }
 
 // AuthService provides authentication functionality.
@@ -70,12 +99,30 @@ type AuthService interface {
 	// UpdateProfile updates user nickname, email and/or password.
 	// Returns new token when password is changed (old tokens become invalid).
 	UpdateProfile(ctx context.Context, nickname, email, currentPassword, newPassword string) (*UpdateProfileResponse, error)
+	// SetupTwoFactor generates a new TOTP secret awaiting confirmation via EnableTwoFactor.
+	SetupTwoFactor(ctx context.Context) (*TwoFactorSetup, error)
+	// EnableTwoFactor confirms the pending secret with a code and returns single-use recovery codes.
+	EnableTwoFactor(ctx context.Context, code string) ([]string, error)
+	// VerifyTwoFactor exchanges a pending token and a TOTP or recovery code for a JWT token.
+	VerifyTwoFactor(ctx context.Context, pendingToken, code string) (*AuthResponse, error)
+	// DisableTwoFactor turns off two-factor authentication after verifying the current password.
+	DisableTwoFactor(ctx context.Context, password string) error
 }
 
 // AuthResponse is returned after successful login/register.
+// When Requires2FA is set, Token and User are empty and PendingToken
+// must be exchanged via VerifyTwoFactor.
 type AuthResponse struct {
-	Token string `json:"token"`
-	User  *User  `json:"user"`
+	Token        string `json:"token"`
+	User         *User  `json:"user"`
+	Requires2FA  bool   `json:"requires2fa,omitempty"`
+	PendingToken string `json:"pendingToken,omitempty"`
+}
+
+// TwoFactorSetup holds a freshly generated TOTP secret.
+type TwoFactorSetup struct {
+	Secret     string `json:"secret"`
+	OTPAuthURL string `json:"otpauthUrl"`
 }
 
 // UpdateProfileResponse is returned after updating profile.
@@ -87,6 +134,15 @@ type UpdateProfileResponse struct {
 
 type authService struct {
 	repo repository.SettingsRepository
+
+	// attemptsMu guards two-factor verification failure tracking.
+	attemptsMu     sync.Mutex
+	failedAttempts int
+	windowStart    time.Time
+
+	// codesMu serializes consuming a two-factor code, so the replay check and the
+	// recovery code removal see each other's writes.
+	codesMu sync.Mutex
 }
 
 // NewAuthService creates a new auth service.
@@ -240,6 +296,23 @@ func (s *authService) Login(ctx context.Context, identifier, password string) (*
 		return nil, err
 	}
 
+	// Require a second factor before issuing the real token
+	totpSecret, err := s.getString(ctx, keyUserTOTPSecret)
+	if err != nil {
+		return nil, err
+	}
+	if totpSecret != "" {
+		pendingToken, err := s.generatePendingToken(storedUsername, jwtSecret)
+		if err != nil {
+			return nil, err
+		}
+		logger.Info("auth login requires 2fa", "module", "service", "action", "login", "resource", "auth", "result", "pending", "actor", storedUsername)
+		return &AuthResponse{
+			Requires2FA:  true,
+			PendingToken: pendingToken,
+		}, nil
+	}
+
 	// Generate token
 	token, err := s.generateToken(storedUsername, jwtSecret)
 	if err != nil {
@@ -273,12 +346,14 @@ func (s *authService) GetCurrentUser(ctx context.Context) (*User, error) {
 		nickname = username
 	}
 	email, _ := s.getString(ctx, keyUserEmail)
+	totpSecret, _ := s.getString(ctx, keyUserTOTPSecret)
 
 	return &User{
-		Username:  username,
-		Nickname:  nickname,
-		Email:     email,
-		AvatarURL: gravatarURL(email),
+		Username:         username,
+		Nickname:         nickname,
+		Email:            email,
+		AvatarURL:        gravatarURL(email),
+		TwoFactorEnabled: totpSecret != "",
 	}, nil
 }
 
@@ -305,6 +380,13 @@ func (s *authService) ValidateToken(tokenString string) (bool, error) {
 		return false, ErrInvalidToken
 	}
 
+	// Pending 2FA tokens must not grant access
+	if claims, ok := token.Claims.(jwt.MapClaims); ok {
+		if _, typed := claims["typ"]; typed {
+			return false, ErrInvalidToken
+		}
+	}
+
 	return true, nil
 }
 
@@ -330,6 +412,375 @@ func (s *authService) generateToken(username, jwtSecretHex string) (string, erro
 	return tokenString, nil
 }
 
+// generatePendingToken creates a short-lived token that only allows completing 2FA verification.
+func (s *authService) generatePendingToken(username, jwtSecretHex string) (string, error) {
+	secretBytes, err := hex.DecodeString(jwtSecretHex)
+	if err != nil {
+		return "", fmt.Errorf("decode jwt secret: %w", err)
+	}
+
+	claims := jwt.MapClaims{
+		"sub": username,
+		"typ": pendingTokenType,
+		"iat": time.Now().Unix(),
+		"exp": time.Now().Add(pendingTokenTTL).Unix(),
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	tokenString, err := token.SignedString(secretBytes)
+	if err != nil {
+		return "", fmt.Errorf("sign pending token: %w", err)
+	}
+
+	return tokenString, nil
+}
+
+// parsePendingToken validates a pending 2FA token and returns its subject.
+func (s *authService) parsePendingToken(ctx context.Context, tokenString string) (string, error) {
+	jwtSecret, err := s.getString(ctx, keyUserJWTSecret)
+	if err != nil || jwtSecret == "" {
+		return "", ErrInvalidToken
+	}
+	secretBytes, err := hex.DecodeString(jwtSecret)
+	if err != nil {
+		return "", ErrInvalidToken
+	}
+
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return secretBytes, nil
+	})
+	if err != nil || !token.Valid {
+		return "", ErrInvalidToken
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || claims["typ"] != pendingTokenType {
+		return "", ErrInvalidToken
+	}
+	subject, _ := claims["sub"].(string)
+	if subject == "" {
+		return "", ErrInvalidToken
+	}
+	return subject, nil
+}
+
+// SetupTwoFactor generates a new TOTP secret awaiting confirmation via EnableTwoFactor.
+func (s *authService) SetupTwoFactor(ctx context.Context) (*TwoFactorSetup, error) {
+	username, err := s.getString(ctx, keyUserUsername)
+	if err != nil {
+		return nil, err
+	}
+	if username == "" {
+		return nil, ErrUserNotFound
+	}
+
+	current, err := s.getString(ctx, keyUserTOTPSecret)
+	if err != nil {
+		return nil, err
+	}
+	if current != "" {
+		return nil, ErrTwoFactorEnabled
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.Set(ctx, keyUserTOTPPendingSecret, secret); err != nil {
+		return nil, fmt.Errorf("save pending totp secret: %w", err)
+	}
+
+	logger.Info("auth 2fa setup", "module", "service", "action", "create", "resource", "auth", "result", "ok", "actor", username)
+	return &TwoFactorSetup{
+		Secret:     secret,
+		OTPAuthURL: totp.URL(totpIssuer, username, secret),
+	}, nil
+}
+
+// EnableTwoFactor confirms the pending secret with a code and returns single-use recovery codes.
+func (s *authService) EnableTwoFactor(ctx context.Context, code string) ([]string, error) {
+	code = strings.TrimSpace(code)
+	if code == "" {
+		return nil, ErrTwoFactorCodeRequired
+	}
+
+	username, err := s.getString(ctx, keyUserUsername)
+	if err != nil {
+		return nil, err
+	}
+	if username == "" {
+		return nil, ErrUserNotFound
+	}
+
+	current, err := s.getString(ctx, keyUserTOTPSecret)
+	if err != nil {
+		return nil, err
+	}
+	if current != "" {
+		return nil, ErrTwoFactorEnabled
+	}
+
+	pending, err := s.getString(ctx, keyUserTOTPPendingSecret)
+	if err != nil {
+		return nil, err
+	}
+	if pending == "" {
+		return nil, ErrTwoFactorNotSetup
+	}
+
+	if err := s.checkTwoFactorAttempts(); err != nil {
+		logger.Warn("auth 2fa enable rate limited", "module", "service", "action", "update", "resource", "auth", "result", "failed", "actor", username)
+		return nil, err
+	}
+	step, ok := totp.Validate(pending, code, time.Now(), totpSkew)
+	if !ok {
+		s.recordTwoFactorFailure()
+		logger.Warn("auth 2fa enable invalid code", "module", "service", "action", "update", "resource", "auth", "result", "failed", "actor", username)
+		return nil, ErrTwoFactorInvalidCode
+	}
+	s.resetTwoFactorAttempts()
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	hashesJSON, err := json.Marshal(hashes)
+	if err != nil {
+		return nil, fmt.Errorf("encode recovery codes: %w", err)
+	}
+
+	if err := s.repo.SetMany(ctx, map[string]string{
+		keyUserTOTPSecret:        pending,
+		keyUserTOTPRecoveryCodes: string(hashesJSON),
+		keyUserTOTPLastStep:      strconv.FormatInt(step, 10),
+	}); err != nil {
+		return nil, fmt.Errorf("save totp secret: %w", err)
+	}
+	if err := s.repo.Delete(ctx, keyUserTOTPPendingSecret); err != nil {
+		return nil, fmt.Errorf("delete pending totp secret: %w", err)
+	}
+
+	logger.Info("auth 2fa enabled", "module", "service", "action", "update", "resource", "auth", "result", "ok", "actor", username)
+	return codes, nil
+}
+
+// VerifyTwoFactor exchanges a pending token and a TOTP or recovery code for a JWT token.
+func (s *authService) VerifyTwoFactor(ctx context.Context, pendingToken, code string) (*AuthResponse, error) {
+	code = strings.TrimSpace(code)
+	if code == "" {
+		return nil, ErrTwoFactorCodeRequired
+	}
+
+	subject, err := s.parsePendingToken(ctx, pendingToken)
+	if err != nil {
+		return nil, err
+	}
+
+	username, err := s.getString(ctx, keyUserUsername)
+	if err != nil {
+		return nil, err
+	}
+	if username == "" || username != subject {
+		return nil, ErrInvalidToken
+	}
+
+	secret, err := s.getString(ctx, keyUserTOTPSecret)
+	if err != nil {
+		return nil, err
+	}
+	if secret == "" {
+		return nil, ErrTwoFactorNotEnabled
+	}
+
+	if err := s.checkTwoFactorAttempts(); err != nil {
+		logger.Warn("auth 2fa verify rate limited", "module", "service", "action", "login", "resource", "auth", "result", "failed", "actor", username)
+		return nil, err
+	}
+
+	ok, method, err := s.consumeTwoFactorCode(ctx, secret, code)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		s.recordTwoFactorFailure()
+		logger.Warn("auth 2fa verify invalid code", "module", "service", "action", "login", "resource", "auth", "result", "failed", "actor", username)
+		return nil, ErrTwoFactorInvalidCode
+	}
+	s.resetTwoFactorAttempts()
+
+	jwtSecret, err := s.getString(ctx, keyUserJWTSecret)
+	if err != nil {
+		return nil, err
+	}
+	token, err := s.generateToken(username, jwtSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	nickname, _ := s.getString(ctx, keyUserNickname)
+	if nickname == "" {
+		nickname = username
+	}
+	email, _ := s.getString(ctx, keyUserEmail)
+
+	logger.Info("auth login", "module", "service", "action", "login", "resource", "auth", "result", "ok", "actor", username, "method", method)
+	return &AuthResponse{
+		Token: token,
+		User: &User{
+			Username:         username,
+			Nickname:         nickname,
+			Email:            email,
+			AvatarURL:        gravatarURL(email),
+			TwoFactorEnabled: true,
+		},
+	}, nil
+}
+
+// DisableTwoFactor turns off two-factor authentication after verifying the current password.
+func (s *authService) DisableTwoFactor(ctx context.Context, password string) error {
+	if password == "" {
+		return ErrCurrentPasswordRequired
+	}
+
+	username, err := s.getString(ctx, keyUserUsername)
+	if err != nil {
+		return err
+	}
+	if username == "" {
+		return ErrUserNotFound
+	}
+
+	storedHash, err := s.getString(ctx, keyUserPasswordHash)
+	if err != nil {
+		return err
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(storedHash), []byte(password)); err != nil {
+		logger.Warn("auth 2fa disable invalid password", "module", "service", "action", "update", "resource", "auth", "result", "failed", "actor", username)
+		return ErrInvalidPassword
+	}
+
+	secret, err := s.getString(ctx, keyUserTOTPSecret)
+	if err != nil {
+		return err
+	}
+	if secret == "" {
+		return ErrTwoFactorNotEnabled
+	}
+
+	for _, key := range []string{keyUserTOTPSecret, keyUserTOTPPendingSecret, keyUserTOTPRecoveryCodes, keyUserTOTPLastStep} {
+		if err := s.repo.Delete(ctx, key); err != nil {
+			return fmt.Errorf("delete %s: %w", key, err)
+		}
+	}
+
+	logger.Info("auth 2fa disabled", "module", "service", "action", "update", "resource", "auth", "result", "ok", "actor", username)
+	return nil
+}
+
+// consumeTwoFactorCode checks a TOTP code (rejecting replays) or a recovery code
+// (removing it once used). It returns which method matched.
+func (s *authService) consumeTwoFactorCode(ctx context.Context, secret, code string) (bool, string, error) {
+	s.codesMu.Lock()
+	defer s.codesMu.Unlock()
+
+	if step, ok := totp.Validate(secret, code, time.Now(), totpSkew); ok {
+		lastStep, err := s.getString(ctx, keyUserTOTPLastStep)
+		if err != nil {
+			return false, "", err
+		}
+		if last, err := strconv.ParseInt(lastStep, 10, 64); err == nil && step <= last {
+			// Each code may only be used once
+			return false, "", nil
+		}
+		if err := s.repo.Set(ctx, keyUserTOTPLastStep, strconv.FormatInt(step, 10)); err != nil {
+			return false, "", fmt.Errorf("save totp step: %w", err)
+		}
+		return true, "totp", nil
+	}
+
+	raw, err := s.getString(ctx, keyUserTOTPRecoveryCodes)
+	if err != nil {
+		return false, "", err
+	}
+	if raw == "" {
+		return false, "", nil
+	}
+	var hashes []string
+	if err := json.Unmarshal([]byte(raw), &hashes); err != nil {
+		return false, "", fmt.Errorf("decode recovery codes: %w", err)
+	}
+
+	target := hashutil.SHA256Hex(normalizeRecoveryCode(code))
+	for i, hash := range hashes {
+		if hash != target {
+			continue
+		}
+		remaining := append(hashes[:i:i], hashes[i+1:]...)
+		encoded, err := json.Marshal(remaining)
+		if err != nil {
+			return false, "", fmt.Errorf("encode recovery codes: %w", err)
+		}
+		if err := s.repo.Set(ctx, keyUserTOTPRecoveryCodes, string(encoded)); err != nil {
+			return false, "", fmt.Errorf("save recovery codes: %w", err)
+		}
+		return true, "recovery_code", nil
+	}
+	return false, "", nil
+}
+
+// checkTwoFactorAttempts returns ErrTooManyAttempts while the failure budget is exhausted.
+func (s *authService) checkTwoFactorAttempts() error {
+	s.attemptsMu.Lock()
+	defer s.attemptsMu.Unlock()
+
+	if time.Since(s.windowStart) > twoFactorAttemptWindow {
+		s.failedAttempts = 0
+		s.windowStart = time.Now()
+	}
+	if s.failedAttempts >= maxTwoFactorAttempts {
+		return ErrTooManyAttempts
+	}
+	return nil
+}
+
+func (s *authService) recordTwoFactorFailure() {
+	s.attemptsMu.Lock()
+	defer s.attemptsMu.Unlock()
+	s.failedAttempts++
+}
+
+func (s *authService) resetTwoFactorAttempts() {
+	s.attemptsMu.Lock()
+	defer s.attemptsMu.Unlock()
+	s.failedAttempts = 0
+}
+
+// generateRecoveryCodes returns plaintext recovery codes and their hashes for storage.
+func generateRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		buf := make([]byte, 5)
+		if _, err := rand.Read(buf); err != nil {
+			return nil, nil, fmt.Errorf("generate recovery code: %w", err)
+		}
+		raw := hex.EncodeToString(buf)
+		code := raw[:5] + "-" + raw[5:]
+		codes = append(codes, code)
+		hashes = append(hashes, hashutil.SHA256Hex(normalizeRecoveryCode(code)))
+	}
+	return codes, hashes, nil
+}
+
+// normalizeRecoveryCode strips separators and case so users can type codes loosely.
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	return strings.ReplaceAll(code, "-", "")
+}
+
 // UpdateProfile updates user nickname, email and/or password.
 func (s *authService) UpdateProfile(ctx context.Context, nickname, email, currentPassword, newPassword string) (*UpdateProfileResponse, error) {
 	// Check if user exists
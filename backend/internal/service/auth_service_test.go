@@ -3,9 +3,14 @@ package service_test
 import (
 	"context"
 	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"gist/backend/internal/service"
+	"gist/backend/pkg/totp"
 
 	"github.com/stretchr/testify/require"
 	"golang.org/x/crypto/bcrypt"
@@ -224,3 +229,129 @@ func TestAuthService_CheckUserExists_RepoError(t *testing.T) {
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "database error")
 }
+
+func setupTwoFactorUser(t *testing.T) (service.AuthService, *settingsRepoStub, string, []string) {
+	t.Helper()
+	repo := newSettingsRepoStub()
+	svc := service.NewAuthService(repo)
+
+	_, err := svc.Register(context.Background(), "alice", "", "alice@example.com", "secret1")
+	require.NoError(t, err)
+
+	setup, err := svc.SetupTwoFactor(context.Background())
+	require.NoError(t, err)
+	require.NotEmpty(t, setup.Secret)
+	require.Contains(t, setup.OTPAuthURL, "otpauth://totp/Gist:alice")
+
+	code, err := totp.CodeAt(setup.Secret, totp.Step(time.Now()))
+	require.NoError(t, err)
+	recovery, err := svc.EnableTwoFactor(context.Background(), code)
+	require.NoError(t, err)
+	require.Len(t, recovery, 10)
+	require.Equal(t, setup.Secret, repo.data[service.KeyUserTOTPSecret])
+
+	return svc, repo, setup.Secret, recovery
+}
+
+func TestAuthService_TwoFactor_LoginFlow(t *testing.T) {
+	svc, _, secret, _ := setupTwoFactorUser(t)
+
+	resp, err := svc.Login(context.Background(), "alice", "secret1")
+	require.NoError(t, err)
+	require.True(t, resp.Requires2FA)
+	require.Empty(t, resp.Token)
+	require.NotEmpty(t, resp.PendingToken)
+
+	ok, err := svc.ValidateToken(resp.PendingToken)
+	require.ErrorIs(t, err, service.ErrInvalidTokenHelper, "pending token must not authenticate")
+	require.False(t, ok)
+
+	// The enable code's step is consumed, so use the next one
+	code, err := totp.CodeAt(secret, totp.Step(time.Now())+1)
+	require.NoError(t, err)
+	verified, err := svc.VerifyTwoFactor(context.Background(), resp.PendingToken, code)
+	require.NoError(t, err)
+	require.NotEmpty(t, verified.Token)
+	require.True(t, verified.User.TwoFactorEnabled)
+
+	ok, err = svc.ValidateToken(verified.Token)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	_, err = svc.VerifyTwoFactor(context.Background(), resp.PendingToken, code)
+	require.ErrorIs(t, err, service.ErrTwoFactorInvalidCodeHelper, "replayed code must be rejected")
+}
+
+func TestAuthService_TwoFactor_RecoveryCodeSingleUse(t *testing.T) {
+	svc, _, _, recovery := setupTwoFactorUser(t)
+
+	resp, err := svc.Login(context.Background(), "alice", "secret1")
+	require.NoError(t, err)
+
+	_, err = svc.VerifyTwoFactor(context.Background(), resp.PendingToken, strings.ToUpper(recovery[0]))
+	require.NoError(t, err)
+
+	_, err = svc.VerifyTwoFactor(context.Background(), resp.PendingToken, recovery[0])
+	require.ErrorIs(t, err, service.ErrTwoFactorInvalidCodeHelper)
+}
+
+func TestAuthService_TwoFactor_RecoveryCodeConcurrentUse(t *testing.T) {
+	svc, _, _, recovery := setupTwoFactorUser(t)
+
+	resp, err := svc.Login(context.Background(), "alice", "secret1")
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	var verified atomic.Int32
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := svc.VerifyTwoFactor(context.Background(), resp.PendingToken, recovery[0]); err == nil {
+				verified.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	require.EqualValues(t, 1, verified.Load())
+}
+
+func TestAuthService_TwoFactor_RateLimited(t *testing.T) {
+	svc, _, _, _ := setupTwoFactorUser(t)
+
+	resp, err := svc.Login(context.Background(), "alice", "secret1")
+	require.NoError(t, err)
+
+	for i := 0; i < 5; i++ {
+		_, err = svc.VerifyTwoFactor(context.Background(), resp.PendingToken, "000000")
+		require.ErrorIs(t, err, service.ErrTwoFactorInvalidCodeHelper)
+	}
+	_, err = svc.VerifyTwoFactor(context.Background(), resp.PendingToken, "000000")
+	require.ErrorIs(t, err, service.ErrTooManyAttemptsHelper)
+}
+
+func TestAuthService_TwoFactor_EnableRequiresSetup(t *testing.T) {
+	repo := newSettingsRepoStub()
+	svc := service.NewAuthService(repo)
+	_, err := svc.Register(context.Background(), "alice", "", "alice@example.com", "secret1")
+	require.NoError(t, err)
+
+	_, err = svc.EnableTwoFactor(context.Background(), "123456")
+	require.ErrorIs(t, err, service.ErrTwoFactorNotSetupHelper)
+}
+
+func TestAuthService_TwoFactor_Disable(t *testing.T) {
+	svc, repo, _, _ := setupTwoFactorUser(t)
+
+	err := svc.DisableTwoFactor(context.Background(), "wrong1")
+	require.ErrorIs(t, err, service.ErrInvalidPasswordHelper)
+
+	err = svc.DisableTwoFactor(context.Background(), "secret1")
+	require.NoError(t, err)
+	require.NotContains(t, repo.data, service.KeyUserTOTPSecret)
+
+	resp, err := svc.Login(context.Background(), "alice", "secret1")
+	require.NoError(t, err)
+	require.False(t, resp.Requires2FA)
+	require.NotEmpty(t, resp.Token)
+}
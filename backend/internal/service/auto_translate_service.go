@@ -0,0 +1,339 @@
+//go:generate mockgen -source=$GOFILE -destination=mock/$GOFILE -package=mock
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"gist/backend/internal/model"
+	"gist/backend/internal/repository"
+	"gist/backend/internal/service/ai"
+	"gist/backend/pkg/langdetect"
+	"gist/backend/pkg/logger"
+	"gist/backend/pkg/sanitizer"
+)
+
+const (
+	// autoTranslateBatchSize is how many entries go to one TranslateBatch call.
+	autoTranslateBatchSize = 50
+	// autoTranslateMaxEntries bounds the entries one run picks up; the newest win.
+	autoTranslateMaxEntries = 500
+	// autoTranslateLookback is how far back the first run after startup looks.
+	autoTranslateLookback = 24 * time.Hour
+	// autoTranslateSummaryRunes is how much of the content is sent as the list
+	// summary, the same as the list view sends.
+	autoTranslateSummaryRunes = 200
+)
+
+// AutoTranslateRun is the outcome of one background list translation run.
+type AutoTranslateRun struct {
+	StartedAt  time.Time
+	FinishedAt time.Time
+	// Entries counts the untranslated new entries collected.
+	Entries int
+	// Skipped counts entries already in the target language, by their detected
+	// language or their feed's titles, and entries without text.
+	Skipped    int
+	Translated int
+	Failed     int
+	// Error is set when the run stopped early, e.g. when AI is not configured.
+	Error string
+}
+
+// AutoTranslateStatus describes the background list translation worker.
+type AutoTranslateStatus struct {
+	// Enabled is the translateOnRefresh AI setting; feeds set to on are
+	// translated either way.
+	Enabled  bool
+	Running  bool
+	Language string
+	// LastRun is nil until the first run since startup finishes.
+	LastRun *AutoTranslateRun
+}
+
+// AutoTranslateService translates list titles and summaries of new entries in
+// the background, so the list view finds them cached instead of waiting for
+// them while scrolling.
+type AutoTranslateService interface {
+	// Run translates entries created since the previous successful run into the
+	// summary language, in batches under the AI rate limiter. Only feeds whose
+	// auto translate choice is on, or inherit while translateOnRefresh is set,
+	// take part. A run already in progress or a closed service makes it return nil
+	// at once.
+	Run(ctx context.Context) error
+	// Status reports the setting, whether a run is in progress and the last run.
+	Status(ctx context.Context) AutoTranslateStatus
+	// Close stops the run in progress and waits for it to return.
+	Close()
+}
+
+type autoTranslateService struct {
+	translations repository.AIListTranslationRepository
+	ai           AIService
+	settings     SettingsService
+	// feeds and folders resolve which feeds translate through their folders'
+	// defaults; without them only the feeds' own choices count.
+	feeds   repository.FeedRepository
+	folders repository.FolderRepository
+
+	mu      sync.Mutex
+	running bool
+	lastRun *AutoTranslateRun
+	// since is where the next run starts collecting entries.
+	since time.Time
+
+	wg sync.WaitGroup
+	// closed is cancelled by Close and ends the run in progress.
+	closed context.Context
+	close  context.CancelFunc
+}
+
+// NewAutoTranslateService also translates the feeds that inherit auto
+// translation from their folders when feeds and folders are set.
+func NewAutoTranslateService(translations repository.AIListTranslationRepository, aiService AIService, settings SettingsService, feeds repository.FeedRepository, folders repository.FolderRepository) AutoTranslateService {
+	closed, closeFn := context.WithCancel(context.Background())
+	return &autoTranslateService{
+		translations: translations,
+		ai:           aiService,
+		settings:     settings,
+		feeds:        feeds,
+		folders:      folders,
+		since:        time.Now().Add(-autoTranslateLookback),
+		closed:       closed,
+		close:        closeFn,
+	}
+}
+
+func (s *autoTranslateService) Run(ctx context.Context) error {
+	s.mu.Lock()
+	if s.running || s.closed.Err() != nil {
+		s.mu.Unlock()
+		return nil
+	}
+	s.running = true
+	since := s.since
+	s.wg.Add(1)
+	s.mu.Unlock()
+	defer s.wg.Done()
+
+	ctx, cancel := context.WithCancel(ctx)
+	stop := context.AfterFunc(s.closed, cancel)
+	defer func() {
+		stop()
+		cancel()
+	}()
+
+	run := AutoTranslateRun{StartedAt: time.Now().UTC()}
+	err := s.run(ctx, since, &run)
+	run.FinishedAt = time.Now().UTC()
+	if err != nil {
+		run.Error = err.Error()
+	}
+
+	s.mu.Lock()
+	s.running = false
+	s.lastRun = &run
+	// A failed run is picked up again by the next one
+	if err == nil {
+		s.since = run.StartedAt
+	}
+	s.mu.Unlock()
+
+	if err != nil {
+		logger.Warn("auto translate run failed", "module", "service", "action", "fetch", "resource", "ai", "result", "failed", "entries", run.Entries, "translated", run.Translated, "failed", run.Failed, "error", err)
+		return err
+	}
+	if run.Entries > 0 {
+		logger.Info("auto translate run completed", "module", "service", "action", "fetch", "resource", "ai", "result", "ok", "entries", run.Entries, "skipped", run.Skipped, "translated", run.Translated, "failed", run.Failed)
+	}
+	return nil
+}
+
+func (s *autoTranslateService) run(ctx context.Context, since time.Time, run *AutoTranslateRun) error {
+	settings, err := s.settings.GetAISettings(ctx)
+	if err != nil {
+		return fmt.Errorf("get ai settings: %w", err)
+	}
+	language := s.ai.GetSummaryLanguage(ctx)
+
+	var entries []model.Entry
+	if s.feeds != nil && s.folders != nil {
+		feedIDs, err := s.translatedFeedIDs(ctx, settings.TranslateOnRefresh)
+		if err != nil {
+			return err
+		}
+		entries, err = s.translations.ListUntranslatedInFeeds(ctx, language, feedIDs, since, autoTranslateMaxEntries)
+		if err != nil {
+			return fmt.Errorf("list untranslated entries: %w", err)
+		}
+	} else {
+		modes := []string{model.FeedAutoTranslateOn}
+		if settings.TranslateOnRefresh {
+			modes = append(modes, model.FeedAutoTranslateInherit)
+		}
+		entries, err = s.translations.ListUntranslated(ctx, language, modes, since, autoTranslateMaxEntries)
+		if err != nil {
+			return fmt.Errorf("list untranslated entries: %w", err)
+		}
+	}
+	pending := foreignEntries(entries, language)
+	run.Entries = len(entries)
+	run.Skipped = len(entries) - len(pending)
+
+	for start := 0; start < len(pending); start += autoTranslateBatchSize {
+		// The scheduler checks quiet hours before a run; ones starting
+		// mid-run pause it at the next batch
+		if start > 0 {
+			if err := waitOutQuietHours(ctx, s.settings, "ai"); err != nil {
+				return err
+			}
+		}
+		chunk := pending[start:min(start+autoTranslateBatchSize, len(pending))]
+		translated, failed, err := s.translateChunk(ctx, chunk, language)
+		run.Translated += translated
+		run.Failed += failed
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// translatedFeedIDs returns the feeds whose effective auto translation is on,
+// byDefault standing in for the feeds nothing sets it for.
+func (s *autoTranslateService) translatedFeedIDs(ctx context.Context, byDefault bool) ([]int64, error) {
+	feeds, err := s.feeds.List(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("list feeds: %w", err)
+	}
+	configs, err := effectiveFeedConfigs(ctx, s.folders, feeds)
+	if err != nil {
+		return nil, fmt.Errorf("list folders: %w", err)
+	}
+	var ids []int64
+	for _, feed := range feeds {
+		enabled := byDefault
+		if translate := configs[feed.ID].AutoTranslate; translate != nil {
+			enabled = *translate
+		}
+		if enabled {
+			ids = append(ids, feed.ID)
+		}
+	}
+	return ids, nil
+}
+
+// translateChunk translates one batch and counts the entries translated and the
+// ones that failed. Entries translated meanwhile, e.g. by the list view, count
+// as neither.
+func (s *autoTranslateService) translateChunk(ctx context.Context, entries []model.Entry, language string) (translated, failed int, err error) {
+	if err := ctx.Err(); err != nil {
+		return 0, 0, err
+	}
+
+	articles := make([]BatchArticleInput, 0, len(entries))
+	for _, entry := range entries {
+		articles = append(articles, BatchArticleInput{
+			ID:      strconv.FormatInt(entry.ID, 10),
+			Title:   entryListTitle(entry),
+			Summary: entryListSummary(entry),
+		})
+	}
+
+	results, errs, err := s.ai.TranslateBatch(ctx, articles, language)
+	if err != nil {
+		return 0, 0, fmt.Errorf("translate batch: %w", err)
+	}
+	for result := range results {
+		if !result.Cached {
+			translated++
+		}
+	}
+	for range errs {
+		failed++
+	}
+	return translated, failed, ctx.Err()
+}
+
+func (s *autoTranslateService) Status(ctx context.Context) AutoTranslateStatus {
+	var status AutoTranslateStatus
+	if settings, err := s.settings.GetAISettings(ctx); err == nil {
+		status.Enabled = settings.TranslateOnRefresh
+	}
+	status.Language = s.ai.GetSummaryLanguage(ctx)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	status.Running = s.running
+	if s.lastRun != nil {
+		run := *s.lastRun
+		status.LastRun = &run
+	}
+	return status
+}
+
+func (s *autoTranslateService) Close() {
+	s.mu.Lock()
+	s.close()
+	s.mu.Unlock()
+	s.wg.Wait()
+}
+
+// foreignEntries leaves out entries detected to be in language already,
+// entries of unknown language whose feed's titles mostly look like language,
+// and entries with nothing to translate.
+func foreignEntries(entries []model.Entry, language string) []model.Entry {
+	target := langdetect.Normalize(language)
+	titled := make(map[int64]int)
+	native := make(map[int64]int)
+	for _, entry := range entries {
+		title := entryListTitle(entry)
+		if title == "" || entry.Language != "" {
+			continue
+		}
+		titled[entry.FeedID]++
+		if ai.LooksLikeLanguage(title, language) {
+			native[entry.FeedID]++
+		}
+	}
+
+	pending := make([]model.Entry, 0, len(entries))
+	for _, entry := range entries {
+		if entry.Language != "" {
+			if entry.Language == target {
+				continue
+			}
+		} else if native[entry.FeedID]*2 > titled[entry.FeedID] {
+			continue
+		}
+		if entryListTitle(entry) == "" && entryListSummary(entry) == "" {
+			continue
+		}
+		pending = append(pending, entry)
+	}
+	return pending
+}
+
+func entryListTitle(entry model.Entry) string {
+	if entry.Title == nil {
+		return ""
+	}
+	return strings.TrimSpace(*entry.Title)
+}
+
+// entryListSummary is the start of the entry's text, as the list view shows it.
+func entryListSummary(entry model.Entry) string {
+	if entry.Content == nil {
+		return ""
+	}
+	text := sanitizer.StripTags(*entry.Content)
+	if utf8.RuneCountInString(text) <= autoTranslateSummaryRunes {
+		return text
+	}
+	return string([]rune(text)[:autoTranslateSummaryRunes])
+}
@@ -0,0 +1,237 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"gist/backend/internal/model"
+	repomock "gist/backend/internal/repository/mock"
+	"gist/backend/internal/service"
+	servicemock "gist/backend/internal/service/mock"
+)
+
+func autoTranslateEntries(feedID int64, titles ...string) []model.Entry {
+	entries := make([]model.Entry, 0, len(titles))
+	for i, title := range titles {
+		title := title
+		entries = append(entries, model.Entry{ID: feedID*1000 + int64(i), FeedID: feedID, Title: &title})
+	}
+	return entries
+}
+
+func batchChannels(articles []service.BatchArticleInput, cached, failed int) (<-chan service.BatchTranslateResult, <-chan error) {
+	results := make(chan service.BatchTranslateResult, len(articles))
+	errs := make(chan error, len(articles))
+	for i, article := range articles {
+		switch {
+		case i < failed:
+			errs <- fmt.Errorf("translate %s failed", article.ID)
+		default:
+			results <- service.BatchTranslateResult{ID: article.ID, Title: &article.Title, Cached: i < failed+cached}
+		}
+	}
+	close(results)
+	close(errs)
+	return results, errs
+}
+
+func TestAutoTranslateService_Run(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	translations := repomock.NewMockAIListTranslationRepository(ctrl)
+	aiService := servicemock.NewMockAIService(ctrl)
+	settings := servicemock.NewMockSettingsService(ctrl)
+	svc := service.NewAutoTranslateService(translations, aiService, settings, nil, nil)
+	t.Cleanup(svc.Close)
+	ctx := context.Background()
+
+	titles := make([]string, 55)
+	for i := range titles {
+		titles[i] = fmt.Sprintf("Post %d", i)
+	}
+	entries := autoTranslateEntries(1, titles...)
+	// A feed already in the target language and an entry without text are skipped
+	entries = append(entries, autoTranslateEntries(2, "新的文章", "另一篇文章", "Go 1.30 发布")...)
+	entries = append(entries, model.Entry{ID: 9, FeedID: 1})
+
+	settings.EXPECT().GetAISettings(gomock.Any()).Return(&service.AISettings{}, nil)
+	// Quiet hours are checked between batches
+	settings.EXPECT().GetGeneralSettings(gomock.Any()).Return(&service.GeneralSettings{}, nil).MinTimes(1)
+	aiService.EXPECT().GetSummaryLanguage(gomock.Any()).Return("zh-CN")
+	translations.EXPECT().ListUntranslated(gomock.Any(), "zh-CN", []string{model.FeedAutoTranslateOn}, gomock.Any(), 500).Return(entries, nil)
+
+	var batches []int
+	aiService.EXPECT().TranslateBatch(gomock.Any(), gomock.Any(), "zh-CN").DoAndReturn(
+		func(_ context.Context, articles []service.BatchArticleInput, _ string) (<-chan service.BatchTranslateResult, <-chan error, error) {
+			batches = append(batches, len(articles))
+			for _, article := range articles {
+				require.NotEqual(t, "2", article.ID[:1])
+			}
+			if len(batches) == 1 {
+				results, errs := batchChannels(articles, 1, 0)
+				return results, errs, nil
+			}
+			results, errs := batchChannels(articles, 0, 1)
+			return results, errs, nil
+		},
+	).Times(2)
+
+	require.NoError(t, svc.Run(ctx))
+	require.Equal(t, []int{50, 5}, batches)
+
+	settings.EXPECT().GetAISettings(gomock.Any()).Return(&service.AISettings{TranslateOnRefresh: true}, nil)
+	aiService.EXPECT().GetSummaryLanguage(gomock.Any()).Return("zh-CN")
+	status := svc.Status(ctx)
+	require.True(t, status.Enabled)
+	require.False(t, status.Running)
+	require.Equal(t, "zh-CN", status.Language)
+	require.NotNil(t, status.LastRun)
+	require.Equal(t, 59, status.LastRun.Entries)
+	require.Equal(t, 4, status.LastRun.Skipped)
+	require.Equal(t, 53, status.LastRun.Translated)
+	require.Equal(t, 1, status.LastRun.Failed)
+	require.Empty(t, status.LastRun.Error)
+
+	// The next run starts where the last one started and includes inherit feeds
+	settings.EXPECT().GetAISettings(gomock.Any()).Return(&service.AISettings{TranslateOnRefresh: true}, nil)
+	aiService.EXPECT().GetSummaryLanguage(gomock.Any()).Return("zh-CN")
+	translations.EXPECT().ListUntranslated(gomock.Any(), "zh-CN", []string{model.FeedAutoTranslateOn, model.FeedAutoTranslateInherit}, status.LastRun.StartedAt, 500).Return(nil, nil)
+	require.NoError(t, svc.Run(ctx))
+}
+
+func TestAutoTranslateService_UsesDetectedLanguage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	translations := repomock.NewMockAIListTranslationRepository(ctrl)
+	aiService := servicemock.NewMockAIService(ctrl)
+	settings := servicemock.NewMockSettingsService(ctrl)
+	svc := service.NewAutoTranslateService(translations, aiService, settings, nil, nil)
+	t.Cleanup(svc.Close)
+	ctx := context.Background()
+
+	// Feed 1 declares its entries' languages; feed 2 declares none and falls
+	// back to its titles
+	entries := autoTranslateEntries(1, "Release notes", "Weekly update")
+	entries[0].Language = "ja"
+	entries[1].Language = "en"
+	entries = append(entries, autoTranslateEntries(2, "Hello", "World")...)
+
+	settings.EXPECT().GetAISettings(gomock.Any()).Return(&service.AISettings{}, nil)
+	aiService.EXPECT().GetSummaryLanguage(gomock.Any()).Return("en-US")
+	translations.EXPECT().ListUntranslated(gomock.Any(), "en-US", gomock.Any(), gomock.Any(), 500).Return(entries, nil)
+	aiService.EXPECT().TranslateBatch(gomock.Any(), gomock.Any(), "en-US").DoAndReturn(
+		func(_ context.Context, articles []service.BatchArticleInput, _ string) (<-chan service.BatchTranslateResult, <-chan error, error) {
+			require.Len(t, articles, 1)
+			require.Equal(t, "Release notes", articles[0].Title)
+			results, errs := batchChannels(articles, 0, 0)
+			return results, errs, nil
+		},
+	)
+
+	require.NoError(t, svc.Run(ctx))
+}
+
+func TestAutoTranslateService_FailedRunIsRetried(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	translations := repomock.NewMockAIListTranslationRepository(ctrl)
+	aiService := servicemock.NewMockAIService(ctrl)
+	settings := servicemock.NewMockSettingsService(ctrl)
+	svc := service.NewAutoTranslateService(translations, aiService, settings, nil, nil)
+	t.Cleanup(svc.Close)
+	ctx := context.Background()
+
+	var firstSince time.Time
+	settings.EXPECT().GetAISettings(gomock.Any()).Return(&service.AISettings{}, nil).Times(2)
+	aiService.EXPECT().GetSummaryLanguage(gomock.Any()).Return("en-US").Times(2)
+	translations.EXPECT().ListUntranslated(gomock.Any(), "en-US", gomock.Any(), gomock.Any(), 500).DoAndReturn(
+		func(_ context.Context, _ string, _ []string, since time.Time, _ int) ([]model.Entry, error) {
+			firstSince = since
+			return autoTranslateEntries(1, "新的文章"), nil
+		},
+	)
+	aiService.EXPECT().TranslateBatch(gomock.Any(), gomock.Any(), "en-US").Return(nil, nil, errors.New("AI is not configured"))
+
+	require.ErrorContains(t, svc.Run(ctx), "AI is not configured")
+
+	translations.EXPECT().ListUntranslated(gomock.Any(), "en-US", gomock.Any(), firstSince, 500).Return(nil, nil)
+	require.NoError(t, svc.Run(ctx))
+}
+
+func TestAutoTranslateService_CloseStopsRun(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	translations := repomock.NewMockAIListTranslationRepository(ctrl)
+	aiService := servicemock.NewMockAIService(ctrl)
+	settings := servicemock.NewMockSettingsService(ctrl)
+	svc := service.NewAutoTranslateService(translations, aiService, settings, nil, nil)
+
+	settings.EXPECT().GetAISettings(gomock.Any()).Return(&service.AISettings{}, nil)
+	aiService.EXPECT().GetSummaryLanguage(gomock.Any()).Return("zh-CN")
+	translations.EXPECT().ListUntranslated(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(autoTranslateEntries(1, "Hello"), nil)
+	started := make(chan struct{})
+	aiService.EXPECT().TranslateBatch(gomock.Any(), gomock.Any(), "zh-CN").DoAndReturn(
+		func(ctx context.Context, _ []service.BatchArticleInput, _ string) (<-chan service.BatchTranslateResult, <-chan error, error) {
+			close(started)
+			<-ctx.Done()
+			results, errs := batchChannels(nil, 0, 0)
+			return results, errs, nil
+		},
+	)
+
+	done := make(chan error, 1)
+	go func() { done <- svc.Run(context.Background()) }()
+	<-started
+
+	// A run already in progress makes another one return at once
+	require.NoError(t, svc.Run(context.Background()))
+
+	svc.Close()
+	select {
+	case err := <-done:
+		require.ErrorIs(t, err, context.Canceled)
+	case <-time.After(time.Second):
+		t.Fatal("run did not stop")
+	}
+
+	// A closed service does not run again
+	require.NoError(t, svc.Run(context.Background()))
+}
+
+func TestAutoTranslateService_FolderDefaults(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	translations := repomock.NewMockAIListTranslationRepository(ctrl)
+	feeds := repomock.NewMockFeedRepository(ctrl)
+	folders := repomock.NewMockFolderRepository(ctrl)
+	aiService := servicemock.NewMockAIService(ctrl)
+	settings := servicemock.NewMockSettingsService(ctrl)
+	svc := service.NewAutoTranslateService(translations, aiService, settings, feeds, folders)
+	t.Cleanup(svc.Close)
+	ctx := context.Background()
+
+	on, off := true, false
+	parentID, childID, quietID := int64(1), int64(2), int64(3)
+	folders.EXPECT().List(gomock.Any()).Return([]model.Folder{
+		{ID: parentID, Name: "Blogs", FeedDefaults: model.FeedDefaults{AutoTranslate: &on}},
+		{ID: childID, Name: "Japanese blogs", ParentID: &parentID},
+		{ID: quietID, Name: "Quiet", FeedDefaults: model.FeedDefaults{AutoTranslate: &off}},
+	}, nil)
+	feeds.EXPECT().List(gomock.Any(), nil).Return([]model.Feed{
+		// Inherits on from the parent of its folder
+		{ID: 10, FolderID: &childID, AutoTranslate: model.FeedAutoTranslateInherit},
+		// Its own off beats the folders
+		{ID: 11, FolderID: &childID, AutoTranslate: model.FeedAutoTranslateOff},
+		// Its own on beats the folder's off
+		{ID: 12, FolderID: &quietID, AutoTranslate: model.FeedAutoTranslateOn},
+		{ID: 13, FolderID: &quietID, AutoTranslate: model.FeedAutoTranslateInherit},
+		// Follows the global setting, off here
+		{ID: 14, AutoTranslate: model.FeedAutoTranslateInherit},
+	}, nil)
+	settings.EXPECT().GetAISettings(gomock.Any()).Return(&service.AISettings{}, nil)
+	aiService.EXPECT().GetSummaryLanguage(gomock.Any()).Return("zh-CN")
+	translations.EXPECT().ListUntranslatedInFeeds(gomock.Any(), "zh-CN", []int64{10, 12}, gomock.Any(), 500).Return(nil, nil)
+
+	require.NoError(t, svc.Run(ctx))
+}
@@ -0,0 +1,548 @@
+//go:generate mockgen -source=$GOFILE -destination=mock/$GOFILE -package=mock
+package service
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"gist/backend/internal/model"
+	"gist/backend/internal/repository"
+	"gist/backend/pkg/logger"
+)
+
+// BackupFormatVersion is written to full exports; imports reject any other version.
+const BackupFormatVersion = 1
+
+// backupBatchSize is how many entries are read per export page and written per import transaction.
+const backupBatchSize = 500
+
+// BackupService exports subscriptions and entry state as one JSON document and
+// imports such documents back, matching feeds by canonical URL and entries by hash.
+type BackupService interface {
+	// Export streams the backup document to w.
+	Export(ctx context.Context, w io.Writer, opts BackupExportOptions) error
+	// Import reads a backup document from r without loading it whole. Importing the
+	// same document twice creates nothing the second time.
+	Import(ctx context.Context, r io.Reader) (BackupImportResult, error)
+}
+
+// BackupExportOptions selects which entries go into an export.
+type BackupExportOptions struct {
+	// Entries is one of the BackupEntries* constants.
+	Entries string
+}
+
+// Entry selections for BackupExportOptions.Entries.
+const (
+	BackupEntriesUnreadStarred = "unread_starred"
+	BackupEntriesAll           = "all"
+	BackupEntriesNone          = "none"
+)
+
+// BackupSectionResult counts what an import did with the records of one section.
+type BackupSectionResult struct {
+	Created int `json:"created"`
+	Updated int `json:"updated"`
+	Skipped int `json:"skipped"`
+}
+
+// BackupImportResult summarizes an import per section.
+type BackupImportResult struct {
+	Folders BackupSectionResult `json:"folders"`
+	Feeds   BackupSectionResult `json:"feeds"`
+	Entries BackupSectionResult `json:"entries"`
+}
+
+type backupFolder struct {
+	ID           int64               `json:"id,string"`
+	Name         string              `json:"name"`
+	ParentID     *int64              `json:"parentId,string,omitempty"`
+	Type         string              `json:"type"`
+	SortOrder    int                 `json:"sortOrder"`
+	FeedDefaults *backupFeedDefaults `json:"feedDefaults,omitempty"`
+}
+
+type backupFeedDefaults struct {
+	UserAgent       *string `json:"userAgent,omitempty"`
+	AutoReadability *bool   `json:"autoReadability,omitempty"`
+	AutoTranslate   *bool   `json:"autoTranslate,omitempty"`
+}
+
+type backupFeed struct {
+	ID                    int64      `json:"id,string"`
+	FolderID              *int64     `json:"folderId,string,omitempty"`
+	Title                 string     `json:"title"`
+	CustomTitle           *string    `json:"customTitle,omitempty"`
+	URL                   string     `json:"url"`
+	SiteURL               *string    `json:"siteUrl,omitempty"`
+	Description           *string    `json:"description,omitempty"`
+	SummaryPromptReminder *string    `json:"summaryPromptReminder,omitempty"`
+	AssumeTimezone        *string    `json:"assumeTimezone,omitempty"`
+	PausedUntil           *time.Time `json:"pausedUntil,omitempty"`
+	DedupeKey             string     `json:"dedupeKey"`
+	Type                  string     `json:"type"`
+	ETag                  *string    `json:"etag,omitempty"`
+	LastModified          *string    `json:"lastModified,omitempty"`
+	IconPath              *string    `json:"iconPath,omitempty"`
+	SortOrder             int        `json:"sortOrder"`
+	MaxEntries            int        `json:"maxEntries,omitempty"`
+	AutoTranslate         string     `json:"autoTranslate,omitempty"`
+	UserAgent             *string    `json:"userAgent,omitempty"`
+	AutoReadability       *bool      `json:"autoReadability,omitempty"`
+}
+
+type backupEntry struct {
+	FeedID          int64      `json:"feedId,string"`
+	Hash            string     `json:"hash"`
+	Title           *string    `json:"title,omitempty"`
+	URL             *string    `json:"url,omitempty"`
+	Content         *string    `json:"content,omitempty"`
+	ReadableContent *string    `json:"readableContent,omitempty"`
+	ThumbnailURL    *string    `json:"thumbnailUrl,omitempty"`
+	Author          *string    `json:"author,omitempty"`
+	PublishedAt     *time.Time `json:"publishedAt,omitempty"`
+	Read            bool       `json:"read"`
+	Starred         bool       `json:"starred"`
+	WordCount       int        `json:"wordCount"`
+	CreatedAt       time.Time  `json:"createdAt"`
+	Note            *string    `json:"note,omitempty"`
+}
+
+type backupService struct {
+	folders repository.FolderRepository
+	feeds   repository.FeedRepository
+	entries repository.EntryRepository
+}
+
+// NewBackupService creates a new backup service.
+func NewBackupService(folders repository.FolderRepository, feeds repository.FeedRepository, entries repository.EntryRepository) BackupService {
+	return &backupService{folders: folders, feeds: feeds, entries: entries}
+}
+
+func (s *backupService) Export(ctx context.Context, w io.Writer, opts BackupExportOptions) error {
+	folders, err := s.folders.List(ctx)
+	if err != nil {
+		return fmt.Errorf("list folders: %w", err)
+	}
+	feeds, err := s.feeds.List(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("list feeds: %w", err)
+	}
+
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+	exportedAt, _ := json.Marshal(time.Now().UTC().Format(time.RFC3339))
+	fmt.Fprintf(bw, `{"version":%d,"exportedAt":%s,"folders":`, BackupFormatVersion, exportedAt)
+	if err := enc.Encode(toBackupFolders(folders)); err != nil {
+		return fmt.Errorf("encode folders: %w", err)
+	}
+	bw.WriteString(`,"feeds":`)
+	if err := enc.Encode(toBackupFeeds(feeds)); err != nil {
+		return fmt.Errorf("encode feeds: %w", err)
+	}
+
+	bw.WriteString(`,"entries":[`)
+	count := 0
+	if opts.Entries != BackupEntriesNone {
+		includeRead := opts.Entries == BackupEntriesAll
+		var afterID int64
+		for {
+			page, err := s.entries.ListForBackup(ctx, includeRead, afterID, backupBatchSize)
+			if err != nil {
+				return fmt.Errorf("list entries: %w", err)
+			}
+			for _, entry := range page {
+				if count > 0 {
+					bw.WriteString(",")
+				}
+				if err := enc.Encode(toBackupEntry(entry)); err != nil {
+					return fmt.Errorf("encode entry: %w", err)
+				}
+				count++
+			}
+			if len(page) < backupBatchSize {
+				break
+			}
+			afterID = page[len(page)-1].ID
+		}
+	}
+	bw.WriteString("]}\n")
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("write backup: %w", err)
+	}
+
+	logger.Info("backup export completed", "module", "service", "action", "export", "resource", "backup", "result", "ok", "folders", len(folders), "feeds", len(feeds), "entries", count)
+	return nil
+}
+
+// toBackupFolders orders folders so every parent comes before its children,
+// which lets Import resolve parents in a single pass.
+func toBackupFolders(folders []model.Folder) []backupFolder {
+	byID := make(map[int64]model.Folder, len(folders))
+	for _, folder := range folders {
+		byID[folder.ID] = folder
+	}
+	depth := func(folder model.Folder) int {
+		d := 0
+		for folder.ParentID != nil && d <= len(folders) {
+			parent, ok := byID[*folder.ParentID]
+			if !ok {
+				break
+			}
+			folder = parent
+			d++
+		}
+		return d
+	}
+	sorted := append([]model.Folder(nil), folders...)
+	sort.SliceStable(sorted, func(i, j int) bool { return depth(sorted[i]) < depth(sorted[j]) })
+
+	result := make([]backupFolder, 0, len(sorted))
+	for _, folder := range sorted {
+		item := backupFolder{
+			ID:        folder.ID,
+			Name:      folder.Name,
+			ParentID:  folder.ParentID,
+			Type:      folder.Type,
+			SortOrder: folder.SortOrder,
+		}
+		if defaults := folder.FeedDefaults; defaults != (model.FeedDefaults{}) {
+			item.FeedDefaults = &backupFeedDefaults{
+				UserAgent:       defaults.UserAgent,
+				AutoReadability: defaults.AutoReadability,
+				AutoTranslate:   defaults.AutoTranslate,
+			}
+		}
+		result = append(result, item)
+	}
+	return result
+}
+
+func toBackupFeeds(feeds []model.Feed) []backupFeed {
+	result := make([]backupFeed, 0, len(feeds))
+	for _, feed := range feeds {
+		result = append(result, backupFeed{
+			ID:                    feed.ID,
+			FolderID:              feed.FolderID,
+			Title:                 feed.Title,
+			CustomTitle:           feed.CustomTitle,
+			URL:                   feed.URL,
+			SiteURL:               feed.SiteURL,
+			Description:           feed.Description,
+			SummaryPromptReminder: feed.SummaryPromptReminder,
+			AssumeTimezone:        feed.AssumeTimezone,
+			PausedUntil:           feed.PausedUntil,
+			DedupeKey:             feed.DedupeKey,
+			Type:                  feed.Type,
+			ETag:                  feed.ETag,
+			LastModified:          feed.LastModified,
+			IconPath:              feed.IconPath,
+			SortOrder:             feed.SortOrder,
+			MaxEntries:            feed.MaxEntries,
+			AutoTranslate:         feed.AutoTranslate,
+			UserAgent:             feed.UserAgent,
+			AutoReadability:       feed.AutoReadability,
+		})
+	}
+	return result
+}
+
+func toBackupEntry(entry model.Entry) backupEntry {
+	return backupEntry{
+		FeedID:          entry.FeedID,
+		Hash:            entry.Hash,
+		Title:           entry.Title,
+		URL:             entry.URL,
+		Content:         entry.Content,
+		ReadableContent: entry.ReadableContent,
+		ThumbnailURL:    entry.ThumbnailURL,
+		Author:          entry.Author,
+		PublishedAt:     entry.PublishedAt,
+		Read:            entry.Read,
+		Starred:         entry.Starred,
+		WordCount:       entry.WordCount,
+		CreatedAt:       entry.CreatedAt,
+		Note:            entry.Note,
+	}
+}
+
+// backupImport carries the id mappings from the exported instance to this one.
+type backupImport struct {
+	folderIDs map[int64]int64
+	feedIDs   map[int64]int64
+	result    BackupImportResult
+}
+
+func (s *backupService) Import(ctx context.Context, r io.Reader) (BackupImportResult, error) {
+	dec := json.NewDecoder(r)
+	if err := expectDelim(dec, '{'); err != nil {
+		return BackupImportResult{}, err
+	}
+
+	state := &backupImport{folderIDs: make(map[int64]int64), feedIDs: make(map[int64]int64)}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return state.result, backupDecodeError("backup", err)
+		}
+		key, _ := tok.(string)
+		switch key {
+		case "version":
+			var version int
+			if err := dec.Decode(&version); err != nil || version != BackupFormatVersion {
+				return state.result, fmt.Errorf("unsupported backup version: %w", ErrInvalid)
+			}
+		case "folders":
+			err = decodeArray(dec, func() error {
+				var folder backupFolder
+				if err := dec.Decode(&folder); err != nil {
+					return backupDecodeError("folder", err)
+				}
+				return s.importFolder(ctx, state, folder)
+			})
+		case "feeds":
+			err = decodeArray(dec, func() error {
+				var feed backupFeed
+				if err := dec.Decode(&feed); err != nil {
+					return backupDecodeError("feed", err)
+				}
+				return s.importFeed(ctx, state, feed)
+			})
+		case "entries":
+			err = s.importEntries(ctx, dec, state)
+		default:
+			var skip json.RawMessage
+			if err = dec.Decode(&skip); err != nil {
+				err = backupDecodeError(key, err)
+			}
+		}
+		if err != nil {
+			logger.Warn("backup import failed", "module", "service", "action", "import", "resource", "backup", "result", "failed", "section", key, "error", err)
+			return state.result, err
+		}
+	}
+
+	logger.Info("backup import completed", "module", "service", "action", "import", "resource", "backup", "result", "ok",
+		"folders_created", state.result.Folders.Created, "feeds_created", state.result.Feeds.Created,
+		"entries_created", state.result.Entries.Created, "entries_updated", state.result.Entries.Updated)
+	return state.result, nil
+}
+
+func (s *backupService) importFolder(ctx context.Context, state *backupImport, folder backupFolder) error {
+	var parentID *int64
+	if folder.ParentID != nil {
+		if id, ok := state.folderIDs[*folder.ParentID]; ok {
+			parentID = &id
+		}
+	}
+	name := strings.TrimSpace(folder.Name)
+	if name == "" {
+		state.result.Folders.Skipped++
+		return nil
+	}
+
+	existing, err := s.folders.FindByName(ctx, name, parentID)
+	if err != nil {
+		return fmt.Errorf("find folder: %w", err)
+	}
+	if existing != nil {
+		state.folderIDs[folder.ID] = existing.ID
+		state.result.Folders.Skipped++
+		return nil
+	}
+	created, err := s.folders.Create(ctx, name, parentID, folder.Type)
+	if errors.Is(err, repository.ErrConflict) {
+		// Created concurrently since the lookup above
+		if existing, findErr := s.folders.FindByName(ctx, name, parentID); findErr == nil && existing != nil {
+			state.folderIDs[folder.ID] = existing.ID
+			state.result.Folders.Skipped++
+			return nil
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("create folder: %w", err)
+	}
+	if defaults := folder.FeedDefaults; defaults != nil {
+		err := s.folders.UpdateFeedDefaults(ctx, created.ID, model.FeedDefaults{
+			UserAgent:       defaults.UserAgent,
+			AutoReadability: defaults.AutoReadability,
+			AutoTranslate:   defaults.AutoTranslate,
+		})
+		if err != nil {
+			return fmt.Errorf("set folder feed defaults: %w", err)
+		}
+	}
+	state.folderIDs[folder.ID] = created.ID
+	state.result.Folders.Created++
+	return nil
+}
+
+func (s *backupService) importFeed(ctx context.Context, state *backupImport, feed backupFeed) error {
+	if strings.TrimSpace(feed.URL) == "" {
+		state.result.Feeds.Skipped++
+		return nil
+	}
+	existing, err := s.feeds.FindByURL(ctx, feed.URL)
+	if err != nil {
+		return fmt.Errorf("find feed: %w", err)
+	}
+	if existing != nil {
+		// A soft-deleted feed still owns its URL, but its entries should stay gone
+		if existing.DeletedAt == nil {
+			state.feedIDs[feed.ID] = existing.ID
+		}
+		state.result.Feeds.Skipped++
+		return nil
+	}
+
+	var folderID *int64
+	if feed.FolderID != nil {
+		if id, ok := state.folderIDs[*feed.FolderID]; ok {
+			folderID = &id
+		}
+	}
+	created, err := s.feeds.Create(ctx, model.Feed{
+		FolderID:              folderID,
+		Title:                 feed.Title,
+		CustomTitle:           feed.CustomTitle,
+		URL:                   feed.URL,
+		SiteURL:               feed.SiteURL,
+		Description:           feed.Description,
+		SummaryPromptReminder: feed.SummaryPromptReminder,
+		AssumeTimezone:        feed.AssumeTimezone,
+		PausedUntil:           feed.PausedUntil,
+		DedupeKey:             feed.DedupeKey,
+		Type:                  feed.Type,
+		ETag:                  feed.ETag,
+		LastModified:          feed.LastModified,
+	})
+	if errors.Is(err, repository.ErrConflict) {
+		// Subscribed concurrently since the lookup above
+		if existing, findErr := s.feeds.FindByURL(ctx, feed.URL); findErr == nil && existing != nil {
+			if existing.DeletedAt == nil {
+				state.feedIDs[feed.ID] = existing.ID
+			}
+			state.result.Feeds.Skipped++
+			return nil
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("create feed: %w", err)
+	}
+	// Create leaves out the retention cap, the translation choice and the
+	// options a feed can inherit from its folder
+	switch feed.AutoTranslate {
+	case model.FeedAutoTranslateOn, model.FeedAutoTranslateOff:
+	default:
+		feed.AutoTranslate = model.FeedAutoTranslateInherit
+	}
+	if feed.MaxEntries > 0 || feed.AutoTranslate != model.FeedAutoTranslateInherit || feed.UserAgent != nil || feed.AutoReadability != nil {
+		created.MaxEntries = max(feed.MaxEntries, 0)
+		created.AutoTranslate = feed.AutoTranslate
+		created.UserAgent = feed.UserAgent
+		created.AutoReadability = feed.AutoReadability
+		if created, err = s.feeds.Update(ctx, created); err != nil {
+			return fmt.Errorf("set feed settings: %w", err)
+		}
+	}
+	// A missing icon file is fetched again when the icon is first requested
+	if feed.IconPath != nil && *feed.IconPath != "" {
+		if err := s.feeds.UpdateIconPath(ctx, created.ID, *feed.IconPath); err != nil {
+			return fmt.Errorf("set feed icon: %w", err)
+		}
+	}
+	state.feedIDs[feed.ID] = created.ID
+	state.result.Feeds.Created++
+	return nil
+}
+
+func (s *backupService) importEntries(ctx context.Context, dec *json.Decoder, state *backupImport) error {
+	batch := make([]model.Entry, 0, backupBatchSize)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		created, updated, skipped, err := s.entries.ImportBatch(ctx, batch)
+		if err != nil {
+			return fmt.Errorf("import entries: %w", err)
+		}
+		state.result.Entries.Created += created
+		state.result.Entries.Updated += updated
+		state.result.Entries.Skipped += skipped
+		batch = batch[:0]
+		return nil
+	}
+
+	err := decodeArray(dec, func() error {
+		var entry backupEntry
+		if err := dec.Decode(&entry); err != nil {
+			return backupDecodeError("entry", err)
+		}
+		feedID, ok := state.feedIDs[entry.FeedID]
+		if !ok || entry.Hash == "" {
+			state.result.Entries.Skipped++
+			return nil
+		}
+		batch = append(batch, model.Entry{
+			FeedID:          feedID,
+			Hash:            entry.Hash,
+			Title:           entry.Title,
+			URL:             entry.URL,
+			Content:         entry.Content,
+			ReadableContent: entry.ReadableContent,
+			ThumbnailURL:    entry.ThumbnailURL,
+			Author:          entry.Author,
+			PublishedAt:     entry.PublishedAt,
+			Read:            entry.Read,
+			Starred:         entry.Starred,
+			WordCount:       entry.WordCount,
+			CreatedAt:       entry.CreatedAt,
+			Note:            entry.Note,
+		})
+		if len(batch) >= backupBatchSize {
+			return flush()
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return flush()
+}
+
+// decodeArray calls decodeItem once per element of the JSON array at the decoder's position.
+func decodeArray(dec *json.Decoder, decodeItem func() error) error {
+	if err := expectDelim(dec, '['); err != nil {
+		return err
+	}
+	for dec.More() {
+		if err := decodeItem(); err != nil {
+			return err
+		}
+	}
+	return expectDelim(dec, ']')
+}
+
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return backupDecodeError("backup", err)
+	}
+	if d, ok := tok.(json.Delim); !ok || d != delim {
+		return fmt.Errorf("expected %s in backup: %w", delim, ErrInvalid)
+	}
+	return nil
+}
+
+// backupDecodeError marks a malformed document as ErrInvalid while keeping the
+// read error, so callers can still tell an oversized body apart.
+func backupDecodeError(what string, err error) error {
+	return fmt.Errorf("decode %s: %w: %w", what, ErrInvalid, err)
+}
@@ -0,0 +1,216 @@
+package service_test
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"gist/backend/internal/model"
+	"gist/backend/internal/repository"
+	"gist/backend/internal/repository/testutil"
+	"gist/backend/internal/service"
+)
+
+func newBackupService(db *sql.DB) service.BackupService {
+	return service.NewBackupService(repository.NewFolderRepository(db, nil, nil), repository.NewFeedRepository(db, nil, nil), repository.NewEntryRepository(db, nil, nil))
+}
+
+func seedBackupSource(t *testing.T, db *sql.DB) (int64, int64) {
+	t.Helper()
+	parentID := testutil.SeedFolder(t, db, "Tech", nil, "article")
+	childID := testutil.SeedFolder(t, db, "Go", &parentID, "article")
+	etag := `"abc"`
+	icon := "example.com.png"
+	feedID := testutil.SeedFeed(t, db, model.Feed{FolderID: &childID, Title: "Go Blog", URL: "https://go.dev/blog/feed.atom", ETag: &etag, IconPath: &icon})
+
+	title := "Unread"
+	testutil.SeedEntry(t, db, model.Entry{FeedID: feedID, Hash: "unread", Title: &title})
+	testutil.SeedEntry(t, db, model.Entry{FeedID: feedID, Hash: "starred", Read: true, Starred: true})
+	testutil.SeedEntry(t, db, model.Entry{FeedID: feedID, Hash: "read", Read: true})
+	return childID, feedID
+}
+
+func TestBackupService_Export(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	childID, feedID := seedBackupSource(t, db)
+	svc := newBackupService(db)
+
+	var buf bytes.Buffer
+	require.NoError(t, svc.Export(context.Background(), &buf, service.BackupExportOptions{Entries: service.BackupEntriesUnreadStarred}))
+
+	var doc struct {
+		Version int `json:"version"`
+		Folders []struct {
+			ID       string  `json:"id"`
+			ParentID *string `json:"parentId"`
+		} `json:"folders"`
+		Feeds []struct {
+			ID       string `json:"id"`
+			FolderID string `json:"folderId"`
+			ETag     string `json:"etag"`
+			IconPath string `json:"iconPath"`
+		} `json:"feeds"`
+		Entries []struct {
+			FeedID string `json:"feedId"`
+			Hash   string `json:"hash"`
+		} `json:"entries"`
+	}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &doc))
+	require.Equal(t, service.BackupFormatVersion, doc.Version)
+
+	// Parents come first so imports can resolve them in one pass
+	require.Len(t, doc.Folders, 2)
+	require.Nil(t, doc.Folders[0].ParentID)
+	require.Equal(t, strconv.FormatInt(childID, 10), doc.Folders[1].ID)
+
+	require.Len(t, doc.Feeds, 1)
+	require.Equal(t, strconv.FormatInt(feedID, 10), doc.Feeds[0].ID)
+	require.Equal(t, strconv.FormatInt(childID, 10), doc.Feeds[0].FolderID)
+	require.Equal(t, `"abc"`, doc.Feeds[0].ETag)
+	require.Equal(t, "example.com.png", doc.Feeds[0].IconPath)
+
+	hashes := make([]string, 0, len(doc.Entries))
+	for _, entry := range doc.Entries {
+		hashes = append(hashes, entry.Hash)
+	}
+	require.ElementsMatch(t, []string{"unread", "starred"}, hashes)
+
+	buf.Reset()
+	require.NoError(t, svc.Export(context.Background(), &buf, service.BackupExportOptions{Entries: service.BackupEntriesNone}))
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &doc))
+	require.Empty(t, doc.Entries)
+}
+
+func TestBackupService_ImportRoundTrip(t *testing.T) {
+	source := testutil.NewTestDB(t)
+	seedBackupSource(t, source)
+
+	var buf bytes.Buffer
+	require.NoError(t, newBackupService(source).Export(context.Background(), &buf, service.BackupExportOptions{Entries: service.BackupEntriesAll}))
+	backup := buf.Bytes()
+
+	target := testutil.NewTestDB(t)
+	svc := newBackupService(target)
+	ctx := context.Background()
+
+	result, err := svc.Import(ctx, bytes.NewReader(backup))
+	require.NoError(t, err)
+	require.Equal(t, service.BackupImportResult{
+		Folders: service.BackupSectionResult{Created: 2},
+		Feeds:   service.BackupSectionResult{Created: 1},
+		Entries: service.BackupSectionResult{Created: 3},
+	}, result)
+
+	feed, err := repository.NewFeedRepository(target, nil, nil).FindByURL(ctx, "https://go.dev/blog/feed.atom")
+	require.NoError(t, err)
+	require.NotNil(t, feed)
+	require.Equal(t, `"abc"`, *feed.ETag)
+	require.Equal(t, "example.com.png", *feed.IconPath)
+	require.NotNil(t, feed.FolderID)
+	folder, err := repository.NewFolderRepository(target, nil, nil).GetByID(ctx, *feed.FolderID)
+	require.NoError(t, err)
+	require.Equal(t, "Go", folder.Name)
+	require.NotNil(t, folder.ParentID)
+
+	entries, err := repository.NewEntryRepository(target, nil, nil).ListForBackup(ctx, true, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	for _, entry := range entries {
+		require.Equal(t, feed.ID, entry.FeedID)
+		require.Equal(t, entry.Hash == "starred", entry.Starred)
+	}
+
+	// Importing the same document again changes nothing
+	result, err = svc.Import(ctx, bytes.NewReader(backup))
+	require.NoError(t, err)
+	require.Equal(t, service.BackupImportResult{
+		Folders: service.BackupSectionResult{Skipped: 2},
+		Feeds:   service.BackupSectionResult{Skipped: 1},
+		Entries: service.BackupSectionResult{Skipped: 3},
+	}, result)
+}
+
+func TestBackupService_ImportRoundTrip_FeedFields(t *testing.T) {
+	source := testutil.NewTestDB(t)
+	ctx := context.Background()
+	folderID := testutil.SeedFolder(t, source, "Photos", nil, "picture")
+	defaults := model.FeedDefaults{UserAgent: stringPtr("FolderBot/1.0"), AutoReadability: boolPtr(true), AutoTranslate: boolPtr(false)}
+	require.NoError(t, repository.NewFolderRepository(source, nil, nil).UpdateFeedDefaults(ctx, folderID, defaults))
+	pausedUntil := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+	sourceFeeds := repository.NewFeedRepository(source, nil, nil)
+	want, err := sourceFeeds.Create(ctx, model.Feed{
+		FolderID:              &folderID,
+		Title:                 "Gallery",
+		CustomTitle:           stringPtr("My gallery"),
+		URL:                   "https://example.com/gallery.xml",
+		SiteURL:               stringPtr("https://example.com"),
+		Description:           stringPtr("Pictures"),
+		SummaryPromptReminder: stringPtr("Describe the photo"),
+		AssumeTimezone:        stringPtr("Asia/Tokyo"),
+		PausedUntil:           &pausedUntil,
+		DedupeKey:             model.DedupeKeyURL,
+		Type:                  "picture",
+		ETag:                  stringPtr(`"v1"`),
+		LastModified:          stringPtr("Mon, 02 Jan 2006 15:04:05 GMT"),
+	})
+	require.NoError(t, err)
+	want.MaxEntries = 25
+	want.AutoTranslate = model.FeedAutoTranslateOff
+	want.UserAgent = stringPtr("FeedBot/2.0")
+	want.AutoReadability = boolPtr(false)
+	_, err = sourceFeeds.Update(ctx, want)
+	require.NoError(t, err)
+	require.NoError(t, sourceFeeds.UpdateIconPath(ctx, want.ID, "example.com.png"))
+	want, err = sourceFeeds.GetByID(ctx, want.ID)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, newBackupService(source).Export(ctx, &buf, service.BackupExportOptions{Entries: service.BackupEntriesNone}))
+	target := testutil.NewTestDB(t)
+	_, err = newBackupService(target).Import(ctx, &buf)
+	require.NoError(t, err)
+
+	got, err := repository.NewFeedRepository(target, nil, nil).FindByURL(ctx, want.URL)
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	require.NotNil(t, got.FolderID)
+	folder, err := repository.NewFolderRepository(target, nil, nil).GetByID(ctx, *got.FolderID)
+	require.NoError(t, err)
+	require.Equal(t, defaults, folder.FeedDefaults)
+	// Only the ids and the row timestamps are local to each instance
+	want.ID, want.FolderID, want.CreatedAt, want.UpdatedAt = got.ID, got.FolderID, got.CreatedAt, got.UpdatedAt
+	require.Equal(t, want, *got)
+}
+
+func TestBackupService_ImportSkipsUnknownFeeds(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	svc := newBackupService(db)
+
+	doc := `{"version":1,"folders":[],"feeds":[],"entries":[{"feedId":"42","hash":"h","read":false,"starred":false}]}`
+	result, err := svc.Import(context.Background(), strings.NewReader(doc))
+	require.NoError(t, err)
+	require.Equal(t, service.BackupSectionResult{Skipped: 1}, result.Entries)
+}
+
+func TestBackupService_ImportRejectsInvalidDocuments(t *testing.T) {
+	svc := newBackupService(testutil.NewTestDB(t))
+
+	for _, doc := range []string{
+		`[]`,
+		`{"version":2}`,
+		`{"version":1,"feeds":{}}`,
+		`{"version":1,"entries":[{"feedId":1}]}`,
+	} {
+		_, err := svc.Import(context.Background(), strings.NewReader(doc))
+		require.Error(t, err, doc)
+		require.True(t, errors.Is(err, service.ErrInvalid), doc)
+	}
+}
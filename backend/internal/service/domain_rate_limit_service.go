@@ -19,6 +19,7 @@ type DomainRateLimitDTO struct {
 	ID              string    `json:"id"`
 	Host            string    `json:"host"`
 	IntervalSeconds int       `json:"intervalSeconds"`
+	MaxConcurrent   int       `json:"maxConcurrent"`
 	CreatedAt       time.Time `json:"createdAt"`
 	UpdatedAt       time.Time `json:"updatedAt"`
 }
@@ -51,8 +52,11 @@ type DomainRateLimitService interface {
 	GetInterval(ctx context.Context, host string) int
 	// GetIntervalDuration returns the interval as time.Duration.
 	GetIntervalDuration(ctx context.Context, host string) time.Duration
-	// SetInterval creates or updates the interval for a host.
-	SetInterval(ctx context.Context, host string, seconds int) error
+	// GetMaxConcurrent returns the host's concurrency cap, 0 if not configured.
+	GetMaxConcurrent(ctx context.Context, host string) int
+	// SetInterval creates or updates the interval and concurrency cap for a host.
+	// maxConcurrent 0 falls back to the general per-host setting.
+	SetInterval(ctx context.Context, host string, seconds int, maxConcurrent int) error
 	// DeleteInterval removes the interval configuration for a host.
 	DeleteInterval(ctx context.Context, host string) error
 	// List returns all configured domain rate limits.
@@ -86,14 +90,26 @@ func (s *domainRateLimitService) GetIntervalDuration(ctx context.Context, host s
 	return time.Duration(seconds) * time.Second
 }
 
-// SetInterval creates or updates the interval for a host.
-func (s *domainRateLimitService) SetInterval(ctx context.Context, host string, seconds int) error {
-	if !isValidHost(host) {
+// GetMaxConcurrent returns the host's concurrency cap, 0 if not configured.
+func (s *domainRateLimitService) GetMaxConcurrent(ctx context.Context, host string) int {
+	limit, err := s.repo.GetByHost(ctx, host)
+	if err == nil && limit != nil {
+		return limit.MaxConcurrent
+	}
+	return 0
+}
+
+// SetInterval creates or updates the interval and concurrency cap for a host.
+func (s *domainRateLimitService) SetInterval(ctx context.Context, host string, seconds int, maxConcurrent int) error {
+	if !isValidHost(host) || maxConcurrent > MaxConcurrentPerHostLimit {
 		return ErrInvalid
 	}
 	if seconds < 0 {
 		seconds = 0
 	}
+	if maxConcurrent < 0 {
+		maxConcurrent = 0
+	}
 
 	// Check if it exists
 	existing, err := s.repo.GetByHost(ctx, host)
@@ -102,20 +118,20 @@ func (s *domainRateLimitService) SetInterval(ctx context.Context, host string, s
 	}
 
 	if existing != nil {
-		err := s.repo.Update(ctx, host, seconds)
+		err := s.repo.Update(ctx, host, seconds, maxConcurrent)
 		if err != nil {
 			logger.Error("domain rate limit update failed", "module", "service", "action", "update", "resource", "domain_rate_limit", "result", "failed", "host", host, "error", err)
 			return err
 		}
-		logger.Info("domain rate limit updated", "module", "service", "action", "update", "resource", "domain_rate_limit", "result", "ok", "host", host, "interval_seconds", seconds)
+		logger.Info("domain rate limit updated", "module", "service", "action", "update", "resource", "domain_rate_limit", "result", "ok", "host", host, "interval_seconds", seconds, "max_concurrent", maxConcurrent)
 		return nil
 	}
-	_, err = s.repo.Create(ctx, host, seconds)
+	_, err = s.repo.Create(ctx, host, seconds, maxConcurrent)
 	if err != nil {
 		logger.Error("domain rate limit create failed", "module", "service", "action", "create", "resource", "domain_rate_limit", "result", "failed", "host", host, "error", err)
 		return err
 	}
-	logger.Info("domain rate limit created", "module", "service", "action", "create", "resource", "domain_rate_limit", "result", "ok", "host", host, "interval_seconds", seconds)
+	logger.Info("domain rate limit created", "module", "service", "action", "create", "resource", "domain_rate_limit", "result", "ok", "host", host, "interval_seconds", seconds, "max_concurrent", maxConcurrent)
 	return nil
 }
 
@@ -148,6 +164,7 @@ func modelToDTO(m model.DomainRateLimit) DomainRateLimitDTO {
 		ID:              strconv.FormatInt(m.ID, 10),
 		Host:            m.Host,
 		IntervalSeconds: m.IntervalSeconds,
+		MaxConcurrent:   m.MaxConcurrent,
 		CreatedAt:       m.CreatedAt,
 		UpdatedAt:       m.UpdatedAt,
 	}
@@ -39,9 +39,9 @@ func TestDomainRateLimitService_SetInterval_Create(t *testing.T) {
 	svc := service.NewDomainRateLimitService(repo)
 
 	repo.EXPECT().GetByHost(gomock.Any(), "example.com").Return(nil, nil)
-	repo.EXPECT().Create(gomock.Any(), "example.com", 0).Return(&model.DomainRateLimit{}, nil)
+	repo.EXPECT().Create(gomock.Any(), "example.com", 0, 0).Return(&model.DomainRateLimit{}, nil)
 
-	err := svc.SetInterval(context.Background(), "example.com", -1)
+	err := svc.SetInterval(context.Background(), "example.com", -1, -1)
 	require.NoError(t, err)
 }
 
@@ -53,9 +53,9 @@ func TestDomainRateLimitService_SetInterval_Update(t *testing.T) {
 	svc := service.NewDomainRateLimitService(repo)
 
 	repo.EXPECT().GetByHost(gomock.Any(), "example.com").Return(&model.DomainRateLimit{Host: "example.com"}, nil)
-	repo.EXPECT().Update(gomock.Any(), "example.com", 10).Return(nil)
+	repo.EXPECT().Update(gomock.Any(), "example.com", 10, 4).Return(nil)
 
-	err := svc.SetInterval(context.Background(), "example.com", 10)
+	err := svc.SetInterval(context.Background(), "example.com", 10, 4)
 	require.NoError(t, err)
 }
 
@@ -66,10 +66,34 @@ func TestDomainRateLimitService_SetInterval_InvalidHost(t *testing.T) {
 	repo := mock.NewMockDomainRateLimitRepository(ctrl)
 	svc := service.NewDomainRateLimitService(repo)
 
-	err := svc.SetInterval(context.Background(), "bad_host", 10)
+	err := svc.SetInterval(context.Background(), "bad_host", 10, 0)
 	require.ErrorIs(t, err, service.ErrInvalid)
 }
 
+func TestDomainRateLimitService_SetInterval_MaxConcurrentTooHigh(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mock.NewMockDomainRateLimitRepository(ctrl)
+	svc := service.NewDomainRateLimitService(repo)
+
+	err := svc.SetInterval(context.Background(), "example.com", 10, service.MaxConcurrentPerHostLimit+1)
+	require.ErrorIs(t, err, service.ErrInvalid)
+}
+
+func TestDomainRateLimitService_GetMaxConcurrent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mock.NewMockDomainRateLimitRepository(ctrl)
+	svc := service.NewDomainRateLimitService(repo)
+
+	repo.EXPECT().GetByHost(gomock.Any(), "example.com").Return(&model.DomainRateLimit{MaxConcurrent: 3}, nil)
+	repo.EXPECT().GetByHost(gomock.Any(), "other.com").Return(nil, nil)
+	require.Equal(t, 3, svc.GetMaxConcurrent(context.Background(), "example.com"))
+	require.Zero(t, svc.GetMaxConcurrent(context.Background(), "other.com"))
+}
+
 func TestDomainRateLimitService_GetInterval(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
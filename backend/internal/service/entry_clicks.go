@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+
+	"gist/backend/pkg/logger"
+)
+
+const (
+	// DefaultClickStatsDays is the period click statistics cover by default.
+	DefaultClickStatsDays = 30
+	// MaxClickStatsDays is the longest period click statistics cover.
+	MaxClickStatsDays = 365
+)
+
+// ClickStats is how often the entries of each feed were opened through Visit
+// over the last Days days, counted in the configured timezone.
+type ClickStats struct {
+	Days     int
+	Timezone string
+	Since    time.Time
+	// Feeds has every feed, most clicked first. Feeds never clicked come last
+	// with a zero count, as candidates for pruning.
+	Feeds []FeedClicks
+}
+
+// FeedClicks is a feed and the number of its entries opened in the period.
+type FeedClicks struct {
+	FeedID        int64
+	Title         string
+	Count         int
+	LastClickedAt *time.Time
+}
+
+// visitableURL reports whether raw is an absolute http(s) URL, the only kind
+// Visit redirects to. Feeds can carry javascript: or data: links.
+func visitableURL(raw string) bool {
+	parsed, err := url.Parse(raw)
+	if err != nil || parsed.Host == "" {
+		return false
+	}
+	scheme := strings.ToLower(parsed.Scheme)
+	return scheme == "http" || scheme == "https"
+}
+
+func (s *entryService) Visit(ctx context.Context, id int64, markRead bool) (string, error) {
+	entry, err := s.entries.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", ErrNotFound
+		}
+		return "", err
+	}
+	if entry.URL == nil || !visitableURL(*entry.URL) {
+		logger.Warn("entry visit refused", "module", "service", "action", "fetch", "resource", "entry", "result", "failed", "entry_id", id)
+		return "", ErrInvalid
+	}
+
+	if err := s.entries.RecordClick(ctx, entry.ID, entry.FeedID, time.Now()); err != nil {
+		logger.Error("entry click record failed", "module", "service", "action", "create", "resource", "entry", "result", "failed", "entry_id", id, "error", err)
+		return "", err
+	}
+	if markRead && !entry.Read {
+		if err := s.MarkAsRead(ctx, id, true); err != nil {
+			return "", err
+		}
+	}
+	logger.Debug("entry visited", "module", "service", "action", "fetch", "resource", "entry", "result", "ok", "entry_id", id, "feed_id", entry.FeedID)
+	return *entry.URL, nil
+}
+
+func (s *entryService) GetClickStats(ctx context.Context, days int) (*ClickStats, error) {
+	if days <= 0 {
+		days = DefaultClickStatsDays
+	}
+	if days > MaxClickStatsDays {
+		return nil, ErrInvalid
+	}
+
+	loc := configuredLocation(loadGeneralSettings(ctx, s.settings))
+	since := statsPeriodStart(time.Now().In(loc), days)
+	counts, err := s.entries.CountClicksByFeed(ctx, since)
+	if err != nil {
+		logger.Error("click stats failed", "module", "service", "action", "list", "resource", "entry", "result", "failed", "error", err)
+		return nil, err
+	}
+
+	stats := &ClickStats{
+		Days:     days,
+		Timezone: loc.String(),
+		Since:    since,
+		Feeds:    make([]FeedClicks, 0, len(counts)),
+	}
+	for _, c := range counts {
+		stats.Feeds = append(stats.Feeds, FeedClicks{FeedID: c.FeedID, Title: c.Title, Count: c.Count, LastClickedAt: c.LastClickedAt})
+	}
+	return stats, nil
+}
+
+// statsPeriodStart returns the midnight starting a period of days days that
+// ends with the day of now, in now's location.
+func statsPeriodStart(now time.Time, days int) time.Time {
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()).AddDate(0, 0, -(days - 1))
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"gist/backend/internal/model"
+	"gist/backend/internal/repository"
+	"gist/backend/pkg/logger"
+)
+
+// Fields EntrySearchParams.SearchIn can match in.
+const (
+	EntrySearchInAll     = "all"
+	EntrySearchInTitle   = "title"
+	EntrySearchInContent = "content"
+)
+
+// EntrySearchParams selects entries for Search.
+type EntrySearchParams struct {
+	Query string
+	// SearchIn is one of the EntrySearchIn* fields; empty means EntrySearchInAll,
+	// which also matches author and URL.
+	SearchIn string
+	FeedID   *int64
+	// FolderID keeps entries of feeds in the folder or any of its subfolders.
+	FolderID *int64
+	// BoostUnread ranks unread entries a little above read ones of like
+	// relevance; without it results are in strict relevance order.
+	BoostUnread bool
+	IncludeFeed bool
+	Limit       int
+	Offset      int
+}
+
+var entrySearchColumns = map[string]string{
+	"":                   "",
+	EntrySearchInAll:     "",
+	EntrySearchInTitle:   repository.EntrySearchColumnTitle,
+	EntrySearchInContent: repository.EntrySearchColumnContent,
+}
+
+func (s *entryService) Search(ctx context.Context, params EntrySearchParams) ([]model.Entry, error) {
+	query := strings.TrimSpace(params.Query)
+	column, ok := entrySearchColumns[params.SearchIn]
+	if query == "" || !ok {
+		return nil, ErrInvalid
+	}
+	if err := s.checkListScope(ctx, EntryListParams{FeedID: params.FeedID, FolderID: params.FolderID}); err != nil {
+		return nil, err
+	}
+
+	// Up to 101 like List, for the handler's hasMore check
+	limit := params.Limit
+	if limit <= 0 {
+		limit = 50
+	}
+	if limit > 101 {
+		limit = 101
+	}
+
+	entries, err := s.entries.Search(ctx, repository.EntrySearchFilter{
+		Query:       query,
+		Column:      column,
+		FeedID:      params.FeedID,
+		FolderID:    params.FolderID,
+		BoostUnread: params.BoostUnread,
+		IncludeFeed: params.IncludeFeed,
+		Limit:       limit,
+		Offset:      params.Offset,
+	})
+	if err != nil {
+		logger.Error("entry search failed", "module", "service", "action", "list", "resource", "entry", "result", "failed", "error", err)
+		return nil, err
+	}
+	logger.Debug("entry search", "module", "service", "action", "list", "resource", "entry", "result", "ok", "count", len(entries))
+	return entries, nil
+}
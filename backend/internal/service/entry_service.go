@@ -5,12 +5,30 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
 
+	"gist/backend/internal/events"
 	"gist/backend/internal/model"
 	"gist/backend/internal/repository"
+	"gist/backend/pkg/linediff"
 	"gist/backend/pkg/logger"
 )
 
+// EntryRevisionLimit is the number of previous content snapshots kept per entry.
+const EntryRevisionLimit = 3
+
+// MaxEntryNoteSize caps the bytes of an entry note.
+const MaxEntryNoteSize = 10 << 10
+
+// EntryRevision is a stored snapshot plus its diff against the version that replaced it.
+type EntryRevision struct {
+	model.EntryRevision
+	Diff []linediff.Line
+}
+
 type EntryListParams struct {
 	FeedID       *int64
 	FolderID     *int64
@@ -18,64 +36,225 @@ type EntryListParams struct {
 	UnreadOnly   bool
 	StarredOnly  bool
 	HasThumbnail bool
-	Limit        int
-	Offset       int
+	NotesOnly    bool
+	// HasReadableContent keeps entries whose readable content has been extracted.
+	HasReadableContent bool
+	// HasSummary keeps entries with a cached AI summary in SummaryLanguage, or
+	// in the configured summary language when that is empty.
+	HasSummary      bool
+	SummaryLanguage string
+	// ContentLanguage keeps entries in that language, a primary subtag such as "en".
+	ContentLanguage   string
+	MinReadingMinutes *int
+	MaxReadingMinutes *int
+	// UpdatedSince keeps entries updated at or after it, for delta lists.
+	UpdatedSince *time.Time
+	// IncludeFeed loads Entry.Feed on each returned entry.
+	IncludeFeed bool
+	Limit       int
+	Offset      int
+}
+
+// EntryTombstoneRetention is how long deleted entries are remembered for
+// ListDeleted. A delta list from further back must be a full one.
+const EntryTombstoneRetention = 30 * 24 * time.Hour
+
+const (
+	// DefaultDigestPerFeed is how many entries a daily digest shows per feed by default.
+	DefaultDigestPerFeed = 5
+	maxDigestPerFeed     = 50
+)
+
+// DailyDigestParams selects the calendar day and per-feed cap of a digest.
+type DailyDigestParams struct {
+	// Date is YYYY-MM-DD in the configured timezone; empty means today.
+	Date    string
+	PerFeed int
+}
+
+// DailyDigest lists one day's entries grouped by folder, then feed.
+type DailyDigest struct {
+	Date     string
+	Timezone string
+	Start    time.Time
+	End      time.Time
+	Folders  []DigestFolder
+}
+
+// RecentCounts maps feed IDs to the number of entries saved since Since.
+type RecentCounts struct {
+	Since  time.Time
+	Counts map[int64]int
+}
+
+// DigestFolder groups the digest feeds of one folder; FolderID is nil for feeds without a folder.
+type DigestFolder struct {
+	FolderID    *int64
+	Name        string
+	UnreadCount int
+	Feeds       []DigestFeed
+}
+
+// DigestFeed holds a feed's capped entries for the day.
+type DigestFeed struct {
+	FeedID      int64
+	Title       string
+	UnreadCount int
+	// Total is the number of the feed's entries that day, including those over the cap.
+	Total   int
+	Entries []DigestItem
+}
+
+// DigestItem is a digest entry with its cached AI summary, if any.
+type DigestItem struct {
+	Entry   model.Entry
+	Summary *string
+}
+
+const (
+	// DefaultReadingStatsDays is the period reading statistics cover by default.
+	DefaultReadingStatsDays = 90
+	// MaxReadingStatsDays is the longest period reading statistics cover.
+	MaxReadingStatsDays  = 365
+	readingStatsTopFeeds = 10
+)
+
+// ReadingStats summarises what was read over the last Days days, counted in
+// the configured timezone. Only reads recorded with a time are included.
+type ReadingStats struct {
+	Days     int
+	Timezone string
+	Since    time.Time
+	// Daily has one element per day from Since to today, including days without reads.
+	Daily    []ReadingDay
+	TopFeeds []ReadingFeed
+	// AverageReadDelay is the mean time from publication to reading; nil
+	// when no read entry in the period has a published date.
+	AverageReadDelay *time.Duration
+	TotalRead        int
+	TotalStarred     int
+}
+
+// ReadingDay is the number of entries read on one day.
+type ReadingDay struct {
+	Date  string
+	Count int
+}
+
+// ReadingFeed is a feed and the number of its entries read in the period.
+type ReadingFeed struct {
+	FeedID int64
+	Title  string
+	Count  int
+}
+
+// MaxBulkEntries caps how many entries GetByIDs returns in one call.
+const MaxBulkEntries = 100
+
+// BulkEntry is an entry returned by GetByIDs, with its cached AI summary when
+// requested and available.
+type BulkEntry struct {
+	Entry   model.Entry
+	Summary *string
 }
 
 type EntryService interface {
 	List(ctx context.Context, params EntryListParams) ([]model.Entry, error)
+	// Count returns how many entries match the filters of params; Limit and
+	// Offset are ignored.
+	Count(ctx context.Context, params EntryListParams) (int, error)
+	// Search returns the entries matching params.Query, most relevant first.
+	// It returns ErrInvalid for an empty query or unknown SearchIn, and
+	// ErrNotFound when the feed or folder scope does not exist.
+	Search(ctx context.Context, params EntrySearchParams) ([]model.Entry, error)
 	GetByID(ctx context.Context, id int64) (model.Entry, error)
+	// GetByIDWithFeed is GetByID with Entry.Feed loaded.
+	GetByIDWithFeed(ctx context.Context, id int64) (model.Entry, error)
+	// GetByIDs returns up to MaxBulkEntries entries in the order requested,
+	// skipping unknown ids. includeAI adds each entry's cached summary in the
+	// configured summary language.
+	GetByIDs(ctx context.Context, ids []int64, includeAI bool) ([]BulkEntry, error)
+	// GetAdjacent returns the entry after (next) or before id in List order under params,
+	// or nil at either end. The reference entry itself need not match the filter.
+	GetAdjacent(ctx context.Context, id int64, params EntryListParams, next bool) (*model.Entry, error)
 	MarkAsRead(ctx context.Context, id int64, read bool) error
 	MarkManyAsRead(ctx context.Context, ids []int64, read bool) error
+	// MarkAsStarred stars or unstars an entry. Starring an entry without readable
+	// content queues its archival; unstarring keeps whatever was archived.
 	MarkAsStarred(ctx context.Context, id int64, starred bool) error
 	MarkAllAsRead(ctx context.Context, feedID *int64, folderID *int64, contentType *string) error
 	GetUnreadCounts(ctx context.Context) (map[int64]int, error)
+	// GetRecentCounts counts the entries each feed saved since the start of
+	// today in the configured timezone, omitting feeds without any.
+	GetRecentCounts(ctx context.Context) (RecentCounts, error)
 	GetStarredCount(ctx context.Context) (int, error)
-	// ClearReadabilityCache clears all readable_content from entries
+	// ListArchiveFailures returns starred entries whose full-content archival gave up.
+	ListArchiveFailures(ctx context.Context) ([]model.FailedArchive, error)
+	// ClearReadabilityCache clears readable_content from entries, keeping archived starred ones
 	ClearReadabilityCache(ctx context.Context) (int64, error)
 	// ClearEntryCache deletes all unstarred entries
 	ClearEntryCache(ctx context.Context) (int64, error)
+	// ListDeleted returns the ids of entries deleted at or after since, within
+	// the feed when feedID is set. Deletions older than EntryTombstoneRetention
+	// are forgotten.
+	ListDeleted(ctx context.Context, since time.Time, feedID *int64) ([]int64, error)
+	// ListRevisions returns previous content snapshots of an entry, newest first.
+	ListRevisions(ctx context.Context, id int64) ([]EntryRevision, error)
+	// GetRawItem returns the feed item an entry was made from, as JSON. It is
+	// ErrNotFound when the entry has none; see GeneralSettings.KeepRawItems.
+	GetRawItem(ctx context.Context, id int64) (string, error)
+	// SetNote stores the note of an entry; a blank note deletes it.
+	SetNote(ctx context.Context, id int64, note string) error
+	DeleteNote(ctx context.Context, id int64) error
+	// GetDailyDigest returns the entries published on one day, capped per feed.
+	// The day is interpreted in the timezone from GeneralSettings.
+	GetDailyDigest(ctx context.Context, params DailyDigestParams) (*DailyDigest, error)
+	// GetReadingStats returns reading statistics for the last days days, today
+	// included; days <= 0 means DefaultReadingStatsDays.
+	GetReadingStats(ctx context.Context, days int) (*ReadingStats, error)
+	// Visit records a click-through to the original of an entry and returns
+	// its URL, marking the entry read unless markRead is false. It returns
+	// ErrInvalid when the entry has no http(s) URL to redirect to.
+	Visit(ctx context.Context, id int64, markRead bool) (string, error)
+	// GetClickStats returns per feed click-through counts for the last days
+	// days, today included; days <= 0 means DefaultClickStatsDays.
+	GetClickStats(ctx context.Context, days int) (*ClickStats, error)
 }
 
 type entryService struct {
-	entries repository.EntryRepository
-	feeds   repository.FeedRepository
-	folders repository.FolderRepository
+	entries   repository.EntryRepository
+	feeds     repository.FeedRepository
+	folders   repository.FolderRepository
+	summaries repository.AISummaryRepository
+	settings  SettingsService
+	archive   ArchiveService
+	// bus receives change events for GET /events; nil drops them.
+	bus *events.Bus
 }
 
 func NewEntryService(
 	entries repository.EntryRepository,
 	feeds repository.FeedRepository,
 	folders repository.FolderRepository,
+	summaries repository.AISummaryRepository,
+	settings SettingsService,
+	archive ArchiveService,
+	bus *events.Bus,
 ) EntryService {
 	return &entryService{
-		entries: entries,
-		feeds:   feeds,
-		folders: folders,
+		entries:   entries,
+		feeds:     feeds,
+		folders:   folders,
+		summaries: summaries,
+		settings:  settings,
+		archive:   archive,
+		bus:       bus,
 	}
 }
 
 func (s *entryService) List(ctx context.Context, params EntryListParams) ([]model.Entry, error) {
-	// Validate feedID exists if provided
-	if params.FeedID != nil {
-		_, err := s.feeds.GetByID(ctx, *params.FeedID)
-		if err != nil {
-			if errors.Is(err, sql.ErrNoRows) {
-				return nil, ErrNotFound
-			}
-			return nil, err
-		}
-	}
-
-	// Validate folderID exists if provided
-	if params.FolderID != nil {
-		_, err := s.folders.GetByID(ctx, *params.FolderID)
-		if err != nil {
-			if errors.Is(err, sql.ErrNoRows) {
-				return nil, ErrNotFound
-			}
-			return nil, err
-		}
+	if err := s.checkListScope(ctx, params); err != nil {
+		return nil, err
 	}
 
 	// Set default limit
@@ -88,16 +267,8 @@ func (s *entryService) List(ctx context.Context, params EntryListParams) ([]mode
 		limit = 101
 	}
 
-	filter := repository.EntryListFilter{
-		FeedID:       params.FeedID,
-		FolderID:     params.FolderID,
-		ContentType:  params.ContentType,
-		UnreadOnly:   params.UnreadOnly,
-		StarredOnly:  params.StarredOnly,
-		HasThumbnail: params.HasThumbnail,
-		Limit:        limit,
-		Offset:       params.Offset,
-	}
+	filter := s.entryListFilter(ctx, params)
+	filter.Limit = limit
 
 	entries, err := s.entries.List(ctx, filter)
 	if err != nil {
@@ -108,6 +279,103 @@ func (s *entryService) List(ctx context.Context, params EntryListParams) ([]mode
 	return entries, nil
 }
 
+func (s *entryService) Count(ctx context.Context, params EntryListParams) (int, error) {
+	if err := s.checkListScope(ctx, params); err != nil {
+		return 0, err
+	}
+
+	filter := s.entryListFilter(ctx, params)
+	filter.Offset = 0
+	filter.IncludeFeed = false
+	count, err := s.entries.Count(ctx, filter)
+	if err != nil {
+		logger.Error("entry count failed", "module", "service", "action", "list", "resource", "entry", "result", "failed", "error", err)
+		return 0, err
+	}
+	return count, nil
+}
+
+// checkListScope returns ErrNotFound when params names a feed or folder that does not exist.
+func (s *entryService) checkListScope(ctx context.Context, params EntryListParams) error {
+	if params.FeedID != nil {
+		if _, err := s.feeds.GetByID(ctx, *params.FeedID); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrNotFound
+			}
+			return err
+		}
+	}
+
+	if params.FolderID != nil {
+		if _, err := s.folders.GetByID(ctx, *params.FolderID); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrNotFound
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// entryListFilter maps list params to a repository filter; Limit is left to the caller.
+func (s *entryService) entryListFilter(ctx context.Context, params EntryListParams) repository.EntryListFilter {
+	filter := repository.EntryListFilter{
+		FeedID:             params.FeedID,
+		FolderID:           params.FolderID,
+		ContentType:        params.ContentType,
+		UnreadOnly:         params.UnreadOnly,
+		StarredOnly:        params.StarredOnly,
+		HasThumbnail:       params.HasThumbnail,
+		NotesOnly:          params.NotesOnly,
+		HasReadableContent: params.HasReadableContent,
+		ContentLanguage:    params.ContentLanguage,
+		ApplyMutes:         params.UnreadOnly,
+		MinReadingMinutes:  params.MinReadingMinutes,
+		MaxReadingMinutes:  params.MaxReadingMinutes,
+		UpdatedSince:       params.UpdatedSince,
+		IncludeFeed:        params.IncludeFeed,
+		Offset:             params.Offset,
+	}
+	if params.HasSummary {
+		filter.SummaryLanguage = params.SummaryLanguage
+		if filter.SummaryLanguage == "" {
+			filter.SummaryLanguage = s.summaryLanguage(ctx)
+		}
+	}
+	return filter
+}
+
+func (s *entryService) ListDeleted(ctx context.Context, since time.Time, feedID *int64) ([]int64, error) {
+	ids, err := s.entries.ListTombstones(ctx, since, feedID)
+	if err != nil {
+		logger.Error("entry tombstone list failed", "module", "service", "action", "list", "resource", "entry", "result", "failed", "error", err)
+		return nil, err
+	}
+	return ids, nil
+}
+
+func (s *entryService) GetAdjacent(ctx context.Context, id int64, params EntryListParams, next bool) (*model.Entry, error) {
+	ref, err := s.entries.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+
+	filter := s.entryListFilter(ctx, params)
+	filter.Offset = 0
+	entry, err := s.entries.GetAdjacent(ctx, ref, filter, next)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		logger.Error("entry adjacent failed", "module", "service", "action", "fetch", "resource", "entry", "result", "failed", "entry_id", id, "next", next, "error", err)
+		return nil, err
+	}
+	return &entry, nil
+}
+
 func (s *entryService) GetByID(ctx context.Context, id int64) (model.Entry, error) {
 	entry, err := s.entries.GetByID(ctx, id)
 	if err != nil {
@@ -116,10 +384,69 @@ func (s *entryService) GetByID(ctx context.Context, id int64) (model.Entry, erro
 		}
 		return model.Entry{}, err
 	}
+	if entry.AuthorMuted, err = s.isAuthorMuted(ctx, entry); err != nil {
+		return model.Entry{}, err
+	}
 	logger.Debug("entry get", "module", "service", "action", "fetch", "resource", "entry", "result", "ok", "entry_id", id)
 	return entry, nil
 }
 
+func (s *entryService) GetByIDWithFeed(ctx context.Context, id int64) (model.Entry, error) {
+	entry, err := s.entries.GetByIDWithFeed(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Entry{}, ErrNotFound
+		}
+		return model.Entry{}, err
+	}
+	if entry.AuthorMuted, err = s.isAuthorMuted(ctx, entry); err != nil {
+		return model.Entry{}, err
+	}
+	logger.Debug("entry get", "module", "service", "action", "fetch", "resource", "entry", "result", "ok", "entry_id", id, "include_feed", true)
+	return entry, nil
+}
+
+func (s *entryService) isAuthorMuted(ctx context.Context, entry model.Entry) (bool, error) {
+	if entry.Author == nil || strings.TrimSpace(*entry.Author) == "" {
+		return false, nil
+	}
+	return s.feeds.IsAuthorMuted(ctx, entry.FeedID, *entry.Author)
+}
+
+func (s *entryService) GetByIDs(ctx context.Context, ids []int64, includeAI bool) ([]BulkEntry, error) {
+	if len(ids) > MaxBulkEntries {
+		return nil, fmt.Errorf("at most %d entries per request: %w", MaxBulkEntries, ErrInvalid)
+	}
+	entries, err := s.entries.GetByIDs(ctx, ids)
+	if err != nil {
+		logger.Error("entry bulk get failed", "module", "service", "action", "fetch", "resource", "entry", "result", "failed", "count", len(ids), "error", err)
+		return nil, err
+	}
+
+	summaries := map[int64]*model.AISummary{}
+	if includeAI && s.summaries != nil && len(entries) > 0 {
+		found := make([]int64, len(entries))
+		for i, entry := range entries {
+			found[i] = entry.ID
+		}
+		summaries, err = s.summaries.GetBatch(ctx, found, s.summaryLanguage(ctx))
+		if err != nil {
+			logger.Error("entry bulk get failed", "module", "service", "action", "fetch", "resource", "entry", "result", "failed", "count", len(ids), "error", err)
+			return nil, err
+		}
+	}
+
+	result := make([]BulkEntry, len(entries))
+	for i, entry := range entries {
+		result[i] = BulkEntry{Entry: entry}
+		if summary, ok := summaries[entry.ID]; ok {
+			result[i].Summary = &summary.Summary
+		}
+	}
+	logger.Debug("entry bulk get", "module", "service", "action", "fetch", "resource", "entry", "result", "ok", "requested", len(ids), "count", len(result))
+	return result, nil
+}
+
 func (s *entryService) MarkAsRead(ctx context.Context, id int64, read bool) error {
 	// Check entry exists
 	_, err := s.entries.GetByID(ctx, id)
@@ -135,6 +462,7 @@ func (s *entryService) MarkAsRead(ctx context.Context, id int64, read bool) erro
 		return err
 	}
 	logger.Info("entry read updated", "module", "service", "action", "update", "resource", "entry", "result", "ok", "entry_id", id, "read", read)
+	s.bus.Publish(events.Event{Type: events.EntriesRead, Data: events.EntriesReadData{EntryIDs: events.IDs([]int64{id}), Read: read}})
 	return nil
 }
 
@@ -148,6 +476,7 @@ func (s *entryService) MarkManyAsRead(ctx context.Context, ids []int64, read boo
 		return err
 	}
 	logger.Info("entries read updated", "module", "service", "action", "update", "resource", "entry", "result", "ok", "count", len(ids), "read", read)
+	s.bus.Publish(events.Event{Type: events.EntriesRead, Data: events.EntriesReadData{EntryIDs: events.IDs(ids), Read: read}})
 	return nil
 }
 
@@ -192,6 +521,7 @@ func (s *entryService) MarkAllAsRead(ctx context.Context, feedID *int64, folderI
 		return err
 	}
 	logger.Info("entries marked read", "module", "service", "action", "update", "resource", "entry", "result", "ok", "feed_id", feedIDValue, "folder_id", folderIDValue, "content_type", contentTypeValue)
+	s.bus.Publish(events.Event{Type: events.EntriesRead, Data: events.EntriesReadData{FeedID: feedID, FolderID: folderID, ContentType: contentType, Read: true}})
 	return nil
 }
 
@@ -212,7 +542,7 @@ func (s *entryService) GetUnreadCounts(ctx context.Context) (map[int64]int, erro
 
 func (s *entryService) MarkAsStarred(ctx context.Context, id int64, starred bool) error {
 	// Check entry exists
-	_, err := s.entries.GetByID(ctx, id)
+	entry, err := s.entries.GetByID(ctx, id)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return ErrNotFound
@@ -225,9 +555,39 @@ func (s *entryService) MarkAsStarred(ctx context.Context, id int64, starred bool
 		return err
 	}
 	logger.Info("entry starred updated", "module", "service", "action", "update", "resource", "entry", "result", "ok", "entry_id", id, "starred", starred)
+	s.bus.Publish(events.Event{Type: events.EntryStarred, Data: events.EntryStarredData{EntryID: id, Starred: starred}})
+
+	if starred && s.archive != nil && ptrString(entry.ReadableContent) == "" && ptrString(entry.URL) != "" {
+		s.archive.Archive(id)
+	}
 	return nil
 }
 
+func (s *entryService) ListArchiveFailures(ctx context.Context) ([]model.FailedArchive, error) {
+	if s.archive == nil {
+		return nil, nil
+	}
+	return s.archive.ListFailed(ctx)
+}
+
+func (s *entryService) GetRecentCounts(ctx context.Context) (RecentCounts, error) {
+	loc := configuredLocation(loadGeneralSettings(ctx, s.settings))
+	now := time.Now().In(loc)
+	since := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+
+	counts, err := s.entries.GetRecentCounts(ctx, since)
+	if err != nil {
+		logger.Error("entry recent counts failed", "module", "service", "action", "list", "resource", "entry", "result", "failed", "error", err)
+		return RecentCounts{}, err
+	}
+
+	result := RecentCounts{Since: since, Counts: make(map[int64]int, len(counts))}
+	for _, rc := range counts {
+		result.Counts[rc.FeedID] = rc.Count
+	}
+	return result, nil
+}
+
 func (s *entryService) GetStarredCount(ctx context.Context) (int, error) {
 	count, err := s.entries.GetStarredCount(ctx)
 	if err != nil {
@@ -262,3 +622,301 @@ func (s *entryService) ClearEntryCache(ctx context.Context) (int64, error) {
 	logger.Info("entry cache cleared", "module", "service", "action", "clear", "resource", "entry", "result", "ok", "count", deleted)
 	return deleted, nil
 }
+
+func (s *entryService) ListRevisions(ctx context.Context, id int64) ([]EntryRevision, error) {
+	entry, err := s.entries.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+
+	stored, err := s.entries.ListRevisions(ctx, id)
+	if err != nil {
+		logger.Error("entry revisions list failed", "module", "service", "action", "list", "resource", "entry", "result", "failed", "entry_id", id, "error", err)
+		return nil, err
+	}
+
+	// Each snapshot is diffed against its successor: the next newer snapshot, or the current content
+	revisions := make([]EntryRevision, len(stored))
+	newer := entry.Content
+	for i, rev := range stored {
+		revisions[i] = EntryRevision{
+			EntryRevision: rev,
+			Diff:          linediff.Diff(contentLines(rev.Content), contentLines(newer)),
+		}
+		newer = rev.Content
+	}
+	logger.Debug("entry revisions list", "module", "service", "action", "list", "resource", "entry", "result", "ok", "entry_id", id, "count", len(revisions))
+	return revisions, nil
+}
+
+func (s *entryService) SetNote(ctx context.Context, id int64, note string) error {
+	note = strings.TrimSpace(note)
+	if note == "" {
+		return s.DeleteNote(ctx, id)
+	}
+	if len(note) > MaxEntryNoteSize {
+		return ErrNoteTooLarge
+	}
+	if _, err := s.entries.GetByID(ctx, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrNotFound
+		}
+		return err
+	}
+
+	if err := s.entries.SetNote(ctx, id, note); err != nil {
+		logger.Error("entry note update failed", "module", "service", "action", "update", "resource", "entry", "result", "failed", "entry_id", id, "error", err)
+		return err
+	}
+	logger.Info("entry note updated", "module", "service", "action", "update", "resource", "entry", "result", "ok", "entry_id", id, "size", len(note))
+	return nil
+}
+
+func (s *entryService) DeleteNote(ctx context.Context, id int64) error {
+	if _, err := s.entries.GetByID(ctx, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrNotFound
+		}
+		return err
+	}
+
+	if err := s.entries.DeleteNote(ctx, id); err != nil {
+		logger.Error("entry note delete failed", "module", "service", "action", "delete", "resource", "entry", "result", "failed", "entry_id", id, "error", err)
+		return err
+	}
+	logger.Info("entry note deleted", "module", "service", "action", "delete", "resource", "entry", "result", "ok", "entry_id", id)
+	return nil
+}
+
+// blockBreaks puts block-level HTML boundaries on their own lines so single-line
+// feed content still produces a useful line diff.
+var blockBreaks = strings.NewReplacer(
+	"</p>", "</p>\n",
+	"<br>", "<br>\n",
+	"<br/>", "<br/>\n",
+	"<br />", "<br />\n",
+	"</li>", "</li>\n",
+	"</div>", "</div>\n",
+	"</blockquote>", "</blockquote>\n",
+	"</h1>", "</h1>\n",
+	"</h2>", "</h2>\n",
+	"</h3>", "</h3>\n",
+	"</h4>", "</h4>\n",
+	"</h5>", "</h5>\n",
+	"</h6>", "</h6>\n",
+)
+
+func contentLines(content *string) []string {
+	if content == nil || *content == "" {
+		return nil
+	}
+	raw := strings.Split(blockBreaks.Replace(*content), "\n")
+	lines := make([]string, 0, len(raw))
+	for _, line := range raw {
+		line = strings.TrimSpace(line)
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+func (s *entryService) GetDailyDigest(ctx context.Context, params DailyDigestParams) (*DailyDigest, error) {
+	loc := configuredLocation(loadGeneralSettings(ctx, s.settings))
+
+	var day time.Time
+	if params.Date == "" {
+		now := time.Now().In(loc)
+		day = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	} else {
+		parsed, err := time.ParseInLocation("2006-01-02", params.Date, loc)
+		if err != nil {
+			return nil, ErrInvalid
+		}
+		day = parsed
+	}
+	start, end := day, day.AddDate(0, 0, 1)
+
+	perFeed := params.PerFeed
+	if perFeed <= 0 {
+		perFeed = DefaultDigestPerFeed
+	}
+	if perFeed > maxDigestPerFeed {
+		perFeed = maxDigestPerFeed
+	}
+
+	digest := &DailyDigest{
+		Date:     day.Format("2006-01-02"),
+		Timezone: loc.String(),
+		Start:    start,
+		End:      end,
+		Folders:  []DigestFolder{},
+	}
+
+	rows, err := s.entries.ListForDigest(ctx, start, end, perFeed)
+	if err != nil {
+		logger.Error("entry digest failed", "module", "service", "action", "list", "resource", "entry", "result", "failed", "date", digest.Date, "error", err)
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return digest, nil
+	}
+
+	counts, err := s.entries.GetAllUnreadCounts(ctx)
+	if err != nil {
+		logger.Error("entry digest failed", "module", "service", "action", "list", "resource", "entry", "result", "failed", "date", digest.Date, "error", err)
+		return nil, err
+	}
+	unread := make(map[int64]int, len(counts))
+	for _, uc := range counts {
+		unread[uc.FeedID] = uc.Count
+	}
+
+	summaries := map[int64]*model.AISummary{}
+	if s.summaries != nil {
+		ids := make([]int64, len(rows))
+		for i, row := range rows {
+			ids[i] = row.Entry.ID
+		}
+		summaries, err = s.summaries.GetBatch(ctx, ids, s.summaryLanguage(ctx))
+		if err != nil {
+			logger.Error("entry digest failed", "module", "service", "action", "list", "resource", "entry", "result", "failed", "date", digest.Date, "error", err)
+			return nil, err
+		}
+	}
+
+	// Rows arrive newest first, which keeps each feed's entries in order
+	folderIndex := map[int64]int{}
+	noFolder := -1
+	feedIndex := map[int64][2]int{}
+	for _, row := range rows {
+		pos, ok := feedIndex[row.Entry.FeedID]
+		if !ok {
+			var fi int
+			if row.FolderID != nil && row.FolderName != nil {
+				idx, seen := folderIndex[*row.FolderID]
+				if !seen {
+					idx = len(digest.Folders)
+					folderIndex[*row.FolderID] = idx
+					digest.Folders = append(digest.Folders, DigestFolder{FolderID: row.FolderID, Name: *row.FolderName})
+				}
+				fi = idx
+			} else {
+				if noFolder < 0 {
+					noFolder = len(digest.Folders)
+					digest.Folders = append(digest.Folders, DigestFolder{})
+				}
+				fi = noFolder
+			}
+			folder := &digest.Folders[fi]
+			folder.UnreadCount += unread[row.Entry.FeedID]
+			folder.Feeds = append(folder.Feeds, DigestFeed{
+				FeedID:      row.Entry.FeedID,
+				Title:       row.FeedTitle,
+				UnreadCount: unread[row.Entry.FeedID],
+				Total:       row.FeedTotal,
+			})
+			pos = [2]int{fi, len(folder.Feeds) - 1}
+			feedIndex[row.Entry.FeedID] = pos
+		}
+
+		item := DigestItem{Entry: row.Entry}
+		if summary, ok := summaries[row.Entry.ID]; ok {
+			item.Summary = &summary.Summary
+		}
+		feed := &digest.Folders[pos[0]].Feeds[pos[1]]
+		feed.Entries = append(feed.Entries, item)
+	}
+
+	// Folders by name with unfiled feeds last, feeds by title
+	sort.SliceStable(digest.Folders, func(i, j int) bool {
+		a, b := digest.Folders[i], digest.Folders[j]
+		if (a.FolderID == nil) != (b.FolderID == nil) {
+			return b.FolderID == nil
+		}
+		return strings.ToLower(a.Name) < strings.ToLower(b.Name)
+	})
+	for i := range digest.Folders {
+		feeds := digest.Folders[i].Feeds
+		sort.SliceStable(feeds, func(a, b int) bool {
+			return strings.ToLower(feeds[a].Title) < strings.ToLower(feeds[b].Title)
+		})
+	}
+
+	logger.Debug("entry digest", "module", "service", "action", "list", "resource", "entry", "result", "ok", "date", digest.Date, "timezone", digest.Timezone, "count", len(rows))
+	return digest, nil
+}
+
+func (s *entryService) summaryLanguage(ctx context.Context) string {
+	if s.settings != nil {
+		if settings, err := s.settings.GetAISettings(ctx); err == nil && settings.SummaryLanguage != "" {
+			return settings.SummaryLanguage
+		}
+	}
+	return "zh-CN"
+}
+
+func (s *entryService) GetReadingStats(ctx context.Context, days int) (*ReadingStats, error) {
+	if days <= 0 {
+		days = DefaultReadingStatsDays
+	}
+	if days > MaxReadingStatsDays {
+		return nil, ErrInvalid
+	}
+
+	loc := configuredLocation(loadGeneralSettings(ctx, s.settings))
+	now := time.Now().In(loc)
+	since := statsPeriodStart(now, days)
+	_, offset := now.Zone()
+
+	stats := &ReadingStats{
+		Days:     days,
+		Timezone: loc.String(),
+		Since:    since,
+		Daily:    make([]ReadingDay, 0, days),
+		TopFeeds: []ReadingFeed{},
+	}
+
+	counts, err := s.entries.CountReadsByDay(ctx, since, time.Duration(offset)*time.Second)
+	if err != nil {
+		logger.Error("reading stats failed", "module", "service", "action", "list", "resource", "entry", "result", "failed", "error", err)
+		return nil, err
+	}
+	byDate := make(map[string]int, len(counts))
+	for _, c := range counts {
+		byDate[c.Date] = c.Count
+	}
+	for day := since; !day.After(now); day = day.AddDate(0, 0, 1) {
+		date := day.Format("2006-01-02")
+		stats.Daily = append(stats.Daily, ReadingDay{Date: date, Count: byDate[date]})
+		stats.TotalRead += byDate[date]
+	}
+
+	feeds, err := s.entries.TopReadFeeds(ctx, since, readingStatsTopFeeds)
+	if err != nil {
+		logger.Error("reading stats failed", "module", "service", "action", "list", "resource", "entry", "result", "failed", "error", err)
+		return nil, err
+	}
+	for _, f := range feeds {
+		stats.TopFeeds = append(stats.TopFeeds, ReadingFeed{FeedID: f.FeedID, Title: f.Title, Count: f.Count})
+	}
+
+	latency, err := s.entries.GetReadLatency(ctx, since)
+	if err != nil {
+		logger.Error("reading stats failed", "module", "service", "action", "list", "resource", "entry", "result", "failed", "error", err)
+		return nil, err
+	}
+	if latency.Samples > 0 {
+		delay := time.Duration(latency.AverageSeconds * float64(time.Second)).Round(time.Second)
+		stats.AverageReadDelay = &delay
+	}
+
+	if stats.TotalStarred, err = s.entries.GetStarredCount(ctx); err != nil {
+		logger.Error("reading stats failed", "module", "service", "action", "list", "resource", "entry", "result", "failed", "error", err)
+		return nil, err
+	}
+	return stats, nil
+}
@@ -4,12 +4,18 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"strings"
 	"testing"
+	"time"
 
+	"gist/backend/internal/events"
 	"gist/backend/internal/model"
 	"gist/backend/internal/repository"
 	"gist/backend/internal/repository/mock"
+	"gist/backend/internal/repository/testutil"
 	"gist/backend/internal/service"
+	servicemock "gist/backend/internal/service/mock"
+	"gist/backend/pkg/linediff"
 
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
@@ -22,7 +28,7 @@ func TestEntryService_List_Success(t *testing.T) {
 	mockEntries := mock.NewMockEntryRepository(ctrl)
 	mockFeeds := mock.NewMockFeedRepository(ctrl)
 	mockFolders := mock.NewMockFolderRepository(ctrl)
-	svc := service.NewEntryService(mockEntries, mockFeeds, mockFolders)
+	svc := service.NewEntryService(mockEntries, mockFeeds, mockFolders, nil, nil, nil, nil)
 	ctx := context.Background()
 
 	expectedEntries := []model.Entry{
@@ -55,7 +61,7 @@ func TestEntryService_List_WithFeedID(t *testing.T) {
 	mockEntries := mock.NewMockEntryRepository(ctrl)
 	mockFeeds := mock.NewMockFeedRepository(ctrl)
 	mockFolders := mock.NewMockFolderRepository(ctrl)
-	svc := service.NewEntryService(mockEntries, mockFeeds, mockFolders)
+	svc := service.NewEntryService(mockEntries, mockFeeds, mockFolders, nil, nil, nil, nil)
 	ctx := context.Background()
 
 	feedID := int64(100)
@@ -88,7 +94,7 @@ func TestEntryService_List_FeedNotFound(t *testing.T) {
 	mockEntries := mock.NewMockEntryRepository(ctrl)
 	mockFeeds := mock.NewMockFeedRepository(ctrl)
 	mockFolders := mock.NewMockFolderRepository(ctrl)
-	svc := service.NewEntryService(mockEntries, mockFeeds, mockFolders)
+	svc := service.NewEntryService(mockEntries, mockFeeds, mockFolders, nil, nil, nil, nil)
 	ctx := context.Background()
 
 	feedID := int64(999)
@@ -108,7 +114,7 @@ func TestEntryService_List_FolderNotFound(t *testing.T) {
 	mockEntries := mock.NewMockEntryRepository(ctrl)
 	mockFeeds := mock.NewMockFeedRepository(ctrl)
 	mockFolders := mock.NewMockFolderRepository(ctrl)
-	svc := service.NewEntryService(mockEntries, mockFeeds, mockFolders)
+	svc := service.NewEntryService(mockEntries, mockFeeds, mockFolders, nil, nil, nil, nil)
 	ctx := context.Background()
 
 	folderID := int64(999)
@@ -128,7 +134,7 @@ func TestEntryService_List_LimitClamp(t *testing.T) {
 	mockEntries := mock.NewMockEntryRepository(ctrl)
 	mockFeeds := mock.NewMockFeedRepository(ctrl)
 	mockFolders := mock.NewMockFolderRepository(ctrl)
-	svc := service.NewEntryService(mockEntries, mockFeeds, mockFolders)
+	svc := service.NewEntryService(mockEntries, mockFeeds, mockFolders, nil, nil, nil, nil)
 	ctx := context.Background()
 
 	// Limit > 101 should be clamped to 101
@@ -150,7 +156,7 @@ func TestEntryService_List_DefaultLimit(t *testing.T) {
 	mockEntries := mock.NewMockEntryRepository(ctrl)
 	mockFeeds := mock.NewMockFeedRepository(ctrl)
 	mockFolders := mock.NewMockFolderRepository(ctrl)
-	svc := service.NewEntryService(mockEntries, mockFeeds, mockFolders)
+	svc := service.NewEntryService(mockEntries, mockFeeds, mockFolders, nil, nil, nil, nil)
 	ctx := context.Background()
 
 	// Limit <= 0 should default to 50
@@ -172,7 +178,7 @@ func TestEntryService_List_FeedCheckError(t *testing.T) {
 	mockEntries := mock.NewMockEntryRepository(ctrl)
 	mockFeeds := mock.NewMockFeedRepository(ctrl)
 	mockFolders := mock.NewMockFolderRepository(ctrl)
-	svc := service.NewEntryService(mockEntries, mockFeeds, mockFolders)
+	svc := service.NewEntryService(mockEntries, mockFeeds, mockFolders, nil, nil, nil, nil)
 	ctx := context.Background()
 
 	feedID := int64(100)
@@ -193,7 +199,7 @@ func TestEntryService_List_RepositoryError(t *testing.T) {
 	mockEntries := mock.NewMockEntryRepository(ctrl)
 	mockFeeds := mock.NewMockFeedRepository(ctrl)
 	mockFolders := mock.NewMockFolderRepository(ctrl)
-	svc := service.NewEntryService(mockEntries, mockFeeds, mockFolders)
+	svc := service.NewEntryService(mockEntries, mockFeeds, mockFolders, nil, nil, nil, nil)
 	ctx := context.Background()
 
 	dbErr := errors.New("list error")
@@ -216,7 +222,7 @@ func TestEntryService_GetByID_Success(t *testing.T) {
 	mockEntries := mock.NewMockEntryRepository(ctrl)
 	mockFeeds := mock.NewMockFeedRepository(ctrl)
 	mockFolders := mock.NewMockFolderRepository(ctrl)
-	svc := service.NewEntryService(mockEntries, mockFeeds, mockFolders)
+	svc := service.NewEntryService(mockEntries, mockFeeds, mockFolders, nil, nil, nil, nil)
 	ctx := context.Background()
 
 	expectedEntry := model.Entry{
@@ -241,7 +247,7 @@ func TestEntryService_GetByID_NotFound(t *testing.T) {
 	mockEntries := mock.NewMockEntryRepository(ctrl)
 	mockFeeds := mock.NewMockFeedRepository(ctrl)
 	mockFolders := mock.NewMockFolderRepository(ctrl)
-	svc := service.NewEntryService(mockEntries, mockFeeds, mockFolders)
+	svc := service.NewEntryService(mockEntries, mockFeeds, mockFolders, nil, nil, nil, nil)
 	ctx := context.Background()
 
 	mockEntries.EXPECT().
@@ -252,6 +258,91 @@ func TestEntryService_GetByID_NotFound(t *testing.T) {
 	require.ErrorIs(t, err, service.ErrNotFound)
 }
 
+func TestEntryService_IncludeFeed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEntries := mock.NewMockEntryRepository(ctrl)
+	svc := service.NewEntryService(mockEntries, mock.NewMockFeedRepository(ctrl), mock.NewMockFolderRepository(ctrl), nil, nil, nil, nil)
+	ctx := context.Background()
+
+	mockEntries.EXPECT().
+		List(ctx, repository.EntryListFilter{IncludeFeed: true, Limit: 50}).
+		Return(nil, nil)
+	_, err := svc.List(ctx, service.EntryListParams{IncludeFeed: true})
+	require.NoError(t, err)
+
+	mockEntries.EXPECT().
+		GetByIDWithFeed(ctx, int64(123)).
+		Return(model.Entry{ID: 123, Feed: &model.EntryFeed{Title: "Go Blog", Type: "article"}}, nil)
+	entry, err := svc.GetByIDWithFeed(ctx, 123)
+	require.NoError(t, err)
+	require.Equal(t, "Go Blog", entry.Feed.Title)
+
+	mockEntries.EXPECT().
+		GetByIDWithFeed(ctx, int64(999)).
+		Return(model.Entry{}, sql.ErrNoRows)
+	_, err = svc.GetByIDWithFeed(ctx, 999)
+	require.ErrorIs(t, err, service.ErrNotFound)
+}
+
+func TestEntryService_GetAdjacent_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEntries := mock.NewMockEntryRepository(ctrl)
+	mockFeeds := mock.NewMockFeedRepository(ctrl)
+	mockFolders := mock.NewMockFolderRepository(ctrl)
+	svc := service.NewEntryService(mockEntries, mockFeeds, mockFolders, nil, nil, nil, nil)
+	ctx := context.Background()
+
+	feedID := int64(100)
+	ref := model.Entry{ID: 123, FeedID: feedID}
+	mockEntries.EXPECT().GetByID(ctx, int64(123)).Return(ref, nil)
+	mockEntries.EXPECT().
+		GetAdjacent(ctx, ref, repository.EntryListFilter{FeedID: &feedID, UnreadOnly: true, ApplyMutes: true}, false).
+		Return(model.Entry{ID: 124, FeedID: feedID}, nil)
+
+	entry, err := svc.GetAdjacent(ctx, 123, service.EntryListParams{FeedID: &feedID, UnreadOnly: true}, false)
+	require.NoError(t, err)
+	require.NotNil(t, entry)
+	require.Equal(t, int64(124), entry.ID)
+}
+
+func TestEntryService_GetAdjacent_NoneLeft(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEntries := mock.NewMockEntryRepository(ctrl)
+	mockFeeds := mock.NewMockFeedRepository(ctrl)
+	mockFolders := mock.NewMockFolderRepository(ctrl)
+	svc := service.NewEntryService(mockEntries, mockFeeds, mockFolders, nil, nil, nil, nil)
+	ctx := context.Background()
+
+	mockEntries.EXPECT().GetByID(ctx, int64(123)).Return(model.Entry{ID: 123}, nil)
+	mockEntries.EXPECT().GetAdjacent(ctx, gomock.Any(), gomock.Any(), true).Return(model.Entry{}, sql.ErrNoRows)
+
+	entry, err := svc.GetAdjacent(ctx, 123, service.EntryListParams{}, true)
+	require.NoError(t, err)
+	require.Nil(t, entry)
+}
+
+func TestEntryService_GetAdjacent_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEntries := mock.NewMockEntryRepository(ctrl)
+	mockFeeds := mock.NewMockFeedRepository(ctrl)
+	mockFolders := mock.NewMockFolderRepository(ctrl)
+	svc := service.NewEntryService(mockEntries, mockFeeds, mockFolders, nil, nil, nil, nil)
+	ctx := context.Background()
+
+	mockEntries.EXPECT().GetByID(ctx, int64(999)).Return(model.Entry{}, sql.ErrNoRows)
+
+	_, err := svc.GetAdjacent(ctx, 999, service.EntryListParams{}, true)
+	require.ErrorIs(t, err, service.ErrNotFound)
+}
+
 func TestEntryService_MarkAsRead_Success(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -259,7 +350,7 @@ func TestEntryService_MarkAsRead_Success(t *testing.T) {
 	mockEntries := mock.NewMockEntryRepository(ctrl)
 	mockFeeds := mock.NewMockFeedRepository(ctrl)
 	mockFolders := mock.NewMockFolderRepository(ctrl)
-	svc := service.NewEntryService(mockEntries, mockFeeds, mockFolders)
+	svc := service.NewEntryService(mockEntries, mockFeeds, mockFolders, nil, nil, nil, nil)
 	ctx := context.Background()
 
 	mockEntries.EXPECT().
@@ -281,7 +372,7 @@ func TestEntryService_MarkAsRead_UpdateError(t *testing.T) {
 	mockEntries := mock.NewMockEntryRepository(ctrl)
 	mockFeeds := mock.NewMockFeedRepository(ctrl)
 	mockFolders := mock.NewMockFolderRepository(ctrl)
-	svc := service.NewEntryService(mockEntries, mockFeeds, mockFolders)
+	svc := service.NewEntryService(mockEntries, mockFeeds, mockFolders, nil, nil, nil, nil)
 	ctx := context.Background()
 
 	dbErr := errors.New("update failed")
@@ -305,7 +396,7 @@ func TestEntryService_MarkAsRead_NotFound(t *testing.T) {
 	mockEntries := mock.NewMockEntryRepository(ctrl)
 	mockFeeds := mock.NewMockFeedRepository(ctrl)
 	mockFolders := mock.NewMockFolderRepository(ctrl)
-	svc := service.NewEntryService(mockEntries, mockFeeds, mockFolders)
+	svc := service.NewEntryService(mockEntries, mockFeeds, mockFolders, nil, nil, nil, nil)
 	ctx := context.Background()
 
 	mockEntries.EXPECT().
@@ -323,7 +414,7 @@ func TestEntryService_MarkManyAsRead_Success(t *testing.T) {
 	mockEntries := mock.NewMockEntryRepository(ctrl)
 	mockFeeds := mock.NewMockFeedRepository(ctrl)
 	mockFolders := mock.NewMockFolderRepository(ctrl)
-	svc := service.NewEntryService(mockEntries, mockFeeds, mockFolders)
+	svc := service.NewEntryService(mockEntries, mockFeeds, mockFolders, nil, nil, nil, nil)
 	ctx := context.Background()
 
 	ids := []int64{123, 456}
@@ -342,7 +433,7 @@ func TestEntryService_MarkManyAsRead_EmptyIDs(t *testing.T) {
 	mockEntries := mock.NewMockEntryRepository(ctrl)
 	mockFeeds := mock.NewMockFeedRepository(ctrl)
 	mockFolders := mock.NewMockFolderRepository(ctrl)
-	svc := service.NewEntryService(mockEntries, mockFeeds, mockFolders)
+	svc := service.NewEntryService(mockEntries, mockFeeds, mockFolders, nil, nil, nil, nil)
 
 	err := svc.MarkManyAsRead(context.Background(), nil, true)
 	require.NoError(t, err)
@@ -355,7 +446,8 @@ func TestEntryService_MarkAsStarred_Success(t *testing.T) {
 	mockEntries := mock.NewMockEntryRepository(ctrl)
 	mockFeeds := mock.NewMockFeedRepository(ctrl)
 	mockFolders := mock.NewMockFolderRepository(ctrl)
-	svc := service.NewEntryService(mockEntries, mockFeeds, mockFolders)
+	bus := events.NewBus(events.SubscriberBuffer)
+	svc := service.NewEntryService(mockEntries, mockFeeds, mockFolders, nil, nil, nil, bus)
 	ctx := context.Background()
 
 	mockEntries.EXPECT().
@@ -366,8 +458,12 @@ func TestEntryService_MarkAsStarred_Success(t *testing.T) {
 		UpdateStarredStatus(ctx, int64(123), true).
 		Return(nil)
 
+	published, unsubscribe := bus.Subscribe()
+	defer unsubscribe()
+
 	err := svc.MarkAsStarred(ctx, 123, true)
 	require.NoError(t, err)
+	require.Equal(t, events.Event{Type: events.EntryStarred, Data: events.EntryStarredData{EntryID: 123, Starred: true}}, <-published)
 }
 
 func TestEntryService_MarkAsStarred_NotFound(t *testing.T) {
@@ -377,7 +473,7 @@ func TestEntryService_MarkAsStarred_NotFound(t *testing.T) {
 	mockEntries := mock.NewMockEntryRepository(ctrl)
 	mockFeeds := mock.NewMockFeedRepository(ctrl)
 	mockFolders := mock.NewMockFolderRepository(ctrl)
-	svc := service.NewEntryService(mockEntries, mockFeeds, mockFolders)
+	svc := service.NewEntryService(mockEntries, mockFeeds, mockFolders, nil, nil, nil, nil)
 	ctx := context.Background()
 
 	mockEntries.EXPECT().
@@ -395,7 +491,7 @@ func TestEntryService_MarkAsStarred_UpdateError(t *testing.T) {
 	mockEntries := mock.NewMockEntryRepository(ctrl)
 	mockFeeds := mock.NewMockFeedRepository(ctrl)
 	mockFolders := mock.NewMockFolderRepository(ctrl)
-	svc := service.NewEntryService(mockEntries, mockFeeds, mockFolders)
+	svc := service.NewEntryService(mockEntries, mockFeeds, mockFolders, nil, nil, nil, nil)
 	ctx := context.Background()
 
 	dbErr := errors.New("update failed")
@@ -419,7 +515,7 @@ func TestEntryService_MarkAllAsRead_ByFeed(t *testing.T) {
 	mockEntries := mock.NewMockEntryRepository(ctrl)
 	mockFeeds := mock.NewMockFeedRepository(ctrl)
 	mockFolders := mock.NewMockFolderRepository(ctrl)
-	svc := service.NewEntryService(mockEntries, mockFeeds, mockFolders)
+	svc := service.NewEntryService(mockEntries, mockFeeds, mockFolders, nil, nil, nil, nil)
 	ctx := context.Background()
 
 	feedID := int64(100)
@@ -443,7 +539,7 @@ func TestEntryService_MarkAllAsRead_ByFolder(t *testing.T) {
 	mockEntries := mock.NewMockEntryRepository(ctrl)
 	mockFeeds := mock.NewMockFeedRepository(ctrl)
 	mockFolders := mock.NewMockFolderRepository(ctrl)
-	svc := service.NewEntryService(mockEntries, mockFeeds, mockFolders)
+	svc := service.NewEntryService(mockEntries, mockFeeds, mockFolders, nil, nil, nil, nil)
 	ctx := context.Background()
 
 	folderID := int64(200)
@@ -467,7 +563,7 @@ func TestEntryService_MarkAllAsRead_All(t *testing.T) {
 	mockEntries := mock.NewMockEntryRepository(ctrl)
 	mockFeeds := mock.NewMockFeedRepository(ctrl)
 	mockFolders := mock.NewMockFolderRepository(ctrl)
-	svc := service.NewEntryService(mockEntries, mockFeeds, mockFolders)
+	svc := service.NewEntryService(mockEntries, mockFeeds, mockFolders, nil, nil, nil, nil)
 	ctx := context.Background()
 
 	mockEntries.EXPECT().
@@ -485,7 +581,7 @@ func TestEntryService_MarkAllAsRead_FeedNotFound(t *testing.T) {
 	mockEntries := mock.NewMockEntryRepository(ctrl)
 	mockFeeds := mock.NewMockFeedRepository(ctrl)
 	mockFolders := mock.NewMockFolderRepository(ctrl)
-	svc := service.NewEntryService(mockEntries, mockFeeds, mockFolders)
+	svc := service.NewEntryService(mockEntries, mockFeeds, mockFolders, nil, nil, nil, nil)
 	ctx := context.Background()
 
 	feedID := int64(999)
@@ -505,7 +601,7 @@ func TestEntryService_MarkAllAsRead_FolderCheckError(t *testing.T) {
 	mockEntries := mock.NewMockEntryRepository(ctrl)
 	mockFeeds := mock.NewMockFeedRepository(ctrl)
 	mockFolders := mock.NewMockFolderRepository(ctrl)
-	svc := service.NewEntryService(mockEntries, mockFeeds, mockFolders)
+	svc := service.NewEntryService(mockEntries, mockFeeds, mockFolders, nil, nil, nil, nil)
 	ctx := context.Background()
 
 	folderID := int64(100)
@@ -526,7 +622,7 @@ func TestEntryService_MarkAllAsRead_RepositoryError(t *testing.T) {
 	mockEntries := mock.NewMockEntryRepository(ctrl)
 	mockFeeds := mock.NewMockFeedRepository(ctrl)
 	mockFolders := mock.NewMockFolderRepository(ctrl)
-	svc := service.NewEntryService(mockEntries, mockFeeds, mockFolders)
+	svc := service.NewEntryService(mockEntries, mockFeeds, mockFolders, nil, nil, nil, nil)
 	ctx := context.Background()
 
 	dbErr := errors.New("mark error")
@@ -546,7 +642,7 @@ func TestEntryService_GetUnreadCounts_Success(t *testing.T) {
 	mockEntries := mock.NewMockEntryRepository(ctrl)
 	mockFeeds := mock.NewMockFeedRepository(ctrl)
 	mockFolders := mock.NewMockFolderRepository(ctrl)
-	svc := service.NewEntryService(mockEntries, mockFeeds, mockFolders)
+	svc := service.NewEntryService(mockEntries, mockFeeds, mockFolders, nil, nil, nil, nil)
 	ctx := context.Background()
 
 	expectedCounts := []repository.UnreadCount{
@@ -573,7 +669,7 @@ func TestEntryService_GetUnreadCounts_Error(t *testing.T) {
 	mockEntries := mock.NewMockEntryRepository(ctrl)
 	mockFeeds := mock.NewMockFeedRepository(ctrl)
 	mockFolders := mock.NewMockFolderRepository(ctrl)
-	svc := service.NewEntryService(mockEntries, mockFeeds, mockFolders)
+	svc := service.NewEntryService(mockEntries, mockFeeds, mockFolders, nil, nil, nil, nil)
 	ctx := context.Background()
 
 	dbErr := errors.New("count error")
@@ -586,6 +682,32 @@ func TestEntryService_GetUnreadCounts_Error(t *testing.T) {
 	require.ErrorIs(t, err, dbErr)
 }
 
+func TestEntryService_GetRecentCounts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEntries := mock.NewMockEntryRepository(ctrl)
+	svc := service.NewEntryService(mockEntries, mock.NewMockFeedRepository(ctrl), mock.NewMockFolderRepository(ctrl), nil, nil, nil, nil)
+	ctx := context.Background()
+
+	var since time.Time
+	mockEntries.EXPECT().
+		GetRecentCounts(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, s time.Time) ([]repository.RecentCount, error) {
+			since = s
+			return []repository.RecentCount{{FeedID: 1, Count: 4}}, nil
+		})
+
+	recent, err := svc.GetRecentCounts(ctx)
+	require.NoError(t, err)
+	require.Equal(t, map[int64]int{1: 4}, recent.Counts)
+	require.True(t, recent.Since.Equal(since))
+	require.False(t, since.After(time.Now()))
+	require.Less(t, time.Since(since), 25*time.Hour)
+	hour, minute, sec := since.Clock()
+	require.Zero(t, hour+minute+sec)
+}
+
 func TestEntryService_GetStarredCount_Success(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -593,7 +715,7 @@ func TestEntryService_GetStarredCount_Success(t *testing.T) {
 	mockEntries := mock.NewMockEntryRepository(ctrl)
 	mockFeeds := mock.NewMockFeedRepository(ctrl)
 	mockFolders := mock.NewMockFolderRepository(ctrl)
-	svc := service.NewEntryService(mockEntries, mockFeeds, mockFolders)
+	svc := service.NewEntryService(mockEntries, mockFeeds, mockFolders, nil, nil, nil, nil)
 	ctx := context.Background()
 
 	mockEntries.EXPECT().
@@ -612,7 +734,7 @@ func TestEntryService_GetStarredCount_Error(t *testing.T) {
 	mockEntries := mock.NewMockEntryRepository(ctrl)
 	mockFeeds := mock.NewMockFeedRepository(ctrl)
 	mockFolders := mock.NewMockFolderRepository(ctrl)
-	svc := service.NewEntryService(mockEntries, mockFeeds, mockFolders)
+	svc := service.NewEntryService(mockEntries, mockFeeds, mockFolders, nil, nil, nil, nil)
 	ctx := context.Background()
 
 	dbErr := errors.New("count error")
@@ -632,7 +754,7 @@ func TestEntryService_List_WithFilters(t *testing.T) {
 	mockEntries := mock.NewMockEntryRepository(ctrl)
 	mockFeeds := mock.NewMockFeedRepository(ctrl)
 	mockFolders := mock.NewMockFolderRepository(ctrl)
-	svc := service.NewEntryService(mockEntries, mockFeeds, mockFolders)
+	svc := service.NewEntryService(mockEntries, mockFeeds, mockFolders, nil, nil, nil, nil)
 	ctx := context.Background()
 
 	contentType := "picture"
@@ -645,6 +767,7 @@ func TestEntryService_List_WithFilters(t *testing.T) {
 			UnreadOnly:   true,
 			StarredOnly:  false,
 			HasThumbnail: true,
+			ApplyMutes:   true,
 			Limit:        20,
 			Offset:       10,
 		}).
@@ -660,13 +783,58 @@ func TestEntryService_List_WithFilters(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestEntryService_Count_SummaryLanguage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEntries := mock.NewMockEntryRepository(ctrl)
+	settings := servicemock.NewMockSettingsService(ctrl)
+	settings.EXPECT().GetAISettings(gomock.Any()).Return(&service.AISettings{SummaryLanguage: "en-US"}, nil).AnyTimes()
+	svc := service.NewEntryService(mockEntries, mock.NewMockFeedRepository(ctrl), mock.NewMockFolderRepository(ctrl), mock.NewMockAISummaryRepository(ctrl), settings, nil, nil)
+	ctx := context.Background()
+
+	// The configured language unless the params name one; Offset never narrows a count
+	mockEntries.EXPECT().
+		Count(ctx, repository.EntryListFilter{HasReadableContent: true, SummaryLanguage: "en-US"}).
+		Return(3, nil)
+	count, err := svc.Count(ctx, service.EntryListParams{HasReadableContent: true, HasSummary: true, Offset: 20})
+	require.NoError(t, err)
+	require.Equal(t, 3, count)
+
+	mockEntries.EXPECT().
+		Count(ctx, repository.EntryListFilter{SummaryLanguage: "ja"}).
+		Return(1, nil)
+	count, err = svc.Count(ctx, service.EntryListParams{HasSummary: true, SummaryLanguage: "ja"})
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+
+	// The language alone does not filter
+	mockEntries.EXPECT().Count(ctx, repository.EntryListFilter{}).Return(9, nil)
+	count, err = svc.Count(ctx, service.EntryListParams{SummaryLanguage: "ja"})
+	require.NoError(t, err)
+	require.Equal(t, 9, count)
+}
+
+func TestEntryService_Count_FeedNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFeeds := mock.NewMockFeedRepository(ctrl)
+	svc := service.NewEntryService(mock.NewMockEntryRepository(ctrl), mockFeeds, mock.NewMockFolderRepository(ctrl), nil, nil, nil, nil)
+	feedID := int64(7)
+	mockFeeds.EXPECT().GetByID(gomock.Any(), feedID).Return(model.Feed{}, sql.ErrNoRows)
+
+	_, err := svc.Count(context.Background(), service.EntryListParams{FeedID: &feedID})
+	require.ErrorIs(t, err, service.ErrNotFound)
+}
+
 func TestEntryService_ClearEntryCache_ResetFailureIgnored(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockEntries := mock.NewMockEntryRepository(ctrl)
 	mockFeeds := mock.NewMockFeedRepository(ctrl)
-	svc := service.NewEntryService(mockEntries, mockFeeds, mock.NewMockFolderRepository(ctrl))
+	svc := service.NewEntryService(mockEntries, mockFeeds, mock.NewMockFolderRepository(ctrl), nil, nil, nil, nil)
 
 	mockEntries.EXPECT().DeleteUnstarred(context.Background()).Return(int64(2), nil)
 	mockFeeds.EXPECT().ClearAllConditionalGet(context.Background()).Return(int64(0), errors.New("reset failed"))
@@ -687,7 +855,7 @@ func TestEntryService_ClearEntryCache_ResetsConditionalGet(t *testing.T) {
 
 	mockEntries := mock.NewMockEntryRepository(ctrl)
 	mockFeeds := mock.NewMockFeedRepository(ctrl)
-	svc := service.NewEntryService(mockEntries, mockFeeds, mock.NewMockFolderRepository(ctrl))
+	svc := service.NewEntryService(mockEntries, mockFeeds, mock.NewMockFolderRepository(ctrl), nil, nil, nil, nil)
 
 	// Both DeleteUnstarred and ClearAllConditionalGet should be called
 	mockEntries.EXPECT().DeleteUnstarred(context.Background()).Return(int64(5), nil)
@@ -705,7 +873,7 @@ func TestEntryService_ClearEntryCache_DeleteError(t *testing.T) {
 
 	mockEntries := mock.NewMockEntryRepository(ctrl)
 	mockFeeds := mock.NewMockFeedRepository(ctrl)
-	svc := service.NewEntryService(mockEntries, mockFeeds, mock.NewMockFolderRepository(ctrl))
+	svc := service.NewEntryService(mockEntries, mockFeeds, mock.NewMockFolderRepository(ctrl), nil, nil, nil, nil)
 
 	dbErr := errors.New("delete failed")
 	mockEntries.EXPECT().DeleteUnstarred(context.Background()).Return(int64(0), dbErr)
@@ -718,3 +886,397 @@ func TestEntryService_ClearEntryCache_DeleteError(t *testing.T) {
 func stringPtr(s string) *string {
 	return &s
 }
+
+func TestEntryService_ListRevisions_DiffsAgainstSuccessor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEntries := mock.NewMockEntryRepository(ctrl)
+	svc := service.NewEntryService(mockEntries, mock.NewMockFeedRepository(ctrl), mock.NewMockFolderRepository(ctrl), nil, nil, nil, nil)
+	ctx := context.Background()
+
+	mockEntries.EXPECT().GetByID(ctx, int64(1)).Return(model.Entry{ID: 1, Content: stringPtr("<p>intro</p><p>edited twice</p>")}, nil)
+	mockEntries.EXPECT().ListRevisions(ctx, int64(1)).Return([]model.EntryRevision{
+		{ID: 20, EntryID: 1, Content: stringPtr("<p>intro</p><p>edited once</p>")},
+		{ID: 10, EntryID: 1, Content: stringPtr("<p>intro</p><p>original</p>")},
+	}, nil)
+
+	revisions, err := svc.ListRevisions(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, revisions, 2)
+
+	require.Equal(t, []linediff.Line{
+		{Op: linediff.Equal, Text: "<p>intro</p>"},
+		{Op: linediff.Delete, Text: "<p>edited once</p>"},
+		{Op: linediff.Insert, Text: "<p>edited twice</p>"},
+	}, revisions[0].Diff)
+	require.Equal(t, []linediff.Line{
+		{Op: linediff.Equal, Text: "<p>intro</p>"},
+		{Op: linediff.Delete, Text: "<p>original</p>"},
+		{Op: linediff.Insert, Text: "<p>edited once</p>"},
+	}, revisions[1].Diff)
+}
+
+func TestEntryService_ListRevisions_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEntries := mock.NewMockEntryRepository(ctrl)
+	svc := service.NewEntryService(mockEntries, mock.NewMockFeedRepository(ctrl), mock.NewMockFolderRepository(ctrl), nil, nil, nil, nil)
+	ctx := context.Background()
+
+	mockEntries.EXPECT().GetByID(ctx, int64(404)).Return(model.Entry{}, sql.ErrNoRows)
+
+	_, err := svc.ListRevisions(ctx, 404)
+	require.ErrorIs(t, err, service.ErrNotFound)
+}
+
+func TestEntryService_GetDailyDigest_GroupsAndInlinesSummaries(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEntries := mock.NewMockEntryRepository(ctrl)
+	mockSummaries := mock.NewMockAISummaryRepository(ctrl)
+	mockSettings := servicemock.NewMockSettingsService(ctrl)
+	mockSettings.EXPECT().GetGeneralSettings(gomock.Any()).Return(&service.GeneralSettings{Timezone: "Asia/Shanghai"}, nil).AnyTimes()
+	mockSettings.EXPECT().GetAISettings(gomock.Any()).Return(&service.AISettings{SummaryLanguage: "en-US"}, nil).AnyTimes()
+	svc := service.NewEntryService(mockEntries, mock.NewMockFeedRepository(ctrl), mock.NewMockFolderRepository(ctrl), mockSummaries, mockSettings, nil, nil)
+	ctx := context.Background()
+
+	shanghai, err := time.LoadLocation("Asia/Shanghai")
+	require.NoError(t, err)
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, shanghai)
+
+	tech, news := int64(10), int64(20)
+	rows := []repository.DigestEntry{
+		{Entry: model.Entry{ID: 1, FeedID: 100}, FeedTitle: "Zeta", FolderID: &tech, FolderName: stringPtr("Tech"), FeedTotal: 7},
+		{Entry: model.Entry{ID: 2, FeedID: 200}, FeedTitle: "Loose", FeedTotal: 1},
+		{Entry: model.Entry{ID: 3, FeedID: 300}, FeedTitle: "Alpha", FolderID: &tech, FolderName: stringPtr("Tech"), FeedTotal: 1},
+		{Entry: model.Entry{ID: 4, FeedID: 100}, FeedTitle: "Zeta", FolderID: &tech, FolderName: stringPtr("Tech"), FeedTotal: 7},
+		{Entry: model.Entry{ID: 5, FeedID: 400}, FeedTitle: "Daily", FolderID: &news, FolderName: stringPtr("News"), FeedTotal: 1},
+	}
+	mockEntries.EXPECT().ListForDigest(ctx, start, start.AddDate(0, 0, 1), 3).Return(rows, nil)
+	mockEntries.EXPECT().GetAllUnreadCounts(ctx).Return([]repository.UnreadCount{{FeedID: 100, Count: 9}, {FeedID: 300, Count: 2}}, nil)
+	mockSummaries.EXPECT().GetBatch(ctx, []int64{1, 2, 3, 4, 5}, "en-US").
+		Return(map[int64]*model.AISummary{4: {EntryID: 4, Summary: "tl;dr"}}, nil)
+
+	digest, err := svc.GetDailyDigest(ctx, service.DailyDigestParams{Date: "2024-03-01", PerFeed: 3})
+	require.NoError(t, err)
+	require.Equal(t, "2024-03-01", digest.Date)
+	require.Equal(t, "Asia/Shanghai", digest.Timezone)
+
+	require.Len(t, digest.Folders, 3)
+	require.Equal(t, "News", digest.Folders[0].Name)
+	require.Equal(t, "Tech", digest.Folders[1].Name)
+	require.Nil(t, digest.Folders[2].FolderID)
+
+	techFolder := digest.Folders[1]
+	require.Equal(t, 11, techFolder.UnreadCount)
+	require.Len(t, techFolder.Feeds, 2)
+	require.Equal(t, "Alpha", techFolder.Feeds[0].Title)
+	zeta := techFolder.Feeds[1]
+	require.Equal(t, 9, zeta.UnreadCount)
+	require.Equal(t, 7, zeta.Total)
+	require.Len(t, zeta.Entries, 2)
+	require.Equal(t, int64(1), zeta.Entries[0].Entry.ID)
+	require.Nil(t, zeta.Entries[0].Summary)
+	require.Equal(t, "tl;dr", *zeta.Entries[1].Summary)
+}
+
+func TestEntryService_GetDailyDigest_EmptyDay(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEntries := mock.NewMockEntryRepository(ctrl)
+	mockSettings := servicemock.NewMockSettingsService(ctrl)
+	mockSettings.EXPECT().GetGeneralSettings(gomock.Any()).Return(&service.GeneralSettings{Timezone: ""}, nil).AnyTimes()
+	mockSettings.EXPECT().GetAISettings(gomock.Any()).Return(&service.AISettings{SummaryLanguage: "en-US"}, nil).AnyTimes()
+	svc := service.NewEntryService(mockEntries, mock.NewMockFeedRepository(ctrl), mock.NewMockFolderRepository(ctrl), mock.NewMockAISummaryRepository(ctrl), mockSettings, nil, nil)
+	ctx := context.Background()
+
+	mockEntries.EXPECT().ListForDigest(ctx, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC), service.DefaultDigestPerFeed).
+		Return(nil, nil)
+
+	digest, err := svc.GetDailyDigest(ctx, service.DailyDigestParams{Date: "2024-03-01"})
+	require.NoError(t, err)
+	require.Equal(t, "UTC", digest.Timezone)
+	require.NotNil(t, digest.Folders)
+	require.Empty(t, digest.Folders)
+}
+
+func TestEntryService_GetDailyDigest_InvalidDate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEntries := mock.NewMockEntryRepository(ctrl)
+	mockSettings := servicemock.NewMockSettingsService(ctrl)
+	mockSettings.EXPECT().GetGeneralSettings(gomock.Any()).Return(&service.GeneralSettings{Timezone: "UTC"}, nil).AnyTimes()
+	mockSettings.EXPECT().GetAISettings(gomock.Any()).Return(&service.AISettings{SummaryLanguage: "en-US"}, nil).AnyTimes()
+	svc := service.NewEntryService(mockEntries, mock.NewMockFeedRepository(ctrl), mock.NewMockFolderRepository(ctrl), mock.NewMockAISummaryRepository(ctrl), mockSettings, nil, nil)
+
+	_, err := svc.GetDailyDigest(context.Background(), service.DailyDigestParams{Date: "2024-13-01"})
+	require.ErrorIs(t, err, service.ErrInvalid)
+}
+
+func TestEntryService_GetReadingStats(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEntries := mock.NewMockEntryRepository(ctrl)
+	mockSettings := servicemock.NewMockSettingsService(ctrl)
+	mockSettings.EXPECT().GetGeneralSettings(gomock.Any()).Return(&service.GeneralSettings{Timezone: "Asia/Shanghai"}, nil).AnyTimes()
+	mockSettings.EXPECT().GetAISettings(gomock.Any()).Return(&service.AISettings{SummaryLanguage: "en-US"}, nil).AnyTimes()
+	svc := service.NewEntryService(mockEntries, mock.NewMockFeedRepository(ctrl), mock.NewMockFolderRepository(ctrl), mock.NewMockAISummaryRepository(ctrl), mockSettings, nil, nil)
+	ctx := context.Background()
+
+	shanghai, err := time.LoadLocation("Asia/Shanghai")
+	require.NoError(t, err)
+	now := time.Now().In(shanghai)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, shanghai)
+	since := today.AddDate(0, 0, -2)
+
+	mockEntries.EXPECT().CountReadsByDay(ctx, since, 8*time.Hour).
+		Return([]repository.DailyReadCount{{Date: today.Format("2006-01-02"), Count: 4}, {Date: since.Format("2006-01-02"), Count: 1}}, nil)
+	mockEntries.EXPECT().TopReadFeeds(ctx, since, 10).Return([]repository.FeedReadCount{{FeedID: 100, Title: "Feed", Count: 5}}, nil)
+	mockEntries.EXPECT().GetReadLatency(ctx, since).Return(repository.ReadLatency{AverageSeconds: 5399.6, Samples: 2}, nil)
+	mockEntries.EXPECT().GetStarredCount(ctx).Return(7, nil)
+
+	stats, err := svc.GetReadingStats(ctx, 3)
+	require.NoError(t, err)
+	require.Equal(t, "Asia/Shanghai", stats.Timezone)
+	require.Equal(t, since, stats.Since)
+	require.Equal(t, []service.ReadingDay{
+		{Date: since.Format("2006-01-02"), Count: 1},
+		{Date: since.AddDate(0, 0, 1).Format("2006-01-02"), Count: 0},
+		{Date: today.Format("2006-01-02"), Count: 4},
+	}, stats.Daily)
+	require.Equal(t, 5, stats.TotalRead)
+	require.Equal(t, []service.ReadingFeed{{FeedID: 100, Title: "Feed", Count: 5}}, stats.TopFeeds)
+	require.Equal(t, 90*time.Minute, *stats.AverageReadDelay)
+	require.Equal(t, 7, stats.TotalStarred)
+}
+
+func TestEntryService_GetReadingStats_Defaults(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEntries := mock.NewMockEntryRepository(ctrl)
+	mockSettings := servicemock.NewMockSettingsService(ctrl)
+	mockSettings.EXPECT().GetGeneralSettings(gomock.Any()).Return(&service.GeneralSettings{Timezone: ""}, nil).AnyTimes()
+	mockSettings.EXPECT().GetAISettings(gomock.Any()).Return(&service.AISettings{SummaryLanguage: "en-US"}, nil).AnyTimes()
+	svc := service.NewEntryService(mockEntries, mock.NewMockFeedRepository(ctrl), mock.NewMockFolderRepository(ctrl), mock.NewMockAISummaryRepository(ctrl), mockSettings, nil, nil)
+	ctx := context.Background()
+
+	mockEntries.EXPECT().CountReadsByDay(ctx, gomock.Any(), time.Duration(0)).Return(nil, nil)
+	mockEntries.EXPECT().TopReadFeeds(ctx, gomock.Any(), 10).Return(nil, nil)
+	mockEntries.EXPECT().GetReadLatency(ctx, gomock.Any()).Return(repository.ReadLatency{}, nil)
+	mockEntries.EXPECT().GetStarredCount(ctx).Return(0, nil)
+
+	stats, err := svc.GetReadingStats(ctx, 0)
+	require.NoError(t, err)
+	require.Equal(t, service.DefaultReadingStatsDays, stats.Days)
+	require.Len(t, stats.Daily, service.DefaultReadingStatsDays)
+	require.NotNil(t, stats.TopFeeds)
+	require.Nil(t, stats.AverageReadDelay)
+
+	_, err = svc.GetReadingStats(ctx, service.MaxReadingStatsDays+1)
+	require.ErrorIs(t, err, service.ErrInvalid)
+}
+
+func TestEntryService_Visit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEntries := mock.NewMockEntryRepository(ctrl)
+	mockSettings := servicemock.NewMockSettingsService(ctrl)
+	mockSettings.EXPECT().GetGeneralSettings(gomock.Any()).Return(&service.GeneralSettings{Timezone: "UTC"}, nil).AnyTimes()
+	mockSettings.EXPECT().GetAISettings(gomock.Any()).Return(&service.AISettings{SummaryLanguage: "en-US"}, nil).AnyTimes()
+	svc := service.NewEntryService(mockEntries, mock.NewMockFeedRepository(ctrl), mock.NewMockFolderRepository(ctrl), mock.NewMockAISummaryRepository(ctrl), mockSettings, nil, nil)
+	ctx := context.Background()
+
+	unread := model.Entry{ID: 1, FeedID: 100, URL: stringPtr("https://example.com/post")}
+	mockEntries.EXPECT().GetByID(ctx, int64(1)).Return(unread, nil).Times(2)
+	mockEntries.EXPECT().RecordClick(ctx, int64(1), int64(100), gomock.Any()).Return(nil)
+	mockEntries.EXPECT().UpdateReadStatus(ctx, int64(1), true).Return(nil)
+
+	target, err := svc.Visit(ctx, 1, true)
+	require.NoError(t, err)
+	require.Equal(t, "https://example.com/post", target)
+
+	// markRead=false only records the click
+	mockEntries.EXPECT().GetByID(ctx, int64(1)).Return(unread, nil)
+	mockEntries.EXPECT().RecordClick(ctx, int64(1), int64(100), gomock.Any()).Return(nil)
+	_, err = svc.Visit(ctx, 1, false)
+	require.NoError(t, err)
+}
+
+func TestEntryService_Visit_RejectsNonHTTPURLs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEntries := mock.NewMockEntryRepository(ctrl)
+	mockSettings := servicemock.NewMockSettingsService(ctrl)
+	mockSettings.EXPECT().GetGeneralSettings(gomock.Any()).Return(&service.GeneralSettings{Timezone: "UTC"}, nil).AnyTimes()
+	mockSettings.EXPECT().GetAISettings(gomock.Any()).Return(&service.AISettings{SummaryLanguage: "en-US"}, nil).AnyTimes()
+	svc := service.NewEntryService(mockEntries, mock.NewMockFeedRepository(ctrl), mock.NewMockFolderRepository(ctrl), mock.NewMockAISummaryRepository(ctrl), mockSettings, nil, nil)
+	ctx := context.Background()
+
+	for _, raw := range []string{"javascript:alert(1)", "JavaScript://example.com/%0Aalert(1)", "data:text/html,hi", "//example.com/post", "/relative"} {
+		mockEntries.EXPECT().GetByID(ctx, int64(1)).Return(model.Entry{ID: 1, FeedID: 100, URL: stringPtr(raw)}, nil)
+		_, err := svc.Visit(ctx, 1, true)
+		require.ErrorIs(t, err, service.ErrInvalid, raw)
+	}
+
+	mockEntries.EXPECT().GetByID(ctx, int64(1)).Return(model.Entry{ID: 1, FeedID: 100}, nil)
+	_, err := svc.Visit(ctx, 1, true)
+	require.ErrorIs(t, err, service.ErrInvalid)
+
+	mockEntries.EXPECT().GetByID(ctx, int64(2)).Return(model.Entry{}, sql.ErrNoRows)
+	_, err = svc.Visit(ctx, 2, true)
+	require.ErrorIs(t, err, service.ErrNotFound)
+}
+
+func TestEntryService_GetClickStats(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEntries := mock.NewMockEntryRepository(ctrl)
+	mockSettings := servicemock.NewMockSettingsService(ctrl)
+	mockSettings.EXPECT().GetGeneralSettings(gomock.Any()).Return(&service.GeneralSettings{Timezone: "Asia/Shanghai"}, nil).AnyTimes()
+	mockSettings.EXPECT().GetAISettings(gomock.Any()).Return(&service.AISettings{SummaryLanguage: "en-US"}, nil).AnyTimes()
+	svc := service.NewEntryService(mockEntries, mock.NewMockFeedRepository(ctrl), mock.NewMockFolderRepository(ctrl), mock.NewMockAISummaryRepository(ctrl), mockSettings, nil, nil)
+	ctx := context.Background()
+
+	shanghai, err := time.LoadLocation("Asia/Shanghai")
+	require.NoError(t, err)
+	now := time.Now().In(shanghai)
+	since := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, shanghai).AddDate(0, 0, -(service.DefaultClickStatsDays - 1))
+	clicked := time.Date(2024, 3, 2, 10, 0, 0, 0, time.UTC)
+
+	mockEntries.EXPECT().CountClicksByFeed(ctx, since).Return([]repository.FeedClickCount{
+		{FeedID: 100, Title: "Busy", Count: 4, LastClickedAt: &clicked},
+		{FeedID: 200, Title: "Ignored"},
+	}, nil)
+
+	stats, err := svc.GetClickStats(ctx, 0)
+	require.NoError(t, err)
+	require.Equal(t, service.DefaultClickStatsDays, stats.Days)
+	require.Equal(t, "Asia/Shanghai", stats.Timezone)
+	require.Equal(t, since, stats.Since)
+	require.Equal(t, []service.FeedClicks{
+		{FeedID: 100, Title: "Busy", Count: 4, LastClickedAt: &clicked},
+		{FeedID: 200, Title: "Ignored"},
+	}, stats.Feeds)
+
+	_, err = svc.GetClickStats(ctx, service.MaxClickStatsDays+1)
+	require.ErrorIs(t, err, service.ErrInvalid)
+}
+
+func TestEntryService_SetNote(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEntries := mock.NewMockEntryRepository(ctrl)
+	svc := service.NewEntryService(mockEntries, mock.NewMockFeedRepository(ctrl), mock.NewMockFolderRepository(ctrl), nil, nil, nil, nil)
+	ctx := context.Background()
+
+	mockEntries.EXPECT().GetByID(ctx, int64(123)).Return(model.Entry{ID: 123}, nil).Times(2)
+	mockEntries.EXPECT().SetNote(ctx, int64(123), "cite this").Return(nil)
+	require.NoError(t, svc.SetNote(ctx, 123, "  cite this\n"))
+
+	// A blank note deletes the stored one
+	mockEntries.EXPECT().DeleteNote(ctx, int64(123)).Return(nil)
+	require.NoError(t, svc.SetNote(ctx, 123, " \n "))
+
+	require.ErrorIs(t, svc.SetNote(ctx, 123, strings.Repeat("x", service.MaxEntryNoteSize+1)), service.ErrNoteTooLarge)
+
+	mockEntries.EXPECT().GetByID(ctx, int64(999)).Return(model.Entry{}, sql.ErrNoRows).Times(2)
+	require.ErrorIs(t, svc.SetNote(ctx, 999, "note"), service.ErrNotFound)
+	require.ErrorIs(t, svc.DeleteNote(ctx, 999), service.ErrNotFound)
+}
+
+func TestEntryService_GetByIDs(t *testing.T) {
+	database := testutil.NewTestDB(t)
+	entries := repository.NewEntryRepository(database, nil, nil)
+	summaries := repository.NewAISummaryRepository(database, nil, nil)
+	svc := service.NewEntryService(entries, repository.NewFeedRepository(database, nil, nil), repository.NewFolderRepository(database, nil, nil), summaries, nil, nil, nil)
+	ctx := context.Background()
+
+	feedID := testutil.SeedFeed(t, database, model.Feed{Title: "Feed", URL: "https://example.com/rss"})
+	first := testutil.SeedEntry(t, database, model.Entry{FeedID: feedID, Title: stringPtr("First"), Content: stringPtr("<p>one</p>")})
+	second := testutil.SeedEntry(t, database, model.Entry{FeedID: feedID, Title: stringPtr("Second"), Starred: true})
+	third := testutil.SeedEntry(t, database, model.Entry{FeedID: feedID, Title: stringPtr("Third"), Read: true})
+	require.NoError(t, summaries.Save(ctx, second, false, "zh-CN", "summary of second"))
+
+	missing := third + 1000
+	got, err := svc.GetByIDs(ctx, []int64{third, missing, first, second, first}, false)
+	require.NoError(t, err)
+	require.Len(t, got, 3)
+	require.Equal(t, []string{"Third", "First", "Second"}, []string{*got[0].Entry.Title, *got[1].Entry.Title, *got[2].Entry.Title})
+	require.True(t, got[0].Entry.Read)
+	require.Equal(t, "<p>one</p>", *got[1].Entry.Content)
+	require.True(t, got[2].Entry.Starred)
+	require.Nil(t, got[2].Summary)
+
+	got, err = svc.GetByIDs(ctx, []int64{second, first}, true)
+	require.NoError(t, err)
+	require.Equal(t, "summary of second", *got[0].Summary)
+	require.Nil(t, got[1].Summary)
+
+	got, err = svc.GetByIDs(ctx, []int64{missing}, true)
+	require.NoError(t, err)
+	require.Empty(t, got)
+
+	_, err = svc.GetByIDs(ctx, make([]int64, service.MaxBulkEntries+1), false)
+	require.ErrorIs(t, err, service.ErrInvalid)
+}
+
+func TestEntryService_GetRawItem_NotKept(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEntries := mock.NewMockEntryRepository(ctrl)
+	svc := service.NewEntryService(mockEntries, mock.NewMockFeedRepository(ctrl), mock.NewMockFolderRepository(ctrl), nil, nil, nil, nil)
+	ctx := context.Background()
+
+	mockEntries.EXPECT().GetByID(ctx, int64(1)).Return(model.Entry{ID: 1}, nil)
+	mockEntries.EXPECT().GetRawItem(ctx, int64(1)).Return(nil, nil)
+
+	_, err := svc.GetRawItem(ctx, 1)
+	require.ErrorIs(t, err, service.ErrNotFound)
+}
+
+func TestEntryService_Search(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEntries := mock.NewMockEntryRepository(ctrl)
+	mockFeeds := mock.NewMockFeedRepository(ctrl)
+	mockFolders := mock.NewMockFolderRepository(ctrl)
+	svc := service.NewEntryService(mockEntries, mockFeeds, mockFolders, nil, nil, nil, nil)
+	ctx := context.Background()
+
+	folderID := int64(7)
+	mockFolders.EXPECT().GetByID(ctx, folderID).Return(model.Folder{ID: folderID}, nil)
+	mockEntries.EXPECT().Search(ctx, repository.EntrySearchFilter{
+		Query:       "go release",
+		Column:      repository.EntrySearchColumnTitle,
+		FolderID:    &folderID,
+		BoostUnread: true,
+		Limit:       50,
+	}).Return([]model.Entry{{ID: 1}}, nil)
+
+	entries, err := svc.Search(ctx, service.EntrySearchParams{Query: "  go release ", SearchIn: service.EntrySearchInTitle, FolderID: &folderID, BoostUnread: true})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+
+	_, err = svc.Search(ctx, service.EntrySearchParams{Query: " "})
+	require.ErrorIs(t, err, service.ErrInvalid)
+	_, err = svc.Search(ctx, service.EntrySearchParams{Query: "go", SearchIn: "author"})
+	require.ErrorIs(t, err, service.ErrInvalid)
+
+	missing := int64(9)
+	mockFeeds.EXPECT().GetByID(ctx, missing).Return(model.Feed{}, sql.ErrNoRows)
+	_, err = svc.Search(ctx, service.EntrySearchParams{Query: "go", FeedID: &missing})
+	require.ErrorIs(t, err, service.ErrNotFound)
+}
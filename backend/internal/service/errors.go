@@ -2,6 +2,8 @@ package service
 
 import (
 	"errors"
+	"fmt"
+	"time"
 
 	"gist/backend/internal/model"
 )
@@ -11,6 +13,30 @@ var (
 	ErrConflict  = errors.New("conflict")
 	ErrInvalid   = errors.New("invalid")
 	ErrFeedFetch = errors.New("feed fetch failed")
+
+	// ErrFolderCycle is returned when a folder would become its own ancestor.
+	ErrFolderCycle = fmt.Errorf("folder cycle: %w", ErrInvalid)
+	// ErrAnubisRejected is returned when the upstream served an Anubis rejection page.
+	ErrAnubisRejected = errors.New("upstream rejected")
+	// ErrAIRateLimited is returned when an AI request could not get a rate limiter slot.
+	ErrAIRateLimited = errors.New("ai rate limited")
+	// ErrAINotConfigured is returned when the AI provider settings are incomplete.
+	ErrAINotConfigured = errors.New("ai not configured")
+	// ErrUnsupportedContentType is returned when a page is neither HTML, PDF nor plain text.
+	ErrUnsupportedContentType = errors.New("unsupported content type")
+	// ErrNotAFeed is returned when pasted content is not an RSS, Atom or JSON feed.
+	ErrNotAFeed = fmt.Errorf("not a feed: %w", ErrInvalid)
+	// ErrStaticFeedTooLarge is returned when pasted feed content exceeds MaxStaticFeedSize.
+	ErrStaticFeedTooLarge = errors.New("static feed too large")
+	// ErrIngestTooLarge is returned when a push exceeds MaxIngestItems or MaxIngestBodySize.
+	ErrIngestTooLarge = errors.New("ingest payload too large")
+	// ErrNoteTooLarge is returned when an entry note exceeds MaxEntryNoteSize.
+	ErrNoteTooLarge = errors.New("note too large")
+	// ErrThumbnailUnavailable is returned when an entry thumbnail could not be fetched
+	// or decoded, now or within the last ThumbnailFailureTTL.
+	ErrThumbnailUnavailable = errors.New("thumbnail unavailable")
+	// ErrMaintenanceRunning is returned when a maintenance action or compaction is already running.
+	ErrMaintenanceRunning = errors.New("maintenance already running")
 )
 
 // FeedConflictError is returned when a feed URL already exists.
@@ -25,3 +51,16 @@ func (e *FeedConflictError) Error() string {
 func (e *FeedConflictError) Is(target error) bool {
 	return target == ErrConflict
 }
+
+// LoginLockedError is returned while login attempts are locked out.
+type LoginLockedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LoginLockedError) Error() string {
+	return "too many login attempts"
+}
+
+func (e *LoginLockedError) Is(target error) bool {
+	return target == ErrTooManyAttempts
+}
@@ -1,14 +1,21 @@
 package service
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // Export for testing
 var IsValidURL = isValidURL
 var HasDynamicTime = hasDynamicTime
 var ExtractDateFromSummary = extractDateFromSummary
 var ExtractPublishedAt = extractPublishedAt
+var ResolvePublishedAt = resolvePublishedAt
+var HasZoneInfo = hasZoneInfo
 var ExtractThumbnail = extractThumbnail
 var ComputeEntryHash = computeEntryHash
+var ItemToEntry = itemToEntry
+var ClassifyFeedType = classifyFeedType
 var OptionalString = optionalString
 var WalkTree = walkTree
 var BuildReferer = buildReferer
@@ -16,30 +23,38 @@ var IsValidHost = isValidHost
 var DefaultAppearanceContentTypes = defaultAppearanceContentTypes
 var MaskAPIKey = maskAPIKey
 var IsMaskedKey = isMaskedKey
+var LockoutDuration = lockoutDuration
+var EffectiveFeedConfig = effectiveFeedConfig
 
 const (
-	KeyAISummaryLanguage = keyAISummaryLanguage
-	KeyUserUsername      = keyUserUsername
-	KeyUserNickname      = keyUserNickname
-	KeyUserEmail         = keyUserEmail
-	KeyUserPasswordHash  = keyUserPasswordHash
-	KeyUserJWTSecret     = keyUserJWTSecret
-	KeyAIProvider        = keyAIProvider
-	KeyAIAPIKey          = keyAIAPIKey
-	KeyAIBaseURL         = keyAIBaseURL
-	KeyAIModel           = keyAIModel
-	KeyAIRequestOptions  = keyAIRequestOptions
-	KeyAIAutoTranslate   = keyAIAutoTranslate
-	KeyAIAutoSummary     = keyAIAutoSummary
-	KeyAIRateLimit       = keyAIRateLimit
-	KeyMarkReadOnScroll  = keyMarkReadOnScroll
-	KeyNetworkEnabled    = keyNetworkEnabled
-	KeyNetworkType       = keyNetworkType
-	KeyNetworkHost       = keyNetworkHost
-	KeyNetworkPort       = keyNetworkPort
-	KeyNetworkUsername   = keyNetworkUsername
-	KeyNetworkPassword   = keyNetworkPassword
-	KeyNetworkIPStack    = keyNetworkIPStack
+	KeyAISummaryLanguage     = keyAISummaryLanguage
+	KeyUserUsername          = keyUserUsername
+	KeyUserNickname          = keyUserNickname
+	KeyUserEmail             = keyUserEmail
+	KeyUserPasswordHash      = keyUserPasswordHash
+	KeyUserJWTSecret         = keyUserJWTSecret
+	KeyUserTOTPSecret        = keyUserTOTPSecret
+	KeyAIProvider            = keyAIProvider
+	KeyAIAPIKey              = keyAIAPIKey
+	KeyAIBaseURL             = keyAIBaseURL
+	KeyAIModel               = keyAIModel
+	KeyAIRequestOptions      = keyAIRequestOptions
+	KeyAIAutoTranslate       = keyAIAutoTranslate
+	KeyAITranslateOnRefresh  = keyAITranslateOnRefresh
+	KeyAIAutoSummary         = keyAIAutoSummary
+	KeyAIRateLimit           = keyAIRateLimit
+	KeyAIModelPrices         = keyAIModelPrices
+	KeyAIUsageRetention      = keyAIUsageRetention
+	KeyAITranslateBlockChars = keyAITranslateBlockChars
+	KeyMarkReadOnScroll      = keyMarkReadOnScroll
+	KeyFallbackUserAgent     = keyFallbackUserAgent
+	KeyNetworkEnabled        = keyNetworkEnabled
+	KeyNetworkType           = keyNetworkType
+	KeyNetworkHost           = keyNetworkHost
+	KeyNetworkPort           = keyNetworkPort
+	KeyNetworkUsername       = keyNetworkUsername
+	KeyNetworkPassword       = keyNetworkPassword
+	KeyNetworkIPStack        = keyNetworkIPStack
 )
 
 var (
@@ -54,6 +69,9 @@ var (
 	ErrCurrentPasswordRequiredHelper = ErrCurrentPasswordRequired
 	ErrSamePasswordHelper            = ErrSamePassword
 	ErrInvalidTokenHelper            = ErrInvalidToken
+	ErrTwoFactorInvalidCodeHelper    = ErrTwoFactorInvalidCode
+	ErrTwoFactorNotSetupHelper       = ErrTwoFactorNotSetup
+	ErrTooManyAttemptsHelper         = ErrTooManyAttempts
 )
 
 // Refresh service helpers for tests.
@@ -65,7 +83,28 @@ func SetRefreshServiceRefreshing(s RefreshService, v bool) {
 
 func BuildAISummarizeSystemPromptForTest(s AIService, ctx context.Context, entryID int64, title string) string {
 	if svc, ok := s.(*aiService); ok {
-		return svc.buildSummarizeSystemPrompt(ctx, entryID, title)
+		return svc.buildSummarizeSystemPrompt(ctx, entryID, title, svc.GetSummaryLanguage(ctx))
 	}
 	return ""
 }
+
+// WithInitialBackfillForTest exposes withInitialBackfill for tests.
+func WithInitialBackfillForTest(ctx context.Context, b InitialBackfill) context.Context {
+	return withInitialBackfill(ctx, b)
+}
+
+const GUIDPermaLinkKey = guidPermaLinkKey
+
+var EncodeThumbnail = encodeThumbnail
+
+var FeedPollHint = feedPollHint
+
+var WaitOutQuietHours = waitOutQuietHours
+
+// SetQuietHoursRecheckForTest shortens how often paused jobs recheck the quiet
+// hours and returns a function restoring it.
+func SetQuietHoursRecheckForTest(d time.Duration) func() {
+	previous := quietHoursRecheck
+	quietHoursRecheck = d
+	return func() { quietHoursRecheck = previous }
+}
@@ -0,0 +1,177 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"unicode"
+
+	"gist/backend/internal/config"
+	"gist/backend/internal/model"
+	"gist/backend/internal/repository"
+)
+
+// Where an effective feed option was set, for FeedConfigSource.Kind.
+const (
+	FeedConfigFromFeed   = "feed"
+	FeedConfigFromFolder = "folder"
+	// FeedConfigFromDefault means neither the feed nor a folder above it sets
+	// the option, so the global setting applies.
+	FeedConfigFromDefault = "default"
+)
+
+// maxUserAgentLength bounds custom User-Agent values of feeds and folders.
+const maxUserAgentLength = 512
+
+// FeedConfigSource says where an effective feed option comes from.
+type FeedConfigSource struct {
+	Kind string
+	// FolderID and FolderName name the folder when Kind is FeedConfigFromFolder.
+	FolderID   int64
+	FolderName string
+}
+
+// FeedConfig is a feed's options after inheritance from its folders. A nil
+// value comes from FeedConfigFromDefault and means the global setting.
+type FeedConfig struct {
+	UserAgent             *string
+	UserAgentSource       FeedConfigSource
+	AutoReadability       *bool
+	AutoReadabilitySource FeedConfigSource
+	AutoTranslate         *bool
+	AutoTranslateSource   FeedConfigSource
+}
+
+// effectiveFeedConfig resolves the feed's options from its own values, then
+// folderChain, its folder first and the top-level folder last. Nothing is
+// stored, so a change to a folder or a move of the feed applies at once.
+func effectiveFeedConfig(feed model.Feed, folderChain []model.Folder) FeedConfig {
+	config := FeedConfig{
+		UserAgentSource:       FeedConfigSource{Kind: FeedConfigFromDefault},
+		AutoReadabilitySource: FeedConfigSource{Kind: FeedConfigFromDefault},
+		AutoTranslateSource:   FeedConfigSource{Kind: FeedConfigFromDefault},
+	}
+	fromFeed := FeedConfigSource{Kind: FeedConfigFromFeed}
+
+	if feed.UserAgent != nil {
+		config.UserAgent, config.UserAgentSource = feed.UserAgent, fromFeed
+	}
+	if feed.AutoReadability != nil {
+		config.AutoReadability, config.AutoReadabilitySource = feed.AutoReadability, fromFeed
+	}
+	switch feed.AutoTranslate {
+	case model.FeedAutoTranslateOn, model.FeedAutoTranslateOff:
+		enabled := feed.AutoTranslate == model.FeedAutoTranslateOn
+		config.AutoTranslate, config.AutoTranslateSource = &enabled, fromFeed
+	}
+
+	for _, folder := range folderChain {
+		from := FeedConfigSource{Kind: FeedConfigFromFolder, FolderID: folder.ID, FolderName: folder.Name}
+		defaults := folder.FeedDefaults
+		if config.UserAgent == nil && defaults.UserAgent != nil {
+			config.UserAgent, config.UserAgentSource = defaults.UserAgent, from
+		}
+		if config.AutoReadability == nil && defaults.AutoReadability != nil {
+			config.AutoReadability, config.AutoReadabilitySource = defaults.AutoReadability, from
+		}
+		if config.AutoTranslate == nil && defaults.AutoTranslate != nil {
+			config.AutoTranslate, config.AutoTranslateSource = defaults.AutoTranslate, from
+		}
+	}
+	return config
+}
+
+// folderChain walks from folderID up to its top-level folder through byID.
+// Folders missing from byID, such as deleted ones, end the chain.
+func folderChain(byID map[int64]model.Folder, folderID *int64) []model.Folder {
+	var chain []model.Folder
+	seen := make(map[int64]bool)
+	for folderID != nil && !seen[*folderID] {
+		folder, ok := byID[*folderID]
+		if !ok {
+			break
+		}
+		seen[folder.ID] = true
+		chain = append(chain, folder)
+		folderID = folder.ParentID
+	}
+	return chain
+}
+
+// loadFolderChain is folderChain for one feed, reading the folders one by one.
+func loadFolderChain(ctx context.Context, folders repository.FolderRepository, folderID *int64) ([]model.Folder, error) {
+	var chain []model.Folder
+	seen := make(map[int64]bool)
+	for folderID != nil && !seen[*folderID] {
+		folder, err := folders.GetByID(ctx, *folderID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				break
+			}
+			return chain, err
+		}
+		seen[folder.ID] = true
+		chain = append(chain, folder)
+		folderID = folder.ParentID
+	}
+	return chain, nil
+}
+
+// effectiveFeedConfigs resolves the options of each feed, loading the folders once.
+func effectiveFeedConfigs(ctx context.Context, folders repository.FolderRepository, feeds []model.Feed) (map[int64]FeedConfig, error) {
+	byID := make(map[int64]model.Folder)
+	if folders != nil {
+		list, err := folders.List(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, folder := range list {
+			byID[folder.ID] = folder
+		}
+	}
+	configs := make(map[int64]FeedConfig, len(feeds))
+	for _, feed := range feeds {
+		configs[feed.ID] = effectiveFeedConfig(feed, folderChain(byID, feed.FolderID))
+	}
+	return configs, nil
+}
+
+// normalizeUserAgent trims a custom User-Agent and collapses its runs of
+// spaces, returning nil for a blank one and ErrInvalid for one too long or
+// holding control characters: a CR/LF would smuggle in extra headers.
+func normalizeUserAgent(userAgent *string) (*string, error) {
+	if userAgent == nil {
+		return nil, nil
+	}
+	trimmed := strings.TrimSpace(*userAgent)
+	if trimmed == "" {
+		return nil, nil
+	}
+	if len(trimmed) > maxUserAgentLength || strings.IndexFunc(trimmed, unicode.IsControl) >= 0 {
+		return nil, ErrInvalid
+	}
+	normalized := strings.Join(strings.Fields(trimmed), " ")
+	return &normalized, nil
+}
+
+// sanitizeHeaderValue replaces the control characters of a stored header
+// value with spaces and collapses whitespace. Values saved before they were
+// validated can still hold CR/LF, so requests pass them through this again.
+func sanitizeHeaderValue(value string) string {
+	return strings.Join(strings.Fields(strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return ' '
+		}
+		return r
+	}, value)), " ")
+}
+
+// userAgentHeader returns the User-Agent header to send for userAgent, the
+// built-in one when nothing is left of it.
+func userAgentHeader(userAgent string) string {
+	if sanitized := sanitizeHeaderValue(userAgent); sanitized != "" {
+		return sanitized
+	}
+	return config.DefaultUserAgent
+}
@@ -0,0 +1,136 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"gist/backend/internal/model"
+	"gist/backend/internal/repository"
+	"gist/backend/internal/repository/testutil"
+	"gist/backend/internal/service"
+)
+
+func boolPtr(value bool) *bool {
+	return &value
+}
+
+func TestEffectiveFeedConfig_MultiLevelInheritance(t *testing.T) {
+	root := model.Folder{ID: 1, Name: "Blogs", FeedDefaults: model.FeedDefaults{
+		UserAgent:       stringPtr("Root/1.0"),
+		AutoReadability: boolPtr(true),
+		AutoTranslate:   boolPtr(false),
+	}}
+	middle := model.Folder{ID: 2, Name: "Asia", ParentID: &root.ID, FeedDefaults: model.FeedDefaults{
+		AutoTranslate: boolPtr(true),
+	}}
+	leaf := model.Folder{ID: 3, Name: "Japanese blogs", ParentID: &middle.ID, FeedDefaults: model.FeedDefaults{
+		UserAgent: stringPtr("Leaf/1.0"),
+	}}
+	feed := model.Feed{ID: 10, FolderID: &leaf.ID, AutoTranslate: model.FeedAutoTranslateInherit}
+
+	config := service.EffectiveFeedConfig(feed, []model.Folder{leaf, middle, root})
+
+	// Each option comes from the nearest folder that sets it
+	require.Equal(t, "Leaf/1.0", *config.UserAgent)
+	require.Equal(t, service.FeedConfigSource{Kind: service.FeedConfigFromFolder, FolderID: 3, FolderName: "Japanese blogs"}, config.UserAgentSource)
+	require.True(t, *config.AutoTranslate)
+	require.Equal(t, service.FeedConfigSource{Kind: service.FeedConfigFromFolder, FolderID: 2, FolderName: "Asia"}, config.AutoTranslateSource)
+	require.True(t, *config.AutoReadability)
+	require.Equal(t, service.FeedConfigSource{Kind: service.FeedConfigFromFolder, FolderID: 1, FolderName: "Blogs"}, config.AutoReadabilitySource)
+}
+
+func TestEffectiveFeedConfig_FeedOverridesFolders(t *testing.T) {
+	folder := model.Folder{ID: 1, Name: "Japanese blogs", FeedDefaults: model.FeedDefaults{
+		UserAgent:       stringPtr("Folder/1.0"),
+		AutoReadability: boolPtr(true),
+		AutoTranslate:   boolPtr(true),
+	}}
+	feed := model.Feed{
+		ID:              10,
+		FolderID:        &folder.ID,
+		UserAgent:       stringPtr("Feed/1.0"),
+		AutoReadability: boolPtr(false),
+		AutoTranslate:   model.FeedAutoTranslateOff,
+	}
+
+	config := service.EffectiveFeedConfig(feed, []model.Folder{folder})
+
+	fromFeed := service.FeedConfigSource{Kind: service.FeedConfigFromFeed}
+	require.Equal(t, "Feed/1.0", *config.UserAgent)
+	require.Equal(t, fromFeed, config.UserAgentSource)
+	require.False(t, *config.AutoReadability)
+	require.Equal(t, fromFeed, config.AutoReadabilitySource)
+	require.False(t, *config.AutoTranslate)
+	require.Equal(t, fromFeed, config.AutoTranslateSource)
+}
+
+func TestEffectiveFeedConfig_DefaultsWithoutFolders(t *testing.T) {
+	config := service.EffectiveFeedConfig(model.Feed{ID: 10, AutoTranslate: model.FeedAutoTranslateInherit}, nil)
+
+	fromDefault := service.FeedConfigSource{Kind: service.FeedConfigFromDefault}
+	require.Nil(t, config.UserAgent)
+	require.Equal(t, fromDefault, config.UserAgentSource)
+	require.Nil(t, config.AutoReadability)
+	require.Equal(t, fromDefault, config.AutoReadabilitySource)
+	require.Nil(t, config.AutoTranslate)
+	require.Equal(t, fromDefault, config.AutoTranslateSource)
+}
+
+func TestFeedService_EffectiveConfigs_ResolvedAtReadTime(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	ctx := context.Background()
+	feeds := repository.NewFeedRepository(db, nil, nil)
+	folders := repository.NewFolderRepository(db, nil, nil)
+	svc := service.NewFeedService(feeds, folders, nil, nil, nil, nil, nil, nil, nil, nil)
+	folderSvc := service.NewFolderService(folders, feeds, repository.NewFolderRuleRepository(db), nil)
+
+	parentID := testutil.SeedFolder(t, db, "Blogs", nil, "article")
+	childID := testutil.SeedFolder(t, db, "Japanese blogs", &parentID, "article")
+	otherID := testutil.SeedFolder(t, db, "News", nil, "article")
+	feedID := testutil.SeedFeed(t, db, model.Feed{Title: "Feed", URL: "https://example.com/feed", FolderID: &childID})
+
+	_, err := folderSvc.UpdateFeedDefaults(ctx, parentID, model.FeedDefaults{UserAgent: stringPtr("  Blogs/1.0  "), AutoTranslate: boolPtr(true)})
+	require.NoError(t, err)
+	_, err = folderSvc.UpdateFeedDefaults(ctx, otherID, model.FeedDefaults{AutoTranslate: boolPtr(false)})
+	require.NoError(t, err)
+
+	resolve := func() service.FeedConfig {
+		t.Helper()
+		feed, err := feeds.GetByID(ctx, feedID)
+		require.NoError(t, err)
+		configs, err := svc.EffectiveConfigs(ctx, []model.Feed{feed})
+		require.NoError(t, err)
+		return configs[feedID]
+	}
+
+	config := resolve()
+	require.Equal(t, "Blogs/1.0", *config.UserAgent)
+	require.Equal(t, parentID, config.UserAgentSource.FolderID)
+	require.True(t, *config.AutoTranslate)
+
+	// The folder's defaults are not copied into the feed
+	feed, err := feeds.GetByID(ctx, feedID)
+	require.NoError(t, err)
+	require.Nil(t, feed.UserAgent)
+	require.Equal(t, model.FeedAutoTranslateInherit, feed.AutoTranslate)
+
+	// Moving the feed changes what it inherits at once
+	feed.FolderID = &otherID
+	_, err = feeds.Update(ctx, feed)
+	require.NoError(t, err)
+	config = resolve()
+	require.Nil(t, config.UserAgent)
+	require.Equal(t, service.FeedConfigFromDefault, config.UserAgentSource.Kind)
+	require.False(t, *config.AutoTranslate)
+	require.Equal(t, "News", config.AutoTranslateSource.FolderName)
+
+	// A blank user agent inherits; one with a line break is rejected
+	_, err = folderSvc.UpdateFeedDefaults(ctx, otherID, model.FeedDefaults{UserAgent: stringPtr("bad\r\nagent")})
+	require.ErrorIs(t, err, service.ErrInvalid)
+	require.NoError(t, svc.UpdateUserAgent(ctx, feedID, stringPtr(" ")))
+	feed, err = feeds.GetByID(ctx, feedID)
+	require.NoError(t, err)
+	require.Nil(t, feed.UserAgent)
+}
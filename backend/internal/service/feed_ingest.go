@@ -0,0 +1,215 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+
+	"gist/backend/internal/hashutil"
+	"gist/backend/internal/model"
+	"gist/backend/internal/urlutil"
+	"gist/backend/pkg/logger"
+)
+
+// IngestTokenPrefix marks tokens that push entries into a single static feed.
+const IngestTokenPrefix = "gist_ingest_"
+
+const (
+	// MaxIngestItems caps the items accepted in one push.
+	MaxIngestItems = 200
+	// MaxIngestBodySize caps the request body of one push.
+	MaxIngestBodySize = 2 << 20
+)
+
+// Outcomes of one pushed item.
+const (
+	IngestCreated = "created"
+	IngestUpdated = "updated"
+	IngestSkipped = "skipped"
+)
+
+// IngestItem is one entry pushed into a static feed.
+type IngestItem struct {
+	Title       string
+	URL         string
+	Content     string
+	Author      string
+	PublishedAt *time.Time
+	// ExternalID identifies the item across pushes; without it the URL does.
+	ExternalID string
+}
+
+// IngestResult reports what happened to the item at Index of a push.
+type IngestResult struct {
+	Index  int
+	Status string
+	// Reason explains why a skipped item was not saved.
+	Reason string
+}
+
+// feedItem maps the pushed item to what a fetched feed would have produced,
+// so it goes through the same normalization and hashing.
+func (item IngestItem) feedItem() *gofeed.Item {
+	feedItem := &gofeed.Item{
+		GUID:            strings.TrimSpace(item.ExternalID),
+		Title:           item.Title,
+		Link:            strings.TrimSpace(item.URL),
+		Content:         item.Content,
+		PublishedParsed: item.PublishedAt,
+	}
+	if author := strings.TrimSpace(item.Author); author != "" {
+		feedItem.Author = &gofeed.Person{Name: author}
+	}
+	return feedItem
+}
+
+func (s *feedService) CreateIngestToken(ctx context.Context, feedID int64) (string, error) {
+	feed, err := s.feeds.GetByID(ctx, feedID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", ErrNotFound
+		}
+		return "", fmt.Errorf("get feed: %w", err)
+	}
+	if !IsStaticFeed(feed) {
+		return "", fmt.Errorf("feed %d is fetched from its URL: %w", feedID, ErrInvalid)
+	}
+
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("generate ingest token: %w", err)
+	}
+	plaintext := IngestTokenPrefix + hex.EncodeToString(raw)
+	if err := s.feeds.UpdateIngestTokenHash(ctx, feedID, hashutil.SHA256Hex(plaintext)); err != nil {
+		logger.Error("ingest token create failed", "module", "service", "action", "create", "resource", "feed", "result", "failed", "feed_id", feedID, "error", err)
+		return "", fmt.Errorf("store ingest token: %w", err)
+	}
+
+	logger.Info("ingest token created", "module", "service", "action", "create", "resource", "feed", "result", "ok", "feed_id", feedID)
+	return plaintext, nil
+}
+
+func (s *feedService) ValidateIngestToken(ctx context.Context, feedID int64, token string) error {
+	if !strings.HasPrefix(token, IngestTokenPrefix) {
+		return ErrInvalidToken
+	}
+	stored, err := s.feeds.GetIngestTokenHash(ctx, feedID)
+	if err != nil {
+		// An unknown feed looks like a wrong token, so feed IDs can't be probed
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrInvalidToken
+		}
+		return fmt.Errorf("get ingest token: %w", err)
+	}
+	if stored == "" || subtle.ConstantTimeCompare([]byte(stored), []byte(hashutil.SHA256Hex(token))) != 1 {
+		return ErrInvalidToken
+	}
+	return nil
+}
+
+func (s *refreshService) IngestItems(ctx context.Context, feedID int64, items []IngestItem) ([]IngestResult, error) {
+	if len(items) > MaxIngestItems {
+		return nil, ErrIngestTooLarge
+	}
+	feed, err := s.feeds.GetByID(ctx, feedID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("get feed: %w", err)
+	}
+	if !IsStaticFeed(feed) {
+		return nil, fmt.Errorf("feed %d is not static: %w", feedID, ErrInvalid)
+	}
+
+	general := loadGeneralSettings(ctx, s.settings)
+	loc := feedLocation(feed, general)
+
+	results := make([]IngestResult, len(items))
+	entries := make([]model.Entry, 0, len(items))
+	hashes := make([]string, 0, len(items))
+	positions := make([]int, 0, len(items))
+	seen := make(map[string]bool, 2*len(items))
+	for i, item := range items {
+		results[i] = IngestResult{Index: i, Status: IngestSkipped}
+		feedItem := item.feedItem()
+		entry := itemToEntry(feed, feedItem, false, loc)
+		if entry.URL == nil {
+			// Items of sources without pages, such as chat exports, are
+			// known by their external ID whatever the feed's dedupe key
+			if feedItem.GUID == "" {
+				results[i].Reason = "url is required"
+				continue
+			}
+			entry.Hash = hashToHex(feedItem.GUID)
+		}
+		attachRawItem(&entry, feedItem, general)
+		if seen[entry.Hash] {
+			results[i].Reason = "duplicate of an earlier item"
+			continue
+		}
+		seen[entry.Hash] = true
+		if entry.URL != nil {
+			// Saving merges entries that link to the same page, so a second
+			// one would overwrite the first
+			page := urlutil.StripFragment(*entry.URL)
+			if seen[page] {
+				results[i].Reason = "duplicate of an earlier item"
+				continue
+			}
+			seen[page] = true
+		}
+		entries = append(entries, entry)
+		hashes = append(hashes, entry.Hash)
+		positions = append(positions, i)
+	}
+
+	stored, err := s.entries.ListByHashes(ctx, feed.ID, hashes)
+	if err != nil {
+		return nil, fmt.Errorf("list stored entries: %w", err)
+	}
+	existing := make(map[string]bool, len(stored))
+	for _, entry := range stored {
+		existing[entry.Hash] = true
+	}
+	var urls []string
+	for _, entry := range entries {
+		if !existing[entry.Hash] && entry.URL != nil {
+			urls = append(urls, *entry.URL)
+		}
+	}
+	legacy, err := s.entries.ExistingLegacyURLs(ctx, feed.ID, urls)
+	if err != nil {
+		return nil, fmt.Errorf("match stored entries: %w", err)
+	}
+	for _, entry := range entries {
+		if entry.URL != nil && legacy[*entry.URL] {
+			existing[entry.Hash] = true
+		}
+	}
+
+	newCount, updatedCount, err := s.storeEntries(ctx, feed, entries, entryRevisionLimit(general))
+	if err != nil {
+		return nil, fmt.Errorf("save entries: %w", err)
+	}
+	for j, entry := range entries {
+		if existing[entry.Hash] {
+			results[positions[j]].Status = IngestUpdated
+		} else {
+			results[positions[j]].Status = IngestCreated
+		}
+	}
+
+	evictedCount := s.evictOverCap(ctx, feed)
+
+	logger.Info("feed entries pushed", "module", "service", "action", "save", "resource", "feed", "result", "ok", "feed_id", feed.ID, "items", len(items), "new", newCount, "updated", updatedCount, "evicted", evictedCount)
+	return results, nil
+}
@@ -0,0 +1,180 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"gist/backend/internal/model"
+	"gist/backend/internal/repository"
+	"gist/backend/internal/repository/testutil"
+	"gist/backend/internal/service"
+)
+
+func TestFeedService_IngestToken(t *testing.T) {
+	database := testutil.NewTestDB(t)
+	feeds := repository.NewFeedRepository(database, nil, nil)
+	svc := service.NewFeedService(feeds, repository.NewFolderRepository(database, nil, nil), repository.NewEntryRepository(database, nil, nil), nil, nil, nil, nil, nil, nil, nil)
+	ctx := context.Background()
+
+	staticID := testutil.SeedFeed(t, database, model.Feed{Title: "Pushed", URL: service.StaticFeedURLPrefix + "pushed"})
+	fetchedID := testutil.SeedFeed(t, database, model.Feed{Title: "Fetched", URL: "https://example.com/rss"})
+
+	// A feed without a token accepts none
+	require.ErrorIs(t, svc.ValidateIngestToken(ctx, staticID, service.IngestTokenPrefix+"guess"), service.ErrInvalidToken)
+
+	token, err := svc.CreateIngestToken(ctx, staticID)
+	require.NoError(t, err)
+	require.True(t, len(token) > len(service.IngestTokenPrefix))
+	require.NoError(t, svc.ValidateIngestToken(ctx, staticID, token))
+	require.ErrorIs(t, svc.ValidateIngestToken(ctx, staticID, token+"x"), service.ErrInvalidToken)
+	require.ErrorIs(t, svc.ValidateIngestToken(ctx, staticID, ""), service.ErrInvalidToken)
+	require.ErrorIs(t, svc.ValidateIngestToken(ctx, 999, token), service.ErrInvalidToken)
+
+	// A new token replaces the old one
+	rotated, err := svc.CreateIngestToken(ctx, staticID)
+	require.NoError(t, err)
+	require.ErrorIs(t, svc.ValidateIngestToken(ctx, staticID, token), service.ErrInvalidToken)
+	require.NoError(t, svc.ValidateIngestToken(ctx, staticID, rotated))
+
+	_, err = svc.CreateIngestToken(ctx, fetchedID)
+	require.ErrorIs(t, err, service.ErrInvalid)
+	_, err = svc.CreateIngestToken(ctx, 999)
+	require.ErrorIs(t, err, service.ErrNotFound)
+}
+
+func TestRefreshService_IngestItems(t *testing.T) {
+	database := testutil.NewTestDB(t)
+	feeds := repository.NewFeedRepository(database, nil, nil)
+	entries := repository.NewEntryRepository(database, nil, nil)
+	svc := service.NewRefreshService(feeds, entries, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	ctx := context.Background()
+
+	feedID := testutil.SeedFeed(t, database, model.Feed{Title: "Changelog", URL: service.StaticFeedURLPrefix + "changelog", DedupeKey: model.DedupeKeyAuto})
+	published := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+
+	results, err := svc.IngestItems(ctx, feedID, []service.IngestItem{
+		{Title: "v1.0", URL: "https://example.com/releases/1.0", Content: "<p>First</p>", Author: "Ops", PublishedAt: &published, ExternalID: "1.0"},
+		{Title: "v1.1", URL: "https://example.com/releases/1.1", ExternalID: "1.1"},
+		{Title: "No link"},
+		{Title: "v1.0 again", URL: "https://example.com/releases/1.0", ExternalID: "1.0"},
+		{Title: "Notes", URL: "https://example.com/releases/1.1#notes", ExternalID: "1.1-notes"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []service.IngestResult{
+		{Index: 0, Status: service.IngestCreated},
+		{Index: 1, Status: service.IngestCreated},
+		{Index: 2, Status: service.IngestSkipped, Reason: "url is required"},
+		{Index: 3, Status: service.IngestSkipped, Reason: "duplicate of an earlier item"},
+		{Index: 4, Status: service.IngestSkipped, Reason: "duplicate of an earlier item"},
+	}, results)
+
+	stored, err := entries.List(ctx, repository.EntryListFilter{FeedID: &feedID, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, stored, 2)
+
+	// The external id identifies an item whose url moved
+	results, err = svc.IngestItems(ctx, feedID, []service.IngestItem{
+		{Title: "v1.0", URL: "https://example.com/releases/v1.0", Content: "<p>First, amended</p>", ExternalID: "1.0"},
+		{Title: "v1.2", URL: "https://example.com/releases/1.2", ExternalID: "1.2"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []service.IngestResult{{Index: 0, Status: service.IngestUpdated}, {Index: 1, Status: service.IngestCreated}}, results)
+
+	stored, err = entries.List(ctx, repository.EntryListFilter{FeedID: &feedID, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, stored, 3)
+
+	_, err = svc.IngestItems(ctx, feedID, make([]service.IngestItem, service.MaxIngestItems+1))
+	require.ErrorIs(t, err, service.ErrIngestTooLarge)
+
+	fetchedID := testutil.SeedFeed(t, database, model.Feed{Title: "Fetched", URL: "https://example.com/rss"})
+	_, err = svc.IngestItems(ctx, fetchedID, []service.IngestItem{{URL: "https://example.com/1"}})
+	require.ErrorIs(t, err, service.ErrInvalid)
+	_, err = svc.IngestItems(ctx, 999, nil)
+	require.ErrorIs(t, err, service.ErrNotFound)
+}
+
+func TestRefreshService_IngestItems_WithoutURL(t *testing.T) {
+	database := testutil.NewTestDB(t)
+	feeds := repository.NewFeedRepository(database, nil, nil)
+	entries := repository.NewEntryRepository(database, nil, nil)
+	svc := service.NewRefreshService(feeds, entries, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	ctx := context.Background()
+
+	// The external id identifies url-less items even when the feed dedupes by url
+	feedID := testutil.SeedFeed(t, database, model.Feed{Title: "#general", URL: service.StaticFeedURLPrefix + "slack-general", DedupeKey: model.DedupeKeyURL})
+
+	results, err := svc.IngestItems(ctx, feedID, []service.IngestItem{
+		{Title: "Deploy done", Content: "Shipped v2", Author: "ops", ExternalID: "C024BE91L-1712345678.000100"},
+		{Title: "Lunch?", Content: "Anyone", ExternalID: "C024BE91L-1712345690.000200"},
+		{Title: "Deploy done", Content: "Shipped v2", ExternalID: "C024BE91L-1712345678.000100"},
+		{Title: "No id either", URL: " "},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []service.IngestResult{
+		{Index: 0, Status: service.IngestCreated},
+		{Index: 1, Status: service.IngestCreated},
+		{Index: 2, Status: service.IngestSkipped, Reason: "duplicate of an earlier item"},
+		{Index: 3, Status: service.IngestSkipped, Reason: "url is required"},
+	}, results)
+
+	// Pushing an edited message again updates it in place
+	results, err = svc.IngestItems(ctx, feedID, []service.IngestItem{
+		{Title: "Deploy done", Content: "Shipped v2.0.1", Author: "ops", ExternalID: "C024BE91L-1712345678.000100"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []service.IngestResult{{Index: 0, Status: service.IngestUpdated}}, results)
+
+	stored, err := entries.List(ctx, repository.EntryListFilter{FeedID: &feedID, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, stored, 2)
+	for _, entry := range stored {
+		require.Nil(t, entry.URL)
+		if *entry.Title == "Deploy done" {
+			require.Equal(t, "Shipped v2.0.1", *entry.Content)
+		}
+	}
+}
+
+func TestRefreshService_IngestItems_MutedAuthor(t *testing.T) {
+	database := testutil.NewTestDB(t)
+	feeds := repository.NewFeedRepository(database, nil, nil)
+	entries := repository.NewEntryRepository(database, nil, nil)
+	feedSvc := service.NewFeedService(feeds, repository.NewFolderRepository(database, nil, nil), entries, nil, nil, nil, nil, nil, nil, nil)
+	refreshSvc := service.NewRefreshService(feeds, entries, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	entrySvc := service.NewEntryService(entries, feeds, repository.NewFolderRepository(database, nil, nil), nil, nil, nil, nil)
+	ctx := context.Background()
+
+	feedID := testutil.SeedFeed(t, database, model.Feed{Title: "Group blog", URL: service.StaticFeedURLPrefix + "group"})
+	authors, err := feedSvc.MuteAuthor(ctx, feedID, "  Jane Doe ")
+	require.NoError(t, err)
+	require.Equal(t, []string{"Jane Doe"}, authors)
+	_, err = feedSvc.MuteAuthor(ctx, feedID, " ")
+	require.ErrorIs(t, err, service.ErrInvalid)
+	_, err = feedSvc.UnmuteAuthor(ctx, feedID, "Adam")
+	require.ErrorIs(t, err, service.ErrNotFound)
+
+	_, err = refreshSvc.IngestItems(ctx, feedID, []service.IngestItem{
+		{Title: "Muted", URL: "https://example.com/muted", Author: "jane doe"},
+		{Title: "Kept", URL: "https://example.com/kept", Author: "Adam"},
+	})
+	require.NoError(t, err)
+
+	stored, err := entries.List(ctx, repository.EntryListFilter{FeedID: &feedID, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, stored, 2)
+	for _, entry := range stored {
+		require.Equal(t, *entry.Author == "jane doe", entry.Read, *entry.Title)
+
+		detail, err := entrySvc.GetByID(ctx, entry.ID)
+		require.NoError(t, err)
+		require.Equal(t, entry.Read, detail.AuthorMuted)
+	}
+
+	authors, err = feedSvc.UnmuteAuthor(ctx, feedID, "JANE DOE")
+	require.NoError(t, err)
+	require.Empty(t, authors)
+}
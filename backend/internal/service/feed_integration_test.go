@@ -56,11 +56,11 @@ func TestFeedService_Integration_Preview(t *testing.T) {
 	}
 
 	clientFactory := network.NewClientFactoryForTest(nil)
-	feedRepo := repository.NewFeedRepository(setupTestDB(t))
-	folderRepo := repository.NewFolderRepository(setupTestDB(t))
-	entryRepo := repository.NewEntryRepository(setupTestDB(t))
+	feedRepo := repository.NewFeedRepository(setupTestDB(t), nil, nil)
+	folderRepo := repository.NewFolderRepository(setupTestDB(t), nil, nil)
+	entryRepo := repository.NewEntryRepository(setupTestDB(t), nil, nil)
 
-	svc := service.NewFeedService(feedRepo, folderRepo, entryRepo, nil, nil, clientFactory, nil)
+	svc := service.NewFeedService(feedRepo, folderRepo, entryRepo, nil, nil, clientFactory, nil, nil, nil, nil)
 
 	for _, feed := range testFeeds {
 		t.Run(feed.name, func(t *testing.T) {
@@ -90,18 +90,18 @@ func TestFeedService_Integration_AddAndList(t *testing.T) {
 
 	dbConn := setupTestDB(t)
 	clientFactory := network.NewClientFactoryForTest(nil)
-	feedRepo := repository.NewFeedRepository(dbConn)
-	folderRepo := repository.NewFolderRepository(dbConn)
-	entryRepo := repository.NewEntryRepository(dbConn)
+	feedRepo := repository.NewFeedRepository(dbConn, nil, nil)
+	folderRepo := repository.NewFolderRepository(dbConn, nil, nil)
+	entryRepo := repository.NewEntryRepository(dbConn, nil, nil)
 
-	svc := service.NewFeedService(feedRepo, folderRepo, entryRepo, nil, nil, clientFactory, nil)
+	svc := service.NewFeedService(feedRepo, folderRepo, entryRepo, nil, nil, clientFactory, nil, nil, nil, nil)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
 	defer cancel()
 
 	// Add one feed
 	testFeed := testFeeds[0]
-	created, err := svc.Add(ctx, testFeed.url, nil, "", "article")
+	created, err := svc.Add(ctx, testFeed.url, nil, "", "article", service.InitialBackfill{})
 	require.NoError(t, err, "failed to add feed")
 	require.NotZero(t, created.ID)
 	require.NotEmpty(t, created.Title)
@@ -143,11 +143,11 @@ func TestFeedService_Integration_FetchMultipleFeeds(t *testing.T) {
 
 	dbConn := setupTestDB(t)
 	clientFactory := network.NewClientFactoryForTest(nil)
-	feedRepo := repository.NewFeedRepository(dbConn)
-	folderRepo := repository.NewFolderRepository(dbConn)
-	entryRepo := repository.NewEntryRepository(dbConn)
+	feedRepo := repository.NewFeedRepository(dbConn, nil, nil)
+	folderRepo := repository.NewFolderRepository(dbConn, nil, nil)
+	entryRepo := repository.NewEntryRepository(dbConn, nil, nil)
 
-	svc := service.NewFeedService(feedRepo, folderRepo, entryRepo, nil, nil, clientFactory, nil)
+	svc := service.NewFeedService(feedRepo, folderRepo, entryRepo, nil, nil, clientFactory, nil, nil, nil, nil)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 120*time.Second)
 	defer cancel()
@@ -155,7 +155,7 @@ func TestFeedService_Integration_FetchMultipleFeeds(t *testing.T) {
 	// Add multiple feeds
 	for _, feed := range testFeeds[:3] { // Only test first 3 for speed
 		t.Run(feed.name, func(t *testing.T) {
-			created, err := svc.Add(ctx, feed.url, nil, "", "article")
+			created, err := svc.Add(ctx, feed.url, nil, "", "article", service.InitialBackfill{})
 			require.NoError(t, err, "failed to add feed: %s", feed.url)
 			require.NotZero(t, created.ID)
 			t.Logf("Added: %s (ID: %d)", created.Title, created.ID)
@@ -0,0 +1,197 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+
+	"gist/backend/internal/events"
+	"gist/backend/internal/model"
+	"gist/backend/internal/urlutil"
+	"gist/backend/pkg/logger"
+)
+
+const (
+	// overlapSampleSize is how many recent entries of each feed are compared.
+	overlapSampleSize = 50
+	// Feeds of different sites overlap when at least overlapMinShared of the
+	// sampled entries, and overlapMinRatio of either sample, are shared.
+	overlapMinShared = 3
+	overlapMinRatio  = 0.5
+	// maxFeedOverlaps caps the pairs stored per detection run.
+	maxFeedOverlaps = 200
+)
+
+// FeedOverlap is a detected overlap with both of its live feeds.
+type FeedOverlap struct {
+	model.FeedOverlap
+	Feed      model.Feed
+	OtherFeed model.Feed
+}
+
+func (s *feedService) DetectOverlaps(ctx context.Context) (int, error) {
+	if s.overlaps == nil {
+		return 0, nil
+	}
+	feeds, err := s.feeds.List(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("list feeds: %w", err)
+	}
+
+	live := make(map[int64]bool, len(feeds))
+	bySite := make(map[string][]int64)
+	for _, feed := range feeds {
+		live[feed.ID] = true
+		if feed.SiteURL == nil {
+			continue
+		}
+		if site := urlutil.CanonicalSiteURL(*feed.SiteURL); site != "" {
+			bySite[site] = append(bySite[site], feed.ID)
+		}
+	}
+
+	pairs := make(map[[2]int64]*model.FeedOverlap)
+	pair := func(a, b int64) *model.FeedOverlap {
+		if a > b {
+			a, b = b, a
+		}
+		key := [2]int64{a, b}
+		if pairs[key] == nil {
+			pairs[key] = &model.FeedOverlap{FeedID: a, OtherFeedID: b}
+		}
+		return pairs[key]
+	}
+	for _, ids := range bySite {
+		for i := range ids {
+			for j := i + 1; j < len(ids); j++ {
+				pair(ids[i], ids[j]).SameSite = true
+			}
+		}
+	}
+	for _, feed := range feeds {
+		samples, err := s.overlaps.SampleSharedEntries(ctx, feed.ID, overlapSampleSize)
+		if err != nil {
+			return 0, fmt.Errorf("sample feed %d: %w", feed.ID, err)
+		}
+		for _, sample := range samples {
+			if !live[sample.OtherFeedID] || sample.Sampled == 0 {
+				continue
+			}
+			overlap := pair(sample.FeedID, sample.OtherFeedID)
+			overlap.SharedEntries = max(overlap.SharedEntries, sample.Shared)
+			overlap.OverlapRatio = max(overlap.OverlapRatio, float64(sample.Shared)/float64(sample.Sampled))
+		}
+	}
+
+	detected := make([]model.FeedOverlap, 0, len(pairs))
+	for _, overlap := range pairs {
+		if overlap.SameSite || (overlap.SharedEntries >= overlapMinShared && overlap.OverlapRatio >= overlapMinRatio) {
+			detected = append(detected, *overlap)
+		}
+	}
+	sort.Slice(detected, func(i, j int) bool {
+		a, b := detected[i], detected[j]
+		if a.OverlapRatio != b.OverlapRatio {
+			return a.OverlapRatio > b.OverlapRatio
+		}
+		if a.SharedEntries != b.SharedEntries {
+			return a.SharedEntries > b.SharedEntries
+		}
+		if a.FeedID != b.FeedID {
+			return a.FeedID < b.FeedID
+		}
+		return a.OtherFeedID < b.OtherFeedID
+	})
+	if len(detected) > maxFeedOverlaps {
+		detected = detected[:maxFeedOverlaps]
+	}
+
+	if err := s.overlaps.Replace(ctx, detected); err != nil {
+		return 0, fmt.Errorf("save feed overlaps: %w", err)
+	}
+	return len(detected), nil
+}
+
+func (s *feedService) ListOverlaps(ctx context.Context) ([]FeedOverlap, error) {
+	if s.overlaps == nil {
+		return []FeedOverlap{}, nil
+	}
+	overlaps, err := s.overlaps.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list feed overlaps: %w", err)
+	}
+	ids := make([]int64, 0, 2*len(overlaps))
+	for _, overlap := range overlaps {
+		ids = append(ids, overlap.FeedID, overlap.OtherFeedID)
+	}
+	feeds, err := s.feeds.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("get overlapping feeds: %w", err)
+	}
+	byID := make(map[int64]model.Feed, len(feeds))
+	for _, feed := range feeds {
+		byID[feed.ID] = feed
+	}
+
+	result := make([]FeedOverlap, 0, len(overlaps))
+	for _, overlap := range overlaps {
+		feed, ok := byID[overlap.FeedID]
+		other, otherOK := byID[overlap.OtherFeedID]
+		if !ok || !otherOK {
+			continue
+		}
+		result = append(result, FeedOverlap{FeedOverlap: overlap, Feed: feed, OtherFeed: other})
+	}
+	return result, nil
+}
+
+func (s *feedService) MergeOverlap(ctx context.Context, id int64, keepFeedID int64) (model.Feed, error) {
+	if s.overlaps == nil {
+		return model.Feed{}, ErrNotFound
+	}
+	overlap, err := s.overlaps.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Feed{}, ErrNotFound
+		}
+		return model.Feed{}, fmt.Errorf("get feed overlap: %w", err)
+	}
+	if keepFeedID == 0 {
+		keepFeedID = overlap.FeedID
+	}
+	var dropFeedID int64
+	switch keepFeedID {
+	case overlap.FeedID:
+		dropFeedID = overlap.OtherFeedID
+	case overlap.OtherFeedID:
+		dropFeedID = overlap.FeedID
+	default:
+		return model.Feed{}, fmt.Errorf("feed %d is not part of overlap %d: %w", keepFeedID, id, ErrInvalid)
+	}
+
+	kept, err := s.feeds.GetByID(ctx, keepFeedID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Feed{}, ErrNotFound
+		}
+		return model.Feed{}, fmt.Errorf("get feed: %w", err)
+	}
+	if _, err := s.feeds.GetByID(ctx, dropFeedID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Feed{}, ErrNotFound
+		}
+		return model.Feed{}, fmt.Errorf("get feed: %w", err)
+	}
+
+	merged, err := s.overlaps.Merge(ctx, dropFeedID, keepFeedID)
+	if err != nil {
+		logger.Error("feed merge failed", "module", "service", "action", "delete", "resource", "feed", "result", "failed", "feed_id", dropFeedID, "kept_feed_id", keepFeedID, "error", err)
+		return model.Feed{}, err
+	}
+	logger.Info("feed merged", "module", "service", "action", "delete", "resource", "feed", "result", "ok", "feed_id", dropFeedID, "kept_feed_id", keepFeedID, "merged_entries", merged)
+	s.bus.Publish(events.Event{Type: events.FeedDeleted, Data: events.FeedData{FeedID: dropFeedID}})
+	s.bus.Publish(events.Event{Type: events.FeedUpdated, Data: events.FeedData{FeedID: keepFeedID}})
+	return kept, nil
+}
@@ -0,0 +1,114 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"gist/backend/internal/model"
+	"gist/backend/internal/repository/mock"
+	"gist/backend/internal/service"
+)
+
+func TestFeedService_DetectOverlaps(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFeeds := mock.NewMockFeedRepository(ctrl)
+	mockOverlaps := mock.NewMockFeedOverlapRepository(ctrl)
+	svc := service.NewFeedService(mockFeeds, nil, nil, nil, nil, nil, nil, nil, mockOverlaps, nil)
+	ctx := context.Background()
+
+	mockFeeds.EXPECT().List(gomock.Any(), (*int64)(nil)).Return([]model.Feed{
+		{ID: 1, URL: "https://example.com/feed", SiteURL: stringPtr("https://www.Example.com/")},
+		{ID: 2, URL: "https://example.com/rss.xml", SiteURL: stringPtr("http://example.com")},
+		{ID: 3, URL: "https://a.com/feed", SiteURL: stringPtr("https://a.com")},
+		{ID: 4, URL: "https://planet.org/feed", SiteURL: stringPtr("https://planet.org")},
+		{ID: 5, URL: "https://b.com/feed"},
+	}, nil)
+	samples := map[int64][]model.FeedOverlapSample{
+		// Ratios are compared both ways: 6 of 10 counts even if 6 of 50 does not
+		3: {{FeedID: 3, OtherFeedID: 4, Sampled: 10, Shared: 6}},
+		4: {{FeedID: 4, OtherFeedID: 3, Sampled: 50, Shared: 6}, {FeedID: 4, OtherFeedID: 5, Sampled: 50, Shared: 10}},
+		// Too few shared entries, then too small a share of either sample
+		5: {{FeedID: 5, OtherFeedID: 1, Sampled: 2, Shared: 2}, {FeedID: 5, OtherFeedID: 4, Sampled: 40, Shared: 10}},
+	}
+	mockOverlaps.EXPECT().SampleSharedEntries(gomock.Any(), gomock.Any(), 50).DoAndReturn(
+		func(_ context.Context, feedID int64, _ int) ([]model.FeedOverlapSample, error) {
+			return samples[feedID], nil
+		},
+	).Times(5)
+	mockOverlaps.EXPECT().Replace(gomock.Any(), []model.FeedOverlap{
+		{FeedID: 3, OtherFeedID: 4, SharedEntries: 6, OverlapRatio: 0.6},
+		{FeedID: 1, OtherFeedID: 2, SameSite: true},
+	}).Return(nil)
+
+	detected, err := svc.DetectOverlaps(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 2, detected)
+}
+
+func TestFeedService_DetectOverlaps_Disabled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc := service.NewFeedService(mock.NewMockFeedRepository(ctrl), nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	detected, err := svc.DetectOverlaps(context.Background())
+	require.NoError(t, err)
+	require.Zero(t, detected)
+}
+
+func TestFeedService_ListOverlaps(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFeeds := mock.NewMockFeedRepository(ctrl)
+	mockOverlaps := mock.NewMockFeedOverlapRepository(ctrl)
+	svc := service.NewFeedService(mockFeeds, nil, nil, nil, nil, nil, nil, nil, mockOverlaps, nil)
+
+	overlap := model.FeedOverlap{ID: 7, FeedID: 1, OtherFeedID: 2, SameSite: true}
+	mockOverlaps.EXPECT().List(gomock.Any()).Return([]model.FeedOverlap{overlap}, nil)
+	mockFeeds.EXPECT().GetByIDs(gomock.Any(), []int64{1, 2}).Return([]model.Feed{{ID: 2, Title: "RSS"}, {ID: 1, Title: "Atom"}}, nil)
+
+	overlaps, err := svc.ListOverlaps(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []service.FeedOverlap{{FeedOverlap: overlap, Feed: model.Feed{ID: 1, Title: "Atom"}, OtherFeed: model.Feed{ID: 2, Title: "RSS"}}}, overlaps)
+}
+
+func TestFeedService_MergeOverlap(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFeeds := mock.NewMockFeedRepository(ctrl)
+	mockOverlaps := mock.NewMockFeedOverlapRepository(ctrl)
+	svc := service.NewFeedService(mockFeeds, nil, nil, nil, nil, nil, nil, nil, mockOverlaps, nil)
+	ctx := context.Background()
+
+	overlap := model.FeedOverlap{ID: 7, FeedID: 1, OtherFeedID: 2}
+	mockOverlaps.EXPECT().GetByID(gomock.Any(), int64(7)).Return(overlap, nil).Times(3)
+
+	// Only a feed of the pair can be kept
+	_, err := svc.MergeOverlap(ctx, 7, 3)
+	require.ErrorIs(t, err, service.ErrInvalid)
+
+	mockOverlaps.EXPECT().GetByID(gomock.Any(), int64(8)).Return(model.FeedOverlap{}, sql.ErrNoRows)
+	_, err = svc.MergeOverlap(ctx, 8, 0)
+	require.ErrorIs(t, err, service.ErrNotFound)
+
+	// Keeping the second feed merges the first into it
+	mockFeeds.EXPECT().GetByID(gomock.Any(), int64(2)).Return(model.Feed{ID: 2, Title: "RSS"}, nil)
+	mockFeeds.EXPECT().GetByID(gomock.Any(), int64(1)).Return(model.Feed{ID: 1, Title: "Atom"}, nil)
+	mockOverlaps.EXPECT().Merge(gomock.Any(), int64(1), int64(2)).Return(4, nil)
+	kept, err := svc.MergeOverlap(ctx, 7, 2)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), kept.ID)
+
+	// Without a choice the first feed is kept; a feed gone since detection stops the merge
+	mockFeeds.EXPECT().GetByID(gomock.Any(), int64(1)).Return(model.Feed{ID: 1, Title: "Atom"}, nil)
+	mockFeeds.EXPECT().GetByID(gomock.Any(), int64(2)).Return(model.Feed{}, sql.ErrNoRows)
+	_, err = svc.MergeOverlap(ctx, 7, 0)
+	require.ErrorIs(t, err, service.ErrNotFound)
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+
+	"github.com/mmcdole/gofeed"
+	"golang.org/x/net/html/charset"
+)
+
+// guidPermaLinkKey is the Item.Custom key holding the isPermaLink attribute of an
+// RSS guid, when the feed set one.
+const guidPermaLinkKey = "gist:guidIsPermaLink"
+
+// feedTTLKey is the Feed.Custom key holding the RSS channel's <ttl>, which the
+// universal gofeed.Feed has no field for.
+const feedTTLKey = "gist:ttl"
+
+// parseFeed parses an RSS, Atom or JSON Feed document. JSON documents go
+// through parseJSONFeed. On top of what gofeed returns, it records the
+// isPermaLink attribute of RSS guids under guidPermaLinkKey, which gofeed
+// drops because it looks the attribute up as isPermalink, and the channel's
+// ttl under feedTTLKey.
+func parseFeed(body []byte) (*gofeed.Feed, error) {
+	if isJSONDocument(body) {
+		return parseJSONFeed(body)
+	}
+	parsed, err := gofeed.NewParser().Parse(bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if parsed.FeedType == "rss" {
+		annotateRSS(parsed, body)
+	}
+	return parsed, nil
+}
+
+// annotateRSS matches item elements to parsed.Items by position, as gofeed
+// translates them one to one and in order. It is best effort: a document the
+// lenient decoder can't get through keeps what was set so far.
+func annotateRSS(parsed *gofeed.Feed, body []byte) {
+	decoder := xml.NewDecoder(bytes.NewReader(body))
+	decoder.Strict = false
+	decoder.CharsetReader = charset.NewReaderLabel
+
+	index := -1
+	inItem := false
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return
+		}
+		if end, ok := token.(xml.EndElement); ok && strings.EqualFold(end.Name.Local, "item") {
+			inItem = false
+			continue
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		switch strings.ToLower(start.Name.Local) {
+		case "item":
+			index++
+			inItem = true
+		case "ttl":
+			var ttl string
+			if inItem || start.Name.Space != "" || decoder.DecodeElement(&ttl, &start) != nil {
+				continue
+			}
+			if parsed.Custom == nil {
+				parsed.Custom = make(map[string]string)
+			}
+			parsed.Custom[feedTTLKey] = strings.TrimSpace(ttl)
+		case "guid":
+			if index < 0 || index >= len(parsed.Items) {
+				continue
+			}
+			for _, attr := range start.Attr {
+				if !strings.EqualFold(attr.Name.Local, "isPermaLink") {
+					continue
+				}
+				item := parsed.Items[index]
+				if item.Custom == nil {
+					item.Custom = make(map[string]string)
+				}
+				item.Custom[guidPermaLinkKey] = strings.ToLower(strings.TrimSpace(attr.Value))
+			}
+		}
+	}
+}
@@ -0,0 +1,248 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gist/backend/internal/events"
+	"gist/backend/internal/model"
+	"gist/backend/pkg/logger"
+)
+
+// FeedPatch lists the settings of a feed to change; nil fields keep their value.
+type FeedPatch struct {
+	// Title renames the feed; its own title stays for refreshes to update.
+	Title *string
+	// FolderID moves the feed into a folder of its type; ClearFolder moves it out of any.
+	FolderID    *int64
+	ClearFolder bool
+	// SummaryPromptReminder, AssumeTimezone and UserAgent are cleared by a blank value.
+	SummaryPromptReminder *string
+	// MaxEntries caps the entries kept for the feed; 0 removes the cap.
+	MaxEntries     *int
+	Type           *string
+	AssumeTimezone *string
+	// DedupeKey switching to url or title_content re-hashes stored entries in the background.
+	DedupeKey     *string
+	AutoTranslate *string
+	UserAgent     *string
+	// AutoReadability opens entries in readability mode; InheritAutoReadability clears it.
+	AutoReadability        *bool
+	InheritAutoReadability bool
+	// PausedUntil pauses the feed until a time in the future; Unpause clears a pause.
+	PausedUntil *time.Time
+	Unpause     bool
+}
+
+func (s *feedService) Patch(ctx context.Context, id int64, patch FeedPatch) (model.Feed, error) {
+	patch, err := normalizeFeedPatch(patch, time.Now())
+	if err != nil {
+		return model.Feed{}, err
+	}
+
+	feed, err := s.feeds.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Feed{}, ErrNotFound
+		}
+		return model.Feed{}, fmt.Errorf("get feed: %w", err)
+	}
+
+	patched := applyFeedPatch(feed, patch)
+	if patched.FolderID != nil && !sameID(patched.FolderID, feed.FolderID) {
+		folder, err := s.folders.GetByID(ctx, *patched.FolderID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return model.Feed{}, ErrNotFound
+			}
+			return model.Feed{}, fmt.Errorf("check folder: %w", err)
+		}
+		if folder.Type != patched.Type {
+			logger.Warn("feed type mismatch with folder type", "module", "service", "action", "update", "resource", "feed", "result", "failed", "feed_id", id, "folder_id", folder.ID, "folder_type", folder.Type, "feed_type", patched.Type)
+			return model.Feed{}, ErrInvalid
+		}
+	}
+	if !feedPatchChanges(feed, patched) {
+		return feed, nil
+	}
+
+	updated, err := s.feeds.Update(ctx, patched)
+	if err != nil {
+		logger.Error("feed update failed", "module", "service", "action", "update", "resource", "feed", "result", "failed", "feed_id", id, "error", err)
+		return model.Feed{}, err
+	}
+	logger.Info("feed updated", "module", "service", "action", "update", "resource", "feed", "result", "ok", "feed_id", updated.ID, "feed_title", updated.Title)
+	s.bus.Publish(events.Event{Type: events.FeedUpdated, Data: events.FeedData{FeedID: updated.ID}})
+
+	if updated.DedupeKey != feed.DedupeKey {
+		s.rehashEntries(updated.ID, updated.DedupeKey)
+	}
+	return updated, nil
+}
+
+// normalizeFeedPatch checks every field of patch, returning it trimmed and
+// with clearing values as "".
+func normalizeFeedPatch(patch FeedPatch, now time.Time) (FeedPatch, error) {
+	if (patch.FolderID != nil && patch.ClearFolder) ||
+		(patch.AutoReadability != nil && patch.InheritAutoReadability) ||
+		(patch.PausedUntil != nil && patch.Unpause) {
+		return FeedPatch{}, fmt.Errorf("conflicting feed patch fields: %w", ErrInvalid)
+	}
+	if patch.Title != nil {
+		title := strings.TrimSpace(*patch.Title)
+		if title == "" {
+			return FeedPatch{}, fmt.Errorf("feed title is required: %w", ErrInvalid)
+		}
+		patch.Title = &title
+	}
+	if patch.SummaryPromptReminder != nil {
+		reminder, err := normalizeSummaryPromptReminder(*patch.SummaryPromptReminder)
+		if err != nil {
+			return FeedPatch{}, err
+		}
+		patch.SummaryPromptReminder = clearedIfNil(reminder)
+	}
+	if patch.MaxEntries != nil && *patch.MaxEntries < 0 {
+		return FeedPatch{}, fmt.Errorf("max entries must not be negative: %w", ErrInvalid)
+	}
+	if patch.Type != nil && !isValidFeedType(*patch.Type) {
+		return FeedPatch{}, fmt.Errorf("feed type %q: %w", *patch.Type, ErrInvalid)
+	}
+	if patch.AssumeTimezone != nil {
+		timezone := strings.TrimSpace(*patch.AssumeTimezone)
+		if timezone != "" {
+			if _, err := time.LoadLocation(timezone); err != nil {
+				return FeedPatch{}, fmt.Errorf("timezone %q: %w", timezone, ErrInvalid)
+			}
+		}
+		patch.AssumeTimezone = &timezone
+	}
+	if patch.DedupeKey != nil && !isValidDedupeKey(*patch.DedupeKey) {
+		return FeedPatch{}, fmt.Errorf("dedupe key %q: %w", *patch.DedupeKey, ErrInvalid)
+	}
+	if patch.AutoTranslate != nil {
+		switch *patch.AutoTranslate {
+		case model.FeedAutoTranslateInherit, model.FeedAutoTranslateOn, model.FeedAutoTranslateOff:
+		default:
+			return FeedPatch{}, fmt.Errorf("auto translate %q: %w", *patch.AutoTranslate, ErrInvalid)
+		}
+	}
+	if patch.UserAgent != nil {
+		userAgent, err := normalizeUserAgent(patch.UserAgent)
+		if err != nil {
+			return FeedPatch{}, err
+		}
+		patch.UserAgent = clearedIfNil(userAgent)
+	}
+	if patch.PausedUntil != nil && !patch.PausedUntil.After(now) {
+		return FeedPatch{}, fmt.Errorf("pause must end in the future: %w", ErrInvalid)
+	}
+	return patch, nil
+}
+
+// applyFeedPatch returns feed with the fields of a normalized patch set.
+func applyFeedPatch(feed model.Feed, patch FeedPatch) model.Feed {
+	if patch.Title != nil {
+		feed.CustomTitle = customTitle(*patch.Title, feed.Title)
+	}
+	if patch.FolderID != nil {
+		feed.FolderID = patch.FolderID
+	} else if patch.ClearFolder {
+		feed.FolderID = nil
+	}
+	if patch.SummaryPromptReminder != nil {
+		feed.SummaryPromptReminder = optionalString(*patch.SummaryPromptReminder)
+	}
+	if patch.MaxEntries != nil {
+		feed.MaxEntries = *patch.MaxEntries
+	}
+	if patch.Type != nil {
+		feed.Type = *patch.Type
+	}
+	if patch.AssumeTimezone != nil {
+		feed.AssumeTimezone = optionalString(*patch.AssumeTimezone)
+	}
+	if patch.DedupeKey != nil {
+		feed.DedupeKey = *patch.DedupeKey
+	}
+	if patch.AutoTranslate != nil {
+		feed.AutoTranslate = *patch.AutoTranslate
+	}
+	if patch.UserAgent != nil {
+		feed.UserAgent = optionalString(*patch.UserAgent)
+	}
+	if patch.AutoReadability != nil {
+		feed.AutoReadability = patch.AutoReadability
+	} else if patch.InheritAutoReadability {
+		feed.AutoReadability = nil
+	}
+	if patch.PausedUntil != nil {
+		feed.PausedUntil = patch.PausedUntil
+	} else if patch.Unpause {
+		feed.PausedUntil = nil
+	}
+	return feed
+}
+
+// feedPatchChanges reports whether patched differs from feed in a field a
+// FeedPatch sets.
+func feedPatchChanges(feed, patched model.Feed) bool {
+	return !samePointer(feed.CustomTitle, patched.CustomTitle) ||
+		!sameID(feed.FolderID, patched.FolderID) ||
+		!samePointer(feed.SummaryPromptReminder, patched.SummaryPromptReminder) ||
+		feed.MaxEntries != patched.MaxEntries ||
+		feed.Type != patched.Type ||
+		!samePointer(feed.AssumeTimezone, patched.AssumeTimezone) ||
+		feed.DedupeKey != patched.DedupeKey ||
+		feed.AutoTranslate != patched.AutoTranslate ||
+		!samePointer(feed.UserAgent, patched.UserAgent) ||
+		!samePointer(feed.AutoReadability, patched.AutoReadability) ||
+		(feed.PausedUntil == nil) != (patched.PausedUntil == nil) ||
+		(feed.PausedUntil != nil && !feed.PausedUntil.Equal(*patched.PausedUntil))
+}
+
+// rehashEntries re-hashes the feed's stored entries for a new dedupe key in
+// the background. GUIDs are not stored, so auto and guid hashes catch up as
+// the feed refreshes.
+func (s *feedService) rehashEntries(id int64, dedupeKey string) {
+	hash := storedEntryHash(dedupeKey)
+	if hash == nil {
+		return
+	}
+	go func() {
+		merged, err := s.entries.Rehash(context.Background(), id, hash)
+		if err != nil {
+			logger.Error("entry rehash failed", "module", "service", "action", "update", "resource", "entry", "result", "failed", "feed_id", id, "dedupe_key", dedupeKey, "error", err)
+			return
+		}
+		logger.Info("entries rehashed", "module", "service", "action", "update", "resource", "entry", "result", "ok", "feed_id", id, "dedupe_key", dedupeKey, "merged", merged)
+	}()
+}
+
+func isValidFeedType(feedType string) bool {
+	switch feedType {
+	case "article", "picture", "notification":
+		return true
+	}
+	return false
+}
+
+// clearedIfNil turns a nil optional value into "", which clears it in a FeedPatch.
+func clearedIfNil(value *string) *string {
+	if value == nil {
+		empty := ""
+		return &empty
+	}
+	return value
+}
+
+func samePointer[T comparable](a, b *T) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
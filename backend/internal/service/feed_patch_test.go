@@ -0,0 +1,158 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"gist/backend/internal/model"
+	"gist/backend/internal/repository/mock"
+	"gist/backend/internal/service"
+)
+
+func TestFeedService_Patch_LeavesUnsetFieldsUntouched(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFeeds := mock.NewMockFeedRepository(ctrl)
+	svc := service.NewFeedService(mockFeeds, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+
+	folderID := int64(10)
+	until := time.Now().Add(time.Hour)
+	feed := model.Feed{
+		ID:                    1,
+		Title:                 "Upstream",
+		CustomTitle:           stringPtr("Mine"),
+		FolderID:              &folderID,
+		SummaryPromptReminder: stringPtr("Keep numbers"),
+		MaxEntries:            200,
+		Type:                  "article",
+		AssumeTimezone:        stringPtr("Asia/Shanghai"),
+		DedupeKey:             model.DedupeKeyGUID,
+		AutoTranslate:         model.FeedAutoTranslateOn,
+		UserAgent:             stringPtr("Reader/1.0"),
+		AutoReadability:       boolPtr(true),
+		PausedUntil:           &until,
+	}
+	mockFeeds.EXPECT().GetByID(gomock.Any(), int64(1)).Return(feed, nil)
+	mockFeeds.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, updated model.Feed) (model.Feed, error) {
+			want := feed
+			want.Type = "picture"
+			want.UserAgent = nil
+			want.PausedUntil = nil
+			require.Equal(t, want, updated)
+			return updated, nil
+		},
+	)
+
+	updated, err := svc.Patch(context.Background(), 1, service.FeedPatch{
+		Type:      stringPtr("picture"),
+		UserAgent: stringPtr(" "),
+		Unpause:   true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "picture", updated.Type)
+	require.Equal(t, "Mine", *updated.CustomTitle)
+}
+
+func TestFeedService_Patch_MovesAndRetypesTogether(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFeeds := mock.NewMockFeedRepository(ctrl)
+	mockFolders := mock.NewMockFolderRepository(ctrl)
+	svc := service.NewFeedService(mockFeeds, mockFolders, nil, nil, nil, nil, nil, nil, nil, nil)
+
+	folderID := int64(10)
+	mockFeeds.EXPECT().GetByID(gomock.Any(), int64(1)).Return(model.Feed{ID: 1, Title: "Feed", Type: "article"}, nil)
+	mockFolders.EXPECT().GetByID(gomock.Any(), folderID).Return(model.Folder{ID: folderID, Type: "picture"}, nil)
+	mockFeeds.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, updated model.Feed) (model.Feed, error) {
+			require.Equal(t, folderID, *updated.FolderID)
+			require.Equal(t, "picture", updated.Type)
+			return updated, nil
+		},
+	)
+
+	_, err := svc.Patch(context.Background(), 1, service.FeedPatch{FolderID: &folderID, Type: stringPtr("picture")})
+	require.NoError(t, err)
+}
+
+func TestFeedService_Patch_ValidatesBeforeWriting(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFeeds := mock.NewMockFeedRepository(ctrl)
+	mockFolders := mock.NewMockFolderRepository(ctrl)
+	svc := service.NewFeedService(mockFeeds, mockFolders, nil, nil, nil, nil, nil, nil, nil, nil)
+	ctx := context.Background()
+	folderID := int64(10)
+
+	// One invalid field rejects the whole patch without reading the feed
+	invalid := []service.FeedPatch{
+		{Title: stringPtr("Renamed"), Type: stringPtr("video")},
+		{Title: stringPtr(" ")},
+		{MaxEntries: intPtr(-1)},
+		{AssumeTimezone: stringPtr("Mars/Olympus")},
+		{DedupeKey: stringPtr("link")},
+		{AutoTranslate: stringPtr("always")},
+		{UserAgent: stringPtr("Reader\n1.0")},
+		{PausedUntil: timePtr(time.Now().Add(-time.Minute))},
+		{FolderID: &folderID, ClearFolder: true},
+		{AutoReadability: boolPtr(true), InheritAutoReadability: true},
+	}
+	for _, patch := range invalid {
+		_, err := svc.Patch(ctx, 1, patch)
+		require.ErrorIs(t, err, service.ErrInvalid)
+	}
+
+	// Checks against stored rows fail before the write too
+	mockFeeds.EXPECT().GetByID(gomock.Any(), int64(2)).Return(model.Feed{}, sql.ErrNoRows)
+	_, err := svc.Patch(ctx, 2, service.FeedPatch{Title: stringPtr("Renamed")})
+	require.ErrorIs(t, err, service.ErrNotFound)
+
+	mockFeeds.EXPECT().GetByID(gomock.Any(), int64(1)).Return(model.Feed{ID: 1, Title: "Feed", Type: "article"}, nil)
+	mockFolders.EXPECT().GetByID(gomock.Any(), folderID).Return(model.Folder{}, sql.ErrNoRows)
+	_, err = svc.Patch(ctx, 1, service.FeedPatch{Title: stringPtr("Renamed"), FolderID: &folderID})
+	require.ErrorIs(t, err, service.ErrNotFound)
+
+	mockFeeds.EXPECT().GetByID(gomock.Any(), int64(1)).Return(model.Feed{ID: 1, Title: "Feed", Type: "article"}, nil)
+	mockFolders.EXPECT().GetByID(gomock.Any(), folderID).Return(model.Folder{ID: folderID, Type: "picture"}, nil)
+	_, err = svc.Patch(ctx, 1, service.FeedPatch{Title: stringPtr("Renamed"), FolderID: &folderID})
+	require.ErrorIs(t, err, service.ErrInvalid)
+}
+
+func TestFeedService_Patch_NoOp(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFeeds := mock.NewMockFeedRepository(ctrl)
+	svc := service.NewFeedService(mockFeeds, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+
+	feed := model.Feed{ID: 1, Title: "Feed", Type: "article", DedupeKey: model.DedupeKeyURL, AssumeTimezone: stringPtr("UTC")}
+	mockFeeds.EXPECT().GetByID(gomock.Any(), int64(1)).Return(feed, nil)
+
+	got, err := svc.Patch(context.Background(), 1, service.FeedPatch{
+		Title:          stringPtr(" Feed "),
+		ClearFolder:    true,
+		Type:           stringPtr("article"),
+		DedupeKey:      stringPtr(model.DedupeKeyURL),
+		AssumeTimezone: stringPtr(" UTC "),
+		Unpause:        true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, feed, got)
+}
+
+func intPtr(value int) *int {
+	return &value
+}
+
+func timePtr(value time.Time) *time.Time {
+	return &value
+}
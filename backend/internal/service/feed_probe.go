@@ -0,0 +1,276 @@
+package service
+
+import (
+	"context"
+	"crypto/tls"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"gist/backend/internal/model"
+	anubischallenge "gist/backend/internal/service/anubis"
+	"gist/backend/pkg/logger"
+	"gist/backend/pkg/network"
+)
+
+// ProbeBodyPreviewSize is how much of the response body a probe returns.
+const ProbeBodyPreviewSize = 2 << 10
+
+// probeHeaders are the response headers a probe reports.
+var probeHeaders = []string{"ETag", "Last-Modified", "Content-Type", "Content-Encoding"}
+
+// FeedProbe reports what one fetch of a feed returned. Fields after Error are
+// only set when the request got a response.
+type FeedProbe struct {
+	URL string
+	// UserAgent is the FeedUserAgent* choice used, UserAgentValue its header value.
+	UserAgent      string
+	UserAgentValue string
+	SentCookie     bool
+	ResolvedIPs    []string
+	RemoteAddr     string
+	Timing         FeedProbeTiming
+	// Error is the transport or body read error, if any.
+	Error string
+
+	StatusCode int
+	Headers    map[string]string
+	BodySize   int
+	// BodyPreview holds the first ProbeBodyPreviewSize bytes of the body, cut at
+	// a rune boundary, with invalid UTF-8 replaced.
+	BodyPreview     string
+	AnubisPage      bool
+	AnubisChallenge bool
+	Parsed          bool
+	ParseError      string
+	FeedType        string
+	ItemCount       int
+	ItemsNoGUID     int
+	ItemsNoLink     int
+	// Ingest is what a refresh would skip or alter of the items, nil when it
+	// would take them all as delivered.
+	Ingest *IngestReport
+}
+
+// FeedProbeTiming breaks a probe's duration down by phase. Phases that did not
+// happen, such as DNS for an IP literal or TLS over plain HTTP, stay zero.
+type FeedProbeTiming struct {
+	DNS     time.Duration
+	Connect time.Duration
+	TLS     time.Duration
+	// TTFB is measured from the start of the request to the first response byte.
+	TTFB  time.Duration
+	Total time.Duration
+}
+
+func (s *refreshService) Probe(ctx context.Context, feedID int64, userAgent string) (FeedProbe, error) {
+	feed, err := s.feeds.GetByID(ctx, feedID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return FeedProbe{}, ErrNotFound
+		}
+		return FeedProbe{}, fmt.Errorf("get feed: %w", err)
+	}
+	if IsStaticFeed(feed) {
+		return FeedProbe{}, fmt.Errorf("feed %d is static: %w", feedID, ErrInvalid)
+	}
+
+	ua, err := s.probeUserAgent(ctx, feed, userAgent)
+	if err != nil {
+		return FeedProbe{}, err
+	}
+
+	probe := s.probe(ctx, feed, ua)
+	logger.Info("feed probed", "module", "service", "action", "fetch", "resource", "feed", "result", "ok", "feed_id", feed.ID, "host", network.ExtractHost(feed.URL), "status_code", probe.StatusCode, "user_agent", probe.UserAgent, "duration_ms", probe.Timing.Total.Milliseconds())
+	return probe, nil
+}
+
+// probeUserAgent returns the user agent for choice, or the one a refresh would
+// start with when choice is empty.
+func (s *refreshService) probeUserAgent(ctx context.Context, feed model.Feed, choice string) (feedUserAgent, error) {
+	defaultUA := s.defaultUserAgent(ctx, feed)
+	switch choice {
+	case "":
+		if feed.PreferredUserAgent == model.FeedUserAgentFallback {
+			if fallback, ok := s.alternateUserAgent(ctx, feed, model.FeedUserAgentDefault); ok {
+				return fallback, nil
+			}
+		}
+		return defaultUA, nil
+	case model.FeedUserAgentDefault:
+		return defaultUA, nil
+	case model.FeedUserAgentFallback:
+		fallback, ok := s.alternateUserAgent(ctx, feed, model.FeedUserAgentDefault)
+		if !ok {
+			return feedUserAgent{}, fmt.Errorf("no fallback user agent configured: %w", ErrInvalid)
+		}
+		return fallback, nil
+	default:
+		return feedUserAgent{}, fmt.Errorf("unknown user agent %q: %w", choice, ErrInvalid)
+	}
+}
+
+// probe fetches feedURL once like a refresh does, minus the conditional GET
+// headers so the full body comes back. Anubis pages are reported, not solved,
+// so nothing gets stored. Parsed items go through a dry run of a refresh.
+func (s *refreshService) probe(ctx context.Context, feed model.Feed, ua feedUserAgent) FeedProbe {
+	feedURL := feed.URL
+	probe := FeedProbe{URL: feedURL, UserAgent: ua.choice, UserAgentValue: ua.value}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, feedURL, nil)
+	if err != nil {
+		probe.Error = err.Error()
+		return probe
+	}
+	req.Header.Set("User-Agent", userAgentHeader(ua.value))
+	if cookie := getCachedAnubisCookie(ctx, s.anubis, network.ExtractHost(feedURL), req.Header); cookie != "" {
+		req.Header.Set("Cookie", cookie)
+		probe.SentCookie = true
+	}
+
+	trace := &probeTrace{}
+	start := time.Now()
+	req = req.WithContext(httptrace.WithClientTrace(req.Context(), trace.clientTrace()))
+	resp, err := s.clientFactory.NewHTTPClient(ctx, refreshTimeout).Do(req)
+	if err != nil {
+		probe.Error = err.Error()
+		trace.fill(&probe, start, time.Now())
+		return probe
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	trace.fill(&probe, start, time.Now())
+	if err != nil {
+		probe.Error = err.Error()
+	}
+
+	probe.StatusCode = resp.StatusCode
+	probe.Headers = make(map[string]string)
+	for _, name := range probeHeaders {
+		if value := resp.Header.Get(name); value != "" {
+			probe.Headers[name] = value
+		}
+	}
+	probe.BodySize = len(body)
+	probe.BodyPreview = bodyPreview(body, ProbeBodyPreviewSize)
+	probe.AnubisPage = anubischallenge.IsAnubisPage(body)
+	probe.AnubisChallenge = anubischallenge.IsAnubisChallenge(body)
+
+	parsed, parseErr := parseFeed(body)
+	if parseErr != nil {
+		probe.ParseError = parseErr.Error()
+		return probe
+	}
+	probe.Parsed = true
+	probe.FeedType = parsed.FeedType
+	probe.ItemCount = len(parsed.Items)
+	for _, item := range parsed.Items {
+		if strings.TrimSpace(item.GUID) == "" {
+			probe.ItemsNoGUID++
+		}
+		if strings.TrimSpace(item.Link) == "" {
+			probe.ItemsNoLink++
+		}
+	}
+	var report ingestReportBuilder
+	entries := prepareEntries(feed, parsed.Items, InitialBackfill{}, loadGeneralSettings(ctx, s.settings), &report)
+	probe.Ingest = report.build(len(parsed.Items), len(entries))
+	return probe
+}
+
+// bodyPreview returns at most limit bytes of body without splitting a rune.
+func bodyPreview(body []byte, limit int) string {
+	if len(body) > limit {
+		body = body[:limit]
+		// Drop a rune cut in half, but not invalid bytes that were there already
+		for i := 1; i < utf8.UTFMax && i <= len(body); i++ {
+			if utf8.RuneStart(body[len(body)-i]) {
+				if !utf8.FullRune(body[len(body)-i:]) {
+					body = body[:len(body)-i]
+				}
+				break
+			}
+		}
+	}
+	return strings.ToValidUTF8(string(body), "�")
+}
+
+// probeTrace records connection events of one request. Dialing may race IPv4
+// against IPv6, so the callbacks can run concurrently.
+type probeTrace struct {
+	mu           sync.Mutex
+	ips          []string
+	remoteAddr   string
+	dnsStart     time.Time
+	dnsDone      time.Time
+	connectStart time.Time
+	connectDone  time.Time
+	tlsStart     time.Time
+	tlsDone      time.Time
+	firstByte    time.Time
+}
+
+func (t *probeTrace) clientTrace() *httptrace.ClientTrace {
+	at := func(field *time.Time) {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		if field.IsZero() {
+			*field = time.Now()
+		}
+	}
+	return &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) { at(&t.dnsStart) },
+		DNSDone: func(info httptrace.DNSDoneInfo) {
+			at(&t.dnsDone)
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			for _, addr := range info.Addrs {
+				t.ips = append(t.ips, addr.IP.String())
+			}
+		},
+		ConnectStart: func(string, string) { at(&t.connectStart) },
+		ConnectDone: func(_, _ string, err error) {
+			if err == nil {
+				at(&t.connectDone)
+			}
+		},
+		TLSHandshakeStart: func() { at(&t.tlsStart) },
+		TLSHandshakeDone:  func(tls.ConnectionState, error) { at(&t.tlsDone) },
+		GotConn: func(info httptrace.GotConnInfo) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			if t.remoteAddr == "" && info.Conn != nil {
+				t.remoteAddr = info.Conn.RemoteAddr().String()
+			}
+		},
+		GotFirstResponseByte: func() { at(&t.firstByte) },
+	}
+}
+
+func (t *probeTrace) fill(probe *FeedProbe, start, end time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	span := func(from, to time.Time) time.Duration {
+		if from.IsZero() || to.IsZero() {
+			return 0
+		}
+		return to.Sub(from)
+	}
+	probe.ResolvedIPs = t.ips
+	probe.RemoteAddr = t.remoteAddr
+	probe.Timing = FeedProbeTiming{
+		DNS:     span(t.dnsStart, t.dnsDone),
+		Connect: span(t.connectStart, t.connectDone),
+		TLS:     span(t.tlsStart, t.tlsDone),
+		TTFB:    span(start, t.firstByte),
+		Total:   end.Sub(start),
+	}
+}
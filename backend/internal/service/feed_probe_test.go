@@ -0,0 +1,148 @@
+package service_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"gist/backend/internal/config"
+	"gist/backend/internal/model"
+	"gist/backend/internal/repository/mock"
+	"gist/backend/internal/service"
+	"gist/backend/pkg/network"
+)
+
+func newProbeService(t *testing.T, feed model.Feed) service.RefreshService {
+	ctrl := gomock.NewController(t)
+	mockFeeds := mock.NewMockFeedRepository(ctrl)
+	// Only the lookup is expected: a probe must not write anything back
+	mockFeeds.EXPECT().GetByID(gomock.Any(), feed.ID).Return(feed, nil)
+	return service.NewRefreshService(mockFeeds, mock.NewMockEntryRepository(ctrl), nil, nil, nil, nil, network.NewClientFactoryForTest(&http.Client{}), nil, nil, nil, nil, nil)
+}
+
+func TestRefreshService_Probe(t *testing.T) {
+	var gotHeaders http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHeaders = r.Header.Clone()
+		w.Header().Set("Content-Type", "application/rss+xml; charset=utf-8")
+		w.Header().Set("ETag", `"v2"`)
+		w.Write([]byte(`<?xml version="1.0"?><rss version="2.0"><channel><title>Feed</title>
+<item><title>One</title><guid>1</guid><link>https://example.com/1</link></item>
+<item><title>Two</title><link>https://example.com/2</link></item>
+<item><title>Three</title><guid>3</guid></item>
+</channel></rss>`))
+	}))
+	defer server.Close()
+
+	etag := `"v1"`
+	svc := newProbeService(t, model.Feed{ID: 1, URL: server.URL, ETag: &etag})
+
+	probe, err := svc.Probe(context.Background(), 1, "")
+	require.NoError(t, err)
+
+	require.Empty(t, gotHeaders.Get("If-None-Match"))
+	require.Equal(t, config.DefaultUserAgent, gotHeaders.Get("User-Agent"))
+	require.Equal(t, model.FeedUserAgentDefault, probe.UserAgent)
+	require.Empty(t, probe.Error)
+	require.Equal(t, http.StatusOK, probe.StatusCode)
+	require.Equal(t, `"v2"`, probe.Headers["ETag"])
+	require.Equal(t, "application/rss+xml; charset=utf-8", probe.Headers["Content-Type"])
+	require.NotContains(t, probe.Headers, "Last-Modified")
+	require.Contains(t, probe.BodyPreview, "<title>Feed</title>")
+	require.NotEmpty(t, probe.RemoteAddr)
+	require.Positive(t, probe.Timing.Total)
+	require.False(t, probe.AnubisPage)
+	require.True(t, probe.Parsed)
+	require.Equal(t, "rss", probe.FeedType)
+	require.Equal(t, 3, probe.ItemCount)
+	require.Equal(t, 1, probe.ItemsNoGUID)
+	require.Equal(t, 1, probe.ItemsNoLink)
+	// The dry run reports what a refresh would skip
+	require.NotNil(t, probe.Ingest)
+	require.Equal(t, 3, probe.Ingest.Items)
+	require.Equal(t, 2, probe.Ingest.Saved)
+	require.Equal(t, map[string]int{service.IngestMissingLink: 1}, probe.Ingest.Counts)
+	require.Equal(t, "Three", probe.Ingest.Issues[0].Title)
+}
+
+func TestRefreshService_Probe_StripsStoredUserAgentLineBreaks(t *testing.T) {
+	var gotHeaders http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHeaders = r.Header.Clone()
+	}))
+	defer server.Close()
+
+	// Saved before custom User-Agents were validated
+	userAgent := "Bad\r\nX-Injected: 1"
+	svc := newProbeService(t, model.Feed{ID: 1, URL: server.URL, UserAgent: &userAgent})
+
+	probe, err := svc.Probe(context.Background(), 1, "")
+	require.NoError(t, err)
+	require.Empty(t, probe.Error)
+	require.Equal(t, "Bad X-Injected: 1", gotHeaders.Get("User-Agent"))
+	require.Empty(t, gotHeaders.Get("X-Injected"))
+}
+
+func TestRefreshService_Probe_AnubisPage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<html><script id="anubis_challenge" type="application/json">{"challenge":"abc","rules":{"difficulty":4}}</script></html>`))
+	}))
+	defer server.Close()
+
+	svc := newProbeService(t, model.Feed{ID: 1, URL: server.URL})
+
+	probe, err := svc.Probe(context.Background(), 1, "")
+	require.NoError(t, err)
+	require.True(t, probe.AnubisPage)
+	require.True(t, probe.AnubisChallenge)
+	require.False(t, probe.Parsed)
+	require.NotEmpty(t, probe.ParseError)
+}
+
+func TestRefreshService_Probe_TruncatesBodyPreview(t *testing.T) {
+	// One ASCII byte shifts the two-byte runes so the cut lands inside one
+	body := "x" + strings.Repeat("é", service.ProbeBodyPreviewSize)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	svc := newProbeService(t, model.Feed{ID: 1, URL: server.URL})
+
+	probe, err := svc.Probe(context.Background(), 1, "")
+	require.NoError(t, err)
+	require.Equal(t, len(body), probe.BodySize)
+	require.True(t, utf8.ValidString(probe.BodyPreview))
+	require.Equal(t, body[:service.ProbeBodyPreviewSize-1], probe.BodyPreview)
+}
+
+func TestRefreshService_Probe_TransportError(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	url := server.URL
+	server.Close()
+
+	svc := newProbeService(t, model.Feed{ID: 1, URL: url})
+
+	probe, err := svc.Probe(context.Background(), 1, "")
+	require.NoError(t, err)
+	require.NotEmpty(t, probe.Error)
+	require.Zero(t, probe.StatusCode)
+}
+
+func TestRefreshService_Probe_Invalid(t *testing.T) {
+	svc := newProbeService(t, model.Feed{ID: 1, URL: service.StaticFeedURLPrefix + "abc"})
+	_, err := svc.Probe(context.Background(), 1, "")
+	require.ErrorIs(t, err, service.ErrInvalid)
+
+	// No settings, so no fallback user agent to probe with
+	svc = newProbeService(t, model.Feed{ID: 2, URL: "https://example.com/rss"})
+	_, err = svc.Probe(context.Background(), 2, model.FeedUserAgentFallback)
+	require.ErrorIs(t, err, service.ErrInvalid)
+}
@@ -2,7 +2,6 @@
 package service
 
 import (
-	"bytes"
 	"context"
 	"database/sql"
 	"errors"
@@ -15,31 +14,107 @@ import (
 	"time"
 	"unicode/utf8"
 
+	"github.com/google/uuid"
 	"github.com/mmcdole/gofeed"
 
 	"gist/backend/internal/config"
+	"gist/backend/internal/events"
 	"gist/backend/internal/hashutil"
 	"gist/backend/internal/model"
 	"gist/backend/internal/repository"
+	"gist/backend/internal/urlutil"
+	"gist/backend/pkg/langdetect"
 	"gist/backend/pkg/logger"
 	"gist/backend/pkg/network"
+	"gist/backend/pkg/readtime"
 	"gist/backend/pkg/sanitizer"
 )
 
 const feedTimeout = 30 * time.Second
 const maxFeedSummaryPromptReminderLength = 2000
 
+// languageDetectRunes is how much of an entry's text is used to guess its
+// language when the feed declares none.
+const languageDetectRunes = 500
+
 type FeedService interface {
-	Add(ctx context.Context, feedURL string, folderID *int64, titleOverride string, feedType string) (model.Feed, error)
+	// Add subscribes to a feed and saves its current items as limited by backfill.
+	Add(ctx context.Context, feedURL string, folderID *int64, titleOverride string, feedType string, backfill InitialBackfill) (model.Feed, error)
 	AddWithoutFetch(ctx context.Context, feedURL string, folderID *int64, titleOverride string, feedType string) (model.Feed, bool, error)
+	// AddStatic creates a feed for pasted content under a synthetic static:// URL.
+	// Its entries are saved with RefreshService.IngestStatic.
+	AddStatic(ctx context.Context, parsed *gofeed.Feed, folderID *int64, titleOverride string, feedType string) (model.Feed, error)
+	// CreateIngestToken issues a token for pushing entries into a static feed,
+	// replacing any previous one. The plaintext is only returned here.
+	CreateIngestToken(ctx context.Context, feedID int64) (string, error)
+	// ValidateIngestToken returns ErrInvalidToken unless token is the feed's ingest token.
+	ValidateIngestToken(ctx context.Context, feedID int64, token string) error
 	Preview(ctx context.Context, feedURL string) (FeedPreview, error)
 	List(ctx context.Context, folderID *int64) ([]model.Feed, error)
-	Update(ctx context.Context, id int64, title string, folderID *int64, summaryPromptReminder *string) (model.Feed, error)
+	// ListWithUnreadCounts lists all feeds with their unread counts in one query.
+	ListWithUnreadCounts(ctx context.Context) ([]model.FeedWithUnread, error)
+	// GetActivityStats returns per-feed entry volume for sidebar sorting.
+	GetActivityStats(ctx context.Context) ([]model.FeedActivityStats, error)
+	// Patch validates every field of patch before writing the changed ones at
+	// once. A patch that changes nothing returns the feed without a write.
+	Patch(ctx context.Context, id int64, patch FeedPatch) (model.Feed, error)
+	// Update renames and moves a feed. A nil summaryPromptReminder or maxEntries
+	// leaves it unchanged; maxEntries 0 removes the entry cap.
+	Update(ctx context.Context, id int64, title string, folderID *int64, summaryPromptReminder *string, maxEntries *int) (model.Feed, error)
 	UpdateType(ctx context.Context, id int64, feedType string) error
+	// UpdateAssumeTimezone sets the IANA zone for item dates without one; nil or empty uses the global setting.
+	UpdateAssumeTimezone(ctx context.Context, id int64, timezone *string) error
+	// Reorder places feeds of one folder in the given order; feeds from different
+	// folders return ErrInvalid.
+	Reorder(ctx context.Context, ids []int64) error
+	// UpdateDedupeKey changes how entry hashes are derived. Switching to url or
+	// title_content re-hashes stored entries in the background and merges duplicates.
+	UpdateDedupeKey(ctx context.Context, id int64, dedupeKey string) error
+	// UpdateAutoTranslate sets whether list titles of the feed's new entries are
+	// translated after refreshes: inherit, on or off.
+	UpdateAutoTranslate(ctx context.Context, id int64, mode string) error
+	// UpdateUserAgent sets the User-Agent refreshes send instead of the default;
+	// nil or blank inherits it from the feed's folders.
+	UpdateUserAgent(ctx context.Context, id int64, userAgent *string) error
+	// UpdateAutoReadability sets whether the feed's entries open in readability
+	// mode; nil inherits it from the feed's folders.
+	UpdateAutoReadability(ctx context.Context, id int64, enabled *bool) error
+	// EffectiveConfigs resolves the options of feeds from their folders, keyed by feed ID.
+	EffectiveConfigs(ctx context.Context, feeds []model.Feed) (map[int64]FeedConfig, error)
+	// ListMutedAuthors returns the authors muted in the feed.
+	ListMutedAuthors(ctx context.Context, id int64) ([]string, error)
+	// MuteAuthor leaves the author's entries out of unread lists and marks new
+	// ones read on arrival. It returns the feed's muted authors.
+	MuteAuthor(ctx context.Context, id int64, author string) ([]string, error)
+	// UnmuteAuthor returns ErrNotFound when author is not muted in the feed.
+	UnmuteAuthor(ctx context.Context, id int64, author string) ([]string, error)
+	// Pause skips the feed in bulk refreshes and unread totals until the given time, which must be in the future.
+	Pause(ctx context.Context, id int64, until time.Time) error
+	// Unpause clears a pause; unpausing a feed that is not paused is a no-op.
+	Unpause(ctx context.Context, id int64) error
+	// Delete and DeleteBatch move feeds to the trash; Restore brings one back within DeleteRetention.
 	Delete(ctx context.Context, id int64) error
 	DeleteBatch(ctx context.Context, ids []int64) error
+	Restore(ctx context.Context, id int64) (model.Feed, error)
+	// PurgeDeleted permanently removes feeds and folders deleted more than DeleteRetention ago,
+	// raw feed items stored more than RawItemRetention ago and entry tombstones
+	// older than EntryTombstoneRetention.
+	PurgeDeleted(ctx context.Context) error
+	// DetectOverlaps stores the pairs of feeds that look like the same
+	// subscription, by site URL or by shared recent entries, and returns how
+	// many were found. It never merges them.
+	DetectOverlaps(ctx context.Context) (int, error)
+	// ListOverlaps returns the detected pairs whose feeds are both live.
+	ListOverlaps(ctx context.Context) ([]FeedOverlap, error)
+	// MergeOverlap moves the other feed's entries and read/starred state onto
+	// keepFeedID and moves that feed to the trash. keepFeedID 0 keeps the
+	// pair's first feed; a feed outside the pair returns ErrInvalid.
+	MergeOverlap(ctx context.Context, id int64, keepFeedID int64) (model.Feed, error)
 }
 
+// DeleteRetention is how long deleted feeds and folders can be restored before they are purged.
+const DeleteRetention = 7 * 24 * time.Hour
+
 type FeedPreview struct {
 	URL         string
 	Title       string
@@ -48,6 +123,8 @@ type FeedPreview struct {
 	ImageURL    *string
 	ItemCount   *int
 	LastUpdated *string
+	// SuggestedType is the feed type guessed from the fetched items.
+	SuggestedType string
 }
 
 type feedService struct {
@@ -58,20 +135,29 @@ type feedService struct {
 	settings      SettingsService
 	clientFactory *network.ClientFactory
 	anubis        AnubisSolver
+	// rules files feeds subscribed without a folder; nil disables them.
+	rules repository.FolderRuleRepository
+	// overlaps stores detected duplicate subscriptions; nil disables detection.
+	overlaps repository.FeedOverlapRepository
+	// bus receives change events for GET /events; nil drops them.
+	bus *events.Bus
 }
 
-func NewFeedService(feeds repository.FeedRepository, folders repository.FolderRepository, entries repository.EntryRepository, icons IconService, settings SettingsService, clientFactory *network.ClientFactory, anubisSolver AnubisSolver) FeedService {
-	return &feedService{feeds: feeds, folders: folders, entries: entries, icons: icons, settings: settings, clientFactory: clientFactory, anubis: anubisSolver}
+func NewFeedService(feeds repository.FeedRepository, folders repository.FolderRepository, entries repository.EntryRepository, icons IconService, settings SettingsService, clientFactory *network.ClientFactory, anubisSolver AnubisSolver, rules repository.FolderRuleRepository, overlaps repository.FeedOverlapRepository, bus *events.Bus) FeedService {
+	return &feedService{feeds: feeds, folders: folders, entries: entries, icons: icons, settings: settings, clientFactory: clientFactory, anubis: anubisSolver, rules: rules, overlaps: overlaps, bus: bus}
 }
 
-func (s *feedService) Add(ctx context.Context, feedURL string, folderID *int64, titleOverride string, feedType string) (model.Feed, error) {
+func (s *feedService) Add(ctx context.Context, feedURL string, folderID *int64, titleOverride string, feedType string, backfill InitialBackfill) (model.Feed, error) {
 	trimmedURL := strings.TrimSpace(feedURL)
 	if !isValidURL(trimmedURL) {
 		return model.Feed{}, ErrInvalid
 	}
-	if existing, err := s.feeds.FindByURL(ctx, trimmedURL); err != nil {
+	// Match on the canonical form so tracking params don't create a second subscription
+	existing, err := s.feeds.FindByURL(ctx, urlutil.CanonicalFeedURL(trimmedURL))
+	if err != nil {
 		return model.Feed{}, fmt.Errorf("check feed url: %w", err)
-	} else if existing != nil {
+	}
+	if existing != nil && existing.DeletedAt == nil {
 		return model.Feed{}, &FeedConflictError{ExistingFeed: *existing}
 	}
 	if folderID != nil {
@@ -82,11 +168,18 @@ func (s *feedService) Add(ctx context.Context, feedURL string, folderID *int64,
 			}
 			return model.Feed{}, fmt.Errorf("check folder: %w", err)
 		}
+		// Feeds must match their folder's type, so the folder decides in auto mode
+		if feedType == FeedTypeAuto {
+			feedType = folder.Type
+		}
 		if folder.Type != feedType {
 			logger.Warn("feed type mismatch with folder type", "module", "service", "action", "create", "resource", "feed", "result", "failed", "folder_id", *folderID, "folder_type", folder.Type, "feed_type", feedType)
 			return model.Feed{}, ErrInvalid
 		}
 	}
+	if existing != nil {
+		return s.resubscribe(ctx, *existing, folderID, titleOverride, feedType)
+	}
 
 	fetched, fetchErr := s.fetchFeed(ctx, trimmedURL)
 	if fetchErr != nil {
@@ -96,6 +189,12 @@ func (s *feedService) Add(ctx context.Context, feedURL string, folderID *int64,
 		if finalTitle == "" {
 			finalTitle = trimmedURL
 		}
+		if folderID == nil {
+			folderID, feedType = s.ruleFolder(ctx, trimmedURL, finalTitle, feedType)
+		}
+		if feedType == FeedTypeAuto {
+			feedType = "article"
+		}
 		errMsg := fetchErr.Error()
 		feed := model.Feed{
 			FolderID:     folderID,
@@ -104,35 +203,56 @@ func (s *feedService) Add(ctx context.Context, feedURL string, folderID *int64,
 			Type:         feedType,
 			ErrorMessage: &errMsg,
 		}
-		return s.feeds.Create(ctx, feed)
+		created, err := s.feeds.Create(ctx, feed)
+		if errors.Is(err, repository.ErrConflict) {
+			return model.Feed{}, s.lostCreateRace(ctx, trimmedURL)
+		}
+		return created, err
 	}
 
-	finalTitle := strings.TrimSpace(titleOverride)
-	if finalTitle == "" {
-		finalTitle = strings.TrimSpace(fetched.title)
+	upstreamTitle := strings.TrimSpace(fetched.title)
+	if upstreamTitle == "" {
+		upstreamTitle = trimmedURL
 	}
-	if finalTitle == "" {
-		finalTitle = trimmedURL
+	// An override that differs from the feed's own title is kept as a rename
+	renamed := customTitle(titleOverride, upstreamTitle)
+	finalTitle := upstreamTitle
+	if renamed != nil {
+		finalTitle = *renamed
+	}
+
+	if folderID == nil {
+		folderID, feedType = s.ruleFolder(ctx, trimmedURL, finalTitle, feedType)
+	}
+	if feedType == FeedTypeAuto {
+		feedType = classifyFeedType(fetched.items)
+		logger.Debug("feed type classified", "module", "service", "action", "create", "resource", "feed", "result", "ok", "host", network.ExtractHost(trimmedURL), "feed_type", feedType)
 	}
 
 	feed := model.Feed{
 		FolderID:     folderID,
-		Title:        finalTitle,
+		Title:        upstreamTitle,
+		CustomTitle:  renamed,
 		URL:          trimmedURL,
 		SiteURL:      optionalString(fetched.siteURL),
 		Description:  optionalString(fetched.description),
 		Type:         feedType,
 		ETag:         optionalString(fetched.etag),
 		LastModified: optionalString(fetched.lastModified),
+		Language:     fetched.language,
 	}
 
 	created, err := s.feeds.Create(ctx, feed)
 	if err != nil {
+		if errors.Is(err, repository.ErrConflict) {
+			return model.Feed{}, s.lostCreateRace(ctx, trimmedURL)
+		}
 		logger.Error("feed create failed", "module", "service", "action", "create", "resource", "feed", "result", "failed", "host", network.ExtractHost(trimmedURL), "error", err)
 		return model.Feed{}, err
 	}
 
 	logger.Info("feed created", "module", "service", "action", "create", "resource", "feed", "result", "ok", "feed_id", created.ID, "feed_title", created.Title, "host", network.ExtractHost(created.URL))
+	s.bus.Publish(events.Event{Type: events.FeedCreated, Data: events.FeedData{FeedID: created.ID}})
 
 	// Download and save icon
 	if s.icons != nil {
@@ -143,20 +263,24 @@ func (s *feedService) Add(ctx context.Context, feedURL string, folderID *int64,
 		if siteURL == "" {
 			siteURL = trimmedURL // Use feed URL as fallback for favicon
 		}
-		if iconPath, err := s.icons.FetchAndSaveIcon(ctx, fetched.imageURL, siteURL); err == nil && iconPath != "" {
-			_ = s.feeds.UpdateIconPath(ctx, created.ID, iconPath)
+		if iconPath := saveFeedIcon(ctx, s.icons, s.feeds, created.ID, created.DisplayTitle(), fetched.imageURL, siteURL); iconPath != "" {
 			created.IconPath = &iconPath
 		}
 	}
 
 	// Save entries from the fetched feed
 	dynamicTime := hasDynamicTime(fetched.items)
-	for _, item := range fetched.items {
-		entry := itemToEntry(created.ID, item, dynamicTime)
+	general := loadGeneralSettings(ctx, s.settings)
+	loc := feedLocation(created, general)
+	for _, item := range backfill.filter(fetched.items) {
+		entry := itemToEntry(created, item, dynamicTime, loc)
 		if entry.URL == nil || *entry.URL == "" {
 			continue
 		}
-		if err := s.entries.CreateOrUpdate(ctx, entry); err != nil {
+		attachRawItem(&entry, item, general)
+		entry.Read = backfill.MarkRead
+		// The feed was just created, so there is no earlier content to snapshot
+		if err := s.entries.CreateOrUpdate(ctx, entry, 0); err != nil {
 			logger.Warn("entry create failed", "module", "service", "action", "create", "resource", "entry", "result", "failed", "feed_id", created.ID, "feed_title", created.Title, "host", network.ExtractHost(*entry.URL), "error", err)
 		}
 	}
@@ -171,9 +295,12 @@ func (s *feedService) AddWithoutFetch(ctx context.Context, feedURL string, folde
 	if !isValidURL(trimmedURL) {
 		return model.Feed{}, false, ErrInvalid
 	}
-	if existing, err := s.feeds.FindByURL(ctx, trimmedURL); err != nil {
+	// Match on the canonical form so tracking params don't create a second subscription
+	existing, err := s.feeds.FindByURL(ctx, urlutil.CanonicalFeedURL(trimmedURL))
+	if err != nil {
 		return model.Feed{}, false, fmt.Errorf("check feed url: %w", err)
-	} else if existing != nil {
+	}
+	if existing != nil && existing.DeletedAt == nil {
 		return *existing, false, nil // Feed already exists, not an error
 	}
 	if folderID != nil {
@@ -189,11 +316,21 @@ func (s *feedService) AddWithoutFetch(ctx context.Context, feedURL string, folde
 			return model.Feed{}, false, ErrInvalid
 		}
 	}
+	if existing != nil {
+		restored, err := s.resubscribe(ctx, *existing, folderID, titleOverride, feedType)
+		if err != nil {
+			return model.Feed{}, false, err
+		}
+		return restored, true, nil
+	}
 
 	finalTitle := strings.TrimSpace(titleOverride)
 	if finalTitle == "" {
 		finalTitle = trimmedURL
 	}
+	if folderID == nil {
+		folderID, _ = s.ruleFolder(ctx, trimmedURL, finalTitle, feedType)
+	}
 
 	feed := model.Feed{
 		FolderID: folderID,
@@ -204,14 +341,117 @@ func (s *feedService) AddWithoutFetch(ctx context.Context, feedURL string, folde
 
 	created, err := s.feeds.Create(ctx, feed)
 	if err != nil {
+		if errors.Is(err, repository.ErrConflict) {
+			var conflict *FeedConflictError
+			if errors.As(s.lostCreateRace(ctx, trimmedURL), &conflict) {
+				return conflict.ExistingFeed, false, nil
+			}
+		}
 		logger.Error("feed create without fetch failed", "module", "service", "action", "create", "resource", "feed", "result", "failed", "host", network.ExtractHost(trimmedURL), "error", err)
 		return model.Feed{}, false, err
 	}
 
 	logger.Info("feed created without fetch", "module", "service", "action", "create", "resource", "feed", "result", "ok", "feed_id", created.ID, "feed_title", created.Title, "host", network.ExtractHost(created.URL))
+	s.bus.Publish(events.Event{Type: events.FeedCreated, Data: events.FeedData{FeedID: created.ID}})
 	return created, true, nil
 }
 
+// lostCreateRace is the error for a subscription to url whose Create failed
+// with repository.ErrConflict: another request subscribed to the same URL
+// between the FindByURL check and the insert. It is a FeedConflictError with
+// the winning feed, as if the check had already seen it.
+func (s *feedService) lostCreateRace(ctx context.Context, url string) error {
+	existing, err := s.feeds.FindByURL(ctx, urlutil.CanonicalFeedURL(url))
+	if err != nil {
+		return fmt.Errorf("check feed url: %w", err)
+	}
+	if existing == nil || existing.DeletedAt != nil {
+		return ErrConflict
+	}
+	logger.Info("feed subscribed concurrently", "module", "service", "action", "create", "resource", "feed", "result", "skipped", "feed_id", existing.ID, "host", network.ExtractHost(url))
+	return &FeedConflictError{ExistingFeed: *existing}
+}
+
+func (s *feedService) AddStatic(ctx context.Context, parsed *gofeed.Feed, folderID *int64, titleOverride string, feedType string) (model.Feed, error) {
+	if parsed == nil {
+		return model.Feed{}, ErrNotAFeed
+	}
+	if folderID != nil {
+		folder, err := s.folders.GetByID(ctx, *folderID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return model.Feed{}, ErrNotFound
+			}
+			return model.Feed{}, fmt.Errorf("check folder: %w", err)
+		}
+		if feedType == FeedTypeAuto {
+			feedType = folder.Type
+		}
+		if folder.Type != feedType {
+			logger.Warn("feed type mismatch with folder type", "module", "service", "action", "create", "resource", "feed", "result", "failed", "folder_id", *folderID, "folder_type", folder.Type, "feed_type", feedType)
+			return model.Feed{}, ErrInvalid
+		}
+	}
+	if feedType == FeedTypeAuto {
+		feedType = classifyFeedType(parsed.Items)
+	}
+
+	upstreamTitle := strings.TrimSpace(parsed.Title)
+	if upstreamTitle == "" {
+		upstreamTitle = strings.TrimSpace(titleOverride)
+	}
+	if upstreamTitle == "" {
+		return model.Feed{}, ErrInvalid
+	}
+
+	created, err := s.feeds.Create(ctx, model.Feed{
+		FolderID:    folderID,
+		Title:       upstreamTitle,
+		CustomTitle: customTitle(titleOverride, upstreamTitle),
+		URL:         StaticFeedURLPrefix + uuid.NewString(),
+		SiteURL:     optionalString(parsed.Link),
+		Description: optionalString(parsed.Description),
+		Type:        feedType,
+	})
+	if err != nil {
+		logger.Error("static feed create failed", "module", "service", "action", "create", "resource", "feed", "result", "failed", "error", err)
+		return model.Feed{}, err
+	}
+
+	logger.Info("static feed created", "module", "service", "action", "create", "resource", "feed", "result", "ok", "feed_id", created.ID, "feed_title", created.Title)
+	s.bus.Publish(events.Event{Type: events.FeedCreated, Data: events.FeedData{FeedID: created.ID}})
+	return created, nil
+}
+
+// resubscribe restores a soft-deleted feed with the same URL, keeping its entries,
+// and applies the folder, title and type of the new subscription.
+func (s *feedService) resubscribe(ctx context.Context, feed model.Feed, folderID *int64, titleOverride string, feedType string) (model.Feed, error) {
+	if err := s.feeds.Restore(ctx, feed.ID, time.Time{}); err != nil {
+		logger.Error("feed resubscribe failed", "module", "service", "action", "restore", "resource", "feed", "result", "failed", "feed_id", feed.ID, "error", err)
+		return model.Feed{}, err
+	}
+
+	feed.FolderID = folderID
+	feed.DeletedAt = nil
+	if strings.TrimSpace(titleOverride) != "" {
+		feed.CustomTitle = customTitle(titleOverride, feed.Title)
+	}
+	updated, err := s.feeds.Update(ctx, feed)
+	if err != nil {
+		return model.Feed{}, err
+	}
+	if feedType != FeedTypeAuto && feedType != "" && feedType != updated.Type {
+		if err := s.feeds.UpdateType(ctx, updated.ID, feedType); err != nil {
+			return model.Feed{}, err
+		}
+		updated.Type = feedType
+	}
+
+	logger.Info("feed resubscribed", "module", "service", "action", "restore", "resource", "feed", "result", "ok", "feed_id", updated.ID, "feed_title", updated.Title, "host", network.ExtractHost(updated.URL))
+	s.bus.Publish(events.Event{Type: events.FeedCreated, Data: events.FeedData{FeedID: updated.ID}})
+	return updated, nil
+}
+
 func (s *feedService) Preview(ctx context.Context, feedURL string) (FeedPreview, error) {
 	trimmedURL := strings.TrimSpace(feedURL)
 	if !isValidURL(trimmedURL) {
@@ -231,13 +471,14 @@ func (s *feedService) Preview(ctx context.Context, feedURL string) (FeedPreview,
 		title = trimmedURL
 	}
 	preview := FeedPreview{
-		URL:         trimmedURL,
-		Title:       title,
-		Description: optionalString(fetched.description),
-		SiteURL:     optionalString(fetched.siteURL),
-		ImageURL:    optionalString(fetched.imageURL),
-		ItemCount:   fetched.itemCount,
-		LastUpdated: optionalString(fetched.lastUpdated),
+		URL:           trimmedURL,
+		Title:         title,
+		Description:   optionalString(fetched.description),
+		SiteURL:       optionalString(fetched.siteURL),
+		ImageURL:      optionalString(fetched.imageURL),
+		ItemCount:     fetched.itemCount,
+		LastUpdated:   optionalString(fetched.lastUpdated),
+		SuggestedType: classifyFeedType(fetched.items),
 	}
 
 	return preview, nil
@@ -252,65 +493,42 @@ func (s *feedService) List(ctx context.Context, folderID *int64) ([]model.Feed,
 	return feeds, nil
 }
 
-func (s *feedService) Update(ctx context.Context, id int64, title string, folderID *int64, summaryPromptReminder *string) (model.Feed, error) {
-	trimmedTitle := strings.TrimSpace(title)
-	if trimmedTitle == "" {
-		return model.Feed{}, ErrInvalid
-	}
-
-	var normalizedReminder *string
-	var err error
-	if summaryPromptReminder != nil {
-		normalizedReminder, err = normalizeSummaryPromptReminder(*summaryPromptReminder)
-		if err != nil {
-			return model.Feed{}, err
-		}
-	}
-
-	feed, err := s.feeds.GetByID(ctx, id)
+func (s *feedService) ListWithUnreadCounts(ctx context.Context) ([]model.FeedWithUnread, error) {
+	feeds, err := s.feeds.ListWithUnreadCounts(ctx)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return model.Feed{}, ErrNotFound
-		}
-		return model.Feed{}, fmt.Errorf("get feed: %w", err)
+		logger.Error("feed list with unread counts failed", "module", "service", "action", "list", "resource", "feed", "result", "failed", "error", err)
+		return nil, err
 	}
+	return feeds, nil
+}
 
-	// Check if folder is actually changing (value comparison, not pointer comparison)
-	folderChanged := false
-	if folderID == nil && feed.FolderID != nil {
-		folderChanged = true // moving from folder to no folder
-	} else if folderID != nil && feed.FolderID == nil {
-		folderChanged = true // moving from no folder to folder
-	} else if folderID != nil && feed.FolderID != nil && *folderID != *feed.FolderID {
-		folderChanged = true // moving from one folder to another
+func (s *feedService) GetActivityStats(ctx context.Context) ([]model.FeedActivityStats, error) {
+	stats, err := s.feeds.GetActivityStats(ctx)
+	if err != nil {
+		logger.Error("feed activity stats failed", "module", "service", "action", "list", "resource", "feed", "result", "failed", "error", err)
+		return nil, err
 	}
+	return stats, nil
+}
 
-	if folderID != nil && folderChanged {
-		folder, err := s.folders.GetByID(ctx, *folderID)
-		if err != nil {
-			if errors.Is(err, sql.ErrNoRows) {
-				return model.Feed{}, ErrNotFound
-			}
-			return model.Feed{}, fmt.Errorf("check folder: %w", err)
-		}
-		if folder.Type != feed.Type {
-			logger.Warn("feed type mismatch with folder type", "module", "service", "action", "update", "resource", "feed", "result", "failed", "feed_id", id, "folder_id", *folderID, "folder_type", folder.Type, "feed_type", feed.Type)
-			return model.Feed{}, ErrInvalid
-		}
-	}
-	feed.Title = trimmedTitle
-	feed.FolderID = folderID
-	if summaryPromptReminder != nil {
-		feed.SummaryPromptReminder = normalizedReminder
-	}
+func (s *feedService) Update(ctx context.Context, id int64, title string, folderID *int64, summaryPromptReminder *string, maxEntries *int) (model.Feed, error) {
+	return s.Patch(ctx, id, FeedPatch{
+		Title:                 &title,
+		FolderID:              folderID,
+		ClearFolder:           folderID == nil,
+		SummaryPromptReminder: summaryPromptReminder,
+		MaxEntries:            maxEntries,
+	})
+}
 
-	updated, err := s.feeds.Update(ctx, feed)
-	if err != nil {
-		logger.Error("feed update failed", "module", "service", "action", "update", "resource", "feed", "result", "failed", "feed_id", id, "error", err)
-		return model.Feed{}, err
+// customTitle returns title as a rename of a feed titled upstream, or nil when
+// title is empty or the same.
+func customTitle(title, upstream string) *string {
+	trimmed := strings.TrimSpace(title)
+	if trimmed == "" || trimmed == upstream {
+		return nil
 	}
-	logger.Info("feed updated", "module", "service", "action", "update", "resource", "feed", "result", "ok", "feed_id", updated.ID, "feed_title", updated.Title)
-	return updated, nil
+	return &trimmed
 }
 
 func normalizeSummaryPromptReminder(raw string) (*string, error) {
@@ -336,24 +554,143 @@ func (s *feedService) Delete(ctx context.Context, id int64) error {
 		return err
 	}
 	logger.Info("feed deleted", "module", "service", "action", "delete", "resource", "feed", "result", "ok", "feed_id", id)
+	s.bus.Publish(events.Event{Type: events.FeedDeleted, Data: events.FeedData{FeedID: id}})
 	return nil
 }
 
 func (s *feedService) UpdateType(ctx context.Context, id int64, feedType string) error {
+	_, err := s.Patch(ctx, id, FeedPatch{Type: &feedType})
+	return err
+}
+
+func (s *feedService) UpdateAssumeTimezone(ctx context.Context, id int64, timezone *string) error {
+	_, err := s.Patch(ctx, id, FeedPatch{AssumeTimezone: clearedIfNil(timezone)})
+	return err
+}
+
+func (s *feedService) ListMutedAuthors(ctx context.Context, id int64) ([]string, error) {
 	if _, err := s.feeds.GetByID(ctx, id); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return ErrNotFound
+			return nil, ErrNotFound
 		}
-		return fmt.Errorf("get feed: %w", err)
+		return nil, fmt.Errorf("get feed: %w", err)
+	}
+	return s.feeds.ListMutedAuthors(ctx, id)
+}
+
+func (s *feedService) MuteAuthor(ctx context.Context, id int64, author string) ([]string, error) {
+	author = strings.TrimSpace(author)
+	if author == "" {
+		return nil, ErrInvalid
+	}
+	if _, err := s.feeds.GetByID(ctx, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("get feed: %w", err)
+	}
+	if err := s.feeds.MuteAuthor(ctx, id, author); err != nil {
+		logger.Error("feed mute author failed", "module", "service", "action", "update", "resource", "feed", "result", "failed", "feed_id", id, "error", err)
+		return nil, err
+	}
+	logger.Info("feed author muted", "module", "service", "action", "update", "resource", "feed", "result", "ok", "feed_id", id)
+	s.bus.Publish(events.Event{Type: events.FeedUpdated, Data: events.FeedData{FeedID: id}})
+	return s.feeds.ListMutedAuthors(ctx, id)
+}
+
+func (s *feedService) UnmuteAuthor(ctx context.Context, id int64, author string) ([]string, error) {
+	author = strings.TrimSpace(author)
+	if author == "" {
+		return nil, ErrInvalid
+	}
+	if err := s.feeds.UnmuteAuthor(ctx, id, author); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		logger.Error("feed unmute author failed", "module", "service", "action", "update", "resource", "feed", "result", "failed", "feed_id", id, "error", err)
+		return nil, err
 	}
-	if err := s.feeds.UpdateType(ctx, id, feedType); err != nil {
-		logger.Error("feed update type failed", "module", "service", "action", "update", "resource", "feed", "result", "failed", "feed_id", id, "type", feedType, "error", err)
+	logger.Info("feed author unmuted", "module", "service", "action", "update", "resource", "feed", "result", "ok", "feed_id", id)
+	s.bus.Publish(events.Event{Type: events.FeedUpdated, Data: events.FeedData{FeedID: id}})
+	return s.feeds.ListMutedAuthors(ctx, id)
+}
+
+func (s *feedService) Reorder(ctx context.Context, ids []int64) error {
+	if len(ids) == 0 || hasDuplicateIDs(ids) {
+		return ErrInvalid
+	}
+	feeds, err := s.feeds.GetByIDs(ctx, ids)
+	if err != nil {
+		return fmt.Errorf("get feeds: %w", err)
+	}
+	if len(feeds) != len(ids) {
+		return ErrNotFound
+	}
+	for _, feed := range feeds[1:] {
+		if !sameID(feeds[0].FolderID, feed.FolderID) {
+			return ErrInvalid
+		}
+	}
+
+	if err := s.feeds.Reorder(ctx, ids); err != nil {
+		logger.Error("feed reorder failed", "module", "service", "action", "update", "resource", "feed", "result", "failed", "count", len(ids), "error", err)
 		return err
 	}
-	logger.Info("feed type updated", "module", "service", "action", "update", "resource", "feed", "result", "ok", "feed_id", id, "type", feedType)
+	logger.Info("feeds reordered", "module", "service", "action", "update", "resource", "feed", "result", "ok", "count", len(ids))
 	return nil
 }
 
+func (s *feedService) UpdateDedupeKey(ctx context.Context, id int64, dedupeKey string) error {
+	_, err := s.Patch(ctx, id, FeedPatch{DedupeKey: &dedupeKey})
+	return err
+}
+
+func isValidDedupeKey(dedupeKey string) bool {
+	switch dedupeKey {
+	case model.DedupeKeyAuto, model.DedupeKeyGUID, model.DedupeKeyURL, model.DedupeKeyTitleContent:
+		return true
+	}
+	return false
+}
+
+func (s *feedService) UpdateAutoTranslate(ctx context.Context, id int64, mode string) error {
+	_, err := s.Patch(ctx, id, FeedPatch{AutoTranslate: &mode})
+	return err
+}
+
+func (s *feedService) UpdateUserAgent(ctx context.Context, id int64, userAgent *string) error {
+	_, err := s.Patch(ctx, id, FeedPatch{UserAgent: clearedIfNil(userAgent)})
+	return err
+}
+
+func (s *feedService) UpdateAutoReadability(ctx context.Context, id int64, enabled *bool) error {
+	_, err := s.Patch(ctx, id, FeedPatch{AutoReadability: enabled, InheritAutoReadability: enabled == nil})
+	return err
+}
+
+func (s *feedService) EffectiveConfigs(ctx context.Context, feeds []model.Feed) (map[int64]FeedConfig, error) {
+	configs, err := effectiveFeedConfigs(ctx, s.folders, feeds)
+	if err != nil {
+		return nil, fmt.Errorf("list folders: %w", err)
+	}
+	return configs, nil
+}
+
+func (s *feedService) Pause(ctx context.Context, id int64, until time.Time) error {
+	_, err := s.Patch(ctx, id, FeedPatch{PausedUntil: &until})
+	return err
+}
+
+func (s *feedService) Unpause(ctx context.Context, id int64) error {
+	_, err := s.Patch(ctx, id, FeedPatch{Unpause: true})
+	return err
+}
+
+// isPaused reports whether a feed is paused at now.
+func isPaused(feed model.Feed, now time.Time) bool {
+	return feed.PausedUntil != nil && feed.PausedUntil.After(now)
+}
+
 func (s *feedService) DeleteBatch(ctx context.Context, ids []int64) error {
 	if len(ids) == 0 {
 		return nil
@@ -369,14 +706,69 @@ func (s *feedService) DeleteBatch(ctx context.Context, ids []int64) error {
 		return ErrNotFound
 	}
 	logger.Info("feed batch deleted", "module", "service", "action", "delete", "resource", "feed", "result", "ok", "count", len(ids))
+	for _, id := range ids {
+		s.bus.Publish(events.Event{Type: events.FeedDeleted, Data: events.FeedData{FeedID: id}})
+	}
+	return nil
+}
+
+func (s *feedService) Restore(ctx context.Context, id int64) (model.Feed, error) {
+	if err := s.feeds.Restore(ctx, id, time.Now().Add(-DeleteRetention)); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Feed{}, ErrNotFound
+		}
+		logger.Error("feed restore failed", "module", "service", "action", "restore", "resource", "feed", "result", "failed", "feed_id", id, "error", err)
+		return model.Feed{}, err
+	}
+	logger.Info("feed restored", "module", "service", "action", "restore", "resource", "feed", "result", "ok", "feed_id", id)
+	s.bus.Publish(events.Event{Type: events.FeedCreated, Data: events.FeedData{FeedID: id}})
+	return s.feeds.GetByID(ctx, id)
+}
+
+func (s *feedService) PurgeDeleted(ctx context.Context) error {
+	cutoff := time.Now().Add(-DeleteRetention)
+	// Feeds first so their entries cascade before the folders they pointed at go away
+	feeds, err := s.feeds.PurgeDeleted(ctx, cutoff)
+	if err != nil {
+		logger.Error("feed purge failed", "module", "service", "action", "delete", "resource", "feed", "result", "failed", "error", err)
+		return err
+	}
+	folders, err := s.folders.PurgeDeleted(ctx, cutoff)
+	if err != nil {
+		logger.Error("folder purge failed", "module", "service", "action", "delete", "resource", "folder", "result", "failed", "error", err)
+		return err
+	}
+	if feeds > 0 || folders > 0 {
+		logger.Info("deleted feeds purged", "module", "service", "action", "delete", "resource", "feed", "result", "ok", "feeds", feeds, "folders", folders)
+	}
+
+	rawItems, err := s.entries.DeleteRawItemsBefore(ctx, time.Now().Add(-RawItemRetention))
+	if err != nil {
+		logger.Error("raw item purge failed", "module", "service", "action", "delete", "resource", "entry", "result", "failed", "error", err)
+		return err
+	}
+	if rawItems > 0 {
+		logger.Info("raw items purged", "module", "service", "action", "delete", "resource", "entry", "result", "ok", "count", rawItems)
+	}
+
+	tombstones, err := s.entries.DeleteTombstonesBefore(ctx, time.Now().Add(-EntryTombstoneRetention))
+	if err != nil {
+		logger.Error("entry tombstone purge failed", "module", "service", "action", "delete", "resource", "entry", "result", "failed", "error", err)
+		return err
+	}
+	if tombstones > 0 {
+		logger.Info("entry tombstones purged", "module", "service", "action", "delete", "resource", "entry", "result", "ok", "count", tombstones)
+	}
 	return nil
 }
 
 type feedFetch struct {
-	title        string
-	description  string
-	siteURL      string
-	imageURL     string
+	title       string
+	description string
+	siteURL     string
+	imageURL    string
+	// language is the primary subtag of the declared language, "" for none.
+	language     string
 	lastUpdated  string
 	itemCount    *int
 	etag         string
@@ -389,90 +781,56 @@ func (s *feedService) fetchFeed(ctx context.Context, feedURL string) (feedFetch,
 }
 
 func (s *feedService) fetchFeedWithUA(ctx context.Context, feedURL string, userAgent string, allowFallback bool) (feedFetch, error) {
-	return s.fetchFeedWithCookie(ctx, feedURL, userAgent, "", allowFallback, 0)
-}
-
-func (s *feedService) fetchFeedWithCookie(ctx context.Context, feedURL string, userAgent string, cookie string, allowFallback bool, retryCount int) (feedFetch, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, feedURL, nil)
-	if err != nil {
-		return feedFetch{}, ErrFeedFetch
-	}
-	req.Header.Set("User-Agent", userAgent)
-
-	// Add cached Anubis cookie if available
-	if cookie == "" {
-		host := network.ExtractHost(feedURL)
-		if cachedCookie := getCachedAnubisCookie(ctx, s.anubis, host, req.Header); cachedCookie != "" {
-			cookie = cachedCookie
+	host := network.ExtractHost(feedURL)
+	attempt, retryCount, err := fetchPastAnubis(ctx, s.anubis, feedURL, anubisRetryOptionsFrom(ctx, s.settings), func(cookie string, solved bool) (anubisAttempt, error) {
+		attempt, err := s.getFeed(ctx, feedURL, userAgent, cookie)
+		if err != nil {
+			return anubisAttempt{}, err
 		}
-	}
-
-	if cookie != "" {
-		req.Header.Set("Cookie", cookie)
-	}
 
-	httpClient := s.clientFactory.NewHTTPClient(ctx, feedTimeout)
-	resp, err := httpClient.Do(req)
-	if err != nil {
-		logger.Warn("feed preview fetch failed", "module", "service", "action", "fetch", "resource", "feed", "result", "failed", "host", network.ExtractHost(feedURL), "error", err)
-		return feedFetch{}, ErrFeedFetch
-	}
-	defer resp.Body.Close()
-
-	// On HTTP error, try fallback UA if available
-	if resp.StatusCode >= http.StatusBadRequest && allowFallback && s.settings != nil {
-		fallbackUA := s.settings.GetFallbackUserAgent(ctx)
-		if fallbackUA != "" {
-			logger.Warn("feed preview retry with fallback ua", "module", "service", "action", "fetch", "resource", "feed", "result", "failed", "host", network.ExtractHost(feedURL), "status_code", resp.StatusCode)
-			return s.fetchFeedWithCookie(ctx, feedURL, fallbackUA, cookie, false, retryCount)
+		// On HTTP error, try fallback UA if available. Not after a solve: the
+		// solved cookie is scoped to the UA it was solved with
+		if attempt.statusCode >= http.StatusBadRequest && !solved && allowFallback && s.settings != nil {
+			if fallbackUA := s.settings.GetFallbackUserAgent(ctx); fallbackUA != "" {
+				logger.Warn("feed preview retry with fallback ua", "module", "service", "action", "fetch", "resource", "feed", "result", "failed", "host", host, "status_code", attempt.statusCode)
+				userAgent = fallbackUA
+				if attempt, err = s.getFeed(ctx, feedURL, userAgent, cookie); err != nil {
+					return anubisAttempt{}, err
+				}
+			}
 		}
-	}
-
-	if resp.StatusCode >= http.StatusBadRequest {
-		logger.Error("feed preview http error", "module", "service", "action", "fetch", "resource", "feed", "result", "failed", "host", network.ExtractHost(feedURL), "status_code", resp.StatusCode)
-		return feedFetch{}, ErrFeedFetch
-	}
 
-	// Read body into memory for Anubis detection and RSS parsing
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		logger.Warn("feed preview read failed", "module", "service", "action", "fetch", "resource", "feed", "result", "failed", "host", network.ExtractHost(feedURL), "error", err)
+		if attempt.statusCode >= http.StatusBadRequest {
+			logger.Error("feed preview http error", "module", "service", "action", "fetch", "resource", "feed", "result", "failed", "host", host, "status_code", attempt.statusCode)
+			return anubisAttempt{}, ErrFeedFetch
+		}
+		return attempt, nil
+	})
+	switch {
+	case err == nil:
+	case errors.Is(err, ErrFeedFetch):
+		return feedFetch{}, err
+	case errors.Is(err, ErrAnubisRejected):
+		logger.Warn("feed preview upstream rejected", "module", "service", "action", "fetch", "resource", "feed", "result", "failed", "host", host, "error", err)
+		return feedFetch{}, ErrAnubisRejected
+	case errors.Is(err, errAnubisRetryExceeded):
+		logger.Warn("feed preview anubis persists", "module", "service", "action", "fetch", "resource", "feed", "result", "failed", "host", host, "retry_count", retryCount)
+		return feedFetch{}, err
+	default:
+		logger.Warn("feed preview anubis solve failed", "module", "service", "action", "fetch", "resource", "feed", "result", "failed", "host", host, "error", err)
 		return feedFetch{}, ErrFeedFetch
 	}
 
-	// Try to parse as RSS/Atom
-	parser := gofeed.NewParser()
-	parsed, parseErr := parser.Parse(bytes.NewReader(body))
+	parsed, parseErr := parseFeed(attempt.body)
 	if parseErr != nil {
-		newCookie, anubisErr := trySolveAnubisChallenge(ctx, s.anubis, body, feedURL, resp.Cookies(), req.Header.Clone(), retryCount)
-		switch {
-		case anubisErr == nil:
-			// Retry with fresh client and same request fingerprint.
-			return s.fetchFeedWithFreshClient(ctx, feedURL, userAgent, newCookie, retryCount+1)
-		case errors.Is(anubisErr, errAnubisNotPage):
-			// Not an Anubis page; keep original parse error handling.
-		case errors.Is(anubisErr, errAnubisRejected):
-			logger.Warn("feed preview upstream rejected", "module", "service", "action", "fetch", "resource", "feed", "result", "failed", "host", network.ExtractHost(feedURL))
-			return feedFetch{}, fmt.Errorf("upstream rejected")
-		case errors.Is(anubisErr, errAnubisRetryExceeded):
-			logger.Warn("feed preview anubis persists", "module", "service", "action", "fetch", "resource", "feed", "result", "failed", "host", network.ExtractHost(feedURL), "retry_count", retryCount)
-			return feedFetch{}, fmt.Errorf("anubis challenge persists after %d retries", retryCount)
-		default:
-			logger.Warn("feed preview anubis solve failed", "module", "service", "action", "fetch", "resource", "feed", "result", "failed", "host", network.ExtractHost(feedURL), "error", anubisErr)
-			return feedFetch{}, ErrFeedFetch
-		}
-		logger.Error("feed preview parse failed", "module", "service", "action", "fetch", "resource", "feed", "result", "failed", "host", network.ExtractHost(feedURL), "error", parseErr)
+		logger.Error("feed preview parse failed", "module", "service", "action", "fetch", "resource", "feed", "result", "failed", "host", host, "error", parseErr)
 		return feedFetch{}, ErrFeedFetch
-
 	}
 
 	title := strings.TrimSpace(parsed.Title)
 	description := strings.TrimSpace(parsed.Description)
 	siteURL := strings.TrimSpace(parsed.Link)
-	imageURL := ""
-	if parsed.Image != nil {
-		imageURL = strings.TrimSpace(parsed.Image.URL)
-	}
+	imageURL := feedIconURL(parsed)
 	lastUpdated := ""
 	if parsed.UpdatedParsed != nil {
 		lastUpdated = parsed.UpdatedParsed.UTC().Format(time.RFC3339)
@@ -485,14 +843,15 @@ func (s *feedService) fetchFeedWithCookie(ctx context.Context, feedURL string, u
 		itemCount = &count
 	}
 
-	etag := strings.TrimSpace(resp.Header.Get("ETag"))
-	lastModified := strings.TrimSpace(resp.Header.Get("Last-Modified"))
+	etag := strings.TrimSpace(attempt.resp.Header.Get("ETag"))
+	lastModified := strings.TrimSpace(attempt.resp.Header.Get("Last-Modified"))
 
 	return feedFetch{
 		title:        title,
 		description:  description,
 		siteURL:      siteURL,
 		imageURL:     imageURL,
+		language:     langdetect.Normalize(parsed.Language),
 		lastUpdated:  lastUpdated,
 		itemCount:    itemCount,
 		etag:         etag,
@@ -501,94 +860,37 @@ func (s *feedService) fetchFeedWithCookie(ctx context.Context, feedURL string, u
 	}, nil
 }
 
-// fetchFeedWithFreshClient creates a new http.Client to avoid connection reuse after Anubis
-func (s *feedService) fetchFeedWithFreshClient(ctx context.Context, feedURL string, userAgent string, cookie string, retryCount int) (feedFetch, error) {
+// getFeed performs one GET of feedURL on a new client. Without a cookie it
+// sends the Anubis cookie cached for this host and UA, if any.
+func (s *feedService) getFeed(ctx context.Context, feedURL string, userAgent string, cookie string) (anubisAttempt, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, feedURL, nil)
 	if err != nil {
-		return feedFetch{}, ErrFeedFetch
+		return anubisAttempt{}, ErrFeedFetch
+	}
+	req.Header.Set("User-Agent", userAgentHeader(userAgent))
+
+	if cookie == "" {
+		cookie = getCachedAnubisCookie(ctx, s.anubis, network.ExtractHost(feedURL), req.Header)
 	}
-	req.Header.Set("User-Agent", userAgent)
 	if cookie != "" {
 		req.Header.Set("Cookie", cookie)
 	}
 
-	// Use fresh client to avoid connection reuse
-	freshClient := s.clientFactory.NewHTTPClient(ctx, feedTimeout)
-	resp, err := freshClient.Do(req)
+	httpClient := s.clientFactory.NewHTTPClient(ctx, feedTimeout)
+	resp, err := httpClient.Do(req)
 	if err != nil {
 		logger.Warn("feed preview fetch failed", "module", "service", "action", "fetch", "resource", "feed", "result", "failed", "host", network.ExtractHost(feedURL), "error", err)
-		return feedFetch{}, ErrFeedFetch
+		return anubisAttempt{}, ErrFeedFetch
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode >= http.StatusBadRequest {
-		logger.Error("feed preview http error", "module", "service", "action", "fetch", "resource", "feed", "result", "failed", "host", network.ExtractHost(feedURL), "status_code", resp.StatusCode)
-		return feedFetch{}, ErrFeedFetch
-	}
-
+	// Read body into memory for Anubis detection and RSS parsing
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		logger.Warn("feed preview read failed", "module", "service", "action", "fetch", "resource", "feed", "result", "failed", "host", network.ExtractHost(feedURL), "error", err)
-		return feedFetch{}, ErrFeedFetch
-	}
-
-	newCookie, anubisErr := trySolveAnubisChallenge(ctx, s.anubis, body, feedURL, resp.Cookies(), req.Header.Clone(), retryCount)
-	switch {
-	case anubisErr == nil:
-		return s.fetchFeedWithFreshClient(ctx, feedURL, userAgent, newCookie, retryCount+1)
-	case errors.Is(anubisErr, errAnubisNotPage):
-		// Not an Anubis page; continue normal parsing.
-	case errors.Is(anubisErr, errAnubisRejected):
-		logger.Warn("feed preview upstream rejected", "module", "service", "action", "fetch", "resource", "feed", "result", "failed", "host", network.ExtractHost(feedURL))
-		return feedFetch{}, fmt.Errorf("upstream rejected")
-	case errors.Is(anubisErr, errAnubisRetryExceeded):
-		logger.Warn("feed preview anubis persists", "module", "service", "action", "fetch", "resource", "feed", "result", "failed", "host", network.ExtractHost(feedURL), "retry_count", retryCount)
-		return feedFetch{}, fmt.Errorf("anubis challenge persists after %d retries", retryCount)
-	default:
-		logger.Warn("feed preview anubis solve failed", "module", "service", "action", "fetch", "resource", "feed", "result", "failed", "host", network.ExtractHost(feedURL), "error", anubisErr)
-		return feedFetch{}, ErrFeedFetch
-	}
-
-	parser := gofeed.NewParser()
-	parsed, parseErr := parser.Parse(bytes.NewReader(body))
-	if parseErr != nil {
-		logger.Error("feed preview parse failed", "module", "service", "action", "fetch", "resource", "feed", "result", "failed", "host", network.ExtractHost(feedURL), "error", parseErr)
-		return feedFetch{}, ErrFeedFetch
-	}
-
-	title := strings.TrimSpace(parsed.Title)
-	description := strings.TrimSpace(parsed.Description)
-	siteURL := strings.TrimSpace(parsed.Link)
-	imageURL := ""
-	if parsed.Image != nil {
-		imageURL = strings.TrimSpace(parsed.Image.URL)
-	}
-	lastUpdated := ""
-	if parsed.UpdatedParsed != nil {
-		lastUpdated = parsed.UpdatedParsed.UTC().Format(time.RFC3339)
-	} else if parsed.PublishedParsed != nil {
-		lastUpdated = parsed.PublishedParsed.UTC().Format(time.RFC3339)
-	}
-	var itemCount *int
-	if parsed.Items != nil {
-		count := len(parsed.Items)
-		itemCount = &count
+		return anubisAttempt{}, ErrFeedFetch
 	}
-
-	etag := strings.TrimSpace(resp.Header.Get("ETag"))
-	lastModified := strings.TrimSpace(resp.Header.Get("Last-Modified"))
-
-	return feedFetch{
-		title:        title,
-		description:  description,
-		siteURL:      siteURL,
-		imageURL:     imageURL,
-		lastUpdated:  lastUpdated,
-		itemCount:    itemCount,
-		etag:         etag,
-		lastModified: lastModified,
-		items:        parsed.Items,
-	}, nil
+	return newHTTPAnubisAttempt(resp, body, req.Header.Clone()), nil
 }
 
 // hasDynamicTime checks if all items have the same updated time (dynamic generation)
@@ -609,15 +911,29 @@ func hasDynamicTime(items []*gofeed.Item) bool {
 	return firstTime != nil
 }
 
-func itemToEntry(feedID int64, item *gofeed.Item, ignoreDynamicTime bool) model.Entry {
+// itemToEntry converts a feed item; loc is assumed for item dates without a zone.
+// Entry title/author length limits in runes, set from config at startup.
+var (
+	MaxEntryTitleLength  = 500
+	MaxEntryAuthorLength = 200
+)
+
+func itemToEntry(feed model.Feed, item *gofeed.Item, ignoreDynamicTime bool, loc *time.Location) model.Entry {
+	entry, _ := itemToDatedEntry(feed, item, ignoreDynamicTime, loc)
+	return entry
+}
+
+// itemToDatedEntry is itemToEntry also returning how the entry was dated.
+func itemToDatedEntry(feed model.Feed, item *gofeed.Item, ignoreDynamicTime bool, loc *time.Location) (model.Entry, publishedDate) {
 	entry := model.Entry{
-		FeedID: feedID,
+		FeedID: feed.ID,
 	}
 
-	var title string
-	if item.Title != "" {
-		title = strings.TrimSpace(item.Title)
-		entry.Title = &title
+	// The hash keeps the raw title so existing entries keep their identity
+	// whatever the cleanup or limits below do
+	title := strings.TrimSpace(item.Title)
+	if cleaned := sanitizer.Truncate(sanitizer.CleanText(title), MaxEntryTitleLength); cleaned != "" {
+		entry.Title = &cleaned
 	}
 
 	var link string
@@ -633,68 +949,207 @@ func itemToEntry(feedID int64, item *gofeed.Item, ignoreDynamicTime bool) model.
 	content = strings.TrimSpace(content)
 	if content != "" {
 		entry.Content = &content
+		entry.WordCount = readtime.WordCount(content)
 	}
 
 	// Extract thumbnail from media tags
 	entry.ThumbnailURL = extractThumbnail(item)
 
 	if item.Author != nil && item.Author.Name != "" {
-		author := sanitizer.SanitizeAuthor(item.Author.Name)
+		author := sanitizer.Truncate(sanitizer.CleanText(sanitizer.SanitizeAuthor(item.Author.Name)), MaxEntryAuthorLength)
 		if author != "" {
 			entry.Author = &author
 		}
 	}
 
-	entry.PublishedAt = extractPublishedAt(item, ignoreDynamicTime)
-	entry.Hash = computeEntryHash(item, title, content)
+	published := resolvePublishedAt(item, ignoreDynamicTime, loc, time.Now().UTC())
+	entry.PublishedAt = &published.At
+	entry.PublishedAtSource = published.Source
+	entry.Hash = computeEntryHash(item, title, content, ignoreDynamicTime, feed.DedupeKey)
 
-	return entry
+	entry.Language = feed.Language
+	if entry.Language == "" {
+		entry.Language = detectEntryLanguage(title, content)
+	}
+
+	return entry, published
+}
+
+// detectEntryLanguage guesses the language from the title and the start of the
+// content, for feeds that declare none.
+func detectEntryLanguage(title, content string) string {
+	text := sanitizer.StripTags(content)
+	if utf8.RuneCountInString(text) > languageDetectRunes {
+		text = string([]rune(text)[:languageDetectRunes])
+	}
+	return langdetect.Detect(title + "\n" + text)
 }
 
-func computeEntryHash(item *gofeed.Item, title string, content string) string {
-	if guid := strings.TrimSpace(item.GUID); guid != "" {
+// computeEntryHash derives entry identity as dedupeKey says. auto uses GUID, then
+// link, then title+content; guid and url fall back to title+content.
+// Links are unwrapped from tracking redirects first. Dynamic feeds (see hasDynamicTime)
+// regenerate links on every fetch, e.g. v2ex appending #replyN, so their links are
+// hashed in normalized form, as are all links under url.
+//
+// Under auto the GUID (the id of Atom entries) is only trusted as is when it
+// can't vary between fetches. Opaque ids such as tag: URIs, yt:video: ids or
+// guids marked isPermaLink="false" always qualify. A URL with a fragment or
+// tracking parameters does not, and the normalized link is hashed instead;
+// a URL equal to the link is hashed the same way as the link.
+func computeEntryHash(item *gofeed.Item, title string, content string, normalizeLink bool, dedupeKey string) string {
+	guid := strings.TrimSpace(item.GUID)
+	link := urlutil.UnwrapRedirect(item.Link)
+	switch dedupeKey {
+	case model.DedupeKeyTitleContent:
+		return contentHash(title, content)
+	case model.DedupeKeyGUID:
+		if guid != "" {
+			return hashToHex(guid)
+		}
+		return contentHash(title, content)
+	case model.DedupeKeyURL:
+		if link != "" {
+			return hashToHex(urlutil.NormalizeForHash(link))
+		}
+		return contentHash(title, content)
+	}
+
+	switch {
+	case guid == "":
+	case !isURLGUID(item, guid):
 		return hashToHex(guid)
+	case urlutil.HasVolatileParts(guid):
+		if link == "" {
+			link = urlutil.UnwrapRedirect(guid)
+		}
+		normalizeLink = true
+	case guid == strings.TrimSpace(item.Link):
+	default:
+		return hashToHex(guid)
+	}
+	if normalizeLink {
+		link = urlutil.NormalizeForHash(link)
 	}
-	if link := strings.TrimSpace(item.Link); link != "" {
+	if link != "" {
 		return hashToHex(link)
 	}
+	return contentHash(title, content)
+}
+
+// isURLGUID reports whether guid is an http(s) URL not declared opaque with isPermaLink="false".
+func isURLGUID(item *gofeed.Item, guid string) bool {
+	if item.Custom[guidPermaLinkKey] == "false" {
+		return false
+	}
+	parsed, err := url.Parse(guid)
+	if err != nil || parsed.Host == "" {
+		return false
+	}
+	scheme := strings.ToLower(parsed.Scheme)
+	return scheme == "http" || scheme == "https"
+}
+
+func contentHash(title string, content string) string {
 	return hashToHex(strings.TrimSpace(title) + strings.TrimSpace(content))
 }
 
+// storedEntryHash returns the hash for dedupeKey computed from stored entry fields,
+// or nil when it needs the GUID, which is not stored.
+func storedEntryHash(dedupeKey string) func(link, title, content string) string {
+	switch dedupeKey {
+	case model.DedupeKeyURL, model.DedupeKeyTitleContent:
+		return func(link, title, content string) string {
+			return computeEntryHash(&gofeed.Item{Link: link}, title, content, false, dedupeKey)
+		}
+	}
+	return nil
+}
+
 func hashToHex(input string) string {
 	return hashutil.SHA256Hex(input)
 }
 
-func extractPublishedAt(item *gofeed.Item, ignoreDynamicTime bool) *time.Time {
+// Item dates outside these bounds are taken for broken: dates before
+// minPublishedAt (the Unix epoch among them) are ignored, dates further than
+// maxPublishedAtSkew ahead of the fetch are clamped to it.
+var minPublishedAt = time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+
+const maxPublishedAtSkew = 24 * time.Hour
+
+// publishedDate is the date an entry is saved with.
+type publishedDate struct {
+	At time.Time
+	// Source is one of the model.PublishedAtFrom* values.
+	Source string
+	// Rejected is the implausible item date passed over, if any.
+	Rejected *time.Time
+}
+
+// extractPublishedAt returns the item date in UTC, reading dates without a zone in loc.
+func extractPublishedAt(item *gofeed.Item, ignoreDynamicTime bool, loc *time.Location) *time.Time {
+	date := resolvePublishedAt(item, ignoreDynamicTime, loc, time.Now().UTC())
+	return &date.At
+}
+
+// resolvePublishedAt picks the date of item fetched at now: the date in its
+// summary, else its published date, else its updated date unless the feed
+// regenerates those (see hasDynamicTime), else now. Dates before
+// minPublishedAt count as missing; a date more than maxPublishedAtSkew ahead
+// of now is clamped to now.
+func resolvePublishedAt(item *gofeed.Item, ignoreDynamicTime bool, loc *time.Location, now time.Time) publishedDate {
+	var rejected *time.Time
+	candidate := func(t *time.Time, source string) (publishedDate, bool) {
+		if t == nil {
+			return publishedDate{}, false
+		}
+		if t.Before(minPublishedAt) {
+			if rejected == nil {
+				rejected = t
+			}
+			return publishedDate{}, false
+		}
+		if t.After(now.Add(maxPublishedAtSkew)) {
+			logger.Debug("entry date clamped", "module", "service", "action", "fetch", "resource", "entry", "result", "ok", "host", network.ExtractHost(item.Link), "published_at", t.Format(time.RFC3339))
+			return publishedDate{At: now, Source: model.PublishedAtFromFetch, Rejected: t}, true
+		}
+		return publishedDate{At: *t, Source: source, Rejected: rejected}, true
+	}
+
 	// 1. Try to extract from summary (SEC RSS: "Filed: 2025-12-17")
-	if t := extractDateFromSummary(item.Description); t != nil {
-		return t
+	if date, ok := candidate(extractDateFromSummary(item.Description, loc), model.PublishedAtFromSummary); ok {
+		return date
 	}
 
 	// 2. Try standard fields
 	if item.PublishedParsed != nil {
-		t := item.PublishedParsed.UTC()
-		return &t
+		t := inAssumedZone(*item.PublishedParsed, item.Published, loc)
+		if date, ok := candidate(&t, model.PublishedAtFromFeed); ok {
+			return date
+		}
 	}
 	if !ignoreDynamicTime && item.UpdatedParsed != nil {
-		t := item.UpdatedParsed.UTC()
-		return &t
+		t := inAssumedZone(*item.UpdatedParsed, item.Updated, loc)
+		if date, ok := candidate(&t, model.PublishedAtFromFeed); ok {
+			return date
+		}
 	}
 
 	// Fallback to current time when no date is available
-	now := time.Now().UTC()
-	return &now
+	return publishedDate{At: now, Source: model.PublishedAtFromFetch, Rejected: rejected}
 }
 
 var filedDateRegex = regexp.MustCompile(`Filed:.*?(\d{4}-\d{2}-\d{2})`)
 
-func extractDateFromSummary(summary string) *time.Time {
+func extractDateFromSummary(summary string, loc *time.Location) *time.Time {
 	if summary == "" {
 		return nil
 	}
+	if loc == nil {
+		loc = time.UTC
+	}
 	matches := filedDateRegex.FindStringSubmatch(summary)
 	if len(matches) >= 2 {
-		if t, err := time.Parse("2006-01-02", matches[1]); err == nil {
+		if t, err := time.ParseInLocation("2006-01-02", matches[1], loc); err == nil {
 			utc := t.UTC()
 			return &utc
 		}
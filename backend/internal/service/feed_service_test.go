@@ -10,11 +10,15 @@ import (
 	"sync"
 	"testing"
 	"time"
+	"unicode/utf8"
 
 	"gist/backend/internal/config"
 	"gist/backend/internal/model"
+	"gist/backend/internal/repository"
 	"gist/backend/internal/repository/mock"
+	"gist/backend/internal/repository/testutil"
 	"gist/backend/internal/service"
+	"gist/backend/internal/service/anubis"
 	servicemock "gist/backend/internal/service/mock"
 	"gist/backend/pkg/network"
 
@@ -92,8 +96,8 @@ func TestFeedService_Add_Success(t *testing.T) {
 		},
 	)
 
-	mockEntries.EXPECT().CreateOrUpdate(gomock.Any(), gomock.Any()).DoAndReturn(
-		func(_ context.Context, entry model.Entry) error {
+	mockEntries.EXPECT().CreateOrUpdate(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, entry model.Entry, _ int) error {
 			require.Equal(t, int64(123), entry.FeedID)
 			require.NotEmpty(t, *entry.URL)
 			return nil
@@ -101,8 +105,8 @@ func TestFeedService_Add_Success(t *testing.T) {
 	).Times(1)
 
 	clientFactory := network.NewClientFactoryForTest(client)
-	svc := service.NewFeedService(mockFeeds, mockFolders, mockEntries, nil, nil, clientFactory, nil)
-	feed, err := svc.Add(context.Background(), feedURL, &folderID, "", "article")
+	svc := service.NewFeedService(mockFeeds, mockFolders, mockEntries, nil, nil, clientFactory, nil, nil, nil, nil)
+	feed, err := svc.Add(context.Background(), feedURL, &folderID, "", "article", service.InitialBackfill{})
 	require.NoError(t, err)
 	require.Equal(t, int64(123), feed.ID)
 	require.Equal(t, "Test Feed", createdFeed.Title)
@@ -114,8 +118,8 @@ func TestFeedService_Add_InvalidURL(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	svc := service.NewFeedService(mock.NewMockFeedRepository(ctrl), mock.NewMockFolderRepository(ctrl), mock.NewMockEntryRepository(ctrl), nil, nil, nil, nil)
-	_, err := svc.Add(context.Background(), "invalid-url", nil, "", "article")
+	svc := service.NewFeedService(mock.NewMockFeedRepository(ctrl), mock.NewMockFolderRepository(ctrl), mock.NewMockEntryRepository(ctrl), nil, nil, nil, nil, nil, nil, nil)
+	_, err := svc.Add(context.Background(), "invalid-url", nil, "", "article", service.InitialBackfill{})
 	require.ErrorIs(t, err, service.ErrInvalid)
 }
 
@@ -133,8 +137,8 @@ func TestFeedService_Add_FolderNotFound(t *testing.T) {
 	mockFeeds.EXPECT().FindByURL(gomock.Any(), feedURL).Return(nil, nil)
 	mockFolders.EXPECT().GetByID(gomock.Any(), folderID).Return(model.Folder{}, sql.ErrNoRows)
 
-	svc := service.NewFeedService(mockFeeds, mockFolders, mockEntries, nil, nil, nil, nil)
-	_, err := svc.Add(context.Background(), feedURL, &folderID, "", "article")
+	svc := service.NewFeedService(mockFeeds, mockFolders, mockEntries, nil, nil, nil, nil, nil, nil, nil)
+	_, err := svc.Add(context.Background(), feedURL, &folderID, "", "article", service.InitialBackfill{})
 	require.ErrorIs(t, err, service.ErrNotFound)
 }
 
@@ -151,8 +155,8 @@ func TestFeedService_Add_FindByURLError(t *testing.T) {
 
 	mockFeeds.EXPECT().FindByURL(gomock.Any(), feedURL).Return(nil, dbErr)
 
-	svc := service.NewFeedService(mockFeeds, mockFolders, mockEntries, nil, nil, nil, nil)
-	_, err := svc.Add(context.Background(), feedURL, nil, "", "article")
+	svc := service.NewFeedService(mockFeeds, mockFolders, mockEntries, nil, nil, nil, nil, nil, nil, nil)
+	_, err := svc.Add(context.Background(), feedURL, nil, "", "article", service.InitialBackfill{})
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "check feed url")
 }
@@ -168,13 +172,118 @@ func TestFeedService_Add_Conflict(t *testing.T) {
 	existing := &model.Feed{ID: 1, URL: "https://example.com"}
 	mockFeeds.EXPECT().FindByURL(gomock.Any(), "https://example.com").Return(existing, nil)
 
-	svc := service.NewFeedService(mockFeeds, mockFolders, mockEntries, nil, nil, nil, nil)
-	_, err := svc.Add(context.Background(), "https://example.com", nil, "", "article")
+	svc := service.NewFeedService(mockFeeds, mockFolders, mockEntries, nil, nil, nil, nil, nil, nil, nil)
+	_, err := svc.Add(context.Background(), "https://example.com", nil, "", "article", service.InitialBackfill{})
 	var conflict *service.FeedConflictError
 	require.ErrorAs(t, err, &conflict)
 	require.Equal(t, int64(1), conflict.ExistingFeed.ID)
 }
 
+func TestFeedService_Add_ConflictIgnoresTrackingParams(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFeeds := mock.NewMockFeedRepository(ctrl)
+	mockFolders := mock.NewMockFolderRepository(ctrl)
+	mockEntries := mock.NewMockEntryRepository(ctrl)
+
+	existing := &model.Feed{ID: 1, URL: "https://example.com/feed"}
+	mockFeeds.EXPECT().FindByURL(gomock.Any(), "https://example.com/feed").Return(existing, nil).Times(2)
+
+	svc := service.NewFeedService(mockFeeds, mockFolders, mockEntries, nil, nil, nil, nil, nil, nil, nil)
+	_, err := svc.Add(context.Background(), "https://Example.com/feed/?utm_source=rss", nil, "", "article", service.InitialBackfill{})
+	var conflict *service.FeedConflictError
+	require.ErrorAs(t, err, &conflict)
+
+	feed, isNew, err := svc.AddWithoutFetch(context.Background(), "https://example.com/feed?fbclid=x", nil, "", "article")
+	require.NoError(t, err)
+	require.False(t, isNew)
+	require.Equal(t, int64(1), feed.ID)
+}
+
+// lookupBarrierFeeds holds the first two FindByURL lookups until both have
+// run, so two concurrent adds both miss the feed and race to Create it.
+type lookupBarrierFeeds struct {
+	repository.FeedRepository
+	mu      sync.Mutex
+	lookups int
+	both    chan struct{}
+}
+
+func (r *lookupBarrierFeeds) FindByURL(ctx context.Context, url string) (*model.Feed, error) {
+	feed, err := r.FeedRepository.FindByURL(ctx, url)
+	r.mu.Lock()
+	r.lookups++
+	lookup := r.lookups
+	if lookup == 2 {
+		close(r.both)
+	}
+	r.mu.Unlock()
+	if lookup <= 2 {
+		<-r.both
+	}
+	return feed, err
+}
+
+func TestFeedService_Add_ConcurrentSameURL(t *testing.T) {
+	feedURL := "https://example.com/rss"
+	client := &http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(sampleRSS)),
+				Header:     make(http.Header),
+				Request:    req,
+			}, nil
+		}),
+	}
+
+	for _, withoutFetch := range []bool{false, true} {
+		db := testutil.NewTestDB(t)
+		feeds := &lookupBarrierFeeds{FeedRepository: repository.NewFeedRepository(db, nil, nil), both: make(chan struct{})}
+		svc := service.NewFeedService(feeds, repository.NewFolderRepository(db, nil, nil), repository.NewEntryRepository(db, nil, nil), nil, nil, network.NewClientFactoryForTest(client), nil, nil, nil, nil)
+
+		var wg sync.WaitGroup
+		results := make([]model.Feed, 2)
+		errs := make([]error, 2)
+		isNew := make([]bool, 2)
+		for i := range 2 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if withoutFetch {
+					results[i], isNew[i], errs[i] = svc.AddWithoutFetch(context.Background(), feedURL, nil, "", "article")
+					return
+				}
+				results[i], errs[i] = svc.Add(context.Background(), feedURL, nil, "", "article", service.InitialBackfill{})
+				isNew[i] = errs[i] == nil
+			}()
+		}
+		wg.Wait()
+
+		var count int
+		require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM feeds`).Scan(&count))
+		require.Equal(t, 1, count)
+
+		// One caller creates the feed, the other gets it back as already subscribed
+		winner, loser := 0, 1
+		if !isNew[0] {
+			winner, loser = 1, 0
+		}
+		require.NoError(t, errs[winner])
+		require.True(t, isNew[winner])
+		require.False(t, isNew[loser])
+		if withoutFetch {
+			require.NoError(t, errs[loser])
+			require.Equal(t, results[winner].ID, results[loser].ID)
+		} else {
+			var conflict *service.FeedConflictError
+			require.ErrorAs(t, errs[loser], &conflict)
+			require.Equal(t, results[winner].ID, conflict.ExistingFeed.ID)
+		}
+	}
+}
+
 func TestFeedService_Add_FetchErrorCreatesFeed(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -207,9 +316,124 @@ func TestFeedService_Add_FetchErrorCreatesFeed(t *testing.T) {
 	)
 
 	clientFactory := network.NewClientFactoryForTest(client)
-	svc := service.NewFeedService(mockFeeds, mockFolders, mockEntries, nil, nil, clientFactory, nil)
-	_, err := svc.Add(context.Background(), feedURL, nil, "Custom", "article")
+	svc := service.NewFeedService(mockFeeds, mockFolders, mockEntries, nil, nil, clientFactory, nil, nil, nil, nil)
+	_, err := svc.Add(context.Background(), feedURL, nil, "Custom", "article", service.InitialBackfill{})
+	require.NoError(t, err)
+}
+
+// anubisSolverStub solves every challenge with a fixed cookie and caches it
+// per host and User-Agent, like the real solver's fingerprint scoping.
+type anubisSolverStub struct {
+	mu      sync.Mutex
+	cookies map[string]string
+	solves  int
+	headers []http.Header
+}
+
+func (s *anubisSolverStub) GetCachedCookieWithHeaders(_ context.Context, host string, requestHeaders http.Header) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.cookies[host+"|"+requestHeaders.Get("User-Agent")]
+}
+
+func (s *anubisSolverStub) SolveFromBodyWithHeaders(_ context.Context, _ []byte, originalURL string, _ []*http.Cookie, requestHeaders http.Header) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.solves++
+	s.headers = append(s.headers, requestHeaders)
+	if s.cookies == nil {
+		s.cookies = make(map[string]string)
+	}
+	s.cookies[network.ExtractHost(originalURL)+"|"+requestHeaders.Get("User-Agent")] = "anubis-auth=solved"
+	return "anubis-auth=solved", nil
+}
+
+const anubisChallengePage = `<html><script id="anubis_challenge" type="application/json">{"challenge":"abc","rules":{"difficulty":1}}</script></html>`
+
+// anubisGuardedClient serves the challenge page until the solved cookie is sent.
+func anubisGuardedClient(requests *[]*http.Request) *http.Client {
+	var mu sync.Mutex
+	return &http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			mu.Lock()
+			*requests = append(*requests, req)
+			mu.Unlock()
+			body := anubisChallengePage
+			if req.Header.Get("Cookie") == "anubis-auth=solved" {
+				body = sampleRSS
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(body)),
+				Header:     make(http.Header),
+				Request:    req,
+			}, nil
+		}),
+	}
+}
+
+func TestFeedService_Add_SolvesAnubisChallenge(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFeeds := mock.NewMockFeedRepository(ctrl)
+	mockEntries := mock.NewMockEntryRepository(ctrl)
+
+	feedURL := "https://example.com/rss"
+	var requests []*http.Request
+	solver := &anubisSolverStub{}
+
+	mockFeeds.EXPECT().FindByURL(gomock.Any(), feedURL).Return(nil, nil)
+	mockFeeds.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, feed model.Feed) (model.Feed, error) {
+			require.Nil(t, feed.ErrorMessage)
+			require.Equal(t, "Test Feed", feed.Title)
+			feed.ID = 1
+			return feed, nil
+		},
+	)
+	mockEntries.EXPECT().CreateOrUpdate(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+
+	clientFactory := network.NewClientFactoryForTest(anubisGuardedClient(&requests))
+	svc := service.NewFeedService(mockFeeds, mock.NewMockFolderRepository(ctrl), mockEntries, nil, nil, clientFactory, solver, nil, nil, nil)
+	_, err := svc.Add(context.Background(), feedURL, nil, "", "article", service.InitialBackfill{})
 	require.NoError(t, err)
+
+	require.Len(t, requests, 2)
+	require.Empty(t, requests[0].Header.Get("Cookie"))
+	require.Equal(t, "anubis-auth=solved", requests[1].Header.Get("Cookie"))
+	require.Equal(t, 1, solver.solves)
+	// The solver gets the request fingerprint so the cookie is cached under it
+	require.Equal(t, config.DefaultUserAgent, solver.headers[0].Get("User-Agent"))
+
+	// A later fetch with the same fingerprint reuses the cached cookie
+	requests = nil
+	_, err = svc.Preview(context.Background(), feedURL)
+	require.NoError(t, err)
+	require.Len(t, requests, 1)
+	require.Equal(t, "anubis-auth=solved", requests[0].Header.Get("Cookie"))
+	require.Equal(t, 1, solver.solves)
+}
+
+func TestFeedService_Preview_AnubisRejected(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	client := &http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(`<script id="anubis_challenge" type="application/json">null</script>`)),
+				Header:     make(http.Header),
+				Request:    req,
+			}, nil
+		}),
+	}
+
+	solver := anubis.NewSolver(network.NewClientFactoryForTest(client), anubis.NewStore(newSettingsRepoStub()))
+	svc := service.NewFeedService(mock.NewMockFeedRepository(ctrl), mock.NewMockFolderRepository(ctrl), mock.NewMockEntryRepository(ctrl), nil, nil, network.NewClientFactoryForTest(client), solver, nil, nil, nil)
+	_, err := svc.Preview(context.Background(), "https://example.com/rss")
+	require.ErrorIs(t, err, service.ErrAnubisRejected)
 }
 
 func TestFeedService_Add_EntryCreateErrorIgnored(t *testing.T) {
@@ -241,15 +465,89 @@ func TestFeedService_Add_EntryCreateErrorIgnored(t *testing.T) {
 		},
 	)
 
-	mockEntries.EXPECT().CreateOrUpdate(gomock.Any(), gomock.Any()).Return(errors.New("entry error")).Times(1)
+	mockEntries.EXPECT().CreateOrUpdate(gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("entry error")).Times(1)
 
 	clientFactory := network.NewClientFactoryForTest(client)
-	svc := service.NewFeedService(mockFeeds, mockFolders, mockEntries, nil, nil, clientFactory, nil)
-	feed, err := svc.Add(context.Background(), feedURL, nil, "", "article")
+	svc := service.NewFeedService(mockFeeds, mockFolders, mockEntries, nil, nil, clientFactory, nil, nil, nil, nil)
+	feed, err := svc.Add(context.Background(), feedURL, nil, "", "article", service.InitialBackfill{})
 	require.NoError(t, err)
 	require.Equal(t, int64(123), feed.ID)
 }
 
+func TestFeedService_Add_AutoTypeClassifiesPictureFeed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFeeds := mock.NewMockFeedRepository(ctrl)
+	mockEntries := mock.NewMockEntryRepository(ctrl)
+
+	const pictureRSS = `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0">
+<channel>
+<title>Pictures</title>
+<link>https://example.com</link>
+<item><title>1</title><link>https://example.com/1</link><description><![CDATA[<img src="https://example.com/1.jpg">]]></description></item>
+<item><title>2</title><link>https://example.com/2</link><description><![CDATA[<img src="https://example.com/2.jpg">]]></description></item>
+</channel>
+</rss>`
+
+	feedURL := "https://example.com/rss"
+	client := &http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(pictureRSS)),
+				Header:     make(http.Header),
+				Request:    req,
+			}, nil
+		}),
+	}
+
+	mockFeeds.EXPECT().FindByURL(gomock.Any(), feedURL).Return(nil, nil)
+	mockFeeds.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, feed model.Feed) (model.Feed, error) {
+			require.Equal(t, "picture", feed.Type)
+			feed.ID = 123
+			return feed, nil
+		},
+	)
+	mockEntries.EXPECT().CreateOrUpdate(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(2)
+
+	svc := service.NewFeedService(mockFeeds, mock.NewMockFolderRepository(ctrl), mockEntries, nil, nil, network.NewClientFactoryForTest(client), nil, nil, nil, nil)
+	feed, err := svc.Add(context.Background(), feedURL, nil, "", service.FeedTypeAuto, service.InitialBackfill{})
+	require.NoError(t, err)
+	require.Equal(t, "picture", feed.Type)
+}
+
+func TestFeedService_Add_AutoTypeUsesFolderType(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFeeds := mock.NewMockFeedRepository(ctrl)
+	mockFolders := mock.NewMockFolderRepository(ctrl)
+
+	feedURL := "https://example.com/rss"
+	client := &http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			return nil, errors.New("network down")
+		}),
+	}
+
+	folderID := int64(7)
+	mockFeeds.EXPECT().FindByURL(gomock.Any(), feedURL).Return(nil, nil)
+	mockFolders.EXPECT().GetByID(gomock.Any(), folderID).Return(model.Folder{ID: folderID, Type: "notification"}, nil)
+	mockFeeds.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, feed model.Feed) (model.Feed, error) {
+			require.Equal(t, "notification", feed.Type)
+			return feed, nil
+		},
+	)
+
+	svc := service.NewFeedService(mockFeeds, mockFolders, mock.NewMockEntryRepository(ctrl), nil, nil, network.NewClientFactoryForTest(client), nil, nil, nil, nil)
+	_, err := svc.Add(context.Background(), feedURL, &folderID, "", service.FeedTypeAuto, service.InitialBackfill{})
+	require.NoError(t, err)
+}
+
 func TestFeedService_Add_IconFetchUpdatesPath(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -286,16 +584,100 @@ func TestFeedService_Add_IconFetchUpdatesPath(t *testing.T) {
 		UpdateIconPath(gomock.Any(), int64(123), "example.com.png").
 		Return(nil)
 
-	mockEntries.EXPECT().CreateOrUpdate(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+	mockEntries.EXPECT().CreateOrUpdate(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(1)
 
 	clientFactory := network.NewClientFactoryForTest(client)
-	svc := service.NewFeedService(mockFeeds, mockFolders, mockEntries, mockIcons, nil, clientFactory, nil)
-	feed, err := svc.Add(context.Background(), feedURL, nil, "", "article")
+	svc := service.NewFeedService(mockFeeds, mockFolders, mockEntries, mockIcons, nil, clientFactory, nil, nil, nil, nil)
+	feed, err := svc.Add(context.Background(), feedURL, nil, "", "article", service.InitialBackfill{})
 	require.NoError(t, err)
 	require.NotNil(t, feed.IconPath)
 	require.Equal(t, "example.com.png", *feed.IconPath)
 }
 
+const backfillRSS = `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0">
+<channel>
+<title>Busy Feed</title>
+<link>https://example.com</link>
+<item><title>Middle</title><link>https://example.com/2</link><pubDate>Tue, 02 Jan 2024 00:00:00 GMT</pubDate></item>
+<item><title>Newest</title><link>https://example.com/3</link><pubDate>Wed, 03 Jan 2024 00:00:00 GMT</pubDate></item>
+<item><title>Oldest</title><link>https://example.com/1</link><pubDate>Mon, 01 Jan 2024 00:00:00 GMT</pubDate></item>
+</channel>
+</rss>`
+
+func TestFeedService_Add_InitialBackfill(t *testing.T) {
+	tests := []struct {
+		name     string
+		backfill service.InitialBackfill
+		titles   []string
+		read     bool
+	}{
+		{name: "all", backfill: service.InitialBackfill{}, titles: []string{"Middle", "Newest", "Oldest"}},
+		{name: "none", backfill: service.InitialBackfill{MarkRead: true}, titles: []string{"Middle", "Newest", "Oldest"}, read: true},
+		{name: "most recent", backfill: service.InitialBackfill{Limit: 2}, titles: []string{"Newest", "Middle"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockFeeds := mock.NewMockFeedRepository(ctrl)
+			mockEntries := mock.NewMockEntryRepository(ctrl)
+
+			client := &http.Client{
+				Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+					return &http.Response{
+						StatusCode: http.StatusOK,
+						Body:       io.NopCloser(strings.NewReader(backfillRSS)),
+						Header:     make(http.Header),
+						Request:    req,
+					}, nil
+				}),
+			}
+
+			mockFeeds.EXPECT().FindByURL(gomock.Any(), gomock.Any()).Return(nil, nil)
+			mockFeeds.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ context.Context, feed model.Feed) (model.Feed, error) {
+					feed.ID = 1
+					return feed, nil
+				},
+			)
+			var titles []string
+			mockEntries.EXPECT().CreateOrUpdate(gomock.Any(), gomock.Any(), 0).DoAndReturn(
+				func(_ context.Context, entry model.Entry, _ int) error {
+					titles = append(titles, *entry.Title)
+					require.Equal(t, tt.read, entry.Read)
+					return nil
+				},
+			).AnyTimes()
+
+			svc := service.NewFeedService(mockFeeds, mock.NewMockFolderRepository(ctrl), mockEntries, nil, nil, network.NewClientFactoryForTest(client), nil, nil, nil, nil)
+			_, err := svc.Add(context.Background(), "https://example.com/rss", nil, "", "article", tt.backfill)
+			require.NoError(t, err)
+			require.Equal(t, tt.titles, titles)
+		})
+	}
+}
+
+func TestParseInitialBackfill(t *testing.T) {
+	for value, want := range map[string]service.InitialBackfill{
+		"":     {},
+		"all":  {},
+		"None": {MarkRead: true},
+		"10":   {Limit: 10},
+	} {
+		got, err := service.ParseInitialBackfill(value)
+		require.NoError(t, err, value)
+		require.Equal(t, want, got, value)
+	}
+
+	for _, value := range []string{"0", "-3", "some"} {
+		_, err := service.ParseInitialBackfill(value)
+		require.ErrorIs(t, err, service.ErrInvalid, value)
+	}
+}
+
 func TestFeedService_AddWithoutFetch(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -306,13 +688,141 @@ func TestFeedService_AddWithoutFetch(t *testing.T) {
 
 	mockFeeds.EXPECT().FindByURL(gomock.Any(), "https://example.com").Return(&model.Feed{ID: 1, URL: "https://example.com"}, nil)
 
-	svc := service.NewFeedService(mockFeeds, mockFolders, mockEntries, nil, nil, nil, nil)
+	svc := service.NewFeedService(mockFeeds, mockFolders, mockEntries, nil, nil, nil, nil, nil, nil, nil)
 	feed, isNew, err := svc.AddWithoutFetch(context.Background(), "https://example.com", nil, "", "article")
 	require.NoError(t, err)
 	require.False(t, isNew)
 	require.Equal(t, int64(1), feed.ID)
 }
 
+func TestFeedService_Add_RestoresSoftDeletedFeed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFeeds := mock.NewMockFeedRepository(ctrl)
+	mockFolders := mock.NewMockFolderRepository(ctrl)
+	mockEntries := mock.NewMockEntryRepository(ctrl)
+
+	deletedAt := time.Now().Add(-30 * 24 * time.Hour)
+	oldFolder := int64(5)
+	existing := &model.Feed{ID: 7, URL: "https://example.com/rss", Title: "Old", Type: "article", FolderID: &oldFolder, DeletedAt: &deletedAt}
+	mockFeeds.EXPECT().FindByURL(gomock.Any(), "https://example.com/rss").Return(existing, nil)
+	// Re-subscribing ignores the restore window
+	mockFeeds.EXPECT().Restore(gomock.Any(), int64(7), time.Time{}).Return(nil)
+	mockFeeds.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, feed model.Feed) (model.Feed, error) {
+			require.Nil(t, feed.FolderID)
+			require.Nil(t, feed.DeletedAt)
+			require.Equal(t, "Old", feed.Title)
+			require.Equal(t, "Renamed", feed.DisplayTitle())
+			return feed, nil
+		},
+	)
+	mockFeeds.EXPECT().UpdateType(gomock.Any(), int64(7), "picture").Return(nil)
+
+	svc := service.NewFeedService(mockFeeds, mockFolders, mockEntries, nil, nil, nil, nil, nil, nil, nil)
+	feed, err := svc.Add(context.Background(), "https://example.com/rss", nil, "Renamed", "picture", service.InitialBackfill{})
+	require.NoError(t, err)
+	require.Equal(t, int64(7), feed.ID)
+	require.Equal(t, "picture", feed.Type)
+}
+
+func TestFeedService_AddWithoutFetch_RestoresSoftDeletedFeed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFeeds := mock.NewMockFeedRepository(ctrl)
+	mockFolders := mock.NewMockFolderRepository(ctrl)
+	mockEntries := mock.NewMockEntryRepository(ctrl)
+
+	deletedAt := time.Now()
+	existing := &model.Feed{ID: 7, URL: "https://example.com/rss", Title: "Old", Type: "article", DeletedAt: &deletedAt}
+	mockFeeds.EXPECT().FindByURL(gomock.Any(), "https://example.com/rss").Return(existing, nil)
+	mockFeeds.EXPECT().Restore(gomock.Any(), int64(7), time.Time{}).Return(nil)
+	mockFeeds.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, feed model.Feed) (model.Feed, error) {
+			require.Equal(t, "Old", feed.Title)
+			return feed, nil
+		},
+	)
+
+	svc := service.NewFeedService(mockFeeds, mockFolders, mockEntries, nil, nil, nil, nil, nil, nil, nil)
+	feed, isNew, err := svc.AddWithoutFetch(context.Background(), "https://example.com/rss", nil, "", "article")
+	require.NoError(t, err)
+	require.True(t, isNew)
+	require.Equal(t, int64(7), feed.ID)
+}
+
+func TestFeedService_Restore(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFeeds := mock.NewMockFeedRepository(ctrl)
+	before := time.Now().Add(-service.DeleteRetention)
+	mockFeeds.EXPECT().Restore(gomock.Any(), int64(3), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ int64, deletedSince time.Time) error {
+			require.WithinDuration(t, before, deletedSince, time.Minute)
+			return nil
+		},
+	)
+	mockFeeds.EXPECT().GetByID(gomock.Any(), int64(3)).Return(model.Feed{ID: 3, Title: "Feed"}, nil)
+
+	svc := service.NewFeedService(mockFeeds, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	feed, err := svc.Restore(context.Background(), 3)
+	require.NoError(t, err)
+	require.Equal(t, int64(3), feed.ID)
+}
+
+func TestFeedService_Restore_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFeeds := mock.NewMockFeedRepository(ctrl)
+	mockFeeds.EXPECT().Restore(gomock.Any(), int64(3), gomock.Any()).Return(sql.ErrNoRows)
+
+	svc := service.NewFeedService(mockFeeds, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	_, err := svc.Restore(context.Background(), 3)
+	require.ErrorIs(t, err, service.ErrNotFound)
+}
+
+func TestFeedService_PurgeDeleted(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFeeds := mock.NewMockFeedRepository(ctrl)
+	mockFolders := mock.NewMockFolderRepository(ctrl)
+	var cutoff time.Time
+	purgeFeeds := mockFeeds.EXPECT().PurgeDeleted(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, deletedBefore time.Time) (int64, error) {
+			cutoff = deletedBefore
+			require.WithinDuration(t, time.Now().Add(-service.DeleteRetention), deletedBefore, time.Minute)
+			return 2, nil
+		},
+	)
+	mockFolders.EXPECT().PurgeDeleted(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, deletedBefore time.Time) (int64, error) {
+			require.Equal(t, cutoff, deletedBefore)
+			return 1, nil
+		},
+	).After(purgeFeeds)
+	mockEntries := mock.NewMockEntryRepository(ctrl)
+	mockEntries.EXPECT().DeleteRawItemsBefore(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, before time.Time) (int64, error) {
+			require.WithinDuration(t, time.Now().Add(-service.RawItemRetention), before, time.Minute)
+			return 3, nil
+		},
+	)
+	mockEntries.EXPECT().DeleteTombstonesBefore(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, before time.Time) (int64, error) {
+			require.WithinDuration(t, time.Now().Add(-service.EntryTombstoneRetention), before, time.Minute)
+			return 2, nil
+		},
+	)
+
+	svc := service.NewFeedService(mockFeeds, mockFolders, mockEntries, nil, nil, nil, nil, nil, nil, nil)
+	require.NoError(t, svc.PurgeDeleted(context.Background()))
+}
+
 func TestFeedService_AddWithoutFetch_NewFeed(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -332,7 +842,7 @@ func TestFeedService_AddWithoutFetch_NewFeed(t *testing.T) {
 		},
 	)
 
-	svc := service.NewFeedService(mockFeeds, mockFolders, mockEntries, nil, nil, nil, nil)
+	svc := service.NewFeedService(mockFeeds, mockFolders, mockEntries, nil, nil, nil, nil, nil, nil, nil)
 	feed, isNew, err := svc.AddWithoutFetch(context.Background(), feedURL, nil, "", "article")
 	require.NoError(t, err)
 	require.True(t, isNew)
@@ -370,7 +880,7 @@ func TestFeedService_Preview_WithFallbackUserAgent(t *testing.T) {
 	}
 
 	clientFactory := network.NewClientFactoryForTest(client)
-	svc := service.NewFeedService(mock.NewMockFeedRepository(ctrl), mock.NewMockFolderRepository(ctrl), mock.NewMockEntryRepository(ctrl), nil, settings, clientFactory, nil)
+	svc := service.NewFeedService(mock.NewMockFeedRepository(ctrl), mock.NewMockFolderRepository(ctrl), mock.NewMockEntryRepository(ctrl), nil, settings, clientFactory, nil, nil, nil, nil)
 	_, err := svc.Preview(context.Background(), feedURL)
 	require.NoError(t, err)
 	mu.Lock()
@@ -384,7 +894,7 @@ func TestFeedService_Preview_InvalidURL(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	svc := service.NewFeedService(mock.NewMockFeedRepository(ctrl), mock.NewMockFolderRepository(ctrl), mock.NewMockEntryRepository(ctrl), nil, nil, nil, nil)
+	svc := service.NewFeedService(mock.NewMockFeedRepository(ctrl), mock.NewMockFolderRepository(ctrl), mock.NewMockEntryRepository(ctrl), nil, nil, nil, nil, nil, nil, nil)
 	_, err := svc.Preview(context.Background(), "invalid-url")
 	require.ErrorIs(t, err, service.ErrInvalid)
 }
@@ -406,7 +916,7 @@ func TestFeedService_Preview_EmptyTitleFallback(t *testing.T) {
 	}
 
 	clientFactory := network.NewClientFactoryForTest(client)
-	svc := service.NewFeedService(mock.NewMockFeedRepository(ctrl), mock.NewMockFolderRepository(ctrl), mock.NewMockEntryRepository(ctrl), nil, nil, clientFactory, nil)
+	svc := service.NewFeedService(mock.NewMockFeedRepository(ctrl), mock.NewMockFolderRepository(ctrl), mock.NewMockEntryRepository(ctrl), nil, nil, clientFactory, nil, nil, nil, nil)
 	preview, err := svc.Preview(context.Background(), feedURL)
 	require.NoError(t, err)
 	require.Equal(t, feedURL, preview.Title)
@@ -420,9 +930,12 @@ func TestFeedService_Update_Delete_UpdateType_DeleteBatch(t *testing.T) {
 	mockFolders := mock.NewMockFolderRepository(ctrl)
 	mockEntries := mock.NewMockEntryRepository(ctrl)
 
-	svc := service.NewFeedService(mockFeeds, mockFolders, mockEntries, nil, nil, nil, nil)
+	svc := service.NewFeedService(mockFeeds, mockFolders, mockEntries, nil, nil, nil, nil, nil, nil, nil)
 
-	_, err := svc.Update(context.Background(), 1, "", nil, nil)
+	_, err := svc.Update(context.Background(), 1, "", nil, nil, nil)
+	require.ErrorIs(t, err, service.ErrInvalid)
+	negative := -1
+	_, err = svc.Update(context.Background(), 1, "Title", nil, nil, &negative)
 	require.ErrorIs(t, err, service.ErrInvalid)
 
 	folderID := int64(10)
@@ -430,22 +943,23 @@ func TestFeedService_Update_Delete_UpdateType_DeleteBatch(t *testing.T) {
 	mockFeeds.EXPECT().GetByID(gomock.Any(), int64(1)).Return(model.Feed{ID: 1, Title: "Old", Type: "article"}, nil)
 	// 然后获取 folder 失败
 	mockFolders.EXPECT().GetByID(gomock.Any(), folderID).Return(model.Folder{}, errors.New("db"))
-	_, err = svc.Update(context.Background(), 1, "Title", &folderID, nil)
+	_, err = svc.Update(context.Background(), 1, "Title", &folderID, nil, nil)
 	require.Error(t, err)
 
 	// feed 不存在
 	mockFeeds.EXPECT().GetByID(gomock.Any(), int64(1)).Return(model.Feed{}, sql.ErrNoRows)
-	_, err = svc.Update(context.Background(), 1, "Title", &folderID, nil)
+	_, err = svc.Update(context.Background(), 1, "Title", &folderID, nil, nil)
 	require.ErrorIs(t, err, service.ErrNotFound)
 
 	mockFeeds.EXPECT().GetByID(gomock.Any(), int64(2)).Return(model.Feed{ID: 2, Title: "Old"}, nil)
 	mockFeeds.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(
 		func(_ context.Context, feed model.Feed) (model.Feed, error) {
-			require.Equal(t, "New", feed.Title)
+			require.Equal(t, "Old", feed.Title)
+			require.Equal(t, "New", *feed.CustomTitle)
 			return feed, nil
 		},
 	)
-	_, err = svc.Update(context.Background(), 2, "New", nil, nil)
+	_, err = svc.Update(context.Background(), 2, "New", nil, nil, nil)
 	require.NoError(t, err)
 
 	mockFeeds.EXPECT().GetByID(gomock.Any(), int64(3)).Return(model.Feed{}, sql.ErrNoRows)
@@ -461,8 +975,13 @@ func TestFeedService_Update_Delete_UpdateType_DeleteBatch(t *testing.T) {
 	err = svc.UpdateType(context.Background(), 5, "picture")
 	require.ErrorIs(t, err, service.ErrNotFound)
 
-	mockFeeds.EXPECT().GetByID(gomock.Any(), int64(6)).Return(model.Feed{ID: 6}, nil)
-	mockFeeds.EXPECT().UpdateType(gomock.Any(), int64(6), "picture").Return(nil)
+	mockFeeds.EXPECT().GetByID(gomock.Any(), int64(6)).Return(model.Feed{ID: 6, Type: "article"}, nil)
+	mockFeeds.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, feed model.Feed) (model.Feed, error) {
+			require.Equal(t, "picture", feed.Type)
+			return feed, nil
+		},
+	)
 	err = svc.UpdateType(context.Background(), 6, "picture")
 	require.NoError(t, err)
 
@@ -489,7 +1008,7 @@ func TestFeedService_DeleteBatch_RepositoryError(t *testing.T) {
 	dbErr := errors.New("delete batch failed")
 	mockFeeds.EXPECT().DeleteBatch(gomock.Any(), []int64{1, 2}).Return(int64(0), dbErr)
 
-	svc := service.NewFeedService(mockFeeds, mockFolders, mockEntries, nil, nil, nil, nil)
+	svc := service.NewFeedService(mockFeeds, mockFolders, mockEntries, nil, nil, nil, nil, nil, nil, nil)
 	err := svc.DeleteBatch(context.Background(), []int64{1, 2})
 	require.ErrorIs(t, err, dbErr)
 }
@@ -508,13 +1027,13 @@ func TestFeedService_HelperFunctions(t *testing.T) {
 	items[1].UpdatedParsed = func() *time.Time { t2 := t1.Add(time.Hour); return &t2 }()
 	require.False(t, service.HasDynamicTime(items))
 
-	date := service.ExtractDateFromSummary("Filed: 2025-12-17")
+	date := service.ExtractDateFromSummary("Filed: 2025-12-17", time.UTC)
 	require.NotNil(t, date)
 	require.Equal(t, "2025-12-17", date.Format("2006-01-02"))
 
 	published := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
 	item := &gofeed.Item{Description: "Filed: 2025-12-17", PublishedParsed: &published}
-	got := service.ExtractPublishedAt(item, false)
+	got := service.ExtractPublishedAt(item, false, time.UTC)
 	require.NotNil(t, got)
 	require.Equal(t, "2025-12-17", got.Format("2006-01-02"))
 
@@ -544,12 +1063,41 @@ func TestFeedService_HelperFunctions(t *testing.T) {
 	require.Nil(t, service.OptionalString("  "))
 }
 
+func TestItemToEntry_CleansTitleAndAuthor(t *testing.T) {
+	item := &gofeed.Item{
+		Title:  " <b>Tom &amp; Jerry</b>\n\t&#x2014;  episode\x00 1 ",
+		Author: &gofeed.Person{Name: "<name>Jane &amp; John</name>"},
+	}
+	entry := service.ItemToEntry(model.Feed{ID: 1}, item, false, time.UTC)
+	require.NotNil(t, entry.Title)
+	require.Equal(t, "Tom & Jerry — episode 1", *entry.Title)
+	require.NotNil(t, entry.Author)
+	require.Equal(t, "Jane & John", *entry.Author)
+}
+
+func TestItemToEntry_TruncatesByRuneAndKeepsHash(t *testing.T) {
+	longTitle := strings.Repeat("标题", 300)
+	item := &gofeed.Item{
+		Title:   longTitle,
+		Content: "content",
+		Author:  &gofeed.Person{Name: strings.Repeat("作者", 150)},
+	}
+	entry := service.ItemToEntry(model.Feed{ID: 1}, item, false, time.UTC)
+	require.NotNil(t, entry.Title)
+	require.Equal(t, 500, utf8.RuneCountInString(*entry.Title))
+	require.True(t, utf8.ValidString(*entry.Title))
+	require.True(t, strings.HasSuffix(*entry.Title, "…"))
+	require.NotNil(t, entry.Author)
+	require.Equal(t, 200, utf8.RuneCountInString(*entry.Author))
+	require.Equal(t, hashString(longTitle+"content"), entry.Hash)
+}
+
 func TestComputeEntryHash_PrioritizesGUID(t *testing.T) {
 	item := &gofeed.Item{
 		GUID: " stable-guid ",
 		Link: "https://example.com/post#reply10",
 	}
-	hash := service.ComputeEntryHash(item, "title", "content")
+	hash := service.ComputeEntryHash(item, "title", "content", false, model.DedupeKeyAuto)
 	require.Equal(t, hashString("stable-guid"), hash)
 	require.Len(t, hash, 64)
 }
@@ -558,18 +1106,188 @@ func TestComputeEntryHash_FallbackToLink(t *testing.T) {
 	item := &gofeed.Item{
 		Link: " https://example.com/post?a=1#reply10 ",
 	}
-	hash := service.ComputeEntryHash(item, "title", "content")
+	hash := service.ComputeEntryHash(item, "title", "content", false, model.DedupeKeyAuto)
 	require.Equal(t, hashString("https://example.com/post?a=1#reply10"), hash)
 	require.Len(t, hash, 64)
 }
 
+func TestComputeEntryHash_NormalizesLinkForDynamicFeeds(t *testing.T) {
+	item := &gofeed.Item{
+		Link: " https://www.v2ex.com/t/1193191?utm_source=rss#reply10 ",
+	}
+	hash := service.ComputeEntryHash(item, "title", "content", true, model.DedupeKeyAuto)
+	require.Equal(t, hashString("https://www.v2ex.com/t/1193191"), hash)
+}
+
 func TestComputeEntryHash_FallbackToTitleAndContent(t *testing.T) {
 	item := &gofeed.Item{}
-	hash := service.ComputeEntryHash(item, " title ", " content ")
+	hash := service.ComputeEntryHash(item, " title ", " content ", false, model.DedupeKeyAuto)
 	require.Equal(t, hashString("titlecontent"), hash)
 	require.Len(t, hash, 64)
 }
 
+func TestComputeEntryHash_UnwrapsTrackingRedirects(t *testing.T) {
+	first := &gofeed.Item{Link: "https://tracking.example/r/a1?u=https%3A%2F%2Fexample.com%2Fpost"}
+	second := &gofeed.Item{Link: "https://tracking.example/r/b2?u=https%3A%2F%2Fexample.com%2Fpost"}
+	require.Equal(t, hashString("https://example.com/post"), service.ComputeEntryHash(first, "", "", false, model.DedupeKeyAuto))
+	require.Equal(t,
+		service.ComputeEntryHash(first, "", "", false, model.DedupeKeyURL),
+		service.ComputeEntryHash(second, "", "", false, model.DedupeKeyURL),
+	)
+}
+
+func TestComputeEntryHash_Strategies(t *testing.T) {
+	item := &gofeed.Item{GUID: "guid-1", Link: "https://example.com/post?utm_source=rss#top"}
+	require.Equal(t, hashString("guid-1"), service.ComputeEntryHash(item, "t", "c", false, model.DedupeKeyGUID))
+	require.Equal(t, hashString("https://example.com/post"), service.ComputeEntryHash(item, "t", "c", false, model.DedupeKeyURL))
+	require.Equal(t, hashString("tc"), service.ComputeEntryHash(item, " t ", " c ", false, model.DedupeKeyTitleContent))
+
+	// guid and url fall back to title and content, never to each other
+	require.Equal(t, hashString("tc"), service.ComputeEntryHash(&gofeed.Item{Link: "https://example.com/post"}, "t", "c", false, model.DedupeKeyGUID))
+	require.Equal(t, hashString("tc"), service.ComputeEntryHash(&gofeed.Item{GUID: "guid-1"}, "t", "c", false, model.DedupeKeyURL))
+}
+
+func TestComputeEntryHash_KeySelection(t *testing.T) {
+	notPermaLink := map[string]string{service.GUIDPermaLinkKey: "false"}
+	tests := []struct {
+		name    string
+		item    *gofeed.Item
+		dynamic bool
+		want    string
+	}{
+		{
+			name: "wordpress query guid",
+			item: &gofeed.Item{GUID: "https://example.com/?p=123", Link: "https://example.com/2024/01/post/"},
+			want: "https://example.com/?p=123",
+		},
+		{
+			name: "youtube video id",
+			item: &gofeed.Item{GUID: "yt:video:abc", Link: "https://www.youtube.com/watch?v=abc&t=1"},
+			want: "yt:video:abc",
+		},
+		{
+			name:    "tag uri with reply fragment links",
+			item:    &gofeed.Item{GUID: "tag:www.v2ex.com,2024-01-01:/t/123", Link: "https://www.v2ex.com/t/123#reply42"},
+			dynamic: true,
+			want:    "tag:www.v2ex.com,2024-01-01:/t/123",
+		},
+		{
+			name: "permalink guid with tracking params",
+			item: &gofeed.Item{GUID: "https://example.com/post?utm_source=rss", Link: "https://example.com/post?utm_source=rss"},
+			want: "https://example.com/post",
+		},
+		{
+			name: "permalink guid with fragment and no link",
+			item: &gofeed.Item{GUID: "https://example.com/post#reply3"},
+			want: "https://example.com/post",
+		},
+		{
+			name:    "guid equal to link in dynamic feed",
+			item:    &gofeed.Item{GUID: "https://example.com/post?ts=1", Link: "https://example.com/post?ts=1"},
+			dynamic: true,
+			want:    "https://example.com/post?ts=1",
+		},
+		{
+			name: "url guid marked not permalink",
+			item: &gofeed.Item{GUID: "https://example.com/post#c1", Link: "https://example.com/post", Custom: notPermaLink},
+			want: "https://example.com/post#c1",
+		},
+		{
+			name: "no guid keeps raw link",
+			item: &gofeed.Item{Link: "https://example.com/post#top"},
+			want: "https://example.com/post#top",
+		},
+		{
+			name: "no guid or link",
+			item: &gofeed.Item{},
+			want: "tc",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, hashString(tt.want), service.ComputeEntryHash(tt.item, "t", "c", tt.dynamic, model.DedupeKeyAuto))
+		})
+	}
+}
+
+func TestParseStaticFeed_KeepsGUIDPermaLink(t *testing.T) {
+	feed, err := service.ParseStaticFeed([]byte(`<?xml version="1.0"?><rss version="2.0"><channel><title>Feed</title>
+<item><title>One</title><guid isPermaLink="false">https://example.com/post#c1</guid><link>https://example.com/post</link></item>
+<item><title>Two</title><guid>https://example.com/two#c2</guid></item>
+</channel></rss>`))
+	require.NoError(t, err)
+	require.Len(t, feed.Items, 2)
+	require.Equal(t, "false", feed.Items[0].Custom[service.GUIDPermaLinkKey])
+	require.Equal(t, hashString("https://example.com/post#c1"), service.ComputeEntryHash(feed.Items[0], "", "", false, model.DedupeKeyAuto))
+	require.Equal(t, hashString("https://example.com/two"), service.ComputeEntryHash(feed.Items[1], "", "", false, model.DedupeKeyAuto))
+}
+
+func TestFeedService_UpdateDedupeKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFeeds := mock.NewMockFeedRepository(ctrl)
+	mockEntries := mock.NewMockEntryRepository(ctrl)
+	svc := service.NewFeedService(mockFeeds, nil, mockEntries, nil, nil, nil, nil, nil, nil, nil)
+
+	require.ErrorIs(t, svc.UpdateDedupeKey(context.Background(), 1, "link"), service.ErrInvalid)
+
+	// Unchanged strategy is a no-op
+	mockFeeds.EXPECT().GetByID(gomock.Any(), int64(1)).Return(model.Feed{ID: 1, DedupeKey: model.DedupeKeyURL}, nil)
+	require.NoError(t, svc.UpdateDedupeKey(context.Background(), 1, model.DedupeKeyURL))
+
+	// guid hashes can't be rebuilt from stored rows, so nothing is re-hashed
+	mockFeeds.EXPECT().GetByID(gomock.Any(), int64(1)).Return(model.Feed{ID: 1, DedupeKey: model.DedupeKeyURL}, nil)
+	mockFeeds.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, feed model.Feed) (model.Feed, error) {
+			require.Equal(t, model.DedupeKeyGUID, feed.DedupeKey)
+			return feed, nil
+		},
+	)
+	require.NoError(t, svc.UpdateDedupeKey(context.Background(), 1, model.DedupeKeyGUID))
+
+	rehashed := make(chan string, 1)
+	mockFeeds.EXPECT().GetByID(gomock.Any(), int64(1)).Return(model.Feed{ID: 1, DedupeKey: model.DedupeKeyAuto}, nil)
+	mockFeeds.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, feed model.Feed) (model.Feed, error) { return feed, nil },
+	)
+	mockEntries.EXPECT().Rehash(gomock.Any(), int64(1), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ int64, hash func(link, title, content string) string) (int, error) {
+			rehashed <- hash("https://tracking.example/r/1?url=https://example.com/a", "t", "c")
+			return 0, nil
+		},
+	)
+	require.NoError(t, svc.UpdateDedupeKey(context.Background(), 1, model.DedupeKeyURL))
+	select {
+	case hash := <-rehashed:
+		require.Equal(t, hashString("https://example.com/a"), hash)
+	case <-time.After(time.Second):
+		t.Fatal("rehash did not run")
+	}
+}
+
+func TestFeedService_UpdateAutoTranslate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFeeds := mock.NewMockFeedRepository(ctrl)
+	svc := service.NewFeedService(mockFeeds, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+
+	require.ErrorIs(t, svc.UpdateAutoTranslate(context.Background(), 1, "always"), service.ErrInvalid)
+
+	mockFeeds.EXPECT().GetByID(gomock.Any(), int64(2)).Return(model.Feed{}, sql.ErrNoRows)
+	require.ErrorIs(t, svc.UpdateAutoTranslate(context.Background(), 2, model.FeedAutoTranslateOn), service.ErrNotFound)
+
+	mockFeeds.EXPECT().GetByID(gomock.Any(), int64(1)).Return(model.Feed{ID: 1, AutoTranslate: model.FeedAutoTranslateInherit}, nil)
+	mockFeeds.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, feed model.Feed) (model.Feed, error) {
+			require.Equal(t, model.FeedAutoTranslateOff, feed.AutoTranslate)
+			return feed, nil
+		},
+	)
+	require.NoError(t, svc.UpdateAutoTranslate(context.Background(), 1, model.FeedAutoTranslateOff))
+}
+
 // TestExtractPublishedAt_FallbackToCurrentTime tests the BUG fix:
 // When an RSS item has no pubDate (PublishedParsed) and no UpdatedParsed,
 // extractPublishedAt should return the current time instead of nil.
@@ -582,7 +1300,7 @@ func TestExtractPublishedAt_FallbackToCurrentTime(t *testing.T) {
 	}
 
 	before := time.Now().UTC()
-	got := service.ExtractPublishedAt(item, false)
+	got := service.ExtractPublishedAt(item, false, time.UTC)
 	after := time.Now().UTC()
 
 	require.NotNil(t, got, "extractPublishedAt should return a non-nil time when no date is available")
@@ -598,7 +1316,7 @@ func TestExtractPublishedAt_UsesPublishedParsed(t *testing.T) {
 		PublishedParsed: &published,
 	}
 
-	got := service.ExtractPublishedAt(item, false)
+	got := service.ExtractPublishedAt(item, false, time.UTC)
 	require.NotNil(t, got)
 	require.Equal(t, published.Format(time.RFC3339), got.UTC().Format(time.RFC3339))
 }
@@ -611,7 +1329,7 @@ func TestExtractPublishedAt_UsesUpdatedParsedWhenNoPublished(t *testing.T) {
 		UpdatedParsed: &updated,
 	}
 
-	got := service.ExtractPublishedAt(item, false)
+	got := service.ExtractPublishedAt(item, false, time.UTC)
 	require.NotNil(t, got)
 	require.Equal(t, updated.Format(time.RFC3339), got.UTC().Format(time.RFC3339))
 }
@@ -627,7 +1345,7 @@ func TestExtractPublishedAt_IgnoresDynamicTimeWhenSet(t *testing.T) {
 	// When ignoreDynamicTime is true, UpdatedParsed should be ignored
 	// and the function should fallback to current time
 	before := time.Now().UTC()
-	got := service.ExtractPublishedAt(item, true)
+	got := service.ExtractPublishedAt(item, true, time.UTC)
 	after := time.Now().UTC()
 
 	require.NotNil(t, got)
@@ -636,10 +1354,106 @@ func TestExtractPublishedAt_IgnoresDynamicTimeWhenSet(t *testing.T) {
 		"returned time should be approximately the current time when ignoring dynamic time")
 }
 
+func TestHasZoneInfo(t *testing.T) {
+	for raw, want := range map[string]bool{
+		"Mon, 02 Jan 2006 15:04:05 +0800":       true,
+		"Mon, 02 Jan 2006 15:04:05 GMT":         true,
+		"Mon, 02 Jan 2006 15:04:05 UT":          true,
+		"Mon, 02 Jan 2006 15:04:05 +0800 (CST)": true,
+		"2006-01-02T15:04:05Z":                  true,
+		"2006-01-02T15:04:05.123-07:00":         true,
+		"2006-01-02T15:04:05+08":                true,
+		"Mon, 02 Jan 2006 15:04:05":             false,
+		"2006-01-02 15:04:05":                   false,
+		"2006-01-02T15:04":                      false,
+		"2006-01-02":                            false,
+		"01/02/2006 03:04 PM":                   false,
+	} {
+		require.Equal(t, want, service.HasZoneInfo(raw), raw)
+	}
+}
+
+func TestExtractPublishedAt_AssumesTimezoneForZonelessDates(t *testing.T) {
+	shanghai, err := time.LoadLocation("Asia/Shanghai")
+	require.NoError(t, err)
+	// gofeed reads a date without a zone as UTC wall clock
+	parsed := time.Date(2025, 3, 15, 10, 30, 0, 0, time.UTC)
+
+	zoneless := &gofeed.Item{Published: "Sat, 15 Mar 2025 10:30:00", PublishedParsed: &parsed}
+	got := service.ExtractPublishedAt(zoneless, false, shanghai)
+	require.Equal(t, time.Date(2025, 3, 15, 2, 30, 0, 0, time.UTC), *got)
+	require.Equal(t, time.UTC, got.Location())
+
+	zoned := &gofeed.Item{Published: "Sat, 15 Mar 2025 10:30:00 GMT", PublishedParsed: &parsed}
+	got = service.ExtractPublishedAt(zoned, false, shanghai)
+	require.Equal(t, parsed, *got)
+
+	offset := time.Date(2025, 3, 15, 10, 30, 0, 0, time.FixedZone("", 8*3600))
+	updated := &gofeed.Item{Updated: "2025-03-15T10:30:00+08:00", UpdatedParsed: &offset}
+	got = service.ExtractPublishedAt(updated, false, time.UTC)
+	require.Equal(t, time.Date(2025, 3, 15, 2, 30, 0, 0, time.UTC), *got)
+	require.Equal(t, time.UTC, got.Location())
+
+	filed := service.ExtractDateFromSummary("Filed: 2025-12-17", shanghai)
+	require.Equal(t, time.Date(2025, 12, 16, 16, 0, 0, 0, time.UTC), *filed)
+}
+
+func TestResolvePublishedAt_ClampsImplausibleDates(t *testing.T) {
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	at := func(t time.Time) *time.Time { return &t }
+	epoch := time.Unix(0, 0).UTC()
+
+	tests := []struct {
+		name        string
+		item        *gofeed.Item
+		dynamicTime bool
+		want        time.Time
+		source      string
+		rejected    *time.Time
+	}{
+		{name: "plausible", item: &gofeed.Item{PublishedParsed: at(now.Add(-time.Hour))}, want: now.Add(-time.Hour), source: model.PublishedAtFromFeed},
+		{name: "exactly a day ahead", item: &gofeed.Item{PublishedParsed: at(now.Add(24 * time.Hour))}, want: now.Add(24 * time.Hour), source: model.PublishedAtFromFeed},
+		{name: "over a day ahead", item: &gofeed.Item{PublishedParsed: at(now.Add(24*time.Hour + time.Second))}, want: now, source: model.PublishedAtFromFetch, rejected: at(now.Add(24*time.Hour + time.Second))},
+		{name: "2038", item: &gofeed.Item{PublishedParsed: at(time.Date(2038, 1, 19, 3, 14, 7, 0, time.UTC))}, want: now, source: model.PublishedAtFromFetch, rejected: at(time.Date(2038, 1, 19, 3, 14, 7, 0, time.UTC))},
+		{name: "start of 2000", item: &gofeed.Item{PublishedParsed: at(time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC))}, want: time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC), source: model.PublishedAtFromFeed},
+		{name: "before 2000", item: &gofeed.Item{PublishedParsed: at(time.Date(1999, 12, 31, 23, 59, 59, 0, time.UTC))}, want: now, source: model.PublishedAtFromFetch, rejected: at(time.Date(1999, 12, 31, 23, 59, 59, 0, time.UTC))},
+		{name: "epoch", item: &gofeed.Item{PublishedParsed: &epoch}, want: now, source: model.PublishedAtFromFetch, rejected: &epoch},
+		{name: "epoch falls back to summary", item: &gofeed.Item{Description: "Filed: 2025-12-17", PublishedParsed: &epoch}, want: time.Date(2025, 12, 17, 0, 0, 0, 0, time.UTC), source: model.PublishedAtFromSummary},
+		{name: "epoch falls back to updated", item: &gofeed.Item{PublishedParsed: &epoch, UpdatedParsed: at(now.Add(-time.Hour))}, want: now.Add(-time.Hour), source: model.PublishedAtFromFeed, rejected: &epoch},
+		{name: "epoch with dynamic updated", item: &gofeed.Item{PublishedParsed: &epoch, UpdatedParsed: at(now.Add(-time.Hour))}, dynamicTime: true, want: now, source: model.PublishedAtFromFetch, rejected: &epoch},
+		{name: "epoch updated", item: &gofeed.Item{UpdatedParsed: &epoch}, want: now, source: model.PublishedAtFromFetch, rejected: &epoch},
+		{name: "future summary", item: &gofeed.Item{Description: "Filed: 2038-01-19", PublishedParsed: at(now.Add(-time.Hour))}, want: now, source: model.PublishedAtFromFetch, rejected: at(time.Date(2038, 1, 19, 0, 0, 0, 0, time.UTC))},
+		{name: "no date", item: &gofeed.Item{}, want: now, source: model.PublishedAtFromFetch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := service.ResolvePublishedAt(tt.item, tt.dynamicTime, time.UTC, now)
+			require.Equal(t, tt.want, got.At)
+			require.Equal(t, tt.source, got.Source)
+			require.Equal(t, tt.rejected, got.Rejected)
+		})
+	}
+}
+
+func TestItemToEntry_PublishedAtSource(t *testing.T) {
+	published := time.Date(2025, 3, 15, 10, 30, 0, 0, time.UTC)
+	entry := service.ItemToEntry(model.Feed{ID: 1}, &gofeed.Item{Link: "https://example.com/1", PublishedParsed: &published}, false, time.UTC)
+	require.Equal(t, published, *entry.PublishedAt)
+	require.Equal(t, model.PublishedAtFromFeed, entry.PublishedAtSource)
+
+	epoch := time.Unix(0, 0).UTC()
+	before := time.Now().UTC()
+	entry = service.ItemToEntry(model.Feed{ID: 1}, &gofeed.Item{Link: "https://example.com/2", PublishedParsed: &epoch}, false, time.UTC)
+	require.False(t, entry.PublishedAt.Before(before))
+	require.Equal(t, model.PublishedAtFromFetch, entry.PublishedAtSource)
+}
+
 // settingsServiceStub is a minimal SettingsService implementation for tests.
 type settingsServiceStub struct {
 	fallbackUserAgent string
 	proxyURL          string
+	general           *service.GeneralSettings
 }
 
 type roundTripperFunc func(*http.Request) (*http.Response, error)
@@ -661,7 +1475,7 @@ func (s *settingsServiceStub) TestAI(ctx context.Context, provider, apiKey, base
 }
 
 func (s *settingsServiceStub) GetGeneralSettings(ctx context.Context) (*service.GeneralSettings, error) {
-	return nil, nil
+	return s.general, nil
 }
 
 func (s *settingsServiceStub) SetGeneralSettings(ctx context.Context, settings *service.GeneralSettings) error {
@@ -692,6 +1506,8 @@ func (s *settingsServiceStub) GetIPStack(ctx context.Context) string {
 	return "default"
 }
 
+func (s *settingsServiceStub) OnNetworkSettingsChange(fn func()) {}
+
 func (s *settingsServiceStub) GetAppearanceSettings(ctx context.Context) (*service.AppearanceSettings, error) {
 	return &service.AppearanceSettings{ContentTypes: append([]string(nil), service.DefaultAppearanceContentTypes...)}, nil
 }
@@ -700,6 +1516,14 @@ func (s *settingsServiceStub) SetAppearanceSettings(ctx context.Context, setting
 	return nil
 }
 
+func (s *settingsServiceStub) GetSecuritySettings(ctx context.Context) (*service.SecuritySettings, error) {
+	return &service.SecuritySettings{CookieSameSite: "lax"}, nil
+}
+
+func (s *settingsServiceStub) SetSecuritySettings(ctx context.Context, settings *service.SecuritySettings) error {
+	return nil
+}
+
 func TestFeedService_List_Success(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -712,13 +1536,27 @@ func TestFeedService_List_Success(t *testing.T) {
 	}
 	mockFeeds.EXPECT().List(gomock.Any(), (*int64)(nil)).Return(feeds, nil)
 
-	svc := service.NewFeedService(mockFeeds, nil, nil, nil, nil, nil, nil)
+	svc := service.NewFeedService(mockFeeds, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 	result, err := svc.List(context.Background(), nil)
 	require.NoError(t, err)
 	require.Len(t, result, 2)
 	require.Equal(t, int64(1), result[0].ID)
 }
 
+func TestFeedService_GetActivityStats(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFeeds := mock.NewMockFeedRepository(ctrl)
+	stats := []model.FeedActivityStats{{FeedID: 1, EntriesLastWeek: 3, TotalEntries: 9}}
+	mockFeeds.EXPECT().GetActivityStats(gomock.Any()).Return(stats, nil)
+
+	svc := service.NewFeedService(mockFeeds, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	result, err := svc.GetActivityStats(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, stats, result)
+}
+
 func TestFeedService_List_WithFolderID(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -729,7 +1567,7 @@ func TestFeedService_List_WithFolderID(t *testing.T) {
 	feeds := []model.Feed{{ID: 1, Title: "Feed 1"}}
 	mockFeeds.EXPECT().List(gomock.Any(), &folderID).Return(feeds, nil)
 
-	svc := service.NewFeedService(mockFeeds, nil, nil, nil, nil, nil, nil)
+	svc := service.NewFeedService(mockFeeds, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 	result, err := svc.List(context.Background(), &folderID)
 	require.NoError(t, err)
 	require.Len(t, result, 1)
@@ -744,7 +1582,7 @@ func TestFeedService_List_Error(t *testing.T) {
 	dbErr := errors.New("db list error")
 	mockFeeds.EXPECT().List(gomock.Any(), (*int64)(nil)).Return(nil, dbErr)
 
-	svc := service.NewFeedService(mockFeeds, nil, nil, nil, nil, nil, nil)
+	svc := service.NewFeedService(mockFeeds, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 	_, err := svc.List(context.Background(), nil)
 	require.ErrorIs(t, err, dbErr)
 }
@@ -763,8 +1601,8 @@ func TestFeedService_Add_FolderGetByIDError(t *testing.T) {
 	mockFeeds.EXPECT().FindByURL(gomock.Any(), feedURL).Return(nil, nil)
 	mockFolders.EXPECT().GetByID(gomock.Any(), folderID).Return(model.Folder{}, dbErr)
 
-	svc := service.NewFeedService(mockFeeds, mockFolders, nil, nil, nil, nil, nil)
-	_, err := svc.Add(context.Background(), feedURL, &folderID, "", "article")
+	svc := service.NewFeedService(mockFeeds, mockFolders, nil, nil, nil, nil, nil, nil, nil, nil)
+	_, err := svc.Add(context.Background(), feedURL, &folderID, "", "article", service.InitialBackfill{})
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "check folder")
 }
@@ -793,8 +1631,8 @@ func TestFeedService_Add_CreateError(t *testing.T) {
 	mockFeeds.EXPECT().Create(gomock.Any(), gomock.Any()).Return(model.Feed{}, errors.New("create error"))
 
 	clientFactory := network.NewClientFactoryForTest(client)
-	svc := service.NewFeedService(mockFeeds, mockFolders, mockEntries, nil, nil, clientFactory, nil)
-	_, err := svc.Add(context.Background(), feedURL, nil, "", "article")
+	svc := service.NewFeedService(mockFeeds, mockFolders, mockEntries, nil, nil, clientFactory, nil, nil, nil, nil)
+	_, err := svc.Add(context.Background(), feedURL, nil, "", "article", service.InitialBackfill{})
 	require.Error(t, err)
 }
 
@@ -821,16 +1659,17 @@ func TestFeedService_Add_TitleOverride(t *testing.T) {
 	mockFeeds.EXPECT().FindByURL(gomock.Any(), feedURL).Return(nil, nil)
 	mockFeeds.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(
 		func(_ context.Context, feed model.Feed) (model.Feed, error) {
-			require.Equal(t, "Custom Title", feed.Title)
+			require.Equal(t, "Test Feed", feed.Title)
+			require.Equal(t, "Custom Title", *feed.CustomTitle)
 			feed.ID = 123
 			return feed, nil
 		},
 	)
-	mockEntries.EXPECT().CreateOrUpdate(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockEntries.EXPECT().CreateOrUpdate(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
 
 	clientFactory := network.NewClientFactoryForTest(client)
-	svc := service.NewFeedService(mockFeeds, mockFolders, mockEntries, nil, nil, clientFactory, nil)
-	feed, err := svc.Add(context.Background(), feedURL, nil, "Custom Title", "article")
+	svc := service.NewFeedService(mockFeeds, mockFolders, mockEntries, nil, nil, clientFactory, nil, nil, nil, nil)
+	feed, err := svc.Add(context.Background(), feedURL, nil, "Custom Title", "article", service.InitialBackfill{})
 	require.NoError(t, err)
 	require.Equal(t, int64(123), feed.ID)
 }
@@ -839,7 +1678,7 @@ func TestFeedService_AddWithoutFetch_InvalidURL(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	svc := service.NewFeedService(nil, nil, nil, nil, nil, nil, nil)
+	svc := service.NewFeedService(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 	_, _, err := svc.AddWithoutFetch(context.Background(), "invalid", nil, "", "article")
 	require.ErrorIs(t, err, service.ErrInvalid)
 }
@@ -853,7 +1692,7 @@ func TestFeedService_AddWithoutFetch_FindByURLError(t *testing.T) {
 	feedURL := "https://example.com/rss"
 	mockFeeds.EXPECT().FindByURL(gomock.Any(), feedURL).Return(nil, errors.New("db error"))
 
-	svc := service.NewFeedService(mockFeeds, nil, nil, nil, nil, nil, nil)
+	svc := service.NewFeedService(mockFeeds, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 	_, _, err := svc.AddWithoutFetch(context.Background(), feedURL, nil, "", "article")
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "check feed url")
@@ -872,7 +1711,7 @@ func TestFeedService_AddWithoutFetch_FolderNotFound(t *testing.T) {
 	mockFeeds.EXPECT().FindByURL(gomock.Any(), feedURL).Return(nil, nil)
 	mockFolders.EXPECT().GetByID(gomock.Any(), folderID).Return(model.Folder{}, sql.ErrNoRows)
 
-	svc := service.NewFeedService(mockFeeds, mockFolders, nil, nil, nil, nil, nil)
+	svc := service.NewFeedService(mockFeeds, mockFolders, nil, nil, nil, nil, nil, nil, nil, nil)
 	_, _, err := svc.AddWithoutFetch(context.Background(), feedURL, &folderID, "", "article")
 	require.ErrorIs(t, err, service.ErrNotFound)
 }
@@ -890,7 +1729,7 @@ func TestFeedService_AddWithoutFetch_FolderGetByIDError(t *testing.T) {
 	mockFeeds.EXPECT().FindByURL(gomock.Any(), feedURL).Return(nil, nil)
 	mockFolders.EXPECT().GetByID(gomock.Any(), folderID).Return(model.Folder{}, errors.New("db error"))
 
-	svc := service.NewFeedService(mockFeeds, mockFolders, nil, nil, nil, nil, nil)
+	svc := service.NewFeedService(mockFeeds, mockFolders, nil, nil, nil, nil, nil, nil, nil, nil)
 	_, _, err := svc.AddWithoutFetch(context.Background(), feedURL, &folderID, "", "article")
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "check folder")
@@ -907,7 +1746,7 @@ func TestFeedService_AddWithoutFetch_CreateError(t *testing.T) {
 	mockFeeds.EXPECT().FindByURL(gomock.Any(), feedURL).Return(nil, nil)
 	mockFeeds.EXPECT().Create(gomock.Any(), gomock.Any()).Return(model.Feed{}, errors.New("create error"))
 
-	svc := service.NewFeedService(mockFeeds, nil, nil, nil, nil, nil, nil)
+	svc := service.NewFeedService(mockFeeds, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 	_, _, err := svc.AddWithoutFetch(context.Background(), feedURL, nil, "", "article")
 	require.Error(t, err)
 }
@@ -920,8 +1759,8 @@ func TestFeedService_Update_GetByIDError(t *testing.T) {
 
 	mockFeeds.EXPECT().GetByID(gomock.Any(), int64(1)).Return(model.Feed{}, errors.New("db error"))
 
-	svc := service.NewFeedService(mockFeeds, nil, nil, nil, nil, nil, nil)
-	_, err := svc.Update(context.Background(), 1, "Title", nil, nil)
+	svc := service.NewFeedService(mockFeeds, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	_, err := svc.Update(context.Background(), 1, "Title", nil, nil, nil)
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "get feed")
 }
@@ -935,8 +1774,8 @@ func TestFeedService_Update_UpdateError(t *testing.T) {
 	mockFeeds.EXPECT().GetByID(gomock.Any(), int64(1)).Return(model.Feed{ID: 1, Title: "Old"}, nil)
 	mockFeeds.EXPECT().Update(gomock.Any(), gomock.Any()).Return(model.Feed{}, errors.New("update error"))
 
-	svc := service.NewFeedService(mockFeeds, nil, nil, nil, nil, nil, nil)
-	_, err := svc.Update(context.Background(), 1, "New Title", nil, nil)
+	svc := service.NewFeedService(mockFeeds, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	_, err := svc.Update(context.Background(), 1, "New Title", nil, nil, nil)
 	require.Error(t, err)
 }
 
@@ -960,8 +1799,8 @@ func TestFeedService_Update_SummaryPromptReminder(t *testing.T) {
 		},
 	)
 
-	svc := service.NewFeedService(mockFeeds, nil, nil, nil, nil, nil, nil)
-	updated, err := svc.Update(context.Background(), 1, "New Title", nil, &rawReminder)
+	svc := service.NewFeedService(mockFeeds, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	updated, err := svc.Update(context.Background(), 1, "New Title", nil, &rawReminder, nil)
 	require.NoError(t, err)
 	require.NotNil(t, updated.SummaryPromptReminder)
 	require.Equal(t, "关注数据和关键结论", *updated.SummaryPromptReminder)
@@ -988,13 +1827,13 @@ func TestFeedService_Update_SummaryPromptReminderClearAndValidate(t *testing.T)
 	)
 
 	clearReminder := "   "
-	svc := service.NewFeedService(mockFeeds, nil, nil, nil, nil, nil, nil)
-	updated, err := svc.Update(context.Background(), 1, "New Title", nil, &clearReminder)
+	svc := service.NewFeedService(mockFeeds, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	updated, err := svc.Update(context.Background(), 1, "New Title", nil, &clearReminder, nil)
 	require.NoError(t, err)
 	require.Nil(t, updated.SummaryPromptReminder)
 
 	tooLongReminder := strings.Repeat("a", 2001)
-	_, err = svc.Update(context.Background(), 1, "New Title", nil, &tooLongReminder)
+	_, err = svc.Update(context.Background(), 1, "New Title", nil, &tooLongReminder, nil)
 	require.ErrorIs(t, err, service.ErrInvalid)
 }
 
@@ -1011,8 +1850,8 @@ func TestFeedService_Update_FolderNotFound(t *testing.T) {
 	// 然后获取 folder 失败
 	mockFolders.EXPECT().GetByID(gomock.Any(), folderID).Return(model.Folder{}, sql.ErrNoRows)
 
-	svc := service.NewFeedService(mockFeeds, mockFolders, nil, nil, nil, nil, nil)
-	_, err := svc.Update(context.Background(), 1, "Title", &folderID, nil)
+	svc := service.NewFeedService(mockFeeds, mockFolders, nil, nil, nil, nil, nil, nil, nil, nil)
+	_, err := svc.Update(context.Background(), 1, "Title", &folderID, nil, nil)
 	require.ErrorIs(t, err, service.ErrNotFound)
 }
 
@@ -1024,7 +1863,7 @@ func TestFeedService_Delete_GetByIDError(t *testing.T) {
 
 	mockFeeds.EXPECT().GetByID(gomock.Any(), int64(1)).Return(model.Feed{}, errors.New("db error"))
 
-	svc := service.NewFeedService(mockFeeds, nil, nil, nil, nil, nil, nil)
+	svc := service.NewFeedService(mockFeeds, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 	err := svc.Delete(context.Background(), 1)
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "get feed")
@@ -1039,7 +1878,7 @@ func TestFeedService_Delete_DeleteError(t *testing.T) {
 	mockFeeds.EXPECT().GetByID(gomock.Any(), int64(1)).Return(model.Feed{ID: 1}, nil)
 	mockFeeds.EXPECT().Delete(gomock.Any(), int64(1)).Return(errors.New("delete error"))
 
-	svc := service.NewFeedService(mockFeeds, nil, nil, nil, nil, nil, nil)
+	svc := service.NewFeedService(mockFeeds, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 	err := svc.Delete(context.Background(), 1)
 	require.Error(t, err)
 }
@@ -1052,7 +1891,7 @@ func TestFeedService_UpdateType_GetByIDError(t *testing.T) {
 
 	mockFeeds.EXPECT().GetByID(gomock.Any(), int64(1)).Return(model.Feed{}, errors.New("db error"))
 
-	svc := service.NewFeedService(mockFeeds, nil, nil, nil, nil, nil, nil)
+	svc := service.NewFeedService(mockFeeds, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 	err := svc.UpdateType(context.Background(), 1, "picture")
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "get feed")
@@ -1064,14 +1903,105 @@ func TestFeedService_UpdateType_UpdateTypeError(t *testing.T) {
 
 	mockFeeds := mock.NewMockFeedRepository(ctrl)
 
-	mockFeeds.EXPECT().GetByID(gomock.Any(), int64(1)).Return(model.Feed{ID: 1}, nil)
-	mockFeeds.EXPECT().UpdateType(gomock.Any(), int64(1), "picture").Return(errors.New("update type error"))
+	mockFeeds.EXPECT().GetByID(gomock.Any(), int64(1)).Return(model.Feed{ID: 1, Type: "article"}, nil)
+	mockFeeds.EXPECT().Update(gomock.Any(), gomock.Any()).Return(model.Feed{}, errors.New("update type error"))
 
-	svc := service.NewFeedService(mockFeeds, nil, nil, nil, nil, nil, nil)
+	svc := service.NewFeedService(mockFeeds, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 	err := svc.UpdateType(context.Background(), 1, "picture")
 	require.Error(t, err)
 }
 
+func TestFeedService_UpdateAssumeTimezone(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFeeds := mock.NewMockFeedRepository(ctrl)
+	svc := service.NewFeedService(mockFeeds, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+
+	mockFeeds.EXPECT().GetByID(gomock.Any(), int64(1)).Return(model.Feed{ID: 1}, nil)
+	tz := " Asia/Shanghai "
+	mockFeeds.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, feed model.Feed) (model.Feed, error) {
+			require.Equal(t, "Asia/Shanghai", *feed.AssumeTimezone)
+			return feed, nil
+		},
+	)
+	require.NoError(t, svc.UpdateAssumeTimezone(context.Background(), 1, &tz))
+
+	// Empty clears the override
+	empty := ""
+	mockFeeds.EXPECT().GetByID(gomock.Any(), int64(1)).Return(model.Feed{ID: 1, AssumeTimezone: stringPtr("Asia/Shanghai")}, nil)
+	mockFeeds.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, feed model.Feed) (model.Feed, error) {
+			require.Nil(t, feed.AssumeTimezone)
+			return feed, nil
+		},
+	)
+	require.NoError(t, svc.UpdateAssumeTimezone(context.Background(), 1, &empty))
+
+	invalid := "Mars/Olympus"
+	require.ErrorIs(t, svc.UpdateAssumeTimezone(context.Background(), 1, &invalid), service.ErrInvalid)
+}
+
+func TestFeedService_Reorder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFeeds := mock.NewMockFeedRepository(ctrl)
+	svc := service.NewFeedService(mockFeeds, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	ctx := context.Background()
+	folderID := int64(10)
+
+	require.ErrorIs(t, svc.Reorder(ctx, []int64{}), service.ErrInvalid)
+	require.ErrorIs(t, svc.Reorder(ctx, []int64{1, 2, 1}), service.ErrInvalid)
+
+	mockFeeds.EXPECT().GetByIDs(ctx, []int64{2, 1}).Return([]model.Feed{{ID: 1, FolderID: &folderID}, {ID: 2, FolderID: &folderID}}, nil)
+	mockFeeds.EXPECT().Reorder(ctx, []int64{2, 1}).Return(nil)
+	require.NoError(t, svc.Reorder(ctx, []int64{2, 1}))
+
+	mockFeeds.EXPECT().GetByIDs(ctx, []int64{1, 3}).Return([]model.Feed{{ID: 1, FolderID: &folderID}, {ID: 3}}, nil)
+	require.ErrorIs(t, svc.Reorder(ctx, []int64{1, 3}), service.ErrInvalid)
+
+	mockFeeds.EXPECT().GetByIDs(ctx, []int64{1, 9}).Return([]model.Feed{{ID: 1, FolderID: &folderID}}, nil)
+	require.ErrorIs(t, svc.Reorder(ctx, []int64{1, 9}), service.ErrNotFound)
+}
+
+func TestFeedService_PauseAndUnpause(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFeeds := mock.NewMockFeedRepository(ctrl)
+	svc := service.NewFeedService(mockFeeds, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+
+	until := time.Now().Add(24 * time.Hour)
+	mockFeeds.EXPECT().GetByID(gomock.Any(), int64(1)).Return(model.Feed{ID: 1}, nil)
+	mockFeeds.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, feed model.Feed) (model.Feed, error) {
+			require.True(t, until.Equal(*feed.PausedUntil))
+			return feed, nil
+		},
+	)
+	require.NoError(t, svc.Pause(context.Background(), 1, until))
+
+	mockFeeds.EXPECT().GetByID(gomock.Any(), int64(1)).Return(model.Feed{ID: 1, PausedUntil: &until}, nil)
+	mockFeeds.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, feed model.Feed) (model.Feed, error) {
+			require.Nil(t, feed.PausedUntil)
+			return feed, nil
+		},
+	)
+	require.NoError(t, svc.Unpause(context.Background(), 1))
+
+	// Unpausing a feed that is not paused writes nothing
+	mockFeeds.EXPECT().GetByID(gomock.Any(), int64(1)).Return(model.Feed{ID: 1}, nil)
+	require.NoError(t, svc.Unpause(context.Background(), 1))
+
+	require.ErrorIs(t, svc.Pause(context.Background(), 1, time.Now().Add(-time.Minute)), service.ErrInvalid)
+
+	mockFeeds.EXPECT().GetByID(gomock.Any(), int64(2)).Return(model.Feed{}, sql.ErrNoRows)
+	require.ErrorIs(t, svc.Unpause(context.Background(), 2), service.ErrNotFound)
+}
+
 func TestFeedService_Preview_HTTPError(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -1089,7 +2019,7 @@ func TestFeedService_Preview_HTTPError(t *testing.T) {
 	}
 
 	clientFactory := network.NewClientFactoryForTest(client)
-	svc := service.NewFeedService(nil, nil, nil, nil, nil, clientFactory, nil)
+	svc := service.NewFeedService(nil, nil, nil, nil, nil, clientFactory, nil, nil, nil, nil)
 	_, err := svc.Preview(context.Background(), feedURL)
 	require.ErrorIs(t, err, service.ErrFeedFetch)
 }
@@ -1106,7 +2036,7 @@ func TestFeedService_Preview_NetworkError(t *testing.T) {
 	}
 
 	clientFactory := network.NewClientFactoryForTest(client)
-	svc := service.NewFeedService(nil, nil, nil, nil, nil, clientFactory, nil)
+	svc := service.NewFeedService(nil, nil, nil, nil, nil, clientFactory, nil, nil, nil, nil)
 	_, err := svc.Preview(context.Background(), feedURL)
 	require.ErrorIs(t, err, service.ErrFeedFetch)
 }
@@ -1128,7 +2058,7 @@ func TestFeedService_Preview_ParseError(t *testing.T) {
 	}
 
 	clientFactory := network.NewClientFactoryForTest(client)
-	svc := service.NewFeedService(nil, nil, nil, nil, nil, clientFactory, nil)
+	svc := service.NewFeedService(nil, nil, nil, nil, nil, clientFactory, nil, nil, nil, nil)
 	_, err := svc.Preview(context.Background(), feedURL)
 	require.ErrorIs(t, err, service.ErrFeedFetch)
 }
@@ -1165,11 +2095,11 @@ func TestFeedService_Add_IconFetchError(t *testing.T) {
 		FetchAndSaveIcon(gomock.Any(), "https://example.com/icon.png", "https://example.com").
 		Return("", errors.New("icon fetch error"))
 
-	mockEntries.EXPECT().CreateOrUpdate(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockEntries.EXPECT().CreateOrUpdate(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
 
 	clientFactory := network.NewClientFactoryForTest(client)
-	svc := service.NewFeedService(mockFeeds, nil, mockEntries, mockIcons, nil, clientFactory, nil)
-	feed, err := svc.Add(context.Background(), feedURL, nil, "", "article")
+	svc := service.NewFeedService(mockFeeds, nil, mockEntries, mockIcons, nil, clientFactory, nil, nil, nil, nil)
+	feed, err := svc.Add(context.Background(), feedURL, nil, "", "article", service.InitialBackfill{})
 	require.NoError(t, err)
 	require.Nil(t, feed.IconPath)
 }
@@ -1204,8 +2134,8 @@ func TestFeedService_Add_TypeMismatch(t *testing.T) {
 	mockFolders.EXPECT().GetByID(gomock.Any(), folderID).Return(model.Folder{ID: folderID, Type: "picture"}, nil)
 
 	clientFactory := network.NewClientFactoryForTest(client)
-	svc := service.NewFeedService(mockFeeds, mockFolders, mockEntries, nil, nil, clientFactory, nil)
-	_, err := svc.Add(context.Background(), feedURL, &folderID, "", "article")
+	svc := service.NewFeedService(mockFeeds, mockFolders, mockEntries, nil, nil, clientFactory, nil, nil, nil, nil)
+	_, err := svc.Add(context.Background(), feedURL, &folderID, "", "article", service.InitialBackfill{})
 	require.ErrorIs(t, err, service.ErrInvalid)
 }
 
@@ -1224,7 +2154,7 @@ func TestFeedService_AddWithoutFetch_TypeMismatch(t *testing.T) {
 	// Folder 是 article 类型，但 feed 是 picture 类型
 	mockFolders.EXPECT().GetByID(gomock.Any(), folderID).Return(model.Folder{ID: folderID, Type: "article"}, nil)
 
-	svc := service.NewFeedService(mockFeeds, mockFolders, nil, nil, nil, nil, nil)
+	svc := service.NewFeedService(mockFeeds, mockFolders, nil, nil, nil, nil, nil, nil, nil, nil)
 	_, _, err := svc.AddWithoutFetch(context.Background(), feedURL, &folderID, "", "picture")
 	require.ErrorIs(t, err, service.ErrInvalid)
 }
@@ -1244,8 +2174,8 @@ func TestFeedService_Update_TypeMismatch(t *testing.T) {
 	// 获取当前 feed
 	mockFeeds.EXPECT().GetByID(gomock.Any(), int64(1)).Return(model.Feed{ID: 1, Title: "Feed Title", Type: "article"}, nil)
 
-	svc := service.NewFeedService(mockFeeds, mockFolders, nil, nil, nil, nil, nil)
-	_, err := svc.Update(context.Background(), 1, "Feed Title", &newFolderID, nil)
+	svc := service.NewFeedService(mockFeeds, mockFolders, nil, nil, nil, nil, nil, nil, nil, nil)
+	_, err := svc.Update(context.Background(), 1, "Feed Title", &newFolderID, nil, nil)
 	require.ErrorIs(t, err, service.ErrInvalid)
 }
 
@@ -1272,8 +2202,8 @@ func TestFeedService_Update_SameTypeSucceeds(t *testing.T) {
 		},
 	)
 
-	svc := service.NewFeedService(mockFeeds, mockFolders, nil, nil, nil, nil, nil)
-	feed, err := svc.Update(context.Background(), 1, "Feed Title", &newFolderID, nil)
+	svc := service.NewFeedService(mockFeeds, mockFolders, nil, nil, nil, nil, nil, nil, nil, nil)
+	feed, err := svc.Update(context.Background(), 1, "Feed Title", &newFolderID, nil, nil)
 	require.NoError(t, err)
 	require.Equal(t, &newFolderID, feed.FolderID)
 }
@@ -1303,14 +2233,14 @@ func TestFeedService_Update_SameFolderNoTypeCheck(t *testing.T) {
 	// 应该直接更新
 	mockFeeds.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(
 		func(_ context.Context, feed model.Feed) (model.Feed, error) {
-			require.Equal(t, "New Title", feed.Title)
+			require.Equal(t, "New Title", feed.DisplayTitle())
 			require.Equal(t, &folderID, feed.FolderID)
 			return feed, nil
 		},
 	)
 
-	svc := service.NewFeedService(mockFeeds, mockFolders, nil, nil, nil, nil, nil)
-	feed, err := svc.Update(context.Background(), 1, "New Title", &folderID, nil)
+	svc := service.NewFeedService(mockFeeds, mockFolders, nil, nil, nil, nil, nil, nil, nil, nil)
+	feed, err := svc.Update(context.Background(), 1, "New Title", &folderID, nil, nil)
 	require.NoError(t, err)
 	require.Equal(t, &folderID, feed.FolderID)
 }
@@ -1342,8 +2272,8 @@ func TestFeedService_Update_FromNullToFolder(t *testing.T) {
 		},
 	)
 
-	svc := service.NewFeedService(mockFeeds, mockFolders, nil, nil, nil, nil, nil)
-	_, err := svc.Update(context.Background(), 1, "Feed Title", &folderID, nil)
+	svc := service.NewFeedService(mockFeeds, mockFolders, nil, nil, nil, nil, nil, nil, nil, nil)
+	_, err := svc.Update(context.Background(), 1, "Feed Title", &folderID, nil, nil)
 	require.NoError(t, err)
 }
 
@@ -1375,8 +2305,8 @@ func TestFeedService_Update_FromFolderToNull(t *testing.T) {
 		},
 	)
 
-	svc := service.NewFeedService(mockFeeds, mockFolders, nil, nil, nil, nil, nil)
-	_, err := svc.Update(context.Background(), 1, "Feed Title", nil, nil)
+	svc := service.NewFeedService(mockFeeds, mockFolders, nil, nil, nil, nil, nil, nil, nil, nil)
+	_, err := svc.Update(context.Background(), 1, "Feed Title", nil, nil, nil)
 	require.NoError(t, err)
 }
 
@@ -1421,12 +2351,41 @@ func TestFeedService_Add_SameTypeSucceeds(t *testing.T) {
 		},
 	)
 
-	mockEntries.EXPECT().CreateOrUpdate(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockEntries.EXPECT().CreateOrUpdate(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
 
 	clientFactory := network.NewClientFactoryForTest(client)
-	svc := service.NewFeedService(mockFeeds, mockFolders, mockEntries, nil, nil, clientFactory, nil)
-	feed, err := svc.Add(context.Background(), feedURL, &folderID, "", "picture")
+	svc := service.NewFeedService(mockFeeds, mockFolders, mockEntries, nil, nil, clientFactory, nil, nil, nil, nil)
+	feed, err := svc.Add(context.Background(), feedURL, &folderID, "", "picture", service.InitialBackfill{})
 	require.NoError(t, err)
 	require.Equal(t, int64(123), feed.ID)
 	require.Equal(t, "picture", createdFeed.Type)
 }
+
+func TestFeedService_AddStatic(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFeeds := mock.NewMockFeedRepository(ctrl)
+	svc := service.NewFeedService(mockFeeds, mock.NewMockFolderRepository(ctrl), mock.NewMockEntryRepository(ctrl), nil, nil, nil, nil, nil, nil, nil)
+
+	parsed, err := service.ParseStaticFeed([]byte(sampleRSS))
+	require.NoError(t, err)
+
+	mockFeeds.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, feed model.Feed) (model.Feed, error) {
+			require.True(t, service.IsStaticFeed(feed))
+			require.Equal(t, "Test Feed", feed.Title)
+			require.Equal(t, "https://example.com", *feed.SiteURL)
+			feed.ID = 7
+			return feed, nil
+		},
+	)
+	feed, err := svc.AddStatic(context.Background(), parsed, nil, "", service.FeedTypeAuto)
+	require.NoError(t, err)
+	require.Equal(t, int64(7), feed.ID)
+	require.Equal(t, "article", feed.Type)
+
+	parsed.Title = ""
+	_, err = svc.AddStatic(context.Background(), parsed, nil, "", "article")
+	require.ErrorIs(t, err, service.ErrInvalid)
+}
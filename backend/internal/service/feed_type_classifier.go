@@ -0,0 +1,89 @@
+package service
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mmcdole/gofeed"
+
+	"gist/backend/pkg/sanitizer"
+)
+
+// FeedTypeAuto asks Add to pick the feed type from the fetched items.
+const FeedTypeAuto = "auto"
+
+const (
+	// classifySampleSize is how many leading items are inspected.
+	classifySampleSize = 10
+	// pictureTextPerImage is the most text (in runes) an item may carry per image
+	// and still count as image-dominated.
+	pictureTextPerImage = 140
+	// pictureItemRatio is the share of sampled items that must be image-dominated.
+	pictureItemRatio = 0.6
+)
+
+var imgTagRegex = regexp.MustCompile(`(?i)<img\b`)
+
+// classifyFeedType suggests "picture" when most sampled items are dominated by
+// images with little text, and "article" otherwise.
+func classifyFeedType(items []*gofeed.Item) string {
+	if len(items) > classifySampleSize {
+		items = items[:classifySampleSize]
+	}
+
+	sampled, pictures := 0, 0
+	for _, item := range items {
+		if item == nil {
+			continue
+		}
+		sampled++
+		if isPictureItem(item) {
+			pictures++
+		}
+	}
+
+	if sampled == 0 || float64(pictures)/float64(sampled) < pictureItemRatio {
+		return "article"
+	}
+	return "picture"
+}
+
+func isPictureItem(item *gofeed.Item) bool {
+	content := item.Content
+	if content == "" {
+		content = item.Description
+	}
+
+	images := len(imgTagRegex.FindAllStringIndex(content, -1))
+	if images == 0 && hasMediaImage(item) {
+		images = 1
+	}
+	if images == 0 {
+		return false
+	}
+
+	textLen := utf8.RuneCountInString(strings.Join(strings.Fields(sanitizer.StripTags(content)), " "))
+	return textLen <= images*pictureTextPerImage
+}
+
+// hasMediaImage reports whether the item carries an image enclosure or
+// media:content. media:thumbnail is ignored as article feeds commonly set it.
+func hasMediaImage(item *gofeed.Item) bool {
+	for _, enc := range item.Enclosures {
+		if strings.HasPrefix(enc.Type, "image/") && strings.TrimSpace(enc.URL) != "" {
+			return true
+		}
+	}
+	if media, ok := item.Extensions["media"]; ok {
+		for _, c := range media["content"] {
+			if strings.TrimSpace(c.Attrs["url"]) == "" {
+				continue
+			}
+			if strings.HasPrefix(c.Attrs["type"], "image/") || c.Attrs["medium"] == "image" {
+				return true
+			}
+		}
+	}
+	return false
+}
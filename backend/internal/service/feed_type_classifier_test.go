@@ -0,0 +1,64 @@
+package service_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mmcdole/gofeed"
+	ext "github.com/mmcdole/gofeed/extensions"
+	"github.com/stretchr/testify/require"
+
+	"gist/backend/internal/service"
+)
+
+func repeatItems(n int, item gofeed.Item) []*gofeed.Item {
+	items := make([]*gofeed.Item, n)
+	for i := range items {
+		copied := item
+		items[i] = &copied
+	}
+	return items
+}
+
+func TestClassifyFeedType(t *testing.T) {
+	longText := "<p>" + strings.Repeat("Lorem ipsum dolor sit amet. ", 40) + "</p>"
+	imageOnly := gofeed.Item{Description: `<p><img src="https://example.com/1.jpg"><img src="https://example.com/2.jpg"></p>`}
+	captioned := gofeed.Item{Content: `<img src="https://example.com/1.jpg"><p>A short caption</p>`}
+	article := gofeed.Item{Content: longText}
+	illustratedArticle := gofeed.Item{Content: `<img src="https://example.com/hero.jpg">` + longText}
+	enclosure := gofeed.Item{
+		Description: "tags: cat, sky",
+		Enclosures:  []*gofeed.Enclosure{{URL: "https://example.com/1.png", Type: "image/png"}},
+	}
+	mediaContent := gofeed.Item{
+		Extensions: ext.Extensions{"media": {"content": {{Attrs: map[string]string{"url": "https://example.com/1.jpg", "medium": "image"}}}}},
+	}
+	thumbnailOnly := gofeed.Item{
+		Description: "Short news blurb",
+		Extensions:  ext.Extensions{"media": {"thumbnail": {{Attrs: map[string]string{"url": "https://example.com/t.jpg"}}}}},
+	}
+
+	cases := []struct {
+		name  string
+		items []*gofeed.Item
+		want  string
+	}{
+		{name: "empty", items: nil, want: "article"},
+		{name: "image only", items: repeatItems(5, imageOnly), want: "picture"},
+		{name: "captioned images", items: repeatItems(5, captioned), want: "picture"},
+		{name: "long text", items: repeatItems(5, article), want: "article"},
+		{name: "article with hero image", items: repeatItems(5, illustratedArticle), want: "article"},
+		{name: "image enclosures", items: repeatItems(5, enclosure), want: "picture"},
+		{name: "media content", items: repeatItems(5, mediaContent), want: "picture"},
+		{name: "media thumbnail ignored", items: repeatItems(5, thumbnailOnly), want: "article"},
+		{name: "mostly pictures", items: append(repeatItems(7, imageOnly), repeatItems(3, article)...), want: "picture"},
+		{name: "mostly articles", items: append(repeatItems(5, imageOnly), repeatItems(5, article)...), want: "article"},
+		{name: "only first ten sampled", items: append(repeatItems(10, article), repeatItems(20, imageOnly)...), want: "article"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.want, service.ClassifyFeedType(tc.items))
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"gist/backend/internal/model"
+	"gist/backend/pkg/logger"
+)
+
+// feedFetchLogKeep is how many of a feed's newest refresh attempts are kept.
+const feedFetchLogKeep = 50
+
+// feedFetchErroringRun is how many failed attempts in a row, with none since
+// succeeding, make a feed erroring rather than unstable.
+const feedFetchErroringRun = 3
+
+// feedFetchStableRatio is the share of successful attempts from which a feed
+// with the odd failure still counts as ok.
+const feedFetchStableRatio = 0.9
+
+// fetchLogWriteTimeout bounds a background fetch log write.
+const fetchLogWriteTimeout = 5 * time.Second
+
+// Feed fetch health, derived from the recent refresh attempts.
+const (
+	FeedFetchHealthUnknown  = "unknown"
+	FeedFetchHealthOK       = "ok"
+	FeedFetchHealthUnstable = "unstable"
+	FeedFetchHealthErroring = "erroring"
+)
+
+// FeedFetchLog is the recent refresh history of a feed.
+type FeedFetchLog struct {
+	// Status is one of the FeedFetchHealth* values.
+	Status string
+	// SuccessRatio is the share of Fetches that got the feed or a 304.
+	SuccessRatio float64
+	Fetches      []model.FeedFetch
+}
+
+// feedFetchHealth derives a feed's health from its recent attempts. A feed
+// failing now and then is unstable instead of switching between ok and
+// erroring with every refresh; only a run of failures makes it erroring.
+func feedFetchHealth(stats model.FeedFetchStats) string {
+	switch {
+	case stats.Attempts == 0:
+		return FeedFetchHealthUnknown
+	case stats.TrailingFailures >= min(feedFetchErroringRun, stats.Attempts):
+		return FeedFetchHealthErroring
+	case float64(stats.Successes) >= feedFetchStableRatio*float64(stats.Attempts):
+		return FeedFetchHealthOK
+	default:
+		return FeedFetchHealthUnstable
+	}
+}
+
+// fetchStats totals fetches, newest first.
+func fetchStats(feedID int64, fetches []model.FeedFetch) model.FeedFetchStats {
+	stats := model.FeedFetchStats{FeedID: feedID, Attempts: len(fetches)}
+	trailing := true
+	for _, fetch := range fetches {
+		if fetch.Succeeded() {
+			stats.Successes++
+			trailing = false
+		} else if trailing {
+			stats.TrailingFailures++
+		}
+	}
+	return stats
+}
+
+// recordFetch appends one refresh attempt of feed to its fetch log in the
+// background. Logging must never fail a refresh, so errors are only logged.
+func (s *refreshService) recordFetch(feed model.Feed, started time.Time, attempt anubisAttempt, outcome string) {
+	if s.fetchLog == nil {
+		return
+	}
+	fetch := model.FeedFetch{
+		FeedID:     feed.ID,
+		FetchedAt:  started.UTC(),
+		StatusCode: attempt.statusCode,
+		DurationMs: time.Since(started).Milliseconds(),
+		Bytes:      len(attempt.body),
+		Outcome:    outcome,
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(s.closed, fetchLogWriteTimeout)
+		defer cancel()
+
+		if err := s.fetchLog.Append(ctx, fetch, feedFetchLogKeep); err != nil {
+			logger.Warn("feed fetch log write failed", "module", "service", "action", "save", "resource", "feed", "result", "failed", "feed_id", feed.ID, "error", err)
+		}
+	}()
+}
+
+func (s *refreshService) GetFetchLog(ctx context.Context, feedID int64) (FeedFetchLog, error) {
+	if _, err := s.feeds.GetByID(ctx, feedID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return FeedFetchLog{}, ErrNotFound
+		}
+		return FeedFetchLog{}, fmt.Errorf("get feed: %w", err)
+	}
+
+	log := FeedFetchLog{Status: FeedFetchHealthUnknown, Fetches: []model.FeedFetch{}}
+	if s.fetchLog == nil {
+		return log, nil
+	}
+	fetches, err := s.fetchLog.List(ctx, feedID, feedFetchLogKeep)
+	if err != nil {
+		return FeedFetchLog{}, fmt.Errorf("list fetch log: %w", err)
+	}
+	if len(fetches) == 0 {
+		return log, nil
+	}
+
+	stats := fetchStats(feedID, fetches)
+	log.Status = feedFetchHealth(stats)
+	log.SuccessRatio = float64(stats.Successes) / float64(stats.Attempts)
+	log.Fetches = fetches
+	return log, nil
+}
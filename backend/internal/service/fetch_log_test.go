@@ -0,0 +1,92 @@
+package service_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"gist/backend/internal/model"
+	"gist/backend/internal/repository/mock"
+	"gist/backend/internal/service"
+	"gist/backend/pkg/network"
+)
+
+func TestRefreshService_RefreshFeed_AppendsFetchLog(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFeeds := mock.NewMockFeedRepository(ctrl)
+	mockFeeds.EXPECT().GetByID(gomock.Any(), int64(1)).Return(model.Feed{ID: 1, URL: "https://example.com/rss", Title: "Feed"}, nil)
+	mockFeeds.EXPECT().UpdateErrorMessage(gomock.Any(), int64(1), gomock.Any()).Return(nil)
+	mockFeeds.EXPECT().UpdateLastFetchedAt(gomock.Any(), int64(1), gomock.Any()).Return(nil)
+
+	appended := make(chan model.FeedFetch, 1)
+	mockFetchLog := mock.NewMockFeedFetchLogRepository(ctrl)
+	mockFetchLog.EXPECT().Append(gomock.Any(), gomock.Any(), 50).DoAndReturn(func(_ context.Context, fetch model.FeedFetch, _ int) error {
+		appended <- fetch
+		return nil
+	})
+
+	client := &http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			return &http.Response{StatusCode: http.StatusServiceUnavailable, Body: http.NoBody, Header: make(http.Header), Request: req}, nil
+		}),
+	}
+	svc := service.NewRefreshService(mockFeeds, mock.NewMockEntryRepository(ctrl), nil, nil, nil, nil, network.NewClientFactoryForTest(client), nil, nil, mockFetchLog, nil, nil)
+	require.NoError(t, svc.RefreshFeed(context.Background(), 1))
+
+	select {
+	case fetch := <-appended:
+		require.Equal(t, int64(1), fetch.FeedID)
+		require.Equal(t, http.StatusServiceUnavailable, fetch.StatusCode)
+		require.Equal(t, model.FeedFetchHTTPError, fetch.Outcome)
+		require.WithinDuration(t, time.Now(), fetch.FetchedAt, time.Minute)
+	case <-time.After(5 * time.Second):
+		t.Fatal("fetch log not written")
+	}
+}
+
+func TestRefreshService_GetFetchLog_Status(t *testing.T) {
+	fetches := func(outcomes ...string) []model.FeedFetch {
+		out := make([]model.FeedFetch, len(outcomes))
+		for i, outcome := range outcomes {
+			out[i] = model.FeedFetch{FeedID: 1, Outcome: outcome}
+		}
+		return out
+	}
+	const ok, fail = model.FeedFetchOK, model.FeedFetchHTTPError
+
+	tests := []struct {
+		name    string
+		fetches []model.FeedFetch
+		status  string
+		ratio   float64
+	}{
+		{"no history", nil, service.FeedFetchHealthUnknown, 0},
+		{"all fine", fetches(ok, model.FeedFetchNotModified, ok), service.FeedFetchHealthOK, 1},
+		{"odd failure", fetches(fail, ok, ok, ok, ok, ok, ok, ok, ok, ok), service.FeedFetchHealthOK, 0.9},
+		{"four out of five", fetches(ok, fail, ok, ok, ok, ok, fail, ok, ok, ok), service.FeedFetchHealthUnstable, 0.8},
+		{"failing lately", fetches(fail, fail, fail, ok, ok, ok, ok, ok, ok, ok), service.FeedFetchHealthErroring, 0.7},
+		{"never worked", fetches(model.FeedFetchNetworkError, model.FeedFetchParseError), service.FeedFetchHealthErroring, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockFeeds := mock.NewMockFeedRepository(ctrl)
+			mockFeeds.EXPECT().GetByID(gomock.Any(), int64(1)).Return(model.Feed{ID: 1}, nil)
+			mockFetchLog := mock.NewMockFeedFetchLogRepository(ctrl)
+			mockFetchLog.EXPECT().List(gomock.Any(), int64(1), 50).Return(tt.fetches, nil)
+
+			svc := service.NewRefreshService(mockFeeds, nil, nil, nil, nil, nil, nil, nil, nil, mockFetchLog, nil, nil)
+			log, err := svc.GetFetchLog(context.Background(), 1)
+			require.NoError(t, err)
+			require.Equal(t, tt.status, log.Status)
+			require.InDelta(t, tt.ratio, log.SuccessRatio, 0.001)
+			require.Len(t, log.Fetches, len(tt.fetches))
+		})
+	}
+}
@@ -0,0 +1,289 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"gist/backend/internal/events"
+	"gist/backend/internal/model"
+	"gist/backend/internal/repository"
+	"gist/backend/pkg/logger"
+)
+
+// maxFolderRulePatternLength bounds rule patterns, which are compiled on every evaluation.
+const maxFolderRulePatternLength = 500
+
+// folderRuleMatcher is a rule ready to be matched against feeds.
+type folderRuleMatcher struct {
+	rule model.FolderRule
+	// re matches url and title rules; host rules compare suffixes instead.
+	re *regexp.Regexp
+}
+
+// compileFolderRule validates a rule's pattern. Host patterns are domains that
+// also match their subdomains. URL and title patterns are case-insensitive
+// globs where * matches any run of characters and ? a single one, or regular
+// expressions when written as /expr/.
+func compileFolderRule(rule model.FolderRule) (folderRuleMatcher, error) {
+	pattern := rule.Pattern
+	if pattern == "" || len(pattern) > maxFolderRulePatternLength {
+		return folderRuleMatcher{}, fmt.Errorf("folder rule pattern length: %w", ErrInvalid)
+	}
+	switch rule.MatchField {
+	case model.FolderRuleMatchHost:
+		if strings.ContainsAny(pattern, "/*? \t") || strings.Trim(pattern, ".") == "" {
+			return folderRuleMatcher{}, fmt.Errorf("folder rule host %q: %w", pattern, ErrInvalid)
+		}
+		return folderRuleMatcher{rule: rule}, nil
+	case model.FolderRuleMatchURL, model.FolderRuleMatchTitle:
+		expr := globToRegexp(pattern)
+		if len(pattern) > 2 && strings.HasPrefix(pattern, "/") && strings.HasSuffix(pattern, "/") {
+			expr = pattern[1 : len(pattern)-1]
+		}
+		re, err := regexp.Compile("(?i)" + expr)
+		if err != nil {
+			return folderRuleMatcher{}, fmt.Errorf("folder rule pattern %q: %w", pattern, ErrInvalid)
+		}
+		return folderRuleMatcher{rule: rule, re: re}, nil
+	default:
+		return folderRuleMatcher{}, fmt.Errorf("folder rule match field %q: %w", rule.MatchField, ErrInvalid)
+	}
+}
+
+// normalizeFolderRule trims the pattern and lowercases hosts, dropping a
+// leading "*." so "*.reddit.com" reads as "reddit.com".
+func normalizeFolderRule(rule model.FolderRule) model.FolderRule {
+	rule.MatchField = strings.ToLower(strings.TrimSpace(rule.MatchField))
+	rule.Pattern = strings.TrimSpace(rule.Pattern)
+	if rule.MatchField == model.FolderRuleMatchHost {
+		rule.Pattern = strings.TrimPrefix(strings.ToLower(rule.Pattern), "*.")
+		rule.Pattern = strings.Trim(rule.Pattern, ".")
+	}
+	return rule
+}
+
+// globToRegexp anchors a glob pattern and translates its wildcards.
+func globToRegexp(glob string) string {
+	var b strings.Builder
+	b.WriteString("^")
+	for _, r := range glob {
+		switch r {
+		case '*':
+			b.WriteString(".*")
+		case '?':
+			b.WriteString(".")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	b.WriteString("$")
+	return b.String()
+}
+
+func (m folderRuleMatcher) matches(feedURL, title string) bool {
+	switch m.rule.MatchField {
+	case model.FolderRuleMatchHost:
+		parsed, err := url.Parse(feedURL)
+		if err != nil {
+			return false
+		}
+		host := strings.ToLower(parsed.Hostname())
+		return host == m.rule.Pattern || strings.HasSuffix(host, "."+m.rule.Pattern)
+	case model.FolderRuleMatchURL:
+		return m.re.MatchString(feedURL)
+	case model.FolderRuleMatchTitle:
+		return m.re.MatchString(title)
+	}
+	return false
+}
+
+// folderRuleSet holds the rules to evaluate and the live folders they point at.
+type folderRuleSet struct {
+	matchers []folderRuleMatcher
+	folders  map[int64]model.Folder
+}
+
+func loadFolderRules(ctx context.Context, rules repository.FolderRuleRepository, folders repository.FolderRepository) (*folderRuleSet, error) {
+	stored, err := rules.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list folder rules: %w", err)
+	}
+	set := &folderRuleSet{folders: make(map[int64]model.Folder)}
+	if len(stored) == 0 {
+		return set, nil
+	}
+	for _, rule := range stored {
+		matcher, err := compileFolderRule(rule)
+		if err != nil {
+			// Validated on save; only a hand-edited database gets here
+			continue
+		}
+		set.matchers = append(set.matchers, matcher)
+	}
+	list, err := folders.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list folders: %w", err)
+	}
+	for _, folder := range list {
+		set.folders[folder.ID] = folder
+	}
+	return set, nil
+}
+
+// match returns the folder of the first rule that matches the feed and whose
+// folder can hold it: feeds must share their folder's type, unless feedType is
+// FeedTypeAuto, in which case the folder decides.
+func (set *folderRuleSet) match(feedURL, title, feedType string) (model.Folder, bool) {
+	for _, matcher := range set.matchers {
+		folder, ok := set.folders[matcher.rule.FolderID]
+		if !ok || (feedType != FeedTypeAuto && folder.Type != feedType) {
+			continue
+		}
+		if matcher.matches(feedURL, title) {
+			return folder, true
+		}
+	}
+	return model.Folder{}, false
+}
+
+// ruleFolder picks the folder rules assign a new feed to. Failing to load the
+// rules only costs the assignment, so it is logged and the feed stays uncategorized.
+func (s *feedService) ruleFolder(ctx context.Context, feedURL, title, feedType string) (*int64, string) {
+	if s.rules == nil {
+		return nil, feedType
+	}
+	set, err := loadFolderRules(ctx, s.rules, s.folders)
+	if err != nil {
+		logger.Warn("folder rules load failed", "module", "service", "action", "list", "resource", "folder", "result", "failed", "error", err)
+		return nil, feedType
+	}
+	folder, ok := set.match(feedURL, title, feedType)
+	if !ok {
+		return nil, feedType
+	}
+	logger.Debug("folder rule matched", "module", "service", "action", "create", "resource", "feed", "result", "ok", "folder_id", folder.ID)
+	return &folder.ID, folder.Type
+}
+
+func (s *folderService) ListRules(ctx context.Context, folderID int64) ([]model.FolderRule, error) {
+	if err := s.checkFolder(ctx, folderID); err != nil {
+		return nil, err
+	}
+	return s.rules.ListByFolder(ctx, folderID)
+}
+
+func (s *folderService) CreateRule(ctx context.Context, rule model.FolderRule) (model.FolderRule, error) {
+	if err := s.checkFolder(ctx, rule.FolderID); err != nil {
+		return model.FolderRule{}, err
+	}
+	rule = normalizeFolderRule(rule)
+	if _, err := compileFolderRule(rule); err != nil {
+		return model.FolderRule{}, err
+	}
+	created, err := s.rules.Create(ctx, rule)
+	if err != nil {
+		logger.Error("folder rule create failed", "module", "service", "action", "create", "resource", "folder", "result", "failed", "folder_id", rule.FolderID, "error", err)
+		return model.FolderRule{}, err
+	}
+	logger.Info("folder rule created", "module", "service", "action", "create", "resource", "folder", "result", "ok", "folder_id", created.FolderID, "rule_id", created.ID)
+	return created, nil
+}
+
+func (s *folderService) UpdateRule(ctx context.Context, rule model.FolderRule) (model.FolderRule, error) {
+	if err := s.checkRule(ctx, rule.FolderID, rule.ID); err != nil {
+		return model.FolderRule{}, err
+	}
+	rule = normalizeFolderRule(rule)
+	if _, err := compileFolderRule(rule); err != nil {
+		return model.FolderRule{}, err
+	}
+	updated, err := s.rules.Update(ctx, rule)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.FolderRule{}, ErrNotFound
+		}
+		logger.Error("folder rule update failed", "module", "service", "action", "update", "resource", "folder", "result", "failed", "rule_id", rule.ID, "error", err)
+		return model.FolderRule{}, err
+	}
+	logger.Info("folder rule updated", "module", "service", "action", "update", "resource", "folder", "result", "ok", "folder_id", updated.FolderID, "rule_id", updated.ID)
+	return updated, nil
+}
+
+func (s *folderService) DeleteRule(ctx context.Context, folderID, ruleID int64) error {
+	if err := s.checkRule(ctx, folderID, ruleID); err != nil {
+		return err
+	}
+	if err := s.rules.Delete(ctx, ruleID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrNotFound
+		}
+		logger.Error("folder rule delete failed", "module", "service", "action", "delete", "resource", "folder", "result", "failed", "rule_id", ruleID, "error", err)
+		return err
+	}
+	logger.Info("folder rule deleted", "module", "service", "action", "delete", "resource", "folder", "result", "ok", "folder_id", folderID, "rule_id", ruleID)
+	return nil
+}
+
+func (s *folderService) ApplyRules(ctx context.Context) (int, error) {
+	set, err := loadFolderRules(ctx, s.rules, s.folders)
+	if err != nil {
+		return 0, err
+	}
+	if len(set.matchers) == 0 {
+		return 0, nil
+	}
+	feeds, err := s.feeds.List(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("list feeds: %w", err)
+	}
+
+	moved := 0
+	for _, feed := range feeds {
+		if feed.FolderID != nil || IsStaticFeed(feed) {
+			continue
+		}
+		folder, ok := set.match(feed.URL, feed.DisplayTitle(), feed.Type)
+		if !ok {
+			continue
+		}
+		feed.FolderID = &folder.ID
+		if _, err := s.feeds.Update(ctx, feed); err != nil {
+			logger.Error("folder rules apply failed", "module", "service", "action", "update", "resource", "feed", "result", "failed", "feed_id", feed.ID, "folder_id", folder.ID, "error", err)
+			return moved, err
+		}
+		s.bus.Publish(events.Event{Type: events.FeedUpdated, Data: events.FeedData{FeedID: feed.ID}})
+		moved++
+	}
+	logger.Info("folder rules applied", "module", "service", "action", "update", "resource", "feed", "result", "ok", "count", moved)
+	return moved, nil
+}
+
+func (s *folderService) checkFolder(ctx context.Context, folderID int64) error {
+	if _, err := s.folders.GetByID(ctx, folderID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("get folder: %w", err)
+	}
+	return nil
+}
+
+// checkRule reports ErrNotFound unless ruleID exists and belongs to folderID.
+func (s *folderService) checkRule(ctx context.Context, folderID, ruleID int64) error {
+	rule, err := s.rules.GetByID(ctx, ruleID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("get folder rule: %w", err)
+	}
+	if rule.FolderID != folderID {
+		return ErrNotFound
+	}
+	return nil
+}
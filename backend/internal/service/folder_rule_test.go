@@ -0,0 +1,214 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"gist/backend/internal/model"
+	"gist/backend/internal/repository/mock"
+	"gist/backend/internal/service"
+	"gist/backend/pkg/network"
+)
+
+func TestFolderService_CreateRule_Validation(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    model.FolderRule
+		want    string
+		wantErr error
+	}{
+		{name: "host is normalized", rule: model.FolderRule{Pattern: " *.Reddit.COM ", MatchField: "host"}, want: "reddit.com"},
+		{name: "url glob", rule: model.FolderRule{Pattern: "*/r/golang*", MatchField: "url"}, want: "*/r/golang*"},
+		{name: "title regex", rule: model.FolderRule{Pattern: "/^(podcast|radio)/", MatchField: "TITLE"}, want: "/^(podcast|radio)/"},
+		{name: "empty pattern", rule: model.FolderRule{Pattern: "  ", MatchField: "host"}, wantErr: service.ErrInvalid},
+		{name: "host with path", rule: model.FolderRule{Pattern: "reddit.com/r/go", MatchField: "host"}, wantErr: service.ErrInvalid},
+		{name: "host wildcard in the middle", rule: model.FolderRule{Pattern: "news.*.com", MatchField: "host"}, wantErr: service.ErrInvalid},
+		{name: "bad regex", rule: model.FolderRule{Pattern: "/(unclosed/", MatchField: "title"}, wantErr: service.ErrInvalid},
+		{name: "unknown field", rule: model.FolderRule{Pattern: "go", MatchField: "author"}, wantErr: service.ErrInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockFolders := mock.NewMockFolderRepository(ctrl)
+			mockRules := mock.NewMockFolderRuleRepository(ctrl)
+			svc := service.NewFolderService(mockFolders, mock.NewMockFeedRepository(ctrl), mockRules, nil)
+
+			mockFolders.EXPECT().GetByID(gomock.Any(), int64(1)).Return(model.Folder{ID: 1, Type: "article"}, nil)
+			if tt.wantErr == nil {
+				mockRules.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(_ context.Context, rule model.FolderRule) (model.FolderRule, error) {
+						rule.ID = 10
+						return rule, nil
+					},
+				)
+			}
+
+			tt.rule.FolderID = 1
+			created, err := svc.CreateRule(context.Background(), tt.rule)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, created.Pattern)
+		})
+	}
+}
+
+func TestFolderService_Rules_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockFolders := mock.NewMockFolderRepository(ctrl)
+	mockRules := mock.NewMockFolderRuleRepository(ctrl)
+	svc := service.NewFolderService(mockFolders, mock.NewMockFeedRepository(ctrl), mockRules, nil)
+	ctx := context.Background()
+
+	mockFolders.EXPECT().GetByID(gomock.Any(), int64(1)).Return(model.Folder{}, sql.ErrNoRows)
+	_, err := svc.ListRules(ctx, 1)
+	require.ErrorIs(t, err, service.ErrNotFound)
+
+	// Rules are addressed through their folder
+	mockRules.EXPECT().GetByID(gomock.Any(), int64(10)).Return(model.FolderRule{ID: 10, FolderID: 2}, nil).Times(2)
+	_, err = svc.UpdateRule(ctx, model.FolderRule{ID: 10, FolderID: 1, Pattern: "a.com", MatchField: "host"})
+	require.ErrorIs(t, err, service.ErrNotFound)
+	require.ErrorIs(t, svc.DeleteRule(ctx, 1, 10), service.ErrNotFound)
+
+	mockRules.EXPECT().GetByID(gomock.Any(), int64(11)).Return(model.FolderRule{}, sql.ErrNoRows)
+	require.ErrorIs(t, svc.DeleteRule(ctx, 1, 11), service.ErrNotFound)
+}
+
+func TestFolderService_ApplyRules(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockFolders := mock.NewMockFolderRepository(ctrl)
+	mockFeeds := mock.NewMockFeedRepository(ctrl)
+	mockRules := mock.NewMockFolderRuleRepository(ctrl)
+	svc := service.NewFolderService(mockFolders, mockFeeds, mockRules, nil)
+
+	filed := int64(9)
+	mockRules.EXPECT().List(gomock.Any()).Return([]model.FolderRule{
+		{ID: 1, FolderID: 1, Pattern: "reddit.com", MatchField: "host"},
+		{ID: 2, FolderID: 2, Pattern: "*podcast*", MatchField: "title"},
+		{ID: 3, FolderID: 3, Pattern: "example.com", MatchField: "host"},
+	}, nil)
+	mockFolders.EXPECT().List(gomock.Any()).Return([]model.Folder{
+		{ID: 1, Type: "article"},
+		{ID: 2, Type: "article"},
+		{ID: 3, Type: "picture"},
+	}, nil)
+	mockFeeds.EXPECT().List(gomock.Any(), nil).Return([]model.Feed{
+		{ID: 100, URL: "https://old.reddit.com/r/golang/.rss", Type: "article"},
+		{ID: 101, URL: "https://example.com/feed", Title: "Weekly Podcast", Type: "article"},
+		// Already filed
+		{ID: 102, URL: "https://www.reddit.com/.rss", Type: "article", FolderID: &filed},
+		// The only matching rule points at a folder of another type
+		{ID: 103, URL: "https://example.com/rss", Type: "article"},
+	}, nil)
+	var moved []int64
+	mockFeeds.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, feed model.Feed) (model.Feed, error) {
+			moved = append(moved, feed.ID, *feed.FolderID)
+			return feed, nil
+		},
+	).Times(2)
+
+	count, err := svc.ApplyRules(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 2, count)
+	require.Equal(t, []int64{100, 1, 101, 2}, moved)
+}
+
+func TestFeedService_Add_FolderRules(t *testing.T) {
+	feedURL := "https://blog.example.com/rss"
+	client := &http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			return nil, errors.New("network down")
+		}),
+	}
+	rules := []model.FolderRule{
+		{ID: 1, FolderID: 1, Pattern: "example.com", MatchField: "host"},
+		{ID: 2, FolderID: 2, Pattern: "/^daily/", MatchField: "title"},
+	}
+	folders := []model.Folder{{ID: 1, Type: "picture"}, {ID: 2, Type: "article"}}
+
+	tests := []struct {
+		name       string
+		title      string
+		feedType   string
+		wantFolder int64
+		wantType   string
+	}{
+		{name: "auto adopts the folder type", feedType: service.FeedTypeAuto, wantFolder: 1, wantType: "picture"},
+		{name: "type mismatch falls through", title: "Daily Links", feedType: "article", wantFolder: 2, wantType: "article"},
+		{name: "no match", feedType: "notification", wantType: "notification"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockFeeds := mock.NewMockFeedRepository(ctrl)
+			mockFolders := mock.NewMockFolderRepository(ctrl)
+			mockRules := mock.NewMockFolderRuleRepository(ctrl)
+
+			mockFeeds.EXPECT().FindByURL(gomock.Any(), feedURL).Return(nil, nil)
+			mockRules.EXPECT().List(gomock.Any()).Return(rules, nil)
+			mockFolders.EXPECT().List(gomock.Any()).Return(folders, nil)
+			mockFeeds.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ context.Context, feed model.Feed) (model.Feed, error) {
+					return feed, nil
+				},
+			)
+
+			svc := service.NewFeedService(mockFeeds, mockFolders, mock.NewMockEntryRepository(ctrl), nil, nil, network.NewClientFactoryForTest(client), nil, mockRules, nil, nil)
+			feed, err := svc.Add(context.Background(), feedURL, nil, tt.title, tt.feedType, service.InitialBackfill{})
+			require.NoError(t, err)
+			require.Equal(t, tt.wantType, feed.Type)
+			if tt.wantFolder == 0 {
+				require.Nil(t, feed.FolderID)
+			} else {
+				require.NotNil(t, feed.FolderID)
+				require.Equal(t, tt.wantFolder, *feed.FolderID)
+			}
+		})
+	}
+}
+
+func TestFeedService_AddWithoutFetch_FolderRules(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockFeeds := mock.NewMockFeedRepository(ctrl)
+	mockFolders := mock.NewMockFolderRepository(ctrl)
+	mockRules := mock.NewMockFolderRuleRepository(ctrl)
+	svc := service.NewFeedService(mockFeeds, mockFolders, mock.NewMockEntryRepository(ctrl), nil, nil, nil, nil, mockRules, nil, nil)
+	ctx := context.Background()
+
+	feedURL := "https://github.com/golang/go/releases.atom"
+	mockFeeds.EXPECT().FindByURL(gomock.Any(), feedURL).Return(nil, nil)
+	mockRules.EXPECT().List(gomock.Any()).Return([]model.FolderRule{{ID: 1, FolderID: 4, Pattern: "https://github.com/*/releases.atom", MatchField: "url"}}, nil)
+	mockFolders.EXPECT().List(gomock.Any()).Return([]model.Folder{{ID: 4, Type: "notification"}}, nil)
+	mockFeeds.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, feed model.Feed) (model.Feed, error) {
+			return feed, nil
+		},
+	)
+	feed, _, err := svc.AddWithoutFetch(ctx, feedURL, nil, "", "notification")
+	require.NoError(t, err)
+	require.Equal(t, int64(4), *feed.FolderID)
+
+	// A broken rule table does not block subscribing
+	otherURL := "https://example.com/rss"
+	mockFeeds.EXPECT().FindByURL(gomock.Any(), otherURL).Return(nil, nil)
+	mockRules.EXPECT().List(gomock.Any()).Return(nil, errors.New("db down"))
+	mockFeeds.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, feed model.Feed) (model.Feed, error) {
+			return feed, nil
+		},
+	)
+	feed, _, err = svc.AddWithoutFetch(ctx, otherURL, nil, "", "article")
+	require.NoError(t, err)
+	require.Nil(t, feed.FolderID)
+}
@@ -7,8 +7,10 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"gist/backend/pkg/logger"
+	"gist/backend/internal/events"
 	"gist/backend/internal/model"
 	"gist/backend/internal/repository"
 )
@@ -17,17 +19,37 @@ type FolderService interface {
 	Create(ctx context.Context, name string, parentID *int64, folderType string) (model.Folder, error)
 	List(ctx context.Context) ([]model.Folder, error)
 	Update(ctx context.Context, id int64, name string, parentID *int64) (model.Folder, error)
+	// UpdateType changes the type of the folder, its subfolders and all their feeds.
 	UpdateType(ctx context.Context, id int64, folderType string) error
+	// UpdateFeedDefaults replaces the options the folder's feeds and subfolders
+	// inherit. Feeds keep their own values; only what they inherit changes.
+	UpdateFeedDefaults(ctx context.Context, id int64, defaults model.FeedDefaults) (model.Folder, error)
+	// Reorder places sibling folders in the given order; folders under different
+	// parents return ErrInvalid.
+	Reorder(ctx context.Context, ids []int64) error
+	// Delete moves the folder and its feeds to the trash; Restore brings them back within DeleteRetention.
 	Delete(ctx context.Context, id int64) error
+	Restore(ctx context.Context, id int64) (model.Folder, error)
+
+	// Folder rules file feeds added without a folder by host, URL or title.
+	ListRules(ctx context.Context, folderID int64) ([]model.FolderRule, error)
+	CreateRule(ctx context.Context, rule model.FolderRule) (model.FolderRule, error)
+	// UpdateRule and DeleteRule return ErrNotFound unless the rule belongs to the given folder.
+	UpdateRule(ctx context.Context, rule model.FolderRule) (model.FolderRule, error)
+	DeleteRule(ctx context.Context, folderID, ruleID int64) error
+	// ApplyRules files the existing uncategorized feeds and returns how many moved.
+	ApplyRules(ctx context.Context) (int, error)
 }
 
 type folderService struct {
 	folders repository.FolderRepository
 	feeds   repository.FeedRepository
+	rules   repository.FolderRuleRepository
+	bus     *events.Bus
 }
 
-func NewFolderService(folders repository.FolderRepository, feeds repository.FeedRepository) FolderService {
-	return &folderService{folders: folders, feeds: feeds}
+func NewFolderService(folders repository.FolderRepository, feeds repository.FeedRepository, rules repository.FolderRuleRepository, bus *events.Bus) FolderService {
+	return &folderService{folders: folders, feeds: feeds, rules: rules, bus: bus}
 }
 
 // detectCycle checks if setting newParentID as parent of id would create a cycle.
@@ -82,10 +104,15 @@ func (s *folderService) Create(ctx context.Context, name string, parentID *int64
 
 	folder, err := s.folders.Create(ctx, trimmed, parentID, folderType)
 	if err != nil {
+		// Lost a race with a concurrent create of the same name
+		if errors.Is(err, repository.ErrConflict) {
+			return model.Folder{}, ErrConflict
+		}
 		logger.Error("folder create failed", "module", "service", "action", "create", "resource", "folder", "result", "failed", "error", err)
 		return model.Folder{}, err
 	}
 	logger.Info("folder created", "module", "service", "action", "create", "resource", "folder", "result", "ok", "folder_id", folder.ID)
+	s.bus.Publish(events.Event{Type: events.FolderCreated, Data: events.FolderData{FolderID: folder.ID}})
 	return folder, nil
 }
 
@@ -110,7 +137,7 @@ func (s *folderService) Update(ctx context.Context, id int64, name string, paren
 		}
 		return model.Folder{}, fmt.Errorf("check cycle: %w", err)
 	} else if hasCycle {
-		return model.Folder{}, ErrInvalid
+		return model.Folder{}, ErrFolderCycle
 	}
 	if parentID != nil {
 		if _, err := s.folders.GetByID(ctx, *parentID); err != nil {
@@ -134,10 +161,14 @@ func (s *folderService) Update(ctx context.Context, id int64, name string, paren
 
 	updated, err := s.folders.Update(ctx, id, trimmed, parentID)
 	if err != nil {
+		if errors.Is(err, repository.ErrConflict) {
+			return model.Folder{}, ErrConflict
+		}
 		logger.Error("folder update failed", "module", "service", "action", "update", "resource", "folder", "result", "failed", "folder_id", id, "error", err)
 		return model.Folder{}, err
 	}
 	logger.Info("folder updated", "module", "service", "action", "update", "resource", "folder", "result", "ok", "folder_id", updated.ID)
+	s.bus.Publish(events.Event{Type: events.FolderUpdated, Data: events.FolderData{FolderID: updated.ID}})
 	return updated, nil
 }
 
@@ -149,50 +180,122 @@ func (s *folderService) UpdateType(ctx context.Context, id int64, folderType str
 		return fmt.Errorf("get folder: %w", err)
 	}
 
-	// Update folder type
-	if err := s.folders.UpdateType(ctx, id, folderType); err != nil {
+	// Subfolders and every feed below the folder follow its type
+	if err := s.folders.UpdateTypeCascade(ctx, id, folderType); err != nil {
 		logger.Error("folder update type failed", "module", "service", "action", "update", "resource", "folder", "result", "failed", "folder_id", id, "type", folderType, "error", err)
 		return err
 	}
 
-	// Update all feeds in this folder to the same type using batch operation
-	if err := s.feeds.UpdateTypeByFolderID(ctx, id, folderType); err != nil {
-		logger.Error("folder update feeds type failed", "module", "service", "action", "update", "resource", "feed", "result", "failed", "folder_id", id, "type", folderType, "error", err)
-		return fmt.Errorf("update feeds type in folder: %w", err)
-	}
-
 	logger.Info("folder type updated", "module", "service", "action", "update", "resource", "folder", "result", "ok", "folder_id", id, "type", folderType)
+	s.bus.Publish(events.Event{Type: events.FolderUpdated, Data: events.FolderData{FolderID: id}})
 	return nil
 }
 
-func (s *folderService) Delete(ctx context.Context, id int64) error {
+func (s *folderService) UpdateFeedDefaults(ctx context.Context, id int64, defaults model.FeedDefaults) (model.Folder, error) {
+	userAgent, err := normalizeUserAgent(defaults.UserAgent)
+	if err != nil {
+		return model.Folder{}, err
+	}
+	defaults.UserAgent = userAgent
 	if _, err := s.folders.GetByID(ctx, id); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return ErrNotFound
+			return model.Folder{}, ErrNotFound
 		}
-		return fmt.Errorf("get folder: %w", err)
+		return model.Folder{}, fmt.Errorf("get folder: %w", err)
 	}
 
-	// Delete all feeds in this folder using batch operation (entries will be cascade deleted by DB)
-	feeds, err := s.feeds.List(ctx, &id)
+	if err := s.folders.UpdateFeedDefaults(ctx, id, defaults); err != nil {
+		logger.Error("folder update feed defaults failed", "module", "service", "action", "update", "resource", "folder", "result", "failed", "folder_id", id, "error", err)
+		return model.Folder{}, err
+	}
+	folder, err := s.folders.GetByID(ctx, id)
 	if err != nil {
-		return fmt.Errorf("list feeds in folder: %w", err)
+		return model.Folder{}, fmt.Errorf("get folder: %w", err)
 	}
-	if len(feeds) > 0 {
-		feedIDs := make([]int64, len(feeds))
-		for i, feed := range feeds {
-			feedIDs[i] = feed.ID
+
+	logger.Info("folder feed defaults updated", "module", "service", "action", "update", "resource", "folder", "result", "ok", "folder_id", id)
+	s.bus.Publish(events.Event{Type: events.FolderUpdated, Data: events.FolderData{FolderID: id}})
+	return folder, nil
+}
+
+func (s *folderService) Reorder(ctx context.Context, ids []int64) error {
+	if len(ids) == 0 || hasDuplicateIDs(ids) {
+		return ErrInvalid
+	}
+	var parentID *int64
+	for i, id := range ids {
+		folder, err := s.folders.GetByID(ctx, id)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrNotFound
+			}
+			return fmt.Errorf("get folder: %w", err)
 		}
-		if _, err := s.feeds.DeleteBatch(ctx, feedIDs); err != nil {
-			logger.Error("folder delete feeds failed", "module", "service", "action", "delete", "resource", "feed", "result", "failed", "folder_id", id, "count", len(feedIDs), "error", err)
-			return fmt.Errorf("delete feeds in folder: %w", err)
+		if i == 0 {
+			parentID = folder.ParentID
+		} else if !sameID(parentID, folder.ParentID) {
+			return ErrInvalid
 		}
 	}
 
+	if err := s.folders.Reorder(ctx, ids); err != nil {
+		logger.Error("folder reorder failed", "module", "service", "action", "update", "resource", "folder", "result", "failed", "count", len(ids), "error", err)
+		return err
+	}
+	logger.Info("folders reordered", "module", "service", "action", "update", "resource", "folder", "result", "ok", "count", len(ids))
+	return nil
+}
+
+// hasDuplicateIDs reports whether an ID appears more than once.
+func hasDuplicateIDs(ids []int64) bool {
+	seen := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			return true
+		}
+		seen[id] = true
+	}
+	return false
+}
+
+// sameID reports whether two optional IDs are both nil or equal.
+func sameID(a, b *int64) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+func (s *folderService) Delete(ctx context.Context, id int64) error {
+	if _, err := s.folders.GetByID(ctx, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("get folder: %w", err)
+	}
+
+	// Soft delete: subfolders and feeds go with the folder and are purged after DeleteRetention
 	if err := s.folders.Delete(ctx, id); err != nil {
 		logger.Error("folder delete failed", "module", "service", "action", "delete", "resource", "folder", "result", "failed", "folder_id", id, "error", err)
 		return err
 	}
 	logger.Info("folder deleted", "module", "service", "action", "delete", "resource", "folder", "result", "ok", "folder_id", id)
+	s.bus.Publish(events.Event{Type: events.FolderDeleted, Data: events.FolderData{FolderID: id}})
 	return nil
 }
+
+func (s *folderService) Restore(ctx context.Context, id int64) (model.Folder, error) {
+	if err := s.folders.Restore(ctx, id, time.Now().Add(-DeleteRetention)); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Folder{}, ErrNotFound
+		}
+		if errors.Is(err, repository.ErrConflict) {
+			return model.Folder{}, ErrConflict
+		}
+		logger.Error("folder restore failed", "module", "service", "action", "restore", "resource", "folder", "result", "failed", "folder_id", id, "error", err)
+		return model.Folder{}, err
+	}
+	logger.Info("folder restored", "module", "service", "action", "restore", "resource", "folder", "result", "ok", "folder_id", id)
+	s.bus.Publish(events.Event{Type: events.FolderCreated, Data: events.FolderData{FolderID: id}})
+	return s.folders.GetByID(ctx, id)
+}
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"gist/backend/internal/model"
+	"gist/backend/internal/repository"
 	"gist/backend/internal/service"
 	"gist/backend/internal/repository/mock"
 
@@ -22,7 +23,7 @@ func TestFolderService_Create_Success(t *testing.T) {
 
 	mockFolders := mock.NewMockFolderRepository(ctrl)
 	mockFeeds := mock.NewMockFeedRepository(ctrl)
-	svc := service.NewFolderService(mockFolders, mockFeeds)
+	svc := service.NewFolderService(mockFolders, mockFeeds, nil, nil)
 	ctx := context.Background()
 
 	mockFolders.EXPECT().
@@ -49,7 +50,7 @@ func TestFolderService_Create_EmptyName(t *testing.T) {
 
 	mockFolders := mock.NewMockFolderRepository(ctrl)
 	mockFeeds := mock.NewMockFeedRepository(ctrl)
-	svc := service.NewFolderService(mockFolders, mockFeeds)
+	svc := service.NewFolderService(mockFolders, mockFeeds, nil, nil)
 	ctx := context.Background()
 
 	_, err := svc.Create(ctx, "", nil, "article")
@@ -65,7 +66,7 @@ func TestFolderService_Create_DuplicateName(t *testing.T) {
 
 	mockFolders := mock.NewMockFolderRepository(ctrl)
 	mockFeeds := mock.NewMockFeedRepository(ctrl)
-	svc := service.NewFolderService(mockFolders, mockFeeds)
+	svc := service.NewFolderService(mockFolders, mockFeeds, nil, nil)
 	ctx := context.Background()
 
 	existingFolder := &model.Folder{ID: 1, Name: "Existing"}
@@ -78,13 +79,30 @@ func TestFolderService_Create_DuplicateName(t *testing.T) {
 	require.ErrorIs(t, err, service.ErrConflict)
 }
 
+func TestFolderService_Create_ConcurrentDuplicate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFolders := mock.NewMockFolderRepository(ctrl)
+	mockFeeds := mock.NewMockFeedRepository(ctrl)
+	svc := service.NewFolderService(mockFolders, mockFeeds, nil, nil)
+	ctx := context.Background()
+
+	// Another request created the folder between the lookup and the insert
+	mockFolders.EXPECT().FindByName(ctx, "Tech", (*int64)(nil)).Return(nil, nil)
+	mockFolders.EXPECT().Create(ctx, "Tech", (*int64)(nil), "article").Return(model.Folder{}, repository.ErrConflict)
+
+	_, err := svc.Create(ctx, "Tech", nil, "article")
+	require.ErrorIs(t, err, service.ErrConflict)
+}
+
 func TestFolderService_Create_ParentNotFound(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockFolders := mock.NewMockFolderRepository(ctrl)
 	mockFeeds := mock.NewMockFeedRepository(ctrl)
-	svc := service.NewFolderService(mockFolders, mockFeeds)
+	svc := service.NewFolderService(mockFolders, mockFeeds, nil, nil)
 	ctx := context.Background()
 
 	parentID := int64(999)
@@ -103,7 +121,7 @@ func TestFolderService_Create_WithParent(t *testing.T) {
 
 	mockFolders := mock.NewMockFolderRepository(ctrl)
 	mockFeeds := mock.NewMockFeedRepository(ctrl)
-	svc := service.NewFolderService(mockFolders, mockFeeds)
+	svc := service.NewFolderService(mockFolders, mockFeeds, nil, nil)
 	ctx := context.Background()
 
 	parentID := int64(100)
@@ -132,7 +150,7 @@ func TestFolderService_Update_Success(t *testing.T) {
 
 	mockFolders := mock.NewMockFolderRepository(ctrl)
 	mockFeeds := mock.NewMockFeedRepository(ctrl)
-	svc := service.NewFolderService(mockFolders, mockFeeds)
+	svc := service.NewFolderService(mockFolders, mockFeeds, nil, nil)
 	ctx := context.Background()
 
 	folderID := int64(123)
@@ -160,13 +178,14 @@ func TestFolderService_Update_DirectCycle(t *testing.T) {
 
 	mockFolders := mock.NewMockFolderRepository(ctrl)
 	mockFeeds := mock.NewMockFeedRepository(ctrl)
-	svc := service.NewFolderService(mockFolders, mockFeeds)
+	svc := service.NewFolderService(mockFolders, mockFeeds, nil, nil)
 	ctx := context.Background()
 
 	folderID := int64(123)
 
 	// Attempt to set parent to self
 	_, err := svc.Update(ctx, folderID, "Test", &folderID)
+	require.ErrorIs(t, err, service.ErrFolderCycle)
 	require.ErrorIs(t, err, service.ErrInvalid)
 }
 
@@ -176,7 +195,7 @@ func TestFolderService_Update_IndirectCycle(t *testing.T) {
 
 	mockFolders := mock.NewMockFolderRepository(ctrl)
 	mockFeeds := mock.NewMockFeedRepository(ctrl)
-	svc := service.NewFolderService(mockFolders, mockFeeds)
+	svc := service.NewFolderService(mockFolders, mockFeeds, nil, nil)
 	ctx := context.Background()
 
 	// Create hierarchy: A -> B -> C
@@ -206,7 +225,7 @@ func TestFolderService_UpdateType_CascadeToFeeds(t *testing.T) {
 
 	mockFolders := mock.NewMockFolderRepository(ctrl)
 	mockFeeds := mock.NewMockFeedRepository(ctrl)
-	svc := service.NewFolderService(mockFolders, mockFeeds)
+	svc := service.NewFolderService(mockFolders, mockFeeds, nil, nil)
 	ctx := context.Background()
 
 	folderID := int64(123)
@@ -216,11 +235,7 @@ func TestFolderService_UpdateType_CascadeToFeeds(t *testing.T) {
 		Return(model.Folder{ID: folderID, Name: "Test", Type: "article"}, nil)
 
 	mockFolders.EXPECT().
-		UpdateType(ctx, folderID, "picture").
-		Return(nil)
-
-	mockFeeds.EXPECT().
-		UpdateTypeByFolderID(ctx, folderID, "picture").
+		UpdateTypeCascade(ctx, folderID, "picture").
 		Return(nil)
 
 	err := svc.UpdateType(ctx, folderID, "picture")
@@ -233,7 +248,7 @@ func TestFolderService_Delete_Success(t *testing.T) {
 
 	mockFolders := mock.NewMockFolderRepository(ctrl)
 	mockFeeds := mock.NewMockFeedRepository(ctrl)
-	svc := service.NewFolderService(mockFolders, mockFeeds)
+	svc := service.NewFolderService(mockFolders, mockFeeds, nil, nil)
 	ctx := context.Background()
 
 	folderID := int64(123)
@@ -242,10 +257,6 @@ func TestFolderService_Delete_Success(t *testing.T) {
 		GetByID(ctx, folderID).
 		Return(model.Folder{ID: folderID, Name: "Test"}, nil)
 
-	mockFeeds.EXPECT().
-		List(ctx, &folderID).
-		Return([]model.Feed{}, nil)
-
 	mockFolders.EXPECT().
 		Delete(ctx, folderID).
 		Return(nil)
@@ -254,40 +265,45 @@ func TestFolderService_Delete_Success(t *testing.T) {
 	require.NoError(t, err)
 }
 
-func TestFolderService_Delete_WithFeeds(t *testing.T) {
+func TestFolderService_Restore_Success(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockFolders := mock.NewMockFolderRepository(ctrl)
-	mockFeeds := mock.NewMockFeedRepository(ctrl)
-	svc := service.NewFolderService(mockFolders, mockFeeds)
+	svc := service.NewFolderService(mockFolders, mock.NewMockFeedRepository(ctrl), nil, nil)
 	ctx := context.Background()
 
 	folderID := int64(123)
-
+	before := time.Now().Add(-service.DeleteRetention)
+	mockFolders.EXPECT().
+		Restore(ctx, folderID, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ int64, deletedSince time.Time) error {
+			require.WithinDuration(t, before, deletedSince, time.Minute)
+			return nil
+		})
 	mockFolders.EXPECT().
 		GetByID(ctx, folderID).
 		Return(model.Folder{ID: folderID, Name: "Test"}, nil)
 
-	feeds := []model.Feed{
-		{ID: 1, FolderID: &folderID, Title: "Feed 1"},
-		{ID: 2, FolderID: &folderID, Title: "Feed 2"},
-	}
+	folder, err := svc.Restore(ctx, folderID)
+	require.NoError(t, err)
+	require.Equal(t, folderID, folder.ID)
+}
 
-	mockFeeds.EXPECT().
-		List(ctx, &folderID).
-		Return(feeds, nil)
+func TestFolderService_Restore_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
 
-	mockFeeds.EXPECT().
-		DeleteBatch(ctx, []int64{1, 2}).
-		Return(int64(2), nil)
+	mockFolders := mock.NewMockFolderRepository(ctrl)
+	svc := service.NewFolderService(mockFolders, mock.NewMockFeedRepository(ctrl), nil, nil)
+	ctx := context.Background()
 
 	mockFolders.EXPECT().
-		Delete(ctx, folderID).
-		Return(nil)
+		Restore(ctx, int64(123), gomock.Any()).
+		Return(sql.ErrNoRows)
 
-	err := svc.Delete(ctx, folderID)
-	require.NoError(t, err)
+	_, err := svc.Restore(ctx, 123)
+	require.ErrorIs(t, err, service.ErrNotFound)
 }
 
 func TestFolderService_Delete_NotFound(t *testing.T) {
@@ -296,7 +312,7 @@ func TestFolderService_Delete_NotFound(t *testing.T) {
 
 	mockFolders := mock.NewMockFolderRepository(ctrl)
 	mockFeeds := mock.NewMockFeedRepository(ctrl)
-	svc := service.NewFolderService(mockFolders, mockFeeds)
+	svc := service.NewFolderService(mockFolders, mockFeeds, nil, nil)
 	ctx := context.Background()
 
 	mockFolders.EXPECT().
@@ -313,7 +329,7 @@ func TestFolderService_List_Success(t *testing.T) {
 
 	mockFolders := mock.NewMockFolderRepository(ctrl)
 	mockFeeds := mock.NewMockFeedRepository(ctrl)
-	svc := service.NewFolderService(mockFolders, mockFeeds)
+	svc := service.NewFolderService(mockFolders, mockFeeds, nil, nil)
 	ctx := context.Background()
 
 	expectedFolders := []model.Folder{
@@ -337,7 +353,7 @@ func TestFolderService_Update_NameConflict(t *testing.T) {
 
 	mockFolders := mock.NewMockFolderRepository(ctrl)
 	mockFeeds := mock.NewMockFeedRepository(ctrl)
-	svc := service.NewFolderService(mockFolders, mockFeeds)
+	svc := service.NewFolderService(mockFolders, mockFeeds, nil, nil)
 	ctx := context.Background()
 
 	folderID := int64(123)
@@ -367,7 +383,7 @@ func TestFolderService_Update_SameNameOK(t *testing.T) {
 
 	mockFolders := mock.NewMockFolderRepository(ctrl)
 	mockFeeds := mock.NewMockFeedRepository(ctrl)
-	svc := service.NewFolderService(mockFolders, mockFeeds)
+	svc := service.NewFolderService(mockFolders, mockFeeds, nil, nil)
 	ctx := context.Background()
 
 	folderID := int64(123)
@@ -398,7 +414,7 @@ func TestFolderService_Create_RepositoryError(t *testing.T) {
 
 	mockFolders := mock.NewMockFolderRepository(ctrl)
 	mockFeeds := mock.NewMockFeedRepository(ctrl)
-	svc := service.NewFolderService(mockFolders, mockFeeds)
+	svc := service.NewFolderService(mockFolders, mockFeeds, nil, nil)
 	ctx := context.Background()
 
 	dbError := errors.New("database connection lost")
@@ -423,7 +439,7 @@ func TestFolderService_Create_ParentCheckError(t *testing.T) {
 
 	mockFolders := mock.NewMockFolderRepository(ctrl)
 	mockFeeds := mock.NewMockFeedRepository(ctrl)
-	svc := service.NewFolderService(mockFolders, mockFeeds)
+	svc := service.NewFolderService(mockFolders, mockFeeds, nil, nil)
 	ctx := context.Background()
 
 	parentID := int64(100)
@@ -449,7 +465,7 @@ func TestFolderService_Update_CycleDetectionError(t *testing.T) {
 
 	mockFolders := mock.NewMockFolderRepository(ctrl)
 	mockFeeds := mock.NewMockFeedRepository(ctrl)
-	svc := service.NewFolderService(mockFolders, mockFeeds)
+	svc := service.NewFolderService(mockFolders, mockFeeds, nil, nil)
 	ctx := context.Background()
 
 	folderID := int64(1)
@@ -477,7 +493,7 @@ func TestFolderService_List_RepositoryError(t *testing.T) {
 
 	mockFolders := mock.NewMockFolderRepository(ctrl)
 	mockFeeds := mock.NewMockFeedRepository(ctrl)
-	svc := service.NewFolderService(mockFolders, mockFeeds)
+	svc := service.NewFolderService(mockFolders, mockFeeds, nil, nil)
 	ctx := context.Background()
 
 	dbError := errors.New("database unavailable")
@@ -504,7 +520,7 @@ func TestFolderService_UpdateType_FolderUpdateFails(t *testing.T) {
 
 	mockFolders := mock.NewMockFolderRepository(ctrl)
 	mockFeeds := mock.NewMockFeedRepository(ctrl)
-	svc := service.NewFolderService(mockFolders, mockFeeds)
+	svc := service.NewFolderService(mockFolders, mockFeeds, nil, nil)
 	ctx := context.Background()
 
 	folderID := int64(123)
@@ -515,7 +531,7 @@ func TestFolderService_UpdateType_FolderUpdateFails(t *testing.T) {
 		Return(model.Folder{ID: folderID, Name: "Test"}, nil)
 
 	mockFolders.EXPECT().
-		UpdateType(ctx, folderID, "picture").
+		UpdateTypeCascade(ctx, folderID, "picture").
 		Return(dbError)
 
 	err := svc.UpdateType(ctx, folderID, "picture")
@@ -534,7 +550,7 @@ func TestFolderService_UpdateType_BatchUpdateFails(t *testing.T) {
 
 	mockFolders := mock.NewMockFolderRepository(ctrl)
 	mockFeeds := mock.NewMockFeedRepository(ctrl)
-	svc := service.NewFolderService(mockFolders, mockFeeds)
+	svc := service.NewFolderService(mockFolders, mockFeeds, nil, nil)
 	ctx := context.Background()
 
 	folderID := int64(123)
@@ -545,11 +561,7 @@ func TestFolderService_UpdateType_BatchUpdateFails(t *testing.T) {
 		Return(model.Folder{ID: folderID, Name: "Test"}, nil)
 
 	mockFolders.EXPECT().
-		UpdateType(ctx, folderID, "picture").
-		Return(nil)
-
-	mockFeeds.EXPECT().
-		UpdateTypeByFolderID(ctx, folderID, "picture").
+		UpdateTypeCascade(ctx, folderID, "picture").
 		Return(dbError)
 
 	err := svc.UpdateType(ctx, folderID, "picture")
@@ -557,111 +569,62 @@ func TestFolderService_UpdateType_BatchUpdateFails(t *testing.T) {
 		t.Fatal("expected error, got nil")
 	}
 
-	if !strings.Contains(err.Error(), "update feeds type in folder") {
-		t.Errorf("expected wrapped error with context, got: %v", err)
+	if !errors.Is(err, dbError) {
+		t.Errorf("expected batch update error, got: %v", err)
 	}
 }
 
-func TestFolderService_Delete_ListFeedsFails(t *testing.T) {
+func TestFolderService_Delete_FolderDeleteFails(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockFolders := mock.NewMockFolderRepository(ctrl)
 	mockFeeds := mock.NewMockFeedRepository(ctrl)
-	svc := service.NewFolderService(mockFolders, mockFeeds)
+	svc := service.NewFolderService(mockFolders, mockFeeds, nil, nil)
 	ctx := context.Background()
 
 	folderID := int64(123)
-	dbError := errors.New("list feeds failed")
+	dbError := errors.New("folder delete failed")
 
 	mockFolders.EXPECT().
 		GetByID(ctx, folderID).
 		Return(model.Folder{ID: folderID, Name: "Test"}, nil)
 
-	mockFeeds.EXPECT().
-		List(ctx, &folderID).
-		Return(nil, dbError)
-
-	err := svc.Delete(ctx, folderID)
-	if err == nil {
-		t.Fatal("expected error, got nil")
-	}
-
-	if !strings.Contains(err.Error(), "list feeds in folder") {
-		t.Errorf("expected wrapped error with context, got: %v", err)
-	}
-}
-
-func TestFolderService_Delete_FeedDeleteBatchFails(t *testing.T) {
-	ctrl := gomock.NewController(t)
-	defer ctrl.Finish()
-
-	mockFolders := mock.NewMockFolderRepository(ctrl)
-	mockFeeds := mock.NewMockFeedRepository(ctrl)
-	svc := service.NewFolderService(mockFolders, mockFeeds)
-	ctx := context.Background()
-
-	folderID := int64(123)
-	dbError := errors.New("feed batch delete failed")
-
 	mockFolders.EXPECT().
-		GetByID(ctx, folderID).
-		Return(model.Folder{ID: folderID, Name: "Test"}, nil)
-
-	feeds := []model.Feed{
-		{ID: 1, FolderID: &folderID, Title: "Feed 1"},
-		{ID: 2, FolderID: &folderID, Title: "Feed 2"},
-	}
-
-	mockFeeds.EXPECT().
-		List(ctx, &folderID).
-		Return(feeds, nil)
-
-	// Batch delete fails
-	mockFeeds.EXPECT().
-		DeleteBatch(ctx, []int64{1, 2}).
-		Return(int64(0), dbError)
+		Delete(ctx, folderID).
+		Return(dbError)
 
 	err := svc.Delete(ctx, folderID)
 	if err == nil {
 		t.Fatal("expected error, got nil")
 	}
 
-	if !strings.Contains(err.Error(), "delete feeds in folder") {
-		t.Errorf("expected error mentioning feed deletion, got: %v", err)
+	if !errors.Is(err, dbError) {
+		t.Errorf("expected original error, got: %v", err)
 	}
 }
 
-func TestFolderService_Delete_FolderDeleteFails(t *testing.T) {
+func TestFolderService_Reorder(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockFolders := mock.NewMockFolderRepository(ctrl)
-	mockFeeds := mock.NewMockFeedRepository(ctrl)
-	svc := service.NewFolderService(mockFolders, mockFeeds)
+	svc := service.NewFolderService(mockFolders, mock.NewMockFeedRepository(ctrl), nil, nil)
 	ctx := context.Background()
+	parentID := int64(10)
 
-	folderID := int64(123)
-	dbError := errors.New("folder delete failed")
+	require.ErrorIs(t, svc.Reorder(ctx, nil), service.ErrInvalid)
+	require.ErrorIs(t, svc.Reorder(ctx, []int64{1, 1}), service.ErrInvalid)
 
-	mockFolders.EXPECT().
-		GetByID(ctx, folderID).
-		Return(model.Folder{ID: folderID, Name: "Test"}, nil)
+	mockFolders.EXPECT().GetByID(ctx, int64(1)).Return(model.Folder{ID: 1, ParentID: &parentID}, nil).Times(2)
+	mockFolders.EXPECT().GetByID(ctx, int64(2)).Return(model.Folder{ID: 2, ParentID: &parentID}, nil)
+	mockFolders.EXPECT().Reorder(ctx, []int64{2, 1}).Return(nil)
+	require.NoError(t, svc.Reorder(ctx, []int64{2, 1}))
 
-	mockFeeds.EXPECT().
-		List(ctx, &folderID).
-		Return([]model.Feed{}, nil)
+	// Siblings only: a top-level folder can't be ordered among children
+	mockFolders.EXPECT().GetByID(ctx, int64(3)).Return(model.Folder{ID: 3}, nil)
+	require.ErrorIs(t, svc.Reorder(ctx, []int64{1, 3}), service.ErrInvalid)
 
-	mockFolders.EXPECT().
-		Delete(ctx, folderID).
-		Return(dbError)
-
-	err := svc.Delete(ctx, folderID)
-	if err == nil {
-		t.Fatal("expected error, got nil")
-	}
-
-	if !errors.Is(err, dbError) {
-		t.Errorf("expected original error, got: %v", err)
-	}
+	mockFolders.EXPECT().GetByID(ctx, int64(4)).Return(model.Folder{}, sql.ErrNoRows)
+	require.ErrorIs(t, svc.Reorder(ctx, []int64{4}), service.ErrNotFound)
 }
@@ -0,0 +1,174 @@
+//go:generate mockgen -source=$GOFILE -destination=mock/$GOFILE -package=mock
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"gist/backend/internal/db"
+	"gist/backend/internal/repository"
+	"gist/backend/pkg/version"
+)
+
+// readinessPingTimeout bounds the database ping so a wedged connection fails fast.
+const readinessPingTimeout = time.Second
+
+// Readiness check names.
+const (
+	HealthCheckDatabase  = "database"
+	HealthCheckDataDir   = "data_dir"
+	HealthCheckMigration = "migration"
+	// HealthCheckFeedCaching warns about feeds whose upstream hid new entries
+	// behind 304 Not Modified answers.
+	HealthCheckFeedCaching = "feed_caching"
+	// HealthCheckFeedStability warns about feeds whose recent refreshes
+	// succeed only some of the time.
+	HealthCheckFeedStability = "feed_stability"
+)
+
+// BuildInfo describes the running binary.
+type BuildInfo struct {
+	Version   string
+	Commit    string
+	BuildTime string
+}
+
+// HealthCheck is the result of a single readiness check.
+type HealthCheck struct {
+	Name  string
+	OK    bool
+	Error string
+}
+
+// HealthWarning is a problem worth reporting that does not affect readiness.
+type HealthWarning struct {
+	Name    string
+	Message string
+}
+
+// ReadinessReport aggregates all readiness checks.
+type ReadinessReport struct {
+	Ready    bool
+	Checks   []HealthCheck
+	Warnings []HealthWarning
+}
+
+type HealthService interface {
+	// BuildInfo returns version information injected at build time.
+	BuildInfo() BuildInfo
+	// Ready runs the database, data directory and migration checks, and
+	// warns about feeds flagged for suspect caching upstream or unstable.
+	Ready(ctx context.Context) ReadinessReport
+}
+
+type healthService struct {
+	db             *sql.DB
+	dataDir        string
+	migrationState func() string
+	feeds          repository.FeedRepository
+	fetchLog       repository.FeedFetchLogRepository
+}
+
+// NewHealthService reports on the database, the data directory and migrations.
+// migrationState defaults to db.MigrationState when nil; feeds and fetchLog, when
+// set, add warnings for feeds flagged for suspect caching or unstable fetches.
+func NewHealthService(database *sql.DB, dataDir string, migrationState func() string, feeds repository.FeedRepository, fetchLog repository.FeedFetchLogRepository) HealthService {
+	if migrationState == nil {
+		migrationState = db.MigrationState
+	}
+	return &healthService{db: database, dataDir: dataDir, migrationState: migrationState, feeds: feeds, fetchLog: fetchLog}
+}
+
+func (s *healthService) BuildInfo() BuildInfo {
+	return BuildInfo{
+		Version:   version.Version,
+		Commit:    version.Commit,
+		BuildTime: version.BuildTime,
+	}
+}
+
+func (s *healthService) Ready(ctx context.Context) ReadinessReport {
+	checks := []HealthCheck{
+		newHealthCheck(HealthCheckDatabase, s.checkDatabase(ctx)),
+		newHealthCheck(HealthCheckDataDir, s.checkDataDir()),
+		newHealthCheck(HealthCheckMigration, s.checkMigration()),
+	}
+
+	ready := true
+	for _, check := range checks {
+		if !check.OK {
+			ready = false
+		}
+	}
+	return ReadinessReport{Ready: ready, Checks: checks, Warnings: s.warnings(ctx)}
+}
+
+func (s *healthService) warnings(ctx context.Context) []HealthWarning {
+	var warnings []HealthWarning
+	if s.feeds != nil {
+		if count, err := s.feeds.CountSuspectCaching(ctx); err == nil && count > 0 {
+			warnings = append(warnings, HealthWarning{Name: HealthCheckFeedCaching, Message: fmt.Sprintf("suspect caching upstream on %d feeds", count)})
+		}
+	}
+	if s.fetchLog != nil {
+		if stats, err := s.fetchLog.ListStats(ctx); err == nil {
+			unstable := 0
+			for _, feed := range stats {
+				if feedFetchHealth(feed) == FeedFetchHealthUnstable {
+					unstable++
+				}
+			}
+			if unstable > 0 {
+				warnings = append(warnings, HealthWarning{Name: HealthCheckFeedStability, Message: fmt.Sprintf("%d feeds unstable over their last %d refreshes", unstable, feedFetchLogKeep)})
+			}
+		}
+	}
+	return warnings
+}
+
+func (s *healthService) checkDatabase(ctx context.Context) error {
+	if s.db == nil {
+		return errors.New("database not configured")
+	}
+	ctx, cancel := context.WithTimeout(ctx, readinessPingTimeout)
+	defer cancel()
+	if err := s.db.PingContext(ctx); err != nil {
+		return fmt.Errorf("ping database: %w", err)
+	}
+	return nil
+}
+
+func (s *healthService) checkDataDir() error {
+	f, err := os.CreateTemp(s.dataDir, ".readyz-*")
+	if err != nil {
+		return fmt.Errorf("data dir not writable: %w", err)
+	}
+	name := f.Name()
+	if err := f.Close(); err != nil {
+		_ = os.Remove(name)
+		return fmt.Errorf("close probe file: %w", err)
+	}
+	if err := os.Remove(name); err != nil {
+		return fmt.Errorf("remove probe file: %w", err)
+	}
+	return nil
+}
+
+func (s *healthService) checkMigration() error {
+	state := s.migrationState()
+	if state != db.MigrationComplete {
+		return fmt.Errorf("migration %s", state)
+	}
+	return nil
+}
+
+func newHealthCheck(name string, err error) HealthCheck {
+	if err != nil {
+		return HealthCheck{Name: name, Error: err.Error()}
+	}
+	return HealthCheck{Name: name, OK: true}
+}
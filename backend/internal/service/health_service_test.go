@@ -0,0 +1,125 @@
+package service_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gist/backend/internal/db"
+	"gist/backend/internal/model"
+	"gist/backend/internal/repository"
+	"gist/backend/internal/repository/testutil"
+	"gist/backend/internal/service"
+
+	"github.com/stretchr/testify/require"
+)
+
+func completeMigration() string { return db.MigrationComplete }
+
+func findHealthCheck(t *testing.T, report service.ReadinessReport, name string) service.HealthCheck {
+	t.Helper()
+	for _, check := range report.Checks {
+		if check.Name == name {
+			return check
+		}
+	}
+	t.Fatalf("check %q not found", name)
+	return service.HealthCheck{}
+}
+
+func TestHealthService_Ready_AllChecksPass(t *testing.T) {
+	dir := t.TempDir()
+	svc := service.NewHealthService(testutil.NewTestDB(t), dir, completeMigration, nil, nil)
+
+	report := svc.Ready(context.Background())
+	require.True(t, report.Ready)
+	require.Len(t, report.Checks, 3)
+
+	// The writability probe must not leave files behind
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Empty(t, entries)
+}
+
+func TestHealthService_Ready_DatabaseClosed(t *testing.T) {
+	database := testutil.NewTestDB(t)
+	require.NoError(t, database.Close())
+	svc := service.NewHealthService(database, t.TempDir(), completeMigration, nil, nil)
+
+	report := svc.Ready(context.Background())
+	require.False(t, report.Ready)
+	require.False(t, findHealthCheck(t, report, service.HealthCheckDatabase).OK)
+	require.True(t, findHealthCheck(t, report, service.HealthCheckDataDir).OK)
+}
+
+func TestHealthService_Ready_DataDirMissing(t *testing.T) {
+	svc := service.NewHealthService(testutil.NewTestDB(t), filepath.Join(t.TempDir(), "missing"), completeMigration, nil, nil)
+
+	report := svc.Ready(context.Background())
+	require.False(t, report.Ready)
+	check := findHealthCheck(t, report, service.HealthCheckDataDir)
+	require.False(t, check.OK)
+	require.Contains(t, check.Error, "data dir not writable")
+}
+
+func TestHealthService_Ready_MigrationRunning(t *testing.T) {
+	svc := service.NewHealthService(testutil.NewTestDB(t), t.TempDir(), func() string { return db.MigrationRunning }, nil, nil)
+
+	report := svc.Ready(context.Background())
+	require.False(t, report.Ready)
+	require.Equal(t, "migration running", findHealthCheck(t, report, service.HealthCheckMigration).Error)
+}
+
+func TestHealthService_Ready_WarnsSuspectCaching(t *testing.T) {
+	database := testutil.NewTestDB(t)
+	feeds := repository.NewFeedRepository(database, nil, nil)
+	svc := service.NewHealthService(database, t.TempDir(), nil, feeds, nil)
+	ctx := context.Background()
+
+	require.Empty(t, svc.Ready(ctx).Warnings)
+
+	feed, err := feeds.Create(ctx, model.Feed{URL: "https://example.com/rss", Title: "Feed"})
+	require.NoError(t, err)
+	now := time.Now()
+	require.NoError(t, feeds.UpdateSuspectCaching(ctx, feed.ID, &now))
+
+	report := svc.Ready(ctx)
+	require.Equal(t, []service.HealthWarning{{Name: service.HealthCheckFeedCaching, Message: "suspect caching upstream on 1 feeds"}}, report.Warnings)
+	// Warnings leave readiness to the checks
+	require.True(t, findHealthCheck(t, report, service.HealthCheckDatabase).OK)
+}
+
+func TestHealthService_BuildInfo_Defaults(t *testing.T) {
+	svc := service.NewHealthService(nil, t.TempDir(), nil, nil, nil)
+
+	info := svc.BuildInfo()
+	require.Equal(t, "dev", info.Version)
+	require.NotEmpty(t, info.Commit)
+}
+
+func TestHealthService_Ready_WarnsUnstableFeeds(t *testing.T) {
+	database := testutil.NewTestDB(t)
+	fetchLog := repository.NewFeedFetchLogRepository(database, nil)
+	svc := service.NewHealthService(database, t.TempDir(), nil, repository.NewFeedRepository(database, nil, nil), fetchLog)
+	ctx := context.Background()
+
+	flapping := testutil.SeedFeed(t, database, model.Feed{Title: "Flapping", URL: "https://a.example.com/rss"})
+	broken := testutil.SeedFeed(t, database, model.Feed{Title: "Broken", URL: "https://b.example.com/rss"})
+	steady := testutil.SeedFeed(t, database, model.Feed{Title: "Steady", URL: "https://c.example.com/rss"})
+	// Works 4 out of 5 refreshes, the last one included
+	for i := 0; i < 10; i++ {
+		outcome := model.FeedFetchOK
+		if i%5 == 2 {
+			outcome = model.FeedFetchHTTPError
+		}
+		require.NoError(t, fetchLog.Append(ctx, model.FeedFetch{FeedID: flapping, FetchedAt: time.Now(), Outcome: outcome}, 50))
+		require.NoError(t, fetchLog.Append(ctx, model.FeedFetch{FeedID: broken, FetchedAt: time.Now(), Outcome: model.FeedFetchNetworkError}, 50))
+		require.NoError(t, fetchLog.Append(ctx, model.FeedFetch{FeedID: steady, FetchedAt: time.Now(), Outcome: model.FeedFetchNotModified}, 50))
+	}
+
+	report := svc.Ready(ctx)
+	require.Equal(t, []service.HealthWarning{{Name: service.HealthCheckFeedStability, Message: "1 feeds unstable over their last 50 refreshes"}}, report.Warnings)
+	require.True(t, report.Ready)
+}
@@ -3,6 +3,8 @@ package service
 import (
 	"context"
 	"fmt"
+	"testing"
+	"time"
 
 	"github.com/mmcdole/gofeed"
 
@@ -38,13 +40,20 @@ func FetchIconsForFeedsForTest(svc IconService, ctx context.Context, parser *gof
 	return nil
 }
 
-// DownloadIconWithFreshClientForTest exposes fresh-client download for tests.
-func DownloadIconWithFreshClientForTest(svc IconService, ctx context.Context, iconURL, cookie string, retryCount int) error {
+// SetIconRecheckIntervalForTest overrides how long revalidated icons are trusted.
+func SetIconRecheckIntervalForTest(t *testing.T, interval time.Duration) {
+	previous := iconRecheckInterval
+	iconRecheckInterval = interval
+	t.Cleanup(func() { iconRecheckInterval = previous })
+}
+
+// DownloadIconForTest exposes downloadIconWithFormat for tests.
+func DownloadIconForTest(svc IconService, ctx context.Context, iconURL string) error {
 	impl, ok := svc.(*iconService)
 	if !ok {
 		return fmt.Errorf("invalid icon service")
 	}
-	_, err := impl.downloadIconWithFreshClient(ctx, iconURL, cookie, retryCount)
+	_, err := impl.downloadIconWithFormat(ctx, iconURL)
 	return err
 }
 
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/mmcdole/gofeed"
+)
+
+// maxInlineIconBytes caps an icon decoded from a data: URL.
+const maxInlineIconBytes = 256 << 10
+
+// feedIconURL returns the icon a parsed feed declares: its image, or else an
+// atom:icon or atom:logo element of an RSS feed. gofeed already maps the
+// Atom feed elements to Image.
+func feedIconURL(parsed *gofeed.Feed) string {
+	if parsed == nil {
+		return ""
+	}
+	if parsed.Image != nil {
+		if imageURL := strings.TrimSpace(parsed.Image.URL); imageURL != "" {
+			return imageURL
+		}
+	}
+	atom := parsed.Extensions["atom"]
+	for _, name := range []string{"icon", "logo"} {
+		for _, ext := range atom[name] {
+			if value := strings.TrimSpace(ext.Value); value != "" {
+				return value
+			}
+		}
+	}
+	return ""
+}
+
+func isDataURL(iconURL string) bool {
+	return len(iconURL) >= 5 && strings.EqualFold(iconURL[:5], "data:")
+}
+
+// decodeDataIcon decodes a "data:image/...[;base64],..." URL. Percent-encoded
+// data is accepted too, as inline SVGs are usually written that way.
+func decodeDataIcon(dataURL string) ([]byte, error) {
+	header, payload, ok := strings.Cut(dataURL[len("data:"):], ",")
+	if !ok {
+		return nil, fmt.Errorf("malformed data url")
+	}
+	params := strings.Split(header, ";")
+	if !strings.HasPrefix(strings.ToLower(strings.TrimSpace(params[0])), "image/") {
+		return nil, fmt.Errorf("data url is not an image: %q", params[0])
+	}
+	isBase64 := strings.EqualFold(strings.TrimSpace(params[len(params)-1]), "base64")
+
+	// Neither encoding more than triples the size, so anything longer is too big
+	if len(payload) > 3*maxInlineIconBytes {
+		return nil, fmt.Errorf("data url icon too large")
+	}
+
+	var data []byte
+	if isBase64 {
+		// Feeds wrap long base64 lines
+		payload = strings.Join(strings.Fields(payload), "")
+		decoded, err := base64.StdEncoding.DecodeString(payload)
+		if err != nil {
+			if decoded, err = base64.RawStdEncoding.DecodeString(strings.TrimRight(payload, "=")); err != nil {
+				return nil, fmt.Errorf("decode data url: %w", err)
+			}
+		}
+		data = decoded
+	} else {
+		decoded, err := url.PathUnescape(payload)
+		if err != nil {
+			return nil, fmt.Errorf("decode data url: %w", err)
+		}
+		data = []byte(decoded)
+	}
+
+	if len(data) > maxInlineIconBytes {
+		return nil, fmt.Errorf("data url icon too large: %d bytes", len(data))
+	}
+	return data, nil
+}
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gist/backend/pkg/logger"
+	"gist/backend/pkg/network"
+)
+
+// iconRecheckInterval is how long a revalidated icon is trusted before its
+// server is asked again.
+var iconRecheckInterval = 7 * 24 * time.Hour
+
+// errIconNotModified is returned for a 304 answer to a conditional icon fetch.
+var errIconNotModified = errors.New("icon not modified")
+
+// iconMeta is what is known about the download of an icon file, kept as JSON
+// under icon-meta/ with the icon's filename. Icons saved before metadata was
+// kept, generated ones and those decoded from data: URLs have none.
+type iconMeta struct {
+	// URL is where the icon was downloaded from; the validators only hold for it.
+	URL          string    `json:"url"`
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"lastModified,omitempty"`
+	SHA256       string    `json:"sha256"`
+	CheckedAt    time.Time `json:"checkedAt"`
+}
+
+func (s *iconService) iconMetaDir() string {
+	return filepath.Join(s.dataDir, "icon-meta")
+}
+
+func (s *iconService) iconMetaPath(iconPath string) string {
+	return filepath.Join(s.iconMetaDir(), filepath.Clean(iconPath)+".json")
+}
+
+// loadIconMeta returns the metadata of an icon, nil when there is none or it
+// cannot be read.
+func (s *iconService) loadIconMeta(iconPath string) *iconMeta {
+	data, err := os.ReadFile(s.iconMetaPath(iconPath))
+	if err != nil {
+		return nil
+	}
+	var meta iconMeta
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return nil
+	}
+	return &meta
+}
+
+func (s *iconService) saveIconMeta(iconPath string, meta iconMeta) {
+	data, err := json.Marshal(meta)
+	if err == nil {
+		path := s.iconMetaPath(iconPath)
+		if err = os.MkdirAll(filepath.Dir(path), 0755); err == nil {
+			err = os.WriteFile(path, data, 0644)
+		}
+	}
+	if err != nil {
+		logger.Debug("icon meta save failed", "module", "service", "action", "save", "resource", "icon", "result", "failed", "path", iconPath, "error", err)
+	}
+}
+
+// recordIconDownload stores the metadata of an icon just downloaded from
+// iconURL. data: URLs have nothing to revalidate and are skipped.
+func (s *iconService) recordIconDownload(iconPath, iconURL string, result *iconDownloadResult) {
+	if isDataURL(iconURL) {
+		return
+	}
+	s.saveIconMeta(iconPath, iconMeta{
+		URL:          iconURL,
+		ETag:         result.etag,
+		LastModified: result.lastModified,
+		SHA256:       iconHash(result.data),
+		CheckedAt:    time.Now(),
+	})
+}
+
+func iconHash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// refreshIcon revalidates a stale domain-based icon with the URL and validators
+// it was downloaded with, rewriting the file only when the content changed.
+// Icons checked within iconRecheckInterval are left alone, and icons without
+// metadata are downloaded again once so later refreshes can be conditional.
+func (s *iconService) refreshIcon(ctx context.Context, iconPath, siteURL string) error {
+	meta := s.loadIconMeta(iconPath)
+	if meta != nil && time.Since(meta.CheckedAt) < iconRecheckInterval {
+		return nil
+	}
+	if meta == nil || meta.URL == "" {
+		return s.downloadFavicon(ctx, iconPath, siteURL)
+	}
+
+	result, err := s.downloadIconConditional(ctx, meta.URL, meta)
+	if errors.Is(err, errIconNotModified) {
+		meta.CheckedAt = time.Now()
+		s.saveIconMeta(iconPath, *meta)
+		logger.Debug("icon not modified", "module", "service", "action", "fetch", "resource", "icon", "result", "skipped", "path", iconPath)
+		return nil
+	}
+	if err != nil {
+		// The stale icon keeps being served
+		logger.Debug("icon revalidation failed", "module", "service", "action", "fetch", "resource", "icon", "result", "failed", "host", network.ExtractHost(meta.URL), "error", err)
+		return nil
+	}
+
+	if iconHash(result.data) != meta.SHA256 {
+		if err := os.WriteFile(filepath.Join(s.dataDir, "icons", filepath.Clean(iconPath)), result.data, 0644); err != nil {
+			return fmt.Errorf("write icon file: %w", err)
+		}
+		logger.Info("icon updated", "module", "service", "action", "save", "resource", "icon", "result", "ok", "path", iconPath)
+	}
+	s.recordIconDownload(iconPath, meta.URL, result)
+	return nil
+}
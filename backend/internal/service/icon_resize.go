@@ -0,0 +1,154 @@
+package service
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gist/backend/pkg/logger"
+)
+
+// MaxIconSize is the largest edge length ResolveIcon resizes to.
+const MaxIconSize = 256
+
+// maxIconSourcePixels bounds the images decoded for resizing; larger ones are served as they are.
+const maxIconSourcePixels = 4096 * 4096
+
+// resizableIconExts are the raster formats ResolveIcon scales down. SVG scales
+// by itself and ICO usually already carries small sizes, so both pass through.
+var resizableIconExts = map[string]bool{".png": true, ".jpg": true, ".jpeg": true, ".gif": true}
+
+func (s *iconService) ResolveIcon(filename string, size int) (string, error) {
+	if size < 0 || size > MaxIconSize {
+		return "", ErrInvalid
+	}
+	if !isValidIconPath(filename) {
+		return "", ErrNotFound
+	}
+	filename = filepath.Clean(filename)
+	fullPath := filepath.Join(s.dataDir, "icons", filename)
+	info, err := os.Stat(fullPath)
+	if err != nil || info.IsDir() {
+		return "", ErrNotFound
+	}
+
+	ext := strings.ToLower(filepath.Ext(filename))
+	if size == 0 || !resizableIconExts[ext] {
+		return fullPath, nil
+	}
+
+	variantPath := filepath.Join(filepath.Dir(fullPath), strings.TrimSuffix(filepath.Base(filename), filepath.Ext(filename))+"@"+strconv.Itoa(size)+".png")
+	// The original is re-downloaded when it goes stale, so the variant must be at least as new
+	if variant, err := os.Stat(variantPath); err == nil && !variant.ModTime().Before(info.ModTime()) {
+		return variantPath, nil
+	}
+
+	resized, err := resizeIconFile(fullPath, variantPath, size)
+	if err != nil {
+		logger.Debug("icon resize failed", "module", "service", "action", "resize", "resource", "icon", "result", "failed", "filename", filename, "size", size, "error", err)
+		return fullPath, nil
+	}
+	if !resized {
+		return fullPath, nil
+	}
+	logger.Debug("icon resized", "module", "service", "action", "resize", "resource", "icon", "result", "ok", "filename", filename, "size", size)
+	return variantPath, nil
+}
+
+// resizeIconFile writes a PNG of src scaled down to size pixels on its longer edge
+// to dst. It reports false, writing nothing, when src is already small enough.
+func resizeIconFile(src, dst string, size int) (bool, error) {
+	file, err := os.Open(src)
+	if err != nil {
+		return false, err
+	}
+	defer file.Close()
+
+	config, _, err := image.DecodeConfig(file)
+	if err != nil {
+		return false, fmt.Errorf("decode icon config: %w", err)
+	}
+	if config.Width <= size && config.Height <= size {
+		return false, nil
+	}
+	if config.Width*config.Height > maxIconSourcePixels {
+		return false, fmt.Errorf("icon too large: %dx%d", config.Width, config.Height)
+	}
+	if _, err := file.Seek(0, 0); err != nil {
+		return false, err
+	}
+	// GIF decodes to its first frame
+	img, _, err := image.Decode(file)
+	if err != nil {
+		return false, fmt.Errorf("decode icon: %w", err)
+	}
+
+	// Write to a temp file first so concurrent requests never serve a partial PNG
+	tmp, err := os.CreateTemp(filepath.Dir(dst), ".resize-*")
+	if err != nil {
+		return false, err
+	}
+	defer os.Remove(tmp.Name())
+	if err := png.Encode(tmp, downscaleImage(img, size)); err != nil {
+		tmp.Close()
+		return false, fmt.Errorf("encode icon: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return false, err
+	}
+	if err := os.Rename(tmp.Name(), dst); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// downscaleImage shrinks src so its longer edge is size pixels, keeping the aspect ratio.
+func downscaleImage(src image.Image, size int) *image.NRGBA {
+	w, h := src.Bounds().Dx(), src.Bounds().Dy()
+	dw, dh := size, size
+	if w > h {
+		dh = max(1, h*size/w)
+	} else if h > w {
+		dw = max(1, w*size/h)
+	}
+	return scaleImage(src, dw, dh)
+}
+
+// scaleImage shrinks src to dw by dh pixels, which must not exceed its own size.
+// Each target pixel averages the source pixels it covers, weighted by alpha.
+func scaleImage(src image.Image, dw, dh int) *image.NRGBA {
+	bounds := src.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+
+	dst := image.NewNRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < dh; y++ {
+		y0, y1 := bounds.Min.Y+y*h/dh, bounds.Min.Y+(y+1)*h/dh
+		for x := 0; x < dw; x++ {
+			x0, x1 := bounds.Min.X+x*w/dw, bounds.Min.X+(x+1)*w/dw
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					// RGBA returns alpha-premultiplied 16-bit channels
+					cr, cg, cb, ca := src.At(sx, sy).RGBA()
+					r, g, b, a = r+uint64(cr), g+uint64(cg), b+uint64(cb), a+uint64(ca)
+					n++
+				}
+			}
+			if a == 0 {
+				continue
+			}
+			dst.SetNRGBA(x, y, color.NRGBA{
+				R: uint8(r * 0xff / a),
+				G: uint8(g * 0xff / a),
+				B: uint8(b * 0xff / a),
+				A: uint8(a / n >> 8),
+			})
+		}
+	}
+	return dst
+}
@@ -6,7 +6,6 @@ import (
 	"context"
 	"crypto/sha256"
 	"encoding/hex"
-	"errors"
 	"fmt"
 	"image"
 	_ "image/gif"
@@ -28,17 +27,25 @@ import (
 	"gist/backend/internal/repository"
 	"gist/backend/pkg/logger"
 	"gist/backend/pkg/network"
+	"gist/backend/pkg/sanitizer"
 )
 
 const (
 	iconTimeout        = 30 * time.Second
 	maxConcurrentIcons = 4 // Concurrent icon fetch limit
+	// iconBackfillBatch is how many feeds the backfill handles between quiet hours checks.
+	iconBackfillBatch = 20
 )
 
 type IconService interface {
 	// FetchAndSaveIcon downloads and saves the icon locally
 	// Returns relative path like "example.com.ico" or "example.com.png" based on domain and detected format
+	// feedImageURL may be a data: URL, decoded instead of downloaded. SVG icons are saved sanitized.
 	FetchAndSaveIcon(ctx context.Context, feedImageURL, siteURL string) (string, error)
+	// GenerateIcon saves a 64x64 PNG of the title's initial on a color derived
+	// from the site's host, for feeds no icon could be fetched for. The same host
+	// and title always give the same file. Returns "" when siteURL has no host.
+	GenerateIcon(siteURL, title string) (string, error)
 	// EnsureIcon checks if the icon file exists, re-downloads if missing
 	EnsureIcon(ctx context.Context, iconPath, siteURL string) error
 	// EnsureIconByFeedID checks if icon exists, fetches feed's siteURL and re-downloads if missing
@@ -47,6 +54,11 @@ type IconService interface {
 	BackfillIcons(ctx context.Context) error
 	// GetIconPath returns the full path for an icon file
 	GetIconPath(filename string) string
+	// ResolveIcon returns the full path of the file to serve for an icon, scaled down so
+	// its longer edge is at most size pixels (0 keeps the original). Raster variants are
+	// cached next to the original as "<name>@<size>.png"; SVG and ICO pass through.
+	// Returns ErrNotFound for unsafe or missing icons and ErrInvalid for a size above MaxIconSize.
+	ResolveIcon(filename string, size int) (string, error)
 	// ClearAllIcons deletes all icon files and clears icon_path in database
 	ClearAllIcons(ctx context.Context) (int64, error)
 }
@@ -56,14 +68,17 @@ type iconService struct {
 	feeds         repository.FeedRepository
 	clientFactory *network.ClientFactory
 	anubis        AnubisSolver
+	// settings supplies the Anubis retry options; nil uses the defaults.
+	settings SettingsService
 }
 
-func NewIconService(dataDir string, feeds repository.FeedRepository, clientFactory *network.ClientFactory, anubisSolver AnubisSolver) IconService {
+func NewIconService(dataDir string, feeds repository.FeedRepository, clientFactory *network.ClientFactory, anubisSolver AnubisSolver, settings SettingsService) IconService {
 	return &iconService{
 		dataDir:       dataDir,
 		feeds:         feeds,
 		clientFactory: clientFactory,
 		anubis:        anubisSolver,
+		settings:      settings,
 	}
 }
 
@@ -186,6 +201,7 @@ func (s *iconService) FetchAndSaveIcon(ctx context.Context, feedImageURL, siteUR
 	if err := os.WriteFile(fullPath, result.data, 0644); err != nil {
 		return "", fmt.Errorf("write icon file: %w", err)
 	}
+	s.recordIconDownload(iconPath, successURL, result)
 
 	logger.Info("icon saved", "module", "service", "action", "save", "resource", "icon", "result", "ok", "path", iconPath, "host", network.ExtractHost(siteURL), "format", result.format.ext)
 	return iconPath, nil
@@ -216,39 +232,47 @@ func (s *iconService) EnsureIcon(ctx context.Context, iconPath, siteURL string)
 		return nil // Cannot recover, skip
 	}
 
-	// File missing, try to re-download:
-	// 1. Local /favicon.ico
-	// 2. Google Favicon API
-	var iconData []byte
+	return s.downloadFavicon(ctx, iconPath, siteURL)
+}
+
+// downloadFavicon downloads the site's favicon to iconPath, trying:
+// 1. Local /favicon.ico
+// 2. Google Favicon API
+func (s *iconService) downloadFavicon(ctx context.Context, iconPath, siteURL string) error {
+	var result *iconDownloadResult
+	var iconURL string
 	var err error
 
 	// Try local favicon.ico first
 	if localURL := s.buildLocalFaviconURL(siteURL); localURL != "" {
-		iconData, err = s.downloadIcon(ctx, localURL)
+		result, err = s.downloadIconWithFormat(ctx, localURL)
 		if err != nil {
 			logger.Debug("local favicon.ico download failed", "module", "service", "action", "fetch", "resource", "icon", "result", "failed", "host", network.ExtractHost(localURL), "error", err)
 		}
+		iconURL = localURL
 	}
 
 	// Fallback to Google Favicon API
-	if iconData == nil {
-		googleURL := s.buildFaviconURL(siteURL)
-		if googleURL == "" {
+	if result == nil {
+		iconURL = s.buildFaviconURL(siteURL)
+		if iconURL == "" {
 			return nil
 		}
-		iconData, err = s.downloadIcon(ctx, googleURL)
+		result, err = s.downloadIconWithFormat(ctx, iconURL)
 		if err != nil {
 			return nil // Silently fail
 		}
 	}
 
+	fullPath := filepath.Join(s.dataDir, "icons", filepath.Clean(iconPath))
 	if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
 		return fmt.Errorf("create icons dir: %w", err)
 	}
 
-	if err := os.WriteFile(fullPath, iconData, 0644); err != nil {
+	if err := os.WriteFile(fullPath, result.data, 0644); err != nil {
 		return fmt.Errorf("write icon file: %w", err)
 	}
+	s.recordIconDownload(iconPath, iconURL, result)
 
 	return nil
 }
@@ -327,6 +351,9 @@ func (s *iconService) GetIconPath(filename string) string {
 }
 
 func (s *iconService) BackfillIcons(ctx context.Context) error {
+	if err := waitOutQuietHours(ctx, s.settings, "icon"); err != nil {
+		return err
+	}
 	parser := gofeed.NewParser()
 
 	// 1. Fetch icons for feeds without icon_path in DB
@@ -338,7 +365,9 @@ func (s *iconService) BackfillIcons(ctx context.Context) error {
 	if len(feeds) > 0 {
 		logger.Info("icon backfill started", "module", "service", "action", "fetch", "resource", "icon", "result", "ok", "count", len(feeds))
 	}
-	s.fetchIconsForFeeds(ctx, parser, feeds)
+	if err := s.fetchIconBatches(ctx, parser, feeds); err != nil {
+		return err
+	}
 
 	// 2. Re-download missing or stale icon files
 	allFeeds, err := s.feeds.List(ctx, nil)
@@ -351,7 +380,12 @@ func (s *iconService) BackfillIcons(ctx context.Context) error {
 	now := time.Now()
 
 	var feedsNeedRefetch []int64
-	for _, feed := range allFeeds {
+	for i, feed := range allFeeds {
+		if i > 0 && i%iconBackfillBatch == 0 {
+			if err := waitOutQuietHours(ctx, s.settings, "icon"); err != nil {
+				return err
+			}
+		}
 		if feed.IconPath == nil || *feed.IconPath == "" {
 			continue
 		}
@@ -369,6 +403,10 @@ func (s *iconService) BackfillIcons(ctx context.Context) error {
 		if !needRefresh {
 			continue
 		}
+		// Drawn icons have nothing to revalidate
+		if statErr == nil && feed.IconSource != nil && *feed.IconSource == model.IconSourceGenerated {
+			continue
+		}
 
 		// Hash-based icons need re-fetch via RSS parsing
 		if isHashFilename(*feed.IconPath) {
@@ -376,12 +414,17 @@ func (s *iconService) BackfillIcons(ctx context.Context) error {
 			continue
 		}
 
-		// Domain-based icons can be re-downloaded directly
+		// Domain-based icons can be re-downloaded directly, or revalidated
+		// with their ETag and Last-Modified when the file is still there
 		siteURL := feed.URL
 		if feed.SiteURL != nil && *feed.SiteURL != "" {
 			siteURL = *feed.SiteURL
 		}
-		_ = s.EnsureIcon(ctx, *feed.IconPath, siteURL)
+		if statErr != nil {
+			_ = s.EnsureIcon(ctx, *feed.IconPath, siteURL)
+		} else {
+			_ = s.refreshIcon(ctx, cleanPath, siteURL)
+		}
 	}
 
 	// 3. Re-fetch hash-based icons by clearing DB and re-parsing RSS
@@ -390,7 +433,9 @@ func (s *iconService) BackfillIcons(ctx context.Context) error {
 			_ = s.feeds.UpdateIconPath(ctx, feedID, "")
 		}
 		if feedsToRefetch, err := s.feeds.ListWithoutIcon(ctx); err == nil {
-			s.fetchIconsForFeeds(ctx, parser, feedsToRefetch)
+			if err := s.fetchIconBatches(ctx, parser, feedsToRefetch); err != nil {
+				return err
+			}
 		} else {
 			logger.Warn("icon backfill refetch list failed", "module", "service", "action", "list", "resource", "icon", "result", "failed", "error", err)
 		}
@@ -400,6 +445,20 @@ func (s *iconService) BackfillIcons(ctx context.Context) error {
 	return nil
 }
 
+// fetchIconBatches fetches icons for feeds in batches, pausing between them
+// while quiet hours are on.
+func (s *iconService) fetchIconBatches(ctx context.Context, parser *gofeed.Parser, feeds []model.Feed) error {
+	for start := 0; start < len(feeds); start += iconBackfillBatch {
+		if start > 0 {
+			if err := waitOutQuietHours(ctx, s.settings, "icon"); err != nil {
+				return err
+			}
+		}
+		s.fetchIconsForFeeds(ctx, parser, feeds[start:min(start+iconBackfillBatch, len(feeds))])
+	}
+	return nil
+}
+
 // fetchIconsForFeeds parses RSS feeds to get imageURL and fetches icons concurrently
 func (s *iconService) fetchIconsForFeeds(ctx context.Context, parser *gofeed.Parser, feeds []model.Feed) {
 	g, ctx := errgroup.WithContext(ctx)
@@ -415,18 +474,12 @@ func (s *iconService) fetchIconsForFeeds(ctx context.Context, parser *gofeed.Par
 
 			// Try to parse feed to get imageURL from RSS
 			imageURL := ""
-			if parsed, err := parser.ParseURLWithContext(feed.URL, ctx); err == nil && parsed.Image != nil {
-				imageURL = strings.TrimSpace(parsed.Image.URL)
+			if parsed, err := parser.ParseURLWithContext(feed.URL, ctx); err == nil {
+				imageURL = feedIconURL(parsed)
 			}
 
-			iconPath, err := s.FetchAndSaveIcon(ctx, imageURL, siteURL)
-			if err != nil || iconPath == "" {
-				if err != nil {
-					logger.Debug("icon fetch failed", "module", "service", "action", "fetch", "resource", "icon", "result", "failed", "feed_id", feed.ID, "error", err)
-				}
-				return nil // Don't propagate error, continue with other feeds
-			}
-			_ = s.feeds.UpdateIconPath(ctx, feed.ID, iconPath)
+			// Errors are logged, not propagated, so other feeds continue
+			saveFeedIcon(ctx, s, s.feeds, feed.ID, feed.DisplayTitle(), imageURL, siteURL)
 			return nil
 
 		})
@@ -522,75 +575,47 @@ func iconFilename(siteURL, ext string) string {
 	return filepath.Clean(parsed.Hostname()) + ext
 }
 
-// iconDownloadResult holds the downloaded icon data and format info
+// iconDownloadResult holds the downloaded icon data and format info, with
+// the validators the server sent for it
 type iconDownloadResult struct {
-	data   []byte
-	format *iconFormat
-}
-
-func (s *iconService) downloadIcon(ctx context.Context, iconURL string) ([]byte, error) {
-	result, err := s.downloadIconWithFormat(ctx, iconURL)
-	if err != nil {
-		return nil, err
-	}
-	return result.data, nil
+	data         []byte
+	format       *iconFormat
+	etag         string
+	lastModified string
 }
 
-// downloadIconWithFormat downloads icon and detects its format
+// downloadIconWithFormat downloads icon and detects its format. A data: URL
+// is decoded in place of a download. SVGs come back sanitized.
 func (s *iconService) downloadIconWithFormat(ctx context.Context, iconURL string) (*iconDownloadResult, error) {
-	return s.downloadIconWithRetry(ctx, iconURL, "", 0)
+	return s.downloadIconConditional(ctx, iconURL, nil)
 }
 
-func (s *iconService) downloadIconWithRetry(ctx context.Context, iconURL string, cookie string, retryCount int) (*iconDownloadResult, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, iconURL, nil)
-	if err != nil {
-		return nil, err
-	}
-	req.Header.Set("User-Agent", config.DefaultUserAgent)
-
-	// Add cookie (either provided or from cache)
-	if cookie == "" {
-		if parsed, err := url.Parse(iconURL); err == nil {
-			if cachedCookie := getCachedAnubisCookie(ctx, s.anubis, parsed.Host, req.Header); cachedCookie != "" {
-				cookie = cachedCookie
-			}
+// downloadIconConditional is downloadIconWithFormat sending the validators of
+// known, if any. It returns errIconNotModified when the server answers 304.
+func (s *iconService) downloadIconConditional(ctx context.Context, iconURL string, known *iconMeta) (*iconDownloadResult, error) {
+	var data []byte
+	var etag, lastModified string
+	if isDataURL(iconURL) {
+		decoded, err := decodeDataIcon(iconURL)
+		if err != nil {
+			return nil, err
+		}
+		data = decoded
+	} else {
+		attempt, retryCount, err := fetchPastAnubis(ctx, s.anubis, iconURL, anubisRetryOptionsFrom(ctx, s.settings), func(cookie string, _ bool) (anubisAttempt, error) {
+			return s.fetchIcon(ctx, iconURL, cookie, known)
+		})
+		if err != nil {
+			return nil, err
+		}
+		if retryCount > 0 {
+			logger.Debug("icon download solved anubis challenge", "module", "service", "action", "fetch", "resource", "icon", "result", "ok", "host", network.ExtractHost(iconURL), "retry_count", retryCount)
+		}
+		data = attempt.body
+		if attempt.resp != nil {
+			etag = attempt.resp.Header.Get("ETag")
+			lastModified = attempt.resp.Header.Get("Last-Modified")
 		}
-	}
-
-	if cookie != "" {
-		req.Header.Set("Cookie", cookie)
-	}
-
-	httpClient := s.clientFactory.NewHTTPClient(ctx, iconTimeout)
-	resp, err := httpClient.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status: %d", resp.StatusCode)
-	}
-
-	data, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-
-	newCookie, anubisErr := trySolveAnubisChallenge(ctx, s.anubis, data, iconURL, resp.Cookies(), req.Header.Clone(), retryCount)
-	switch {
-	case anubisErr == nil:
-		logger.Debug("icon download detected anubis challenge", "module", "service", "action", "fetch", "resource", "icon", "result", "ok", "host", network.ExtractHost(iconURL))
-		// Retry with fresh client to avoid connection reuse
-		return s.downloadIconWithFreshClient(ctx, iconURL, newCookie, retryCount+1)
-	case errors.Is(anubisErr, errAnubisNotPage):
-		// Not an Anubis page; continue normal icon decoding.
-	case errors.Is(anubisErr, errAnubisRejected):
-		return nil, fmt.Errorf("upstream rejected")
-	case errors.Is(anubisErr, errAnubisRetryExceeded):
-		return nil, fmt.Errorf("anubis challenge persists after %d retries", retryCount)
-	default:
-		return nil, anubisErr
 	}
 
 	// Detect format and validate dimensions (besticon approach)
@@ -599,64 +624,67 @@ func (s *iconService) downloadIconWithRetry(ctx context.Context, iconURL string,
 		return nil, fmt.Errorf("invalid icon format: %w", err)
 	}
 
+	// Icons are served to the browser as they are saved
+	if format.ext == "svg" {
+		if data, err = sanitizer.SanitizeSVG(data); err != nil {
+			return nil, fmt.Errorf("invalid svg icon: %w", err)
+		}
+	}
+
 	return &iconDownloadResult{
-		data:   data,
-		format: format,
+		data:         data,
+		format:       format,
+		etag:         etag,
+		lastModified: lastModified,
 	}, nil
 }
 
-// downloadIconWithFreshClient creates a new http.Client to avoid connection reuse after Anubis
-func (s *iconService) downloadIconWithFreshClient(ctx context.Context, iconURL string, cookie string, retryCount int) (*iconDownloadResult, error) {
+// fetchIcon sends one GET for the icon with a new client, and the Anubis
+// cookie cached for its host when cookie is empty. With known it is
+// conditional on the icon having changed since.
+func (s *iconService) fetchIcon(ctx context.Context, iconURL string, cookie string, known *iconMeta) (anubisAttempt, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, iconURL, nil)
 	if err != nil {
-		return nil, err
+		return anubisAttempt{}, err
 	}
 	req.Header.Set("User-Agent", config.DefaultUserAgent)
+	if known != nil {
+		if known.ETag != "" {
+			req.Header.Set("If-None-Match", known.ETag)
+		}
+		if known.LastModified != "" {
+			req.Header.Set("If-Modified-Since", known.LastModified)
+		}
+	}
+
+	if cookie == "" {
+		if parsed, err := url.Parse(iconURL); err == nil {
+			cookie = getCachedAnubisCookie(ctx, s.anubis, parsed.Host, req.Header)
+		}
+	}
 	if cookie != "" {
 		req.Header.Set("Cookie", cookie)
 	}
 
-	// Use fresh client to avoid connection reuse
-	freshClient := s.clientFactory.NewHTTPClient(ctx, iconTimeout)
-	resp, err := freshClient.Do(req)
+	httpClient := s.clientFactory.NewHTTPClient(ctx, iconTimeout)
+	resp, err := httpClient.Do(req)
 	if err != nil {
-		return nil, err
+		return anubisAttempt{}, err
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotModified && known != nil {
+		return anubisAttempt{}, errIconNotModified
+	}
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status: %d", resp.StatusCode)
+		return anubisAttempt{}, fmt.Errorf("unexpected status: %d", resp.StatusCode)
 	}
 
 	data, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, err
-	}
-
-	newCookie, anubisErr := trySolveAnubisChallenge(ctx, s.anubis, data, iconURL, resp.Cookies(), req.Header.Clone(), retryCount)
-	switch {
-	case anubisErr == nil:
-		return s.downloadIconWithFreshClient(ctx, iconURL, newCookie, retryCount+1)
-	case errors.Is(anubisErr, errAnubisNotPage):
-		// Not an Anubis page; continue normal icon decoding.
-	case errors.Is(anubisErr, errAnubisRejected):
-		return nil, fmt.Errorf("upstream rejected")
-	case errors.Is(anubisErr, errAnubisRetryExceeded):
-		return nil, fmt.Errorf("anubis challenge persists after %d retries", retryCount)
-	default:
-		return nil, anubisErr
-	}
-
-	// Detect format and validate dimensions (besticon approach)
-	format, err := detectImageFormat(data)
-	if err != nil {
-		return nil, fmt.Errorf("invalid icon format: %w", err)
+		return anubisAttempt{}, err
 	}
-
-	return &iconDownloadResult{
-		data:   data,
-		format: format,
-	}, nil
+	return newHTTPAnubisAttempt(resp, data, req.Header.Clone()), nil
 }
 
 func (s *iconService) ClearAllIcons(ctx context.Context) (int64, error) {
@@ -679,6 +707,11 @@ func (s *iconService) ClearAllIcons(ctx context.Context) (int64, error) {
 		}
 	}
 
+	// Without metadata the next fetches are unconditional
+	if err := os.RemoveAll(s.iconMetaDir()); err != nil {
+		logger.Warn("icon meta clear failed", "module", "service", "action", "clear", "resource", "icon", "result", "failed", "error", err)
+	}
+
 	// 2. Clear all icon_path in database
 	_, err = s.feeds.ClearAllIconPaths(ctx)
 	if err != nil {
@@ -705,6 +738,9 @@ func isSVG(data []byte) bool {
 		return false
 	}
 
+	// Editors often leave a BOM or blank lines before the prolog
+	data = bytes.TrimLeft(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")), " \t\r\n")
+
 	// Check if it starts with something reasonable
 	switch {
 	case bytes.HasPrefix(data, []byte("<!")):
@@ -4,10 +4,12 @@ import (
 	"bytes"
 	"context"
 	"crypto/sha256"
+	"encoding/base64"
 	"encoding/hex"
 	"errors"
 	"fmt"
 	"image"
+	"image/color"
 	"image/png"
 	"net/http"
 	"net/http/httptest"
@@ -17,6 +19,7 @@ import (
 	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/mmcdole/gofeed"
 	"github.com/stretchr/testify/require"
@@ -31,6 +34,7 @@ type feedRepoStub struct {
 	listWithoutIconFn   func(context.Context) ([]model.Feed, error)
 	listFn              func(context.Context, *int64) ([]model.Feed, error)
 	updateIconPathFn    func(context.Context, int64, string) error
+	updateGeneratedFn   func(context.Context, int64, string) error
 	getByIDFn           func(context.Context, int64) (model.Feed, error)
 	clearAllIconPathsFn func(context.Context) (int64, error)
 	clearAllCondGetFn   func(context.Context) (int64, error)
@@ -62,6 +66,10 @@ func (f *feedRepoStub) List(ctx context.Context, folderID *int64) ([]model.Feed,
 	return f.listFn(ctx, folderID)
 }
 
+func (f *feedRepoStub) ListWithUnreadCounts(context.Context) ([]model.FeedWithUnread, error) {
+	return nil, nil
+}
+
 func (f *feedRepoStub) ListWithoutIcon(ctx context.Context) ([]model.Feed, error) {
 	if f.listWithoutIconFn == nil {
 		panic("not implemented")
@@ -80,6 +88,16 @@ func (f *feedRepoStub) UpdateIconPath(ctx context.Context, id int64, iconPath st
 	return f.updateIconPathFn(ctx, id, iconPath)
 }
 
+func (f *feedRepoStub) UpdateGeneratedIconPath(ctx context.Context, id int64, iconPath string) error {
+	if f.updateGeneratedFn == nil {
+		panic("not implemented")
+	}
+	return f.updateGeneratedFn(ctx, id, iconPath)
+}
+
+func (f *feedRepoStub) UpdateConditionalGet(context.Context, int64, *string, *string) error {
+	panic("not implemented")
+}
 func (f *feedRepoStub) UpdateErrorMessage(context.Context, int64, *string) error {
 	panic("not implemented")
 }
@@ -88,7 +106,71 @@ func (f *feedRepoStub) UpdateType(context.Context, int64, string) error {
 	panic("not implemented")
 }
 
-func (f *feedRepoStub) UpdateTypeByFolderID(context.Context, int64, string) error {
+func (f *feedRepoStub) UpdateAssumeTimezone(context.Context, int64, *string) error {
+	panic("not implemented")
+}
+
+func (f *feedRepoStub) UpdatePausedUntil(context.Context, int64, *time.Time) error {
+	panic("not implemented")
+}
+
+func (f *feedRepoStub) UpdateDedupeKey(context.Context, int64, string) error {
+	panic("not implemented")
+}
+
+func (f *feedRepoStub) Reorder(context.Context, []int64) error {
+	panic("not implemented")
+}
+
+func (f *feedRepoStub) UpdatePreferredUserAgent(context.Context, int64, string) error {
+	panic("not implemented")
+}
+
+func (f *feedRepoStub) UpdateAutoTranslate(context.Context, int64, string) error {
+	return nil
+}
+
+func (f *feedRepoStub) UpdateUserAgent(context.Context, int64, *string) error {
+	panic("not implemented")
+}
+
+func (f *feedRepoStub) UpdateAutoReadability(context.Context, int64, *bool) error {
+	panic("not implemented")
+}
+
+func (f *feedRepoStub) UpdateLanguage(context.Context, int64, string) error {
+	return nil
+}
+
+func (f *feedRepoStub) UpdatePollHint(context.Context, int64, int, string) error {
+	panic("not implemented")
+}
+
+func (f *feedRepoStub) UpdateLastFetchedAt(context.Context, int64, time.Time) error {
+	panic("not implemented")
+}
+
+func (f *feedRepoStub) UpdateNotModifiedStreak(context.Context, int64, int) error {
+	panic("not implemented")
+}
+
+func (f *feedRepoStub) UpdateLastEntrySeenAt(context.Context, int64, time.Time) error {
+	panic("not implemented")
+}
+
+func (f *feedRepoStub) UpdateSuspectCaching(context.Context, int64, *time.Time) error {
+	panic("not implemented")
+}
+
+func (f *feedRepoStub) CountSuspectCaching(context.Context) (int, error) {
+	panic("not implemented")
+}
+
+func (f *feedRepoStub) PostingInterval(context.Context, int64, int) (time.Duration, error) {
+	panic("not implemented")
+}
+
+func (f *feedRepoStub) UpdateTypeByFolderIDs(context.Context, []int64, string) error {
 	panic("not implemented")
 }
 
@@ -114,10 +196,50 @@ func (f *feedRepoStub) ClearAllConditionalGet(ctx context.Context) (int64, error
 	return f.clearAllCondGetFn(ctx)
 }
 
+func (f *feedRepoStub) UpdateTitles(context.Context, int64, string, *string) error {
+	return nil
+}
+
 func (f *feedRepoStub) UpdateSiteURL(context.Context, int64, string) error {
 	panic("not implemented")
 }
 
+func (f *feedRepoStub) GetActivityStats(context.Context) ([]model.FeedActivityStats, error) {
+	panic("not implemented")
+}
+
+func (f *feedRepoStub) UpdateIngestTokenHash(context.Context, int64, string) error {
+	panic("not implemented")
+}
+
+func (f *feedRepoStub) GetIngestTokenHash(context.Context, int64) (string, error) {
+	panic("not implemented")
+}
+
+func (f *feedRepoStub) ListMutedAuthors(context.Context, int64) ([]string, error) {
+	panic("not implemented")
+}
+
+func (f *feedRepoStub) MuteAuthor(context.Context, int64, string) error {
+	panic("not implemented")
+}
+
+func (f *feedRepoStub) UnmuteAuthor(context.Context, int64, string) error {
+	panic("not implemented")
+}
+
+func (f *feedRepoStub) IsAuthorMuted(context.Context, int64, string) (bool, error) {
+	panic("not implemented")
+}
+
+func (f *feedRepoStub) Restore(context.Context, int64, time.Time) error {
+	panic("not implemented")
+}
+
+func (f *feedRepoStub) PurgeDeleted(context.Context, time.Time) (int64, error) {
+	panic("not implemented")
+}
+
 func pngBytes(t *testing.T, width, height int) []byte {
 	t.Helper()
 	img := image.NewRGBA(image.Rect(0, 0, width, height))
@@ -166,7 +288,7 @@ func TestIconService_FetchAndSaveIcon_ExistingRSSHash(t *testing.T) {
 	filename := hex.EncodeToString(hash[:8]) + ".png"
 	require.NoError(t, os.WriteFile(filepath.Join(iconsDir, filename), []byte("data"), 0644))
 
-	svc := service.NewIconService(dataDir, &feedRepoStub{}, network.NewClientFactoryForTest(&http.Client{}), nil)
+	svc := service.NewIconService(dataDir, &feedRepoStub{}, network.NewClientFactoryForTest(&http.Client{}), nil, nil)
 	got, err := svc.FetchAndSaveIcon(context.Background(), feedImageURL, "https://example.com")
 	require.NoError(t, err)
 	require.Equal(t, filename, got)
@@ -184,7 +306,7 @@ func TestIconService_FetchAndSaveIcon_LocalFavicon(t *testing.T) {
 	defer server.Close()
 
 	dataDir := t.TempDir()
-	svc := service.NewIconService(dataDir, &feedRepoStub{}, network.NewClientFactoryForTest(&http.Client{}), nil)
+	svc := service.NewIconService(dataDir, &feedRepoStub{}, network.NewClientFactoryForTest(&http.Client{}), nil, nil)
 
 	got, err := svc.FetchAndSaveIcon(context.Background(), "", server.URL)
 	require.NoError(t, err)
@@ -198,15 +320,125 @@ func TestIconService_FetchAndSaveIcon_LocalFavicon(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestIconService_FetchAndSaveIcon_DataURL(t *testing.T) {
+	iconData := pngBytes(t, 4, 4)
+	dataURL := "data:image/png;base64," + base64.StdEncoding.EncodeToString(iconData)
+
+	dataDir := t.TempDir()
+	svc := service.NewIconService(dataDir, &feedRepoStub{}, network.NewClientFactoryForTest(&http.Client{}), nil, nil)
+
+	got, err := svc.FetchAndSaveIcon(context.Background(), dataURL, "")
+	require.NoError(t, err)
+	hash := sha256.Sum256([]byte(dataURL))
+	require.Equal(t, hex.EncodeToString(hash[:8])+".png", got)
+
+	saved, err := os.ReadFile(filepath.Join(dataDir, "icons", got))
+	require.NoError(t, err)
+	require.Equal(t, iconData, saved)
+
+	// Not an image, not decodable, or too big: nothing to save
+	oversized := "data:image/png;base64," + base64.StdEncoding.EncodeToString(append(iconData, make([]byte, 256<<10)...))
+	for _, bad := range []string{"data:text/html;base64,PHA+", "data:image/png;base64,%%%", oversized} {
+		got, err := svc.FetchAndSaveIcon(context.Background(), bad, "")
+		require.NoError(t, err)
+		require.Empty(t, got)
+	}
+}
+
+func TestIconService_FetchAndSaveIcon_SanitizesSVG(t *testing.T) {
+	hostile := "\ufeff\n" + `<svg xmlns="http://www.w3.org/2000/svg" onload="alert(document.cookie)" viewBox="0 0 8 8"><script>alert(1)</script><circle r="4"/></svg>`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/svg+xml")
+		_, _ = w.Write([]byte(hostile))
+	}))
+	defer server.Close()
+
+	dataDir := t.TempDir()
+	svc := service.NewIconService(dataDir, &feedRepoStub{}, network.NewClientFactoryForTest(&http.Client{}), nil, nil)
+
+	for _, iconURL := range []string{server.URL + "/icon.svg", "data:image/svg+xml," + url.PathEscape(hostile)} {
+		got, err := svc.FetchAndSaveIcon(context.Background(), iconURL, "")
+		require.NoError(t, err)
+		require.True(t, strings.HasSuffix(got, ".svg"), got)
+
+		saved, err := os.ReadFile(filepath.Join(dataDir, "icons", got))
+		require.NoError(t, err)
+		require.NotContains(t, string(saved), "onload")
+		require.NotContains(t, string(saved), "script")
+		require.Contains(t, string(saved), `viewBox="0 0 8 8"`)
+		require.Contains(t, string(saved), `<circle r="4"/>`)
+	}
+}
+
+func TestIconService_FetchAndSaveIcon_RSSImage(t *testing.T) {
+	iconData := pngBytes(t, 2, 2)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/logo.png" {
+			_, _ = w.Write(iconData)
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	dataDir := t.TempDir()
+	svc := service.NewIconService(dataDir, &feedRepoStub{}, network.NewClientFactoryForTest(&http.Client{}), nil, nil)
+
+	feedImageURL := server.URL + "/logo.png"
+	got, err := svc.FetchAndSaveIcon(context.Background(), feedImageURL, server.URL)
+	require.NoError(t, err)
+	hash := sha256.Sum256([]byte(feedImageURL))
+	require.Equal(t, hex.EncodeToString(hash[:8])+".png", got)
+
+	saved, err := os.ReadFile(filepath.Join(dataDir, "icons", got))
+	require.NoError(t, err)
+	require.Equal(t, iconData, saved)
+}
+
 func TestIconService_FetchAndSaveIcon_NoURLs(t *testing.T) {
 	dataDir := t.TempDir()
-	svc := service.NewIconService(dataDir, &feedRepoStub{}, network.NewClientFactoryForTest(&http.Client{}), nil)
+	svc := service.NewIconService(dataDir, &feedRepoStub{}, network.NewClientFactoryForTest(&http.Client{}), nil, nil)
 
 	got, err := svc.FetchAndSaveIcon(context.Background(), "", "")
 	require.NoError(t, err)
 	require.Empty(t, got)
 }
 
+func TestIconService_GenerateIcon(t *testing.T) {
+	first := service.NewIconService(t.TempDir(), &feedRepoStub{}, nil, nil, nil)
+	second := service.NewIconService(t.TempDir(), &feedRepoStub{}, nil, nil, nil)
+
+	got, err := first.GenerateIcon("https://www.example.com/blog", "the feed")
+	require.NoError(t, err)
+	require.Equal(t, "www.example.com.generated-t.png", got)
+
+	// The same host and title draw the same bytes, so icons don't change between restarts
+	again, err := second.GenerateIcon("https://www.example.com/blog", "the feed")
+	require.NoError(t, err)
+	require.Equal(t, got, again)
+	data, err := os.ReadFile(first.GetIconPath(got))
+	require.NoError(t, err)
+	dataAgain, err := os.ReadFile(second.GetIconPath(again))
+	require.NoError(t, err)
+	require.Equal(t, data, dataAgain)
+
+	img, err := png.Decode(bytes.NewReader(data))
+	require.NoError(t, err)
+	require.Equal(t, image.Rect(0, 0, 64, 64), img.Bounds())
+	// Background in the corner, the glyph's white in the top bar of the T
+	require.NotEqual(t, color.RGBAModel.Convert(color.White), color.RGBAModel.Convert(img.At(0, 0)))
+	require.Equal(t, color.RGBAModel.Convert(color.White), color.RGBAModel.Convert(img.At(32, 13)))
+
+	// Titles without a letter the font has fall back to the host's
+	got, err = first.GenerateIcon("https://www.example.com", "少数派")
+	require.NoError(t, err)
+	require.Equal(t, "www.example.com.generated-e.png", got)
+
+	got, err = first.GenerateIcon("", "Feed")
+	require.NoError(t, err)
+	require.Empty(t, got)
+}
+
 func TestIconService_EnsureIcon_Download(t *testing.T) {
 	iconData := pngBytes(t, 2, 2)
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -219,7 +451,7 @@ func TestIconService_EnsureIcon_Download(t *testing.T) {
 	defer server.Close()
 
 	dataDir := t.TempDir()
-	svc := service.NewIconService(dataDir, &feedRepoStub{}, network.NewClientFactoryForTest(&http.Client{}), nil)
+	svc := service.NewIconService(dataDir, &feedRepoStub{}, network.NewClientFactoryForTest(&http.Client{}), nil, nil)
 
 	parsed, err := url.Parse(server.URL)
 	require.NoError(t, err)
@@ -230,9 +462,92 @@ func TestIconService_EnsureIcon_Download(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestIconService_BackfillIcons_RevalidatesStaleIcons(t *testing.T) {
+	iconData := pngBytes(t, 2, 2)
+	var mu sync.Mutex
+	var requests []http.Header
+	etag := `"v1"`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/favicon.ico" {
+			http.NotFound(w, r)
+			return
+		}
+		mu.Lock()
+		requests = append(requests, r.Header.Clone())
+		current := etag
+		mu.Unlock()
+		if r.Header.Get("If-None-Match") == current {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", current)
+		w.Header().Set("Last-Modified", "Mon, 01 Jan 2024 00:00:00 GMT")
+		_, _ = w.Write(iconData)
+	}))
+	defer server.Close()
+
+	dataDir := t.TempDir()
+	parsed, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	iconPath := parsed.Hostname() + ".png"
+	fullPath := filepath.Join(dataDir, "icons", iconPath)
+	repo := &feedRepoStub{
+		listWithoutIconFn: func(context.Context) ([]model.Feed, error) { return nil, nil },
+		listFn: func(context.Context, *int64) ([]model.Feed, error) {
+			return []model.Feed{{ID: 1, URL: server.URL + "/feed", SiteURL: &server.URL, IconPath: &iconPath}}, nil
+		},
+	}
+	svc := service.NewIconService(dataDir, repo, network.NewClientFactoryForTest(&http.Client{}), nil, nil)
+	ctx := context.Background()
+
+	// The first download records the validators
+	require.NoError(t, svc.EnsureIcon(ctx, iconPath, server.URL))
+	require.Len(t, requests, 1)
+	require.Empty(t, requests[0].Get("If-None-Match"))
+
+	stale := time.Now().Add(-31 * 24 * time.Hour)
+	require.NoError(t, os.Chtimes(fullPath, stale, stale))
+
+	// Checked moments ago, so the server is not asked
+	require.NoError(t, svc.BackfillIcons(ctx))
+	require.Len(t, requests, 1)
+
+	// Once the check is old the icon is revalidated, and a 304 keeps the file
+	service.SetIconRecheckIntervalForTest(t, 0)
+	require.NoError(t, svc.BackfillIcons(ctx))
+	require.Len(t, requests, 2)
+	require.Equal(t, `"v1"`, requests[1].Get("If-None-Match"))
+	require.Equal(t, "Mon, 01 Jan 2024 00:00:00 GMT", requests[1].Get("If-Modified-Since"))
+	info, err := os.Stat(fullPath)
+	require.NoError(t, err)
+	require.WithinDuration(t, stale, info.ModTime(), time.Second)
+
+	// A changed icon is downloaded and written
+	mu.Lock()
+	etag = `"v2"`
+	iconData = pngBytes(t, 3, 3)
+	mu.Unlock()
+	require.NoError(t, svc.BackfillIcons(ctx))
+	require.Len(t, requests, 3)
+	saved, err := os.ReadFile(fullPath)
+	require.NoError(t, err)
+	require.Equal(t, iconData, saved)
+
+	// Clearing the cache forgets the validators
+	repo.clearAllIconPathsFn = func(context.Context) (int64, error) { return 1, nil }
+	repo.clearAllCondGetFn = func(context.Context) (int64, error) { return 1, nil }
+	_, err = svc.ClearAllIcons(ctx)
+	require.NoError(t, err)
+	_, err = os.Stat(filepath.Join(dataDir, "icon-meta"))
+	require.True(t, os.IsNotExist(err))
+	require.NoError(t, svc.EnsureIcon(ctx, iconPath, server.URL))
+	require.Len(t, requests, 4)
+	require.Empty(t, requests[3].Get("If-None-Match"))
+}
+
 func TestIconService_EnsureIcon_InvalidPathAndHash(t *testing.T) {
 	dataDir := t.TempDir()
-	svc := service.NewIconService(dataDir, &feedRepoStub{}, network.NewClientFactoryForTest(&http.Client{}), nil)
+	svc := service.NewIconService(dataDir, &feedRepoStub{}, network.NewClientFactoryForTest(&http.Client{}), nil, nil)
 
 	require.NoError(t, svc.EnsureIcon(context.Background(), "../icon.png", "https://example.com"))
 	require.NoError(t, svc.EnsureIcon(context.Background(), "0123456789abcdef.png", "https://example.com"))
@@ -261,7 +576,7 @@ func TestIconService_EnsureIconByFeedID(t *testing.T) {
 		},
 	}
 
-	svc := service.NewIconService(dataDir, repo, network.NewClientFactoryForTest(&http.Client{}), nil)
+	svc := service.NewIconService(dataDir, repo, network.NewClientFactoryForTest(&http.Client{}), nil, nil)
 
 	err = svc.EnsureIconByFeedID(context.Background(), 1, iconPath)
 	require.NoError(t, err)
@@ -274,13 +589,90 @@ func TestIconService_EnsureIconByFeedID(t *testing.T) {
 
 func TestIconService_GetIconPath(t *testing.T) {
 	dataDir := t.TempDir()
-	svc := service.NewIconService(dataDir, &feedRepoStub{}, network.NewClientFactoryForTest(&http.Client{}), nil)
+	svc := service.NewIconService(dataDir, &feedRepoStub{}, network.NewClientFactoryForTest(&http.Client{}), nil, nil)
 
 	path := svc.GetIconPath("example.com.png")
 	require.Equal(t, filepath.Join(dataDir, "icons", "example.com.png"), path)
 	require.Empty(t, svc.GetIconPath("../bad.png"))
 }
 
+func writeTestPNG(t *testing.T, path string, width, height int) {
+	t.Helper()
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.NRGBA{R: 200, G: 20, B: 20, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	require.NoError(t, os.WriteFile(path, buf.Bytes(), 0644))
+}
+
+func TestIconService_ResolveIcon(t *testing.T) {
+	dataDir := t.TempDir()
+	iconsDir := filepath.Join(dataDir, "icons")
+	require.NoError(t, os.MkdirAll(iconsDir, 0755))
+	svc := service.NewIconService(dataDir, &feedRepoStub{}, network.NewClientFactoryForTest(&http.Client{}), nil, nil)
+
+	writeTestPNG(t, filepath.Join(iconsDir, "big.example.com.png"), 512, 256)
+	writeTestPNG(t, filepath.Join(iconsDir, "small.example.com.png"), 16, 16)
+	require.NoError(t, os.WriteFile(filepath.Join(iconsDir, "vector.example.com.svg"), []byte("<svg></svg>"), 0644))
+
+	t.Run("original without size", func(t *testing.T) {
+		path, err := svc.ResolveIcon("big.example.com.png", 0)
+		require.NoError(t, err)
+		require.Equal(t, filepath.Join(iconsDir, "big.example.com.png"), path)
+	})
+
+	t.Run("raster is resized and cached", func(t *testing.T) {
+		path, err := svc.ResolveIcon("big.example.com.png", 32)
+		require.NoError(t, err)
+		require.Equal(t, filepath.Join(iconsDir, "big.example.com@32.png"), path)
+
+		file, err := os.Open(path)
+		require.NoError(t, err)
+		defer file.Close()
+		img, err := png.Decode(file)
+		require.NoError(t, err)
+		require.Equal(t, image.Pt(32, 16), img.Bounds().Size())
+		r, g, b, a := img.At(5, 5).RGBA()
+		require.Equal(t, []uint32{200, 20, 20, 255}, []uint32{r >> 8, g >> 8, b >> 8, a >> 8})
+
+		// Served from disk the second time
+		before, err := os.Stat(path)
+		require.NoError(t, err)
+		again, err := svc.ResolveIcon("big.example.com.png", 32)
+		require.NoError(t, err)
+		require.Equal(t, path, again)
+		after, err := os.Stat(path)
+		require.NoError(t, err)
+		require.Equal(t, before.ModTime(), after.ModTime())
+	})
+
+	t.Run("small and vector icons pass through", func(t *testing.T) {
+		path, err := svc.ResolveIcon("small.example.com.png", 32)
+		require.NoError(t, err)
+		require.Equal(t, filepath.Join(iconsDir, "small.example.com.png"), path)
+
+		path, err = svc.ResolveIcon("vector.example.com.svg", 32)
+		require.NoError(t, err)
+		require.Equal(t, filepath.Join(iconsDir, "vector.example.com.svg"), path)
+	})
+
+	t.Run("unsafe or missing icons are not found", func(t *testing.T) {
+		for _, name := range []string{"missing.png", "../secret.png", "/etc/passwd", ""} {
+			_, err := svc.ResolveIcon(name, 32)
+			require.ErrorIs(t, err, service.ErrNotFound, name)
+		}
+	})
+
+	t.Run("size out of range", func(t *testing.T) {
+		_, err := svc.ResolveIcon("big.example.com.png", service.MaxIconSize+1)
+		require.ErrorIs(t, err, service.ErrInvalid)
+	})
+}
+
 func TestIconService_ClearAllIcons(t *testing.T) {
 	dataDir := t.TempDir()
 	iconsDir := filepath.Join(dataDir, "icons")
@@ -297,7 +689,7 @@ func TestIconService_ClearAllIcons(t *testing.T) {
 		},
 	}
 
-	svc := service.NewIconService(dataDir, repo, network.NewClientFactoryForTest(&http.Client{}), nil)
+	svc := service.NewIconService(dataDir, repo, network.NewClientFactoryForTest(&http.Client{}), nil, nil)
 	deleted, err := svc.ClearAllIcons(context.Background())
 	require.NoError(t, err)
 	require.Equal(t, int64(2), deleted)
@@ -313,7 +705,7 @@ func TestIconService_BackfillIcons_ListError(t *testing.T) {
 			return nil, errors.New("list failed")
 		},
 	}
-	svc := service.NewIconService(t.TempDir(), repo, network.NewClientFactoryForTest(&http.Client{}), nil)
+	svc := service.NewIconService(t.TempDir(), repo, network.NewClientFactoryForTest(&http.Client{}), nil, nil)
 
 	err := svc.BackfillIcons(context.Background())
 	require.Error(t, err)
@@ -328,7 +720,7 @@ func TestIconService_BackfillIcons_NoFeeds(t *testing.T) {
 			return nil, nil
 		},
 	}
-	svc := service.NewIconService(t.TempDir(), repo, network.NewClientFactoryForTest(&http.Client{}), nil)
+	svc := service.NewIconService(t.TempDir(), repo, network.NewClientFactoryForTest(&http.Client{}), nil, nil)
 
 	err := svc.BackfillIcons(context.Background())
 	require.NoError(t, err)
@@ -371,7 +763,7 @@ func TestIconService_FetchIconsForFeeds(t *testing.T) {
 		return nil
 	}
 
-	svc := service.NewIconService(dataDir, repo, network.NewClientFactoryForTest(&http.Client{}), nil)
+	svc := service.NewIconService(dataDir, repo, network.NewClientFactoryForTest(&http.Client{}), nil, nil)
 	feeds := []model.Feed{{ID: 10, URL: server.URL + "/rss", Title: "Test"}}
 
 	err := service.FetchIconsForFeedsForTest(svc, context.Background(), gofeed.NewParser(), feeds)
@@ -385,7 +777,50 @@ func TestIconService_FetchIconsForFeeds(t *testing.T) {
 	require.NoError(t, err)
 }
 
-func TestIconService_DownloadIconWithFreshClient(t *testing.T) {
+func TestIconService_FetchIconsForFeeds_AtomIcon(t *testing.T) {
+	iconData := pngBytes(t, 2, 2)
+
+	var baseURL string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/rss", func(w http.ResponseWriter, r *http.Request) {
+		rss := fmt.Sprintf(`<?xml version="1.0"?>
+<rss version="2.0" xmlns:atom="http://www.w3.org/2005/Atom">
+  <channel>
+    <title>Test</title>
+    <link>%s</link>
+    <atom:icon>%s/atom-icon.png</atom:icon>
+  </channel>
+</rss>`, baseURL, baseURL)
+		_, _ = w.Write([]byte(rss))
+	})
+	mux.HandleFunc("/atom-icon.png", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(iconData)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	baseURL = server.URL
+
+	dataDir := t.TempDir()
+	var updated string
+	repo := &feedRepoStub{}
+	repo.updateIconPathFn = func(ctx context.Context, id int64, iconPath string) error {
+		repo.mu.Lock()
+		defer repo.mu.Unlock()
+		updated = iconPath
+		return nil
+	}
+
+	svc := service.NewIconService(dataDir, repo, network.NewClientFactoryForTest(&http.Client{}), nil, nil)
+	feeds := []model.Feed{{ID: 10, URL: server.URL + "/rss", Title: "Test"}}
+	require.NoError(t, service.FetchIconsForFeedsForTest(svc, context.Background(), gofeed.NewParser(), feeds))
+
+	hash := sha256.Sum256([]byte(server.URL + "/atom-icon.png"))
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	require.Equal(t, hex.EncodeToString(hash[:8])+".png", updated)
+}
+
+func TestIconService_DownloadIcon(t *testing.T) {
 	iconData := pngBytes(t, 2, 2)
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		_, _ = w.Write(iconData)
@@ -393,9 +828,9 @@ func TestIconService_DownloadIconWithFreshClient(t *testing.T) {
 	defer server.Close()
 
 	dataDir := t.TempDir()
-	svc := service.NewIconService(dataDir, &feedRepoStub{}, network.NewClientFactoryForTest(&http.Client{}), nil)
+	svc := service.NewIconService(dataDir, &feedRepoStub{}, network.NewClientFactoryForTest(&http.Client{}), nil, nil)
 
-	err := service.DownloadIconWithFreshClientForTest(svc, context.Background(), server.URL, "", 0)
+	err := service.DownloadIconForTest(svc, context.Background(), server.URL)
 	require.NoError(t, err)
 }
 
@@ -403,7 +838,7 @@ func TestIconService_DownloadIconWithFreshClient(t *testing.T) {
 // See commit 8a23586: fix: Add DuckDuckGo Favicon API as fallback
 func TestIconService_BuildDDGFaviconURL(t *testing.T) {
 	dataDir := t.TempDir()
-	svc := service.NewIconService(dataDir, &feedRepoStub{}, network.NewClientFactoryForTest(&http.Client{}), nil)
+	svc := service.NewIconService(dataDir, &feedRepoStub{}, network.NewClientFactoryForTest(&http.Client{}), nil, nil)
 
 	tests := []struct {
 		name     string
@@ -471,7 +906,7 @@ func TestIconService_FetchAndSaveIcon_DDGFallback(t *testing.T) {
 	defer server.Close()
 
 	dataDir := t.TempDir()
-	svc := service.NewIconService(dataDir, &feedRepoStub{}, network.NewClientFactoryForTest(&http.Client{}), nil)
+	svc := service.NewIconService(dataDir, &feedRepoStub{}, network.NewClientFactoryForTest(&http.Client{}), nil, nil)
 
 	// Test that DDG URL is correctly built
 	parsed, err := url.Parse(server.URL)
@@ -517,7 +952,7 @@ func TestIconService_ClearAllIcons_ResetsConditionalGet(t *testing.T) {
 		},
 	}
 
-	svc := service.NewIconService(dataDir, repo, network.NewClientFactoryForTest(&http.Client{}), nil)
+	svc := service.NewIconService(dataDir, repo, network.NewClientFactoryForTest(&http.Client{}), nil, nil)
 	_, err := svc.ClearAllIcons(context.Background())
 	require.NoError(t, err)
 
@@ -0,0 +1,417 @@
+//go:generate mockgen -source=$GOFILE -destination=mock/$GOFILE -package=mock
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"html"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gist/backend/internal/model"
+	"gist/backend/internal/repository"
+	"gist/backend/pkg/logger"
+	"gist/backend/pkg/network"
+)
+
+const (
+	// imageCacheMaxImageBytes skips single images larger than this.
+	imageCacheMaxImageBytes = 5 << 20
+	// CachedImagePrefix is the URL path cached images are served under.
+	CachedImagePrefix = "/cached-images/"
+)
+
+var (
+	errImageTooLarge = errors.New("image too large")
+	// errImageSVG keeps SVGs, which can carry scripts, out of the same-origin cache.
+	errImageSVG = errors.New("svg images are not cached")
+
+	// imgSrcRegex captures the src attribute of an <img> tag, double or single quoted.
+	imgSrcRegex = regexp.MustCompile(`(?i)(<img\b[^>]*?\s)src\s*=\s*(?:"([^"]*)"|'([^']*)')`)
+	// cachedSrcRegex matches a src rewritten by the cache together with its original value.
+	cachedSrcRegex = regexp.MustCompile(`src="` + regexp.QuoteMeta(CachedImagePrefix) + `[^"]*" data-original-src="([^"]*)"`)
+	// cachedImageNameRegex is the shape of a cached file name: URL hash plus detected extension.
+	cachedImageNameRegex = regexp.MustCompile(`^[0-9a-f]{16}\.[a-z]{3,4}$`)
+)
+
+// ImageCacheService keeps local copies of entry images so they survive upstream deletion.
+type ImageCacheService interface {
+	// RewriteEntries points images of already stored entries at their cached copies,
+	// so unchanged upstream content compares equal to what is stored.
+	RewriteEntries(ctx context.Context, feed model.Feed, entries []model.Entry)
+	// CacheEntries downloads images of the feed's saved entries that qualify for caching
+	// (picture feeds and starred entries) and rewrites their stored content.
+	CacheEntries(ctx context.Context, feed model.Feed, hashes []string)
+	// CollectGarbage removes cached images of entries that were deleted or no longer qualify,
+	// and returns how many entries were cleaned up.
+	CollectGarbage(ctx context.Context) (int, error)
+	// GetImagePath returns the full path of a cached image, or "" for an invalid name.
+	GetImagePath(entryID int64, filename string) string
+}
+
+type imageCacheService struct {
+	dir      string
+	entries  repository.EntryRepository
+	feeds    repository.FeedRepository
+	settings SettingsService
+	proxy    ProxyService
+	limiter  *hostRateLimiter
+
+	mu         sync.Mutex
+	totalBytes int64 // -1 until measured from disk
+}
+
+func NewImageCacheService(dataDir string, entries repository.EntryRepository, feeds repository.FeedRepository, settings SettingsService, proxy ProxyService, rateLimitSvc DomainRateLimitService) ImageCacheService {
+	return &imageCacheService{
+		dir:      filepath.Join(dataDir, "images"),
+		entries:  entries,
+		feeds:    feeds,
+		settings: settings,
+		proxy:    proxy,
+		limiter: newHostRateLimiter(func(host string) time.Duration {
+			if rateLimitSvc != nil {
+				return rateLimitSvc.GetIntervalDuration(context.Background(), host)
+			}
+			return 0
+		}, nil),
+		totalBytes: -1,
+	}
+}
+
+// imageCacheBudget is the byte budget resolved from settings; nil means caching is off.
+type imageCacheBudget struct {
+	entryBytes int64
+	totalBytes int64
+}
+
+func (s *imageCacheService) budget(ctx context.Context) *imageCacheBudget {
+	if s.settings == nil || s.proxy == nil {
+		return nil
+	}
+	settings, err := s.settings.GetGeneralSettings(ctx)
+	if err != nil || settings == nil || !settings.ImageCache {
+		return nil
+	}
+	entryMB, totalMB := settings.ImageCacheEntryLimitMB, settings.ImageCacheTotalLimitMB
+	if entryMB <= 0 {
+		entryMB = DefaultImageCacheEntryLimitMB
+	}
+	if totalMB <= 0 {
+		totalMB = DefaultImageCacheTotalLimitMB
+	}
+	return &imageCacheBudget{entryBytes: int64(entryMB) << 20, totalBytes: int64(totalMB) << 20}
+}
+
+func (s *imageCacheService) RewriteEntries(ctx context.Context, feed model.Feed, entries []model.Entry) {
+	// Nothing was ever cached; skip the lookup
+	if _, err := os.Stat(s.dir); err != nil {
+		return
+	}
+
+	hashes := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		hashes = append(hashes, entry.Hash)
+	}
+	stored, err := s.entries.ListByHashes(ctx, feed.ID, hashes)
+	if err != nil {
+		logger.Warn("image cache lookup failed", "module", "service", "action", "fetch", "resource", "image", "result", "failed", "feed_id", feed.ID, "error", err)
+		return
+	}
+	ids := make(map[string]int64, len(stored))
+	for _, entry := range stored {
+		ids[entry.Hash] = entry.ID
+	}
+
+	for i := range entries {
+		id, ok := ids[entries[i].Hash]
+		if !ok || entries[i].Content == nil {
+			continue
+		}
+		if _, err := os.Stat(s.entryDir(id)); err != nil {
+			continue
+		}
+		content, _ := s.rewrite(ctx, id, *entries[i].Content, ptrString(entries[i].URL), nil)
+		entries[i].Content = &content
+	}
+}
+
+func (s *imageCacheService) CacheEntries(ctx context.Context, feed model.Feed, hashes []string) {
+	budget := s.budget(ctx)
+	if budget == nil || len(hashes) == 0 {
+		return
+	}
+
+	stored, err := s.entries.ListByHashes(ctx, feed.ID, hashes)
+	if err != nil {
+		logger.Warn("image cache lookup failed", "module", "service", "action", "fetch", "resource", "image", "result", "failed", "feed_id", feed.ID, "error", err)
+		return
+	}
+
+	for _, entry := range stored {
+		if ctx.Err() != nil {
+			return
+		}
+		if entry.Content == nil || (feed.Type != "picture" && !entry.Starred) {
+			continue
+		}
+		content, cached := s.rewrite(ctx, entry.ID, *entry.Content, ptrString(entry.URL), budget)
+		if content == *entry.Content {
+			continue
+		}
+		if err := s.entries.UpdateContent(ctx, entry.ID, content); err != nil {
+			logger.Warn("image cache update content failed", "module", "service", "action", "update", "resource", "entry", "result", "failed", "entry_id", entry.ID, "error", err)
+			continue
+		}
+		logger.Debug("entry images cached", "module", "service", "action", "save", "resource", "image", "result", "ok", "entry_id", entry.ID, "count", cached)
+	}
+}
+
+// rewrite replaces <img> sources with their cached copies. With a budget, images
+// that are not cached yet are downloaded; without one only existing copies are used.
+func (s *imageCacheService) rewrite(ctx context.Context, entryID int64, content, pageURL string, budget *imageCacheBudget) (string, int) {
+	dir := s.entryDir(entryID)
+	var used int64 = -1
+	cached := 0
+
+	result := imgSrcRegex.ReplaceAllStringFunc(content, func(match string) string {
+		sub := imgSrcRegex.FindStringSubmatch(match)
+		raw := sub[2]
+		if raw == "" {
+			raw = sub[3]
+		}
+		if strings.HasPrefix(raw, CachedImagePrefix) {
+			return match
+		}
+		imageURL := resolveImageURL(html.UnescapeString(raw), pageURL)
+		if imageURL == "" {
+			return match
+		}
+
+		name := imageCacheKey(imageURL)
+		filename := findCachedImage(dir, name)
+		if filename == "" && budget != nil && ctx.Err() == nil {
+			if used < 0 {
+				used = dirSize(dir)
+			}
+			var size int64
+			filename, size = s.store(ctx, dir, name, imageURL, pageURL, budget.entryBytes-used, budget.totalBytes)
+			used += size
+		}
+		if filename == "" {
+			return match
+		}
+
+		cached++
+		original := strings.ReplaceAll(raw, `"`, "&quot;")
+		return fmt.Sprintf(`%ssrc="%s%d/%s" data-original-src="%s"`, sub[1], CachedImagePrefix, entryID, filename, original)
+	})
+	return result, cached
+}
+
+// store downloads an image into dir within the remaining budgets and returns the file name and size.
+func (s *imageCacheService) store(ctx context.Context, dir, name, imageURL, pageURL string, entryRemaining, totalLimit int64) (string, int64) {
+	data, ext, err := s.download(ctx, imageURL, pageURL)
+	if err != nil {
+		logger.Debug("image cache download skipped", "module", "service", "action", "fetch", "resource", "image", "result", "failed", "host", network.ExtractHost(imageURL), "error", err)
+		return "", 0
+	}
+
+	size := int64(len(data))
+	if size > entryRemaining || !s.reserve(size, totalLimit) {
+		logger.Debug("image cache budget exceeded", "module", "service", "action", "save", "resource", "image", "result", "skipped", "host", network.ExtractHost(imageURL), "size", size)
+		return "", 0
+	}
+
+	filename := name + "." + ext
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		s.reserve(-size, totalLimit)
+		logger.Warn("image cache mkdir failed", "module", "service", "action", "save", "resource", "image", "result", "failed", "error", err)
+		return "", 0
+	}
+	if err := os.WriteFile(filepath.Join(dir, filename), data, 0644); err != nil {
+		s.reserve(-size, totalLimit)
+		logger.Warn("image cache write failed", "module", "service", "action", "save", "resource", "image", "result", "failed", "error", err)
+		return "", 0
+	}
+	return filename, size
+}
+
+// download fetches an image through the per-host rate limiter and validates its format.
+func (s *imageCacheService) download(ctx context.Context, imageURL, pageURL string) ([]byte, string, error) {
+	if host := network.ExtractHost(imageURL); host != "" {
+		if err := s.limiter.acquireSemaphore(ctx, host); err != nil {
+			return nil, "", err
+		}
+		defer s.limiter.releaseSemaphore(host)
+		if err := s.limiter.waitForInterval(ctx, host); err != nil {
+			return nil, "", err
+		}
+		s.limiter.recordRequest(host)
+	}
+
+	result, err := s.proxy.FetchImage(ctx, imageURL, pageURL)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(result.Data) > imageCacheMaxImageBytes {
+		return nil, "", errImageTooLarge
+	}
+	format, err := detectImageFormat(result.Data)
+	if err != nil {
+		return nil, "", err
+	}
+	if format.ext == "svg" {
+		return nil, "", errImageSVG
+	}
+	return result.Data, format.ext, nil
+}
+
+// reserve claims n bytes of the global budget; negative n releases them.
+func (s *imageCacheService) reserve(n, limit int64) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.totalBytes < 0 {
+		s.totalBytes = dirSize(s.dir)
+	}
+	if n > 0 && s.totalBytes+n > limit {
+		return false
+	}
+	s.totalBytes += n
+	return true
+}
+
+func (s *imageCacheService) CollectGarbage(ctx context.Context) (int, error) {
+	dirs, err := os.ReadDir(s.dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	feeds, err := s.feeds.List(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	feedTypes := make(map[int64]string, len(feeds))
+	for _, feed := range feeds {
+		feedTypes[feed.ID] = feed.Type
+	}
+
+	removed := 0
+	for _, d := range dirs {
+		id, err := strconv.ParseInt(d.Name(), 10, 64)
+		if !d.IsDir() || err != nil {
+			continue
+		}
+
+		entry, err := s.entries.GetByID(ctx, id)
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+		case err != nil:
+			return removed, err
+		default:
+			// Feeds in the trash are missing from the list; keep their images in case of restore
+			feedType, active := feedTypes[entry.FeedID]
+			if entry.Starred || feedType == "picture" || !active {
+				continue
+			}
+			if entry.Content != nil {
+				restored := cachedSrcRegex.ReplaceAllString(*entry.Content, `src="$1"`)
+				if err := s.entries.UpdateContent(ctx, entry.ID, restored); err != nil {
+					return removed, err
+				}
+			}
+		}
+
+		if err := os.RemoveAll(filepath.Join(s.dir, d.Name())); err != nil {
+			return removed, err
+		}
+		removed++
+	}
+
+	if removed > 0 {
+		s.mu.Lock()
+		s.totalBytes = -1
+		s.mu.Unlock()
+		logger.Info("image cache collected", "module", "service", "action", "clear", "resource", "image", "result", "ok", "count", removed)
+	}
+	return removed, nil
+}
+
+func (s *imageCacheService) GetImagePath(entryID int64, filename string) string {
+	// SVGs cached before they were refused are never served
+	if entryID <= 0 || !cachedImageNameRegex.MatchString(filename) || strings.HasSuffix(filename, ".svg") {
+		return ""
+	}
+	return filepath.Join(s.entryDir(entryID), filename)
+}
+
+func (s *imageCacheService) entryDir(entryID int64) string {
+	return filepath.Join(s.dir, strconv.FormatInt(entryID, 10))
+}
+
+// imageCacheKey names a cached image after its URL.
+func imageCacheKey(imageURL string) string {
+	sum := sha256.Sum256([]byte(imageURL))
+	return hex.EncodeToString(sum[:])[:16]
+}
+
+func findCachedImage(dir, name string) string {
+	matches, _ := filepath.Glob(filepath.Join(dir, name+".*"))
+	if len(matches) == 0 {
+		return ""
+	}
+	return filepath.Base(matches[0])
+}
+
+// resolveImageURL returns an absolute http(s) URL for src, or "" if there is none.
+func resolveImageURL(src, pageURL string) string {
+	ref, err := url.Parse(strings.TrimSpace(src))
+	if err != nil {
+		return ""
+	}
+	if !ref.IsAbs() {
+		base, err := url.Parse(pageURL)
+		if err != nil || pageURL == "" {
+			return ""
+		}
+		ref = base.ResolveReference(ref)
+	}
+	if ref.Scheme != "http" && ref.Scheme != "https" {
+		return ""
+	}
+	return ref.String()
+}
+
+func dirSize(dir string) int64 {
+	var size int64
+	_ = filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		if info, err := d.Info(); err == nil {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size
+}
+
+func ptrString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
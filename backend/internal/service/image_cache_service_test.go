@@ -0,0 +1,282 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"gist/backend/internal/model"
+	repomock "gist/backend/internal/repository/mock"
+	"gist/backend/internal/service"
+	"gist/backend/internal/service/mock"
+)
+
+func imageCacheEnabled(entryMB, totalMB int) *service.GeneralSettings {
+	return &service.GeneralSettings{ImageCache: true, ImageCacheEntryLimitMB: entryMB, ImageCacheTotalLimitMB: totalMB}
+}
+
+// paddedPNG is a valid PNG header followed by padding, to reach a given size cheaply.
+func paddedPNG(t *testing.T, size int) []byte {
+	data := pngBytes(t, 4, 4)
+	if size > len(data) {
+		data = append(data, make([]byte, size-len(data))...)
+	}
+	return data
+}
+
+func TestImageCacheService_CacheEntries_PictureFeed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dataDir := t.TempDir()
+	mockEntries := repomock.NewMockEntryRepository(ctrl)
+	mockFeeds := repomock.NewMockFeedRepository(ctrl)
+	mockSettings := mock.NewMockSettingsService(ctrl)
+	mockProxy := mock.NewMockProxyService(ctrl)
+	mockSettings.EXPECT().GetGeneralSettings(gomock.Any()).Return(imageCacheEnabled(0, 0), nil).AnyTimes()
+	svc := service.NewImageCacheService(dataDir, mockEntries, mockFeeds, mockSettings, mockProxy, nil)
+
+	feed := model.Feed{ID: 1, Type: "picture"}
+	upstream := `<p>hi</p><img alt="a" src="https://cdn.example.com/a.png?x=1&amp;y=2">`
+	entry := model.Entry{ID: 42, FeedID: 1, Hash: "h1", URL: stringPtr("https://example.com/post"), Content: stringPtr(upstream)}
+
+	mockEntries.EXPECT().ListByHashes(gomock.Any(), int64(1), []string{"h1"}).Return([]model.Entry{entry}, nil)
+	mockProxy.EXPECT().FetchImage(gomock.Any(), "https://cdn.example.com/a.png?x=1&y=2", "https://example.com/post").
+		Return(&service.ProxyResult{Data: pngBytes(t, 4, 4), ContentType: "image/png"}, nil)
+
+	var stored string
+	mockEntries.EXPECT().UpdateContent(gomock.Any(), int64(42), gomock.Any()).DoAndReturn(func(_ context.Context, _ int64, content string) error {
+		stored = content
+		return nil
+	})
+
+	svc.CacheEntries(context.Background(), feed, []string{"h1"})
+
+	require.Contains(t, stored, `<img alt="a" src="/cached-images/42/`)
+	require.Contains(t, stored, `data-original-src="https://cdn.example.com/a.png?x=1&amp;y=2"`)
+	files, err := os.ReadDir(filepath.Join(dataDir, "images", "42"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	require.True(t, strings.HasSuffix(files[0].Name(), ".png"))
+	require.Contains(t, stored, files[0].Name())
+	require.Equal(t, filepath.Join(dataDir, "images", "42", files[0].Name()), svc.GetImagePath(42, files[0].Name()))
+
+	// The next refresh sees upstream content again; it must match what is stored
+	entry.Content = &stored
+	mockEntries.EXPECT().ListByHashes(gomock.Any(), int64(1), []string{"h1"}).Return([]model.Entry{entry}, nil)
+	incoming := []model.Entry{{FeedID: 1, Hash: "h1", URL: stringPtr("https://example.com/post"), Content: stringPtr(upstream)}}
+	svc.RewriteEntries(context.Background(), feed, incoming)
+	require.Equal(t, stored, *incoming[0].Content)
+}
+
+func TestImageCacheService_CacheEntries_Disabled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dataDir := t.TempDir()
+	mockEntries := repomock.NewMockEntryRepository(ctrl)
+	mockFeeds := repomock.NewMockFeedRepository(ctrl)
+	mockSettings := mock.NewMockSettingsService(ctrl)
+	mockProxy := mock.NewMockProxyService(ctrl)
+	mockSettings.EXPECT().GetGeneralSettings(gomock.Any()).Return(&service.GeneralSettings{}, nil).AnyTimes()
+	svc := service.NewImageCacheService(dataDir, mockEntries, mockFeeds, mockSettings, mockProxy, nil)
+
+	svc.CacheEntries(context.Background(), model.Feed{ID: 1, Type: "picture"}, []string{"h1"})
+}
+
+func TestImageCacheService_CacheEntries_ArticleFeedOnlyStarred(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dataDir := t.TempDir()
+	mockEntries := repomock.NewMockEntryRepository(ctrl)
+	mockFeeds := repomock.NewMockFeedRepository(ctrl)
+	mockSettings := mock.NewMockSettingsService(ctrl)
+	mockProxy := mock.NewMockProxyService(ctrl)
+	mockSettings.EXPECT().GetGeneralSettings(gomock.Any()).Return(imageCacheEnabled(0, 0), nil).AnyTimes()
+	svc := service.NewImageCacheService(dataDir, mockEntries, mockFeeds, mockSettings, mockProxy, nil)
+
+	content := `<img src="https://cdn.example.com/a.png">`
+	mockEntries.EXPECT().ListByHashes(gomock.Any(), int64(1), []string{"h1", "h2"}).Return([]model.Entry{
+		{ID: 1, FeedID: 1, Hash: "h1", Content: stringPtr(content)},
+		{ID: 2, FeedID: 1, Hash: "h2", Content: stringPtr(content), Starred: true},
+	}, nil)
+	mockProxy.EXPECT().FetchImage(gomock.Any(), "https://cdn.example.com/a.png", "").
+		Return(&service.ProxyResult{Data: pngBytes(t, 4, 4)}, nil).Times(1)
+	mockEntries.EXPECT().UpdateContent(gomock.Any(), int64(2), gomock.Any()).Return(nil)
+
+	svc.CacheEntries(context.Background(), model.Feed{ID: 1, Type: "article"}, []string{"h1", "h2"})
+
+	_, err := os.Stat(filepath.Join(dataDir, "images", "1"))
+	require.True(t, os.IsNotExist(err))
+}
+
+func TestImageCacheService_CacheEntries_SkipsOversizedAndInvalid(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dataDir := t.TempDir()
+	mockEntries := repomock.NewMockEntryRepository(ctrl)
+	mockFeeds := repomock.NewMockFeedRepository(ctrl)
+	mockSettings := mock.NewMockSettingsService(ctrl)
+	mockProxy := mock.NewMockProxyService(ctrl)
+	mockSettings.EXPECT().GetGeneralSettings(gomock.Any()).Return(imageCacheEnabled(100, 100), nil).AnyTimes()
+	svc := service.NewImageCacheService(dataDir, mockEntries, mockFeeds, mockSettings, mockProxy, nil)
+
+	content := `<img src="https://cdn.example.com/big.png"><img src="https://cdn.example.com/page.html"><img src="https://cdn.example.com/x.svg"><img src="data:image/png;base64,AAAA">`
+	mockEntries.EXPECT().ListByHashes(gomock.Any(), int64(1), []string{"h1"}).Return([]model.Entry{
+		{ID: 1, FeedID: 1, Hash: "h1", Content: stringPtr(content)},
+	}, nil)
+	mockProxy.EXPECT().FetchImage(gomock.Any(), "https://cdn.example.com/big.png", "").
+		Return(&service.ProxyResult{Data: paddedPNG(t, 6<<20)}, nil)
+	mockProxy.EXPECT().FetchImage(gomock.Any(), "https://cdn.example.com/page.html", "").
+		Return(&service.ProxyResult{Data: []byte("<html></html>")}, nil)
+	mockProxy.EXPECT().FetchImage(gomock.Any(), "https://cdn.example.com/x.svg", "").
+		Return(&service.ProxyResult{Data: []byte(`<svg xmlns="http://www.w3.org/2000/svg"><script>alert(1)</script></svg>`)}, nil)
+
+	svc.CacheEntries(context.Background(), model.Feed{ID: 1, Type: "picture"}, []string{"h1"})
+
+	_, err := os.Stat(filepath.Join(dataDir, "images", "1"))
+	require.True(t, os.IsNotExist(err))
+}
+
+func TestImageCacheService_CacheEntries_EntryBudget(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dataDir := t.TempDir()
+	mockEntries := repomock.NewMockEntryRepository(ctrl)
+	mockFeeds := repomock.NewMockFeedRepository(ctrl)
+	mockSettings := mock.NewMockSettingsService(ctrl)
+	mockProxy := mock.NewMockProxyService(ctrl)
+	mockSettings.EXPECT().GetGeneralSettings(gomock.Any()).Return(imageCacheEnabled(1, 100), nil).AnyTimes()
+	svc := service.NewImageCacheService(dataDir, mockEntries, mockFeeds, mockSettings, mockProxy, nil)
+
+	content := `<img src="https://cdn.example.com/1.png"><img src="https://cdn.example.com/2.png">`
+	mockEntries.EXPECT().ListByHashes(gomock.Any(), int64(1), []string{"h1"}).Return([]model.Entry{
+		{ID: 1, FeedID: 1, Hash: "h1", Content: stringPtr(content)},
+	}, nil)
+	mockProxy.EXPECT().FetchImage(gomock.Any(), gomock.Any(), "").
+		Return(&service.ProxyResult{Data: paddedPNG(t, 600<<10)}, nil).Times(2)
+
+	var stored string
+	mockEntries.EXPECT().UpdateContent(gomock.Any(), int64(1), gomock.Any()).DoAndReturn(func(_ context.Context, _ int64, content string) error {
+		stored = content
+		return nil
+	})
+
+	svc.CacheEntries(context.Background(), model.Feed{ID: 1, Type: "picture"}, []string{"h1"})
+
+	require.Equal(t, 1, strings.Count(stored, "/cached-images/1/"))
+	require.Contains(t, stored, `<img src="https://cdn.example.com/2.png">`)
+}
+
+func TestImageCacheService_CollectGarbage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dataDir := t.TempDir()
+	mockEntries := repomock.NewMockEntryRepository(ctrl)
+	mockFeeds := repomock.NewMockFeedRepository(ctrl)
+	mockSettings := mock.NewMockSettingsService(ctrl)
+	mockProxy := mock.NewMockProxyService(ctrl)
+	mockSettings.EXPECT().GetGeneralSettings(gomock.Any()).Return(imageCacheEnabled(0, 0), nil).AnyTimes()
+	svc := service.NewImageCacheService(dataDir, mockEntries, mockFeeds, mockSettings, mockProxy, nil)
+
+	for _, id := range []string{"1", "2", "3", "4"} {
+		dir := filepath.Join(dataDir, "images", id)
+		require.NoError(t, os.MkdirAll(dir, 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "0123456789abcdef.png"), []byte("x"), 0644))
+	}
+	cached := `<img src="/cached-images/2/0123456789abcdef.png" data-original-src="https://cdn.example.com/a.png?x=1&amp;y=2">`
+
+	mockFeeds.EXPECT().List(gomock.Any(), (*int64)(nil)).Return([]model.Feed{
+		{ID: 10, Type: "article"},
+		{ID: 20, Type: "picture"},
+	}, nil)
+	mockEntries.EXPECT().GetByID(gomock.Any(), int64(1)).Return(model.Entry{}, sql.ErrNoRows)
+	mockEntries.EXPECT().GetByID(gomock.Any(), int64(2)).Return(model.Entry{ID: 2, FeedID: 10, Content: stringPtr(cached)}, nil)
+	mockEntries.EXPECT().GetByID(gomock.Any(), int64(3)).Return(model.Entry{ID: 3, FeedID: 10, Starred: true}, nil)
+	mockEntries.EXPECT().GetByID(gomock.Any(), int64(4)).Return(model.Entry{ID: 4, FeedID: 20}, nil)
+	mockEntries.EXPECT().UpdateContent(gomock.Any(), int64(2), `<img src="https://cdn.example.com/a.png?x=1&amp;y=2">`).Return(nil)
+
+	removed, err := svc.CollectGarbage(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 2, removed)
+
+	for id, exists := range map[string]bool{"1": false, "2": false, "3": true, "4": true} {
+		_, err := os.Stat(filepath.Join(dataDir, "images", id))
+		require.Equal(t, exists, err == nil, "entry %s", id)
+	}
+}
+
+func TestImageCacheService_CollectGarbage_KeepsTrashedFeeds(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dataDir := t.TempDir()
+	mockEntries := repomock.NewMockEntryRepository(ctrl)
+	mockFeeds := repomock.NewMockFeedRepository(ctrl)
+	mockSettings := mock.NewMockSettingsService(ctrl)
+	mockProxy := mock.NewMockProxyService(ctrl)
+	mockSettings.EXPECT().GetGeneralSettings(gomock.Any()).Return(imageCacheEnabled(0, 0), nil).AnyTimes()
+	svc := service.NewImageCacheService(dataDir, mockEntries, mockFeeds, mockSettings, mockProxy, nil)
+
+	require.NoError(t, os.MkdirAll(filepath.Join(dataDir, "images", "5"), 0755))
+
+	mockFeeds.EXPECT().List(gomock.Any(), (*int64)(nil)).Return(nil, nil)
+	mockEntries.EXPECT().GetByID(gomock.Any(), int64(5)).Return(model.Entry{ID: 5, FeedID: 99}, nil)
+
+	removed, err := svc.CollectGarbage(context.Background())
+	require.NoError(t, err)
+	require.Zero(t, removed)
+}
+
+func TestImageCacheService_CollectGarbage_LookupError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dataDir := t.TempDir()
+	mockEntries := repomock.NewMockEntryRepository(ctrl)
+	mockFeeds := repomock.NewMockFeedRepository(ctrl)
+	mockSettings := mock.NewMockSettingsService(ctrl)
+	mockProxy := mock.NewMockProxyService(ctrl)
+	mockSettings.EXPECT().GetGeneralSettings(gomock.Any()).Return(imageCacheEnabled(0, 0), nil).AnyTimes()
+	svc := service.NewImageCacheService(dataDir, mockEntries, mockFeeds, mockSettings, mockProxy, nil)
+
+	require.NoError(t, os.MkdirAll(filepath.Join(dataDir, "images", "5"), 0755))
+
+	mockFeeds.EXPECT().List(gomock.Any(), (*int64)(nil)).Return(nil, nil)
+	mockEntries.EXPECT().GetByID(gomock.Any(), int64(5)).Return(model.Entry{}, errors.New("db down"))
+
+	_, err := svc.CollectGarbage(context.Background())
+	require.Error(t, err)
+	_, statErr := os.Stat(filepath.Join(dataDir, "images", "5"))
+	require.NoError(t, statErr)
+}
+
+func TestImageCacheService_GetImagePath_RejectsInvalidNames(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dataDir := t.TempDir()
+	mockEntries := repomock.NewMockEntryRepository(ctrl)
+	mockFeeds := repomock.NewMockFeedRepository(ctrl)
+	mockSettings := mock.NewMockSettingsService(ctrl)
+	mockProxy := mock.NewMockProxyService(ctrl)
+	mockSettings.EXPECT().GetGeneralSettings(gomock.Any()).Return(nil, nil).AnyTimes()
+	svc := service.NewImageCacheService(dataDir, mockEntries, mockFeeds, mockSettings, mockProxy, nil)
+
+	for _, name := range []string{"../secret.png", "0123456789abcdef", "0123456789ABCDEF.png", "x.png", "0123456789abcdef.svg"} {
+		require.Empty(t, svc.GetImagePath(1, name), name)
+	}
+	require.Empty(t, svc.GetImagePath(0, "0123456789abcdef.png"))
+	require.Equal(t, filepath.Join(dataDir, "images", "7", "0123456789abcdef.png"), svc.GetImagePath(7, "0123456789abcdef.png"))
+}
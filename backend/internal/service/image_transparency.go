@@ -0,0 +1,254 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif" // register GIF for image.DecodeConfig
+	_ "image/png" // register PNG for image.DecodeConfig
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html"
+
+	"gist/backend/internal/config"
+	"gist/backend/pkg/logger"
+	"gist/backend/pkg/network"
+)
+
+const (
+	// maxTransparencyChecks caps the images of one article probed for transparency.
+	maxTransparencyChecks = 20
+	// transparencyProbeBytes is how much of an image is read to find its color model.
+	transparencyProbeBytes = 64 << 10
+	// transparencyMaxImageBytes skips larger images, which are photos far more
+	// often than diagrams.
+	transparencyMaxImageBytes = 2 << 20
+	transparencyTimeout       = 10 * time.Second
+)
+
+var errTruncatedImage = errors.New("image header truncated")
+
+// markTransparentImages adds data-transparent="true" to the <img> tags of
+// content whose PNG or GIF has an alpha channel, so the reader can keep dark
+// diagrams legible in dark mode. Images that cannot be checked are left alone.
+func (s *readabilityService) markTransparentImages(ctx context.Context, content, pageURL string) string {
+	if s.clientFactory == nil {
+		return content
+	}
+	doc, err := html.Parse(strings.NewReader(content))
+	if err != nil {
+		return content
+	}
+
+	var images []*html.Node
+	walkTree(doc, func(n *html.Node) {
+		if n.Data == "img" {
+			images = append(images, n)
+		}
+	})
+
+	results := make(map[string]bool)
+	marked := 0
+	for _, n := range images {
+		imageURL := transparencyCandidate(n, pageURL)
+		if imageURL == "" {
+			continue
+		}
+		transparent, checked := results[imageURL]
+		if !checked {
+			if len(results) >= maxTransparencyChecks || ctx.Err() != nil {
+				break
+			}
+			transparent, err = s.probeTransparency(ctx, imageURL, pageURL)
+			if err != nil {
+				logger.Debug("image transparency check skipped", "module", "service", "action", "fetch", "resource", "image", "result", "failed", "host", network.ExtractHost(imageURL), "error", err)
+			}
+			results[imageURL] = transparent
+		}
+		if transparent {
+			n.Attr = append(n.Attr, html.Attribute{Key: "data-transparent", Val: "true"})
+			marked++
+		}
+	}
+	if marked == 0 {
+		return content
+	}
+
+	var buf bytes.Buffer
+	if err := html.Render(&buf, doc); err != nil {
+		return content
+	}
+	logger.Debug("transparent images marked", "module", "service", "action", "update", "resource", "image", "result", "ok", "count", marked, "checked", len(results))
+	return buf.String()
+}
+
+// transparencyCandidate returns the absolute URL of an image worth probing, or
+// "" for images already marked, inline ones and formats without transparency.
+func transparencyCandidate(n *html.Node, pageURL string) string {
+	var src string
+	for _, attr := range n.Attr {
+		switch attr.Key {
+		case "src":
+			src = attr.Val
+		case "data-transparent":
+			return ""
+		}
+	}
+	if src == "" || strings.HasPrefix(src, CachedImagePrefix) {
+		return ""
+	}
+	imageURL := resolveImageURL(src, pageURL)
+	if imageURL == "" {
+		return ""
+	}
+	if parsed, err := url.Parse(imageURL); err == nil {
+		switch strings.ToLower(path.Ext(parsed.Path)) {
+		case ".jpg", ".jpeg", ".svg", ".webp", ".avif":
+			return ""
+		}
+	}
+	return imageURL
+}
+
+// probeTransparency reads the start of an image through the per-host rate
+// limiter and reports whether it is a PNG or GIF with an alpha channel.
+func (s *readabilityService) probeTransparency(ctx context.Context, imageURL, pageURL string) (bool, error) {
+	if host := network.ExtractHost(imageURL); host != "" {
+		if err := s.hosts.acquireSemaphore(ctx, host); err != nil {
+			return false, err
+		}
+		defer s.hosts.releaseSemaphore(host)
+		if err := s.hosts.waitForInterval(ctx, host); err != nil {
+			return false, err
+		}
+		s.hosts.recordRequest(host)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, transparencyTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, imageURL, nil)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("User-Agent", config.ChromeUserAgent)
+	req.Header.Set("Referer", pageURL)
+	req.Header.Set("Range", fmt.Sprintf("bytes=0-%d", transparencyProbeBytes-1))
+
+	resp, err := s.clientFactory.NewHTTPClient(ctx, transparencyTimeout).Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusPartialContent {
+		return false, fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	if imageLength(resp) > transparencyMaxImageBytes {
+		return false, errImageTooLarge
+	}
+
+	head, err := io.ReadAll(io.LimitReader(resp.Body, transparencyProbeBytes))
+	if err != nil {
+		return false, err
+	}
+	return hasAlphaChannel(head)
+}
+
+// imageLength is the full size of the image behind resp, from Content-Range
+// for partial responses; -1 when unknown.
+func imageLength(resp *http.Response) int64 {
+	if resp.StatusCode == http.StatusPartialContent {
+		contentRange := resp.Header.Get("Content-Range")
+		if i := strings.LastIndexByte(contentRange, '/'); i >= 0 {
+			if n, err := strconv.ParseInt(contentRange[i+1:], 10, 64); err == nil {
+				return n
+			}
+		}
+		return -1
+	}
+	return resp.ContentLength
+}
+
+// hasAlphaChannel reports whether the PNG or GIF starting with head can be
+// transparent. Other formats are not.
+func hasAlphaChannel(head []byte) (bool, error) {
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(head))
+	if err != nil {
+		return false, err
+	}
+	if cfg.Width == 0 || cfg.Height == 0 {
+		return false, nil
+	}
+	switch format {
+	case "png":
+		if cfg.ColorModel == color.NRGBAModel || cfg.ColorModel == color.NRGBA64Model {
+			return true, nil
+		}
+		// Palette and opaque color types carry transparency in a tRNS chunk,
+		// which DecodeConfig does not read
+		return pngHasTRNS(head)
+	case "gif":
+		return gifHasTransparency(head)
+	}
+	return false, nil
+}
+
+// pngHasTRNS walks the chunks before the image data looking for tRNS.
+func pngHasTRNS(head []byte) (bool, error) {
+	for i := 8; ; {
+		if i+8 > len(head) {
+			return false, errTruncatedImage
+		}
+		length := int(binary.BigEndian.Uint32(head[i : i+4]))
+		switch string(head[i+4 : i+8]) {
+		case "tRNS":
+			return true, nil
+		case "IDAT", "IEND":
+			return false, nil
+		}
+		i += 12 + length
+	}
+}
+
+// gifHasTransparency reports whether the first frame of a GIF has a
+// transparent color, set by the graphic control extension before it.
+func gifHasTransparency(head []byte) (bool, error) {
+	if len(head) < 13 {
+		return false, errTruncatedImage
+	}
+	i := 13
+	if flags := head[10]; flags&0x80 != 0 {
+		i += 3 << ((flags & 0x07) + 1)
+	}
+	for i < len(head) {
+		switch head[i] {
+		case 0x21: // extension
+			if i+3 >= len(head) {
+				return false, errTruncatedImage
+			}
+			if head[i+1] == 0xF9 && head[i+2] == 4 {
+				return head[i+3]&0x01 != 0, nil
+			}
+			// Skip the data sub-blocks up to the terminating empty one
+			i += 2
+			for i < len(head) && head[i] != 0 {
+				i += int(head[i]) + 1
+			}
+			i++
+		case 0x2C, 0x3B: // image descriptor, trailer
+			return false, nil
+		default:
+			return false, fmt.Errorf("unexpected GIF block 0x%02x", head[i])
+		}
+	}
+	return false, errTruncatedImage
+}
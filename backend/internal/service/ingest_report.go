@@ -0,0 +1,209 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/mmcdole/gofeed"
+
+	"gist/backend/internal/model"
+	"gist/backend/pkg/logger"
+	"gist/backend/pkg/sanitizer"
+)
+
+// Reasons an item was skipped or altered on its way into the database.
+const (
+	// IngestMissingLink items are skipped: entries need a URL.
+	IngestMissingLink = "missing_link"
+	// IngestDuplicate items are skipped: an earlier item of the same document
+	// has their hash.
+	IngestDuplicate = "duplicate"
+	// IngestBackfillLimit items are skipped: they fall outside the initial
+	// backfill limit of a new feed.
+	IngestBackfillLimit = "backfill_limit"
+	// IngestTruncated items are saved with their title or author cut to the
+	// configured length.
+	IngestTruncated = "truncated"
+	// IngestUnparsedDate items are saved with the fetch time, as their date
+	// could not be parsed.
+	IngestUnparsedDate = "unparsed_date"
+	// IngestImplausibleDate items are saved with another date than theirs, as
+	// it was before 2000 or more than a day ahead of the fetch.
+	IngestImplausibleDate = "implausible_date"
+)
+
+// maxIngestIssues caps the items listed in a report; Counts keeps counting.
+const maxIngestIssues = 50
+
+// IngestReport tells what happened to the items of one refresh that did not
+// make it into the database as delivered.
+type IngestReport struct {
+	CheckedAt time.Time `json:"checkedAt"`
+	// Items is how many items the feed document held, Saved how many of them
+	// were handed to storage.
+	Items int `json:"items"`
+	Saved int `json:"saved"`
+	// Counts has the number of items per Ingest* reason.
+	Counts map[string]int `json:"counts"`
+	// Issues lists the first maxIngestIssues affected items.
+	Issues []IngestIssue `json:"issues"`
+}
+
+// IngestIssue is one item skipped or altered for Reason.
+type IngestIssue struct {
+	Reason string `json:"reason"`
+	Title  string `json:"title,omitempty"`
+	Link   string `json:"link,omitempty"`
+	GUID   string `json:"guid,omitempty"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// ingestReportBuilder collects the issues of one refresh. The report is only
+// allocated with the first issue, so clean refreshes cost nothing.
+type ingestReportBuilder struct {
+	report *IngestReport
+}
+
+func (b *ingestReportBuilder) add(reason string, item *gofeed.Item, detail string) {
+	if b.report == nil {
+		b.report = &IngestReport{Counts: make(map[string]int)}
+	}
+	b.report.Counts[reason]++
+	if len(b.report.Issues) < maxIngestIssues {
+		b.report.Issues = append(b.report.Issues, IngestIssue{
+			Reason: reason,
+			Title:  sanitizer.Truncate(strings.TrimSpace(item.Title), 200),
+			Link:   strings.TrimSpace(item.Link),
+			GUID:   strings.TrimSpace(item.GUID),
+			Detail: detail,
+		})
+	}
+}
+
+// build returns the report, nil when nothing was skipped or altered.
+func (b *ingestReportBuilder) build(items, saved int) *IngestReport {
+	if b.report == nil {
+		return nil
+	}
+	b.report.CheckedAt = time.Now().UTC()
+	b.report.Items = items
+	b.report.Saved = saved
+	return b.report
+}
+
+// prepareEntries converts the items of feed to the entries a refresh saves,
+// noting in report every item skipped or altered on the way. Refreshes and
+// probes share it so a probe's dry run reports what a refresh would do.
+func prepareEntries(feed model.Feed, items []*gofeed.Item, backfill InitialBackfill, general *GeneralSettings, report *ingestReportBuilder) []model.Entry {
+	dynamicTime := hasDynamicTime(items)
+	loc := feedLocation(feed, general)
+	kept := backfill.filter(items)
+	if len(kept) < len(items) {
+		keep := make(map[*gofeed.Item]bool, len(kept))
+		for _, item := range kept {
+			keep[item] = true
+		}
+		for _, item := range items {
+			if !keep[item] {
+				report.add(IngestBackfillLimit, item, "")
+			}
+		}
+	}
+
+	entries := make([]model.Entry, 0, len(kept))
+	seen := make(map[string]bool, len(kept))
+	for _, item := range kept {
+		entry, published := itemToDatedEntry(feed, item, dynamicTime, loc)
+		if entry.URL == nil || *entry.URL == "" {
+			report.add(IngestMissingLink, item, "")
+			continue
+		}
+		if seen[entry.Hash] {
+			report.add(IngestDuplicate, item, "")
+			continue
+		}
+		seen[entry.Hash] = true
+
+		if utf8.RuneCountInString(sanitizer.CleanText(strings.TrimSpace(item.Title))) > MaxEntryTitleLength {
+			report.add(IngestTruncated, item, "title")
+		}
+		if item.Author != nil && utf8.RuneCountInString(sanitizer.CleanText(sanitizer.SanitizeAuthor(item.Author.Name))) > MaxEntryAuthorLength {
+			report.add(IngestTruncated, item, "author")
+		}
+		if dateText := unparsedDate(item); dateText != "" {
+			report.add(IngestUnparsedDate, item, dateText)
+		}
+		if published.Rejected != nil {
+			report.add(IngestImplausibleDate, item, published.Rejected.Format(time.RFC3339))
+		}
+
+		attachRawItem(&entry, item, general)
+		entry.Read = backfill.MarkRead
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// unparsedDate returns the date text of an item none of whose dates parsed,
+// empty when the item has a parsed date or no date at all.
+func unparsedDate(item *gofeed.Item) string {
+	if item.PublishedParsed != nil || item.UpdatedParsed != nil {
+		return ""
+	}
+	if published := strings.TrimSpace(item.Published); published != "" {
+		return published
+	}
+	return strings.TrimSpace(item.Updated)
+}
+
+// saveIngestReport stores the report of feed's last refresh, or clears the
+// previous one after a clean refresh. Like the fetch log, it never fails a
+// refresh.
+func (s *refreshService) saveIngestReport(ctx context.Context, feed model.Feed, report *IngestReport) {
+	if s.fetchLog == nil {
+		return
+	}
+	var data *string
+	if report != nil {
+		encoded, err := json.Marshal(report)
+		if err != nil {
+			return
+		}
+		value := string(encoded)
+		data = &value
+		logger.Debug("feed items skipped or altered", "module", "service", "action", "save", "resource", "entry", "result", "ok", "feed_id", feed.ID, "items", report.Items, "saved", report.Saved, "issues", len(report.Issues))
+	}
+	if err := s.fetchLog.SaveIngestReport(context.WithoutCancel(ctx), feed.ID, data); err != nil {
+		logger.Warn("ingest report save failed", "module", "service", "action", "save", "resource", "feed", "result", "failed", "feed_id", feed.ID, "error", err)
+	}
+}
+
+func (s *refreshService) GetIngestReport(ctx context.Context, feedID int64) (*IngestReport, error) {
+	if _, err := s.feeds.GetByID(ctx, feedID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("get feed: %w", err)
+	}
+	if s.fetchLog == nil {
+		return nil, nil
+	}
+	data, err := s.fetchLog.GetIngestReport(ctx, feedID)
+	if err != nil {
+		return nil, fmt.Errorf("get ingest report: %w", err)
+	}
+	if data == nil {
+		return nil, nil
+	}
+	var report IngestReport
+	if err := json.Unmarshal([]byte(*data), &report); err != nil {
+		return nil, fmt.Errorf("decode ingest report: %w", err)
+	}
+	return &report, nil
+}
@@ -0,0 +1,165 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"gist/backend/internal/model"
+	"gist/backend/internal/repository/mock"
+	"gist/backend/internal/service"
+	"gist/backend/pkg/network"
+)
+
+const ingestIssuesRSS = `<?xml version="1.0"?><rss version="2.0"><channel><title>Feed</title><link>https://example.com</link>
+<item><title>One</title><guid>1</guid><link>https://example.com/1</link><pubDate>Mon, 02 Jan 2006 15:04:05 GMT</pubDate></item>
+<item><title>No link</title><guid>2</guid></item>
+<item><title>One again</title><guid>1</guid><link>https://example.com/1</link></item>
+<item><title>Someday</title><guid>3</guid><link>https://example.com/3</link><pubDate>sometime last week</pubDate></item>
+</channel></rss>`
+
+// newIngestRefreshService returns a refresh service whose feed 10 serves body,
+// and the entries each refresh saves.
+func newIngestRefreshService(t *testing.T, body string, fetchLog *mock.MockFeedFetchLogRepository) (service.RefreshService, chan []model.Entry) {
+	ctrl := gomock.NewController(t)
+	mockFeeds := mock.NewMockFeedRepository(ctrl)
+	siteURL := "https://example.com"
+	mockFeeds.EXPECT().GetByID(gomock.Any(), int64(10)).Return(model.Feed{ID: 10, URL: "https://example.com/rss", SiteURL: &siteURL, Title: "Feed"}, nil).AnyTimes()
+	mockFeeds.EXPECT().UpdateTitles(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockFeeds.EXPECT().UpdateLastFetchedAt(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockFeeds.EXPECT().UpdateLastEntrySeenAt(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockFeeds.EXPECT().UpdateErrorMessage(gomock.Any(), int64(10), nil).Return(nil).AnyTimes()
+	mockFeeds.EXPECT().ListMutedAuthors(gomock.Any(), int64(10)).Return(nil, nil).AnyTimes()
+
+	saved := make(chan []model.Entry, 1)
+	mockEntries := mock.NewMockEntryRepository(ctrl)
+	mockEntries.EXPECT().SaveBatch(gomock.Any(), int64(10), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ int64, entries []model.Entry, _ int) (int, int, error) {
+			saved <- entries
+			return len(entries), 0, nil
+		},
+	)
+	fetchLog.EXPECT().Append(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+
+	client := &http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(body)), Header: make(http.Header), Request: req}, nil
+		}),
+	}
+	svc := service.NewRefreshService(mockFeeds, mockEntries, nil, nil, nil, nil, network.NewClientFactoryForTest(client), nil, nil, fetchLog, nil, nil)
+	return svc, saved
+}
+
+func TestRefreshService_RefreshFeed_SavesIngestReport(t *testing.T) {
+	fetchLog := mock.NewMockFeedFetchLogRepository(gomock.NewController(t))
+	var stored *string
+	fetchLog.EXPECT().SaveIngestReport(gomock.Any(), int64(10), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ int64, report *string) error {
+			stored = report
+			return nil
+		},
+	)
+	svc, saved := newIngestRefreshService(t, ingestIssuesRSS, fetchLog)
+
+	require.NoError(t, svc.RefreshFeed(context.Background(), 10))
+
+	entries := <-saved
+	require.Len(t, entries, 2)
+	require.Equal(t, "One", *entries[0].Title)
+	require.Equal(t, "Someday", *entries[1].Title)
+
+	require.NotNil(t, stored)
+	var report service.IngestReport
+	require.NoError(t, json.Unmarshal([]byte(*stored), &report))
+	require.Equal(t, 4, report.Items)
+	require.Equal(t, 2, report.Saved)
+	require.Equal(t, map[string]int{
+		service.IngestMissingLink:  1,
+		service.IngestDuplicate:    1,
+		service.IngestUnparsedDate: 1,
+	}, report.Counts)
+	require.Len(t, report.Issues, 3)
+	require.Equal(t, service.IngestIssue{Reason: service.IngestMissingLink, Title: "No link", GUID: "2"}, report.Issues[0])
+	require.Equal(t, service.IngestDuplicate, report.Issues[1].Reason)
+	require.Equal(t, "One again", report.Issues[1].Title)
+	require.Equal(t, service.IngestIssue{Reason: service.IngestUnparsedDate, Title: "Someday", Link: "https://example.com/3", GUID: "3", Detail: "sometime last week"}, report.Issues[2])
+	require.False(t, report.CheckedAt.IsZero())
+}
+
+func TestRefreshService_RefreshFeed_ReportsImplausibleDates(t *testing.T) {
+	fetchLog := mock.NewMockFeedFetchLogRepository(gomock.NewController(t))
+	var stored *string
+	fetchLog.EXPECT().SaveIngestReport(gomock.Any(), int64(10), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ int64, report *string) error {
+			stored = report
+			return nil
+		},
+	)
+	svc, saved := newIngestRefreshService(t, `<?xml version="1.0"?><rss version="2.0"><channel><title>Feed</title><link>https://example.com</link>
+<item><title>Epoch</title><guid>1</guid><link>https://example.com/1</link><pubDate>Thu, 01 Jan 1970 00:00:00 GMT</pubDate></item>
+<item><title>Ahead</title><guid>2</guid><link>https://example.com/2</link><pubDate>Tue, 19 Jan 2038 03:14:07 GMT</pubDate></item>
+<item><title>Fine</title><guid>3</guid><link>https://example.com/3</link><pubDate>Mon, 02 Jan 2006 15:04:05 GMT</pubDate></item>
+</channel></rss>`, fetchLog)
+
+	before := time.Now().UTC()
+	require.NoError(t, svc.RefreshFeed(context.Background(), 10))
+
+	entries := <-saved
+	require.Len(t, entries, 3)
+	for _, entry := range entries[:2] {
+		require.Equal(t, model.PublishedAtFromFetch, entry.PublishedAtSource)
+		require.False(t, entry.PublishedAt.Before(before))
+	}
+	require.Equal(t, model.PublishedAtFromFeed, entries[2].PublishedAtSource)
+
+	require.NotNil(t, stored)
+	var report service.IngestReport
+	require.NoError(t, json.Unmarshal([]byte(*stored), &report))
+	require.Equal(t, map[string]int{service.IngestImplausibleDate: 2}, report.Counts)
+	require.Equal(t, "1970-01-01T00:00:00Z", report.Issues[0].Detail)
+	require.Equal(t, "2038-01-19T03:14:07Z", report.Issues[1].Detail)
+}
+
+func TestRefreshService_RefreshFeed_CleanRefreshClearsIngestReport(t *testing.T) {
+	fetchLog := mock.NewMockFeedFetchLogRepository(gomock.NewController(t))
+	fetchLog.EXPECT().SaveIngestReport(gomock.Any(), int64(10), nil).Return(nil)
+	svc, saved := newIngestRefreshService(t, `<?xml version="1.0"?><rss version="2.0"><channel><title>Feed</title><link>https://example.com</link>
+<item><title>One</title><guid>1</guid><link>https://example.com/1</link><pubDate>Mon, 02 Jan 2006 15:04:05 GMT</pubDate></item>
+</channel></rss>`, fetchLog)
+
+	require.NoError(t, svc.RefreshFeed(context.Background(), 10))
+	require.NotEmpty(t, <-saved)
+}
+
+func TestRefreshService_GetIngestReport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockFeeds := mock.NewMockFeedRepository(ctrl)
+	mockFeeds.EXPECT().GetByID(gomock.Any(), int64(1)).Return(model.Feed{ID: 1}, nil).Times(2)
+	mockFeeds.EXPECT().GetByID(gomock.Any(), int64(2)).Return(model.Feed{}, sql.ErrNoRows)
+	fetchLog := mock.NewMockFeedFetchLogRepository(ctrl)
+	data := `{"checkedAt":"2026-01-02T03:04:05Z","items":3,"saved":2,"counts":{"missing_link":1},"issues":[{"reason":"missing_link","title":"No link"}]}`
+	fetchLog.EXPECT().GetIngestReport(gomock.Any(), int64(1)).Return(&data, nil)
+	fetchLog.EXPECT().GetIngestReport(gomock.Any(), int64(1)).Return(nil, nil)
+	svc := service.NewRefreshService(mockFeeds, mock.NewMockEntryRepository(ctrl), nil, nil, nil, nil, nil, nil, nil, fetchLog, nil, nil)
+
+	report, err := svc.GetIngestReport(context.Background(), 1)
+	require.NoError(t, err)
+	require.Equal(t, 3, report.Items)
+	require.Equal(t, map[string]int{service.IngestMissingLink: 1}, report.Counts)
+	require.Equal(t, "No link", report.Issues[0].Title)
+
+	report, err = svc.GetIngestReport(context.Background(), 1)
+	require.NoError(t, err)
+	require.Nil(t, report)
+
+	_, err = svc.GetIngestReport(context.Background(), 2)
+	require.ErrorIs(t, err, service.ErrNotFound)
+}
@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+)
+
+// InitialBackfill limits what a new feed's first fetch brings in as unread.
+// The zero value keeps every item ("all").
+type InitialBackfill struct {
+	// MarkRead saves the items already read, so unread counts start at zero ("none").
+	MarkRead bool
+	// Limit keeps only the most recent Limit items; 0 keeps all.
+	Limit int
+}
+
+// ParseInitialBackfill reads "all" (or empty), "none", or a positive item count.
+func ParseInitialBackfill(value string) (InitialBackfill, error) {
+	switch value = strings.ToLower(strings.TrimSpace(value)); value {
+	case "", "all":
+		return InitialBackfill{}, nil
+	case "none":
+		return InitialBackfill{MarkRead: true}, nil
+	}
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit <= 0 {
+		return InitialBackfill{}, ErrInvalid
+	}
+	return InitialBackfill{Limit: limit}, nil
+}
+
+// filter returns the items to save. Items are ranked by date, newest first;
+// undated items keep their document order after dated ones.
+func (b InitialBackfill) filter(items []*gofeed.Item) []*gofeed.Item {
+	if b.Limit <= 0 || len(items) <= b.Limit {
+		return items
+	}
+	ranked := slices.Clone(items)
+	slices.SortStableFunc(ranked, func(a, c *gofeed.Item) int {
+		return itemTime(c).Compare(itemTime(a))
+	})
+	return ranked[:b.Limit]
+}
+
+func itemTime(item *gofeed.Item) time.Time {
+	if item.PublishedParsed != nil {
+		return *item.PublishedParsed
+	}
+	if item.UpdatedParsed != nil {
+		return *item.UpdatedParsed
+	}
+	return time.Time{}
+}
+
+type initialBackfillKey struct{}
+
+// withInitialBackfill makes refreshes with ctx treat their fetch as a feed's first.
+func withInitialBackfill(ctx context.Context, b InitialBackfill) context.Context {
+	return context.WithValue(ctx, initialBackfillKey{}, b)
+}
+
+func initialBackfillFrom(ctx context.Context) InitialBackfill {
+	b, _ := ctx.Value(initialBackfillKey{}).(InitialBackfill)
+	return b
+}
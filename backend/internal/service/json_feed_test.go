@@ -0,0 +1,183 @@
+package service_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"gist/backend/internal/model"
+	"gist/backend/internal/repository/mock"
+	"gist/backend/internal/service"
+	"gist/backend/pkg/network"
+)
+
+const sampleJSONFeed = `{
+  "version": "https://jsonfeed.org/version/1.1",
+  "title": "Indie Blog",
+  "home_page_url": "https://blog.example.com/",
+  "feed_url": "https://blog.example.com/feed.json",
+  "description": "Notes",
+  "icon": "https://blog.example.com/icon.png",
+  "authors": [{"name": "Ada"}],
+  "items": [
+    {
+      "id": "note-1",
+      "url": "https://blog.example.com/notes/1",
+      "content_text": "First line with <angle> & ampersand.\n\nSecond paragraph.",
+      "date_published": "2024-05-01T10:00:00+02:00"
+    },
+    {
+      "id": "tag:blog.example.com,2024:link-2",
+      "url": "https://blog.example.com/links/2",
+      "external_url": "https://other.example.org/article",
+      "title": "An interesting link",
+      "content_html": "<p>Worth reading.</p>",
+      "banner_image": "https://blog.example.com/banner.jpg",
+      "date_published": "2024-05-02T08:30:00Z",
+      "authors": [{"name": "Bob"}],
+      "attachments": [{"url": "https://blog.example.com/ep2.mp3", "mime_type": "audio/mpeg", "size_in_bytes": 4096, "duration_in_seconds": 60}]
+    }
+  ]
+}`
+
+func TestParseStaticFeed_JSONFeed(t *testing.T) {
+	parsed, err := service.ParseStaticFeed([]byte("\ufeff\n" + sampleJSONFeed))
+	require.NoError(t, err)
+	require.Equal(t, "json", parsed.FeedType)
+	require.Equal(t, "1.1", parsed.FeedVersion)
+	require.Equal(t, "Indie Blog", parsed.Title)
+	require.Equal(t, "https://blog.example.com/", parsed.Link)
+	require.Equal(t, "https://blog.example.com/icon.png", parsed.Image.URL)
+	require.Len(t, parsed.Items, 2)
+
+	feed := model.Feed{ID: 1, DedupeKey: model.DedupeKeyAuto}
+
+	// Only content_text: escaped into paragraphs, author inherited from the feed
+	textOnly := service.ItemToEntry(feed, parsed.Items[0], false, nil)
+	require.Equal(t, "<p>First line with &lt;angle&gt; &amp; ampersand.</p>\n<p>Second paragraph.</p>", *textOnly.Content)
+	require.Equal(t, "Ada", *textOnly.Author)
+	require.Equal(t, "https://blog.example.com/notes/1", *textOnly.URL)
+	require.Equal(t, time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC), textOnly.PublishedAt.UTC())
+	require.Equal(t, hashString("note-1"), textOnly.Hash)
+
+	// External url differing from id: the item's own url is the link, the id its identity
+	linked := service.ItemToEntry(feed, parsed.Items[1], false, nil)
+	require.Equal(t, "https://blog.example.com/links/2", *linked.URL)
+	require.Equal(t, []string{"https://blog.example.com/links/2", "https://other.example.org/article"}, parsed.Items[1].Links)
+	require.Equal(t, hashString("tag:blog.example.com,2024:link-2"), linked.Hash)
+	require.Equal(t, "<p>Worth reading.</p>", *linked.Content)
+	require.Equal(t, "https://blog.example.com/banner.jpg", *linked.ThumbnailURL)
+	require.Equal(t, "Bob", *linked.Author)
+	require.Len(t, parsed.Items[1].Enclosures, 1)
+	require.Equal(t, "audio/mpeg", parsed.Items[1].Enclosures[0].Type)
+	require.Equal(t, "4096", parsed.Items[1].Enclosures[0].Length)
+}
+
+func TestParseStaticFeed_JSONFeedVariants(t *testing.T) {
+	// JSON Feed 1.0 with a single author and a numeric id
+	parsed, err := service.ParseStaticFeed([]byte(`{"version": "https://jsonfeed.org/version/1", "title": "Old", "author": {"name": "Cy"}, "items": [{"id": 42, "external_url": "https://example.org/x", "content_html": "<p>x</p>"}]}`))
+	require.NoError(t, err)
+	require.Equal(t, "42", parsed.Items[0].GUID)
+	require.Equal(t, "Cy", parsed.Items[0].Author.Name)
+	// Without its own url the item links to the external page
+	require.Equal(t, "https://example.org/x", parsed.Items[0].Link)
+
+	// Other JSON documents are not feeds
+	_, err = service.ParseStaticFeed([]byte(`{"error": "not found"}`))
+	require.ErrorIs(t, err, service.ErrNotAFeed)
+	_, err = service.ParseStaticFeed([]byte(`{"version": "https://jsonfeed.org/version/1.1", "items": [`))
+	require.ErrorIs(t, err, service.ErrNotAFeed)
+}
+
+func TestFeedService_Add_JSONFeed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFeeds := mock.NewMockFeedRepository(ctrl)
+	mockEntries := mock.NewMockEntryRepository(ctrl)
+
+	feedURL := "https://blog.example.com/feed.json"
+	client := &http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			header := make(http.Header)
+			header.Set("Content-Type", "application/feed+json")
+			header.Set("ETag", `"v1"`)
+			return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(sampleJSONFeed)), Header: header, Request: req}, nil
+		}),
+	}
+
+	var createdFeed model.Feed
+	mockFeeds.EXPECT().FindByURL(gomock.Any(), feedURL).Return(nil, nil)
+	mockFeeds.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, feed model.Feed) (model.Feed, error) {
+			createdFeed = feed
+			feed.ID = 7
+			return feed, nil
+		},
+	)
+	mockEntries.EXPECT().CreateOrUpdate(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(2)
+
+	svc := service.NewFeedService(mockFeeds, mock.NewMockFolderRepository(ctrl), mockEntries, nil, nil, network.NewClientFactoryForTest(client), nil, nil, nil, nil)
+	_, err := svc.Add(context.Background(), feedURL, nil, "", "article", service.InitialBackfill{})
+	require.NoError(t, err)
+	require.Equal(t, "Indie Blog", createdFeed.Title)
+	require.Equal(t, "https://blog.example.com/", *createdFeed.SiteURL)
+	require.Equal(t, `"v1"`, *createdFeed.ETag)
+}
+
+func TestRefreshService_RefreshFeed_JSONFeedConditionalGET(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFeeds := mock.NewMockFeedRepository(ctrl)
+	mockFeeds.EXPECT().UpdateLastFetchedAt(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockFeeds.EXPECT().UpdateLastEntrySeenAt(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockFeeds.EXPECT().UpdateNotModifiedStreak(gomock.Any(), gomock.Any(), 1).Return(nil).AnyTimes()
+	mockEntries := mock.NewMockEntryRepository(ctrl)
+
+	etag := `"v1"`
+	feed := model.Feed{ID: 7, URL: "https://blog.example.com/feed.json", Title: "Indie Blog", ETag: &etag}
+	mockFeeds.EXPECT().GetByID(gomock.Any(), int64(7)).Return(feed, nil).Times(2)
+	mockFeeds.EXPECT().UpdateErrorMessage(gomock.Any(), int64(7), nil).Return(nil).Times(2)
+	mockFeeds.EXPECT().UpdateConditionalGet(gomock.Any(), int64(7), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ int64, etag, _ *string) error {
+			require.Equal(t, `"v2"`, *etag)
+			return nil
+		},
+	)
+	mockFeeds.EXPECT().UpdateSiteURL(gomock.Any(), int64(7), "https://blog.example.com/").Return(nil)
+	mockFeeds.EXPECT().ListMutedAuthors(gomock.Any(), int64(7)).Return(nil, nil).AnyTimes()
+	mockEntries.EXPECT().SaveBatch(gomock.Any(), int64(7), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ int64, entries []model.Entry, _ int) (int, int, error) {
+			require.Len(t, entries, 2)
+			return 2, 0, nil
+		},
+	)
+
+	changed := true
+	client := &http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			require.Equal(t, etag, req.Header.Get("If-None-Match"))
+			if !changed {
+				return &http.Response{StatusCode: http.StatusNotModified, Body: http.NoBody, Header: make(http.Header), Request: req}, nil
+			}
+			header := make(http.Header)
+			header.Set("Content-Type", "application/feed+json")
+			header.Set("ETag", `"v2"`)
+			return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(sampleJSONFeed)), Header: header, Request: req}, nil
+		}),
+	}
+
+	svc := service.NewRefreshService(mockFeeds, mockEntries, nil, nil, nil, nil, network.NewClientFactoryForTest(client), nil, nil, nil, nil, nil)
+	require.NoError(t, svc.RefreshFeed(context.Background(), 7))
+
+	// An unchanged feed answers 304 and saves nothing
+	changed = false
+	require.NoError(t, svc.RefreshFeed(context.Background(), 7))
+}
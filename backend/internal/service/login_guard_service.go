@@ -0,0 +1,155 @@
+//go:generate mockgen -source=$GOFILE -destination=mock/$GOFILE -package=mock
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"gist/backend/internal/model"
+	"gist/backend/internal/repository"
+	"gist/backend/pkg/logger"
+)
+
+// Login brute-force protection parameters
+const (
+	loginFailureThreshold = 5
+	loginBaseLockout      = time.Minute
+	loginMaxLockout       = time.Hour
+	loginAttemptTTL       = 24 * time.Hour
+	loginEventsKeep       = 100
+)
+
+// LoginGuardService throttles password guessing and keeps a login audit log.
+type LoginGuardService interface {
+	// Check returns a *LoginLockedError while the ip+identifier pair is locked out.
+	Check(ip, identifier string) error
+	// RecordFailure counts a failed attempt and writes an audit event.
+	RecordFailure(ctx context.Context, ip, userAgent, identifier string)
+	// RecordSuccess clears the failure count and writes an audit event.
+	RecordSuccess(ctx context.Context, ip, userAgent, identifier string)
+	// ListEvents returns the most recent login events, newest first.
+	ListEvents(ctx context.Context) ([]model.LoginEvent, error)
+}
+
+type loginAttempt struct {
+	failures    int
+	lockedUntil time.Time
+	lastFailure time.Time
+}
+
+type loginGuardService struct {
+	repo repository.LoginEventRepository
+
+	mu       sync.Mutex
+	attempts map[string]*loginAttempt
+}
+
+// NewLoginGuardService creates a new login guard service.
+func NewLoginGuardService(repo repository.LoginEventRepository) LoginGuardService {
+	return &loginGuardService{
+		repo:     repo,
+		attempts: make(map[string]*loginAttempt),
+	}
+}
+
+// Check returns a *LoginLockedError while the ip+identifier pair is locked out.
+func (s *loginGuardService) Check(ip, identifier string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	attempt, ok := s.attempts[loginAttemptKey(ip, identifier)]
+	if !ok {
+		return nil
+	}
+	if remaining := time.Until(attempt.lockedUntil); remaining > 0 {
+		return &LoginLockedError{RetryAfter: remaining}
+	}
+	return nil
+}
+
+// RecordFailure counts a failed attempt and writes an audit event.
+func (s *loginGuardService) RecordFailure(ctx context.Context, ip, userAgent, identifier string) {
+	now := time.Now()
+
+	s.mu.Lock()
+	s.pruneLocked(now)
+	key := loginAttemptKey(ip, identifier)
+	attempt, ok := s.attempts[key]
+	if !ok {
+		attempt = &loginAttempt{}
+		s.attempts[key] = attempt
+	}
+	attempt.failures++
+	attempt.lastFailure = now
+	if lockout := lockoutDuration(attempt.failures); lockout > 0 {
+		attempt.lockedUntil = now.Add(lockout)
+		logger.Warn("auth login locked", "module", "service", "action", "login", "resource", "auth", "result", "failed", "actor", identifier, "remote_ip", ip, "failures", attempt.failures, "lockout_seconds", int(lockout.Seconds()))
+	}
+	s.mu.Unlock()
+
+	s.saveEvent(ctx, ip, userAgent, identifier, false)
+}
+
+// RecordSuccess clears the failure count and writes an audit event.
+func (s *loginGuardService) RecordSuccess(ctx context.Context, ip, userAgent, identifier string) {
+	s.mu.Lock()
+	delete(s.attempts, loginAttemptKey(ip, identifier))
+	s.mu.Unlock()
+
+	s.saveEvent(ctx, ip, userAgent, identifier, true)
+}
+
+// ListEvents returns the most recent login events, newest first.
+func (s *loginGuardService) ListEvents(ctx context.Context) ([]model.LoginEvent, error) {
+	events, err := s.repo.List(ctx, loginEventsKeep)
+	if err != nil {
+		return nil, fmt.Errorf("list login events: %w", err)
+	}
+	return events, nil
+}
+
+func (s *loginGuardService) saveEvent(ctx context.Context, ip, userAgent, identifier string, success bool) {
+	event := model.LoginEvent{
+		Identifier: identifier,
+		IP:         ip,
+		UserAgent:  userAgent,
+		Success:    success,
+		CreatedAt:  time.Now().UTC(),
+	}
+	if err := s.repo.Create(ctx, event, loginEventsKeep); err != nil {
+		// Audit failures must not block logins
+		logger.Warn("login event save failed", "module", "service", "action", "create", "resource", "login_event", "result", "failed", "error", err)
+	}
+}
+
+// pruneLocked drops stale trackers so the map does not grow unbounded. Caller holds s.mu.
+func (s *loginGuardService) pruneLocked(now time.Time) {
+	for key, attempt := range s.attempts {
+		if now.Sub(attempt.lastFailure) > loginAttemptTTL && now.After(attempt.lockedUntil) {
+			delete(s.attempts, key)
+		}
+	}
+}
+
+// lockoutDuration returns how long to lock after the given number of consecutive failures:
+// nothing below the threshold, then 1 minute doubling per failure, capped at 1 hour.
+func lockoutDuration(failures int) time.Duration {
+	if failures < loginFailureThreshold {
+		return 0
+	}
+	lockout := loginBaseLockout
+	for i := loginFailureThreshold; i < failures; i++ {
+		lockout *= 2
+		if lockout >= loginMaxLockout {
+			return loginMaxLockout
+		}
+	}
+	return lockout
+}
+
+func loginAttemptKey(ip, identifier string) string {
+	return ip + "|" + strings.ToLower(strings.TrimSpace(identifier))
+}
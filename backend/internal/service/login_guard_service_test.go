@@ -0,0 +1,85 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"gist/backend/internal/model"
+	"gist/backend/internal/repository/mock"
+	"gist/backend/internal/service"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestLockoutDuration(t *testing.T) {
+	require.Zero(t, service.LockoutDuration(4))
+	require.Equal(t, time.Minute, service.LockoutDuration(5))
+	require.Equal(t, 2*time.Minute, service.LockoutDuration(6))
+	require.Equal(t, 4*time.Minute, service.LockoutDuration(7))
+	require.Equal(t, time.Hour, service.LockoutDuration(20))
+}
+
+func TestLoginGuardService_LocksAfterFailures(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mock.NewMockLoginEventRepository(ctrl)
+	repo.EXPECT().Create(gomock.Any(), gomock.Any(), 100).Return(nil).AnyTimes()
+	svc := service.NewLoginGuardService(repo)
+	ctx := context.Background()
+
+	for i := 0; i < 4; i++ {
+		require.NoError(t, svc.Check("10.0.0.1", "alice"))
+		svc.RecordFailure(ctx, "10.0.0.1", "curl", "alice")
+	}
+	require.NoError(t, svc.Check("10.0.0.1", "alice"))
+	svc.RecordFailure(ctx, "10.0.0.1", "curl", "Alice")
+
+	err := svc.Check("10.0.0.1", "alice")
+	require.ErrorIs(t, err, service.ErrTooManyAttemptsHelper)
+	var locked *service.LoginLockedError
+	require.True(t, errors.As(err, &locked))
+	require.InDelta(t, time.Minute.Seconds(), locked.RetryAfter.Seconds(), 1)
+
+	// Other IPs and identifiers are unaffected
+	require.NoError(t, svc.Check("10.0.0.2", "alice"))
+	require.NoError(t, svc.Check("10.0.0.1", "bob"))
+}
+
+func TestLoginGuardService_SuccessResets(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mock.NewMockLoginEventRepository(ctrl)
+	svc := service.NewLoginGuardService(repo)
+	ctx := context.Background()
+
+	repo.EXPECT().Create(gomock.Any(), gomock.Any(), 100).DoAndReturn(
+		func(_ context.Context, event model.LoginEvent, _ int) error {
+			require.Equal(t, "10.0.0.1", event.IP)
+			require.Equal(t, "curl", event.UserAgent)
+			return nil
+		}).Times(6)
+
+	for i := 0; i < 5; i++ {
+		svc.RecordFailure(ctx, "10.0.0.1", "curl", "alice")
+	}
+	require.Error(t, svc.Check("10.0.0.1", "alice"))
+
+	svc.RecordSuccess(ctx, "10.0.0.1", "curl", "alice")
+	require.NoError(t, svc.Check("10.0.0.1", "alice"))
+}
+
+func TestLoginGuardService_EventSaveErrorIgnored(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mock.NewMockLoginEventRepository(ctrl)
+	svc := service.NewLoginGuardService(repo)
+
+	repo.EXPECT().Create(gomock.Any(), gomock.Any(), 100).Return(errors.New("db down"))
+	svc.RecordSuccess(context.Background(), "10.0.0.1", "curl", "alice")
+}
@@ -0,0 +1,152 @@
+//go:generate mockgen -source=$GOFILE -destination=mock/$GOFILE -package=mock
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"gist/backend/internal/repository"
+	"gist/backend/pkg/logger"
+)
+
+// Maintenance actions accepted by MaintenanceService.Run.
+const (
+	MaintenanceNormalizeDates   = "normalize_dates"
+	MaintenanceCanonicalizeURLs = "canonicalize_urls"
+	MaintenanceIndexSearch      = "index_search"
+)
+
+// MaintenanceResult reports what a maintenance action changed.
+type MaintenanceResult struct {
+	Action  string
+	Updated int
+	// Skipped counts rows the action could not handle, e.g. unparseable dates.
+	Skipped int
+}
+
+type MaintenanceService interface {
+	// Run executes a one-off maintenance action; unknown actions return ErrInvalid.
+	// It returns ErrMaintenanceRunning while another action or a compaction runs.
+	Run(ctx context.Context, action string) (MaintenanceResult, error)
+	// Storage reports the disk use of the database and the data directory.
+	Storage(ctx context.Context) (StorageReport, error)
+	// Compact checkpoints the WAL and, with vacuum, rebuilds the database file.
+	// It returns ErrMaintenanceRunning while another compaction or action runs.
+	Compact(ctx context.Context, vacuum bool) (CompactResult, error)
+}
+
+type maintenanceService struct {
+	entries  repository.EntryRepository
+	feeds    repository.FeedRepository
+	settings SettingsService
+	storage  repository.StorageRepository
+	dbPath   string
+	dataDir  string
+
+	// mu lets one maintenance action or compaction run at a time.
+	mu sync.Mutex
+}
+
+// NewMaintenanceService reports on and compacts the database at dbPath and
+// the cache directories under dataDir through storage.
+func NewMaintenanceService(entries repository.EntryRepository, feeds repository.FeedRepository, settings SettingsService, storage repository.StorageRepository, dbPath, dataDir string) MaintenanceService {
+	return &maintenanceService{entries: entries, feeds: feeds, settings: settings, storage: storage, dbPath: dbPath, dataDir: dataDir}
+}
+
+func (s *maintenanceService) Run(ctx context.Context, action string) (MaintenanceResult, error) {
+	if !s.mu.TryLock() {
+		return MaintenanceResult{}, ErrMaintenanceRunning
+	}
+	defer s.mu.Unlock()
+
+	switch action {
+	case MaintenanceNormalizeDates:
+		return s.normalizeDates(ctx)
+	case MaintenanceCanonicalizeURLs:
+		return s.canonicalizeURLs(ctx)
+	case MaintenanceIndexSearch:
+		return s.indexSearch(ctx)
+	default:
+		return MaintenanceResult{}, ErrInvalid
+	}
+}
+
+// normalizeDates rewrites published_at values not stored as UTC RFC3339. Values
+// without a zone are read in the feed's assume_timezone, else the configured one.
+// Dates gofeed already read as UTC at ingest can't be told apart and stay as they are.
+func (s *maintenanceService) normalizeDates(ctx context.Context) (MaintenanceResult, error) {
+	result := MaintenanceResult{Action: MaintenanceNormalizeDates}
+
+	values, err := s.entries.ListNonUTCPublishedAt(ctx)
+	if err != nil {
+		return result, fmt.Errorf("list published dates: %w", err)
+	}
+	if len(values) == 0 {
+		return result, nil
+	}
+
+	feeds, err := s.feeds.List(ctx, nil)
+	if err != nil {
+		return result, fmt.Errorf("list feeds: %w", err)
+	}
+	general := loadGeneralSettings(ctx, s.settings)
+	fallback := configuredLocation(general)
+	locations := make(map[int64]*time.Location, len(feeds))
+	for _, feed := range feeds {
+		locations[feed.ID] = feedLocation(feed, general)
+	}
+
+	for _, v := range values {
+		loc, ok := locations[v.FeedID]
+		if !ok {
+			loc = fallback
+		}
+		publishedAt, ok := parseStoredPublishedAt(v.Value, loc)
+		if !ok {
+			result.Skipped++
+			continue
+		}
+		if err := s.entries.UpdatePublishedAt(ctx, v.EntryID, publishedAt); err != nil {
+			logger.Error("normalize dates failed", "module", "service", "action", "update", "resource", "entry", "result", "failed", "entry_id", v.EntryID, "error", err)
+			return result, err
+		}
+		result.Updated++
+	}
+
+	logger.Info("dates normalized", "module", "service", "action", "update", "resource", "entry", "result", "ok", "updated", result.Updated, "skipped", result.Skipped)
+	return result, nil
+}
+
+// canonicalizeURLs fills in the canonical URL of every entry saved before it
+// was kept, instead of waiting for each to be saved again or visited.
+func (s *maintenanceService) canonicalizeURLs(ctx context.Context) (MaintenanceResult, error) {
+	result := MaintenanceResult{Action: MaintenanceCanonicalizeURLs}
+
+	updated, err := s.entries.BackfillCanonicalURLs(ctx)
+	result.Updated = updated
+	if err != nil {
+		logger.Error("canonicalize urls failed", "module", "service", "action", "update", "resource", "entry", "result", "failed", "updated", updated, "error", err)
+		return result, err
+	}
+
+	logger.Info("urls canonicalized", "module", "service", "action", "update", "resource", "entry", "result", "ok", "updated", result.Updated)
+	return result, nil
+}
+
+// indexSearch indexes the readable content and AI summaries of entries
+// fetched or summarized before they were searchable.
+func (s *maintenanceService) indexSearch(ctx context.Context) (MaintenanceResult, error) {
+	result := MaintenanceResult{Action: MaintenanceIndexSearch}
+
+	updated, err := s.entries.BackfillSearchIndex(ctx)
+	result.Updated = updated
+	if err != nil {
+		logger.Error("search index backfill failed", "module", "service", "action", "update", "resource", "entry", "result", "failed", "updated", updated, "error", err)
+		return result, err
+	}
+
+	logger.Info("search index backfilled", "module", "service", "action", "update", "resource", "entry", "result", "ok", "updated", result.Updated)
+	return result, nil
+}
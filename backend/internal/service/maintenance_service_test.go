@@ -0,0 +1,203 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"gist/backend/internal/model"
+	"gist/backend/internal/repository"
+	"gist/backend/internal/repository/mock"
+	"gist/backend/internal/service"
+	servicemock "gist/backend/internal/service/mock"
+)
+
+func TestMaintenanceService_NormalizeDates(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	entries := mock.NewMockEntryRepository(ctrl)
+	feeds := mock.NewMockFeedRepository(ctrl)
+	settings := servicemock.NewMockSettingsService(ctrl)
+	svc := service.NewMaintenanceService(entries, feeds, settings, nil, "", "")
+	ctx := context.Background()
+
+	entries.EXPECT().ListNonUTCPublishedAt(ctx).Return([]repository.RawPublishedAt{
+		{EntryID: 1, FeedID: 10, Value: "2024-03-01 10:00:00"},
+		{EntryID: 2, FeedID: 20, Value: "2024-03-01T10:00:00"},
+		{EntryID: 3, FeedID: 10, Value: "2024-03-01T10:00:00+01:00"},
+		{EntryID: 4, FeedID: 99, Value: "2024-03-01"},
+		{EntryID: 5, FeedID: 10, Value: "last tuesday"},
+	}, nil)
+	feeds.EXPECT().List(ctx, (*int64)(nil)).Return([]model.Feed{
+		{ID: 10, AssumeTimezone: stringPtr("America/New_York")},
+		{ID: 20},
+	}, nil)
+	settings.EXPECT().GetGeneralSettings(ctx).Return(&service.GeneralSettings{Timezone: "Asia/Shanghai"}, nil)
+
+	entries.EXPECT().UpdatePublishedAt(ctx, int64(1), time.Date(2024, 3, 1, 15, 0, 0, 0, time.UTC)).Return(nil)
+	entries.EXPECT().UpdatePublishedAt(ctx, int64(2), time.Date(2024, 3, 1, 2, 0, 0, 0, time.UTC)).Return(nil)
+	entries.EXPECT().UpdatePublishedAt(ctx, int64(3), time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)).Return(nil)
+	// Feeds missing from the list (e.g. trashed) use the global timezone
+	entries.EXPECT().UpdatePublishedAt(ctx, int64(4), time.Date(2024, 2, 29, 16, 0, 0, 0, time.UTC)).Return(nil)
+
+	result, err := svc.Run(ctx, service.MaintenanceNormalizeDates)
+	require.NoError(t, err)
+	require.Equal(t, service.MaintenanceResult{Action: service.MaintenanceNormalizeDates, Updated: 4, Skipped: 1}, result)
+}
+
+func TestMaintenanceService_NormalizeDates_NothingToDo(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	entries := mock.NewMockEntryRepository(ctrl)
+	svc := service.NewMaintenanceService(entries, mock.NewMockFeedRepository(ctrl), nil, nil, "", "")
+
+	entries.EXPECT().ListNonUTCPublishedAt(gomock.Any()).Return(nil, nil)
+
+	result, err := svc.Run(context.Background(), service.MaintenanceNormalizeDates)
+	require.NoError(t, err)
+	require.Zero(t, result.Updated)
+}
+
+func TestMaintenanceService_NormalizeDates_UpdateError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	entries := mock.NewMockEntryRepository(ctrl)
+	feeds := mock.NewMockFeedRepository(ctrl)
+	svc := service.NewMaintenanceService(entries, feeds, nil, nil, "", "")
+
+	entries.EXPECT().ListNonUTCPublishedAt(gomock.Any()).Return([]repository.RawPublishedAt{{EntryID: 1, FeedID: 10, Value: "2024-03-01"}}, nil)
+	feeds.EXPECT().List(gomock.Any(), (*int64)(nil)).Return(nil, nil)
+	entries.EXPECT().UpdatePublishedAt(gomock.Any(), int64(1), time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)).Return(errors.New("db down"))
+
+	_, err := svc.Run(context.Background(), service.MaintenanceNormalizeDates)
+	require.Error(t, err)
+}
+
+func TestMaintenanceService_CanonicalizeURLs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	entries := mock.NewMockEntryRepository(ctrl)
+	svc := service.NewMaintenanceService(entries, mock.NewMockFeedRepository(ctrl), nil, nil, "", "")
+
+	entries.EXPECT().BackfillCanonicalURLs(gomock.Any()).Return(1200, nil)
+	result, err := svc.Run(context.Background(), service.MaintenanceCanonicalizeURLs)
+	require.NoError(t, err)
+	require.Equal(t, service.MaintenanceResult{Action: service.MaintenanceCanonicalizeURLs, Updated: 1200}, result)
+
+	dbErr := errors.New("disk full")
+	entries.EXPECT().BackfillCanonicalURLs(gomock.Any()).Return(500, dbErr)
+	result, err = svc.Run(context.Background(), service.MaintenanceCanonicalizeURLs)
+	require.ErrorIs(t, err, dbErr)
+	require.Equal(t, 500, result.Updated)
+}
+
+func TestMaintenanceService_IndexSearch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	entries := mock.NewMockEntryRepository(ctrl)
+	svc := service.NewMaintenanceService(entries, mock.NewMockFeedRepository(ctrl), nil, nil, "", "")
+
+	entries.EXPECT().BackfillSearchIndex(gomock.Any()).Return(730, nil)
+	result, err := svc.Run(context.Background(), service.MaintenanceIndexSearch)
+	require.NoError(t, err)
+	require.Equal(t, service.MaintenanceResult{Action: service.MaintenanceIndexSearch, Updated: 730}, result)
+}
+
+func TestMaintenanceService_UnknownAction(t *testing.T) {
+	svc := service.NewMaintenanceService(nil, nil, nil, nil, "", "")
+
+	_, err := svc.Run(context.Background(), "drop_tables")
+	require.ErrorIs(t, err, service.ErrInvalid)
+}
+
+func TestMaintenanceService_Storage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	storage := mock.NewMockStorageRepository(ctrl)
+	dataDir := t.TempDir()
+	dbPath := filepath.Join(dataDir, "gist.db")
+	require.NoError(t, os.WriteFile(dbPath, make([]byte, 1000), 0o644))
+	require.NoError(t, os.WriteFile(dbPath+"-wal", make([]byte, 10), 0o644))
+	require.NoError(t, os.MkdirAll(filepath.Join(dataDir, "icons", "sub"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dataDir, "icons", "a.png"), make([]byte, 5), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dataDir, "icons", "sub", "b.png"), make([]byte, 7), 0o644))
+	svc := service.NewMaintenanceService(nil, nil, nil, storage, dbPath, dataDir)
+	ctx := context.Background()
+
+	entryBytes := int64(250)
+	storage.EXPECT().TableStats(ctx, gomock.Any()).Return([]repository.TableStat{
+		{Name: "entries", Rows: 3, Bytes: &entryBytes},
+	}, nil)
+
+	report, err := svc.Storage(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(1000), report.DatabaseBytes)
+	require.Equal(t, int64(10), report.WALBytes)
+	require.Zero(t, report.SHMBytes)
+	require.True(t, report.TableBytesAvailable)
+	require.Len(t, report.Tables, 1)
+	require.InDelta(t, 0.25, *report.Tables[0].Share, 1e-9)
+
+	require.Len(t, report.Directories, 4)
+	require.Equal(t, service.StorageDirectory{Name: "icons", Present: true, Files: 2, Bytes: 12}, report.Directories[0])
+	require.Equal(t, service.StorageDirectory{Name: "images"}, report.Directories[1])
+
+	// Without dbstat only row counts are known
+	storage.EXPECT().TableStats(ctx, gomock.Any()).Return([]repository.TableStat{{Name: "entries", Rows: 3}}, nil)
+	report, err = svc.Storage(ctx)
+	require.NoError(t, err)
+	require.False(t, report.TableBytesAvailable)
+	require.Nil(t, report.Tables[0].Share)
+}
+
+func TestMaintenanceService_Compact(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	storage := mock.NewMockStorageRepository(ctrl)
+	svc := service.NewMaintenanceService(nil, nil, nil, storage, filepath.Join(t.TempDir(), "gist.db"), "")
+	ctx := context.Background()
+
+	storage.EXPECT().Checkpoint(ctx).Return(true, nil)
+	result, err := svc.Compact(ctx, false)
+	require.NoError(t, err)
+	require.Equal(t, service.CompactResult{}, result)
+
+	gomock.InOrder(
+		storage.EXPECT().Checkpoint(ctx).Return(false, nil),
+		storage.EXPECT().Vacuum(ctx).Return(nil),
+		storage.EXPECT().Checkpoint(ctx).Return(false, nil),
+	)
+	result, err = svc.Compact(ctx, true)
+	require.NoError(t, err)
+	require.True(t, result.Checkpointed)
+	require.True(t, result.Vacuumed)
+}
+
+func TestMaintenanceService_CompactWhileRunning(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	storage := mock.NewMockStorageRepository(ctrl)
+	svc := service.NewMaintenanceService(nil, nil, nil, storage, "", "")
+	ctx := context.Background()
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	storage.EXPECT().Checkpoint(ctx).DoAndReturn(func(context.Context) (bool, error) {
+		close(started)
+		<-release
+		return false, nil
+	})
+
+	done := make(chan error)
+	go func() {
+		_, err := svc.Compact(ctx, false)
+		done <- err
+	}()
+	<-started
+
+	_, err := svc.Compact(ctx, false)
+	require.ErrorIs(t, err, service.ErrMaintenanceRunning)
+	_, err = svc.Run(ctx, service.MaintenanceNormalizeDates)
+	require.ErrorIs(t, err, service.ErrMaintenanceRunning)
+
+	close(release)
+	require.NoError(t, <-done)
+}
@@ -0,0 +1,169 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"gist/backend/pkg/logger"
+)
+
+// storageTables are the tables that grow with use and are reported by Storage.
+var storageTables = []string{"entries", "ai_summaries", "ai_translations", "ai_list_translations"}
+
+// storageDirs are the directories under the data directory reported by Storage.
+// Directories that don't exist are reported as absent.
+var storageDirs = []string{"icons", "images", "thumbnails", "backups"}
+
+// StorageReport is the disk use of the database and the data directory.
+type StorageReport struct {
+	DatabaseBytes int64
+	WALBytes      int64
+	SHMBytes      int64
+	Directories   []StorageDirectory
+	Tables        []StorageTable
+	// TableBytesAvailable is false when SQLite lacks the dbstat table, in which
+	// case tables report rows only.
+	TableBytesAvailable bool
+}
+
+type StorageDirectory struct {
+	Name    string
+	Present bool
+	Files   int64
+	Bytes   int64
+}
+
+type StorageTable struct {
+	Name  string
+	Rows  int64
+	Bytes *int64
+	// Share is Bytes as a fraction of DatabaseBytes, nil along with Bytes.
+	Share *float64
+}
+
+// CompactResult reports what a compaction reclaimed. Sizes are of the database
+// file together with its WAL and shared-memory files.
+type CompactResult struct {
+	Checkpointed bool
+	Vacuumed     bool
+	BytesBefore  int64
+	BytesAfter   int64
+}
+
+func (s *maintenanceService) Storage(ctx context.Context) (StorageReport, error) {
+	var report StorageReport
+	if s.dbPath != "" {
+		report.DatabaseBytes = fileSize(s.dbPath)
+		report.WALBytes = fileSize(s.dbPath + "-wal")
+		report.SHMBytes = fileSize(s.dbPath + "-shm")
+	}
+
+	if s.dataDir != "" {
+		for _, name := range storageDirs {
+			report.Directories = append(report.Directories, directoryUsage(filepath.Join(s.dataDir, name), name))
+		}
+	}
+
+	if s.storage == nil {
+		return report, nil
+	}
+	stats, err := s.storage.TableStats(ctx, storageTables)
+	if err != nil {
+		logger.Error("storage stats failed", "module", "service", "action", "fetch", "resource", "storage", "result", "failed", "error", err)
+		return report, err
+	}
+	report.TableBytesAvailable = len(stats) > 0
+	for _, stat := range stats {
+		table := StorageTable{Name: stat.Name, Rows: stat.Rows, Bytes: stat.Bytes}
+		if stat.Bytes == nil {
+			report.TableBytesAvailable = false
+		} else if report.DatabaseBytes > 0 {
+			share := float64(*stat.Bytes) / float64(report.DatabaseBytes)
+			table.Share = &share
+		}
+		report.Tables = append(report.Tables, table)
+	}
+	return report, nil
+}
+
+func (s *maintenanceService) Compact(ctx context.Context, vacuum bool) (CompactResult, error) {
+	if s.storage == nil {
+		return CompactResult{}, ErrInvalid
+	}
+	if !s.mu.TryLock() {
+		return CompactResult{}, ErrMaintenanceRunning
+	}
+	defer s.mu.Unlock()
+
+	result := CompactResult{BytesBefore: s.databaseBytes()}
+
+	busy, err := s.storage.Checkpoint(ctx)
+	if err != nil {
+		logger.Error("storage checkpoint failed", "module", "service", "action", "update", "resource", "storage", "result", "failed", "error", err)
+		return result, err
+	}
+	result.Checkpointed = !busy
+
+	if vacuum {
+		if err := s.storage.Vacuum(ctx); err != nil {
+			logger.Error("storage vacuum failed", "module", "service", "action", "update", "resource", "storage", "result", "failed", "error", err)
+			return result, err
+		}
+		result.Vacuumed = true
+		// VACUUM goes through the WAL, so checkpoint again to shrink it back
+		if busy, err = s.storage.Checkpoint(ctx); err != nil {
+			logger.Error("storage checkpoint failed", "module", "service", "action", "update", "resource", "storage", "result", "failed", "error", err)
+			return result, err
+		}
+		result.Checkpointed = !busy
+	}
+
+	result.BytesAfter = s.databaseBytes()
+	logger.Info("storage compacted", "module", "service", "action", "update", "resource", "storage", "result", "ok", "vacuum", vacuum, "checkpointed", result.Checkpointed, "bytes_before", result.BytesBefore, "bytes_after", result.BytesAfter)
+	return result, nil
+}
+
+func (s *maintenanceService) databaseBytes() int64 {
+	if s.dbPath == "" {
+		return 0
+	}
+	return fileSize(s.dbPath) + fileSize(s.dbPath+"-wal") + fileSize(s.dbPath+"-shm")
+}
+
+// fileSize returns the size of path, or 0 if it can't be read.
+func fileSize(path string) int64 {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0
+	}
+	return info.Size()
+}
+
+func directoryUsage(dir, name string) StorageDirectory {
+	usage := StorageDirectory{Name: name}
+	info, err := os.Stat(dir)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			logger.Debug("storage directory stat failed", "module", "service", "action", "fetch", "resource", "storage", "result", "failed", "dir", dir, "error", err)
+		}
+		return usage
+	}
+	if !info.IsDir() {
+		return usage
+	}
+	usage.Present = true
+	_ = filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil || !d.Type().IsRegular() {
+			return nil
+		}
+		if info, err := d.Info(); err == nil {
+			usage.Files++
+			usage.Bytes += info.Size()
+		}
+		return nil
+	})
+	return usage
+}
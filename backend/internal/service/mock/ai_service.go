@@ -59,34 +59,79 @@ func (mr *MockAIServiceMockRecorder) ClearAllCache(ctx any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClearAllCache", reflect.TypeOf((*MockAIService)(nil).ClearAllCache), ctx)
 }
 
+// Digest mocks base method.
+func (m *MockAIService) Digest(ctx context.Context, params service.AIDigestParams) (service.AIDigestStream, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Digest", ctx, params)
+	ret0, _ := ret[0].(service.AIDigestStream)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Digest indicates an expected call of Digest.
+func (mr *MockAIServiceMockRecorder) Digest(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Digest", reflect.TypeOf((*MockAIService)(nil).Digest), ctx, params)
+}
+
+// GetCachedDigest mocks base method.
+func (m *MockAIService) GetCachedDigest(ctx context.Context, params service.AIDigestParams) (*model.AIDigest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCachedDigest", ctx, params)
+	ret0, _ := ret[0].(*model.AIDigest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCachedDigest indicates an expected call of GetCachedDigest.
+func (mr *MockAIServiceMockRecorder) GetCachedDigest(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCachedDigest", reflect.TypeOf((*MockAIService)(nil).GetCachedDigest), ctx, params)
+}
+
+// GetCachedFeedTitles mocks base method.
+func (m *MockAIService) GetCachedFeedTitles(ctx context.Context, feeds []model.Feed, language string) (map[int64]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCachedFeedTitles", ctx, feeds, language)
+	ret0, _ := ret[0].(map[int64]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCachedFeedTitles indicates an expected call of GetCachedFeedTitles.
+func (mr *MockAIServiceMockRecorder) GetCachedFeedTitles(ctx, feeds, language any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCachedFeedTitles", reflect.TypeOf((*MockAIService)(nil).GetCachedFeedTitles), ctx, feeds, language)
+}
+
 // GetCachedSummary mocks base method.
-func (m *MockAIService) GetCachedSummary(ctx context.Context, entryID int64, isReadability bool) (*model.AISummary, error) {
+func (m *MockAIService) GetCachedSummary(ctx context.Context, entryID int64, isReadability bool, language string) (*model.AISummary, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetCachedSummary", ctx, entryID, isReadability)
+	ret := m.ctrl.Call(m, "GetCachedSummary", ctx, entryID, isReadability, language)
 	ret0, _ := ret[0].(*model.AISummary)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetCachedSummary indicates an expected call of GetCachedSummary.
-func (mr *MockAIServiceMockRecorder) GetCachedSummary(ctx, entryID, isReadability any) *gomock.Call {
+func (mr *MockAIServiceMockRecorder) GetCachedSummary(ctx, entryID, isReadability, language any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCachedSummary", reflect.TypeOf((*MockAIService)(nil).GetCachedSummary), ctx, entryID, isReadability)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCachedSummary", reflect.TypeOf((*MockAIService)(nil).GetCachedSummary), ctx, entryID, isReadability, language)
 }
 
 // GetCachedTranslation mocks base method.
-func (m *MockAIService) GetCachedTranslation(ctx context.Context, entryID int64, isReadability bool) (*model.AITranslation, error) {
+func (m *MockAIService) GetCachedTranslation(ctx context.Context, entryID int64, isReadability bool, language string) (*model.AITranslation, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetCachedTranslation", ctx, entryID, isReadability)
+	ret := m.ctrl.Call(m, "GetCachedTranslation", ctx, entryID, isReadability, language)
 	ret0, _ := ret[0].(*model.AITranslation)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetCachedTranslation indicates an expected call of GetCachedTranslation.
-func (mr *MockAIServiceMockRecorder) GetCachedTranslation(ctx, entryID, isReadability any) *gomock.Call {
+func (mr *MockAIServiceMockRecorder) GetCachedTranslation(ctx, entryID, isReadability, language any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCachedTranslation", reflect.TypeOf((*MockAIService)(nil).GetCachedTranslation), ctx, entryID, isReadability)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCachedTranslation", reflect.TypeOf((*MockAIService)(nil).GetCachedTranslation), ctx, entryID, isReadability, language)
 }
 
 // GetSummaryLanguage mocks base method.
@@ -103,38 +148,67 @@ func (mr *MockAIServiceMockRecorder) GetSummaryLanguage(ctx any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSummaryLanguage", reflect.TypeOf((*MockAIService)(nil).GetSummaryLanguage), ctx)
 }
 
+// GetUsage mocks base method.
+func (m *MockAIService) GetUsage(ctx context.Context, params service.AIUsageParams) (*service.AIUsageReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUsage", ctx, params)
+	ret0, _ := ret[0].(*service.AIUsageReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUsage indicates an expected call of GetUsage.
+func (mr *MockAIServiceMockRecorder) GetUsage(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsage", reflect.TypeOf((*MockAIService)(nil).GetUsage), ctx, params)
+}
+
+// SaveDigest mocks base method.
+func (m *MockAIService) SaveDigest(ctx context.Context, params service.AIDigestParams, entries int, digest string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveDigest", ctx, params, entries, digest)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveDigest indicates an expected call of SaveDigest.
+func (mr *MockAIServiceMockRecorder) SaveDigest(ctx, params, entries, digest any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveDigest", reflect.TypeOf((*MockAIService)(nil).SaveDigest), ctx, params, entries, digest)
+}
+
 // SaveSummary mocks base method.
-func (m *MockAIService) SaveSummary(ctx context.Context, entryID int64, isReadability bool, summary string) error {
+func (m *MockAIService) SaveSummary(ctx context.Context, entryID int64, isReadability bool, language, summary string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SaveSummary", ctx, entryID, isReadability, summary)
+	ret := m.ctrl.Call(m, "SaveSummary", ctx, entryID, isReadability, language, summary)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // SaveSummary indicates an expected call of SaveSummary.
-func (mr *MockAIServiceMockRecorder) SaveSummary(ctx, entryID, isReadability, summary any) *gomock.Call {
+func (mr *MockAIServiceMockRecorder) SaveSummary(ctx, entryID, isReadability, language, summary any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveSummary", reflect.TypeOf((*MockAIService)(nil).SaveSummary), ctx, entryID, isReadability, summary)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveSummary", reflect.TypeOf((*MockAIService)(nil).SaveSummary), ctx, entryID, isReadability, language, summary)
 }
 
 // SaveTranslation mocks base method.
-func (m *MockAIService) SaveTranslation(ctx context.Context, entryID int64, isReadability bool, content string) error {
+func (m *MockAIService) SaveTranslation(ctx context.Context, entryID int64, isReadability bool, language, content string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SaveTranslation", ctx, entryID, isReadability, content)
+	ret := m.ctrl.Call(m, "SaveTranslation", ctx, entryID, isReadability, language, content)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // SaveTranslation indicates an expected call of SaveTranslation.
-func (mr *MockAIServiceMockRecorder) SaveTranslation(ctx, entryID, isReadability, content any) *gomock.Call {
+func (mr *MockAIServiceMockRecorder) SaveTranslation(ctx, entryID, isReadability, language, content any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveTranslation", reflect.TypeOf((*MockAIService)(nil).SaveTranslation), ctx, entryID, isReadability, content)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveTranslation", reflect.TypeOf((*MockAIService)(nil).SaveTranslation), ctx, entryID, isReadability, language, content)
 }
 
 // Summarize mocks base method.
-func (m *MockAIService) Summarize(ctx context.Context, entryID int64, content, title string, isReadability bool) (<-chan string, <-chan error, error) {
+func (m *MockAIService) Summarize(ctx context.Context, entryID int64, content, title string, isReadability bool, language string) (<-chan string, <-chan error, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Summarize", ctx, entryID, content, title, isReadability)
+	ret := m.ctrl.Call(m, "Summarize", ctx, entryID, content, title, isReadability, language)
 	ret0, _ := ret[0].(<-chan string)
 	ret1, _ := ret[1].(<-chan error)
 	ret2, _ := ret[2].(error)
@@ -142,15 +216,15 @@ func (m *MockAIService) Summarize(ctx context.Context, entryID int64, content, t
 }
 
 // Summarize indicates an expected call of Summarize.
-func (mr *MockAIServiceMockRecorder) Summarize(ctx, entryID, content, title, isReadability any) *gomock.Call {
+func (mr *MockAIServiceMockRecorder) Summarize(ctx, entryID, content, title, isReadability, language any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Summarize", reflect.TypeOf((*MockAIService)(nil).Summarize), ctx, entryID, content, title, isReadability)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Summarize", reflect.TypeOf((*MockAIService)(nil).Summarize), ctx, entryID, content, title, isReadability, language)
 }
 
 // TranslateBatch mocks base method.
-func (m *MockAIService) TranslateBatch(ctx context.Context, articles []service.BatchArticleInput) (<-chan service.BatchTranslateResult, <-chan error, error) {
+func (m *MockAIService) TranslateBatch(ctx context.Context, articles []service.BatchArticleInput, language string) (<-chan service.BatchTranslateResult, <-chan error, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "TranslateBatch", ctx, articles)
+	ret := m.ctrl.Call(m, "TranslateBatch", ctx, articles, language)
 	ret0, _ := ret[0].(<-chan service.BatchTranslateResult)
 	ret1, _ := ret[1].(<-chan error)
 	ret2, _ := ret[2].(error)
@@ -158,15 +232,15 @@ func (m *MockAIService) TranslateBatch(ctx context.Context, articles []service.B
 }
 
 // TranslateBatch indicates an expected call of TranslateBatch.
-func (mr *MockAIServiceMockRecorder) TranslateBatch(ctx, articles any) *gomock.Call {
+func (mr *MockAIServiceMockRecorder) TranslateBatch(ctx, articles, language any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TranslateBatch", reflect.TypeOf((*MockAIService)(nil).TranslateBatch), ctx, articles)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TranslateBatch", reflect.TypeOf((*MockAIService)(nil).TranslateBatch), ctx, articles, language)
 }
 
 // TranslateBlocks mocks base method.
-func (m *MockAIService) TranslateBlocks(ctx context.Context, entryID int64, content, title string, isReadability bool) ([]service.TranslateBlockInfo, <-chan service.TranslateBlockResult, <-chan error, error) {
+func (m *MockAIService) TranslateBlocks(ctx context.Context, entryID int64, content, title string, isReadability bool, language string) ([]service.TranslateBlockInfo, <-chan service.TranslateBlockResult, <-chan error, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "TranslateBlocks", ctx, entryID, content, title, isReadability)
+	ret := m.ctrl.Call(m, "TranslateBlocks", ctx, entryID, content, title, isReadability, language)
 	ret0, _ := ret[0].([]service.TranslateBlockInfo)
 	ret1, _ := ret[1].(<-chan service.TranslateBlockResult)
 	ret2, _ := ret[2].(<-chan error)
@@ -175,7 +249,22 @@ func (m *MockAIService) TranslateBlocks(ctx context.Context, entryID int64, cont
 }
 
 // TranslateBlocks indicates an expected call of TranslateBlocks.
-func (mr *MockAIServiceMockRecorder) TranslateBlocks(ctx, entryID, content, title, isReadability any) *gomock.Call {
+func (mr *MockAIServiceMockRecorder) TranslateBlocks(ctx, entryID, content, title, isReadability, language any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TranslateBlocks", reflect.TypeOf((*MockAIService)(nil).TranslateBlocks), ctx, entryID, content, title, isReadability, language)
+}
+
+// TranslateFeedTitles mocks base method.
+func (m *MockAIService) TranslateFeedTitles(ctx context.Context, feedIDs []int64, language string) ([]service.FeedTitleResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TranslateFeedTitles", ctx, feedIDs, language)
+	ret0, _ := ret[0].([]service.FeedTitleResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TranslateFeedTitles indicates an expected call of TranslateFeedTitles.
+func (mr *MockAIServiceMockRecorder) TranslateFeedTitles(ctx, feedIDs, language any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TranslateBlocks", reflect.TypeOf((*MockAIService)(nil).TranslateBlocks), ctx, entryID, content, title, isReadability)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TranslateFeedTitles", reflect.TypeOf((*MockAIService)(nil).TranslateFeedTitles), ctx, feedIDs, language)
 }
@@ -0,0 +1,103 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: api_token_service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=api_token_service.go -destination=mock/api_token_service.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	model "gist/backend/internal/model"
+	service "gist/backend/internal/service"
+	reflect "reflect"
+	time "time"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockAPITokenService is a mock of APITokenService interface.
+type MockAPITokenService struct {
+	ctrl     *gomock.Controller
+	recorder *MockAPITokenServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockAPITokenServiceMockRecorder is the mock recorder for MockAPITokenService.
+type MockAPITokenServiceMockRecorder struct {
+	mock *MockAPITokenService
+}
+
+// NewMockAPITokenService creates a new mock instance.
+func NewMockAPITokenService(ctrl *gomock.Controller) *MockAPITokenService {
+	mock := &MockAPITokenService{ctrl: ctrl}
+	mock.recorder = &MockAPITokenServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAPITokenService) EXPECT() *MockAPITokenServiceMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockAPITokenService) Create(ctx context.Context, name string, expiresAt *time.Time) (*service.CreatedAPIToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, name, expiresAt)
+	ret0, _ := ret[0].(*service.CreatedAPIToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockAPITokenServiceMockRecorder) Create(ctx, name, expiresAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockAPITokenService)(nil).Create), ctx, name, expiresAt)
+}
+
+// Delete mocks base method.
+func (m *MockAPITokenService) Delete(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockAPITokenServiceMockRecorder) Delete(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockAPITokenService)(nil).Delete), ctx, id)
+}
+
+// List mocks base method.
+func (m *MockAPITokenService) List(ctx context.Context) ([]model.APIToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx)
+	ret0, _ := ret[0].([]model.APIToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockAPITokenServiceMockRecorder) List(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockAPITokenService)(nil).List), ctx)
+}
+
+// Validate mocks base method.
+func (m *MockAPITokenService) Validate(ctx context.Context, token string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Validate", ctx, token)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Validate indicates an expected call of Validate.
+func (mr *MockAPITokenServiceMockRecorder) Validate(ctx, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Validate", reflect.TypeOf((*MockAPITokenService)(nil).Validate), ctx, token)
+}
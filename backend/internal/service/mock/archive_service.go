@@ -0,0 +1,81 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: archive_service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=archive_service.go -destination=mock/archive_service.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	model "gist/backend/internal/model"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockArchiveService is a mock of ArchiveService interface.
+type MockArchiveService struct {
+	ctrl     *gomock.Controller
+	recorder *MockArchiveServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockArchiveServiceMockRecorder is the mock recorder for MockArchiveService.
+type MockArchiveServiceMockRecorder struct {
+	mock *MockArchiveService
+}
+
+// NewMockArchiveService creates a new mock instance.
+func NewMockArchiveService(ctrl *gomock.Controller) *MockArchiveService {
+	mock := &MockArchiveService{ctrl: ctrl}
+	mock.recorder = &MockArchiveServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockArchiveService) EXPECT() *MockArchiveServiceMockRecorder {
+	return m.recorder
+}
+
+// Archive mocks base method.
+func (m *MockArchiveService) Archive(entryID int64) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Archive", entryID)
+}
+
+// Archive indicates an expected call of Archive.
+func (mr *MockArchiveServiceMockRecorder) Archive(entryID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Archive", reflect.TypeOf((*MockArchiveService)(nil).Archive), entryID)
+}
+
+// Close mocks base method.
+func (m *MockArchiveService) Close() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Close")
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockArchiveServiceMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockArchiveService)(nil).Close))
+}
+
+// ListFailed mocks base method.
+func (m *MockArchiveService) ListFailed(ctx context.Context) ([]model.FailedArchive, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListFailed", ctx)
+	ret0, _ := ret[0].([]model.FailedArchive)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListFailed indicates an expected call of ListFailed.
+func (mr *MockArchiveServiceMockRecorder) ListFailed(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListFailed", reflect.TypeOf((*MockArchiveService)(nil).ListFailed), ctx)
+}
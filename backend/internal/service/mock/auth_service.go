@@ -56,6 +56,35 @@ func (mr *MockAuthServiceMockRecorder) CheckUserExists(ctx any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckUserExists", reflect.TypeOf((*MockAuthService)(nil).CheckUserExists), ctx)
 }
 
+// DisableTwoFactor mocks base method.
+func (m *MockAuthService) DisableTwoFactor(ctx context.Context, password string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisableTwoFactor", ctx, password)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DisableTwoFactor indicates an expected call of DisableTwoFactor.
+func (mr *MockAuthServiceMockRecorder) DisableTwoFactor(ctx, password any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisableTwoFactor", reflect.TypeOf((*MockAuthService)(nil).DisableTwoFactor), ctx, password)
+}
+
+// EnableTwoFactor mocks base method.
+func (m *MockAuthService) EnableTwoFactor(ctx context.Context, code string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnableTwoFactor", ctx, code)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnableTwoFactor indicates an expected call of EnableTwoFactor.
+func (mr *MockAuthServiceMockRecorder) EnableTwoFactor(ctx, code any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnableTwoFactor", reflect.TypeOf((*MockAuthService)(nil).EnableTwoFactor), ctx, code)
+}
+
 // GetCurrentUser mocks base method.
 func (m *MockAuthService) GetCurrentUser(ctx context.Context) (*service.User, error) {
 	m.ctrl.T.Helper()
@@ -101,6 +130,21 @@ func (mr *MockAuthServiceMockRecorder) Register(ctx, username, nickname, email,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Register", reflect.TypeOf((*MockAuthService)(nil).Register), ctx, username, nickname, email, password)
 }
 
+// SetupTwoFactor mocks base method.
+func (m *MockAuthService) SetupTwoFactor(ctx context.Context) (*service.TwoFactorSetup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetupTwoFactor", ctx)
+	ret0, _ := ret[0].(*service.TwoFactorSetup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetupTwoFactor indicates an expected call of SetupTwoFactor.
+func (mr *MockAuthServiceMockRecorder) SetupTwoFactor(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetupTwoFactor", reflect.TypeOf((*MockAuthService)(nil).SetupTwoFactor), ctx)
+}
+
 // UpdateProfile mocks base method.
 func (m *MockAuthService) UpdateProfile(ctx context.Context, nickname, email, currentPassword, newPassword string) (*service.UpdateProfileResponse, error) {
 	m.ctrl.T.Helper()
@@ -130,3 +174,18 @@ func (mr *MockAuthServiceMockRecorder) ValidateToken(token any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateToken", reflect.TypeOf((*MockAuthService)(nil).ValidateToken), token)
 }
+
+// VerifyTwoFactor mocks base method.
+func (m *MockAuthService) VerifyTwoFactor(ctx context.Context, pendingToken, code string) (*service.AuthResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyTwoFactor", ctx, pendingToken, code)
+	ret0, _ := ret[0].(*service.AuthResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyTwoFactor indicates an expected call of VerifyTwoFactor.
+func (mr *MockAuthServiceMockRecorder) VerifyTwoFactor(ctx, pendingToken, code any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyTwoFactor", reflect.TypeOf((*MockAuthService)(nil).VerifyTwoFactor), ctx, pendingToken, code)
+}
@@ -0,0 +1,82 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: auto_translate_service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=auto_translate_service.go -destination=mock/auto_translate_service.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	service "gist/backend/internal/service"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockAutoTranslateService is a mock of AutoTranslateService interface.
+type MockAutoTranslateService struct {
+	ctrl     *gomock.Controller
+	recorder *MockAutoTranslateServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockAutoTranslateServiceMockRecorder is the mock recorder for MockAutoTranslateService.
+type MockAutoTranslateServiceMockRecorder struct {
+	mock *MockAutoTranslateService
+}
+
+// NewMockAutoTranslateService creates a new mock instance.
+func NewMockAutoTranslateService(ctrl *gomock.Controller) *MockAutoTranslateService {
+	mock := &MockAutoTranslateService{ctrl: ctrl}
+	mock.recorder = &MockAutoTranslateServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAutoTranslateService) EXPECT() *MockAutoTranslateServiceMockRecorder {
+	return m.recorder
+}
+
+// Close mocks base method.
+func (m *MockAutoTranslateService) Close() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Close")
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockAutoTranslateServiceMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockAutoTranslateService)(nil).Close))
+}
+
+// Run mocks base method.
+func (m *MockAutoTranslateService) Run(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Run", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Run indicates an expected call of Run.
+func (mr *MockAutoTranslateServiceMockRecorder) Run(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Run", reflect.TypeOf((*MockAutoTranslateService)(nil).Run), ctx)
+}
+
+// Status mocks base method.
+func (m *MockAutoTranslateService) Status(ctx context.Context) service.AutoTranslateStatus {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Status", ctx)
+	ret0, _ := ret[0].(service.AutoTranslateStatus)
+	return ret0
+}
+
+// Status indicates an expected call of Status.
+func (mr *MockAutoTranslateServiceMockRecorder) Status(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Status", reflect.TypeOf((*MockAutoTranslateService)(nil).Status), ctx)
+}
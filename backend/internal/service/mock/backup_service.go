@@ -0,0 +1,72 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: backup_service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=backup_service.go -destination=mock/backup_service.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	service "gist/backend/internal/service"
+	io "io"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockBackupService is a mock of BackupService interface.
+type MockBackupService struct {
+	ctrl     *gomock.Controller
+	recorder *MockBackupServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockBackupServiceMockRecorder is the mock recorder for MockBackupService.
+type MockBackupServiceMockRecorder struct {
+	mock *MockBackupService
+}
+
+// NewMockBackupService creates a new mock instance.
+func NewMockBackupService(ctrl *gomock.Controller) *MockBackupService {
+	mock := &MockBackupService{ctrl: ctrl}
+	mock.recorder = &MockBackupServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBackupService) EXPECT() *MockBackupServiceMockRecorder {
+	return m.recorder
+}
+
+// Export mocks base method.
+func (m *MockBackupService) Export(ctx context.Context, w io.Writer, opts service.BackupExportOptions) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Export", ctx, w, opts)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Export indicates an expected call of Export.
+func (mr *MockBackupServiceMockRecorder) Export(ctx, w, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Export", reflect.TypeOf((*MockBackupService)(nil).Export), ctx, w, opts)
+}
+
+// Import mocks base method.
+func (m *MockBackupService) Import(ctx context.Context, r io.Reader) (service.BackupImportResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Import", ctx, r)
+	ret0, _ := ret[0].(service.BackupImportResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Import indicates an expected call of Import.
+func (mr *MockBackupServiceMockRecorder) Import(ctx, r any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Import", reflect.TypeOf((*MockBackupService)(nil).Import), ctx, r)
+}
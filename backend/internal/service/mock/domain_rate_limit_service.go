@@ -84,6 +84,20 @@ func (mr *MockDomainRateLimitServiceMockRecorder) GetIntervalDuration(ctx, host
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIntervalDuration", reflect.TypeOf((*MockDomainRateLimitService)(nil).GetIntervalDuration), ctx, host)
 }
 
+// GetMaxConcurrent mocks base method.
+func (m *MockDomainRateLimitService) GetMaxConcurrent(ctx context.Context, host string) int {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMaxConcurrent", ctx, host)
+	ret0, _ := ret[0].(int)
+	return ret0
+}
+
+// GetMaxConcurrent indicates an expected call of GetMaxConcurrent.
+func (mr *MockDomainRateLimitServiceMockRecorder) GetMaxConcurrent(ctx, host any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMaxConcurrent", reflect.TypeOf((*MockDomainRateLimitService)(nil).GetMaxConcurrent), ctx, host)
+}
+
 // List mocks base method.
 func (m *MockDomainRateLimitService) List(ctx context.Context) ([]service.DomainRateLimitDTO, error) {
 	m.ctrl.T.Helper()
@@ -100,15 +114,15 @@ func (mr *MockDomainRateLimitServiceMockRecorder) List(ctx any) *gomock.Call {
 }
 
 // SetInterval mocks base method.
-func (m *MockDomainRateLimitService) SetInterval(ctx context.Context, host string, seconds int) error {
+func (m *MockDomainRateLimitService) SetInterval(ctx context.Context, host string, seconds, maxConcurrent int) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SetInterval", ctx, host, seconds)
+	ret := m.ctrl.Call(m, "SetInterval", ctx, host, seconds, maxConcurrent)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // SetInterval indicates an expected call of SetInterval.
-func (mr *MockDomainRateLimitServiceMockRecorder) SetInterval(ctx, host, seconds any) *gomock.Call {
+func (mr *MockDomainRateLimitServiceMockRecorder) SetInterval(ctx, host, seconds, maxConcurrent any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetInterval", reflect.TypeOf((*MockDomainRateLimitService)(nil).SetInterval), ctx, host, seconds)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetInterval", reflect.TypeOf((*MockDomainRateLimitService)(nil).SetInterval), ctx, host, seconds, maxConcurrent)
 }
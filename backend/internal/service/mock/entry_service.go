@@ -14,6 +14,7 @@ import (
 	model "gist/backend/internal/model"
 	service "gist/backend/internal/service"
 	reflect "reflect"
+	time "time"
 
 	gomock "go.uber.org/mock/gomock"
 )
@@ -72,6 +73,50 @@ func (mr *MockEntryServiceMockRecorder) ClearReadabilityCache(ctx any) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClearReadabilityCache", reflect.TypeOf((*MockEntryService)(nil).ClearReadabilityCache), ctx)
 }
 
+// Count mocks base method.
+func (m *MockEntryService) Count(ctx context.Context, params service.EntryListParams) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Count", ctx, params)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Count indicates an expected call of Count.
+func (mr *MockEntryServiceMockRecorder) Count(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Count", reflect.TypeOf((*MockEntryService)(nil).Count), ctx, params)
+}
+
+// DeleteNote mocks base method.
+func (m *MockEntryService) DeleteNote(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteNote", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteNote indicates an expected call of DeleteNote.
+func (mr *MockEntryServiceMockRecorder) DeleteNote(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteNote", reflect.TypeOf((*MockEntryService)(nil).DeleteNote), ctx, id)
+}
+
+// GetAdjacent mocks base method.
+func (m *MockEntryService) GetAdjacent(ctx context.Context, id int64, params service.EntryListParams, next bool) (*model.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAdjacent", ctx, id, params, next)
+	ret0, _ := ret[0].(*model.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAdjacent indicates an expected call of GetAdjacent.
+func (mr *MockEntryServiceMockRecorder) GetAdjacent(ctx, id, params, next any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAdjacent", reflect.TypeOf((*MockEntryService)(nil).GetAdjacent), ctx, id, params, next)
+}
+
 // GetByID mocks base method.
 func (m *MockEntryService) GetByID(ctx context.Context, id int64) (model.Entry, error) {
 	m.ctrl.T.Helper()
@@ -87,6 +132,111 @@ func (mr *MockEntryServiceMockRecorder) GetByID(ctx, id any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockEntryService)(nil).GetByID), ctx, id)
 }
 
+// GetByIDWithFeed mocks base method.
+func (m *MockEntryService) GetByIDWithFeed(ctx context.Context, id int64) (model.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByIDWithFeed", ctx, id)
+	ret0, _ := ret[0].(model.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByIDWithFeed indicates an expected call of GetByIDWithFeed.
+func (mr *MockEntryServiceMockRecorder) GetByIDWithFeed(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByIDWithFeed", reflect.TypeOf((*MockEntryService)(nil).GetByIDWithFeed), ctx, id)
+}
+
+// GetByIDs mocks base method.
+func (m *MockEntryService) GetByIDs(ctx context.Context, ids []int64, includeAI bool) ([]service.BulkEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByIDs", ctx, ids, includeAI)
+	ret0, _ := ret[0].([]service.BulkEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByIDs indicates an expected call of GetByIDs.
+func (mr *MockEntryServiceMockRecorder) GetByIDs(ctx, ids, includeAI any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByIDs", reflect.TypeOf((*MockEntryService)(nil).GetByIDs), ctx, ids, includeAI)
+}
+
+// GetClickStats mocks base method.
+func (m *MockEntryService) GetClickStats(ctx context.Context, days int) (*service.ClickStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetClickStats", ctx, days)
+	ret0, _ := ret[0].(*service.ClickStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetClickStats indicates an expected call of GetClickStats.
+func (mr *MockEntryServiceMockRecorder) GetClickStats(ctx, days any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClickStats", reflect.TypeOf((*MockEntryService)(nil).GetClickStats), ctx, days)
+}
+
+// GetDailyDigest mocks base method.
+func (m *MockEntryService) GetDailyDigest(ctx context.Context, params service.DailyDigestParams) (*service.DailyDigest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDailyDigest", ctx, params)
+	ret0, _ := ret[0].(*service.DailyDigest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDailyDigest indicates an expected call of GetDailyDigest.
+func (mr *MockEntryServiceMockRecorder) GetDailyDigest(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDailyDigest", reflect.TypeOf((*MockEntryService)(nil).GetDailyDigest), ctx, params)
+}
+
+// GetRawItem mocks base method.
+func (m *MockEntryService) GetRawItem(ctx context.Context, id int64) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRawItem", ctx, id)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRawItem indicates an expected call of GetRawItem.
+func (mr *MockEntryServiceMockRecorder) GetRawItem(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRawItem", reflect.TypeOf((*MockEntryService)(nil).GetRawItem), ctx, id)
+}
+
+// GetReadingStats mocks base method.
+func (m *MockEntryService) GetReadingStats(ctx context.Context, days int) (*service.ReadingStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReadingStats", ctx, days)
+	ret0, _ := ret[0].(*service.ReadingStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReadingStats indicates an expected call of GetReadingStats.
+func (mr *MockEntryServiceMockRecorder) GetReadingStats(ctx, days any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReadingStats", reflect.TypeOf((*MockEntryService)(nil).GetReadingStats), ctx, days)
+}
+
+// GetRecentCounts mocks base method.
+func (m *MockEntryService) GetRecentCounts(ctx context.Context) (service.RecentCounts, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRecentCounts", ctx)
+	ret0, _ := ret[0].(service.RecentCounts)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRecentCounts indicates an expected call of GetRecentCounts.
+func (mr *MockEntryServiceMockRecorder) GetRecentCounts(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecentCounts", reflect.TypeOf((*MockEntryService)(nil).GetRecentCounts), ctx)
+}
+
 // GetStarredCount mocks base method.
 func (m *MockEntryService) GetStarredCount(ctx context.Context) (int, error) {
 	m.ctrl.T.Helper()
@@ -132,6 +282,51 @@ func (mr *MockEntryServiceMockRecorder) List(ctx, params any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockEntryService)(nil).List), ctx, params)
 }
 
+// ListArchiveFailures mocks base method.
+func (m *MockEntryService) ListArchiveFailures(ctx context.Context) ([]model.FailedArchive, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListArchiveFailures", ctx)
+	ret0, _ := ret[0].([]model.FailedArchive)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListArchiveFailures indicates an expected call of ListArchiveFailures.
+func (mr *MockEntryServiceMockRecorder) ListArchiveFailures(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListArchiveFailures", reflect.TypeOf((*MockEntryService)(nil).ListArchiveFailures), ctx)
+}
+
+// ListDeleted mocks base method.
+func (m *MockEntryService) ListDeleted(ctx context.Context, since time.Time, feedID *int64) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDeleted", ctx, since, feedID)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDeleted indicates an expected call of ListDeleted.
+func (mr *MockEntryServiceMockRecorder) ListDeleted(ctx, since, feedID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDeleted", reflect.TypeOf((*MockEntryService)(nil).ListDeleted), ctx, since, feedID)
+}
+
+// ListRevisions mocks base method.
+func (m *MockEntryService) ListRevisions(ctx context.Context, id int64) ([]service.EntryRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRevisions", ctx, id)
+	ret0, _ := ret[0].([]service.EntryRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRevisions indicates an expected call of ListRevisions.
+func (mr *MockEntryServiceMockRecorder) ListRevisions(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRevisions", reflect.TypeOf((*MockEntryService)(nil).ListRevisions), ctx, id)
+}
+
 // MarkAllAsRead mocks base method.
 func (m *MockEntryService) MarkAllAsRead(ctx context.Context, feedID, folderID *int64, contentType *string) error {
 	m.ctrl.T.Helper()
@@ -187,3 +382,47 @@ func (mr *MockEntryServiceMockRecorder) MarkManyAsRead(ctx, ids, read any) *gomo
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkManyAsRead", reflect.TypeOf((*MockEntryService)(nil).MarkManyAsRead), ctx, ids, read)
 }
+
+// Search mocks base method.
+func (m *MockEntryService) Search(ctx context.Context, params service.EntrySearchParams) ([]model.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", ctx, params)
+	ret0, _ := ret[0].([]model.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Search indicates an expected call of Search.
+func (mr *MockEntryServiceMockRecorder) Search(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockEntryService)(nil).Search), ctx, params)
+}
+
+// SetNote mocks base method.
+func (m *MockEntryService) SetNote(ctx context.Context, id int64, note string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetNote", ctx, id, note)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetNote indicates an expected call of SetNote.
+func (mr *MockEntryServiceMockRecorder) SetNote(ctx, id, note any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetNote", reflect.TypeOf((*MockEntryService)(nil).SetNote), ctx, id, note)
+}
+
+// Visit mocks base method.
+func (m *MockEntryService) Visit(ctx context.Context, id int64, markRead bool) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Visit", ctx, id, markRead)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Visit indicates an expected call of Visit.
+func (mr *MockEntryServiceMockRecorder) Visit(ctx, id, markRead any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Visit", reflect.TypeOf((*MockEntryService)(nil).Visit), ctx, id, markRead)
+}
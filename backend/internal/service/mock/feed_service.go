@@ -14,7 +14,9 @@ import (
 	model "gist/backend/internal/model"
 	service "gist/backend/internal/service"
 	reflect "reflect"
+	time "time"
 
+	gofeed "github.com/mmcdole/gofeed"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -43,18 +45,33 @@ func (m *MockFeedService) EXPECT() *MockFeedServiceMockRecorder {
 }
 
 // Add mocks base method.
-func (m *MockFeedService) Add(ctx context.Context, feedURL string, folderID *int64, titleOverride, feedType string) (model.Feed, error) {
+func (m *MockFeedService) Add(ctx context.Context, feedURL string, folderID *int64, titleOverride, feedType string, backfill service.InitialBackfill) (model.Feed, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Add", ctx, feedURL, folderID, titleOverride, feedType)
+	ret := m.ctrl.Call(m, "Add", ctx, feedURL, folderID, titleOverride, feedType, backfill)
 	ret0, _ := ret[0].(model.Feed)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Add indicates an expected call of Add.
-func (mr *MockFeedServiceMockRecorder) Add(ctx, feedURL, folderID, titleOverride, feedType any) *gomock.Call {
+func (mr *MockFeedServiceMockRecorder) Add(ctx, feedURL, folderID, titleOverride, feedType, backfill any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockFeedService)(nil).Add), ctx, feedURL, folderID, titleOverride, feedType)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockFeedService)(nil).Add), ctx, feedURL, folderID, titleOverride, feedType, backfill)
+}
+
+// AddStatic mocks base method.
+func (m *MockFeedService) AddStatic(ctx context.Context, parsed *gofeed.Feed, folderID *int64, titleOverride, feedType string) (model.Feed, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddStatic", ctx, parsed, folderID, titleOverride, feedType)
+	ret0, _ := ret[0].(model.Feed)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddStatic indicates an expected call of AddStatic.
+func (mr *MockFeedServiceMockRecorder) AddStatic(ctx, parsed, folderID, titleOverride, feedType any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddStatic", reflect.TypeOf((*MockFeedService)(nil).AddStatic), ctx, parsed, folderID, titleOverride, feedType)
 }
 
 // AddWithoutFetch mocks base method.
@@ -73,6 +90,21 @@ func (mr *MockFeedServiceMockRecorder) AddWithoutFetch(ctx, feedURL, folderID, t
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddWithoutFetch", reflect.TypeOf((*MockFeedService)(nil).AddWithoutFetch), ctx, feedURL, folderID, titleOverride, feedType)
 }
 
+// CreateIngestToken mocks base method.
+func (m *MockFeedService) CreateIngestToken(ctx context.Context, feedID int64) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateIngestToken", ctx, feedID)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateIngestToken indicates an expected call of CreateIngestToken.
+func (mr *MockFeedServiceMockRecorder) CreateIngestToken(ctx, feedID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateIngestToken", reflect.TypeOf((*MockFeedService)(nil).CreateIngestToken), ctx, feedID)
+}
+
 // Delete mocks base method.
 func (m *MockFeedService) Delete(ctx context.Context, id int64) error {
 	m.ctrl.T.Helper()
@@ -101,6 +133,51 @@ func (mr *MockFeedServiceMockRecorder) DeleteBatch(ctx, ids any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBatch", reflect.TypeOf((*MockFeedService)(nil).DeleteBatch), ctx, ids)
 }
 
+// DetectOverlaps mocks base method.
+func (m *MockFeedService) DetectOverlaps(ctx context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DetectOverlaps", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DetectOverlaps indicates an expected call of DetectOverlaps.
+func (mr *MockFeedServiceMockRecorder) DetectOverlaps(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DetectOverlaps", reflect.TypeOf((*MockFeedService)(nil).DetectOverlaps), ctx)
+}
+
+// EffectiveConfigs mocks base method.
+func (m *MockFeedService) EffectiveConfigs(ctx context.Context, feeds []model.Feed) (map[int64]service.FeedConfig, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EffectiveConfigs", ctx, feeds)
+	ret0, _ := ret[0].(map[int64]service.FeedConfig)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EffectiveConfigs indicates an expected call of EffectiveConfigs.
+func (mr *MockFeedServiceMockRecorder) EffectiveConfigs(ctx, feeds any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EffectiveConfigs", reflect.TypeOf((*MockFeedService)(nil).EffectiveConfigs), ctx, feeds)
+}
+
+// GetActivityStats mocks base method.
+func (m *MockFeedService) GetActivityStats(ctx context.Context) ([]model.FeedActivityStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActivityStats", ctx)
+	ret0, _ := ret[0].([]model.FeedActivityStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActivityStats indicates an expected call of GetActivityStats.
+func (mr *MockFeedServiceMockRecorder) GetActivityStats(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActivityStats", reflect.TypeOf((*MockFeedService)(nil).GetActivityStats), ctx)
+}
+
 // List mocks base method.
 func (m *MockFeedService) List(ctx context.Context, folderID *int64) ([]model.Feed, error) {
 	m.ctrl.T.Helper()
@@ -116,6 +193,110 @@ func (mr *MockFeedServiceMockRecorder) List(ctx, folderID any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockFeedService)(nil).List), ctx, folderID)
 }
 
+// ListMutedAuthors mocks base method.
+func (m *MockFeedService) ListMutedAuthors(ctx context.Context, id int64) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListMutedAuthors", ctx, id)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListMutedAuthors indicates an expected call of ListMutedAuthors.
+func (mr *MockFeedServiceMockRecorder) ListMutedAuthors(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMutedAuthors", reflect.TypeOf((*MockFeedService)(nil).ListMutedAuthors), ctx, id)
+}
+
+// ListOverlaps mocks base method.
+func (m *MockFeedService) ListOverlaps(ctx context.Context) ([]service.FeedOverlap, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOverlaps", ctx)
+	ret0, _ := ret[0].([]service.FeedOverlap)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOverlaps indicates an expected call of ListOverlaps.
+func (mr *MockFeedServiceMockRecorder) ListOverlaps(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOverlaps", reflect.TypeOf((*MockFeedService)(nil).ListOverlaps), ctx)
+}
+
+// ListWithUnreadCounts mocks base method.
+func (m *MockFeedService) ListWithUnreadCounts(ctx context.Context) ([]model.FeedWithUnread, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWithUnreadCounts", ctx)
+	ret0, _ := ret[0].([]model.FeedWithUnread)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWithUnreadCounts indicates an expected call of ListWithUnreadCounts.
+func (mr *MockFeedServiceMockRecorder) ListWithUnreadCounts(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWithUnreadCounts", reflect.TypeOf((*MockFeedService)(nil).ListWithUnreadCounts), ctx)
+}
+
+// MergeOverlap mocks base method.
+func (m *MockFeedService) MergeOverlap(ctx context.Context, id, keepFeedID int64) (model.Feed, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MergeOverlap", ctx, id, keepFeedID)
+	ret0, _ := ret[0].(model.Feed)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MergeOverlap indicates an expected call of MergeOverlap.
+func (mr *MockFeedServiceMockRecorder) MergeOverlap(ctx, id, keepFeedID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MergeOverlap", reflect.TypeOf((*MockFeedService)(nil).MergeOverlap), ctx, id, keepFeedID)
+}
+
+// MuteAuthor mocks base method.
+func (m *MockFeedService) MuteAuthor(ctx context.Context, id int64, author string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MuteAuthor", ctx, id, author)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MuteAuthor indicates an expected call of MuteAuthor.
+func (mr *MockFeedServiceMockRecorder) MuteAuthor(ctx, id, author any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MuteAuthor", reflect.TypeOf((*MockFeedService)(nil).MuteAuthor), ctx, id, author)
+}
+
+// Patch mocks base method.
+func (m *MockFeedService) Patch(ctx context.Context, id int64, patch service.FeedPatch) (model.Feed, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Patch", ctx, id, patch)
+	ret0, _ := ret[0].(model.Feed)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Patch indicates an expected call of Patch.
+func (mr *MockFeedServiceMockRecorder) Patch(ctx, id, patch any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Patch", reflect.TypeOf((*MockFeedService)(nil).Patch), ctx, id, patch)
+}
+
+// Pause mocks base method.
+func (m *MockFeedService) Pause(ctx context.Context, id int64, until time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Pause", ctx, id, until)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Pause indicates an expected call of Pause.
+func (mr *MockFeedServiceMockRecorder) Pause(ctx, id, until any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Pause", reflect.TypeOf((*MockFeedService)(nil).Pause), ctx, id, until)
+}
+
 // Preview mocks base method.
 func (m *MockFeedService) Preview(ctx context.Context, feedURL string) (service.FeedPreview, error) {
 	m.ctrl.T.Helper()
@@ -131,19 +312,147 @@ func (mr *MockFeedServiceMockRecorder) Preview(ctx, feedURL any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Preview", reflect.TypeOf((*MockFeedService)(nil).Preview), ctx, feedURL)
 }
 
+// PurgeDeleted mocks base method.
+func (m *MockFeedService) PurgeDeleted(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeDeleted", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PurgeDeleted indicates an expected call of PurgeDeleted.
+func (mr *MockFeedServiceMockRecorder) PurgeDeleted(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeDeleted", reflect.TypeOf((*MockFeedService)(nil).PurgeDeleted), ctx)
+}
+
+// Reorder mocks base method.
+func (m *MockFeedService) Reorder(ctx context.Context, ids []int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reorder", ctx, ids)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Reorder indicates an expected call of Reorder.
+func (mr *MockFeedServiceMockRecorder) Reorder(ctx, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reorder", reflect.TypeOf((*MockFeedService)(nil).Reorder), ctx, ids)
+}
+
+// Restore mocks base method.
+func (m *MockFeedService) Restore(ctx context.Context, id int64) (model.Feed, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, id)
+	ret0, _ := ret[0].(model.Feed)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockFeedServiceMockRecorder) Restore(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockFeedService)(nil).Restore), ctx, id)
+}
+
+// UnmuteAuthor mocks base method.
+func (m *MockFeedService) UnmuteAuthor(ctx context.Context, id int64, author string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnmuteAuthor", ctx, id, author)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UnmuteAuthor indicates an expected call of UnmuteAuthor.
+func (mr *MockFeedServiceMockRecorder) UnmuteAuthor(ctx, id, author any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnmuteAuthor", reflect.TypeOf((*MockFeedService)(nil).UnmuteAuthor), ctx, id, author)
+}
+
+// Unpause mocks base method.
+func (m *MockFeedService) Unpause(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unpause", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Unpause indicates an expected call of Unpause.
+func (mr *MockFeedServiceMockRecorder) Unpause(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unpause", reflect.TypeOf((*MockFeedService)(nil).Unpause), ctx, id)
+}
+
 // Update mocks base method.
-func (m *MockFeedService) Update(ctx context.Context, id int64, title string, folderID *int64, summaryPromptReminder *string) (model.Feed, error) {
+func (m *MockFeedService) Update(ctx context.Context, id int64, title string, folderID *int64, summaryPromptReminder *string, maxEntries *int) (model.Feed, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", ctx, id, title, folderID, summaryPromptReminder)
+	ret := m.ctrl.Call(m, "Update", ctx, id, title, folderID, summaryPromptReminder, maxEntries)
 	ret0, _ := ret[0].(model.Feed)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockFeedServiceMockRecorder) Update(ctx, id, title, folderID, summaryPromptReminder any) *gomock.Call {
+func (mr *MockFeedServiceMockRecorder) Update(ctx, id, title, folderID, summaryPromptReminder, maxEntries any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockFeedService)(nil).Update), ctx, id, title, folderID, summaryPromptReminder, maxEntries)
+}
+
+// UpdateAssumeTimezone mocks base method.
+func (m *MockFeedService) UpdateAssumeTimezone(ctx context.Context, id int64, timezone *string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAssumeTimezone", ctx, id, timezone)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateAssumeTimezone indicates an expected call of UpdateAssumeTimezone.
+func (mr *MockFeedServiceMockRecorder) UpdateAssumeTimezone(ctx, id, timezone any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAssumeTimezone", reflect.TypeOf((*MockFeedService)(nil).UpdateAssumeTimezone), ctx, id, timezone)
+}
+
+// UpdateAutoReadability mocks base method.
+func (m *MockFeedService) UpdateAutoReadability(ctx context.Context, id int64, enabled *bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAutoReadability", ctx, id, enabled)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateAutoReadability indicates an expected call of UpdateAutoReadability.
+func (mr *MockFeedServiceMockRecorder) UpdateAutoReadability(ctx, id, enabled any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAutoReadability", reflect.TypeOf((*MockFeedService)(nil).UpdateAutoReadability), ctx, id, enabled)
+}
+
+// UpdateAutoTranslate mocks base method.
+func (m *MockFeedService) UpdateAutoTranslate(ctx context.Context, id int64, mode string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAutoTranslate", ctx, id, mode)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateAutoTranslate indicates an expected call of UpdateAutoTranslate.
+func (mr *MockFeedServiceMockRecorder) UpdateAutoTranslate(ctx, id, mode any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockFeedService)(nil).Update), ctx, id, title, folderID, summaryPromptReminder)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAutoTranslate", reflect.TypeOf((*MockFeedService)(nil).UpdateAutoTranslate), ctx, id, mode)
+}
+
+// UpdateDedupeKey mocks base method.
+func (m *MockFeedService) UpdateDedupeKey(ctx context.Context, id int64, dedupeKey string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateDedupeKey", ctx, id, dedupeKey)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateDedupeKey indicates an expected call of UpdateDedupeKey.
+func (mr *MockFeedServiceMockRecorder) UpdateDedupeKey(ctx, id, dedupeKey any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateDedupeKey", reflect.TypeOf((*MockFeedService)(nil).UpdateDedupeKey), ctx, id, dedupeKey)
 }
 
 // UpdateType mocks base method.
@@ -159,3 +468,31 @@ func (mr *MockFeedServiceMockRecorder) UpdateType(ctx, id, feedType any) *gomock
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateType", reflect.TypeOf((*MockFeedService)(nil).UpdateType), ctx, id, feedType)
 }
+
+// UpdateUserAgent mocks base method.
+func (m *MockFeedService) UpdateUserAgent(ctx context.Context, id int64, userAgent *string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserAgent", ctx, id, userAgent)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateUserAgent indicates an expected call of UpdateUserAgent.
+func (mr *MockFeedServiceMockRecorder) UpdateUserAgent(ctx, id, userAgent any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserAgent", reflect.TypeOf((*MockFeedService)(nil).UpdateUserAgent), ctx, id, userAgent)
+}
+
+// ValidateIngestToken mocks base method.
+func (m *MockFeedService) ValidateIngestToken(ctx context.Context, feedID int64, token string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ValidateIngestToken", ctx, feedID, token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ValidateIngestToken indicates an expected call of ValidateIngestToken.
+func (mr *MockFeedServiceMockRecorder) ValidateIngestToken(ctx, feedID, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateIngestToken", reflect.TypeOf((*MockFeedService)(nil).ValidateIngestToken), ctx, feedID, token)
+}
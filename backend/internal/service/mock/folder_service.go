@@ -41,6 +41,21 @@ func (m *MockFolderService) EXPECT() *MockFolderServiceMockRecorder {
 	return m.recorder
 }
 
+// ApplyRules mocks base method.
+func (m *MockFolderService) ApplyRules(ctx context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApplyRules", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ApplyRules indicates an expected call of ApplyRules.
+func (mr *MockFolderServiceMockRecorder) ApplyRules(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplyRules", reflect.TypeOf((*MockFolderService)(nil).ApplyRules), ctx)
+}
+
 // Create mocks base method.
 func (m *MockFolderService) Create(ctx context.Context, name string, parentID *int64, folderType string) (model.Folder, error) {
 	m.ctrl.T.Helper()
@@ -56,6 +71,21 @@ func (mr *MockFolderServiceMockRecorder) Create(ctx, name, parentID, folderType
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockFolderService)(nil).Create), ctx, name, parentID, folderType)
 }
 
+// CreateRule mocks base method.
+func (m *MockFolderService) CreateRule(ctx context.Context, rule model.FolderRule) (model.FolderRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRule", ctx, rule)
+	ret0, _ := ret[0].(model.FolderRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateRule indicates an expected call of CreateRule.
+func (mr *MockFolderServiceMockRecorder) CreateRule(ctx, rule any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRule", reflect.TypeOf((*MockFolderService)(nil).CreateRule), ctx, rule)
+}
+
 // Delete mocks base method.
 func (m *MockFolderService) Delete(ctx context.Context, id int64) error {
 	m.ctrl.T.Helper()
@@ -70,6 +100,20 @@ func (mr *MockFolderServiceMockRecorder) Delete(ctx, id any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockFolderService)(nil).Delete), ctx, id)
 }
 
+// DeleteRule mocks base method.
+func (m *MockFolderService) DeleteRule(ctx context.Context, folderID, ruleID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteRule", ctx, folderID, ruleID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteRule indicates an expected call of DeleteRule.
+func (mr *MockFolderServiceMockRecorder) DeleteRule(ctx, folderID, ruleID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRule", reflect.TypeOf((*MockFolderService)(nil).DeleteRule), ctx, folderID, ruleID)
+}
+
 // List mocks base method.
 func (m *MockFolderService) List(ctx context.Context) ([]model.Folder, error) {
 	m.ctrl.T.Helper()
@@ -85,6 +129,50 @@ func (mr *MockFolderServiceMockRecorder) List(ctx any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockFolderService)(nil).List), ctx)
 }
 
+// ListRules mocks base method.
+func (m *MockFolderService) ListRules(ctx context.Context, folderID int64) ([]model.FolderRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRules", ctx, folderID)
+	ret0, _ := ret[0].([]model.FolderRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRules indicates an expected call of ListRules.
+func (mr *MockFolderServiceMockRecorder) ListRules(ctx, folderID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRules", reflect.TypeOf((*MockFolderService)(nil).ListRules), ctx, folderID)
+}
+
+// Reorder mocks base method.
+func (m *MockFolderService) Reorder(ctx context.Context, ids []int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reorder", ctx, ids)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Reorder indicates an expected call of Reorder.
+func (mr *MockFolderServiceMockRecorder) Reorder(ctx, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reorder", reflect.TypeOf((*MockFolderService)(nil).Reorder), ctx, ids)
+}
+
+// Restore mocks base method.
+func (m *MockFolderService) Restore(ctx context.Context, id int64) (model.Folder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, id)
+	ret0, _ := ret[0].(model.Folder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockFolderServiceMockRecorder) Restore(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockFolderService)(nil).Restore), ctx, id)
+}
+
 // Update mocks base method.
 func (m *MockFolderService) Update(ctx context.Context, id int64, name string, parentID *int64) (model.Folder, error) {
 	m.ctrl.T.Helper()
@@ -100,6 +188,36 @@ func (mr *MockFolderServiceMockRecorder) Update(ctx, id, name, parentID any) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockFolderService)(nil).Update), ctx, id, name, parentID)
 }
 
+// UpdateFeedDefaults mocks base method.
+func (m *MockFolderService) UpdateFeedDefaults(ctx context.Context, id int64, defaults model.FeedDefaults) (model.Folder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateFeedDefaults", ctx, id, defaults)
+	ret0, _ := ret[0].(model.Folder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateFeedDefaults indicates an expected call of UpdateFeedDefaults.
+func (mr *MockFolderServiceMockRecorder) UpdateFeedDefaults(ctx, id, defaults any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateFeedDefaults", reflect.TypeOf((*MockFolderService)(nil).UpdateFeedDefaults), ctx, id, defaults)
+}
+
+// UpdateRule mocks base method.
+func (m *MockFolderService) UpdateRule(ctx context.Context, rule model.FolderRule) (model.FolderRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRule", ctx, rule)
+	ret0, _ := ret[0].(model.FolderRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateRule indicates an expected call of UpdateRule.
+func (mr *MockFolderServiceMockRecorder) UpdateRule(ctx, rule any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRule", reflect.TypeOf((*MockFolderService)(nil).UpdateRule), ctx, rule)
+}
+
 // UpdateType mocks base method.
 func (m *MockFolderService) UpdateType(ctx context.Context, id int64, folderType string) error {
 	m.ctrl.T.Helper()
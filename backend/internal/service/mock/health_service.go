@@ -0,0 +1,70 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: health_service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=health_service.go -destination=mock/health_service.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	service "gist/backend/internal/service"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockHealthService is a mock of HealthService interface.
+type MockHealthService struct {
+	ctrl     *gomock.Controller
+	recorder *MockHealthServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockHealthServiceMockRecorder is the mock recorder for MockHealthService.
+type MockHealthServiceMockRecorder struct {
+	mock *MockHealthService
+}
+
+// NewMockHealthService creates a new mock instance.
+func NewMockHealthService(ctrl *gomock.Controller) *MockHealthService {
+	mock := &MockHealthService{ctrl: ctrl}
+	mock.recorder = &MockHealthServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHealthService) EXPECT() *MockHealthServiceMockRecorder {
+	return m.recorder
+}
+
+// BuildInfo mocks base method.
+func (m *MockHealthService) BuildInfo() service.BuildInfo {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BuildInfo")
+	ret0, _ := ret[0].(service.BuildInfo)
+	return ret0
+}
+
+// BuildInfo indicates an expected call of BuildInfo.
+func (mr *MockHealthServiceMockRecorder) BuildInfo() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BuildInfo", reflect.TypeOf((*MockHealthService)(nil).BuildInfo))
+}
+
+// Ready mocks base method.
+func (m *MockHealthService) Ready(ctx context.Context) service.ReadinessReport {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Ready", ctx)
+	ret0, _ := ret[0].(service.ReadinessReport)
+	return ret0
+}
+
+// Ready indicates an expected call of Ready.
+func (mr *MockHealthServiceMockRecorder) Ready(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ready", reflect.TypeOf((*MockHealthService)(nil).Ready), ctx)
+}
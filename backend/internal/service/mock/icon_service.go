@@ -112,6 +112,21 @@ func (mr *MockIconServiceMockRecorder) FetchAndSaveIcon(ctx, feedImageURL, siteU
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchAndSaveIcon", reflect.TypeOf((*MockIconService)(nil).FetchAndSaveIcon), ctx, feedImageURL, siteURL)
 }
 
+// GenerateIcon mocks base method.
+func (m *MockIconService) GenerateIcon(siteURL, title string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GenerateIcon", siteURL, title)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GenerateIcon indicates an expected call of GenerateIcon.
+func (mr *MockIconServiceMockRecorder) GenerateIcon(siteURL, title any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateIcon", reflect.TypeOf((*MockIconService)(nil).GenerateIcon), siteURL, title)
+}
+
 // GetIconPath mocks base method.
 func (m *MockIconService) GetIconPath(filename string) string {
 	m.ctrl.T.Helper()
@@ -125,3 +140,18 @@ func (mr *MockIconServiceMockRecorder) GetIconPath(filename any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIconPath", reflect.TypeOf((*MockIconService)(nil).GetIconPath), filename)
 }
+
+// ResolveIcon mocks base method.
+func (m *MockIconService) ResolveIcon(filename string, size int) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveIcon", filename, size)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResolveIcon indicates an expected call of ResolveIcon.
+func (mr *MockIconServiceMockRecorder) ResolveIcon(filename, size any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveIcon", reflect.TypeOf((*MockIconService)(nil).ResolveIcon), filename, size)
+}
@@ -0,0 +1,95 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: image_cache_service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=image_cache_service.go -destination=mock/image_cache_service.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	model "gist/backend/internal/model"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockImageCacheService is a mock of ImageCacheService interface.
+type MockImageCacheService struct {
+	ctrl     *gomock.Controller
+	recorder *MockImageCacheServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockImageCacheServiceMockRecorder is the mock recorder for MockImageCacheService.
+type MockImageCacheServiceMockRecorder struct {
+	mock *MockImageCacheService
+}
+
+// NewMockImageCacheService creates a new mock instance.
+func NewMockImageCacheService(ctrl *gomock.Controller) *MockImageCacheService {
+	mock := &MockImageCacheService{ctrl: ctrl}
+	mock.recorder = &MockImageCacheServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockImageCacheService) EXPECT() *MockImageCacheServiceMockRecorder {
+	return m.recorder
+}
+
+// CacheEntries mocks base method.
+func (m *MockImageCacheService) CacheEntries(ctx context.Context, feed model.Feed, hashes []string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "CacheEntries", ctx, feed, hashes)
+}
+
+// CacheEntries indicates an expected call of CacheEntries.
+func (mr *MockImageCacheServiceMockRecorder) CacheEntries(ctx, feed, hashes any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CacheEntries", reflect.TypeOf((*MockImageCacheService)(nil).CacheEntries), ctx, feed, hashes)
+}
+
+// CollectGarbage mocks base method.
+func (m *MockImageCacheService) CollectGarbage(ctx context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CollectGarbage", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CollectGarbage indicates an expected call of CollectGarbage.
+func (mr *MockImageCacheServiceMockRecorder) CollectGarbage(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CollectGarbage", reflect.TypeOf((*MockImageCacheService)(nil).CollectGarbage), ctx)
+}
+
+// GetImagePath mocks base method.
+func (m *MockImageCacheService) GetImagePath(entryID int64, filename string) string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetImagePath", entryID, filename)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// GetImagePath indicates an expected call of GetImagePath.
+func (mr *MockImageCacheServiceMockRecorder) GetImagePath(entryID, filename any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetImagePath", reflect.TypeOf((*MockImageCacheService)(nil).GetImagePath), entryID, filename)
+}
+
+// RewriteEntries mocks base method.
+func (m *MockImageCacheService) RewriteEntries(ctx context.Context, feed model.Feed, entries []model.Entry) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RewriteEntries", ctx, feed, entries)
+}
+
+// RewriteEntries indicates an expected call of RewriteEntries.
+func (mr *MockImageCacheServiceMockRecorder) RewriteEntries(ctx, feed, entries any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RewriteEntries", reflect.TypeOf((*MockImageCacheService)(nil).RewriteEntries), ctx, feed, entries)
+}
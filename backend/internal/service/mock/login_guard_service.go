@@ -0,0 +1,95 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: login_guard_service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=login_guard_service.go -destination=mock/login_guard_service.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	model "gist/backend/internal/model"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockLoginGuardService is a mock of LoginGuardService interface.
+type MockLoginGuardService struct {
+	ctrl     *gomock.Controller
+	recorder *MockLoginGuardServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockLoginGuardServiceMockRecorder is the mock recorder for MockLoginGuardService.
+type MockLoginGuardServiceMockRecorder struct {
+	mock *MockLoginGuardService
+}
+
+// NewMockLoginGuardService creates a new mock instance.
+func NewMockLoginGuardService(ctrl *gomock.Controller) *MockLoginGuardService {
+	mock := &MockLoginGuardService{ctrl: ctrl}
+	mock.recorder = &MockLoginGuardServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLoginGuardService) EXPECT() *MockLoginGuardServiceMockRecorder {
+	return m.recorder
+}
+
+// Check mocks base method.
+func (m *MockLoginGuardService) Check(ip, identifier string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Check", ip, identifier)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Check indicates an expected call of Check.
+func (mr *MockLoginGuardServiceMockRecorder) Check(ip, identifier any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Check", reflect.TypeOf((*MockLoginGuardService)(nil).Check), ip, identifier)
+}
+
+// ListEvents mocks base method.
+func (m *MockLoginGuardService) ListEvents(ctx context.Context) ([]model.LoginEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEvents", ctx)
+	ret0, _ := ret[0].([]model.LoginEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEvents indicates an expected call of ListEvents.
+func (mr *MockLoginGuardServiceMockRecorder) ListEvents(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEvents", reflect.TypeOf((*MockLoginGuardService)(nil).ListEvents), ctx)
+}
+
+// RecordFailure mocks base method.
+func (m *MockLoginGuardService) RecordFailure(ctx context.Context, ip, userAgent, identifier string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RecordFailure", ctx, ip, userAgent, identifier)
+}
+
+// RecordFailure indicates an expected call of RecordFailure.
+func (mr *MockLoginGuardServiceMockRecorder) RecordFailure(ctx, ip, userAgent, identifier any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordFailure", reflect.TypeOf((*MockLoginGuardService)(nil).RecordFailure), ctx, ip, userAgent, identifier)
+}
+
+// RecordSuccess mocks base method.
+func (m *MockLoginGuardService) RecordSuccess(ctx context.Context, ip, userAgent, identifier string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RecordSuccess", ctx, ip, userAgent, identifier)
+}
+
+// RecordSuccess indicates an expected call of RecordSuccess.
+func (mr *MockLoginGuardServiceMockRecorder) RecordSuccess(ctx, ip, userAgent, identifier any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordSuccess", reflect.TypeOf((*MockLoginGuardService)(nil).RecordSuccess), ctx, ip, userAgent, identifier)
+}
@@ -0,0 +1,87 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: maintenance_service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=maintenance_service.go -destination=mock/maintenance_service.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	service "gist/backend/internal/service"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockMaintenanceService is a mock of MaintenanceService interface.
+type MockMaintenanceService struct {
+	ctrl     *gomock.Controller
+	recorder *MockMaintenanceServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockMaintenanceServiceMockRecorder is the mock recorder for MockMaintenanceService.
+type MockMaintenanceServiceMockRecorder struct {
+	mock *MockMaintenanceService
+}
+
+// NewMockMaintenanceService creates a new mock instance.
+func NewMockMaintenanceService(ctrl *gomock.Controller) *MockMaintenanceService {
+	mock := &MockMaintenanceService{ctrl: ctrl}
+	mock.recorder = &MockMaintenanceServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMaintenanceService) EXPECT() *MockMaintenanceServiceMockRecorder {
+	return m.recorder
+}
+
+// Compact mocks base method.
+func (m *MockMaintenanceService) Compact(ctx context.Context, vacuum bool) (service.CompactResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Compact", ctx, vacuum)
+	ret0, _ := ret[0].(service.CompactResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Compact indicates an expected call of Compact.
+func (mr *MockMaintenanceServiceMockRecorder) Compact(ctx, vacuum any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Compact", reflect.TypeOf((*MockMaintenanceService)(nil).Compact), ctx, vacuum)
+}
+
+// Run mocks base method.
+func (m *MockMaintenanceService) Run(ctx context.Context, action string) (service.MaintenanceResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Run", ctx, action)
+	ret0, _ := ret[0].(service.MaintenanceResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Run indicates an expected call of Run.
+func (mr *MockMaintenanceServiceMockRecorder) Run(ctx, action any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Run", reflect.TypeOf((*MockMaintenanceService)(nil).Run), ctx, action)
+}
+
+// Storage mocks base method.
+func (m *MockMaintenanceService) Storage(ctx context.Context) (service.StorageReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Storage", ctx)
+	ret0, _ := ret[0].(service.StorageReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Storage indicates an expected call of Storage.
+func (mr *MockMaintenanceServiceMockRecorder) Storage(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Storage", reflect.TypeOf((*MockMaintenanceService)(nil).Storage), ctx)
+}
@@ -58,16 +58,16 @@ func (mr *MockOPMLServiceMockRecorder) Export(ctx any) *gomock.Call {
 }
 
 // Import mocks base method.
-func (m *MockOPMLService) Import(ctx context.Context, reader io.Reader, onProgress func(service.ImportProgress)) (service.ImportResult, error) {
+func (m *MockOPMLService) Import(ctx context.Context, reader io.Reader, backfill service.InitialBackfill, onProgress func(service.ImportProgress)) (service.ImportResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Import", ctx, reader, onProgress)
+	ret := m.ctrl.Call(m, "Import", ctx, reader, backfill, onProgress)
 	ret0, _ := ret[0].(service.ImportResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Import indicates an expected call of Import.
-func (mr *MockOPMLServiceMockRecorder) Import(ctx, reader, onProgress any) *gomock.Call {
+func (mr *MockOPMLServiceMockRecorder) Import(ctx, reader, backfill, onProgress any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Import", reflect.TypeOf((*MockOPMLService)(nil).Import), ctx, reader, onProgress)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Import", reflect.TypeOf((*MockOPMLService)(nil).Import), ctx, reader, backfill, onProgress)
 }
@@ -11,6 +11,7 @@ package mock
 
 import (
 	context "context"
+	service "gist/backend/internal/service"
 	reflect "reflect"
 
 	gomock "go.uber.org/mock/gomock"
@@ -52,6 +53,21 @@ func (mr *MockReadabilityServiceMockRecorder) Close() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockReadabilityService)(nil).Close))
 }
 
+// FetchPage mocks base method.
+func (m *MockReadabilityService) FetchPage(ctx context.Context, pageURL string) (service.ReadablePage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchPage", ctx, pageURL)
+	ret0, _ := ret[0].(service.ReadablePage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchPage indicates an expected call of FetchPage.
+func (mr *MockReadabilityServiceMockRecorder) FetchPage(ctx, pageURL any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchPage", reflect.TypeOf((*MockReadabilityService)(nil).FetchPage), ctx, pageURL)
+}
+
 // FetchReadableContent mocks base method.
 func (m *MockReadabilityService) FetchReadableContent(ctx context.Context, entryID int64) (string, error) {
 	m.ctrl.T.Helper()
@@ -66,3 +82,18 @@ func (mr *MockReadabilityServiceMockRecorder) FetchReadableContent(ctx, entryID
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchReadableContent", reflect.TypeOf((*MockReadabilityService)(nil).FetchReadableContent), ctx, entryID)
 }
+
+// StartReadableContent mocks base method.
+func (m *MockReadabilityService) StartReadableContent(ctx context.Context, entryID int64) (service.ReadableResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartReadableContent", ctx, entryID)
+	ret0, _ := ret[0].(service.ReadableResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StartReadableContent indicates an expected call of StartReadableContent.
+func (mr *MockReadabilityServiceMockRecorder) StartReadableContent(ctx, entryID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartReadableContent", reflect.TypeOf((*MockReadabilityService)(nil).StartReadableContent), ctx, entryID)
+}
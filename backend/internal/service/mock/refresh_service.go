@@ -11,9 +11,11 @@ package mock
 
 import (
 	context "context"
+	model "gist/backend/internal/model"
 	service "gist/backend/internal/service"
 	reflect "reflect"
 
+	gofeed "github.com/mmcdole/gofeed"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -41,6 +43,63 @@ func (m *MockRefreshService) EXPECT() *MockRefreshServiceMockRecorder {
 	return m.recorder
 }
 
+// Close mocks base method.
+func (m *MockRefreshService) Close() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Close")
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockRefreshServiceMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockRefreshService)(nil).Close))
+}
+
+// GetArchiveBackfill mocks base method.
+func (m *MockRefreshService) GetArchiveBackfill(ctx context.Context, feedID int64) (*service.ArchiveBackfill, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetArchiveBackfill", ctx, feedID)
+	ret0, _ := ret[0].(*service.ArchiveBackfill)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetArchiveBackfill indicates an expected call of GetArchiveBackfill.
+func (mr *MockRefreshServiceMockRecorder) GetArchiveBackfill(ctx, feedID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetArchiveBackfill", reflect.TypeOf((*MockRefreshService)(nil).GetArchiveBackfill), ctx, feedID)
+}
+
+// GetFetchLog mocks base method.
+func (m *MockRefreshService) GetFetchLog(ctx context.Context, feedID int64) (service.FeedFetchLog, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFetchLog", ctx, feedID)
+	ret0, _ := ret[0].(service.FeedFetchLog)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFetchLog indicates an expected call of GetFetchLog.
+func (mr *MockRefreshServiceMockRecorder) GetFetchLog(ctx, feedID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFetchLog", reflect.TypeOf((*MockRefreshService)(nil).GetFetchLog), ctx, feedID)
+}
+
+// GetIngestReport mocks base method.
+func (m *MockRefreshService) GetIngestReport(ctx context.Context, feedID int64) (*service.IngestReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIngestReport", ctx, feedID)
+	ret0, _ := ret[0].(*service.IngestReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIngestReport indicates an expected call of GetIngestReport.
+func (mr *MockRefreshServiceMockRecorder) GetIngestReport(ctx, feedID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIngestReport", reflect.TypeOf((*MockRefreshService)(nil).GetIngestReport), ctx, feedID)
+}
+
 // GetRefreshStatus mocks base method.
 func (m *MockRefreshService) GetRefreshStatus() service.RefreshStatus {
 	m.ctrl.T.Helper()
@@ -55,6 +114,53 @@ func (mr *MockRefreshServiceMockRecorder) GetRefreshStatus() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRefreshStatus", reflect.TypeOf((*MockRefreshService)(nil).GetRefreshStatus))
 }
 
+// GetRun mocks base method.
+func (m *MockRefreshService) GetRun(ctx context.Context, id int64) (model.RefreshRun, []model.RefreshRunFeed, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRun", ctx, id)
+	ret0, _ := ret[0].(model.RefreshRun)
+	ret1, _ := ret[1].([]model.RefreshRunFeed)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetRun indicates an expected call of GetRun.
+func (mr *MockRefreshServiceMockRecorder) GetRun(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRun", reflect.TypeOf((*MockRefreshService)(nil).GetRun), ctx, id)
+}
+
+// IngestItems mocks base method.
+func (m *MockRefreshService) IngestItems(ctx context.Context, feedID int64, items []service.IngestItem) ([]service.IngestResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IngestItems", ctx, feedID, items)
+	ret0, _ := ret[0].([]service.IngestResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IngestItems indicates an expected call of IngestItems.
+func (mr *MockRefreshServiceMockRecorder) IngestItems(ctx, feedID, items any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IngestItems", reflect.TypeOf((*MockRefreshService)(nil).IngestItems), ctx, feedID, items)
+}
+
+// IngestStatic mocks base method.
+func (m *MockRefreshService) IngestStatic(ctx context.Context, feedID int64, parsed *gofeed.Feed) (int, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IngestStatic", ctx, feedID, parsed)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// IngestStatic indicates an expected call of IngestStatic.
+func (mr *MockRefreshServiceMockRecorder) IngestStatic(ctx, feedID, parsed any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IngestStatic", reflect.TypeOf((*MockRefreshService)(nil).IngestStatic), ctx, feedID, parsed)
+}
+
 // IsRefreshing mocks base method.
 func (m *MockRefreshService) IsRefreshing() bool {
 	m.ctrl.T.Helper()
@@ -69,18 +175,62 @@ func (mr *MockRefreshServiceMockRecorder) IsRefreshing() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsRefreshing", reflect.TypeOf((*MockRefreshService)(nil).IsRefreshing))
 }
 
+// ListRuns mocks base method.
+func (m *MockRefreshService) ListRuns(ctx context.Context) ([]model.RefreshRun, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRuns", ctx)
+	ret0, _ := ret[0].([]model.RefreshRun)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRuns indicates an expected call of ListRuns.
+func (mr *MockRefreshServiceMockRecorder) ListRuns(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRuns", reflect.TypeOf((*MockRefreshService)(nil).ListRuns), ctx)
+}
+
+// PollIntervals mocks base method.
+func (m *MockRefreshService) PollIntervals(ctx context.Context, feeds []model.Feed) map[int64]service.PollInterval {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PollIntervals", ctx, feeds)
+	ret0, _ := ret[0].(map[int64]service.PollInterval)
+	return ret0
+}
+
+// PollIntervals indicates an expected call of PollIntervals.
+func (mr *MockRefreshServiceMockRecorder) PollIntervals(ctx, feeds any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PollIntervals", reflect.TypeOf((*MockRefreshService)(nil).PollIntervals), ctx, feeds)
+}
+
+// Probe mocks base method.
+func (m *MockRefreshService) Probe(ctx context.Context, feedID int64, userAgent string) (service.FeedProbe, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Probe", ctx, feedID, userAgent)
+	ret0, _ := ret[0].(service.FeedProbe)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Probe indicates an expected call of Probe.
+func (mr *MockRefreshServiceMockRecorder) Probe(ctx, feedID, userAgent any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Probe", reflect.TypeOf((*MockRefreshService)(nil).Probe), ctx, feedID, userAgent)
+}
+
 // RefreshAll mocks base method.
-func (m *MockRefreshService) RefreshAll(ctx context.Context) error {
+func (m *MockRefreshService) RefreshAll(ctx context.Context, trigger string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "RefreshAll", ctx)
+	ret := m.ctrl.Call(m, "RefreshAll", ctx, trigger)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // RefreshAll indicates an expected call of RefreshAll.
-func (mr *MockRefreshServiceMockRecorder) RefreshAll(ctx any) *gomock.Call {
+func (mr *MockRefreshServiceMockRecorder) RefreshAll(ctx, trigger any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshAll", reflect.TypeOf((*MockRefreshService)(nil).RefreshAll), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshAll", reflect.TypeOf((*MockRefreshService)(nil).RefreshAll), ctx, trigger)
 }
 
 // RefreshFeed mocks base method.
@@ -110,3 +260,18 @@ func (mr *MockRefreshServiceMockRecorder) RefreshFeeds(ctx, feedIDs any) *gomock
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshFeeds", reflect.TypeOf((*MockRefreshService)(nil).RefreshFeeds), ctx, feedIDs)
 }
+
+// StartArchiveBackfill mocks base method.
+func (m *MockRefreshService) StartArchiveBackfill(ctx context.Context, feedID int64, opts service.ArchiveBackfillOptions) (service.ArchiveBackfill, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartArchiveBackfill", ctx, feedID, opts)
+	ret0, _ := ret[0].(service.ArchiveBackfill)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StartArchiveBackfill indicates an expected call of StartArchiveBackfill.
+func (mr *MockRefreshServiceMockRecorder) StartArchiveBackfill(ctx, feedID, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartArchiveBackfill", reflect.TypeOf((*MockRefreshService)(nil).StartArchiveBackfill), ctx, feedID, opts)
+}
@@ -0,0 +1,72 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: saved_service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=saved_service.go -destination=mock/saved_service.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	model "gist/backend/internal/model"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockSavedService is a mock of SavedService interface.
+type MockSavedService struct {
+	ctrl     *gomock.Controller
+	recorder *MockSavedServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockSavedServiceMockRecorder is the mock recorder for MockSavedService.
+type MockSavedServiceMockRecorder struct {
+	mock *MockSavedService
+}
+
+// NewMockSavedService creates a new mock instance.
+func NewMockSavedService(ctrl *gomock.Controller) *MockSavedService {
+	mock := &MockSavedService{ctrl: ctrl}
+	mock.recorder = &MockSavedServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSavedService) EXPECT() *MockSavedServiceMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockSavedService) Delete(ctx context.Context, entryID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, entryID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockSavedServiceMockRecorder) Delete(ctx, entryID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockSavedService)(nil).Delete), ctx, entryID)
+}
+
+// Save mocks base method.
+func (m *MockSavedService) Save(ctx context.Context, pageURL string) (model.Entry, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, pageURL)
+	ret0, _ := ret[0].(model.Entry)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockSavedServiceMockRecorder) Save(ctx, pageURL any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockSavedService)(nil).Save), ctx, pageURL)
+}
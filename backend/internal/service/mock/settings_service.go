@@ -158,6 +158,33 @@ func (mr *MockSettingsServiceMockRecorder) GetProxyURL(ctx any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProxyURL", reflect.TypeOf((*MockSettingsService)(nil).GetProxyURL), ctx)
 }
 
+// GetSecuritySettings mocks base method.
+func (m *MockSettingsService) GetSecuritySettings(ctx context.Context) (*service.SecuritySettings, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSecuritySettings", ctx)
+	ret0, _ := ret[0].(*service.SecuritySettings)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSecuritySettings indicates an expected call of GetSecuritySettings.
+func (mr *MockSettingsServiceMockRecorder) GetSecuritySettings(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSecuritySettings", reflect.TypeOf((*MockSettingsService)(nil).GetSecuritySettings), ctx)
+}
+
+// OnNetworkSettingsChange mocks base method.
+func (m *MockSettingsService) OnNetworkSettingsChange(fn func()) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "OnNetworkSettingsChange", fn)
+}
+
+// OnNetworkSettingsChange indicates an expected call of OnNetworkSettingsChange.
+func (mr *MockSettingsServiceMockRecorder) OnNetworkSettingsChange(fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OnNetworkSettingsChange", reflect.TypeOf((*MockSettingsService)(nil).OnNetworkSettingsChange), fn)
+}
+
 // SetAISettings mocks base method.
 func (m *MockSettingsService) SetAISettings(ctx context.Context, settings *service.AISettings) error {
 	m.ctrl.T.Helper()
@@ -214,6 +241,20 @@ func (mr *MockSettingsServiceMockRecorder) SetNetworkSettings(ctx, settings any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetNetworkSettings", reflect.TypeOf((*MockSettingsService)(nil).SetNetworkSettings), ctx, settings)
 }
 
+// SetSecuritySettings mocks base method.
+func (m *MockSettingsService) SetSecuritySettings(ctx context.Context, settings *service.SecuritySettings) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetSecuritySettings", ctx, settings)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetSecuritySettings indicates an expected call of SetSecuritySettings.
+func (mr *MockSettingsServiceMockRecorder) SetSecuritySettings(ctx, settings any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetSecuritySettings", reflect.TypeOf((*MockSettingsService)(nil).SetSecuritySettings), ctx, settings)
+}
+
 // TestAI mocks base method.
 func (m *MockSettingsService) TestAI(ctx context.Context, provider, apiKey, baseURL, model string, requestOptions map[string]any) (string, error) {
 	m.ctrl.T.Helper()
@@ -0,0 +1,56 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: thumbnail_service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=thumbnail_service.go -destination=mock/thumbnail_service.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockThumbnailService is a mock of ThumbnailService interface.
+type MockThumbnailService struct {
+	ctrl     *gomock.Controller
+	recorder *MockThumbnailServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockThumbnailServiceMockRecorder is the mock recorder for MockThumbnailService.
+type MockThumbnailServiceMockRecorder struct {
+	mock *MockThumbnailService
+}
+
+// NewMockThumbnailService creates a new mock instance.
+func NewMockThumbnailService(ctrl *gomock.Controller) *MockThumbnailService {
+	mock := &MockThumbnailService{ctrl: ctrl}
+	mock.recorder = &MockThumbnailServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockThumbnailService) EXPECT() *MockThumbnailServiceMockRecorder {
+	return m.recorder
+}
+
+// Thumbnail mocks base method.
+func (m *MockThumbnailService) Thumbnail(ctx context.Context, entryID int64, width int) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Thumbnail", ctx, entryID, width)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Thumbnail indicates an expected call of Thumbnail.
+func (mr *MockThumbnailServiceMockRecorder) Thumbnail(ctx, entryID, width any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Thumbnail", reflect.TypeOf((*MockThumbnailService)(nil).Thumbnail), ctx, entryID, width)
+}
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+
+	"gist/backend/internal/model"
+	"gist/backend/pkg/logger"
+)
+
+// postingIntervalSample is how many of a feed's newest entries its average
+// posting interval is taken from.
+const postingIntervalSample = 20
+
+// overdueFactor is how many average posting intervals may pass without a new
+// entry before a feed that keeps answering 304 is considered overdue.
+const overdueFactor = 2
+
+// dueUnconditionalFetch reports whether feed has answered 304 Not Modified
+// often enough, while its posting history says new entries should exist, that
+// its validators are no longer trusted for the next fetch.
+func (s *refreshService) dueUnconditionalFetch(ctx context.Context, feed model.Feed) bool {
+	limit := DefaultUnconditionalFetchAfter
+	if general := loadGeneralSettings(ctx, s.settings); general != nil && general.UnconditionalFetchAfter > 0 {
+		limit = general.UnconditionalFetchAfter
+	}
+	if feed.NotModifiedStreak < limit || feed.LastEntrySeenAt == nil {
+		return false
+	}
+	if (feed.ETag == nil || *feed.ETag == "") && (feed.LastModified == nil || *feed.LastModified == "") {
+		return false
+	}
+
+	interval, err := s.feeds.PostingInterval(ctx, feed.ID, postingIntervalSample)
+	if err != nil {
+		logger.Warn("feed posting interval failed", "module", "service", "action", "refresh", "resource", "feed", "result", "failed", "feed_id", feed.ID, "error", err)
+		return false
+	}
+	return interval > 0 && time.Since(*feed.LastEntrySeenAt) > overdueFactor*interval
+}
+
+// recordNotModified counts one more 304 in a row for feed.
+func (s *refreshService) recordNotModified(ctx context.Context, feed model.Feed) {
+	if err := s.feeds.UpdateNotModifiedStreak(ctx, feed.ID, feed.NotModifiedStreak+1); err != nil {
+		logger.Warn("update feed not modified streak failed", "module", "service", "action", "update", "resource", "feed", "result", "failed", "feed_id", feed.ID, "error", err)
+	}
+}
+
+// recordModified ends the 304 streak of feed after a full response, remembers
+// its newest entry, and flags or clears suspect caching: an unconditional fetch
+// finding new entries means the validators had hidden them, while a
+// conditional one finding new entries shows they work again.
+func (s *refreshService) recordModified(ctx context.Context, feed model.Feed, parsed *gofeed.Feed, newCount int, unconditional bool) {
+	if feed.NotModifiedStreak > 0 {
+		if err := s.feeds.UpdateNotModifiedStreak(ctx, feed.ID, 0); err != nil {
+			logger.Warn("update feed not modified streak failed", "module", "service", "action", "update", "resource", "feed", "result", "failed", "feed_id", feed.ID, "error", err)
+		}
+	}
+	if seenAt := latestItemTime(parsed.Items); seenAt != nil {
+		if err := s.feeds.UpdateLastEntrySeenAt(ctx, feed.ID, *seenAt); err != nil {
+			logger.Warn("update feed last entry seen failed", "module", "service", "action", "update", "resource", "feed", "result", "failed", "feed_id", feed.ID, "error", err)
+		}
+	}
+
+	if newCount == 0 {
+		return
+	}
+	switch {
+	case unconditional:
+		now := time.Now().UTC()
+		logger.Warn("suspect caching upstream", "module", "service", "action", "refresh", "resource", "feed", "result", "failed", "feed_id", feed.ID, "feed_title", feed.Title, "not_modified_streak", feed.NotModifiedStreak, "new", newCount)
+		if err := s.feeds.UpdateSuspectCaching(ctx, feed.ID, &now); err != nil {
+			logger.Warn("flag feed suspect caching failed", "module", "service", "action", "update", "resource", "feed", "result", "failed", "feed_id", feed.ID, "error", err)
+		}
+	case feed.SuspectCachingAt != nil:
+		if err := s.feeds.UpdateSuspectCaching(ctx, feed.ID, nil); err != nil {
+			logger.Warn("clear feed suspect caching failed", "module", "service", "action", "update", "resource", "feed", "result", "failed", "feed_id", feed.ID, "error", err)
+		}
+	}
+}
+
+// latestItemTime returns the newest publish time among items, falling back to
+// the update time of items without one. Times in the future are ignored.
+func latestItemTime(items []*gofeed.Item) *time.Time {
+	now := time.Now()
+	var latest *time.Time
+	for _, item := range items {
+		t := item.PublishedParsed
+		if t == nil {
+			t = item.UpdatedParsed
+		}
+		if t == nil || t.After(now) {
+			continue
+		}
+		if latest == nil || t.After(*latest) {
+			latest = t
+		}
+	}
+	if latest == nil {
+		return nil
+	}
+	utc := latest.UTC()
+	return &utc
+}
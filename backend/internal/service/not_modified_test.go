@@ -0,0 +1,155 @@
+package service_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"gist/backend/internal/model"
+	"gist/backend/internal/repository/mock"
+	"gist/backend/internal/service"
+	"gist/backend/pkg/network"
+)
+
+// staleFeed has answered 304 streak times in a row and last saw an entry ten
+// days ago.
+func staleFeed(streak int) model.Feed {
+	etag, lastModified := `"v1"`, "Mon, 01 Jan 2026 00:00:00 GMT"
+	seenAt := time.Now().Add(-10 * 24 * time.Hour)
+	return model.Feed{ID: 1, URL: "https://example.com/rss", Title: "Feed", ETag: &etag, LastModified: &lastModified, NotModifiedStreak: streak, LastEntrySeenAt: &seenAt}
+}
+
+func notModifiedClient(t *testing.T, wantValidators bool) *http.Client {
+	return &http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			require.Equal(t, wantValidators, req.Header.Get("If-None-Match") != "")
+			require.Equal(t, wantValidators, req.Header.Get("If-Modified-Since") != "")
+			return &http.Response{StatusCode: http.StatusNotModified, Body: http.NoBody, Header: make(http.Header), Request: req}, nil
+		}),
+	}
+}
+
+func TestRefreshService_RefreshFeed_NotModifiedCountsStreak(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Two days without an entry are within twice the daily posting interval
+	feed := staleFeed(service.DefaultUnconditionalFetchAfter)
+	seenAt := time.Now().Add(-36 * time.Hour)
+	feed.LastEntrySeenAt = &seenAt
+	mockFeeds := mock.NewMockFeedRepository(ctrl)
+	mockFeeds.EXPECT().GetByID(gomock.Any(), int64(1)).Return(feed, nil)
+	mockFeeds.EXPECT().PostingInterval(gomock.Any(), int64(1), gomock.Any()).Return(24*time.Hour, nil)
+	mockFeeds.EXPECT().UpdateErrorMessage(gomock.Any(), int64(1), nil).Return(nil)
+	mockFeeds.EXPECT().UpdateLastFetchedAt(gomock.Any(), int64(1), gomock.Any()).Return(nil)
+	mockFeeds.EXPECT().UpdateNotModifiedStreak(gomock.Any(), int64(1), service.DefaultUnconditionalFetchAfter+1).Return(nil)
+
+	svc := service.NewRefreshService(mockFeeds, mock.NewMockEntryRepository(ctrl), nil, nil, nil, nil, network.NewClientFactoryForTest(notModifiedClient(t, true)), nil, nil, nil, nil, nil)
+	require.NoError(t, svc.RefreshFeed(context.Background(), 1))
+}
+
+func TestRefreshService_RefreshFeed_ShortStreakKeepsValidators(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFeeds := mock.NewMockFeedRepository(ctrl)
+	mockFeeds.EXPECT().GetByID(gomock.Any(), int64(1)).Return(staleFeed(3), nil)
+	mockFeeds.EXPECT().UpdateErrorMessage(gomock.Any(), int64(1), nil).Return(nil)
+	mockFeeds.EXPECT().UpdateLastFetchedAt(gomock.Any(), int64(1), gomock.Any()).Return(nil)
+	mockFeeds.EXPECT().UpdateNotModifiedStreak(gomock.Any(), int64(1), 4).Return(nil)
+
+	svc := service.NewRefreshService(mockFeeds, mock.NewMockEntryRepository(ctrl), nil, nil, nil, nil, network.NewClientFactoryForTest(notModifiedClient(t, true)), nil, nil, nil, nil, nil)
+	require.NoError(t, svc.RefreshFeed(context.Background(), 1))
+}
+
+func TestRefreshService_RefreshFeed_UnconditionalFetchAfterSetting(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFeeds := mock.NewMockFeedRepository(ctrl)
+	mockFeeds.EXPECT().GetByID(gomock.Any(), int64(1)).Return(staleFeed(3), nil)
+	mockFeeds.EXPECT().PostingInterval(gomock.Any(), int64(1), gomock.Any()).Return(24*time.Hour, nil)
+	mockFeeds.EXPECT().UpdateErrorMessage(gomock.Any(), int64(1), nil).Return(nil)
+	mockFeeds.EXPECT().UpdateLastFetchedAt(gomock.Any(), int64(1), gomock.Any()).Return(nil)
+	mockFeeds.EXPECT().UpdateNotModifiedStreak(gomock.Any(), int64(1), 4).Return(nil)
+
+	settings := &settingsServiceStub{general: &service.GeneralSettings{UnconditionalFetchAfter: 3}}
+	svc := service.NewRefreshService(mockFeeds, mock.NewMockEntryRepository(ctrl), nil, settings, nil, nil, network.NewClientFactoryForTest(notModifiedClient(t, false)), nil, nil, nil, nil, nil)
+	require.NoError(t, svc.RefreshFeed(context.Background(), 1))
+}
+
+func TestRefreshService_RefreshFeed_UnconditionalFetchFlagsSuspectCaching(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	published := time.Now().Add(-time.Hour).UTC().Truncate(time.Second)
+	body := fmt.Sprintf(`<rss version="2.0"><channel><title>Feed</title><link>https://example.com</link>
+<item><title>New</title><link>https://example.com/new</link><pubDate>%s</pubDate></item>
+</channel></rss>`, published.Format(time.RFC1123Z))
+
+	mockFeeds := mock.NewMockFeedRepository(ctrl)
+	mockEntries := mock.NewMockEntryRepository(ctrl)
+	mockFeeds.EXPECT().GetByID(gomock.Any(), int64(1)).Return(staleFeed(service.DefaultUnconditionalFetchAfter), nil)
+	mockFeeds.EXPECT().PostingInterval(gomock.Any(), int64(1), gomock.Any()).Return(24*time.Hour, nil)
+	mockFeeds.EXPECT().UpdateErrorMessage(gomock.Any(), int64(1), nil).Return(nil)
+	mockFeeds.EXPECT().UpdateLastFetchedAt(gomock.Any(), int64(1), gomock.Any()).Return(nil)
+	mockFeeds.EXPECT().UpdateTitles(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockFeeds.EXPECT().ListMutedAuthors(gomock.Any(), int64(1)).Return(nil, nil).AnyTimes()
+	mockFeeds.EXPECT().UpdateSiteURL(gomock.Any(), int64(1), "https://example.com").Return(nil)
+	mockEntries.EXPECT().SaveBatch(gomock.Any(), int64(1), gomock.Any(), gomock.Any()).Return(1, 0, nil)
+
+	mockFeeds.EXPECT().UpdateNotModifiedStreak(gomock.Any(), int64(1), 0).Return(nil)
+	mockFeeds.EXPECT().UpdateLastEntrySeenAt(gomock.Any(), int64(1), published).Return(nil)
+	mockFeeds.EXPECT().UpdateSuspectCaching(gomock.Any(), int64(1), gomock.Not(gomock.Nil())).Return(nil)
+
+	client := &http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			require.Empty(t, req.Header.Get("If-None-Match"))
+			require.Empty(t, req.Header.Get("If-Modified-Since"))
+			return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(body)), Header: make(http.Header), Request: req}, nil
+		}),
+	}
+
+	svc := service.NewRefreshService(mockFeeds, mockEntries, nil, nil, nil, nil, network.NewClientFactoryForTest(client), nil, nil, nil, nil, nil)
+	require.NoError(t, svc.RefreshFeed(context.Background(), 1))
+}
+
+func TestRefreshService_RefreshFeed_ConditionalFetchClearsSuspectCaching(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	flaggedAt := time.Now().Add(-time.Hour)
+	feed := staleFeed(0)
+	feed.SuspectCachingAt = &flaggedAt
+	body := `<rss version="2.0"><channel><title>Feed</title><link>https://example.com</link>
+<item><title>New</title><link>https://example.com/new</link></item>
+</channel></rss>`
+
+	mockFeeds := mock.NewMockFeedRepository(ctrl)
+	mockEntries := mock.NewMockEntryRepository(ctrl)
+	mockFeeds.EXPECT().GetByID(gomock.Any(), int64(1)).Return(feed, nil)
+	mockFeeds.EXPECT().UpdateErrorMessage(gomock.Any(), int64(1), nil).Return(nil)
+	mockFeeds.EXPECT().UpdateLastFetchedAt(gomock.Any(), int64(1), gomock.Any()).Return(nil)
+	mockFeeds.EXPECT().UpdateTitles(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockFeeds.EXPECT().ListMutedAuthors(gomock.Any(), int64(1)).Return(nil, nil).AnyTimes()
+	mockFeeds.EXPECT().UpdateSiteURL(gomock.Any(), int64(1), "https://example.com").Return(nil)
+	mockEntries.EXPECT().SaveBatch(gomock.Any(), int64(1), gomock.Any(), gomock.Any()).Return(1, 0, nil)
+	mockFeeds.EXPECT().UpdateSuspectCaching(gomock.Any(), int64(1), nil).Return(nil)
+
+	client := &http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			require.Equal(t, `"v1"`, req.Header.Get("If-None-Match"))
+			return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(body)), Header: make(http.Header), Request: req}, nil
+		}),
+	}
+
+	svc := service.NewRefreshService(mockFeeds, mockEntries, nil, nil, nil, nil, network.NewClientFactoryForTest(client), nil, nil, nil, nil, nil)
+	require.NoError(t, svc.RefreshFeed(context.Background(), 1))
+}
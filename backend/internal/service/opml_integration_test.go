@@ -63,12 +63,12 @@ func TestOPMLService_Integration_ImportAndExport(t *testing.T) {
 	dbConn := setupOPMLTestDB(t)
 	clientFactory := network.NewClientFactoryForTest(nil)
 
-	feedRepo := repository.NewFeedRepository(dbConn)
-	folderRepo := repository.NewFolderRepository(dbConn)
-	entryRepo := repository.NewEntryRepository(dbConn)
+	feedRepo := repository.NewFeedRepository(dbConn, nil, nil)
+	folderRepo := repository.NewFolderRepository(dbConn, nil, nil)
+	entryRepo := repository.NewEntryRepository(dbConn, nil, nil)
 
-	feedSvc := service.NewFeedService(feedRepo, folderRepo, entryRepo, nil, nil, clientFactory, nil)
-	folderSvc := service.NewFolderService(folderRepo, feedRepo)
+	feedSvc := service.NewFeedService(feedRepo, folderRepo, entryRepo, nil, nil, clientFactory, nil, nil, nil, nil)
+	folderSvc := service.NewFolderService(folderRepo, feedRepo, repository.NewFolderRuleRepository(dbConn), nil)
 	// Create OPML service with nil for optional dependencies
 	opmlSvc := service.NewOPMLService(folderSvc, feedSvc, nil, nil, folderRepo, feedRepo)
 
@@ -77,7 +77,7 @@ func TestOPMLService_Integration_ImportAndExport(t *testing.T) {
 
 	// Import OPML with progress callback
 	var progressCount int
-	result, err := opmlSvc.Import(ctx, strings.NewReader(testOPML), func(p service.ImportProgress) {
+	result, err := opmlSvc.Import(ctx, strings.NewReader(testOPML), service.InitialBackfill{}, func(p service.ImportProgress) {
 		progressCount++
 		t.Logf("Progress: %d/%d - %s (%s)", p.Current, p.Total, p.Feed, p.Status)
 	})
@@ -132,12 +132,12 @@ func TestOPMLService_Integration_ImportFromFile(t *testing.T) {
 	dbConn := setupOPMLTestDB(t)
 	clientFactory := network.NewClientFactoryForTest(nil)
 
-	feedRepo := repository.NewFeedRepository(dbConn)
-	folderRepo := repository.NewFolderRepository(dbConn)
-	entryRepo := repository.NewEntryRepository(dbConn)
+	feedRepo := repository.NewFeedRepository(dbConn, nil, nil)
+	folderRepo := repository.NewFolderRepository(dbConn, nil, nil)
+	entryRepo := repository.NewEntryRepository(dbConn, nil, nil)
 
-	feedSvc := service.NewFeedService(feedRepo, folderRepo, entryRepo, nil, nil, clientFactory, nil)
-	folderSvc := service.NewFolderService(folderRepo, feedRepo)
+	feedSvc := service.NewFeedService(feedRepo, folderRepo, entryRepo, nil, nil, clientFactory, nil, nil, nil, nil)
+	folderSvc := service.NewFolderService(folderRepo, feedRepo, repository.NewFolderRuleRepository(dbConn), nil)
 	opmlSvc := service.NewOPMLService(folderSvc, feedSvc, nil, nil, folderRepo, feedRepo)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Second)
@@ -149,7 +149,7 @@ func TestOPMLService_Integration_ImportFromFile(t *testing.T) {
 	defer file.Close()
 
 	// Import with nil progress callback for simplicity
-	result, err := opmlSvc.Import(ctx, file, nil)
+	result, err := opmlSvc.Import(ctx, file, service.InitialBackfill{}, nil)
 	require.NoError(t, err)
 
 	t.Logf("Import result: folders=%d created/%d skipped, feeds=%d created/%d skipped",
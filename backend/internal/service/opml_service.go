@@ -17,7 +17,9 @@ import (
 )
 
 type OPMLService interface {
-	Import(ctx context.Context, reader io.Reader, onProgress func(ImportProgress)) (ImportResult, error)
+	// Import creates the folders and feeds of an OPML document; backfill applies
+	// to the first refresh of every newly created feed.
+	Import(ctx context.Context, reader io.Reader, backfill InitialBackfill, onProgress func(ImportProgress)) (ImportResult, error)
 	Export(ctx context.Context) ([]byte, error)
 }
 
@@ -62,7 +64,7 @@ func NewOPMLService(
 	}
 }
 
-func (s *opmlService) Import(ctx context.Context, reader io.Reader, onProgress func(ImportProgress)) (ImportResult, error) {
+func (s *opmlService) Import(ctx context.Context, reader io.Reader, backfill InitialBackfill, onProgress func(ImportProgress)) (ImportResult, error) {
 	doc, err := opml.Parse(reader)
 	if err != nil {
 		return ImportResult{}, ErrInvalid
@@ -94,7 +96,7 @@ func (s *opmlService) Import(ctx context.Context, reader io.Reader, onProgress f
 		go func() {
 			bgCtx := context.Background()
 			if s.refreshService != nil {
-				_ = s.refreshService.RefreshFeeds(bgCtx, newFeedIDs)
+				_ = s.refreshService.RefreshFeeds(withInitialBackfill(bgCtx, backfill), newFeedIDs)
 			}
 			if s.iconService != nil {
 				_ = s.iconService.BackfillIcons(bgCtx)
@@ -362,8 +364,8 @@ func buildFolderOutline(node *folderNode) opml.Outline {
 
 func buildFeedOutline(feed model.Feed) opml.Outline {
 	outline := opml.Outline{
-		Text:   feed.Title,
-		Title:  feed.Title,
+		Text:   feed.DisplayTitle(),
+		Title:  feed.DisplayTitle(),
 		Type:   "rss",
 		XMLURL: feed.URL,
 	}
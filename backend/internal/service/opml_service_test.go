@@ -12,6 +12,7 @@ import (
 	"gist/backend/internal/service"
 	"gist/backend/pkg/opml"
 
+	"github.com/mmcdole/gofeed"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 )
@@ -31,7 +32,7 @@ func TestOPMLService_Import_Invalid(t *testing.T) {
 	defer ctrl.Finish()
 
 	svc := service.NewOPMLService(nil, nil, nil, nil, mock_repo.NewMockFolderRepository(ctrl), mock_repo.NewMockFeedRepository(ctrl))
-	_, err := svc.Import(context.Background(), strings.NewReader("<invalid"), nil)
+	_, err := svc.Import(context.Background(), strings.NewReader("<invalid"), service.InitialBackfill{}, nil)
 	require.ErrorIs(t, err, service.ErrInvalid)
 }
 
@@ -53,7 +54,7 @@ func TestOPMLService_Import_CreatesFoldersAndFeeds(t *testing.T) {
 	}
 
 	svc := service.NewOPMLService(folderService, feedService, refreshSvc, iconSvc, folderRepo, nil)
-	result, err := svc.Import(context.Background(), strings.NewReader(sampleOPML), onProgress)
+	result, err := svc.Import(context.Background(), strings.NewReader(sampleOPML), service.InitialBackfill{}, onProgress)
 	require.NoError(t, err)
 	require.Equal(t, 1, result.FoldersCreated)
 	require.Equal(t, 2, result.FeedsCreated)
@@ -102,7 +103,7 @@ func TestOPMLService_Import_EmptyFolderNameUsesUntitled(t *testing.T) {
 </opml>`
 
 	svc := service.NewOPMLService(folderService, feedService, nil, nil, folderRepo, nil)
-	_, err := svc.Import(context.Background(), strings.NewReader(input), nil)
+	_, err := svc.Import(context.Background(), strings.NewReader(input), service.InitialBackfill{}, nil)
 	require.NoError(t, err)
 	require.NotEmpty(t, folderService.created)
 	require.Equal(t, "Untitled", folderService.created[0].Name)
@@ -171,10 +172,42 @@ func (s *folderServiceStub) UpdateType(ctx context.Context, id int64, folderType
 	return nil
 }
 
+func (s *folderServiceStub) UpdateFeedDefaults(ctx context.Context, id int64, defaults model.FeedDefaults) (model.Folder, error) {
+	return model.Folder{}, nil
+}
+
+func (s *folderServiceStub) Reorder(ctx context.Context, ids []int64) error {
+	return nil
+}
+
 func (s *folderServiceStub) Delete(ctx context.Context, id int64) error {
 	return nil
 }
 
+func (s *folderServiceStub) Restore(ctx context.Context, id int64) (model.Folder, error) {
+	return model.Folder{}, nil
+}
+
+func (s *folderServiceStub) ListRules(ctx context.Context, folderID int64) ([]model.FolderRule, error) {
+	return nil, nil
+}
+
+func (s *folderServiceStub) CreateRule(ctx context.Context, rule model.FolderRule) (model.FolderRule, error) {
+	return rule, nil
+}
+
+func (s *folderServiceStub) UpdateRule(ctx context.Context, rule model.FolderRule) (model.FolderRule, error) {
+	return rule, nil
+}
+
+func (s *folderServiceStub) DeleteRule(ctx context.Context, folderID, ruleID int64) error {
+	return nil
+}
+
+func (s *folderServiceStub) ApplyRules(ctx context.Context) (int, error) {
+	return 0, nil
+}
+
 type feedServiceStub struct {
 	nextID int64
 	calls  []feedAddCall
@@ -185,7 +218,7 @@ type feedAddCall struct {
 	folderID *int64
 }
 
-func (s *feedServiceStub) Add(ctx context.Context, feedURL string, folderID *int64, titleOverride string, feedType string) (model.Feed, error) {
+func (s *feedServiceStub) Add(ctx context.Context, feedURL string, folderID *int64, titleOverride string, feedType string, backfill service.InitialBackfill) (model.Feed, error) {
 	return model.Feed{}, nil
 }
 
@@ -196,6 +229,18 @@ func (s *feedServiceStub) AddWithoutFetch(ctx context.Context, feedURL string, f
 	return feed, true, nil
 }
 
+func (s *feedServiceStub) AddStatic(ctx context.Context, parsed *gofeed.Feed, folderID *int64, titleOverride string, feedType string) (model.Feed, error) {
+	return model.Feed{}, nil
+}
+
+func (s *feedServiceStub) CreateIngestToken(ctx context.Context, feedID int64) (string, error) {
+	return "", nil
+}
+
+func (s *feedServiceStub) ValidateIngestToken(ctx context.Context, feedID int64, token string) error {
+	return nil
+}
+
 func (s *feedServiceStub) Preview(ctx context.Context, feedURL string) (service.FeedPreview, error) {
 	return service.FeedPreview{}, nil
 }
@@ -204,7 +249,35 @@ func (s *feedServiceStub) List(ctx context.Context, folderID *int64) ([]model.Fe
 	return nil, nil
 }
 
-func (s *feedServiceStub) Update(ctx context.Context, id int64, title string, folderID *int64, summaryPromptReminder *string) (model.Feed, error) {
+func (s *feedServiceStub) GetActivityStats(ctx context.Context) ([]model.FeedActivityStats, error) {
+	return nil, nil
+}
+
+func (s *feedServiceStub) Restore(ctx context.Context, id int64) (model.Feed, error) {
+	return model.Feed{}, nil
+}
+
+func (s *feedServiceStub) PurgeDeleted(ctx context.Context) error {
+	return nil
+}
+
+func (s *feedServiceStub) Patch(ctx context.Context, id int64, patch service.FeedPatch) (model.Feed, error) {
+	return model.Feed{}, nil
+}
+
+func (s *feedServiceStub) DetectOverlaps(ctx context.Context) (int, error) {
+	return 0, nil
+}
+
+func (s *feedServiceStub) ListOverlaps(ctx context.Context) ([]service.FeedOverlap, error) {
+	return nil, nil
+}
+
+func (s *feedServiceStub) MergeOverlap(ctx context.Context, id int64, keepFeedID int64) (model.Feed, error) {
+	return model.Feed{}, nil
+}
+
+func (s *feedServiceStub) Update(ctx context.Context, id int64, title string, folderID *int64, summaryPromptReminder *string, maxEntries *int) (model.Feed, error) {
 	return model.Feed{}, nil
 }
 
@@ -212,6 +285,58 @@ func (s *feedServiceStub) UpdateType(ctx context.Context, id int64, feedType str
 	return nil
 }
 
+func (s *feedServiceStub) UpdateAssumeTimezone(ctx context.Context, id int64, timezone *string) error {
+	return nil
+}
+
+func (s *feedServiceStub) Reorder(ctx context.Context, ids []int64) error {
+	return nil
+}
+
+func (s *feedServiceStub) UpdateDedupeKey(ctx context.Context, id int64, dedupeKey string) error {
+	return nil
+}
+
+func (s *feedServiceStub) ListWithUnreadCounts(ctx context.Context) ([]model.FeedWithUnread, error) {
+	return nil, nil
+}
+
+func (s *feedServiceStub) UpdateAutoTranslate(ctx context.Context, id int64, mode string) error {
+	return nil
+}
+
+func (s *feedServiceStub) UpdateUserAgent(ctx context.Context, id int64, userAgent *string) error {
+	return nil
+}
+
+func (s *feedServiceStub) UpdateAutoReadability(ctx context.Context, id int64, enabled *bool) error {
+	return nil
+}
+
+func (s *feedServiceStub) EffectiveConfigs(ctx context.Context, feeds []model.Feed) (map[int64]service.FeedConfig, error) {
+	return nil, nil
+}
+
+func (s *feedServiceStub) ListMutedAuthors(ctx context.Context, id int64) ([]string, error) {
+	return nil, nil
+}
+
+func (s *feedServiceStub) MuteAuthor(ctx context.Context, id int64, author string) ([]string, error) {
+	return nil, nil
+}
+
+func (s *feedServiceStub) UnmuteAuthor(ctx context.Context, id int64, author string) ([]string, error) {
+	return nil, nil
+}
+
+func (s *feedServiceStub) Pause(ctx context.Context, id int64, until time.Time) error {
+	return nil
+}
+
+func (s *feedServiceStub) Unpause(ctx context.Context, id int64) error {
+	return nil
+}
+
 func (s *feedServiceStub) Delete(ctx context.Context, id int64) error {
 	return nil
 }
@@ -224,7 +349,7 @@ type refreshServiceStub struct {
 	done chan []int64
 }
 
-func (s *refreshServiceStub) RefreshAll(ctx context.Context) error {
+func (s *refreshServiceStub) RefreshAll(ctx context.Context, trigger string) error {
 	return nil
 }
 
@@ -240,6 +365,14 @@ func (s *refreshServiceStub) RefreshFeeds(ctx context.Context, feedIDs []int64)
 	return nil
 }
 
+func (s *refreshServiceStub) IngestStatic(ctx context.Context, feedID int64, parsed *gofeed.Feed) (int, int, error) {
+	return 0, 0, nil
+}
+
+func (s *refreshServiceStub) IngestItems(ctx context.Context, feedID int64, items []service.IngestItem) ([]service.IngestResult, error) {
+	return nil, nil
+}
+
 func (s *refreshServiceStub) IsRefreshing() bool {
 	return false
 }
@@ -248,6 +381,40 @@ func (s *refreshServiceStub) GetRefreshStatus() service.RefreshStatus {
 	return service.RefreshStatus{}
 }
 
+func (s *refreshServiceStub) ListRuns(ctx context.Context) ([]model.RefreshRun, error) {
+	return nil, nil
+}
+
+func (s *refreshServiceStub) GetRun(ctx context.Context, id int64) (model.RefreshRun, []model.RefreshRunFeed, error) {
+	return model.RefreshRun{}, nil, service.ErrNotFound
+}
+
+func (s *refreshServiceStub) GetFetchLog(ctx context.Context, feedID int64) (service.FeedFetchLog, error) {
+	return service.FeedFetchLog{}, nil
+}
+
+func (s *refreshServiceStub) GetIngestReport(ctx context.Context, feedID int64) (*service.IngestReport, error) {
+	return nil, nil
+}
+
+func (s *refreshServiceStub) StartArchiveBackfill(ctx context.Context, feedID int64, opts service.ArchiveBackfillOptions) (service.ArchiveBackfill, error) {
+	return service.ArchiveBackfill{}, nil
+}
+
+func (s *refreshServiceStub) GetArchiveBackfill(ctx context.Context, feedID int64) (*service.ArchiveBackfill, error) {
+	return nil, nil
+}
+
+func (s *refreshServiceStub) Probe(ctx context.Context, feedID int64, userAgent string) (service.FeedProbe, error) {
+	return service.FeedProbe{}, nil
+}
+
+func (s *refreshServiceStub) PollIntervals(ctx context.Context, feeds []model.Feed) map[int64]service.PollInterval {
+	return nil
+}
+
+func (s *refreshServiceStub) Close() {}
+
 type iconServiceStub struct {
 	done chan struct{}
 }
@@ -256,6 +423,10 @@ func (s *iconServiceStub) FetchAndSaveIcon(ctx context.Context, feedImageURL, si
 	return "", nil
 }
 
+func (s *iconServiceStub) GenerateIcon(siteURL, title string) (string, error) {
+	return "", nil
+}
+
 func (s *iconServiceStub) EnsureIcon(ctx context.Context, iconPath, siteURL string) error {
 	return nil
 }
@@ -276,6 +447,10 @@ func (s *iconServiceStub) GetIconPath(filename string) string {
 	return ""
 }
 
+func (s *iconServiceStub) ResolveIcon(filename string, size int) (string, error) {
+	return "", nil
+}
+
 func (s *iconServiceStub) ClearAllIcons(ctx context.Context) (int64, error) {
 	return 0, nil
 }
@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+
+	"gist/backend/internal/model"
+	"gist/backend/pkg/logger"
+	"gist/backend/pkg/network"
+)
+
+// PollSourceHost marks a poll interval set by the domain rate limit of the feed's host.
+const PollSourceHost = "host"
+
+// maxPollHint caps the interval a feed can ask for, so one declaring a weekly
+// or yearly update period is still checked daily.
+const maxPollHint = 24 * time.Hour
+
+// pollIntervalSlack refreshes feeds that are due within this margin, so
+// scheduler drift of a few seconds doesn't push an hourly feed to the next run.
+const pollIntervalSlack = time.Minute
+
+// PollInterval is the shortest time between two bulk refreshes of a feed.
+type PollInterval struct {
+	Interval time.Duration
+	// Source is model.PollHintTTL, model.PollHintSyndication or PollSourceHost,
+	// and empty when the feed is refreshed on every run.
+	Source string
+}
+
+// syndicationPeriods maps sy:updatePeriod values to their length.
+var syndicationPeriods = map[string]time.Duration{
+	"hourly":  time.Hour,
+	"daily":   24 * time.Hour,
+	"weekly":  7 * 24 * time.Hour,
+	"monthly": 30 * 24 * time.Hour,
+	"yearly":  365 * 24 * time.Hour,
+}
+
+// feedPollHint returns the minimum refresh interval a feed declares through
+// RSS <ttl> or the syndication module, taking the longer when it has both.
+// It returns 0 and an empty source when the feed declares neither.
+func feedPollHint(parsed *gofeed.Feed) (time.Duration, string) {
+	var hint time.Duration
+	var source string
+
+	if minutes, err := strconv.Atoi(parsed.Custom[feedTTLKey]); err == nil && minutes > 0 {
+		hint, source = time.Duration(minutes)*time.Minute, model.PollHintTTL
+	}
+
+	sy := parsed.Extensions["sy"]
+	if len(sy["updatePeriod"]) > 0 || len(sy["updateFrequency"]) > 0 {
+		// The module defaults to once a day
+		period, frequency := syndicationPeriods["daily"], 1
+		if values := sy["updatePeriod"]; len(values) > 0 {
+			if p, ok := syndicationPeriods[strings.ToLower(strings.TrimSpace(values[0].Value))]; ok {
+				period = p
+			}
+		}
+		if values := sy["updateFrequency"]; len(values) > 0 {
+			if n, err := strconv.Atoi(strings.TrimSpace(values[0].Value)); err == nil && n > 0 {
+				frequency = n
+			}
+		}
+		if interval := period / time.Duration(frequency); interval > hint {
+			hint, source = interval, model.PollHintSyndication
+		}
+	}
+
+	return min(hint, maxPollHint), source
+}
+
+// updatePollHint stores the hint of a freshly parsed feed when it changed.
+func (s *refreshService) updatePollHint(ctx context.Context, feed model.Feed, parsed *gofeed.Feed) {
+	hint, source := feedPollHint(parsed)
+	seconds := int(hint / time.Second)
+	if seconds == feed.MinPollSeconds && source == feed.MinPollSource {
+		return
+	}
+	if err := s.feeds.UpdatePollHint(ctx, feed.ID, seconds, source); err != nil {
+		logger.Warn("update feed poll hint failed", "module", "service", "action", "update", "resource", "feed", "result", "failed", "feed_id", feed.ID, "error", err)
+		return
+	}
+	logger.Debug("feed poll hint updated", "module", "service", "action", "update", "resource", "feed", "result", "ok", "feed_id", feed.ID, "poll_seconds", seconds, "source", source)
+}
+
+func (s *refreshService) PollIntervals(ctx context.Context, feeds []model.Feed) map[int64]PollInterval {
+	hostIntervals := make(map[string]time.Duration)
+	intervals := make(map[int64]PollInterval, len(feeds))
+	for _, feed := range feeds {
+		interval := PollInterval{Interval: time.Duration(feed.MinPollSeconds) * time.Second}
+		if interval.Interval > 0 {
+			interval.Source = feed.MinPollSource
+		}
+		if host := network.ExtractHost(feed.URL); host != "" && s.rateLimitSvc != nil {
+			hostInterval, ok := hostIntervals[host]
+			if !ok {
+				hostInterval = s.rateLimitSvc.GetIntervalDuration(ctx, host)
+				hostIntervals[host] = hostInterval
+			}
+			if hostInterval > interval.Interval {
+				interval = PollInterval{Interval: hostInterval, Source: PollSourceHost}
+			}
+		}
+		intervals[feed.ID] = interval
+	}
+	return intervals
+}
+
+// withoutRecentlyFetched drops feeds fetched more recently than their poll
+// interval allows from a bulk refresh.
+func (s *refreshService) withoutRecentlyFetched(ctx context.Context, feeds []model.Feed, now time.Time) []model.Feed {
+	intervals := s.PollIntervals(ctx, feeds)
+	due := feeds[:0:0]
+	for _, feed := range feeds {
+		interval := intervals[feed.ID]
+		if feed.LastFetchedAt != nil && interval.Interval > 0 && now.Sub(*feed.LastFetchedAt) < interval.Interval-pollIntervalSlack {
+			logger.Debug("refresh skip recently fetched feed", "module", "service", "action", "refresh", "resource", "feed", "result", "skipped", "feed_id", feed.ID, "poll_seconds", int(interval.Interval/time.Second), "source", interval.Source)
+			continue
+		}
+		due = append(due, feed)
+	}
+	return due
+}
@@ -0,0 +1,154 @@
+package service_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"gist/backend/internal/model"
+	"gist/backend/internal/repository/mock"
+	"gist/backend/internal/service"
+	servicemock "gist/backend/internal/service/mock"
+	"gist/backend/pkg/network"
+)
+
+func TestFeedPollHint(t *testing.T) {
+	const sy = `xmlns:sy="http://purl.org/rss/1.0/modules/syndication/"`
+	tests := []struct {
+		name   string
+		doc    string
+		want   time.Duration
+		source string
+	}{
+		{
+			name: "no hints",
+			doc:  `<rss version="2.0"><channel><title>T</title></channel></rss>`,
+		},
+		{
+			name:   "ttl in minutes",
+			doc:    `<rss version="2.0"><channel><title>T</title><ttl>90</ttl></channel></rss>`,
+			want:   90 * time.Minute,
+			source: model.PollHintTTL,
+		},
+		{
+			name: "item ttl is not the channel's",
+			doc:  `<rss version="2.0"><channel><title>T</title><item><title>I</title><ttl>60</ttl></item></channel></rss>`,
+		},
+		{
+			name:   "syndication period over frequency",
+			doc:    `<rss version="2.0" ` + sy + `><channel><title>T</title><sy:updatePeriod>hourly</sy:updatePeriod><sy:updateFrequency>2</sy:updateFrequency></channel></rss>`,
+			want:   30 * time.Minute,
+			source: model.PollHintSyndication,
+		},
+		{
+			name:   "longer hint wins",
+			doc:    `<rss version="2.0" ` + sy + `><channel><title>T</title><ttl>30</ttl><sy:updatePeriod>hourly</sy:updatePeriod></channel></rss>`,
+			want:   time.Hour,
+			source: model.PollHintSyndication,
+		},
+		{
+			name:   "capped at a day",
+			doc:    `<feed xmlns="http://www.w3.org/2005/Atom" ` + sy + `><title>T</title><sy:updatePeriod>weekly</sy:updatePeriod></feed>`,
+			want:   24 * time.Hour,
+			source: model.PollHintSyndication,
+		},
+		{
+			name: "invalid ttl",
+			doc:  `<rss version="2.0"><channel><title>T</title><ttl>soon</ttl></channel></rss>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, err := service.ParseStaticFeed([]byte(tt.doc))
+			require.NoError(t, err)
+			hint, source := service.FeedPollHint(parsed)
+			require.Equal(t, tt.want, hint)
+			require.Equal(t, tt.source, source)
+		})
+	}
+}
+
+func TestRefreshService_RefreshAll_SkipsRecentlyFetched(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFeeds := mock.NewMockFeedRepository(ctrl)
+	now := time.Now()
+	recent := now.Add(-10 * time.Minute)
+	old := now.Add(-2 * time.Hour)
+	mockFeeds.EXPECT().List(gomock.Any(), nil).Return([]model.Feed{
+		{ID: 1, URL: "https://hinted.example.com/rss", MinPollSeconds: 3600, MinPollSource: model.PollHintTTL, LastFetchedAt: &recent},
+		{ID: 2, URL: "https://due.example.com/rss", MinPollSeconds: 3600, MinPollSource: model.PollHintTTL, LastFetchedAt: &old},
+		{ID: 3, URL: "https://plain.example.com/rss", LastFetchedAt: &recent},
+		// The host's rate limit counts as well
+		{ID: 4, URL: "https://slow.example.org/rss", LastFetchedAt: &recent},
+	}, nil)
+	for _, id := range []int64{2, 3} {
+		mockFeeds.EXPECT().UpdateErrorMessage(gomock.Any(), id, nil).Return(nil)
+		mockFeeds.EXPECT().UpdateLastFetchedAt(gomock.Any(), id, gomock.Any()).Return(nil)
+		mockFeeds.EXPECT().UpdateNotModifiedStreak(gomock.Any(), id, 1).Return(nil)
+	}
+
+	var hosts []string
+	client := &http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			hosts = append(hosts, req.URL.Host)
+			return &http.Response{StatusCode: http.StatusNotModified, Body: http.NoBody, Header: make(http.Header), Request: req}, nil
+		}),
+	}
+
+	rateLimits := servicemock.NewMockDomainRateLimitService(ctrl)
+	rateLimits.EXPECT().GetIntervalDuration(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, host string) time.Duration {
+			if host == "slow.example.org" {
+				return time.Hour
+			}
+			return 0
+		},
+	).AnyTimes()
+	rateLimits.EXPECT().GetMaxConcurrent(gomock.Any(), gomock.Any()).Return(0).AnyTimes()
+	svc := service.NewRefreshService(mockFeeds, mock.NewMockEntryRepository(ctrl), nil, nil, nil, nil, network.NewClientFactoryForTest(client), nil, rateLimits, nil, nil, nil)
+	require.NoError(t, svc.RefreshAll(context.Background(), model.RefreshTriggerManual))
+	require.ElementsMatch(t, []string{"due.example.com", "plain.example.com"}, hosts)
+
+	intervals := svc.PollIntervals(context.Background(), []model.Feed{
+		{ID: 1, URL: "https://hinted.example.com/rss", MinPollSeconds: 3600, MinPollSource: model.PollHintTTL},
+		{ID: 4, URL: "https://slow.example.org/rss", MinPollSeconds: 60, MinPollSource: model.PollHintTTL},
+	})
+	require.Equal(t, service.PollInterval{Interval: time.Hour, Source: model.PollHintTTL}, intervals[1])
+	require.Equal(t, service.PollInterval{Interval: time.Hour, Source: service.PollSourceHost}, intervals[4])
+}
+
+func TestRefreshService_RefreshFeed_StoresPollHint(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFeeds := mock.NewMockFeedRepository(ctrl)
+	mockFeeds.EXPECT().UpdateTitles(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockEntries := mock.NewMockEntryRepository(ctrl)
+	feed := model.Feed{ID: 1, URL: "https://example.com/rss"}
+	mockFeeds.EXPECT().GetByID(gomock.Any(), int64(1)).Return(feed, nil)
+	mockFeeds.EXPECT().UpdateErrorMessage(gomock.Any(), int64(1), nil).Return(nil)
+	mockFeeds.EXPECT().UpdatePollHint(gomock.Any(), int64(1), 7200, model.PollHintTTL).Return(nil)
+	mockFeeds.EXPECT().UpdateSiteURL(gomock.Any(), int64(1), "https://example.com").Return(nil)
+	mockFeeds.EXPECT().UpdateLastFetchedAt(gomock.Any(), int64(1), gomock.Any()).Return(nil)
+	mockFeeds.EXPECT().ListMutedAuthors(gomock.Any(), int64(1)).Return(nil, nil).AnyTimes()
+	mockEntries.EXPECT().SaveBatch(gomock.Any(), int64(1), gomock.Any(), gomock.Any()).Return(0, 0, nil)
+
+	body := `<rss version="2.0"><channel><title>T</title><link>https://example.com</link><ttl>120</ttl></channel></rss>`
+	client := &http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(body)), Header: make(http.Header), Request: req}, nil
+		}),
+	}
+
+	svc := service.NewRefreshService(mockFeeds, mockEntries, nil, nil, nil, nil, network.NewClientFactoryForTest(client), nil, nil, nil, nil, nil)
+	require.NoError(t, svc.RefreshFeed(context.Background(), 1))
+}
@@ -44,12 +44,15 @@ type ProxyService interface {
 type proxyService struct {
 	clientFactory *network.ClientFactory
 	anubis        AnubisSolver
+	// settings supplies the Anubis retry options; nil uses the defaults.
+	settings SettingsService
 }
 
-func NewProxyService(clientFactory *network.ClientFactory, anubisSolver AnubisSolver) ProxyService {
+func NewProxyService(clientFactory *network.ClientFactory, anubisSolver AnubisSolver, settings SettingsService) ProxyService {
 	return &proxyService{
 		clientFactory: clientFactory,
 		anubis:        anubisSolver,
+		settings:      settings,
 	}
 }
 
@@ -58,31 +61,49 @@ func (s *proxyService) Close() {
 }
 
 func (s *proxyService) FetchImage(ctx context.Context, imageURL, refererURL string) (*ProxyResult, error) {
-	return s.fetchImageWithRetry(ctx, imageURL, refererURL, "", 0)
-}
+	host := network.ExtractHost(imageURL)
+	attempt, retryCount, err := fetchPastAnubis(ctx, s.anubis, imageURL, anubisRetryOptionsFrom(ctx, s.settings), func(cookie string, _ bool) (anubisAttempt, error) {
+		// A new session per fetch avoids reusing the challenged connection
+		session := s.clientFactory.NewAzureSession(ctx, proxyTimeout)
+		defer session.Close()
+		return s.doFetch(ctx, session, imageURL, refererURL, cookie)
+	})
+	switch {
+	case errors.Is(err, ErrAnubisRejected):
+		logger.Warn("proxy upstream rejected", "module", "service", "action", "fetch", "resource", "proxy", "result", "failed", "host", host, "error", err)
+		return nil, ErrUpstreamRejected
+	case errors.Is(err, errAnubisRetryExceeded):
+		logger.Warn("proxy anubis persists", "module", "service", "action", "fetch", "resource", "proxy", "result", "failed", "host", host, "retry_count", retryCount)
+		return nil, fmt.Errorf("%w: %v", ErrFetchFailed, err)
+	case errors.Is(err, errAnubisSolveFailed):
+		logger.Warn("proxy anubis solve failed", "module", "service", "action", "fetch", "resource", "proxy", "result", "failed", "host", host, "error", err)
+		return nil, ErrFetchFailed
+	case err != nil:
+		return nil, err
+	}
 
-func (s *proxyService) fetchImageWithRetry(ctx context.Context, imageURL, refererURL, cookie string, retryCount int) (*ProxyResult, error) {
-	session := s.clientFactory.NewAzureSession(ctx, proxyTimeout)
-	defer session.Close()
-	return s.doFetch(ctx, session, imageURL, refererURL, cookie, retryCount)
-}
+	contentType, err := detectProxyImageContentType(attempt.body)
+	if err != nil {
+		logger.Warn("proxy invalid image", "module", "service", "action", "fetch", "resource", "proxy", "result", "failed", "host", host, "error", err)
+		return nil, err
+	}
 
-// fetchWithFreshSession creates a new azuretls session to avoid connection reuse after Anubis
-func (s *proxyService) fetchWithFreshSession(ctx context.Context, imageURL, refererURL, cookie string, retryCount int) (*ProxyResult, error) {
-	session := s.clientFactory.NewAzureSession(ctx, proxyTimeout)
-	defer session.Close()
-	return s.doFetch(ctx, session, imageURL, refererURL, cookie, retryCount)
+	return &ProxyResult{
+		Data:        attempt.body,
+		ContentType: contentType,
+	}, nil
 }
 
-// doFetch performs the actual HTTP request with the given session
-func (s *proxyService) doFetch(ctx context.Context, session *azuretls.Session, imageURL, refererURL, cookie string, retryCount int) (*ProxyResult, error) {
+// doFetch performs one HTTP request with the given session, sending the Anubis
+// cookie cached for the host when cookie is empty
+func (s *proxyService) doFetch(ctx context.Context, session *azuretls.Session, imageURL, refererURL, cookie string) (anubisAttempt, error) {
 	parsedURL, err := url.Parse(imageURL)
 	if err != nil {
-		return nil, ErrInvalidURL
+		return anubisAttempt{}, ErrInvalidURL
 	}
 
 	if parsedURL.Scheme != "http" && parsedURL.Scheme != "https" {
-		return nil, ErrInvalidProtocol
+		return anubisAttempt{}, ErrInvalidProtocol
 	}
 
 	// Build Referer
@@ -104,12 +125,11 @@ func (s *proxyService) doFetch(ctx context.Context, session *azuretls.Session, i
 
 	// Add cookie
 	requestHeaders := orderedHeadersToHTTPHeader(headers)
+	if cookie == "" {
+		cookie = getCachedAnubisCookie(ctx, s.anubis, parsedURL.Host, requestHeaders)
+	}
 	if cookie != "" {
 		headers = append(headers, []string{"cookie", cookie})
-	} else {
-		if cachedCookie := getCachedAnubisCookie(ctx, s.anubis, parsedURL.Host, requestHeaders); cachedCookie != "" {
-			headers = append(headers, []string{"cookie", cachedCookie})
-		}
 	}
 
 	resp, err := session.Do(&azuretls.Request{
@@ -119,43 +139,15 @@ func (s *proxyService) doFetch(ctx context.Context, session *azuretls.Session, i
 	})
 	if err != nil {
 		logger.Warn("proxy fetch failed", "module", "service", "action", "fetch", "resource", "proxy", "result", "failed", "host", parsedURL.Host, "error", err)
-		return nil, fmt.Errorf("%w: %v", ErrFetchFailed, err)
+		return anubisAttempt{}, fmt.Errorf("%w: %v", ErrFetchFailed, err)
 	}
 
 	if resp.StatusCode != http.StatusOK {
 		logger.Error("proxy http error", "module", "service", "action", "fetch", "resource", "proxy", "result", "failed", "host", parsedURL.Host, "status_code", resp.StatusCode)
-		return nil, fmt.Errorf("%w: %d", ErrFetchFailed, resp.StatusCode)
-	}
-
-	data := resp.Body
-
-	newCookie, anubisErr := trySolveAnubisChallenge(ctx, s.anubis, data, imageURL, cookiesFromMap(resp.Cookies), requestHeaders, retryCount)
-	switch {
-	case anubisErr == nil:
-		return s.fetchWithFreshSession(ctx, imageURL, refererURL, newCookie, retryCount+1)
-	case errors.Is(anubisErr, errAnubisNotPage):
-		// Not an Anubis page; continue normal proxy response handling.
-	case errors.Is(anubisErr, errAnubisRejected):
-		logger.Warn("proxy upstream rejected", "module", "service", "action", "fetch", "resource", "proxy", "result", "failed", "host", parsedURL.Host)
-		return nil, ErrUpstreamRejected
-	case errors.Is(anubisErr, errAnubisRetryExceeded):
-		logger.Warn("proxy anubis persists", "module", "service", "action", "fetch", "resource", "proxy", "result", "failed", "host", parsedURL.Host, "retry_count", retryCount)
-		return nil, fmt.Errorf("%w: anubis challenge persists after %d retries", ErrFetchFailed, retryCount)
-	default:
-		logger.Warn("proxy anubis solve failed", "module", "service", "action", "fetch", "resource", "proxy", "result", "failed", "host", parsedURL.Host, "error", anubisErr)
-		return nil, ErrFetchFailed
+		return anubisAttempt{}, fmt.Errorf("%w: %d", ErrFetchFailed, resp.StatusCode)
 	}
 
-	contentType, err := detectProxyImageContentType(data)
-	if err != nil {
-		logger.Warn("proxy invalid image", "module", "service", "action", "fetch", "resource", "proxy", "result", "failed", "host", parsedURL.Host, "error", err)
-		return nil, err
-	}
-
-	return &ProxyResult{
-		Data:        data,
-		ContentType: contentType,
-	}, nil
+	return newAzureAnubisAttempt(resp, requestHeaders), nil
 }
 
 func detectProxyImageContentType(data []byte) (string, error) {
@@ -27,7 +27,7 @@ func TestBuildReferer(t *testing.T) {
 
 func TestProxyService_FetchImage_InvalidURL(t *testing.T) {
 	clientFactory := network.NewClientFactoryForTest(&http.Client{})
-	svc := service.NewProxyService(clientFactory, nil)
+	svc := service.NewProxyService(clientFactory, nil, nil)
 
 	_, err := svc.FetchImage(context.Background(), "://invalid", "")
 	require.ErrorIs(t, err, service.ErrInvalidURL)
@@ -35,7 +35,7 @@ func TestProxyService_FetchImage_InvalidURL(t *testing.T) {
 
 func TestProxyService_FetchImage_InvalidProtocol(t *testing.T) {
 	clientFactory := network.NewClientFactoryForTest(&http.Client{})
-	svc := service.NewProxyService(clientFactory, nil)
+	svc := service.NewProxyService(clientFactory, nil, nil)
 
 	_, err := svc.FetchImage(context.Background(), "ftp://example.com/a.png", "")
 	require.ErrorIs(t, err, service.ErrInvalidProtocol)
@@ -49,7 +49,7 @@ func TestProxyService_FetchImage_Success(t *testing.T) {
 	defer server.Close()
 
 	clientFactory := network.NewClientFactoryForTest(&http.Client{})
-	svc := service.NewProxyService(clientFactory, nil)
+	svc := service.NewProxyService(clientFactory, nil, nil)
 
 	result, err := svc.FetchImage(context.Background(), server.URL+"/img.png", "")
 	require.NoError(t, err)
@@ -66,7 +66,7 @@ func TestProxyService_FetchImage_InvalidImage(t *testing.T) {
 	defer server.Close()
 
 	clientFactory := network.NewClientFactoryForTest(&http.Client{})
-	svc := service.NewProxyService(clientFactory, nil)
+	svc := service.NewProxyService(clientFactory, nil, nil)
 
 	_, err := svc.FetchImage(context.Background(), server.URL+"/img.png", "")
 	require.ErrorIs(t, err, service.ErrInvalidImage)
@@ -81,7 +81,7 @@ func TestProxyService_FetchImage_SVG(t *testing.T) {
 	defer server.Close()
 
 	clientFactory := network.NewClientFactoryForTest(&http.Client{})
-	svc := service.NewProxyService(clientFactory, nil)
+	svc := service.NewProxyService(clientFactory, nil, nil)
 
 	result, err := svc.FetchImage(context.Background(), server.URL+"/img.svg", "")
 	require.NoError(t, err)
@@ -98,7 +98,7 @@ func TestProxyService_FetchImage_SVGWithLongPrefixFallback(t *testing.T) {
 	defer server.Close()
 
 	clientFactory := network.NewClientFactoryForTest(&http.Client{})
-	svc := service.NewProxyService(clientFactory, nil)
+	svc := service.NewProxyService(clientFactory, nil, nil)
 
 	result, err := svc.FetchImage(context.Background(), server.URL+"/img.svg", "")
 	require.NoError(t, err)
@@ -114,7 +114,7 @@ func TestProxyService_FetchImage_UpstreamRejected(t *testing.T) {
 
 	clientFactory := network.NewClientFactoryForTest(&http.Client{})
 	anubisSolver := anubis.NewSolver(clientFactory, nil)
-	svc := service.NewProxyService(clientFactory, anubisSolver)
+	svc := service.NewProxyService(clientFactory, anubisSolver, nil)
 
 	_, err := svc.FetchImage(context.Background(), server.URL+"/img.png", "")
 	require.ErrorIs(t, err, service.ErrUpstreamRejected)
@@ -122,14 +122,14 @@ func TestProxyService_FetchImage_UpstreamRejected(t *testing.T) {
 
 func TestProxyService_Close(t *testing.T) {
 	clientFactory := network.NewClientFactoryForTest(&http.Client{})
-	svc := service.NewProxyService(clientFactory, nil)
+	svc := service.NewProxyService(clientFactory, nil, nil)
 	svc.Close()
 }
 
-func TestProxyService_FetchWithFreshSession_InvalidURL(t *testing.T) {
+func TestProxyService_FetchImage_UnparsableURL(t *testing.T) {
 	clientFactory := network.NewClientFactoryForTest(&http.Client{})
-	svc := service.NewProxyService(clientFactory, nil)
+	svc := service.NewProxyService(clientFactory, nil, nil)
 
-	_, err := service.ProxyFetchWithFreshSessionForTest(svc, context.Background(), "://bad", "", "", 0)
+	_, err := svc.FetchImage(context.Background(), "://bad", "")
 	require.ErrorIs(t, err, service.ErrInvalidURL)
 }
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"time"
+
+	"gist/backend/internal/model"
+	"gist/backend/pkg/logger"
+)
+
+// zoneSuffixRegex matches the zone designators feeds put at the end of a date:
+// "Z", numeric offsets ("+0800", "-07:00", "+08" after a time) and abbreviations ("GMT", "UT", "CEST").
+// A trailing parenthesized comment such as "(CST)" is ignored.
+var zoneSuffixRegex = regexp.MustCompile(`(?i)(?:\dZ|[+-]\d{2}:?\d{2}|\d:\d{2}(?::\d{2}(?:\.\d+)?)?[+-]\d{2}|\bUT|\b[A-Z]{3,5})(?:\s*\([^)]*\))?$`)
+
+// hasZoneInfo reports whether a raw feed date names its time zone.
+func hasZoneInfo(raw string) bool {
+	return zoneSuffixRegex.MatchString(strings.TrimSpace(raw))
+}
+
+// inAssumedZone converts a parsed feed date to UTC. gofeed reads zoneless dates
+// as UTC, so when raw carries no zone the wall clock is re-read in loc.
+// An empty raw string gives nothing to judge by and is kept as parsed.
+func inAssumedZone(parsed time.Time, raw string, loc *time.Location) time.Time {
+	if loc == nil || strings.TrimSpace(raw) == "" || hasZoneInfo(raw) {
+		return parsed.UTC()
+	}
+	wall := parsed.UTC()
+	return time.Date(wall.Year(), wall.Month(), wall.Day(), wall.Hour(), wall.Minute(), wall.Second(), wall.Nanosecond(), loc).UTC()
+}
+
+// zonelessLayouts are the published_at formats older versions stored without a zone.
+var zonelessLayouts = []string{
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// parseStoredPublishedAt re-reads a stored published_at value, interpreting it in loc
+// when it carries no zone. ok is false for values in no known format.
+func parseStoredPublishedAt(value string, loc *time.Location) (time.Time, bool) {
+	value = strings.TrimSpace(value)
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02 15:04:05.999999999Z07:00"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.UTC(), true
+		}
+	}
+	for _, layout := range zonelessLayouts {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return t.UTC(), true
+		}
+	}
+	return time.Time{}, false
+}
+
+// configuredLocation returns the timezone from GeneralSettings, falling back to UTC.
+func configuredLocation(general *GeneralSettings) *time.Location {
+	if general == nil || general.Timezone == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(general.Timezone)
+	if err != nil {
+		logger.Warn("invalid timezone setting", "module", "service", "action", "parse", "resource", "settings", "result", "failed", "timezone", general.Timezone, "error", err)
+		return time.UTC
+	}
+	return loc
+}
+
+// feedLocation returns the zone assumed for a feed's zoneless dates: its own
+// assume_timezone when set, otherwise the configured timezone.
+func feedLocation(feed model.Feed, general *GeneralSettings) *time.Location {
+	if feed.AssumeTimezone != nil && *feed.AssumeTimezone != "" {
+		if loc, err := time.LoadLocation(*feed.AssumeTimezone); err == nil {
+			return loc
+		}
+		logger.Warn("invalid feed timezone", "module", "service", "action", "parse", "resource", "feed", "result", "failed", "feed_id", feed.ID, "timezone", *feed.AssumeTimezone)
+	}
+	return configuredLocation(general)
+}
+
+// loadGeneralSettings returns nil when settings are unavailable, so callers use defaults.
+func loadGeneralSettings(ctx context.Context, settings SettingsService) *GeneralSettings {
+	if settings == nil {
+		return nil
+	}
+	general, err := settings.GetGeneralSettings(ctx)
+	if err != nil {
+		return nil
+	}
+	return general
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gist/backend/pkg/logger"
+)
+
+// quietHoursRecheck is how often a job paused for quiet hours reads the
+// settings again, so shortening or disabling the window resumes it.
+var quietHoursRecheck = time.Minute
+
+// ValidateQuietHours checks a quiet hours window: both ends "HH:MM", or both
+// empty to disable it.
+func ValidateQuietHours(start, end string) error {
+	if start == "" && end == "" {
+		return nil
+	}
+	if _, ok := parseClock(start); !ok {
+		return fmt.Errorf("quiet hours start %q: %w", start, ErrInvalid)
+	}
+	if _, ok := parseClock(end); !ok {
+		return fmt.Errorf("quiet hours end %q: %w", end, ErrInvalid)
+	}
+	return nil
+}
+
+// QuietUntil reports whether now falls within the configured quiet hours and,
+// if so, when they end.
+func QuietUntil(ctx context.Context, settings SettingsService, now time.Time) (time.Time, bool) {
+	return quietHoursEnd(loadGeneralSettings(ctx, settings), now)
+}
+
+// quietHoursEnd returns the end of the quiet hours of general that now falls
+// in. Windows ending before they start run past midnight (22:00-07:00).
+func quietHoursEnd(general *GeneralSettings, now time.Time) (time.Time, bool) {
+	if general == nil {
+		return time.Time{}, false
+	}
+	start, okStart := parseClock(general.QuietHoursStart)
+	end, okEnd := parseClock(general.QuietHoursEnd)
+	if !okStart || !okEnd || start == end {
+		return time.Time{}, false
+	}
+
+	loc := configuredLocation(general)
+	local := now.In(loc)
+	minute := local.Hour()*60 + local.Minute()
+	inside := minute >= start && minute < end
+	if start > end {
+		inside = minute >= start || minute < end
+	}
+	if !inside {
+		return time.Time{}, false
+	}
+
+	year, month, day := local.Date()
+	until := time.Date(year, month, day, end/60, end%60, 0, 0, loc)
+	if !until.After(local) {
+		until = time.Date(year, month, day+1, end/60, end%60, 0, 0, loc)
+	}
+	return until, true
+}
+
+// waitOutQuietHours blocks while quiet hours are on, for background jobs to
+// call before starting and between batches. It returns early with the
+// context's error.
+func waitOutQuietHours(ctx context.Context, settings SettingsService, resource string) error {
+	until, quiet := QuietUntil(ctx, settings, time.Now())
+	if !quiet {
+		return nil
+	}
+	logger.Info("paused for quiet hours", "module", "service", "action", "update", "resource", resource, "result", "skipped", "until", until.Format(time.RFC3339))
+	for quiet {
+		wait := min(time.Until(until), quietHoursRecheck)
+		timer := time.NewTimer(max(wait, time.Millisecond))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+		until, quiet = QuietUntil(ctx, settings, time.Now())
+	}
+	logger.Info("resumed after quiet hours", "module", "service", "action", "update", "resource", resource, "result", "ok")
+	return nil
+}
+
+// parseClock parses "HH:MM" into minutes after midnight.
+func parseClock(value string) (int, bool) {
+	if len(value) != len("15:04") {
+		return 0, false
+	}
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, false
+	}
+	return t.Hour()*60 + t.Minute(), true
+}
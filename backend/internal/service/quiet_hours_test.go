@@ -0,0 +1,143 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"gist/backend/internal/service"
+	"gist/backend/internal/service/ai"
+)
+
+func newQuietHoursSettings(t *testing.T, start, end, timezone string) service.SettingsService {
+	t.Helper()
+	svc := service.NewSettingsService(newSettingsRepoStub(), ai.NewRateLimiter(0), service.SecuritySettings{})
+	require.NoError(t, svc.SetGeneralSettings(context.Background(), &service.GeneralSettings{
+		Timezone:        timezone,
+		QuietHoursStart: start,
+		QuietHoursEnd:   end,
+	}))
+	return svc
+}
+
+func TestValidateQuietHours(t *testing.T) {
+	require.NoError(t, service.ValidateQuietHours("", ""))
+	require.NoError(t, service.ValidateQuietHours("22:00", "07:00"))
+	require.NoError(t, service.ValidateQuietHours("00:00", "23:59"))
+
+	for _, window := range [][2]string{{"22:00", ""}, {"", "07:00"}, {"7:00", "08:00"}, {"24:00", "07:00"}, {"22:00", "07:60"}, {"10pm", "07:00"}} {
+		require.ErrorIs(t, service.ValidateQuietHours(window[0], window[1]), service.ErrInvalid, window)
+	}
+}
+
+func TestSettingsService_GeneralSettings_QuietHours(t *testing.T) {
+	ctx := context.Background()
+	svc := newQuietHoursSettings(t, "22:00", "07:00", "")
+
+	settings, err := svc.GetGeneralSettings(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "22:00", settings.QuietHoursStart)
+	require.Equal(t, "07:00", settings.QuietHoursEnd)
+
+	settings.QuietHoursStart, settings.QuietHoursEnd = "", ""
+	require.NoError(t, svc.SetGeneralSettings(ctx, settings))
+	settings, err = svc.GetGeneralSettings(ctx)
+	require.NoError(t, err)
+	require.Empty(t, settings.QuietHoursStart)
+	require.Empty(t, settings.QuietHoursEnd)
+
+	settings.QuietHoursStart = "22:00"
+	require.ErrorIs(t, svc.SetGeneralSettings(ctx, settings), service.ErrInvalid)
+}
+
+func TestQuietUntil(t *testing.T) {
+	ctx := context.Background()
+	at := func(value string) time.Time {
+		parsed, err := time.Parse(time.RFC3339, value)
+		require.NoError(t, err)
+		return parsed
+	}
+
+	t.Run("across midnight", func(t *testing.T) {
+		svc := newQuietHoursSettings(t, "22:00", "07:00", "UTC")
+
+		until, quiet := service.QuietUntil(ctx, svc, at("2026-03-01T23:30:00Z"))
+		require.True(t, quiet)
+		require.True(t, until.Equal(at("2026-03-02T07:00:00Z")))
+
+		until, quiet = service.QuietUntil(ctx, svc, at("2026-03-02T06:59:00Z"))
+		require.True(t, quiet)
+		require.True(t, until.Equal(at("2026-03-02T07:00:00Z")))
+
+		_, quiet = service.QuietUntil(ctx, svc, at("2026-03-02T07:00:00Z"))
+		require.False(t, quiet)
+		_, quiet = service.QuietUntil(ctx, svc, at("2026-03-02T21:59:00Z"))
+		require.False(t, quiet)
+	})
+
+	t.Run("within a day", func(t *testing.T) {
+		svc := newQuietHoursSettings(t, "01:00", "05:30", "UTC")
+
+		until, quiet := service.QuietUntil(ctx, svc, at("2026-03-01T01:00:00Z"))
+		require.True(t, quiet)
+		require.True(t, until.Equal(at("2026-03-01T05:30:00Z")))
+
+		_, quiet = service.QuietUntil(ctx, svc, at("2026-03-01T23:30:00Z"))
+		require.False(t, quiet)
+	})
+
+	t.Run("configured timezone", func(t *testing.T) {
+		svc := newQuietHoursSettings(t, "22:00", "07:00", "Asia/Shanghai")
+
+		// 15:00 UTC is 23:00 in Shanghai
+		until, quiet := service.QuietUntil(ctx, svc, at("2026-03-01T15:00:00Z"))
+		require.True(t, quiet)
+		require.True(t, until.Equal(at("2026-03-01T23:00:00Z")))
+
+		_, quiet = service.QuietUntil(ctx, svc, at("2026-03-01T23:30:00Z"))
+		require.False(t, quiet)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		_, quiet := service.QuietUntil(ctx, newQuietHoursSettings(t, "", "", "UTC"), time.Now())
+		require.False(t, quiet)
+		_, quiet = service.QuietUntil(ctx, newQuietHoursSettings(t, "03:00", "03:00", "UTC"), time.Now())
+		require.False(t, quiet)
+		_, quiet = service.QuietUntil(ctx, nil, time.Now())
+		require.False(t, quiet)
+	})
+}
+
+func TestWaitOutQuietHours(t *testing.T) {
+	defer service.SetQuietHoursRecheckForTest(10 * time.Millisecond)()
+	ctx := context.Background()
+
+	// A window covering the whole day but its last minute is on for the test
+	svc := newQuietHoursSettings(t, "00:00", "23:59", "UTC")
+	if _, quiet := service.QuietUntil(ctx, svc, time.Now()); !quiet {
+		t.Skip("run during the last minute of the day")
+	}
+
+	cancelled, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	require.ErrorIs(t, service.WaitOutQuietHours(cancelled, svc, "feed"), context.DeadlineExceeded)
+
+	done := make(chan error, 1)
+	go func() { done <- service.WaitOutQuietHours(ctx, svc, "feed") }()
+	select {
+	case <-done:
+		t.Fatal("returned inside quiet hours")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// Disabling the window resumes the job at its next check
+	require.NoError(t, svc.SetGeneralSettings(ctx, &service.GeneralSettings{Timezone: "UTC"}))
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("did not resume after quiet hours were disabled")
+	}
+}
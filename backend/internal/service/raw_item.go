@@ -0,0 +1,64 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+	"unicode/utf8"
+
+	"gist/backend/internal/model"
+	"gist/backend/pkg/logger"
+
+	"github.com/mmcdole/gofeed"
+)
+
+// RawItemRetention is how long a kept feed item is stored after the first save
+// of its entry, whether or not the entry itself is kept longer.
+const RawItemRetention = 7 * 24 * time.Hour
+
+// rawItemMaxBytes caps a kept feed item; longer ones are cut.
+const rawItemMaxBytes = 64 << 10
+
+// attachRawItem sets entry.RawItem to item serialized to JSON when general
+// settings keep raw items. gofeed keeps no source bytes, so this is the parsed
+// item, extensions included.
+func attachRawItem(entry *model.Entry, item *gofeed.Item, general *GeneralSettings) {
+	if general == nil || !general.KeepRawItems || item == nil {
+		return
+	}
+	data, err := json.MarshalIndent(item, "", "  ")
+	if err != nil {
+		logger.Debug("raw item encode failed", "module", "service", "action", "save", "resource", "entry", "result", "failed", "feed_id", entry.FeedID, "error", err)
+		return
+	}
+	raw := string(data)
+	if len(raw) > rawItemMaxBytes {
+		cut := rawItemMaxBytes
+		for cut > 0 && !utf8.RuneStart(raw[cut]) {
+			cut--
+		}
+		raw = raw[:cut]
+	}
+	entry.RawItem = &raw
+}
+
+func (s *entryService) GetRawItem(ctx context.Context, id int64) (string, error) {
+	if _, err := s.entries.GetByID(ctx, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", ErrNotFound
+		}
+		return "", err
+	}
+
+	raw, err := s.entries.GetRawItem(ctx, id)
+	if err != nil {
+		logger.Error("entry raw item get failed", "module", "service", "action", "fetch", "resource", "entry", "result", "failed", "entry_id", id, "error", err)
+		return "", err
+	}
+	if raw == nil {
+		return "", ErrNotFound
+	}
+	return *raw, nil
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"mime"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gabriel-vasile/mimetype"
+	"github.com/ledongthuc/pdf"
+)
+
+type readableKind int
+
+const (
+	readableHTML readableKind = iota
+	readablePDF
+	readableText
+)
+
+// detectReadableKind decides how a fetched page is turned into readable content.
+// Magic bytes win over the Content-Type header, since PDFs are often served as
+// application/octet-stream; a missing or generic header falls back to sniffing.
+func detectReadableKind(contentType string, body []byte) (readableKind, error) {
+	if bytes.HasPrefix(body, []byte("%PDF-")) {
+		return readablePDF, nil
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil || mediaType == "application/octet-stream" {
+		mediaType, _, _ = mime.ParseMediaType(mimetype.Detect(body).String())
+	}
+
+	switch mediaType {
+	case "text/html", "application/xhtml+xml":
+		return readableHTML, nil
+	case "application/pdf":
+		return readablePDF, nil
+	case "text/plain":
+		return readableText, nil
+	}
+	return readableHTML, fmt.Errorf("%w: %s", ErrUnsupportedContentType, mediaType)
+}
+
+// extractPDFText returns the lines of text in a PDF, page by page. Text
+// objects usually hold one line each, so each becomes its own paragraph.
+func extractPDFText(body []byte) (lines []string, err error) {
+	// The PDF reader panics on some malformed documents
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("malformed pdf: %v", r)
+		}
+	}()
+
+	reader, err := pdf.NewReader(bytes.NewReader(body), int64(len(body)))
+	if err != nil {
+		return nil, err
+	}
+
+	for i := 1; i <= reader.NumPage(); i++ {
+		pageText, err := reader.Page(i).GetPlainText(nil)
+		if err != nil {
+			return nil, err
+		}
+		for _, line := range strings.Split(pageText, "\n") {
+			if line = strings.Join(strings.Fields(line), " "); line != "" {
+				lines = append(lines, line)
+			}
+		}
+	}
+	return lines, nil
+}
+
+// plainTextToHTML escapes a text document. Text whose layout matters (indented
+// lines or tabs, as in RFCs and code) is kept in a single <pre>; anything else
+// becomes paragraphs split on blank lines. Returns nil when there is no text.
+func plainTextToHTML(text string) []byte {
+	text = strings.ToValidUTF8(strings.TrimPrefix(text, "\ufeff"), string(utf8.RuneError))
+	text = strings.ReplaceAll(strings.ReplaceAll(text, "\r\n", "\n"), "\r", "\n")
+	if strings.TrimSpace(text) == "" {
+		return nil
+	}
+
+	if hasTextLayout(text) {
+		var buf bytes.Buffer
+		buf.WriteString("<pre>")
+		buf.WriteString(html.EscapeString(strings.Trim(text, "\n")))
+		buf.WriteString("</pre>")
+		return buf.Bytes()
+	}
+
+	var paragraphs []string
+	for _, block := range strings.Split(text, "\n\n") {
+		if paragraph := strings.Join(strings.Fields(block), " "); paragraph != "" {
+			paragraphs = append(paragraphs, paragraph)
+		}
+	}
+	return paragraphsToHTML(paragraphs)
+}
+
+// paragraphsToHTML escapes each paragraph into a <p>. Returns nil for none.
+func paragraphsToHTML(paragraphs []string) []byte {
+	if len(paragraphs) == 0 {
+		return nil
+	}
+	var buf bytes.Buffer
+	for _, paragraph := range paragraphs {
+		buf.WriteString("<p>")
+		buf.WriteString(html.EscapeString(paragraph))
+		buf.WriteString("</p>\n")
+	}
+	return buf.Bytes()
+}
+
+func hasTextLayout(text string) bool {
+	for _, line := range strings.Split(text, "\n") {
+		if strings.HasPrefix(line, "  ") || strings.Contains(line, "\t") {
+			return true
+		}
+	}
+	return false
+}
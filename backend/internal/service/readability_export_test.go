@@ -19,6 +19,11 @@ func FixLazyImagesForTest(htmlContent []byte) []byte {
 	return fixLazyImages(htmlContent)
 }
 
+// StripLeadingTitleForTest exposes stripLeadingTitle for tests.
+func StripLeadingTitleForTest(htmlContent, title, author string) string {
+	return string(stripLeadingTitle([]byte(htmlContent), title, author))
+}
+
 // ParseHTMLForTest exposes the readability parsing logic for tests.
 // This tests that KeepClasses=true is set correctly.
 func ParseHTMLForTest(htmlContent string, pageURL string) (string, error) {
@@ -42,30 +47,33 @@ func ParseHTMLForTest(htmlContent string, pageURL string) (string, error) {
 }
 
 // ReadabilityFetchWithChromeForTest exposes fetchWithChrome for tests.
-func ReadabilityFetchWithChromeForTest(svc ReadabilityService, ctx context.Context, targetURL, cookie string, retryCount int) ([]byte, error) {
-	impl, ok := svc.(*readabilityService)
-	if !ok {
-		return nil, ErrInvalid
-	}
-	return impl.fetchWithChrome(ctx, targetURL, cookie, retryCount)
-}
-
-// ReadabilityFetchWithFreshSessionForTest exposes fetchWithFreshSession for tests.
-func ReadabilityFetchWithFreshSessionForTest(svc ReadabilityService, ctx context.Context, targetURL, cookie string, retryCount int) ([]byte, error) {
+func ReadabilityFetchWithChromeForTest(svc ReadabilityService, ctx context.Context, targetURL string) ([]byte, error) {
 	impl, ok := svc.(*readabilityService)
 	if !ok {
 		return nil, ErrInvalid
 	}
-	return impl.fetchWithFreshSession(ctx, targetURL, cookie, retryCount)
+	body, _, err := impl.fetchWithChrome(ctx, targetURL)
+	return body, err
 }
 
 // ReadabilityDoFetchForTest exposes doFetch for tests.
-func ReadabilityDoFetchForTest(svc ReadabilityService, ctx context.Context, targetURL, cookie string, retryCount int) ([]byte, error) {
+func ReadabilityDoFetchForTest(svc ReadabilityService, ctx context.Context, targetURL, cookie string) ([]byte, error) {
 	impl, ok := svc.(*readabilityService)
 	if !ok {
 		return nil, ErrInvalid
 	}
 	session := impl.clientFactory.NewAzureSession(ctx, readabilityTimeout)
 	defer session.Close()
-	return impl.doFetch(ctx, session, targetURL, cookie, retryCount)
+	attempt, _, err := impl.doFetch(ctx, session, targetURL, cookie)
+	return attempt.body, err
+}
+
+// PlainTextToHTMLForTest exposes plainTextToHTML for tests.
+func PlainTextToHTMLForTest(text string) string {
+	return string(plainTextToHTML(text))
+}
+
+// ExtractPDFTextForTest exposes extractPDFText for tests.
+func ExtractPDFTextForTest(body []byte) ([]string, error) {
+	return extractPDFText(body)
 }
@@ -54,8 +54,8 @@ func TestReadabilityService_Integration_FetchReadableContent(t *testing.T) {
 
 	dbConn := setupReadabilityTestDB(t)
 	clientFactory := network.NewClientFactoryForTest(nil)
-	entryRepo := repository.NewEntryRepository(dbConn)
-	feedRepo := repository.NewFeedRepository(dbConn)
+	entryRepo := repository.NewEntryRepository(dbConn, nil, nil)
+	feedRepo := repository.NewFeedRepository(dbConn, nil, nil)
 
 	// Create a feed first
 	feed, err := feedRepo.Create(context.Background(), model.Feed{
@@ -65,7 +65,7 @@ func TestReadabilityService_Integration_FetchReadableContent(t *testing.T) {
 	})
 	require.NoError(t, err)
 
-	svc := service.NewReadabilityService(entryRepo, clientFactory, nil)
+	svc := service.NewReadabilityService(entryRepo, clientFactory, nil, nil, nil, nil)
 	defer svc.Close()
 
 	for _, article := range testArticles {
@@ -82,7 +82,7 @@ func TestReadabilityService_Integration_FetchReadableContent(t *testing.T) {
 				Title:  &title,
 				Hash:   hashString(url),
 			}
-			err := entryRepo.CreateOrUpdate(ctx, entry)
+			err := entryRepo.CreateOrUpdate(ctx, entry, 0)
 			require.NoError(t, err)
 
 			// Get the created entry
@@ -124,8 +124,8 @@ func TestReadabilityService_Integration_CachedContent(t *testing.T) {
 
 	dbConn := setupReadabilityTestDB(t)
 	clientFactory := network.NewClientFactoryForTest(nil)
-	entryRepo := repository.NewEntryRepository(dbConn)
-	feedRepo := repository.NewFeedRepository(dbConn)
+	entryRepo := repository.NewEntryRepository(dbConn, nil, nil)
+	feedRepo := repository.NewFeedRepository(dbConn, nil, nil)
 
 	// Create a feed
 	feed, err := feedRepo.Create(context.Background(), model.Feed{
@@ -135,7 +135,7 @@ func TestReadabilityService_Integration_CachedContent(t *testing.T) {
 	})
 	require.NoError(t, err)
 
-	svc := service.NewReadabilityService(entryRepo, clientFactory, nil)
+	svc := service.NewReadabilityService(entryRepo, clientFactory, nil, nil, nil, nil)
 	defer svc.Close()
 
 	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
@@ -150,7 +150,7 @@ func TestReadabilityService_Integration_CachedContent(t *testing.T) {
 		Title:  &title,
 		Hash:   hashString(url),
 	}
-	err = entryRepo.CreateOrUpdate(ctx, entry)
+	err = entryRepo.CreateOrUpdate(ctx, entry, 0)
 	require.NoError(t, err)
 
 	// Get the entry
@@ -164,7 +164,7 @@ func TestReadabilityService_Integration_CachedContent(t *testing.T) {
 
 	// Update with cached readable content using the proper method
 	cachedContent := "<p>This is cached readable content.</p>"
-	err = entryRepo.UpdateReadableContent(ctx, entries[0].ID, cachedContent)
+	err = entryRepo.UpdateReadableContent(ctx, entries[0].ID, cachedContent, 6)
 	require.NoError(t, err)
 
 	// Fetch should return cached content without making network request
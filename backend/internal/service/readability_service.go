@@ -10,6 +10,7 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
 	"time"
 
 	readability "codeberg.org/readeck/go-readability/v2"
@@ -17,95 +18,260 @@ import (
 	"golang.org/x/net/html"
 
 	"gist/backend/internal/config"
+	"gist/backend/internal/events"
+	"gist/backend/internal/model"
 	"gist/backend/internal/repository"
 	"gist/backend/pkg/logger"
 	"gist/backend/pkg/network"
+	"gist/backend/pkg/readtime"
 )
 
 const readabilityTimeout = 30 * time.Second
 
+// readableFailureTTL is how long the failure of a background extraction is
+// kept for the client polling it.
+const readableFailureTTL = 5 * time.Minute
+
+// Outcomes of StartReadableContent and of events.EntryReadable.
+const (
+	ReadablePending = "pending"
+	ReadableReady   = "ready"
+	ReadableFailed  = "failed"
+)
+
+// ReadableResult is the state of an entry's readable content: ReadableReady with
+// Content, or ReadablePending while it is being extracted.
+type ReadableResult struct {
+	Status  string
+	Content string
+}
+
 type ReadabilityService interface {
+	// FetchReadableContent returns the readable content, extracting and caching it
+	// first when needed. Concurrent calls for an entry share one extraction, which
+	// keeps running when ctx is cancelled.
 	FetchReadableContent(ctx context.Context, entryID int64) (string, error)
+	// StartReadableContent returns cached readable content, or starts (or joins) its
+	// extraction in the background and returns ReadablePending. Finished extractions
+	// publish events.EntryReadable; a failed one is returned by the next call.
+	StartReadableContent(ctx context.Context, entryID int64) (ReadableResult, error)
+	// FetchPage fetches a page the way readable content is fetched and extracts
+	// its title, content, image and author, for pages saved outside any feed.
+	FetchPage(ctx context.Context, pageURL string) (ReadablePage, error)
 	Close()
 }
 
+// readableJob is one extraction in flight; done is closed once content or err is set.
+type readableJob struct {
+	done    chan struct{}
+	content string
+	err     error
+}
+
+type readableFailure struct {
+	err error
+	at  time.Time
+}
+
 type readabilityService struct {
 	entries       repository.EntryRepository
 	clientFactory *network.ClientFactory
 	anubis        AnubisSolver
+	// settings supplies the Anubis retry options; nil uses the defaults.
+	settings SettingsService
+	// hosts spaces out the image probes of markTransparentImages
+	hosts *hostRateLimiter
+	// bus receives change events for GET /events; nil drops them.
+	bus *events.Bus
+
+	// ctx bounds background extractions and is cancelled by Close
+	ctx      context.Context
+	cancel   context.CancelFunc
+	mu       sync.Mutex
+	jobs     map[int64]*readableJob    // entry_id -> extraction in flight
+	failures map[int64]readableFailure // entry_id -> last failed extraction
 }
 
-func NewReadabilityService(entries repository.EntryRepository, clientFactory *network.ClientFactory, anubisSolver AnubisSolver) ReadabilityService {
+func NewReadabilityService(entries repository.EntryRepository, clientFactory *network.ClientFactory, anubisSolver AnubisSolver, settings SettingsService, rateLimitSvc DomainRateLimitService, bus *events.Bus) ReadabilityService {
+	ctx, cancel := context.WithCancel(context.Background())
 	return &readabilityService{
 		entries:       entries,
 		clientFactory: clientFactory,
 		anubis:        anubisSolver,
+		settings:      settings,
+		hosts: newHostRateLimiter(func(host string) time.Duration {
+			if rateLimitSvc != nil {
+				return rateLimitSvc.GetIntervalDuration(ctx, host)
+			}
+			return 0
+		}, func(host string) int {
+			if rateLimitSvc != nil {
+				return rateLimitSvc.GetMaxConcurrent(ctx, host)
+			}
+			return 0
+		}),
+		bus:      bus,
+		ctx:      ctx,
+		cancel:   cancel,
+		jobs:     make(map[int64]*readableJob),
+		failures: make(map[int64]readableFailure),
 	}
 }
 
 func (s *readabilityService) FetchReadableContent(ctx context.Context, entryID int64) (string, error) {
+	entry, cached, err := s.readableEntry(ctx, entryID)
+	if err != nil || cached {
+		return ptrString(entry.ReadableContent), err
+	}
+
+	job := s.startJob(entry)
+	select {
+	case <-job.done:
+		return job.content, job.err
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+func (s *readabilityService) StartReadableContent(ctx context.Context, entryID int64) (ReadableResult, error) {
+	s.mu.Lock()
+	if _, ok := s.jobs[entryID]; ok {
+		s.mu.Unlock()
+		return ReadableResult{Status: ReadablePending}, nil
+	}
+	if failure, ok := s.failures[entryID]; ok {
+		// Report a failure once, so asking again retries
+		delete(s.failures, entryID)
+		if time.Since(failure.at) < readableFailureTTL {
+			s.mu.Unlock()
+			return ReadableResult{}, failure.err
+		}
+	}
+	s.mu.Unlock()
+
+	entry, cached, err := s.readableEntry(ctx, entryID)
+	if err != nil {
+		return ReadableResult{}, err
+	}
+	if cached {
+		return ReadableResult{Status: ReadableReady, Content: *entry.ReadableContent}, nil
+	}
+	s.startJob(entry)
+	return ReadableResult{Status: ReadablePending}, nil
+}
+
+// readableEntry loads the entry and reports whether its readable content is cached.
+// Entries without a URL are ErrInvalid.
+func (s *readabilityService) readableEntry(ctx context.Context, entryID int64) (model.Entry, bool, error) {
 	entry, err := s.entries.GetByID(ctx, entryID)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return "", ErrNotFound
+			return model.Entry{}, false, ErrNotFound
 		}
-		return "", err
+		return model.Entry{}, false, err
 	}
 
-	// Return cached content if available
 	if entry.ReadableContent != nil && *entry.ReadableContent != "" {
 		logger.Debug("readability cache hit", "module", "service", "action", "fetch", "resource", "entry", "result", "ok", "entry_id", entryID, "cache", "hit")
-		return *entry.ReadableContent, nil
+		return entry, true, nil
 	}
-
-	// Validate URL
 	if entry.URL == nil || *entry.URL == "" {
-		return "", ErrInvalid
+		return model.Entry{}, false, ErrInvalid
 	}
+	return entry, false, nil
+}
 
-	// Fetch with Chrome fingerprint and Anubis support
-	body, err := s.fetchWithChrome(ctx, *entry.URL, "", 0)
-	if err != nil {
-		logger.Warn("readability fetch failed", "module", "service", "action", "fetch", "resource", "entry", "result", "failed", "entry_id", entryID, "host", network.ExtractHost(*entry.URL), "error", err)
-		return "", err
+// startJob starts extracting the entry's readable content in the background, or
+// returns the extraction already in flight.
+func (s *readabilityService) startJob(entry model.Entry) *readableJob {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if job, ok := s.jobs[entry.ID]; ok {
+		return job
 	}
 
-	// Parse URL for readability
-	parsedURL, err := url.Parse(*entry.URL)
+	job := &readableJob{done: make(chan struct{})}
+	s.jobs[entry.ID] = job
+	delete(s.failures, entry.ID)
+	go s.runJob(entry, job)
+	return job
+}
+
+func (s *readabilityService) runJob(entry model.Entry, job *readableJob) {
+	content, err := s.extract(s.ctx, entry)
+
+	s.mu.Lock()
+	job.content, job.err = content, err
+	delete(s.jobs, entry.ID)
 	if err != nil {
-		return "", fmt.Errorf("parse URL failed: %w", err)
+		now := time.Now()
+		for id, failure := range s.failures {
+			if now.Sub(failure.at) >= readableFailureTTL {
+				delete(s.failures, id)
+			}
+		}
+		s.failures[entry.ID] = readableFailure{err: err, at: now}
 	}
+	close(job.done)
+	s.mu.Unlock()
 
-	// Parse with readability
-	// go-readability handles lazy images (unwrapNoscriptImages, fixLazyImages) and script removal internally
-	parser := readability.NewParser()
-	parser.KeepClasses = true // Preserve class attributes (e.g., language-python on code blocks)
-	article, err := parser.Parse(bytes.NewReader(body), parsedURL)
+	data := events.EntryReadableData{EntryID: entry.ID, Status: ReadableReady}
 	if err != nil {
-		logger.Error("readability parse failed", "module", "service", "action", "fetch", "resource", "entry", "result", "failed", "entry_id", entryID, "host", network.ExtractHost(*entry.URL), "error", err)
-		return "", fmt.Errorf("parse content failed: %w", err)
+		data.Status, data.Error = ReadableFailed, err.Error()
 	}
+	s.bus.Publish(events.Event{Type: events.EntryReadable, Data: data})
+}
 
-	// Render HTML content
-	var buf bytes.Buffer
-	if err := article.RenderHTML(&buf); err != nil {
-		return "", fmt.Errorf("render failed: %w", err)
+// extract fetches the entry's page, extracts its readable content and caches it.
+func (s *readabilityService) extract(ctx context.Context, entry model.Entry) (string, error) {
+	entryID := entry.ID
+
+	// Fetch with Chrome fingerprint and Anubis support
+	body, contentType, err := s.fetchWithChrome(ctx, *entry.URL)
+	if err != nil {
+		logger.Warn("readability fetch failed", "module", "service", "action", "fetch", "resource", "entry", "result", "failed", "entry_id", entryID, "host", network.ExtractHost(*entry.URL), "error", err)
+		return "", err
 	}
 
-	// Post-process: fix lazy images and remove metadata elements
-	rendered := buf.Bytes()
+	kind, err := detectReadableKind(contentType, body)
+	if err != nil {
+		logger.Warn("readability unsupported content", "module", "service", "action", "fetch", "resource", "entry", "result", "failed", "entry_id", entryID, "host", network.ExtractHost(*entry.URL), "error", err)
+		return "", err
+	}
 
-	// Fix lazy images with data-original that go-readability doesn't handle
-	rendered = fixLazyImages(rendered)
+	var rendered []byte
+	switch kind {
+	case readablePDF:
+		lines, err := extractPDFText(body)
+		if err != nil {
+			logger.Error("readability pdf parse failed", "module", "service", "action", "fetch", "resource", "entry", "result", "failed", "entry_id", entryID, "host", network.ExtractHost(*entry.URL), "error", err)
+			return "", fmt.Errorf("parse pdf failed: %w", err)
+		}
+		rendered = paragraphsToHTML(lines)
+	case readableText:
+		rendered = plainTextToHTML(string(body))
+	default:
+		rendered, err = s.parseHTML(entryID, *entry.URL, body)
+		if err != nil {
+			return "", err
+		}
+		// The reading view and AI prompts already show the title
+		rendered = stripLeadingTitle(rendered, ptrString(entry.Title), ptrString(entry.Author))
+	}
+	if rendered == nil {
+		// Scanned PDFs and blank text files have nothing to read
+		return "", ErrInvalid
+	}
 
 	// Remove date elements (Safari Reader style)
 	content := removeMetadataElements(rendered)
 	if content == "" {
 		return "", ErrInvalid
 	}
+	content = s.markTransparentImages(ctx, content, *entry.URL)
 
 	// Save to database
-	if err := s.entries.UpdateReadableContent(ctx, entryID, content); err != nil {
+	if err := s.entries.UpdateReadableContent(ctx, entryID, content, readtime.WordCount(content)); err != nil {
 		logger.Error("readability cache save failed", "module", "service", "action", "save", "resource", "entry", "result", "failed", "entry_id", entryID, "error", err)
 		return "", err
 	}
@@ -114,38 +280,95 @@ func (s *readabilityService) FetchReadableContent(ctx context.Context, entryID i
 	return content, nil
 }
 
-// Close releases resources held by the service
-func (s *readabilityService) Close() {
-	// No persistent resources to release
+// parseHTML runs the readability parser over an HTML page.
+func (s *readabilityService) parseHTML(entryID int64, pageURL string, body []byte) ([]byte, error) {
+	article, err := parseArticle(pageURL, body)
+	if err != nil {
+		logger.Error("readability parse failed", "module", "service", "action", "fetch", "resource", "entry", "result", "failed", "entry_id", entryID, "host", network.ExtractHost(pageURL), "error", err)
+		return nil, err
+	}
+	return renderArticle(article)
 }
 
-// fetchWithChrome fetches URL with Chrome TLS fingerprint and browser headers
-func (s *readabilityService) fetchWithChrome(ctx context.Context, targetURL string, cookie string, retryCount int) ([]byte, error) {
-	session := s.clientFactory.NewAzureSession(ctx, readabilityTimeout)
-	defer session.Close()
-	return s.doFetch(ctx, session, targetURL, cookie, retryCount)
+// parseArticle runs the readability parser over an HTML page.
+func parseArticle(pageURL string, body []byte) (readability.Article, error) {
+	parsedURL, err := url.Parse(pageURL)
+	if err != nil {
+		return readability.Article{}, fmt.Errorf("parse URL failed: %w", err)
+	}
+
+	// go-readability handles lazy images (unwrapNoscriptImages, fixLazyImages) and script removal internally
+	parser := readability.NewParser()
+	parser.KeepClasses = true // Preserve class attributes (e.g., language-python on code blocks)
+	article, err := parser.Parse(bytes.NewReader(body), parsedURL)
+	if err != nil {
+		return readability.Article{}, fmt.Errorf("parse content failed: %w", err)
+	}
+	return article, nil
 }
 
-// fetchWithFreshSession creates a new azuretls session to avoid connection reuse after Anubis
-func (s *readabilityService) fetchWithFreshSession(ctx context.Context, targetURL, cookie string, retryCount int) ([]byte, error) {
-	session := s.clientFactory.NewAzureSession(ctx, readabilityTimeout)
-	defer session.Close()
-	return s.doFetch(ctx, session, targetURL, cookie, retryCount)
+// renderArticle renders the content of a parsed article as HTML.
+func renderArticle(article readability.Article) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := article.RenderHTML(&buf); err != nil {
+		return nil, fmt.Errorf("render failed: %w", err)
+	}
+
+	// Fix lazy images with data-original that go-readability doesn't handle
+	return fixLazyImages(buf.Bytes()), nil
+}
+
+// Close cancels the extractions still in flight
+func (s *readabilityService) Close() {
+	s.cancel()
 }
 
-// doFetch performs the actual HTTP request with the given session
-func (s *readabilityService) doFetch(ctx context.Context, session *azuretls.Session, targetURL, cookie string, retryCount int) ([]byte, error) {
+// fetchWithChrome fetches URL with Chrome TLS fingerprint and browser headers,
+// past Anubis challenges, and returns the body with its Content-Type header
+func (s *readabilityService) fetchWithChrome(ctx context.Context, targetURL string) ([]byte, string, error) {
+	host := network.ExtractHost(targetURL)
+	var contentType string
+	attempt, retryCount, err := fetchPastAnubis(ctx, s.anubis, targetURL, anubisRetryOptionsFrom(ctx, s.settings), func(cookie string, _ bool) (anubisAttempt, error) {
+		// A new session per fetch avoids reusing the challenged connection
+		session := s.clientFactory.NewAzureSession(ctx, readabilityTimeout)
+		defer session.Close()
+		attempt, fetchedType, err := s.doFetch(ctx, session, targetURL, cookie)
+		contentType = fetchedType
+		return attempt, err
+	})
+	switch {
+	case errors.Is(err, ErrAnubisRejected):
+		logger.Warn("readability upstream rejected", "module", "service", "action", "fetch", "resource", "entry", "result", "failed", "host", host, "error", err)
+		return nil, "", ErrAnubisRejected
+	case errors.Is(err, errAnubisRetryExceeded):
+		logger.Warn("readability anubis persists", "module", "service", "action", "fetch", "resource", "entry", "result", "failed", "host", host, "retry_count", retryCount)
+	case errors.Is(err, errAnubisSolveFailed):
+		logger.Warn("readability anubis solve failed", "module", "service", "action", "fetch", "resource", "entry", "result", "failed", "host", host, "error", err)
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	if retryCount > 0 {
+		logger.Debug("readability solved anubis challenge", "module", "service", "action", "fetch", "resource", "entry", "result", "ok", "host", host, "retry_count", retryCount)
+	}
+	return attempt.body, contentType, nil
+}
+
+// doFetch performs one HTTP request with the given session, sending the Anubis
+// cookie cached for the host when cookie is empty, and returns the response
+// with its Content-Type header
+func (s *readabilityService) doFetch(ctx context.Context, session *azuretls.Session, targetURL, cookie string) (anubisAttempt, string, error) {
 	parsedURL, err := url.Parse(targetURL)
 	if err != nil {
-		return nil, ErrFeedFetch
+		return anubisAttempt{}, "", ErrFeedFetch
 	}
 
 	if parsedURL.Scheme != "http" && parsedURL.Scheme != "https" {
-		return nil, ErrInvalid
+		return anubisAttempt{}, "", ErrInvalid
 	}
 
 	headers := azuretls.OrderedHeaders{
-		{"accept", "text/html,application/xhtml+xml,application/xml;q=0.9,image/avif,image/webp,image/apng,*/*;q=0.8,application/signed-exchange;v=b3;q=0.7"},
+		{"accept", "text/html,application/xhtml+xml,application/xml;q=0.9,application/pdf;q=0.9,text/plain;q=0.8,image/avif,image/webp,image/apng,*/*;q=0.8,application/signed-exchange;v=b3;q=0.7"},
 		{"accept-language", "zh-CN,zh;q=0.9"},
 		{"cache-control", "max-age=0"},
 		{"priority", "u=0, i"},
@@ -164,12 +387,11 @@ func (s *readabilityService) doFetch(ctx context.Context, session *azuretls.Sess
 	}
 
 	requestHeaders := orderedHeadersToHTTPHeader(headers)
+	if cookie == "" {
+		cookie = getCachedAnubisCookie(ctx, s.anubis, parsedURL.Host, requestHeaders)
+	}
 	if cookie != "" {
 		headers = append(headers, []string{"cookie", cookie})
-	} else {
-		if cachedCookie := getCachedAnubisCookie(ctx, s.anubis, parsedURL.Host, requestHeaders); cachedCookie != "" {
-			headers = append(headers, []string{"cookie", cachedCookie})
-		}
 	}
 
 	resp, err := session.Do(&azuretls.Request{
@@ -179,35 +401,15 @@ func (s *readabilityService) doFetch(ctx context.Context, session *azuretls.Sess
 	})
 	if err != nil {
 		logger.Warn("readability request failed", "module", "service", "action", "fetch", "resource", "entry", "result", "failed", "host", parsedURL.Host, "error", err)
-		return nil, fmt.Errorf("request failed: %w", err)
+		return anubisAttempt{}, "", fmt.Errorf("request failed: %w", err)
 	}
 
 	if resp.StatusCode != http.StatusOK {
 		logger.Error("readability http error", "module", "service", "action", "fetch", "resource", "entry", "result", "failed", "host", parsedURL.Host, "status_code", resp.StatusCode)
-		return nil, fmt.Errorf("HTTP %d", resp.StatusCode)
-	}
-
-	body := resp.Body
-
-	newCookie, anubisErr := trySolveAnubisChallenge(ctx, s.anubis, body, targetURL, cookiesFromMap(resp.Cookies), requestHeaders, retryCount)
-	switch {
-	case anubisErr == nil:
-		logger.Debug("readability detected anubis challenge", "module", "service", "action", "fetch", "resource", "entry", "result", "ok", "host", parsedURL.Host)
-		return s.fetchWithFreshSession(ctx, targetURL, newCookie, retryCount+1)
-	case errors.Is(anubisErr, errAnubisNotPage):
-		// Not an Anubis page; continue normal readability parsing.
-	case errors.Is(anubisErr, errAnubisRejected):
-		logger.Warn("readability upstream rejected", "module", "service", "action", "fetch", "resource", "entry", "result", "failed", "host", parsedURL.Host)
-		return nil, fmt.Errorf("upstream rejected")
-	case errors.Is(anubisErr, errAnubisRetryExceeded):
-		logger.Warn("readability anubis persists", "module", "service", "action", "fetch", "resource", "entry", "result", "failed", "host", parsedURL.Host, "retry_count", retryCount)
-		return nil, fmt.Errorf("anubis challenge persists after %d retries", retryCount)
-	default:
-		logger.Warn("readability anubis solve failed", "module", "service", "action", "fetch", "resource", "entry", "result", "failed", "host", parsedURL.Host, "error", anubisErr)
-		return nil, fmt.Errorf("anubis solve failed: %w", anubisErr)
+		return anubisAttempt{}, "", fmt.Errorf("HTTP %d", resp.StatusCode)
 	}
 
-	return body, nil
+	return newAzureAnubisAttempt(resp, requestHeaders), resp.Header.Get("Content-Type"), nil
 }
 
 // walkTree traverses all descendant element nodes and calls fn for each.
@@ -1,11 +1,23 @@
 package service_test
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
+	"fmt"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/png"
 	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"gist/backend/internal/events"
 	"gist/backend/internal/model"
 	"gist/backend/internal/repository/mock"
 	"gist/backend/internal/service"
@@ -22,7 +34,7 @@ func TestReadabilityService_FetchReadableContent_NotFound(t *testing.T) {
 	mockEntries := mock.NewMockEntryRepository(ctrl)
 	mockEntries.EXPECT().GetByID(gomock.Any(), int64(1)).Return(model.Entry{}, sql.ErrNoRows)
 
-	svc := service.NewReadabilityService(mockEntries, nil, nil)
+	svc := service.NewReadabilityService(mockEntries, nil, nil, nil, nil, nil)
 	_, err := svc.FetchReadableContent(context.Background(), 1)
 	require.ErrorIs(t, err, service.ErrNotFound)
 }
@@ -38,7 +50,7 @@ func TestReadabilityService_FetchReadableContent_CacheHit(t *testing.T) {
 		ReadableContent: &readable,
 	}, nil)
 
-	svc := service.NewReadabilityService(mockEntries, nil, nil)
+	svc := service.NewReadabilityService(mockEntries, nil, nil, nil, nil, nil)
 	got, err := svc.FetchReadableContent(context.Background(), 1)
 	require.NoError(t, err)
 	require.Equal(t, readable, got)
@@ -51,37 +63,146 @@ func TestReadabilityService_FetchReadableContent_InvalidURL(t *testing.T) {
 	mockEntries := mock.NewMockEntryRepository(ctrl)
 	mockEntries.EXPECT().GetByID(gomock.Any(), int64(1)).Return(model.Entry{ID: 1}, nil)
 
-	svc := service.NewReadabilityService(mockEntries, nil, nil)
+	svc := service.NewReadabilityService(mockEntries, nil, nil, nil, nil, nil)
 	_, err := svc.FetchReadableContent(context.Background(), 1)
 	require.ErrorIs(t, err, service.ErrInvalid)
 }
 
 func TestReadabilityService_Close(t *testing.T) {
-	svc := service.NewReadabilityService(nil, network.NewClientFactoryForTest(&http.Client{}), nil)
+	svc := service.NewReadabilityService(nil, network.NewClientFactoryForTest(&http.Client{}), nil, nil, nil, nil)
 	svc.Close()
 }
 
 func TestReadabilityService_FetchWithChrome_InvalidURL(t *testing.T) {
-	svc := service.NewReadabilityService(nil, network.NewClientFactoryForTest(&http.Client{}), nil)
+	svc := service.NewReadabilityService(nil, network.NewClientFactoryForTest(&http.Client{}), nil, nil, nil, nil)
 
-	_, err := service.ReadabilityFetchWithChromeForTest(svc, context.Background(), "http://[::1", "", 0)
+	_, err := service.ReadabilityFetchWithChromeForTest(svc, context.Background(), "http://[::1")
 	require.ErrorIs(t, err, service.ErrFeedFetch)
 }
 
-func TestReadabilityService_FetchWithFreshSession_InvalidScheme(t *testing.T) {
-	svc := service.NewReadabilityService(nil, network.NewClientFactoryForTest(&http.Client{}), nil)
+func TestReadabilityService_FetchWithChrome_InvalidScheme(t *testing.T) {
+	svc := service.NewReadabilityService(nil, network.NewClientFactoryForTest(&http.Client{}), nil, nil, nil, nil)
 
-	_, err := service.ReadabilityFetchWithFreshSessionForTest(svc, context.Background(), "file:///etc/passwd", "", 0)
+	_, err := service.ReadabilityFetchWithChromeForTest(svc, context.Background(), "file:///etc/passwd")
 	require.ErrorIs(t, err, service.ErrInvalid)
 }
 
 func TestReadabilityService_DoFetch_InvalidURL(t *testing.T) {
-	svc := service.NewReadabilityService(nil, network.NewClientFactoryForTest(&http.Client{}), nil)
+	svc := service.NewReadabilityService(nil, network.NewClientFactoryForTest(&http.Client{}), nil, nil, nil, nil)
 
-	_, err := service.ReadabilityDoFetchForTest(svc, context.Background(), "http://[::1", "", 0)
+	_, err := service.ReadabilityDoFetchForTest(svc, context.Background(), "http://[::1", "")
 	require.ErrorIs(t, err, service.ErrFeedFetch)
 }
 
+// buildTestPDF writes a single-page PDF showing each line in its own text object.
+func buildTestPDF(lines ...string) []byte {
+	var stream bytes.Buffer
+	for i, line := range lines {
+		fmt.Fprintf(&stream, "BT /F1 12 Tf 72 %d Td (%s) Tj ET\n", 720-i*20, line)
+	}
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Contents 4 0 R /Resources << /Font << /F1 5 0 R >> >> >>",
+		fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", stream.Len(), stream.String()),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>",
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	return buf.Bytes()
+}
+
+func TestReadabilityService_FetchReadableContent_ContentTypes(t *testing.T) {
+	pdfBody := buildTestPDF("Hello from a PDF", "Second line & more")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/paper.pdf":
+			w.Header().Set("Content-Type", "application/pdf")
+			_, _ = w.Write(pdfBody)
+		case "/download":
+			// Magic bytes win over a generic header
+			w.Header().Set("Content-Type", "application/octet-stream")
+			_, _ = w.Write(pdfBody)
+		case "/notes.txt":
+			w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+			_, _ = w.Write([]byte("First <paragraph>\nwraps here.\n\nSecond paragraph."))
+		case "/image.png":
+			w.Header().Set("Content-Type", "image/png")
+			_, _ = w.Write([]byte("\x89PNG\r\n\x1a\n"))
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		path     string
+		contains []string
+		wantErr  error
+	}{
+		{name: "pdf", path: "/paper.pdf", contains: []string{"<p>Hello from a PDF</p>", "<p>Second line &amp; more</p>"}},
+		{name: "pdf sniffed", path: "/download", contains: []string{"<p>Hello from a PDF</p>"}},
+		{name: "plain text", path: "/notes.txt", contains: []string{"<p>First &lt;paragraph&gt; wraps here.</p>", "<p>Second paragraph.</p>"}},
+		{name: "unsupported", path: "/image.png", wantErr: service.ErrUnsupportedContentType},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			entryURL := server.URL + tt.path
+			mockEntries := mock.NewMockEntryRepository(ctrl)
+			mockEntries.EXPECT().GetByID(gomock.Any(), int64(1)).Return(model.Entry{ID: 1, URL: &entryURL}, nil)
+
+			var saved string
+			if tt.wantErr == nil {
+				mockEntries.EXPECT().UpdateReadableContent(gomock.Any(), int64(1), gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ int64, content string, _ int) error {
+						saved = content
+						return nil
+					})
+			}
+
+			svc := service.NewReadabilityService(mockEntries, network.NewClientFactoryForTest(&http.Client{}), nil, nil, nil, nil)
+			got, err := svc.FetchReadableContent(context.Background(), 1)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				require.Contains(t, err.Error(), "image/png")
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, saved, got)
+			for _, want := range tt.contains {
+				require.Contains(t, got, want)
+			}
+		})
+	}
+}
+
+func TestPlainTextToHTML_KeepsLayoutInPre(t *testing.T) {
+	got := service.PlainTextToHTMLForTest("Section 1\n\n   code <x>\n\tindented")
+	require.Equal(t, "<pre>Section 1\n\n   code &lt;x&gt;\n\tindented</pre>", got)
+
+	require.Empty(t, service.PlainTextToHTMLForTest(" \n\n "))
+}
+
+func TestExtractPDFText_Malformed(t *testing.T) {
+	_, err := service.ExtractPDFTextForTest([]byte("%PDF-1.4\ngarbage"))
+	require.Error(t, err)
+}
+
 // TestFixLazyImages_RuyoNet tests lazy image fix for 51.ruyo.net style pages.
 // Real case: https://51.ruyo.net/19255.html
 // These pages use data-original with a placeholder SVG in src.
@@ -347,3 +468,345 @@ func TestReadabilityService_HandlesComplexHTML(t *testing.T) {
 	// Should preserve structured elements
 	require.Contains(t, result, "List item one")
 }
+
+func TestStripLeadingTitle(t *testing.T) {
+	const body = `<p>The article starts here.</p>`
+	tests := []struct {
+		name    string
+		html    string
+		title   string
+		author  string
+		removed []string
+		kept    []string
+	}{
+		{
+			name:    "exact heading in readability wrapper",
+			html:    `<div id="readability-page-1" class="page"><div><h1>Hello, World!</h1>` + body + `</div></div>`,
+			title:   "hello world",
+			removed: []string{"<h1>"},
+		},
+		{
+			name:    "heading wrapped in a link",
+			html:    `<h2><a href="https://example.com/post">Hello   World</a></h2>` + body,
+			title:   "Hello World",
+			removed: []string{"<h2>", "<a "},
+		},
+		{
+			name:    "site name suffix",
+			html:    `<h1>Why We Rewrote the Parser - Example Blog</h1>` + body,
+			title:   "Why we rewrote the parser",
+			removed: []string{"Example Blog"},
+		},
+		{
+			name:    "near match",
+			html:    `<h1>Why we re-wrote the parser</h1>` + body,
+			title:   "Why We Rewrote The Parser",
+			removed: []string{"<h1>"},
+		},
+		{
+			name:    "byline and emptied header",
+			html:    `<header><h1>Release Notes</h1><p class="meta">By Jane Doe · March 3, 2024</p></header>` + body,
+			title:   "Release notes",
+			author:  "Jane Doe",
+			removed: []string{"<header>", "Jane Doe"},
+		},
+		{
+			name:    "byline of someone else",
+			html:    `<h1>Release Notes</h1><p>By John Roe</p>` + body,
+			title:   "Release notes",
+			author:  "Jane Doe",
+			removed: []string{"<h1>"},
+			kept:    []string{"By John Roe"},
+		},
+		{
+			name:  "different heading",
+			html:  `<h1>Release Notes for Version Two</h1>` + body,
+			title: "Release Notes",
+			kept:  []string{"<h1>Release Notes for Version Two</h1>"},
+		},
+		{
+			name:  "heading not leading",
+			html:  `<p>Intro.</p><h1>Release Notes</h1>` + body,
+			title: "Release Notes",
+			kept:  []string{"<h1>Release Notes</h1>"},
+		},
+		{
+			name:  "title as a section of the heading",
+			html:  `<h1>Introduction</h1>` + body,
+			title: "Part 1 - Introduction",
+			kept:  []string{"<h1>Introduction</h1>"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := service.StripLeadingTitleForTest(tt.html, tt.title, tt.author)
+			require.Contains(t, got, "The article starts here.")
+			for _, s := range tt.removed {
+				require.NotContains(t, got, s)
+			}
+			for _, s := range tt.kept {
+				require.Contains(t, got, s)
+			}
+		})
+	}
+}
+
+func TestReadabilityService_FetchReadableContent_StripsTitle(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write([]byte(`<!DOCTYPE html><html><head><title>Posts</title></head><body>
+<h1>Example Blog</h1>
+<article>
+<h1>Shipping Faster - Example Blog</h1>
+<p>By Jane Doe</p>
+<p>We cut our release cycle from weeks to days by automating every step that used to need a person. This post walks through what changed and why it worked for our team.</p>
+<p>The first change was moving every check into the pipeline, so reviews focus on design instead of formatting. The second was shipping behind flags, which made rollbacks cheap.</p>
+</article>
+</body></html>`))
+	}))
+	defer server.Close()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	entryURL := server.URL + "/post"
+	title, author := "Shipping faster", "Jane Doe"
+	mockEntries := mock.NewMockEntryRepository(ctrl)
+	mockEntries.EXPECT().GetByID(gomock.Any(), int64(1)).Return(model.Entry{ID: 1, URL: &entryURL, Title: &title, Author: &author}, nil)
+	mockEntries.EXPECT().UpdateReadableContent(gomock.Any(), int64(1), gomock.Any(), gomock.Any()).Return(nil)
+
+	svc := service.NewReadabilityService(mockEntries, network.NewClientFactoryForTest(&http.Client{}), nil, nil, nil, nil)
+	got, err := svc.FetchReadableContent(context.Background(), 1)
+	require.NoError(t, err)
+	require.Contains(t, got, "We cut our release cycle")
+	require.NotContains(t, got, "Example Blog")
+	require.NotContains(t, got, "By Jane Doe")
+}
+
+// nextReadableEvent waits for the entry.readable event published by a background extraction.
+func nextReadableEvent(t *testing.T, published <-chan events.Event) events.EntryReadableData {
+	t.Helper()
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case event := <-published:
+			if event.Type == events.EntryReadable {
+				return event.Data.(events.EntryReadableData)
+			}
+		case <-timeout:
+			t.Fatal("no entry.readable event")
+		}
+	}
+}
+
+func TestReadabilityService_StartReadableContent(t *testing.T) {
+	release := make(chan struct{})
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		<-release
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		_, _ = w.Write([]byte("Slow host, finally."))
+	}))
+	defer server.Close()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	entryURL := server.URL + "/post"
+	var cached *string
+	mockEntries := mock.NewMockEntryRepository(ctrl)
+	mockEntries.EXPECT().GetByID(gomock.Any(), int64(1)).DoAndReturn(func(context.Context, int64) (model.Entry, error) {
+		return model.Entry{ID: 1, URL: &entryURL, ReadableContent: cached}, nil
+	}).AnyTimes()
+	mockEntries.EXPECT().UpdateReadableContent(gomock.Any(), int64(1), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ int64, content string, _ int) error {
+			cached = &content
+			return nil
+		})
+
+	bus := events.NewBus(events.SubscriberBuffer)
+	published, unsubscribe := bus.Subscribe()
+	defer unsubscribe()
+
+	svc := service.NewReadabilityService(mockEntries, network.NewClientFactoryForTest(&http.Client{}), nil, nil, nil, bus)
+	defer svc.Close()
+	ctx := context.Background()
+
+	got, err := svc.StartReadableContent(ctx, 1)
+	require.NoError(t, err)
+	require.Equal(t, service.ReadableResult{Status: service.ReadablePending}, got)
+	got, err = svc.StartReadableContent(ctx, 1)
+	require.NoError(t, err)
+	require.Equal(t, service.ReadablePending, got.Status)
+
+	// A blocking caller that gives up joins the same extraction and leaves it running
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = svc.FetchReadableContent(cancelled, 1)
+	require.ErrorIs(t, err, context.Canceled)
+
+	close(release)
+	require.Equal(t, events.EntryReadableData{EntryID: 1, Status: service.ReadableReady}, nextReadableEvent(t, published))
+	require.Equal(t, int32(1), hits.Load())
+
+	got, err = svc.StartReadableContent(ctx, 1)
+	require.NoError(t, err)
+	require.Equal(t, service.ReadableReady, got.Status)
+	require.Contains(t, got.Content, "Slow host, finally.")
+}
+
+func TestReadabilityService_StartReadableContent_Failure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		_, _ = w.Write([]byte("\x89PNG\r\n\x1a\n"))
+	}))
+	defer server.Close()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	entryURL := server.URL + "/image.png"
+	mockEntries := mock.NewMockEntryRepository(ctrl)
+	mockEntries.EXPECT().GetByID(gomock.Any(), int64(1)).Return(model.Entry{ID: 1, URL: &entryURL}, nil).Times(2)
+
+	bus := events.NewBus(events.SubscriberBuffer)
+	published, unsubscribe := bus.Subscribe()
+	defer unsubscribe()
+
+	svc := service.NewReadabilityService(mockEntries, network.NewClientFactoryForTest(&http.Client{}), nil, nil, nil, bus)
+	defer svc.Close()
+	ctx := context.Background()
+
+	got, err := svc.StartReadableContent(ctx, 1)
+	require.NoError(t, err)
+	require.Equal(t, service.ReadablePending, got.Status)
+
+	event := nextReadableEvent(t, published)
+	require.Equal(t, service.ReadableFailed, event.Status)
+	require.Contains(t, event.Error, "image/png")
+
+	// The failure is reported once, then asking again retries
+	_, err = svc.StartReadableContent(ctx, 1)
+	require.ErrorIs(t, err, service.ErrUnsupportedContentType)
+	got, err = svc.StartReadableContent(ctx, 1)
+	require.NoError(t, err)
+	require.Equal(t, service.ReadablePending, got.Status)
+	nextReadableEvent(t, published)
+}
+
+// encodeTestImage encodes a 4x4 image filled with c, as GIF when asGIF is set.
+func encodeTestImage(t *testing.T, c color.Color, asGIF bool) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if asGIF {
+		img := image.NewPaletted(image.Rect(0, 0, 4, 4), color.Palette{c, color.Black})
+		require.NoError(t, gif.Encode(&buf, img, nil))
+		return buf.Bytes()
+	}
+	img := image.NewNRGBA(image.Rect(0, 0, 4, 4))
+	for x := 0; x < 4; x++ {
+		for y := 0; y < 4; y++ {
+			img.Set(x, y, c)
+		}
+	}
+	require.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+func TestReadabilityService_FetchReadableContent_MarksTransparentImages(t *testing.T) {
+	blank := color.NRGBA{}
+	opaque := color.NRGBA{R: 255, G: 255, B: 255, A: 255}
+	palette := image.NewPaletted(image.Rect(0, 0, 4, 4), color.Palette{blank, color.Black})
+	var palettePNG bytes.Buffer
+	require.NoError(t, png.Encode(&palettePNG, palette))
+	images := map[string][]byte{
+		"/alpha.png":   encodeTestImage(t, blank, false),
+		"/opaque.png":  encodeTestImage(t, opaque, false),
+		"/palette.png": palettePNG.Bytes(),
+		"/clear.gif":   encodeTestImage(t, blank, true),
+		"/solid.gif":   encodeTestImage(t, opaque, true),
+	}
+
+	var imageRequests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/post" {
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			_, _ = w.Write([]byte(`<!DOCTYPE html><html><head><title>Diagrams</title></head><body><article>
+<p>Our architecture has grown over the years, and these diagrams show how requests flow between the services that make it up today.</p>
+<p><img src="/alpha.png" alt="alpha"><img src="/opaque.png" alt="opaque"><img src="/palette.png" alt="palette"></p>
+<p>Each service owns its data and talks to the others through queues, which keeps failures contained to the service where they started.</p>
+<p><img src="/clear.gif" alt="clear"><img src="/solid.gif" alt="solid"><img src="/missing.png" alt="missing"><img src="/photo.jpg" alt="photo"><img src="/alpha.png" alt="again"></p>
+<p>The rest of this post walks through how we got here and what we would do differently if we started over.</p>
+</article></body></html>`))
+			return
+		}
+		imageRequests.Add(1)
+		require.Equal(t, "bytes=0-65535", r.Header.Get("Range"))
+		data, ok := images[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write(data)
+	}))
+	defer server.Close()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	entryURL := server.URL + "/post"
+	mockEntries := mock.NewMockEntryRepository(ctrl)
+	mockEntries.EXPECT().GetByID(gomock.Any(), int64(1)).Return(model.Entry{ID: 1, URL: &entryURL}, nil)
+	mockEntries.EXPECT().UpdateReadableContent(gomock.Any(), int64(1), gomock.Any(), gomock.Any()).Return(nil)
+
+	svc := service.NewReadabilityService(mockEntries, network.NewClientFactoryForTest(&http.Client{}), nil, nil, nil, nil)
+	got, err := svc.FetchReadableContent(context.Background(), 1)
+	require.NoError(t, err)
+
+	transparent := regexp.MustCompile(`<img[^>]*alt="(\w+)"[^>]*data-transparent="true"`).FindAllStringSubmatch(got, -1)
+	var marked []string
+	for _, m := range transparent {
+		marked = append(marked, m[1])
+	}
+	require.Equal(t, []string{"alpha", "palette", "clear", "again"}, marked)
+	// The JPEG is never fetched and the repeated image is fetched once
+	require.Equal(t, int32(6), imageRequests.Load())
+}
+
+func TestReadabilityService_FetchReadableContent_CapsTransparencyChecks(t *testing.T) {
+	var imageRequests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/post" {
+			var page strings.Builder
+			page.WriteString(`<!DOCTYPE html><html><head><title>Gallery</title></head><body><article>
+<p>Here is every diagram we drew while planning the migration, in the order we drew them, with notes on what each one taught us.</p><p>`)
+			for i := 0; i < 30; i++ {
+				fmt.Fprintf(&page, `<img src="/%d.png" alt="diagram %d">`, i, i)
+			}
+			page.WriteString(`</p><p>Most of them turned out wrong, which is the point of drawing them before writing any code at all.</p></article></body></html>`)
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			_, _ = w.Write([]byte(page.String()))
+			return
+		}
+		imageRequests.Add(1)
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	entryURL := server.URL + "/post"
+	mockEntries := mock.NewMockEntryRepository(ctrl)
+	mockEntries.EXPECT().GetByID(gomock.Any(), int64(1)).Return(model.Entry{ID: 1, URL: &entryURL}, nil)
+	mockEntries.EXPECT().UpdateReadableContent(gomock.Any(), int64(1), gomock.Any(), gomock.Any()).Return(nil)
+
+	svc := service.NewReadabilityService(mockEntries, network.NewClientFactoryForTest(&http.Client{}), nil, nil, nil, nil)
+	got, err := svc.FetchReadableContent(context.Background(), 1)
+	require.NoError(t, err)
+	require.Contains(t, got, `alt="diagram 29"`)
+	require.NotContains(t, got, "data-transparent")
+	require.Equal(t, int32(20), imageRequests.Load())
+}
@@ -0,0 +1,219 @@
+package service
+
+import (
+	"bytes"
+	"strings"
+	"unicode"
+
+	"golang.org/x/net/html"
+)
+
+// titleSimilarityThreshold is the bigram similarity above which a heading counts
+// as the entry title. It is high so only punctuation-level differences pass.
+const titleSimilarityThreshold = 0.85
+
+// bylineMaxExtraWords bounds the words besides the author's name a byline may
+// carry, such as "By" and a date.
+const bylineMaxExtraWords = 8
+
+// titleSeparators split a site name from a title, as in "Title - Example Blog".
+var titleSeparators = []string{" - ", " – ", " — ", " | ", " · ", " :: "}
+
+// stripLeadingTitle removes a heading at the start of the article that repeats
+// the entry title, and the byline right after it when it names the author.
+func stripLeadingTitle(htmlContent []byte, title, author string) []byte {
+	if normalizeTitleText(title) == "" {
+		return htmlContent
+	}
+	doc, err := html.Parse(bytes.NewReader(htmlContent))
+	if err != nil {
+		return htmlContent
+	}
+
+	var body *html.Node
+	walkTree(doc, func(n *html.Node) {
+		if body == nil && n.Data == "body" {
+			body = n
+		}
+	})
+	if body == nil {
+		return htmlContent
+	}
+	heading := leadingHeading(body)
+	if heading == nil || !headingMatchesTitle(nodeText(heading), title) {
+		return htmlContent
+	}
+
+	parent := heading.Parent
+	if byline := nextSignificantSibling(heading); byline != nil && isByline(byline, author) {
+		parent.RemoveChild(byline)
+	}
+	parent.RemoveChild(heading)
+	// Drop wrappers such as <header> that held nothing else
+	for parent != body && firstSignificantChild(parent) == nil {
+		next := parent.Parent
+		next.RemoveChild(parent)
+		parent = next
+	}
+
+	var buf bytes.Buffer
+	if err := html.Render(&buf, doc); err != nil {
+		return htmlContent
+	}
+	return buf.Bytes()
+}
+
+// leadingHeading returns the heading the content starts with, looking through
+// wrapper elements, or nil when the content starts with anything else.
+func leadingHeading(n *html.Node) *html.Node {
+	for {
+		child := firstSignificantChild(n)
+		if child == nil || child.Type != html.ElementNode {
+			return nil
+		}
+		switch child.Data {
+		case "h1", "h2", "h3", "h4", "h5", "h6":
+			return child
+		case "div", "section", "article", "header", "hgroup", "main":
+			n = child
+		default:
+			return nil
+		}
+	}
+}
+
+func firstSignificantChild(n *html.Node) *html.Node {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if isSignificant(c) {
+			return c
+		}
+	}
+	return nil
+}
+
+func nextSignificantSibling(n *html.Node) *html.Node {
+	for c := n.NextSibling; c != nil; c = c.NextSibling {
+		if isSignificant(c) {
+			return c
+		}
+	}
+	return nil
+}
+
+// isSignificant reports whether n is an element or non-blank text.
+func isSignificant(n *html.Node) bool {
+	switch n.Type {
+	case html.ElementNode:
+		return true
+	case html.TextNode:
+		return strings.TrimSpace(n.Data) != ""
+	}
+	return false
+}
+
+// nodeText returns the text of n and its descendants.
+func nodeText(n *html.Node) string {
+	var sb strings.Builder
+	var collect func(*html.Node)
+	collect = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			sb.WriteString(n.Data)
+			sb.WriteByte(' ')
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			collect(c)
+		}
+	}
+	collect(n)
+	return sb.String()
+}
+
+// isByline reports whether n is a short line naming author, with no media.
+func isByline(n *html.Node, author string) bool {
+	name := normalizeTitleText(author)
+	if name == "" || n.Type != html.ElementNode {
+		return false
+	}
+	switch n.Data {
+	case "p", "div", "span", "address", "small", "time":
+	default:
+		return false
+	}
+	hasMedia := false
+	walkTree(n, func(c *html.Node) {
+		switch c.Data {
+		case "img", "picture", "video", "iframe", "figure":
+			hasMedia = true
+		}
+	})
+	if hasMedia {
+		return false
+	}
+
+	line := normalizeTitleText(nodeText(n))
+	if !strings.Contains(" "+line+" ", " "+name+" ") {
+		return false
+	}
+	return len(strings.Fields(line))-len(strings.Fields(name)) <= bylineMaxExtraWords
+}
+
+// headingMatchesTitle compares heading and title ignoring case, whitespace and
+// punctuation. The heading may carry a site name after a separator, and a near
+// match above titleSimilarityThreshold also counts.
+func headingMatchesTitle(heading, title string) bool {
+	t := normalizeTitleText(title)
+	for _, h := range headingVariants(heading) {
+		if h == t || titleSimilarity(h, t) >= titleSimilarityThreshold {
+			return true
+		}
+	}
+	return false
+}
+
+// headingVariants returns the normalized heading and, when it has a site name
+// separator, its first and last parts.
+func headingVariants(s string) []string {
+	variants := []string{normalizeTitleText(s)}
+	for _, sep := range titleSeparators {
+		if first, _, ok := strings.Cut(s, sep); ok {
+			last := s[strings.LastIndex(s, sep)+len(sep):]
+			variants = append(variants, normalizeTitleText(first), normalizeTitleText(last))
+		}
+	}
+	result := variants[:0]
+	for _, v := range variants {
+		if v != "" {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// normalizeTitleText lowercases s and reduces it to words of letters and digits.
+func normalizeTitleText(s string) string {
+	fields := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	return strings.Join(fields, " ")
+}
+
+// titleSimilarity is the Dice coefficient of the character bigrams of a and b.
+func titleSimilarity(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) < 2 || len(rb) < 2 {
+		return 0
+	}
+	bigrams := make(map[[2]rune]int, len(ra))
+	for i := 0; i+1 < len(ra); i++ {
+		bigrams[[2]rune{ra[i], ra[i+1]}]++
+	}
+	shared := 0
+	for i := 0; i+1 < len(rb); i++ {
+		key := [2]rune{rb[i], rb[i+1]}
+		if bigrams[key] > 0 {
+			bigrams[key]--
+			shared++
+		}
+	}
+	return 2 * float64(shared) / float64(len(ra)+len(rb)-2)
+}
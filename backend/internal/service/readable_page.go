@@ -0,0 +1,150 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+
+	"gist/backend/pkg/logger"
+	"gist/backend/pkg/network"
+)
+
+// ReadablePage is a web page as FetchPage extracted it. Title falls back to
+// the URL, the other fields may be empty.
+type ReadablePage struct {
+	Title        string
+	Content      string
+	ThumbnailURL string
+	Author       string
+}
+
+// pageMeta is what the head of an HTML page says about it, for pages the
+// readability parser makes nothing of.
+type pageMeta struct {
+	title       string
+	image       string
+	description string
+}
+
+func (s *readabilityService) FetchPage(ctx context.Context, pageURL string) (ReadablePage, error) {
+	body, contentType, err := s.fetchWithChrome(ctx, pageURL)
+	if err != nil {
+		logger.Warn("page fetch failed", "module", "service", "action", "fetch", "resource", "entry", "result", "failed", "host", network.ExtractHost(pageURL), "error", err)
+		return ReadablePage{}, err
+	}
+	kind, err := detectReadableKind(contentType, body)
+	if err != nil {
+		logger.Warn("page unsupported content", "module", "service", "action", "fetch", "resource", "entry", "result", "failed", "host", network.ExtractHost(pageURL), "error", err)
+		return ReadablePage{}, err
+	}
+
+	var page ReadablePage
+	var rendered []byte
+	switch kind {
+	case readablePDF:
+		lines, err := extractPDFText(body)
+		if err != nil {
+			return ReadablePage{}, fmt.Errorf("parse pdf failed: %w", err)
+		}
+		rendered = paragraphsToHTML(lines)
+	case readableText:
+		rendered = plainTextToHTML(string(body))
+	default:
+		meta := parsePageMeta(pageURL, body)
+		page.Title, page.ThumbnailURL = meta.title, meta.image
+		if article, err := parseArticle(pageURL, body); err == nil {
+			if title := strings.TrimSpace(article.Title()); title != "" {
+				page.Title = title
+			}
+			if image := resolvePageURL(pageURL, article.ImageURL()); image != "" {
+				page.ThumbnailURL = image
+			}
+			page.Author = strings.TrimSpace(article.Byline())
+			if rendered, err = renderArticle(article); err == nil {
+				rendered = stripLeadingTitle(rendered, page.Title, page.Author)
+			}
+		} else {
+			logger.Debug("page parse failed", "module", "service", "action", "fetch", "resource", "entry", "result", "failed", "host", network.ExtractHost(pageURL), "error", err)
+		}
+		if len(bytes.TrimSpace(rendered)) == 0 && meta.description != "" {
+			rendered = []byte("<p>" + html.EscapeString(meta.description) + "</p>")
+		}
+	}
+	if rendered != nil {
+		page.Content = removeMetadataElements(rendered)
+	}
+	if page.Title == "" {
+		page.Title = pageURL
+	}
+	return page, nil
+}
+
+// parsePageMeta reads the Open Graph title, image and description of a page,
+// falling back to its <title> and description meta tag.
+func parsePageMeta(pageURL string, body []byte) pageMeta {
+	doc, err := html.Parse(bytes.NewReader(body))
+	if err != nil {
+		return pageMeta{}
+	}
+	var meta pageMeta
+	var docTitle, description string
+	walkTree(doc, func(n *html.Node) {
+		switch n.Data {
+		case "title":
+			if docTitle == "" && n.FirstChild != nil {
+				docTitle = strings.TrimSpace(n.FirstChild.Data)
+			}
+		case "meta":
+			var key, content string
+			for _, attr := range n.Attr {
+				switch strings.ToLower(attr.Key) {
+				case "property", "name":
+					key = strings.ToLower(attr.Val)
+				case "content":
+					content = strings.TrimSpace(attr.Val)
+				}
+			}
+			switch key {
+			case "og:title":
+				meta.title = content
+			case "og:image", "twitter:image":
+				if meta.image == "" {
+					meta.image = resolvePageURL(pageURL, content)
+				}
+			case "og:description":
+				meta.description = content
+			case "description":
+				description = content
+			}
+		}
+	})
+	if meta.title == "" {
+		meta.title = docTitle
+	}
+	if meta.description == "" {
+		meta.description = description
+	}
+	return meta
+}
+
+// resolvePageURL resolves ref against the page, empty unless it makes an
+// http(s) URL.
+func resolvePageURL(pageURL, ref string) string {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return ""
+	}
+	base, err := url.Parse(pageURL)
+	if err != nil {
+		return ""
+	}
+	resolved, err := base.Parse(ref)
+	if err != nil || !visitableURL(resolved.String()) {
+		return ""
+	}
+	return resolved.String()
+}
@@ -0,0 +1,65 @@
+package service_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"gist/backend/internal/service"
+	"gist/backend/pkg/network"
+)
+
+func TestReadabilityService_FetchPage(t *testing.T) {
+	paragraph := "<p>" + strings.Repeat("Saved pages keep their readable content for later. ", 20) + "</p>"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/article":
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			_, _ = w.Write([]byte(`<html><head><title>Article | Site</title>
+<meta property="og:title" content="Article">
+<meta property="og:image" content="/cover.jpg">
+</head><body><article><h1>Article</h1>` + paragraph + paragraph + `</article></body></html>`))
+		case "/empty":
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			_, _ = w.Write([]byte(`<html><head><title>App</title>
+<meta name="twitter:image" content="javascript:alert(1)">
+<meta name="description" content="A page that <renders> in the browser">
+</head><body><div id="root"></div></body></html>`))
+		case "/notes.txt":
+			w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+			_, _ = w.Write([]byte("Some notes."))
+		case "/image.png":
+			w.Header().Set("Content-Type", "image/png")
+			_, _ = w.Write([]byte("\x89PNG\r\n\x1a\n"))
+		}
+	}))
+	defer server.Close()
+
+	svc := service.NewReadabilityService(nil, network.NewClientFactoryForTest(&http.Client{}), nil, nil, nil, nil)
+	defer svc.Close()
+
+	page, err := svc.FetchPage(context.Background(), server.URL+"/article")
+	require.NoError(t, err)
+	require.Equal(t, "Article", page.Title)
+	require.Equal(t, server.URL+"/cover.jpg", page.ThumbnailURL)
+	require.Contains(t, page.Content, "Saved pages keep their readable content")
+	require.NotContains(t, page.Content, "<h1>")
+
+	page, err = svc.FetchPage(context.Background(), server.URL+"/empty")
+	require.NoError(t, err)
+	require.Equal(t, "App", page.Title)
+	require.Empty(t, page.ThumbnailURL)
+	require.Contains(t, page.Content, "<p>A page that &lt;renders&gt; in the browser</p>")
+
+	page, err = svc.FetchPage(context.Background(), server.URL+"/notes.txt")
+	require.NoError(t, err)
+	require.Equal(t, server.URL+"/notes.txt", page.Title)
+	require.Contains(t, page.Content, "Some notes.")
+
+	_, err = svc.FetchPage(context.Background(), server.URL+"/image.png")
+	require.ErrorIs(t, err, service.ErrUnsupportedContentType)
+}
@@ -6,11 +6,11 @@ import (
 	"gist/backend/internal/model"
 )
 
-// RefreshFeedWithFreshClientForTest exposes refreshFeedWithFreshClient for tests.
-func RefreshFeedWithFreshClientForTest(svc RefreshService, ctx context.Context, feed model.Feed, userAgent, cookie string, retryCount int) error {
+// RefreshFeedWithUAForTest exposes refreshFeedWithUA, without the UA fallback, for tests.
+func RefreshFeedWithUAForTest(svc RefreshService, ctx context.Context, feed model.Feed, userAgent string) error {
 	impl, ok := svc.(*refreshService)
 	if !ok {
 		return ErrInvalid
 	}
-	return impl.refreshFeedWithFreshClient(ctx, feed, userAgent, cookie, retryCount)
+	return impl.refreshFeedWithUA(ctx, feed, feedUserAgent{choice: model.FeedUserAgentDefault, value: userAgent}, false)
 }
@@ -2,8 +2,8 @@
 package service
 
 import (
-	"bytes"
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
 	"io"
@@ -16,57 +16,88 @@ import (
 	"golang.org/x/sync/semaphore"
 
 	"gist/backend/internal/config"
+	"gist/backend/internal/events"
 	"gist/backend/internal/model"
 	"gist/backend/internal/repository"
+	"gist/backend/pkg/langdetect"
 	"gist/backend/pkg/logger"
 	"gist/backend/pkg/network"
 )
 
 const refreshTimeout = 30 * time.Second
 
-const (
-	// maxConcurrentRefresh limits parallel feed refreshes to avoid overwhelming
-	// the network and remote servers.
-	maxConcurrentRefresh = 8
-	// maxConcurrentPerHost limits parallel requests to the same host to be polite.
-	maxConcurrentPerHost = 1
-)
+// refreshRunKeep is how many refresh runs are kept in history.
+const refreshRunKeep = 50
 
 // hostRateLimiter manages per-host concurrency and rate limits.
 type hostRateLimiter struct {
 	mu          sync.Mutex
-	semaphores  map[string]*semaphore.Weighted
+	semaphores  map[string]*hostSemaphore
 	lastRequest map[string]time.Time
 	getInterval func(host string) time.Duration
+	// getMaxConcurrent sizes a host's semaphore when it is first used; nil or
+	// a non-positive result means DefaultMaxConcurrentPerHost.
+	getMaxConcurrent func(host string) int
+}
+
+// hostSemaphore is dropped once no request holds or waits on it, so a
+// long-lived limiter reads the host's limit again the next time it is busy.
+type hostSemaphore struct {
+	sem   *semaphore.Weighted
+	users int
 }
 
-func newHostRateLimiter(getInterval func(host string) time.Duration) *hostRateLimiter {
+func newHostRateLimiter(getInterval func(host string) time.Duration, getMaxConcurrent func(host string) int) *hostRateLimiter {
 	return &hostRateLimiter{
-		semaphores:  make(map[string]*semaphore.Weighted),
-		lastRequest: make(map[string]time.Time),
-		getInterval: getInterval,
+		semaphores:       make(map[string]*hostSemaphore),
+		lastRequest:      make(map[string]time.Time),
+		getInterval:      getInterval,
+		getMaxConcurrent: getMaxConcurrent,
 	}
 }
 
-// acquireSemaphore acquires the per-host semaphore to ensure serial execution for the same host.
+// acquireSemaphore acquires the per-host semaphore to bound parallel requests to the same host.
 // This does NOT occupy global concurrency slots, allowing different hosts to queue in parallel.
 func (h *hostRateLimiter) acquireSemaphore(ctx context.Context, host string) error {
 	h.mu.Lock()
-	sem, ok := h.semaphores[host]
+	hs, ok := h.semaphores[host]
 	if !ok {
-		sem = semaphore.NewWeighted(maxConcurrentPerHost)
-		h.semaphores[host] = sem
+		limit := DefaultMaxConcurrentPerHost
+		if h.getMaxConcurrent != nil {
+			if n := h.getMaxConcurrent(host); n > 0 {
+				limit = n
+			}
+		}
+		hs = &hostSemaphore{sem: semaphore.NewWeighted(int64(limit))}
+		h.semaphores[host] = hs
 	}
+	hs.users++
 	h.mu.Unlock()
 
-	return sem.Acquire(ctx, 1)
+	if err := hs.sem.Acquire(ctx, 1); err != nil {
+		h.leave(host, hs)
+		return err
+	}
+	return nil
 }
 
 // releaseSemaphore releases the per-host semaphore.
 func (h *hostRateLimiter) releaseSemaphore(host string) {
 	h.mu.Lock()
-	if sem, ok := h.semaphores[host]; ok {
-		sem.Release(1)
+	hs, ok := h.semaphores[host]
+	h.mu.Unlock()
+	if ok {
+		hs.sem.Release(1)
+		h.leave(host, hs)
+	}
+}
+
+// leave drops a user of the host's semaphore, and the semaphore with its last one.
+func (h *hostRateLimiter) leave(host string, hs *hostSemaphore) {
+	h.mu.Lock()
+	hs.users--
+	if hs.users == 0 && h.semaphores[host] == hs {
+		delete(h.semaphores, host)
 	}
 	h.mu.Unlock()
 }
@@ -106,7 +137,8 @@ func (h *hostRateLimiter) recordRequest(host string) {
 
 // processParsedFeed handles the common logic after successfully parsing a feed.
 // It clears error messages, updates ETag/LastModified, saves entries, and fetches icons.
-func (s *refreshService) processParsedFeed(ctx context.Context, feed model.Feed, parsed *gofeed.Feed, resp *http.Response) error {
+// It returns how many new entries were saved.
+func (s *refreshService) processParsedFeed(ctx context.Context, feed model.Feed, parsed *gofeed.Feed, resp *http.Response) int {
 	// Clear error message on successful refresh
 	feed.ErrorMessage = nil
 	_ = s.feeds.UpdateErrorMessage(ctx, feed.ID, nil)
@@ -121,15 +153,23 @@ func (s *refreshService) processParsedFeed(ctx context.Context, feed model.Feed,
 		if newLastModified != "" {
 			feed.LastModified = &newLastModified
 		}
-		if _, err := s.feeds.Update(ctx, feed); err != nil {
+		if err := s.feeds.UpdateConditionalGet(ctx, feed.ID, feed.ETag, feed.LastModified); err != nil {
 			logger.Warn("update feed etag failed", "module", "service", "action", "update", "resource", "feed", "result", "failed", "feed_id", feed.ID, "feed_title", feed.Title, "error", err)
 		}
 	}
 
+	s.updatePollHint(ctx, feed, parsed)
+	feed = s.updateTitle(ctx, feed, parsed)
+	feed = s.updateLanguage(ctx, feed, parsed)
+
 	// Save entries
-	newCount, updatedCount := s.saveEntries(ctx, feed.ID, parsed.Items)
-	if newCount > 0 || updatedCount > 0 {
-		logger.Info("feed refreshed", "module", "service", "action", "refresh", "resource", "feed", "result", "ok", "feed_id", feed.ID, "feed_title", feed.Title, "new", newCount, "updated", updatedCount)
+	newCount, updatedCount, evictedCount := s.saveEntries(ctx, feed, parsed.Items)
+	if outcome := feedOutcomeFrom(ctx); outcome != nil {
+		outcome.newCount, outcome.updatedCount, outcome.evictedCount = newCount, updatedCount, evictedCount
+		outcome.errMsg = nil
+	}
+	if newCount > 0 || updatedCount > 0 || evictedCount > 0 {
+		logger.Info("feed refreshed", "module", "service", "action", "refresh", "resource", "feed", "result", "ok", "feed_id", feed.ID, "feed_title", feed.Title, "new", newCount, "updated", updatedCount, "evicted", evictedCount)
 	}
 
 	// Backfill siteURL if empty (for feeds added before siteURL was implemented)
@@ -143,58 +183,111 @@ func (s *refreshService) processParsedFeed(ctx context.Context, feed model.Feed,
 
 	// Fetch icon if feed doesn't have one
 	if s.icons != nil && (feed.IconPath == nil || *feed.IconPath == "") {
-		imageURL := ""
-		if parsed.Image != nil {
-			imageURL = strings.TrimSpace(parsed.Image.URL)
-		}
+		imageURL := feedIconURL(parsed)
 		siteURL := feed.URL
 		if feed.SiteURL != nil && *feed.SiteURL != "" {
 			siteURL = *feed.SiteURL
 		}
-		if iconPath, err := s.icons.FetchAndSaveIcon(ctx, imageURL, siteURL); err == nil && iconPath != "" {
-			_ = s.feeds.UpdateIconPath(ctx, feed.ID, iconPath)
-		}
+		saveFeedIcon(ctx, s.icons, s.feeds, feed.ID, feed.DisplayTitle(), imageURL, siteURL)
 	}
 
-	return nil
+	return newCount
 }
 
-// saveEntries saves parsed feed items to the database.
-// Returns the count of new and updated entries.
-func (s *refreshService) saveEntries(ctx context.Context, feedID int64, items []*gofeed.Item) (newCount, updatedCount int) {
-	dynamicTime := hasDynamicTime(items)
-	for _, item := range items {
-		entry := itemToEntry(feedID, item, dynamicTime)
-		if entry.URL == nil || *entry.URL == "" {
-			continue
-		}
+// saveEntries saves parsed feed items to the database, then trims the feed to
+// its entry cap, and keeps a report of the items skipped or altered on the way.
+// Returns the count of new, updated and evicted entries.
+func (s *refreshService) saveEntries(ctx context.Context, feed model.Feed, items []*gofeed.Item) (newCount, updatedCount, evictedCount int) {
+	general := loadGeneralSettings(ctx, s.settings)
+	revisionLimit := entryRevisionLimit(general)
+	var report ingestReportBuilder
+	entries := prepareEntries(feed, items, initialBackfillFrom(ctx), general, &report)
 
-		exists, err := s.entries.ExistsByHash(ctx, feedID, entry.Hash)
-		if err != nil {
-			logger.Warn("check entry exists failed", "module", "service", "action", "list", "resource", "entry", "result", "failed", "error", err)
-			continue
-		}
-		if !exists {
-			legacyExists, err := s.entries.ExistsByLegacyURL(ctx, feedID, *entry.URL, entry.Hash)
-			if err != nil {
-				logger.Warn("check legacy entry exists failed", "module", "service", "action", "list", "resource", "entry", "result", "failed", "error", err)
-				continue
-			}
-			exists = legacyExists
-		}
+	newCount, updatedCount, err := s.storeEntries(ctx, feed, entries, revisionLimit)
+	if err != nil {
+		logger.Warn("save entries failed", "module", "service", "action", "save", "resource", "entry", "result", "failed", "feed_id", feed.ID, "count", len(entries), "error", err)
+		return 0, 0, 0
+	}
+	s.saveIngestReport(ctx, feed, report.build(len(items), len(entries)))
+	return newCount, updatedCount, s.evictOverCap(ctx, feed)
+}
 
-		if err := s.entries.CreateOrUpdate(ctx, entry); err != nil {
-			logger.Warn("save entry failed", "module", "service", "action", "save", "resource", "entry", "result", "failed", "error", err)
-			continue
+// evictOverCap deletes the feed's oldest read entries beyond feed.MaxEntries and
+// returns how many went. Unread entries are never evicted, so a feed whose unread
+// entries alone exceed the cap stays over it.
+func (s *refreshService) evictOverCap(ctx context.Context, feed model.Feed) int {
+	if feed.MaxEntries <= 0 {
+		return 0
+	}
+	evicted, unread, err := s.entries.EvictOverCap(context.WithoutCancel(ctx), feed.ID, feed.MaxEntries)
+	if err != nil {
+		logger.Warn("evict entries failed", "module", "service", "action", "delete", "resource", "entry", "result", "failed", "feed_id", feed.ID, "max_entries", feed.MaxEntries, "error", err)
+		return 0
+	}
+	if unread > feed.MaxEntries {
+		logger.Warn("unread entries exceed feed cap", "module", "service", "action", "delete", "resource", "entry", "result", "skipped", "feed_id", feed.ID, "feed_title", feed.Title, "max_entries", feed.MaxEntries, "unread", unread)
+	}
+	return evicted
+}
+
+// storeEntries saves converted entries of feed, announces the new ones and
+// caches their images.
+func (s *refreshService) storeEntries(ctx context.Context, feed model.Feed, entries []model.Entry, revisionLimit int) (int, int, error) {
+	s.markMutedRead(ctx, feed, entries)
+	// Keep cached image links so unchanged content doesn't look edited
+	if s.images != nil {
+		s.images.RewriteEntries(ctx, feed, entries)
+	}
+
+	// One transaction per feed: a failed save leaves the feed's entries untouched.
+	// A fetched feed is saved even when the refresh is cancelled meanwhile, so
+	// shutting down only stops the fetches still to come.
+	newCount, updatedCount, err := s.entries.SaveBatch(context.WithoutCancel(ctx), feed.ID, entries, revisionLimit)
+	if err != nil {
+		return 0, 0, err
+	}
+	if newCount > 0 {
+		s.bus.Publish(events.Event{Type: events.EntriesNew, Data: events.EntriesNewData{FeedID: feed.ID, Count: newCount}})
+	}
+
+	if s.images != nil {
+		hashes := make([]string, 0, len(entries))
+		for _, entry := range entries {
+			hashes = append(hashes, entry.Hash)
 		}
+		s.images.CacheEntries(ctx, feed, hashes)
+	}
+	return newCount, updatedCount, nil
+}
 
-		if exists {
-			updatedCount++
-		} else {
-			newCount++
+// markMutedRead marks entries by authors muted in feed as read, so new ones
+// arrive read. Stored entries keep their read state.
+func (s *refreshService) markMutedRead(ctx context.Context, feed model.Feed, entries []model.Entry) {
+	authors, err := s.feeds.ListMutedAuthors(ctx, feed.ID)
+	if err != nil {
+		logger.Warn("list muted authors failed", "module", "service", "action", "list", "resource", "feed", "result", "failed", "feed_id", feed.ID, "error", err)
+		return
+	}
+	if len(authors) == 0 {
+		return
+	}
+	muted := make(map[string]bool, len(authors))
+	for _, author := range authors {
+		muted[repository.NormalizeAuthor(author)] = true
+	}
+	for i := range entries {
+		if entries[i].Author != nil && muted[repository.NormalizeAuthor(*entries[i].Author)] {
+			entries[i].Read = true
 		}
 	}
-	return
+}
+
+// entryRevisionLimit returns how many content snapshots to keep, or 0 when versioning is disabled.
+func entryRevisionLimit(general *GeneralSettings) int {
+	if general == nil || general.EntryRevisions {
+		return EntryRevisionLimit
+	}
+	return 0
 }
 
 var ErrAlreadyRefreshing = errors.New("refresh already in progress")
@@ -203,42 +296,143 @@ var ErrAlreadyRefreshing = errors.New("refresh already in progress")
 type RefreshStatus struct {
 	IsRefreshing    bool
 	LastRefreshedAt *time.Time
+	// QuietUntil is set while quiet hours pause scheduled refreshes, to when they end.
+	QuietUntil *time.Time
 }
 
 type RefreshService interface {
-	RefreshAll(ctx context.Context) error
+	// RefreshAll refreshes every feed that is due; trigger is recorded in the run
+	// history. Feeds fetched more recently than their PollInterval are skipped.
+	RefreshAll(ctx context.Context, trigger string) error
+	// RefreshFeed refreshes one feed. If the feed is already being refreshed it
+	// waits for that refresh instead and returns ErrAlreadyRefreshing.
 	RefreshFeed(ctx context.Context, feedID int64) error
+	// RefreshFeeds refreshes the given feeds, skipping ones already being refreshed.
 	RefreshFeeds(ctx context.Context, feedIDs []int64) error
+	// IngestStatic saves the items of a pasted feed document to a static feed the
+	// same way a refresh would; items already stored are matched by hash.
+	IngestStatic(ctx context.Context, feedID int64, parsed *gofeed.Feed) (newCount int, updatedCount int, err error)
+	// IngestItems saves items pushed to a static feed the same way, reporting
+	// per item whether it was created, updated or skipped. More than
+	// MaxIngestItems items return ErrIngestTooLarge.
+	IngestItems(ctx context.Context, feedID int64, items []IngestItem) ([]IngestResult, error)
 	IsRefreshing() bool
 	GetRefreshStatus() RefreshStatus
+	// ListRuns returns recent refresh runs, newest first.
+	ListRuns(ctx context.Context) ([]model.RefreshRun, error)
+	// GetRun returns a refresh run with its per-feed outcomes.
+	GetRun(ctx context.Context, id int64) (model.RefreshRun, []model.RefreshRunFeed, error)
+	// GetFetchLog returns the recent refresh attempts of a feed, newest first,
+	// and its fetch health over them.
+	GetFetchLog(ctx context.Context, feedID int64) (FeedFetchLog, error)
+	// GetIngestReport returns what the last refresh of a feed skipped or
+	// altered, nil when it took every item as delivered.
+	GetIngestReport(ctx context.Context, feedID int64) (*IngestReport, error)
+	// Probe fetches a feed once and reports what came back, without saving
+	// anything or touching its error message. userAgent picks a FeedUserAgent*
+	// choice; empty uses the one a refresh would start with.
+	Probe(ctx context.Context, feedID int64, userAgent string) (FeedProbe, error)
+	// StartArchiveBackfill crawls the archive pages the feed links to with
+	// rel="next" in the background, saving their items like a refresh. A feed
+	// whose backfill is still running returns ErrConflict.
+	StartArchiveBackfill(ctx context.Context, feedID int64, opts ArchiveBackfillOptions) (ArchiveBackfill, error)
+	// GetArchiveBackfill returns the latest archive backfill of a feed since
+	// startup, nil when there was none.
+	GetArchiveBackfill(ctx context.Context, feedID int64) (*ArchiveBackfill, error)
+	// PollIntervals returns the poll interval of each feed by ID: the longer of
+	// the feed's own hint and its host's domain rate limit.
+	PollIntervals(ctx context.Context, feeds []model.Feed) map[int64]PollInterval
+	// Close cancels the refreshes in progress, whatever context started them.
+	// Feeds already fetched still finish saving.
+	Close()
 }
 
 type refreshService struct {
 	feeds           repository.FeedRepository
 	entries         repository.EntryRepository
+	runs            repository.RefreshRunRepository
+	fetchLog        repository.FeedFetchLogRepository
+	folders         repository.FolderRepository
 	settings        SettingsService
 	icons           IconService
+	images          ImageCacheService
 	clientFactory   *network.ClientFactory
 	anubis          AnubisSolver
 	rateLimitSvc    DomainRateLimitService
+	bus             *events.Bus
 	mu              sync.Mutex
 	isRefreshing    bool
 	lastRefreshedAt *time.Time
+	// refreshing holds a done channel for each feed being refreshed, closed
+	// when its refresh finishes.
+	refreshing map[int64]chan struct{}
+	// archiveJobs holds the latest archive backfill of each feed.
+	archiveJobs map[int64]*ArchiveBackfill
+	// hosts is shared by every refresh so bulk and single-feed ones together
+	// keep to each host's limits.
+	hosts *hostRateLimiter
+	// closed is cancelled by Close and ends every refresh in progress.
+	closed context.Context
+	close  context.CancelFunc
 }
 
-func NewRefreshService(feeds repository.FeedRepository, entries repository.EntryRepository, settings SettingsService, icons IconService, clientFactory *network.ClientFactory, anubisSolver AnubisSolver, rateLimitSvc DomainRateLimitService) RefreshService {
-	return &refreshService{
+func NewRefreshService(feeds repository.FeedRepository, entries repository.EntryRepository, runs repository.RefreshRunRepository, settings SettingsService, icons IconService, images ImageCacheService, clientFactory *network.ClientFactory, anubisSolver AnubisSolver, rateLimitSvc DomainRateLimitService, fetchLog repository.FeedFetchLogRepository, folders repository.FolderRepository, bus *events.Bus) RefreshService {
+	closed, closeFn := context.WithCancel(context.Background())
+	s := &refreshService{
+		closed:        closed,
+		close:         closeFn,
 		feeds:         feeds,
 		entries:       entries,
+		runs:          runs,
+		fetchLog:      fetchLog,
+		folders:       folders,
 		settings:      settings,
 		icons:         icons,
+		images:        images,
 		clientFactory: clientFactory,
 		anubis:        anubisSolver,
 		rateLimitSvc:  rateLimitSvc,
+		bus:           bus,
+		refreshing:    make(map[int64]chan struct{}),
+		archiveJobs:   make(map[int64]*ArchiveBackfill),
+	}
+	s.hosts = newHostRateLimiter(func(host string) time.Duration {
+		if s.rateLimitSvc != nil {
+			return s.rateLimitSvc.GetIntervalDuration(s.closed, host)
+		}
+		return 0
+	}, func(host string) int {
+		if s.rateLimitSvc != nil {
+			if n := s.rateLimitSvc.GetMaxConcurrent(s.closed, host); n > 0 {
+				return n
+			}
+		}
+		if general := loadGeneralSettings(s.closed, s.settings); general != nil && general.MaxConcurrentPerHost > 0 {
+			return general.MaxConcurrentPerHost
+		}
+		return DefaultMaxConcurrentPerHost
+	})
+	return s
+}
+
+func (s *refreshService) Close() {
+	s.close()
+}
+
+// untilClosed derives a context that also ends when Close is called.
+func (s *refreshService) untilClosed(ctx context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(ctx)
+	stop := context.AfterFunc(s.closed, cancel)
+	return ctx, func() {
+		stop()
+		cancel()
 	}
 }
 
-func (s *refreshService) RefreshAll(ctx context.Context) error {
+func (s *refreshService) RefreshAll(ctx context.Context, trigger string) error {
+	ctx, cancel := s.untilClosed(ctx)
+	defer cancel()
+
 	s.mu.Lock()
 	if s.isRefreshing {
 		s.mu.Unlock()
@@ -258,10 +452,15 @@ func (s *refreshService) RefreshAll(ctx context.Context) error {
 		logger.Error("refresh list feeds", "module", "service", "action", "list", "resource", "feed", "result", "failed", "error", err)
 		return err
 	}
+	startedAt := time.Now()
+	feeds = s.withoutRecentlyFetched(ctx, withoutStatic(withoutPaused(feeds, startedAt)), startedAt)
 
 	logger.Info("refresh started", "module", "service", "action", "refresh", "resource", "feed", "result", "ok", "count", len(feeds))
-	s.refreshFeedsWithRateLimit(ctx, feeds)
+	s.bus.Publish(events.Event{Type: events.RefreshStarted, Data: events.RefreshData{Count: len(feeds)}})
+	results := s.refreshFeedsWithRateLimit(ctx, feeds)
+	s.recordRun(ctx, trigger, startedAt, results)
 	logger.Info("refresh completed", "module", "service", "action", "refresh", "resource", "feed", "result", "ok", "count", len(feeds))
+	s.bus.Publish(events.Event{Type: events.RefreshFinished, Data: events.RefreshData{Count: len(feeds)}})
 
 	now := time.Now()
 	s.mu.Lock()
@@ -271,6 +470,50 @@ func (s *refreshService) RefreshAll(ctx context.Context) error {
 	return nil
 }
 
+// withoutPaused drops feeds paused at now from a bulk refresh.
+func withoutPaused(feeds []model.Feed, now time.Time) []model.Feed {
+	active := feeds[:0:0]
+	for _, feed := range feeds {
+		if isPaused(feed, now) {
+			logger.Debug("refresh skip paused feed", "module", "service", "action", "refresh", "resource", "feed", "result", "skipped", "feed_id", feed.ID, "paused_until", feed.PausedUntil.UTC().Format(time.RFC3339))
+			continue
+		}
+		active = append(active, feed)
+	}
+	return active
+}
+
+// withoutStatic drops feeds with pasted content, which have nothing to fetch.
+func withoutStatic(feeds []model.Feed) []model.Feed {
+	fetchable := feeds[:0:0]
+	for _, feed := range feeds {
+		if IsStaticFeed(feed) {
+			continue
+		}
+		fetchable = append(fetchable, feed)
+	}
+	return fetchable
+}
+
+// claimFeed marks the feed as being refreshed and returns the func that clears
+// the mark. If the feed is already being refreshed, release is nil and
+// inFlight is closed when that refresh finishes.
+func (s *refreshService) claimFeed(feedID int64) (release func(), inFlight <-chan struct{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if ch, ok := s.refreshing[feedID]; ok {
+		return nil, ch
+	}
+	done := make(chan struct{})
+	s.refreshing[feedID] = done
+	return func() {
+		s.mu.Lock()
+		delete(s.refreshing, feedID)
+		close(done)
+		s.mu.Unlock()
+	}, nil
+}
+
 func (s *refreshService) IsRefreshing() bool {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -278,26 +521,86 @@ func (s *refreshService) IsRefreshing() bool {
 }
 
 func (s *refreshService) GetRefreshStatus() RefreshStatus {
+	var quietUntil *time.Time
+	if until, quiet := QuietUntil(s.closed, s.settings, time.Now()); quiet {
+		quietUntil = &until
+	}
+
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	return RefreshStatus{
 		IsRefreshing:    s.isRefreshing,
 		LastRefreshedAt: s.lastRefreshedAt,
+		QuietUntil:      quietUntil,
 	}
 }
 
 func (s *refreshService) RefreshFeed(ctx context.Context, feedID int64) error {
+	ctx, cancel := s.untilClosed(ctx)
+	defer cancel()
+
 	feed, err := s.feeds.GetByID(ctx, feedID)
 	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrNotFound
+		}
 		return err
 	}
-	return s.refreshFeedInternal(ctx, feed)
+	if IsStaticFeed(feed) {
+		return fmt.Errorf("static feeds are updated by pasting new content: %w", ErrInvalid)
+	}
+
+	// The refresh already in progress covers this one once it finishes
+	release, inFlight := s.claimFeed(feed.ID)
+	if release == nil {
+		logger.Debug("refresh waiting for feed in progress", "module", "service", "action", "refresh", "resource", "feed", "result", "skipped", "feed_id", feed.ID)
+		select {
+		case <-inFlight:
+			return ErrAlreadyRefreshing
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	defer release()
+
+	// A refresh by hand also tries again to fetch a real icon in place of a
+	// generated one, once the feed itself comes back changed
+	if feed.IconSource != nil && *feed.IconSource == model.IconSourceGenerated {
+		feed.IconPath = nil
+	}
+
+	// Refreshing one feed by hand means the user wants it back
+	if feed.PausedUntil != nil {
+		if err := s.feeds.UpdatePausedUntil(ctx, feed.ID, nil); err != nil {
+			logger.Warn("refresh clear pause failed", "module", "service", "action", "update", "resource", "feed", "result", "failed", "feed_id", feed.ID, "error", err)
+		} else {
+			feed.PausedUntil = nil
+		}
+	}
+
+	if host := network.ExtractHost(feed.URL); host != "" {
+		if err := s.hosts.acquireSemaphore(ctx, host); err != nil {
+			return err
+		}
+		defer s.hosts.releaseSemaphore(host)
+		if err := s.hosts.waitForInterval(ctx, host); err != nil {
+			return err
+		}
+		s.hosts.recordRequest(host)
+	}
+
+	startedAt := time.Now()
+	result, err := s.refreshOne(ctx, feed)
+	s.recordRun(ctx, model.RefreshTriggerSingleFeed, startedAt, []model.RefreshRunFeed{result})
+	return err
 }
 
 func (s *refreshService) RefreshFeeds(ctx context.Context, feedIDs []int64) error {
 	if len(feedIDs) == 0 {
 		return nil
 	}
+	ctx, cancel := s.untilClosed(ctx)
+	defer cancel()
 
 	// Get all feeds by IDs in a single query
 	feeds, err := s.feeds.GetByIDs(ctx, feedIDs)
@@ -306,24 +609,108 @@ func (s *refreshService) RefreshFeeds(ctx context.Context, feedIDs []int64) erro
 		return err
 	}
 
+	feeds = withoutStatic(withoutPaused(feeds, time.Now()))
 	if len(feeds) == 0 {
 		return nil
 	}
 
-	s.refreshFeedsWithRateLimit(ctx, feeds)
+	s.bus.Publish(events.Event{Type: events.RefreshStarted, Data: events.RefreshData{Count: len(feeds)}})
+	startedAt := time.Now()
+	results := s.refreshFeedsWithRateLimit(ctx, feeds)
+	s.recordRun(ctx, model.RefreshTriggerManual, startedAt, results)
+	s.bus.Publish(events.Event{Type: events.RefreshFinished, Data: events.RefreshData{Count: len(feeds)}})
 	return nil
 }
 
-// refreshFeedsWithRateLimit refreshes multiple feeds with rate limiting and concurrency control.
-func (s *refreshService) refreshFeedsWithRateLimit(ctx context.Context, feeds []model.Feed) {
-	globalSem := semaphore.NewWeighted(maxConcurrentRefresh)
+func (s *refreshService) IngestStatic(ctx context.Context, feedID int64, parsed *gofeed.Feed) (int, int, error) {
+	if parsed == nil {
+		return 0, 0, ErrNotAFeed
+	}
+	feed, err := s.feeds.GetByID(ctx, feedID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, 0, ErrNotFound
+		}
+		return 0, 0, fmt.Errorf("get feed: %w", err)
+	}
+	if !IsStaticFeed(feed) {
+		return 0, 0, fmt.Errorf("feed %d is not static: %w", feedID, ErrInvalid)
+	}
 
-	hl := newHostRateLimiter(func(host string) time.Duration {
-		if s.rateLimitSvc != nil {
-			return s.rateLimitSvc.GetIntervalDuration(ctx, host)
+	newCount, updatedCount, evictedCount := s.saveEntries(ctx, feed, parsed.Items)
+	logger.Info("static feed ingested", "module", "service", "action", "save", "resource", "feed", "result", "ok", "feed_id", feed.ID, "feed_title", feed.Title, "items", len(parsed.Items), "new", newCount, "updated", updatedCount, "evicted", evictedCount)
+	return newCount, updatedCount, nil
+}
+
+func (s *refreshService) ListRuns(ctx context.Context) ([]model.RefreshRun, error) {
+	if s.runs == nil {
+		return nil, nil
+	}
+	return s.runs.List(ctx, refreshRunKeep)
+}
+
+func (s *refreshService) GetRun(ctx context.Context, id int64) (model.RefreshRun, []model.RefreshRunFeed, error) {
+	if s.runs == nil {
+		return model.RefreshRun{}, nil, ErrNotFound
+	}
+	run, err := s.runs.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.RefreshRun{}, nil, ErrNotFound
 		}
-		return 0
-	})
+		return model.RefreshRun{}, nil, err
+	}
+	feeds, err := s.runs.ListFeeds(ctx, id)
+	if err != nil {
+		return model.RefreshRun{}, nil, err
+	}
+	return run, feeds, nil
+}
+
+// recordRun stores the outcome of a refresh pass. It runs even when ctx was
+// cancelled mid-refresh so interrupted runs still show up in history.
+func (s *refreshService) recordRun(ctx context.Context, trigger string, startedAt time.Time, results []model.RefreshRunFeed) {
+	if s.runs == nil {
+		return
+	}
+	run := model.RefreshRun{
+		Trigger:    trigger,
+		StartedAt:  startedAt,
+		FinishedAt: time.Now(),
+		FeedsTotal: len(results),
+	}
+	for _, result := range results {
+		if result.ErrorMessage != nil {
+			run.FeedsFailed++
+		}
+		run.EntriesNew += result.EntriesNew
+		run.EntriesUpdated += result.EntriesUpdated
+		run.EntriesEvicted += result.EntriesEvicted
+	}
+	if _, err := s.runs.Create(context.WithoutCancel(ctx), run, results, refreshRunKeep); err != nil {
+		logger.Warn("record refresh run failed", "module", "service", "action", "create", "resource", "refresh_run", "result", "failed", "trigger", trigger, "error", err)
+	}
+}
+
+// refreshFeedsWithRateLimit refreshes multiple feeds with rate limiting and concurrency control.
+// It returns one result per feed, including feeds skipped because ctx was cancelled.
+func (s *refreshService) refreshFeedsWithRateLimit(ctx context.Context, feeds []model.Feed) []model.RefreshRunFeed {
+	var resultsMu sync.Mutex
+	results := make([]model.RefreshRunFeed, 0, len(feeds))
+	addResult := func(result model.RefreshRunFeed) {
+		resultsMu.Lock()
+		results = append(results, result)
+		resultsMu.Unlock()
+	}
+
+	// The global limit is read once per run, so a change mid-run applies to the
+	// next one; per-host limits are read whenever a host gets busy again
+	maxConcurrent := DefaultMaxConcurrentRefresh
+	if general := loadGeneralSettings(ctx, s.settings); general != nil && general.MaxConcurrentRefresh > 0 {
+		maxConcurrent = general.MaxConcurrentRefresh
+	}
+	globalSem := semaphore.NewWeighted(int64(maxConcurrent))
+	hl := s.hosts
 
 	var wg sync.WaitGroup
 	for _, feed := range feeds {
@@ -332,23 +719,34 @@ func (s *refreshService) refreshFeedsWithRateLimit(ctx context.Context, feeds []
 		go func() {
 			defer wg.Done()
 
+			// Another refresh is already fetching the feed
+			release, _ := s.claimFeed(feed.ID)
+			if release == nil {
+				logger.Debug("refresh skip feed in progress", "module", "service", "action", "refresh", "resource", "feed", "result", "skipped", "feed_id", feed.ID)
+				return
+			}
+			defer release()
+
 			host := network.ExtractHost(feed.URL)
 
 			if host != "" {
 				if err := hl.acquireSemaphore(ctx, host); err != nil {
 					logger.Debug("refresh host acquire cancelled", "module", "service", "action", "refresh", "resource", "feed", "result", "cancelled", "host", host, "error", err)
+					addResult(cancelledRefreshResult(feed, err))
 					return
 				}
 				defer hl.releaseSemaphore(host)
 
 				if err := hl.waitForInterval(ctx, host); err != nil {
 					logger.Debug("refresh host wait cancelled", "module", "service", "action", "refresh", "resource", "feed", "result", "cancelled", "host", host, "error", err)
+					addResult(cancelledRefreshResult(feed, err))
 					return
 				}
 			}
 
 			if err := globalSem.Acquire(ctx, 1); err != nil {
 				logger.Debug("refresh global acquire cancelled", "module", "service", "action", "refresh", "resource", "feed", "result", "cancelled", "host", host, "error", err)
+				addResult(cancelledRefreshResult(feed, err))
 				return
 			}
 			defer globalSem.Release(1)
@@ -357,188 +755,288 @@ func (s *refreshService) refreshFeedsWithRateLimit(ctx context.Context, feeds []
 				hl.recordRequest(host)
 			}
 
-			if err := s.refreshFeedInternal(ctx, feed); err != nil {
+			result, err := s.refreshOne(ctx, feed)
+			if err != nil {
 				logger.Error("refresh feed failed", "module", "service", "action", "refresh", "resource", "feed", "result", "failed", "feed_id", feed.ID, "feed_title", feed.Title, "error", err)
 			}
+			addResult(result)
 		}()
 	}
 
 	wg.Wait()
+	return results
 }
 
-func (s *refreshService) refreshFeedInternal(ctx context.Context, feed model.Feed) error {
-	return s.refreshFeedWithUA(ctx, feed, config.DefaultUserAgent, true)
+// updateTitle stores the title of a freshly parsed feed when it changed, and
+// drops a custom title that has become the same.
+func (s *refreshService) updateTitle(ctx context.Context, feed model.Feed, parsed *gofeed.Feed) model.Feed {
+	title := strings.TrimSpace(parsed.Title)
+	if title == "" {
+		return feed
+	}
+	customTitle := feed.CustomTitle
+	if customTitle != nil && *customTitle == title {
+		customTitle = nil
+	}
+	if title == feed.Title && customTitle == feed.CustomTitle {
+		return feed
+	}
+	if err := s.feeds.UpdateTitles(ctx, feed.ID, title, customTitle); err != nil {
+		logger.Warn("update feed title failed", "module", "service", "action", "update", "resource", "feed", "result", "failed", "feed_id", feed.ID, "error", err)
+		return feed
+	}
+	logger.Debug("feed title updated", "module", "service", "action", "update", "resource", "feed", "result", "ok", "feed_id", feed.ID, "feed_title", title)
+	feed.Title, feed.CustomTitle = title, customTitle
+	return feed
 }
 
-func (s *refreshService) refreshFeedWithUA(ctx context.Context, feed model.Feed, userAgent string, allowFallback bool) error {
-	return s.refreshFeedWithCookie(ctx, feed, userAgent, "", allowFallback, 0)
+// updateLanguage stores the language the feed declares when it changed, so
+// the entries saved next take it over.
+func (s *refreshService) updateLanguage(ctx context.Context, feed model.Feed, parsed *gofeed.Feed) model.Feed {
+	language := langdetect.Normalize(parsed.Language)
+	if language == feed.Language {
+		return feed
+	}
+	if err := s.feeds.UpdateLanguage(ctx, feed.ID, language); err != nil {
+		logger.Warn("update feed language failed", "module", "service", "action", "update", "resource", "feed", "result", "failed", "feed_id", feed.ID, "error", err)
+		return feed
+	}
+	logger.Debug("feed language updated", "module", "service", "action", "update", "resource", "feed", "result", "ok", "feed_id", feed.ID, "language", language)
+	feed.Language = language
+	return feed
 }
 
-func (s *refreshService) refreshFeedWithCookie(ctx context.Context, feed model.Feed, userAgent string, cookie string, allowFallback bool, retryCount int) error {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, feed.URL, nil)
-	if err != nil {
+// feedRefreshOutcome collects what happened to a single feed while the refresh
+// call chain runs; it travels in the context so retries and fallbacks share it.
+type feedRefreshOutcome struct {
+	newCount     int
+	updatedCount int
+	evictedCount int
+	errMsg       *string
+}
+
+type feedOutcomeKey struct{}
+
+func feedOutcomeFrom(ctx context.Context) *feedRefreshOutcome {
+	outcome, _ := ctx.Value(feedOutcomeKey{}).(*feedRefreshOutcome)
+	return outcome
+}
+
+// refreshOne refreshes a feed and reports its outcome for the run history.
+func (s *refreshService) refreshOne(ctx context.Context, feed model.Feed) (model.RefreshRunFeed, error) {
+	outcome := &feedRefreshOutcome{}
+	err := s.refreshFeedInternal(context.WithValue(ctx, feedOutcomeKey{}, outcome), feed)
+	if ctx.Err() == nil {
+		if updateErr := s.feeds.UpdateLastFetchedAt(ctx, feed.ID, time.Now()); updateErr != nil {
+			logger.Warn("update feed last fetched failed", "module", "service", "action", "update", "resource", "feed", "result", "failed", "feed_id", feed.ID, "error", updateErr)
+		}
+	}
+	if err != nil && outcome.errMsg == nil {
 		errMsg := err.Error()
-		_ = s.feeds.UpdateErrorMessage(ctx, feed.ID, &errMsg)
+		outcome.errMsg = &errMsg
+	}
+	return model.RefreshRunFeed{
+		FeedID:         feed.ID,
+		FeedTitle:      feed.DisplayTitle(),
+		EntriesNew:     outcome.newCount,
+		EntriesUpdated: outcome.updatedCount,
+		EntriesEvicted: outcome.evictedCount,
+		ErrorMessage:   outcome.errMsg,
+	}, err
+}
+
+func cancelledRefreshResult(feed model.Feed, err error) model.RefreshRunFeed {
+	errMsg := fmt.Sprintf("refresh cancelled: %v", err)
+	return model.RefreshRunFeed{FeedID: feed.ID, FeedTitle: feed.DisplayTitle(), ErrorMessage: &errMsg}
+}
+
+// setFeedError stores the feed's error message and mirrors it into the run outcome.
+func (s *refreshService) setFeedError(ctx context.Context, feedID int64, errMsg string) {
+	_ = s.feeds.UpdateErrorMessage(ctx, feedID, &errMsg)
+	if outcome := feedOutcomeFrom(ctx); outcome != nil {
+		outcome.errMsg = &errMsg
+	}
+}
+
+// feedUserAgent is a User-Agent header value and the FeedUserAgent* choice it stands for.
+type feedUserAgent struct {
+	choice string
+	value  string
+}
+
+func (s *refreshService) refreshFeedInternal(ctx context.Context, feed model.Feed) error {
+	// A dead proxy would otherwise cost every feed the full request timeout
+	if err := s.clientFactory.CheckProxy(ctx); err != nil {
+		if errors.Is(err, network.ErrProxyUnreachable) {
+			s.setFeedError(ctx, feed.ID, err.Error())
+		}
 		return err
 	}
-	req.Header.Set("User-Agent", userAgent)
 
-	// Add cached Anubis cookie if available
-	if cookie == "" {
-		host := network.ExtractHost(feed.URL)
-		if cachedCookie := getCachedAnubisCookie(ctx, s.anubis, host, req.Header); cachedCookie != "" {
-			cookie = cachedCookie
+	// Feeds that rejected the default UA before start with the fallback
+	if feed.PreferredUserAgent == model.FeedUserAgentFallback {
+		if fallback, ok := s.alternateUserAgent(ctx, feed, model.FeedUserAgentDefault); ok {
+			return s.refreshFeedWithUA(ctx, feed, fallback, true)
 		}
 	}
+	return s.refreshFeedWithUA(ctx, feed, s.defaultUserAgent(ctx, feed), true)
+}
 
-	if cookie != "" {
-		req.Header.Set("Cookie", cookie)
+// defaultUserAgent is the FeedUserAgentDefault choice for the feed: the custom
+// User-Agent the feed or one of its folders sets, else the built-in one.
+func (s *refreshService) defaultUserAgent(ctx context.Context, feed model.Feed) feedUserAgent {
+	userAgent := feedUserAgent{choice: model.FeedUserAgentDefault, value: config.DefaultUserAgent}
+	var chain []model.Folder
+	if feed.UserAgent == nil && s.folders != nil {
+		var err error
+		if chain, err = loadFolderChain(ctx, s.folders, feed.FolderID); err != nil {
+			logger.Warn("load feed folders failed", "module", "service", "action", "refresh", "resource", "feed", "result", "failed", "feed_id", feed.ID, "error", err)
+		}
 	}
+	if custom := effectiveFeedConfig(feed, chain).UserAgent; custom != nil {
+		userAgent.value = *custom
+	}
+	return userAgent
+}
 
-	// Conditional GET
-	if feed.ETag != nil && *feed.ETag != "" {
-		req.Header.Set("If-None-Match", *feed.ETag)
+// alternateUserAgent returns the user agent to retry with after choice failed.
+// There is none to switch to from the default when no fallback UA is configured.
+func (s *refreshService) alternateUserAgent(ctx context.Context, feed model.Feed, choice string) (feedUserAgent, bool) {
+	if choice == model.FeedUserAgentFallback {
+		return s.defaultUserAgent(ctx, feed), true
 	}
-	if feed.LastModified != nil && *feed.LastModified != "" {
-		req.Header.Set("If-Modified-Since", *feed.LastModified)
+	if s.settings == nil {
+		return feedUserAgent{}, false
 	}
+	fallbackUA := s.settings.GetFallbackUserAgent(ctx)
+	if fallbackUA == "" {
+		return feedUserAgent{}, false
+	}
+	return feedUserAgent{choice: model.FeedUserAgentFallback, value: fallbackUA}, true
+}
 
-	httpClient := s.clientFactory.NewHTTPClient(ctx, refreshTimeout)
-	resp, err := httpClient.Do(req)
+// rememberUserAgent stores the user agent that just fetched the feed when it
+// differs from the preferred one, so the next refresh tries it first.
+func (s *refreshService) rememberUserAgent(ctx context.Context, feed model.Feed, userAgent feedUserAgent) {
+	preferred := feed.PreferredUserAgent
+	if preferred == "" {
+		preferred = model.FeedUserAgentDefault
+	}
+	if userAgent.choice == preferred {
+		return
+	}
+	if err := s.feeds.UpdatePreferredUserAgent(ctx, feed.ID, userAgent.choice); err != nil {
+		logger.Warn("update preferred ua failed", "module", "service", "action", "update", "resource", "feed", "result", "failed", "feed_id", feed.ID, "user_agent", userAgent.choice, "error", err)
+		return
+	}
+	logger.Info("feed preferred ua updated", "module", "service", "action", "update", "resource", "feed", "result", "ok", "feed_id", feed.ID, "feed_title", feed.Title, "user_agent", userAgent.choice)
+}
+
+func (s *refreshService) refreshFeedWithUA(ctx context.Context, feed model.Feed, userAgent feedUserAgent, allowFallback bool) error {
+	// A feed answering 304 for longer than its posting history allows is
+	// fetched once without validators, in case they hide new entries
+	request := feed
+	unconditional := s.dueUnconditionalFetch(ctx, feed)
+	if unconditional {
+		logger.Info("fetching feed without validators", "module", "service", "action", "refresh", "resource", "feed", "result", "ok", "feed_id", feed.ID, "feed_title", feed.Title, "not_modified_streak", feed.NotModifiedStreak)
+		request.ETag, request.LastModified = nil, nil
+	}
+
+	started := time.Now()
+	attempt, _, err := fetchPastAnubis(ctx, s.anubis, feed.URL, anubisRetryOptionsFrom(ctx, s.settings), func(cookie string, solved bool) (anubisAttempt, error) {
+		attempt, err := s.fetchFeed(ctx, request, userAgent, cookie)
+		if err != nil {
+			return anubisAttempt{}, err
+		}
+
+		// On HTTP error, try the other UA if available. Not after a solve: the
+		// solved cookie is scoped to the UA it was solved with
+		if attempt.statusCode >= http.StatusBadRequest && allowFallback && !solved {
+			if alternate, ok := s.alternateUserAgent(ctx, feed, userAgent.choice); ok {
+				logger.Warn("retrying with alternate ua", "module", "service", "action", "refresh", "resource", "feed", "result", "failed", "feed_id", feed.ID, "feed_title", feed.Title, "status_code", attempt.statusCode, "user_agent", alternate.choice)
+				userAgent, allowFallback = alternate, false
+				return s.fetchFeed(ctx, request, userAgent, cookie)
+			}
+		}
+		return attempt, nil
+	})
 	if err != nil {
-		errMsg := err.Error()
-		_ = s.feeds.UpdateErrorMessage(ctx, feed.ID, &errMsg)
+		if ctx.Err() == nil {
+			s.recordFetch(feed, started, attempt, model.FeedFetchNetworkError)
+		}
+		s.setFeedError(ctx, feed.ID, err.Error())
 		return err
 	}
-	defer resp.Body.Close()
 
 	// Not modified, skip parsing but clear any previous error
-	if resp.StatusCode == http.StatusNotModified {
+	if attempt.statusCode == http.StatusNotModified {
+		s.recordFetch(feed, started, attempt, model.FeedFetchNotModified)
 		logger.Debug("feed not modified", "module", "service", "action", "refresh", "resource", "feed", "result", "skipped", "feed_id", feed.ID, "host", network.ExtractHost(feed.URL))
 		_ = s.feeds.UpdateErrorMessage(ctx, feed.ID, nil)
+		s.rememberUserAgent(ctx, feed, userAgent)
+		s.recordNotModified(ctx, feed)
 		return nil
 	}
 
-	// On HTTP error, try fallback UA if available
-	if resp.StatusCode >= http.StatusBadRequest && allowFallback && s.settings != nil {
-		fallbackUA := s.settings.GetFallbackUserAgent(ctx)
-		if fallbackUA != "" {
-			logger.Warn("retrying with fallback ua", "module", "service", "action", "refresh", "resource", "feed", "result", "failed", "feed_id", feed.ID, "feed_title", feed.Title, "status_code", resp.StatusCode)
-			return s.refreshFeedWithCookie(ctx, feed, fallbackUA, cookie, false, retryCount)
-		}
-	}
-
-	if resp.StatusCode >= http.StatusBadRequest {
-		logger.Error("feed http error", "module", "service", "action", "refresh", "resource", "feed", "result", "failed", "feed_id", feed.ID, "feed_title", feed.Title, "status_code", resp.StatusCode)
-		errMsg := fmt.Sprintf("HTTP %d", resp.StatusCode)
-		_ = s.feeds.UpdateErrorMessage(ctx, feed.ID, &errMsg)
+	if attempt.statusCode >= http.StatusBadRequest {
+		logger.Error("feed http error", "module", "service", "action", "refresh", "resource", "feed", "result", "failed", "feed_id", feed.ID, "feed_title", feed.Title, "status_code", attempt.statusCode)
+		errMsg := fmt.Sprintf("HTTP %d", attempt.statusCode)
+		s.recordFetch(feed, started, attempt, model.FeedFetchHTTPError)
+		s.setFeedError(ctx, feed.ID, errMsg)
 		return nil
 	}
 
-	// Read body into memory for Anubis detection and RSS parsing
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		errMsg := err.Error()
-		_ = s.feeds.UpdateErrorMessage(ctx, feed.ID, &errMsg)
-		return err
-	}
-
-	parser := gofeed.NewParser()
-	parsed, parseErr := parser.Parse(bytes.NewReader(body))
+	parsed, parseErr := parseFeed(attempt.body)
 	if parseErr != nil {
-		newCookie, anubisErr := trySolveAnubisChallenge(ctx, s.anubis, body, feed.URL, resp.Cookies(), req.Header.Clone(), retryCount)
-		switch {
-		case anubisErr == nil:
-			// Retry with fresh client and same request fingerprint.
-			return s.refreshFeedWithFreshClient(ctx, feed, userAgent, newCookie, retryCount+1)
-		case errors.Is(anubisErr, errAnubisNotPage):
-			// Not an Anubis page; keep original parse error handling.
-		case errors.Is(anubisErr, errAnubisRejected):
-			errMsg := "upstream rejected"
-			_ = s.feeds.UpdateErrorMessage(ctx, feed.ID, &errMsg)
-			return errors.New(errMsg)
-		case errors.Is(anubisErr, errAnubisRetryExceeded):
-			errMsg := fmt.Sprintf("anubis challenge persists after %d retries", retryCount)
-			_ = s.feeds.UpdateErrorMessage(ctx, feed.ID, &errMsg)
-			return errors.New(errMsg)
-		default:
-			errMsg := fmt.Sprintf("anubis solve failed: %v", anubisErr)
-			_ = s.feeds.UpdateErrorMessage(ctx, feed.ID, &errMsg)
-			return anubisErr
-		}
+		s.recordFetch(feed, started, attempt, model.FeedFetchParseError)
 		errMsg := parseErr.Error()
-		_ = s.feeds.UpdateErrorMessage(ctx, feed.ID, &errMsg)
+		s.setFeedError(ctx, feed.ID, errMsg)
 		return parseErr
 	}
 
-	return s.processParsedFeed(ctx, feed, parsed, resp)
+	s.recordFetch(feed, started, attempt, model.FeedFetchOK)
+	s.rememberUserAgent(ctx, feed, userAgent)
+	newCount := s.processParsedFeed(ctx, feed, parsed, attempt.resp)
+	s.recordModified(ctx, feed, parsed, newCount, unconditional)
+	return nil
 }
 
-// refreshFeedWithFreshClient creates a new http.Client to avoid connection reuse after Anubis
-func (s *refreshService) refreshFeedWithFreshClient(ctx context.Context, feed model.Feed, userAgent string, cookie string, retryCount int) error {
+// fetchFeed sends one conditional GET for the feed with a new client, and the
+// Anubis cookie cached for this host and UA when cookie is empty.
+func (s *refreshService) fetchFeed(ctx context.Context, feed model.Feed, userAgent feedUserAgent, cookie string) (anubisAttempt, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, feed.URL, nil)
 	if err != nil {
-		errMsg := err.Error()
-		_ = s.feeds.UpdateErrorMessage(ctx, feed.ID, &errMsg)
-		return err
+		return anubisAttempt{}, err
+	}
+	req.Header.Set("User-Agent", userAgentHeader(userAgent.value))
+
+	if cookie == "" {
+		cookie = getCachedAnubisCookie(ctx, s.anubis, network.ExtractHost(feed.URL), req.Header)
 	}
-	req.Header.Set("User-Agent", userAgent)
 	if cookie != "" {
 		req.Header.Set("Cookie", cookie)
 	}
 
-	// Use fresh client to avoid connection reuse
-	freshClient := s.clientFactory.NewHTTPClient(ctx, refreshTimeout)
-	resp, err := freshClient.Do(req)
-	if err != nil {
-		errMsg := err.Error()
-		_ = s.feeds.UpdateErrorMessage(ctx, feed.ID, &errMsg)
-		return err
+	// Conditional GET
+	if feed.ETag != nil && *feed.ETag != "" {
+		req.Header.Set("If-None-Match", *feed.ETag)
+	}
+	if feed.LastModified != nil && *feed.LastModified != "" {
+		req.Header.Set("If-Modified-Since", *feed.LastModified)
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode >= http.StatusBadRequest {
-		logger.Error("feed http error", "module", "service", "action", "refresh", "resource", "feed", "result", "failed", "feed_id", feed.ID, "feed_title", feed.Title, "status_code", resp.StatusCode)
-		errMsg := fmt.Sprintf("HTTP %d", resp.StatusCode)
-		_ = s.feeds.UpdateErrorMessage(ctx, feed.ID, &errMsg)
-		return nil
+	httpClient := s.clientFactory.NewHTTPClient(ctx, refreshTimeout)
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return anubisAttempt{}, err
 	}
+	defer resp.Body.Close()
 
+	// Read body into memory for Anubis detection and RSS parsing
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		logger.Error("feed refresh read failed", "module", "service", "action", "refresh", "resource", "feed", "result", "failed", "feed_id", feed.ID, "feed_title", feed.Title, "error", err)
-		errMsg := err.Error()
-		_ = s.feeds.UpdateErrorMessage(ctx, feed.ID, &errMsg)
-		return err
+		return anubisAttempt{}, err
 	}
-
-	newCookie, anubisErr := trySolveAnubisChallenge(ctx, s.anubis, body, feed.URL, resp.Cookies(), req.Header.Clone(), retryCount)
-	switch {
-	case anubisErr == nil:
-		return s.refreshFeedWithFreshClient(ctx, feed, userAgent, newCookie, retryCount+1)
-	case errors.Is(anubisErr, errAnubisNotPage):
-		// Not an Anubis page; continue normal parsing.
-	case errors.Is(anubisErr, errAnubisRejected):
-		errMsg := "upstream rejected"
-		_ = s.feeds.UpdateErrorMessage(ctx, feed.ID, &errMsg)
-		return errors.New(errMsg)
-	case errors.Is(anubisErr, errAnubisRetryExceeded):
-		errMsg := fmt.Sprintf("anubis challenge persists after %d retries", retryCount)
-		_ = s.feeds.UpdateErrorMessage(ctx, feed.ID, &errMsg)
-		return errors.New(errMsg)
-	default:
-		errMsg := fmt.Sprintf("anubis solve failed: %v", anubisErr)
-		_ = s.feeds.UpdateErrorMessage(ctx, feed.ID, &errMsg)
-		return anubisErr
-	}
-
-	parser := gofeed.NewParser()
-	parsed, parseErr := parser.Parse(bytes.NewReader(body))
-	if parseErr != nil {
-		errMsg := parseErr.Error()
-		_ = s.feeds.UpdateErrorMessage(ctx, feed.ID, &errMsg)
-		return parseErr
-	}
-
-	return s.processParsedFeed(ctx, feed, parsed, resp)
+	return newHTTPAnubisAttempt(resp, body, req.Header.Clone()), nil
 }
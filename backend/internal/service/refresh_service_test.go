@@ -3,18 +3,26 @@ package service_test
 import (
 	"context"
 	"crypto/sha256"
+	"database/sql"
 	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"gist/backend/internal/config"
+	"gist/backend/internal/events"
 	"gist/backend/internal/model"
+	"gist/backend/internal/repository"
 	"gist/backend/internal/repository/mock"
+	"gist/backend/internal/repository/testutil"
 	"gist/backend/internal/service"
+	"gist/backend/internal/service/anubis"
 	servicemock "gist/backend/internal/service/mock"
 	"gist/backend/pkg/network"
 
@@ -31,13 +39,18 @@ func TestRefreshService_RefreshAll_AlreadyRefreshing(t *testing.T) {
 		mock.NewMockEntryRepository(ctrl),
 		nil,
 		nil,
+		nil,
+		nil,
 		network.NewClientFactoryForTest(&http.Client{}),
 		nil,
 		nil,
+		nil,
+		nil,
+		nil,
 	)
 	service.SetRefreshServiceRefreshing(svc, true)
 
-	err := svc.RefreshAll(context.Background())
+	err := svc.RefreshAll(context.Background(), model.RefreshTriggerManual)
 	require.ErrorIs(t, err, service.ErrAlreadyRefreshing)
 }
 
@@ -53,21 +66,61 @@ func TestRefreshService_RefreshAll_ListError(t *testing.T) {
 		mock.NewMockEntryRepository(ctrl),
 		nil,
 		nil,
+		nil,
+		nil,
 		network.NewClientFactoryForTest(&http.Client{}),
 		nil,
 		nil,
+		nil,
+		nil,
+		nil,
 	)
 
-	err := svc.RefreshAll(context.Background())
+	err := svc.RefreshAll(context.Background(), model.RefreshTriggerManual)
 	require.Error(t, err)
 	require.False(t, svc.IsRefreshing())
 }
 
+func TestRefreshService_Close_CancelsRefreshInProgress(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFeeds := mock.NewMockFeedRepository(ctrl)
+	svc := service.NewRefreshService(
+		mockFeeds,
+		mock.NewMockEntryRepository(ctrl),
+		nil,
+		nil,
+		nil,
+		nil,
+		network.NewClientFactoryForTest(&http.Client{}),
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+	mockFeeds.EXPECT().List(gomock.Any(), (*int64)(nil)).DoAndReturn(func(ctx context.Context, _ *int64) ([]model.Feed, error) {
+		svc.Close()
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(time.Second):
+			return nil, errors.New("refresh context not cancelled")
+		}
+	})
+
+	err := svc.RefreshAll(context.Background(), model.RefreshTriggerManual)
+	require.ErrorIs(t, err, context.Canceled)
+}
+
 func TestRefreshService_RefreshFeed_NotModified(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockFeeds := mock.NewMockFeedRepository(ctrl)
+	mockFeeds.EXPECT().UpdateLastFetchedAt(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockFeeds.EXPECT().UpdateNotModifiedStreak(gomock.Any(), gomock.Any(), 1).Return(nil).AnyTimes()
 	mockEntries := mock.NewMockEntryRepository(ctrl)
 
 	feed := model.Feed{ID: 1, URL: "https://example.com/rss", Title: "Feed"}
@@ -90,20 +143,99 @@ func TestRefreshService_RefreshFeed_NotModified(t *testing.T) {
 		mockEntries,
 		nil,
 		nil,
+		nil,
+		nil,
 		network.NewClientFactoryForTest(client),
 		nil,
 		nil,
+		nil,
+		nil,
+		nil,
 	)
 
 	err := svc.RefreshFeed(context.Background(), 1)
 	require.NoError(t, err)
 }
 
+func TestRefreshService_RefreshFeed_ProxyUnreachable(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Nothing listens on a just-released port
+	ln, err := net.Listen("tcp4", "127.0.0.1:0")
+	require.NoError(t, err)
+	proxyAddr := ln.Addr().String()
+	require.NoError(t, ln.Close())
+
+	mockFeeds := mock.NewMockFeedRepository(ctrl)
+	mockFeeds.EXPECT().UpdateLastFetchedAt(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	for _, id := range []int64{1, 2} {
+		mockFeeds.EXPECT().GetByID(gomock.Any(), id).Return(model.Feed{ID: id, URL: "https://example.com/rss", Title: "Feed"}, nil)
+		mockFeeds.EXPECT().UpdateErrorMessage(gomock.Any(), id, gomock.Any()).DoAndReturn(
+			func(_ context.Context, _ int64, msg *string) error {
+				require.NotNil(t, msg)
+				require.Contains(t, *msg, "proxy unreachable")
+				return nil
+			},
+		)
+	}
+
+	settings := &settingsServiceStub{proxyURL: "socks5://" + proxyAddr}
+	svc := service.NewRefreshService(mockFeeds, mock.NewMockEntryRepository(ctrl), nil, nil, nil, nil, network.NewClientFactory(settings, settings), nil, nil, nil, nil, nil)
+
+	require.ErrorIs(t, svc.RefreshFeed(context.Background(), 1), network.ErrProxyUnreachable)
+	// The second feed reuses the probe result instead of timing out
+	start := time.Now()
+	require.ErrorIs(t, svc.RefreshFeed(context.Background(), 2), network.ErrProxyUnreachable)
+	require.Less(t, time.Since(start), time.Second)
+}
+
+func TestRefreshService_RefreshFeed_ClearsPause(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFeeds := mock.NewMockFeedRepository(ctrl)
+	mockFeeds.EXPECT().UpdateLastFetchedAt(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockFeeds.EXPECT().UpdateNotModifiedStreak(gomock.Any(), gomock.Any(), 1).Return(nil).AnyTimes()
+
+	until := time.Now().Add(time.Hour)
+	feed := model.Feed{ID: 1, URL: "https://example.com/rss", Title: "Feed", PausedUntil: &until}
+	mockFeeds.EXPECT().GetByID(gomock.Any(), int64(1)).Return(feed, nil)
+	mockFeeds.EXPECT().UpdatePausedUntil(gomock.Any(), int64(1), (*time.Time)(nil)).Return(nil)
+	mockFeeds.EXPECT().UpdateErrorMessage(gomock.Any(), int64(1), nil).Return(nil)
+
+	client := &http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			return &http.Response{StatusCode: http.StatusNotModified, Body: http.NoBody, Header: make(http.Header), Request: req}, nil
+		}),
+	}
+
+	svc := service.NewRefreshService(
+		mockFeeds,
+		mock.NewMockEntryRepository(ctrl),
+		nil,
+		nil,
+		nil,
+		nil,
+		network.NewClientFactoryForTest(client),
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+
+	require.NoError(t, svc.RefreshFeed(context.Background(), 1))
+}
+
 func TestRefreshService_RefreshFeed_Success(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockFeeds := mock.NewMockFeedRepository(ctrl)
+	mockFeeds.EXPECT().UpdateTitles(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockFeeds.EXPECT().UpdateLastFetchedAt(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockFeeds.EXPECT().UpdateLastEntrySeenAt(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
 	mockEntries := mock.NewMockEntryRepository(ctrl)
 	mockIcons := servicemock.NewMockIconService(ctrl)
 
@@ -111,20 +243,19 @@ func TestRefreshService_RefreshFeed_Success(t *testing.T) {
 	mockFeeds.EXPECT().GetByID(gomock.Any(), int64(10)).Return(feed, nil)
 	mockFeeds.EXPECT().UpdateErrorMessage(gomock.Any(), int64(10), nil).Return(nil)
 
-	mockFeeds.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(
-		func(_ context.Context, updated model.Feed) (model.Feed, error) {
-			require.NotNil(t, updated.ETag)
-			require.NotNil(t, updated.LastModified)
-			return updated, nil
-		},
-	)
+	mockFeeds.EXPECT().UpdateConditionalGet(gomock.Any(), int64(10), gomock.Not(gomock.Nil()), gomock.Not(gomock.Nil())).Return(nil)
 	mockFeeds.EXPECT().UpdateSiteURL(gomock.Any(), int64(10), "https://example.com").Return(nil)
 	mockIcons.EXPECT().FetchAndSaveIcon(gomock.Any(), "https://example.com/icon.png", "https://example.com").Return("example.com.png", nil)
 	mockFeeds.EXPECT().UpdateIconPath(gomock.Any(), int64(10), "example.com.png").Return(nil)
 
-	mockEntries.EXPECT().ExistsByHash(gomock.Any(), int64(10), hashString("https://example.com/1")).Return(false, nil)
-	mockEntries.EXPECT().ExistsByLegacyURL(gomock.Any(), int64(10), "https://example.com/1", hashString("https://example.com/1")).Return(false, nil)
-	mockEntries.EXPECT().CreateOrUpdate(gomock.Any(), gomock.Any()).Return(nil)
+	mockFeeds.EXPECT().ListMutedAuthors(gomock.Any(), int64(10)).Return(nil, nil).AnyTimes()
+	mockEntries.EXPECT().SaveBatch(gomock.Any(), int64(10), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ int64, entries []model.Entry, _ int) (int, int, error) {
+			require.Len(t, entries, 1)
+			require.Equal(t, hashString("https://example.com/1"), entries[0].Hash)
+			return 1, 0, nil
+		},
+	)
 
 	client := &http.Client{
 		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
@@ -144,186 +275,939 @@ func TestRefreshService_RefreshFeed_Success(t *testing.T) {
 		mockFeeds,
 		mockEntries,
 		nil,
+		nil,
 		mockIcons,
+		nil,
 		network.NewClientFactoryForTest(client),
 		nil,
 		nil,
+		nil,
+		nil,
+		nil,
 	)
 
 	err := svc.RefreshFeed(context.Background(), 10)
 	require.NoError(t, err)
 }
 
-func TestRefreshService_RefreshFeed_FallbackUserAgent(t *testing.T) {
+func TestRefreshService_RefreshFeed_ReplacesGeneratedIcon(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockFeeds := mock.NewMockFeedRepository(ctrl)
+	mockFeeds.EXPECT().UpdateTitles(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockFeeds.EXPECT().UpdateLastFetchedAt(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockFeeds.EXPECT().UpdateLastEntrySeenAt(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockFeeds.EXPECT().UpdateErrorMessage(gomock.Any(), int64(10), nil).Return(nil).AnyTimes()
+	mockFeeds.EXPECT().UpdateConditionalGet(gomock.Any(), int64(10), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockFeeds.EXPECT().ListMutedAuthors(gomock.Any(), int64(10)).Return(nil, nil).AnyTimes()
 	mockEntries := mock.NewMockEntryRepository(ctrl)
+	mockEntries.EXPECT().SaveBatch(gomock.Any(), int64(10), gomock.Any(), gomock.Any()).Return(0, 0, nil).AnyTimes()
+	mockIcons := servicemock.NewMockIconService(ctrl)
 
-	feed := model.Feed{ID: 2, URL: "https://example.com/rss", Title: "Feed"}
-	mockFeeds.EXPECT().GetByID(gomock.Any(), int64(2)).Return(feed, nil)
-	mockFeeds.EXPECT().UpdateErrorMessage(gomock.Any(), int64(2), nil).Return(nil)
-	mockFeeds.EXPECT().UpdateSiteURL(gomock.Any(), int64(2), "https://example.com").Return(nil)
+	generated := "example.com.generated-t.png"
+	source := model.IconSourceGenerated
+	siteURL := "https://example.com"
+	feed := model.Feed{ID: 10, URL: "https://example.com/rss", SiteURL: &siteURL, Title: "Test Feed", IconPath: &generated, IconSource: &source}
+	mockFeeds.EXPECT().GetByID(gomock.Any(), int64(10)).Return(feed, nil).Times(2)
 
-	settings := &settingsServiceStub{fallbackUserAgent: "UA-Test"}
+	client := &http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(sampleRSS)),
+				Header:     make(http.Header),
+				Request:    req,
+			}, nil
+		}),
+	}
+	svc := service.NewRefreshService(mockFeeds, mockEntries, nil, nil, mockIcons, nil, network.NewClientFactoryForTest(client), nil, nil, nil, nil, nil)
+
+	// Still nothing to fetch: the generated icon is kept
+	gomock.InOrder(
+		mockIcons.EXPECT().FetchAndSaveIcon(gomock.Any(), "https://example.com/icon.png", siteURL).Return("", nil),
+		mockIcons.EXPECT().GenerateIcon(siteURL, "Test Feed").Return(generated, nil),
+		mockFeeds.EXPECT().UpdateGeneratedIconPath(gomock.Any(), int64(10), generated).Return(nil),
+	)
+	require.NoError(t, svc.RefreshFeed(context.Background(), 10))
+
+	// A real icon replaces it
+	mockIcons.EXPECT().FetchAndSaveIcon(gomock.Any(), "https://example.com/icon.png", siteURL).Return("example.com.png", nil)
+	mockFeeds.EXPECT().UpdateIconPath(gomock.Any(), int64(10), "example.com.png").Return(nil)
+	require.NoError(t, svc.RefreshFeed(context.Background(), 10))
+}
+
+func TestRefreshService_RefreshFeed_InheritsFolderUserAgent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFeeds := mock.NewMockFeedRepository(ctrl)
+	mockFeeds.EXPECT().UpdateTitles(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockFeeds.EXPECT().UpdateLastFetchedAt(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockFeeds.EXPECT().UpdateLastEntrySeenAt(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockFeeds.EXPECT().UpdateErrorMessage(gomock.Any(), int64(10), nil).Return(nil).AnyTimes()
+	mockFeeds.EXPECT().UpdateConditionalGet(gomock.Any(), int64(10), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockFeeds.EXPECT().ListMutedAuthors(gomock.Any(), int64(10)).Return(nil, nil).AnyTimes()
+	mockEntries := mock.NewMockEntryRepository(ctrl)
+	mockEntries.EXPECT().SaveBatch(gomock.Any(), int64(10), gomock.Any(), gomock.Any()).Return(0, 0, nil).AnyTimes()
+	mockFolders := mock.NewMockFolderRepository(ctrl)
+
+	parentUA := "Parent/1.0"
+	parentID, childID := int64(1), int64(2)
+	mockFolders.EXPECT().GetByID(gomock.Any(), childID).Return(model.Folder{ID: childID, ParentID: &parentID}, nil).AnyTimes()
+	mockFolders.EXPECT().GetByID(gomock.Any(), parentID).Return(model.Folder{ID: parentID, FeedDefaults: model.FeedDefaults{UserAgent: &parentUA}}, nil).AnyTimes()
 
+	var userAgents []string
 	client := &http.Client{
 		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
-			if req.Header.Get("User-Agent") == settings.fallbackUserAgent {
-				return &http.Response{
-					StatusCode: http.StatusOK,
-					Body:       io.NopCloser(strings.NewReader(sampleRSS)),
-					Header:     make(http.Header),
-					Request:    req,
-				}, nil
-			}
+			userAgents = append(userAgents, req.Header.Get("User-Agent"))
 			return &http.Response{
-				StatusCode: http.StatusBadRequest,
-				Body:       http.NoBody,
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(sampleRSS)),
 				Header:     make(http.Header),
 				Request:    req,
 			}, nil
 		}),
 	}
+	svc := service.NewRefreshService(mockFeeds, mockEntries, nil, nil, nil, nil, network.NewClientFactoryForTest(client), nil, nil, nil, mockFolders, nil)
 
-	mockEntries.EXPECT().ExistsByHash(gomock.Any(), int64(2), hashString("https://example.com/1")).Return(false, nil)
-	mockEntries.EXPECT().ExistsByLegacyURL(gomock.Any(), int64(2), "https://example.com/1", hashString("https://example.com/1")).Return(false, nil)
-	mockEntries.EXPECT().CreateOrUpdate(gomock.Any(), gomock.Any()).Return(nil)
+	// The grandparent folder's User-Agent reaches the request
+	siteURL := "https://example.com"
+	feed := model.Feed{ID: 10, URL: "https://example.com/rss", SiteURL: &siteURL, Title: "Test Feed", FolderID: &childID}
+	mockFeeds.EXPECT().GetByID(gomock.Any(), int64(10)).Return(feed, nil)
+	require.NoError(t, svc.RefreshFeed(context.Background(), 10))
 
-	svc := service.NewRefreshService(
-		mockFeeds,
-		mockEntries,
-		settings,
-		nil,
-		network.NewClientFactoryForTest(client),
-		nil,
-		nil,
-	)
+	// The feed's own one wins
+	feedUA := "Feed/1.0"
+	feed.UserAgent = &feedUA
+	mockFeeds.EXPECT().GetByID(gomock.Any(), int64(10)).Return(feed, nil)
+	require.NoError(t, svc.RefreshFeed(context.Background(), 10))
 
-	err := svc.RefreshFeed(context.Background(), 2)
-	require.NoError(t, err)
+	require.Equal(t, []string{parentUA, feedUA}, userAgents)
 }
 
-func TestRefreshService_RefreshFeeds_Empty(t *testing.T) {
+func TestRefreshService_RefreshFeed_InitialBackfill(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	svc := service.NewRefreshService(
-		mock.NewMockFeedRepository(ctrl),
-		mock.NewMockEntryRepository(ctrl),
-		nil,
-		nil,
-		network.NewClientFactoryForTest(&http.Client{}),
-		nil,
-		nil,
+	mockFeeds := mock.NewMockFeedRepository(ctrl)
+	mockFeeds.EXPECT().UpdateTitles(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockFeeds.EXPECT().UpdateLastFetchedAt(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockFeeds.EXPECT().UpdateLastEntrySeenAt(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockEntries := mock.NewMockEntryRepository(ctrl)
+
+	siteURL, iconPath := "https://example.com", "example.com.png"
+	feed := model.Feed{ID: 10, URL: "https://example.com/rss", Title: "Feed", SiteURL: &siteURL, IconPath: &iconPath}
+	mockFeeds.EXPECT().GetByID(gomock.Any(), int64(10)).Return(feed, nil)
+	mockFeeds.EXPECT().UpdateErrorMessage(gomock.Any(), int64(10), nil).Return(nil)
+	mockFeeds.EXPECT().ListMutedAuthors(gomock.Any(), int64(10)).Return(nil, nil).AnyTimes()
+	mockEntries.EXPECT().SaveBatch(gomock.Any(), int64(10), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ int64, entries []model.Entry, _ int) (int, int, error) {
+			require.Len(t, entries, 1)
+			require.Equal(t, "Newest", *entries[0].Title)
+			require.True(t, entries[0].Read)
+			return 1, 0, nil
+		},
 	)
 
-	err := svc.RefreshFeeds(context.Background(), nil)
-	require.NoError(t, err)
+	client := &http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(backfillRSS)),
+				Header:     make(http.Header),
+				Request:    req,
+			}, nil
+		}),
+	}
+
+	svc := service.NewRefreshService(mockFeeds, mockEntries, nil, nil, nil, nil, network.NewClientFactoryForTest(client), nil, nil, nil, nil, nil)
+	ctx := service.WithInitialBackfillForTest(context.Background(), service.InitialBackfill{MarkRead: true, Limit: 1})
+	require.NoError(t, svc.RefreshFeed(ctx, 10))
 }
 
-func TestRefreshService_RefreshFeeds_GetByIDsError(t *testing.T) {
+func TestRefreshService_RefreshFeed_CachesImages(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockFeeds := mock.NewMockFeedRepository(ctrl)
-	mockFeeds.EXPECT().GetByIDs(gomock.Any(), []int64{1, 2}).Return(nil, errors.New("db error"))
+	mockFeeds.EXPECT().UpdateLastFetchedAt(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockFeeds.EXPECT().UpdateLastEntrySeenAt(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockEntries := mock.NewMockEntryRepository(ctrl)
+	mockImages := servicemock.NewMockImageCacheService(ctrl)
+
+	siteURL, iconPath := "https://example.com", "example.com.png"
+	feed := model.Feed{ID: 10, URL: "https://example.com/rss", Title: "Test Feed", Type: "picture", SiteURL: &siteURL, IconPath: &iconPath}
+	mockFeeds.EXPECT().GetByID(gomock.Any(), int64(10)).Return(feed, nil)
+	mockFeeds.EXPECT().UpdateErrorMessage(gomock.Any(), int64(10), nil).Return(nil)
+
+	hash := hashString("https://example.com/1")
+	rewrite := mockImages.EXPECT().RewriteEntries(gomock.Any(), feed, gomock.Any()).Do(
+		func(_ context.Context, _ model.Feed, entries []model.Entry) {
+			cached := "cached"
+			entries[0].Content = &cached
+		},
+	)
+	mockFeeds.EXPECT().ListMutedAuthors(gomock.Any(), int64(10)).Return(nil, nil).AnyTimes()
+	save := mockEntries.EXPECT().SaveBatch(gomock.Any(), int64(10), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ int64, entries []model.Entry, _ int) (int, int, error) {
+			require.Equal(t, "cached", *entries[0].Content)
+			return 0, 1, nil
+		},
+	).After(rewrite)
+	mockImages.EXPECT().CacheEntries(gomock.Any(), feed, []string{hash}).After(save)
+
+	client := &http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(sampleRSS)),
+				Header:     make(http.Header),
+				Request:    req,
+			}, nil
+		}),
+	}
 
 	svc := service.NewRefreshService(
 		mockFeeds,
-		mock.NewMockEntryRepository(ctrl),
+		mockEntries,
+		nil,
+		nil,
+		nil,
+		mockImages,
+		network.NewClientFactoryForTest(client),
+		nil,
 		nil,
 		nil,
-		network.NewClientFactoryForTest(&http.Client{}),
 		nil,
 		nil,
 	)
 
-	err := svc.RefreshFeeds(context.Background(), []int64{1, 2})
-	require.Error(t, err)
+	err := svc.RefreshFeed(context.Background(), 10)
+	require.NoError(t, err)
 }
 
-func TestRefreshService_RefreshFeed_SameGUIDDifferentURL_SecondRefreshCountsAsUpdate(t *testing.T) {
+func TestRefreshService_RefreshFeed_FallbackUserAgent(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockFeeds := mock.NewMockFeedRepository(ctrl)
+	mockFeeds.EXPECT().UpdateTitles(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockFeeds.EXPECT().UpdateLastFetchedAt(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockFeeds.EXPECT().UpdateLastEntrySeenAt(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
 	mockEntries := mock.NewMockEntryRepository(ctrl)
 
-	feed := model.Feed{ID: 20, URL: "https://example.com/rss", Title: "Feed"}
-	mockFeeds.EXPECT().GetByID(gomock.Any(), int64(20)).Return(feed, nil).Times(2)
-	mockFeeds.EXPECT().UpdateErrorMessage(gomock.Any(), int64(20), nil).Return(nil).Times(2)
-	mockFeeds.EXPECT().UpdateSiteURL(gomock.Any(), int64(20), "https://example.com").Return(nil).Times(2)
+	feed := model.Feed{ID: 2, URL: "https://example.com/rss", Title: "Feed"}
+	mockFeeds.EXPECT().GetByID(gomock.Any(), int64(2)).Return(feed, nil)
+	mockFeeds.EXPECT().UpdateErrorMessage(gomock.Any(), int64(2), nil).Return(nil)
+	mockFeeds.EXPECT().UpdateSiteURL(gomock.Any(), int64(2), "https://example.com").Return(nil)
+	mockFeeds.EXPECT().UpdatePreferredUserAgent(gomock.Any(), int64(2), model.FeedUserAgentFallback).Return(nil)
 
-	var call int
-	const rssTemplate = `<?xml version="1.0" encoding="UTF-8"?>
-<rss version="2.0">
-<channel>
-<title>Test Feed</title>
-<link>https://example.com</link>
-<description>Desc</description>
-<item>
-  <title>Item 1</title>
-  <guid>v2ex-guid-1</guid>
-  <link>%s</link>
-  <description>Content 1</description>
-</item>
-</channel>
-</rss>`
+	settings := &settingsServiceStub{fallbackUserAgent: "UA-Test"}
 
 	client := &http.Client{
 		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
-			call++
-			link := "https://www.v2ex.com/t/1193191#reply10"
-			if call == 2 {
-				link = "https://www.v2ex.com/t/1193191#reply20"
+			if req.Header.Get("User-Agent") == settings.fallbackUserAgent {
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       io.NopCloser(strings.NewReader(sampleRSS)),
+					Header:     make(http.Header),
+					Request:    req,
+				}, nil
 			}
 			return &http.Response{
-				StatusCode: http.StatusOK,
-				Body:       io.NopCloser(strings.NewReader(fmt.Sprintf(rssTemplate, link))),
+				StatusCode: http.StatusBadRequest,
+				Body:       http.NoBody,
 				Header:     make(http.Header),
 				Request:    req,
 			}, nil
 		}),
 	}
 
-	seen := make(map[string]bool)
-	existsResults := make([]bool, 0, 2)
-	mockEntries.EXPECT().ExistsByHash(gomock.Any(), int64(20), hashString("v2ex-guid-1")).DoAndReturn(
-		func(_ context.Context, _ int64, hash string) (bool, error) {
-			exists := seen[hash]
-			existsResults = append(existsResults, exists)
-			return exists, nil
-		},
-	).Times(2)
-	mockEntries.EXPECT().ExistsByLegacyURL(gomock.Any(), int64(20), "https://www.v2ex.com/t/1193191#reply10", hashString("v2ex-guid-1")).Return(false, nil).Times(1)
-	mockEntries.EXPECT().CreateOrUpdate(gomock.Any(), gomock.Any()).DoAndReturn(
-		func(_ context.Context, entry model.Entry) error {
-			seen[entry.Hash] = true
-			return nil
+	mockFeeds.EXPECT().ListMutedAuthors(gomock.Any(), int64(2)).Return(nil, nil).AnyTimes()
+	mockEntries.EXPECT().SaveBatch(gomock.Any(), int64(2), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ int64, entries []model.Entry, _ int) (int, int, error) {
+			require.Len(t, entries, 1)
+			require.Equal(t, hashString("https://example.com/1"), entries[0].Hash)
+			return 1, 0, nil
 		},
-	).Times(2)
+	)
 
 	svc := service.NewRefreshService(
 		mockFeeds,
 		mockEntries,
 		nil,
+		settings,
+		nil,
 		nil,
 		network.NewClientFactoryForTest(client),
 		nil,
 		nil,
+		nil,
+		nil,
+		nil,
 	)
 
-	err := svc.RefreshFeed(context.Background(), 20)
-	require.NoError(t, err)
-	err = svc.RefreshFeed(context.Background(), 20)
+	err := svc.RefreshFeed(context.Background(), 2)
+	require.NoError(t, err)
+}
+
+func TestRefreshService_RefreshFeed_PreferredFallbackUserAgentTriedFirst(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFeeds := mock.NewMockFeedRepository(ctrl)
+	mockFeeds.EXPECT().UpdateTitles(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockFeeds.EXPECT().UpdateLastFetchedAt(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockFeeds.EXPECT().UpdateLastEntrySeenAt(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockEntries := mock.NewMockEntryRepository(ctrl)
+
+	feed := model.Feed{ID: 2, URL: "https://example.com/rss", Title: "Feed", PreferredUserAgent: model.FeedUserAgentFallback}
+	mockFeeds.EXPECT().GetByID(gomock.Any(), int64(2)).Return(feed, nil)
+	mockFeeds.EXPECT().UpdateErrorMessage(gomock.Any(), int64(2), nil).Return(nil)
+	mockFeeds.EXPECT().UpdateSiteURL(gomock.Any(), int64(2), "https://example.com").Return(nil)
+	mockFeeds.EXPECT().ListMutedAuthors(gomock.Any(), int64(2)).Return(nil, nil).AnyTimes()
+	mockEntries.EXPECT().SaveBatch(gomock.Any(), int64(2), gomock.Any(), gomock.Any()).Return(1, 0, nil)
+
+	settings := &settingsServiceStub{fallbackUserAgent: "UA-Test"}
+
+	var userAgents []string
+	client := &http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			userAgents = append(userAgents, req.Header.Get("User-Agent"))
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(sampleRSS)),
+				Header:     make(http.Header),
+				Request:    req,
+			}, nil
+		}),
+	}
+
+	svc := service.NewRefreshService(
+		mockFeeds,
+		mockEntries,
+		nil,
+		settings,
+		nil,
+		nil,
+		network.NewClientFactoryForTest(client),
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+
+	err := svc.RefreshFeed(context.Background(), 2)
+	require.NoError(t, err)
+	require.Equal(t, []string{"UA-Test"}, userAgents)
+}
+
+func TestRefreshService_RefreshFeed_PreferredFallbackUserAgentFailsBackToDefault(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFeeds := mock.NewMockFeedRepository(ctrl)
+	mockFeeds.EXPECT().UpdateTitles(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockFeeds.EXPECT().UpdateLastFetchedAt(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockFeeds.EXPECT().UpdateLastEntrySeenAt(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockEntries := mock.NewMockEntryRepository(ctrl)
+
+	feed := model.Feed{ID: 2, URL: "https://example.com/rss", Title: "Feed", PreferredUserAgent: model.FeedUserAgentFallback}
+	mockFeeds.EXPECT().GetByID(gomock.Any(), int64(2)).Return(feed, nil)
+	mockFeeds.EXPECT().UpdateErrorMessage(gomock.Any(), int64(2), nil).Return(nil)
+	mockFeeds.EXPECT().UpdateSiteURL(gomock.Any(), int64(2), "https://example.com").Return(nil)
+	mockFeeds.EXPECT().UpdatePreferredUserAgent(gomock.Any(), int64(2), model.FeedUserAgentDefault).Return(nil)
+	mockFeeds.EXPECT().ListMutedAuthors(gomock.Any(), int64(2)).Return(nil, nil).AnyTimes()
+	mockEntries.EXPECT().SaveBatch(gomock.Any(), int64(2), gomock.Any(), gomock.Any()).Return(1, 0, nil)
+
+	settings := &settingsServiceStub{fallbackUserAgent: "UA-Test"}
+
+	var userAgents []string
+	client := &http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			userAgents = append(userAgents, req.Header.Get("User-Agent"))
+			if req.Header.Get("User-Agent") == settings.fallbackUserAgent {
+				return &http.Response{
+					StatusCode: http.StatusForbidden,
+					Body:       http.NoBody,
+					Header:     make(http.Header),
+					Request:    req,
+				}, nil
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(sampleRSS)),
+				Header:     make(http.Header),
+				Request:    req,
+			}, nil
+		}),
+	}
+
+	svc := service.NewRefreshService(
+		mockFeeds,
+		mockEntries,
+		nil,
+		settings,
+		nil,
+		nil,
+		network.NewClientFactoryForTest(client),
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+
+	err := svc.RefreshFeed(context.Background(), 2)
+	require.NoError(t, err)
+	require.Equal(t, []string{"UA-Test", config.DefaultUserAgent}, userAgents)
+}
+
+func TestRefreshService_RefreshFeeds_Empty(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc := service.NewRefreshService(
+		mock.NewMockFeedRepository(ctrl),
+		mock.NewMockEntryRepository(ctrl),
+		nil,
+		nil,
+		nil,
+		nil,
+		network.NewClientFactoryForTest(&http.Client{}),
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+
+	err := svc.RefreshFeeds(context.Background(), nil)
+	require.NoError(t, err)
+}
+
+func TestRefreshService_RefreshFeeds_GetByIDsError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFeeds := mock.NewMockFeedRepository(ctrl)
+	mockFeeds.EXPECT().GetByIDs(gomock.Any(), []int64{1, 2}).Return(nil, errors.New("db error"))
+
+	svc := service.NewRefreshService(
+		mockFeeds,
+		mock.NewMockEntryRepository(ctrl),
+		nil,
+		nil,
+		nil,
+		nil,
+		network.NewClientFactoryForTest(&http.Client{}),
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+
+	err := svc.RefreshFeeds(context.Background(), []int64{1, 2})
+	require.Error(t, err)
+}
+
+func TestRefreshService_RefreshFeeds_SkipsPaused(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFeeds := mock.NewMockFeedRepository(ctrl)
+	mockFeeds.EXPECT().UpdateLastFetchedAt(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockFeeds.EXPECT().UpdateNotModifiedStreak(gomock.Any(), gomock.Any(), 1).Return(nil).AnyTimes()
+	mockEntries := mock.NewMockEntryRepository(ctrl)
+
+	future := time.Now().Add(time.Hour)
+	past := time.Now().Add(-time.Hour)
+	mockFeeds.EXPECT().GetByIDs(gomock.Any(), []int64{1, 2}).Return([]model.Feed{
+		{ID: 1, URL: "https://paused.example.com/rss", PausedUntil: &future},
+		{ID: 2, URL: "https://example.com/rss", PausedUntil: &past},
+	}, nil)
+	mockFeeds.EXPECT().UpdateErrorMessage(gomock.Any(), int64(2), nil).Return(nil)
+
+	var hosts []string
+	client := &http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			hosts = append(hosts, req.URL.Host)
+			return &http.Response{StatusCode: http.StatusNotModified, Body: http.NoBody, Header: make(http.Header), Request: req}, nil
+		}),
+	}
+
+	svc := service.NewRefreshService(
+		mockFeeds,
+		mockEntries,
+		nil,
+		nil,
+		nil,
+		nil,
+		network.NewClientFactoryForTest(client),
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+
+	require.NoError(t, svc.RefreshFeeds(context.Background(), []int64{1, 2}))
+	require.Equal(t, []string{"example.com"}, hosts)
+}
+
+// saveSeen simulates SaveBatch against an in-memory set of stored hashes,
+// recording whether each entry already existed.
+func saveSeen(seen map[string]bool, results *[]bool, entries []model.Entry) (int, int, error) {
+	newCount, updatedCount := 0, 0
+	for _, entry := range entries {
+		*results = append(*results, seen[entry.Hash])
+		if seen[entry.Hash] {
+			updatedCount++
+		} else {
+			newCount++
+		}
+		seen[entry.Hash] = true
+	}
+	return newCount, updatedCount, nil
+}
+
+func TestRefreshService_RefreshFeed_SameGUIDDifferentURL_SecondRefreshCountsAsUpdate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFeeds := mock.NewMockFeedRepository(ctrl)
+	mockFeeds.EXPECT().UpdateTitles(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockFeeds.EXPECT().UpdateLastFetchedAt(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockEntries := mock.NewMockEntryRepository(ctrl)
+
+	feed := model.Feed{ID: 20, URL: "https://example.com/rss", Title: "Feed"}
+	mockFeeds.EXPECT().GetByID(gomock.Any(), int64(20)).Return(feed, nil).Times(2)
+	mockFeeds.EXPECT().UpdateErrorMessage(gomock.Any(), int64(20), nil).Return(nil).Times(2)
+	mockFeeds.EXPECT().UpdateSiteURL(gomock.Any(), int64(20), "https://example.com").Return(nil).Times(2)
+
+	var call int
+	const rssTemplate = `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0">
+<channel>
+<title>Test Feed</title>
+<link>https://example.com</link>
+<description>Desc</description>
+<item>
+  <title>Item 1</title>
+  <guid>v2ex-guid-1</guid>
+  <link>%s</link>
+  <description>Content 1</description>
+</item>
+</channel>
+</rss>`
+
+	client := &http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			call++
+			link := "https://www.v2ex.com/t/1193191#reply10"
+			if call == 2 {
+				link = "https://www.v2ex.com/t/1193191#reply20"
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(fmt.Sprintf(rssTemplate, link))),
+				Header:     make(http.Header),
+				Request:    req,
+			}, nil
+		}),
+	}
+
+	seen := make(map[string]bool)
+	existsResults := make([]bool, 0, 2)
+	mockFeeds.EXPECT().ListMutedAuthors(gomock.Any(), int64(20)).Return(nil, nil).AnyTimes()
+	mockEntries.EXPECT().SaveBatch(gomock.Any(), int64(20), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ int64, entries []model.Entry, _ int) (int, int, error) {
+			require.Len(t, entries, 1)
+			require.Equal(t, hashString("v2ex-guid-1"), entries[0].Hash)
+			return saveSeen(seen, &existsResults, entries)
+		},
+	).Times(2)
+
+	svc := service.NewRefreshService(
+		mockFeeds,
+		mockEntries,
+		nil,
+		nil,
+		nil,
+		nil,
+		network.NewClientFactoryForTest(client),
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+
+	err := svc.RefreshFeed(context.Background(), 20)
+	require.NoError(t, err)
+	err = svc.RefreshFeed(context.Background(), 20)
 	require.NoError(t, err)
 	require.Equal(t, []bool{false, true}, existsResults)
 }
 
+func TestRefreshService_RefreshFeed_DynamicFeedWithoutGUID_MutatingLinkKeepsIdentity(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFeeds := mock.NewMockFeedRepository(ctrl)
+	mockFeeds.EXPECT().UpdateTitles(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockFeeds.EXPECT().UpdateLastFetchedAt(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockFeeds.EXPECT().UpdateLastEntrySeenAt(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockEntries := mock.NewMockEntryRepository(ctrl)
+
+	feed := model.Feed{ID: 21, URL: "https://example.com/rss", Title: "Feed"}
+	mockFeeds.EXPECT().GetByID(gomock.Any(), int64(21)).Return(feed, nil).Times(2)
+	mockFeeds.EXPECT().UpdateErrorMessage(gomock.Any(), int64(21), nil).Return(nil).Times(2)
+	mockFeeds.EXPECT().UpdateSiteURL(gomock.Any(), int64(21), "https://example.com").Return(nil).Times(2)
+
+	// All items share the same updated time, as v2ex regenerates the feed on every request
+	var call int
+	const atomTemplate = `<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+<title>Test Feed</title>
+<link rel="alternate" href="https://example.com"/>
+<updated>2026-01-02T03:04:05Z</updated>
+<entry>
+  <title>Item 1</title>
+  <link rel="alternate" href="https://www.v2ex.com/t/1193191#reply%d"/>
+  <updated>2026-01-02T03:04:05Z</updated>
+  <content type="html">Content 1</content>
+</entry>
+<entry>
+  <title>Item 2</title>
+  <link rel="alternate" href="https://www.v2ex.com/t/1193192#reply%d"/>
+  <updated>2026-01-02T03:04:05Z</updated>
+  <content type="html">Content 2</content>
+</entry>
+</feed>`
+
+	client := &http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			call++
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(fmt.Sprintf(atomTemplate, call*10, call*5))),
+				Header:     make(http.Header),
+				Request:    req,
+			}, nil
+		}),
+	}
+
+	stableHashes := map[string]bool{
+		hashString("https://www.v2ex.com/t/1193191"): true,
+		hashString("https://www.v2ex.com/t/1193192"): true,
+	}
+	seen := make(map[string]bool)
+	existsResults := make([]bool, 0, 4)
+	mockFeeds.EXPECT().ListMutedAuthors(gomock.Any(), int64(21)).Return(nil, nil).AnyTimes()
+	mockEntries.EXPECT().SaveBatch(gomock.Any(), int64(21), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ int64, entries []model.Entry, _ int) (int, int, error) {
+			for _, entry := range entries {
+				require.True(t, stableHashes[entry.Hash], "hash must not depend on the fragment")
+			}
+			return saveSeen(seen, &existsResults, entries)
+		},
+	).Times(2)
+
+	svc := service.NewRefreshService(
+		mockFeeds,
+		mockEntries,
+		nil,
+		nil,
+		nil,
+		nil,
+		network.NewClientFactoryForTest(client),
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+
+	err := svc.RefreshFeed(context.Background(), 21)
+	require.NoError(t, err)
+	err = svc.RefreshFeed(context.Background(), 21)
+	require.NoError(t, err)
+	require.Equal(t, []bool{false, false, true, true}, existsResults)
+}
+
+func TestRefreshService_RefreshFeeds_RecordsRun(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFeeds := mock.NewMockFeedRepository(ctrl)
+	mockFeeds.EXPECT().UpdateTitles(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockFeeds.EXPECT().UpdateLastFetchedAt(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockFeeds.EXPECT().UpdateLastEntrySeenAt(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockEntries := mock.NewMockEntryRepository(ctrl)
+	mockRuns := mock.NewMockRefreshRunRepository(ctrl)
+
+	siteURL := "https://example.com"
+	okFeed := model.Feed{ID: 30, URL: "https://example.com/rss", Title: "OK", SiteURL: &siteURL}
+	badFeed := model.Feed{ID: 31, URL: "https://broken.example.org/rss", Title: "Broken"}
+	mockFeeds.EXPECT().GetByIDs(gomock.Any(), []int64{30, 31}).Return([]model.Feed{okFeed, badFeed}, nil)
+	mockFeeds.EXPECT().UpdateErrorMessage(gomock.Any(), int64(30), nil).Return(nil)
+	errMsg := "HTTP 500"
+	mockFeeds.EXPECT().UpdateErrorMessage(gomock.Any(), int64(31), &errMsg).Return(nil)
+
+	mockFeeds.EXPECT().ListMutedAuthors(gomock.Any(), int64(30)).Return(nil, nil).AnyTimes()
+	mockEntries.EXPECT().SaveBatch(gomock.Any(), int64(30), gomock.Any(), gomock.Any()).Return(1, 0, nil)
+
+	mockRuns.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any(), 50).DoAndReturn(
+		func(_ context.Context, run model.RefreshRun, feeds []model.RefreshRunFeed, _ int) (model.RefreshRun, error) {
+			require.Equal(t, model.RefreshTriggerManual, run.Trigger)
+			require.Equal(t, 2, run.FeedsTotal)
+			require.Equal(t, 1, run.FeedsFailed)
+			require.Equal(t, 1, run.EntriesNew)
+			require.False(t, run.FinishedAt.Before(run.StartedAt))
+
+			byID := make(map[int64]model.RefreshRunFeed)
+			for _, f := range feeds {
+				byID[f.FeedID] = f
+			}
+			require.Nil(t, byID[30].ErrorMessage)
+			require.Equal(t, 1, byID[30].EntriesNew)
+			require.NotNil(t, byID[31].ErrorMessage)
+			require.Equal(t, "HTTP 500", *byID[31].ErrorMessage, "run history matches the feed error message")
+			run.ID = 1
+			return run, nil
+		},
+	)
+
+	client := &http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			if req.URL.Host == "broken.example.org" {
+				return &http.Response{StatusCode: http.StatusInternalServerError, Body: http.NoBody, Header: make(http.Header), Request: req}, nil
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(sampleRSS)),
+				Header:     make(http.Header),
+				Request:    req,
+			}, nil
+		}),
+	}
+
+	svc := service.NewRefreshService(
+		mockFeeds,
+		mockEntries,
+		mockRuns,
+		nil,
+		nil,
+		nil,
+		network.NewClientFactoryForTest(client),
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+
+	err := svc.RefreshFeeds(context.Background(), []int64{30, 31})
+	require.NoError(t, err)
+}
+
+func TestRefreshService_RefreshFeeds_UnchangedEntriesNotRewritten(t *testing.T) {
+	database := testutil.NewTestDB(t)
+	feeds := repository.NewFeedRepository(database, nil, nil)
+	entries := repository.NewEntryRepository(database, nil, nil)
+	runs := repository.NewRefreshRunRepository(database, nil, nil)
+	ctx := context.Background()
+
+	feedID := testutil.SeedFeed(t, database, model.Feed{Title: "Feed", URL: "https://example.com/rss"})
+	client := &http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(sampleRSS)),
+				Header:     make(http.Header),
+				Request:    req,
+			}, nil
+		}),
+	}
+	svc := service.NewRefreshService(feeds, entries, runs, nil, nil, nil, network.NewClientFactoryForTest(client), nil, nil, nil, nil, nil)
+
+	require.NoError(t, svc.RefreshFeeds(ctx, []int64{feedID}))
+	// Backdate the entries so a rewrite within the same second would still show
+	_, err := database.ExecContext(ctx, `UPDATE entries SET updated_at = '2020-01-01T00:00:00Z'`)
+	require.NoError(t, err)
+	before, err := entries.List(ctx, repository.EntryListFilter{FeedID: &feedID, Limit: 10})
+	require.NoError(t, err)
+	require.NotEmpty(t, before)
+
+	require.NoError(t, svc.RefreshFeeds(ctx, []int64{feedID}))
+	after, err := entries.List(ctx, repository.EntryListFilter{FeedID: &feedID, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, after, len(before))
+	for i := range after {
+		require.Equal(t, before[i].UpdatedAt, after[i].UpdatedAt)
+	}
+
+	history, err := svc.ListRuns(ctx)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	require.Equal(t, len(before), history[1].EntriesNew)
+	require.Zero(t, history[0].EntriesNew)
+	require.Zero(t, history[0].EntriesUpdated)
+}
+
+func TestRefreshService_RefreshFeeds_UpdatesUpstreamTitle(t *testing.T) {
+	database := testutil.NewTestDB(t)
+	feeds := repository.NewFeedRepository(database, nil, nil)
+	entries := repository.NewEntryRepository(database, nil, nil)
+	runs := repository.NewRefreshRunRepository(database, nil, nil)
+	ctx := context.Background()
+
+	renamed := "My Name"
+	renamedID := testutil.SeedFeed(t, database, model.Feed{Title: "Old", URL: "https://example.com/rss", CustomTitle: &renamed})
+	matching := "Test Feed"
+	matchingID := testutil.SeedFeed(t, database, model.Feed{Title: "Old", URL: "https://example.org/rss", CustomTitle: &matching})
+	client := &http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(sampleRSS)),
+				Header:     make(http.Header),
+				Request:    req,
+			}, nil
+		}),
+	}
+	svc := service.NewRefreshService(feeds, entries, runs, nil, nil, nil, network.NewClientFactoryForTest(client), nil, nil, nil, nil, nil)
+	require.NoError(t, svc.RefreshFeeds(ctx, []int64{renamedID, matchingID}))
+
+	feed, err := feeds.GetByID(ctx, renamedID)
+	require.NoError(t, err)
+	require.Equal(t, "Test Feed", feed.Title)
+	require.Equal(t, "My Name", feed.DisplayTitle())
+
+	// A rename that the feed has caught up with is no longer kept apart
+	feed, err = feeds.GetByID(ctx, matchingID)
+	require.NoError(t, err)
+	require.Equal(t, "Test Feed", feed.Title)
+	require.Nil(t, feed.CustomTitle)
+}
+
+func TestRefreshService_RefreshFeeds_StoresLanguage(t *testing.T) {
+	database := testutil.NewTestDB(t)
+	feeds := repository.NewFeedRepository(database, nil, nil)
+	entries := repository.NewEntryRepository(database, nil, nil)
+	runs := repository.NewRefreshRunRepository(database, nil, nil)
+	ctx := context.Background()
+
+	declaredID := testutil.SeedFeed(t, database, model.Feed{Title: "Declared", URL: "https://example.com/rss"})
+	undeclaredID := testutil.SeedFeed(t, database, model.Feed{Title: "Undeclared", URL: "https://example.org/rss"})
+	bodies := map[string]string{
+		"example.com": `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0"><channel><title>Declared</title><language>de-DE</language>
+<item><title>Hello</title><link>https://example.com/1</link></item>
+</channel></rss>`,
+		"example.org": `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0"><channel><title>Undeclared</title>
+<item><title>今日のニュース</title><link>https://example.org/1</link><description>&lt;p&gt;新しいバージョンが公開されました&lt;/p&gt;</description></item>
+</channel></rss>`,
+	}
+	client := &http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(bodies[req.URL.Host])),
+				Header:     make(http.Header),
+				Request:    req,
+			}, nil
+		}),
+	}
+	svc := service.NewRefreshService(feeds, entries, runs, nil, nil, nil, network.NewClientFactoryForTest(client), nil, nil, nil, nil, nil)
+	require.NoError(t, svc.RefreshFeeds(ctx, []int64{declaredID, undeclaredID}))
+
+	// The declared language wins over what the text looks like
+	feed, err := feeds.GetByID(ctx, declaredID)
+	require.NoError(t, err)
+	require.Equal(t, "de", feed.Language)
+	stored, err := entries.List(ctx, repository.EntryListFilter{FeedID: &declaredID, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, stored, 1)
+	require.Equal(t, "de", stored[0].Language)
+
+	feed, err = feeds.GetByID(ctx, undeclaredID)
+	require.NoError(t, err)
+	require.Empty(t, feed.Language)
+	stored, err = entries.List(ctx, repository.EntryListFilter{FeedID: &undeclaredID, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, stored, 1)
+	require.Equal(t, "ja", stored[0].Language)
+}
+
+func TestRefreshService_RefreshFeeds_EvictsOverCap(t *testing.T) {
+	database := testutil.NewTestDB(t)
+	feeds := repository.NewFeedRepository(database, nil, nil)
+	entries := repository.NewEntryRepository(database, nil, nil)
+	runs := repository.NewRefreshRunRepository(database, nil, nil)
+	ctx := context.Background()
+
+	feedID := testutil.SeedFeed(t, database, model.Feed{Title: "Feed", URL: "https://example.com/rss"})
+	feed, err := feeds.GetByID(ctx, feedID)
+	require.NoError(t, err)
+	feed.MaxEntries = 2
+	_, err = feeds.Update(ctx, feed)
+	require.NoError(t, err)
+	for i := 1; i <= 3; i++ {
+		published := time.Date(2005, 1, i, 0, 0, 0, 0, time.UTC)
+		testutil.SeedEntry(t, database, model.Entry{FeedID: feedID, URL: stringPtr(fmt.Sprintf("https://example.com/old/%d", i)), PublishedAt: &published, Read: true})
+	}
+	newestOld := time.Date(2005, 1, 3, 0, 0, 0, 0, time.UTC)
+
+	client := &http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(sampleRSS)),
+				Header:     make(http.Header),
+				Request:    req,
+			}, nil
+		}),
+	}
+	svc := service.NewRefreshService(feeds, entries, runs, nil, nil, nil, network.NewClientFactoryForTest(client), nil, nil, nil, nil, nil)
+	require.NoError(t, svc.RefreshFeeds(ctx, []int64{feedID}))
+
+	stored, err := entries.List(ctx, repository.EntryListFilter{FeedID: &feedID, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, stored, 2)
+	require.False(t, stored[0].Read)
+	require.Equal(t, newestOld, stored[1].PublishedAt.UTC())
+
+	history, err := svc.ListRuns(ctx)
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	require.Equal(t, 2, history[0].EntriesEvicted)
+	_, runFeeds, err := svc.GetRun(ctx, history[0].ID)
+	require.NoError(t, err)
+	require.Len(t, runFeeds, 1)
+	require.Equal(t, 2, runFeeds[0].EntriesEvicted)
+}
+
+func TestRefreshService_GetRun_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRuns := mock.NewMockRefreshRunRepository(ctrl)
+	mockRuns.EXPECT().GetByID(gomock.Any(), int64(99)).Return(model.RefreshRun{}, sql.ErrNoRows)
+
+	svc := service.NewRefreshService(nil, nil, mockRuns, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	_, _, err := svc.GetRun(context.Background(), 99)
+	require.ErrorIs(t, err, service.ErrNotFound)
+}
+
 type rateLimitStub struct {
-	interval time.Duration
+	interval      time.Duration
+	maxConcurrent map[string]int
 }
 
 func (r *rateLimitStub) GetInterval(ctx context.Context, host string) int {
@@ -339,7 +1223,11 @@ func (r *rateLimitStub) GetIntervalDuration(ctx context.Context, host string) ti
 	return r.interval
 }
 
-func (r *rateLimitStub) SetInterval(ctx context.Context, host string, seconds int) error {
+func (r *rateLimitStub) GetMaxConcurrent(ctx context.Context, host string) int {
+	return r.maxConcurrent[host]
+}
+
+func (r *rateLimitStub) SetInterval(ctx context.Context, host string, seconds int, maxConcurrent int) error {
 	return nil
 }
 
@@ -356,6 +1244,8 @@ func TestRefreshService_RefreshFeeds_WithRateLimit(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockFeeds := mock.NewMockFeedRepository(ctrl)
+	mockFeeds.EXPECT().UpdateLastFetchedAt(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockFeeds.EXPECT().UpdateNotModifiedStreak(gomock.Any(), gomock.Any(), 1).Return(nil).AnyTimes()
 	mockEntries := mock.NewMockEntryRepository(ctrl)
 
 	feeds := []model.Feed{
@@ -383,16 +1273,218 @@ func TestRefreshService_RefreshFeeds_WithRateLimit(t *testing.T) {
 		mockEntries,
 		nil,
 		nil,
+		nil,
+		nil,
 		network.NewClientFactoryForTest(client),
 		nil,
 		&rateLimitStub{interval: 5 * time.Millisecond},
+		nil,
+		nil,
+		nil,
 	)
 
 	err := svc.RefreshFeeds(context.Background(), []int64{1, 2})
 	require.NoError(t, err)
 }
 
-func TestRefreshService_RefreshFeedWithFreshClient_HTTPError(t *testing.T) {
+// peakConcurrencyClient answers 304 after a short delay and records the most
+// requests it saw in flight at once.
+func peakConcurrencyClient(peak *int32) *http.Client {
+	var inFlight int32
+	return &http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			n := atomic.AddInt32(&inFlight, 1)
+			defer atomic.AddInt32(&inFlight, -1)
+			for {
+				old := atomic.LoadInt32(peak)
+				if n <= old || atomic.CompareAndSwapInt32(peak, old, n) {
+					break
+				}
+			}
+			time.Sleep(20 * time.Millisecond)
+			return &http.Response{
+				StatusCode: http.StatusNotModified,
+				Body:       http.NoBody,
+				Header:     make(http.Header),
+				Request:    req,
+			}, nil
+		}),
+	}
+}
+
+func TestRefreshService_RefreshFeeds_ConcurrencySettings(t *testing.T) {
+	tests := []struct {
+		name     string
+		urls     []string
+		general  *service.GeneralSettings
+		perHost  map[string]int
+		wantPeak int32
+	}{
+		{
+			name:     "default keeps one request per host",
+			urls:     []string{"https://a.example/1", "https://a.example/2", "https://a.example/3"},
+			wantPeak: 1,
+		},
+		{
+			name:     "host override raises the per-host cap",
+			urls:     []string{"https://a.example/1", "https://a.example/2", "https://a.example/3", "https://a.example/4"},
+			perHost:  map[string]int{"a.example": 2},
+			wantPeak: 2,
+		},
+		{
+			name:     "general per-host setting applies without override",
+			urls:     []string{"https://a.example/1", "https://a.example/2", "https://a.example/3"},
+			general:  &service.GeneralSettings{MaxConcurrentRefresh: 8, MaxConcurrentPerHost: 3},
+			wantPeak: 3,
+		},
+		{
+			name:     "global cap bounds different hosts",
+			urls:     []string{"https://a.example/1", "https://b.example/1", "https://c.example/1"},
+			general:  &service.GeneralSettings{MaxConcurrentRefresh: 1, MaxConcurrentPerHost: 1},
+			wantPeak: 1,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockFeeds := mock.NewMockFeedRepository(ctrl)
+			mockFeeds.EXPECT().UpdateLastFetchedAt(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+			mockFeeds.EXPECT().UpdateNotModifiedStreak(gomock.Any(), gomock.Any(), 1).Return(nil).AnyTimes()
+			feeds := make([]model.Feed, len(tc.urls))
+			ids := make([]int64, len(tc.urls))
+			for i, u := range tc.urls {
+				feeds[i] = model.Feed{ID: int64(i + 1), URL: u, Title: u}
+				ids[i] = feeds[i].ID
+			}
+			mockFeeds.EXPECT().GetByIDs(gomock.Any(), ids).Return(feeds, nil)
+			mockFeeds.EXPECT().UpdateErrorMessage(gomock.Any(), gomock.Any(), nil).Return(nil).Times(len(feeds))
+
+			var peak int32
+			svc := service.NewRefreshService(
+				mockFeeds,
+				mock.NewMockEntryRepository(ctrl),
+				nil,
+				&settingsServiceStub{general: tc.general},
+				nil,
+				nil,
+				network.NewClientFactoryForTest(peakConcurrencyClient(&peak)),
+				nil,
+				&rateLimitStub{maxConcurrent: tc.perHost},
+				nil,
+				nil,
+				nil,
+			)
+
+			require.NoError(t, svc.RefreshFeeds(context.Background(), ids))
+			require.Equal(t, tc.wantPeak, atomic.LoadInt32(&peak))
+		})
+	}
+}
+
+// blockingClient answers 304 once release is closed, signalling started as
+// each request arrives and counting them in requests.
+func blockingClient(started chan<- struct{}, release <-chan struct{}, requests *int32) *http.Client {
+	return &http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			atomic.AddInt32(requests, 1)
+			started <- struct{}{}
+			<-release
+			return &http.Response{StatusCode: http.StatusNotModified, Body: http.NoBody, Header: make(http.Header), Request: req}, nil
+		}),
+	}
+}
+
+func TestRefreshService_RefreshFeed_WaitsForFeedInProgress(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	feed := model.Feed{ID: 1, URL: "https://example.com/rss", Title: "Feed"}
+	mockFeeds := mock.NewMockFeedRepository(ctrl)
+	mockFeeds.EXPECT().GetByIDs(gomock.Any(), []int64{1}).Return([]model.Feed{feed}, nil)
+	mockFeeds.EXPECT().GetByID(gomock.Any(), int64(1)).Return(feed, nil)
+	mockFeeds.EXPECT().UpdateErrorMessage(gomock.Any(), int64(1), nil).Return(nil)
+	mockFeeds.EXPECT().UpdateLastFetchedAt(gomock.Any(), int64(1), gomock.Any()).Return(nil)
+	mockFeeds.EXPECT().UpdateNotModifiedStreak(gomock.Any(), int64(1), 1).Return(nil)
+
+	started, release := make(chan struct{}, 2), make(chan struct{})
+	var requests int32
+	svc := service.NewRefreshService(mockFeeds, mock.NewMockEntryRepository(ctrl), nil, nil, nil, nil, network.NewClientFactoryForTest(blockingClient(started, release, &requests)), nil, nil, nil, nil, nil)
+
+	batchDone := make(chan error, 1)
+	go func() { batchDone <- svc.RefreshFeeds(context.Background(), []int64{1}) }()
+	<-started
+
+	singleDone := make(chan error, 1)
+	go func() { singleDone <- svc.RefreshFeed(context.Background(), 1) }()
+	select {
+	case err := <-singleDone:
+		t.Fatalf("RefreshFeed returned %v before the refresh in progress finished", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	require.NoError(t, <-batchDone)
+	require.ErrorIs(t, <-singleDone, service.ErrAlreadyRefreshing)
+	require.Equal(t, int32(1), atomic.LoadInt32(&requests))
+}
+
+func TestRefreshService_RefreshFeeds_SkipsFeedInProgress(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	feed := model.Feed{ID: 1, URL: "https://example.com/rss", Title: "Feed"}
+	mockFeeds := mock.NewMockFeedRepository(ctrl)
+	mockFeeds.EXPECT().GetByID(gomock.Any(), int64(1)).Return(feed, nil)
+	mockFeeds.EXPECT().GetByIDs(gomock.Any(), []int64{1}).Return([]model.Feed{feed}, nil)
+	mockFeeds.EXPECT().UpdateErrorMessage(gomock.Any(), int64(1), nil).Return(nil)
+	mockFeeds.EXPECT().UpdateLastFetchedAt(gomock.Any(), int64(1), gomock.Any()).Return(nil)
+	mockFeeds.EXPECT().UpdateNotModifiedStreak(gomock.Any(), int64(1), 1).Return(nil)
+
+	started, release := make(chan struct{}, 2), make(chan struct{})
+	var requests int32
+	svc := service.NewRefreshService(mockFeeds, mock.NewMockEntryRepository(ctrl), nil, nil, nil, nil, network.NewClientFactoryForTest(blockingClient(started, release, &requests)), nil, nil, nil, nil, nil)
+
+	singleDone := make(chan error, 1)
+	go func() { singleDone <- svc.RefreshFeed(context.Background(), 1) }()
+	<-started
+
+	// The batch returns without waiting for the feed being refreshed
+	require.NoError(t, svc.RefreshFeeds(context.Background(), []int64{1}))
+
+	close(release)
+	require.NoError(t, <-singleDone)
+	require.Equal(t, int32(1), atomic.LoadInt32(&requests))
+}
+
+func TestRefreshService_RefreshFeed_WaitsForHostInterval(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFeeds := mock.NewMockFeedRepository(ctrl)
+	mockFeeds.EXPECT().UpdateLastFetchedAt(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockFeeds.EXPECT().UpdateNotModifiedStreak(gomock.Any(), gomock.Any(), 1).Return(nil).AnyTimes()
+	for _, id := range []int64{1, 2} {
+		mockFeeds.EXPECT().GetByID(gomock.Any(), id).Return(model.Feed{ID: id, URL: fmt.Sprintf("https://example.com/%d", id), Title: "Feed"}, nil)
+		mockFeeds.EXPECT().UpdateErrorMessage(gomock.Any(), id, nil).Return(nil)
+	}
+
+	client := &http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			return &http.Response{StatusCode: http.StatusNotModified, Body: http.NoBody, Header: make(http.Header), Request: req}, nil
+		}),
+	}
+	svc := service.NewRefreshService(mockFeeds, mock.NewMockEntryRepository(ctrl), nil, nil, nil, nil, network.NewClientFactoryForTest(client), nil, &rateLimitStub{interval: 200 * time.Millisecond}, nil, nil, nil)
+
+	require.NoError(t, svc.RefreshFeed(context.Background(), 1))
+	start := time.Now()
+	require.NoError(t, svc.RefreshFeed(context.Background(), 2))
+	require.GreaterOrEqual(t, time.Since(start), 150*time.Millisecond)
+}
+
+func TestRefreshService_RefreshFeedWithUA_HTTPError(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
@@ -424,11 +1516,142 @@ func TestRefreshService_RefreshFeedWithFreshClient_HTTPError(t *testing.T) {
 		mockEntries,
 		nil,
 		nil,
+		nil,
+		nil,
 		network.NewClientFactoryForTest(client),
 		nil,
 		nil,
+		nil,
+		nil,
+		nil,
+	)
+
+	err := service.RefreshFeedWithUAForTest(svc, context.Background(), feed, "UA-Test")
+	require.NoError(t, err)
+}
+
+func TestRefreshService_RefreshFeedWithUA_AnubisCooldown(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFeeds := mock.NewMockFeedRepository(ctrl)
+	mockEntries := mock.NewMockEntryRepository(ctrl)
+
+	feed := model.Feed{ID: 5, URL: "https://example.com/rss", Title: "Feed"}
+	mockFeeds.EXPECT().UpdateErrorMessage(gomock.Any(), int64(5), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ int64, msg *string) error {
+			require.NotNil(t, msg)
+			require.True(t, strings.HasPrefix(*msg, "host rejecting automated access (cooldown until "), *msg)
+			return nil
+		},
 	)
 
-	err := service.RefreshFeedWithFreshClientForTest(svc, context.Background(), feed, "UA-Test", "", 0)
+	client := &http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(`<script id="anubis_challenge" type="application/json">null</script>`)),
+				Header:     make(http.Header),
+				Request:    req,
+			}, nil
+		}),
+	}
+
+	settingsRepo := newSettingsRepoStub()
+	settingsRepo.data["anubis.breaker.example.com"] = fmt.Sprintf(`{"failures":3,"rejectedUntil":%q}`, time.Now().Add(time.Hour).UTC().Format(time.RFC3339))
+	clientFactory := network.NewClientFactoryForTest(client)
+	solver := anubis.NewSolver(clientFactory, anubis.NewStore(settingsRepo))
+
+	svc := service.NewRefreshService(mockFeeds, mockEntries, nil, nil, nil, nil, clientFactory, solver, nil, nil, nil, nil)
+
+	err := service.RefreshFeedWithUAForTest(svc, context.Background(), feed, "UA-Test")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "host rejecting automated access")
+}
+
+func TestRefreshService_IngestStatic(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFeeds := mock.NewMockFeedRepository(ctrl)
+	mockEntries := mock.NewMockEntryRepository(ctrl)
+	bus := events.NewBus(events.SubscriberBuffer)
+	svc := service.NewRefreshService(mockFeeds, mockEntries, nil, nil, nil, nil, network.NewClientFactoryForTest(&http.Client{}), nil, nil, nil, nil, bus)
+
+	parsed, err := service.ParseStaticFeed([]byte(`<?xml version="1.0"?><rss version="2.0"><channel><title>Pasted</title>
+<item><title>One</title><guid>static-1</guid><link>https://example.com/1</link></item></channel></rss>`))
+	require.NoError(t, err)
+
+	feed := model.Feed{ID: 30, URL: service.StaticFeedURLPrefix + "abc", Title: "Pasted"}
+	mockFeeds.EXPECT().GetByID(gomock.Any(), int64(30)).Return(feed, nil).Times(2)
+	seen := make(map[string]bool)
+	var results []bool
+	mockFeeds.EXPECT().ListMutedAuthors(gomock.Any(), int64(30)).Return(nil, nil).AnyTimes()
+	mockEntries.EXPECT().SaveBatch(gomock.Any(), int64(30), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ int64, entries []model.Entry, _ int) (int, int, error) {
+			return saveSeen(seen, &results, entries)
+		},
+	).Times(2)
+
+	published, unsubscribe := bus.Subscribe()
+	defer unsubscribe()
+
+	newCount, updatedCount, err := svc.IngestStatic(context.Background(), 30, parsed)
+	require.NoError(t, err)
+	require.Equal(t, 1, newCount)
+	require.Equal(t, 0, updatedCount)
+	require.Equal(t, events.Event{Type: events.EntriesNew, Data: events.EntriesNewData{FeedID: 30, Count: 1}}, <-published)
+
+	// Pasting the same content again stores nothing new
+	newCount, _, err = svc.IngestStatic(context.Background(), 30, parsed)
+	require.NoError(t, err)
+	require.Equal(t, 0, newCount)
+
+	mockFeeds.EXPECT().GetByID(gomock.Any(), int64(31)).Return(model.Feed{ID: 31, URL: "https://example.com/rss"}, nil)
+	_, _, err = svc.IngestStatic(context.Background(), 31, parsed)
+	require.ErrorIs(t, err, service.ErrInvalid)
+
+	mockFeeds.EXPECT().GetByID(gomock.Any(), int64(32)).Return(model.Feed{}, sql.ErrNoRows)
+	_, _, err = svc.IngestStatic(context.Background(), 32, parsed)
+	require.ErrorIs(t, err, service.ErrNotFound)
+}
+
+func TestRefreshService_RefreshFeeds_SkipsStatic(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFeeds := mock.NewMockFeedRepository(ctrl)
+	mockFeeds.EXPECT().GetByIDs(gomock.Any(), []int64{1}).Return([]model.Feed{
+		{ID: 1, URL: service.StaticFeedURLPrefix + "abc"},
+	}, nil)
+
+	client := &http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			t.Fatalf("static feed fetched: %s", req.URL)
+			return nil, nil
+		}),
+	}
+	svc := service.NewRefreshService(mockFeeds, mock.NewMockEntryRepository(ctrl), nil, nil, nil, nil, network.NewClientFactoryForTest(client), nil, nil, nil, nil, nil)
+
+	require.NoError(t, svc.RefreshFeeds(context.Background(), []int64{1}))
+
+	mockFeeds.EXPECT().GetByID(gomock.Any(), int64(1)).Return(model.Feed{ID: 1, URL: service.StaticFeedURLPrefix + "abc"}, nil)
+	require.ErrorIs(t, svc.RefreshFeed(context.Background(), 1), service.ErrInvalid)
+}
+
+func TestParseStaticFeed(t *testing.T) {
+	_, err := service.ParseStaticFeed(make([]byte, service.MaxStaticFeedSize+1))
+	require.ErrorIs(t, err, service.ErrStaticFeedTooLarge)
+
+	for _, content := range []string{"", "   ", "<html><body>not a feed</body></html>", "plain text"} {
+		_, err = service.ParseStaticFeed([]byte(content))
+		require.ErrorIs(t, err, service.ErrNotAFeed, content)
+		require.ErrorIs(t, err, service.ErrInvalid, content)
+	}
+
+	parsed, err := service.ParseStaticFeed([]byte(`<feed xmlns="http://www.w3.org/2005/Atom"><title>Atom</title>
+<entry><id>a1</id><title>A</title></entry></feed>`))
 	require.NoError(t, err)
+	require.Equal(t, "Atom", parsed.Title)
+	require.Len(t, parsed.Items, 1)
 }
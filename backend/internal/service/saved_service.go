@@ -0,0 +1,191 @@
+//go:generate mockgen -source=$GOFILE -destination=mock/$GOFILE -package=mock
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+
+	"gist/backend/internal/events"
+	"gist/backend/internal/model"
+	"gist/backend/internal/repository"
+	"gist/backend/pkg/logger"
+	"gist/backend/pkg/network"
+)
+
+const (
+	// SavedFeedURL is the synthetic URL of the feed holding pages saved by URL.
+	SavedFeedURL = StaticFeedURLPrefix + "saved"
+	// SavedFeedTitle is the title the saved feed is created with.
+	SavedFeedTitle = "Saved"
+)
+
+// SavedService keeps a read-it-later inbox: pages saved by URL become entries
+// of a built-in static feed, so search, AI and export treat them like any
+// other entry.
+type SavedService interface {
+	// Save fetches the page and stores it as an entry of the saved feed, which
+	// is created on first use. Saving a URL again returns the entry stored the
+	// first time with created false, without fetching the page.
+	Save(ctx context.Context, pageURL string) (entry model.Entry, created bool, err error)
+	// Delete removes an entry of the saved feed. Entries of other feeds are
+	// not found.
+	Delete(ctx context.Context, entryID int64) error
+}
+
+type savedService struct {
+	feeds       repository.FeedRepository
+	entries     repository.EntryRepository
+	readability ReadabilityService
+	bus         *events.Bus
+}
+
+func NewSavedService(feeds repository.FeedRepository, entries repository.EntryRepository, readability ReadabilityService, bus *events.Bus) SavedService {
+	return &savedService{feeds: feeds, entries: entries, readability: readability, bus: bus}
+}
+
+func (s *savedService) Save(ctx context.Context, pageURL string) (model.Entry, bool, error) {
+	pageURL = strings.TrimSpace(pageURL)
+	if !visitableURL(pageURL) {
+		return model.Entry{}, false, ErrInvalid
+	}
+
+	feed, err := s.savedFeed(ctx)
+	if err != nil {
+		return model.Entry{}, false, err
+	}
+	// Saved pages are told apart by URL whatever the feed is set to
+	feed.DedupeKey = model.DedupeKeyURL
+	hash := computeEntryHash(&gofeed.Item{Link: pageURL}, "", "", false, model.DedupeKeyURL)
+	existing, err := s.storedEntry(ctx, feed.ID, hash)
+	if err != nil {
+		return model.Entry{}, false, err
+	}
+	if existing != nil {
+		logger.Debug("page already saved", "module", "service", "action", "create", "resource", "entry", "result", "skipped", "entry_id", existing.ID)
+		return *existing, false, nil
+	}
+
+	page, err := s.readability.FetchPage(ctx, pageURL)
+	if err != nil {
+		if !errors.Is(err, ErrAnubisRejected) && !errors.Is(err, ErrUnsupportedContentType) {
+			err = fmt.Errorf("%w: %v", ErrFeedFetch, err)
+		}
+		return model.Entry{}, false, err
+	}
+
+	now := time.Now().UTC()
+	item := &gofeed.Item{
+		Title:           page.Title,
+		Link:            pageURL,
+		Content:         page.Content,
+		PublishedParsed: &now,
+	}
+	if page.Author != "" {
+		item.Author = &gofeed.Person{Name: page.Author}
+	}
+	entry := itemToEntry(feed, item, false, time.UTC)
+	if page.ThumbnailURL != "" {
+		thumbnail := page.ThumbnailURL
+		entry.ThumbnailURL = &thumbnail
+	}
+	if _, _, err := s.entries.SaveBatch(ctx, feed.ID, []model.Entry{entry}, 0); err != nil {
+		logger.Error("page save failed", "module", "service", "action", "create", "resource", "entry", "result", "failed", "host", network.ExtractHost(pageURL), "error", err)
+		return model.Entry{}, false, err
+	}
+	s.bus.Publish(events.Event{Type: events.EntriesNew, Data: events.EntriesNewData{FeedID: feed.ID, Count: 1}})
+
+	stored, err := s.storedEntry(ctx, feed.ID, entry.Hash)
+	if err != nil {
+		return model.Entry{}, false, err
+	}
+	if stored == nil {
+		return model.Entry{}, false, fmt.Errorf("saved entry not found")
+	}
+	logger.Info("page saved", "module", "service", "action", "create", "resource", "entry", "result", "ok", "entry_id", stored.ID, "host", network.ExtractHost(pageURL))
+	return *stored, true, nil
+}
+
+func (s *savedService) Delete(ctx context.Context, entryID int64) error {
+	entry, err := s.entries.GetByID(ctx, entryID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrNotFound
+		}
+		return err
+	}
+	feed, err := s.feeds.FindByURL(ctx, SavedFeedURL)
+	if err != nil {
+		return err
+	}
+	if feed == nil || feed.ID != entry.FeedID {
+		return ErrNotFound
+	}
+
+	if err := s.entries.Delete(ctx, entryID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrNotFound
+		}
+		logger.Error("saved entry delete failed", "module", "service", "action", "delete", "resource", "entry", "result", "failed", "entry_id", entryID, "error", err)
+		return err
+	}
+	logger.Info("saved entry deleted", "module", "service", "action", "delete", "resource", "entry", "result", "ok", "entry_id", entryID)
+	return nil
+}
+
+// savedFeed returns the saved feed, creating it on first use and restoring it
+// when it was deleted.
+func (s *savedService) savedFeed(ctx context.Context) (model.Feed, error) {
+	feed, err := s.feeds.FindByURL(ctx, SavedFeedURL)
+	if err != nil {
+		return model.Feed{}, fmt.Errorf("find saved feed: %w", err)
+	}
+	if feed != nil {
+		if feed.DeletedAt != nil {
+			if err := s.feeds.Restore(ctx, feed.ID, time.Time{}); err != nil {
+				logger.Error("saved feed restore failed", "module", "service", "action", "restore", "resource", "feed", "result", "failed", "feed_id", feed.ID, "error", err)
+				return model.Feed{}, err
+			}
+			feed.DeletedAt = nil
+			s.bus.Publish(events.Event{Type: events.FeedCreated, Data: events.FeedData{FeedID: feed.ID}})
+		}
+		return *feed, nil
+	}
+
+	created, err := s.feeds.Create(ctx, model.Feed{
+		Title:     SavedFeedTitle,
+		URL:       SavedFeedURL,
+		Type:      "article",
+		DedupeKey: model.DedupeKeyURL,
+	})
+	if errors.Is(err, repository.ErrConflict) {
+		// Created by a concurrent save since the lookup above
+		if existing, findErr := s.feeds.FindByURL(ctx, SavedFeedURL); findErr == nil && existing != nil && existing.DeletedAt == nil {
+			return *existing, nil
+		}
+	}
+	if err != nil {
+		logger.Error("saved feed create failed", "module", "service", "action", "create", "resource", "feed", "result", "failed", "error", err)
+		return model.Feed{}, err
+	}
+	logger.Info("saved feed created", "module", "service", "action", "create", "resource", "feed", "result", "ok", "feed_id", created.ID)
+	s.bus.Publish(events.Event{Type: events.FeedCreated, Data: events.FeedData{FeedID: created.ID}})
+	return created, nil
+}
+
+// storedEntry returns the entry of feed with hash, nil when there is none.
+func (s *savedService) storedEntry(ctx context.Context, feedID int64, hash string) (*model.Entry, error) {
+	stored, err := s.entries.ListByHashes(ctx, feedID, []string{hash})
+	if err != nil {
+		return nil, fmt.Errorf("list saved entries: %w", err)
+	}
+	if len(stored) == 0 {
+		return nil, nil
+	}
+	return &stored[0], nil
+}
@@ -0,0 +1,157 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"gist/backend/internal/model"
+	"gist/backend/internal/repository"
+	repositorymock "gist/backend/internal/repository/mock"
+	"gist/backend/internal/repository/testutil"
+	"gist/backend/internal/service"
+	servicemock "gist/backend/internal/service/mock"
+)
+
+func TestSavedService_Save(t *testing.T) {
+	database := testutil.NewTestDB(t)
+	feeds := repository.NewFeedRepository(database, nil, nil)
+	entries := repository.NewEntryRepository(database, nil, nil)
+	readability := servicemock.NewMockReadabilityService(gomock.NewController(t))
+	svc := service.NewSavedService(feeds, entries, readability, nil)
+	ctx := context.Background()
+
+	readability.EXPECT().FetchPage(gomock.Any(), "https://example.com/post?utm_source=x").Return(service.ReadablePage{
+		Title:        "A post",
+		Content:      "<p>Worth reading later.</p>",
+		ThumbnailURL: "https://example.com/cover.jpg",
+		Author:       "Ann",
+	}, nil)
+
+	entry, created, err := svc.Save(ctx, " https://example.com/post?utm_source=x ")
+	require.NoError(t, err)
+	require.True(t, created)
+	require.NotZero(t, entry.ID)
+	require.Equal(t, "A post", *entry.Title)
+	require.Equal(t, "https://example.com/post?utm_source=x", *entry.URL)
+	require.Equal(t, "https://example.com/cover.jpg", *entry.ThumbnailURL)
+	require.Equal(t, "Ann", *entry.Author)
+	require.NotNil(t, entry.PublishedAt)
+
+	feed, err := feeds.GetByID(ctx, entry.FeedID)
+	require.NoError(t, err)
+	require.Equal(t, service.SavedFeedURL, feed.URL)
+	require.Equal(t, service.SavedFeedTitle, feed.Title)
+	require.Equal(t, "article", feed.Type)
+	require.True(t, service.IsStaticFeed(feed))
+
+	// The same page again, even with other tracking parameters, is not fetched
+	again, created, err := svc.Save(ctx, "https://example.com/post?utm_source=y")
+	require.NoError(t, err)
+	require.False(t, created)
+	require.Equal(t, entry.ID, again.ID)
+
+	// A second page lands in the same feed
+	readability.EXPECT().FetchPage(gomock.Any(), "https://example.com/other").Return(service.ReadablePage{Title: "Other"}, nil)
+	other, created, err := svc.Save(ctx, "https://example.com/other")
+	require.NoError(t, err)
+	require.True(t, created)
+	require.Equal(t, entry.FeedID, other.FeedID)
+	require.NotEqual(t, entry.ID, other.ID)
+}
+
+func TestSavedService_Save_Errors(t *testing.T) {
+	database := testutil.NewTestDB(t)
+	readability := servicemock.NewMockReadabilityService(gomock.NewController(t))
+	svc := service.NewSavedService(repository.NewFeedRepository(database, nil, nil), repository.NewEntryRepository(database, nil, nil), readability, nil)
+	ctx := context.Background()
+
+	_, _, err := svc.Save(ctx, "javascript:alert(1)")
+	require.ErrorIs(t, err, service.ErrInvalid)
+	_, _, err = svc.Save(ctx, "example.com/post")
+	require.ErrorIs(t, err, service.ErrInvalid)
+
+	readability.EXPECT().FetchPage(gomock.Any(), "https://example.com/down").Return(service.ReadablePage{}, errors.New("connection refused"))
+	_, _, err = svc.Save(ctx, "https://example.com/down")
+	require.ErrorIs(t, err, service.ErrFeedFetch)
+
+	readability.EXPECT().FetchPage(gomock.Any(), "https://example.com/image.png").Return(service.ReadablePage{}, service.ErrUnsupportedContentType)
+	_, _, err = svc.Save(ctx, "https://example.com/image.png")
+	require.ErrorIs(t, err, service.ErrUnsupportedContentType)
+	require.NotErrorIs(t, err, service.ErrFeedFetch)
+}
+
+func TestSavedService_Save_RestoresDeletedFeed(t *testing.T) {
+	database := testutil.NewTestDB(t)
+	feeds := repository.NewFeedRepository(database, nil, nil)
+	readability := servicemock.NewMockReadabilityService(gomock.NewController(t))
+	svc := service.NewSavedService(feeds, repository.NewEntryRepository(database, nil, nil), readability, nil)
+	ctx := context.Background()
+
+	feedID := testutil.SeedFeed(t, database, model.Feed{Title: service.SavedFeedTitle, URL: service.SavedFeedURL, DedupeKey: model.DedupeKeyURL})
+	require.NoError(t, feeds.Delete(ctx, feedID))
+
+	readability.EXPECT().FetchPage(gomock.Any(), "https://example.com/post").Return(service.ReadablePage{Title: "Post"}, nil)
+	entry, _, err := svc.Save(ctx, "https://example.com/post")
+	require.NoError(t, err)
+	require.Equal(t, feedID, entry.FeedID)
+
+	feed, err := feeds.GetByID(ctx, feedID)
+	require.NoError(t, err)
+	require.Nil(t, feed.DeletedAt)
+}
+
+func TestSavedService_Save_FeedCreatedConcurrently(t *testing.T) {
+	database := testutil.NewTestDB(t)
+	ctrl := gomock.NewController(t)
+	feeds := repositorymock.NewMockFeedRepository(ctrl)
+	readability := servicemock.NewMockReadabilityService(ctrl)
+	svc := service.NewSavedService(feeds, repository.NewEntryRepository(database, nil, nil), readability, nil)
+	ctx := context.Background()
+
+	feedID := testutil.SeedFeed(t, database, model.Feed{Title: service.SavedFeedTitle, URL: service.SavedFeedURL, DedupeKey: model.DedupeKeyURL})
+	existing := &model.Feed{ID: feedID, Title: service.SavedFeedTitle, URL: service.SavedFeedURL, DedupeKey: model.DedupeKeyURL}
+
+	// Another save creates the feed between the lookup and the create
+	gomock.InOrder(
+		feeds.EXPECT().FindByURL(gomock.Any(), service.SavedFeedURL).Return(nil, nil),
+		feeds.EXPECT().Create(gomock.Any(), gomock.Any()).Return(model.Feed{}, repository.ErrConflict),
+		feeds.EXPECT().FindByURL(gomock.Any(), service.SavedFeedURL).Return(existing, nil),
+	)
+	readability.EXPECT().FetchPage(gomock.Any(), "https://example.com/post").Return(service.ReadablePage{Title: "Post"}, nil)
+
+	entry, created, err := svc.Save(ctx, "https://example.com/post")
+	require.NoError(t, err)
+	require.True(t, created)
+	require.Equal(t, feedID, entry.FeedID)
+}
+
+func TestSavedService_Delete(t *testing.T) {
+	database := testutil.NewTestDB(t)
+	entries := repository.NewEntryRepository(database, nil, nil)
+	readability := servicemock.NewMockReadabilityService(gomock.NewController(t))
+	svc := service.NewSavedService(repository.NewFeedRepository(database, nil, nil), entries, readability, nil)
+	ctx := context.Background()
+
+	otherFeedID := testutil.SeedFeed(t, database, model.Feed{Title: "Blog", URL: "https://example.com/rss"})
+	otherEntryID := testutil.SeedEntry(t, database, model.Entry{FeedID: otherFeedID, Hash: "h"})
+
+	// Without a saved feed nothing is a saved entry
+	require.ErrorIs(t, svc.Delete(ctx, otherEntryID), service.ErrNotFound)
+
+	readability.EXPECT().FetchPage(gomock.Any(), "https://example.com/post").Return(service.ReadablePage{Title: "Post"}, nil)
+	entry, _, err := svc.Save(ctx, "https://example.com/post")
+	require.NoError(t, err)
+
+	require.ErrorIs(t, svc.Delete(ctx, otherEntryID), service.ErrNotFound)
+	require.NoError(t, svc.Delete(ctx, entry.ID))
+	_, err = entries.GetByID(ctx, entry.ID)
+	require.Error(t, err)
+	require.ErrorIs(t, svc.Delete(ctx, entry.ID), service.ErrNotFound)
+
+	_, err = entries.GetByID(ctx, otherEntryID)
+	require.NoError(t, err)
+}
@@ -3,6 +3,7 @@ package service_test
 import (
 	"context"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -72,6 +73,31 @@ func (s *settingsRepoStub) SetMany(ctx context.Context, values map[string]string
 	return nil
 }
 
+func (s *settingsRepoStub) SetManyVersioned(ctx context.Context, versionKey, expected string, values map[string]string) (string, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current := s.data[versionKey]
+	if current == "" {
+		current = "0"
+	}
+	if expected != "" && expected != current {
+		return current, false, nil
+	}
+	for key := range values {
+		if err := s.setErr[key]; err != nil {
+			return "", false, err
+		}
+	}
+	for key, value := range values {
+		s.data[key] = value
+	}
+	n, _ := strconv.Atoi(current)
+	next := strconv.Itoa(n + 1)
+	s.data[versionKey] = next
+	return next, true, nil
+}
+
 func (s *settingsRepoStub) GetByPrefix(ctx context.Context, prefix string) ([]model.Setting, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
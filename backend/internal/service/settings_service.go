@@ -4,8 +4,11 @@ package service
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/url"
+	"strings"
+	"sync"
 
 	"gist/backend/internal/repository"
 	"gist/backend/internal/service/ai"
@@ -23,15 +26,98 @@ type AISettings struct {
 	AutoTranslate   bool           `json:"autoTranslate"`
 	AutoSummary     bool           `json:"autoSummary"`
 	RateLimit       int            `json:"rateLimit"`
+	// TranslateOnRefresh has new entries' list titles translated after each
+	// refresh, unless their feed opts out; see model.FeedAutoTranslateInherit.
+	TranslateOnRefresh bool `json:"translateOnRefresh"`
+	// ModelPrices maps model names to prices for usage cost estimates.
+	// A nil map on update keeps the stored table.
+	ModelPrices map[string]AIModelPrice `json:"modelPrices"`
+	// UsageRetentionMonths is how long usage rows are kept; 0 on update keeps the stored value.
+	UsageRetentionMonths int `json:"usageRetentionMonths"`
+	// TranslateBlockChars is how much article HTML one translation call gets;
+	// smaller blocks are merged up to it. 0 on update keeps the stored value.
+	TranslateBlockChars int `json:"translateBlockChars"`
+	// Version is the save counter read with the settings; see SettingsService.
+	Version string `json:"version"`
 }
 
+// AIModelPrice is what a model costs, in USD per million tokens.
+type AIModelPrice struct {
+	Input  float64 `json:"input"`
+	Output float64 `json:"output"`
+}
+
+// DefaultAIUsageRetentionMonths is used when no usage retention is stored.
+const DefaultAIUsageRetentionMonths = 12
+
 // GeneralSettings holds general application settings.
 type GeneralSettings struct {
 	FallbackUserAgent string `json:"fallbackUserAgent"`
 	AutoReadability   bool   `json:"autoReadability"`
 	MarkReadOnScroll  bool   `json:"markReadOnScroll"`
+	// EntryRevisions keeps snapshots of entry content that changes on refresh (enabled by default).
+	EntryRevisions bool `json:"entryRevisions"`
+	// ImageCache stores images of picture feeds and starred entries locally (disabled by default).
+	ImageCache bool `json:"imageCache"`
+	// ImageCacheEntryLimitMB and ImageCacheTotalLimitMB bound the disk used per entry and overall.
+	ImageCacheEntryLimitMB int `json:"imageCacheEntryLimitMb"`
+	ImageCacheTotalLimitMB int `json:"imageCacheTotalLimitMb"`
+	// Timezone is the IANA name that calendar days (e.g. the daily digest) are counted in.
+	Timezone string `json:"timezone"`
+	// MaxConcurrentRefresh and MaxConcurrentPerHost bound parallel feed fetches overall
+	// and per host. They are read when a refresh run starts.
+	MaxConcurrentRefresh int `json:"maxConcurrentRefresh"`
+	MaxConcurrentPerHost int `json:"maxConcurrentPerHost"`
+	// AnubisMaxRetries bounds the Anubis challenges solved for one fetch, and
+	// AnubisRetryDelayMs is the wait between a solve and the fetch that follows it.
+	AnubisMaxRetries   int `json:"anubisMaxRetries"`
+	AnubisRetryDelayMs int `json:"anubisRetryDelayMs"`
+	// UnconditionalFetchAfter is how many refreshes in a row a feed may answer
+	// 304 Not Modified, while its posting interval says new entries are due,
+	// before it is fetched once without ETag and Last-Modified.
+	UnconditionalFetchAfter int `json:"unconditionalFetchAfter"`
+	// KeepRawItems stores the feed items entries are made from, for viewing
+	// their source, for RawItemRetention (disabled by default).
+	KeepRawItems bool `json:"keepRawItems"`
+	// QuietHoursStart and QuietHoursEnd bound a daily window, as "HH:MM" in
+	// Timezone, in which scheduled refreshes are skipped and background jobs
+	// pause. The window may cross midnight; empty disables it.
+	QuietHoursStart string `json:"quietHoursStart"`
+	QuietHoursEnd   string `json:"quietHoursEnd"`
+	// Version is the save counter read with the settings; see SettingsService.
+	Version string `json:"version"`
 }
 
+// Image cache budget defaults, used when no limit is stored.
+const (
+	DefaultImageCacheEntryLimitMB = 20
+	DefaultImageCacheTotalLimitMB = 1024
+)
+
+// DefaultTimezone is used when no timezone is stored.
+const DefaultTimezone = "UTC"
+
+// Refresh concurrency defaults and the largest values accepted for them.
+const (
+	DefaultMaxConcurrentRefresh = 8
+	DefaultMaxConcurrentPerHost = 1
+	MaxConcurrentRefreshLimit   = 64
+	MaxConcurrentPerHostLimit   = 16
+)
+
+// Anubis retry defaults and the largest values accepted for them.
+const (
+	DefaultAnubisMaxRetries = 2
+	AnubisMaxRetriesLimit   = 10
+	AnubisRetryDelayLimitMs = 10000
+)
+
+// Not-modified staleness guard default and the largest value accepted for it.
+const (
+	DefaultUnconditionalFetchAfter = 10
+	UnconditionalFetchAfterLimit   = 1000
+)
+
 // NetworkSettings holds network proxy configuration.
 type NetworkSettings struct {
 	Enabled  bool   `json:"enabled"`
@@ -41,40 +127,92 @@ type NetworkSettings struct {
 	Username string `json:"username"`
 	Password string `json:"password"`
 	IPStack  string `json:"ipStack"` // default, ipv4, ipv6
+	// Version is the save counter read with the settings; see SettingsService.
+	Version string `json:"version"`
 }
 
 // AppearanceSettings holds appearance configuration.
 type AppearanceSettings struct {
 	ContentTypes []string `json:"contentTypes"`
+	// Version is the save counter read with the settings; see SettingsService.
+	Version string `json:"version"`
 }
 
+// SecuritySettings holds CORS and auth cookie configuration.
+type SecuritySettings struct {
+	CORSOrigins    []string `json:"corsOrigins"`
+	CookieSameSite string   `json:"cookieSameSite"` // lax, none
+	CookieSecure   bool     `json:"cookieSecure"`   // false: Secure only when served over HTTPS
+	CookieDomain   string   `json:"cookieDomain"`
+}
+
+// ErrInsecureSameSiteNone is returned when SameSite=None is requested without Secure.
+// Browsers drop such cookies, so the configuration would silently break login.
+var ErrInsecureSameSiteNone = errors.New("cookie SameSite=None requires Secure")
+
 // Setting keys
 const (
-	keyAIProvider        = "ai.provider"
-	keyAIAPIKey          = "ai.api_key"
-	keyAIBaseURL         = "ai.base_url"
-	keyAIModel           = "ai.model"
-	keyAIRequestOptions  = "ai.request_options"
-	keyAISummaryLanguage = "ai.summary_language"
-	keyAIAutoTranslate   = "ai.auto_translate"
-	keyAIAutoSummary     = "ai.auto_summary"
-	keyAIRateLimit       = "ai.rate_limit"
-
-	keyFallbackUserAgent = "general.fallback_user_agent"
-	keyAutoReadability   = "general.auto_readability"
-	keyMarkReadOnScroll  = "general.mark_read_on_scroll"
-	keyNetworkEnabled    = "network.proxy_enabled"
-	keyNetworkType       = "network.proxy_type"
-	keyNetworkHost       = "network.proxy_host"
-	keyNetworkPort       = "network.proxy_port"
-	keyNetworkUsername   = "network.proxy_username"
-	keyNetworkPassword   = "network.proxy_password"
-	keyNetworkIPStack    = "network.ip_stack"
+	keyAIProvider            = "ai.provider"
+	keyAIAPIKey              = "ai.api_key"
+	keyAIBaseURL             = "ai.base_url"
+	keyAIModel               = "ai.model"
+	keyAIRequestOptions      = "ai.request_options"
+	keyAISummaryLanguage     = "ai.summary_language"
+	keyAIAutoTranslate       = "ai.auto_translate"
+	keyAIAutoSummary         = "ai.auto_summary"
+	keyAITranslateOnRefresh  = "ai.translate_on_refresh"
+	keyAIRateLimit           = "ai.rate_limit"
+	keyAIModelPrices         = "ai.model_prices"
+	keyAIUsageRetention      = "ai.usage_retention_months"
+	keyAITranslateBlockChars = "ai.translate_block_chars"
+
+	keyFallbackUserAgent  = "general.fallback_user_agent"
+	keyAutoReadability    = "general.auto_readability"
+	keyMarkReadOnScroll   = "general.mark_read_on_scroll"
+	keyEntryRevisions     = "general.entry_revisions"
+	keyImageCache         = "general.image_cache"
+	keyImageCacheEntryMB  = "general.image_cache_entry_limit_mb"
+	keyImageCacheTotalMB  = "general.image_cache_total_limit_mb"
+	keyTimezone           = "general.timezone"
+	keyMaxConcurrent      = "general.max_concurrent_refresh"
+	keyMaxPerHost         = "general.max_concurrent_per_host"
+	keyAnubisMaxRetries   = "general.anubis_max_retries"
+	keyAnubisRetryDelay   = "general.anubis_retry_delay_ms"
+	keyUnconditionalFetch = "general.unconditional_fetch_after"
+	keyKeepRawItems       = "general.keep_raw_items"
+	keyQuietHoursStart    = "general.quiet_hours_start"
+	keyQuietHoursEnd      = "general.quiet_hours_end"
+	keyNetworkEnabled     = "network.proxy_enabled"
+	keyNetworkType        = "network.proxy_type"
+	keyNetworkHost        = "network.proxy_host"
+	keyNetworkPort        = "network.proxy_port"
+	keyNetworkUsername    = "network.proxy_username"
+	keyNetworkPassword    = "network.proxy_password"
+	keyNetworkIPStack     = "network.ip_stack"
 
 	keyAppearanceContentTypes = "appearance.content_types"
+
+	keySecurityCORSOrigins    = "security.cors_origins"
+	keySecurityCookieSameSite = "security.cookie_same_site"
+	keySecurityCookieSecure   = "security.cookie_secure"
+	keySecurityCookieDomain   = "security.cookie_domain"
+
+	keySettingsVersionPrefix = "meta.settings_version."
+)
+
+// Settings groups saved with optimistic concurrency. Each has its own version counter.
+const (
+	settingsGroupAI         = "ai"
+	settingsGroupGeneral    = "general"
+	settingsGroupNetwork    = "network"
+	settingsGroupAppearance = "appearance"
 )
 
 // SettingsService provides settings management.
+//
+// AI, general, network and appearance settings carry a Version read with them.
+// Passing it back on Set rejects the save with ErrConflict when another save
+// happened in between; an empty Version overwrites unconditionally.
 type SettingsService interface {
 	// GetAISettings returns the AI configuration with masked API keys.
 	GetAISettings(ctx context.Context) (*AISettings, error)
@@ -100,20 +238,35 @@ type SettingsService interface {
 	GetProxyURL(ctx context.Context) string
 	// GetIPStack returns the IP stack preference (default, ipv4, ipv6).
 	GetIPStack(ctx context.Context) string
+	// OnNetworkSettingsChange registers fn to run after network settings are saved.
+	OnNetworkSettingsChange(fn func())
 	// GetAppearanceSettings returns appearance settings.
 	GetAppearanceSettings(ctx context.Context) (*AppearanceSettings, error)
 	// SetAppearanceSettings updates appearance settings.
 	SetAppearanceSettings(ctx context.Context, settings *AppearanceSettings) error
+	// GetSecuritySettings returns CORS and cookie settings, falling back to environment defaults.
+	GetSecuritySettings(ctx context.Context) (*SecuritySettings, error)
+	// SetSecuritySettings validates and stores CORS and cookie settings.
+	SetSecuritySettings(ctx context.Context, settings *SecuritySettings) error
 }
 
 type settingsService struct {
-	repo        repository.SettingsRepository
-	rateLimiter *ai.RateLimiter
+	repo             repository.SettingsRepository
+	rateLimiter      *ai.RateLimiter
+	securityDefaults SecuritySettings
+
+	listenersMu      sync.Mutex
+	networkListeners []func()
 }
 
-// NewSettingsService creates a new settings service.
-func NewSettingsService(repo repository.SettingsRepository, rateLimiter *ai.RateLimiter) SettingsService {
-	return &settingsService{repo: repo, rateLimiter: rateLimiter}
+// NewSettingsService creates a settings service whose security settings fall back
+// to the given defaults (typically from environment variables) when not stored.
+func NewSettingsService(repo repository.SettingsRepository, rateLimiter *ai.RateLimiter, defaults SecuritySettings) SettingsService {
+	defaults.CORSOrigins = append([]string(nil), defaults.CORSOrigins...)
+	if defaults.CookieSameSite == "" {
+		defaults.CookieSameSite = "lax"
+	}
+	return &settingsService{repo: repo, rateLimiter: rateLimiter, securityDefaults: defaults}
 }
 
 // GetAISettings returns the AI configuration with masked API keys.
@@ -145,34 +298,29 @@ func (s *settingsService) GetAISettings(ctx context.Context) (*AISettings, error
 	}
 	settings.AutoTranslate = s.getBool(ctx, keyAIAutoTranslate)
 	settings.AutoSummary = s.getBool(ctx, keyAIAutoSummary)
+	settings.TranslateOnRefresh = s.getBool(ctx, keyAITranslateOnRefresh)
 	if val, err := s.getInt(ctx, keyAIRateLimit); err == nil && val > 0 {
 		settings.RateLimit = val
 	} else {
 		settings.RateLimit = ai.DefaultRateLimit
 	}
+	prices, _ := s.getString(ctx, keyAIModelPrices)
+	settings.ModelPrices = parseModelPrices(prices)
+	settings.UsageRetentionMonths = DefaultAIUsageRetentionMonths
+	if val, err := s.getInt(ctx, keyAIUsageRetention); err == nil && val > 0 {
+		settings.UsageRetentionMonths = val
+	}
+	settings.TranslateBlockChars = ai.DefaultTranslateBlockChars
+	if val, err := s.getInt(ctx, keyAITranslateBlockChars); err == nil && val > 0 {
+		settings.TranslateBlockChars = val
+	}
+	settings.Version = s.getVersion(ctx, settingsGroupAI)
 
 	return settings, nil
 }
 
 // SetAISettings updates the AI configuration.
 func (s *settingsService) SetAISettings(ctx context.Context, settings *AISettings) error {
-	if settings.Provider != "" {
-		if err := s.repo.Set(ctx, keyAIProvider, settings.Provider); err != nil {
-			return fmt.Errorf("set provider: %w", err)
-		}
-	}
-	if err := s.setAPIKey(ctx, keyAIAPIKey, settings.APIKey); err != nil {
-		logger.Warn("ai settings update api key failed", "module", "service", "action", "update", "resource", "settings", "result", "failed", "error", err)
-		return fmt.Errorf("set api key: %w", err)
-	}
-	if err := s.repo.Set(ctx, keyAIBaseURL, settings.BaseURL); err != nil {
-		logger.Warn("ai settings update base url failed", "module", "service", "action", "update", "resource", "settings", "result", "failed", "error", err)
-		return fmt.Errorf("set base url: %w", err)
-	}
-	if err := s.repo.Set(ctx, keyAIModel, settings.Model); err != nil {
-		logger.Warn("ai settings update model failed", "module", "service", "action", "update", "resource", "settings", "result", "failed", "model", settings.Model, "error", err)
-		return fmt.Errorf("set model: %w", err)
-	}
 	requestOptions, err := json.Marshal(settings.RequestOptions)
 	if err != nil {
 		logger.Warn("ai settings marshal request options failed", "module", "service", "action", "update", "resource", "settings", "result", "failed", "error", err)
@@ -181,39 +329,60 @@ func (s *settingsService) SetAISettings(ctx context.Context, settings *AISetting
 	if string(requestOptions) == "null" {
 		requestOptions = []byte("{}")
 	}
-	if err := s.repo.Set(ctx, keyAIRequestOptions, string(requestOptions)); err != nil {
-		logger.Warn("ai settings update request options failed", "module", "service", "action", "update", "resource", "settings", "result", "failed", "error", err)
-		return fmt.Errorf("set request options: %w", err)
-	}
-	if err := s.repo.Set(ctx, keyAISummaryLanguage, settings.SummaryLanguage); err != nil {
-		logger.Warn("ai settings update summary language failed", "module", "service", "action", "update", "resource", "settings", "result", "failed", "error", err)
-		return fmt.Errorf("set summary language: %w", err)
-	}
 	autoTranslateVal := "false"
 	if settings.AutoTranslate {
 		autoTranslateVal = "true"
 	}
-	if err := s.repo.Set(ctx, keyAIAutoTranslate, autoTranslateVal); err != nil {
-		logger.Warn("ai settings update auto translate failed", "module", "service", "action", "update", "resource", "settings", "result", "failed", "error", err)
-		return fmt.Errorf("set auto translate: %w", err)
-	}
 	autoSummaryVal := "false"
 	if settings.AutoSummary {
 		autoSummaryVal = "true"
 	}
-	if err := s.repo.Set(ctx, keyAIAutoSummary, autoSummaryVal); err != nil {
-		logger.Warn("ai settings update auto summary failed", "module", "service", "action", "update", "resource", "settings", "result", "failed", "error", err)
-		return fmt.Errorf("set auto summary: %w", err)
+	translateOnRefreshVal := "false"
+	if settings.TranslateOnRefresh {
+		translateOnRefreshVal = "true"
 	}
-	// Set rate limit and update limiter
 	rateLimit := settings.RateLimit
 	if rateLimit <= 0 {
 		rateLimit = ai.DefaultRateLimit
 	}
-	if err := s.repo.Set(ctx, keyAIRateLimit, fmt.Sprintf("%d", rateLimit)); err != nil {
-		logger.Warn("ai settings update rate limit failed", "module", "service", "action", "update", "resource", "settings", "result", "failed", "error", err)
-		return fmt.Errorf("set rate limit: %w", err)
+
+	values := map[string]string{
+		keyAIBaseURL:            settings.BaseURL,
+		keyAIModel:              settings.Model,
+		keyAIRequestOptions:     string(requestOptions),
+		keyAISummaryLanguage:    settings.SummaryLanguage,
+		keyAIAutoTranslate:      autoTranslateVal,
+		keyAIAutoSummary:        autoSummaryVal,
+		keyAITranslateOnRefresh: translateOnRefreshVal,
+		keyAIRateLimit:          fmt.Sprintf("%d", rateLimit),
+	}
+	if settings.Provider != "" {
+		values[keyAIProvider] = settings.Provider
+	}
+	// An empty or masked key keeps the stored one
+	if settings.APIKey != "" && !isMaskedKey(settings.APIKey) {
+		values[keyAIAPIKey] = settings.APIKey
 	}
+	if settings.ModelPrices != nil {
+		prices, err := json.Marshal(settings.ModelPrices)
+		if err != nil {
+			return fmt.Errorf("marshal model prices: %w", err)
+		}
+		values[keyAIModelPrices] = string(prices)
+	}
+	if settings.UsageRetentionMonths > 0 {
+		values[keyAIUsageRetention] = fmt.Sprintf("%d", settings.UsageRetentionMonths)
+	}
+	if settings.TranslateBlockChars > 0 {
+		values[keyAITranslateBlockChars] = fmt.Sprintf("%d", settings.TranslateBlockChars)
+	}
+
+	version, err := s.setVersioned(ctx, settingsGroupAI, settings.Version, values)
+	if err != nil {
+		logger.Warn("ai settings update failed", "module", "service", "action", "update", "resource", "settings", "result", "failed", "provider", settings.Provider, "model", settings.Model, "error", err)
+		return fmt.Errorf("set ai settings: %w", err)
+	}
+	settings.Version = version
 	if s.rateLimiter != nil {
 		s.rateLimiter.SetLimit(rateLimit)
 	}
@@ -322,6 +491,19 @@ func (s *settingsService) getBool(ctx context.Context, key string) bool {
 	return err == nil && val == "true"
 }
 
+// parseModelPrices decodes a stored price table; a missing or malformed value is an empty table.
+func parseModelPrices(val string) map[string]AIModelPrice {
+	prices := make(map[string]AIModelPrice)
+	if val == "" {
+		return prices
+	}
+	if err := json.Unmarshal([]byte(val), &prices); err != nil {
+		logger.Warn("ai model prices parse failed", "module", "service", "action", "fetch", "resource", "settings", "result", "failed", "error", err)
+		return make(map[string]AIModelPrice)
+	}
+	return prices
+}
+
 func (s *settingsService) getRequestOptions(ctx context.Context) (map[string]any, error) {
 	val, err := s.getString(ctx, keyAIRequestOptions)
 	if err != nil || val == "" {
@@ -335,13 +517,26 @@ func (s *settingsService) getRequestOptions(ctx context.Context) (map[string]any
 	return options, nil
 }
 
-// setAPIKey sets an API key.
-// If the value is empty or looks like a masked key, it keeps the existing key.
-func (s *settingsService) setAPIKey(ctx context.Context, key, value string) error {
-	if value == "" || isMaskedKey(value) {
-		return nil
+// getVersion returns the save counter of a settings group ("0" before the first save).
+func (s *settingsService) getVersion(ctx context.Context, group string) string {
+	val, err := s.getString(ctx, keySettingsVersionPrefix+group)
+	if err != nil || val == "" {
+		return "0"
+	}
+	return val
+}
+
+// setVersioned stores values for a settings group and returns its new version.
+// A non-empty expected version that is no longer current fails with ErrConflict.
+func (s *settingsService) setVersioned(ctx context.Context, group, expected string, values map[string]string) (string, error) {
+	version, ok, err := s.repo.SetManyVersioned(ctx, keySettingsVersionPrefix+group, expected, values)
+	if err != nil {
+		return "", err
 	}
-	return s.repo.Set(ctx, key, value)
+	if !ok {
+		return "", fmt.Errorf("%s settings changed since version %s: %w", group, expected, ErrConflict)
+	}
+	return version, nil
 }
 
 // GetGeneralSettings returns the general settings.
@@ -349,15 +544,72 @@ func (s *settingsService) GetGeneralSettings(ctx context.Context) (*GeneralSetti
 	settings := &GeneralSettings{}
 
 	if val, err := s.getString(ctx, keyFallbackUserAgent); err == nil {
-		settings.FallbackUserAgent = val
+		settings.FallbackUserAgent = sanitizeHeaderValue(val)
 	}
 	settings.AutoReadability = s.getBool(ctx, keyAutoReadability)
 	settings.MarkReadOnScroll = s.getBool(ctx, keyMarkReadOnScroll)
+	// Unset means enabled; only an explicit "false" turns versioning off
+	if val, err := s.getString(ctx, keyEntryRevisions); err != nil || val != "false" {
+		settings.EntryRevisions = true
+	}
+	settings.ImageCache = s.getBool(ctx, keyImageCache)
+	settings.ImageCacheEntryLimitMB = DefaultImageCacheEntryLimitMB
+	if val, err := s.getInt(ctx, keyImageCacheEntryMB); err == nil && val > 0 {
+		settings.ImageCacheEntryLimitMB = val
+	}
+	settings.ImageCacheTotalLimitMB = DefaultImageCacheTotalLimitMB
+	if val, err := s.getInt(ctx, keyImageCacheTotalMB); err == nil && val > 0 {
+		settings.ImageCacheTotalLimitMB = val
+	}
+	settings.Timezone = DefaultTimezone
+	if val, err := s.getString(ctx, keyTimezone); err == nil && val != "" {
+		settings.Timezone = val
+	}
+	settings.MaxConcurrentRefresh = DefaultMaxConcurrentRefresh
+	if val, err := s.getInt(ctx, keyMaxConcurrent); err == nil && val > 0 && val <= MaxConcurrentRefreshLimit {
+		settings.MaxConcurrentRefresh = val
+	}
+	settings.MaxConcurrentPerHost = DefaultMaxConcurrentPerHost
+	if val, err := s.getInt(ctx, keyMaxPerHost); err == nil && val > 0 && val <= MaxConcurrentPerHostLimit {
+		settings.MaxConcurrentPerHost = val
+	}
+	settings.AnubisMaxRetries = DefaultAnubisMaxRetries
+	if val, err := s.getInt(ctx, keyAnubisMaxRetries); err == nil && val > 0 && val <= AnubisMaxRetriesLimit {
+		settings.AnubisMaxRetries = val
+	}
+	if val, err := s.getInt(ctx, keyAnubisRetryDelay); err == nil && val > 0 && val <= AnubisRetryDelayLimitMs {
+		settings.AnubisRetryDelayMs = val
+	}
+	settings.UnconditionalFetchAfter = DefaultUnconditionalFetchAfter
+	if val, err := s.getInt(ctx, keyUnconditionalFetch); err == nil && val > 0 && val <= UnconditionalFetchAfterLimit {
+		settings.UnconditionalFetchAfter = val
+	}
+	settings.KeepRawItems = s.getBool(ctx, keyKeepRawItems)
+	if val, err := s.getString(ctx, keyQuietHoursStart); err == nil {
+		settings.QuietHoursStart = val
+	}
+	if val, err := s.getString(ctx, keyQuietHoursEnd); err == nil {
+		settings.QuietHoursEnd = val
+	}
+	settings.Version = s.getVersion(ctx, settingsGroupGeneral)
 	return settings, nil
 }
 
 // SetGeneralSettings updates the general settings.
 func (s *settingsService) SetGeneralSettings(ctx context.Context, settings *GeneralSettings) error {
+	fallbackUA, err := normalizeUserAgent(&settings.FallbackUserAgent)
+	if err != nil {
+		return fmt.Errorf("fallback user agent: %w", err)
+	}
+	settings.FallbackUserAgent = ""
+	if fallbackUA != nil {
+		settings.FallbackUserAgent = *fallbackUA
+	}
+
+	if err := ValidateQuietHours(settings.QuietHoursStart, settings.QuietHoursEnd); err != nil {
+		return err
+	}
+
 	autoReadabilityVal := "false"
 	if settings.AutoReadability {
 		autoReadabilityVal = "true"
@@ -366,16 +618,60 @@ func (s *settingsService) SetGeneralSettings(ctx context.Context, settings *Gene
 	if settings.MarkReadOnScroll {
 		markReadOnScrollVal = "true"
 	}
-
-	if err := s.repo.SetMany(ctx, map[string]string{
+	entryRevisionsVal := "false"
+	if settings.EntryRevisions {
+		entryRevisionsVal = "true"
+	}
+	imageCacheVal := "false"
+	if settings.ImageCache {
+		imageCacheVal = "true"
+	}
+	keepRawItemsVal := "false"
+	if settings.KeepRawItems {
+		keepRawItemsVal = "true"
+	}
+	values := map[string]string{
 		keyFallbackUserAgent: settings.FallbackUserAgent,
 		keyAutoReadability:   autoReadabilityVal,
 		keyMarkReadOnScroll:  markReadOnScrollVal,
-	}); err != nil {
+		keyEntryRevisions:    entryRevisionsVal,
+		keyImageCache:        imageCacheVal,
+		keyKeepRawItems:      keepRawItemsVal,
+		keyQuietHoursStart:   settings.QuietHoursStart,
+		keyQuietHoursEnd:     settings.QuietHoursEnd,
+		// No delay is a valid choice, so the delay is always stored
+		keyAnubisRetryDelay: fmt.Sprintf("%d", max(settings.AnubisRetryDelayMs, 0)),
+	}
+	// Zero limits keep whatever is stored (or the defaults)
+	if settings.ImageCacheEntryLimitMB > 0 {
+		values[keyImageCacheEntryMB] = fmt.Sprintf("%d", settings.ImageCacheEntryLimitMB)
+	}
+	if settings.ImageCacheTotalLimitMB > 0 {
+		values[keyImageCacheTotalMB] = fmt.Sprintf("%d", settings.ImageCacheTotalLimitMB)
+	}
+	if settings.Timezone != "" {
+		values[keyTimezone] = settings.Timezone
+	}
+	if settings.MaxConcurrentRefresh > 0 {
+		values[keyMaxConcurrent] = fmt.Sprintf("%d", settings.MaxConcurrentRefresh)
+	}
+	if settings.MaxConcurrentPerHost > 0 {
+		values[keyMaxPerHost] = fmt.Sprintf("%d", settings.MaxConcurrentPerHost)
+	}
+	if settings.AnubisMaxRetries > 0 {
+		values[keyAnubisMaxRetries] = fmt.Sprintf("%d", settings.AnubisMaxRetries)
+	}
+	if settings.UnconditionalFetchAfter > 0 {
+		values[keyUnconditionalFetch] = fmt.Sprintf("%d", settings.UnconditionalFetchAfter)
+	}
+
+	version, err := s.setVersioned(ctx, settingsGroupGeneral, settings.Version, values)
+	if err != nil {
 		logger.Warn("general settings update failed", "module", "service", "action", "update", "resource", "settings", "result", "failed", "error", err)
 		return fmt.Errorf("set general settings: %w", err)
 	}
-	logger.Info("general settings updated", "module", "service", "action", "update", "resource", "settings", "result", "ok", "auto_readability", settings.AutoReadability, "mark_read_on_scroll", settings.MarkReadOnScroll)
+	settings.Version = version
+	logger.Info("general settings updated", "module", "service", "action", "update", "resource", "settings", "result", "ok", "auto_readability", settings.AutoReadability, "mark_read_on_scroll", settings.MarkReadOnScroll, "entry_revisions", settings.EntryRevisions, "image_cache", settings.ImageCache, "timezone", settings.Timezone, "max_concurrent_refresh", settings.MaxConcurrentRefresh, "max_concurrent_per_host", settings.MaxConcurrentPerHost, "anubis_max_retries", settings.AnubisMaxRetries, "anubis_retry_delay_ms", settings.AnubisRetryDelayMs, "unconditional_fetch_after", settings.UnconditionalFetchAfter, "keep_raw_items", settings.KeepRawItems, "quiet_hours_start", settings.QuietHoursStart, "quiet_hours_end", settings.QuietHoursEnd)
 	return nil
 }
 
@@ -383,10 +679,10 @@ func (s *settingsService) SetGeneralSettings(ctx context.Context, settings *Gene
 // Returns empty string if disabled (user hasn't set one).
 func (s *settingsService) GetFallbackUserAgent(ctx context.Context) string {
 	val, err := s.getString(ctx, keyFallbackUserAgent)
-	if err != nil || val == "" {
+	if err != nil {
 		return ""
 	}
-	return val
+	return sanitizeHeaderValue(val)
 }
 
 // ClearAnubisCookies deletes all Anubis cookies from settings.
@@ -396,7 +692,13 @@ func (s *settingsService) ClearAnubisCookies(ctx context.Context) (int64, error)
 		logger.Warn("anubis cookies clear failed", "module", "service", "action", "clear", "resource", "settings", "result", "failed", "error", err)
 		return 0, err
 	}
-	logger.Info("anubis cookies cleared", "module", "service", "action", "clear", "resource", "settings", "result", "ok", "count", deleted)
+	// Clearing cookies is the manual retry, so it also resets suspended hosts
+	breakers, err := s.repo.DeleteByPrefix(ctx, "anubis.breaker.")
+	if err != nil {
+		logger.Warn("anubis breakers clear failed", "module", "service", "action", "clear", "resource", "settings", "result", "failed", "error", err)
+		return deleted, err
+	}
+	logger.Info("anubis cookies cleared", "module", "service", "action", "clear", "resource", "settings", "result", "ok", "count", deleted, "breakers", breakers)
 	return deleted, nil
 }
 
@@ -426,6 +728,7 @@ func (s *settingsService) GetNetworkSettings(ctx context.Context) (*NetworkSetti
 	if val, err := s.getString(ctx, keyNetworkIPStack); err == nil && val != "" {
 		settings.IPStack = val
 	}
+	settings.Version = s.getVersion(ctx, settingsGroupNetwork)
 
 	return settings, nil
 }
@@ -436,53 +739,54 @@ func (s *settingsService) SetNetworkSettings(ctx context.Context, settings *Netw
 	if settings.Enabled {
 		enabledVal = "true"
 	}
-	if err := s.repo.Set(ctx, keyNetworkEnabled, enabledVal); err != nil {
-		logger.Warn("network settings update enabled failed", "module", "service", "action", "update", "resource", "settings", "result", "failed", "error", err)
-		return fmt.Errorf("set network enabled: %w", err)
-	}
-
-	if settings.Type != "" {
-		if err := s.repo.Set(ctx, keyNetworkType, settings.Type); err != nil {
-			logger.Warn("network settings update type failed", "module", "service", "action", "update", "resource", "settings", "result", "failed", "error", err)
-			return fmt.Errorf("set network type: %w", err)
-		}
-	}
-
-	if err := s.repo.Set(ctx, keyNetworkHost, settings.Host); err != nil {
-		logger.Warn("network settings update host failed", "module", "service", "action", "update", "resource", "settings", "result", "failed", "error", err)
-		return fmt.Errorf("set network host: %w", err)
+	ipStack := settings.IPStack
+	if ipStack == "" {
+		ipStack = "default"
 	}
 
-	if err := s.repo.Set(ctx, keyNetworkPort, fmt.Sprintf("%d", settings.Port)); err != nil {
-		logger.Warn("network settings update port failed", "module", "service", "action", "update", "resource", "settings", "result", "failed", "error", err)
-		return fmt.Errorf("set network port: %w", err)
+	values := map[string]string{
+		keyNetworkEnabled:  enabledVal,
+		keyNetworkHost:     settings.Host,
+		keyNetworkPort:     fmt.Sprintf("%d", settings.Port),
+		keyNetworkUsername: settings.Username,
+		keyNetworkIPStack:  ipStack,
 	}
-
-	if err := s.repo.Set(ctx, keyNetworkUsername, settings.Username); err != nil {
-		logger.Warn("network settings update username failed", "module", "service", "action", "update", "resource", "settings", "result", "failed", "error", err)
-		return fmt.Errorf("set network username: %w", err)
+	if settings.Type != "" {
+		values[keyNetworkType] = settings.Type
 	}
-
 	// Only update password if it's not masked
-	if err := s.setAPIKey(ctx, keyNetworkPassword, settings.Password); err != nil {
-		logger.Warn("network settings update password failed", "module", "service", "action", "update", "resource", "settings", "result", "failed", "error", err)
-		return fmt.Errorf("set network password: %w", err)
+	if settings.Password != "" && !isMaskedKey(settings.Password) {
+		values[keyNetworkPassword] = settings.Password
 	}
 
-	// Set IP stack preference
-	ipStack := settings.IPStack
-	if ipStack == "" {
-		ipStack = "default"
-	}
-	if err := s.repo.Set(ctx, keyNetworkIPStack, ipStack); err != nil {
-		logger.Warn("network settings update ip stack failed", "module", "service", "action", "update", "resource", "settings", "result", "failed", "error", err)
-		return fmt.Errorf("set ip stack: %w", err)
+	version, err := s.setVersioned(ctx, settingsGroupNetwork, settings.Version, values)
+	if err != nil {
+		logger.Warn("network settings update failed", "module", "service", "action", "update", "resource", "settings", "result", "failed", "error", err)
+		return fmt.Errorf("set network settings: %w", err)
 	}
+	settings.Version = version
 
 	logger.Info("network settings updated", "module", "service", "action", "update", "resource", "settings", "result", "ok", "enabled", settings.Enabled, "type", settings.Type, "ip_stack", ipStack)
+	s.notifyNetworkChange()
 	return nil
 }
 
+// OnNetworkSettingsChange registers fn to run after network settings are saved.
+func (s *settingsService) OnNetworkSettingsChange(fn func()) {
+	s.listenersMu.Lock()
+	defer s.listenersMu.Unlock()
+	s.networkListeners = append(s.networkListeners, fn)
+}
+
+func (s *settingsService) notifyNetworkChange() {
+	s.listenersMu.Lock()
+	listeners := append([]func(){}, s.networkListeners...)
+	s.listenersMu.Unlock()
+	for _, fn := range listeners {
+		fn()
+	}
+}
+
 // GetIPStack returns the IP stack preference (default, ipv4, ipv6).
 func (s *settingsService) GetIPStack(ctx context.Context) string {
 	val, err := s.getString(ctx, keyNetworkIPStack)
@@ -554,6 +858,7 @@ func (s *settingsService) GetProxyURL(ctx context.Context) string {
 func (s *settingsService) GetAppearanceSettings(ctx context.Context) (*AppearanceSettings, error) {
 	settings := &AppearanceSettings{
 		ContentTypes: append([]string(nil), defaultAppearanceContentTypes...),
+		Version:      s.getVersion(ctx, settingsGroupAppearance),
 	}
 	raw, err := s.getString(ctx, keyAppearanceContentTypes)
 	if err != nil || raw == "" {
@@ -581,9 +886,13 @@ func (s *settingsService) SetAppearanceSettings(ctx context.Context, settings *A
 	if err != nil {
 		return fmt.Errorf("marshal content types: %w", err)
 	}
-	if err := s.repo.Set(ctx, keyAppearanceContentTypes, string(payload)); err != nil {
+	version, err := s.setVersioned(ctx, settingsGroupAppearance, settings.Version, map[string]string{
+		keyAppearanceContentTypes: string(payload),
+	})
+	if err != nil {
 		return fmt.Errorf("set appearance content types: %w", err)
 	}
+	settings.Version = version
 	return nil
 }
 
@@ -613,3 +922,120 @@ func isValidAppearanceContentType(value string) bool {
 		return false
 	}
 }
+
+// GetSecuritySettings returns CORS and cookie settings, falling back to environment defaults.
+func (s *settingsService) GetSecuritySettings(ctx context.Context) (*SecuritySettings, error) {
+	settings := s.securityDefaults
+	settings.CORSOrigins = append([]string(nil), s.securityDefaults.CORSOrigins...)
+
+	stored, err := s.repo.GetByPrefix(ctx, "security.")
+	if err != nil {
+		return &settings, err
+	}
+	for _, item := range stored {
+		switch item.Key {
+		case keySecurityCORSOrigins:
+			var origins []string
+			if err := json.Unmarshal([]byte(item.Value), &origins); err == nil {
+				settings.CORSOrigins = origins
+			}
+		case keySecurityCookieSameSite:
+			if item.Value != "" {
+				settings.CookieSameSite = item.Value
+			}
+		case keySecurityCookieSecure:
+			settings.CookieSecure = item.Value == "true"
+		case keySecurityCookieDomain:
+			settings.CookieDomain = item.Value
+		}
+	}
+	if settings.CORSOrigins == nil {
+		settings.CORSOrigins = []string{}
+	}
+	return &settings, nil
+}
+
+// SetSecuritySettings validates and stores CORS and cookie settings.
+func (s *settingsService) SetSecuritySettings(ctx context.Context, settings *SecuritySettings) error {
+	if err := NormalizeSecuritySettings(settings); err != nil {
+		return err
+	}
+
+	origins, err := json.Marshal(settings.CORSOrigins)
+	if err != nil {
+		return fmt.Errorf("marshal cors origins: %w", err)
+	}
+	secure := "false"
+	if settings.CookieSecure {
+		secure = "true"
+	}
+
+	if err := s.repo.SetMany(ctx, map[string]string{
+		keySecurityCORSOrigins:    string(origins),
+		keySecurityCookieSameSite: settings.CookieSameSite,
+		keySecurityCookieSecure:   secure,
+		keySecurityCookieDomain:   settings.CookieDomain,
+	}); err != nil {
+		logger.Warn("security settings update failed", "module", "service", "action", "update", "resource", "settings", "result", "failed", "error", err)
+		return fmt.Errorf("set security settings: %w", err)
+	}
+
+	logger.Info("security settings updated", "module", "service", "action", "update", "resource", "settings", "result", "ok", "cors_origins", len(settings.CORSOrigins), "same_site", settings.CookieSameSite, "secure", settings.CookieSecure)
+	return nil
+}
+
+// NormalizeSecuritySettings trims and validates security settings in place.
+func NormalizeSecuritySettings(settings *SecuritySettings) error {
+	sameSite := strings.ToLower(strings.TrimSpace(settings.CookieSameSite))
+	if sameSite == "" {
+		sameSite = "lax"
+	}
+	if sameSite != "lax" && sameSite != "none" {
+		return ErrInvalid
+	}
+	if sameSite == "none" && !settings.CookieSecure {
+		return ErrInsecureSameSiteNone
+	}
+	settings.CookieSameSite = sameSite
+
+	settings.CookieDomain = strings.ToLower(strings.TrimSpace(settings.CookieDomain))
+	if strings.ContainsAny(settings.CookieDomain, "/: ") {
+		return ErrInvalid
+	}
+
+	origins := make([]string, 0, len(settings.CORSOrigins))
+	seen := make(map[string]struct{}, len(settings.CORSOrigins))
+	for _, raw := range settings.CORSOrigins {
+		origin, ok := normalizeOrigin(raw)
+		if !ok {
+			return ErrInvalid
+		}
+		if origin == "" {
+			continue
+		}
+		if _, dup := seen[origin]; dup {
+			continue
+		}
+		seen[origin] = struct{}{}
+		origins = append(origins, origin)
+	}
+	settings.CORSOrigins = origins
+	return nil
+}
+
+// normalizeOrigin returns scheme://host[:port] for an origin. Wildcards are rejected
+// because credentials (the auth cookie) are allowed on cross-origin requests.
+func normalizeOrigin(raw string) (string, bool) {
+	raw = strings.TrimRight(strings.TrimSpace(raw), "/")
+	if raw == "" {
+		return "", true
+	}
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || strings.Contains(u.Host, "*") {
+		return "", false
+	}
+	if u.Path != "" || u.RawQuery != "" || u.Fragment != "" || u.User != nil {
+		return "", false
+	}
+	return strings.ToLower(u.Scheme + "://" + u.Host), true
+}
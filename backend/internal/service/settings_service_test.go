@@ -4,16 +4,20 @@ import (
 	"context"
 	"errors"
 	"gist/backend/internal/service"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"gist/backend/internal/service/ai"
+	"gist/backend/pkg/network"
 
 	"github.com/stretchr/testify/require"
 )
 
 func TestSettingsService_GetAISettings_Defaults(t *testing.T) {
 	repo := newSettingsRepoStub()
-	svc := service.NewSettingsService(repo, ai.NewRateLimiter(0))
+	svc := service.NewSettingsService(repo, ai.NewRateLimiter(0), service.SecuritySettings{})
 
 	settings, err := svc.GetAISettings(context.Background())
 	require.NoError(t, err)
@@ -35,7 +39,7 @@ func TestSettingsService_GetAISettings_MaskedKey(t *testing.T) {
 	repo.data[service.KeyAIAutoSummary] = "true"
 	repo.data[service.KeyAIRateLimit] = "5"
 
-	svc := service.NewSettingsService(repo, ai.NewRateLimiter(0))
+	svc := service.NewSettingsService(repo, ai.NewRateLimiter(0), service.SecuritySettings{})
 	settings, err := svc.GetAISettings(context.Background())
 	require.NoError(t, err)
 	require.NotEqual(t, "sk-test-1234567890", settings.APIKey)
@@ -50,7 +54,7 @@ func TestSettingsService_GetAISettings_InvalidRequestOptions(t *testing.T) {
 	repo := newSettingsRepoStub()
 	repo.data[service.KeyAIRequestOptions] = `{invalid json`
 
-	svc := service.NewSettingsService(repo, ai.NewRateLimiter(0))
+	svc := service.NewSettingsService(repo, ai.NewRateLimiter(0), service.SecuritySettings{})
 	settings, err := svc.GetAISettings(context.Background())
 
 	require.Nil(t, settings)
@@ -60,23 +64,29 @@ func TestSettingsService_GetAISettings_InvalidRequestOptions(t *testing.T) {
 func TestSettingsService_SetAISettings_StoresAndUpdatesLimiter(t *testing.T) {
 	repo := newSettingsRepoStub()
 	limiter := ai.NewRateLimiter(1)
-	svc := service.NewSettingsService(repo, limiter)
+	svc := service.NewSettingsService(repo, limiter, service.SecuritySettings{})
 
 	settings := &service.AISettings{
-		Provider:        ai.ProviderOpenAI,
-		APIKey:          "sk-realkey-123",
-		BaseURL:         "https://api.example.com",
-		Model:           "gpt-4",
-		RequestOptions:  map[string]any{"temperature": 0.2},
-		SummaryLanguage: "en-US",
-		AutoTranslate:   true,
-		AutoSummary:     true,
-		RateLimit:       20,
+		Provider:           ai.ProviderOpenAI,
+		APIKey:             "sk-realkey-123",
+		BaseURL:            "https://api.example.com",
+		Model:              "gpt-4",
+		RequestOptions:     map[string]any{"temperature": 0.2},
+		SummaryLanguage:    "en-US",
+		AutoTranslate:      true,
+		TranslateOnRefresh: true,
+		AutoSummary:        true,
+		RateLimit:          20,
 	}
 
 	err := svc.SetAISettings(context.Background(), settings)
 	require.NoError(t, err)
 	require.Equal(t, "sk-realkey-123", repo.data[service.KeyAIAPIKey])
+	require.Equal(t, "true", repo.data[service.KeyAITranslateOnRefresh])
+
+	got, err := svc.GetAISettings(context.Background())
+	require.NoError(t, err)
+	require.True(t, got.TranslateOnRefresh)
 	require.Equal(t, 20, limiter.GetLimit())
 
 	repo.data[service.KeyAIAPIKey] = "sk-existing"
@@ -89,9 +99,42 @@ func TestSettingsService_SetAISettings_StoresAndUpdatesLimiter(t *testing.T) {
 	require.JSONEq(t, `{"temperature":0.2}`, repo.data[service.KeyAIRequestOptions])
 }
 
+func TestSettingsService_AISettings_UsagePricing(t *testing.T) {
+	repo := newSettingsRepoStub()
+	svc := service.NewSettingsService(repo, ai.NewRateLimiter(1), service.SecuritySettings{})
+	ctx := context.Background()
+
+	got, err := svc.GetAISettings(ctx)
+	require.NoError(t, err)
+	require.Empty(t, got.ModelPrices)
+	require.Equal(t, service.DefaultAIUsageRetentionMonths, got.UsageRetentionMonths)
+	require.Equal(t, ai.DefaultTranslateBlockChars, got.TranslateBlockChars)
+
+	settings := &service.AISettings{
+		Provider:             ai.ProviderOpenAI,
+		Model:                "gpt-4o",
+		ModelPrices:          map[string]service.AIModelPrice{"gpt-4o": {Input: 2.5, Output: 10}},
+		UsageRetentionMonths: 6,
+		TranslateBlockChars:  800,
+	}
+	require.NoError(t, svc.SetAISettings(ctx, settings))
+
+	// Omitted prices, retention and block budget keep what is stored
+	settings.ModelPrices = nil
+	settings.UsageRetentionMonths = 0
+	settings.TranslateBlockChars = 0
+	require.NoError(t, svc.SetAISettings(ctx, settings))
+
+	got, err = svc.GetAISettings(ctx)
+	require.NoError(t, err)
+	require.Equal(t, map[string]service.AIModelPrice{"gpt-4o": {Input: 2.5, Output: 10}}, got.ModelPrices)
+	require.Equal(t, 6, got.UsageRetentionMonths)
+	require.Equal(t, 800, got.TranslateBlockChars)
+}
+
 func TestSettingsService_GeneralSettings(t *testing.T) {
 	repo := newSettingsRepoStub()
-	svc := service.NewSettingsService(repo, ai.NewRateLimiter(0))
+	svc := service.NewSettingsService(repo, ai.NewRateLimiter(0), service.SecuritySettings{})
 
 	err := svc.SetGeneralSettings(context.Background(), &service.GeneralSettings{
 		FallbackUserAgent: "UA-Test",
@@ -111,9 +154,142 @@ func TestSettingsService_GeneralSettings(t *testing.T) {
 	require.Equal(t, "UA-Test", ua)
 }
 
+func TestSettingsService_GeneralSettings_FallbackUserAgent(t *testing.T) {
+	ctx := context.Background()
+	repo := newSettingsRepoStub()
+	svc := service.NewSettingsService(repo, ai.NewRateLimiter(0), service.SecuritySettings{})
+
+	require.NoError(t, svc.SetGeneralSettings(ctx, &service.GeneralSettings{FallbackUserAgent: "  Mozilla/5.0   (X11)  "}))
+	require.Equal(t, "Mozilla/5.0 (X11)", repo.data[service.KeyFallbackUserAgent])
+
+	// Values that could inject headers are rejected, keeping the stored one
+	for _, ua := range []string{"UA\r\nX-Injected: 1", "UA\nX-Injected: 1", "UA\x00", "UA\tTab", strings.Repeat("a", 513)} {
+		err := svc.SetGeneralSettings(ctx, &service.GeneralSettings{FallbackUserAgent: ua})
+		require.ErrorIs(t, err, service.ErrInvalid, ua)
+		require.Equal(t, "Mozilla/5.0 (X11)", repo.data[service.KeyFallbackUserAgent])
+	}
+
+	// A blank value disables the fallback instead of sending an empty UA
+	require.NoError(t, svc.SetGeneralSettings(ctx, &service.GeneralSettings{FallbackUserAgent: "   "}))
+	require.Empty(t, repo.data[service.KeyFallbackUserAgent])
+	require.Empty(t, svc.GetFallbackUserAgent(ctx))
+
+	// Values stored before validation are cleaned on the way out
+	repo.data[service.KeyFallbackUserAgent] = "Bad\r\nX-Injected: 1"
+	require.Equal(t, "Bad X-Injected: 1", svc.GetFallbackUserAgent(ctx))
+	settings, err := svc.GetGeneralSettings(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "Bad X-Injected: 1", settings.FallbackUserAgent)
+	repo.data[service.KeyFallbackUserAgent] = "\r\n"
+	require.Empty(t, svc.GetFallbackUserAgent(ctx))
+}
+
+func TestSettingsService_GeneralSettings_EntryRevisionsDefaultEnabled(t *testing.T) {
+	repo := newSettingsRepoStub()
+	svc := service.NewSettingsService(repo, ai.NewRateLimiter(0), service.SecuritySettings{})
+
+	settings, err := svc.GetGeneralSettings(context.Background())
+	require.NoError(t, err)
+	require.True(t, settings.EntryRevisions)
+
+	settings.EntryRevisions = false
+	require.NoError(t, svc.SetGeneralSettings(context.Background(), settings))
+
+	settings, err = svc.GetGeneralSettings(context.Background())
+	require.NoError(t, err)
+	require.False(t, settings.EntryRevisions)
+}
+
+func TestSettingsService_GeneralSettings_ImageCache(t *testing.T) {
+	repo := newSettingsRepoStub()
+	svc := service.NewSettingsService(repo, ai.NewRateLimiter(0), service.SecuritySettings{})
+
+	settings, err := svc.GetGeneralSettings(context.Background())
+	require.NoError(t, err)
+	require.False(t, settings.ImageCache)
+	require.Equal(t, service.DefaultImageCacheEntryLimitMB, settings.ImageCacheEntryLimitMB)
+	require.Equal(t, service.DefaultImageCacheTotalLimitMB, settings.ImageCacheTotalLimitMB)
+
+	settings.ImageCache = true
+	settings.ImageCacheEntryLimitMB = 8
+	settings.ImageCacheTotalLimitMB = 0
+	require.NoError(t, svc.SetGeneralSettings(context.Background(), settings))
+
+	settings, err = svc.GetGeneralSettings(context.Background())
+	require.NoError(t, err)
+	require.True(t, settings.ImageCache)
+	require.Equal(t, 8, settings.ImageCacheEntryLimitMB)
+	// A zero limit is not stored; the default still applies
+	require.Equal(t, service.DefaultImageCacheTotalLimitMB, settings.ImageCacheTotalLimitMB)
+}
+
+func TestSettingsService_GeneralSettings_Timezone(t *testing.T) {
+	repo := newSettingsRepoStub()
+	svc := service.NewSettingsService(repo, ai.NewRateLimiter(0), service.SecuritySettings{})
+
+	settings, err := svc.GetGeneralSettings(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, service.DefaultTimezone, settings.Timezone)
+
+	settings.Timezone = "Asia/Shanghai"
+	require.NoError(t, svc.SetGeneralSettings(context.Background(), settings))
+
+	settings, err = svc.GetGeneralSettings(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "Asia/Shanghai", settings.Timezone)
+}
+
+func TestSettingsService_GeneralSettings_AnubisRetry(t *testing.T) {
+	repo := newSettingsRepoStub()
+	svc := service.NewSettingsService(repo, ai.NewRateLimiter(0), service.SecuritySettings{})
+
+	settings, err := svc.GetGeneralSettings(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, service.DefaultAnubisMaxRetries, settings.AnubisMaxRetries)
+	require.Zero(t, settings.AnubisRetryDelayMs)
+
+	settings.AnubisMaxRetries = 5
+	settings.AnubisRetryDelayMs = 1500
+	require.NoError(t, svc.SetGeneralSettings(context.Background(), settings))
+
+	settings, err = svc.GetGeneralSettings(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 5, settings.AnubisMaxRetries)
+	require.Equal(t, 1500, settings.AnubisRetryDelayMs)
+
+	// Out of range stored values fall back to the defaults
+	repo.data["general.anubis_max_retries"] = "99"
+	repo.data["general.anubis_retry_delay_ms"] = "-1"
+	settings, err = svc.GetGeneralSettings(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, service.DefaultAnubisMaxRetries, settings.AnubisMaxRetries)
+	require.Zero(t, settings.AnubisRetryDelayMs)
+}
+
+func TestSettingsService_GeneralSettings_UnconditionalFetchAfter(t *testing.T) {
+	repo := newSettingsRepoStub()
+	svc := service.NewSettingsService(repo, ai.NewRateLimiter(0), service.SecuritySettings{})
+
+	settings, err := svc.GetGeneralSettings(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, service.DefaultUnconditionalFetchAfter, settings.UnconditionalFetchAfter)
+
+	settings.UnconditionalFetchAfter = 25
+	require.NoError(t, svc.SetGeneralSettings(context.Background(), settings))
+
+	settings, err = svc.GetGeneralSettings(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 25, settings.UnconditionalFetchAfter)
+
+	repo.data["general.unconditional_fetch_after"] = "0"
+	settings, err = svc.GetGeneralSettings(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, service.DefaultUnconditionalFetchAfter, settings.UnconditionalFetchAfter)
+}
+
 func TestSettingsService_GeneralSettings_SetManyErrorIsAtomic(t *testing.T) {
 	repo := newSettingsRepoStub()
-	svc := service.NewSettingsService(repo, ai.NewRateLimiter(0))
+	svc := service.NewSettingsService(repo, ai.NewRateLimiter(0), service.SecuritySettings{})
 
 	repo.setErr[service.KeyMarkReadOnScroll] = errors.New("write failed")
 
@@ -126,23 +302,76 @@ func TestSettingsService_GeneralSettings_SetManyErrorIsAtomic(t *testing.T) {
 	require.Empty(t, repo.data)
 }
 
+func TestSettingsService_AISettings_RejectsStaleVersion(t *testing.T) {
+	repo := newSettingsRepoStub()
+	svc := service.NewSettingsService(repo, ai.NewRateLimiter(0), service.SecuritySettings{})
+	ctx := context.Background()
+
+	tabA, err := svc.GetAISettings(ctx)
+	require.NoError(t, err)
+	tabB, err := svc.GetAISettings(ctx)
+	require.NoError(t, err)
+	require.Equal(t, tabA.Version, tabB.Version)
+
+	tabB.APIKey = "sk-new-key-123456"
+	tabB.Model = "gpt-4o"
+	require.NoError(t, svc.SetAISettings(ctx, tabB))
+	require.NotEqual(t, tabA.Version, tabB.Version)
+
+	tabA.Model = "gpt-4"
+	err = svc.SetAISettings(ctx, tabA)
+	require.ErrorIs(t, err, service.ErrConflict)
+	require.Equal(t, "sk-new-key-123456", repo.data[service.KeyAIAPIKey])
+	require.Equal(t, "gpt-4o", repo.data[service.KeyAIModel])
+
+	// Clients that don't send a version still overwrite
+	tabA.Version = ""
+	require.NoError(t, svc.SetAISettings(ctx, tabA))
+	require.Equal(t, "gpt-4", repo.data[service.KeyAIModel])
+}
+
+func TestSettingsService_GeneralSettings_VersionAdvancesOnSave(t *testing.T) {
+	repo := newSettingsRepoStub()
+	svc := service.NewSettingsService(repo, ai.NewRateLimiter(0), service.SecuritySettings{})
+	ctx := context.Background()
+
+	settings, err := svc.GetGeneralSettings(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "0", settings.Version)
+
+	settings.AutoReadability = true
+	require.NoError(t, svc.SetGeneralSettings(ctx, settings))
+
+	got, err := svc.GetGeneralSettings(ctx)
+	require.NoError(t, err)
+	require.Equal(t, settings.Version, got.Version)
+	require.NotEqual(t, "0", got.Version)
+
+	// Saving with the fresh version succeeds, the old one does not
+	require.NoError(t, svc.SetGeneralSettings(ctx, got))
+	settings.Version = "1"
+	require.ErrorIs(t, svc.SetGeneralSettings(ctx, settings), service.ErrConflict)
+}
+
 func TestSettingsService_ClearAnubisCookies(t *testing.T) {
 	repo := newSettingsRepoStub()
 	repo.data["anubis.cookie.example.com"] = "cookie"
 	repo.data["anubis.cookie.test.com"] = "cookie"
+	repo.data["anubis.breaker.test.com"] = `{"failures":3}`
 	repo.data["other.key"] = "value"
 
-	svc := service.NewSettingsService(repo, ai.NewRateLimiter(0))
+	svc := service.NewSettingsService(repo, ai.NewRateLimiter(0), service.SecuritySettings{})
 
 	deleted, err := svc.ClearAnubisCookies(context.Background())
 	require.NoError(t, err)
 	require.Equal(t, int64(2), deleted)
 	require.Contains(t, repo.data, "other.key")
+	require.NotContains(t, repo.data, "anubis.breaker.test.com")
 }
 
 func TestSettingsService_TestAI_InvalidConfig(t *testing.T) {
 	repo := newSettingsRepoStub()
-	svc := service.NewSettingsService(repo, ai.NewRateLimiter(0))
+	svc := service.NewSettingsService(repo, ai.NewRateLimiter(0), service.SecuritySettings{})
 
 	_, err := svc.TestAI(context.Background(), ai.ProviderOpenAI, "", "", "", nil)
 	require.Error(t, err)
@@ -163,7 +392,7 @@ func TestMaskAPIKey(t *testing.T) {
 
 func TestSettingsService_GetNetworkSettings_Defaults(t *testing.T) {
 	repo := newSettingsRepoStub()
-	svc := service.NewSettingsService(repo, ai.NewRateLimiter(0))
+	svc := service.NewSettingsService(repo, ai.NewRateLimiter(0), service.SecuritySettings{})
 
 	settings, err := svc.GetNetworkSettings(context.Background())
 	require.NoError(t, err)
@@ -175,7 +404,7 @@ func TestSettingsService_GetNetworkSettings_Defaults(t *testing.T) {
 
 func TestSettingsService_AppearanceSettings_Defaults(t *testing.T) {
 	repo := newSettingsRepoStub()
-	svc := service.NewSettingsService(repo, ai.NewRateLimiter(0))
+	svc := service.NewSettingsService(repo, ai.NewRateLimiter(0), service.SecuritySettings{})
 
 	settings, err := svc.GetAppearanceSettings(context.Background())
 	require.NoError(t, err)
@@ -185,7 +414,7 @@ func TestSettingsService_AppearanceSettings_Defaults(t *testing.T) {
 
 func TestSettingsService_AppearanceSettings_Validate(t *testing.T) {
 	repo := newSettingsRepoStub()
-	svc := service.NewSettingsService(repo, ai.NewRateLimiter(0))
+	svc := service.NewSettingsService(repo, ai.NewRateLimiter(0), service.SecuritySettings{})
 
 	err := svc.SetAppearanceSettings(context.Background(), &service.AppearanceSettings{ContentTypes: []string{}})
 	require.Error(t, err)
@@ -209,7 +438,7 @@ func TestSettingsService_GetNetworkSettings_StoredValues(t *testing.T) {
 	repo.data[service.KeyNetworkUsername] = "user"
 	repo.data[service.KeyNetworkPassword] = "secret123"
 
-	svc := service.NewSettingsService(repo, ai.NewRateLimiter(0))
+	svc := service.NewSettingsService(repo, ai.NewRateLimiter(0), service.SecuritySettings{})
 	settings, err := svc.GetNetworkSettings(context.Background())
 	require.NoError(t, err)
 	require.True(t, settings.Enabled)
@@ -223,7 +452,7 @@ func TestSettingsService_GetNetworkSettings_StoredValues(t *testing.T) {
 
 func TestSettingsService_SetNetworkSettings(t *testing.T) {
 	repo := newSettingsRepoStub()
-	svc := service.NewSettingsService(repo, ai.NewRateLimiter(0))
+	svc := service.NewSettingsService(repo, ai.NewRateLimiter(0), service.SecuritySettings{})
 
 	settings := &service.NetworkSettings{
 		Enabled:  true,
@@ -243,9 +472,31 @@ func TestSettingsService_SetNetworkSettings(t *testing.T) {
 	require.Equal(t, "password123", repo.data[service.KeyNetworkPassword])
 }
 
+func TestSettingsService_SetNetworkSettings_ReloadsClientFactory(t *testing.T) {
+	repo := newSettingsRepoStub()
+	svc := service.NewSettingsService(repo, ai.NewRateLimiter(0), service.SecuritySettings{})
+	factory := network.NewClientFactory(svc, svc)
+	svc.OnNetworkSettingsChange(factory.Invalidate)
+	ctx := context.Background()
+	target := httptest.NewRequest(http.MethodGet, "http://example.com/feed.xml", nil)
+
+	require.NoError(t, svc.SetNetworkSettings(ctx, &service.NetworkSettings{Enabled: true, Type: "http", Host: "old.proxy", Port: 8080}))
+	proxyURL, err := factory.NewHTTPTransport(ctx).Proxy(target)
+	require.NoError(t, err)
+	require.Equal(t, "http://old.proxy:8080", proxyURL.String())
+
+	require.NoError(t, svc.SetNetworkSettings(ctx, &service.NetworkSettings{Enabled: true, Type: "http", Host: "new.proxy", Port: 3128}))
+	proxyURL, err = factory.NewHTTPTransport(ctx).Proxy(target)
+	require.NoError(t, err)
+	require.Equal(t, "http://new.proxy:3128", proxyURL.String())
+
+	require.NoError(t, svc.SetNetworkSettings(ctx, &service.NetworkSettings{Enabled: false}))
+	require.Nil(t, factory.NewHTTPTransport(ctx).Proxy)
+}
+
 func TestSettingsService_GetIPStack(t *testing.T) {
 	repo := newSettingsRepoStub()
-	svc := service.NewSettingsService(repo, ai.NewRateLimiter(0))
+	svc := service.NewSettingsService(repo, ai.NewRateLimiter(0), service.SecuritySettings{})
 
 	require.Equal(t, "default", svc.GetIPStack(context.Background()))
 
@@ -257,7 +508,7 @@ func TestSettingsService_SetNetworkSettings_MaskedPassword(t *testing.T) {
 	repo := newSettingsRepoStub()
 	repo.data[service.KeyNetworkPassword] = "existing-password"
 
-	svc := service.NewSettingsService(repo, ai.NewRateLimiter(0))
+	svc := service.NewSettingsService(repo, ai.NewRateLimiter(0), service.SecuritySettings{})
 
 	settings := &service.NetworkSettings{
 		Enabled:  true,
@@ -369,9 +620,74 @@ func TestSettingsService_GetProxyURL(t *testing.T) {
 				repo.data[service.KeyNetworkPassword] = tt.password
 			}
 
-			svc := service.NewSettingsService(repo, ai.NewRateLimiter(0))
+			svc := service.NewSettingsService(repo, ai.NewRateLimiter(0), service.SecuritySettings{})
 			result := svc.GetProxyURL(context.Background())
 			require.Equal(t, tt.expected, result)
 		})
 	}
 }
+
+func TestSettingsService_SecuritySettings_EnvDefaults(t *testing.T) {
+	repo := newSettingsRepoStub()
+	svc := service.NewSettingsService(repo, ai.NewRateLimiter(0), service.SecuritySettings{
+		CORSOrigins:  []string{"https://app.example.com"},
+		CookieSecure: true,
+	})
+
+	settings, err := svc.GetSecuritySettings(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []string{"https://app.example.com"}, settings.CORSOrigins)
+	require.Equal(t, "lax", settings.CookieSameSite)
+	require.True(t, settings.CookieSecure)
+}
+
+func TestSettingsService_SetSecuritySettings_OverridesDefaults(t *testing.T) {
+	repo := newSettingsRepoStub()
+	svc := service.NewSettingsService(repo, ai.NewRateLimiter(0), service.SecuritySettings{
+		CORSOrigins: []string{"https://old.example.com"},
+	})
+
+	err := svc.SetSecuritySettings(context.Background(), &service.SecuritySettings{
+		CORSOrigins:    []string{"https://App.Example.com/", "https://app.example.com", " "},
+		CookieSameSite: "None",
+		CookieSecure:   true,
+		CookieDomain:   " Example.com ",
+	})
+	require.NoError(t, err)
+
+	settings, err := svc.GetSecuritySettings(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []string{"https://app.example.com"}, settings.CORSOrigins)
+	require.Equal(t, "none", settings.CookieSameSite)
+	require.True(t, settings.CookieSecure)
+	require.Equal(t, "example.com", settings.CookieDomain)
+}
+
+func TestNormalizeSecuritySettings(t *testing.T) {
+	cases := []struct {
+		name     string
+		settings service.SecuritySettings
+		wantErr  error
+	}{
+		{name: "defaults", settings: service.SecuritySettings{}},
+		{name: "none requires secure", settings: service.SecuritySettings{CookieSameSite: "none"}, wantErr: service.ErrInsecureSameSiteNone},
+		{name: "strict unsupported", settings: service.SecuritySettings{CookieSameSite: "strict"}, wantErr: service.ErrInvalid},
+		{name: "wildcard origin", settings: service.SecuritySettings{CORSOrigins: []string{"*"}}, wantErr: service.ErrInvalid},
+		{name: "origin with path", settings: service.SecuritySettings{CORSOrigins: []string{"https://a.example.com/app"}}, wantErr: service.ErrInvalid},
+		{name: "non http origin", settings: service.SecuritySettings{CORSOrigins: []string{"ftp://a.example.com"}}, wantErr: service.ErrInvalid},
+		{name: "domain with port", settings: service.SecuritySettings{CookieDomain: "example.com:8080"}, wantErr: service.ErrInvalid},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			settings := tc.settings
+			err := service.NormalizeSecuritySettings(&settings)
+			if tc.wantErr != nil {
+				require.ErrorIs(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, "lax", settings.CookieSameSite)
+		})
+	}
+}
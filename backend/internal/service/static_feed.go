@@ -0,0 +1,37 @@
+package service
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/mmcdole/gofeed"
+
+	"gist/backend/internal/model"
+)
+
+// StaticFeedURLPrefix marks feeds whose content is pasted in rather than fetched.
+// Their URL is synthetic, so they are never refreshed.
+const StaticFeedURLPrefix = "static://"
+
+// MaxStaticFeedSize caps the feed document accepted for a static feed.
+const MaxStaticFeedSize = 5 << 20
+
+// IsStaticFeed reports whether the feed was created from pasted content.
+func IsStaticFeed(feed model.Feed) bool {
+	return strings.HasPrefix(feed.URL, StaticFeedURLPrefix)
+}
+
+// ParseStaticFeed parses a pasted RSS, Atom or JSON feed document.
+func ParseStaticFeed(data []byte) (*gofeed.Feed, error) {
+	if len(data) > MaxStaticFeedSize {
+		return nil, ErrStaticFeedTooLarge
+	}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil, ErrNotAFeed
+	}
+	parsed, err := parseFeed(data)
+	if err != nil {
+		return nil, ErrNotAFeed
+	}
+	return parsed, nil
+}
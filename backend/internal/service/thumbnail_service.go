@@ -0,0 +1,272 @@
+//go:generate mockgen -source=$GOFILE -destination=mock/$GOFILE -package=mock
+package service
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	_ "golang.org/x/image/webp"
+	"golang.org/x/sync/singleflight"
+
+	"gist/backend/internal/repository"
+	"gist/backend/pkg/logger"
+	"gist/backend/pkg/network"
+)
+
+const (
+	// DefaultThumbnailWidth is the width thumbnails are scaled to when none is asked for.
+	DefaultThumbnailWidth = 400
+	// MinThumbnailWidth and MaxThumbnailWidth bound the requested width.
+	MinThumbnailWidth = 32
+	MaxThumbnailWidth = 1600
+	// ThumbnailFailureTTL is how long a thumbnail that failed to generate is not retried.
+	ThumbnailFailureTTL = time.Hour
+
+	thumbnailQuality = 80
+	// maxThumbnailSourcePixels bounds the images decoded for thumbnails.
+	maxThumbnailSourcePixels = 6000 * 6000
+)
+
+// ThumbnailService scales entry thumbnails down for the picture grid and keeps
+// the results on disk.
+type ThumbnailService interface {
+	// Thumbnail returns the path of a JPEG of the entry's thumbnail at most width
+	// pixels wide, generating it on first use. It returns ErrNotFound when the
+	// entry has no thumbnail and ErrThumbnailUnavailable when it can't be loaded.
+	Thumbnail(ctx context.Context, entryID int64, width int) (string, error)
+}
+
+type thumbnailService struct {
+	dir      string
+	maxBytes int64
+	entries  repository.EntryRepository
+	proxy    ProxyService
+	group    singleflight.Group
+
+	mu         sync.Mutex
+	totalBytes int64 // -1 until measured from disk
+	failures   map[int64]time.Time
+}
+
+// NewThumbnailService keeps at most maxBytes of thumbnails under dataDir,
+// evicting the least recently served ones first.
+func NewThumbnailService(dataDir string, maxBytes int64, entries repository.EntryRepository, proxy ProxyService) ThumbnailService {
+	return &thumbnailService{
+		dir:        filepath.Join(dataDir, "thumbnails"),
+		maxBytes:   maxBytes,
+		entries:    entries,
+		proxy:      proxy,
+		totalBytes: -1,
+		failures:   make(map[int64]time.Time),
+	}
+}
+
+func (s *thumbnailService) Thumbnail(ctx context.Context, entryID int64, width int) (string, error) {
+	if width < MinThumbnailWidth || width > MaxThumbnailWidth {
+		return "", fmt.Errorf("thumbnail width %d: %w", width, ErrInvalid)
+	}
+	name := strconv.FormatInt(entryID, 10) + "-" + strconv.Itoa(width) + ".jpg"
+	path := filepath.Join(s.dir, name)
+
+	// The modification time doubles as the last access for eviction
+	now := time.Now()
+	if err := os.Chtimes(path, now, now); err == nil {
+		return path, nil
+	}
+	if s.failedRecently(entryID) {
+		return "", ErrThumbnailUnavailable
+	}
+
+	// Concurrent requests share one generation, which outlives the first client
+	_, err, _ := s.group.Do(name, func() (any, error) {
+		return nil, s.generate(context.WithoutCancel(ctx), entryID, width, path)
+	})
+	if err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+func (s *thumbnailService) generate(ctx context.Context, entryID int64, width int, path string) error {
+	entry, err := s.entries.GetByID(ctx, entryID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("get entry: %w", err)
+	}
+	if entry.ThumbnailURL == nil || *entry.ThumbnailURL == "" {
+		return ErrNotFound
+	}
+
+	pageURL := ptrString(entry.URL)
+	imageURL := resolveImageURL(*entry.ThumbnailURL, pageURL)
+	data, err := s.fetch(ctx, imageURL, pageURL, width)
+	if err != nil {
+		s.markFailed(entryID)
+		logger.Debug("thumbnail generation failed", "module", "service", "action", "resize", "resource", "thumbnail", "result", "failed", "entry_id", entryID, "host", network.ExtractHost(imageURL), "error", err)
+		return ErrThumbnailUnavailable
+	}
+
+	if err := s.store(path, data); err != nil {
+		logger.Warn("thumbnail write failed", "module", "service", "action", "save", "resource", "thumbnail", "result", "failed", "entry_id", entryID, "error", err)
+		return err
+	}
+	logger.Debug("thumbnail generated", "module", "service", "action", "resize", "resource", "thumbnail", "result", "ok", "entry_id", entryID, "width", width, "size", len(data))
+	return nil
+}
+
+// fetch downloads imageURL with pageURL as Referer and encodes it as a JPEG
+// thumbnail at most width pixels wide.
+func (s *thumbnailService) fetch(ctx context.Context, imageURL, pageURL string, width int) ([]byte, error) {
+	if imageURL == "" {
+		return nil, ErrInvalidURL
+	}
+	result, err := s.proxy.FetchImage(ctx, imageURL, pageURL)
+	if err != nil {
+		return nil, err
+	}
+	return encodeThumbnail(result.Data, width)
+}
+
+// store writes data to path and evicts the least recently used thumbnails
+// once the cache is over its size limit.
+func (s *thumbnailService) store(path string, data []byte) error {
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return err
+	}
+	// Write to a temp file first so concurrent requests never serve a partial JPEG
+	tmp, err := os.CreateTemp(s.dir, ".thumbnail-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.totalBytes < 0 {
+		s.totalBytes = dirSize(s.dir)
+	} else {
+		s.totalBytes += int64(len(data))
+	}
+	if s.totalBytes > s.maxBytes {
+		s.evict(path)
+	}
+	return nil
+}
+
+// evict removes the least recently used thumbnails, never keep, until the cache
+// fits its size limit. Callers must hold mu.
+func (s *thumbnailService) evict(keep string) {
+	dirEntries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return
+	}
+	type cached struct {
+		path    string
+		size    int64
+		modTime time.Time
+	}
+	files := make([]cached, 0, len(dirEntries))
+	for _, d := range dirEntries {
+		info, err := d.Info()
+		if err != nil || d.IsDir() {
+			continue
+		}
+		files = append(files, cached{path: filepath.Join(s.dir, d.Name()), size: info.Size(), modTime: info.ModTime()})
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].modTime.Before(files[j].modTime) })
+
+	removed := 0
+	for _, file := range files {
+		if s.totalBytes <= s.maxBytes {
+			break
+		}
+		if file.path == keep {
+			continue
+		}
+		if err := os.Remove(file.path); err != nil {
+			continue
+		}
+		s.totalBytes -= file.size
+		removed++
+	}
+	logger.Debug("thumbnails evicted", "module", "service", "action", "clear", "resource", "thumbnail", "result", "ok", "count", removed)
+}
+
+func (s *thumbnailService) failedRecently(entryID int64) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	until, ok := s.failures[entryID]
+	if ok && time.Now().After(until) {
+		delete(s.failures, entryID)
+		return false
+	}
+	return ok
+}
+
+func (s *thumbnailService) markFailed(entryID int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	for id, until := range s.failures {
+		if now.After(until) {
+			delete(s.failures, id)
+		}
+	}
+	s.failures[entryID] = now.Add(ThumbnailFailureTTL)
+}
+
+// encodeThumbnail decodes a JPEG, PNG, GIF or WebP image and returns it as a JPEG
+// scaled down to width pixels, or at its own size if that is narrower.
+// Transparent areas are flattened onto white.
+func encodeThumbnail(data []byte, width int) ([]byte, error) {
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("decode thumbnail config: %w", err)
+	}
+	if config.Width*config.Height > maxThumbnailSourcePixels {
+		return nil, fmt.Errorf("thumbnail source too large: %dx%d", config.Width, config.Height)
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("decode thumbnail: %w", err)
+	}
+
+	bounds := img.Bounds()
+	if bounds.Dx() > width {
+		img = scaleImage(img, width, max(1, bounds.Dy()*width/bounds.Dx()))
+		bounds = img.Bounds()
+	}
+	canvas := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(canvas, canvas.Bounds(), image.White, image.Point{}, draw.Src)
+	draw.Draw(canvas, canvas.Bounds(), img, bounds.Min, draw.Over)
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, canvas, &jpeg.Options{Quality: thumbnailQuality}); err != nil {
+		return nil, fmt.Errorf("encode thumbnail: %w", err)
+	}
+	return buf.Bytes(), nil
+}
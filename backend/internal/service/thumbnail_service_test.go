@@ -0,0 +1,181 @@
+package service_test
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"gist/backend/internal/model"
+	repomock "gist/backend/internal/repository/mock"
+	"gist/backend/internal/service"
+	"gist/backend/internal/service/mock"
+)
+
+// expectThumbnail sets up one lookup and download of the entry's thumbnail.
+func expectThumbnail(mockEntries *repomock.MockEntryRepository, mockProxy *mock.MockProxyService, entryID int64, data []byte) {
+	thumbnail := "/img/cover.png"
+	page := "https://example.com/post"
+	mockEntries.EXPECT().GetByID(gomock.Any(), entryID).Return(model.Entry{ID: entryID, URL: &page, ThumbnailURL: &thumbnail}, nil)
+	mockProxy.EXPECT().FetchImage(gomock.Any(), "https://example.com/img/cover.png", page).Return(&service.ProxyResult{Data: data, ContentType: "image/png"}, nil)
+}
+
+func TestThumbnailService_Thumbnail(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dataDir := t.TempDir()
+	mockEntries := repomock.NewMockEntryRepository(ctrl)
+	mockProxy := mock.NewMockProxyService(ctrl)
+	svc := service.NewThumbnailService(dataDir, 1<<20, mockEntries, mockProxy)
+	expectThumbnail(mockEntries, mockProxy, 1, pngBytes(t, 800, 400))
+
+	path, err := svc.Thumbnail(context.Background(), 1, 400)
+	require.NoError(t, err)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	require.NoError(t, err)
+	require.Equal(t, "jpeg", format)
+	require.Equal(t, 400, config.Width)
+	require.Equal(t, 200, config.Height)
+
+	// Served from disk the second time
+	cached, err := svc.Thumbnail(context.Background(), 1, 400)
+	require.NoError(t, err)
+	require.Equal(t, path, cached)
+}
+
+func TestThumbnailService_Thumbnail_KeepsSmallerImagesAndFlattensAlpha(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dataDir := t.TempDir()
+	mockEntries := repomock.NewMockEntryRepository(ctrl)
+	mockProxy := mock.NewMockProxyService(ctrl)
+	svc := service.NewThumbnailService(dataDir, 1<<20, mockEntries, mockProxy)
+	expectThumbnail(mockEntries, mockProxy, 1, pngBytes(t, 100, 50))
+
+	path, err := svc.Thumbnail(context.Background(), 1, 400)
+	require.NoError(t, err)
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+	img, err := jpeg.Decode(file)
+	require.NoError(t, err)
+	require.Equal(t, image.Rect(0, 0, 100, 50), img.Bounds())
+	// Transparent pixels turn white, give or take JPEG rounding
+	gray := color.GrayModel.Convert(img.At(50, 25)).(color.Gray)
+	require.GreaterOrEqual(t, gray.Y, uint8(0xf0))
+}
+
+func TestThumbnailService_Thumbnail_NegativeCache(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dataDir := t.TempDir()
+	mockEntries := repomock.NewMockEntryRepository(ctrl)
+	mockProxy := mock.NewMockProxyService(ctrl)
+	svc := service.NewThumbnailService(dataDir, 1<<20, mockEntries, mockProxy)
+	thumbnail := "https://cdn.example.com/a.jpg"
+	mockEntries.EXPECT().GetByID(gomock.Any(), int64(1)).Return(model.Entry{ID: 1, ThumbnailURL: &thumbnail}, nil)
+	mockProxy.EXPECT().FetchImage(gomock.Any(), thumbnail, "").Return(nil, service.ErrFetchFailed)
+
+	_, err := svc.Thumbnail(context.Background(), 1, 400)
+	require.ErrorIs(t, err, service.ErrThumbnailUnavailable)
+
+	// Not retried, at any width
+	_, err = svc.Thumbnail(context.Background(), 1, 800)
+	require.ErrorIs(t, err, service.ErrThumbnailUnavailable)
+}
+
+func TestThumbnailService_Thumbnail_UndecodableImage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dataDir := t.TempDir()
+	mockEntries := repomock.NewMockEntryRepository(ctrl)
+	mockProxy := mock.NewMockProxyService(ctrl)
+	svc := service.NewThumbnailService(dataDir, 1<<20, mockEntries, mockProxy)
+	expectThumbnail(mockEntries, mockProxy, 1, []byte("<html>not an image</html>"))
+
+	_, err := svc.Thumbnail(context.Background(), 1, 400)
+	require.ErrorIs(t, err, service.ErrThumbnailUnavailable)
+}
+
+func TestThumbnailService_Thumbnail_Errors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dataDir := t.TempDir()
+	mockEntries := repomock.NewMockEntryRepository(ctrl)
+	mockProxy := mock.NewMockProxyService(ctrl)
+	svc := service.NewThumbnailService(dataDir, 1<<20, mockEntries, mockProxy)
+
+	_, err := svc.Thumbnail(context.Background(), 1, service.MaxThumbnailWidth+1)
+	require.ErrorIs(t, err, service.ErrInvalid)
+
+	mockEntries.EXPECT().GetByID(gomock.Any(), int64(1)).Return(model.Entry{}, sql.ErrNoRows)
+	_, err = svc.Thumbnail(context.Background(), 1, 400)
+	require.ErrorIs(t, err, service.ErrNotFound)
+
+	mockEntries.EXPECT().GetByID(gomock.Any(), int64(2)).Return(model.Entry{ID: 2}, nil)
+	_, err = svc.Thumbnail(context.Background(), 2, 400)
+	require.ErrorIs(t, err, service.ErrNotFound)
+
+	// Database errors are not cached as failures
+	mockEntries.EXPECT().GetByID(gomock.Any(), int64(3)).Return(model.Entry{}, errors.New("db down")).Times(2)
+	_, err = svc.Thumbnail(context.Background(), 3, 400)
+	require.Error(t, err)
+	require.NotErrorIs(t, err, service.ErrThumbnailUnavailable)
+	_, err = svc.Thumbnail(context.Background(), 3, 400)
+	require.Error(t, err)
+}
+
+func TestThumbnailService_Thumbnail_EvictsLeastRecentlyUsed(t *testing.T) {
+	source := pngBytes(t, 64, 64)
+	encoded, err := service.EncodeThumbnail(source, 64)
+	require.NoError(t, err)
+	size := int64(len(encoded))
+
+	// Room for two thumbnails
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dataDir := t.TempDir()
+	mockEntries := repomock.NewMockEntryRepository(ctrl)
+	mockProxy := mock.NewMockProxyService(ctrl)
+	svc := service.NewThumbnailService(dataDir, 2*size+size/2, mockEntries, mockProxy)
+	for id := int64(1); id <= 3; id++ {
+		expectThumbnail(mockEntries, mockProxy, id, source)
+	}
+
+	first, err := svc.Thumbnail(context.Background(), 1, 64)
+	require.NoError(t, err)
+	second, err := svc.Thumbnail(context.Background(), 2, 64)
+	require.NoError(t, err)
+	old := time.Now().Add(-time.Hour)
+	require.NoError(t, os.Chtimes(first, old, old))
+	require.NoError(t, os.Chtimes(second, old.Add(time.Minute), old.Add(time.Minute)))
+
+	// Serving the first thumbnail again makes the second the least recently used
+	_, err = svc.Thumbnail(context.Background(), 1, 64)
+	require.NoError(t, err)
+	third, err := svc.Thumbnail(context.Background(), 3, 64)
+	require.NoError(t, err)
+
+	require.FileExists(t, first)
+	require.NoFileExists(t, second)
+	require.FileExists(t, third)
+	require.Equal(t, filepath.Dir(first), filepath.Join(dataDir, "thumbnails"))
+}
@@ -0,0 +1,60 @@
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"os"
+	"time"
+
+	"gist/backend/pkg/logger"
+)
+
+// Phase is one step of the shutdown sequence.
+type Phase struct {
+	Name string
+	// Timeout bounds Run; a phase that overruns is abandoned and the next one starts.
+	Timeout time.Duration
+	Run     func(ctx context.Context) error
+}
+
+// OnSignal waits for a signal on sigCh, then runs phases.
+func OnSignal(sigCh <-chan os.Signal, phases []Phase) {
+	sig := <-sigCh
+	logger.Info("shutting down", "module", "server", "action", "shutdown", "resource", "server", "result", "ok", "signal", sig.String())
+	Run(phases)
+}
+
+// Run executes phases in order. A failed or timed out phase is logged and does
+// not stop the ones after it, so the database is closed whatever happened before.
+func Run(phases []Phase) {
+	for _, phase := range phases {
+		start := time.Now()
+		err := runPhase(phase)
+		duration := time.Since(start).Milliseconds()
+		switch {
+		case err == nil:
+			logger.Info("shutdown phase", "module", "server", "action", "shutdown", "resource", phase.Name, "result", "ok", "duration_ms", duration)
+		case errors.Is(err, context.DeadlineExceeded):
+			logger.Warn("shutdown phase", "module", "server", "action", "shutdown", "resource", phase.Name, "result", "timeout", "duration_ms", duration)
+		default:
+			logger.Error("shutdown phase", "module", "server", "action", "shutdown", "resource", phase.Name, "result", "failed", "duration_ms", duration, "error", err)
+		}
+	}
+}
+
+func runPhase(phase Phase) error {
+	ctx, cancel := context.WithTimeout(context.Background(), phase.Timeout)
+	defer cancel()
+
+	// Run in its own goroutine so a phase that ignores ctx cannot stall the rest
+	done := make(chan error, 1)
+	go func() {
+		done <- phase.Run(ctx)
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
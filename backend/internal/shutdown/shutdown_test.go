@@ -0,0 +1,80 @@
+package shutdown_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"gist/backend/internal/model"
+	"gist/backend/internal/repository"
+	"gist/backend/internal/repository/testutil"
+	"gist/backend/internal/shutdown"
+)
+
+func TestOnSignal_ClosesDatabaseLast(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	writes := repository.NewWriteTracker()
+	feeds := repository.NewFeedRepository(db, nil, writes)
+
+	var order []string
+	record := func(name string, run func(ctx context.Context) error) shutdown.Phase {
+		return shutdown.Phase{Name: name, Timeout: time.Second, Run: func(ctx context.Context) error {
+			order = append(order, name)
+			return run(ctx)
+		}}
+	}
+	phases := []shutdown.Phase{
+		record("http", func(ctx context.Context) error { return nil }),
+		record("refresh", func(ctx context.Context) error {
+			// A write finishing during shutdown must still reach the database
+			_, err := feeds.Create(ctx, model.Feed{Title: "Feed", URL: "https://example.com/feed.xml"})
+			return err
+		}),
+		record("writes", writes.Wait),
+		record("database", func(ctx context.Context) error { return db.Close() }),
+	}
+
+	sigCh := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	go func() {
+		shutdown.OnSignal(sigCh, phases)
+		close(done)
+	}()
+	sigCh <- syscall.SIGTERM
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("shutdown did not finish")
+	}
+	require.Equal(t, []string{"http", "refresh", "writes", "database"}, order)
+	require.Error(t, db.Ping())
+}
+
+func TestRun_ContinuesAfterTimeoutAndFailure(t *testing.T) {
+	var ran []string
+	phases := []shutdown.Phase{
+		{Name: "stuck", Timeout: 20 * time.Millisecond, Run: func(ctx context.Context) error {
+			select {} // ignores ctx
+		}},
+		{Name: "failing", Timeout: time.Second, Run: func(ctx context.Context) error {
+			ran = append(ran, "failing")
+			return errors.New("boom")
+		}},
+		{Name: "last", Timeout: time.Second, Run: func(ctx context.Context) error {
+			ran = append(ran, "last")
+			return nil
+		}},
+	}
+
+	start := time.Now()
+	shutdown.Run(phases)
+
+	require.Equal(t, []string{"failing", "last"}, ran)
+	require.Less(t, time.Since(start), time.Second)
+}
@@ -23,3 +23,193 @@ func StripFragment(raw string) string {
 	}
 	return trimmed
 }
+
+// trackingParams are query parameters that never change the feed content.
+var trackingParams = map[string]struct{}{
+	"fbclid": {},
+	"gclid":  {},
+	"ref":    {},
+}
+
+// NormalizeForHash extends StripFragment for entry identity: it also drops
+// tracking parameters (utm_*, fbclid, gclid, ref) and sorts the remaining query,
+// so links that only differ by volatile parts hash the same.
+func NormalizeForHash(raw string) string {
+	stripped := StripFragment(raw)
+	parsed, err := url.Parse(stripped)
+	if err != nil || parsed.RawQuery == "" {
+		return stripped
+	}
+	parsed.RawQuery = removeTrackingParams(parsed.Query()).Encode()
+	return parsed.String()
+}
+
+// HasVolatileParts reports whether NormalizeForHash would drop anything from
+// raw: a fragment or a tracking parameter.
+func HasVolatileParts(raw string) bool {
+	parsed, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return strings.Contains(raw, "#")
+	}
+	if parsed.Fragment != "" {
+		return true
+	}
+	query := parsed.Query()
+	before := len(query)
+	return len(removeTrackingParams(query)) != before
+}
+
+// redirectParams are query parameters tracking wrappers put the real link in.
+var redirectParams = []string{"u", "url", "target"}
+
+// UnwrapRedirect returns the link a tracking redirect points to, such as
+// https://tracking.example/r/abc?u=https://example.com/post, or raw when the
+// query carries no absolute http(s) URL in u, url or target.
+func UnwrapRedirect(raw string) string {
+	trimmed := strings.TrimSpace(raw)
+	parsed, err := url.Parse(trimmed)
+	if err != nil || parsed.RawQuery == "" {
+		return trimmed
+	}
+	query := parsed.Query()
+	for _, key := range redirectParams {
+		inner, err := url.Parse(strings.TrimSpace(query.Get(key)))
+		if err != nil || inner.Host == "" {
+			continue
+		}
+		if scheme := strings.ToLower(inner.Scheme); scheme == "http" || scheme == "https" {
+			return inner.String()
+		}
+	}
+	return trimmed
+}
+
+// CanonicalFeedURL normalizes a feed URL for duplicate detection: it lowercases
+// scheme and host, strips default ports, fragments, tracking parameters (utm_*,
+// fbclid, gclid, ref) and trailing slashes, and sorts the remaining query.
+// Unparseable input is returned trimmed.
+func CanonicalFeedURL(raw string) string {
+	trimmed := strings.TrimSpace(raw)
+	parsed, err := url.Parse(trimmed)
+	if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+		return trimmed
+	}
+
+	scheme := strings.ToLower(parsed.Scheme)
+	query := removeTrackingParams(parsed.Query())
+
+	canonical := url.URL{
+		Scheme:   scheme,
+		User:     parsed.User,
+		Host:     canonicalHost(scheme, parsed),
+		Path:     strings.TrimRight(parsed.Path, "/"),
+		RawQuery: query.Encode(),
+	}
+	return canonical.String()
+}
+
+// entryTrackingParams are dropped by CanonicalEntryURL on top of the ones
+// NormalizeForHash drops, which stay as they are so stored hashes hold.
+var entryTrackingParams = map[string]struct{}{
+	"_hsenc":  {},
+	"_hsmi":   {},
+	"amp":     {},
+	"igshid":  {},
+	"mc_cid":  {},
+	"mc_eid":  {},
+	"mkt_tok": {},
+	"msclkid": {},
+	"yclid":   {},
+}
+
+// mobileHostPrefixes are subdomains publishers serve mobile and AMP copies of
+// their articles under.
+var mobileHostPrefixes = []string{"m.", "amp."}
+
+// CanonicalEntryURL normalizes an article link so the copies a publisher
+// serves under several URLs compare equal across feeds: on top of what
+// CanonicalFeedURL does, it drops more tracking parameters, the m. and amp.
+// subdomains, and /amp at either end of the path. It is empty for anything but
+// absolute http(s) URLs.
+func CanonicalEntryURL(raw string) string {
+	parsed, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || parsed.Host == "" {
+		return ""
+	}
+	scheme := strings.ToLower(parsed.Scheme)
+	if scheme != "http" && scheme != "https" {
+		return ""
+	}
+
+	host := canonicalHost(scheme, parsed)
+	for _, prefix := range mobileHostPrefixes {
+		// m.co is a whole domain, not a subdomain of co
+		if rest, ok := strings.CutPrefix(host, prefix); ok && strings.Contains(rest, ".") {
+			host = rest
+			break
+		}
+	}
+
+	path := strings.TrimRight(parsed.Path, "/")
+	path = strings.TrimSuffix(path, "/amp")
+	if rest, ok := strings.CutPrefix(path, "/amp/"); ok {
+		path = "/" + rest
+	}
+
+	query := removeTrackingParams(parsed.Query())
+	for key := range query {
+		if _, ok := entryTrackingParams[strings.ToLower(key)]; ok {
+			query.Del(key)
+		}
+	}
+
+	canonical := url.URL{
+		Scheme:   scheme,
+		User:     parsed.User,
+		Host:     host,
+		Path:     strings.TrimRight(path, "/"),
+		RawQuery: query.Encode(),
+	}
+	return canonical.String()
+}
+
+// CanonicalSiteURL reduces a feed's site URL to host and path so the feeds of
+// one site compare equal: scheme, www., query, fragment and trailing slashes
+// are dropped. It is empty for anything but absolute http(s) URLs.
+func CanonicalSiteURL(raw string) string {
+	parsed, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || parsed.Host == "" {
+		return ""
+	}
+	scheme := strings.ToLower(parsed.Scheme)
+	if scheme != "http" && scheme != "https" {
+		return ""
+	}
+	host := strings.ToLower(parsed.Hostname())
+	if rest, ok := strings.CutPrefix(host, "www."); ok && strings.Contains(rest, ".") {
+		host = rest
+	}
+	return host + strings.TrimRight(parsed.EscapedPath(), "/")
+}
+
+// canonicalHost lowercases the host of parsed and drops the default port of scheme.
+func canonicalHost(scheme string, parsed *url.URL) string {
+	host := strings.ToLower(parsed.Hostname())
+	if strings.Contains(host, ":") {
+		host = "[" + host + "]" // IPv6 literal
+	}
+	if port := parsed.Port(); port != "" && !(scheme == "http" && port == "80") && !(scheme == "https" && port == "443") {
+		host += ":" + port
+	}
+	return host
+}
+
+func removeTrackingParams(query url.Values) url.Values {
+	for key := range query {
+		lower := strings.ToLower(key)
+		if _, ok := trackingParams[lower]; ok || strings.HasPrefix(lower, "utm_") {
+			query.Del(key)
+		}
+	}
+	return query
+}
@@ -0,0 +1,144 @@
+package urlutil_test
+
+import (
+	"testing"
+
+	"gist/backend/internal/urlutil"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanonicalFeedURL(t *testing.T) {
+	cases := []struct {
+		in   string
+		want string
+	}{
+		{in: "https://example.com/feed", want: "https://example.com/feed"},
+		{in: " HTTPS://Example.COM/feed/ ", want: "https://example.com/feed"},
+		{in: "https://example.com:443/feed", want: "https://example.com/feed"},
+		{in: "http://example.com:80/feed", want: "http://example.com/feed"},
+		{in: "http://example.com:8080/feed", want: "http://example.com:8080/feed"},
+		{in: "https://example.com/feed?utm_source=rss&utm_Medium=x", want: "https://example.com/feed"},
+		{in: "https://example.com/feed?fbclid=abc&ref=hn&page=2", want: "https://example.com/feed?page=2"},
+		{in: "https://example.com/feed?b=2&a=1", want: "https://example.com/feed?a=1&b=2"},
+		{in: "https://example.com/feed#top", want: "https://example.com/feed"},
+		{in: "https://example.com/", want: "https://example.com"},
+		{in: "http://[::1]:8080/rss", want: "http://[::1]:8080/rss"},
+		{in: "not a url", want: "not a url"},
+	}
+
+	for _, tc := range cases {
+		require.Equal(t, tc.want, urlutil.CanonicalFeedURL(tc.in), tc.in)
+	}
+}
+
+func TestCanonicalEntryURL(t *testing.T) {
+	cases := []struct {
+		in   string
+		want string
+	}{
+		{in: "https://example.com/x", want: "https://example.com/x"},
+		{in: "https://m.example.com/x", want: "https://example.com/x"},
+		{in: "https://amp.example.com/x/", want: "https://example.com/x"},
+		{in: "https://example.com/amp/x", want: "https://example.com/x"},
+		{in: "https://example.com/x/amp/", want: "https://example.com/x"},
+		{in: "https://example.com/x?utm_campaign=rss", want: "https://example.com/x"},
+		{in: "https://example.com/x?amp=1&id=2&mc_cid=abc", want: "https://example.com/x?id=2"},
+		{in: " HTTPS://Example.COM:443/x#comments ", want: "https://example.com/x"},
+		{in: "http://example.com:8080/x", want: "http://example.com:8080/x"},
+		// Only whole labels and path segments are rules
+		{in: "https://m.co/x", want: "https://m.co/x"},
+		{in: "https://mail.example.com/x", want: "https://mail.example.com/x"},
+		{in: "https://example.com/amplifier", want: "https://example.com/amplifier"},
+		{in: "https://example.com/x/amp/comments", want: "https://example.com/x/amp/comments"},
+		// Case is kept outside scheme and host
+		{in: "https://example.com/Post", want: "https://example.com/Post"},
+		{in: "mailto:someone@example.com", want: ""},
+		{in: "/relative/path", want: ""},
+		{in: "  ", want: ""},
+	}
+
+	for _, tc := range cases {
+		require.Equal(t, tc.want, urlutil.CanonicalEntryURL(tc.in), tc.in)
+	}
+}
+
+func TestCanonicalSiteURL(t *testing.T) {
+	cases := []struct {
+		in   string
+		want string
+	}{
+		{in: "https://example.com", want: "example.com"},
+		{in: "http://www.Example.com/", want: "example.com"},
+		{in: "https://example.com:8443/blog/?ref=rss#top", want: "example.com/blog"},
+		{in: "https://blog.example.com/", want: "blog.example.com"},
+		{in: "https://www.co", want: "www.co"},
+		{in: "ftp://example.com", want: ""},
+		{in: "/relative", want: ""},
+		{in: "", want: ""},
+	}
+
+	for _, tc := range cases {
+		require.Equal(t, tc.want, urlutil.CanonicalSiteURL(tc.in), tc.in)
+	}
+}
+
+func TestStripFragment(t *testing.T) {
+	require.Equal(t, "https://example.com/post?id=1", urlutil.StripFragment("https://example.com/post?id=1#reply"))
+	require.Equal(t, "", urlutil.StripFragment("  "))
+}
+
+func TestNormalizeForHash(t *testing.T) {
+	cases := []struct {
+		in   string
+		want string
+	}{
+		{in: "https://www.v2ex.com/t/1193191#reply20", want: "https://www.v2ex.com/t/1193191"},
+		{in: " https://example.com/post?b=2&a=1#top ", want: "https://example.com/post?a=1&b=2"},
+		{in: "https://example.com/post?id=1&utm_source=rss&fbclid=x", want: "https://example.com/post?id=1"},
+		{in: "https://example.com/post?utm_source=rss", want: "https://example.com/post"},
+		{in: "https://Example.com/Post/", want: "https://Example.com/Post/"},
+		{in: "  ", want: ""},
+	}
+
+	for _, tc := range cases {
+		require.Equal(t, tc.want, urlutil.NormalizeForHash(tc.in), tc.in)
+	}
+}
+
+func TestHasVolatileParts(t *testing.T) {
+	cases := []struct {
+		in   string
+		want bool
+	}{
+		{in: "https://example.com/?p=123", want: false},
+		{in: "https://example.com/post?b=2&a=1", want: false},
+		{in: "https://www.v2ex.com/t/1193191#reply10", want: true},
+		{in: "https://example.com/post?utm_source=rss", want: true},
+		{in: "https://example.com/post?id=1&fbclid=abc", want: true},
+		{in: "tag:example.com,2024:post-1", want: false},
+		{in: "", want: false},
+	}
+
+	for _, tc := range cases {
+		require.Equal(t, tc.want, urlutil.HasVolatileParts(tc.in), tc.in)
+	}
+}
+
+func TestUnwrapRedirect(t *testing.T) {
+	cases := []struct {
+		in   string
+		want string
+	}{
+		{in: "https://tracking.example/r/abc?u=https%3A%2F%2Fexample.com%2Fpost%3Fid%3D1", want: "https://example.com/post?id=1"},
+		{in: "https://tracking.example/r/abc?url=http://example.com/post", want: "http://example.com/post"},
+		{in: "https://tracking.example/click?id=9&target=https://example.com/a", want: "https://example.com/a"},
+		{in: "https://example.com/search?u=alice", want: "https://example.com/search?u=alice"},
+		{in: "https://example.com/r?u=javascript:alert(1)", want: "https://example.com/r?u=javascript:alert(1)"},
+		{in: " https://example.com/post ", want: "https://example.com/post"},
+	}
+
+	for _, tc := range cases {
+		require.Equal(t, tc.want, urlutil.UnwrapRedirect(tc.in), tc.in)
+	}
+}
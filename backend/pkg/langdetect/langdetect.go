@@ -0,0 +1,125 @@
+// Package langdetect guesses the language of short texts from their script and,
+// for Latin text, from common trigrams.
+package langdetect
+
+import (
+	"strings"
+	"unicode"
+)
+
+// minLatinScore is the trigram hits the best Latin language needs before it is
+// trusted.
+const minLatinScore = 3
+
+// latinTrigrams are frequent trigrams of each Latin language; a space marks a
+// word boundary.
+var latinTrigrams = map[string][]string{
+	"en": {" th", "the", "he ", " an", "and", "nd ", " of", "of ", " to", "to ", "ing", "ng ", " is", "is ", "ed ", "at ", " fo", "for", "hat", "tha", " wi", "wit", "ith", " be", "ere", "ly ", "ou ", "you", " yo", "his"},
+	"fr": {" le", "le ", " la", "les", " et", "et ", " qu", "que", "ue ", " pa", "our", " po", "des", " de", "es ", " un", "une", "ait", " du", "du ", "est", " es", "ons", "ur ", "eme", " ce", "ce ", "ans", " da", "ux "},
+	"de": {"er ", " de", "der", "ie ", "die", " di", "ich", "ein", "sch", "che", " un", "und", "den", " ei", "cht", "gen", "ung", "ine", " ge", "ht ", "ist", " is", "mit", " mi", " zu", "zu ", "das", " da", "ber", "auf"},
+	"es": {" de", "de ", "os ", " la", "la ", "el ", " el", " qu", "que", "ue ", " en", "en ", "as ", "ión", "ció", "aci", " lo", "los", "ado", " se", " po", "por", "con", "del", "ara", " es", "est", "ien", " y ", "una"},
+	"pt": {" de", "de ", "os ", "ão ", "ção", " qu", "que", "ue ", " do", "do ", "da ", " da", "em ", " em", "as ", " pa", "ara", "com", " co", " se", "ar ", " um", "um ", "uma", "ões", "çõe", "não", " nã", "ado", " é "},
+	"it": {" di", "di ", "to ", " la", "la ", "re ", " il", "il ", "che", " ch", "he ", "one", "ell", "lla", "zio", "ion", " de", "del", " pe", "per", "er ", "ato", " co", "con", " un", "are", " in", "nto", "no ", "gli"},
+}
+
+// Normalize reduces a language tag such as "en-US" or "pt_BR" to its lowercase
+// primary subtag, or "" when tag is not a language tag.
+func Normalize(tag string) string {
+	tag = strings.TrimSpace(tag)
+	if i := strings.IndexAny(tag, "-_"); i >= 0 {
+		tag = tag[:i]
+	}
+	if len(tag) < 2 || len(tag) > 3 {
+		return ""
+	}
+	for _, r := range tag {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z') {
+			return ""
+		}
+	}
+	return strings.ToLower(tag)
+}
+
+// Detect guesses the primary subtag of text's language: ko, ja, zh, ru, uk and
+// ar by script, and en, fr, de, es, pt and it by trigrams. It returns "" when
+// text is too short or no language stands out.
+func Detect(text string) string {
+	var latin, han, kana, hangul, cyrillic, ukrainian, arabic int
+	for _, r := range text {
+		switch {
+		case unicode.Is(unicode.Hangul, r):
+			hangul++
+		case unicode.In(r, unicode.Hiragana, unicode.Katakana):
+			kana++
+		case unicode.Is(unicode.Han, r):
+			han++
+		case unicode.Is(unicode.Cyrillic, r):
+			cyrillic++
+			if strings.ContainsRune("іїєґІЇЄҐ", r) {
+				ukrainian++
+			}
+		case unicode.Is(unicode.Arabic, r):
+			arabic++
+		case unicode.Is(unicode.Latin, r):
+			latin++
+		}
+	}
+
+	cjk := han + kana
+	switch top := max(latin, cjk, hangul, cyrillic, arabic); {
+	case top < 3:
+		return ""
+	case top == hangul:
+		return "ko"
+	case top == cjk && kana > 0:
+		return "ja"
+	case top == cjk:
+		return "zh"
+	case top == cyrillic && ukrainian > 0:
+		return "uk"
+	case top == cyrillic:
+		return "ru"
+	case top == arabic:
+		return "ar"
+	}
+	return detectLatin(text)
+}
+
+// detectLatin scores text against each language's trigrams and returns the best
+// one when it clearly beats the rest.
+func detectLatin(text string) string {
+	var b strings.Builder
+	b.WriteByte(' ')
+	space := true
+	for _, r := range strings.ToLower(text) {
+		if unicode.IsLetter(r) {
+			b.WriteRune(r)
+			space = false
+		} else if !space {
+			b.WriteByte(' ')
+			space = true
+		}
+	}
+	if !space {
+		b.WriteByte(' ')
+	}
+	normalized := b.String()
+
+	best, bestScore, runnerUp := "", 0, 0
+	for lang, trigrams := range latinTrigrams {
+		score := 0
+		for _, trigram := range trigrams {
+			score += strings.Count(normalized, trigram)
+		}
+		switch {
+		case score > bestScore:
+			best, bestScore, runnerUp = lang, score, bestScore
+		case score > runnerUp:
+			runnerUp = score
+		}
+	}
+	if bestScore < minLatinScore || bestScore == runnerUp {
+		return ""
+	}
+	return best
+}
@@ -0,0 +1,55 @@
+package langdetect_test
+
+import (
+	"testing"
+
+	"gist/backend/pkg/langdetect"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalize(t *testing.T) {
+	cases := map[string]string{
+		"en":      "en",
+		"en-US":   "en",
+		"pt_BR":   "pt",
+		" ZH-tw ": "zh",
+		"fil":     "fil",
+		"":        "",
+		"e":       "",
+		"english": "",
+		"1234":    "",
+	}
+	for in, want := range cases {
+		require.Equal(t, want, langdetect.Normalize(in), in)
+	}
+}
+
+func TestDetect(t *testing.T) {
+	cases := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{name: "empty", in: "", want: ""},
+		{name: "too short", in: "Go", want: ""},
+		{name: "chinese", in: "今天的天气非常好", want: "zh"},
+		{name: "japanese", in: "今日はいい天気ですね", want: "ja"},
+		{name: "korean", in: "오늘 날씨가 정말 좋네요", want: "ko"},
+		{name: "russian", in: "Сегодня очень хорошая погода", want: "ru"},
+		{name: "ukrainian", in: "Сьогодні дуже гарна погода, і ми їдемо", want: "uk"},
+		{name: "arabic", in: "الطقس جميل جدا اليوم", want: "ar"},
+		{name: "english", in: "The weather is nice and the sun is shining for all of us today", want: "en"},
+		{name: "french", in: "Le temps est magnifique et les enfants jouent dans le parc avec une balle", want: "fr"},
+		{name: "german", in: "Das Wetter ist heute schön und die Kinder spielen mit dem Ball auf der Wiese", want: "de"},
+		{name: "spanish", in: "El tiempo es muy bueno y los niños juegan en el parque con la pelota", want: "es"},
+		{name: "portuguese", in: "O tempo está ótimo e as crianças brincam no parque com uma bola, não é", want: "pt"},
+		{name: "italian", in: "Il tempo è bellissimo e i bambini giocano nel parco con la palla della scuola", want: "it"},
+		{name: "latin without signal", in: "Kubernetes v1.31 RC", want: ""},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.want, langdetect.Detect(tc.in))
+		})
+	}
+}
@@ -0,0 +1,100 @@
+// Package linediff computes a simple line-based diff using the longest common subsequence.
+package linediff
+
+// Op is the kind of change for a diff line.
+type Op string
+
+const (
+	Equal  Op = "equal"
+	Insert Op = "insert"
+	Delete Op = "delete"
+)
+
+// maxCells bounds the LCS table size. Inputs beyond it are reported as a full replacement.
+const maxCells = 1 << 20
+
+// Line is a single line of diff output.
+type Line struct {
+	Op   Op
+	Text string
+}
+
+// Diff returns the edit script turning a into b.
+func Diff(a, b []string) []Line {
+	// Trim the common prefix and suffix so the LCS table only covers the changed region
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	result := make([]Line, 0, len(a)+len(b))
+	for _, text := range a[:prefix] {
+		result = append(result, Line{Op: Equal, Text: text})
+	}
+	result = append(result, diffMiddle(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for _, text := range a[len(a)-suffix:] {
+		result = append(result, Line{Op: Equal, Text: text})
+	}
+	return result
+}
+
+func diffMiddle(a, b []string) []Line {
+	n, m := len(a), len(b)
+	if n == 0 || m == 0 || (n+1)*(m+1) > maxCells {
+		return replaceAll(a, b)
+	}
+
+	// lcs[i][j] is the LCS length of a[i:] and b[j:]
+	width := m + 1
+	lcs := make([]int, (n+1)*width)
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i*width+j] = lcs[(i+1)*width+j+1] + 1
+			} else if lcs[(i+1)*width+j] >= lcs[i*width+j+1] {
+				lcs[i*width+j] = lcs[(i+1)*width+j]
+			} else {
+				lcs[i*width+j] = lcs[i*width+j+1]
+			}
+		}
+	}
+
+	result := make([]Line, 0, n+m)
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case a[i] == b[j]:
+			result = append(result, Line{Op: Equal, Text: a[i]})
+			i++
+			j++
+		case lcs[(i+1)*width+j] >= lcs[i*width+j+1]:
+			result = append(result, Line{Op: Delete, Text: a[i]})
+			i++
+		default:
+			result = append(result, Line{Op: Insert, Text: b[j]})
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		result = append(result, Line{Op: Delete, Text: a[i]})
+	}
+	for ; j < m; j++ {
+		result = append(result, Line{Op: Insert, Text: b[j]})
+	}
+	return result
+}
+
+func replaceAll(a, b []string) []Line {
+	result := make([]Line, 0, len(a)+len(b))
+	for _, text := range a {
+		result = append(result, Line{Op: Delete, Text: text})
+	}
+	for _, text := range b {
+		result = append(result, Line{Op: Insert, Text: text})
+	}
+	return result
+}
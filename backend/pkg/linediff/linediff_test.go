@@ -0,0 +1,47 @@
+package linediff_test
+
+import (
+	"testing"
+
+	"gist/backend/pkg/linediff"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff_Identical(t *testing.T) {
+	lines := linediff.Diff([]string{"a", "b"}, []string{"a", "b"})
+	require.Equal(t, []linediff.Line{
+		{Op: linediff.Equal, Text: "a"},
+		{Op: linediff.Equal, Text: "b"},
+	}, lines)
+}
+
+func TestDiff_ChangedLine(t *testing.T) {
+	lines := linediff.Diff(
+		[]string{"title", "old sentence", "footer"},
+		[]string{"title", "new sentence", "added", "footer"},
+	)
+	require.Equal(t, []linediff.Line{
+		{Op: linediff.Equal, Text: "title"},
+		{Op: linediff.Delete, Text: "old sentence"},
+		{Op: linediff.Insert, Text: "new sentence"},
+		{Op: linediff.Insert, Text: "added"},
+		{Op: linediff.Equal, Text: "footer"},
+	}, lines)
+}
+
+func TestDiff_KeepsCommonLinesInMiddle(t *testing.T) {
+	lines := linediff.Diff([]string{"x", "keep", "y"}, []string{"keep", "z"})
+	require.Equal(t, []linediff.Line{
+		{Op: linediff.Delete, Text: "x"},
+		{Op: linediff.Equal, Text: "keep"},
+		{Op: linediff.Delete, Text: "y"},
+		{Op: linediff.Insert, Text: "z"},
+	}, lines)
+}
+
+func TestDiff_Empty(t *testing.T) {
+	require.Empty(t, linediff.Diff(nil, nil))
+	require.Equal(t, []linediff.Line{{Op: linediff.Insert, Text: "a"}}, linediff.Diff(nil, []string{"a"}))
+	require.Equal(t, []linediff.Line{{Op: linediff.Delete, Text: "a"}}, linediff.Diff([]string{"a"}, nil))
+}
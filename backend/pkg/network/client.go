@@ -2,10 +2,13 @@ package network
 
 import (
 	"context"
+	"encoding/json"
+	"io"
 	"net"
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/Noooste/azuretls-client"
@@ -25,12 +28,29 @@ type IPStackProvider interface {
 	GetIPStack(ctx context.Context) string
 }
 
+// settingsTTL bounds how long a proxy/IP stack read is reused before the
+// providers are consulted again. Invalidate skips the wait after a change.
+const settingsTTL = 30 * time.Second
+
 // ClientFactory creates HTTP clients with proxy configuration.
+// Settings are read when a client is created, so changes apply without a restart.
 type ClientFactory struct {
 	proxyProvider   ProxyProvider
 	ipStackProvider IPStackProvider
 	testTransport   http.RoundTripper // For testing only
 	testHTTPClient  *http.Client      // For testing only
+
+	mu       sync.Mutex
+	cached   *networkConfig
+	cachedAt time.Time
+
+	healthMu sync.Mutex
+	health   *proxyHealth
+}
+
+type networkConfig struct {
+	proxyURL string
+	ipStack  string
 }
 
 // NewClientFactory creates a new client factory.
@@ -75,9 +95,8 @@ func (f *ClientFactory) NewHTTPClient(ctx context.Context, timeout time.Duration
 		return client
 	}
 
-	proxyURL := f.proxyProvider.GetProxyURL(ctx)
-	ipStack := f.getIPStack(ctx)
-	client.Transport = f.newTransport(proxyURL, ipStack)
+	cfg := f.config(ctx)
+	client.Transport = f.newTransport(cfg.proxyURL, cfg.ipStack)
 
 	return client
 }
@@ -88,9 +107,8 @@ func (f *ClientFactory) NewAzureSession(ctx context.Context, timeout time.Durati
 	session.Browser = azuretls.Chrome
 	session.SetTimeout(timeout)
 
-	proxyURL := f.proxyProvider.GetProxyURL(ctx)
-	if proxyURL != "" {
-		_ = session.SetProxy(proxyURL)
+	if proxyURL := f.config(ctx).proxyURL; proxyURL != "" {
+		_ = session.SetProxy(remoteDNSProxyURL(proxyURL))
 	}
 
 	return session
@@ -98,7 +116,19 @@ func (f *ClientFactory) NewAzureSession(ctx context.Context, timeout time.Durati
 
 // GetProxyURL returns the current proxy URL.
 func (f *ClientFactory) GetProxyURL(ctx context.Context) string {
-	return f.proxyProvider.GetProxyURL(ctx)
+	return f.config(ctx).proxyURL
+}
+
+// Invalidate drops the cached network settings and proxy health so the next
+// client picks up changes.
+func (f *ClientFactory) Invalidate() {
+	f.mu.Lock()
+	f.cached = nil
+	f.mu.Unlock()
+
+	f.healthMu.Lock()
+	f.health = nil
+	f.healthMu.Unlock()
 }
 
 // TestProxy tests if the proxy is working by making a request to the given URL.
@@ -122,29 +152,84 @@ func (f *ClientFactory) TestProxy(ctx context.Context, testURL string) error {
 // NewHTTPTransport creates an http.Transport with proxy configuration.
 // This is useful when you need to customize the http.Client (e.g., CheckRedirect).
 func (f *ClientFactory) NewHTTPTransport(ctx context.Context) *http.Transport {
-	proxyURL := f.proxyProvider.GetProxyURL(ctx)
-	ipStack := f.getIPStack(ctx)
-	return f.newTransport(proxyURL, ipStack)
+	cfg := f.config(ctx)
+	return f.newTransport(cfg.proxyURL, cfg.ipStack)
 }
 
+// ProxyTestResult reports a successful proxy test request.
+type ProxyTestResult struct {
+	Latency time.Duration
+	// EgressIP is the address the test URL saw, when it answers with one
+	// (a bare IP, or JSON with an "ip" or "origin" field). Empty otherwise.
+	EgressIP string
+}
+
+// maxEgressBody bounds how much of the test response is read for the egress IP.
+const maxEgressBody = 4 << 10
+
 // TestProxyWithConfig tests a proxy configuration without saving it.
-func (f *ClientFactory) TestProxyWithConfig(ctx context.Context, proxyURL, testURL string) error {
-	ipStack := f.getIPStack(ctx)
+func (f *ClientFactory) TestProxyWithConfig(ctx context.Context, proxyURL, testURL string) (ProxyTestResult, error) {
+	ipStack := f.config(ctx).ipStack
 	client := &http.Client{Timeout: 10 * time.Second}
 	client.Transport = f.newTransport(proxyURL, ipStack)
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, testURL, nil)
 	if err != nil {
-		return err
+		return ProxyTestResult{}, err
 	}
 
+	start := time.Now()
 	resp, err := client.Do(req)
 	if err != nil {
-		return err
+		return ProxyTestResult{}, err
 	}
 	defer resp.Body.Close()
+	result := ProxyTestResult{Latency: time.Since(start)}
 
-	return nil
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxEgressBody))
+	if err == nil {
+		result.EgressIP = parseEgressIP(body)
+	}
+	return result, nil
+}
+
+// parseEgressIP extracts the client address an IP echo service reports.
+func parseEgressIP(body []byte) string {
+	text := strings.TrimSpace(string(body))
+	if ip := net.ParseIP(text); ip != nil {
+		return ip.String()
+	}
+
+	var fields struct {
+		IP     string `json:"ip"`
+		Origin string `json:"origin"`
+	}
+	if json.Unmarshal(body, &fields) != nil {
+		return ""
+	}
+	for _, candidate := range []string{fields.IP, fields.Origin} {
+		// httpbin lists every hop in origin; the first is the client
+		candidate, _, _ = strings.Cut(candidate, ",")
+		if ip := net.ParseIP(strings.TrimSpace(candidate)); ip != nil {
+			return ip.String()
+		}
+	}
+	return ""
+}
+
+// config returns the current proxy URL and IP stack, reading the providers
+// at most once per settingsTTL unless Invalidate is called.
+func (f *ClientFactory) config(ctx context.Context) networkConfig {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.cached != nil && time.Since(f.cachedAt) < settingsTTL {
+		return *f.cached
+	}
+
+	cfg := networkConfig{proxyURL: f.proxyProvider.GetProxyURL(ctx), ipStack: f.getIPStack(ctx)}
+	f.cached = &cfg
+	f.cachedAt = time.Now()
+	return cfg
 }
 
 // getIPStack returns the IP stack preference, defaulting to "default".
@@ -186,11 +271,15 @@ func (f *ClientFactory) newTransport(proxyURL, ipStack string) *http.Transport {
 		if err != nil {
 			return newOneShotTransport(dialFunc, nil)
 		}
+		contextDialer, ok := dialer.(proxy.ContextDialer)
+		if !ok {
+			return newOneShotTransport(dialFunc, nil)
+		}
 
-		// Create transport with SOCKS5 dialer
-		return newOneShotTransport(func(ctx context.Context, network, addr string) (net.Conn, error) {
-			return dialer.Dial(network, addr)
-		}, nil)
+		// The transport hands over host:port as written in the URL, and the
+		// dialer sends names unresolved, so the proxy does the DNS lookup
+		// (socks5h semantics) and nothing resolves locally.
+		return newOneShotTransport(contextDialer.DialContext, nil)
 	}
 
 	// For HTTP/HTTPS proxies, use standard http.ProxyURL with custom dial
@@ -211,7 +300,21 @@ type ipStackDialer struct {
 }
 
 func (d *ipStackDialer) Dial(network, addr string) (net.Conn, error) {
-	return dialWithIPStack(context.Background(), network, addr, d.ipStack)
+	return d.DialContext(context.Background(), network, addr)
+}
+
+// DialContext lets the SOCKS5 dialer abort the connection to the proxy with the request.
+func (d *ipStackDialer) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	return dialWithIPStack(ctx, network, addr, d.ipStack)
+}
+
+// remoteDNSProxyURL switches socks5 proxy URLs to socks5h, which asks
+// clients to leave hostname resolution to the proxy.
+func remoteDNSProxyURL(proxyURL string) string {
+	if rest, ok := strings.CutPrefix(proxyURL, "socks5://"); ok {
+		return "socks5h://" + rest
+	}
+	return proxyURL
 }
 
 // makeDialFunc creates a DialContext function with IP stack preference.
@@ -60,7 +60,7 @@ func TestClientFactory_TestProxy(t *testing.T) {
 
 func TestClientFactory_TestProxyWithConfig(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("203.0.113.7\n"))
 	}))
 	defer server.Close()
 
@@ -68,8 +68,26 @@ func TestClientFactory_TestProxyWithConfig(t *testing.T) {
 	factory := NewClientFactory(provider, provider)
 	ctx := context.Background()
 
-	err := factory.TestProxyWithConfig(ctx, "", server.URL)
+	result, err := factory.TestProxyWithConfig(ctx, "", server.URL)
 	require.NoError(t, err)
+	require.Positive(t, result.Latency)
+	require.Equal(t, "203.0.113.7", result.EgressIP)
+}
+
+func TestParseEgressIP(t *testing.T) {
+	tests := []struct {
+		body string
+		want string
+	}{
+		{body: " 2001:db8::1\n", want: "2001:db8::1"},
+		{body: `{"ip":"203.0.113.7"}`, want: "203.0.113.7"},
+		{body: `{"origin":"203.0.113.7, 10.0.0.1"}`, want: "203.0.113.7"},
+		{body: "<HTML><BODY>Success</BODY></HTML>", want: ""},
+		{body: `{"ip":"not an ip"}`, want: ""},
+	}
+	for _, tt := range tests {
+		require.Equal(t, tt.want, parseEgressIP([]byte(tt.body)), tt.body)
+	}
 }
 
 func TestClientFactory_GetProxyURL(t *testing.T) {
@@ -94,6 +112,26 @@ func TestClientFactory_NewHTTPTransport_Proxy(t *testing.T) {
 	require.Equal(t, "http://proxy.local:8080", pu.String())
 }
 
+func TestClientFactory_CachesSettingsUntilInvalidated(t *testing.T) {
+	provider := &mockProvider{proxyURL: "http://old.proxy:8080", ipStack: "default"}
+	factory := NewClientFactory(provider, provider)
+	ctx := context.Background()
+	req := httptest.NewRequest(http.MethodGet, "http://example.com", nil)
+
+	pu, err := factory.NewHTTPClient(ctx, time.Second).Transport.(*http.Transport).Proxy(req)
+	require.NoError(t, err)
+	require.Equal(t, "http://old.proxy:8080", pu.String())
+
+	// Within the TTL the cached value is reused
+	provider.proxyURL = "http://new.proxy:3128"
+	require.Equal(t, "http://old.proxy:8080", factory.GetProxyURL(ctx))
+
+	factory.Invalidate()
+	pu, err = factory.NewHTTPClient(ctx, time.Second).Transport.(*http.Transport).Proxy(req)
+	require.NoError(t, err)
+	require.Equal(t, "http://new.proxy:3128", pu.String())
+}
+
 func TestClientFactory_NewHTTPTransport_InvalidProxy(t *testing.T) {
 	provider := &mockProvider{proxyURL: "://bad", ipStack: "default"}
 	factory := NewClientFactory(provider, provider)
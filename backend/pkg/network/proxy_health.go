@@ -0,0 +1,65 @@
+package network
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"time"
+
+	"gist/backend/pkg/logger"
+)
+
+// ErrProxyUnreachable reports that the configured proxy does not accept connections.
+var ErrProxyUnreachable = errors.New("proxy unreachable")
+
+const (
+	// proxyProbeTimeout bounds the TCP connect to the proxy.
+	proxyProbeTimeout = 3 * time.Second
+	// proxyHealthTTL is how long a probe result is reused, so a refresh run
+	// probes once instead of per feed.
+	proxyHealthTTL = 60 * time.Second
+)
+
+type proxyHealth struct {
+	addr      string
+	err       error
+	checkedAt time.Time
+}
+
+// CheckProxy returns an error wrapping ErrProxyUnreachable when a proxy is
+// configured but refuses TCP connections, so callers can fail fast instead of
+// waiting out a request timeout. It returns nil when no proxy is configured.
+func (f *ClientFactory) CheckProxy(ctx context.Context) error {
+	proxyURL := f.config(ctx).proxyURL
+	if proxyURL == "" {
+		return nil
+	}
+	parsed, err := url.Parse(proxyURL)
+	if err != nil || parsed.Host == "" {
+		return nil
+	}
+	addr := parsed.Host
+
+	f.healthMu.Lock()
+	defer f.healthMu.Unlock()
+	if f.health != nil && f.health.addr == addr && time.Since(f.health.checkedAt) < proxyHealthTTL {
+		return f.health.err
+	}
+
+	d := &net.Dialer{Timeout: proxyProbeTimeout}
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		if ctx.Err() != nil {
+			// The caller gave up; that says nothing about the proxy
+			return ctx.Err()
+		}
+		err = fmt.Errorf("%w: %v", ErrProxyUnreachable, err)
+		logger.Warn("proxy probe failed", "module", "network", "action", "probe", "resource", "proxy", "result", "failed", "addr", addr, "error", err)
+	} else {
+		_ = conn.Close()
+	}
+	f.health = &proxyHealth{addr: addr, err: err, checkedAt: time.Now()}
+	return err
+}
@@ -0,0 +1,116 @@
+package network
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientFactory_CheckProxy(t *testing.T) {
+	ln, err := net.Listen("tcp4", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := ln.Addr().String()
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			_ = conn.Close()
+		}
+	}()
+
+	provider := &mockProvider{proxyURL: "socks5://" + addr, ipStack: "default"}
+	factory := NewClientFactory(provider, provider)
+	ctx := context.Background()
+	require.NoError(t, factory.CheckProxy(ctx))
+
+	// The healthy result is reused even after the proxy goes away
+	require.NoError(t, ln.Close())
+	require.NoError(t, factory.CheckProxy(ctx))
+
+	factory.Invalidate()
+	err = factory.CheckProxy(ctx)
+	require.ErrorIs(t, err, ErrProxyUnreachable)
+
+	// So is the failure, without dialing again
+	checkedAt := factory.health.checkedAt
+	require.ErrorIs(t, factory.CheckProxy(ctx), ErrProxyUnreachable)
+	require.Equal(t, checkedAt, factory.health.checkedAt)
+
+	require.NoError(t, NewClientFactory(&mockProvider{ipStack: "default"}, nil).CheckProxy(ctx))
+}
+
+// serveSOCKS5Once accepts one no-auth SOCKS5 CONNECT, reports the requested
+// address type and host, then answers with a canned HTTP response.
+func serveSOCKS5Once(t *testing.T, ln net.Listener, requested chan<- string) {
+	t.Helper()
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		_ = conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+		greeting := make([]byte, 3)
+		if _, err := io.ReadFull(conn, greeting); err != nil {
+			return
+		}
+		_, _ = conn.Write([]byte{0x05, 0x00})
+
+		header := make([]byte, 4)
+		if _, err := io.ReadFull(conn, header); err != nil {
+			return
+		}
+		var host string
+		switch header[3] {
+		case 0x03:
+			size := make([]byte, 1)
+			_, _ = io.ReadFull(conn, size)
+			name := make([]byte, size[0])
+			_, _ = io.ReadFull(conn, name)
+			host = "name:" + string(name)
+		case 0x01:
+			ip := make([]byte, 4)
+			_, _ = io.ReadFull(conn, ip)
+			host = "ip:" + net.IP(ip).String()
+		}
+		port := make([]byte, 2)
+		_, _ = io.ReadFull(conn, port)
+		requested <- host
+
+		_, _ = conn.Write([]byte{0x05, 0x00, 0x00, 0x01, 127, 0, 0, 1, 0, 0})
+		_, _ = conn.Write([]byte("HTTP/1.1 200 OK\r\nContent-Length: 2\r\nConnection: close\r\n\r\nok"))
+	}()
+}
+
+func TestClientFactory_SOCKS5ResolvesThroughProxy(t *testing.T) {
+	ln, err := net.Listen("tcp4", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+	requested := make(chan string, 1)
+	serveSOCKS5Once(t, ln, requested)
+
+	provider := &mockProvider{proxyURL: "socks5://" + ln.Addr().String(), ipStack: "default"}
+	factory := NewClientFactory(provider, provider)
+	client := factory.NewHTTPClient(context.Background(), 5*time.Second)
+
+	// .invalid never resolves, so this only works if the proxy does the lookup
+	resp, err := client.Get("http://feeds.example.invalid/rss")
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "name:feeds.example.invalid", <-requested)
+}
+
+func TestRemoteDNSProxyURL(t *testing.T) {
+	require.Equal(t, "socks5h://u:p@proxy:1080", remoteDNSProxyURL("socks5://u:p@proxy:1080"))
+	require.Equal(t, "socks5h://proxy:1080", remoteDNSProxyURL("socks5h://proxy:1080"))
+	require.Equal(t, "http://proxy:8080", remoteDNSProxyURL("http://proxy:8080"))
+}
@@ -0,0 +1,269 @@
+// Package plaintext renders HTML content as plain text for text-to-speech
+// clients and AI prompts.
+package plaintext
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+)
+
+// CodePlaceholder stands in for each code block unless Options.DropCode is set.
+const CodePlaceholder = "[code omitted]"
+
+// Options controls how FromHTML renders content.
+type Options struct {
+	// DropCode removes code blocks entirely instead of replacing them with CodePlaceholder.
+	DropCode bool
+}
+
+// blockElements start and end a paragraph.
+var blockElements = map[string]bool{
+	"address": true, "article": true, "aside": true, "blockquote": true, "caption": true,
+	"dd": true, "details": true, "div": true, "dl": true, "dt": true, "fieldset": true,
+	"figcaption": true, "figure": true, "footer": true, "form": true, "h1": true, "h2": true,
+	"h3": true, "h4": true, "h5": true, "h6": true, "header": true, "hr": true, "main": true,
+	"nav": true, "p": true, "section": true, "summary": true, "table": true, "tr": true,
+}
+
+// skippedElements have content that is never read out.
+var skippedElements = map[string]bool{
+	"audio": true, "button": true, "canvas": true, "head": true, "iframe": true, "math": true,
+	"noscript": true, "object": true, "script": true, "select": true, "style": true,
+	"svg": true, "template": true, "video": true,
+}
+
+const (
+	lineBreak      = 1
+	paragraphBreak = 2
+)
+
+type list struct {
+	ordered bool
+	next    int
+	items   int
+}
+
+// writer collapses whitespace and turns pending breaks into newlines only once
+// more text follows, so the output never starts or ends with blank lines.
+type writer struct {
+	buf     strings.Builder
+	pending int
+	space   bool
+	// item is set between a list item's prefix and its first text, which stays on the prefix's line
+	item bool
+}
+
+func (w *writer) brk(level int) {
+	if w.item {
+		return
+	}
+	w.pending = max(w.pending, level)
+	w.space = false
+}
+
+func (w *writer) flush() {
+	if w.buf.Len() > 0 {
+		switch {
+		case w.pending >= paragraphBreak:
+			w.buf.WriteString("\n\n")
+		case w.pending == lineBreak:
+			w.buf.WriteByte('\n')
+		case w.space:
+			w.buf.WriteByte(' ')
+		}
+	}
+	w.pending = 0
+	w.space = false
+	w.item = false
+}
+
+// text writes s with runs of whitespace collapsed to single spaces.
+func (w *writer) text(s string) {
+	for _, r := range s {
+		if unicode.IsSpace(r) {
+			if w.pending == 0 {
+				w.space = true
+			}
+			continue
+		}
+		if w.pending > 0 || w.space || w.item {
+			w.flush()
+		}
+		w.buf.WriteRune(r)
+	}
+}
+
+// raw writes s as one unit, starting a pending break or space first.
+func (w *writer) raw(s string) {
+	w.flush()
+	w.buf.WriteString(s)
+}
+
+// FromHTML converts HTML to plain text. Blocks become paragraphs separated by a
+// blank line, list items keep their bullets or numbers, images are read as
+// "[Image: alt]" and code blocks follow opts. Invalid UTF-8 is replaced.
+//
+// Examples:
+//   - "<p>Hello <b>World</b></p><p>Bye</p>" -> "Hello World\n\nBye"
+//   - "<ol><li>One</li><li>Two</li></ol>" -> "1. One\n2. Two"
+func FromHTML(input string, opts Options) string {
+	input = strings.ToValidUTF8(input, "�")
+	tokenizer := html.NewTokenizer(strings.NewReader(input))
+	var w writer
+	var lists []*list
+	// skip names the element whose content is being skipped; nested copies of it are counted in skipDepth
+	skip, skipDepth := "", 0
+
+	for {
+		tt := tokenizer.Next()
+		// The tokenizer only fails at EOF or on read errors, which a string reader never returns
+		if tt == html.ErrorToken {
+			break
+		}
+		token := tokenizer.Token()
+		name := token.Data
+
+		if skip != "" {
+			switch {
+			case tt == html.StartTagToken && name == skip:
+				skipDepth++
+			case tt == html.EndTagToken && name == skip:
+				skipDepth--
+				if skipDepth == 0 {
+					skip = ""
+				}
+			}
+			continue
+		}
+
+		switch tt {
+		case html.TextToken:
+			w.text(token.Data)
+		case html.StartTagToken, html.SelfClosingTagToken:
+			switch {
+			case name == "pre":
+				w.brk(paragraphBreak)
+				if !opts.DropCode {
+					w.raw(CodePlaceholder)
+					w.brk(paragraphBreak)
+				}
+				if tt == html.StartTagToken {
+					skip, skipDepth = name, 1
+				}
+			case skippedElements[name]:
+				if tt == html.StartTagToken {
+					skip, skipDepth = name, 1
+				}
+			case name == "br":
+				if !w.item {
+					w.pending = min(w.pending+1, paragraphBreak)
+					w.space = false
+				}
+			case name == "img":
+				if alt := strings.Join(strings.Fields(attr(token, "alt")), " "); alt != "" {
+					w.space = true
+					w.raw("[Image: " + alt + "]")
+					w.space = true
+				}
+			case name == "ul" || name == "ol":
+				w.brk(listBreak(lists))
+				l := &list{ordered: name == "ol", next: 1}
+				if start, err := strconv.Atoi(attr(token, "start")); err == nil && l.ordered {
+					l.next = start
+				}
+				lists = append(lists, l)
+			case name == "li":
+				w.item = false
+				w.brk(lineBreak)
+				// Items of one list stay on consecutive lines even when they hold paragraphs
+				if len(lists) > 0 && lists[len(lists)-1].items > 0 {
+					w.pending = lineBreak
+				}
+				w.raw(itemPrefix(lists))
+				w.space, w.item = true, true
+			case name == "td" || name == "th":
+				w.space = w.pending == 0
+			case blockElements[name]:
+				w.brk(paragraphBreak)
+			}
+		case html.EndTagToken:
+			switch {
+			case name == "ul" || name == "ol":
+				if len(lists) > 0 {
+					lists = lists[:len(lists)-1]
+				}
+				w.brk(listBreak(lists))
+			case name == "li":
+				w.item = false
+				w.brk(lineBreak)
+				// Items of one list stay on consecutive lines even when they hold paragraphs
+				if len(lists) > 0 && lists[len(lists)-1].items > 0 {
+					w.pending = lineBreak
+				}
+			case blockElements[name]:
+				w.brk(paragraphBreak)
+			}
+		}
+	}
+
+	return w.buf.String()
+}
+
+// listBreak separates a list from its surroundings: a paragraph at the top level,
+// a line inside another list.
+func listBreak(lists []*list) int {
+	if len(lists) > 0 {
+		return lineBreak
+	}
+	return paragraphBreak
+}
+
+// itemPrefix returns the indented bullet or number of the next item in the innermost list.
+func itemPrefix(lists []*list) string {
+	if len(lists) == 0 {
+		return "•"
+	}
+	indent := strings.Repeat("  ", len(lists)-1)
+	l := lists[len(lists)-1]
+	l.items++
+	if !l.ordered {
+		return indent + "•"
+	}
+	prefix := indent + strconv.Itoa(l.next) + "."
+	l.next++
+	return prefix
+}
+
+func attr(token html.Token, key string) string {
+	for _, a := range token.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+// Truncate shortens text to at most maxChars characters, preferring to cut at a
+// word boundary, and reports whether it cut anything. maxChars <= 0 means no limit.
+func Truncate(text string, maxChars int) (string, bool) {
+	if maxChars <= 0 || utf8.RuneCountInString(text) <= maxChars {
+		return text, false
+	}
+	end := 0
+	for i := 0; i < maxChars; i++ {
+		_, size := utf8.DecodeRuneInString(text[end:])
+		end += size
+	}
+	cut := text[:end]
+	// Cutting mid-word reads badly, unless the only boundary would drop most of the text
+	if next, _ := utf8.DecodeRuneInString(text[end:]); !unicode.IsSpace(next) {
+		if i := strings.LastIndexFunc(cut, unicode.IsSpace); i > len(cut)/2 {
+			cut = cut[:i]
+		}
+	}
+	return strings.TrimRightFunc(cut, unicode.IsSpace), true
+}
@@ -0,0 +1,75 @@
+package plaintext_test
+
+import (
+	"testing"
+
+	"gist/backend/pkg/plaintext"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromHTML(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		opts  plaintext.Options
+		want  string
+	}{
+		{name: "empty", input: "", want: ""},
+		{name: "plain text", input: "Just text", want: "Just text"},
+		{name: "paragraphs", input: "<p>Hello <b>World</b></p><p>Bye</p>", want: "Hello World\n\nBye"},
+		{name: "collapses whitespace", input: "<p>  a\n\t b  </p>\n\n<div> c </div>", want: "a b\n\nc"},
+		{name: "line break", input: "one<br>two<br/><br>three", want: "one\ntwo\n\nthree"},
+		{name: "headings", input: "<h1>Title</h1>Body", want: "Title\n\nBody"},
+		{name: "entities", input: "<p>Tom &amp; Jerry&nbsp;&lt;3 &#8212; caf&eacute;</p>", want: "Tom & Jerry <3 — café"},
+		{name: "unicode", input: "<p>你好，世界</p><p>Привет</p>", want: "你好，世界\n\nПривет"},
+		{name: "invalid utf-8", input: "a\xffb", want: "a�b"},
+		{name: "ordered list", input: "<p>Steps:</p><ol><li>One</li><li> Two </li></ol><p>Done</p>", want: "Steps:\n\n1. One\n2. Two\n\nDone"},
+		{name: "ordered list start", input: `<ol start="4"><li>Four</li><li>Five</li></ol>`, want: "4. Four\n5. Five"},
+		{name: "unordered list", input: "<ul><li>a</li><li>b</li></ul>", want: "• a\n• b"},
+		{name: "nested list", input: "<ol><li>One<ul><li>a</li><li>b</li></ul></li><li>Two</li></ol>", want: "1. One\n  • a\n  • b\n2. Two"},
+		{name: "paragraph in list item", input: "<ol><li><p>One</p></li><li><p>Two</p></li></ol>", want: "1. One\n2. Two"},
+		{name: "empty list item", input: "<ol><li></li><li>b</li></ol>", want: "1.\n2. b"},
+		{name: "image alt", input: `<p>See<img src="x.png" alt=" A  cat "> here</p>`, want: "See [Image: A cat] here"},
+		{name: "image without alt", input: `<p>See <img src="x.png"> here</p>`, want: "See here"},
+		{name: "image only", input: `<figure><img alt="Chart"><figcaption>Sales</figcaption></figure>`, want: "[Image: Chart]\n\nSales"},
+		{name: "code block omitted", input: "<p>Run:</p><pre><code>go test ./...</code></pre><p>Then</p>", want: "Run:\n\n[code omitted]\n\nThen"},
+		{name: "code block dropped", input: "<p>Run:</p><pre><code>go test ./...</code></pre><p>Then</p>", opts: plaintext.Options{DropCode: true}, want: "Run:\n\nThen"},
+		{name: "inline code kept", input: "<p>Call <code>Close</code> first</p>", want: "Call Close first"},
+		{name: "scripts and styles skipped", input: "<style>p{}</style><p>Text</p><script>alert(1)</script><svg><text>x</text></svg>", want: "Text"},
+		{name: "nested skipped element", input: "<object><object>inner</object>still skipped</object>after", want: "after"},
+		{name: "table cells", input: "<table><tr><th>Name</th><th>Age</th></tr><tr><td>Ann</td><td>3</td></tr></table>", want: "Name Age\n\nAnn 3"},
+		{name: "unclosed tags", input: "<p>one<p>two", want: "one\n\ntwo"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, plaintext.FromHTML(tt.input, tt.opts))
+		})
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		maxChars  int
+		want      string
+		truncated bool
+	}{
+		{name: "no limit", input: "hello world", maxChars: 0, want: "hello world"},
+		{name: "fits", input: "hello world", maxChars: 11, want: "hello world"},
+		{name: "word boundary", input: "hello brave world", maxChars: 14, want: "hello brave", truncated: true},
+		{name: "cut at space", input: "hello world", maxChars: 6, want: "hello", truncated: true},
+		{name: "long word", input: "a verylongword", maxChars: 8, want: "a verylo", truncated: true},
+		{name: "counts characters not bytes", input: "你好世界", maxChars: 2, want: "你好", truncated: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, truncated := plaintext.Truncate(tt.input, tt.maxChars)
+			require.Equal(t, tt.want, got)
+			require.Equal(t, tt.truncated, truncated)
+		})
+	}
+}
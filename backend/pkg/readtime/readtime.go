@@ -0,0 +1,50 @@
+// Package readtime estimates word counts and reading time for entry content.
+package readtime
+
+import (
+	"unicode"
+
+	"gist/backend/pkg/sanitizer"
+)
+
+// WordsPerMinute is the assumed reading speed.
+const WordsPerMinute = 250
+
+// WordCount counts words in HTML content. Latin text is split on whitespace;
+// Han and kana characters count as half a word each, since CJK text has no spaces.
+func WordCount(html string) int {
+	words, cjk := 0, 0
+	inWord := false
+	for _, r := range sanitizer.StripTags(html) {
+		switch {
+		case isCJK(r):
+			cjk++
+			inWord = false
+		case unicode.IsSpace(r):
+			inWord = false
+		default:
+			if !inWord && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+				words++
+				inWord = true
+			}
+		}
+	}
+	return words + (cjk+1)/2
+}
+
+// Minutes rounds a word count up to whole reading minutes.
+func Minutes(wordCount int) int {
+	if wordCount <= 0 {
+		return 0
+	}
+	return (wordCount + WordsPerMinute - 1) / WordsPerMinute
+}
+
+// MaxWords is the largest word count that still reads in the given minutes.
+func MaxWords(minutes int) int {
+	return minutes * WordsPerMinute
+}
+
+func isCJK(r rune) bool {
+	return unicode.Is(unicode.Han, r) || unicode.Is(unicode.Hiragana, r) || unicode.Is(unicode.Katakana, r)
+}
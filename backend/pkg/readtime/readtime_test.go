@@ -0,0 +1,47 @@
+package readtime_test
+
+import (
+	"strings"
+	"testing"
+
+	"gist/backend/pkg/readtime"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWordCount(t *testing.T) {
+	cases := []struct {
+		name string
+		in   string
+		want int
+	}{
+		{name: "empty", in: "", want: 0},
+		{name: "latin", in: "<p>Hello, <strong>brave</strong> new world!</p>", want: 4},
+		{name: "punctuation only", in: "<p> — </p>", want: 0},
+		{name: "han", in: "<p>这是一个测试</p>", want: 3},
+		{name: "odd han rounds up", in: "你好世", want: 2},
+		{name: "kana", in: "ひらがなカタカナ", want: 4},
+		{name: "mixed", in: "Go 语言很好 v1.25", want: 4},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.want, readtime.WordCount(tc.in))
+		})
+	}
+}
+
+func TestMinutes(t *testing.T) {
+	require.Equal(t, 0, readtime.Minutes(0))
+	require.Equal(t, 1, readtime.Minutes(1))
+	require.Equal(t, 1, readtime.Minutes(250))
+	require.Equal(t, 2, readtime.Minutes(251))
+	require.Equal(t, 4, readtime.Minutes(readtime.WordCount(strings.Repeat("word ", 1000))))
+}
+
+func TestMaxWords(t *testing.T) {
+	require.Equal(t, 0, readtime.MaxWords(0))
+	require.Equal(t, 750, readtime.MaxWords(3))
+	require.Equal(t, 3, readtime.Minutes(readtime.MaxWords(3)))
+	require.Equal(t, 4, readtime.Minutes(readtime.MaxWords(3)+1))
+}
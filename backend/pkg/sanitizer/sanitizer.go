@@ -4,6 +4,8 @@ import (
 	"io"
 	"regexp"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	"golang.org/x/net/html"
 )
@@ -77,3 +79,43 @@ func StripTags(input string) string {
 
 	return strings.TrimSpace(buf.String())
 }
+
+// CleanText 将标题等单行文本清理为纯文本：移除 HTML 标签、解码实体、
+// 去掉控制字符并把连续空白折叠为单个空格。
+//
+// 示例：
+//   - "<b>Tom &amp; Jerry</b>" -> "Tom & Jerry"
+//   - "Hello\n\t  World" -> "Hello World"
+func CleanText(input string) string {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return ""
+	}
+
+	// tokenizer 在提取文本节点时会顺带解码实体
+	if strings.ContainsAny(input, "<&") {
+		input = StripTags(input)
+	}
+
+	cleaned := strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return ' '
+		}
+		if unicode.IsControl(r) || r == utf8.RuneError {
+			return -1
+		}
+		return r
+	}, input)
+	return strings.Join(strings.Fields(cleaned), " ")
+}
+
+// Truncate 将字符串截断为最多 maxRunes 个字符（按 rune 计，不会切断多字节字符），
+// 超出时以 "…" 结尾，结果长度包含省略号。maxRunes <= 0 时不截断。
+func Truncate(input string, maxRunes int) string {
+	if maxRunes <= 0 || utf8.RuneCountInString(input) <= maxRunes {
+		return input
+	}
+
+	runes := []rune(input)
+	return strings.TrimRightFunc(string(runes[:maxRunes-1]), unicode.IsSpace) + "…"
+}
@@ -147,6 +147,125 @@ func TestStripTags(t *testing.T) {
 	}
 }
 
+func TestCleanText(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{name: "Plain text", input: "Hello World", expected: "Hello World"},
+		{name: "Named entities", input: "Tom &amp; Jerry &quot;Live&quot;", expected: `Tom & Jerry "Live"`},
+		{name: "Numeric entities", input: "&#24352;&#19977; &#x2014; blog", expected: "张三 — blog"},
+		{name: "Tags and entities", input: "<b>R&amp;D</b> <i>notes</i>", expected: "R&D notes"},
+		{name: "Collapse whitespace", input: "  Hello\n\t  World  ", expected: "Hello World"},
+		{name: "Drop control characters", input: "Hel\x00lo\x1b World\u200b", expected: "Hello World\u200b"},
+		{name: "Empty string", input: "", expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := sanitizer.CleanText(tt.input)
+			if result != tt.expected {
+				t.Errorf("CleanText(%q) = %q, expected %q", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		max      int
+		expected string
+	}{
+		{name: "Short input unchanged", input: "Hello", max: 10, expected: "Hello"},
+		{name: "Exact length unchanged", input: "Hello", max: 5, expected: "Hello"},
+		{name: "ASCII truncated", input: "Hello World", max: 6, expected: "Hello…"},
+		{name: "CJK counted by rune", input: "你好世界欢迎光临", max: 5, expected: "你好世界…"},
+		{name: "CJK within limit", input: "你好世界", max: 4, expected: "你好世界"},
+		{name: "Zero disables truncation", input: "Hello", max: 0, expected: "Hello"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := sanitizer.Truncate(tt.input, tt.max)
+			if result != tt.expected {
+				t.Errorf("Truncate(%q, %d) = %q, expected %q", tt.input, tt.max, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSanitizeSVG(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "Clean SVG unchanged",
+			input:    `<?xml version="1.0"?>` + "\n" + `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 16 16"><path d="M0 0h16v16z"/></svg>`,
+			expected: `<?xml version="1.0"?>` + "\n" + `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 16 16"><path d="M0 0h16v16z"/></svg>`,
+		},
+		{
+			name:     "Event attributes removed",
+			input:    `<svg xmlns="http://www.w3.org/2000/svg" onload="alert(1)"><rect width="2" ONCLICK='x()' height="2"/></svg>`,
+			expected: `<svg xmlns="http://www.w3.org/2000/svg"><rect width="2" height="2"/></svg>`,
+		},
+		{
+			name:     "Script elements removed",
+			input:    `<svg xmlns="http://www.w3.org/2000/svg"><script>alert(1)</script><svg:script xmlns:svg="http://www.w3.org/2000/svg"><![CDATA[x()]]></svg:script><g/></svg>`,
+			expected: `<svg xmlns="http://www.w3.org/2000/svg"><g/></svg>`,
+		},
+		{
+			name:     "javascript: links removed",
+			input:    `<svg xmlns="http://www.w3.org/2000/svg" xmlns:xlink="http://www.w3.org/1999/xlink"><a xlink:href="java&#x09;script:x()" title='a onload="b"'>t</a></svg>`,
+			expected: `<svg xmlns="http://www.w3.org/2000/svg" xmlns:xlink="http://www.w3.org/1999/xlink"><a title='a onload="b"'>t</a></svg>`,
+		},
+		{
+			name:     "Elements outside the allowlist removed with their content",
+			input:    `<svg xmlns="http://www.w3.org/2000/svg"><foreignObject><iframe srcdoc="&lt;script&gt;alert(1)&lt;/script&gt;"/></foreignObject><a href="#x"><animate attributeName="href" values="javascript:x()"/><set attributeName="href" to="javascript:x()"/></a><h:p xmlns:h="http://www.w3.org/1999/xhtml">x</h:p><circle r="4"/></svg>`,
+			expected: `<svg xmlns="http://www.w3.org/2000/svg"><a href="#x"></a><circle r="4"/></svg>`,
+		},
+		{
+			name:     "Attributes outside the allowlist removed",
+			input:    `<svg xmlns="http://www.w3.org/2000/svg" xmlns:h="http://www.w3.org/1999/xhtml"><rect srcdoc="x" h:style="x" width="2" fill="red"/><image href="data:text/html,x"/><image xlink:href="data:image/png;base64,AAAA" xmlns:xlink="http://www.w3.org/1999/xlink"/></svg>`,
+			expected: `<svg xmlns="http://www.w3.org/2000/svg" xmlns:h="http://www.w3.org/1999/xhtml"><rect width="2" fill="red"/><image/><image xlink:href="data:image/png;base64,AAAA" xmlns:xlink="http://www.w3.org/1999/xlink"/></svg>`,
+		},
+		{
+			name:     "Processing instructions removed",
+			input:    `<?xml version="1.0"?><?xml-stylesheet href="evil.xsl"?><svg xmlns="http://www.w3.org/2000/svg"/>`,
+			expected: `<?xml version="1.0"?><svg xmlns="http://www.w3.org/2000/svg"/>`,
+		},
+		{
+			name:     "DOCTYPE removed",
+			input:    `<!DOCTYPE svg PUBLIC "-//W3C//DTD SVG 1.1//EN" "http://www.w3.org/Graphics/SVG/1.1/DTD/svg11.dtd"><svg xmlns="http://www.w3.org/2000/svg"/>`,
+			expected: `<svg xmlns="http://www.w3.org/2000/svg"/>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := sanitizer.SanitizeSVG([]byte(tt.input))
+			if err != nil {
+				t.Fatalf("SanitizeSVG(%q) error: %v", tt.input, err)
+			}
+			if string(result) != tt.expected {
+				t.Errorf("SanitizeSVG(%q) = %q, expected %q", tt.input, result, tt.expected)
+			}
+		})
+	}
+
+	// 未定义的实体可能来自 DOCTYPE，无法判断其展开内容
+	if _, err := sanitizer.SanitizeSVG([]byte(`<svg xmlns="http://www.w3.org/2000/svg">&x;</svg>`)); err == nil {
+		t.Error("SanitizeSVG accepted an undefined entity")
+	}
+	if _, err := sanitizer.SanitizeSVG([]byte(`<svg><g></svg>`)); err == nil {
+		t.Error("SanitizeSVG accepted mismatched tags")
+	}
+}
+
 // BenchmarkSanitizeAuthor 性能测试
 func BenchmarkSanitizeAuthor(b *testing.B) {
 	inputs := []string{
@@ -0,0 +1,192 @@
+package sanitizer
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// svgAttrRegex 匹配起始标签中的一个属性。XML 的属性值必须带引号，
+// 所以对已通过解析的标签这个匹配是精确的。
+var svgAttrRegex = regexp.MustCompile(`\s+[^\s=/>]+\s*=\s*(?:"[^"]*"|'[^']*')`)
+
+const (
+	svgNamespace   = "http://www.w3.org/2000/svg"
+	xlinkNamespace = "http://www.w3.org/1999/xlink"
+	xmlNamespace   = "http://www.w3.org/XML/1998/namespace"
+)
+
+// svgElements 是允许保留的 SVG 元素（小写）。script、foreignObject、
+// animate/set（可把 href 改成 javascript:）等不在其中，连同子树一起移除。
+var svgElements = toSet(
+	"svg", "g", "defs", "symbol", "use", "title", "desc", "style", "a",
+	"path", "rect", "circle", "ellipse", "line", "polyline", "polygon",
+	"text", "tspan", "textpath", "image", "marker", "pattern", "clippath", "mask",
+	"lineargradient", "radialgradient", "stop",
+	"filter", "feblend", "fecolormatrix", "fecomponenttransfer", "fecomposite",
+	"fedisplacementmap", "fedropshadow", "feflood", "fefunca", "fefuncb", "fefuncg",
+	"fefuncr", "fegaussianblur", "femerge", "femergenode", "femorphology",
+	"feoffset", "feturbulence",
+)
+
+// svgAttributes 是允许保留的属性（小写，不含命名空间前缀）。
+var svgAttributes = toSet(
+	"id", "class", "style", "title", "lang", "space", "version", "baseprofile",
+	"x", "y", "x1", "x2", "y1", "y2", "cx", "cy", "r", "rx", "ry", "fx", "fy", "fr",
+	"dx", "dy", "width", "height", "d", "points", "pathlength", "viewbox",
+	"preserveaspectratio", "transform", "href", "rotate", "textlength", "lengthadjust",
+	"fill", "fill-opacity", "fill-rule", "stroke", "stroke-width", "stroke-linecap",
+	"stroke-linejoin", "stroke-miterlimit", "stroke-dasharray", "stroke-dashoffset",
+	"stroke-opacity", "opacity", "color", "display", "visibility", "overflow",
+	"paint-order", "vector-effect", "shape-rendering", "image-rendering", "mix-blend-mode",
+	"clip-path", "clip-rule", "clippathunits", "mask", "maskunits", "maskcontentunits",
+	"filter", "filterunits", "primitiveunits", "gradientunits", "gradienttransform",
+	"spreadmethod", "offset", "stop-color", "stop-opacity", "patternunits",
+	"patterncontentunits", "patterntransform", "markerwidth", "markerheight",
+	"markerunits", "refx", "refy", "orient", "marker-start", "marker-mid", "marker-end",
+	"font-family", "font-size", "font-weight", "font-style", "text-anchor",
+	"dominant-baseline", "letter-spacing",
+	"in", "in2", "result", "stddeviation", "mode", "operator", "k1", "k2", "k3", "k4",
+	"type", "values", "tablevalues", "slope", "intercept", "amplitude", "exponent",
+	"flood-color", "flood-opacity", "radius", "scale", "xchannelselector",
+	"ychannelselector", "basefrequency", "numoctaves", "seed", "stitchtiles",
+	"color-interpolation-filters",
+)
+
+// svgSafeDataImage 匹配允许作为 href 的内嵌位图。
+var svgSafeDataImage = regexp.MustCompile(`^data:image/(?:png|jpeg|gif|webp)[;,]`)
+
+// SanitizeSVG 只保留白名单中的 SVG 元素和属性，其余元素连同子树移除，
+// 其余属性直接去掉；href 只能指向片段、http(s) 地址或内嵌位图。
+// 保留的内容按原始字节输出。
+//
+// DOCTYPE 和处理指令会被一并移除，因为前者声明的实体可以展开成任意标记，
+// 后者可以引入样式表。无法按 XML 严格解析的输入（包括引用了未定义实体的）
+// 返回错误，浏览器本就不会渲染这样的 SVG。
+func SanitizeSVG(data []byte) ([]byte, error) {
+	d := xml.NewDecoder(bytes.NewReader(data))
+	d.Strict = true
+
+	var buf bytes.Buffer
+	buf.Grow(len(data))
+	var offset int64
+	skipDepth := 0 // > 0 时位于被移除的元素内
+
+	for {
+		token, err := d.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("parse svg: %w", err)
+		}
+		next := d.InputOffset()
+		raw := data[offset:next]
+		offset = next
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			if skipDepth > 0 || !allowedSVGElement(t.Name) {
+				skipDepth++
+				continue
+			}
+			tag, err := sanitizeSVGTag(raw, t.Attr)
+			if err != nil {
+				return nil, err
+			}
+			buf.Write(tag)
+			continue
+		case xml.EndElement:
+			if skipDepth > 0 {
+				skipDepth--
+				continue
+			}
+		case xml.ProcInst:
+			if t.Target != "xml" {
+				continue
+			}
+		case xml.Directive:
+			continue
+		}
+		if skipDepth == 0 {
+			buf.Write(raw)
+		}
+	}
+
+	return buf.Bytes(), nil
+}
+
+// sanitizeSVGTag 从起始标签的原始字节中去掉不安全的属性。attrs 是解析器
+// 按出现顺序给出的同一组属性，用于判断（值已解码实体）。
+func sanitizeSVGTag(raw []byte, attrs []xml.Attr) ([]byte, error) {
+	matches := svgAttrRegex.FindAllIndex(raw, -1)
+	if len(matches) != len(attrs) {
+		return nil, fmt.Errorf("parse svg: unexpected attributes in %q", raw)
+	}
+
+	var out []byte
+	last := 0
+	for i, attr := range attrs {
+		if allowedSVGAttr(attr) {
+			continue
+		}
+		out = append(out, raw[last:matches[i][0]]...)
+		last = matches[i][1]
+	}
+	if out == nil {
+		return raw, nil
+	}
+	return append(out, raw[last:]...), nil
+}
+
+func allowedSVGElement(name xml.Name) bool {
+	if name.Space != "" && name.Space != svgNamespace {
+		return false
+	}
+	return svgElements[strings.ToLower(name.Local)]
+}
+
+func allowedSVGAttr(attr xml.Attr) bool {
+	switch attr.Name.Space {
+	case "xmlns":
+		// 命名空间声明本身无害，其他命名空间中的元素会被整体移除
+		return true
+	case "", xlinkNamespace, xmlNamespace:
+	default:
+		return false
+	}
+	local := strings.ToLower(attr.Name.Local)
+	if attr.Name.Space == "" && local == "xmlns" {
+		return true
+	}
+	if !svgAttributes[local] {
+		return false
+	}
+	// 浏览器解析 URL 时会忽略其中的空白和控制字符
+	value := strings.ToLower(strings.Map(func(r rune) rune {
+		if r <= ' ' {
+			return -1
+		}
+		return r
+	}, attr.Value))
+	if strings.Contains(value, "javascript:") {
+		return false
+	}
+	if local == "href" {
+		return strings.HasPrefix(value, "#") || strings.HasPrefix(value, "http://") ||
+			strings.HasPrefix(value, "https://") || svgSafeDataImage.MatchString(value)
+	}
+	return true
+}
+
+func toSet(values ...string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, v := range values {
+		set[v] = true
+	}
+	return set
+}
@@ -0,0 +1,74 @@
+package totp_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"gist/backend/pkg/totp"
+
+	"github.com/stretchr/testify/require"
+)
+
+// rfcSecret is the base32 encoding of the RFC 6238 SHA1 test key "12345678901234567890".
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestCodeAt_RFC6238Vectors(t *testing.T) {
+	cases := []struct {
+		unix int64
+		want string
+	}{
+		{unix: 59, want: "287082"},
+		{unix: 1111111109, want: "081804"},
+		{unix: 1234567890, want: "005924"},
+		{unix: 2000000000, want: "279037"},
+	}
+
+	for _, tc := range cases {
+		code, err := totp.CodeAt(rfcSecret, totp.Step(time.Unix(tc.unix, 0)))
+		require.NoError(t, err)
+		require.Equal(t, tc.want, code, "unix=%d", tc.unix)
+	}
+}
+
+func TestValidate_AllowsSkew(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+	previous, err := totp.CodeAt(rfcSecret, totp.Step(now)-1)
+	require.NoError(t, err)
+
+	step, ok := totp.Validate(rfcSecret, previous, now, 1)
+	require.True(t, ok)
+	require.Equal(t, totp.Step(now)-1, step)
+
+	_, ok = totp.Validate(rfcSecret, previous, now, 0)
+	require.False(t, ok)
+}
+
+func TestValidate_RejectsMalformedCode(t *testing.T) {
+	now := time.Unix(59, 0)
+	_, ok := totp.Validate(rfcSecret, "", now, 1)
+	require.False(t, ok)
+	_, ok = totp.Validate(rfcSecret, "28708", now, 1)
+	require.False(t, ok)
+	_, ok = totp.Validate("not base32!", "287082", now, 1)
+	require.False(t, ok)
+}
+
+func TestGenerateSecret_RoundTrip(t *testing.T) {
+	secret, err := totp.GenerateSecret()
+	require.NoError(t, err)
+	require.Len(t, secret, 32)
+
+	now := time.Now()
+	code, err := totp.CodeAt(secret, totp.Step(now))
+	require.NoError(t, err)
+	_, ok := totp.Validate(secret, code, now, 0)
+	require.True(t, ok)
+}
+
+func TestURL(t *testing.T) {
+	u := totp.URL("Gist", "alice", rfcSecret)
+	require.True(t, strings.HasPrefix(u, "otpauth://totp/Gist:alice?"))
+	require.Contains(t, u, "secret="+rfcSecret)
+	require.Contains(t, u, "issuer=Gist")
+}
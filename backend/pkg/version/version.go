@@ -0,0 +1,13 @@
+// Package version holds build information injected at link time, e.g.
+//
+//	go build -ldflags "-X gist/backend/pkg/version.Version=1.2.3 -X gist/backend/pkg/version.Commit=abc123"
+package version
+
+var (
+	// Version is the release version of the build.
+	Version = "dev"
+	// Commit is the git commit the binary was built from.
+	Commit = "unknown"
+	// BuildTime is the time the binary was built (RFC3339).
+	BuildTime = "unknown"
+)
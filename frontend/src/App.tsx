@@ -22,6 +22,7 @@ import { useAppearanceSettings } from '@/hooks/useAppearanceSettings'
 import { useTitle, buildTitle } from '@/hooks/useTitle'
 import { useUISettingKey, useUISettingActions, hasSidebarVisibilitySetting, setUISetting } from '@/hooks/useUISettings'
 import { useRefreshStatus } from '@/hooks/useRefreshStatus'
+import { useServerEvents } from '@/hooks/useServerEvents'
 import { isAddFeedPath } from '@/lib/router'
 import { cn } from '@/lib/utils'
 import type { ContentType, Feed, Folder } from '@/types/api'
@@ -130,6 +131,8 @@ function AuthenticatedApp() {
 
   // Poll refresh status and auto-invalidate entries when scheduled refresh completes
   useRefreshStatus()
+  // Invalidate caches on changes pushed by the backend, including from other devices
+  useServerEvents()
 
   // Sidebar visibility for tablet/desktop
   const sidebarVisible = useUISettingKey('sidebarVisible')
@@ -1,35 +1,73 @@
 import type {
   ApiErrorResponse,
+  ArchiveBackfill,
+  ArchiveStatusResponse,
+  BackupEntries,
+  BackupImportResult,
+  BulkEntriesResponse,
+  ClickStats,
   ContentType,
+  DailyDigest,
+  DedupeKey,
   Entry,
+  EntryCountResponse,
   EntryListParams,
   EntryListResponse,
+  EntryRevisionListResponse,
+  EntrySearchParams,
+  EntrySearchResponse,
   Feed,
+  FeedArchiveBackfill,
+  FeedIngestReport,
+  FeedOverlap,
+  FeedPatch,
   FeedPreview,
+  FeedProbe,
+  FeedAutoTranslate,
+  FeedStats,
   Folder,
+  FolderFeedDefaults,
+  FolderRule,
+  FolderRuleMatchField,
   ImportTask,
+  IngestTokenResponse,
+  InitialBackfill,
   MarkAllReadParams,
+  MutedAuthorsResponse,
+  ReadingStats,
+  ServerEventData,
+  ServerEventType,
   StarredCountResponse,
+  StaticFeedUpdateResult,
   UnreadCountsResponse,
 } from '@/types/api'
-import type { AISettings, AITestRequest, AITestResponse, AppearanceSettings, DomainRateLimit, DomainRateLimitListResponse, GeneralSettings, NetworkSettings, NetworkTestRequest, NetworkTestResponse } from '@/types/settings'
+import type { AISettings, AITestRequest, AITestResponse, AppearanceSettings, DomainRateLimit, DomainRateLimitListResponse, GeneralSettings, NetworkSettings, NetworkTestRequest, NetworkTestResponse, SecuritySettings } from '@/types/settings'
 
 const API_BASE_URL = import.meta.env.VITE_API_URL ?? ''
 const TOKEN_KEY = 'gist_auth_token'
 
 export class ApiError extends Error {
   status: number
-
-  constructor(message: string, status: number) {
+  code?: string
+  details?: Record<string, unknown>
+
+  constructor(
+    message: string,
+    status: number,
+    code?: string,
+    details?: Record<string, unknown>
+  ) {
     super(message)
     this.status = status
+    this.code = code
+    this.details = details
   }
 }
 
 function isErrorResponse(value: unknown): value is ApiErrorResponse {
   if (typeof value !== 'object' || value === null) return false
-  if (!('error' in value)) return false
-  return typeof (value as { error: unknown }).error === 'string'
+  if (!('code' in value) || !('message' in value)) return false
+  return typeof (value as { message: unknown }).message === 'string'
 }
 
 async function parseResponse(response: Response): Promise<unknown> {
@@ -52,7 +90,7 @@ async function parseResponse(response: Response): Promise<unknown> {
  * Extract error message from response data
  */
 function extractErrorMessage(data: unknown, fallback: string): string {
-  if (isErrorResponse(data)) return data.error
+  if (isErrorResponse(data)) return data.message
   if (typeof data === 'string') return data
   return fallback
 }
@@ -85,7 +123,9 @@ async function fetchWithAuth(
     const data = await parseResponse(response)
     throw new ApiError(
       extractErrorMessage(data, response.statusText) || 'Request failed',
-      response.status
+      response.status,
+      isErrorResponse(data) ? data.code : undefined,
+      isErrorResponse(data) ? data.details : undefined
     )
   }
 
@@ -221,11 +261,16 @@ async function request<T>(path: string, options: RequestInit = {}): Promise<T> {
     }
 
     const message = isErrorResponse(data)
-      ? data.error
+      ? data.message
       : typeof data === 'string'
         ? data
         : response.statusText
-    throw new ApiError(message || 'Request failed', response.status)
+    throw new ApiError(
+      message || 'Request failed',
+      response.status,
+      isErrorResponse(data) ? data.code : undefined,
+      isErrorResponse(data) ? data.details : undefined
+    )
   }
 
   if (response.status === 204) {
@@ -241,11 +286,23 @@ export interface AuthUser {
   nickname: string
   email: string
   avatarUrl: string
+  twoFactorEnabled?: boolean
 }
 
 export interface AuthResponse {
   token: string
   user: AuthUser
+  requires2fa?: boolean
+  pendingToken?: string
+}
+
+export interface TwoFactorSetupResponse {
+  secret: string
+  otpauthUrl: string
+}
+
+export interface TwoFactorEnableResponse {
+  recoveryCodes: string[]
 }
 
 export interface AuthStatusResponse {
@@ -271,6 +328,33 @@ export async function login(identifier: string, password: string): Promise<AuthR
   })
 }
 
+export async function verifyTwoFactor(pendingToken: string, code: string): Promise<AuthResponse> {
+  return request<AuthResponse>('/api/auth/2fa/verify', {
+    method: 'POST',
+    body: JSON.stringify({ pendingToken, code }),
+  })
+}
+
+export async function setupTwoFactor(): Promise<TwoFactorSetupResponse> {
+  return request<TwoFactorSetupResponse>('/api/auth/2fa/setup', {
+    method: 'POST',
+  })
+}
+
+export async function enableTwoFactor(code: string): Promise<TwoFactorEnableResponse> {
+  return request<TwoFactorEnableResponse>('/api/auth/2fa/enable', {
+    method: 'POST',
+    body: JSON.stringify({ code }),
+  })
+}
+
+export async function disableTwoFactor(password: string): Promise<void> {
+  return request<void>('/api/auth/2fa/disable', {
+    method: 'POST',
+    body: JSON.stringify({ password }),
+  })
+}
+
 export async function getCurrentUser(): Promise<AuthUser> {
   return request<AuthUser>('/api/auth/me')
 }
@@ -300,6 +384,35 @@ export async function updateProfile(data: UpdateProfileRequest): Promise<UpdateP
   })
 }
 
+export interface APIToken {
+  id: string
+  name: string
+  createdAt: string
+  lastUsedAt?: string
+  expiresAt?: string
+}
+
+export interface CreatedAPIToken extends APIToken {
+  token: string
+}
+
+export async function listAPITokens(): Promise<{ items: APIToken[] }> {
+  return request<{ items: APIToken[] }>('/api/auth/tokens')
+}
+
+export async function createAPIToken(name: string, expiresAt?: string): Promise<CreatedAPIToken> {
+  return request<CreatedAPIToken>('/api/auth/tokens', {
+    method: 'POST',
+    body: JSON.stringify({ name, expiresAt }),
+  })
+}
+
+export async function deleteAPIToken(id: string): Promise<void> {
+  return request<void>(`/api/auth/tokens/${id}`, {
+    method: 'DELETE',
+  })
+}
+
 export async function listFolders(): Promise<Folder[]> {
   return request<Folder[]>('/api/folders')
 }
@@ -331,6 +444,12 @@ export async function deleteFolder(id: string): Promise<void> {
   })
 }
 
+export async function restoreFolder(id: string): Promise<Folder> {
+  return request<Folder>(`/api/folders/${id}/restore`, {
+    method: 'POST',
+  })
+}
+
 export async function updateFolderType(id: string, type: ContentType): Promise<void> {
   return request<void>(`/api/folders/${id}/type`, {
     method: 'PATCH',
@@ -338,6 +457,24 @@ export async function updateFolderType(id: string, type: ContentType): Promise<v
   })
 }
 
+export async function updateFolderFeedDefaults(id: string, defaults: FolderFeedDefaults): Promise<Folder> {
+  return request<Folder>(`/api/folders/${id}/feed-defaults`, {
+    method: 'PUT',
+    body: JSON.stringify({
+      userAgent: defaults.userAgent ?? null,
+      autoReadability: defaults.autoReadability ?? null,
+      autoTranslate: defaults.autoTranslate ?? null,
+    }),
+  })
+}
+
+export async function reorderFolders(ids: string[]): Promise<void> {
+  return request<void>('/api/folders/reorder', {
+    method: 'PUT',
+    body: JSON.stringify({ ids }),
+  })
+}
+
 export async function deleteFolders(ids: string[]): Promise<void> {
   return request<void>('/api/folders', {
     method: 'DELETE',
@@ -345,9 +482,54 @@ export async function deleteFolders(ids: string[]): Promise<void> {
   })
 }
 
-export async function listFeeds(folderId?: string): Promise<Feed[]> {
-  const params = folderId === undefined ? '' : `?folderId=${encodeURIComponent(folderId)}`
-  return request<Feed[]>(`/api/feeds${params}`)
+export async function listFolderRules(folderId: string): Promise<FolderRule[]> {
+  return request<FolderRule[]>(`/api/folders/${folderId}/rules`)
+}
+
+export async function createFolderRule(
+  folderId: string,
+  payload: { pattern: string; matchField: FolderRuleMatchField; priority?: number }
+): Promise<FolderRule> {
+  return request<FolderRule>(`/api/folders/${folderId}/rules`, {
+    method: 'POST',
+    body: JSON.stringify(payload),
+  })
+}
+
+export async function updateFolderRule(
+  folderId: string,
+  ruleId: string,
+  payload: { pattern: string; matchField: FolderRuleMatchField; priority?: number }
+): Promise<FolderRule> {
+  return request<FolderRule>(`/api/folders/${folderId}/rules/${ruleId}`, {
+    method: 'PUT',
+    body: JSON.stringify(payload),
+  })
+}
+
+export async function deleteFolderRule(folderId: string, ruleId: string): Promise<void> {
+  return request<void>(`/api/folders/${folderId}/rules/${ruleId}`, {
+    method: 'DELETE',
+  })
+}
+
+/** Files existing feeds without a folder by the current rules; resolves to how many moved */
+export async function applyFolderRules(): Promise<{ moved: number }> {
+  return request<{ moved: number }>('/api/folders/rules/apply', {
+    method: 'POST',
+  })
+}
+
+export async function listFeeds(folderId?: string, language?: string): Promise<Feed[]> {
+  const params = new URLSearchParams()
+  if (folderId !== undefined) params.set('folderId', folderId)
+  if (language) params.set('language', language)
+  const query = params.toString()
+  return request<Feed[]>(`/api/feeds${query ? `?${query}` : ''}`)
+}
+
+export async function getFeedStats(): Promise<FeedStats[]> {
+  return request<FeedStats[]>('/api/feeds/stats')
 }
 
 export async function createFeed(payload: {
@@ -355,6 +537,10 @@ export async function createFeed(payload: {
   folderId?: string
   title?: string
   type?: ContentType
+  typeMode?: 'auto' | 'manual'
+  initialBackfill?: InitialBackfill
+  backfillArchive?: boolean
+  archivePages?: number
 }): Promise<Feed> {
   return request<Feed>('/api/feeds', {
     method: 'POST',
@@ -362,9 +548,34 @@ export async function createFeed(payload: {
   })
 }
 
+export async function createStaticFeed(payload: {
+  content: string
+  folderId?: string
+  title?: string
+  type?: ContentType
+}): Promise<Feed> {
+  return request<Feed>('/api/feeds/static', {
+    method: 'POST',
+    body: JSON.stringify(payload),
+  })
+}
+
+export async function updateStaticFeed(id: string, content: string): Promise<StaticFeedUpdateResult> {
+  return request<StaticFeedUpdateResult>(`/api/feeds/${id}/static`, {
+    method: 'PUT',
+    body: JSON.stringify({ content }),
+  })
+}
+
+export async function createFeedIngestToken(id: string): Promise<IngestTokenResponse> {
+  return request<IngestTokenResponse>(`/api/feeds/${id}/ingest-token`, {
+    method: 'POST',
+  })
+}
+
 export async function updateFeed(
   id: string,
-  payload: { title: string; folderId?: string; summaryPromptReminder?: string }
+  payload: { title: string; folderId?: string; summaryPromptReminder?: string; maxEntries?: number }
 ): Promise<Feed> {
   return request<Feed>(`/api/feeds/${id}`, {
     method: 'PUT',
@@ -372,12 +583,45 @@ export async function updateFeed(
   })
 }
 
+/** Changes only the given fields; an invalid one leaves the feed unchanged */
+export async function patchFeed(id: string, patch: FeedPatch): Promise<Feed> {
+  return request<Feed>(`/api/feeds/${id}`, {
+    method: 'PATCH',
+    body: JSON.stringify(patch),
+  })
+}
+
 export async function deleteFeed(id: string): Promise<void> {
   return request<void>(`/api/feeds/${id}`, {
     method: 'DELETE',
   })
 }
 
+export async function restoreFeed(id: string): Promise<Feed> {
+  return request<Feed>(`/api/feeds/${id}/restore`, {
+    method: 'POST',
+  })
+}
+
+export async function listFeedOverlaps(): Promise<FeedOverlap[]> {
+  return request<FeedOverlap[]>('/api/feeds/overlaps')
+}
+
+/** Merges the other feed of the overlap into keepFeedId, the overlap's first feed if left out */
+export async function mergeFeedOverlap(id: string, keepFeedId?: string): Promise<Feed> {
+  return request<Feed>(`/api/feeds/overlaps/${id}/merge`, {
+    method: 'POST',
+    body: JSON.stringify(keepFeedId ? { keepFeedId } : {}),
+  })
+}
+
+export async function probeFeed(id: string, userAgent?: 'default' | 'fallback'): Promise<FeedProbe> {
+  const query = userAgent ? `?userAgent=${userAgent}` : ''
+  return request<FeedProbe>(`/api/feeds/${id}/probe${query}`, {
+    method: 'POST',
+  })
+}
+
 export async function updateFeedType(id: string, type: ContentType): Promise<void> {
   return request<void>(`/api/feeds/${id}/type`, {
     method: 'PATCH',
@@ -385,6 +629,82 @@ export async function updateFeedType(id: string, type: ContentType): Promise<voi
   })
 }
 
+export async function updateFeedTimezone(id: string, assumeTimezone: string | null): Promise<void> {
+  return request<void>(`/api/feeds/${id}/timezone`, {
+    method: 'PATCH',
+    body: JSON.stringify({ assumeTimezone }),
+  })
+}
+
+export async function updateFeedDedupeKey(id: string, dedupeKey: DedupeKey): Promise<void> {
+  return request<void>(`/api/feeds/${id}/dedupe-key`, {
+    method: 'PATCH',
+    body: JSON.stringify({ dedupeKey }),
+  })
+}
+
+export async function updateFeedAutoTranslate(id: string, autoTranslate: FeedAutoTranslate): Promise<void> {
+  return request<void>(`/api/feeds/${id}/auto-translate`, {
+    method: 'PATCH',
+    body: JSON.stringify({ autoTranslate }),
+  })
+}
+
+export async function updateFeedUserAgent(id: string, userAgent: string | null): Promise<void> {
+  return request<void>(`/api/feeds/${id}/user-agent`, {
+    method: 'PATCH',
+    body: JSON.stringify({ userAgent }),
+  })
+}
+
+export async function updateFeedAutoReadability(id: string, autoReadability: boolean | null): Promise<void> {
+  return request<void>(`/api/feeds/${id}/auto-readability`, {
+    method: 'PATCH',
+    body: JSON.stringify({ autoReadability }),
+  })
+}
+
+export async function listMutedAuthors(id: string): Promise<MutedAuthorsResponse> {
+  return request<MutedAuthorsResponse>(`/api/feeds/${id}/muted-authors`)
+}
+
+export async function muteAuthor(id: string, author: string): Promise<MutedAuthorsResponse> {
+  return request<MutedAuthorsResponse>(`/api/feeds/${id}/muted-authors`, {
+    method: 'PUT',
+    body: JSON.stringify({ author }),
+  })
+}
+
+export async function unmuteAuthor(id: string, author: string): Promise<MutedAuthorsResponse> {
+  return request<MutedAuthorsResponse>(`/api/feeds/${id}/muted-authors`, {
+    method: 'DELETE',
+    body: JSON.stringify({ author }),
+  })
+}
+
+export async function pauseFeed(
+  id: string,
+  pause: { duration: string } | { until: string }
+): Promise<void> {
+  return request<void>(`/api/feeds/${id}/pause`, {
+    method: 'POST',
+    body: JSON.stringify(pause),
+  })
+}
+
+export async function unpauseFeed(id: string): Promise<void> {
+  return request<void>(`/api/feeds/${id}/pause`, {
+    method: 'DELETE',
+  })
+}
+
+export async function reorderFeeds(ids: string[]): Promise<void> {
+  return request<void>('/api/feeds/reorder', {
+    method: 'PUT',
+    body: JSON.stringify({ ids }),
+  })
+}
+
 export async function deleteFeeds(ids: string[]): Promise<void> {
   return request<void>('/api/feeds', {
     method: 'DELETE',
@@ -398,21 +718,170 @@ export async function refreshAllFeeds(): Promise<void> {
   })
 }
 
+export async function refreshFeed(id: string): Promise<void> {
+  return request<void>(`/api/feeds/${id}/refresh`, {
+    method: 'POST',
+  })
+}
+
 export interface RefreshStatus {
   isRefreshing: boolean
   lastRefreshedAt?: string
+  /** Set while quiet hours skip scheduled refreshes, until pausedUntil */
+  paused?: 'quiet_hours'
+  pausedUntil?: string
 }
 
 export async function getRefreshStatus(): Promise<RefreshStatus> {
   return request<RefreshStatus>('/api/feeds/refresh')
 }
 
+/** Compact feed of the sidebar bootstrap; title is the custom title when renamed */
+export interface BootstrapFeed {
+  id: string
+  folderId?: string
+  title: string
+  url: string
+  siteUrl?: string
+  iconPath?: string
+  type: ContentType
+  sortOrder: number
+  hasError: boolean
+  paused: boolean
+}
+
+export interface BootstrapResponse {
+  folders: Folder[]
+  feeds: BootstrapFeed[]
+  /** Feed ID to unread count, as getUnreadCounts returns */
+  unreadCounts: Record<string, number>
+  starredCount: number
+  refresh: RefreshStatus
+  appearance: AppearanceSettings
+}
+
+export async function getBootstrap(): Promise<BootstrapResponse> {
+  return request<BootstrapResponse>('/api/bootstrap')
+}
+
+const serverEventTypes: ServerEventType[] = [
+  'refresh.started',
+  'refresh.finished',
+  'entries.new',
+  'entries.read',
+  'entry.starred',
+  'entry.readable',
+  'feed.created',
+  'feed.updated',
+  'feed.deleted',
+  'folder.created',
+  'folder.updated',
+  'folder.deleted',
+]
+
+/**
+ * Subscribes to change events. EventSource cannot send headers, so the stream
+ * authenticates with the auth cookie and reconnects by itself after errors.
+ * Returns a function that closes the stream.
+ */
+export function subscribeEvents(
+  onEvent: (type: ServerEventType, data: ServerEventData) => void,
+  onReconnect?: () => void
+): () => void {
+  const source = new EventSource(`${API_BASE_URL}/api/events`, { withCredentials: true })
+  let dropped = false
+  for (const type of serverEventTypes) {
+    source.addEventListener(type, (event) => {
+      onEvent(type, JSON.parse((event as MessageEvent<string>).data) as ServerEventData)
+    })
+  }
+  source.onerror = () => {
+    dropped = true
+  }
+  source.onopen = () => {
+    // Events sent while disconnected are lost, so let the caller resync
+    if (dropped) {
+      dropped = false
+      onReconnect?.()
+    }
+  }
+  return () => source.close()
+}
+
+export interface RefreshRun {
+  id: string
+  trigger: 'manual' | 'scheduled' | 'single-feed'
+  startedAt: string
+  finishedAt: string
+  feedsTotal: number
+  feedsFailed: number
+  entriesNew: number
+  entriesUpdated: number
+  entriesEvicted: number
+}
+
+export interface RefreshRunFeed {
+  feedId: string
+  feedTitle: string
+  status: 'ok' | 'failed'
+  entriesNew: number
+  entriesUpdated: number
+  entriesEvicted: number
+  errorMessage?: string
+}
+
+export interface RefreshRunDetail {
+  run: RefreshRun
+  feeds: RefreshRunFeed[]
+}
+
+export async function listRefreshRuns(): Promise<RefreshRun[]> {
+  return request<RefreshRun[]>('/api/refresh/runs')
+}
+
+export async function getRefreshRun(id: string): Promise<RefreshRunDetail> {
+  return request<RefreshRunDetail>(`/api/refresh/runs/${id}`)
+}
+
+export interface FeedFetch {
+  fetchedAt: string
+  statusCode: number
+  durationMs: number
+  bytes: number
+  outcome: 'ok' | 'not_modified' | 'http_error' | 'network_error' | 'parse_error'
+}
+
+export interface FeedFetchLog {
+  status: 'unknown' | 'ok' | 'unstable' | 'erroring'
+  successRatio: number
+  fetches: FeedFetch[]
+}
+
+export async function getFeedFetchLog(id: string): Promise<FeedFetchLog> {
+  return request<FeedFetchLog>(`/api/feeds/${id}/fetch-log`)
+}
+
+export async function getFeedIngestReport(id: string): Promise<FeedIngestReport> {
+  return request<FeedIngestReport>(`/api/feeds/${id}/ingest-report`)
+}
+
+export async function startArchiveBackfill(id: string, payload: { maxPages?: number; markRead?: boolean } = {}): Promise<ArchiveBackfill> {
+  return request<ArchiveBackfill>(`/api/feeds/${id}/backfill-archive`, {
+    method: 'POST',
+    body: JSON.stringify(payload),
+  })
+}
+
+export async function getArchiveBackfill(id: string): Promise<FeedArchiveBackfill> {
+  return request<FeedArchiveBackfill>(`/api/feeds/${id}/backfill-archive`)
+}
+
 export async function previewFeed(url: string): Promise<FeedPreview> {
   const params = new URLSearchParams({ url })
   return request<FeedPreview>(`/api/feeds/preview?${params.toString()}`)
 }
 
-export async function listEntries(params: EntryListParams = {}): Promise<EntryListResponse> {
+function entryFilterSearchParams(params: EntryListParams): URLSearchParams {
   const searchParams = new URLSearchParams()
 
   if (params.feedId !== undefined) {
@@ -433,6 +902,36 @@ export async function listEntries(params: EntryListParams = {}): Promise<EntryLi
   if (params.hasThumbnail) {
     searchParams.set('hasThumbnail', 'true')
   }
+  if (params.notesOnly) {
+    searchParams.set('notesOnly', 'true')
+  }
+  if (params.hasReadableContent) {
+    searchParams.set('hasReadableContent', 'true')
+  }
+  if (params.hasSummary) {
+    searchParams.set('hasSummary', 'true')
+  }
+  if (params.language) {
+    searchParams.set('language', params.language)
+  }
+  if (params.contentLanguage) {
+    searchParams.set('contentLanguage', params.contentLanguage)
+  }
+  if (params.minReadingMinutes !== undefined) {
+    searchParams.set('minReadingMinutes', String(params.minReadingMinutes))
+  }
+  if (params.maxReadingMinutes !== undefined) {
+    searchParams.set('maxReadingMinutes', String(params.maxReadingMinutes))
+  }
+  if (params.includeFeed) {
+    searchParams.set('include', 'feed')
+  }
+  return searchParams
+}
+
+export async function listEntries(params: EntryListParams = {}): Promise<EntryListResponse> {
+  const searchParams = entryFilterSearchParams(params)
+
   if (params.limit !== undefined) {
     searchParams.set('limit', String(params.limit))
   }
@@ -445,8 +944,90 @@ export async function listEntries(params: EntryListParams = {}): Promise<EntryLi
   return request<EntryListResponse>(path)
 }
 
-export async function getEntry(id: string): Promise<Entry> {
-  return request<Entry>(`/api/entries/${id}`)
+export async function searchEntries(params: EntrySearchParams): Promise<EntrySearchResponse> {
+  const searchParams = new URLSearchParams({ q: params.q })
+
+  if (params.searchIn !== undefined) {
+    searchParams.set('searchIn', params.searchIn)
+  }
+  if (params.feedId !== undefined) {
+    searchParams.set('feedId', params.feedId)
+  }
+  if (params.folderId !== undefined) {
+    searchParams.set('folderId', params.folderId)
+  }
+  if (params.boostUnread === false) {
+    searchParams.set('boostUnread', 'false')
+  }
+  if (params.includeFeed) {
+    searchParams.set('include', 'feed')
+  }
+  if (params.limit !== undefined) {
+    searchParams.set('limit', String(params.limit))
+  }
+  if (params.offset !== undefined) {
+    searchParams.set('offset', String(params.offset))
+  }
+
+  return request<EntrySearchResponse>(`/api/entries/search?${searchParams.toString()}`)
+}
+
+export async function countEntries(params: EntryListParams = {}): Promise<EntryCountResponse> {
+  const queryString = entryFilterSearchParams(params).toString()
+  const path = queryString ? `/api/entries/counts?${queryString}` : '/api/entries/counts'
+  return request<EntryCountResponse>(path)
+}
+
+export async function getEntry(id: string, includeFeed = false): Promise<Entry> {
+  return request<Entry>(includeFeed ? `/api/entries/${id}?include=feed` : `/api/entries/${id}`)
+}
+
+/** Fetches up to 100 entries in the order given; unknown ids are left out. */
+export async function getEntriesBulk(ids: string[], includeAI = false): Promise<BulkEntriesResponse> {
+  return request<BulkEntriesResponse>('/api/entries/bulk', {
+    method: 'POST',
+    body: JSON.stringify({ ids, includeAI }),
+  })
+}
+
+export async function getAdjacentEntry(
+  id: string,
+  direction: 'next' | 'prev',
+  params: EntryListParams = {}
+): Promise<Entry | undefined> {
+  const searchParams = entryFilterSearchParams(params)
+  searchParams.set('direction', direction)
+  return request<Entry | undefined>(`/api/entries/${id}/adjacent?${searchParams.toString()}`)
+}
+
+export async function getEntryRevisions(id: string): Promise<EntryRevisionListResponse> {
+  return request<EntryRevisionListResponse>(`/api/entries/${id}/revisions`)
+}
+
+export async function getEntryRawItem(id: string): Promise<string> {
+  return request<string>(`/api/entries/${id}/raw`)
+}
+
+export async function getDailyDigest(date?: string, perFeed?: number): Promise<DailyDigest> {
+  const searchParams = new URLSearchParams()
+  if (date) searchParams.set('date', date)
+  if (perFeed) searchParams.set('perFeed', String(perFeed))
+  const query = searchParams.toString()
+  return request<DailyDigest>(`/api/digest${query ? `?${query}` : ''}`)
+}
+
+export async function getReadingStats(days?: number): Promise<ReadingStats> {
+  const query = days ? `?days=${days}` : ''
+  return request<ReadingStats>(`/api/stats/reading${query}`)
+}
+
+export async function getClickStats(days?: number): Promise<ClickStats> {
+  const query = days ? `?days=${days}` : ''
+  return request<ClickStats>(`/api/stats/clicks${query}`)
+}
+
+export function getEntryVisitUrl(id: string, markRead = true): string {
+  return `${API_BASE_URL}/api/entries/${id}/visit${markRead ? '' : '?markRead=false'}`
 }
 
 export async function updateEntryReadStatus(id: string, read: boolean): Promise<void> {
@@ -463,11 +1044,20 @@ export async function updateManyEntryReadStatus(ids: string[], read: boolean): P
   })
 }
 
+const READABLE_POLL_INTERVAL_MS = 1000
+
 export async function fetchReadableContent(id: string): Promise<string> {
-  const response = await request<{ readableContent: string }>(`/api/entries/${id}/fetch-readable`, {
-    method: 'POST',
-  })
-  return response.readableContent
+  // Slow pages are extracted in the background; ask again until it finishes
+  for (;;) {
+    const response = await request<{ status: 'ready' | 'pending'; readableContent?: string }>(
+      `/api/entries/${id}/fetch-readable`,
+      { method: 'POST' }
+    )
+    if (response.status !== 'pending') {
+      return response.readableContent ?? ''
+    }
+    await new Promise((resolve) => setTimeout(resolve, READABLE_POLL_INTERVAL_MS))
+  }
 }
 
 export async function markAllAsRead(params: MarkAllReadParams): Promise<void> {
@@ -488,13 +1078,46 @@ export async function updateEntryStarred(id: string, starred: boolean): Promise<
   })
 }
 
+export async function updateEntryNote(id: string, note: string): Promise<void> {
+  return request<void>(`/api/entries/${id}/note`, {
+    method: 'PUT',
+    body: JSON.stringify({ note }),
+  })
+}
+
+export async function deleteEntryNote(id: string): Promise<void> {
+  return request<void>(`/api/entries/${id}/note`, {
+    method: 'DELETE',
+  })
+}
+
+export async function savePage(url: string): Promise<Entry> {
+  return request<Entry>('/api/saved', {
+    method: 'POST',
+    body: JSON.stringify({ url }),
+  })
+}
+
+export async function deleteSavedPage(entryId: string): Promise<void> {
+  return request<void>(`/api/saved/${entryId}`, {
+    method: 'DELETE',
+  })
+}
+
 export async function getStarredCount(): Promise<StarredCountResponse> {
   return request<StarredCountResponse>('/api/starred-count')
 }
 
-export async function startImportOPML(file: File): Promise<void> {
+export async function getStarredArchiveStatus(): Promise<ArchiveStatusResponse> {
+  return request<ArchiveStatusResponse>('/api/entries/starred/archive-status')
+}
+
+export async function startImportOPML(file: File, initialBackfill?: InitialBackfill): Promise<void> {
   const formData = new FormData()
   formData.append('file', file)
+  if (initialBackfill !== undefined) {
+    formData.append('initialBackfill', String(initialBackfill))
+  }
 
   const url = `${API_BASE_URL}/api/opml/import`
   const headers: HeadersInit = {}
@@ -600,6 +1223,37 @@ export async function exportOPML(): Promise<void> {
   URL.revokeObjectURL(url)
 }
 
+export async function exportFullBackup(entries: BackupEntries = 'unread_starred'): Promise<void> {
+  const headers: HeadersInit = {}
+  const token = getAuthToken()
+  if (token) {
+    headers['Authorization'] = `Bearer ${token}`
+  }
+
+  const response = await fetch(`${API_BASE_URL}/api/export/full?entries=${entries}`, { headers })
+  if (!response.ok) {
+    throw new ApiError('Export failed', response.status)
+  }
+  const blob = await response.blob()
+  const url = URL.createObjectURL(blob)
+  const a = document.createElement('a')
+  a.href = url
+  a.download = 'gist-backup.json'
+  document.body.appendChild(a)
+  a.click()
+  document.body.removeChild(a)
+  URL.revokeObjectURL(url)
+}
+
+export async function importFullBackup(file: File): Promise<BackupImportResult> {
+  const formData = new FormData()
+  formData.append('file', file)
+  return request<BackupImportResult>('/api/import/full', {
+    method: 'POST',
+    body: formData,
+  })
+}
+
 export async function getAISettings(): Promise<AISettings> {
   return request<AISettings>('/api/settings/ai')
 }
@@ -651,6 +1305,17 @@ export async function updateAppearanceSettings(settings: AppearanceSettings): Pr
   })
 }
 
+export async function getSecuritySettings(): Promise<SecuritySettings> {
+  return request<SecuritySettings>('/api/settings/security')
+}
+
+export async function updateSecuritySettings(settings: SecuritySettings): Promise<SecuritySettings> {
+  return request<SecuritySettings>('/api/settings/security', {
+    method: 'PUT',
+    body: JSON.stringify(settings),
+  })
+}
+
 export async function testNetworkProxy(config: NetworkTestRequest): Promise<NetworkTestResponse> {
   return request<NetworkTestResponse>('/api/settings/network/test', {
     method: 'POST',
@@ -663,6 +1328,7 @@ export interface SummarizeRequest {
   content: string
   title?: string
   isReadability?: boolean
+  language?: string
 }
 
 export interface SummarizeResponse {
@@ -713,11 +1379,68 @@ export async function* streamSummary(
   }
 }
 
+export interface DigestRequest {
+  folderId?: string
+  feedId?: string
+  since: string
+  until?: string
+  language?: string
+}
+
+export interface DigestResponse {
+  digest: string
+  cached: boolean
+  entries: number
+}
+
+export async function* streamDigest(
+  req: DigestRequest,
+  signal?: AbortSignal
+): AsyncGenerator<string | (DigestResponse & { done: true })> {
+  const url = `${API_BASE_URL}/api/ai/digest`
+  const response = await fetchWithAuth(url, {
+    method: 'POST',
+    body: JSON.stringify(req),
+    signal,
+  })
+
+  const contentType = response.headers.get('Content-Type') ?? ''
+
+  // Cached digests and windows without unread entries return JSON
+  if (contentType.includes('application/json')) {
+    const data = (await response.json()) as DigestResponse
+    yield { ...data, done: true }
+    return
+  }
+
+  if (!response.body) {
+    throw new ApiError('No response body', 500)
+  }
+
+  const reader = response.body.getReader()
+  const decoder = new TextDecoder()
+
+  try {
+    while (true) {
+      const { done, value } = await reader.read()
+      if (done) break
+
+      const text = decoder.decode(value, { stream: true })
+      if (text) {
+        yield text
+      }
+    }
+  } finally {
+    reader.releaseLock()
+  }
+}
+
 export interface TranslateRequest {
   entryId: string
   content: string
   title?: string
   isReadability?: boolean
+  language?: string
 }
 
 export interface TranslateResponse {
@@ -737,6 +1460,8 @@ export interface TranslateInit {
 
 export interface TranslateBlockResult {
   index: number
+  /** Last block the html replaces; blocks after index up to it were translated together */
+  endIndex?: number
   html: string
 }
 
@@ -825,18 +1550,36 @@ export interface BatchTranslateResult {
  */
 export async function* streamBatchTranslate(
   articles: BatchTranslateArticle[],
-  signal?: AbortSignal
+  signal?: AbortSignal,
+  language?: string
 ): AsyncGenerator<BatchTranslateResult> {
   const url = `${API_BASE_URL}/api/ai/translate/batch`
   const response = await fetchWithAuth(url, {
     method: 'POST',
-    body: JSON.stringify({ articles }),
+    body: JSON.stringify({ articles, language }),
     signal,
   })
 
   yield* readNDJSONLines<BatchTranslateResult>(response)
 }
 
+export interface FeedTitleTranslation {
+  feedId: string
+  title: string
+  cached: boolean
+  unchanged: boolean
+}
+
+export async function translateFeedTitles(
+  feedIds: string[],
+  language?: string
+): Promise<FeedTitleTranslation[]> {
+  return request<FeedTitleTranslation[]>('/api/ai/translate/feeds', {
+    method: 'POST',
+    body: JSON.stringify({ feedIds, language }),
+  })
+}
+
 export interface ClearAICacheResponse {
   summaries: number
   translations: number
@@ -849,6 +1592,55 @@ export async function clearAICache(): Promise<ClearAICacheResponse> {
   })
 }
 
+export interface AIUsageDay {
+  date: string
+  operation: 'summarize' | 'translate' | 'list_translate' | 'digest'
+  calls: number
+  promptTokens: number
+  completionTokens: number
+  cost: number
+}
+
+export interface AIUsageResponse {
+  from: string
+  to: string
+  days: AIUsageDay[]
+  promptTokens: number
+  completionTokens: number
+  cost: number
+  unpricedModels: string[]
+}
+
+export async function getAIUsage(from?: string, to?: string): Promise<AIUsageResponse> {
+  const params = new URLSearchParams()
+  if (from) params.set('from', from)
+  if (to) params.set('to', to)
+  const query = params.toString()
+  return request<AIUsageResponse>(`/api/ai/usage${query ? `?${query}` : ''}`)
+}
+
+export interface AutoTranslateRun {
+  startedAt: string
+  finishedAt: string
+  entries: number
+  /** Entries of feeds already in the target language, and entries without text */
+  skipped: number
+  translated: number
+  failed: number
+  error?: string
+}
+
+export interface AutoTranslateStatus {
+  enabled: boolean
+  running: boolean
+  language: string
+  lastRun?: AutoTranslateRun
+}
+
+export async function getAutoTranslateStatus(): Promise<AutoTranslateStatus> {
+  return request<AutoTranslateStatus>('/api/ai/auto-translate/status')
+}
+
 export interface ClearCacheResponse {
   deleted: number
 }
@@ -883,17 +1675,17 @@ export async function getDomainRateLimits(): Promise<DomainRateLimitListResponse
   return request<DomainRateLimitListResponse>('/api/domain-rate-limits')
 }
 
-export async function createDomainRateLimit(host: string, intervalSeconds: number): Promise<DomainRateLimit> {
+export async function createDomainRateLimit(host: string, intervalSeconds: number, maxConcurrent = 0): Promise<DomainRateLimit> {
   return request<DomainRateLimit>('/api/domain-rate-limits', {
     method: 'POST',
-    body: JSON.stringify({ host, intervalSeconds }),
+    body: JSON.stringify({ host, intervalSeconds, maxConcurrent }),
   })
 }
 
-export async function updateDomainRateLimit(host: string, intervalSeconds: number): Promise<DomainRateLimit> {
+export async function updateDomainRateLimit(host: string, intervalSeconds: number, maxConcurrent = 0): Promise<DomainRateLimit> {
   return request<DomainRateLimit>(`/api/domain-rate-limits/${encodeURIComponent(host)}`, {
     method: 'PUT',
-    body: JSON.stringify({ intervalSeconds }),
+    body: JSON.stringify({ intervalSeconds, maxConcurrent }),
   })
 }
 
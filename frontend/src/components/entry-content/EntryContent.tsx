@@ -3,6 +3,7 @@ import { useTranslation } from 'react-i18next'
 import { useEntry, useMarkAsRead, useMarkAsStarred, useRemoveFromUnreadList } from '@/hooks/useEntries'
 import { useAISettings } from '@/hooks/useAISettings'
 import { useGeneralSettings } from '@/hooks/useGeneralSettings'
+import { useFeeds } from '@/hooks/useFeeds'
 import { useEntryContentScroll } from '@/hooks/useEntryContentScroll'
 import { useScrollToTop } from '@/hooks/useScrollToTop'
 import { useReadability } from '@/hooks/useReadability'
@@ -22,6 +23,7 @@ export function EntryContent({ entryId, isMobile, onBack }: EntryContentProps) {
   const { data: entry, isLoading } = useEntry(entryId)
   const { data: aiSettings } = useAISettings()
   const { data: generalSettings } = useGeneralSettings()
+  const { data: feeds = [] } = useFeeds()
   const { mutate: markAsRead } = useMarkAsRead()
   const { mutate: markAsStarred } = useMarkAsStarred()
   const removeFromUnreadList = useRemoveFromUnreadList()
@@ -34,7 +36,9 @@ export function EntryContent({ entryId, isMobile, onBack }: EntryContentProps) {
 
   const autoTranslate = aiSettings?.autoTranslate ?? false
   const targetLanguage = aiSettings?.summaryLanguage ?? 'zh-CN'
-  const autoReadability = generalSettings?.autoReadability ?? false
+  // The feed or its folders can override the general setting
+  const feed = entry ? feeds.find((f) => f.id === entry.feedId) : undefined
+  const autoReadability = feed?.effective?.autoReadability ?? generalSettings?.autoReadability ?? false
   const autoSummary = aiSettings?.autoSummary ?? false
 
   // Readability hook
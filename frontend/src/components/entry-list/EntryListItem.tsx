@@ -78,7 +78,7 @@ export const EntryListItem = forwardRef<HTMLDivElement, EntryListItemProps>(
         <div className="flex min-w-0 items-center gap-1.5 overflow-hidden text-xs text-muted-foreground">
           {showIcon ? (
             <img
-              src={`/icons/${feed.iconPath}`}
+              src={`/icons/${feed.iconPath}?size=32`}
               alt=""
               className="size-4 shrink-0 rounded object-contain"
               onError={() => setIconError(true)}
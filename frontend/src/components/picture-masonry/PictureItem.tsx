@@ -3,7 +3,7 @@ import { useTranslation } from 'react-i18next'
 import { Play } from 'lucide-react'
 import { cn } from '@/lib/utils'
 import { getEntryImages } from '@/lib/extract-images'
-import { getProxiedImageUrl } from '@/lib/image-proxy'
+import { getEntryThumbnailUrl } from '@/lib/image-proxy'
 import { isVideoThumbnail } from '@/lib/media-utils'
 import { formatRelativeTime } from '@/lib/date-utils'
 import { useLightboxStore } from '@/stores/lightbox-store'
@@ -46,13 +46,18 @@ export const PictureItem = memo(function PictureItem({
   const handleImageLoad = useCallback(
     (e: React.SyntheticEvent<HTMLImageElement>) => {
       const img = e.currentTarget
+      // The server answers with a 1x1 transparent pixel when the image can't be loaded
+      if (img.naturalWidth === 1 && img.naturalHeight === 1 && thumbnailUrl) {
+        markFailed(thumbnailUrl)
+        return
+      }
       if (img.naturalWidth && img.naturalHeight && thumbnailUrl) {
         // Save dimensions to store (which also persists to IndexedDB)
         setDimension(thumbnailUrl, img.naturalWidth, img.naturalHeight)
       }
       setImageLoaded(true)
     },
-    [thumbnailUrl, setDimension]
+    [thumbnailUrl, setDimension, markFailed]
   )
 
   const handleClick = useCallback(() => {
@@ -83,7 +88,7 @@ export const PictureItem = memo(function PictureItem({
           style={{ aspectRatio }}
         >
           <img
-            src={getProxiedImageUrl(thumbnailUrl, entry.url ?? undefined)}
+            src={getEntryThumbnailUrl(entry.id)}
             alt={entry.title || ''}
             className={cn(
               'size-full object-cover transition-opacity duration-300',
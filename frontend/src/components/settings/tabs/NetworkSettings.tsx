@@ -79,7 +79,12 @@ export function NetworkSettings() {
       const result = await testNetworkProxy(settings)
       if (result.success) {
         setTestStatus('success')
-        setTestMessage(result.message || t('settings.proxy_test_success'))
+        const details = [
+          result.latencyMs !== undefined ? `${result.latencyMs} ms` : '',
+          result.egressIp ?? '',
+        ].filter(Boolean)
+        const message = result.message || t('settings.proxy_test_success')
+        setTestMessage(details.length > 0 ? `${message} (${details.join(', ')})` : message)
       } else {
         setTestStatus('error')
         setTestMessage(result.error || t('settings.proxy_test_failed'))
@@ -91,7 +91,7 @@ export function FeedItem({
             <span className={sidebarItemIconStyles}>
               {iconPath && !iconError ? (
                 <img
-                  src={`/icons/${iconPath}`}
+                  src={`/icons/${iconPath}?size=32`}
                   alt=""
                   className="size-4 rounded-sm object-cover"
                   onError={() => setIconError(true)}
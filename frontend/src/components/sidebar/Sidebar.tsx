@@ -207,7 +207,7 @@ export function Sidebar({
       if (sortBy === 'date') {
         sorted.sort((a, b) => new Date(a.createdAt).getTime() - new Date(b.createdAt).getTime())
       } else {
-        sorted.sort((a, b) => compareNames(a.title, b.title))
+        sorted.sort((a, b) => (a.sortOrder ?? 0) - (b.sortOrder ?? 0) || compareNames(a.title, b.title))
       }
       return sorted
     },
@@ -220,7 +220,9 @@ export function Sidebar({
     if (sortBy === 'date') {
       sorted.sort((a, b) => new Date(a.folder.createdAt).getTime() - new Date(b.folder.createdAt).getTime())
     } else {
-      sorted.sort((a, b) => compareNames(a.folder.name, b.folder.name))
+      sorted.sort(
+        (a, b) => (a.folder.sortOrder ?? 0) - (b.folder.sortOrder ?? 0) || compareNames(a.folder.name, b.folder.name)
+      )
     }
     return sorted.map((item) => ({
       ...item,
@@ -206,6 +206,42 @@ describe('useAITranslation', () => {
     expect(result.current.isTranslating).toBe(true)
   })
 
+  it('合并翻译的结果会替换其覆盖的全部原始块', async () => {
+    mockStreamTranslateBlocks.mockImplementation(() =>
+      (async function* () {
+        yield {
+          blocks: [
+            { index: 0, html: '<p>One</p>', needTranslate: true },
+            { index: 1, html: '<p>Two</p>', needTranslate: true },
+            { index: 2, html: '<img src="a.png">', needTranslate: false },
+          ],
+        }
+        yield { index: 0, endIndex: 1, html: '<p>一</p><p>二</p>' }
+        yield { done: true }
+      })()
+    )
+
+    const entry = createEntry('<p>One</p><p>Two</p><img src="a.png">')
+    const { result } = renderHook(() =>
+      useAITranslation({
+        entry,
+        isReadableActive: false,
+        readableContent: null,
+        autoTranslate: true,
+        targetLanguage: 'zh-CN',
+      })
+    )
+
+    await waitFor(() => {
+      expect(result.current.combinedTranslatedContent).toBe('<p>一</p><p>二</p><img src="a.png">')
+    })
+    expect(result.current.translatedContentBlocks?.map(block => block.html)).toEqual([
+      '<p>一</p><p>二</p>',
+      '',
+      '<img src="a.png">',
+    ])
+  })
+
   it('切回原始内容后会重新触发自动翻译', async () => {
     mockNeedsTranslation.mockImplementation((_title: string, summary: string | null) => {
       return !(summary ?? '').includes('already-target')
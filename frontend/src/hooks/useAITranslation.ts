@@ -186,6 +186,10 @@ export function useAITranslation({
           setTranslatedBlocks(prev => {
             const newMap = new Map(prev)
             newMap.set(event.index, event.html)
+            // Merged blocks are all in the first one's html
+            for (let i = event.index + 1; i <= (event.endIndex ?? event.index); i++) {
+              newMap.set(i, '')
+            }
             return newMap
           })
         }
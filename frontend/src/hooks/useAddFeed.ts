@@ -96,7 +96,7 @@ export function useAddFeed(contentType: ContentType = 'article'): UseAddFeedRetu
       await queryClient.invalidateQueries({ queryKey: ['unreadCounts'] })
       return true
     } catch (err) {
-      if (err instanceof ApiError && err.message === 'feed_exists') {
+      if (err instanceof ApiError && err.code === 'feed_conflict') {
         setError(t('add_feed.feed_exists'))
       } else {
         setError(getErrorMessage(err, 'Failed to subscribe to feed.'))
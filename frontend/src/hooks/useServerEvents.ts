@@ -0,0 +1,75 @@
+import { useEffect } from 'react'
+import { useQueryClient } from '@tanstack/react-query'
+import { subscribeEvents } from '@/api'
+
+/**
+ * Listens to the backend event stream and invalidates the caches a change
+ * touches, so new entries and changes from other devices show up without
+ * waiting for the next poll.
+ *
+ * Mount this hook once at the app level (e.g., AuthenticatedApp).
+ */
+export function useServerEvents() {
+  const queryClient = useQueryClient()
+
+  useEffect(() => {
+    const invalidateAll = () => {
+      queryClient.invalidateQueries({ queryKey: ['entries'] })
+      queryClient.invalidateQueries({ queryKey: ['unreadCounts'] })
+      queryClient.invalidateQueries({ queryKey: ['starredCount'] })
+      queryClient.invalidateQueries({ queryKey: ['feeds'] })
+      queryClient.invalidateQueries({ queryKey: ['folders'] })
+    }
+
+    return subscribeEvents((type, data) => {
+      switch (type) {
+        case 'refresh.started':
+          queryClient.invalidateQueries({ queryKey: ['refreshStatus'] })
+          break
+        case 'refresh.finished':
+        case 'entries.new':
+          queryClient.invalidateQueries({ queryKey: ['entries'] })
+          queryClient.invalidateQueries({ queryKey: ['unreadCounts'] })
+          queryClient.invalidateQueries({ queryKey: ['refreshStatus'] })
+          break
+        case 'entries.read':
+        case 'entry.starred':
+          // Only mark the list stale: refetching it now would reorder or drop
+          // entries the user is reading, e.g. in unread-only view
+          queryClient.invalidateQueries({ queryKey: ['entries'], refetchType: 'none' })
+          queryClient.invalidateQueries({ queryKey: ['unreadCounts'] })
+          queryClient.invalidateQueries({ queryKey: ['starredCount'] })
+          if (data.entryId) {
+            queryClient.invalidateQueries({ queryKey: ['entry', data.entryId] })
+          }
+          for (const id of data.entryIds ?? []) {
+            queryClient.invalidateQueries({ queryKey: ['entry', id] })
+          }
+          break
+        case 'entry.readable':
+          if (data.entryId && data.status === 'ready') {
+            queryClient.invalidateQueries({ queryKey: ['entry', data.entryId] })
+          }
+          break
+        case 'feed.created':
+        case 'feed.updated':
+        case 'feed.deleted':
+          queryClient.invalidateQueries({ queryKey: ['feeds'] })
+          queryClient.invalidateQueries({ queryKey: ['unreadCounts'] })
+          if (type === 'feed.deleted') {
+            queryClient.invalidateQueries({ queryKey: ['entries'] })
+          }
+          break
+        case 'folder.created':
+        case 'folder.updated':
+        case 'folder.deleted':
+          queryClient.invalidateQueries({ queryKey: ['folders'] })
+          if (type === 'folder.deleted') {
+            queryClient.invalidateQueries({ queryKey: ['feeds'] })
+            queryClient.invalidateQueries({ queryKey: ['entries'] })
+          }
+          break
+      }
+    }, invalidateAll)
+  }, [queryClient])
+}
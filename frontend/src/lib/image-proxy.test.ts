@@ -1,5 +1,5 @@
-import { describe, it, expect } from 'vitest'
-import { getProxiedImageUrl } from './image-proxy'
+import { describe, it, expect, vi, afterEach } from 'vitest'
+import { getEntryThumbnailUrl, getProxiedImageUrl } from './image-proxy'
 
 describe('image-proxy', () => {
   describe('toAbsoluteUrl (tested via getProxiedImageUrl)', () => {
@@ -23,6 +23,11 @@ describe('image-proxy', () => {
       expect(getProxiedImageUrl(proxied)).toBe(proxied)
     })
 
+    it('should return locally cached image URL as-is', () => {
+      const cached = '/cached-images/123/abc.jpg'
+      expect(getProxiedImageUrl(cached, 'https://example.com/post')).toBe(cached)
+    })
+
     it('should resolve relative URL with base URL', () => {
       const url = getProxiedImageUrl('image.jpg', 'https://example.com/article/post.html')
       expect(url).toContain('/api/proxy/image/')
@@ -108,4 +113,20 @@ describe('image-proxy', () => {
       expect(url).toContain('?ref=')
     })
   })
+
+  describe('getEntryThumbnailUrl', () => {
+    afterEach(() => {
+      vi.unstubAllGlobals()
+    })
+
+    it('should request the default width', () => {
+      vi.stubGlobal('devicePixelRatio', 1)
+      expect(getEntryThumbnailUrl('123')).toBe('/api/entries/123/thumbnail?w=400')
+    })
+
+    it('should double the width on high-density screens', () => {
+      vi.stubGlobal('devicePixelRatio', 2)
+      expect(getEntryThumbnailUrl('123', 300)).toBe('/api/entries/123/thumbnail?w=600')
+    })
+  })
 })
@@ -14,8 +14,8 @@ export function toAbsoluteUrl(url: string, baseUrl: string | undefined): string
     return `https:${url}`
   }
 
-  // Data URI or already proxied - return as is
-  if (url.startsWith('data:') || url.startsWith('/api/')) {
+  // Data URI, already proxied or served from the local image cache - return as is
+  if (url.startsWith('data:') || url.startsWith('/api/') || url.startsWith('/cached-images/')) {
     return url
   }
 
@@ -59,8 +59,8 @@ export function getProxiedImageUrl(src: string, articleUrl?: string): string {
   const absoluteUrl = toAbsoluteUrl(src, articleUrl)
   if (!absoluteUrl) return src
 
-  // Skip data URIs and already proxied URLs
-  if (absoluteUrl.startsWith('data:') || absoluteUrl.startsWith('/api/')) {
+  // Skip data URIs, already proxied URLs and locally cached images
+  if (absoluteUrl.startsWith('data:') || absoluteUrl.startsWith('/api/') || absoluteUrl.startsWith('/cached-images/')) {
     return absoluteUrl
   }
 
@@ -71,3 +71,12 @@ export function getProxiedImageUrl(src: string, articleUrl?: string): string {
   }
   return url
 }
+
+/**
+ * Get the URL of an entry thumbnail scaled down by the server for the picture grid.
+ * The width doubles on high-density screens.
+ */
+export function getEntryThumbnailUrl(entryId: string, width = 400): string {
+  const scale = typeof window !== 'undefined' && window.devicePixelRatio > 1 ? 2 : 1
+  return `/api/entries/${entryId}/thumbnail?w=${width * scale}`
+}
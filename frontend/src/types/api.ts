@@ -1,29 +1,141 @@
 export type ContentType = 'article' | 'picture' | 'notification'
 
+/** Items a new feed brings in on its first fetch: all, none (saved as read), or the N most recent */
+export type InitialBackfill = 'all' | 'none' | number
+
 export interface Folder {
   id: string
   name: string
   parentId?: string
   type: ContentType
+  sortOrder?: number
+  /** Options the folder's feeds and subfolders inherit; absent ones come from the parent folder */
+  feedDefaults?: FolderFeedDefaults
+  createdAt: string
+  updatedAt: string
+}
+
+export interface FolderFeedDefaults {
+  userAgent?: string
+  autoReadability?: boolean
+  autoTranslate?: boolean
+}
+
+/** Field a folder rule matches: the feed URL's host (subdomains included), the full URL, or the title */
+export type FolderRuleMatchField = 'host' | 'url' | 'title'
+
+export interface FolderRule {
+  id: string
+  folderId: string
+  /** Domain for host rules; a glob with * and ?, or a /regex/, for url and title rules */
+  pattern: string
+  matchField: FolderRuleMatchField
+  /** Lower priorities are tried first */
+  priority: number
   createdAt: string
   updatedAt: string
 }
 
+export type DedupeKey = 'auto' | 'guid' | 'url' | 'title_content'
+
+/** Whether new entries get list translations after refreshes; inherit follows the feed's folders, then the translateOnRefresh AI setting */
+export type FeedAutoTranslate = 'inherit' | 'on' | 'off'
+
 export interface Feed {
   id: string
   folderId?: string
   title: string
+  /** Title the feed itself declares; title is the rename when customTitle is set */
+  upstreamTitle?: string
+  customTitle?: string
   url: string
   siteUrl?: string
   description?: string
   summaryPromptReminder?: string
+  assumeTimezone?: string
+  dedupeKey?: DedupeKey
+  autoTranslate?: FeedAutoTranslate
+  /** Language the feed declares, as a primary subtag such as en */
+  language?: string
+  preferredUserAgent?: string
+  translatedTitle?: string
   iconPath?: string
   type: ContentType
+  sortOrder?: number
   etag?: string
   lastModified?: string
   errorMessage?: string
+  paused?: boolean
+  pausedUntil?: string
+  lastFetchedAt?: string
+  /** Set while the feed's 304 answers are suspected of hiding new entries */
+  suspectCachingAt?: string
+  /** Shortest time between scheduled refreshes, 0 when refreshed on every run */
+  pollIntervalSeconds?: number
+  pollIntervalSource?: 'ttl' | 'syndication' | 'host'
+  /** Entries kept before the oldest read ones are evicted, 0 when unlimited */
+  maxEntries?: number
+  /** The feed's own User-Agent and readability choice, absent when inherited */
+  userAgent?: string
+  autoReadability?: boolean
+  /** Options after inheritance from the feed's folders, only set by the list */
+  effective?: FeedEffectiveConfig
   createdAt: string
   updatedAt: string
+  stats?: FeedStats
+}
+
+/** Fields of a feed to change at once; left out fields stay, null clears */
+export interface FeedPatch {
+  title?: string
+  folderId?: string | null
+  summaryPromptReminder?: string | null
+  maxEntries?: number
+  type?: ContentType
+  assumeTimezone?: string | null
+  dedupeKey?: DedupeKey
+  autoTranslate?: FeedAutoTranslate
+  userAgent?: string | null
+  autoReadability?: boolean | null
+  /** RFC3339 time in the future */
+  pausedUntil?: string | null
+}
+
+/** Two feeds that look like the same subscription; merged only on request */
+export interface FeedOverlap {
+  id: string
+  feed: Feed
+  otherFeed: Feed
+  /** Both feeds declare the same site URL */
+  sameSite: boolean
+  /** Recent entries of one feed the other has too, out of the last 50 */
+  sharedEntries: number
+  overlapRatio: number
+  detectedAt: string
+}
+
+/** Where an effective option is set; default means the global setting */
+export interface FeedConfigSource {
+  source: 'feed' | 'folder' | 'default'
+  folderId?: string
+  folderName?: string
+}
+
+/** Absent values follow the global setting */
+export interface FeedEffectiveConfig {
+  userAgent?: string
+  userAgentSource: FeedConfigSource
+  autoReadability?: boolean
+  autoReadabilitySource: FeedConfigSource
+  autoTranslate?: boolean
+  autoTranslateSource: FeedConfigSource
+}
+
+export interface FeedStats {
+  feedId: string
+  entriesLastWeek: number
+  lastPublishedAt?: string
+  totalEntries: number
 }
 
 export interface FeedPreview {
@@ -34,6 +146,7 @@ export interface FeedPreview {
   imageUrl?: string
   itemCount?: number
   lastUpdated?: string
+  suggestedType: ContentType
 }
 
 export interface Entry {
@@ -48,13 +161,129 @@ export interface Entry {
   publishedAt?: string
   read: boolean
   starred: boolean
+  wordCount: number
+  readingMinutes: number
   createdAt: string
   updatedAt: string
+  /** The feed's declared language, else one detected from the text; unset when unknown */
+  language?: string
+  note?: string
+  hasNote?: boolean
+  /** Set on a single entry whose author is muted in its feed */
+  authorMuted?: boolean
+  /** Set when the entry was fetched with includeFeed */
+  feedTitle?: string
+  feedIconPath?: string
+  feedType?: ContentType
+}
+
+export interface BulkEntry extends Entry {
+  /** Cached AI summary, only with includeAI */
+  summary?: string
+}
+
+export interface BulkEntriesResponse {
+  entries: BulkEntry[]
 }
 
 export interface EntryListResponse {
   entries: Entry[]
   hasMore: boolean
+  /** False for a delta list, asked for with since or If-Modified-Since */
+  full?: boolean
+  /** Ids of entries deleted since then, on the first page of a delta list */
+  deleted?: string[]
+}
+
+export interface EntryRevisionDiffLine {
+  op: 'equal' | 'insert' | 'delete'
+  text: string
+}
+
+export interface EntryRevision {
+  id: string
+  title?: string
+  content?: string
+  createdAt: string
+  diff: EntryRevisionDiffLine[]
+}
+
+export interface EntryRevisionListResponse {
+  revisions: EntryRevision[]
+}
+
+export interface DigestEntry {
+  id: string
+  feedId: string
+  title?: string
+  url?: string
+  thumbnailUrl?: string
+  author?: string
+  publishedAt?: string
+  read: boolean
+  starred: boolean
+  readingMinutes: number
+  summary?: string
+}
+
+export interface DigestFeed {
+  id: string
+  title: string
+  unreadCount: number
+  total: number
+  entries: DigestEntry[]
+}
+
+export interface DigestFolder {
+  id?: string
+  name: string
+  unreadCount: number
+  feeds: DigestFeed[]
+}
+
+export interface DailyDigest {
+  date: string
+  timezone: string
+  start: string
+  end: string
+  folders: DigestFolder[]
+}
+
+export interface ReadingDay {
+  date: string
+  count: number
+}
+
+export interface ReadingFeed {
+  feedId: string
+  title: string
+  count: number
+}
+
+export interface ReadingStats {
+  days: number
+  timezone: string
+  since: string
+  daily: ReadingDay[]
+  topFeeds: ReadingFeed[]
+  averageReadDelaySeconds?: number
+  totalRead: number
+  totalStarred: number
+}
+
+export interface FeedClicks {
+  feedId: string
+  title: string
+  count: number
+  lastClickedAt?: string
+}
+
+export interface ClickStats {
+  days: number
+  timezone: string
+  since: string
+  /** Every feed, most clicked first; feeds never clicked have count 0 */
+  feeds: FeedClicks[]
 }
 
 export interface EntryListParams {
@@ -64,18 +293,69 @@ export interface EntryListParams {
   unreadOnly?: boolean
   starredOnly?: boolean
   hasThumbnail?: boolean
+  notesOnly?: boolean
+  hasReadableContent?: boolean
+  /** Entries with a cached AI summary in language, or the configured summary language */
+  hasSummary?: boolean
+  language?: string
+  /** Entries in this language, a primary subtag such as en */
+  contentLanguage?: string
+  minReadingMinutes?: number
+  maxReadingMinutes?: number
+  /** Inline each entry's feed title, icon and type */
+  includeFeed?: boolean
+  limit?: number
+  offset?: number
+}
+
+export type EntrySearchIn = 'all' | 'title' | 'content'
+
+export interface EntrySearchParams {
+  q: string
+  /** Where to match; all also covers author and URL */
+  searchIn?: EntrySearchIn
+  feedId?: string
+  /** Searches the folder and its subfolders */
+  folderId?: string
+  /** Rank unread entries a little higher (default true) */
+  boostUnread?: boolean
+  includeFeed?: boolean
   limit?: number
   offset?: number
 }
 
+export interface EntrySearchResponse {
+  entries: Entry[]
+  hasMore: boolean
+}
+
 export interface UnreadCountsResponse {
   counts: Record<string, number>
+  today: Record<string, number>
 }
 
 export interface StarredCountResponse {
   count: number
 }
 
+export interface EntryCountResponse {
+  count: number
+}
+
+export interface ArchiveFailure {
+  entryId: string
+  feedId: string
+  title?: string
+  url?: string
+  attempts: number
+  lastError?: string
+  failedAt?: string
+}
+
+export interface ArchiveStatusResponse {
+  failed: ArchiveFailure[]
+}
+
 export interface MarkAllReadParams {
   feedId?: string
   folderId?: string
@@ -83,7 +363,9 @@ export interface MarkAllReadParams {
 }
 
 export interface ApiErrorResponse {
-  error: string
+  code: string
+  message: string
+  details?: Record<string, unknown>
 }
 
 export interface ImportResult {
@@ -93,6 +375,147 @@ export interface ImportResult {
   feedsSkipped: number
 }
 
+export interface StaticFeedUpdateResult {
+  entriesNew: number
+  entriesUpdated: number
+}
+
+/** Authors whose entries are left out of a feed's unread list. */
+export interface MutedAuthorsResponse {
+  authors: string[]
+}
+
+/** Token for pushing entries into a static feed via POST /api/feeds/:id/entries. */
+export interface IngestTokenResponse {
+  token: string
+}
+
+/** Diagnostic report of POST /api/feeds/:id/probe. */
+export interface FeedProbe {
+  url: string
+  userAgent: 'default' | 'fallback'
+  userAgentValue: string
+  sentCookie: boolean
+  resolvedIps: string[]
+  remoteAddr?: string
+  timing: {
+    dnsMs: number
+    connectMs: number
+    tlsMs: number
+    ttfbMs: number
+    totalMs: number
+  }
+  /** Transport or read error; the fields below are empty when the request got no response. */
+  error?: string
+  statusCode?: number
+  headers: Record<string, string>
+  bodySize: number
+  bodyPreview: string
+  anubisPage: boolean
+  anubisChallenge: boolean
+  parsed: boolean
+  parseError?: string
+  feedType?: string
+  itemCount: number
+  itemsNoGuid: number
+  itemsNoLink: number
+  /** What a refresh would skip or alter of the items, null when nothing. */
+  ingest: IngestReport | null
+}
+
+export type IngestReason = 'missing_link' | 'duplicate' | 'backfill_limit' | 'truncated' | 'unparsed_date' | 'implausible_date'
+
+/** An item a refresh skipped or saved altered. */
+export interface IngestIssue {
+  reason: IngestReason
+  title?: string
+  link?: string
+  guid?: string
+  /** The truncated field, the date text that did not parse, or the implausible date. */
+  detail?: string
+}
+
+/** Items of one refresh that did not make it into the database as delivered. */
+export interface IngestReport {
+  checkedAt: string
+  items: number
+  saved: number
+  counts: Partial<Record<IngestReason, number>>
+  /** The first 50 affected items. */
+  issues: IngestIssue[]
+}
+
+/** Response of GET /api/feeds/:id/ingest-report; null when the last refresh was clean. */
+export interface FeedIngestReport {
+  report: IngestReport | null
+}
+
+export type ArchiveBackfillStatus = 'running' | 'done' | 'failed'
+
+/** A crawl of the RFC 5005 archive pages a feed links to with rel="next". */
+export interface ArchiveBackfill {
+  feedId: string
+  status: ArchiveBackfillStatus
+  maxPages: number
+  /** Documents fetched, the feed document included. */
+  pages: number
+  items: number
+  newEntries: number
+  /** Set when the crawl stopped at maxPages with pages left. */
+  hasMore: boolean
+  error?: string
+  startedAt: string
+  finishedAt?: string
+}
+
+/** Response of GET /api/feeds/:id/backfill-archive; null when none has run. */
+export interface FeedArchiveBackfill {
+  backfill: ArchiveBackfill | null
+}
+
+export type ServerEventType =
+  | 'refresh.started'
+  | 'refresh.finished'
+  | 'entries.new'
+  | 'entries.read'
+  | 'entry.starred'
+  | 'entry.readable'
+  | 'feed.created'
+  | 'feed.updated'
+  | 'feed.deleted'
+  | 'folder.created'
+  | 'folder.updated'
+  | 'folder.deleted'
+
+/** Payload of a GET /api/events message; only the fields of its type are set. */
+export interface ServerEventData {
+  count?: number
+  feedId?: string
+  folderId?: string
+  entryId?: string
+  entryIds?: string[]
+  contentType?: string
+  read?: boolean
+  starred?: boolean
+  /** entry.readable: 'ready' once cached, or 'failed' with error. */
+  status?: 'ready' | 'failed'
+  error?: string
+}
+
+export type BackupEntries = 'unread_starred' | 'all' | 'none'
+
+export interface BackupSectionResult {
+  created: number
+  updated: number
+  skipped: number
+}
+
+export interface BackupImportResult {
+  folders: BackupSectionResult
+  feeds: BackupSectionResult
+  entries: BackupSectionResult
+}
+
 export interface ImportTask {
   id?: string
   status: 'idle' | 'running' | 'done' | 'error' | 'cancelled'
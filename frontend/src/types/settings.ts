@@ -12,8 +12,22 @@ export interface AISettings {
   requestOptions: RequestOptions;
   summaryLanguage: string;
   autoTranslate: boolean;
+  /** Translate list titles of new entries in the background after scheduled refreshes */
+  translateOnRefresh?: boolean;
   autoSummary: boolean;
   rateLimit: number;
+  modelPrices?: Record<string, AIModelPrice>;
+  usageRetentionMonths?: number;
+  /** Article HTML sent per translation call; smaller blocks are merged up to it */
+  translateBlockChars?: number;
+  /** Save counter from the last read; a stale one is rejected with 409 */
+  version?: string;
+}
+
+/** USD per million tokens */
+export interface AIModelPrice {
+  input: number;
+  output: number;
 }
 
 export interface AITestRequest {
@@ -34,6 +48,26 @@ export interface GeneralSettings {
   fallbackUserAgent: string;
   autoReadability: boolean;
   markReadOnScroll: boolean;
+  entryRevisions?: boolean;
+  imageCache?: boolean;
+  imageCacheEntryLimitMb?: number;
+  imageCacheTotalLimitMb?: number;
+  timezone?: string;
+  maxConcurrentRefresh?: number;
+  maxConcurrentPerHost?: number;
+  /** Anubis challenges solved per fetch at most, 1 to 10 */
+  anubisMaxRetries?: number;
+  /** Wait after each Anubis solve before fetching again, 0 to 10000 */
+  anubisRetryDelayMs?: number;
+  /** Refreshes in a row answered 304 before an overdue feed is fetched without validators, 1 to 1000 */
+  unconditionalFetchAfter?: number;
+  /** Keep each entry's feed item for 7 days, readable at /api/entries/:id/raw */
+  keepRawItems?: boolean;
+  /** Daily quiet hours as "HH:MM" in timezone, may cross midnight; both empty disables them */
+  quietHoursStart?: string;
+  quietHoursEnd?: string;
+  /** Save counter from the last read; a stale one is rejected with 409 */
+  version?: string;
 }
 
 export type ProxyType = 'http' | 'socks5';
@@ -48,6 +82,8 @@ export interface NetworkSettings {
   username: string;
   password: string;
   ipStack: IPStack;
+  /** Save counter from the last read; a stale one is rejected with 409 */
+  version?: string;
 }
 
 export interface NetworkTestRequest {
@@ -57,18 +93,23 @@ export interface NetworkTestRequest {
   port: number;
   username: string;
   password: string;
+  /** Defaults to https://captive.apple.com/; an IP echo URL also reports the egress IP */
+  testUrl?: string;
 }
 
 export interface NetworkTestResponse {
   success: boolean;
   message?: string;
   error?: string;
+  latencyMs?: number;
+  egressIp?: string;
 }
 
 export interface DomainRateLimit {
   id: string;
   host: string;
   intervalSeconds: number;
+  maxConcurrent?: number;
 }
 
 export interface DomainRateLimitListResponse {
@@ -77,4 +118,13 @@ export interface DomainRateLimitListResponse {
 
 export interface AppearanceSettings {
   contentTypes: ContentType[];
+  /** Save counter from the last read; a stale one is rejected with 409 */
+  version?: string;
+}
+
+export interface SecuritySettings {
+  corsOrigins: string[];
+  cookieSameSite: 'lax' | 'none';
+  cookieSecure: boolean;
+  cookieDomain: string;
 }